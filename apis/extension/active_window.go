@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const minutesPerDay = 24 * 60
+
+// ActiveWindow is a daily time window in UTC, the Start and End are formatted as "HH:MM". The window wraps
+// around midnight when the End is before the Start, e.g. 22:00-06:00.
+type ActiveWindow struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// ActiveWindows declares when the exclusive CPUs of a LSR Pod are in use. Outside all the windows, the CPUs
+// are returned to the share pool of the BE Pods, and they can be allocated to another LSR Pod whose windows
+// don't overlap. Empty ActiveWindows means the Pod is always active.
+type ActiveWindows []ActiveWindow
+
+// minuteRange is the half-open range [start, end) of minutes in a day.
+type minuteRange struct {
+	start, end int
+}
+
+func parseMinuteOfDay(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, should be formatted as HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// ranges splits the window into the ranges of the day, a window across midnight has two ranges.
+func (w ActiveWindow) ranges() ([]minuteRange, error) {
+	start, err := parseMinuteOfDay(w.Start)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseMinuteOfDay(w.End)
+	if err != nil {
+		return nil, err
+	}
+	if start == end {
+		return nil, fmt.Errorf("active window %s-%s is empty", w.Start, w.End)
+	}
+	if start < end {
+		return []minuteRange{{start, end}}, nil
+	}
+	return []minuteRange{{start, minutesPerDay}, {0, end}}, nil
+}
+
+func (w ActiveWindows) ranges() ([]minuteRange, error) {
+	var result []minuteRange
+	for _, window := range w {
+		r, err := window.ranges()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, r...)
+	}
+	return result, nil
+}
+
+// Validate checks all the windows are well-formed.
+func (w ActiveWindows) Validate() error {
+	_, err := w.ranges()
+	return err
+}
+
+// IsActiveAt returns whether t is in any of the windows. The invalid windows are always active to not
+// give away the CPUs by mistake.
+func (w ActiveWindows) IsActiveAt(t time.Time) bool {
+	if len(w) == 0 {
+		return true
+	}
+	ranges, err := w.ranges()
+	if err != nil {
+		return true
+	}
+	t = t.UTC()
+	minute := t.Hour()*60 + t.Minute()
+	for _, r := range ranges {
+		if minute >= r.start && minute < r.end {
+			return true
+		}
+	}
+	return false
+}
+
+// Overlaps returns whether the windows overlap with the other ones. Empty or invalid windows overlap with
+// anything since they are always active.
+func (w ActiveWindows) Overlaps(other ActiveWindows) bool {
+	if len(w) == 0 || len(other) == 0 {
+		return true
+	}
+	ranges, err := w.ranges()
+	if err != nil {
+		return true
+	}
+	otherRanges, err := other.ranges()
+	if err != nil {
+		return true
+	}
+	for _, r := range ranges {
+		for _, o := range otherRanges {
+			if r.start < o.end && o.start < r.end {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetPodActiveWindows returns the ActiveWindows of the exclusive CPUs of the Pod, only the LSR Pods
+// can declare the ActiveWindows.
+func GetPodActiveWindows(pod *corev1.Pod) ActiveWindows {
+	if GetPodQoSClass(pod) != QoSLSR {
+		return nil
+	}
+	resourceSpec, err := GetResourceSpec(pod.Annotations)
+	if err != nil {
+		return nil
+	}
+	return resourceSpec.ActiveWindows
+}
+
+// IsPodCPUSetActive returns whether the Pod holds its exclusive CPUs at t.
+func IsPodCPUSetActive(pod *corev1.Pod, t time.Time) bool {
+	return GetPodActiveWindows(pod).IsActiveAt(t)
+}
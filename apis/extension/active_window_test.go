@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestActiveWindows(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2022, 10, 1, hour, minute, 0, 0, time.UTC)
+	}
+	marketHours := ActiveWindows{{Start: "09:30", End: "16:00"}}
+	night := ActiveWindows{{Start: "22:00", End: "06:00"}}
+
+	assert.NoError(t, marketHours.Validate())
+	assert.NoError(t, night.Validate())
+	assert.Error(t, ActiveWindows{{Start: "9:30am", End: "16:00"}}.Validate())
+	assert.Error(t, ActiveWindows{{Start: "09:30", End: "09:30"}}.Validate())
+
+	assert.True(t, ActiveWindows(nil).IsActiveAt(at(3, 0)))
+	assert.True(t, marketHours.IsActiveAt(at(9, 30)))
+	assert.False(t, marketHours.IsActiveAt(at(16, 0)))
+	assert.True(t, night.IsActiveAt(at(23, 0)))
+	assert.True(t, night.IsActiveAt(at(5, 59)))
+	assert.False(t, night.IsActiveAt(at(12, 0)))
+	// the time is compared in UTC
+	assert.True(t, marketHours.IsActiveAt(at(10, 0).In(time.FixedZone("UTC+8", 8*3600))))
+
+	assert.False(t, marketHours.Overlaps(night))
+	assert.True(t, marketHours.Overlaps(ActiveWindows{{Start: "15:00", End: "18:00"}}))
+	assert.True(t, night.Overlaps(ActiveWindows{{Start: "05:00", End: "07:00"}}))
+	assert.False(t, marketHours.Overlaps(ActiveWindows{{Start: "16:00", End: "18:00"}}))
+	assert.True(t, marketHours.Overlaps(nil))
+}
+
+func TestIsPodCPUSetActive(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				LabelPodQoS: string(QoSLSR),
+			},
+			Annotations: map[string]string{
+				AnnotationResourceSpec: `{"activeWindows":[{"start":"09:30","end":"16:00"}]}`,
+			},
+		},
+	}
+	assert.True(t, IsPodCPUSetActive(pod, time.Date(2022, 10, 1, 10, 0, 0, 0, time.UTC)))
+	assert.False(t, IsPodCPUSetActive(pod, time.Date(2022, 10, 1, 20, 0, 0, 0, time.UTC)))
+
+	// only the LSR pods can declare the active windows
+	pod.Labels[LabelPodQoS] = string(QoSLSE)
+	assert.True(t, IsPodCPUSetActive(pod, time.Date(2022, 10, 1, 20, 0, 0, 0, time.UTC)))
+}
@@ -19,10 +19,14 @@ package extension
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/apiserver/pkg/quota/v1"
 	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 )
 
 //RootQuotaName means quotaTree's root\head.
@@ -38,8 +42,138 @@ const (
 	AnnotationSharedWeight = QuotaKoordinatorPrefix + "/shared-weight"
 	AnnotationRuntime      = QuotaKoordinatorPrefix + "/runtime"
 	AnnotationRequest      = QuotaKoordinatorPrefix + "/request"
+	// AnnotationMinQuotaOversellRatio allows the sum of the children's min to be up to ratio times of the
+	// parent's resource before the children's min are scaled down. It is inherited by the whole subtree.
+	AnnotationMinQuotaOversellRatio = QuotaKoordinatorPrefix + "/min-quota-oversell-ratio"
+	// AnnotationQuotaPolicy is the QuotaPolicy of the quota group, the unset fields are inherited from the parent.
+	AnnotationQuotaPolicy = QuotaKoordinatorPrefix + "/policy"
+	// AnnotationReserved is the part of the min which is never lent to the other quota groups, even if it's idle.
+	AnnotationReserved = QuotaKoordinatorPrefix + "/reserved"
+	// AnnotationBorrowLimit is the max resource the quota group can borrow beyond its min, it's independent of the max.
+	AnnotationBorrowLimit = QuotaKoordinatorPrefix + "/borrow-limit"
+	// AnnotationPriorityTier is the priority tier of the quota group among its siblings, the shared resource is
+	// distributed to the higher tier up to its request before the lower tiers get anything. Default is 0.
+	AnnotationPriorityTier = QuotaKoordinatorPrefix + "/priority-tier"
+	// AnnotationChargedQuota is the quota group the pod is charged to, it's written after the pod is scheduled.
+	AnnotationChargedQuota = QuotaKoordinatorPrefix + "/charged-quota"
+	// AnnotationAdmissionRuntime is the runtime of the charged quota group when the pod is admitted.
+	AnnotationAdmissionRuntime = QuotaKoordinatorPrefix + "/admission-runtime"
+	// AnnotationNodeSelector is the label selector of the node pool the quota tree is scoped to, the runtime of the
+	// quota groups in the tree is computed against the capacity of the node pool. It only takes effect on the quota
+	// groups whose parent is the root quota group, the children inherit the node pool of their parents.
+	AnnotationNodeSelector = QuotaKoordinatorPrefix + "/node-selector"
+	// AnnotationQuotaReservation is the name of the quota reservation the pod belongs to, the reserved quota is
+	// taken over by the pod once the pod is counted in the quota group.
+	AnnotationQuotaReservation = QuotaKoordinatorPrefix + "/reservation"
+	// AnnotationEffectiveConfig is the configuration of the quota group resolved by the scheduler, e.g. the policy
+	// inherited from the parents and the effective SharedWeight. It's written by the scheduler and read only.
+	AnnotationEffectiveConfig = QuotaKoordinatorPrefix + "/effective-config"
+	// ElasticQuotaFinalizer blocks the deletion of the quota until its child quotas are moved to its parent
+	// and its pods are drained.
+	ElasticQuotaFinalizer = QuotaKoordinatorPrefix + "/quota-protection"
+)
+
+// QuotaEvictionPolicy decides whether the pods of the quota group can be evicted to reclaim the resource.
+type QuotaEvictionPolicy string
+
+const (
+	// QuotaEvictionPolicyReclaimable allows the pods using the resource borrowed from other quota groups
+	// to be evicted when the resource is reclaimed.
+	QuotaEvictionPolicyReclaimable QuotaEvictionPolicy = "Reclaimable"
+	// QuotaEvictionPolicyNever never evicts the pods of the quota group.
+	QuotaEvictionPolicyNever QuotaEvictionPolicy = "Never"
 )
 
+// QuotaPolicy is the policy of the quota group. The unset fields are inherited from the parent quota group,
+// NodeSelector and DefaultLimits are merged with the parent's and the child's values take precedence.
+type QuotaPolicy struct {
+	// AllowLentResource is overridden by the label LabelAllowLentResource if set.
+	AllowLentResource *bool `json:"allowLentResource,omitempty"`
+	// NodeSelector restricts the nodes on which the pods of the quota group can run.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// DefaultLimits is the default resource limits of the containers of the quota group's pods.
+	DefaultLimits corev1.ResourceList `json:"defaultLimits,omitempty"`
+	// EvictionPolicy decides whether the pods of the quota group can be evicted.
+	EvictionPolicy QuotaEvictionPolicy `json:"evictionPolicy,omitempty"`
+	// RuntimeCalculateStrategy is the strategy to distribute the resource of the quota group to its children,
+	// e.g. DominantResourceFairness, it overrides the strategy of the scheduler for the subtree.
+	RuntimeCalculateStrategy string `json:"runtimeCalculateStrategy,omitempty"`
+	// DeviceRestriction restricts the devices the pods of the quota group can use. It's intersected with the
+	// parent's, so a child can only narrow the devices allowed by its parent.
+	DeviceRestriction *QuotaDeviceRestriction `json:"deviceRestriction,omitempty"`
+}
+
+// QuotaDeviceRestriction restricts the device types and models, which tiers the hardware by tenant, e.g. a quota
+// group can use A10 but not A100. A nil list allows everything, while an empty list allows nothing.
+type QuotaDeviceRestriction struct {
+	// AllowedTypes are the device types the pods can request.
+	AllowedTypes []schedulingv1alpha1.DeviceType `json:"allowedTypes"`
+	// AllowedModels are the GPU models the pods can run on, the model of a node is labeled by LabelGPUModel.
+	AllowedModels []string `json:"allowedModels"`
+}
+
+func (r *QuotaDeviceRestriction) DeepCopy() *QuotaDeviceRestriction {
+	if r == nil {
+		return nil
+	}
+	out := &QuotaDeviceRestriction{}
+	if r.AllowedTypes != nil {
+		out.AllowedTypes = make([]schedulingv1alpha1.DeviceType, len(r.AllowedTypes))
+		copy(out.AllowedTypes, r.AllowedTypes)
+	}
+	if r.AllowedModels != nil {
+		out.AllowedModels = make([]string, len(r.AllowedModels))
+		copy(out.AllowedModels, r.AllowedModels)
+	}
+	return out
+}
+
+func (p *QuotaPolicy) DeepCopy() *QuotaPolicy {
+	if p == nil {
+		return nil
+	}
+	out := &QuotaPolicy{
+		DefaultLimits:            p.DefaultLimits.DeepCopy(),
+		EvictionPolicy:           p.EvictionPolicy,
+		RuntimeCalculateStrategy: p.RuntimeCalculateStrategy,
+		DeviceRestriction:        p.DeviceRestriction.DeepCopy(),
+	}
+	if p.AllowLentResource != nil {
+		allowLentResource := *p.AllowLentResource
+		out.AllowLentResource = &allowLentResource
+	}
+	if p.NodeSelector != nil {
+		out.NodeSelector = make(map[string]string, len(p.NodeSelector))
+		for k, v := range p.NodeSelector {
+			out.NodeSelector[k] = v
+		}
+	}
+	return out
+}
+
+// FederatedLendingPolicy is the cluster-level policy of the lending between the root quota trees, i.e. the
+// children of the root quota group. The members of the federation lend their idle min to each other even if
+// they don't allow lending their resource to the others, and the lent resource is reclaimed once the demand
+// of the lender returns.
+type FederatedLendingPolicy struct {
+	// Enabled enables the federated lending.
+	Enabled bool `json:"enabled,omitempty"`
+	// Members are the names of the root quota trees joining the federation, empty means all of them.
+	Members []string `json:"members,omitempty"`
+}
+
+func (p *FederatedLendingPolicy) DeepCopy() *FederatedLendingPolicy {
+	if p == nil {
+		return nil
+	}
+	out := &FederatedLendingPolicy{Enabled: p.Enabled}
+	if p.Members != nil {
+		out.Members = make([]string, len(p.Members))
+		copy(out.Members, p.Members)
+	}
+	return out
+}
+
 func GetParentQuotaName(quota *v1alpha1.ElasticQuota) string {
 	parentName := quota.Labels[LabelQuotaParent]
 	if parentName == "" {
@@ -68,6 +202,87 @@ func GetSharedWeight(quota *v1alpha1.ElasticQuota) corev1.ResourceList {
 	return quota.Spec.Max.DeepCopy() //default equals to max
 }
 
+// GetReserved returns the reserved part of the min, which is limited by the min.
+func GetReserved(quota *v1alpha1.ElasticQuota) corev1.ResourceList {
+	value, exist := quota.Annotations[AnnotationReserved]
+	if !exist {
+		return corev1.ResourceList{}
+	}
+	resList := corev1.ResourceList{}
+	if err := json.Unmarshal([]byte(value), &resList); err != nil {
+		return corev1.ResourceList{}
+	}
+	resList = v1.Mask(resList, v1.ResourceNames(quota.Spec.Min))
+	for resourceName, quantity := range resList {
+		if quantity.Sign() <= 0 {
+			delete(resList, resourceName)
+		} else if min := quota.Spec.Min[resourceName]; quantity.Cmp(min) > 0 {
+			resList[resourceName] = min.DeepCopy()
+		}
+	}
+	return resList
+}
+
+// GetBorrowLimit returns the max resource the quota group can borrow beyond its min, the resources not in the
+// result can be borrowed without limit.
+func GetBorrowLimit(quota *v1alpha1.ElasticQuota) corev1.ResourceList {
+	value, exist := quota.Annotations[AnnotationBorrowLimit]
+	if !exist {
+		return corev1.ResourceList{}
+	}
+	resList := corev1.ResourceList{}
+	if err := json.Unmarshal([]byte(value), &resList); err != nil {
+		return corev1.ResourceList{}
+	}
+	for resourceName, quantity := range resList {
+		if quantity.Sign() < 0 {
+			delete(resList, resourceName)
+		}
+	}
+	return resList
+}
+
+// GetMinQuotaOversellRatio returns the oversell ratio of the children's min, 0 means not set.
+func GetMinQuotaOversellRatio(quota *v1alpha1.ElasticQuota) float64 {
+	value, exist := quota.Annotations[AnnotationMinQuotaOversellRatio]
+	if !exist {
+		return 0
+	}
+	ratio, err := strconv.ParseFloat(value, 64)
+	if err != nil || ratio <= 0 {
+		return 0
+	}
+	return ratio
+}
+
+// GetPriorityTier returns the priority tier of the quota group, 0 if not set.
+func GetPriorityTier(quota *v1alpha1.ElasticQuota) (int32, error) {
+	value, exist := quota.Annotations[AnnotationPriorityTier]
+	if !exist {
+		return 0, nil
+	}
+	tier, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid priority tier %q, err: %v", value, err)
+	}
+	return int32(tier), nil
+}
+
+// GetQuotaPolicy returns the QuotaPolicy of the quota itself, which is not merged with the parent's.
+func GetQuotaPolicy(quota *v1alpha1.ElasticQuota) (*QuotaPolicy, error) {
+	policy := &QuotaPolicy{}
+	if value, exist := quota.Annotations[AnnotationQuotaPolicy]; exist {
+		if err := json.Unmarshal([]byte(value), policy); err != nil {
+			return nil, err
+		}
+	}
+	if value, exist := quota.Labels[LabelAllowLentResource]; exist {
+		allowLentResource := value != "false"
+		policy.AllowLentResource = &allowLentResource
+	}
+	return policy, nil
+}
+
 func IsForbiddenModify(quota *v1alpha1.ElasticQuota) (bool, error) {
 	if quota.Name == SystemQuotaName || quota.Name == RootQuotaName {
 		// can't modify SystemQuotaGroup
@@ -76,3 +291,42 @@ func IsForbiddenModify(quota *v1alpha1.ElasticQuota) (bool, error) {
 
 	return false, nil
 }
+
+// GetRuntime returns the runtime of the quota group written by the scheduler, nil if not written.
+func GetRuntime(quota *v1alpha1.ElasticQuota) (corev1.ResourceList, error) {
+	value, exist := quota.Annotations[AnnotationRuntime]
+	if !exist {
+		return nil, nil
+	}
+	resList := corev1.ResourceList{}
+	if err := json.Unmarshal([]byte(value), &resList); err != nil {
+		return nil, err
+	}
+	return resList, nil
+}
+
+// GetAdmissionRuntime returns the runtime of the charged quota group when the pod is admitted, nil if not written.
+func GetAdmissionRuntime(pod *corev1.Pod) (corev1.ResourceList, error) {
+	value, exist := pod.Annotations[AnnotationAdmissionRuntime]
+	if !exist {
+		return nil, nil
+	}
+	resList := corev1.ResourceList{}
+	if err := json.Unmarshal([]byte(value), &resList); err != nil {
+		return nil, err
+	}
+	return resList, nil
+}
+
+// GetNodeSelector returns the node selector of the node pool the quota is scoped to, nil if not set.
+func GetNodeSelector(quota *v1alpha1.ElasticQuota) (*metav1.LabelSelector, error) {
+	value, exist := quota.Annotations[AnnotationNodeSelector]
+	if !exist {
+		return nil, nil
+	}
+	selector := &metav1.LabelSelector{}
+	if err := json.Unmarshal([]byte(value), selector); err != nil {
+		return nil, err
+	}
+	return selector, nil
+}
@@ -19,6 +19,8 @@ package extension
 import (
 	"encoding/json"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
 
 	schedulingconfig "github.com/koordinator-sh/koordinator/apis/scheduling/config"
@@ -32,22 +34,53 @@ const (
 	// AnnotationNodeCPUSharedPools describes the CPU Shared Pool defined by Koordinator.
 	// The shared pool is mainly used by Koordinator LS Pods or K8s Burstable Pods.
 	AnnotationNodeCPUSharedPools = NodeDomainPrefix + "/cpu-shared-pools"
+	// AnnotationNodeCPUReservation describes the CPUs reserved for the user-space networking/storage offload stacks,
+	// which are excluded from both the LS exclusive allocation and the BE shares.
+	AnnotationNodeCPUReservation = NodeDomainPrefix + "/cpu-reservation"
+	// AnnotationNodeNUMAMemory describes the memory and hugepages capacity of each NUMA Node.
+	AnnotationNodeNUMAMemory = NodeDomainPrefix + "/numa-memory"
+	// AnnotationNodeCPUPoolLayout describes the current layout of the exclusive pool and the shared pool
+	// when the node dynamically resizes the pools, see LabelNodeCPUPoolPolicy.
+	AnnotationNodeCPUPoolLayout = NodeDomainPrefix + "/cpu-pool-layout"
+	// AnnotationNodeMIGConfig describes the desired MIG geometry of the GPUs on the node, koordlet reconfigures
+	// the GPUs which no Pod is using the MIG devices of.
+	AnnotationNodeMIGConfig = NodeDomainPrefix + "/mig-config"
 
 	// LabelNodeCPUBindPolicy constrains how to bind CPU logical CPUs when scheduling.
 	LabelNodeCPUBindPolicy = NodeDomainPrefix + "/cpu-bind-policy"
 	// LabelNodeNUMAAllocateStrategy indicates how to choose satisfied NUMA Nodes when scheduling.
 	LabelNodeNUMAAllocateStrategy = NodeDomainPrefix + "/numa-allocate-strategy"
+	// LabelNodeNUMATopologyPolicy is the node-wide NUMA topology policy, which is used when the Pod doesn't declare one.
+	LabelNodeNUMATopologyPolicy = NodeDomainPrefix + "/numa-topology-policy"
+	// LabelNodeCPUPoolPolicy indicates how koordlet sizes the exclusive pool and the shared pool of the node.
+	LabelNodeCPUPoolPolicy = NodeDomainPrefix + "/cpu-pool-policy"
+	// LabelGPUModel is the model of the GPUs on the node, e.g. A100.
+	LabelGPUModel = NodeDomainPrefix + "/gpu-model"
 )
 
 const (
 	// NodeCPUBindPolicyFullPCPUsOnly requires that the scheduler must allocate full physical cores.
 	// Equivalent to kubelet CPU manager policy option full-pcpus-only=true.
 	NodeCPUBindPolicyFullPCPUsOnly = "FullPCPUsOnly"
+	// NodeCPUBindPolicyFullPCPUsIsolated requires that the scheduler must allocate full physical cores to the LSE/LSR
+	// Pods, even if the requested CPUs are not aligned to the cores, and koordlet never shares the physical cores
+	// of the LSE/LSR Pods with the BE Pods, so the hyper-threading siblings can't interfere with each other.
+	NodeCPUBindPolicyFullPCPUsIsolated = "FullPCPUsIsolated"
 )
 
 const (
-	NodeNUMAAllocateStrategyLeastAllocated = string(schedulingconfig.NUMALeastAllocated)
-	NodeNUMAAllocateStrategyMostAllocated  = string(schedulingconfig.NUMAMostAllocated)
+	// NodeCPUPoolPolicyStatic is the default policy, all the CPUs not allocated to the LSE/LSR Pods are in the shared pool.
+	NodeCPUPoolPolicyStatic = "Static"
+	// NodeCPUPoolPolicyDynamic makes koordlet shrink the shared pool to the CPUs the LS and BE Pods are using, and
+	// park the idle CPUs in the exclusive pool for the upcoming LSE/LSR Pods. The shared pool grows again as the
+	// load rises or the LSE/LSR Pods are deleted. The scheduler only allocates the CPUs in the exclusive pool.
+	NodeCPUPoolPolicyDynamic = "Dynamic"
+)
+
+const (
+	NodeNUMAAllocateStrategyLeastAllocated   = string(schedulingconfig.NUMALeastAllocated)
+	NodeNUMAAllocateStrategyMostAllocated    = string(schedulingconfig.NUMAMostAllocated)
+	NodeNUMAAllocateStrategyDistributeEvenly = string(schedulingconfig.NUMADistributeEvenly)
 )
 
 const (
@@ -71,6 +104,18 @@ type CPUInfo struct {
 	Node   int32 `json:"node"`
 }
 
+// NUMAMemory describes the memory capacity of the NUMA Nodes.
+type NUMAMemory struct {
+	Detail []NUMANodeMemory `json:"detail,omitempty"`
+}
+
+type NUMANodeMemory struct {
+	Node     int32             `json:"node"`
+	Capacity resource.Quantity `json:"capacity"`
+	// HugePages is the capacity of the pre-allocated hugepages of each page size, e.g. hugepages-2Mi.
+	HugePages corev1.ResourceList `json:"hugePages,omitempty"`
+}
+
 type PodCPUAlloc struct {
 	Namespace        string    `json:"namespace,omitempty"`
 	Name             string    `json:"name,omitempty"`
@@ -87,6 +132,29 @@ type KubeletCPUManagerPolicy struct {
 	ReservedCPUs string            `json:"reservedCPUs,omitempty"`
 }
 
+type CPUReservation struct {
+	ReservedCPUs string `json:"reservedCPUs,omitempty"`
+}
+
+// MIGConfig describes the desired MIG profiles of each GPU, the GPU not in the config is not reconfigured.
+type MIGConfig struct {
+	GPUs []GPUMIGConfig `json:"gpus,omitempty"`
+}
+
+type GPUMIGConfig struct {
+	// Minor is the Minor number of the GPU
+	Minor int32 `json:"minor"`
+	// Profiles are the profiles of the MIG devices partitioned from the GPU, e.g. ["3g.20gb", "2g.10gb", "1g.5gb"]
+	Profiles []string `json:"profiles,omitempty"`
+}
+
+// CPUPoolLayout describes the CPUs of the exclusive pool, including the CPUs allocated to the LSE/LSR Pods
+// and the idle CPUs parked for them, and the CPUs of the shared pool.
+type CPUPoolLayout struct {
+	ExclusiveCPUs string `json:"exclusiveCPUs,omitempty"`
+	SharedCPUs    string `json:"sharedCPUs,omitempty"`
+}
+
 func GetCPUTopology(annotations map[string]string) (*CPUTopology, error) {
 	topology := &CPUTopology{}
 	data, ok := annotations[AnnotationNodeCPUTopology]
@@ -100,6 +168,29 @@ func GetCPUTopology(annotations map[string]string) (*CPUTopology, error) {
 	return topology, nil
 }
 
+// GetNUMAMemory parses the memory capacity of the NUMA Nodes from annotations
+func GetNUMAMemory(annotations map[string]string) (*NUMAMemory, error) {
+	numaMemory := &NUMAMemory{}
+	data, ok := annotations[AnnotationNodeNUMAMemory]
+	if !ok {
+		return numaMemory, nil
+	}
+	err := json.Unmarshal([]byte(data), numaMemory)
+	if err != nil {
+		return nil, err
+	}
+	return numaMemory, nil
+}
+
+// GetNodeNUMATopologyPolicy returns the node-wide NUMA topology policy, the unknown policy is ignored.
+func GetNodeNUMATopologyPolicy(labels map[string]string) NUMATopologyPolicy {
+	policy := NUMATopologyPolicy(labels[LabelNodeNUMATopologyPolicy])
+	if !policy.IsValid() {
+		return NUMATopologyPolicyNone
+	}
+	return policy
+}
+
 func GetPodCPUAllocs(annotations map[string]string) (PodCPUAllocs, error) {
 	var allocs PodCPUAllocs
 	data, ok := annotations[AnnotationNodeCPUAllocs]
@@ -138,3 +229,42 @@ func GetKubeletCPUManagerPolicy(annotations map[string]string) (*KubeletCPUManag
 	}
 	return cpuManagerPolicy, nil
 }
+
+func GetNodeMIGConfig(annotations map[string]string) (*MIGConfig, error) {
+	data, ok := annotations[AnnotationNodeMIGConfig]
+	if !ok {
+		return nil, nil
+	}
+	config := &MIGConfig{}
+	err := json.Unmarshal([]byte(data), config)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func GetNodeCPUPoolLayout(annotations map[string]string) (*CPUPoolLayout, error) {
+	data, ok := annotations[AnnotationNodeCPUPoolLayout]
+	if !ok {
+		return nil, nil
+	}
+	layout := &CPUPoolLayout{}
+	err := json.Unmarshal([]byte(data), layout)
+	if err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+func GetNodeCPUReservation(annotations map[string]string) (*CPUReservation, error) {
+	reservation := &CPUReservation{}
+	data, ok := annotations[AnnotationNodeCPUReservation]
+	if !ok {
+		return reservation, nil
+	}
+	err := json.Unmarshal([]byte(data), reservation)
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
@@ -18,8 +18,10 @@ package extension
 
 import (
 	"encoding/json"
+	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 )
@@ -28,8 +30,36 @@ const (
 	AnnotationPodCPUBurst = DomainPrefix + "cpuBurst"
 
 	AnnotationPodMemoryQoS = DomainPrefix + "memoryQOS"
+
+	// AnnotationPodBlkIOQoS overrides the block io qos of the pod's qos class, e.g. {"readIOPS": 1000, "weight": 100}
+	AnnotationPodBlkIOQoS = DomainPrefix + "blkioQOS"
+
+	// AnnotationPodCacheGroup is the name of the CacheGroup in the same namespace the pod joins.
+	AnnotationPodCacheGroup = DomainPrefix + "cacheGroup"
+
+	// AnnotationPodNetworkQoS is the network bandwidth limits and priority of the pod, e.g.
+	// {"ingressLimit": "100M", "egressLimit": "50M", "priority": 2}
+	AnnotationPodNetworkQoS = DomainPrefix + "networkQOS"
+)
+
+const (
+	// NetworkQoSPriorityHighest is the highest network priority, which is dequeued first
+	NetworkQoSPriorityHighest int32 = 0
+	// NetworkQoSPriorityLowest is the lowest network priority
+	NetworkQoSPriorityLowest int32 = 7
 )
 
+// NetworkQoSConfig is the network QoS of the pod. The limits are in bits per second.
+type NetworkQoSConfig struct {
+	// IngressLimit limits the bandwidth of the traffic received by the pod
+	IngressLimit *resource.Quantity `json:"ingressLimit,omitempty"`
+	// EgressLimit limits the bandwidth of the traffic sent by the pod
+	EgressLimit *resource.Quantity `json:"egressLimit,omitempty"`
+	// Priority is the priority of the egress traffic among the pods of the same QoS class, in [0, 7],
+	// and the smaller value has the higher priority.
+	Priority *int32 `json:"priority,omitempty"`
+}
+
 func GetPodCPUBurstConfig(pod *corev1.Pod) (*slov1alpha1.CPUBurstConfig, error) {
 	if pod == nil || pod.Annotations == nil {
 		return nil, nil
@@ -62,3 +92,52 @@ func GetPodMemoryQoSConfig(pod *corev1.Pod) (*slov1alpha1.PodMemoryQOSConfig, er
 	}
 	return &cfg, nil
 }
+
+// GetPodBlkIOQoSConfig returns the block io qos of the pod, the unset fields follow the qos class of the pod.
+func GetPodBlkIOQoSConfig(pod *corev1.Pod) (*slov1alpha1.BlkIOQOS, error) {
+	if pod == nil || pod.Annotations == nil {
+		return nil, nil
+	}
+	value, exist := pod.Annotations[AnnotationPodBlkIOQoS]
+	if !exist {
+		return nil, nil
+	}
+	cfg := slov1alpha1.BlkIOQOS{}
+	if err := json.Unmarshal([]byte(value), &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// GetPodCacheGroup returns the name of the CacheGroup the pod joins, or empty if the pod is not in any group.
+func GetPodCacheGroup(pod *corev1.Pod) string {
+	if pod == nil || pod.Annotations == nil {
+		return ""
+	}
+	return pod.Annotations[AnnotationPodCacheGroup]
+}
+
+// GetPodNetworkQoSConfig parses the network QoS of the pod, it returns nil if the pod does not specify it.
+func GetPodNetworkQoSConfig(pod *corev1.Pod) (*NetworkQoSConfig, error) {
+	if pod == nil || pod.Annotations == nil {
+		return nil, nil
+	}
+	value, exist := pod.Annotations[AnnotationPodNetworkQoS]
+	if !exist {
+		return nil, nil
+	}
+	cfg := NetworkQoSConfig{}
+	if err := json.Unmarshal([]byte(value), &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.IngressLimit != nil && cfg.IngressLimit.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid ingress limit %s", cfg.IngressLimit.String())
+	}
+	if cfg.EgressLimit != nil && cfg.EgressLimit.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid egress limit %s", cfg.EgressLimit.String())
+	}
+	if cfg.Priority != nil && (*cfg.Priority < NetworkQoSPriorityHighest || *cfg.Priority > NetworkQoSPriorityLowest) {
+		return nil, fmt.Errorf("invalid priority %d, should be in [%d, %d]", *cfg.Priority, NetworkQoSPriorityHighest, NetworkQoSPriorityLowest)
+	}
+	return &cfg, nil
+}
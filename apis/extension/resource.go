@@ -18,6 +18,7 @@ package extension
 
 import (
 	"encoding/json"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -42,8 +43,25 @@ const (
 	GPUCore        corev1.ResourceName = ResourceDomainPrefix + "gpu-core"
 	GPUMemory      corev1.ResourceName = ResourceDomainPrefix + "gpu-memory"
 	GPUMemoryRatio corev1.ResourceName = ResourceDomainPrefix + "gpu-memory-ratio"
+
+	// NvidiaMIGPrefix is the prefix of the resources requesting the MIG devices of a profile, e.g. nvidia.com/mig-1g.5gb
+	NvidiaMIGPrefix = "nvidia.com/mig-"
 )
 
+// MIGResourceName returns the resource name requesting the MIG devices of the profile.
+func MIGResourceName(profile string) corev1.ResourceName {
+	return corev1.ResourceName(NvidiaMIGPrefix + profile)
+}
+
+// GetMIGProfile returns the MIG profile if the resource requests the MIG devices.
+func GetMIGProfile(name corev1.ResourceName) (string, bool) {
+	if !strings.HasPrefix(string(name), NvidiaMIGPrefix) {
+		return "", false
+	}
+	profile := strings.TrimPrefix(string(name), NvidiaMIGPrefix)
+	return profile, profile != ""
+}
+
 const (
 	// AnnotationResourceSpec represents resource allocation API defined by Koordinator.
 	// The user specifies the desired CPU orchestration policy by setting the annotation.
@@ -68,6 +86,15 @@ type ResourceSpec struct {
 	PreferredCPUBindPolicy CPUBindPolicy `json:"preferredCPUBindPolicy,omitempty"`
 	// PreferredCPUExclusivePolicy represents best-effort CPU exclusive policy.
 	PreferredCPUExclusivePolicy CPUExclusivePolicy `json:"preferredCPUExclusivePolicy,omitempty"`
+	// ActiveWindows represents when the exclusive CPUs of the LSR Pod are in use, see ActiveWindows.
+	ActiveWindows ActiveWindows `json:"activeWindows,omitempty"`
+	// NUMATopologyPolicy represents the NUMA topology policy of the Pod, it overrides the node-wide policy.
+	NUMATopologyPolicy NUMATopologyPolicy `json:"numaTopologyPolicy,omitempty"`
+	// NUMAAllocateStrategy represents how the exclusive CPUs of the Pod are allocated across the NUMA Nodes,
+	// it overrides the node-wide and the default strategy.
+	NUMAAllocateStrategy NUMAAllocateStrategy `json:"numaAllocateStrategy,omitempty"`
+	// MemoryBindPolicy represents whether the memory of the Pod with exclusive CPUs is bound to the NUMA Nodes.
+	MemoryBindPolicy MemoryBindPolicy `json:"memoryBindPolicy,omitempty"`
 }
 
 // ResourceStatus describes resource allocation result, such as how to bind CPU.
@@ -77,6 +104,10 @@ type ResourceStatus struct {
 	CPUSet string `json:"cpuset,omitempty"`
 	// CPUSharedPools represents the desired CPU Shared Pools used by LS Pods.
 	CPUSharedPools []CPUSharedPool `json:"cpuSharedPools,omitempty"`
+	// NUMANodes represents the NUMA Nodes the Pod is bound to. It is Linux CPU list formatted string.
+	// koord-scheduler sets it when the NUMA topology policy is Restricted or SingleNUMANode or
+	// the MemoryBindPolicy is NUMALocal, and koordlet binds the memory of the containers to these NUMA Nodes.
+	NUMANodes string `json:"numaNodes,omitempty"`
 }
 
 // CPUBindPolicy defines the CPU binding policy
@@ -104,6 +135,71 @@ const (
 	CPUExclusivePolicyNUMANodeLevel CPUExclusivePolicy = schedulingconfig.CPUExclusivePolicyNUMANodeLevel
 )
 
+// NUMATopologyPolicy defines how strictly the resources of the Pod are aligned to the NUMA Nodes,
+// which has the same semantics as the kubelet topology manager policy.
+type NUMATopologyPolicy string
+
+const (
+	// NUMATopologyPolicyNone follows the node-wide NUMA topology policy
+	NUMATopologyPolicyNone NUMATopologyPolicy = ""
+	// NUMATopologyPolicyBestEffort prefers the allocation in the fewest NUMA Nodes, but never rejects the Pod
+	NUMATopologyPolicyBestEffort NUMATopologyPolicy = "BestEffort"
+	// NUMATopologyPolicyRestricted requires the allocation in the fewest NUMA Nodes the Pod can fit in
+	NUMATopologyPolicyRestricted NUMATopologyPolicy = "Restricted"
+	// NUMATopologyPolicySingleNUMANode requires the allocation in a single NUMA Node
+	NUMATopologyPolicySingleNUMANode NUMATopologyPolicy = "SingleNUMANode"
+)
+
+// IsValid returns whether the NUMATopologyPolicy is known.
+func (p NUMATopologyPolicy) IsValid() bool {
+	switch p {
+	case NUMATopologyPolicyNone, NUMATopologyPolicyBestEffort, NUMATopologyPolicyRestricted, NUMATopologyPolicySingleNUMANode:
+		return true
+	}
+	return false
+}
+
+// NUMAAllocateStrategy indicates how to choose satisfied NUMA Nodes
+type NUMAAllocateStrategy = schedulingconfig.NUMAAllocateStrategy
+
+const (
+	// NUMAMostAllocated packs the CPUs onto the NUMA Node with the least amount of available CPUs
+	NUMAMostAllocated NUMAAllocateStrategy = schedulingconfig.NUMAMostAllocated
+	// NUMALeastAllocated packs the CPUs onto the NUMA Node with the most amount of available CPUs
+	NUMALeastAllocated NUMAAllocateStrategy = schedulingconfig.NUMALeastAllocated
+	// NUMADistributeEvenly spreads the CPUs evenly across all the NUMA Nodes, which suits the memory-bandwidth-bound workloads
+	NUMADistributeEvenly NUMAAllocateStrategy = schedulingconfig.NUMADistributeEvenly
+)
+
+// IsValidNUMAAllocateStrategy returns whether the NUMAAllocateStrategy of the Pod is known, empty means not specified.
+func IsValidNUMAAllocateStrategy(strategy NUMAAllocateStrategy) bool {
+	switch strategy {
+	case "", NUMAMostAllocated, NUMALeastAllocated, NUMADistributeEvenly:
+		return true
+	}
+	return false
+}
+
+// MemoryBindPolicy defines how the memory of the Pod is bound to the NUMA Nodes
+type MemoryBindPolicy string
+
+const (
+	// MemoryBindPolicyNone doesn't bind the memory unless the NUMA topology policy requires the alignment
+	MemoryBindPolicyNone MemoryBindPolicy = ""
+	// MemoryBindPolicyNUMALocal binds the memory to the NUMA Nodes of the allocated CPUs (cpuset.mems),
+	// and the requested memory is accounted in these NUMA Nodes
+	MemoryBindPolicyNUMALocal MemoryBindPolicy = "NUMALocal"
+)
+
+// IsValid returns whether the MemoryBindPolicy is known.
+func (p MemoryBindPolicy) IsValid() bool {
+	switch p {
+	case MemoryBindPolicyNone, MemoryBindPolicyNUMALocal:
+		return true
+	}
+	return false
+}
+
 type NUMACPUSharedPools []CPUSharedPool
 
 type CPUSharedPool struct {
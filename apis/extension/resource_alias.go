@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+)
+
+// CanonicalDeviceResourceNames are the device resource names understood by koordinator, the vendor-specific
+// resource names can be aliased to one of them.
+var CanonicalDeviceResourceNames = []corev1.ResourceName{
+	NvidiaGPU, KoordGPU, GPUCore, GPUMemory, GPUMemoryRatio, KoordRDMA, KoordFPGA,
+}
+
+var (
+	resourceNameAliasesLock sync.RWMutex
+	resourceNameAliases     = map[corev1.ResourceName]corev1.ResourceName{}
+)
+
+// SetResourceNameAliases replaces the aliases which map the vendor-specific resource names onto the canonical
+// device resource names, e.g. amd.com/gpu=nvidia.com/gpu. They are applied consistently by the quota accounting,
+// the device allocation and the koordlet reporting.
+func SetResourceNameAliases(aliases map[string]string) error {
+	newAliases := make(map[corev1.ResourceName]corev1.ResourceName, len(aliases))
+	for alias, name := range aliases {
+		if err := validateResourceNameAlias(corev1.ResourceName(alias), corev1.ResourceName(name)); err != nil {
+			return err
+		}
+		newAliases[corev1.ResourceName(alias)] = corev1.ResourceName(name)
+	}
+
+	resourceNameAliasesLock.Lock()
+	defer resourceNameAliasesLock.Unlock()
+	resourceNameAliases = newAliases
+	return nil
+}
+
+func validateResourceNameAlias(alias, name corev1.ResourceName) error {
+	if isCanonicalDeviceResourceName(alias) {
+		return fmt.Errorf("resource name %s is canonical and can't be an alias", alias)
+	}
+	if !isCanonicalDeviceResourceName(name) {
+		return fmt.Errorf("resource name alias %s refers to %s, which is not a canonical device resource name", alias, name)
+	}
+	return nil
+}
+
+func isCanonicalDeviceResourceName(name corev1.ResourceName) bool {
+	for _, canonical := range CanonicalDeviceResourceNames {
+		if name == canonical {
+			return true
+		}
+	}
+	return false
+}
+
+// GetCanonicalResourceName returns the canonical resource name of the alias, or the name itself if it's not an alias.
+func GetCanonicalResourceName(name corev1.ResourceName) corev1.ResourceName {
+	resourceNameAliasesLock.RLock()
+	defer resourceNameAliasesLock.RUnlock()
+	if canonical, ok := resourceNameAliases[name]; ok {
+		return canonical
+	}
+	return name
+}
+
+// TranslateResourceNameAliases renames the aliased resources to the canonical names, the quantities are summed up
+// if both the alias and the canonical name exist. The original list is returned if there is no alias in it.
+func TranslateResourceNameAliases(resources corev1.ResourceList) corev1.ResourceList {
+	resourceNameAliasesLock.RLock()
+	defer resourceNameAliasesLock.RUnlock()
+	if len(resourceNameAliases) == 0 {
+		return resources
+	}
+
+	var translated corev1.ResourceList
+	for name, quantity := range resources {
+		canonical, ok := resourceNameAliases[name]
+		if !ok {
+			continue
+		}
+		if translated == nil {
+			translated = resources.DeepCopy()
+		}
+		delete(translated, name)
+		translated = quotav1.Add(translated, corev1.ResourceList{canonical: quantity})
+	}
+	if translated == nil {
+		return resources
+	}
+	return translated
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+)
+
+func TestTranslateResourceNameAliases(t *testing.T) {
+	defer SetResourceNameAliases(nil)
+
+	resources := corev1.ResourceList{
+		corev1.ResourceCPU: resource.MustParse("4"),
+		"amd.com/gpu":      resource.MustParse("1"),
+	}
+	// no alias is configured
+	assert.Equal(t, resources, TranslateResourceNameAliases(resources))
+
+	assert.Error(t, SetResourceNameAliases(map[string]string{string(NvidiaGPU): "amd.com/gpu"}))
+	assert.Error(t, SetResourceNameAliases(map[string]string{"amd.com/gpu": "example.com/unknown"}))
+	assert.NoError(t, SetResourceNameAliases(map[string]string{
+		"amd.com/gpu":        string(NvidiaGPU),
+		"example.com/rdma-x": string(KoordRDMA),
+	}))
+	assert.Equal(t, NvidiaGPU, GetCanonicalResourceName("amd.com/gpu"))
+	assert.Equal(t, corev1.ResourceCPU, GetCanonicalResourceName(corev1.ResourceCPU))
+
+	translated := TranslateResourceNameAliases(corev1.ResourceList{
+		corev1.ResourceCPU: resource.MustParse("4"),
+		"amd.com/gpu":      resource.MustParse("1"),
+		NvidiaGPU:          resource.MustParse("2"),
+	})
+	assert.True(t, quotav1.Equals(corev1.ResourceList{
+		corev1.ResourceCPU: resource.MustParse("4"),
+		NvidiaGPU:          resource.MustParse("3"),
+	}, translated))
+	// the original list is not changed
+	assert.Contains(t, resources, corev1.ResourceName("amd.com/gpu"))
+}
@@ -18,6 +18,7 @@ package extension
 
 import (
 	"encoding/json"
+	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -33,6 +34,21 @@ const (
 
 	// AnnotationReservationAllocated represents the reservation allocated by the pod.
 	AnnotationReservationAllocated = SchedulingDomainPrefix + "/reservation-allocated"
+
+	// AnnotationReservationRecreateOnPreemption indicates whether to re-create the preempted part of the reservation
+	// on another node when the reservation is preempted.
+	AnnotationReservationRecreateOnPreemption = SchedulingDomainPrefix + "/reservation-recreate-on-preemption"
+
+	// AnnotationReservationPreemptedFrom represents the name of the preempted reservation which the reservation is
+	// re-created from.
+	AnnotationReservationPreemptedFrom = SchedulingDomainPrefix + "/reservation-preempted-from"
+
+	// AnnotationReservationRelocatedFrom represents the name of the reservation which the reservation is relocated
+	// from, since the node of the original reservation is cordoned or not ready.
+	AnnotationReservationRelocatedFrom = SchedulingDomainPrefix + "/reservation-relocated-from"
+
+	// LabelReservationParent represents the name of the bulk reservation which creates the reservation for its replicas.
+	LabelReservationParent = SchedulingDomainPrefix + "/reservation-parent"
 )
 
 const (
@@ -42,8 +58,51 @@ const (
 
 	// AnnotationDeviceAllocated represents the device allocated by the pod
 	AnnotationDeviceAllocated = SchedulingDomainPrefix + "/device-allocated"
+
+	// AnnotationRemoteDeviceLatencyTolerance represents the highest latency class of the remote devices the pod tolerates.
+	// The pod without the annotation is never allocated the remote devices.
+	AnnotationRemoteDeviceLatencyTolerance = SchedulingDomainPrefix + "/remote-device-latency-tolerance"
+
+	// AnnotationGPUIsolationLevel represents how koordlet isolates the pod on the GPUs shared with the other pods.
+	// For specific value definitions, see GPUIsolationLevel.
+	AnnotationGPUIsolationLevel = SchedulingDomainPrefix + "/gpu-isolation-level"
+
+	// AnnotationGPUAllocationStrategy represents how the partial-GPU requests are placed on the GPUs. It can be set
+	// on the Pod or on the ElasticQuota of the Pod, and the Pod takes precedence.
+	// For specific value definitions, see GPUAllocationStrategy.
+	AnnotationGPUAllocationStrategy = SchedulingDomainPrefix + "/gpu-allocation-strategy"
+)
+
+type GPUAllocationStrategy string
+
+const (
+	// GPUAllocationStrategyBinpack packs the partial-GPU requests onto the most used GPUs, so the whole GPUs are
+	// kept available for the pods requesting them.
+	GPUAllocationStrategyBinpack GPUAllocationStrategy = "Binpack"
+	// GPUAllocationStrategySpread places the partial-GPU requests onto the least used GPUs to minimize the
+	// interference between the pods sharing a GPU.
+	GPUAllocationStrategySpread GPUAllocationStrategy = "Spread"
+)
+
+type GPUIsolationLevel string
+
+const (
+	// GPUIsolationLevelNone doesn't limit the pod on the shared GPUs, the pod is trusted to use the declared resources.
+	GPUIsolationLevelNone GPUIsolationLevel = "None"
+	// GPUIsolationLevelMemory limits the GPU memory of the pod to the allocated gpu-memory on each shared GPU.
+	GPUIsolationLevelMemory GPUIsolationLevel = "Memory"
+	// GPUIsolationLevelMemoryAndCore limits both the GPU memory and the compute utilization to the allocated
+	// gpu-memory and gpu-core on each shared GPU.
+	GPUIsolationLevelMemoryAndCore GPUIsolationLevel = "MemoryAndCore"
 )
 
+// DeviceResourceNames are the resources requesting each type of devices.
+var DeviceResourceNames = map[schedulingv1alpha1.DeviceType][]corev1.ResourceName{
+	schedulingv1alpha1.GPU:  {NvidiaGPU, KoordGPU, GPUCore, GPUMemory, GPUMemoryRatio},
+	schedulingv1alpha1.RDMA: {KoordRDMA},
+	schedulingv1alpha1.FPGA: {KoordFPGA},
+}
+
 const (
 	AnnotationGangPrefix = "gang.scheduling.koordinator.sh"
 	// AnnotationGangName specifies the name of the gang
@@ -67,10 +126,28 @@ const (
 	// The gang will go to bind only all gangs in one group meet the conditions
 	AnnotationGangGroups = AnnotationGangPrefix + "/groups"
 
+	// AnnotationGangParent specifies the parent PodGroup of the gang in the same namespace, e.g. the job of the role.
+	// All the gangs of the same parent are bundled as a group, which goes to bind only when the number of the gangs
+	// reaches the MinMember of the parent PodGroup and all the gangs meet the conditions.
+	AnnotationGangParent = AnnotationGangPrefix + "/parent"
+
 	// AnnotationGangTimeout means that the entire gang cannot be scheduled due to timeout
 	// The annotation is added by the scheduler when the gang times out
 	AnnotationGangTimeout = AnnotationGangPrefix + "/timeout"
 
+	// AnnotationGangMaxStragglers specifies how many children of the NonStrict gang can fail to be scheduled in a round,
+	// the rest of the children go to bind once they are all assumed. It's ignored in the Strict mode.
+	AnnotationGangMaxStragglers = AnnotationGangPrefix + "/max-stragglers"
+
+	// AnnotationGangMinResources specifies the minimum aggregate resources of the assumed and bound children in JSON,
+	// e.g. {"nvidia.com/gpu": "16"}. The gang goes to bind only when both the minimum number and the minimum resources
+	// are reached. The PodGroup specifies it by Spec.MinResources instead.
+	AnnotationGangMinResources = AnnotationGangPrefix + "/min-resources"
+
+	// AnnotationGangAssemblyStatus is the progress of assembling the gang, it's updated on the PodGroup by the scheduler
+	// so that users can see which child or node constraint blocks the gang without the scheduler logs.
+	AnnotationGangAssemblyStatus = AnnotationGangPrefix + "/assembly-status"
+
 	GangModeStrict    = "Strict"
 	GangModeNonStrict = "NonStrict"
 )
@@ -93,6 +170,29 @@ func GetCustomUsageThresholds(node *corev1.Node) (*CustomUsageThresholds, error)
 	return usageThresholds, nil
 }
 
+// GangAssemblyStatus is the value of AnnotationGangAssemblyStatus.
+type GangAssemblyStatus struct {
+	MinMember int32 `json:"minMember"`
+	Children  int32 `json:"children"`
+	// Waiting is the number of the children assumed and waiting in Permit stage.
+	Waiting int32 `json:"waiting"`
+	Bound   int32 `json:"bound"`
+	// FailedChildren is the last failure reason of the children neither assumed nor bound, keyed by the pod name.
+	FailedChildren map[string]string `json:"failedChildren,omitempty"`
+}
+
+func GetGangAssemblyStatus(annotations map[string]string) (*GangAssemblyStatus, error) {
+	data, ok := annotations[AnnotationGangAssemblyStatus]
+	if !ok {
+		return nil, nil
+	}
+	status := &GangAssemblyStatus{}
+	if err := json.Unmarshal([]byte(data), status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
 type ReservationAllocated struct {
 	Name string    `json:"name,omitempty"`
 	UID  types.UID `json:"uid,omitempty"`
@@ -166,6 +266,10 @@ type DeviceAllocations map[schedulingv1alpha1.DeviceType][]*DeviceAllocation
 type DeviceAllocation struct {
 	Minor     int32               `json:"minor"`
 	Resources corev1.ResourceList `json:"resources"`
+	// Endpoint is the address to attach the remote device, only set for the remote device types
+	Endpoint string `json:"endpoint,omitempty"`
+	// UUID is the UUID of the device to expose to the container, only set for the MIG devices
+	UUID string `json:"uuid,omitempty"`
 }
 
 func GetDeviceAllocations(podAnnotations map[string]string) (DeviceAllocations, error) {
@@ -181,6 +285,58 @@ func GetDeviceAllocations(podAnnotations map[string]string) (DeviceAllocations,
 	return deviceAllocations, nil
 }
 
+// GetGPUIsolationLevel returns the GPU isolation level of the pod, GPUIsolationLevelMemory if it is not specified.
+func GetGPUIsolationLevel(podAnnotations map[string]string) (GPUIsolationLevel, error) {
+	level, ok := podAnnotations[AnnotationGPUIsolationLevel]
+	if !ok || level == "" {
+		return GPUIsolationLevelMemory, nil
+	}
+	switch GPUIsolationLevel(level) {
+	case GPUIsolationLevelNone, GPUIsolationLevelMemory, GPUIsolationLevelMemoryAndCore:
+		return GPUIsolationLevel(level), nil
+	}
+	return "", fmt.Errorf("invalid gpu isolation level %q", level)
+}
+
+// GetGPUAllocationStrategy returns the GPU allocation strategy in the annotations, it's empty if not set.
+func GetGPUAllocationStrategy(annotations map[string]string) (GPUAllocationStrategy, error) {
+	strategy, ok := annotations[AnnotationGPUAllocationStrategy]
+	if !ok || strategy == "" {
+		return "", nil
+	}
+	switch GPUAllocationStrategy(strategy) {
+	case GPUAllocationStrategyBinpack, GPUAllocationStrategySpread:
+		return GPUAllocationStrategy(strategy), nil
+	}
+	return "", fmt.Errorf("invalid gpu allocation strategy %q", strategy)
+}
+
+var deviceLatencyClassOrders = map[schedulingv1alpha1.DeviceLatencyClass]int{
+	schedulingv1alpha1.DeviceLatencyClassLow:    1,
+	schedulingv1alpha1.DeviceLatencyClassMedium: 2,
+	schedulingv1alpha1.DeviceLatencyClassHigh:   3,
+}
+
+// GetRemoteDeviceLatencyTolerance returns the highest latency class of the remote devices the pod tolerates,
+// empty if the pod doesn't tolerate the remote devices.
+func GetRemoteDeviceLatencyTolerance(podAnnotations map[string]string) schedulingv1alpha1.DeviceLatencyClass {
+	latencyClass := schedulingv1alpha1.DeviceLatencyClass(podAnnotations[AnnotationRemoteDeviceLatencyTolerance])
+	if _, ok := deviceLatencyClassOrders[latencyClass]; !ok {
+		return ""
+	}
+	return latencyClass
+}
+
+// IsDeviceLatencyTolerated checks whether the remote device of the latency class is tolerated.
+func IsDeviceLatencyTolerated(latencyClass, tolerance schedulingv1alpha1.DeviceLatencyClass) bool {
+	order, ok := deviceLatencyClassOrders[latencyClass]
+	if !ok {
+		return false
+	}
+	toleranceOrder, ok := deviceLatencyClassOrders[tolerance]
+	return ok && order <= toleranceOrder
+}
+
 func SetDeviceAllocations(pod *corev1.Pod, allocations DeviceAllocations) error {
 	if pod.Annotations == nil {
 		pod.Annotations = map[string]string{}
@@ -138,3 +138,29 @@ func Test_SetDeviceAllocations(t *testing.T) {
 		})
 	}
 }
+
+func Test_GetGPUIsolationLevel(t *testing.T) {
+	level, err := GetGPUIsolationLevel(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, GPUIsolationLevelMemory, level)
+
+	level, err = GetGPUIsolationLevel(map[string]string{AnnotationGPUIsolationLevel: "MemoryAndCore"})
+	assert.NoError(t, err)
+	assert.Equal(t, GPUIsolationLevelMemoryAndCore, level)
+
+	_, err = GetGPUIsolationLevel(map[string]string{AnnotationGPUIsolationLevel: "Hard"})
+	assert.Error(t, err)
+}
+
+func Test_GetGPUAllocationStrategy(t *testing.T) {
+	strategy, err := GetGPUAllocationStrategy(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, GPUAllocationStrategy(""), strategy)
+
+	strategy, err = GetGPUAllocationStrategy(map[string]string{AnnotationGPUAllocationStrategy: "Spread"})
+	assert.NoError(t, err)
+	assert.Equal(t, GPUAllocationStrategySpread, strategy)
+
+	_, err = GetGPUAllocationStrategy(map[string]string{AnnotationGPUAllocationStrategy: "Pack"})
+	assert.Error(t, err)
+}
@@ -30,6 +30,13 @@ import (
 
 var (
 	defaultNodeMetricExpirationSeconds int64 = 180
+	defaultPodChurnWindowSeconds       int64 = 60
+	defaultForecastHorizonSeconds      int64 = 300
+	defaultForecastLevelSmoothing      int64 = 50
+	defaultForecastTrendSmoothing      int64 = 30
+	defaultMetricProviderCacheSeconds  int64 = 30
+	defaultMetricProviderNodeLabel           = "node"
+	defaultHotspotCooldownSeconds      int64 = 300
 
 	defaultResourceWeights = map[corev1.ResourceName]int64{
 		corev1.ResourceCPU:    1,
@@ -58,6 +65,7 @@ var (
 	}
 
 	defaultEnablePreemption = pointer.Bool(false)
+	defaultEnableRelocation = pointer.Bool(false)
 
 	defaultMinCandidateNodesPercentage      = pointer.Int32Ptr(10)
 	defaultMinCandidateNodesAbsolute        = pointer.Int32Ptr(100)
@@ -71,6 +79,24 @@ var (
 		corev1.ResourceMemory: *resource.NewQuantity(math.MaxInt64, resource.BinarySI),
 	}
 
+	defaultRuntimeCalculateStrategy = RuntimeCalculateStrategyWeightedFairShare
+	defaultMinQuotaOversellPercent  = pointer.Int64Ptr(100)
+
+	defaultAccountingVerificationPeriod    = 10 * time.Minute
+	defaultQuotaStatusSyncPeriod           = 10 * time.Second
+	defaultQuotaDeletionSyncPeriod         = 30 * time.Second
+	defaultQuotaMetricsRecordPeriod        = 30 * time.Second
+	defaultQuotaRuntimeHistoryInterval     = time.Minute
+	defaultQuotaRuntimeHistoryRetention    = time.Hour
+	defaultQuotaReservationCleanupInterval = 10 * time.Second
+	defaultSharedWeightProviderTimeout     = 5 * time.Second
+	defaultSharedWeightSyncPeriod          = time.Minute
+	defaultSharedWeightCacheTTL            = 10 * time.Minute
+	defaultSharedWeightTransitionPercent   = int64(100)
+	defaultUsageDecayHalfLife              = time.Hour
+	defaultUsageDecayPenaltyPercent        = int64(50)
+	defaultUsageDecayUpdateInterval        = 30 * time.Second
+
 	defaultTimeout           = 600 * time.Second
 	defaultControllerWorkers = 1
 )
@@ -92,6 +118,31 @@ func SetDefaults_LoadAwareSchedulingArgs(obj *LoadAwareSchedulingArgs) {
 	if len(obj.EstimatedScalingFactors) == 0 {
 		obj.EstimatedScalingFactors = defaultEstimatedScalingFactors
 	}
+	if obj.PodChurnWindowSeconds == nil {
+		obj.PodChurnWindowSeconds = pointer.Int64Ptr(defaultPodChurnWindowSeconds)
+	}
+	if obj.Forecast != nil {
+		if obj.Forecast.HorizonSeconds == nil {
+			obj.Forecast.HorizonSeconds = pointer.Int64Ptr(defaultForecastHorizonSeconds)
+		}
+		if obj.Forecast.LevelSmoothingPercent == nil {
+			obj.Forecast.LevelSmoothingPercent = pointer.Int64Ptr(defaultForecastLevelSmoothing)
+		}
+		if obj.Forecast.TrendSmoothingPercent == nil {
+			obj.Forecast.TrendSmoothingPercent = pointer.Int64Ptr(defaultForecastTrendSmoothing)
+		}
+	}
+	if obj.MetricProvider != nil {
+		if obj.MetricProvider.NodeLabel == "" {
+			obj.MetricProvider.NodeLabel = defaultMetricProviderNodeLabel
+		}
+		if obj.MetricProvider.CacheSeconds == nil {
+			obj.MetricProvider.CacheSeconds = pointer.Int64Ptr(defaultMetricProviderCacheSeconds)
+		}
+	}
+	if obj.HotspotCooldown != nil && obj.HotspotCooldown.CooldownSeconds == nil {
+		obj.HotspotCooldown.CooldownSeconds = pointer.Int64Ptr(defaultHotspotCooldownSeconds)
+	}
 }
 
 // SetDefaults_NodeNUMAResourceArgs sets the default parameters for NodeNUMANodeResource plugin.
@@ -108,6 +159,9 @@ func SetDefaults_ReservationArgs(obj *ReservationArgs) {
 	if obj.EnablePreemption == nil {
 		obj.EnablePreemption = defaultEnablePreemption
 	}
+	if obj.EnableRelocation == nil {
+		obj.EnableRelocation = defaultEnableRelocation
+	}
 }
 
 func SetDefaults_ElasticQuotaArgs(obj *ElasticQuotaArgs) {
@@ -126,6 +180,64 @@ func SetDefaults_ElasticQuotaArgs(obj *ElasticQuotaArgs) {
 	if len(obj.SystemQuotaGroupMax) == 0 {
 		obj.SystemQuotaGroupMax = defaultSystemQuotaGroupMax
 	}
+	if obj.RuntimeCalculateStrategy == "" {
+		obj.RuntimeCalculateStrategy = defaultRuntimeCalculateStrategy
+	}
+	if obj.PodAccounting != nil && obj.PodAccounting.UsedCountPolicy == "" {
+		obj.PodAccounting.UsedCountPolicy = PodUsedCountPolicyScheduled
+	}
+	if obj.ReservationAccountingPolicy == "" {
+		obj.ReservationAccountingPolicy = ReservationAccountingPolicyNone
+	}
+	if obj.MinQuotaOversellPercent == nil {
+		obj.MinQuotaOversellPercent = defaultMinQuotaOversellPercent
+	}
+	if obj.AccountingVerificationPeriod == nil {
+		obj.AccountingVerificationPeriod = &metav1.Duration{Duration: defaultAccountingVerificationPeriod}
+	}
+	if obj.QuotaStatusSyncPeriod == nil {
+		obj.QuotaStatusSyncPeriod = &metav1.Duration{Duration: defaultQuotaStatusSyncPeriod}
+	}
+	if obj.QuotaDeletionSyncPeriod == nil {
+		obj.QuotaDeletionSyncPeriod = &metav1.Duration{Duration: defaultQuotaDeletionSyncPeriod}
+	}
+	if obj.QuotaMetricsRecordPeriod == nil {
+		obj.QuotaMetricsRecordPeriod = &metav1.Duration{Duration: defaultQuotaMetricsRecordPeriod}
+	}
+	if obj.QuotaRuntimeHistoryInterval == nil {
+		obj.QuotaRuntimeHistoryInterval = &metav1.Duration{Duration: defaultQuotaRuntimeHistoryInterval}
+	}
+	if obj.QuotaRuntimeHistoryRetention == nil {
+		obj.QuotaRuntimeHistoryRetention = &metav1.Duration{Duration: defaultQuotaRuntimeHistoryRetention}
+	}
+	if obj.QuotaReservationCleanupInterval == nil {
+		obj.QuotaReservationCleanupInterval = &metav1.Duration{Duration: defaultQuotaReservationCleanupInterval}
+	}
+	if provider := obj.SharedWeightProvider; provider != nil {
+		if provider.Timeout.Duration == 0 {
+			provider.Timeout.Duration = defaultSharedWeightProviderTimeout
+		}
+		if provider.SyncPeriod.Duration == 0 {
+			provider.SyncPeriod.Duration = defaultSharedWeightSyncPeriod
+		}
+		if provider.CacheTTL.Duration == 0 {
+			provider.CacheTTL.Duration = defaultSharedWeightCacheTTL
+		}
+		if provider.TransitionPercent == 0 {
+			provider.TransitionPercent = defaultSharedWeightTransitionPercent
+		}
+	}
+	if usageDecay := obj.UsageDecay; usageDecay != nil {
+		if usageDecay.HalfLife.Duration == 0 {
+			usageDecay.HalfLife.Duration = defaultUsageDecayHalfLife
+		}
+		if usageDecay.PenaltyPercent == 0 {
+			usageDecay.PenaltyPercent = defaultUsageDecayPenaltyPercent
+		}
+		if usageDecay.UpdateInterval.Duration == 0 {
+			usageDecay.UpdateInterval.Duration = defaultUsageDecayUpdateInterval
+		}
+	}
 }
 
 func SetDefaults_CoschedulingArgs(obj *CoschedulingArgs) {
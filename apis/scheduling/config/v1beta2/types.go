@@ -20,6 +20,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	schedconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -36,13 +38,119 @@ type LoadAwareSchedulingArgs struct {
 	NodeMetricExpirationSeconds *int64 `json:"nodeMetricExpirationSeconds,omitempty"`
 	// ResourceWeights indicates the weights of resources.
 	// The weights of CPU and Memory are both 1 by default.
+	// The weight of pods scores the node by the number of pods against the allocatable pods.
 	ResourceWeights map[corev1.ResourceName]int64 `json:"resourceWeights,omitempty"`
 	// UsageThresholds indicates the resource utilization threshold.
 	// The default for CPU is 65%, and the default for memory is 95%.
+	// The threshold of pods filters the node by the number of pods against the allocatable pods.
 	UsageThresholds map[corev1.ResourceName]int64 `json:"usageThresholds,omitempty"`
 	// EstimatedScalingFactors indicates the factor when estimating resource usage.
 	// The default value of CPU is 85%, and the default value of Memory is 70%.
 	EstimatedScalingFactors map[corev1.ResourceName]int64 `json:"estimatedScalingFactors,omitempty"`
+	// PodChurnWindowSeconds indicates the window in seconds to count the pods started or stopped on the node.
+	// Default is 60 seconds.
+	PodChurnWindowSeconds *int64 `json:"podChurnWindowSeconds,omitempty"`
+	// PodChurnThreshold indicates the max number of pods started or stopped on the node in the window,
+	// the node exceeding it is filtered out. Zero means no limit.
+	PodChurnThreshold *int64 `json:"podChurnThreshold,omitempty"`
+	// PodChurnWeight indicates the weight of the pod churn in the score, the node with less pod churn
+	// against the PodChurnThreshold gets a higher score. Zero means the pod churn is not scored.
+	PodChurnWeight *int64 `json:"podChurnWeight,omitempty"`
+	// LocalStorageIOUtilizationThreshold indicates the IO utilization threshold in percentage of the local
+	// storage reported in NodeMetric, the node whose local storage requested by the pod exceeds it is filtered out.
+	// Zero means no limit.
+	LocalStorageIOUtilizationThreshold *int64 `json:"localStorageIOUtilizationThreshold,omitempty"`
+	// LocalStorageWeight indicates the weight of the local storage in the score, the node with more free capacity
+	// and less IO utilization of the requested local storage gets a higher score. Zero means the local storage
+	// is not scored.
+	LocalStorageWeight *int64 `json:"localStorageWeight,omitempty"`
+	// Aggregated indicates the aggregated node usage reported in NodeMetric used to score the node instead of
+	// the latest average usage, so that the transiently idle but historically hot nodes are avoided.
+	// Nil means the latest average usage is used.
+	Aggregated *LoadAwareSchedulingAggregatedArgs `json:"aggregated,omitempty"`
+	// UsageThresholdProfiles indicates the profiles overriding the UsageThresholds and ResourceWeights for the
+	// selected pods, e.g. the batch pods may be scheduled to the nodes with higher utilization than the prod pods.
+	// The first matched profile takes effect, and the UsageThresholds annotated on the node still take precedence.
+	UsageThresholdProfiles []LoadAwareSchedulingUsageThresholdProfile `json:"usageThresholdProfiles,omitempty"`
+	// Forecast indicates scoring the node by the usage projected ahead from the recent NodeMetric history,
+	// so that the nodes ramping up are avoided. It takes precedence over the Aggregated if the projection is
+	// available. Nil means disabled.
+	Forecast *LoadAwareSchedulingForecastArgs `json:"forecast,omitempty"`
+	// MetricProvider indicates the custom metric source of the node usage, e.g. Prometheus. The usage provided
+	// overrides the usage reported in NodeMetric of the same resource. Nil means only NodeMetric is used.
+	MetricProvider *LoadAwareSchedulingMetricProviderArgs `json:"metricProvider,omitempty"`
+	// HotspotCooldown indicates filtering the node for a period after its usage crosses the critical thresholds,
+	// even if the usage dips, to prevent the oscillating placements onto a flapping node. Nil means disabled.
+	HotspotCooldown *LoadAwareSchedulingHotspotCooldownArgs `json:"hotspotCooldown,omitempty"`
+}
+
+// LoadAwareSchedulingHotspotCooldownArgs holds the arguments of the node hotspot cooldown.
+type LoadAwareSchedulingHotspotCooldownArgs struct {
+	// CriticalThresholds indicates the critical usage thresholds in percentage, the node is a hotspot if the
+	// usage of any resource crosses it. The resources provided by the MetricProvider are supported, e.g. PSI.
+	CriticalThresholds map[corev1.ResourceName]int64 `json:"criticalThresholds,omitempty"`
+	// CooldownSeconds indicates the period in seconds the hotspot is filtered since it is last seen as a
+	// hotspot. Default is 300 seconds.
+	CooldownSeconds *int64 `json:"cooldownSeconds,omitempty"`
+}
+
+// MetricProviderType is the type of the custom metric source.
+type MetricProviderType string
+
+const (
+	// MetricProviderPrometheus queries the node usage by the instant queries of the Prometheus HTTP API.
+	MetricProviderPrometheus MetricProviderType = "Prometheus"
+)
+
+// LoadAwareSchedulingMetricProviderArgs holds the arguments to query the node usage from the custom metric source.
+type LoadAwareSchedulingMetricProviderArgs struct {
+	// Type indicates the type of the metric provider.
+	Type MetricProviderType `json:"type,omitempty"`
+	// Address indicates the address of the metric source, e.g. http://prometheus.monitoring:9090.
+	Address string `json:"address,omitempty"`
+	// NodeLabel indicates the label of the query result identifying the node. Default is "node".
+	NodeLabel string `json:"nodeLabel,omitempty"`
+	// Queries indicates the query of the usage of each resource, which returns one sample per node. The usage is
+	// in the same units as the allocatable of the node, or in percentage if the node has no allocatable of the
+	// resource, e.g. the saturation of the application QPS.
+	Queries map[corev1.ResourceName]string `json:"queries,omitempty"`
+	// CacheSeconds indicates the interval in seconds to refresh the cached node usage. Default is 30 seconds.
+	CacheSeconds *int64 `json:"cacheSeconds,omitempty"`
+}
+
+// LoadAwareSchedulingForecastArgs holds the arguments to project the node usage by double exponential smoothing (Holt).
+type LoadAwareSchedulingForecastArgs struct {
+	// HorizonSeconds indicates how far ahead in seconds the node usage is projected. Default is 300 seconds.
+	HorizonSeconds *int64 `json:"horizonSeconds,omitempty"`
+	// LevelSmoothingPercent indicates the smoothing factor of the usage level in percentage, the higher the more
+	// the latest usage is trusted. Default is 50.
+	LevelSmoothingPercent *int64 `json:"levelSmoothingPercent,omitempty"`
+	// TrendSmoothingPercent indicates the smoothing factor of the usage trend in percentage, the higher the more
+	// the latest trend is trusted. Zero means the trend is ignored, which degrades to EWMA. Default is 30.
+	TrendSmoothingPercent *int64 `json:"trendSmoothingPercent,omitempty"`
+}
+
+// LoadAwareSchedulingUsageThresholdProfile selects the pods by namespace and priority class to override
+// the UsageThresholds and ResourceWeights.
+type LoadAwareSchedulingUsageThresholdProfile struct {
+	// Namespaces indicates the namespaces of the selected pods, empty means all namespaces.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// PriorityClasses indicates the koordinator priority classes of the selected pods, e.g. koord-prod or
+	// koord-batch, empty means all priority classes.
+	PriorityClasses []string `json:"priorityClasses,omitempty"`
+	// UsageThresholds overrides the UsageThresholds in Filter for the selected pods if not empty.
+	UsageThresholds map[corev1.ResourceName]int64 `json:"usageThresholds,omitempty"`
+	// ResourceWeights overrides the ResourceWeights in Score for the selected pods if not empty.
+	ResourceWeights map[corev1.ResourceName]int64 `json:"resourceWeights,omitempty"`
+}
+
+// LoadAwareSchedulingAggregatedArgs holds the arguments to use the aggregated node usage.
+type LoadAwareSchedulingAggregatedArgs struct {
+	// ScoreAggregationType indicates the aggregation type of the node usage used in scoring, e.g. p95 or p99.
+	ScoreAggregationType slov1alpha1.AggregationType `json:"scoreAggregationType,omitempty"`
+	// ScoreAggregatedDuration indicates the duration of the aggregated node usage used in scoring.
+	// Zero means the longest duration reported in NodeMetric.
+	ScoreAggregatedDuration metav1.Duration `json:"scoreAggregatedDuration,omitempty"`
 }
 
 // ScoringStrategyType is a "string" type.
@@ -122,6 +230,12 @@ type ReservationArgs struct {
 
 	// EnablePreemption indicates whether to enable preemption for reservations.
 	EnablePreemption *bool `json:"enablePreemption,omitempty"`
+	// PreemptionPriorityThreshold is the priority below which the reservations can be preempted by the pending pods
+	// with higher priority. If it is unset, any reservation with lower priority than the pending pod can be preempted.
+	PreemptionPriorityThreshold *int32 `json:"preemptionPriorityThreshold,omitempty"`
+	// EnableRelocation indicates whether to relocate the reservations on the cordoned or not ready nodes to the
+	// healthy nodes.
+	EnableRelocation *bool `json:"enableRelocation,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -150,10 +264,189 @@ type ElasticQuotaArgs struct {
 	// DefaultQuotaGroupMax limit the maxQuota of DefaultQuotaGroup
 	DefaultQuotaGroupMax corev1.ResourceList `json:"defaultQuotaGroupMax,omitempty"`
 
+	// DefaultQuotaGroupMin is the guaranteed resource of DefaultQuotaGroup, which absorbs the pods without
+	// the quota label. It is reserved from the cluster, so the quota trees can't share it even if it's unused.
+	DefaultQuotaGroupMin corev1.ResourceList `json:"defaultQuotaGroupMin,omitempty"`
+
 	// SystemQuotaGroupMax limit the maxQuota of SystemQuotaGroup
 	SystemQuotaGroupMax corev1.ResourceList `json:"systemQuotaGroupMax,omitempty"`
+
+	// RuntimeCalculateStrategy is the strategy to distribute the resource of the parent quota group
+	// to its children. Defaults to WeightedFairShare.
+	RuntimeCalculateStrategy RuntimeCalculateStrategyType `json:"runtimeCalculateStrategy,omitempty"`
+
+	// MinQuotaPriorityClasses are the priority classes of the pods whose request is counted toward the min,
+	// the request of the other pods competes for the shared resource only. Empty means all the pods.
+	MinQuotaPriorityClasses []string `json:"minQuotaPriorityClasses,omitempty"`
+
+	// PriorityAging raises the in-group scheduling priority of the pods pending long for the quota contention,
+	// so the small old jobs are not starved by the continuous streams of new big ones. It takes effect when the
+	// plugin is enabled at the queueSort instead of the PrioritySort. Nil means disabled.
+	PriorityAging *PriorityAgingArgs `json:"priorityAging,omitempty"`
+
+	// PodAccounting decides which pods are counted in the Request and the Used of the quota groups. Nil counts
+	// the pods from they are assumed until they are terminal.
+	PodAccounting *PodAccountingArgs `json:"podAccounting,omitempty"`
+
+	// ReservationAccountingPolicy decides how the resource reserved by the active Reservations but not allocated
+	// by their owners yet is charged to the quota groups of the Reservations. Defaults to None.
+	ReservationAccountingPolicy ReservationAccountingPolicy `json:"reservationAccountingPolicy,omitempty"`
+
+	// MinQuotaOversellPercent allows the sum of the children's min to be up to the percent of the parent's
+	// resource before the children's min are scaled down, the quota group with the annotation
+	// extension.AnnotationMinQuotaOversellRatio overrides it. Defaults to 100, i.e. no oversell.
+	MinQuotaOversellPercent *int64 `json:"minQuotaOversellPercent,omitempty"`
+
+	// AccountingVerificationPeriod is the period to recompute the Request/Used of the quota groups from the pods
+	// and correct the drift, it only works when the feature ElasticQuotaAccountingVerification is enabled.
+	// Defaults to 10 minutes.
+	AccountingVerificationPeriod *metav1.Duration `json:"accountingVerificationPeriod,omitempty"`
+
+	// SharedWeightProvider overrides the SharedWeight of the quota groups by an external source, nil means disabled.
+	SharedWeightProvider *SharedWeightProviderArgs `json:"sharedWeightProvider,omitempty"`
+
+	// FederatedLending lets the root quota trees lend their idle min to each other, nil means disabled.
+	FederatedLending *FederatedLendingArgs `json:"federatedLending,omitempty"`
+
+	// UsageDecay lowers the SharedWeight of the quota groups which have used lots of shared resource recently,
+	// nil means disabled.
+	UsageDecay *UsageDecayArgs `json:"usageDecay,omitempty"`
+
+	// QuotaStatusSyncPeriod is the period to publish the Used, Request and Runtime of the quota groups to the
+	// ElasticQuotas. Defaults to 10 seconds.
+	QuotaStatusSyncPeriod *metav1.Duration `json:"quotaStatusSyncPeriod,omitempty"`
+
+	// BatchRecalculateInterval batches the request changes of the quota groups and propagates them up the quota
+	// tree every interval instead of on each pod event, the request and the runtime may be stale for one interval
+	// at most. Nil or zero means disabled.
+	BatchRecalculateInterval *metav1.Duration `json:"batchRecalculateInterval,omitempty"`
+
+	// QuotaDeletionSyncPeriod is the period to add the finalizer to the ElasticQuotas and finish the deletion of the
+	// drained ones, the deletion is also synced on the ElasticQuota events. Defaults to 30 seconds.
+	QuotaDeletionSyncPeriod *metav1.Duration `json:"quotaDeletionSyncPeriod,omitempty"`
+
+	// QuotaMetricsRecordPeriod is the period to export the runtime, used, request, min, max and the dominant share
+	// of the quota groups to the metrics. Defaults to 30 seconds.
+	QuotaMetricsRecordPeriod *metav1.Duration `json:"quotaMetricsRecordPeriod,omitempty"`
+
+	// QuotaRuntimeHistoryInterval is the interval to sample the runtime, used and request of the quota groups for
+	// the runtime history. Defaults to 1 minute.
+	QuotaRuntimeHistoryInterval *metav1.Duration `json:"quotaRuntimeHistoryInterval,omitempty"`
+
+	// QuotaRuntimeHistoryRetention is how long the samples of the runtime history are kept, zero means the history
+	// is not recorded. Defaults to 1 hour.
+	QuotaRuntimeHistoryRetention *metav1.Duration `json:"quotaRuntimeHistoryRetention,omitempty"`
+
+	// PodQuotaAnnotationSyncPeriod is the period to annotate the scheduled pods with the quota group they are charged
+	// to and the runtime of the quota group at admission. Nil or zero means disabled.
+	PodQuotaAnnotationSyncPeriod *metav1.Duration `json:"podQuotaAnnotationSyncPeriod,omitempty"`
+
+	// QuotaReservationCleanupInterval is the interval to release the expired quota reservations. Defaults to 10 seconds.
+	QuotaReservationCleanupInterval *metav1.Duration `json:"quotaReservationCleanupInterval,omitempty"`
+}
+
+// PriorityAgingArgs defines how the in-group scheduling priority of the pods rises with the waiting time.
+// The priority of the pod itself is not changed, so the preemption is not affected.
+type PriorityAgingArgs struct {
+	// Interval is the waiting time to raise the priority one Step.
+	Interval metav1.Duration `json:"interval,omitempty"`
+	// Step is the priority raised each Interval.
+	Step int32 `json:"step,omitempty"`
+	// MaxBoost is the max priority raised, 0 means unlimited.
+	MaxBoost int32 `json:"maxBoost,omitempty"`
+}
+
+// FederatedLendingArgs configures the lending between the root quota trees, i.e. the children of the root quota
+// group. The members lend their idle min to each other even if they don't allow lending their resource, and the
+// lent resource is reclaimed once the demand of the lender returns.
+type FederatedLendingArgs struct {
+	// Members are the names of the root quota trees joining the federation, empty means all of them.
+	Members []string `json:"members,omitempty"`
+}
+
+// SharedWeightProviderArgs configures the external source of the SharedWeight of the quota groups.
+type SharedWeightProviderArgs struct {
+	// URL is the HTTP endpoint returning the SharedWeight of the quota groups in a JSON object from the quota
+	// name to the SharedWeight.
+	URL string `json:"url,omitempty"`
+	// Timeout is the timeout of each request. Defaults to 5 seconds.
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+	// SyncPeriod is the period to get the SharedWeight from the URL. Defaults to 1 minute.
+	SyncPeriod metav1.Duration `json:"syncPeriod,omitempty"`
+	// CacheTTL is how long the last SharedWeight is used when the URL fails, the SharedWeight of the quota's
+	// annotation is used after that. Defaults to 10 minutes.
+	CacheTTL metav1.Duration `json:"cacheTTL,omitempty"`
+	// TransitionPercent is the percent of the difference between the current and the target SharedWeight
+	// moved in each sync, so the runtime quota changes smoothly. Defaults to 100, i.e. changing at once.
+	TransitionPercent int64 `json:"transitionPercent,omitempty"`
 }
 
+// UsageDecayArgs configures the usage decay of the SharedWeight. The integral of the shared resource used by each
+// quota group, i.e. the used exceeding the min, decays by half every HalfLife, and the SharedWeight of the quota
+// group is lowered in proportion to its decayed usage.
+type UsageDecayArgs struct {
+	// HalfLife is the half-life of the usage integral. Defaults to 1 hour.
+	HalfLife metav1.Duration `json:"halfLife,omitempty"`
+	// PenaltyPercent is the max percent the SharedWeight is lowered, in (0, 100). Defaults to 50.
+	PenaltyPercent int64 `json:"penaltyPercent,omitempty"`
+	// UpdateInterval is the interval to accumulate the usage and update the SharedWeight. Defaults to 30 seconds.
+	UpdateInterval metav1.Duration `json:"updateInterval,omitempty"`
+}
+
+// PodAccountingArgs excludes the pods distorting the quota accounting, e.g. the pods stuck in Pending on the
+// assigned nodes. It's applied consistently to all the pod events and the accounting verification.
+type PodAccountingArgs struct {
+	// UsedCountPolicy decides when the pod starts to be counted in the Used. Defaults to Scheduled.
+	UsedCountPolicy PodUsedCountPolicy `json:"usedCountPolicy,omitempty"`
+	// PendingTimeout excludes the pods staying Pending on the assigned nodes longer than it, e.g. the pods
+	// failing to pull the image, until they start running. 0 means never.
+	PendingTimeout metav1.Duration `json:"pendingTimeout,omitempty"`
+	// TerminalGracePeriod keeps counting the Succeeded or Failed pods in the Used for the period after they
+	// finish, e.g. to wait for the devices to be released. 0 excludes them immediately.
+	TerminalGracePeriod metav1.Duration `json:"terminalGracePeriod,omitempty"`
+}
+
+// PodUsedCountPolicy is the policy to count the pods in the Used of the quota groups.
+type PodUsedCountPolicy string
+
+const (
+	// PodUsedCountPolicyScheduled counts the pods in the Used once they are assumed by the scheduler.
+	PodUsedCountPolicyScheduled PodUsedCountPolicy = "Scheduled"
+	// PodUsedCountPolicyRunning counts the pods in the Used only when they are running, the scheduled pods not
+	// running yet are counted in the Request only. The quota groups may be overcommitted while the pods start.
+	PodUsedCountPolicyRunning PodUsedCountPolicy = "Running"
+)
+
+// ReservationAccountingPolicy is the policy to charge the Reservations to the quota groups.
+type ReservationAccountingPolicy string
+
+const (
+	// ReservationAccountingPolicyNone doesn't charge the Reservations, only the owner pods are counted.
+	ReservationAccountingPolicyNone ReservationAccountingPolicy = "None"
+	// ReservationAccountingPolicyRequest charges the unallocated resource of the Reservations to the Request, so the
+	// runtime quota covers the reserved resource, but the quota groups can still lend it out.
+	ReservationAccountingPolicyRequest ReservationAccountingPolicy = "Request"
+	// ReservationAccountingPolicyRequestAndUsed charges the unallocated resource of the Reservations to both the
+	// Request and the Used, so the reserved resource can't be used by the other pods of the quota groups.
+	ReservationAccountingPolicyRequestAndUsed ReservationAccountingPolicy = "RequestAndUsed"
+)
+
+// RuntimeCalculateStrategyType is the name of the strategy to calculate the runtime quota.
+type RuntimeCalculateStrategyType string
+
+const (
+	// RuntimeCalculateStrategyWeightedFairShare guarantees the min of each quota group, and distributes the rest
+	// to the quota groups requesting more than their min in proportion to the SharedWeight.
+	RuntimeCalculateStrategyWeightedFairShare RuntimeCalculateStrategyType = "WeightedFairShare"
+	// RuntimeCalculateStrategyPriorityStrict guarantees the min of each quota group, and distributes the rest
+	// to the quota groups in descending order of the SharedWeight, a quota group gets nothing more until
+	// all the quota groups with higher SharedWeight are satisfied.
+	RuntimeCalculateStrategyPriorityStrict RuntimeCalculateStrategyType = "PriorityStrict"
+	// RuntimeCalculateStrategyDominantResourceFairness guarantees the min of each quota group, and distributes
+	// the rest across all the resource dimensions together to balance the dominant share of the quota groups.
+	RuntimeCalculateStrategyDominantResourceFairness RuntimeCalculateStrategyType = "DominantResourceFairness"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // CoschedulingArgs defines the parameters for Gang Scheduling plugin.
@@ -167,3 +460,30 @@ type CoschedulingArgs struct {
 	// default is 1
 	ControllerWorkers *int64 `json:"controllerWorkers,omitempty"`
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DeviceShareArgs defines the parameters for DeviceShare plugin.
+type DeviceShareArgs struct {
+	metav1.TypeMeta
+
+	// CustomDeviceTypes registers the third-party devices, e.g. the NPUs, reported in the Device CR besides
+	// the built-in device types.
+	CustomDeviceTypes []CustomDeviceType `json:"customDeviceTypes,omitempty"`
+}
+
+// CustomDeviceType declares how the devices of a third-party type are requested and allocated.
+type CustomDeviceType struct {
+	// Type is the device type reported in the Device CR, e.g. npu.
+	Type string `json:"type"`
+	// ResourceNames are the resources requesting the devices. The first one is reported by each device in the
+	// Device CR, the others are its aliases, e.g. [example.com/npu, example.com/npu-legacy].
+	ResourceNames []corev1.ResourceName `json:"resourceNames"`
+	// Shareable means a device can be shared by the pods like the RDMA, each device reports 100 of the resource,
+	// and the pod requests less than 100 to share one device or multiples of 100 to get whole devices.
+	// Otherwise, the pod requests the number of the devices, and each device is allocated to one pod exclusively.
+	Shareable bool `json:"shareable,omitempty"`
+	// TopologyAware prefers the devices under the same PCIe switch or on the same NUMA Node with the allocated
+	// GPUs, according to the topology reported in the Device CR.
+	TopologyAware bool `json:"topologyAware,omitempty"`
+}
@@ -25,6 +25,7 @@ import (
 	unsafe "unsafe"
 
 	config "github.com/koordinator-sh/koordinator/apis/scheduling/config"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
@@ -49,6 +50,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*CustomDeviceType)(nil), (*config.CustomDeviceType)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_CustomDeviceType_To_config_CustomDeviceType(a.(*CustomDeviceType), b.(*config.CustomDeviceType), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.CustomDeviceType)(nil), (*CustomDeviceType)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_CustomDeviceType_To_v1beta2_CustomDeviceType(a.(*config.CustomDeviceType), b.(*CustomDeviceType), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*DeviceShareArgs)(nil), (*config.DeviceShareArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_DeviceShareArgs_To_config_DeviceShareArgs(a.(*DeviceShareArgs), b.(*config.DeviceShareArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.DeviceShareArgs)(nil), (*DeviceShareArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_DeviceShareArgs_To_v1beta2_DeviceShareArgs(a.(*config.DeviceShareArgs), b.(*DeviceShareArgs), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ElasticQuotaArgs)(nil), (*config.ElasticQuotaArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_ElasticQuotaArgs_To_config_ElasticQuotaArgs(a.(*ElasticQuotaArgs), b.(*config.ElasticQuotaArgs), scope)
 	}); err != nil {
@@ -59,6 +80,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*FederatedLendingArgs)(nil), (*config.FederatedLendingArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_FederatedLendingArgs_To_config_FederatedLendingArgs(a.(*FederatedLendingArgs), b.(*config.FederatedLendingArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.FederatedLendingArgs)(nil), (*FederatedLendingArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_FederatedLendingArgs_To_v1beta2_FederatedLendingArgs(a.(*config.FederatedLendingArgs), b.(*FederatedLendingArgs), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*LoadAwareSchedulingArgs)(nil), (*config.LoadAwareSchedulingArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_LoadAwareSchedulingArgs_To_config_LoadAwareSchedulingArgs(a.(*LoadAwareSchedulingArgs), b.(*config.LoadAwareSchedulingArgs), scope)
 	}); err != nil {
@@ -69,6 +100,56 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*LoadAwareSchedulingAggregatedArgs)(nil), (*config.LoadAwareSchedulingAggregatedArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_LoadAwareSchedulingAggregatedArgs_To_config_LoadAwareSchedulingAggregatedArgs(a.(*LoadAwareSchedulingAggregatedArgs), b.(*config.LoadAwareSchedulingAggregatedArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.LoadAwareSchedulingAggregatedArgs)(nil), (*LoadAwareSchedulingAggregatedArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_LoadAwareSchedulingAggregatedArgs_To_v1beta2_LoadAwareSchedulingAggregatedArgs(a.(*config.LoadAwareSchedulingAggregatedArgs), b.(*LoadAwareSchedulingAggregatedArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*LoadAwareSchedulingForecastArgs)(nil), (*config.LoadAwareSchedulingForecastArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_LoadAwareSchedulingForecastArgs_To_config_LoadAwareSchedulingForecastArgs(a.(*LoadAwareSchedulingForecastArgs), b.(*config.LoadAwareSchedulingForecastArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.LoadAwareSchedulingForecastArgs)(nil), (*LoadAwareSchedulingForecastArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_LoadAwareSchedulingForecastArgs_To_v1beta2_LoadAwareSchedulingForecastArgs(a.(*config.LoadAwareSchedulingForecastArgs), b.(*LoadAwareSchedulingForecastArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*LoadAwareSchedulingHotspotCooldownArgs)(nil), (*config.LoadAwareSchedulingHotspotCooldownArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_LoadAwareSchedulingHotspotCooldownArgs_To_config_LoadAwareSchedulingHotspotCooldownArgs(a.(*LoadAwareSchedulingHotspotCooldownArgs), b.(*config.LoadAwareSchedulingHotspotCooldownArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.LoadAwareSchedulingHotspotCooldownArgs)(nil), (*LoadAwareSchedulingHotspotCooldownArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_LoadAwareSchedulingHotspotCooldownArgs_To_v1beta2_LoadAwareSchedulingHotspotCooldownArgs(a.(*config.LoadAwareSchedulingHotspotCooldownArgs), b.(*LoadAwareSchedulingHotspotCooldownArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*LoadAwareSchedulingMetricProviderArgs)(nil), (*config.LoadAwareSchedulingMetricProviderArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_LoadAwareSchedulingMetricProviderArgs_To_config_LoadAwareSchedulingMetricProviderArgs(a.(*LoadAwareSchedulingMetricProviderArgs), b.(*config.LoadAwareSchedulingMetricProviderArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.LoadAwareSchedulingMetricProviderArgs)(nil), (*LoadAwareSchedulingMetricProviderArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_LoadAwareSchedulingMetricProviderArgs_To_v1beta2_LoadAwareSchedulingMetricProviderArgs(a.(*config.LoadAwareSchedulingMetricProviderArgs), b.(*LoadAwareSchedulingMetricProviderArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*LoadAwareSchedulingUsageThresholdProfile)(nil), (*config.LoadAwareSchedulingUsageThresholdProfile)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_LoadAwareSchedulingUsageThresholdProfile_To_config_LoadAwareSchedulingUsageThresholdProfile(a.(*LoadAwareSchedulingUsageThresholdProfile), b.(*config.LoadAwareSchedulingUsageThresholdProfile), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.LoadAwareSchedulingUsageThresholdProfile)(nil), (*LoadAwareSchedulingUsageThresholdProfile)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_LoadAwareSchedulingUsageThresholdProfile_To_v1beta2_LoadAwareSchedulingUsageThresholdProfile(a.(*config.LoadAwareSchedulingUsageThresholdProfile), b.(*LoadAwareSchedulingUsageThresholdProfile), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NodeNUMAResourceArgs)(nil), (*config.NodeNUMAResourceArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_NodeNUMAResourceArgs_To_config_NodeNUMAResourceArgs(a.(*NodeNUMAResourceArgs), b.(*config.NodeNUMAResourceArgs), scope)
 	}); err != nil {
@@ -79,6 +160,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PodAccountingArgs)(nil), (*config.PodAccountingArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_PodAccountingArgs_To_config_PodAccountingArgs(a.(*PodAccountingArgs), b.(*config.PodAccountingArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.PodAccountingArgs)(nil), (*PodAccountingArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_PodAccountingArgs_To_v1beta2_PodAccountingArgs(a.(*config.PodAccountingArgs), b.(*PodAccountingArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PriorityAgingArgs)(nil), (*config.PriorityAgingArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_PriorityAgingArgs_To_config_PriorityAgingArgs(a.(*PriorityAgingArgs), b.(*config.PriorityAgingArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.PriorityAgingArgs)(nil), (*PriorityAgingArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_PriorityAgingArgs_To_v1beta2_PriorityAgingArgs(a.(*config.PriorityAgingArgs), b.(*PriorityAgingArgs), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ReservationArgs)(nil), (*config.ReservationArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_ReservationArgs_To_config_ReservationArgs(a.(*ReservationArgs), b.(*config.ReservationArgs), scope)
 	}); err != nil {
@@ -99,6 +200,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SharedWeightProviderArgs)(nil), (*config.SharedWeightProviderArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_SharedWeightProviderArgs_To_config_SharedWeightProviderArgs(a.(*SharedWeightProviderArgs), b.(*config.SharedWeightProviderArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.SharedWeightProviderArgs)(nil), (*SharedWeightProviderArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_SharedWeightProviderArgs_To_v1beta2_SharedWeightProviderArgs(a.(*config.SharedWeightProviderArgs), b.(*SharedWeightProviderArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*UsageDecayArgs)(nil), (*config.UsageDecayArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta2_UsageDecayArgs_To_config_UsageDecayArgs(a.(*UsageDecayArgs), b.(*config.UsageDecayArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.UsageDecayArgs)(nil), (*UsageDecayArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_UsageDecayArgs_To_v1beta2_UsageDecayArgs(a.(*config.UsageDecayArgs), b.(*UsageDecayArgs), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -124,12 +245,77 @@ func Convert_config_CoschedulingArgs_To_v1beta2_CoschedulingArgs(in *config.Cosc
 	return autoConvert_config_CoschedulingArgs_To_v1beta2_CoschedulingArgs(in, out, s)
 }
 
+func autoConvert_v1beta2_CustomDeviceType_To_config_CustomDeviceType(in *CustomDeviceType, out *config.CustomDeviceType, s conversion.Scope) error {
+	out.Type = in.Type
+	out.ResourceNames = *(*[]corev1.ResourceName)(unsafe.Pointer(&in.ResourceNames))
+	out.Shareable = in.Shareable
+	out.TopologyAware = in.TopologyAware
+	return nil
+}
+
+// Convert_v1beta2_CustomDeviceType_To_config_CustomDeviceType is an autogenerated conversion function.
+func Convert_v1beta2_CustomDeviceType_To_config_CustomDeviceType(in *CustomDeviceType, out *config.CustomDeviceType, s conversion.Scope) error {
+	return autoConvert_v1beta2_CustomDeviceType_To_config_CustomDeviceType(in, out, s)
+}
+
+func autoConvert_config_CustomDeviceType_To_v1beta2_CustomDeviceType(in *config.CustomDeviceType, out *CustomDeviceType, s conversion.Scope) error {
+	out.Type = in.Type
+	out.ResourceNames = *(*[]corev1.ResourceName)(unsafe.Pointer(&in.ResourceNames))
+	out.Shareable = in.Shareable
+	out.TopologyAware = in.TopologyAware
+	return nil
+}
+
+// Convert_config_CustomDeviceType_To_v1beta2_CustomDeviceType is an autogenerated conversion function.
+func Convert_config_CustomDeviceType_To_v1beta2_CustomDeviceType(in *config.CustomDeviceType, out *CustomDeviceType, s conversion.Scope) error {
+	return autoConvert_config_CustomDeviceType_To_v1beta2_CustomDeviceType(in, out, s)
+}
+
+func autoConvert_v1beta2_DeviceShareArgs_To_config_DeviceShareArgs(in *DeviceShareArgs, out *config.DeviceShareArgs, s conversion.Scope) error {
+	out.CustomDeviceTypes = *(*[]config.CustomDeviceType)(unsafe.Pointer(&in.CustomDeviceTypes))
+	return nil
+}
+
+// Convert_v1beta2_DeviceShareArgs_To_config_DeviceShareArgs is an autogenerated conversion function.
+func Convert_v1beta2_DeviceShareArgs_To_config_DeviceShareArgs(in *DeviceShareArgs, out *config.DeviceShareArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_DeviceShareArgs_To_config_DeviceShareArgs(in, out, s)
+}
+
+func autoConvert_config_DeviceShareArgs_To_v1beta2_DeviceShareArgs(in *config.DeviceShareArgs, out *DeviceShareArgs, s conversion.Scope) error {
+	out.CustomDeviceTypes = *(*[]CustomDeviceType)(unsafe.Pointer(&in.CustomDeviceTypes))
+	return nil
+}
+
+// Convert_config_DeviceShareArgs_To_v1beta2_DeviceShareArgs is an autogenerated conversion function.
+func Convert_config_DeviceShareArgs_To_v1beta2_DeviceShareArgs(in *config.DeviceShareArgs, out *DeviceShareArgs, s conversion.Scope) error {
+	return autoConvert_config_DeviceShareArgs_To_v1beta2_DeviceShareArgs(in, out, s)
+}
+
 func autoConvert_v1beta2_ElasticQuotaArgs_To_config_ElasticQuotaArgs(in *ElasticQuotaArgs, out *config.ElasticQuotaArgs, s conversion.Scope) error {
 	out.MinCandidateNodesPercentage = (*int32)(unsafe.Pointer(in.MinCandidateNodesPercentage))
 	out.MinCandidateNodesAbsolute = (*int32)(unsafe.Pointer(in.MinCandidateNodesAbsolute))
 	out.ContinueOverUseCountTriggerEvict = (*int64)(unsafe.Pointer(in.ContinueOverUseCountTriggerEvict))
 	out.DefaultQuotaGroupMax = *(*corev1.ResourceList)(unsafe.Pointer(&in.DefaultQuotaGroupMax))
+	out.DefaultQuotaGroupMin = *(*corev1.ResourceList)(unsafe.Pointer(&in.DefaultQuotaGroupMin))
 	out.SystemQuotaGroupMax = *(*corev1.ResourceList)(unsafe.Pointer(&in.SystemQuotaGroupMax))
+	out.RuntimeCalculateStrategy = config.RuntimeCalculateStrategyType(in.RuntimeCalculateStrategy)
+	out.MinQuotaPriorityClasses = *(*[]string)(unsafe.Pointer(&in.MinQuotaPriorityClasses))
+	out.PriorityAging = (*config.PriorityAgingArgs)(unsafe.Pointer(in.PriorityAging))
+	out.PodAccounting = (*config.PodAccountingArgs)(unsafe.Pointer(in.PodAccounting))
+	out.ReservationAccountingPolicy = config.ReservationAccountingPolicy(in.ReservationAccountingPolicy)
+	out.MinQuotaOversellPercent = (*int64)(unsafe.Pointer(in.MinQuotaOversellPercent))
+	out.AccountingVerificationPeriod = (*v1.Duration)(unsafe.Pointer(in.AccountingVerificationPeriod))
+	out.SharedWeightProvider = (*config.SharedWeightProviderArgs)(unsafe.Pointer(in.SharedWeightProvider))
+	out.FederatedLending = (*config.FederatedLendingArgs)(unsafe.Pointer(in.FederatedLending))
+	out.UsageDecay = (*config.UsageDecayArgs)(unsafe.Pointer(in.UsageDecay))
+	out.QuotaStatusSyncPeriod = (*v1.Duration)(unsafe.Pointer(in.QuotaStatusSyncPeriod))
+	out.BatchRecalculateInterval = (*v1.Duration)(unsafe.Pointer(in.BatchRecalculateInterval))
+	out.QuotaDeletionSyncPeriod = (*v1.Duration)(unsafe.Pointer(in.QuotaDeletionSyncPeriod))
+	out.QuotaMetricsRecordPeriod = (*v1.Duration)(unsafe.Pointer(in.QuotaMetricsRecordPeriod))
+	out.QuotaRuntimeHistoryInterval = (*v1.Duration)(unsafe.Pointer(in.QuotaRuntimeHistoryInterval))
+	out.QuotaRuntimeHistoryRetention = (*v1.Duration)(unsafe.Pointer(in.QuotaRuntimeHistoryRetention))
+	out.PodQuotaAnnotationSyncPeriod = (*v1.Duration)(unsafe.Pointer(in.PodQuotaAnnotationSyncPeriod))
+	out.QuotaReservationCleanupInterval = (*v1.Duration)(unsafe.Pointer(in.QuotaReservationCleanupInterval))
 	return nil
 }
 
@@ -143,7 +329,26 @@ func autoConvert_config_ElasticQuotaArgs_To_v1beta2_ElasticQuotaArgs(in *config.
 	out.MinCandidateNodesAbsolute = (*int32)(unsafe.Pointer(in.MinCandidateNodesAbsolute))
 	out.ContinueOverUseCountTriggerEvict = (*int64)(unsafe.Pointer(in.ContinueOverUseCountTriggerEvict))
 	out.DefaultQuotaGroupMax = *(*corev1.ResourceList)(unsafe.Pointer(&in.DefaultQuotaGroupMax))
+	out.DefaultQuotaGroupMin = *(*corev1.ResourceList)(unsafe.Pointer(&in.DefaultQuotaGroupMin))
 	out.SystemQuotaGroupMax = *(*corev1.ResourceList)(unsafe.Pointer(&in.SystemQuotaGroupMax))
+	out.RuntimeCalculateStrategy = RuntimeCalculateStrategyType(in.RuntimeCalculateStrategy)
+	out.MinQuotaPriorityClasses = *(*[]string)(unsafe.Pointer(&in.MinQuotaPriorityClasses))
+	out.PriorityAging = (*PriorityAgingArgs)(unsafe.Pointer(in.PriorityAging))
+	out.PodAccounting = (*PodAccountingArgs)(unsafe.Pointer(in.PodAccounting))
+	out.ReservationAccountingPolicy = ReservationAccountingPolicy(in.ReservationAccountingPolicy)
+	out.MinQuotaOversellPercent = (*int64)(unsafe.Pointer(in.MinQuotaOversellPercent))
+	out.AccountingVerificationPeriod = (*v1.Duration)(unsafe.Pointer(in.AccountingVerificationPeriod))
+	out.SharedWeightProvider = (*SharedWeightProviderArgs)(unsafe.Pointer(in.SharedWeightProvider))
+	out.FederatedLending = (*FederatedLendingArgs)(unsafe.Pointer(in.FederatedLending))
+	out.UsageDecay = (*UsageDecayArgs)(unsafe.Pointer(in.UsageDecay))
+	out.QuotaStatusSyncPeriod = (*v1.Duration)(unsafe.Pointer(in.QuotaStatusSyncPeriod))
+	out.BatchRecalculateInterval = (*v1.Duration)(unsafe.Pointer(in.BatchRecalculateInterval))
+	out.QuotaDeletionSyncPeriod = (*v1.Duration)(unsafe.Pointer(in.QuotaDeletionSyncPeriod))
+	out.QuotaMetricsRecordPeriod = (*v1.Duration)(unsafe.Pointer(in.QuotaMetricsRecordPeriod))
+	out.QuotaRuntimeHistoryInterval = (*v1.Duration)(unsafe.Pointer(in.QuotaRuntimeHistoryInterval))
+	out.QuotaRuntimeHistoryRetention = (*v1.Duration)(unsafe.Pointer(in.QuotaRuntimeHistoryRetention))
+	out.PodQuotaAnnotationSyncPeriod = (*v1.Duration)(unsafe.Pointer(in.PodQuotaAnnotationSyncPeriod))
+	out.QuotaReservationCleanupInterval = (*v1.Duration)(unsafe.Pointer(in.QuotaReservationCleanupInterval))
 	return nil
 }
 
@@ -152,12 +357,42 @@ func Convert_config_ElasticQuotaArgs_To_v1beta2_ElasticQuotaArgs(in *config.Elas
 	return autoConvert_config_ElasticQuotaArgs_To_v1beta2_ElasticQuotaArgs(in, out, s)
 }
 
+func autoConvert_v1beta2_FederatedLendingArgs_To_config_FederatedLendingArgs(in *FederatedLendingArgs, out *config.FederatedLendingArgs, s conversion.Scope) error {
+	out.Members = *(*[]string)(unsafe.Pointer(&in.Members))
+	return nil
+}
+
+// Convert_v1beta2_FederatedLendingArgs_To_config_FederatedLendingArgs is an autogenerated conversion function.
+func Convert_v1beta2_FederatedLendingArgs_To_config_FederatedLendingArgs(in *FederatedLendingArgs, out *config.FederatedLendingArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_FederatedLendingArgs_To_config_FederatedLendingArgs(in, out, s)
+}
+
+func autoConvert_config_FederatedLendingArgs_To_v1beta2_FederatedLendingArgs(in *config.FederatedLendingArgs, out *FederatedLendingArgs, s conversion.Scope) error {
+	out.Members = *(*[]string)(unsafe.Pointer(&in.Members))
+	return nil
+}
+
+// Convert_config_FederatedLendingArgs_To_v1beta2_FederatedLendingArgs is an autogenerated conversion function.
+func Convert_config_FederatedLendingArgs_To_v1beta2_FederatedLendingArgs(in *config.FederatedLendingArgs, out *FederatedLendingArgs, s conversion.Scope) error {
+	return autoConvert_config_FederatedLendingArgs_To_v1beta2_FederatedLendingArgs(in, out, s)
+}
+
 func autoConvert_v1beta2_LoadAwareSchedulingArgs_To_config_LoadAwareSchedulingArgs(in *LoadAwareSchedulingArgs, out *config.LoadAwareSchedulingArgs, s conversion.Scope) error {
 	out.FilterExpiredNodeMetrics = (*bool)(unsafe.Pointer(in.FilterExpiredNodeMetrics))
 	out.NodeMetricExpirationSeconds = (*int64)(unsafe.Pointer(in.NodeMetricExpirationSeconds))
 	out.ResourceWeights = *(*map[corev1.ResourceName]int64)(unsafe.Pointer(&in.ResourceWeights))
 	out.UsageThresholds = *(*map[corev1.ResourceName]int64)(unsafe.Pointer(&in.UsageThresholds))
 	out.EstimatedScalingFactors = *(*map[corev1.ResourceName]int64)(unsafe.Pointer(&in.EstimatedScalingFactors))
+	out.PodChurnWindowSeconds = (*int64)(unsafe.Pointer(in.PodChurnWindowSeconds))
+	out.PodChurnThreshold = (*int64)(unsafe.Pointer(in.PodChurnThreshold))
+	out.PodChurnWeight = (*int64)(unsafe.Pointer(in.PodChurnWeight))
+	out.LocalStorageIOUtilizationThreshold = (*int64)(unsafe.Pointer(in.LocalStorageIOUtilizationThreshold))
+	out.LocalStorageWeight = (*int64)(unsafe.Pointer(in.LocalStorageWeight))
+	out.Aggregated = (*config.LoadAwareSchedulingAggregatedArgs)(unsafe.Pointer(in.Aggregated))
+	out.UsageThresholdProfiles = *(*[]config.LoadAwareSchedulingUsageThresholdProfile)(unsafe.Pointer(&in.UsageThresholdProfiles))
+	out.Forecast = (*config.LoadAwareSchedulingForecastArgs)(unsafe.Pointer(in.Forecast))
+	out.MetricProvider = (*config.LoadAwareSchedulingMetricProviderArgs)(unsafe.Pointer(in.MetricProvider))
+	out.HotspotCooldown = (*config.LoadAwareSchedulingHotspotCooldownArgs)(unsafe.Pointer(in.HotspotCooldown))
 	return nil
 }
 
@@ -172,6 +407,16 @@ func autoConvert_config_LoadAwareSchedulingArgs_To_v1beta2_LoadAwareSchedulingAr
 	out.ResourceWeights = *(*map[corev1.ResourceName]int64)(unsafe.Pointer(&in.ResourceWeights))
 	out.UsageThresholds = *(*map[corev1.ResourceName]int64)(unsafe.Pointer(&in.UsageThresholds))
 	out.EstimatedScalingFactors = *(*map[corev1.ResourceName]int64)(unsafe.Pointer(&in.EstimatedScalingFactors))
+	out.PodChurnWindowSeconds = (*int64)(unsafe.Pointer(in.PodChurnWindowSeconds))
+	out.PodChurnThreshold = (*int64)(unsafe.Pointer(in.PodChurnThreshold))
+	out.PodChurnWeight = (*int64)(unsafe.Pointer(in.PodChurnWeight))
+	out.LocalStorageIOUtilizationThreshold = (*int64)(unsafe.Pointer(in.LocalStorageIOUtilizationThreshold))
+	out.LocalStorageWeight = (*int64)(unsafe.Pointer(in.LocalStorageWeight))
+	out.Aggregated = (*LoadAwareSchedulingAggregatedArgs)(unsafe.Pointer(in.Aggregated))
+	out.UsageThresholdProfiles = *(*[]LoadAwareSchedulingUsageThresholdProfile)(unsafe.Pointer(&in.UsageThresholdProfiles))
+	out.Forecast = (*LoadAwareSchedulingForecastArgs)(unsafe.Pointer(in.Forecast))
+	out.MetricProvider = (*LoadAwareSchedulingMetricProviderArgs)(unsafe.Pointer(in.MetricProvider))
+	out.HotspotCooldown = (*LoadAwareSchedulingHotspotCooldownArgs)(unsafe.Pointer(in.HotspotCooldown))
 	return nil
 }
 
@@ -180,6 +425,128 @@ func Convert_config_LoadAwareSchedulingArgs_To_v1beta2_LoadAwareSchedulingArgs(i
 	return autoConvert_config_LoadAwareSchedulingArgs_To_v1beta2_LoadAwareSchedulingArgs(in, out, s)
 }
 
+func autoConvert_v1beta2_LoadAwareSchedulingAggregatedArgs_To_config_LoadAwareSchedulingAggregatedArgs(in *LoadAwareSchedulingAggregatedArgs, out *config.LoadAwareSchedulingAggregatedArgs, s conversion.Scope) error {
+	out.ScoreAggregationType = slov1alpha1.AggregationType(in.ScoreAggregationType)
+	out.ScoreAggregatedDuration = in.ScoreAggregatedDuration
+	return nil
+}
+
+// Convert_v1beta2_LoadAwareSchedulingAggregatedArgs_To_config_LoadAwareSchedulingAggregatedArgs is an autogenerated conversion function.
+func Convert_v1beta2_LoadAwareSchedulingAggregatedArgs_To_config_LoadAwareSchedulingAggregatedArgs(in *LoadAwareSchedulingAggregatedArgs, out *config.LoadAwareSchedulingAggregatedArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_LoadAwareSchedulingAggregatedArgs_To_config_LoadAwareSchedulingAggregatedArgs(in, out, s)
+}
+
+func autoConvert_config_LoadAwareSchedulingAggregatedArgs_To_v1beta2_LoadAwareSchedulingAggregatedArgs(in *config.LoadAwareSchedulingAggregatedArgs, out *LoadAwareSchedulingAggregatedArgs, s conversion.Scope) error {
+	out.ScoreAggregationType = slov1alpha1.AggregationType(in.ScoreAggregationType)
+	out.ScoreAggregatedDuration = in.ScoreAggregatedDuration
+	return nil
+}
+
+// Convert_config_LoadAwareSchedulingAggregatedArgs_To_v1beta2_LoadAwareSchedulingAggregatedArgs is an autogenerated conversion function.
+func Convert_config_LoadAwareSchedulingAggregatedArgs_To_v1beta2_LoadAwareSchedulingAggregatedArgs(in *config.LoadAwareSchedulingAggregatedArgs, out *LoadAwareSchedulingAggregatedArgs, s conversion.Scope) error {
+	return autoConvert_config_LoadAwareSchedulingAggregatedArgs_To_v1beta2_LoadAwareSchedulingAggregatedArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_LoadAwareSchedulingForecastArgs_To_config_LoadAwareSchedulingForecastArgs(in *LoadAwareSchedulingForecastArgs, out *config.LoadAwareSchedulingForecastArgs, s conversion.Scope) error {
+	out.HorizonSeconds = (*int64)(unsafe.Pointer(in.HorizonSeconds))
+	out.LevelSmoothingPercent = (*int64)(unsafe.Pointer(in.LevelSmoothingPercent))
+	out.TrendSmoothingPercent = (*int64)(unsafe.Pointer(in.TrendSmoothingPercent))
+	return nil
+}
+
+// Convert_v1beta2_LoadAwareSchedulingForecastArgs_To_config_LoadAwareSchedulingForecastArgs is an autogenerated conversion function.
+func Convert_v1beta2_LoadAwareSchedulingForecastArgs_To_config_LoadAwareSchedulingForecastArgs(in *LoadAwareSchedulingForecastArgs, out *config.LoadAwareSchedulingForecastArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_LoadAwareSchedulingForecastArgs_To_config_LoadAwareSchedulingForecastArgs(in, out, s)
+}
+
+func autoConvert_config_LoadAwareSchedulingForecastArgs_To_v1beta2_LoadAwareSchedulingForecastArgs(in *config.LoadAwareSchedulingForecastArgs, out *LoadAwareSchedulingForecastArgs, s conversion.Scope) error {
+	out.HorizonSeconds = (*int64)(unsafe.Pointer(in.HorizonSeconds))
+	out.LevelSmoothingPercent = (*int64)(unsafe.Pointer(in.LevelSmoothingPercent))
+	out.TrendSmoothingPercent = (*int64)(unsafe.Pointer(in.TrendSmoothingPercent))
+	return nil
+}
+
+// Convert_config_LoadAwareSchedulingForecastArgs_To_v1beta2_LoadAwareSchedulingForecastArgs is an autogenerated conversion function.
+func Convert_config_LoadAwareSchedulingForecastArgs_To_v1beta2_LoadAwareSchedulingForecastArgs(in *config.LoadAwareSchedulingForecastArgs, out *LoadAwareSchedulingForecastArgs, s conversion.Scope) error {
+	return autoConvert_config_LoadAwareSchedulingForecastArgs_To_v1beta2_LoadAwareSchedulingForecastArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_LoadAwareSchedulingHotspotCooldownArgs_To_config_LoadAwareSchedulingHotspotCooldownArgs(in *LoadAwareSchedulingHotspotCooldownArgs, out *config.LoadAwareSchedulingHotspotCooldownArgs, s conversion.Scope) error {
+	out.CriticalThresholds = *(*map[corev1.ResourceName]int64)(unsafe.Pointer(&in.CriticalThresholds))
+	out.CooldownSeconds = (*int64)(unsafe.Pointer(in.CooldownSeconds))
+	return nil
+}
+
+// Convert_v1beta2_LoadAwareSchedulingHotspotCooldownArgs_To_config_LoadAwareSchedulingHotspotCooldownArgs is an autogenerated conversion function.
+func Convert_v1beta2_LoadAwareSchedulingHotspotCooldownArgs_To_config_LoadAwareSchedulingHotspotCooldownArgs(in *LoadAwareSchedulingHotspotCooldownArgs, out *config.LoadAwareSchedulingHotspotCooldownArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_LoadAwareSchedulingHotspotCooldownArgs_To_config_LoadAwareSchedulingHotspotCooldownArgs(in, out, s)
+}
+
+func autoConvert_config_LoadAwareSchedulingHotspotCooldownArgs_To_v1beta2_LoadAwareSchedulingHotspotCooldownArgs(in *config.LoadAwareSchedulingHotspotCooldownArgs, out *LoadAwareSchedulingHotspotCooldownArgs, s conversion.Scope) error {
+	out.CriticalThresholds = *(*map[corev1.ResourceName]int64)(unsafe.Pointer(&in.CriticalThresholds))
+	out.CooldownSeconds = (*int64)(unsafe.Pointer(in.CooldownSeconds))
+	return nil
+}
+
+// Convert_config_LoadAwareSchedulingHotspotCooldownArgs_To_v1beta2_LoadAwareSchedulingHotspotCooldownArgs is an autogenerated conversion function.
+func Convert_config_LoadAwareSchedulingHotspotCooldownArgs_To_v1beta2_LoadAwareSchedulingHotspotCooldownArgs(in *config.LoadAwareSchedulingHotspotCooldownArgs, out *LoadAwareSchedulingHotspotCooldownArgs, s conversion.Scope) error {
+	return autoConvert_config_LoadAwareSchedulingHotspotCooldownArgs_To_v1beta2_LoadAwareSchedulingHotspotCooldownArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_LoadAwareSchedulingMetricProviderArgs_To_config_LoadAwareSchedulingMetricProviderArgs(in *LoadAwareSchedulingMetricProviderArgs, out *config.LoadAwareSchedulingMetricProviderArgs, s conversion.Scope) error {
+	out.Type = config.MetricProviderType(in.Type)
+	out.Address = in.Address
+	out.NodeLabel = in.NodeLabel
+	out.Queries = *(*map[corev1.ResourceName]string)(unsafe.Pointer(&in.Queries))
+	out.CacheSeconds = (*int64)(unsafe.Pointer(in.CacheSeconds))
+	return nil
+}
+
+// Convert_v1beta2_LoadAwareSchedulingMetricProviderArgs_To_config_LoadAwareSchedulingMetricProviderArgs is an autogenerated conversion function.
+func Convert_v1beta2_LoadAwareSchedulingMetricProviderArgs_To_config_LoadAwareSchedulingMetricProviderArgs(in *LoadAwareSchedulingMetricProviderArgs, out *config.LoadAwareSchedulingMetricProviderArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_LoadAwareSchedulingMetricProviderArgs_To_config_LoadAwareSchedulingMetricProviderArgs(in, out, s)
+}
+
+func autoConvert_config_LoadAwareSchedulingMetricProviderArgs_To_v1beta2_LoadAwareSchedulingMetricProviderArgs(in *config.LoadAwareSchedulingMetricProviderArgs, out *LoadAwareSchedulingMetricProviderArgs, s conversion.Scope) error {
+	out.Type = MetricProviderType(in.Type)
+	out.Address = in.Address
+	out.NodeLabel = in.NodeLabel
+	out.Queries = *(*map[corev1.ResourceName]string)(unsafe.Pointer(&in.Queries))
+	out.CacheSeconds = (*int64)(unsafe.Pointer(in.CacheSeconds))
+	return nil
+}
+
+// Convert_config_LoadAwareSchedulingMetricProviderArgs_To_v1beta2_LoadAwareSchedulingMetricProviderArgs is an autogenerated conversion function.
+func Convert_config_LoadAwareSchedulingMetricProviderArgs_To_v1beta2_LoadAwareSchedulingMetricProviderArgs(in *config.LoadAwareSchedulingMetricProviderArgs, out *LoadAwareSchedulingMetricProviderArgs, s conversion.Scope) error {
+	return autoConvert_config_LoadAwareSchedulingMetricProviderArgs_To_v1beta2_LoadAwareSchedulingMetricProviderArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_LoadAwareSchedulingUsageThresholdProfile_To_config_LoadAwareSchedulingUsageThresholdProfile(in *LoadAwareSchedulingUsageThresholdProfile, out *config.LoadAwareSchedulingUsageThresholdProfile, s conversion.Scope) error {
+	out.Namespaces = *(*[]string)(unsafe.Pointer(&in.Namespaces))
+	out.PriorityClasses = *(*[]string)(unsafe.Pointer(&in.PriorityClasses))
+	out.UsageThresholds = *(*map[corev1.ResourceName]int64)(unsafe.Pointer(&in.UsageThresholds))
+	out.ResourceWeights = *(*map[corev1.ResourceName]int64)(unsafe.Pointer(&in.ResourceWeights))
+	return nil
+}
+
+// Convert_v1beta2_LoadAwareSchedulingUsageThresholdProfile_To_config_LoadAwareSchedulingUsageThresholdProfile is an autogenerated conversion function.
+func Convert_v1beta2_LoadAwareSchedulingUsageThresholdProfile_To_config_LoadAwareSchedulingUsageThresholdProfile(in *LoadAwareSchedulingUsageThresholdProfile, out *config.LoadAwareSchedulingUsageThresholdProfile, s conversion.Scope) error {
+	return autoConvert_v1beta2_LoadAwareSchedulingUsageThresholdProfile_To_config_LoadAwareSchedulingUsageThresholdProfile(in, out, s)
+}
+
+func autoConvert_config_LoadAwareSchedulingUsageThresholdProfile_To_v1beta2_LoadAwareSchedulingUsageThresholdProfile(in *config.LoadAwareSchedulingUsageThresholdProfile, out *LoadAwareSchedulingUsageThresholdProfile, s conversion.Scope) error {
+	out.Namespaces = *(*[]string)(unsafe.Pointer(&in.Namespaces))
+	out.PriorityClasses = *(*[]string)(unsafe.Pointer(&in.PriorityClasses))
+	out.UsageThresholds = *(*map[corev1.ResourceName]int64)(unsafe.Pointer(&in.UsageThresholds))
+	out.ResourceWeights = *(*map[corev1.ResourceName]int64)(unsafe.Pointer(&in.ResourceWeights))
+	return nil
+}
+
+// Convert_config_LoadAwareSchedulingUsageThresholdProfile_To_v1beta2_LoadAwareSchedulingUsageThresholdProfile is an autogenerated conversion function.
+func Convert_config_LoadAwareSchedulingUsageThresholdProfile_To_v1beta2_LoadAwareSchedulingUsageThresholdProfile(in *config.LoadAwareSchedulingUsageThresholdProfile, out *LoadAwareSchedulingUsageThresholdProfile, s conversion.Scope) error {
+	return autoConvert_config_LoadAwareSchedulingUsageThresholdProfile_To_v1beta2_LoadAwareSchedulingUsageThresholdProfile(in, out, s)
+}
+
 func autoConvert_v1beta2_NodeNUMAResourceArgs_To_config_NodeNUMAResourceArgs(in *NodeNUMAResourceArgs, out *config.NodeNUMAResourceArgs, s conversion.Scope) error {
 	out.DefaultCPUBindPolicy = config.CPUBindPolicy(in.DefaultCPUBindPolicy)
 	out.ScoringStrategy = (*config.ScoringStrategy)(unsafe.Pointer(in.ScoringStrategy))
@@ -202,8 +569,58 @@ func Convert_config_NodeNUMAResourceArgs_To_v1beta2_NodeNUMAResourceArgs(in *con
 	return autoConvert_config_NodeNUMAResourceArgs_To_v1beta2_NodeNUMAResourceArgs(in, out, s)
 }
 
+func autoConvert_v1beta2_PodAccountingArgs_To_config_PodAccountingArgs(in *PodAccountingArgs, out *config.PodAccountingArgs, s conversion.Scope) error {
+	out.UsedCountPolicy = config.PodUsedCountPolicy(in.UsedCountPolicy)
+	out.PendingTimeout = in.PendingTimeout
+	out.TerminalGracePeriod = in.TerminalGracePeriod
+	return nil
+}
+
+// Convert_v1beta2_PodAccountingArgs_To_config_PodAccountingArgs is an autogenerated conversion function.
+func Convert_v1beta2_PodAccountingArgs_To_config_PodAccountingArgs(in *PodAccountingArgs, out *config.PodAccountingArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_PodAccountingArgs_To_config_PodAccountingArgs(in, out, s)
+}
+
+func autoConvert_config_PodAccountingArgs_To_v1beta2_PodAccountingArgs(in *config.PodAccountingArgs, out *PodAccountingArgs, s conversion.Scope) error {
+	out.UsedCountPolicy = PodUsedCountPolicy(in.UsedCountPolicy)
+	out.PendingTimeout = in.PendingTimeout
+	out.TerminalGracePeriod = in.TerminalGracePeriod
+	return nil
+}
+
+// Convert_config_PodAccountingArgs_To_v1beta2_PodAccountingArgs is an autogenerated conversion function.
+func Convert_config_PodAccountingArgs_To_v1beta2_PodAccountingArgs(in *config.PodAccountingArgs, out *PodAccountingArgs, s conversion.Scope) error {
+	return autoConvert_config_PodAccountingArgs_To_v1beta2_PodAccountingArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_PriorityAgingArgs_To_config_PriorityAgingArgs(in *PriorityAgingArgs, out *config.PriorityAgingArgs, s conversion.Scope) error {
+	out.Interval = in.Interval
+	out.Step = in.Step
+	out.MaxBoost = in.MaxBoost
+	return nil
+}
+
+// Convert_v1beta2_PriorityAgingArgs_To_config_PriorityAgingArgs is an autogenerated conversion function.
+func Convert_v1beta2_PriorityAgingArgs_To_config_PriorityAgingArgs(in *PriorityAgingArgs, out *config.PriorityAgingArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_PriorityAgingArgs_To_config_PriorityAgingArgs(in, out, s)
+}
+
+func autoConvert_config_PriorityAgingArgs_To_v1beta2_PriorityAgingArgs(in *config.PriorityAgingArgs, out *PriorityAgingArgs, s conversion.Scope) error {
+	out.Interval = in.Interval
+	out.Step = in.Step
+	out.MaxBoost = in.MaxBoost
+	return nil
+}
+
+// Convert_config_PriorityAgingArgs_To_v1beta2_PriorityAgingArgs is an autogenerated conversion function.
+func Convert_config_PriorityAgingArgs_To_v1beta2_PriorityAgingArgs(in *config.PriorityAgingArgs, out *PriorityAgingArgs, s conversion.Scope) error {
+	return autoConvert_config_PriorityAgingArgs_To_v1beta2_PriorityAgingArgs(in, out, s)
+}
+
 func autoConvert_v1beta2_ReservationArgs_To_config_ReservationArgs(in *ReservationArgs, out *config.ReservationArgs, s conversion.Scope) error {
 	out.EnablePreemption = (*bool)(unsafe.Pointer(in.EnablePreemption))
+	out.PreemptionPriorityThreshold = (*int32)(unsafe.Pointer(in.PreemptionPriorityThreshold))
+	out.EnableRelocation = (*bool)(unsafe.Pointer(in.EnableRelocation))
 	return nil
 }
 
@@ -214,6 +631,8 @@ func Convert_v1beta2_ReservationArgs_To_config_ReservationArgs(in *ReservationAr
 
 func autoConvert_config_ReservationArgs_To_v1beta2_ReservationArgs(in *config.ReservationArgs, out *ReservationArgs, s conversion.Scope) error {
 	out.EnablePreemption = (*bool)(unsafe.Pointer(in.EnablePreemption))
+	out.PreemptionPriorityThreshold = (*int32)(unsafe.Pointer(in.PreemptionPriorityThreshold))
+	out.EnableRelocation = (*bool)(unsafe.Pointer(in.EnableRelocation))
 	return nil
 }
 
@@ -243,3 +662,55 @@ func autoConvert_config_ScoringStrategy_To_v1beta2_ScoringStrategy(in *config.Sc
 func Convert_config_ScoringStrategy_To_v1beta2_ScoringStrategy(in *config.ScoringStrategy, out *ScoringStrategy, s conversion.Scope) error {
 	return autoConvert_config_ScoringStrategy_To_v1beta2_ScoringStrategy(in, out, s)
 }
+
+func autoConvert_v1beta2_SharedWeightProviderArgs_To_config_SharedWeightProviderArgs(in *SharedWeightProviderArgs, out *config.SharedWeightProviderArgs, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Timeout = in.Timeout
+	out.SyncPeriod = in.SyncPeriod
+	out.CacheTTL = in.CacheTTL
+	out.TransitionPercent = in.TransitionPercent
+	return nil
+}
+
+// Convert_v1beta2_SharedWeightProviderArgs_To_config_SharedWeightProviderArgs is an autogenerated conversion function.
+func Convert_v1beta2_SharedWeightProviderArgs_To_config_SharedWeightProviderArgs(in *SharedWeightProviderArgs, out *config.SharedWeightProviderArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_SharedWeightProviderArgs_To_config_SharedWeightProviderArgs(in, out, s)
+}
+
+func autoConvert_config_SharedWeightProviderArgs_To_v1beta2_SharedWeightProviderArgs(in *config.SharedWeightProviderArgs, out *SharedWeightProviderArgs, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Timeout = in.Timeout
+	out.SyncPeriod = in.SyncPeriod
+	out.CacheTTL = in.CacheTTL
+	out.TransitionPercent = in.TransitionPercent
+	return nil
+}
+
+// Convert_config_SharedWeightProviderArgs_To_v1beta2_SharedWeightProviderArgs is an autogenerated conversion function.
+func Convert_config_SharedWeightProviderArgs_To_v1beta2_SharedWeightProviderArgs(in *config.SharedWeightProviderArgs, out *SharedWeightProviderArgs, s conversion.Scope) error {
+	return autoConvert_config_SharedWeightProviderArgs_To_v1beta2_SharedWeightProviderArgs(in, out, s)
+}
+
+func autoConvert_v1beta2_UsageDecayArgs_To_config_UsageDecayArgs(in *UsageDecayArgs, out *config.UsageDecayArgs, s conversion.Scope) error {
+	out.HalfLife = in.HalfLife
+	out.PenaltyPercent = in.PenaltyPercent
+	out.UpdateInterval = in.UpdateInterval
+	return nil
+}
+
+// Convert_v1beta2_UsageDecayArgs_To_config_UsageDecayArgs is an autogenerated conversion function.
+func Convert_v1beta2_UsageDecayArgs_To_config_UsageDecayArgs(in *UsageDecayArgs, out *config.UsageDecayArgs, s conversion.Scope) error {
+	return autoConvert_v1beta2_UsageDecayArgs_To_config_UsageDecayArgs(in, out, s)
+}
+
+func autoConvert_config_UsageDecayArgs_To_v1beta2_UsageDecayArgs(in *config.UsageDecayArgs, out *UsageDecayArgs, s conversion.Scope) error {
+	out.HalfLife = in.HalfLife
+	out.PenaltyPercent = in.PenaltyPercent
+	out.UpdateInterval = in.UpdateInterval
+	return nil
+}
+
+// Convert_config_UsageDecayArgs_To_v1beta2_UsageDecayArgs is an autogenerated conversion function.
+func Convert_config_UsageDecayArgs_To_v1beta2_UsageDecayArgs(in *config.UsageDecayArgs, out *UsageDecayArgs, s conversion.Scope) error {
+	return autoConvert_config_UsageDecayArgs_To_v1beta2_UsageDecayArgs(in, out, s)
+}
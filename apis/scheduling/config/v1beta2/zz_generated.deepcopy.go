@@ -63,6 +63,59 @@ func (in *CoschedulingArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomDeviceType) DeepCopyInto(out *CustomDeviceType) {
+	*out = *in
+	if in.ResourceNames != nil {
+		in, out := &in.ResourceNames, &out.ResourceNames
+		*out = make([]corev1.ResourceName, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomDeviceType.
+func (in *CustomDeviceType) DeepCopy() *CustomDeviceType {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomDeviceType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceShareArgs) DeepCopyInto(out *DeviceShareArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.CustomDeviceTypes != nil {
+		in, out := &in.CustomDeviceTypes, &out.CustomDeviceTypes
+		*out = make([]CustomDeviceType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceShareArgs.
+func (in *DeviceShareArgs) DeepCopy() *DeviceShareArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceShareArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeviceShareArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ElasticQuotaArgs) DeepCopyInto(out *ElasticQuotaArgs) {
 	*out = *in
@@ -89,6 +142,13 @@ func (in *ElasticQuotaArgs) DeepCopyInto(out *ElasticQuotaArgs) {
 			(*out)[key] = val.DeepCopy()
 		}
 	}
+	if in.DefaultQuotaGroupMin != nil {
+		in, out := &in.DefaultQuotaGroupMin, &out.DefaultQuotaGroupMin
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
 	if in.SystemQuotaGroupMax != nil {
 		in, out := &in.SystemQuotaGroupMax, &out.SystemQuotaGroupMax
 		*out = make(corev1.ResourceList, len(*in))
@@ -96,6 +156,86 @@ func (in *ElasticQuotaArgs) DeepCopyInto(out *ElasticQuotaArgs) {
 			(*out)[key] = val.DeepCopy()
 		}
 	}
+	if in.MinQuotaPriorityClasses != nil {
+		in, out := &in.MinQuotaPriorityClasses, &out.MinQuotaPriorityClasses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PriorityAging != nil {
+		in, out := &in.PriorityAging, &out.PriorityAging
+		*out = new(PriorityAgingArgs)
+		**out = **in
+	}
+	if in.PodAccounting != nil {
+		in, out := &in.PodAccounting, &out.PodAccounting
+		*out = new(PodAccountingArgs)
+		**out = **in
+	}
+	if in.MinQuotaOversellPercent != nil {
+		in, out := &in.MinQuotaOversellPercent, &out.MinQuotaOversellPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.AccountingVerificationPeriod != nil {
+		in, out := &in.AccountingVerificationPeriod, &out.AccountingVerificationPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.SharedWeightProvider != nil {
+		in, out := &in.SharedWeightProvider, &out.SharedWeightProvider
+		*out = new(SharedWeightProviderArgs)
+		**out = **in
+	}
+	if in.FederatedLending != nil {
+		in, out := &in.FederatedLending, &out.FederatedLending
+		*out = new(FederatedLendingArgs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UsageDecay != nil {
+		in, out := &in.UsageDecay, &out.UsageDecay
+		*out = new(UsageDecayArgs)
+		**out = **in
+	}
+	if in.QuotaStatusSyncPeriod != nil {
+		in, out := &in.QuotaStatusSyncPeriod, &out.QuotaStatusSyncPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.BatchRecalculateInterval != nil {
+		in, out := &in.BatchRecalculateInterval, &out.BatchRecalculateInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.QuotaDeletionSyncPeriod != nil {
+		in, out := &in.QuotaDeletionSyncPeriod, &out.QuotaDeletionSyncPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.QuotaMetricsRecordPeriod != nil {
+		in, out := &in.QuotaMetricsRecordPeriod, &out.QuotaMetricsRecordPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.QuotaRuntimeHistoryInterval != nil {
+		in, out := &in.QuotaRuntimeHistoryInterval, &out.QuotaRuntimeHistoryInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.QuotaRuntimeHistoryRetention != nil {
+		in, out := &in.QuotaRuntimeHistoryRetention, &out.QuotaRuntimeHistoryRetention
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.PodQuotaAnnotationSyncPeriod != nil {
+		in, out := &in.PodQuotaAnnotationSyncPeriod, &out.PodQuotaAnnotationSyncPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.QuotaReservationCleanupInterval != nil {
+		in, out := &in.QuotaReservationCleanupInterval, &out.QuotaReservationCleanupInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	return
 }
 
@@ -117,6 +257,27 @@ func (in *ElasticQuotaArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedLendingArgs) DeepCopyInto(out *FederatedLendingArgs) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedLendingArgs.
+func (in *FederatedLendingArgs) DeepCopy() *FederatedLendingArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedLendingArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoadAwareSchedulingArgs) DeepCopyInto(out *LoadAwareSchedulingArgs) {
 	*out = *in
@@ -152,6 +313,58 @@ func (in *LoadAwareSchedulingArgs) DeepCopyInto(out *LoadAwareSchedulingArgs) {
 			(*out)[key] = val
 		}
 	}
+	if in.PodChurnWindowSeconds != nil {
+		in, out := &in.PodChurnWindowSeconds, &out.PodChurnWindowSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PodChurnThreshold != nil {
+		in, out := &in.PodChurnThreshold, &out.PodChurnThreshold
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PodChurnWeight != nil {
+		in, out := &in.PodChurnWeight, &out.PodChurnWeight
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LocalStorageIOUtilizationThreshold != nil {
+		in, out := &in.LocalStorageIOUtilizationThreshold, &out.LocalStorageIOUtilizationThreshold
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LocalStorageWeight != nil {
+		in, out := &in.LocalStorageWeight, &out.LocalStorageWeight
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Aggregated != nil {
+		in, out := &in.Aggregated, &out.Aggregated
+		*out = new(LoadAwareSchedulingAggregatedArgs)
+		**out = **in
+	}
+	if in.UsageThresholdProfiles != nil {
+		in, out := &in.UsageThresholdProfiles, &out.UsageThresholdProfiles
+		*out = make([]LoadAwareSchedulingUsageThresholdProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Forecast != nil {
+		in, out := &in.Forecast, &out.Forecast
+		*out = new(LoadAwareSchedulingForecastArgs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MetricProvider != nil {
+		in, out := &in.MetricProvider, &out.MetricProvider
+		*out = new(LoadAwareSchedulingMetricProviderArgs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HotspotCooldown != nil {
+		in, out := &in.HotspotCooldown, &out.HotspotCooldown
+		*out = new(LoadAwareSchedulingHotspotCooldownArgs)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -173,6 +386,150 @@ func (in *LoadAwareSchedulingArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadAwareSchedulingAggregatedArgs) DeepCopyInto(out *LoadAwareSchedulingAggregatedArgs) {
+	*out = *in
+	out.ScoreAggregatedDuration = in.ScoreAggregatedDuration
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadAwareSchedulingAggregatedArgs.
+func (in *LoadAwareSchedulingAggregatedArgs) DeepCopy() *LoadAwareSchedulingAggregatedArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadAwareSchedulingAggregatedArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadAwareSchedulingForecastArgs) DeepCopyInto(out *LoadAwareSchedulingForecastArgs) {
+	*out = *in
+	if in.HorizonSeconds != nil {
+		in, out := &in.HorizonSeconds, &out.HorizonSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LevelSmoothingPercent != nil {
+		in, out := &in.LevelSmoothingPercent, &out.LevelSmoothingPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TrendSmoothingPercent != nil {
+		in, out := &in.TrendSmoothingPercent, &out.TrendSmoothingPercent
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadAwareSchedulingForecastArgs.
+func (in *LoadAwareSchedulingForecastArgs) DeepCopy() *LoadAwareSchedulingForecastArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadAwareSchedulingForecastArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadAwareSchedulingHotspotCooldownArgs) DeepCopyInto(out *LoadAwareSchedulingHotspotCooldownArgs) {
+	*out = *in
+	if in.CriticalThresholds != nil {
+		in, out := &in.CriticalThresholds, &out.CriticalThresholds
+		*out = make(map[corev1.ResourceName]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CooldownSeconds != nil {
+		in, out := &in.CooldownSeconds, &out.CooldownSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadAwareSchedulingHotspotCooldownArgs.
+func (in *LoadAwareSchedulingHotspotCooldownArgs) DeepCopy() *LoadAwareSchedulingHotspotCooldownArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadAwareSchedulingHotspotCooldownArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadAwareSchedulingMetricProviderArgs) DeepCopyInto(out *LoadAwareSchedulingMetricProviderArgs) {
+	*out = *in
+	if in.Queries != nil {
+		in, out := &in.Queries, &out.Queries
+		*out = make(map[corev1.ResourceName]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CacheSeconds != nil {
+		in, out := &in.CacheSeconds, &out.CacheSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadAwareSchedulingMetricProviderArgs.
+func (in *LoadAwareSchedulingMetricProviderArgs) DeepCopy() *LoadAwareSchedulingMetricProviderArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadAwareSchedulingMetricProviderArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadAwareSchedulingUsageThresholdProfile) DeepCopyInto(out *LoadAwareSchedulingUsageThresholdProfile) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PriorityClasses != nil {
+		in, out := &in.PriorityClasses, &out.PriorityClasses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UsageThresholds != nil {
+		in, out := &in.UsageThresholds, &out.UsageThresholds
+		*out = make(map[corev1.ResourceName]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ResourceWeights != nil {
+		in, out := &in.ResourceWeights, &out.ResourceWeights
+		*out = make(map[corev1.ResourceName]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadAwareSchedulingUsageThresholdProfile.
+func (in *LoadAwareSchedulingUsageThresholdProfile) DeepCopy() *LoadAwareSchedulingUsageThresholdProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadAwareSchedulingUsageThresholdProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeNUMAResourceArgs) DeepCopyInto(out *NodeNUMAResourceArgs) {
 	*out = *in
@@ -203,6 +560,41 @@ func (in *NodeNUMAResourceArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodAccountingArgs) DeepCopyInto(out *PodAccountingArgs) {
+	*out = *in
+	out.PendingTimeout = in.PendingTimeout
+	out.TerminalGracePeriod = in.TerminalGracePeriod
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodAccountingArgs.
+func (in *PodAccountingArgs) DeepCopy() *PodAccountingArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(PodAccountingArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PriorityAgingArgs) DeepCopyInto(out *PriorityAgingArgs) {
+	*out = *in
+	out.Interval = in.Interval
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PriorityAgingArgs.
+func (in *PriorityAgingArgs) DeepCopy() *PriorityAgingArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(PriorityAgingArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReservationArgs) DeepCopyInto(out *ReservationArgs) {
 	*out = *in
@@ -212,6 +604,16 @@ func (in *ReservationArgs) DeepCopyInto(out *ReservationArgs) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.PreemptionPriorityThreshold != nil {
+		in, out := &in.PreemptionPriorityThreshold, &out.PreemptionPriorityThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.EnableRelocation != nil {
+		in, out := &in.EnableRelocation, &out.EnableRelocation
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -253,3 +655,40 @@ func (in *ScoringStrategy) DeepCopy() *ScoringStrategy {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedWeightProviderArgs) DeepCopyInto(out *SharedWeightProviderArgs) {
+	*out = *in
+	out.Timeout = in.Timeout
+	out.SyncPeriod = in.SyncPeriod
+	out.CacheTTL = in.CacheTTL
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedWeightProviderArgs.
+func (in *SharedWeightProviderArgs) DeepCopy() *SharedWeightProviderArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedWeightProviderArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UsageDecayArgs) DeepCopyInto(out *UsageDecayArgs) {
+	*out = *in
+	out.HalfLife = in.HalfLife
+	out.UpdateInterval = in.UpdateInterval
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UsageDecayArgs.
+func (in *UsageDecayArgs) DeepCopy() *UsageDecayArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(UsageDecayArgs)
+	in.DeepCopyInto(out)
+	return out
+}
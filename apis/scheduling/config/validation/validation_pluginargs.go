@@ -23,7 +23,10 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	"github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 )
 
 // ValidateLoadAwareSchedulingArgs validates that LoadAwareSchedulingArgs are correct.
@@ -44,13 +47,117 @@ func ValidateLoadAwareSchedulingArgs(args *config.LoadAwareSchedulingArgs) error
 		allErrs = append(allErrs, field.Invalid(field.NewPath("estimatedScalingFactors"), args.EstimatedScalingFactors, err.Error()))
 	}
 
+	if args.PodChurnWindowSeconds != nil && *args.PodChurnWindowSeconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("podChurnWindowSeconds"), *args.PodChurnWindowSeconds, "podChurnWindowSeconds should be a positive value"))
+	}
+	if args.PodChurnThreshold != nil && *args.PodChurnThreshold < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("podChurnThreshold"), *args.PodChurnThreshold, "podChurnThreshold should not be negative"))
+	}
+	if args.PodChurnWeight != nil {
+		if *args.PodChurnWeight < 0 || *args.PodChurnWeight > 100 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("podChurnWeight"), *args.PodChurnWeight, "podChurnWeight should be in [0, 100]"))
+		} else if *args.PodChurnWeight > 0 && (args.PodChurnThreshold == nil || *args.PodChurnThreshold == 0) {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("podChurnWeight"), *args.PodChurnWeight, "podChurnThreshold is required to score the pod churn"))
+		}
+	}
+
+	if args.LocalStorageIOUtilizationThreshold != nil && (*args.LocalStorageIOUtilizationThreshold < 0 || *args.LocalStorageIOUtilizationThreshold > 100) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("localStorageIOUtilizationThreshold"), *args.LocalStorageIOUtilizationThreshold, "localStorageIOUtilizationThreshold should be in [0, 100]"))
+	}
+	if args.LocalStorageWeight != nil && (*args.LocalStorageWeight < 0 || *args.LocalStorageWeight > 100) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("localStorageWeight"), *args.LocalStorageWeight, "localStorageWeight should be in [0, 100]"))
+	}
+	if args.Aggregated != nil {
+		switch args.Aggregated.ScoreAggregationType {
+		case slov1alpha1.AVG, slov1alpha1.P50, slov1alpha1.P90, slov1alpha1.P95, slov1alpha1.P99:
+		default:
+			allErrs = append(allErrs, field.NotSupported(field.NewPath("aggregated", "scoreAggregationType"), args.Aggregated.ScoreAggregationType,
+				[]string{string(slov1alpha1.AVG), string(slov1alpha1.P50), string(slov1alpha1.P90), string(slov1alpha1.P95), string(slov1alpha1.P99)}))
+		}
+		if args.Aggregated.ScoreAggregatedDuration.Duration < 0 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("aggregated", "scoreAggregatedDuration"), args.Aggregated.ScoreAggregatedDuration, "scoreAggregatedDuration should not be negative"))
+		}
+	}
+
 	for resourceName := range args.ResourceWeights {
+		// the number of pods is counted directly, no need to estimate
+		if resourceName == corev1.ResourcePods {
+			continue
+		}
 		if _, ok := args.EstimatedScalingFactors[resourceName]; !ok {
 			allErrs = append(allErrs, field.NotFound(field.NewPath("estimatedScalingFactors"), resourceName))
 			break
 		}
 	}
 
+	if forecast := args.Forecast; forecast != nil {
+		forecastPath := field.NewPath("forecast")
+		if forecast.HorizonSeconds != nil && *forecast.HorizonSeconds < 0 {
+			allErrs = append(allErrs, field.Invalid(forecastPath.Child("horizonSeconds"), *forecast.HorizonSeconds, "horizonSeconds should not be negative"))
+		}
+		if forecast.LevelSmoothingPercent != nil && (*forecast.LevelSmoothingPercent <= 0 || *forecast.LevelSmoothingPercent > 100) {
+			allErrs = append(allErrs, field.Invalid(forecastPath.Child("levelSmoothingPercent"), *forecast.LevelSmoothingPercent, "levelSmoothingPercent should be in (0, 100]"))
+		}
+		if forecast.TrendSmoothingPercent != nil && (*forecast.TrendSmoothingPercent < 0 || *forecast.TrendSmoothingPercent > 100) {
+			allErrs = append(allErrs, field.Invalid(forecastPath.Child("trendSmoothingPercent"), *forecast.TrendSmoothingPercent, "trendSmoothingPercent should be in [0, 100]"))
+		}
+	}
+
+	if provider := args.MetricProvider; provider != nil {
+		providerPath := field.NewPath("metricProvider")
+		if provider.Type == "" {
+			allErrs = append(allErrs, field.Required(providerPath.Child("type"), "type is required"))
+		}
+		if provider.Type == config.MetricProviderPrometheus && provider.Address == "" {
+			allErrs = append(allErrs, field.Required(providerPath.Child("address"), "address is required"))
+		}
+		if len(provider.Queries) == 0 {
+			allErrs = append(allErrs, field.Required(providerPath.Child("queries"), "at least one query is required"))
+		}
+		if provider.CacheSeconds != nil && *provider.CacheSeconds <= 0 {
+			allErrs = append(allErrs, field.Invalid(providerPath.Child("cacheSeconds"), *provider.CacheSeconds, "cacheSeconds should be a positive value"))
+		}
+	}
+
+	if hotspot := args.HotspotCooldown; hotspot != nil {
+		hotspotPath := field.NewPath("hotspotCooldown")
+		if len(hotspot.CriticalThresholds) == 0 {
+			allErrs = append(allErrs, field.Required(hotspotPath.Child("criticalThresholds"), "at least one threshold is required"))
+		} else if err := validateResourceThresholds(hotspot.CriticalThresholds); err != nil {
+			allErrs = append(allErrs, field.Invalid(hotspotPath.Child("criticalThresholds"), hotspot.CriticalThresholds, err.Error()))
+		}
+		if hotspot.CooldownSeconds != nil && *hotspot.CooldownSeconds <= 0 {
+			allErrs = append(allErrs, field.Invalid(hotspotPath.Child("cooldownSeconds"), *hotspot.CooldownSeconds, "cooldownSeconds should be a positive value"))
+		}
+	}
+
+	for i, profile := range args.UsageThresholdProfiles {
+		profilePath := field.NewPath("usageThresholdProfiles").Index(i)
+		for j, priorityClass := range profile.PriorityClasses {
+			switch extension.PriorityClass(priorityClass) {
+			case extension.PriorityProd, extension.PriorityMid, extension.PriorityBatch, extension.PriorityFree:
+			default:
+				allErrs = append(allErrs, field.NotSupported(profilePath.Child("priorityClasses").Index(j), priorityClass,
+					[]string{string(extension.PriorityProd), string(extension.PriorityMid), string(extension.PriorityBatch), string(extension.PriorityFree)}))
+			}
+		}
+		if err := validateResourceThresholds(profile.UsageThresholds); err != nil {
+			allErrs = append(allErrs, field.Invalid(profilePath.Child("usageThresholds"), profile.UsageThresholds, err.Error()))
+		}
+		if err := validateResourceWeights(profile.ResourceWeights); err != nil {
+			allErrs = append(allErrs, field.Invalid(profilePath.Child("resourceWeights"), profile.ResourceWeights, err.Error()))
+		}
+		for resourceName := range profile.ResourceWeights {
+			if resourceName == corev1.ResourcePods {
+				continue
+			}
+			if _, ok := args.EstimatedScalingFactors[resourceName]; !ok {
+				allErrs = append(allErrs, field.NotFound(field.NewPath("estimatedScalingFactors"), resourceName))
+				break
+			}
+		}
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -113,6 +220,17 @@ func ValidateElasticQuotaArgs(elasticArgs *config.ElasticQuotaArgs) error {
 		}
 	}
 
+	for resName, q := range elasticArgs.DefaultQuotaGroupMin {
+		if q.Cmp(*resource.NewQuantity(0, resource.DecimalSI)) == -1 {
+			return fmt.Errorf("elasticQuotaArgs error, defaultQuotaGroupMin should be a positive value, resourceName:%v, got %v",
+				resName, q)
+		}
+		if max, ok := elasticArgs.DefaultQuotaGroupMax[resName]; ok && q.Cmp(max) == 1 {
+			return fmt.Errorf("elasticQuotaArgs error, defaultQuotaGroupMin should be less than defaultQuotaGroupMax, resourceName:%v, got %v",
+				resName, q)
+		}
+	}
+
 	for resName, q := range elasticArgs.SystemQuotaGroupMax {
 		if q.Cmp(*resource.NewQuantity(0, resource.DecimalSI)) == -1 {
 			return fmt.Errorf("elasticQuotaArgs error, systemQuotaGroupMax should be a positive value, resourceName:%v, got %v",
@@ -120,6 +238,111 @@ func ValidateElasticQuotaArgs(elasticArgs *config.ElasticQuotaArgs) error {
 		}
 	}
 
+	for _, priorityClass := range elasticArgs.MinQuotaPriorityClasses {
+		switch extension.PriorityClass(priorityClass) {
+		case extension.PriorityProd, extension.PriorityMid, extension.PriorityBatch, extension.PriorityFree:
+		default:
+			return fmt.Errorf("elasticQuotaArgs error, minQuotaPriorityClasses has unknown priority class %q", priorityClass)
+		}
+	}
+
+	if aging := elasticArgs.PriorityAging; aging != nil {
+		if aging.Interval.Duration <= 0 {
+			return fmt.Errorf("elasticQuotaArgs error, priorityAging.interval should be positive, got %v", aging.Interval.Duration)
+		}
+		if aging.Step <= 0 {
+			return fmt.Errorf("elasticQuotaArgs error, priorityAging.step should be positive, got %v", aging.Step)
+		}
+		if aging.MaxBoost < 0 {
+			return fmt.Errorf("elasticQuotaArgs error, priorityAging.maxBoost should not be negative, got %v", aging.MaxBoost)
+		}
+	}
+
+	if accounting := elasticArgs.PodAccounting; accounting != nil {
+		switch accounting.UsedCountPolicy {
+		case config.PodUsedCountPolicyScheduled, config.PodUsedCountPolicyRunning:
+		default:
+			return fmt.Errorf("elasticQuotaArgs error, podAccounting.usedCountPolicy is unknown, got %q", accounting.UsedCountPolicy)
+		}
+		if accounting.PendingTimeout.Duration < 0 {
+			return fmt.Errorf("elasticQuotaArgs error, podAccounting.pendingTimeout should not be negative, got %v", accounting.PendingTimeout.Duration)
+		}
+		if accounting.TerminalGracePeriod.Duration < 0 {
+			return fmt.Errorf("elasticQuotaArgs error, podAccounting.terminalGracePeriod should not be negative, got %v", accounting.TerminalGracePeriod.Duration)
+		}
+	}
+
+	switch elasticArgs.ReservationAccountingPolicy {
+	case "", config.ReservationAccountingPolicyNone, config.ReservationAccountingPolicyRequest, config.ReservationAccountingPolicyRequestAndUsed:
+	default:
+		return fmt.Errorf("elasticQuotaArgs error, reservationAccountingPolicy is unknown, got %q", elasticArgs.ReservationAccountingPolicy)
+	}
+
+	if elasticArgs.MinQuotaOversellPercent != nil && *elasticArgs.MinQuotaOversellPercent < 100 {
+		return fmt.Errorf("elasticQuotaArgs error, minQuotaOversellPercent should not be less than 100, got %v", *elasticArgs.MinQuotaOversellPercent)
+	}
+
+	if elasticArgs.AccountingVerificationPeriod != nil && elasticArgs.AccountingVerificationPeriod.Duration <= 0 {
+		return fmt.Errorf("elasticQuotaArgs error, accountingVerificationPeriod should be positive, got %v", elasticArgs.AccountingVerificationPeriod.Duration)
+	}
+
+	if elasticArgs.QuotaStatusSyncPeriod != nil && elasticArgs.QuotaStatusSyncPeriod.Duration <= 0 {
+		return fmt.Errorf("elasticQuotaArgs error, quotaStatusSyncPeriod should be positive, got %v", elasticArgs.QuotaStatusSyncPeriod.Duration)
+	}
+
+	if elasticArgs.QuotaDeletionSyncPeriod != nil && elasticArgs.QuotaDeletionSyncPeriod.Duration <= 0 {
+		return fmt.Errorf("elasticQuotaArgs error, quotaDeletionSyncPeriod should be positive, got %v", elasticArgs.QuotaDeletionSyncPeriod.Duration)
+	}
+
+	if elasticArgs.QuotaMetricsRecordPeriod != nil && elasticArgs.QuotaMetricsRecordPeriod.Duration <= 0 {
+		return fmt.Errorf("elasticQuotaArgs error, quotaMetricsRecordPeriod should be positive, got %v", elasticArgs.QuotaMetricsRecordPeriod.Duration)
+	}
+
+	if elasticArgs.QuotaRuntimeHistoryInterval != nil && elasticArgs.QuotaRuntimeHistoryInterval.Duration <= 0 {
+		return fmt.Errorf("elasticQuotaArgs error, quotaRuntimeHistoryInterval should be positive, got %v", elasticArgs.QuotaRuntimeHistoryInterval.Duration)
+	}
+
+	if elasticArgs.QuotaRuntimeHistoryRetention != nil && elasticArgs.QuotaRuntimeHistoryRetention.Duration < 0 {
+		return fmt.Errorf("elasticQuotaArgs error, quotaRuntimeHistoryRetention should not be negative, got %v", elasticArgs.QuotaRuntimeHistoryRetention.Duration)
+	}
+
+	if elasticArgs.PodQuotaAnnotationSyncPeriod != nil && elasticArgs.PodQuotaAnnotationSyncPeriod.Duration < 0 {
+		return fmt.Errorf("elasticQuotaArgs error, podQuotaAnnotationSyncPeriod should not be negative, got %v", elasticArgs.PodQuotaAnnotationSyncPeriod.Duration)
+	}
+
+	if elasticArgs.QuotaReservationCleanupInterval != nil && elasticArgs.QuotaReservationCleanupInterval.Duration <= 0 {
+		return fmt.Errorf("elasticQuotaArgs error, quotaReservationCleanupInterval should be positive, got %v", elasticArgs.QuotaReservationCleanupInterval.Duration)
+	}
+
+	if elasticArgs.BatchRecalculateInterval != nil && elasticArgs.BatchRecalculateInterval.Duration < 0 {
+		return fmt.Errorf("elasticQuotaArgs error, batchRecalculateInterval should not be negative, got %v", elasticArgs.BatchRecalculateInterval.Duration)
+	}
+
+	if provider := elasticArgs.SharedWeightProvider; provider != nil {
+		if provider.URL == "" {
+			return fmt.Errorf("elasticQuotaArgs error, sharedWeightProvider.url should not be empty")
+		}
+		if provider.Timeout.Duration <= 0 || provider.SyncPeriod.Duration <= 0 || provider.CacheTTL.Duration <= 0 {
+			return fmt.Errorf("elasticQuotaArgs error, sharedWeightProvider timeout, syncPeriod and cacheTTL should be positive, got %v, %v, %v",
+				provider.Timeout.Duration, provider.SyncPeriod.Duration, provider.CacheTTL.Duration)
+		}
+		if provider.TransitionPercent <= 0 || provider.TransitionPercent > 100 {
+			return fmt.Errorf("elasticQuotaArgs error, sharedWeightProvider.transitionPercent should be in (0, 100], got %v", provider.TransitionPercent)
+		}
+	}
+
+	if usageDecay := elasticArgs.UsageDecay; usageDecay != nil {
+		if usageDecay.HalfLife.Duration <= 0 {
+			return fmt.Errorf("elasticQuotaArgs error, usageDecay.halfLife should be positive, got %v", usageDecay.HalfLife.Duration)
+		}
+		if usageDecay.PenaltyPercent <= 0 || usageDecay.PenaltyPercent >= 100 {
+			return fmt.Errorf("elasticQuotaArgs error, usageDecay.penaltyPercent should be in (0, 100), got %v", usageDecay.PenaltyPercent)
+		}
+		if usageDecay.UpdateInterval.Duration <= 0 {
+			return fmt.Errorf("elasticQuotaArgs error, usageDecay.updateInterval should be positive, got %v", usageDecay.UpdateInterval.Duration)
+		}
+	}
+
 	return nil
 }
 
@@ -132,3 +355,45 @@ func ValidateCoschedulingArgs(coeSchedulingArgs *config.CoschedulingArgs) error
 	}
 	return nil
 }
+
+// ValidateDeviceShareArgs validates that DeviceShareArgs are correct.
+func ValidateDeviceShareArgs(args *config.DeviceShareArgs) error {
+	var allErrs field.ErrorList
+
+	builtinTypes := map[schedulingv1alpha1.DeviceType]bool{
+		schedulingv1alpha1.GPU:       true,
+		schedulingv1alpha1.FPGA:      true,
+		schedulingv1alpha1.RDMA:      true,
+		schedulingv1alpha1.RemoteGPU: true,
+		schedulingv1alpha1.MIG:       true,
+	}
+	deviceTypes := map[string]bool{}
+	resourceNames := map[corev1.ResourceName]bool{}
+	for _, name := range extension.CanonicalDeviceResourceNames {
+		resourceNames[name] = true
+	}
+	for i, deviceType := range args.CustomDeviceTypes {
+		path := field.NewPath("customDeviceTypes").Index(i)
+		if deviceType.Type == "" {
+			allErrs = append(allErrs, field.Required(path.Child("type"), "type should not be empty"))
+		} else if builtinTypes[schedulingv1alpha1.DeviceType(deviceType.Type)] || deviceTypes[deviceType.Type] {
+			allErrs = append(allErrs, field.Duplicate(path.Child("type"), deviceType.Type))
+		}
+		deviceTypes[deviceType.Type] = true
+
+		if len(deviceType.ResourceNames) == 0 {
+			allErrs = append(allErrs, field.Required(path.Child("resourceNames"), "resourceNames should not be empty"))
+		}
+		for j, name := range deviceType.ResourceNames {
+			if _, isMIG := extension.GetMIGProfile(name); resourceNames[name] || isMIG {
+				allErrs = append(allErrs, field.Duplicate(path.Child("resourceNames").Index(j), name))
+			}
+			resourceNames[name] = true
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return allErrs.ToAggregate()
+}
@@ -27,6 +27,19 @@ const (
 	GPU  DeviceType = "gpu"
 	FPGA DeviceType = "fpga"
 	RDMA DeviceType = "rdma"
+	// RemoteGPU represents the GPU attached over the fabric from a remote device pool
+	RemoteGPU DeviceType = "remote-gpu"
+	// MIG represents the Multi-Instance GPU device partitioned from a local GPU
+	MIG DeviceType = "mig"
+)
+
+// DeviceLatencyClass represents the access latency class of the remote device
+type DeviceLatencyClass string
+
+const (
+	DeviceLatencyClassLow    DeviceLatencyClass = "Low"
+	DeviceLatencyClassMedium DeviceLatencyClass = "Medium"
+	DeviceLatencyClassHigh   DeviceLatencyClass = "High"
 )
 
 type DeviceSpec struct {
@@ -44,6 +57,35 @@ type DeviceInfo struct {
 	Health bool `json:"health,omitempty"`
 	// Resources is a set of (resource name, quantity) pairs
 	Resources corev1.ResourceList `json:"resources,omitempty"`
+	// Remote represents the attributes of the remote device, only set for the remote device types
+	Remote *RemoteDeviceInfo `json:"remote,omitempty"`
+	// Topology represents the attachment of the local device on the node
+	Topology *DeviceTopology `json:"topology,omitempty"`
+	// MIG represents the attributes of the MIG device, only set for the MIG devices
+	MIG *MIGDeviceInfo `json:"mig,omitempty"`
+}
+
+type MIGDeviceInfo struct {
+	// ParentMinor represents the Minor number of the GPU the MIG device is partitioned from
+	ParentMinor int32 `json:"parentMinor"`
+	// Profile represents the profile of the MIG device, e.g. 1g.5gb
+	Profile string `json:"profile,omitempty"`
+}
+
+type DeviceTopology struct {
+	// NodeID represents the NUMA Node the device is attached to, -1 if unknown
+	NodeID int32 `json:"nodeID"`
+	// PCIEID represents the PCIe root port the device is attached to, the devices under the same PCIe switch share it
+	PCIEID string `json:"pcieID,omitempty"`
+	// BusID represents the PCI bus address of the device, e.g. 0000:3b:00.0
+	BusID string `json:"busID,omitempty"`
+}
+
+type RemoteDeviceInfo struct {
+	// Endpoint represents the address to attach the device over the fabric
+	Endpoint string `json:"endpoint,omitempty"`
+	// LatencyClass represents the access latency class of the device
+	LatencyClass DeviceLatencyClass `json:"latencyClass,omitempty"`
 }
 
 type DeviceStatus struct {
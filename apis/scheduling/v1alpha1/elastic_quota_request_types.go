@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type ElasticQuotaRequestSpec struct {
+	// QuotaName is the name of the ElasticQuota in the same namespace to be changed.
+	// +kubebuilder:validation:Required
+	QuotaName string `json:"quotaName"`
+	// Min is the requested min of the ElasticQuota. Only the listed resources are changed,
+	// the other resources of the ElasticQuota are kept.
+	// +optional
+	Min corev1.ResourceList `json:"min,omitempty"`
+	// Max is the requested max of the ElasticQuota. Only the listed resources are changed,
+	// the other resources of the ElasticQuota are kept.
+	// +optional
+	Max corev1.ResourceList `json:"max,omitempty"`
+	// Reason describes why the change is requested.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+type ElasticQuotaRequestPhase string
+
+const (
+	// ElasticQuotaRequestPending represents the request is waiting for the approval.
+	ElasticQuotaRequestPending ElasticQuotaRequestPhase = "Pending"
+	// ElasticQuotaRequestApproved represents the request is approved by the cluster admin and is to be applied.
+	ElasticQuotaRequestApproved ElasticQuotaRequestPhase = "Approved"
+	// ElasticQuotaRequestRejected represents the request is rejected by the cluster admin.
+	ElasticQuotaRequestRejected ElasticQuotaRequestPhase = "Rejected"
+	// ElasticQuotaRequestApplied represents the approved amounts are patched onto the ElasticQuota.
+	ElasticQuotaRequestApplied ElasticQuotaRequestPhase = "Applied"
+	// ElasticQuotaRequestFailed represents the approved amounts can't be applied, e.g. the ElasticQuota is missing
+	// or the quota tree rejects the change.
+	ElasticQuotaRequestFailed ElasticQuotaRequestPhase = "Failed"
+)
+
+type ElasticQuotaRequestStatus struct {
+	// Phase of the request. The cluster admin approves or rejects the request by setting the phase to
+	// Approved or Rejected through the status subresource, which the namespace admin is not allowed to update.
+	// +optional
+	Phase ElasticQuotaRequestPhase `json:"phase,omitempty"`
+	// ObservedGeneration is the generation of the spec the phase refers to. The approval is revoked and the request
+	// goes back to Pending if the spec is changed after the approval.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// ApprovedMin is the min approved by the cluster admin. Defaults to the requested min.
+	// +optional
+	ApprovedMin corev1.ResourceList `json:"approvedMin,omitempty"`
+	// ApprovedMax is the max approved by the cluster admin. Defaults to the requested max.
+	// +optional
+	ApprovedMax corev1.ResourceList `json:"approvedMax,omitempty"`
+	// Approver is the user who approves or rejects the request, which is only informational.
+	// +optional
+	Approver string `json:"approver,omitempty"`
+	// Message is a human-readable message indicating details about the phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// LastTransitionTime is the last time the phase transited.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:resource:shortName=eqr
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Quota",type="string",JSONPath=".spec.quotaName"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The phase of ElasticQuotaRequest"
+// +kubebuilder:printcolumn:name="Approver",type="string",JSONPath=".status.approver"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ElasticQuotaRequest is the Schema for the ElasticQuotaRequest API.
+// An ElasticQuotaRequest lets the namespace admin request changes of the min/max of an ElasticQuota in the
+// namespace, and the approved amounts are patched onto the ElasticQuota by koord-manager.
+type ElasticQuotaRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ElasticQuotaRequestSpec   `json:"spec,omitempty"`
+	Status ElasticQuotaRequestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ElasticQuotaRequestList contains a list of ElasticQuotaRequest
+type ElasticQuotaRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ElasticQuotaRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ElasticQuotaRequest{}, &ElasticQuotaRequestList{})
+}
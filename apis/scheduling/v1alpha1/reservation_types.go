@@ -62,8 +62,38 @@ type ReservationSpec struct {
 	// and are not allocatable to other owners anymore.
 	// +optional
 	AllocateOnce bool `json:"allocateOnce,omitempty"`
+	// ExpirationPolicy decides how the reservation behaves when it expires or is consumed. Defaults to Release.
+	// +kubebuilder:validation:Enum=Release;Renew;Consumed
+	// +optional
+	ExpirationPolicy ReservationExpirationPolicy `json:"expirationPolicy,omitempty"`
+	// MaxConsumptions is the number of owners which can allocate the reservation one after another, e.g. the
+	// restarted pods of a batch job. The reservation succeeds when the last one allocates it.
+	// It is required when the `expirationPolicy` is Consumed.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConsumptions *int32 `json:"maxConsumptions,omitempty"`
+	// Replicas is the number of the reservations to create from the template, e.g. to pre-book the capacity for the
+	// whole surge of a rolling update in one request. A reservation with `replicas` is not scheduled itself, but
+	// creates the child reservations owned by it and reports how many of them are available.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
 }
 
+type ReservationExpirationPolicy string
+
+const (
+	// ReservationExpirationPolicyRelease expires the reservation and releases the reserved resources when the TTL
+	// or the `expires` is reached.
+	ReservationExpirationPolicyRelease ReservationExpirationPolicy = "Release"
+	// ReservationExpirationPolicyRenew renews the reservation for another TTL when it expires and is still allocated
+	// by any owner, otherwise the reservation expires and releases the reserved resources.
+	ReservationExpirationPolicyRenew ReservationExpirationPolicy = "Renew"
+	// ReservationExpirationPolicyConsumed keeps the reservation available for the sequential owners until
+	// `maxConsumptions` owners have allocated it. The TTL and `expires` are still respected.
+	ReservationExpirationPolicyConsumed ReservationExpirationPolicy = "Consumed"
+)
+
 // ReservationTemplateSpec describes the data a Reservation should have when created from a template
 type ReservationTemplateSpec struct {
 	// Standard object's metadata.
@@ -99,6 +129,20 @@ type ReservationStatus struct {
 	// Resource allocated by current owners.
 	// +optional
 	Allocated corev1.ResourceList `json:"allocated,omitempty"`
+	// Number of owners which have allocated the reservation, including the finished ones.
+	// +optional
+	Consumptions int32 `json:"consumptions,omitempty"`
+	// The last time the reservation was renewed by the Renew expiration policy. The TTL is counted from it instead
+	// of the creation time when it is set.
+	// +optional
+	RenewTime *metav1.Time `json:"renewTime,omitempty"`
+	// Number of the child reservations created for the `replicas`.
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+	// Number of the child reservations available to allocate. The reservation is partially fulfilled if it is less
+	// than the `replicas`.
+	// +optional
+	AvailableReplicas int32 `json:"availableReplicas,omitempty"`
 }
 
 // ReservationOwner indicates the owner specification which can allocate reserved resources.
@@ -159,6 +203,10 @@ const (
 	ReasonReservationAvailable = "Available"
 	ReasonReservationSucceeded = "Succeeded"
 	ReasonReservationExpired   = "Expired"
+	ReasonReservationPreempted = "Preempted"
+	ReasonReservationRelocated = "Relocated"
+
+	ReasonReservationPartiallyAvailable = "PartiallyAvailable"
 )
 
 type ReservationCondition struct {
@@ -0,0 +1,58 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type CacheGroupSpec struct {
+	// ResctrlQOS is the LLC and memory bandwidth partition shared by the pods of the group. The pods in the group
+	// are assigned to a dedicated RDT class (CLOS) on each node, which is isolated from the other tenants.
+	// +optional
+	ResctrlQOS *ResctrlQOS `json:"resctrlQOS,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +kubebuilder:resource:shortName=cg
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// CacheGroup is the Schema for the CacheGroup API.
+// A CacheGroup declares a group of cooperating pods (e.g. the pods of the same service) which share an RDT class
+// while being isolated from the other tenants. The pods in the same namespace join the group by the annotation
+// `koordinator.sh/cacheGroup`, koord-scheduler prefers to co-locate them and koordlet assigns them to the CLOS.
+type CacheGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CacheGroupSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// CacheGroupList contains a list of CacheGroup
+type CacheGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CacheGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CacheGroup{}, &CacheGroupList{})
+}
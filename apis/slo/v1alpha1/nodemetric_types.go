@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -25,6 +26,47 @@ import (
 
 type NodeMetricInfo struct {
 	NodeUsage ResourceMap `json:"nodeUsage,omitempty"`
+	// AggregatedNodeUsages contains the aggregated node usages over the durations of the NodeAggregatePolicy,
+	// e.g. the P95 usage of the last 10 minutes.
+	AggregatedNodeUsages []AggregatedUsage `json:"aggregatedNodeUsages,omitempty"`
+	// LocalStorages contains the capacity and the IO load of the local storages backing the local PVs.
+	LocalStorages []LocalStorageInfo `json:"localStorages,omitempty"`
+}
+
+// LocalStorageInfo describes a local storage of the node, e.g. a mount point from which the local PVs are provisioned.
+type LocalStorageInfo struct {
+	// StorageClassName is the storage class of the local PVs provisioned from the storage.
+	StorageClassName string `json:"storageClassName"`
+	// Path is the mount point of the storage on the node.
+	Path string `json:"path,omitempty"`
+	// Capacity is the total capacity of the storage.
+	Capacity resource.Quantity `json:"capacity,omitempty"`
+	// Free is the free capacity of the storage.
+	Free resource.Quantity `json:"free,omitempty"`
+	// IOUtilization is the percentage of the time the device backing the storage is busy serving IO.
+	IOUtilization int64 `json:"ioUtilization,omitempty"`
+}
+
+// AggregationType is the function to aggregate the usage samples over a duration.
+type AggregationType string
+
+const (
+	// AVG is the average of the usage samples.
+	AVG AggregationType = "avg"
+	// P50 is the 50th percentile of the usage samples.
+	P50 AggregationType = "p50"
+	// P90 is the 90th percentile of the usage samples.
+	P90 AggregationType = "p90"
+	// P95 is the 95th percentile of the usage samples.
+	P95 AggregationType = "p95"
+	// P99 is the 99th percentile of the usage samples.
+	P99 AggregationType = "p99"
+)
+
+// AggregatedUsage is the usage aggregated over the duration by each aggregation type.
+type AggregatedUsage struct {
+	Usage    map[AggregationType]ResourceMap `json:"usage,omitempty"`
+	Duration metav1.Duration                 `json:"duration,omitempty"`
 }
 
 type PodMetricInfo struct {
@@ -45,6 +87,14 @@ type NodeMetricCollectPolicy struct {
 	AggregateDurationSeconds *int64 `json:"aggregateDurationSeconds,omitempty"`
 	// ReportIntervalSeconds represents the report period in seconds
 	ReportIntervalSeconds *int64 `json:"reportIntervalSeconds,omitempty"`
+	// NodeAggregatePolicy represents the durations to aggregate the node usage, nil means the node usage is not
+	// aggregated but the latest average reported only.
+	NodeAggregatePolicy *AggregatePolicy `json:"nodeAggregatePolicy,omitempty"`
+}
+
+// AggregatePolicy defines the durations to aggregate the usage over.
+type AggregatePolicy struct {
+	Durations []metav1.Duration `json:"durations,omitempty"`
 }
 
 // NodeMetricStatus defines the observed state of NodeMetric
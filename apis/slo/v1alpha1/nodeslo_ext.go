@@ -43,3 +43,33 @@ func (in *ExtensionsMap) DeepCopy() *ExtensionsMap {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// GetResourceQOSStrategy returns the ResourceQOSStrategy for the pods of the RuntimeClass, which is the strategy of
+// the node if the RuntimeClass is not overridden.
+func (in *NodeSLOSpec) GetResourceQOSStrategy(runtimeClassName string) *ResourceQOSStrategy {
+	if strategy := in.getRuntimeClassStrategy(runtimeClassName); strategy != nil && strategy.ResourceQOSStrategy != nil {
+		return strategy.ResourceQOSStrategy
+	}
+	return in.ResourceQOSStrategy
+}
+
+// GetCPUBurstStrategy returns the CPUBurstStrategy for the pods of the RuntimeClass, which is the strategy of the
+// node if the RuntimeClass is not overridden.
+func (in *NodeSLOSpec) GetCPUBurstStrategy(runtimeClassName string) *CPUBurstStrategy {
+	if strategy := in.getRuntimeClassStrategy(runtimeClassName); strategy != nil && strategy.CPUBurstStrategy != nil {
+		return strategy.CPUBurstStrategy
+	}
+	return in.CPUBurstStrategy
+}
+
+func (in *NodeSLOSpec) getRuntimeClassStrategy(runtimeClassName string) *RuntimeClassStrategy {
+	if runtimeClassName == "" {
+		return nil
+	}
+	for i := range in.RuntimeClassStrategies {
+		if in.RuntimeClassStrategies[i].RuntimeClassName == runtimeClassName {
+			return &in.RuntimeClassStrategies[i]
+		}
+	}
+	return nil
+}
@@ -129,6 +129,7 @@ type ResourceQOS struct {
 	CPUQOS     *CPUQOSCfg     `json:"cpuQOS,omitempty"`
 	MemoryQOS  *MemoryQOSCfg  `json:"memoryQOS,omitempty"`
 	ResctrlQOS *ResctrlQOSCfg `json:"resctrlQOS,omitempty"`
+	BlkIOQOS   *BlkIOQOSCfg   `json:"blkioQOS,omitempty"`
 }
 
 type ResourceQOSStrategy struct {
@@ -167,6 +168,18 @@ type ResourceThresholdStrategy struct {
 	CPUSuppressThresholdPercent *int64 `json:"cpuSuppressThresholdPercent,omitempty"`
 	// CPUSuppressPolicy
 	CPUSuppressPolicy CPUSuppressPolicy `json:"cpuSuppressPolicy,omitempty"`
+	// CPUSuppressRampUpSeconds is the period to restore the BE CPU to the full capacity when the suppression is
+	// relieved or disabled. The BE CPU is widened gradually and the ramp-up holds while the LS CPU usage keeps
+	// increasing, to avoid the interference oscillation. If not set, the BE CPU increases by at most 10% of the
+	// node CPU each round, and is restored at once when the suppression is disabled.
+	// +kubebuilder:validation:Minimum=0
+	CPUSuppressRampUpSeconds *int64 `json:"cpuSuppressRampUpSeconds,omitempty"`
+	// CPUSuppressPSIThresholdPercent suppresses the BE CPU to the minimum when the CPU pressure of any LS pod, the
+	// avg10 of "some" in percentage, exceeds the threshold, which reacts faster to the contention than the
+	// utilization. It requires the PSICollector feature of koordlet. If not set, the CPU pressure is ignored.
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	CPUSuppressPSIThresholdPercent *int64 `json:"cpuSuppressPSIThresholdPercent,omitempty"`
 
 	// upper: memory evict threshold percentage (0,100), default = 70
 	// +kubebuilder:default=70
@@ -177,6 +190,12 @@ type ResourceThresholdStrategy struct {
 	// +kubebuilder:validation:Maximum=100
 	// +kubebuilder:validation:Minimum=0
 	MemoryEvictLowerPercent *int64 `json:"memoryEvictLowerPercent,omitempty"`
+	// MemoryEvictPSIThresholdPercent evicts BE pods when the memory pressure of the node, the avg10 of "full" in
+	// percentage, exceeds the threshold, even if the memory usage is below MemoryEvictThresholdPercent. It requires
+	// the PSICollector feature of koordlet. If not set, the memory pressure is ignored.
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	MemoryEvictPSIThresholdPercent *int64 `json:"memoryEvictPSIThresholdPercent,omitempty"`
 
 	// if be CPU RealLimit/allocatedLimit > CPUEvictBESatisfactionUpperPercent, then stop evict BE pods
 	CPUEvictBESatisfactionUpperPercent *int64 `json:"cpuEvictBESatisfactionUpperPercent,omitempty"`
@@ -187,6 +206,34 @@ type ResourceThresholdStrategy struct {
 }
 
 // ResctrlQOSCfg stores node-level config of resctrl qos
+// BlkIOQOS limits the block io of the pods on the devices used by their volumes and rootfs, e.g. to prevent the BE
+// jobs from starving the LS databases on the shared disks.
+type BlkIOQOS struct {
+	// Weight is the proportional io weight of pods (blkio.weight), which is scaled to io.weight on the cgroup v2
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=1000
+	Weight *int64 `json:"weight,omitempty"`
+	// ReadIOPS limits the read iops of a pod on each device, 0 means no limit
+	// +kubebuilder:validation:Minimum=0
+	ReadIOPS *int64 `json:"readIOPS,omitempty"`
+	// WriteIOPS limits the write iops of a pod on each device, 0 means no limit
+	// +kubebuilder:validation:Minimum=0
+	WriteIOPS *int64 `json:"writeIOPS,omitempty"`
+	// ReadBPS limits the read bytes per second of a pod on each device, 0 means no limit
+	// +kubebuilder:validation:Minimum=0
+	ReadBPS *int64 `json:"readBPS,omitempty"`
+	// WriteBPS limits the write bytes per second of a pod on each device, 0 means no limit
+	// +kubebuilder:validation:Minimum=0
+	WriteBPS *int64 `json:"writeBPS,omitempty"`
+}
+
+// BlkIOQOSCfg stores node-level config of block io qos
+type BlkIOQOSCfg struct {
+	// Enable indicates whether the block io qos is enabled.
+	Enable   *bool `json:"enable,omitempty"`
+	BlkIOQOS `json:",inline"`
+}
+
 type ResctrlQOSCfg struct {
 	// Enable indicates whether the resctrl qos is enabled.
 	Enable     *bool `json:"enable,omitempty"`
@@ -246,6 +293,25 @@ type CPUBurstStrategy struct {
 	SharePoolThresholdPercent *int64 `json:"sharePoolThresholdPercent,omitempty"`
 }
 
+// CPUReservationStrategy reserves the CPUs for the user-space networking/storage offload stacks (e.g. OVS-DPDK,
+// SPDK). The reserved CPUs are excluded from both the LS exclusive allocation and the BE shares.
+type CPUReservationStrategy struct {
+	// ReservedCPUs is the cpuset of the reserved CPUs, e.g. "0-1,32-33"
+	ReservedCPUs *string `json:"reservedCPUs,omitempty"`
+}
+
+// RuntimeClassStrategy overrides the strategies of the node for the pods running with the RuntimeClass, so the
+// sandboxed pods (e.g. kata, gvisor) can be configured differently from the runc pods on the mixed-runtime nodes.
+// The unset fields of the strategies inherit the strategies of the node.
+type RuntimeClassStrategy struct {
+	// RuntimeClassName is the name of the RuntimeClass of the pods
+	RuntimeClassName string `json:"runtimeClassName"`
+	// QoS config strategy for pods of different qos-class
+	ResourceQOSStrategy *ResourceQOSStrategy `json:"resourceQOSStrategy,omitempty"`
+	// CPU Burst Strategy
+	CPUBurstStrategy *CPUBurstStrategy `json:"cpuBurstStrategy,omitempty"`
+}
+
 // NodeSLOSpec defines the desired state of NodeSLO
 type NodeSLOSpec struct {
 	// BE pods will be limited if node resource usage overload
@@ -254,6 +320,10 @@ type NodeSLOSpec struct {
 	ResourceQOSStrategy *ResourceQOSStrategy `json:"resourceQOSStrategy,omitempty"`
 	// CPU Burst Strategy
 	CPUBurstStrategy *CPUBurstStrategy `json:"cpuBurstStrategy,omitempty"`
+	// CPU Reservation Strategy for the offload stacks
+	CPUReservationStrategy *CPUReservationStrategy `json:"cpuReservationStrategy,omitempty"`
+	// Strategies overridden for the pods of the RuntimeClasses
+	RuntimeClassStrategies []RuntimeClassStrategy `json:"runtimeClassStrategies,omitempty"`
 	// Third party extensions for NodeSLO
 	Extensions *ExtensionsMap `json:"extensions,omitempty"`
 }
@@ -24,9 +24,114 @@ package v1alpha1
 import (
 	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AggregatePolicy) DeepCopyInto(out *AggregatePolicy) {
+	*out = *in
+	if in.Durations != nil {
+		in, out := &in.Durations, &out.Durations
+		*out = make([]metav1.Duration, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AggregatePolicy.
+func (in *AggregatePolicy) DeepCopy() *AggregatePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AggregatePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AggregatedUsage) DeepCopyInto(out *AggregatedUsage) {
+	*out = *in
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = make(map[AggregationType]ResourceMap, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AggregatedUsage.
+func (in *AggregatedUsage) DeepCopy() *AggregatedUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(AggregatedUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlkIOQOS) DeepCopyInto(out *BlkIOQOS) {
+	*out = *in
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ReadIOPS != nil {
+		in, out := &in.ReadIOPS, &out.ReadIOPS
+		*out = new(int64)
+		**out = **in
+	}
+	if in.WriteIOPS != nil {
+		in, out := &in.WriteIOPS, &out.WriteIOPS
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ReadBPS != nil {
+		in, out := &in.ReadBPS, &out.ReadBPS
+		*out = new(int64)
+		**out = **in
+	}
+	if in.WriteBPS != nil {
+		in, out := &in.WriteBPS, &out.WriteBPS
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlkIOQOS.
+func (in *BlkIOQOS) DeepCopy() *BlkIOQOS {
+	if in == nil {
+		return nil
+	}
+	out := new(BlkIOQOS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlkIOQOSCfg) DeepCopyInto(out *BlkIOQOSCfg) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	in.BlkIOQOS.DeepCopyInto(&out.BlkIOQOS)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlkIOQOSCfg.
+func (in *BlkIOQOSCfg) DeepCopy() *BlkIOQOSCfg {
+	if in == nil {
+		return nil
+	}
+	out := new(BlkIOQOSCfg)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CPUBurstConfig) DeepCopyInto(out *CPUBurstConfig) {
 	*out = *in
@@ -119,6 +224,121 @@ func (in *CPUQOSCfg) DeepCopy() *CPUQOSCfg {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUReservationStrategy) DeepCopyInto(out *CPUReservationStrategy) {
+	*out = *in
+	if in.ReservedCPUs != nil {
+		in, out := &in.ReservedCPUs, &out.ReservedCPUs
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUReservationStrategy.
+func (in *CPUReservationStrategy) DeepCopy() *CPUReservationStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUReservationStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CacheGroup) DeepCopyInto(out *CacheGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheGroup.
+func (in *CacheGroup) DeepCopy() *CacheGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(CacheGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CacheGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CacheGroupList) DeepCopyInto(out *CacheGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CacheGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheGroupList.
+func (in *CacheGroupList) DeepCopy() *CacheGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(CacheGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CacheGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CacheGroupSpec) DeepCopyInto(out *CacheGroupSpec) {
+	*out = *in
+	if in.ResctrlQOS != nil {
+		in, out := &in.ResctrlQOS, &out.ResctrlQOS
+		*out = new(ResctrlQOS)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheGroupSpec.
+func (in *CacheGroupSpec) DeepCopy() *CacheGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CacheGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalStorageInfo) DeepCopyInto(out *LocalStorageInfo) {
+	*out = *in
+	out.Capacity = in.Capacity.DeepCopy()
+	out.Free = in.Free.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalStorageInfo.
+func (in *LocalStorageInfo) DeepCopy() *LocalStorageInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalStorageInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MemoryQOS) DeepCopyInto(out *MemoryQOS) {
 	*out = *in
@@ -240,6 +460,11 @@ func (in *NodeMetricCollectPolicy) DeepCopyInto(out *NodeMetricCollectPolicy) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.NodeAggregatePolicy != nil {
+		in, out := &in.NodeAggregatePolicy, &out.NodeAggregatePolicy
+		*out = new(AggregatePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMetricCollectPolicy.
@@ -256,6 +481,20 @@ func (in *NodeMetricCollectPolicy) DeepCopy() *NodeMetricCollectPolicy {
 func (in *NodeMetricInfo) DeepCopyInto(out *NodeMetricInfo) {
 	*out = *in
 	in.NodeUsage.DeepCopyInto(&out.NodeUsage)
+	if in.AggregatedNodeUsages != nil {
+		in, out := &in.AggregatedNodeUsages, &out.AggregatedNodeUsages
+		*out = make([]AggregatedUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LocalStorages != nil {
+		in, out := &in.LocalStorages, &out.LocalStorages
+		*out = make([]LocalStorageInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMetricInfo.
@@ -432,6 +671,18 @@ func (in *NodeSLOSpec) DeepCopyInto(out *NodeSLOSpec) {
 		*out = new(CPUBurstStrategy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CPUReservationStrategy != nil {
+		in, out := &in.CPUReservationStrategy, &out.CPUReservationStrategy
+		*out = new(CPUReservationStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RuntimeClassStrategies != nil {
+		in, out := &in.RuntimeClassStrategies, &out.RuntimeClassStrategies
+		*out = make([]RuntimeClassStrategy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Extensions != nil {
 		in, out := &in.Extensions, &out.Extensions
 		*out = (*in).DeepCopy()
@@ -593,6 +844,11 @@ func (in *ResourceQOS) DeepCopyInto(out *ResourceQOS) {
 		*out = new(ResctrlQOSCfg)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.BlkIOQOS != nil {
+		in, out := &in.BlkIOQOS, &out.BlkIOQOS
+		*out = new(BlkIOQOSCfg)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQOS.
@@ -658,6 +914,16 @@ func (in *ResourceThresholdStrategy) DeepCopyInto(out *ResourceThresholdStrategy
 		*out = new(int64)
 		**out = **in
 	}
+	if in.CPUSuppressRampUpSeconds != nil {
+		in, out := &in.CPUSuppressRampUpSeconds, &out.CPUSuppressRampUpSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CPUSuppressPSIThresholdPercent != nil {
+		in, out := &in.CPUSuppressPSIThresholdPercent, &out.CPUSuppressPSIThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
 	if in.MemoryEvictThresholdPercent != nil {
 		in, out := &in.MemoryEvictThresholdPercent, &out.MemoryEvictThresholdPercent
 		*out = new(int64)
@@ -668,6 +934,11 @@ func (in *ResourceThresholdStrategy) DeepCopyInto(out *ResourceThresholdStrategy
 		*out = new(int64)
 		**out = **in
 	}
+	if in.MemoryEvictPSIThresholdPercent != nil {
+		in, out := &in.MemoryEvictPSIThresholdPercent, &out.MemoryEvictPSIThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
 	if in.CPUEvictBESatisfactionUpperPercent != nil {
 		in, out := &in.CPUEvictBESatisfactionUpperPercent, &out.CPUEvictBESatisfactionUpperPercent
 		*out = new(int64)
@@ -694,3 +965,28 @@ func (in *ResourceThresholdStrategy) DeepCopy() *ResourceThresholdStrategy {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeClassStrategy) DeepCopyInto(out *RuntimeClassStrategy) {
+	*out = *in
+	if in.ResourceQOSStrategy != nil {
+		in, out := &in.ResourceQOSStrategy, &out.ResourceQOSStrategy
+		*out = new(ResourceQOSStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CPUBurstStrategy != nil {
+		in, out := &in.CPUBurstStrategy, &out.CPUBurstStrategy
+		*out = new(CPUBurstStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeClassStrategy.
+func (in *RuntimeClassStrategy) DeepCopy() *RuntimeClassStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeClassStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+type kubeOptions struct {
+	kubeconfig string
+	context    string
+}
+
+func (o *kubeOptions) restConfig() (*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.kubeconfig
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: o.context}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+}
+
+// NewKoordCommand creates the root command of koord-cli, which can also be installed as the kubectl plugin
+// "kubectl-koord".
+func NewKoordCommand() *cobra.Command {
+	kubeOpts := &kubeOptions{}
+	cmd := &cobra.Command{
+		Use:           "koord",
+		Short:         "The command line tool to operate the koordinator resources",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	fs := cmd.PersistentFlags()
+	fs.StringVar(&kubeOpts.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file, defaults to the KUBECONFIG environment or ~/.kube/config.")
+	fs.StringVar(&kubeOpts.context, "context", "", "The name of the kubeconfig context to use.")
+
+	cmd.AddCommand(newQuotaCommand(kubeOpts))
+	return cmd
+}
+
+func newQuotaCommand(kubeOpts *kubeOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "quota",
+		Short: "Inspect the elastic quotas",
+	}
+	cmd.AddCommand(newQuotaTreeCommand(kubeOpts))
+	return cmd
+}
@@ -0,0 +1,283 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	"sigs.k8s.io/yaml"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+
+	ansiRed   = "\x1b[31m"
+	ansiReset = "\x1b[0m"
+)
+
+const (
+	quotaStatusOK          = "OK"
+	quotaStatusOverRuntime = "OverRuntime"
+	quotaStatusOverMax     = "OverMax"
+)
+
+type quotaTreeOptions struct {
+	quotasFile string
+	resources  []string
+	color      string
+}
+
+// newQuotaTreeCommand creates the command to render the hierarchy of the ElasticQuotas as a tree, along with the
+// Used published to the status and the Runtime published to the annotation by koord-scheduler.
+func newQuotaTreeCommand(kubeOpts *kubeOptions) *cobra.Command {
+	opts := &quotaTreeOptions{}
+	cmd := &cobra.Command{
+		Use:   "tree",
+		Short: "Render the hierarchy of the elastic quotas with the used/runtime/min/max",
+		Long: `Render the hierarchy of the elastic quotas with the used/runtime/min/max.
+The quota group whose used exceeds its runtime or max is highlighted, the runtime is read from the annotation
+published by koord-scheduler, so it's only available when the status writer of the ElasticQuota plugin is enabled.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.color != colorAuto && opts.color != colorAlways && opts.color != colorNever {
+				return fmt.Errorf("invalid color %q, expect one of auto, always and never", opts.color)
+			}
+			quotas, err := loadQuotas(cmd.Context(), kubeOpts, opts.quotasFile)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			return renderQuotaTree(out, buildQuotaTree(quotas), opts.resources, useColor(out, opts.color))
+		},
+	}
+	fs := cmd.Flags()
+	fs.StringVar(&opts.quotasFile, "quotas", "", `Read the ElasticQuotas from the file exported by "kubectl get elasticquotas -A -o yaml" instead of the cluster.`)
+	fs.StringSliceVar(&opts.resources, "resources", []string{string(corev1.ResourceCPU), string(corev1.ResourceMemory)}, "The resources to display.")
+	fs.StringVar(&opts.color, "color", colorAuto, "Whether to highlight the over-quota groups, one of auto, always and never.")
+	return cmd
+}
+
+func loadQuotas(ctx context.Context, kubeOpts *kubeOptions, quotasFile string) ([]*v1alpha1.ElasticQuota, error) {
+	quotaList := &v1alpha1.ElasticQuotaList{}
+	if quotasFile != "" {
+		data, err := ioutil.ReadFile(quotasFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(data, quotaList); err != nil {
+			return nil, fmt.Errorf("failed to parse %s, err: %v", quotasFile, err)
+		}
+	} else {
+		restConfig, err := kubeOpts.restConfig()
+		if err != nil {
+			return nil, err
+		}
+		client, err := schedclientset.NewForConfig(restConfig)
+		if err != nil {
+			return nil, err
+		}
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		quotaList, err = client.SchedulingV1alpha1().ElasticQuotas(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list elastic quotas, err: %v", err)
+		}
+	}
+	quotas := make([]*v1alpha1.ElasticQuota, 0, len(quotaList.Items))
+	for i := range quotaList.Items {
+		quotas = append(quotas, &quotaList.Items[i])
+	}
+	return quotas, nil
+}
+
+type quotaTreeNode struct {
+	quota    *v1alpha1.ElasticQuota
+	runtime  corev1.ResourceList
+	children []*quotaTreeNode
+}
+
+// buildQuotaTree returns the top level quota groups, whose parent is the root quota or missing.
+func buildQuotaTree(quotas []*v1alpha1.ElasticQuota) []*quotaTreeNode {
+	nodes := make(map[string]*quotaTreeNode, len(quotas))
+	for _, quota := range quotas {
+		node := &quotaTreeNode{quota: quota}
+		if data, ok := quota.Annotations[extension.AnnotationRuntime]; ok {
+			runtime := corev1.ResourceList{}
+			if err := json.Unmarshal([]byte(data), &runtime); err == nil {
+				node.runtime = runtime
+			}
+		}
+		nodes[quota.Name] = node
+	}
+
+	var roots []*quotaTreeNode
+	for _, node := range nodes {
+		parent, ok := nodes[extension.GetParentQuotaName(node.quota)]
+		if !ok || node.quota.Name == extension.RootQuotaName {
+			roots = append(roots, node)
+			continue
+		}
+		parent.children = append(parent.children, node)
+	}
+	for _, node := range nodes {
+		sortQuotaTreeNodes(node.children)
+	}
+	sortQuotaTreeNodes(roots)
+
+	// the quota groups in a cycle are unreachable from the roots, break the cycle to show them
+	visited := map[string]bool{}
+	var visit func(node *quotaTreeNode)
+	visit = func(node *quotaTreeNode) {
+		visited[node.quota.Name] = true
+		for _, child := range node.children {
+			visit(child)
+		}
+	}
+	for _, root := range roots {
+		visit(root)
+	}
+	var names []string
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !visited[name] {
+			node := nodes[name]
+			roots = append(roots, node)
+			visit(node)
+		}
+	}
+	return roots
+}
+
+func sortQuotaTreeNodes(nodes []*quotaTreeNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].quota.Name < nodes[j].quota.Name
+	})
+}
+
+// getQuotaStatus checks whether the used of the quota group exceeds its max or runtime.
+func getQuotaStatus(node *quotaTreeNode) string {
+	used := node.quota.Status.Used
+	for name, quantity := range used {
+		if max, ok := node.quota.Spec.Max[name]; ok && quantity.Cmp(max) > 0 {
+			return quotaStatusOverMax
+		}
+	}
+	if node.runtime != nil {
+		for name, quantity := range used {
+			runtime := node.runtime[name]
+			if quantity.Cmp(runtime) > 0 {
+				return quotaStatusOverRuntime
+			}
+		}
+	}
+	return quotaStatusOK
+}
+
+func renderQuotaTree(out io.Writer, roots []*quotaTreeNode, resources []string, color bool) error {
+	resourceNames := make([]corev1.ResourceName, 0, len(resources))
+	for _, name := range resources {
+		resourceNames = append(resourceNames, corev1.ResourceName(name))
+	}
+	format := func(resourceList corev1.ResourceList) string {
+		if resourceList == nil {
+			return "<none>"
+		}
+		items := make([]string, 0, len(resourceList))
+		for _, name := range resourceNames {
+			if quantity, ok := resourceList[name]; ok {
+				items = append(items, fmt.Sprintf("%s=%s", name, quantity.String()))
+			}
+		}
+		if len(items) == 0 {
+			return "-"
+		}
+		return strings.Join(items, ",")
+	}
+
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tNAMESPACE\tUSED\tRUNTIME\tMIN\tMAX\tSTATUS")
+	var highlighted []bool
+	var render func(node *quotaTreeNode, prefix, childPrefix string)
+	render = func(node *quotaTreeNode, prefix, childPrefix string) {
+		status := getQuotaStatus(node)
+		highlighted = append(highlighted, status != quotaStatusOK)
+		fmt.Fprintf(w, "%s%s\t%s\t%s\t%s\t%s\t%s\t%s\n", prefix, node.quota.Name, node.quota.Namespace,
+			format(node.quota.Status.Used), format(node.runtime), format(node.quota.Spec.Min), format(node.quota.Spec.Max), status)
+		for i, child := range node.children {
+			if i == len(node.children)-1 {
+				render(child, childPrefix+"└── ", childPrefix+"    ")
+			} else {
+				render(child, childPrefix+"├── ", childPrefix+"│   ")
+			}
+		}
+	}
+	for _, root := range roots {
+		render(root, "", "")
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	// colorize after the alignment, the escape sequences would break the width of the columns
+	lines := strings.SplitAfter(buf.String(), "\n")
+	for i, line := range lines {
+		if color && i > 0 && i-1 < len(highlighted) && highlighted[i-1] {
+			line = ansiRed + strings.TrimSuffix(line, "\n") + ansiReset + "\n"
+		}
+		if _, err := io.WriteString(out, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func useColor(out io.Writer, color string) bool {
+	switch color {
+	case colorAlways:
+		return true
+	case colorNever:
+		return false
+	}
+	f, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
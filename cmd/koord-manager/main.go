@@ -35,14 +35,18 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	schedv1alpha1 "sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
 
 	configv1alpha1 "github.com/koordinator-sh/koordinator/apis/config/v1alpha1"
 	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	"github.com/koordinator-sh/koordinator/cmd/koord-manager/extensions"
 	extclient "github.com/koordinator-sh/koordinator/pkg/client"
+	"github.com/koordinator-sh/koordinator/pkg/controllers/podgroup"
+	"github.com/koordinator-sh/koordinator/pkg/controllers/quotarequest"
 	"github.com/koordinator-sh/koordinator/pkg/features"
 	sloconfig "github.com/koordinator-sh/koordinator/pkg/slo-controller/config"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/configdrift"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/nodemetric"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/nodeslo"
@@ -67,10 +71,12 @@ func init() {
 	_ = configv1alpha1.AddToScheme(clientgoscheme.Scheme)
 	_ = slov1alpha1.AddToScheme(clientgoscheme.Scheme)
 	_ = schedulingv1alpha1.AddToScheme(clientgoscheme.Scheme)
+	_ = schedv1alpha1.AddToScheme(clientgoscheme.Scheme)
 
 	_ = configv1alpha1.AddToScheme(scheme)
 	_ = slov1alpha1.AddToScheme(scheme)
 	_ = schedulingv1alpha1.AddToScheme(scheme)
+	_ = schedv1alpha1.AddToScheme(scheme)
 
 	scheme.AddUnversionedTypes(metav1.SchemeGroupVersion, &metav1.UpdateOptions{}, &metav1.DeleteOptions{}, &metav1.CreateOptions{})
 	// +kubebuilder:scaffold:scheme
@@ -94,6 +100,7 @@ func main() {
 	flag.StringVar(&pprofAddr, "pprof-addr", ":8090", "The address the pprof binds to.")
 	flag.StringVar(&syncPeriodStr, "sync-period", "", "Determines the minimum frequency at which watched resources are reconciled.")
 	sloconfig.InitFlags(flag.CommandLine)
+	podgroup.InitFlags(flag.CommandLine)
 
 	utilfeature.DefaultMutableFeatureGate.AddFlag(pflag.CommandLine)
 	klog.InitFlags(nil)
@@ -187,6 +194,40 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "NodeSLO")
 		os.Exit(1)
 	}
+	if utilfeature.DefaultFeatureGate.Enabled(features.NodeSLORollout) {
+		if err = (&nodeslo.NodeSLORolloutReconciler{
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+			Recorder:  mgr.GetEventRecorderFor("nodeslo-rollout-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "NodeSLORollout")
+			os.Exit(1)
+		}
+	}
+	if utilfeature.DefaultFeatureGate.Enabled(features.ColocationConfigDriftDetection) {
+		if err = (&configdrift.ConfigDriftReconciler{
+			Client:   mgr.GetClient(),
+			Recorder: mgr.GetEventRecorderFor("configdrift-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ConfigDrift")
+			os.Exit(1)
+		}
+	}
+	if utilfeature.DefaultFeatureGate.Enabled(features.PodGroupAutoCreation) {
+		if err = podgroup.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "PodGroup")
+			os.Exit(1)
+		}
+	}
+	if utilfeature.DefaultFeatureGate.Enabled(features.ElasticQuotaRequest) {
+		if err = (&quotarequest.ElasticQuotaRequestReconciler{
+			Client:   mgr.GetClient(),
+			Recorder: mgr.GetEventRecorderFor("elasticquotarequest-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ElasticQuotaRequest")
+			os.Exit(1)
+		}
+	}
 	extensions.PrepareExtensions(cfg, mgr)
 	// +kubebuilder:scaffold:builder
 
@@ -0,0 +1,228 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	checkCRD                 = "CRD"
+	checkNameSchedulerConfig = "SchedulerConfig"
+	checkConflictAgent       = "ConflictingAgent"
+)
+
+// requiredCRDs are the resources of the CRDs koordinator depends on, grouped by the group version.
+var requiredCRDs = map[string][]string{
+	"slo.koordinator.sh/v1alpha1":        {"nodemetrics", "nodeslos"},
+	"scheduling.koordinator.sh/v1alpha1": {"devices", "podmigrationjobs", "reservations"},
+	"config.koordinator.sh/v1alpha1":     {"clustercolocationprofiles"},
+	"scheduling.sigs.k8s.io/v1alpha1":    {"elasticquotas", "podgroups"},
+}
+
+// defaultConflictingAgents are the name keywords of the well-known node agents which also manage the cpusets or
+// the cgroups of the pods, they would overwrite the settings of koordlet.
+var defaultConflictingAgents = []string{"cpu-manager-for-kubernetes", "cmk", "crane-agent", "katalyst-agent"}
+
+type preflightOptions struct {
+	kubeconfig        string
+	output            string
+	schedulerConfig   string
+	conflictingAgents []string
+	namespace         string
+	probeImage        string
+	probeTimeout      time.Duration
+}
+
+// NewPreflightCommand creates the command which validates the prerequisites of the cluster before enabling the
+// colocation, and prints a machine-readable readiness report.
+func NewPreflightCommand() *cobra.Command {
+	opts := &preflightOptions{}
+	cmd := &cobra.Command{
+		Use:   "koord-preflight",
+		Short: "Validate the cluster prerequisites before enabling the colocation",
+		Long: `Validate the cluster prerequisites before enabling the colocation, including the CRDs, the
+koord-scheduler config, the conflicting node agents, and the kernel features of each node probed by a DaemonSet.
+The readiness report is printed in json or yaml, and the command fails if any check fails.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			report, err := runPreflight(context.Background(), opts)
+			if err != nil {
+				return err
+			}
+			if err = writeReport(cmd.OutOrStdout(), report, opts.output); err != nil {
+				return err
+			}
+			if !report.Ready {
+				return fmt.Errorf("preflight checks failed")
+			}
+			return nil
+		},
+	}
+	fs := cmd.Flags()
+	fs.StringVar(&opts.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file, defaults to the KUBECONFIG environment or ~/.kube/config.")
+	fs.StringVarP(&opts.output, "output", "o", "json", "The format of the report, one of json and yaml.")
+	fs.StringVar(&opts.schedulerConfig, "scheduler-config", "", "The file of the koord-scheduler config to validate, skipped if empty.")
+	fs.StringSliceVar(&opts.conflictingAgents, "conflicting-agents", defaultConflictingAgents, "The name keywords of the DaemonSets conflicting with koordlet.")
+	fs.StringVar(&opts.namespace, "namespace", "koordinator-system", "The namespace to run the node probe DaemonSet.")
+	fs.StringVar(&opts.probeImage, "probe-image", "", "The image of koord-preflight to probe the kernel features of the nodes, the node probe is skipped if empty.")
+	fs.DurationVar(&opts.probeTimeout, "probe-timeout", 3*time.Minute, "The timeout to wait for the node probe results.")
+
+	cmd.AddCommand(newProbeCommand())
+	return cmd
+}
+
+func runPreflight(ctx context.Context, opts *preflightOptions) (*Report, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = opts.kubeconfig
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+	report.add(checkCRDs(client.Discovery())...)
+	if opts.schedulerConfig != "" {
+		report.add(checkSchedulerConfigFile(opts.schedulerConfig)...)
+	}
+	report.add(checkConflictingAgents(ctx, client, opts.conflictingAgents)...)
+	if opts.probeImage != "" {
+		report.add(probeNodes(ctx, client, opts.namespace, opts.probeImage, opts.probeTimeout)...)
+	}
+	report.summarize()
+	return report, nil
+}
+
+func checkCRDs(client discovery.DiscoveryInterface) []CheckResult {
+	var results []CheckResult
+	for _, groupVersion := range sortedKeys(requiredCRDs) {
+		served := map[string]bool{}
+		resourceList, err := client.ServerResourcesForGroupVersion(groupVersion)
+		if err != nil && !errors.IsNotFound(err) {
+			results = append(results, failed(checkCRD, groupVersion, fmt.Sprintf("failed to discover, err: %v", err)))
+			continue
+		}
+		if resourceList != nil {
+			for _, r := range resourceList.APIResources {
+				served[r.Name] = true
+			}
+		}
+		for _, resource := range requiredCRDs[groupVersion] {
+			target := resource + "." + strings.Split(groupVersion, "/")[0]
+			if served[resource] {
+				results = append(results, passed(checkCRD, target, ""))
+			} else {
+				results = append(results, failed(checkCRD, target, "CRD is not installed"))
+			}
+		}
+	}
+	return results
+}
+
+// schedulerConfiguration is the part of the KubeSchedulerConfiguration validated by the preflight.
+type schedulerConfiguration struct {
+	Profiles []struct {
+		SchedulerName string `json:"schedulerName"`
+	} `json:"profiles"`
+}
+
+func checkSchedulerConfigFile(path string) []CheckResult {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return []CheckResult{failed(checkNameSchedulerConfig, path, err.Error())}
+	}
+	return checkSchedulerConfig(path, data)
+}
+
+// checkSchedulerConfig rejects the profiles conflicting with each other or with the default kube-scheduler.
+func checkSchedulerConfig(target string, data []byte) []CheckResult {
+	config := &schedulerConfiguration{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return []CheckResult{failed(checkNameSchedulerConfig, target, fmt.Sprintf("failed to parse, err: %v", err))}
+	}
+	if len(config.Profiles) == 0 {
+		return []CheckResult{failed(checkNameSchedulerConfig, target, "no profile is configured")}
+	}
+	var results []CheckResult
+	names := map[string]bool{}
+	for _, profile := range config.Profiles {
+		name := profile.SchedulerName
+		if name == "" {
+			name = "default-scheduler"
+		}
+		switch {
+		case names[name]:
+			results = append(results, failed(checkNameSchedulerConfig, target, fmt.Sprintf("duplicated profile of scheduler %s", name)))
+		case name == "default-scheduler":
+			results = append(results, warned(checkNameSchedulerConfig, target,
+				"profile of default-scheduler conflicts with kube-scheduler unless kube-scheduler is replaced"))
+		}
+		names[name] = true
+	}
+	if len(results) == 0 {
+		results = append(results, passed(checkNameSchedulerConfig, target, ""))
+	}
+	return results
+}
+
+func checkConflictingAgents(ctx context.Context, client kubernetes.Interface, keywords []string) []CheckResult {
+	daemonSets, err := client.AppsV1().DaemonSets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return []CheckResult{failed(checkConflictAgent, "", fmt.Sprintf("failed to list DaemonSets, err: %v", err))}
+	}
+	var results []CheckResult
+	for _, ds := range daemonSets.Items {
+		for _, keyword := range keywords {
+			if keyword != "" && strings.Contains(ds.Name, keyword) {
+				results = append(results, failed(checkConflictAgent, ds.Namespace+"/"+ds.Name,
+					fmt.Sprintf("DaemonSet matching %q may overwrite the cpusets or the cgroups managed by koordlet", keyword)))
+				break
+			}
+		}
+	}
+	if len(results) == 0 {
+		results = append(results, passed(checkConflictAgent, "", ""))
+	}
+	return results
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
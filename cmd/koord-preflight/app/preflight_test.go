@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckSchedulerConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []CheckResult
+	}{
+		{
+			name: "koord-scheduler profile",
+			data: "profiles:\n- schedulerName: koord-scheduler\n",
+			want: []CheckResult{passed(checkNameSchedulerConfig, "config", "")},
+		},
+		{
+			name: "no profile",
+			data: "profiles: []\n",
+			want: []CheckResult{failed(checkNameSchedulerConfig, "config", "no profile is configured")},
+		},
+		{
+			name: "duplicated profiles",
+			data: "profiles:\n- schedulerName: koord-scheduler\n- schedulerName: koord-scheduler\n",
+			want: []CheckResult{failed(checkNameSchedulerConfig, "config", "duplicated profile of scheduler koord-scheduler")},
+		},
+		{
+			name: "profile without scheduler name conflicts with kube-scheduler",
+			data: "profiles:\n- schedulerName: \"\"\n",
+			want: []CheckResult{warned(checkNameSchedulerConfig, "config",
+				"profile of default-scheduler conflicts with kube-scheduler unless kube-scheduler is replaced")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkSchedulerConfig("config", []byte(tt.data))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCheckSchedulerConfigParseError(t *testing.T) {
+	got := checkSchedulerConfig("config", []byte("profiles: ["))
+	assert.Len(t, got, 1)
+	assert.Equal(t, CheckStatusFail, got[0].Status)
+	assert.Contains(t, got[0].Message, "failed to parse")
+}
+
+func TestCheckSchedulerConfigFile(t *testing.T) {
+	got := checkSchedulerConfigFile("/path/not/exist")
+	assert.Len(t, got, 1)
+	assert.Equal(t, CheckStatusFail, got[0].Status)
+	assert.Equal(t, "/path/not/exist", got[0].Target)
+}
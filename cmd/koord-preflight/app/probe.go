@@ -0,0 +1,289 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/util/kubelet"
+	"github.com/koordinator-sh/koordinator/pkg/util/system"
+)
+
+const (
+	probeName = "koord-preflight-probe"
+
+	checkNodeProbe     = "NodeProbe"
+	checkCgroupV2      = "CgroupV2"
+	checkCPUBurst      = "CPUBurst"
+	checkGroupIdentity = "GroupIdentity"
+	checkMemoryQoS     = "MemoryQoS"
+	checkResctrl       = "Resctrl"
+	checkCPUManager    = "KubeletCPUManager"
+
+	hostKubeletRootDir = "/host-var-lib-kubelet/"
+)
+
+// NodeProbeResult is printed by the probe pod as the last line of its log.
+type NodeProbeResult struct {
+	NodeName string        `json:"nodeName"`
+	Checks   []CheckResult `json:"checks"`
+}
+
+func newProbeCommand() *cobra.Command {
+	var waitForever bool
+	cmd := &cobra.Command{
+		Use:    "probe",
+		Short:  "Probe the kernel features of the node, it runs in the probe DaemonSet",
+		Hidden: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			nodeName := os.Getenv("NODE_NAME")
+			result := &NodeProbeResult{NodeName: nodeName, Checks: probeNode(nodeName)}
+			data, err := json.Marshal(result)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(data))
+			if waitForever {
+				// keep the pod running, so the DaemonSet won't restart it
+				select {}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&waitForever, "wait", false, "Keep running after the result is printed.")
+	return cmd
+}
+
+// probeNode checks the kernel features koordlet depends on, the host directories are mounted as the DaemonSet
+// mode of koordlet.
+func probeNode(nodeName string) []CheckResult {
+	var results []CheckResult
+	if system.FileExists(filepath.Join(system.Conf.CgroupRootDir, "cgroup.controllers")) {
+		results = append(results, warned(checkCgroupV2, nodeName, "cgroup v2 is not fully supported by koordlet yet"))
+	} else {
+		results = append(results, passed(checkCgroupV2, nodeName, "cgroup v1"))
+	}
+
+	cgroupFeatures := []struct {
+		name string
+		file system.CgroupFile
+		dir  string
+	}{
+		{name: checkCPUBurst, file: system.CPUBurst, dir: system.Conf.CgroupKubePath},
+		{name: checkGroupIdentity, file: system.CPUBVTWarpNs, dir: system.Conf.CgroupKubePath},
+		{name: checkMemoryQoS, file: system.MemMin, dir: system.Conf.CgroupKubePath},
+	}
+	for _, feature := range cgroupFeatures {
+		filePath := system.GetCgroupFilePath(feature.dir, feature.file)
+		if system.FileExists(filePath) {
+			results = append(results, passed(feature.name, nodeName, ""))
+		} else {
+			results = append(results, warned(feature.name, nodeName, fmt.Sprintf("%s is not supported by the kernel", feature.file.ResourceFileName)))
+		}
+	}
+
+	if supported, err := system.IsSupportResctrl(); err != nil {
+		results = append(results, warned(checkResctrl, nodeName, fmt.Sprintf("failed to check resctrl, err: %v", err)))
+	} else if !supported {
+		results = append(results, warned(checkResctrl, nodeName, "resctrl is not supported, LLC and MBA isolation is disabled"))
+	} else {
+		results = append(results, passed(checkResctrl, nodeName, ""))
+	}
+
+	results = append(results, checkKubeletCPUManager(nodeName, kubelet.GetCPUManagerStateFilePath(hostKubeletRootDir)))
+	return results
+}
+
+// checkKubeletCPUManager warns if the static policy of kubelet cpu manager is enabled, which also manages
+// the cpusets of the pods.
+func checkKubeletCPUManager(nodeName, stateFilePath string) CheckResult {
+	data, err := ioutil.ReadFile(stateFilePath)
+	if os.IsNotExist(err) {
+		return passed(checkCPUManager, nodeName, "cpu manager state is not found")
+	} else if err != nil {
+		return warned(checkCPUManager, nodeName, fmt.Sprintf("failed to read cpu manager state, err: %v", err))
+	}
+	state := struct {
+		PolicyName string `json:"policyName"`
+	}{}
+	if err = json.Unmarshal(data, &state); err != nil {
+		return warned(checkCPUManager, nodeName, fmt.Sprintf("failed to parse cpu manager state, err: %v", err))
+	}
+	if state.PolicyName == "static" {
+		return warned(checkCPUManager, nodeName, "static policy of kubelet cpu manager also manages the cpusets of the pods")
+	}
+	return passed(checkCPUManager, nodeName, "policy "+state.PolicyName)
+}
+
+// probeNodes runs the probe DaemonSet on all nodes, collects the results from the logs of the probe pods and
+// deletes the DaemonSet at last.
+func probeNodes(ctx context.Context, client kubernetes.Interface, namespace, image string, timeout time.Duration) []CheckResult {
+	ds, err := client.AppsV1().DaemonSets(namespace).Create(ctx, newProbeDaemonSet(namespace, image), metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		return []CheckResult{failed(checkNodeProbe, namespace+"/"+probeName, "probe DaemonSet already exists, it may be left by the previous run")}
+	} else if err != nil {
+		return []CheckResult{failed(checkNodeProbe, namespace+"/"+probeName, fmt.Sprintf("failed to create probe DaemonSet, err: %v", err))}
+	}
+	defer func() {
+		propagation := metav1.DeletePropagationBackground
+		if err := client.AppsV1().DaemonSets(namespace).Delete(context.Background(), ds.Name, metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil {
+			klog.Warningf("failed to delete probe DaemonSet %s/%s, err: %v", namespace, ds.Name, err)
+		}
+	}()
+
+	selector := metav1.FormatLabelSelector(ds.Spec.Selector)
+	collected := map[string][]CheckResult{}
+	var pods []corev1.Pod
+	err = wait.PollImmediate(2*time.Second, timeout, func() (bool, error) {
+		current, err := client.AppsV1().DaemonSets(namespace).Get(ctx, ds.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		podList, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return false, nil
+		}
+		pods = podList.Items
+		for i := range pods {
+			pod := &pods[i]
+			if _, ok := collected[pod.Spec.NodeName]; ok || pod.Status.Phase != corev1.PodRunning {
+				continue
+			}
+			if result, err := getProbeResult(ctx, client, pod); err == nil {
+				collected[result.NodeName] = result.Checks
+			}
+		}
+		return current.Status.DesiredNumberScheduled > 0 && len(collected) >= int(current.Status.DesiredNumberScheduled), nil
+	})
+
+	nodeNames := make([]string, 0, len(collected))
+	for nodeName := range collected {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+	var results []CheckResult
+	for _, nodeName := range nodeNames {
+		results = append(results, collected[nodeName]...)
+	}
+	if err != nil {
+		for _, pod := range pods {
+			if _, ok := collected[pod.Spec.NodeName]; !ok {
+				results = append(results, failed(checkNodeProbe, pod.Spec.NodeName,
+					fmt.Sprintf("probe pod %s is not finished in %v, phase %s", pod.Name, timeout, pod.Status.Phase)))
+			}
+		}
+		if len(pods) == 0 {
+			results = append(results, failed(checkNodeProbe, namespace+"/"+probeName, fmt.Sprintf("no probe pod is running in %v", timeout)))
+		}
+	}
+	return results
+}
+
+func getProbeResult(ctx context.Context, client kubernetes.Interface, pod *corev1.Pod) (*NodeProbeResult, error) {
+	data, err := client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{}).DoRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var lastLine string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); strings.HasPrefix(line, "{") {
+			lastLine = line
+		}
+	}
+	if lastLine == "" {
+		return nil, fmt.Errorf("probe result of pod %s is not ready", pod.Name)
+	}
+	result := &NodeProbeResult{}
+	if err = json.Unmarshal([]byte(lastLine), result); err != nil {
+		return nil, err
+	}
+	if result.NodeName == "" {
+		result.NodeName = pod.Spec.NodeName
+	}
+	return result, nil
+}
+
+func newProbeDaemonSet(namespace, image string) *appsv1.DaemonSet {
+	labels := map[string]string{"app": probeName}
+	hostPathVolume := func(name, path string) corev1.Volume {
+		return corev1.Volume{
+			Name:         name,
+			VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: path}},
+		}
+	}
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      probeName,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+					Containers: []corev1.Container{
+						{
+							Name:    "probe",
+							Image:   image,
+							Command: []string{"koord-preflight", "probe", "--wait"},
+							Env: []corev1.EnvVar{
+								{
+									Name: "NODE_NAME",
+									ValueFrom: &corev1.EnvVarSource{
+										FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"},
+									},
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "host-cgroup", MountPath: system.Conf.CgroupRootDir, ReadOnly: true},
+								{Name: "host-sys-fs", MountPath: system.Conf.SysFSRootDir, ReadOnly: true},
+								{Name: "host-var-lib-kubelet", MountPath: hostKubeletRootDir, ReadOnly: true},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						hostPathVolume("host-cgroup", "/sys/fs/cgroup/"),
+						hostPathVolume("host-sys-fs", "/sys/fs/"),
+						hostPathVolume("host-var-lib-kubelet", "/var/lib/kubelet/"),
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"sigs.k8s.io/yaml"
+)
+
+type CheckStatus string
+
+const (
+	CheckStatusPass CheckStatus = "Pass"
+	// CheckStatusWarn means the prerequisite is missing, but only some of the features are affected.
+	CheckStatusWarn CheckStatus = "Warn"
+	// CheckStatusFail means the colocation is not ready to be enabled.
+	CheckStatusFail CheckStatus = "Fail"
+)
+
+// CheckResult is the result of one check of the preflight, Target is the checked object, e.g. the node name.
+type CheckResult struct {
+	Name    string      `json:"name"`
+	Target  string      `json:"target,omitempty"`
+	Status  CheckStatus `json:"status"`
+	Message string      `json:"message,omitempty"`
+}
+
+// Report is the readiness report of the cluster, the colocation is ready to be enabled if none of the checks fails.
+type Report struct {
+	Ready  bool          `json:"ready"`
+	Checks []CheckResult `json:"checks"`
+}
+
+func (r *Report) add(results ...CheckResult) {
+	r.Checks = append(r.Checks, results...)
+}
+
+func (r *Report) summarize() {
+	r.Ready = true
+	for _, check := range r.Checks {
+		if check.Status == CheckStatusFail {
+			r.Ready = false
+			return
+		}
+	}
+}
+
+func writeReport(out io.Writer, report *Report, format string) error {
+	var data []byte
+	var err error
+	switch format {
+	case "json":
+		data, err = json.MarshalIndent(report, "", "  ")
+		data = append(data, '\n')
+	case "yaml":
+		data, err = yaml.Marshal(report)
+	default:
+		return fmt.Errorf("invalid output format %q, expect json or yaml", format)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(data)
+	return err
+}
+
+func passed(name, target, message string) CheckResult {
+	return CheckResult{Name: name, Target: target, Status: CheckStatusPass, Message: message}
+}
+
+func warned(name, target, message string) CheckResult {
+	return CheckResult{Name: name, Target: target, Status: CheckStatusWarn, Message: message}
+}
+
+func failed(name, target, message string) CheckResult {
+	return CheckResult{Name: name, Target: target, Status: CheckStatusFail, Message: message}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportSummarize(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []CheckResult
+		want   bool
+	}{
+		{
+			name: "no check",
+			want: true,
+		},
+		{
+			name:   "warned check",
+			checks: []CheckResult{passed(checkCRD, "", ""), warned(checkCPUBurst, "node-1", "not supported")},
+			want:   true,
+		},
+		{
+			name:   "failed check",
+			checks: []CheckResult{passed(checkCRD, "", ""), failed(checkNodeProbe, "node-1", "timeout")},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := &Report{}
+			report.add(tt.checks...)
+			report.summarize()
+			assert.Equal(t, tt.want, report.Ready)
+			assert.Equal(t, tt.checks, report.Checks)
+		})
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	report := &Report{Ready: false, Checks: []CheckResult{failed(checkNodeProbe, "node-1", "timeout")}}
+	tests := []struct {
+		name    string
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "json",
+			format: "json",
+			want: `{
+  "ready": false,
+  "checks": [
+    {
+      "name": "NodeProbe",
+      "target": "node-1",
+      "status": "Fail",
+      "message": "timeout"
+    }
+  ]
+}
+`,
+		},
+		{
+			name:   "yaml",
+			format: "yaml",
+			want: `checks:
+- message: timeout
+  name: NodeProbe
+  status: Fail
+  target: node-1
+ready: false
+`,
+		},
+		{
+			name:    "invalid format",
+			format:  "xml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := writeReport(out, report, tt.format)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
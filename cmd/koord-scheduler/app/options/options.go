@@ -19,24 +19,36 @@ package options
 import (
 	"github.com/gin-gonic/gin"
 	"k8s.io/apimachinery/pkg/runtime"
+	cliflag "k8s.io/component-base/cli/flag"
 	scheduleroptions "k8s.io/kubernetes/cmd/kube-scheduler/app/options"
 
+	"github.com/koordinator-sh/koordinator/apis/extension"
 	schedulerappconfig "github.com/koordinator-sh/koordinator/cmd/koord-scheduler/app/config"
 	koordinatorclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
 	koordinatorinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/services"
 )
 
 // Options has all the params needed to run a Scheduler
 type Options struct {
 	*scheduleroptions.Options
+	// FlowControl limits the requests of each client to the services
+	FlowControl *services.FlowControlOptions
+	// ResourceNameAliases maps the vendor-specific resource names onto the canonical device resource names
+	ResourceNameAliases map[string]string
 }
 
 // NewOptions returns default scheduler app options.
 func NewOptions() *Options {
-	return &Options{
-		Options: scheduleroptions.NewOptions(),
+	o := &Options{
+		Options:     scheduleroptions.NewOptions(),
+		FlowControl: services.NewDefaultFlowControlOptions(),
 	}
+	o.FlowControl.AddFlags(o.Flags.FlagSet("services"))
+	o.Flags.FlagSet("misc").Var(cliflag.NewMapStringString(&o.ResourceNameAliases), "resource-name-aliases", "A set of alias=name pairs "+
+		"that map the vendor-specific resource names onto the canonical device resource names, e.g. amd.com/gpu=nvidia.com/gpu.")
+	return o
 }
 
 // Config return a scheduler config object
@@ -45,6 +57,9 @@ func (o *Options) Config() (*schedulerappconfig.Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := extension.SetResourceNameAliases(o.ResourceNameAliases); err != nil {
+		return nil, err
+	}
 
 	// use json for CRD clients
 	kubeConfig := *config.KubeConfig
@@ -55,11 +70,19 @@ func (o *Options) Config() (*schedulerappconfig.Config, error) {
 		return nil, err
 	}
 	koordinatorSharedInformerFactory := koordinatorinformers.NewSharedInformerFactoryWithOptions(koordinatorClient, 0)
+	// the assume state is exported next to the leader election lock, so that the incoming leader can find it
+	leaderElection := config.ComponentConfig.LeaderElection
+	assumeStateManager := frameworkext.NewAssumeStateManager(config.Client, leaderElection.ResourceNamespace, leaderElection.ResourceName+"-assume-state")
+
+	// the flow control is applied before registering any service
+	engine := gin.Default()
+	engine.Use(services.NewFlowController(o.FlowControl).Handler())
 
 	return &schedulerappconfig.Config{
 		Config:                           config,
-		ServicesEngine:                   services.NewEngine(gin.Default()),
+		ServicesEngine:                   services.NewEngine(engine),
 		KoordinatorClient:                koordinatorClient,
 		KoordinatorSharedInformerFactory: koordinatorSharedInformerFactory,
+		AssumeStateManager:               assumeStateManager,
 	}, nil
 }
@@ -61,6 +61,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/eventhandlers"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/services"
+	koordschedulermetrics "github.com/koordinator-sh/koordinator/pkg/scheduler/metrics"
 )
 
 // Option configures a framework.Registry.
@@ -107,6 +108,8 @@ for cost reduction and efficiency enhancement.
 	cliflag.SetUsageAndHelpFunc(cmd, *nfs, cols)
 
 	cmd.MarkFlagFilename("config", "yaml", "yml", "json")
+	cmd.AddCommand(newSimulateQuotaCommand())
+	cmd.AddCommand(newValidateConfigCommand(registryOptions))
 
 	return cmd
 }
@@ -144,6 +147,8 @@ func Run(ctx context.Context, cc *schedulerserverconfig.CompletedConfig, sched *
 		return fmt.Errorf("unable to register configz: %s", err)
 	}
 
+	koordschedulermetrics.Register()
+
 	// Prepare the event broadcaster.
 	cc.EventBroadcaster.StartRecordingToSink(ctx.Done())
 
@@ -201,6 +206,7 @@ func Run(ctx context.Context, cc *schedulerserverconfig.CompletedConfig, sched *
 		cc.LeaderElection.Callbacks = leaderelection.LeaderCallbacks{
 			OnStartedLeading: func(ctx context.Context) {
 				close(waitingForLeader)
+				runAssumeStateManager(ctx, cc)
 				sched.Run(ctx)
 			},
 			OnStoppedLeading: func() {
@@ -227,10 +233,26 @@ func Run(ctx context.Context, cc *schedulerserverconfig.CompletedConfig, sched *
 
 	// Leader election is disabled, so runCommand inline until done.
 	close(waitingForLeader)
+	runAssumeStateManager(ctx, cc)
 	sched.Run(ctx)
 	return fmt.Errorf("finished without leader elect")
 }
 
+// runAssumeStateManager restores the pods assumed by the previous leader and resyncs the cached states of the plugins
+// before scheduling, and exports the assumed pods while leading, so that the handoff during rolling restarts won't
+// double-allocate resources.
+func runAssumeStateManager(ctx context.Context, cc *schedulerserverconfig.CompletedConfig) {
+	if cc.AssumeStateManager == nil {
+		return
+	}
+	podLister := cc.InformerFactory.Core().V1().Pods().Lister()
+	if err := cc.AssumeStateManager.Restore(ctx, podLister); err != nil {
+		klog.Errorf("failed to restore assume state, err: %v", err)
+	}
+	cc.AssumeStateManager.Resync(ctx)
+	go cc.AssumeStateManager.Run(ctx, podLister)
+}
+
 // buildHandlerChain wraps the given handler with the standard filters.
 func buildHandlerChain(handler http.Handler, authn authenticator.Request, authz authorizer.Authorizer) http.Handler {
 	requestInfoResolver := &apirequest.RequestInfoFactory{}
@@ -331,8 +353,10 @@ func Setup(ctx context.Context, opts *options.Options, schedulingHooks []framewo
 	// Currently, only by copying the initialization code and implementing custom initialization.
 	extendedHandle := frameworkext.NewExtendedHandle(
 		frameworkext.WithServicesEngine(cc.ServicesEngine),
+		frameworkext.WithAssumeStateManager(cc.AssumeStateManager),
 		frameworkext.WithKoordinatorClientSet(cc.KoordinatorClient),
 		frameworkext.WithKoordinatorSharedInformerFactory(cc.KoordinatorSharedInformerFactory),
+		frameworkext.WithStopCh(ctx.Done()),
 	)
 
 	outOfTreeRegistry := make(runtime.Registry)
@@ -380,6 +404,10 @@ func Setup(ctx context.Context, opts *options.Options, schedulingHooks []framewo
 		sched.Profiles[k] = extendedFrameworkFactory.New(v)
 	}
 
+	if cc.AssumeStateManager != nil {
+		cc.AssumeStateManager.SetScheduler(sched)
+	}
+
 	schedulerInternalHandler := &eventhandlers.SchedulerInternalHandlerImpl{
 		Scheduler: sched,
 	}
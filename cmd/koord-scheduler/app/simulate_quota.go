@@ -0,0 +1,147 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/elasticquota/core"
+)
+
+type simulateQuotaOptions struct {
+	quotasFile    string
+	proposedFile  string
+	totalResource string
+	delete        bool
+}
+
+// newSimulateQuotaCommand creates the command to preview the runtime of all the quota groups after a proposed
+// change of an ElasticQuota, it never touches the cluster.
+func newSimulateQuotaCommand() *cobra.Command {
+	opts := &simulateQuotaOptions{}
+	cmd := &cobra.Command{
+		Use:   "simulate-quota",
+		Short: "Preview the runtime of the quota groups after a proposed change of an ElasticQuota",
+		Long: `Preview the runtime of the quota groups after a proposed change of an ElasticQuota.
+The current quota tree is read from the ElasticQuotas exported by "kubectl get elasticquotas -A -o yaml",
+the request of each quota group is restored from the annotation published by the scheduler.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSimulateQuota(cmd.OutOrStdout(), opts)
+		},
+	}
+	fs := cmd.Flags()
+	fs.StringVar(&opts.quotasFile, "quotas", "", "The file of the current ElasticQuotas.")
+	fs.StringVar(&opts.proposedFile, "proposed", "", "The file of the proposed ElasticQuota.")
+	fs.StringVar(&opts.totalResource, "total-resource", "", "The total resource of the cluster, e.g. cpu=100,memory=1000Gi.")
+	fs.BoolVar(&opts.delete, "delete", false, "Simulate the deletion of the proposed ElasticQuota instead of the update.")
+	cmd.MarkFlagRequired("quotas")
+	cmd.MarkFlagRequired("proposed")
+	cmd.MarkFlagRequired("total-resource")
+	return cmd
+}
+
+func runSimulateQuota(out io.Writer, opts *simulateQuotaOptions) error {
+	totalResource, err := parseResourceList(opts.totalResource)
+	if err != nil {
+		return err
+	}
+	quotaList := &v1alpha1.ElasticQuotaList{}
+	if err := readYAMLFile(opts.quotasFile, quotaList); err != nil {
+		return err
+	}
+	proposed := &v1alpha1.ElasticQuota{}
+	if err := readYAMLFile(opts.proposedFile, proposed); err != nil {
+		return err
+	}
+
+	quotas := make([]*v1alpha1.ElasticQuota, 0, len(quotaList.Items))
+	for i := range quotaList.Items {
+		quotas = append(quotas, &quotaList.Items[i])
+	}
+	gqm, err := core.NewGroupQuotaManagerFromQuotas(quotas, totalResource)
+	if err != nil {
+		return err
+	}
+	changes, err := gqm.SimulateQuotaUpdate(proposed, opts.delete)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCURRENT\tSIMULATED\tCHANGED")
+	for _, change := range changes {
+		changed := change.Current == nil || change.Simulated == nil || !quotav1.Equals(change.Current, change.Simulated)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%v\n", change.Name, formatResourceList(change.Current), formatResourceList(change.Simulated), changed)
+	}
+	return w.Flush()
+}
+
+func readYAMLFile(path string, obj interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(data, obj); err != nil {
+		return fmt.Errorf("failed to parse %s, err: %v", path, err)
+	}
+	return nil
+}
+
+// parseResourceList parses the resource list in the form of name=quantity separated by comma.
+func parseResourceList(s string) (corev1.ResourceList, error) {
+	resourceList := corev1.ResourceList{}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid resource %q, expect name=quantity", item)
+		}
+		quantity, err := resource.ParseQuantity(kv[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity of resource %q, err: %v", kv[0], err)
+		}
+		resourceList[corev1.ResourceName(kv[0])] = quantity
+	}
+	return resourceList, nil
+}
+
+func formatResourceList(resourceList corev1.ResourceList) string {
+	if resourceList == nil {
+		return "<none>"
+	}
+	items := make([]string, 0, len(resourceList))
+	for name, quantity := range resourceList {
+		items = append(items, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+	sort.Strings(items)
+	return strings.Join(items, ",")
+}
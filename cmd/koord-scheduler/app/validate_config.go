@@ -0,0 +1,473 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/component-base/featuregate"
+	kubeschedulerconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	kubeschedulervalidation "k8s.io/kubernetes/pkg/scheduler/apis/config/validation"
+	frameworkplugins "k8s.io/kubernetes/pkg/scheduler/framework/plugins"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+	koordschedulerscheme "github.com/koordinator-sh/koordinator/apis/scheduling/config/scheme"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config/validation"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/coscheduling"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/elasticquota"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/elasticquota/core"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/loadaware"
+	sloconfig "github.com/koordinator-sh/koordinator/pkg/slo-controller/config"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/configdrift"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+const (
+	findingError   = "Error"
+	findingWarning = "Warning"
+)
+
+type validateConfigOptions struct {
+	configFile           string
+	colocationConfigFile string
+	managerFeatureGates  string
+	nodesFile            string
+	nodeMetricsFile      string
+}
+
+type configFinding struct {
+	severity string
+	profile  string
+	message  string
+}
+
+type configFindings []configFinding
+
+func (f *configFindings) add(severity, profile, format string, args ...interface{}) {
+	*f = append(*f, configFinding{severity: severity, profile: profile, message: fmt.Sprintf(format, args...)})
+}
+
+// newValidateConfigCommand creates the command to check a koord-scheduler configuration before the deployment. Besides
+// the validation of koord-scheduler itself, it checks the consistency across the plugins and the other components,
+// and optionally simulates the LoadAwareScheduling filter on the exported nodes. It never touches the cluster.
+func newValidateConfigCommand(registryOptions []Option) *cobra.Command {
+	opts := &validateConfigOptions{}
+	cmd := &cobra.Command{
+		Use:   "validate-config",
+		Short: "Validate the koord-scheduler configuration and the consistency with the other components",
+		Long: `Validate the koord-scheduler configuration and the consistency with the other components.
+The profiles and the plugin args are validated as koord-scheduler does at startup, then checked against each other,
+against the colocation config of slo-controller and against the feature gates of koord-manager if provided.
+If the nodes exported by "kubectl get nodes -o yaml" are provided, the LoadAwareScheduling filter of each profile
+is simulated on them with the NodeMetrics exported by "kubectl get nodemetrics -o yaml".
+It exits with error if any finding is an error.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runValidateConfig(cmd.OutOrStdout(), opts, registryOptions)
+		},
+	}
+	fs := cmd.Flags()
+	fs.StringVar(&opts.configFile, "config", "", "The file of the KubeSchedulerConfiguration to validate.")
+	fs.StringVar(&opts.colocationConfigFile, "colocation-config", "", "The file of the slo-controller ConfigMap containing the colocation config.")
+	fs.StringVar(&opts.managerFeatureGates, "manager-feature-gates", "", "The --feature-gates of koord-manager, e.g. ElasticQuotaValidatingWebhook=true.")
+	fs.StringVar(&opts.nodesFile, "nodes", "", "The file of the nodes to simulate the LoadAwareScheduling filter on.")
+	fs.StringVar(&opts.nodeMetricsFile, "node-metrics", "", "The file of the NodeMetrics of the nodes.")
+	cmd.MarkFlagRequired("config")
+	return cmd
+}
+
+func runValidateConfig(out io.Writer, opts *validateConfigOptions, registryOptions []Option) error {
+	data, err := ioutil.ReadFile(opts.configFile)
+	if err != nil {
+		return err
+	}
+	obj, gvk, err := koordschedulerscheme.Codecs.UniversalDecoder().Decode(data, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to decode %s, err: %v", opts.configFile, err)
+	}
+	cfg, ok := obj.(*kubeschedulerconfig.KubeSchedulerConfiguration)
+	if !ok {
+		return fmt.Errorf("%s is not a KubeSchedulerConfiguration, got %v", opts.configFile, gvk)
+	}
+
+	var findings configFindings
+	if err := kubeschedulervalidation.ValidateKubeSchedulerConfiguration(cfg); err != nil {
+		findings.add(findingError, "", "%v", err)
+	}
+	registered, err := getRegisteredPlugins(registryOptions)
+	if err != nil {
+		return err
+	}
+	for i := range cfg.Profiles {
+		validateProfile(&findings, &cfg.Profiles[i], registered)
+	}
+
+	if opts.colocationConfigFile != "" {
+		colocationCfg, err := loadColocationConfig(opts.colocationConfigFile)
+		if err != nil {
+			return err
+		}
+		validateColocationConsistency(&findings, cfg, string(data), colocationCfg)
+	}
+	if opts.managerFeatureGates != "" {
+		featureGate := utilfeature.DefaultMutableFeatureGate.DeepCopy()
+		if err := featureGate.Set(opts.managerFeatureGates); err != nil {
+			return fmt.Errorf("invalid manager feature gates, err: %v", err)
+		}
+		validateManagerFeatureGates(&findings, cfg, featureGate.Enabled)
+	}
+	if opts.nodesFile != "" {
+		nodeList := &corev1.NodeList{}
+		if err := readYAMLFile(opts.nodesFile, nodeList); err != nil {
+			return err
+		}
+		nodeMetricList := &slov1alpha1.NodeMetricList{}
+		if opts.nodeMetricsFile != "" {
+			if err := readYAMLFile(opts.nodeMetricsFile, nodeMetricList); err != nil {
+				return err
+			}
+		}
+		simulateLoadAwareFilter(&findings, cfg, nodeList.Items, nodeMetricList.Items)
+	}
+
+	return renderConfigFindings(out, findings)
+}
+
+// getRegisteredPlugins returns the names of the in-tree plugins and the plugins registered by koord-scheduler.
+func getRegisteredPlugins(registryOptions []Option) (map[string]bool, error) {
+	outOfTreeRegistry := make(frameworkruntime.Registry)
+	for _, option := range registryOptions {
+		// the handle is only used when the plugins are instantiated
+		if err := option(nil, outOfTreeRegistry); err != nil {
+			return nil, err
+		}
+	}
+	registered := map[string]bool{}
+	for name := range frameworkplugins.NewInTreeRegistry() {
+		registered[name] = true
+	}
+	for name := range outOfTreeRegistry {
+		registered[name] = true
+	}
+	return registered, nil
+}
+
+func getExtensionPoints(plugins *kubeschedulerconfig.Plugins) map[string]kubeschedulerconfig.PluginSet {
+	if plugins == nil {
+		return nil
+	}
+	return map[string]kubeschedulerconfig.PluginSet{
+		"queueSort":  plugins.QueueSort,
+		"preFilter":  plugins.PreFilter,
+		"filter":     plugins.Filter,
+		"postFilter": plugins.PostFilter,
+		"preScore":   plugins.PreScore,
+		"score":      plugins.Score,
+		"reserve":    plugins.Reserve,
+		"permit":     plugins.Permit,
+		"preBind":    plugins.PreBind,
+		"bind":       plugins.Bind,
+		"postBind":   plugins.PostBind,
+	}
+}
+
+func isPluginEnabled(pluginSet kubeschedulerconfig.PluginSet, name string) bool {
+	for _, plugin := range pluginSet.Enabled {
+		if plugin.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func getPluginArgs(profile *kubeschedulerconfig.KubeSchedulerProfile, name string) runtime.Object {
+	for _, pluginConfig := range profile.PluginConfig {
+		if pluginConfig.Name == name {
+			return pluginConfig.Args
+		}
+	}
+	return nil
+}
+
+func validateProfile(findings *configFindings, profile *kubeschedulerconfig.KubeSchedulerProfile, registered map[string]bool) {
+	name := profile.SchedulerName
+	extensionPoints := getExtensionPoints(profile.Plugins)
+	var extensionPointNames []string
+	for extensionPoint := range extensionPoints {
+		extensionPointNames = append(extensionPointNames, extensionPoint)
+	}
+	sort.Strings(extensionPointNames)
+	for _, extensionPoint := range extensionPointNames {
+		for _, plugin := range extensionPoints[extensionPoint].Enabled {
+			if !registered[plugin.Name] {
+				findings.add(findingError, name, "plugin %s enabled at %s is not registered in koord-scheduler", plugin.Name, extensionPoint)
+			}
+		}
+	}
+
+	for _, pluginConfig := range profile.PluginConfig {
+		var err error
+		switch args := pluginConfig.Args.(type) {
+		case *config.LoadAwareSchedulingArgs:
+			err = validation.ValidateLoadAwareSchedulingArgs(args)
+		case *config.CoschedulingArgs:
+			err = validation.ValidateCoschedulingArgs(args)
+		case *config.DeviceShareArgs:
+			err = validation.ValidateDeviceShareArgs(args)
+		case *config.ElasticQuotaArgs:
+			err = validation.ValidateElasticQuotaArgs(args)
+			if err == nil && args.RuntimeCalculateStrategy != "" {
+				_, err = core.NewRuntimeCalculateStrategy(string(args.RuntimeCalculateStrategy))
+			}
+		}
+		if err != nil {
+			findings.add(findingError, name, "invalid args of %s: %v", pluginConfig.Name, err)
+		}
+	}
+
+	if profile.Plugins == nil {
+		return
+	}
+	// the gangs are only assembled in time if their children are dequeued together
+	if isPluginEnabled(profile.Plugins.Permit, coscheduling.Name) && !isPluginEnabled(profile.Plugins.QueueSort, coscheduling.Name) {
+		findings.add(findingWarning, name, "%s is enabled at permit but not at queueSort, the children of the gangs are "+
+			"interleaved with the other pods and the gangs may time out", coscheduling.Name)
+	}
+	if isPluginEnabled(profile.Plugins.PreFilter, coscheduling.Name) && !isPluginEnabled(profile.Plugins.Permit, coscheduling.Name) {
+		findings.add(findingError, name, "%s is enabled at preFilter but not at permit, the children of the gangs are "+
+			"bound before the minMember is assembled", coscheduling.Name)
+	}
+	if quotaArgs, ok := getPluginArgs(profile, elasticquota.Name).(*config.ElasticQuotaArgs); ok &&
+		quotaArgs.PriorityAging != nil && !isPluginEnabled(profile.Plugins.QueueSort, elasticquota.Name) {
+		findings.add(findingWarning, name, "priorityAging of %s is configured but %s is not enabled at queueSort, "+
+			"the pods pending for the quota are not aged", elasticquota.Name, elasticquota.Name)
+	}
+	if loadAwareArgs, ok := getPluginArgs(profile, loadaware.Name).(*config.LoadAwareSchedulingArgs); ok &&
+		isPluginEnabled(profile.Plugins.Filter, loadaware.Name) &&
+		loadAwareArgs.FilterExpiredNodeMetrics != nil && *loadAwareArgs.FilterExpiredNodeMetrics &&
+		loadAwareArgs.NodeMetricExpirationSeconds != nil &&
+		time.Duration(*loadAwareArgs.NodeMetricExpirationSeconds)*time.Second <= loadaware.DefaultNodeMetricReportInterval {
+		findings.add(findingWarning, name, "nodeMetricExpirationSeconds %d of %s is not longer than the default report "+
+			"interval %v of the NodeMetrics, the nodes are filtered between the reports", *loadAwareArgs.NodeMetricExpirationSeconds,
+			loadaware.Name, loadaware.DefaultNodeMetricReportInterval)
+	}
+}
+
+func loadColocationConfig(path string) (*sloconfig.ColocationCfg, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := readYAMLFile(path, configMap); err != nil {
+		return nil, err
+	}
+	colocationCfg := sloconfig.NewDefaultColocationCfg()
+	configStr := configMap.Data[sloconfig.ColocationConfigKey]
+	if configStr == "" {
+		return colocationCfg, nil
+	}
+	newCfg := &sloconfig.ColocationCfg{}
+	if err := json.Unmarshal([]byte(configStr), newCfg); err != nil {
+		return nil, fmt.Errorf("failed to parse the colocation config of %s, err: %v", path, err)
+	}
+	// merge the default cluster strategy as slo-controller does
+	merged, err := util.MergeCfg(colocationCfg.ColocationStrategy.DeepCopy(), &newCfg.ColocationStrategy)
+	if err != nil {
+		return nil, err
+	}
+	newCfg.ColocationStrategy = *merged.(*sloconfig.ColocationStrategy)
+	if !sloconfig.IsColocationStrategyValid(&newCfg.ColocationStrategy) {
+		return nil, fmt.Errorf("invalid colocation strategy in %s", path)
+	}
+	return newCfg, nil
+}
+
+// validateColocationConsistency checks the scheduler config against the colocation config, which decides how much
+// batch resource the nodes report.
+func validateColocationConsistency(findings *configFindings, cfg *kubeschedulerconfig.KubeSchedulerConfiguration, data string, colocationCfg *sloconfig.ColocationCfg) {
+	summary, err := configdrift.ParseSchedulerConfig(data)
+	if err == nil {
+		for _, drift := range configdrift.CheckColocationDrift(colocationCfg, summary) {
+			findings.add(findingError, "", "%s", drift)
+		}
+	}
+
+	if colocationCfg.Enable == nil || !*colocationCfg.Enable {
+		return
+	}
+	reclaimThresholds := map[corev1.ResourceName]*int64{
+		corev1.ResourceCPU:    colocationCfg.CPUReclaimThresholdPercent,
+		corev1.ResourceMemory: colocationCfg.MemoryReclaimThresholdPercent,
+	}
+	for i := range cfg.Profiles {
+		profile := &cfg.Profiles[i]
+		if profile.Plugins == nil || !isPluginEnabled(profile.Plugins.Filter, loadaware.Name) {
+			continue
+		}
+		args, ok := getPluginArgs(profile, loadaware.Name).(*config.LoadAwareSchedulingArgs)
+		if !ok {
+			continue
+		}
+		for _, resourceName := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			threshold := args.UsageThresholds[resourceName]
+			reclaimThreshold := reclaimThresholds[resourceName]
+			if threshold > 0 && reclaimThreshold != nil && threshold < *reclaimThreshold {
+				findings.add(findingWarning, profile.SchedulerName, "usage threshold %d%% of %s of %s is lower than the reclaim "+
+					"threshold %d%% of the colocation, the batch resource reported by the nodes can't be fully used",
+					threshold, resourceName, loadaware.Name, *reclaimThreshold)
+			}
+		}
+	}
+}
+
+// validateManagerFeatureGates checks the features of koord-manager which the scheduling depends on.
+func validateManagerFeatureGates(findings *configFindings, cfg *kubeschedulerconfig.KubeSchedulerConfiguration, enabled func(feature featuregate.Feature) bool) {
+	if enabled(features.ElasticQuotaNamespaceBinding) && !enabled(features.PodMutatingWebhook) {
+		findings.add(findingError, "", "%s takes no effect since %s is disabled", features.ElasticQuotaNamespaceBinding, features.PodMutatingWebhook)
+	}
+	if enabled(features.PodGroupAutoCreation) && !enabled(features.PodMutatingWebhook) {
+		findings.add(findingError, "", "%s takes no effect on the pods since %s is disabled", features.PodGroupAutoCreation, features.PodMutatingWebhook)
+	}
+	if enabled(features.ElasticQuotaResizeValidation) && !enabled(features.PodValidatingWebhook) {
+		findings.add(findingError, "", "%s takes no effect since %s is disabled", features.ElasticQuotaResizeValidation, features.PodValidatingWebhook)
+	}
+
+	for i := range cfg.Profiles {
+		profile := &cfg.Profiles[i]
+		for _, pluginConfig := range profile.PluginConfig {
+			if _, ok := pluginConfig.Args.(*config.ElasticQuotaArgs); !ok {
+				continue
+			}
+			if !enabled(features.ElasticQuotaValidatingWebhook) {
+				findings.add(findingWarning, profile.SchedulerName, "the elastic quotas are used but %s is disabled, the quotas "+
+					"breaking the invariants of the quota tree are admitted", features.ElasticQuotaValidatingWebhook)
+			}
+		}
+	}
+}
+
+// simulateLoadAwareFilter runs the usage thresholds and the expiration of the LoadAwareScheduling filter of each profile
+// on the nodes, and flags the profiles filtering most of the nodes.
+func simulateLoadAwareFilter(findings *configFindings, cfg *kubeschedulerconfig.KubeSchedulerConfiguration, nodes []corev1.Node, nodeMetrics []slov1alpha1.NodeMetric) {
+	if len(nodes) == 0 {
+		return
+	}
+	nodeMetricMap := make(map[string]*slov1alpha1.NodeMetric, len(nodeMetrics))
+	for i := range nodeMetrics {
+		nodeMetricMap[nodeMetrics[i].Name] = &nodeMetrics[i]
+	}
+	for i := range cfg.Profiles {
+		profile := &cfg.Profiles[i]
+		if profile.Plugins == nil || !isPluginEnabled(profile.Plugins.Filter, loadaware.Name) {
+			continue
+		}
+		args, ok := getPluginArgs(profile, loadaware.Name).(*config.LoadAwareSchedulingArgs)
+		if !ok {
+			continue
+		}
+		filtered := 0
+		for j := range nodes {
+			if isFilteredByLoadAware(args, &nodes[j], nodeMetricMap[nodes[j].Name]) {
+				filtered++
+			}
+		}
+		if filtered == len(nodes) {
+			findings.add(findingError, profile.SchedulerName, "%s filters all the %d nodes", loadaware.Name, len(nodes))
+		} else if filtered*2 > len(nodes) {
+			findings.add(findingWarning, profile.SchedulerName, "%s filters %d of the %d nodes", loadaware.Name, filtered, len(nodes))
+		}
+	}
+}
+
+// isFilteredByLoadAware is the offline version of the filter of LoadAwareScheduling, the node metric is considered
+// expired if it is reported less often than the expiration.
+func isFilteredByLoadAware(args *config.LoadAwareSchedulingArgs, node *corev1.Node, nodeMetric *slov1alpha1.NodeMetric) bool {
+	if nodeMetric == nil {
+		return false
+	}
+	if args.FilterExpiredNodeMetrics != nil && *args.FilterExpiredNodeMetrics && args.NodeMetricExpirationSeconds != nil {
+		reportInterval := loadaware.DefaultNodeMetricReportInterval
+		if nodeMetric.Spec.CollectPolicy != nil && nodeMetric.Spec.CollectPolicy.ReportIntervalSeconds != nil {
+			reportInterval = time.Duration(*nodeMetric.Spec.CollectPolicy.ReportIntervalSeconds) * time.Second
+		}
+		if nodeMetric.Status.UpdateTime == nil || time.Duration(*args.NodeMetricExpirationSeconds)*time.Second <= reportInterval {
+			return true
+		}
+	}
+	if nodeMetric.Status.NodeMetric == nil {
+		return false
+	}
+
+	usageThresholds := args.UsageThresholds
+	if customUsageThresholds, err := extension.GetCustomUsageThresholds(node); err == nil && len(customUsageThresholds.UsageThresholds) > 0 {
+		usageThresholds = customUsageThresholds.UsageThresholds
+	}
+	for resourceName, threshold := range usageThresholds {
+		// the pods on the nodes are not exported, skip the pod density
+		if threshold == 0 || resourceName == corev1.ResourcePods {
+			continue
+		}
+		total := node.Status.Allocatable[resourceName]
+		if total.IsZero() {
+			continue
+		}
+		used := nodeMetric.Status.NodeMetric.NodeUsage.ResourceList[resourceName]
+		usage := int64(math.Round(float64(used.MilliValue()) / float64(total.MilliValue()) * 100))
+		if usage >= threshold {
+			return true
+		}
+	}
+	return false
+}
+
+func renderConfigFindings(out io.Writer, findings configFindings) error {
+	if len(findings) == 0 {
+		_, err := fmt.Fprintln(out, "no problem found")
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "SEVERITY\tPROFILE\tMESSAGE")
+	errorCount := 0
+	for _, finding := range findings {
+		if finding.severity == findingError {
+			errorCount++
+		}
+		profile := finding.profile
+		if profile == "" {
+			profile = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", finding.severity, profile, finding.message)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if errorCount > 0 {
+		return fmt.Errorf("found %d errors in the configuration", errorCount)
+	}
+	return nil
+}
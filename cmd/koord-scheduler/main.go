@@ -26,15 +26,19 @@ import (
 	"github.com/koordinator-sh/koordinator/cmd/koord-scheduler/app"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/batchresource"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/cachegroup"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/compatibledefaultpreemption"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/coscheduling"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/deviceshare"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/elasticquota"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/loadaware"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/nodenumaresource"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/reservation"
 
 	// Ensure scheme package is initialized.
 	_ "github.com/koordinator-sh/koordinator/apis/scheduling/config/scheme"
+	// Ensure the feature gates of koord-scheduler are registered.
+	_ "github.com/koordinator-sh/koordinator/pkg/features"
 )
 
 func main() {
@@ -58,6 +62,8 @@ func main() {
 		app.WithPlugin(batchresource.Name, batchresource.New),
 		app.WithPlugin(coscheduling.Name, coscheduling.New),
 		app.WithPlugin(deviceshare.Name, deviceshare.New),
+		app.WithPlugin(cachegroup.Name, cachegroup.New),
+		app.WithPlugin(elasticquota.Name, elasticquota.New),
 	)
 
 	logs.InitLogs()
@@ -28,6 +28,7 @@ import (
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/cmd/koordlet/options"
 	"github.com/koordinator-sh/koordinator/pkg/features"
 	agent "github.com/koordinator-sh/koordinator/pkg/koordlet"
@@ -52,6 +53,9 @@ func main() {
 	if err := runtimehooks.DefaultMutableRuntimeHooksFG.SetFromMap(cfg.RuntimeHookConf.FeatureGates); err != nil {
 		klog.Fatalf("Unable to setup runtime-hooks: %v", err)
 	}
+	if err := apiext.SetResourceNameAliases(cfg.ResourceNameAliases); err != nil {
+		klog.Fatalf("Unable to setup resource-name-aliases: %v", err)
+	}
 
 	stopCtx := signals.SetupSignalHandler()
 
@@ -27,6 +27,7 @@ import (
 type SchedulingV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	DevicesGetter
+	ElasticQuotaRequestsGetter
 	PodMigrationJobsGetter
 	ReservationsGetter
 }
@@ -40,6 +41,10 @@ func (c *SchedulingV1alpha1Client) Devices() DeviceInterface {
 	return newDevices(c)
 }
 
+func (c *SchedulingV1alpha1Client) ElasticQuotaRequests(namespace string) ElasticQuotaRequestInterface {
+	return newElasticQuotaRequests(c, namespace)
+}
+
 func (c *SchedulingV1alpha1Client) PodMigrationJobs() PodMigrationJobInterface {
 	return newPodMigrationJobs(c)
 }
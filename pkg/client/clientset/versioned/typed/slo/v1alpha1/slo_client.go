@@ -26,6 +26,7 @@ import (
 
 type SloV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	CacheGroupsGetter
 	NodeMetricsGetter
 	NodeSLOsGetter
 }
@@ -35,6 +36,10 @@ type SloV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *SloV1alpha1Client) CacheGroups(namespace string) CacheGroupInterface {
+	return newCacheGroups(c, namespace)
+}
+
 func (c *SloV1alpha1Client) NodeMetrics() NodeMetricInterface {
 	return newNodeMetrics(c)
 }
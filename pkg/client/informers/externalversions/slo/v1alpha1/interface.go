@@ -24,6 +24,8 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// CacheGroups returns a CacheGroupInformer.
+	CacheGroups() CacheGroupInformer
 	// NodeMetrics returns a NodeMetricInformer.
 	NodeMetrics() NodeMetricInformer
 	// NodeSLOs returns a NodeSLOInformer.
@@ -41,6 +43,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// CacheGroups returns a CacheGroupInformer.
+func (v *version) CacheGroups() CacheGroupInformer {
+	return &cacheGroupInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // NodeMetrics returns a NodeMetricInformer.
 func (v *version) NodeMetrics() NodeMetricInformer {
 	return &nodeMetricInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -22,6 +22,14 @@ package v1alpha1
 // DeviceLister.
 type DeviceListerExpansion interface{}
 
+// ElasticQuotaRequestListerExpansion allows custom methods to be added to
+// ElasticQuotaRequestLister.
+type ElasticQuotaRequestListerExpansion interface{}
+
+// ElasticQuotaRequestNamespaceListerExpansion allows custom methods to be added to
+// ElasticQuotaRequestNamespaceLister.
+type ElasticQuotaRequestNamespaceListerExpansion interface{}
+
 // PodMigrationJobListerExpansion allows custom methods to be added to
 // PodMigrationJobLister.
 type PodMigrationJobListerExpansion interface{}
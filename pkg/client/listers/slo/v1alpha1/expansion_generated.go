@@ -18,6 +18,14 @@ limitations under the License.
 
 package v1alpha1
 
+// CacheGroupListerExpansion allows custom methods to be added to
+// CacheGroupLister.
+type CacheGroupListerExpansion interface{}
+
+// CacheGroupNamespaceListerExpansion allows custom methods to be added to
+// CacheGroupNamespaceLister.
+type CacheGroupNamespaceListerExpansion interface{}
+
 // NodeMetricListerExpansion allows custom methods to be added to
 // NodeMetricLister.
 type NodeMetricListerExpansion interface{}
@@ -0,0 +1,221 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podgroup
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	// WorkloadMappingsFile is the file of the workload mappings, the DefaultWorkloadMappings are used if it is empty.
+	WorkloadMappingsFile = ""
+
+	workloadMappings = DefaultWorkloadMappings
+)
+
+func InitFlags(fs *flag.FlagSet) {
+	fs.StringVar(&WorkloadMappingsFile, "podgroup-workload-mappings-file", WorkloadMappingsFile,
+		"determines the file of the workload mappings used to generate PodGroups from the workload CRs.")
+}
+
+// WorkloadMapping describes how to generate the PodGroup of a kind of workload CR. The PodGroup has the same
+// namespace and name as the CR, and it is owned by the CR.
+type WorkloadMapping struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	// MinMember is the replicas fields of the CR whose sum is the MinMember of the PodGroup.
+	MinMember []ReplicasField `json:"minMember"`
+	// ScheduleTimeoutSeconds is the ScheduleTimeoutSeconds of the PodGroup, nil means the default of the scheduler.
+	ScheduleTimeoutSeconds *int32 `json:"scheduleTimeoutSeconds,omitempty"`
+}
+
+// ReplicasField is a replicas field of the workload CR.
+type ReplicasField struct {
+	// Path is the dot separated path of the field, e.g. "spec.workerGroupSpecs[*].minReplicas".
+	// The segment with the "[*]" suffix iterates all the items of the list.
+	Path string `json:"path"`
+	// Default is the replicas if the field is not set but its parent is set, e.g. the replica spec exists
+	// without the replicas. The field is counted as zero if its parent is not set either.
+	Default int32 `json:"default,omitempty"`
+}
+
+// DefaultWorkloadMappings are the mappings of the well-known workloads.
+var DefaultWorkloadMappings = []WorkloadMapping{
+	{
+		APIVersion: "kubeflow.org/v1",
+		Kind:       "MPIJob",
+		MinMember: []ReplicasField{
+			{Path: "spec.mpiReplicaSpecs.Launcher.replicas", Default: 1},
+			{Path: "spec.mpiReplicaSpecs.Worker.replicas", Default: 1},
+		},
+	},
+	{
+		APIVersion: "kubeflow.org/v1",
+		Kind:       "PyTorchJob",
+		MinMember: []ReplicasField{
+			{Path: "spec.pytorchReplicaSpecs.Master.replicas", Default: 1},
+			{Path: "spec.pytorchReplicaSpecs.Worker.replicas", Default: 1},
+		},
+	},
+	{
+		APIVersion: "ray.io/v1alpha1",
+		Kind:       "RayCluster",
+		MinMember: []ReplicasField{
+			{Path: "spec.headGroupSpec.replicas", Default: 1},
+			{Path: "spec.workerGroupSpecs[*].minReplicas", Default: 0},
+		},
+	},
+}
+
+// LoadWorkloadMappings loads the workload mappings from WorkloadMappingsFile.
+func LoadWorkloadMappings() error {
+	if WorkloadMappingsFile == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(WorkloadMappingsFile)
+	if err != nil {
+		return err
+	}
+	mappings, err := ParseWorkloadMappings(data)
+	if err != nil {
+		return err
+	}
+	workloadMappings = mappings
+	return nil
+}
+
+// ParseWorkloadMappings parses the workload mappings in yaml or json.
+func ParseWorkloadMappings(data []byte) ([]WorkloadMapping, error) {
+	var mappings []WorkloadMapping
+	if err := yaml.Unmarshal(data, &mappings); err != nil {
+		return nil, err
+	}
+	for i := range mappings {
+		if err := mappings[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return mappings, nil
+}
+
+func (m *WorkloadMapping) Validate() error {
+	if m.APIVersion == "" || m.Kind == "" {
+		return fmt.Errorf("apiVersion and kind of workload mapping must be set")
+	}
+	if len(m.MinMember) == 0 {
+		return fmt.Errorf("minMember of workload mapping %s/%s must be set", m.APIVersion, m.Kind)
+	}
+	for _, field := range m.MinMember {
+		if field.Path == "" || field.Default < 0 {
+			return fmt.Errorf("invalid minMember field %q of workload mapping %s/%s", field.Path, m.APIVersion, m.Kind)
+		}
+	}
+	if m.ScheduleTimeoutSeconds != nil && *m.ScheduleTimeoutSeconds <= 0 {
+		return fmt.Errorf("scheduleTimeoutSeconds of workload mapping %s/%s must be positive", m.APIVersion, m.Kind)
+	}
+	return nil
+}
+
+// GetWorkloadMappings returns the workload mappings in use.
+func GetWorkloadMappings() []WorkloadMapping {
+	return workloadMappings
+}
+
+// GetWorkloadMapping returns the workload mapping of the apiVersion and kind, nil if the workload is not mapped.
+func GetWorkloadMapping(apiVersion, kind string) *WorkloadMapping {
+	for i := range workloadMappings {
+		if workloadMappings[i].APIVersion == apiVersion && workloadMappings[i].Kind == kind {
+			return &workloadMappings[i]
+		}
+	}
+	return nil
+}
+
+// GetMinMember sums the replicas fields of the workload.
+func (m *WorkloadMapping) GetMinMember(workload *unstructured.Unstructured) (int32, error) {
+	var minMember int32
+	for _, field := range m.MinMember {
+		replicas, err := getReplicas(workload.Object, strings.Split(field.Path, "."), field.Default)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get %s, %v", field.Path, err)
+		}
+		minMember += replicas
+	}
+	return minMember, nil
+}
+
+func getReplicas(obj map[string]interface{}, segments []string, defaultReplicas int32) (int32, error) {
+	segment := segments[0]
+	if strings.HasSuffix(segment, "[*]") {
+		value, ok := obj[strings.TrimSuffix(segment, "[*]")]
+		if !ok || value == nil {
+			return 0, nil
+		}
+		items, ok := value.([]interface{})
+		if !ok {
+			return 0, fmt.Errorf("%s is not a list", segment)
+		}
+		var sum int32
+		for _, item := range items {
+			itemObj, ok := item.(map[string]interface{})
+			if !ok {
+				return 0, fmt.Errorf("item of %s is not an object", segment)
+			}
+			if len(segments) == 1 {
+				return 0, fmt.Errorf("%s must be followed by a field", segment)
+			}
+			replicas, err := getReplicas(itemObj, segments[1:], defaultReplicas)
+			if err != nil {
+				return 0, err
+			}
+			sum += replicas
+		}
+		return sum, nil
+	}
+
+	value, ok := obj[segment]
+	if len(segments) == 1 {
+		if !ok || value == nil {
+			return defaultReplicas, nil
+		}
+		switch v := value.(type) {
+		case int64:
+			return int32(v), nil
+		case float64:
+			return int32(v), nil
+		case int32:
+			return v, nil
+		case int:
+			return int32(v), nil
+		}
+		return 0, fmt.Errorf("%s is not a number", segment)
+	}
+	if !ok || value == nil {
+		return 0, nil
+	}
+	child, ok := value.(map[string]interface{})
+	if !ok {
+		return 0, fmt.Errorf("%s is not an object", segment)
+	}
+	return getReplicas(child, segments[1:], defaultReplicas)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podgroup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestWorkloadMapping_GetMinMember(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    string
+		object  map[string]interface{}
+		want    int32
+		wantErr bool
+	}{
+		{
+			name: "MPIJob",
+			kind: "MPIJob",
+			object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"mpiReplicaSpecs": map[string]interface{}{
+						"Launcher": map[string]interface{}{},
+						"Worker":   map[string]interface{}{"replicas": int64(4)},
+					},
+				},
+			},
+			want: 5,
+		},
+		{
+			name: "PyTorchJob without master",
+			kind: "PyTorchJob",
+			object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"pytorchReplicaSpecs": map[string]interface{}{
+						"Worker": map[string]interface{}{"replicas": int64(2)},
+					},
+				},
+			},
+			want: 2,
+		},
+		{
+			name: "RayCluster",
+			kind: "RayCluster",
+			object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"headGroupSpec": map[string]interface{}{},
+					"workerGroupSpecs": []interface{}{
+						map[string]interface{}{"minReplicas": int64(2)},
+						map[string]interface{}{"minReplicas": int64(3)},
+						map[string]interface{}{},
+					},
+				},
+			},
+			want: 6,
+		},
+		{
+			name: "invalid replicas",
+			kind: "PyTorchJob",
+			object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"pytorchReplicaSpecs": map[string]interface{}{
+						"Worker": map[string]interface{}{"replicas": "2"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mapping *WorkloadMapping
+			for i := range DefaultWorkloadMappings {
+				if DefaultWorkloadMappings[i].Kind == tt.kind {
+					mapping = &DefaultWorkloadMappings[i]
+				}
+			}
+			assert.NotNil(t, mapping)
+			got, err := mapping.GetMinMember(&unstructured.Unstructured{Object: tt.object})
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseWorkloadMappings(t *testing.T) {
+	mappings, err := ParseWorkloadMappings([]byte(`
+- apiVersion: example.com/v1
+  kind: TrainingJob
+  minMember:
+    - path: spec.replicas
+      default: 1
+  scheduleTimeoutSeconds: 60
+`))
+	assert.NoError(t, err)
+	assert.Len(t, mappings, 1)
+	assert.Equal(t, "TrainingJob", mappings[0].Kind)
+	assert.Equal(t, []ReplicasField{{Path: "spec.replicas", Default: 1}}, mappings[0].MinMember)
+	assert.Equal(t, int32(60), *mappings[0].ScheduleTimeoutSeconds)
+
+	_, err = ParseWorkloadMappings([]byte(`
+- apiVersion: example.com/v1
+  kind: TrainingJob
+`))
+	assert.Error(t, err)
+}
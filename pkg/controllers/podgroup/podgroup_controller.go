@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podgroup
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+)
+
+// PodGroupReconciler generates the PodGroup of the workload CRs of a WorkloadMapping, so that the pods of
+// the workload are scheduled as a gang without annotating the min member by hand.
+type PodGroupReconciler struct {
+	client.Client
+	Mapping WorkloadMapping
+}
+
+// +kubebuilder:rbac:groups=scheduling.sigs.k8s.io,resources=podgroups,verbs=get;list;watch;create;patch
+// +kubebuilder:rbac:groups=kubeflow.org,resources=mpijobs;pytorchjobs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ray.io,resources=rayclusters,verbs=get;list;watch
+
+func (r *PodGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	workload := r.newWorkload()
+	if err := r.Client.Get(ctx, req.NamespacedName, workload); err != nil {
+		if errors.IsNotFound(err) {
+			// the PodGroup is garbage collected by the owner reference
+			return ctrl.Result{}, nil
+		}
+		klog.Errorf("failed to get %s %v, err: %v", r.Mapping.Kind, req.NamespacedName, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	if workload.GetDeletionTimestamp() != nil {
+		return ctrl.Result{}, nil
+	}
+
+	minMember, err := r.Mapping.GetMinMember(workload)
+	if err != nil {
+		klog.Warningf("skip generating PodGroup for %s %v, err: %v", r.Mapping.Kind, req.NamespacedName, err)
+		return ctrl.Result{}, nil
+	}
+	if minMember <= 0 {
+		return ctrl.Result{}, nil
+	}
+
+	podGroup := &v1alpha1.PodGroup{}
+	err = r.Client.Get(ctx, req.NamespacedName, podGroup)
+	if errors.IsNotFound(err) {
+		podGroup = r.newPodGroup(workload, minMember)
+		if err = r.Client.Create(ctx, podGroup); err != nil && !errors.IsAlreadyExists(err) {
+			klog.Errorf("failed to create PodGroup %v, err: %v", req.NamespacedName, err)
+			return ctrl.Result{Requeue: true}, err
+		}
+		klog.V(3).Infof("created PodGroup %v with minMember %d for %s", req.NamespacedName, minMember, r.Mapping.Kind)
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		klog.Errorf("failed to get PodGroup %v, err: %v", req.NamespacedName, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	if !metav1.IsControlledBy(podGroup, workload) {
+		// the PodGroup is managed by the user
+		klog.V(4).Infof("skip PodGroup %v not controlled by %s", req.NamespacedName, r.Mapping.Kind)
+		return ctrl.Result{}, nil
+	}
+	if podGroup.Spec.MinMember == minMember && equalInt32Ptr(podGroup.Spec.ScheduleTimeoutSeconds, r.Mapping.ScheduleTimeoutSeconds) {
+		return ctrl.Result{}, nil
+	}
+	patch := client.MergeFrom(podGroup.DeepCopy())
+	podGroup.Spec.MinMember = minMember
+	podGroup.Spec.ScheduleTimeoutSeconds = r.Mapping.ScheduleTimeoutSeconds
+	if err = r.Client.Patch(ctx, podGroup, patch); err != nil {
+		klog.Errorf("failed to patch PodGroup %v, err: %v", req.NamespacedName, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	klog.V(3).Infof("updated PodGroup %v with minMember %d for %s", req.NamespacedName, minMember, r.Mapping.Kind)
+	return ctrl.Result{}, nil
+}
+
+func (r *PodGroupReconciler) newWorkload() *unstructured.Unstructured {
+	workload := &unstructured.Unstructured{}
+	workload.SetAPIVersion(r.Mapping.APIVersion)
+	workload.SetKind(r.Mapping.Kind)
+	return workload
+}
+
+func (r *PodGroupReconciler) newPodGroup(workload *unstructured.Unstructured, minMember int32) *v1alpha1.PodGroup {
+	return &v1alpha1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: workload.GetNamespace(),
+			Name:      workload.GetName(),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(workload, workload.GroupVersionKind()),
+			},
+		},
+		Spec: v1alpha1.PodGroupSpec{
+			MinMember:              minMember,
+			ScheduleTimeoutSeconds: r.Mapping.ScheduleTimeoutSeconds,
+		},
+	}
+}
+
+func equalInt32Ptr(a, b *int32) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func (r *PodGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("podgroup-" + strings.ToLower(r.Mapping.Kind)).
+		For(r.newWorkload()).
+		Owns(&v1alpha1.PodGroup{}).
+		Complete(r)
+}
+
+// SetupWithManager sets up a PodGroupReconciler for each workload mapping whose CRD is installed.
+func SetupWithManager(mgr ctrl.Manager) error {
+	if err := LoadWorkloadMappings(); err != nil {
+		return err
+	}
+	for _, mapping := range GetWorkloadMappings() {
+		gv, err := schema.ParseGroupVersion(mapping.APIVersion)
+		if err != nil {
+			return err
+		}
+		if _, err = mgr.GetRESTMapper().RESTMapping(gv.WithKind(mapping.Kind).GroupKind(), gv.Version); err != nil {
+			if meta.IsNoMatchError(err) {
+				klog.Infof("skip generating PodGroups for %s/%s since its CRD is not installed", mapping.APIVersion, mapping.Kind)
+				continue
+			}
+			return err
+		}
+		r := &PodGroupReconciler{
+			Client:  mgr.GetClient(),
+			Mapping: mapping,
+		}
+		if err = r.SetupWithManager(mgr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ reconcile.Reconciler = &PodGroupReconciler{}
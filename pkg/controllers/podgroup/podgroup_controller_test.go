@@ -0,0 +1,93 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podgroup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+)
+
+func newTestPyTorchJob(workerReplicas int64) *unstructured.Unstructured {
+	job := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"pytorchReplicaSpecs": map[string]interface{}{
+				"Master": map[string]interface{}{"replicas": int64(1)},
+				"Worker": map[string]interface{}{"replicas": workerReplicas},
+			},
+		},
+	}}
+	job.SetAPIVersion("kubeflow.org/v1")
+	job.SetKind("PyTorchJob")
+	job.SetNamespace("default")
+	job.SetName("test-job")
+	job.SetUID("test-job-uid")
+	return job
+}
+
+func TestPodGroupReconciler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+
+	job := newTestPyTorchJob(3)
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(job).Build()
+	r := &PodGroupReconciler{
+		Client:  client,
+		Mapping: *GetWorkloadMapping("kubeflow.org/v1", "PyTorchJob"),
+	}
+	key := types.NamespacedName{Namespace: "default", Name: "test-job"}
+
+	_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+	podGroup := &v1alpha1.PodGroup{}
+	assert.NoError(t, client.Get(context.TODO(), key, podGroup))
+	assert.Equal(t, int32(4), podGroup.Spec.MinMember)
+	assert.True(t, metav1.IsControlledBy(podGroup, job))
+
+	// the min member follows the replicas of the workload
+	updatedJob := newTestPyTorchJob(7)
+	assert.NoError(t, client.Get(context.TODO(), key, job))
+	updatedJob.SetResourceVersion(job.GetResourceVersion())
+	assert.NoError(t, client.Update(context.TODO(), updatedJob))
+	_, err = r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+	assert.NoError(t, client.Get(context.TODO(), key, podGroup))
+	assert.Equal(t, int32(8), podGroup.Spec.MinMember)
+
+	// the PodGroup created by the user is left untouched
+	podGroup.OwnerReferences = nil
+	podGroup.Spec.MinMember = 2
+	assert.NoError(t, client.Update(context.TODO(), podGroup))
+	_, err = r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+	assert.NoError(t, client.Get(context.TODO(), key, podGroup))
+	assert.Equal(t, int32(2), podGroup.Spec.MinMember)
+
+	// the deleted workload is ignored
+	_, err = r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "not-exist"}})
+	assert.NoError(t, err)
+}
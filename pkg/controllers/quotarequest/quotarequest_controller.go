@@ -0,0 +1,193 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotarequest
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	schedv1alpha1 "sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+// ElasticQuotaRequestReconciler applies the approved ElasticQuotaRequests onto the ElasticQuotas, so that the
+// namespace admins can change the min/max of their quotas without being granted to update the ElasticQuotas.
+type ElasticQuotaRequestReconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=scheduling.koordinator.sh,resources=elasticquotarequests,verbs=get;list;watch
+// +kubebuilder:rbac:groups=scheduling.koordinator.sh,resources=elasticquotarequests/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=scheduling.sigs.k8s.io,resources=elasticquotas,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+func (r *ElasticQuotaRequestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	request := &v1alpha1.ElasticQuotaRequest{}
+	if err := r.Client.Get(ctx, req.NamespacedName, request); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		klog.Errorf("failed to get ElasticQuotaRequest %v, err: %v", req.NamespacedName, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	if request.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	if request.Status.Phase == "" || request.Status.ObservedGeneration != request.Generation {
+		// a new request, or the spec is changed after the approval, which must be approved again
+		message := "waiting for approval"
+		if request.Status.Phase != "" && request.Status.Phase != v1alpha1.ElasticQuotaRequestPending {
+			message = fmt.Sprintf("spec is changed in phase %s, waiting for approval", request.Status.Phase)
+		}
+		return ctrl.Result{}, r.updatePhase(ctx, request, v1alpha1.ElasticQuotaRequestPending, message)
+	}
+	if request.Status.Phase != v1alpha1.ElasticQuotaRequestApproved {
+		return ctrl.Result{}, nil
+	}
+
+	quota := &schedv1alpha1.ElasticQuota{}
+	quotaKey := client.ObjectKey{Namespace: request.Namespace, Name: request.Spec.QuotaName}
+	if err := r.Client.Get(ctx, quotaKey, quota); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, r.updatePhase(ctx, request, v1alpha1.ElasticQuotaRequestFailed,
+				fmt.Sprintf("ElasticQuota %s not found", request.Spec.QuotaName))
+		}
+		klog.Errorf("failed to get ElasticQuota %v, err: %v", quotaKey, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	min, max := getApprovedQuota(request, quota)
+	if err := validateQuota(min, max); err != nil {
+		return ctrl.Result{}, r.updatePhase(ctx, request, v1alpha1.ElasticQuotaRequestFailed, err.Error())
+	}
+	if !quotav1.Equals(quota.Spec.Min, min) || !quotav1.Equals(quota.Spec.Max, max) {
+		patch := client.MergeFrom(quota.DeepCopy())
+		quota.Spec.Min = min
+		quota.Spec.Max = max
+		if err := r.Client.Patch(ctx, quota, patch); err != nil {
+			if errors.IsInvalid(err) || errors.IsForbidden(err) {
+				// rejected by the validation of the quota tree, it won't succeed by retrying
+				return ctrl.Result{}, r.updatePhase(ctx, request, v1alpha1.ElasticQuotaRequestFailed,
+					fmt.Sprintf("failed to patch ElasticQuota %s, err: %v", request.Spec.QuotaName, err))
+			}
+			klog.Errorf("failed to patch ElasticQuota %v, err: %v", quotaKey, err)
+			return ctrl.Result{Requeue: true}, err
+		}
+		klog.V(3).Infof("patched ElasticQuota %v by ElasticQuotaRequest %v, min %v, max %v",
+			quotaKey, req.NamespacedName, min, max)
+	}
+	return ctrl.Result{}, r.updatePhase(ctx, request, v1alpha1.ElasticQuotaRequestApplied,
+		fmt.Sprintf("applied to ElasticQuota %s", request.Spec.QuotaName))
+}
+
+// getApprovedQuota returns the min/max of the quota overridden by the approved amounts of the request, the approved
+// amounts default to the requested ones.
+func getApprovedQuota(request *v1alpha1.ElasticQuotaRequest, quota *schedv1alpha1.ElasticQuota) (corev1.ResourceList, corev1.ResourceList) {
+	approvedMin, approvedMax := request.Status.ApprovedMin, request.Status.ApprovedMax
+	if approvedMin == nil {
+		approvedMin = request.Spec.Min
+	}
+	if approvedMax == nil {
+		approvedMax = request.Spec.Max
+	}
+	min, max := quota.Spec.Min.DeepCopy(), quota.Spec.Max.DeepCopy()
+	if min == nil {
+		min = corev1.ResourceList{}
+	}
+	if max == nil {
+		max = corev1.ResourceList{}
+	}
+	for name, quantity := range approvedMin {
+		min[name] = quantity.DeepCopy()
+	}
+	for name, quantity := range approvedMax {
+		max[name] = quantity.DeepCopy()
+	}
+	return min, max
+}
+
+func validateQuota(min, max corev1.ResourceList) error {
+	for name, quantity := range min {
+		if quantity.Sign() < 0 {
+			return fmt.Errorf("min of %s is negative", name)
+		}
+		maxQuantity, ok := max[name]
+		if ok && quantity.Cmp(maxQuantity) > 0 {
+			return fmt.Errorf("min of %s is larger than max, min %s, max %s", name, quantity.String(), maxQuantity.String())
+		}
+	}
+	for name, quantity := range max {
+		if quantity.Sign() < 0 {
+			return fmt.Errorf("max of %s is negative", name)
+		}
+	}
+	return nil
+}
+
+func (r *ElasticQuotaRequestReconciler) updatePhase(ctx context.Context, request *v1alpha1.ElasticQuotaRequest,
+	phase v1alpha1.ElasticQuotaRequestPhase, message string) error {
+	if request.Status.Phase == phase && request.Status.Message == message &&
+		request.Status.ObservedGeneration == request.Generation {
+		return nil
+	}
+	patch := client.MergeFrom(request.DeepCopy())
+	now := metav1.Now()
+	request.Status.Phase = phase
+	request.Status.Message = message
+	request.Status.ObservedGeneration = request.Generation
+	request.Status.LastTransitionTime = &now
+	if phase == v1alpha1.ElasticQuotaRequestPending {
+		// the approval of the previous spec is revoked
+		request.Status.ApprovedMin = nil
+		request.Status.ApprovedMax = nil
+		request.Status.Approver = ""
+	}
+	if err := r.Client.Status().Patch(ctx, request, patch); err != nil {
+		klog.Errorf("failed to update ElasticQuotaRequest %s/%s to %s, err: %v", request.Namespace, request.Name, phase, err)
+		return err
+	}
+	eventType := corev1.EventTypeNormal
+	if phase == v1alpha1.ElasticQuotaRequestFailed {
+		eventType = corev1.EventTypeWarning
+	}
+	if r.Recorder != nil {
+		r.Recorder.Eventf(request, eventType, string(phase), message)
+	}
+	klog.V(4).Infof("ElasticQuotaRequest %s/%s is %s, %s", request.Namespace, request.Name, phase, message)
+	return nil
+}
+
+func (r *ElasticQuotaRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("elasticquotarequest").
+		For(&v1alpha1.ElasticQuotaRequest{}).
+		Complete(r)
+}
+
+var _ reconcile.Reconciler = &ElasticQuotaRequestReconciler{}
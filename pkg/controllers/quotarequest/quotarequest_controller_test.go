@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotarequest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	schedv1alpha1 "sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+func TestElasticQuotaRequestReconciler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	_ = schedv1alpha1.AddToScheme(scheme)
+
+	quota := &schedv1alpha1.ElasticQuota{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "quota-a"},
+		Spec: schedv1alpha1.ElasticQuotaSpec{
+			Min: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("10"),
+				corev1.ResourceMemory: resource.MustParse("20Gi"),
+			},
+			Max: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("20"),
+				corev1.ResourceMemory: resource.MustParse("40Gi"),
+			},
+		},
+	}
+	request := &v1alpha1.ElasticQuotaRequest{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "more-cpu", Generation: 1},
+		Spec: v1alpha1.ElasticQuotaRequestSpec{
+			QuotaName: "quota-a",
+			Min:       corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("30")},
+			Max:       corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("40")},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(quota, request).Build()
+	r := &ElasticQuotaRequestReconciler{Client: c, Recorder: record.NewFakeRecorder(10)}
+	requestKey := types.NamespacedName{Namespace: "team-a", Name: "more-cpu"}
+	quotaKey := types.NamespacedName{Namespace: "team-a", Name: "quota-a"}
+	reconcile := func() *v1alpha1.ElasticQuotaRequest {
+		_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: requestKey})
+		assert.NoError(t, err)
+		got := &v1alpha1.ElasticQuotaRequest{}
+		assert.NoError(t, c.Get(context.TODO(), requestKey, got))
+		return got
+	}
+	approve := func(request *v1alpha1.ElasticQuotaRequest, approvedMin corev1.ResourceList) {
+		patch := client.MergeFrom(request.DeepCopy())
+		request.Status.Phase = v1alpha1.ElasticQuotaRequestApproved
+		request.Status.ApprovedMin = approvedMin
+		request.Status.Approver = "admin"
+		assert.NoError(t, c.Status().Patch(context.TODO(), request, patch))
+	}
+
+	// the new request waits for approval, and the quota is untouched
+	got := reconcile()
+	assert.Equal(t, v1alpha1.ElasticQuotaRequestPending, got.Status.Phase)
+	assert.Equal(t, int64(1), got.Status.ObservedGeneration)
+	gotQuota := &schedv1alpha1.ElasticQuota{}
+	assert.NoError(t, c.Get(context.TODO(), quotaKey, gotQuota))
+	assert.Equal(t, int64(10), gotQuota.Spec.Min.Cpu().Value())
+
+	// the approved amounts are applied, the other resources are kept
+	approve(got, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("25")})
+	got = reconcile()
+	assert.Equal(t, v1alpha1.ElasticQuotaRequestApplied, got.Status.Phase)
+	assert.NoError(t, c.Get(context.TODO(), quotaKey, gotQuota))
+	assert.Equal(t, int64(25), gotQuota.Spec.Min.Cpu().Value())
+	assert.Equal(t, int64(40), gotQuota.Spec.Max.Cpu().Value())
+	assert.Equal(t, int64(20<<30), gotQuota.Spec.Min.Memory().Value())
+	assert.Equal(t, int64(40<<30), gotQuota.Spec.Max.Memory().Value())
+
+	// changing the spec revokes the approval
+	got.Spec.Min = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50")}
+	got.Generation = 2
+	assert.NoError(t, c.Update(context.TODO(), got))
+	got = reconcile()
+	assert.Equal(t, v1alpha1.ElasticQuotaRequestPending, got.Status.Phase)
+	assert.Equal(t, int64(2), got.Status.ObservedGeneration)
+	assert.Empty(t, got.Status.Approver)
+
+	// min larger than max can't be applied
+	approve(got, nil)
+	got = reconcile()
+	assert.Equal(t, v1alpha1.ElasticQuotaRequestFailed, got.Status.Phase)
+	assert.NoError(t, c.Get(context.TODO(), quotaKey, gotQuota))
+	assert.Equal(t, int64(25), gotQuota.Spec.Min.Cpu().Value())
+}
+
+func TestElasticQuotaRequestReconciler_QuotaNotFound(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	_ = schedv1alpha1.AddToScheme(scheme)
+
+	request := &v1alpha1.ElasticQuotaRequest{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "more-cpu"},
+		Spec: v1alpha1.ElasticQuotaRequestSpec{
+			QuotaName: "not-exist",
+			Min:       corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("30")},
+		},
+		Status: v1alpha1.ElasticQuotaRequestStatus{Phase: v1alpha1.ElasticQuotaRequestApproved},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(request).Build()
+	r := &ElasticQuotaRequestReconciler{Client: c, Recorder: record.NewFakeRecorder(10)}
+	key := types.NamespacedName{Namespace: "team-a", Name: "more-cpu"}
+	_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+	got := &v1alpha1.ElasticQuotaRequest{}
+	assert.NoError(t, c.Get(context.TODO(), key, got))
+	assert.Equal(t, v1alpha1.ElasticQuotaRequestFailed, got.Status.Phase)
+}
@@ -49,6 +49,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&DefaultEvictorArgs{},
 		&RemovePodsViolatingNodeAffinityArgs{},
 		&MigrationControllerArgs{},
+		&QuotaRebalancingArgs{},
+		&GPUDefragmentationArgs{},
 	)
 	return nil
 }
@@ -38,6 +38,12 @@ const (
 	defaultMigrationJobMode           = sev1alpha1.PodMigrationJobModeReservationFirst
 	defaultMigrationJobTTL            = 5 * time.Minute
 	defaultMigrationJobEvictionPolicy = migrationevictor.NativeEvictorName
+	defaultEvictionBudgetWindow       = time.Hour
+
+	defaultGPUDefragmentationMaxPodsToEvictPerNode = 4
+
+	defaultQuotaRebalancingOverusedThresholdPercent = 10
+	defaultQuotaRebalancingMaxPodsToEvictPerQuota   = 2
 )
 
 func addDefaultingFuncs(scheme *runtime.Scheme) error {
@@ -196,12 +202,27 @@ func SetDefaults_DefaultEvictorArgs(obj *DefaultEvictorArgs) {
 	}
 }
 
+func SetDefaults_QuotaRebalancingArgs(obj *QuotaRebalancingArgs) {
+	if obj.OverusedThresholdPercent == nil {
+		obj.OverusedThresholdPercent = pointer.Int32(defaultQuotaRebalancingOverusedThresholdPercent)
+	}
+	if obj.MaxPodsToEvictPerQuota == nil {
+		obj.MaxPodsToEvictPerQuota = pointer.Int32(defaultQuotaRebalancingMaxPodsToEvictPerQuota)
+	}
+}
+
 func SetDefaults_RemovePodsViolatingNodeAffinityArgs(obj *RemovePodsViolatingNodeAffinityArgs) {
 	if len(obj.NodeAffinityType) == 0 {
 		obj.NodeAffinityType = append(obj.NodeAffinityType, "requiredDuringSchedulingIgnoredDuringExecution")
 	}
 }
 
+func SetDefaults_GPUDefragmentationArgs(obj *GPUDefragmentationArgs) {
+	if obj.MaxPodsToEvictPerNode == nil {
+		obj.MaxPodsToEvictPerNode = pointer.Int32(defaultGPUDefragmentationMaxPodsToEvictPerNode)
+	}
+}
+
 func SetDefaults_MigrationControllerArgs(obj *MigrationControllerArgs) {
 	if obj.MaxConcurrentReconciles == nil {
 		obj.MaxConcurrentReconciles = pointer.Int32(defaultMigrationControllerMaxConcurrentReconciles)
@@ -215,4 +236,7 @@ func SetDefaults_MigrationControllerArgs(obj *MigrationControllerArgs) {
 	if obj.EvictionPolicy == "" {
 		obj.EvictionPolicy = defaultMigrationJobEvictionPolicy
 	}
+	if obj.EvictionBudgetWindow == nil {
+		obj.EvictionBudgetWindow = &metav1.Duration{Duration: defaultEvictionBudgetWindow}
+	}
 }
@@ -56,6 +56,9 @@ type DefaultEvictorArgs struct {
 	// LabelSelector sets whether to apply label filtering when evicting.
 	// Any pod matching the label selector is considered evictable.
 	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+	// DisableGangProtection allows the members of a gang to be evicted even if
+	// the gang drops below its minMember.
+	DisableGangProtection bool `json:"disableGangProtection"`
 }
 
 type PriorityThreshold struct {
@@ -128,10 +131,33 @@ type MigrationControllerArgs struct {
 	// Value can be an absolute number (ex: 5) or a percentage of desired pods (ex: 10%).
 	MaxUnavailablePerWorkload *intstr.IntOrString `json:"maxUnavailablePerWorkload,omitempty"`
 
+	// MaxEvictionsPerNamespace represents the maximum number of pods that can be evicted per namespace
+	// within the EvictionBudgetWindow.
+	MaxEvictionsPerNamespace *int32 `json:"maxEvictionsPerNamespace,omitempty"`
+
+	// MaxEvictionsPerWorkload represents the maximum number of pods that can be evicted per workload
+	// within the EvictionBudgetWindow.
+	// Value can be an absolute number (ex: 5) or a percentage of desired pods (ex: 10%).
+	MaxEvictionsPerWorkload *intstr.IntOrString `json:"maxEvictionsPerWorkload,omitempty"`
+
+	// EvictionBudgetWindow represents the sliding window of MaxEvictionsPerNamespace and MaxEvictionsPerWorkload
+	// Default is 1 hour
+	EvictionBudgetWindow *metav1.Duration `json:"evictionBudgetWindow,omitempty"`
+
 	// DefaultJobMode represents the default operating mode of the PodMigrationJob
 	// Default is PodMigrationJobModeReservationFirst
 	DefaultJobMode string `json:"defaultJobMode,omitempty"`
 
+	// ReservationFirstWorkloadKinds represents the kinds of the workloads (ex: StatefulSet) whose Pods are always
+	// migrated in PodMigrationJobModeReservationFirst regardless of DefaultJobMode, so that the Pods are evicted
+	// only after the resources are reserved on the destination nodes.
+	ReservationFirstWorkloadKinds []string `json:"reservationFirstWorkloadKinds,omitempty"`
+
+	// EnablePreMigrationHook enables the pre-migration hooks declared by the annotations of the Pods, the Pod
+	// is evicted only after the hook reports the workload is ready to be migrated.
+	// Default is false
+	EnablePreMigrationHook bool `json:"enablePreMigrationHook,omitempty"`
+
 	// DefaultJobTTL represents the default TTL of the PodMigrationJob
 	// Default is 5 minute
 	DefaultJobTTL *metav1.Duration `json:"defaultJobTTL,omitempty"`
@@ -145,3 +171,39 @@ type MigrationControllerArgs struct {
 	// DefaultDeleteOptions defines options when deleting migrated pods and preempted pods through the method specified by EvictionPolicy
 	DefaultDeleteOptions *metav1.DeleteOptions `json:"defaultDeleteOptions,omitempty"`
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GPUDefragmentationArgs holds arguments used to configure the GPUDefragmentation plugin.
+type GPUDefragmentationArgs struct {
+	metav1.TypeMeta
+
+	// Namespaces carries a list of included/excluded namespaces
+	Namespaces *Namespaces `json:"namespaces,omitempty"`
+	// LabelSelector sets whether to apply label filtering when evicting.
+	// Any pod matching the label selector is considered evictable.
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+	// MaxPodsToEvictPerNode restricts maximum of pods to be evicted to defragment a node in one round.
+	// Default is 4
+	MaxPodsToEvictPerNode *int32 `json:"maxPodsToEvictPerNode,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// QuotaRebalancingArgs holds arguments used to configure the QuotaRebalancing plugin.
+type QuotaRebalancingArgs struct {
+	metav1.TypeMeta
+
+	// Namespaces carries a list of included/excluded namespaces
+	Namespaces *Namespaces `json:"namespaces,omitempty"`
+	// LabelSelector sets whether to apply label filtering when evicting.
+	// Any pod matching the label selector is considered evictable.
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+	// OverusedThresholdPercent represents how much the used of a quota group can exceed its runtime before
+	// its pods are evicted, in percentage of the runtime.
+	// Default is 10
+	OverusedThresholdPercent *int32 `json:"overusedThresholdPercent,omitempty"`
+	// MaxPodsToEvictPerQuota restricts maximum of pods to be evicted per quota group in one round.
+	// Default is 2
+	MaxPodsToEvictPerQuota *int32 `json:"maxPodsToEvictPerQuota,omitempty"`
+}
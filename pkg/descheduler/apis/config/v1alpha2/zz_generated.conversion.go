@@ -59,6 +59,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*GPUDefragmentationArgs)(nil), (*config.GPUDefragmentationArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_GPUDefragmentationArgs_To_config_GPUDefragmentationArgs(a.(*GPUDefragmentationArgs), b.(*config.GPUDefragmentationArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.GPUDefragmentationArgs)(nil), (*GPUDefragmentationArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_GPUDefragmentationArgs_To_v1alpha2_GPUDefragmentationArgs(a.(*config.GPUDefragmentationArgs), b.(*GPUDefragmentationArgs), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*MigrationControllerArgs)(nil), (*config.MigrationControllerArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_MigrationControllerArgs_To_config_MigrationControllerArgs(a.(*MigrationControllerArgs), b.(*config.MigrationControllerArgs), scope)
 	}); err != nil {
@@ -129,6 +139,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*QuotaRebalancingArgs)(nil), (*config.QuotaRebalancingArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_QuotaRebalancingArgs_To_config_QuotaRebalancingArgs(a.(*QuotaRebalancingArgs), b.(*config.QuotaRebalancingArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.QuotaRebalancingArgs)(nil), (*QuotaRebalancingArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_QuotaRebalancingArgs_To_v1alpha2_QuotaRebalancingArgs(a.(*config.QuotaRebalancingArgs), b.(*QuotaRebalancingArgs), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*RemovePodsViolatingNodeAffinityArgs)(nil), (*config.RemovePodsViolatingNodeAffinityArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_RemovePodsViolatingNodeAffinityArgs_To_config_RemovePodsViolatingNodeAffinityArgs(a.(*RemovePodsViolatingNodeAffinityArgs), b.(*config.RemovePodsViolatingNodeAffinityArgs), scope)
 	}); err != nil {
@@ -165,6 +185,7 @@ func autoConvert_v1alpha2_DefaultEvictorArgs_To_config_DefaultEvictorArgs(in *De
 	out.NodeFit = in.NodeFit
 	out.PriorityThreshold = (*config.PriorityThreshold)(unsafe.Pointer(in.PriorityThreshold))
 	out.LabelSelector = (*v1.LabelSelector)(unsafe.Pointer(in.LabelSelector))
+	out.DisableGangProtection = in.DisableGangProtection
 	return nil
 }
 
@@ -186,6 +207,7 @@ func autoConvert_config_DefaultEvictorArgs_To_v1alpha2_DefaultEvictorArgs(in *co
 	out.NodeFit = in.NodeFit
 	out.PriorityThreshold = (*PriorityThreshold)(unsafe.Pointer(in.PriorityThreshold))
 	out.LabelSelector = (*v1.LabelSelector)(unsafe.Pointer(in.LabelSelector))
+	out.DisableGangProtection = in.DisableGangProtection
 	return nil
 }
 
@@ -304,6 +326,30 @@ func Convert_config_DeschedulerProfile_To_v1alpha2_DeschedulerProfile(in *config
 	return autoConvert_config_DeschedulerProfile_To_v1alpha2_DeschedulerProfile(in, out, s)
 }
 
+func autoConvert_v1alpha2_GPUDefragmentationArgs_To_config_GPUDefragmentationArgs(in *GPUDefragmentationArgs, out *config.GPUDefragmentationArgs, s conversion.Scope) error {
+	out.Namespaces = (*config.Namespaces)(unsafe.Pointer(in.Namespaces))
+	out.LabelSelector = (*v1.LabelSelector)(unsafe.Pointer(in.LabelSelector))
+	out.MaxPodsToEvictPerNode = (*int32)(unsafe.Pointer(in.MaxPodsToEvictPerNode))
+	return nil
+}
+
+// Convert_v1alpha2_GPUDefragmentationArgs_To_config_GPUDefragmentationArgs is an autogenerated conversion function.
+func Convert_v1alpha2_GPUDefragmentationArgs_To_config_GPUDefragmentationArgs(in *GPUDefragmentationArgs, out *config.GPUDefragmentationArgs, s conversion.Scope) error {
+	return autoConvert_v1alpha2_GPUDefragmentationArgs_To_config_GPUDefragmentationArgs(in, out, s)
+}
+
+func autoConvert_config_GPUDefragmentationArgs_To_v1alpha2_GPUDefragmentationArgs(in *config.GPUDefragmentationArgs, out *GPUDefragmentationArgs, s conversion.Scope) error {
+	out.Namespaces = (*Namespaces)(unsafe.Pointer(in.Namespaces))
+	out.LabelSelector = (*v1.LabelSelector)(unsafe.Pointer(in.LabelSelector))
+	out.MaxPodsToEvictPerNode = (*int32)(unsafe.Pointer(in.MaxPodsToEvictPerNode))
+	return nil
+}
+
+// Convert_config_GPUDefragmentationArgs_To_v1alpha2_GPUDefragmentationArgs is an autogenerated conversion function.
+func Convert_config_GPUDefragmentationArgs_To_v1alpha2_GPUDefragmentationArgs(in *config.GPUDefragmentationArgs, out *GPUDefragmentationArgs, s conversion.Scope) error {
+	return autoConvert_config_GPUDefragmentationArgs_To_v1alpha2_GPUDefragmentationArgs(in, out, s)
+}
+
 func autoConvert_v1alpha2_MigrationControllerArgs_To_config_MigrationControllerArgs(in *MigrationControllerArgs, out *config.MigrationControllerArgs, s conversion.Scope) error {
 	out.DryRun = in.DryRun
 	if err := v1.Convert_Pointer_int32_To_int32(&in.MaxConcurrentReconciles, &out.MaxConcurrentReconciles, s); err != nil {
@@ -319,7 +365,14 @@ func autoConvert_v1alpha2_MigrationControllerArgs_To_config_MigrationControllerA
 	out.MaxMigratingPerNamespace = (*int32)(unsafe.Pointer(in.MaxMigratingPerNamespace))
 	out.MaxMigratingPerWorkload = (*intstr.IntOrString)(unsafe.Pointer(in.MaxMigratingPerWorkload))
 	out.MaxUnavailablePerWorkload = (*intstr.IntOrString)(unsafe.Pointer(in.MaxUnavailablePerWorkload))
+	out.MaxEvictionsPerNamespace = (*int32)(unsafe.Pointer(in.MaxEvictionsPerNamespace))
+	out.MaxEvictionsPerWorkload = (*intstr.IntOrString)(unsafe.Pointer(in.MaxEvictionsPerWorkload))
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.EvictionBudgetWindow, &out.EvictionBudgetWindow, s); err != nil {
+		return err
+	}
 	out.DefaultJobMode = in.DefaultJobMode
+	out.ReservationFirstWorkloadKinds = *(*[]string)(unsafe.Pointer(&in.ReservationFirstWorkloadKinds))
+	out.EnablePreMigrationHook = in.EnablePreMigrationHook
 	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.DefaultJobTTL, &out.DefaultJobTTL, s); err != nil {
 		return err
 	}
@@ -347,10 +400,17 @@ func autoConvert_config_MigrationControllerArgs_To_v1alpha2_MigrationControllerA
 	out.MaxMigratingPerNamespace = (*int32)(unsafe.Pointer(in.MaxMigratingPerNamespace))
 	out.MaxMigratingPerWorkload = (*intstr.IntOrString)(unsafe.Pointer(in.MaxMigratingPerWorkload))
 	out.MaxUnavailablePerWorkload = (*intstr.IntOrString)(unsafe.Pointer(in.MaxUnavailablePerWorkload))
+	out.MaxEvictionsPerNamespace = (*int32)(unsafe.Pointer(in.MaxEvictionsPerNamespace))
+	out.MaxEvictionsPerWorkload = (*intstr.IntOrString)(unsafe.Pointer(in.MaxEvictionsPerWorkload))
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.EvictionBudgetWindow, &out.EvictionBudgetWindow, s); err != nil {
+		return err
+	}
 	if err := v1.Convert_int32_To_Pointer_int32(&in.MaxConcurrentReconciles, &out.MaxConcurrentReconciles, s); err != nil {
 		return err
 	}
 	out.DefaultJobMode = in.DefaultJobMode
+	out.ReservationFirstWorkloadKinds = *(*[]string)(unsafe.Pointer(&in.ReservationFirstWorkloadKinds))
+	out.EnablePreMigrationHook = in.EnablePreMigrationHook
 	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.DefaultJobTTL, &out.DefaultJobTTL, s); err != nil {
 		return err
 	}
@@ -514,6 +574,32 @@ func Convert_config_PriorityThreshold_To_v1alpha2_PriorityThreshold(in *config.P
 	return autoConvert_config_PriorityThreshold_To_v1alpha2_PriorityThreshold(in, out, s)
 }
 
+func autoConvert_v1alpha2_QuotaRebalancingArgs_To_config_QuotaRebalancingArgs(in *QuotaRebalancingArgs, out *config.QuotaRebalancingArgs, s conversion.Scope) error {
+	out.Namespaces = (*config.Namespaces)(unsafe.Pointer(in.Namespaces))
+	out.LabelSelector = (*v1.LabelSelector)(unsafe.Pointer(in.LabelSelector))
+	out.OverusedThresholdPercent = (*int32)(unsafe.Pointer(in.OverusedThresholdPercent))
+	out.MaxPodsToEvictPerQuota = (*int32)(unsafe.Pointer(in.MaxPodsToEvictPerQuota))
+	return nil
+}
+
+// Convert_v1alpha2_QuotaRebalancingArgs_To_config_QuotaRebalancingArgs is an autogenerated conversion function.
+func Convert_v1alpha2_QuotaRebalancingArgs_To_config_QuotaRebalancingArgs(in *QuotaRebalancingArgs, out *config.QuotaRebalancingArgs, s conversion.Scope) error {
+	return autoConvert_v1alpha2_QuotaRebalancingArgs_To_config_QuotaRebalancingArgs(in, out, s)
+}
+
+func autoConvert_config_QuotaRebalancingArgs_To_v1alpha2_QuotaRebalancingArgs(in *config.QuotaRebalancingArgs, out *QuotaRebalancingArgs, s conversion.Scope) error {
+	out.Namespaces = (*Namespaces)(unsafe.Pointer(in.Namespaces))
+	out.LabelSelector = (*v1.LabelSelector)(unsafe.Pointer(in.LabelSelector))
+	out.OverusedThresholdPercent = (*int32)(unsafe.Pointer(in.OverusedThresholdPercent))
+	out.MaxPodsToEvictPerQuota = (*int32)(unsafe.Pointer(in.MaxPodsToEvictPerQuota))
+	return nil
+}
+
+// Convert_config_QuotaRebalancingArgs_To_v1alpha2_QuotaRebalancingArgs is an autogenerated conversion function.
+func Convert_config_QuotaRebalancingArgs_To_v1alpha2_QuotaRebalancingArgs(in *config.QuotaRebalancingArgs, out *QuotaRebalancingArgs, s conversion.Scope) error {
+	return autoConvert_config_QuotaRebalancingArgs_To_v1alpha2_QuotaRebalancingArgs(in, out, s)
+}
+
 func autoConvert_v1alpha2_RemovePodsViolatingNodeAffinityArgs_To_config_RemovePodsViolatingNodeAffinityArgs(in *RemovePodsViolatingNodeAffinityArgs, out *config.RemovePodsViolatingNodeAffinityArgs, s conversion.Scope) error {
 	out.Namespaces = (*config.Namespaces)(unsafe.Pointer(in.Namespaces))
 	out.LabelSelector = (*v1.LabelSelector)(unsafe.Pointer(in.LabelSelector))
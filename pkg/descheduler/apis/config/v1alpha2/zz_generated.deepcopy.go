@@ -156,6 +156,46 @@ func (in *DeschedulerProfile) DeepCopy() *DeschedulerProfile {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUDefragmentationArgs) DeepCopyInto(out *GPUDefragmentationArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = new(Namespaces)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxPodsToEvictPerNode != nil {
+		in, out := &in.MaxPodsToEvictPerNode, &out.MaxPodsToEvictPerNode
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUDefragmentationArgs.
+func (in *GPUDefragmentationArgs) DeepCopy() *GPUDefragmentationArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUDefragmentationArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GPUDefragmentationArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MigrationControllerArgs) DeepCopyInto(out *MigrationControllerArgs) {
 	*out = *in
@@ -195,6 +235,26 @@ func (in *MigrationControllerArgs) DeepCopyInto(out *MigrationControllerArgs) {
 		*out = new(intstr.IntOrString)
 		**out = **in
 	}
+	if in.MaxEvictionsPerNamespace != nil {
+		in, out := &in.MaxEvictionsPerNamespace, &out.MaxEvictionsPerNamespace
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxEvictionsPerWorkload != nil {
+		in, out := &in.MaxEvictionsPerWorkload, &out.MaxEvictionsPerWorkload
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.EvictionBudgetWindow != nil {
+		in, out := &in.EvictionBudgetWindow, &out.EvictionBudgetWindow
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ReservationFirstWorkloadKinds != nil {
+		in, out := &in.ReservationFirstWorkloadKinds, &out.ReservationFirstWorkloadKinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.DefaultJobTTL != nil {
 		in, out := &in.DefaultJobTTL, &out.DefaultJobTTL
 		*out = new(v1.Duration)
@@ -351,6 +411,51 @@ func (in *PriorityThreshold) DeepCopy() *PriorityThreshold {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaRebalancingArgs) DeepCopyInto(out *QuotaRebalancingArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = new(Namespaces)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OverusedThresholdPercent != nil {
+		in, out := &in.OverusedThresholdPercent, &out.OverusedThresholdPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxPodsToEvictPerQuota != nil {
+		in, out := &in.MaxPodsToEvictPerQuota, &out.MaxPodsToEvictPerQuota
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaRebalancingArgs.
+func (in *QuotaRebalancingArgs) DeepCopy() *QuotaRebalancingArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaRebalancingArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *QuotaRebalancingArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RemovePodsViolatingNodeAffinityArgs) DeepCopyInto(out *RemovePodsViolatingNodeAffinityArgs) {
 	*out = *in
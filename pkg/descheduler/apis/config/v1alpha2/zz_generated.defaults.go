@@ -31,7 +31,9 @@ import (
 func RegisterDefaults(scheme *runtime.Scheme) error {
 	scheme.AddTypeDefaultingFunc(&DefaultEvictorArgs{}, func(obj interface{}) { SetObjectDefaults_DefaultEvictorArgs(obj.(*DefaultEvictorArgs)) })
 	scheme.AddTypeDefaultingFunc(&DeschedulerConfiguration{}, func(obj interface{}) { SetObjectDefaults_DeschedulerConfiguration(obj.(*DeschedulerConfiguration)) })
+	scheme.AddTypeDefaultingFunc(&GPUDefragmentationArgs{}, func(obj interface{}) { SetObjectDefaults_GPUDefragmentationArgs(obj.(*GPUDefragmentationArgs)) })
 	scheme.AddTypeDefaultingFunc(&MigrationControllerArgs{}, func(obj interface{}) { SetObjectDefaults_MigrationControllerArgs(obj.(*MigrationControllerArgs)) })
+	scheme.AddTypeDefaultingFunc(&QuotaRebalancingArgs{}, func(obj interface{}) { SetObjectDefaults_QuotaRebalancingArgs(obj.(*QuotaRebalancingArgs)) })
 	scheme.AddTypeDefaultingFunc(&RemovePodsViolatingNodeAffinityArgs{}, func(obj interface{}) {
 		SetObjectDefaults_RemovePodsViolatingNodeAffinityArgs(obj.(*RemovePodsViolatingNodeAffinityArgs))
 	})
@@ -46,10 +48,18 @@ func SetObjectDefaults_DeschedulerConfiguration(in *DeschedulerConfiguration) {
 	SetDefaults_DeschedulerConfiguration(in)
 }
 
+func SetObjectDefaults_GPUDefragmentationArgs(in *GPUDefragmentationArgs) {
+	SetDefaults_GPUDefragmentationArgs(in)
+}
+
 func SetObjectDefaults_MigrationControllerArgs(in *MigrationControllerArgs) {
 	SetDefaults_MigrationControllerArgs(in)
 }
 
+func SetObjectDefaults_QuotaRebalancingArgs(in *QuotaRebalancingArgs) {
+	SetDefaults_QuotaRebalancingArgs(in)
+}
+
 func SetObjectDefaults_RemovePodsViolatingNodeAffinityArgs(in *RemovePodsViolatingNodeAffinityArgs) {
 	SetDefaults_RemovePodsViolatingNodeAffinityArgs(in)
 }
@@ -81,6 +81,8 @@ func validatePluginConfig(path *field.Path, profile *config.DeschedulerProfile)
 		// NOTE: you can add the in-tree plugins configuration validation function
 		names.MigrationController:         ValidateMigrationControllerArgs,
 		"RemovePodsViolatingNodeAffinity": ValidateRemovePodsViolatingNodeAffinityArgs,
+		"GPUDefragmentation":              ValidateGPUDefragmentationArgs,
+		"QuotaRebalancing":                ValidateQuotaRebalancingArgs,
 	}
 
 	seenPluginConfig := make(sets.String)
@@ -45,6 +45,49 @@ func ValidateRemovePodsViolatingNodeAffinityArgs(path *field.Path, args *desched
 	return allErrs.ToAggregate()
 }
 
+func ValidateGPUDefragmentationArgs(path *field.Path, args *deschedulerconfig.GPUDefragmentationArgs) error {
+	var allErrs field.ErrorList
+
+	if args.MaxPodsToEvictPerNode != nil && *args.MaxPodsToEvictPerNode < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxPodsToEvictPerNode"), *args.MaxPodsToEvictPerNode, "maxPodsToEvictPerNode should be greater or equal 0"))
+	}
+	if args.LabelSelector != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(args.LabelSelector, field.NewPath("labelSelector"))...)
+	}
+	// At most one of include/exclude can be set
+	if args.Namespaces != nil && len(args.Namespaces.Include) > 0 && len(args.Namespaces.Exclude) > 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("namespaces"), args.Namespaces, "only one of Include/Exclude namespaces can be set"))
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return allErrs.ToAggregate()
+}
+
+func ValidateQuotaRebalancingArgs(path *field.Path, args *deschedulerconfig.QuotaRebalancingArgs) error {
+	var allErrs field.ErrorList
+
+	if args.OverusedThresholdPercent != nil && *args.OverusedThresholdPercent < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("overusedThresholdPercent"), *args.OverusedThresholdPercent, "overusedThresholdPercent should be greater or equal 0"))
+	}
+	if args.MaxPodsToEvictPerQuota != nil && *args.MaxPodsToEvictPerQuota < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxPodsToEvictPerQuota"), *args.MaxPodsToEvictPerQuota, "maxPodsToEvictPerQuota should be greater or equal 0"))
+	}
+	if args.LabelSelector != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(args.LabelSelector, field.NewPath("labelSelector"))...)
+	}
+	// At most one of include/exclude can be set
+	if args.Namespaces != nil && len(args.Namespaces.Include) > 0 && len(args.Namespaces.Exclude) > 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("namespaces"), args.Namespaces, "only one of Include/Exclude namespaces can be set"))
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return allErrs.ToAggregate()
+}
+
 func ValidateMigrationControllerArgs(path *field.Path, args *deschedulerconfig.MigrationControllerArgs) error {
 	var allErrs field.ErrorList
 
@@ -68,6 +111,20 @@ func ValidateMigrationControllerArgs(path *field.Path, args *deschedulerconfig.M
 		}
 	}
 
+	if args.MaxEvictionsPerNamespace != nil && *args.MaxEvictionsPerNamespace < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxEvictionsPerNamespace"), *args.MaxEvictionsPerNamespace, "maxEvictionsPerNamespace should be greater or equal 0"))
+	}
+
+	if args.MaxEvictionsPerWorkload != nil {
+		if _, err := intstr.GetScaledValueFromIntOrPercent(args.MaxEvictionsPerWorkload, 100, true); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("maxEvictionsPerWorkload"), *args.MaxEvictionsPerWorkload, fmt.Sprintf("maxEvictionsPerWorkload is invalid, err: %v ", err)))
+		}
+	}
+
+	if (args.MaxEvictionsPerNamespace != nil || args.MaxEvictionsPerWorkload != nil) && args.EvictionBudgetWindow.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("evictionBudgetWindow"), args.EvictionBudgetWindow, "evictionBudgetWindow should be positive when set eviction budgets"))
+	}
+
 	if args.EvictQPS != "" {
 		evictQPS, err := strconv.ParseFloat(args.EvictQPS, 64)
 		if err != nil {
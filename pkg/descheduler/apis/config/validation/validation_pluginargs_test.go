@@ -122,6 +122,21 @@ func TestValidateMigrationControllerArgs(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid maxEvictionsPerNamespace",
+			args: &v1alpha2.MigrationControllerArgs{
+				MaxEvictionsPerNamespace: pointer.Int32(-1),
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid evictionBudgetWindow",
+			args: &v1alpha2.MigrationControllerArgs{
+				MaxEvictionsPerNamespace: pointer.Int32(10),
+				EvictionBudgetWindow:     &metav1.Duration{Duration: -time.Hour},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
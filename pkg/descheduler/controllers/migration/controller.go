@@ -54,6 +54,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/options"
 	evictionsutil "github.com/koordinator-sh/koordinator/pkg/descheduler/evictions"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/metrics"
 	podutil "github.com/koordinator-sh/koordinator/pkg/descheduler/pod"
 	utilclient "github.com/koordinator-sh/koordinator/pkg/util/client"
 )
@@ -61,6 +62,9 @@ import (
 const (
 	Name                = names.MigrationController
 	defaultRequeueAfter = 3 * time.Second
+	// disruptionRequeueAfter is the interval to retry the eviction blocked by the PodDisruptionBudgets
+	// or the pre-migration hooks.
+	disruptionRequeueAfter = 10 * time.Second
 )
 
 var _ framework.Evictor = &Reconciler{}
@@ -75,6 +79,7 @@ type Reconciler struct {
 	unretriablePodFilter   framework.FilterFunc
 	retriablePodFilter     framework.FilterFunc
 	assumedCache           *assumedCache
+	evictionBudget         *evictionBudget
 	clock                  clock.Clock
 }
 
@@ -145,6 +150,7 @@ func newReconciler(args *deschedulerconfig.MigrationControllerArgs, handle frame
 		args.IgnorePvcPods,
 		args.EvictFailedBarePods,
 		evictionsutil.WithLabelSelector(selector),
+		evictionsutil.WithGangProtector(evictionsutil.NewGangProtector(handle.SharedInformerFactory().Core().V1().Pods().Lister())),
 	)
 
 	var includedNamespaces, excludedNamespaces sets.String
@@ -176,6 +182,7 @@ func newReconciler(args *deschedulerconfig.MigrationControllerArgs, handle frame
 		controllerFinder:       controllerFinder,
 		unretriablePodFilter:   podFilter,
 		assumedCache:           newAssumedCache(),
+		evictionBudget:         newEvictionBudget(args.EvictionBudgetWindow.Duration),
 		clock:                  clock.RealClock{},
 	}
 
@@ -183,6 +190,8 @@ func newReconciler(args *deschedulerconfig.MigrationControllerArgs, handle frame
 		r.filterMaxMigratingPerNode,
 		r.filterMaxMigratingPerNamespace,
 		r.filterMaxMigratingOrUnavailablePerWorkload,
+		r.filterMaxEvictionsPerNamespace,
+		r.filterMaxEvictionsPerWorkload,
 	)
 
 	err = manager.Add(r)
@@ -212,16 +221,18 @@ func (r *Reconciler) Filter(pod *corev1.Pod) bool {
 
 // Evict evicts a pod (no pre-check performed)
 func (r *Reconciler) Evict(ctx context.Context, pod *corev1.Pod, evictOptions framework.EvictOptions) bool {
-	if r.args.DryRun {
-		klog.Infof("%s Try to evict pod %s/%s by dryRun mode caused by %s", evictOptions.PluginName, pod.Namespace, pod.Name, evictOptions.Reason)
-		return true
-	}
-
 	if !r.Filter(pod) {
 		klog.Errorf("Pod %s/%s can not be evicted", pod.Namespace, pod.Name)
 		return false
 	}
 
+	if r.args.DryRun {
+		// record the pods would be migrated to evaluate the strategies before enabling them
+		metrics.PodsEvicted.With(map[string]string{"result": "dry run", "strategy": evictOptions.PluginName, "namespace": pod.Namespace, "node": pod.Spec.NodeName}).Inc()
+		klog.Infof("%s Try to evict pod %s/%s by dryRun mode caused by %s", evictOptions.PluginName, pod.Namespace, pod.Name, evictOptions.Reason)
+		return true
+	}
+
 	if evictOptions.DeleteOptions == nil {
 		evictOptions.DeleteOptions = r.args.DefaultDeleteOptions
 	}
@@ -235,7 +246,7 @@ func (r *Reconciler) Evict(ctx context.Context, pod *corev1.Pod, evictOptions fr
 				Name:      pod.Name,
 				UID:       pod.UID,
 			},
-			Mode:          sev1alpha1.PodMigrationJobMode(r.args.DefaultJobMode),
+			Mode:          r.getJobMode(pod),
 			TTL:           r.args.DefaultJobTTL.DeepCopy(),
 			DeleteOptions: evictOptions.DeleteOptions,
 		},
@@ -251,7 +262,23 @@ func (r *Reconciler) Evict(ctx context.Context, pod *corev1.Pod, evictOptions fr
 	return true
 }
 
+// getJobMode returns the PodMigrationJobMode of the Pod, the Pods of ReservationFirstWorkloadKinds
+// are always migrated by reservation before eviction.
+func (r *Reconciler) getJobMode(pod *corev1.Pod) sev1alpha1.PodMigrationJobMode {
+	if ownerRef := metav1.GetControllerOf(pod); ownerRef != nil {
+		for _, kind := range r.args.ReservationFirstWorkloadKinds {
+			if ownerRef.Kind == kind {
+				return sev1alpha1.PodMigrationJobModeReservationFirst
+			}
+		}
+	}
+	return sev1alpha1.PodMigrationJobMode(r.args.DefaultJobMode)
+}
+
 func (r *Reconciler) Start(ctx context.Context) error {
+	if err := r.rebuildEvictionBudget(ctx); err != nil {
+		klog.Errorf("Failed to rebuild the eviction budgets, err: %v", err)
+	}
 	r.scavenger(ctx.Done())
 	return nil
 }
@@ -499,6 +526,12 @@ func (r *Reconciler) preparePodRef(ctx context.Context, job *sev1alpha1.PodMigra
 		return false, err
 	}
 	job.Spec.PodRef.UID = pod.UID
+	if ownerRef := metav1.GetControllerOf(&pod); ownerRef != nil {
+		if job.Annotations == nil {
+			job.Annotations = map[string]string{}
+		}
+		job.Annotations[AnnotationWorkloadUID] = string(ownerRef.UID)
+	}
 	return true, nil
 }
 
@@ -676,6 +709,10 @@ func (r *Reconciler) abortJobIfReserveOnSameNode(ctx context.Context, job *sev1a
 
 func (r *Reconciler) abortJobByReservationUnschedulable(ctx context.Context, job *sev1alpha1.PodMigrationJob, reservationObj reservation.Object) error {
 	klog.V(4).Infof("MigrationJob %s stop migration because Reservation %q cannot be scheduled", job.Name, reservationObj)
+	// the Pod is never evicted without the reserved resources, release the Reservation to not block the others
+	if err := r.deleteReservation(ctx, job); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
 	var message string
 	unschedulableCond := reservation.GetUnschedulableCondition(reservationObj)
 	if unschedulableCond != nil {
@@ -684,7 +721,11 @@ func (r *Reconciler) abortJobByReservationUnschedulable(ctx context.Context, job
 	job.Status.Phase = sev1alpha1.PodMigrationJobFailed
 	job.Status.Reason = sev1alpha1.PodMigrationJobReasonUnschedulable
 	job.Status.Message = message
-	return r.Client.Status().Update(ctx, job)
+	err := r.Client.Status().Update(ctx, job)
+	if err == nil {
+		r.eventRecorder.Eventf(job, nil, corev1.EventTypeWarning, sev1alpha1.PodMigrationJobReasonUnschedulable, "Migrating", "Cancel eviction of Pod %s/%s because Reservation %q cannot be scheduled", job.Spec.PodRef.Namespace, job.Spec.PodRef.Name, reservationObj)
+	}
+	return err
 }
 
 func (r *Reconciler) syncReservationScheduleFailed(ctx context.Context, job *sev1alpha1.PodMigrationJob, reservationObj reservation.Object) error {
@@ -791,14 +832,34 @@ func (r *Reconciler) evictPod(ctx context.Context, job *sev1alpha1.PodMigrationJ
 		return false, reconcile.Result{}, err
 	}
 
+	ready, message, err := r.checkPreMigrationHook(ctx, job, pod)
+	if err != nil {
+		return false, reconcile.Result{}, err
+	} else if !ready {
+		return r.waitForDisruptionAllowed(ctx, job, sev1alpha1.PodMigrationJobReasonWaitForPreMigrationHook, message)
+	}
+
+	allowed, message, err := r.checkPodDisruptionBudgets(ctx, pod)
+	if err != nil {
+		return false, reconcile.Result{}, err
+	} else if !allowed {
+		return r.waitForDisruptionAllowed(ctx, job, sev1alpha1.PodMigrationJobReasonWaitForPodDisruptionBudget, message)
+	}
+
 	if job.Spec.DeleteOptions == nil {
 		job.Spec.DeleteOptions = r.args.DefaultDeleteOptions
 	}
 	err = r.evictorInterpreter.Evict(ctx, job, pod)
+	if errors.IsTooManyRequests(err) {
+		// the eviction API rejects the eviction since the budgets are exhausted, retry later
+		message = fmt.Sprintf("Pod %q can't be evicted caused by %v", podNamespacedName, err)
+		return r.waitForDisruptionAllowed(ctx, job, sev1alpha1.PodMigrationJobReasonWaitForPodDisruptionBudget, message)
+	}
 	if err != nil {
 		r.eventRecorder.Eventf(job, nil, corev1.EventTypeWarning, sev1alpha1.PodMigrationJobReasonEvicting, "Migrating", "Failed evict Pod %q caused by %v", podNamespacedName, err)
 		return false, reconcile.Result{}, err
 	}
+	r.evictionBudget.track(pod, r.clock.Now())
 
 	cond = &sev1alpha1.PodMigrationJobCondition{
 		Type:    sev1alpha1.PodMigrationJobConditionEviction,
@@ -813,6 +874,26 @@ func (r *Reconciler) evictPod(ctx context.Context, job *sev1alpha1.PodMigrationJ
 	return false, reconcile.Result{RequeueAfter: defaultRequeueAfter}, err
 }
 
+// waitForDisruptionAllowed records why the Pod can't be evicted now and requeues the job to retry the eviction.
+func (r *Reconciler) waitForDisruptionAllowed(ctx context.Context, job *sev1alpha1.PodMigrationJob, reason, message string) (bool, reconcile.Result, error) {
+	klog.V(4).Infof("MigrationJob %s is waiting to evict Pod, reason: %s, message: %s", job.Name, reason, message)
+	_, cond := util.GetCondition(&job.Status, sev1alpha1.PodMigrationJobConditionEviction)
+	if cond != nil && cond.Reason == reason && cond.Message == message {
+		return false, reconcile.Result{RequeueAfter: disruptionRequeueAfter}, nil
+	}
+	cond = &sev1alpha1.PodMigrationJobCondition{
+		Type:    sev1alpha1.PodMigrationJobConditionEviction,
+		Status:  sev1alpha1.PodMigrationJobConditionStatusFalse,
+		Reason:  reason,
+		Message: message,
+	}
+	err := r.updateCondition(ctx, job, cond)
+	if err == nil {
+		r.eventRecorder.Eventf(job, nil, corev1.EventTypeNormal, reason, "Migrating", message)
+	}
+	return false, reconcile.Result{RequeueAfter: disruptionRequeueAfter}, err
+}
+
 func (r *Reconciler) prepareJobWithReservationScheduleSuccess(ctx context.Context, job *sev1alpha1.PodMigrationJob, reservationObj reservation.Object) error {
 	scheduledNodeName := reservationObj.GetScheduledNodeName()
 	if scheduledNodeName == "" || job.Status.NodeName != "" {
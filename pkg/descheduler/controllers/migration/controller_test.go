@@ -34,6 +34,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/uuid"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/component-base/metrics/testutil"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -47,6 +48,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/util"
 	evictionsutil "github.com/koordinator-sh/koordinator/pkg/descheduler/evictions"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/metrics"
 	podutil "github.com/koordinator-sh/koordinator/pkg/descheduler/pod"
 )
 
@@ -148,6 +150,7 @@ func newTestReconciler() *Reconciler {
 		controllerFinder:       controllerFinder,
 		unretriablePodFilter:   podFilter,
 		assumedCache:           newAssumedCache(),
+		evictionBudget:         newEvictionBudget(args.EvictionBudgetWindow.Duration),
 		clock:                  clock.RealClock{},
 	}
 
@@ -1394,6 +1397,80 @@ func TestEvict(t *testing.T) {
 	assert.Equal(t, expectPodRef, jobList.Items[0].Spec.PodRef)
 }
 
+func TestEvictInDryRun(t *testing.T) {
+	metrics.Register()
+	reconciler := newTestReconciler()
+	reconciler.args.DryRun = true
+	filtered := false
+	reconciler.retriablePodFilter = func(pod *corev1.Pod) bool {
+		return !filtered
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "test-pod",
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "apps/v1",
+					Controller: pointer.Bool(true),
+					Kind:       "ReplicaSet",
+					Name:       "test",
+					UID:        "2f96233d-a6b9-4981-b594-7c90c987aed9",
+				},
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "test-node-1",
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+		},
+	}
+	dryRunEvicted := metrics.PodsEvicted.WithLabelValues("dry run", "test-plugin", "test", "test-node-1")
+	before, err := testutil.GetCounterMetricValue(dryRunEvicted)
+	assert.NoError(t, err)
+
+	// the dry run reports only the pods would be migrated, no job is created
+	assert.True(t, reconciler.Evict(context.TODO(), pod, framework.EvictOptions{PluginName: "test-plugin"}))
+	var jobList sev1alpha1.PodMigrationJobList
+	assert.NoError(t, reconciler.Client.List(context.TODO(), &jobList))
+	assert.Empty(t, jobList.Items)
+	after, err := testutil.GetCounterMetricValue(dryRunEvicted)
+	assert.NoError(t, err)
+	assert.Equal(t, before+1, after)
+
+	// the pods rejected by the filters are not reported as evicted
+	filtered = true
+	assert.False(t, reconciler.Evict(context.TODO(), pod, framework.EvictOptions{PluginName: "test-plugin"}))
+	rejected, err := testutil.GetCounterMetricValue(dryRunEvicted)
+	assert.NoError(t, err)
+	assert.Equal(t, after, rejected)
+}
+
+func TestGetJobMode(t *testing.T) {
+	reconciler := newTestReconciler()
+	reconciler.args.DefaultJobMode = string(sev1alpha1.PodMigrationJobModeEvictionDirectly)
+	reconciler.args.ReservationFirstWorkloadKinds = []string{"StatefulSet"}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "test-pod",
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					Controller: pointer.Bool(true),
+					Kind:       "Deployment",
+					Name:       "test",
+				},
+			},
+		},
+	}
+	assert.Equal(t, sev1alpha1.PodMigrationJobModeEvictionDirectly, reconciler.getJobMode(pod))
+
+	pod.OwnerReferences[0].Kind = "StatefulSet"
+	assert.Equal(t, sev1alpha1.PodMigrationJobModeReservationFirst, reconciler.getJobMode(pod))
+}
+
 func TestAbortJobIfReserveOnSameNode(t *testing.T) {
 	reconciler := newTestReconciler()
 	pod := &corev1.Pod{
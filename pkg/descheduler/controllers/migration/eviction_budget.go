@@ -0,0 +1,145 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/util"
+)
+
+const (
+	// AnnotationWorkloadUID records the UID of the workload controlling the Pod of the PodMigrationJob, which
+	// rebuilds the eviction budget of the workload after the Pod is evicted.
+	AnnotationWorkloadUID = "koordinator.sh/migration-workload-uid"
+)
+
+// evictionBudget tracks the evictions of each namespace and workload in a sliding window,
+// which limits the rate of the evictions rather than the number of the concurrent migrations.
+type evictionBudget struct {
+	lock      sync.Mutex
+	window    time.Duration
+	evictions map[string][]time.Time
+}
+
+func newEvictionBudget(window time.Duration) *evictionBudget {
+	return &evictionBudget{
+		window:    window,
+		evictions: map[string][]time.Time{},
+	}
+}
+
+func namespaceBudgetKey(namespace string) string {
+	return "namespace/" + namespace
+}
+
+func workloadBudgetKey(uid types.UID) string {
+	return "workload/" + string(uid)
+}
+
+// count returns the number of the evictions of the key in the window before now.
+func (b *evictionBudget) count(key string, now time.Time) int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return len(b.pruneLocked(key, now))
+}
+
+// track records the eviction of the pod at now to the budgets of its namespace and workload.
+func (b *evictionBudget) track(pod *corev1.Pod, now time.Time) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	keys := []string{namespaceBudgetKey(pod.Namespace)}
+	if ownerRef := metav1.GetControllerOf(pod); ownerRef != nil {
+		keys = append(keys, workloadBudgetKey(ownerRef.UID))
+	}
+	for _, key := range keys {
+		b.evictions[key] = append(b.pruneLocked(key, now), now)
+	}
+}
+
+// restore records the eviction in the namespace and the workload at evictedAt, which may be earlier than
+// the evictions tracked, the evictions out of the window before now are dropped.
+func (b *evictionBudget) restore(namespace string, workloadUID types.UID, evictedAt, now time.Time) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	keys := []string{namespaceBudgetKey(namespace)}
+	if workloadUID != "" {
+		keys = append(keys, workloadBudgetKey(workloadUID))
+	}
+	for _, key := range keys {
+		evictions := append(b.evictions[key], evictedAt)
+		sort.Slice(evictions, func(i, j int) bool {
+			return evictions[i].Before(evictions[j])
+		})
+		b.evictions[key] = evictions
+		b.pruneLocked(key, now)
+	}
+}
+
+func (b *evictionBudget) pruneLocked(key string, now time.Time) []time.Time {
+	evictions := b.evictions[key]
+	i := 0
+	for i < len(evictions) && now.Sub(evictions[i]) >= b.window {
+		i++
+	}
+	evictions = evictions[i:]
+	if len(evictions) == 0 {
+		delete(b.evictions, key)
+		return nil
+	}
+	b.evictions[key] = evictions
+	return evictions
+}
+
+// rebuildEvictionBudget restores the evictions in the window from the PodMigrationJobs completing the eviction,
+// so the budgets are not reset by restarting the descheduler.
+func (r *Reconciler) rebuildEvictionBudget(ctx context.Context) error {
+	jobList := &sev1alpha1.PodMigrationJobList{}
+	if err := r.Client.List(ctx, jobList, &client.ListOptions{}); err != nil {
+		return err
+	}
+	now := r.clock.Now()
+	restored := 0
+	for i := range jobList.Items {
+		job := &jobList.Items[i]
+		if job.Spec.PodRef == nil {
+			continue
+		}
+		_, cond := util.GetCondition(&job.Status, sev1alpha1.PodMigrationJobConditionEviction)
+		if cond == nil || cond.Status != sev1alpha1.PodMigrationJobConditionStatusTrue ||
+			cond.Reason != sev1alpha1.PodMigrationJobReasonEvictComplete {
+			continue
+		}
+		if now.Sub(cond.LastTransitionTime.Time) >= r.evictionBudget.window {
+			continue
+		}
+		r.evictionBudget.restore(job.Spec.PodRef.Namespace, types.UID(job.Annotations[AnnotationWorkloadUID]), cond.LastTransitionTime.Time, now)
+		restored++
+	}
+	klog.V(4).Infof("Rebuilt the eviction budgets from %d evictions in %v", restored, r.evictionBudget.window)
+	return nil
+}
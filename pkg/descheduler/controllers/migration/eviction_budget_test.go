@@ -0,0 +1,139 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/utils/pointer"
+
+	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+func TestEvictionBudget(t *testing.T) {
+	now := time.Now()
+	budget := newEvictionBudget(time.Hour)
+	ownerRef := metav1.OwnerReference{
+		APIVersion: "apps/v1",
+		Controller: pointer.Bool(true),
+		Kind:       "StatefulSet",
+		Name:       "test",
+		UID:        "2f96233d-a6b9-4981-b594-7c90c987aed9",
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "test-pod",
+			OwnerReferences: []metav1.OwnerReference{ownerRef},
+		},
+	}
+
+	budget.track(pod, now)
+	budget.track(pod, now.Add(30*time.Minute))
+	assert.Equal(t, 2, budget.count(namespaceBudgetKey("default"), now.Add(30*time.Minute)))
+	assert.Equal(t, 2, budget.count(workloadBudgetKey(ownerRef.UID), now.Add(30*time.Minute)))
+	assert.Equal(t, 0, budget.count(namespaceBudgetKey("other"), now))
+
+	// the first eviction slides out of the window
+	assert.Equal(t, 1, budget.count(namespaceBudgetKey("default"), now.Add(time.Hour)))
+	assert.Equal(t, 0, budget.count(namespaceBudgetKey("default"), now.Add(90*time.Minute)))
+	assert.Empty(t, budget.evictions[namespaceBudgetKey("default")])
+}
+
+func TestFilterMaxEvictionsPerNamespace(t *testing.T) {
+	reconciler := newTestReconciler()
+	reconciler.args.MaxEvictionsPerNamespace = pointer.Int32(2)
+	fakeClock := clock.NewFakeClock(time.Now())
+	reconciler.clock = fakeClock
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-pod",
+		},
+	}
+	assert.True(t, reconciler.filterMaxEvictionsPerNamespace(pod))
+	reconciler.evictionBudget.track(pod, fakeClock.Now())
+	assert.True(t, reconciler.filterMaxEvictionsPerNamespace(pod))
+	reconciler.evictionBudget.track(pod, fakeClock.Now())
+	assert.False(t, reconciler.filterMaxEvictionsPerNamespace(pod))
+
+	fakeClock.Step(reconciler.args.EvictionBudgetWindow.Duration)
+	assert.True(t, reconciler.filterMaxEvictionsPerNamespace(pod))
+}
+
+func TestRebuildEvictionBudget(t *testing.T) {
+	reconciler := newTestReconciler()
+	fakeClock := clock.NewFakeClock(time.Now())
+	reconciler.clock = fakeClock
+
+	workloadUID := "2f96233d-a6b9-4981-b594-7c90c987aed9"
+	jobs := []struct {
+		evictedBefore time.Duration
+		reason        string
+	}{
+		{evictedBefore: 10 * time.Minute, reason: sev1alpha1.PodMigrationJobReasonEvictComplete},
+		{evictedBefore: 30 * time.Minute, reason: sev1alpha1.PodMigrationJobReasonEvictComplete},
+		{evictedBefore: 2 * time.Hour, reason: sev1alpha1.PodMigrationJobReasonEvictComplete},
+		{evictedBefore: 10 * time.Minute, reason: sev1alpha1.PodMigrationJobReasonEvicting},
+	}
+	for i, v := range jobs {
+		status := sev1alpha1.PodMigrationJobConditionStatusTrue
+		if v.reason != sev1alpha1.PodMigrationJobReasonEvictComplete {
+			status = sev1alpha1.PodMigrationJobConditionStatusFalse
+		}
+		job := &sev1alpha1.PodMigrationJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        fmt.Sprintf("test-%d", i),
+				Annotations: map[string]string{AnnotationWorkloadUID: workloadUID},
+			},
+			Spec: sev1alpha1.PodMigrationJobSpec{
+				PodRef: &corev1.ObjectReference{
+					Namespace: "default",
+					Name:      fmt.Sprintf("test-pod-%d", i),
+				},
+			},
+			Status: sev1alpha1.PodMigrationJobStatus{
+				Phase: sev1alpha1.PodMigrationJobSucceeded,
+				Conditions: []sev1alpha1.PodMigrationJobCondition{
+					{
+						Type:               sev1alpha1.PodMigrationJobConditionEviction,
+						Status:             status,
+						Reason:             v.reason,
+						LastTransitionTime: metav1.NewTime(fakeClock.Now().Add(-v.evictedBefore)),
+					},
+				},
+			},
+		}
+		assert.NoError(t, reconciler.Client.Create(context.TODO(), job))
+	}
+
+	assert.NoError(t, reconciler.rebuildEvictionBudget(context.TODO()))
+	now := fakeClock.Now()
+	assert.Equal(t, 2, reconciler.evictionBudget.count(namespaceBudgetKey("default"), now))
+	assert.Equal(t, 2, reconciler.evictionBudget.count(workloadBudgetKey(types.UID(workloadUID)), now))
+	// the evictions restored slide out of the window by the time they completed
+	assert.Equal(t, 1, reconciler.evictionBudget.count(namespaceBudgetKey("default"), now.Add(40*time.Minute)))
+}
@@ -176,6 +176,47 @@ func (r *Reconciler) filterMaxMigratingOrUnavailablePerWorkload(pod *corev1.Pod)
 	return true
 }
 
+func (r *Reconciler) filterMaxEvictionsPerNamespace(pod *corev1.Pod) bool {
+	if r.args.MaxEvictionsPerNamespace == nil || *r.args.MaxEvictionsPerNamespace <= 0 {
+		return true
+	}
+	count := r.evictionBudget.count(namespaceBudgetKey(pod.Namespace), r.clock.Now())
+	if count >= int(*r.args.MaxEvictionsPerNamespace) {
+		klog.V(4).Infof("The namespace %s of Pod %s/%s has %d evictions in %v that exceed MaxEvictionsPerNamespace %d",
+			pod.Namespace, pod.Namespace, pod.Name, count, r.args.EvictionBudgetWindow.Duration, *r.args.MaxEvictionsPerNamespace)
+		return false
+	}
+	return true
+}
+
+func (r *Reconciler) filterMaxEvictionsPerWorkload(pod *corev1.Pod) bool {
+	if r.args.MaxEvictionsPerWorkload == nil {
+		return true
+	}
+	ownerRef := metav1.GetControllerOf(pod)
+	if ownerRef == nil {
+		return true
+	}
+	count := r.evictionBudget.count(workloadBudgetKey(ownerRef.UID), r.clock.Now())
+	if count == 0 {
+		return true
+	}
+	_, expectedReplicas, err := r.controllerFinder.GetPodsForRef(ownerRef.APIVersion, ownerRef.Kind, ownerRef.Name, pod.Namespace, nil, false)
+	if err != nil {
+		return false
+	}
+	maxEvictions, err := intstr.GetScaledValueFromIntOrPercent(r.args.MaxEvictionsPerWorkload, int(expectedReplicas), true)
+	if err != nil {
+		return false
+	}
+	if count >= maxEvictions {
+		klog.V(4).Infof("The workload %s(%s) of Pod %s/%s has %d evictions in %v that exceed MaxEvictionsPerWorkload %d",
+			ownerRef.Name, ownerRef.UID, pod.Namespace, pod.Name, count, r.args.EvictionBudgetWindow.Duration, maxEvictions)
+		return false
+	}
+	return true
+}
+
 func (r *Reconciler) exceedMaxMigratingReplicas(totalReplicas int, migratingReplicas int, maxMigrating *intstr.IntOrString) (bool, int, error) {
 	maxMigratingCount, err := util.GetMaxMigrating(totalReplicas, maxMigrating)
 	if err != nil {
@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8spodutil "k8s.io/kubernetes/pkg/api/v1/pod"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// AnnotationUnhealthyPodEvictionPolicy declares the UnhealthyPodEvictionPolicy of the PodDisruptionBudget,
+	// which is the same as the spec.unhealthyPodEvictionPolicy of Kubernetes 1.26+.
+	AnnotationUnhealthyPodEvictionPolicy = "koordinator.sh/unhealthy-pod-eviction-policy"
+)
+
+// UnhealthyPodEvictionPolicyType defines the criteria for when unhealthy pods should be considered for eviction.
+type UnhealthyPodEvictionPolicyType string
+
+const (
+	// IfHealthyBudget policy means that running pods (status.phase="Running"), but not yet healthy can be evicted
+	// only if the guarded application is not disrupted (status.currentHealthy is at least equal to
+	// status.desiredHealthy). Healthy pods will be subject to the PDB for eviction. It's the default policy.
+	IfHealthyBudget UnhealthyPodEvictionPolicyType = "IfHealthyBudget"
+	// AlwaysAllow policy means that all running pods (status.phase="Running"), but not yet healthy are considered
+	// disrupted and can be evicted regardless of whether the criteria in a PDB is met.
+	AlwaysAllow UnhealthyPodEvictionPolicyType = "AlwaysAllow"
+)
+
+func getUnhealthyPodEvictionPolicy(pdb *policyv1.PodDisruptionBudget) UnhealthyPodEvictionPolicyType {
+	if UnhealthyPodEvictionPolicyType(pdb.Annotations[AnnotationUnhealthyPodEvictionPolicy]) == AlwaysAllow {
+		return AlwaysAllow
+	}
+	return IfHealthyBudget
+}
+
+// checkPodDisruptionBudgets checks whether the PodDisruptionBudgets allow the Pod to be evicted, in the same way
+// as the eviction API, so that the Pods evicted by deletion respect the PodDisruptionBudgets too.
+// It returns the message about why the Pod can't be evicted if not allowed.
+func (r *Reconciler) checkPodDisruptionBudgets(ctx context.Context, pod *corev1.Pod) (bool, string, error) {
+	// the Pods not running don't disrupt the application
+	if pod.Status.Phase != corev1.PodRunning {
+		return true, "", nil
+	}
+
+	pdbList := &policyv1.PodDisruptionBudgetList{}
+	if err := r.Client.List(ctx, pdbList, client.InNamespace(pod.Namespace)); err != nil {
+		return false, "", err
+	}
+	var pdbs []*policyv1.PodDisruptionBudget
+	for i := range pdbList.Items {
+		pdb := &pdbList.Items[i]
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() || !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		pdbs = append(pdbs, pdb)
+	}
+	if len(pdbs) == 0 {
+		return true, "", nil
+	}
+	if len(pdbs) > 1 {
+		return false, fmt.Sprintf("Pod %s/%s is covered by %d PodDisruptionBudgets", pod.Namespace, pod.Name, len(pdbs)), nil
+	}
+
+	pdb := pdbs[0]
+	if pdb.Status.ObservedGeneration < pdb.Generation {
+		return false, fmt.Sprintf("PodDisruptionBudget %s/%s is not observed by the disruption controller yet", pdb.Namespace, pdb.Name), nil
+	}
+	if !k8spodutil.IsPodReady(pod) {
+		if getUnhealthyPodEvictionPolicy(pdb) == AlwaysAllow {
+			return true, "", nil
+		}
+		if pdb.Status.DesiredHealthy > 0 && pdb.Status.CurrentHealthy >= pdb.Status.DesiredHealthy {
+			return true, "", nil
+		}
+	}
+	if pdb.Status.DisruptionsAllowed > 0 {
+		return true, "", nil
+	}
+	return false, fmt.Sprintf("PodDisruptionBudget %s/%s allows no more disruptions", pdb.Namespace, pdb.Name), nil
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/util"
+)
+
+func TestCheckPodDisruptionBudgets(t *testing.T) {
+	newPod := func(phase corev1.PodPhase, ready bool) *corev1.Pod {
+		readyStatus := corev1.ConditionFalse
+		if ready {
+			readyStatus = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "test-pod",
+				Labels:    map[string]string{"app": "test"},
+			},
+			Status: corev1.PodStatus{
+				Phase: phase,
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodReady, Status: readyStatus},
+				},
+			},
+		}
+	}
+	newPDB := func(name string, status policyv1.PodDisruptionBudgetStatus, policy UnhealthyPodEvictionPolicyType) *policyv1.PodDisruptionBudget {
+		pdb := &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      name,
+			},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}},
+			},
+			Status: status,
+		}
+		if policy != "" {
+			pdb.Annotations = map[string]string{AnnotationUnhealthyPodEvictionPolicy: string(policy)}
+		}
+		return pdb
+	}
+	exhausted := policyv1.PodDisruptionBudgetStatus{CurrentHealthy: 2, DesiredHealthy: 3}
+
+	tests := []struct {
+		name    string
+		pod     *corev1.Pod
+		pdbs    []*policyv1.PodDisruptionBudget
+		allowed bool
+	}{
+		{
+			name:    "no PodDisruptionBudget",
+			pod:     newPod(corev1.PodRunning, true),
+			allowed: true,
+		},
+		{
+			name:    "pod not running",
+			pod:     newPod(corev1.PodPending, false),
+			pdbs:    []*policyv1.PodDisruptionBudget{newPDB("test-pdb", exhausted, "")},
+			allowed: true,
+		},
+		{
+			name: "disruptions allowed",
+			pod:  newPod(corev1.PodRunning, true),
+			pdbs: []*policyv1.PodDisruptionBudget{
+				newPDB("test-pdb", policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 1, CurrentHealthy: 3, DesiredHealthy: 2}, ""),
+			},
+			allowed: true,
+		},
+		{
+			name:    "budget exhausted",
+			pod:     newPod(corev1.PodRunning, true),
+			pdbs:    []*policyv1.PodDisruptionBudget{newPDB("test-pdb", exhausted, "")},
+			allowed: false,
+		},
+		{
+			name: "multiple PodDisruptionBudgets",
+			pod:  newPod(corev1.PodRunning, true),
+			pdbs: []*policyv1.PodDisruptionBudget{
+				newPDB("test-pdb-1", policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 1}, ""),
+				newPDB("test-pdb-2", policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 1}, ""),
+			},
+			allowed: false,
+		},
+		{
+			name: "unhealthy pod with healthy budget",
+			pod:  newPod(corev1.PodRunning, false),
+			pdbs: []*policyv1.PodDisruptionBudget{
+				newPDB("test-pdb", policyv1.PodDisruptionBudgetStatus{CurrentHealthy: 2, DesiredHealthy: 2}, ""),
+			},
+			allowed: true,
+		},
+		{
+			name:    "unhealthy pod with IfHealthyBudget",
+			pod:     newPod(corev1.PodRunning, false),
+			pdbs:    []*policyv1.PodDisruptionBudget{newPDB("test-pdb", exhausted, IfHealthyBudget)},
+			allowed: false,
+		},
+		{
+			name:    "unhealthy pod with AlwaysAllow",
+			pod:     newPod(corev1.PodRunning, false),
+			pdbs:    []*policyv1.PodDisruptionBudget{newPDB("test-pdb", exhausted, AlwaysAllow)},
+			allowed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reconciler := newTestReconciler()
+			for _, pdb := range tt.pdbs {
+				assert.Nil(t, reconciler.Client.Create(context.TODO(), pdb))
+			}
+			allowed, message, err := reconciler.checkPodDisruptionBudgets(context.TODO(), tt.pod)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.allowed, allowed)
+			assert.Equal(t, tt.allowed, message == "")
+		})
+	}
+}
+
+func TestEvictPodWaitForPodDisruptionBudget(t *testing.T) {
+	reconciler := newTestReconciler()
+	reconciler.evictorInterpreter = fakeEvictionInterpreter{}
+
+	job := &sev1alpha1.PodMigrationJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: sev1alpha1.PodMigrationJobSpec{
+			PodRef: &corev1.ObjectReference{
+				Namespace: "default",
+				Name:      "test-pod",
+			},
+		},
+	}
+	assert.Nil(t, reconciler.Create(context.TODO(), job))
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-pod",
+			Labels:    map[string]string{"app": "test"},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	assert.Nil(t, reconciler.Client.Create(context.TODO(), pod))
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-pdb"},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}},
+		},
+	}
+	assert.Nil(t, reconciler.Client.Create(context.TODO(), pdb))
+
+	evicted, result, err := reconciler.evictPod(context.TODO(), job)
+	assert.False(t, evicted)
+	assert.Equal(t, reconcile.Result{RequeueAfter: disruptionRequeueAfter}, result)
+	assert.Nil(t, err)
+	_, cond := util.GetCondition(&job.Status, sev1alpha1.PodMigrationJobConditionEviction)
+	assert.NotNil(t, cond)
+	assert.Equal(t, sev1alpha1.PodMigrationJobReasonWaitForPodDisruptionBudget, cond.Reason)
+
+	pdb.Status.DisruptionsAllowed = 1
+	assert.Nil(t, reconciler.Client.Status().Update(context.TODO(), pdb))
+	evicted, result, err = reconciler.evictPod(context.TODO(), job)
+	assert.False(t, evicted)
+	assert.Equal(t, reconcile.Result{RequeueAfter: defaultRequeueAfter}, result)
+	assert.Nil(t, err)
+	_, cond = util.GetCondition(&job.Status, sev1alpha1.PodMigrationJobConditionEviction)
+	assert.Equal(t, sev1alpha1.PodMigrationJobReasonEvicting, cond.Reason)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+const (
+	// AnnotationPreMigrationHookURL is the URL of the HTTP pre-migration hook of the Pod. The controller POSTs
+	// the PreMigrationHookRequest to the URL before evicting the Pod, and evicts it after the hook returns 2xx.
+	// The hook may be called more than once for a migration, so it should be idempotent.
+	AnnotationPreMigrationHookURL = "koordinator.sh/pre-migration-hook-url"
+	// AnnotationPreMigrationHandshake enables the annotation-driven pre-migration handshake of the Pod if "true".
+	// The controller annotates the Pod with AnnotationMigrationRequested, and evicts it after the workload
+	// annotates the Pod with AnnotationMigrationReady whose value is the same.
+	AnnotationPreMigrationHandshake = "koordinator.sh/pre-migration-handshake"
+	// AnnotationMigrationRequested is the name of the PodMigrationJob requesting to migrate the Pod.
+	AnnotationMigrationRequested = "koordinator.sh/migration-requested"
+	// AnnotationMigrationReady is written by the workload once the Pod is ready to be migrated.
+	AnnotationMigrationReady = "koordinator.sh/migration-ready"
+
+	defaultPreMigrationHookTimeout = 10 * time.Second
+)
+
+// PreMigrationHookRequest is the body of the request sent to the HTTP pre-migration hook.
+type PreMigrationHookRequest struct {
+	Namespace        string `json:"namespace"`
+	Name             string `json:"name"`
+	PodMigrationJob  string `json:"podMigrationJob"`
+	PodMigrationMode string `json:"podMigrationMode,omitempty"`
+}
+
+// checkPreMigrationHook calls the pre-migration hook declared by the Pod, and returns whether the workload is
+// ready to be migrated and the message about why not.
+func (r *Reconciler) checkPreMigrationHook(ctx context.Context, job *sev1alpha1.PodMigrationJob, pod *corev1.Pod) (bool, string, error) {
+	if !r.args.EnablePreMigrationHook {
+		return true, "", nil
+	}
+	if url := pod.Annotations[AnnotationPreMigrationHookURL]; url != "" {
+		if err := r.callPreMigrationHook(ctx, url, job, pod); err != nil {
+			return false, fmt.Sprintf("Pod %s/%s is not ready to be migrated, pre-migration hook: %v", pod.Namespace, pod.Name, err), nil
+		}
+	}
+	if pod.Annotations[AnnotationPreMigrationHandshake] == "true" {
+		if pod.Annotations[AnnotationMigrationReady] == job.Name {
+			return true, "", nil
+		}
+		if pod.Annotations[AnnotationMigrationRequested] != job.Name {
+			patch := client.MergeFrom(pod.DeepCopy())
+			if pod.Annotations == nil {
+				pod.Annotations = map[string]string{}
+			}
+			pod.Annotations[AnnotationMigrationRequested] = job.Name
+			if err := r.Client.Patch(ctx, pod, patch); err != nil {
+				return false, "", err
+			}
+		}
+		return false, fmt.Sprintf("Pod %s/%s is waiting for the workload to be ready to be migrated", pod.Namespace, pod.Name), nil
+	}
+	return true, "", nil
+}
+
+func (r *Reconciler) callPreMigrationHook(ctx context.Context, url string, job *sev1alpha1.PodMigrationJob, pod *corev1.Pod) error {
+	body, err := json.Marshal(&PreMigrationHookRequest{
+		Namespace:        pod.Namespace,
+		Name:             pod.Name,
+		PodMigrationJob:  job.Name,
+		PodMigrationMode: string(job.Spec.Mode),
+	})
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultPreMigrationHookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
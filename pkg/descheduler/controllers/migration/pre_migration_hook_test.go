@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+func TestCheckPreMigrationHookByHTTP(t *testing.T) {
+	ready := false
+	var got PreMigrationHookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&got))
+		if !ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	reconciler := newTestReconciler()
+	job := &sev1alpha1.PodMigrationJob{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "test-pod",
+			Annotations: map[string]string{AnnotationPreMigrationHookURL: server.URL},
+		},
+	}
+
+	// the hooks are ignored if not enabled
+	passed, _, err := reconciler.checkPreMigrationHook(context.TODO(), job, pod)
+	assert.Nil(t, err)
+	assert.True(t, passed)
+
+	reconciler.args.EnablePreMigrationHook = true
+	passed, message, err := reconciler.checkPreMigrationHook(context.TODO(), job, pod)
+	assert.Nil(t, err)
+	assert.False(t, passed)
+	assert.NotEmpty(t, message)
+	assert.Equal(t, PreMigrationHookRequest{Namespace: "default", Name: "test-pod", PodMigrationJob: "test"}, got)
+
+	ready = true
+	passed, _, err = reconciler.checkPreMigrationHook(context.TODO(), job, pod)
+	assert.Nil(t, err)
+	assert.True(t, passed)
+}
+
+func TestCheckPreMigrationHookByHandshake(t *testing.T) {
+	reconciler := newTestReconciler()
+	reconciler.args.EnablePreMigrationHook = true
+	job := &sev1alpha1.PodMigrationJob{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "test-pod",
+			Annotations: map[string]string{AnnotationPreMigrationHandshake: "true"},
+		},
+	}
+	assert.Nil(t, reconciler.Client.Create(context.TODO(), pod))
+
+	passed, _, err := reconciler.checkPreMigrationHook(context.TODO(), job, pod)
+	assert.Nil(t, err)
+	assert.False(t, passed)
+	gotPod := &corev1.Pod{}
+	assert.Nil(t, reconciler.Client.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "test-pod"}, gotPod))
+	assert.Equal(t, "test", gotPod.Annotations[AnnotationMigrationRequested])
+
+	// the workload is ready for another migration
+	gotPod.Annotations[AnnotationMigrationReady] = "another"
+	passed, _, err = reconciler.checkPreMigrationHook(context.TODO(), job, gotPod)
+	assert.Nil(t, err)
+	assert.False(t, passed)
+
+	gotPod.Annotations[AnnotationMigrationReady] = "test"
+	passed, _, err = reconciler.checkPreMigrationHook(context.TODO(), job, gotPod)
+	assert.Nil(t, err)
+	assert.True(t, passed)
+}
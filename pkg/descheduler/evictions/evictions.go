@@ -58,6 +58,7 @@ type PodEvictor struct {
 	totalCount                 int
 	nodepodCount               nodePodEvictedCount
 	namespacePodCount          namespacePodEvictCount
+	gangProtector              *GangProtector
 }
 
 func NewPodEvictor(
@@ -80,6 +81,11 @@ func NewPodEvictor(
 	}
 }
 
+// SetGangProtector sets the GangProtector which rejects the evictions breaking the gangs.
+func (pe *PodEvictor) SetGangProtector(gangProtector *GangProtector) {
+	pe.gangProtector = gangProtector
+}
+
 // NodeEvicted gives a number of pods evicted for node
 func (pe *PodEvictor) NodeEvicted(nodeName string) int {
 	pe.lock.Lock()
@@ -140,7 +146,16 @@ func (pe *PodEvictor) Evict(ctx context.Context, pod *corev1.Pod, opts framework
 		return false
 	}
 
+	if pe.gangProtector != nil {
+		if err := pe.gangProtector.Check(pod); err != nil {
+			metrics.PodsEvicted.With(map[string]string{"result": "gang would be broken", "strategy": opts.PluginName, "namespace": pod.Namespace, "node": nodeName}).Inc()
+			klog.ErrorS(err, "Error evicting pod", "pod", klog.KObj(pod))
+			return false
+		}
+	}
+
 	if pe.dryRun {
+		metrics.PodsEvicted.With(map[string]string{"result": "dry run", "strategy": opts.PluginName, "namespace": pod.Namespace, "node": nodeName}).Inc()
 		klog.V(1).InfoS("Evicted pod in dry run mode", "pod", klog.KObj(pod), "reason", opts.Reason, "strategy", opts.PluginName, "node", nodeName)
 	} else {
 		err := EvictPod(ctx, pe.client, pod, opts.DeleteOptions)
@@ -160,6 +175,9 @@ func (pe *PodEvictor) Evict(ctx context.Context, pod *corev1.Pod, opts framework
 			pe.namespacePodCount[pod.Namespace]++
 			pe.totalCount++
 		}()
+		if pe.gangProtector != nil {
+			pe.gangProtector.MarkEvicted(pod)
+		}
 
 		metrics.PodsEvicted.With(map[string]string{"result": "success", "strategy": opts.PluginName, "namespace": pod.Namespace, "node": nodeName}).Inc()
 
@@ -179,7 +197,7 @@ func EvictPod(ctx context.Context, client clientset.Interface, pod *corev1.Pod,
 	}
 	err := client.PolicyV1beta1().Evictions(eviction.Namespace).Evict(ctx, eviction)
 	if apierrors.IsTooManyRequests(err) {
-		return fmt.Errorf("error when evicting pod (ignoring) %q: %v", pod.Name, err)
+		return fmt.Errorf("error when evicting pod (ignoring) %q: %w", pod.Name, err)
 	}
 	if apierrors.IsNotFound(err) {
 		return fmt.Errorf("pod not found when evicting %q: %v", pod.Name, err)
@@ -191,6 +209,7 @@ type Options struct {
 	priority      *int32
 	nodeFit       bool
 	labelSelector labels.Selector
+	gangProtector *GangProtector
 }
 
 // WithPriorityThreshold sets a threshold for pod's priority class.
@@ -219,6 +238,13 @@ func WithLabelSelector(labelSelector labels.Selector) func(opts *Options) {
 	}
 }
 
+// WithGangProtector sets the GangProtector to filter the pods whose eviction breaks their gangs.
+func WithGangProtector(gangProtector *GangProtector) func(opts *Options) {
+	return func(opts *Options) {
+		opts.gangProtector = gangProtector
+	}
+}
+
 type nodeGetterFn func() ([]*corev1.Node, error)
 
 type constraint func(pod *corev1.Pod) error
@@ -315,6 +341,9 @@ func NewEvictorFilter(
 			return nil
 		})
 	}
+	if options.gangProtector != nil {
+		ev.constraints = append(ev.constraints, options.gangProtector.Check)
+	}
 
 	return ev
 }
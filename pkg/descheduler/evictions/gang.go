@@ -0,0 +1,163 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evictions
+
+import (
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+// GangProtector prevents the evictions which drop the alive members of a gang below its minMember.
+// If the minMember is not declared by the pod, all the alive members are considered required, and
+// the gang can only be evicted entirely.
+type GangProtector struct {
+	podLister corelisters.PodLister
+	lock      sync.Mutex
+	// evicted records the evicted members which may be still alive in the informer
+	evicted map[string]sets.String
+	// wholeSelected records the members selected together with the entire gang
+	wholeSelected map[string]sets.String
+}
+
+func NewGangProtector(podLister corelisters.PodLister) *GangProtector {
+	return &GangProtector{
+		podLister:     podLister,
+		evicted:       map[string]sets.String{},
+		wholeSelected: map[string]sets.String{},
+	}
+}
+
+// aliveMembersNoLock returns the members of the gang which are assigned and not evicted.
+func (g *GangProtector) aliveMembersNoLock(gangKey string, pod *corev1.Pod) []*corev1.Pod {
+	pods, err := g.podLister.Pods(pod.Namespace).List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Failed to list pods of gang", "gang", gangKey)
+		return nil
+	}
+	listed := sets.NewString()
+	var members []*corev1.Pod
+	for _, p := range pods {
+		if util.GetGangKey(p) != gangKey {
+			continue
+		}
+		listed.Insert(string(p.UID))
+		if p.Spec.NodeName == "" || p.DeletionTimestamp != nil ||
+			p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if g.evicted[gangKey].Has(string(p.UID)) {
+			continue
+		}
+		members = append(members, p)
+	}
+	// forget the evicted pods which have been deleted
+	if evicted := g.evicted[gangKey]; evicted != nil {
+		for _, uid := range evicted.UnsortedList() {
+			if !listed.Has(uid) {
+				evicted.Delete(uid)
+			}
+		}
+		if evicted.Len() == 0 {
+			delete(g.evicted, gangKey)
+		}
+	}
+	return members
+}
+
+func (g *GangProtector) gangInfoNoLock(gangKey string, pod *corev1.Pod) (int, int) {
+	aliveMembers := len(g.aliveMembersNoLock(gangKey, pod))
+	minMember := util.GetGangMinMember(pod)
+	if minMember == 0 {
+		minMember = aliveMembers
+	}
+	return minMember, aliveMembers
+}
+
+// Check returns an error if evicting the pod breaks its gang.
+func (g *GangProtector) Check(pod *corev1.Pod) error {
+	gangKey := util.GetGangKey(pod)
+	if gangKey == "" {
+		return nil
+	}
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if g.wholeSelected[gangKey].Has(string(pod.UID)) {
+		return nil
+	}
+	minMember, aliveMembers := g.gangInfoNoLock(gangKey, pod)
+	if aliveMembers-1 < minMember {
+		return fmt.Errorf("pod is a member of gang %s which requires %d members and has %d alive", gangKey, minMember, aliveMembers)
+	}
+	return nil
+}
+
+// Select returns the pods which can be evicted without breaking their gangs, see util.SelectGangEvictablePods.
+// The members of the entire gangs selected are allowed to be evicted by the following Check.
+func (g *GangProtector) Select(pods []*corev1.Pod) []*corev1.Pod {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	selected := util.SelectGangEvictablePods(pods, g.gangInfoNoLock)
+
+	counts := map[string]int{}
+	for _, pod := range selected {
+		if gangKey := util.GetGangKey(pod); gangKey != "" {
+			counts[gangKey]++
+		}
+	}
+	for _, pod := range selected {
+		gangKey := util.GetGangKey(pod)
+		if gangKey == "" {
+			continue
+		}
+		if _, aliveMembers := g.gangInfoNoLock(gangKey, pod); counts[gangKey] < aliveMembers {
+			continue
+		}
+		if g.wholeSelected[gangKey] == nil {
+			g.wholeSelected[gangKey] = sets.NewString()
+		}
+		g.wholeSelected[gangKey].Insert(string(pod.UID))
+	}
+	return selected
+}
+
+// MarkEvicted records the pod is evicted, which is no longer counted as an alive member of its gang.
+func (g *GangProtector) MarkEvicted(pod *corev1.Pod) {
+	gangKey := util.GetGangKey(pod)
+	if gangKey == "" {
+		return
+	}
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if g.evicted[gangKey] == nil {
+		g.evicted[gangKey] = sets.NewString()
+	}
+	g.evicted[gangKey].Insert(string(pod.UID))
+	if selected := g.wholeSelected[gangKey]; selected != nil {
+		selected.Delete(string(pod.UID))
+		if selected.Len() == 0 {
+			delete(g.wholeSelected, gangKey)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evictions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/test"
+)
+
+func TestGangProtector(t *testing.T) {
+	var pods []*corev1.Pod
+	for _, name := range []string{"a-1", "a-2", "a-3", "b-1", "b-2"} {
+		gangName, minMember := "gang-a", "2"
+		if name[0] == 'b' {
+			gangName, minMember = "gang-b", "2"
+		}
+		pod := test.BuildTestPod(name, 100, 0, "node1", func(pod *corev1.Pod) {
+			pod.UID = types.UID(name)
+			pod.Annotations = map[string]string{
+				extension.AnnotationGangName:   gangName,
+				extension.AnnotationGangMinNum: minMember,
+			}
+		})
+		pods = append(pods, pod)
+	}
+	normalPod := test.BuildTestPod("normal", 100, 0, "node1", nil)
+
+	fakeClient := fake.NewSimpleClientset()
+	sharedInformerFactory := informers.NewSharedInformerFactory(fakeClient, 0)
+	podInformer := sharedInformerFactory.Core().V1().Pods()
+	for _, pod := range append(pods, normalPod) {
+		assert.NoError(t, podInformer.Informer().GetStore().Add(pod))
+	}
+	gp := NewGangProtector(podInformer.Lister())
+
+	assert.NoError(t, gp.Check(normalPod))
+	// gang-a has 3 members and requires 2, only one can be evicted
+	assert.NoError(t, gp.Check(pods[0]))
+	gp.MarkEvicted(pods[0])
+	assert.Error(t, gp.Check(pods[1]))
+	// gang-b can't lose any member
+	assert.Error(t, gp.Check(pods[3]))
+
+	// gang-b is selected entirely, and gang-a can't lose any more member
+	selected := gp.Select([]*corev1.Pod{pods[1], pods[3], pods[4], normalPod})
+	assert.Equal(t, []*corev1.Pod{normalPod, pods[3], pods[4]}, selected)
+	assert.NoError(t, gp.Check(pods[3]))
+	gp.MarkEvicted(pods[3])
+	assert.NoError(t, gp.Check(pods[4]))
+
+	// the evicted pod is forgotten after it is deleted
+	assert.NoError(t, podInformer.Informer().GetStore().Delete(pods[0]))
+	assert.Error(t, gp.Check(pods[1]))
+	assert.False(t, gp.evicted["default/gang-a"].Has("a-1"))
+}
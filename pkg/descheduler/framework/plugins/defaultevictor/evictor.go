@@ -38,10 +38,17 @@ const (
 type DefaultEvictor struct {
 	handle        framework.Handle
 	evictorFilter *evictions.EvictorFilter
-	evictor       *evictions.PodEvictor
+	// candidateFilter is the evictorFilter without the gang protection, which is applied by the gangProtector
+	// to the candidates as a whole
+	candidateFilter *evictions.EvictorFilter
+	gangProtector   *evictions.GangProtector
+	evictor         *evictions.PodEvictor
 }
 
-var _ framework.Evictor = &DefaultEvictor{}
+var (
+	_ framework.Evictor                    = &DefaultEvictor{}
+	_ framework.EvictionCandidatesSelector = &DefaultEvictor{}
+)
 
 func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error) {
 	evictorArgs, ok := args.(*deschedulerconfig.DefaultEvictorArgs)
@@ -69,17 +76,30 @@ func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error)
 		return nil, err
 	}
 
-	evictorFilter := evictions.NewEvictorFilter(
-		nodesGetter,
-		handle.GetPodsAssignedToNodeFunc(),
-		evictorArgs.EvictLocalStoragePods,
-		evictorArgs.EvictSystemCriticalPods,
-		evictorArgs.IgnorePvcPods,
-		evictorArgs.EvictFailedBarePods,
-		evictions.WithNodeFit(evictorArgs.NodeFit),
-		evictions.WithLabelSelector(selector),
-		evictions.WithPriorityThreshold(priorityThreshold),
-	)
+	var gangProtector *evictions.GangProtector
+	if !evictorArgs.DisableGangProtection {
+		gangProtector = evictions.NewGangProtector(handle.SharedInformerFactory().Core().V1().Pods().Lister())
+	}
+
+	newEvictorFilter := func(gangProtector *evictions.GangProtector) *evictions.EvictorFilter {
+		return evictions.NewEvictorFilter(
+			nodesGetter,
+			handle.GetPodsAssignedToNodeFunc(),
+			evictorArgs.EvictLocalStoragePods,
+			evictorArgs.EvictSystemCriticalPods,
+			evictorArgs.IgnorePvcPods,
+			evictorArgs.EvictFailedBarePods,
+			evictions.WithNodeFit(evictorArgs.NodeFit),
+			evictions.WithLabelSelector(selector),
+			evictions.WithPriorityThreshold(priorityThreshold),
+			evictions.WithGangProtector(gangProtector),
+		)
+	}
+	evictorFilter := newEvictorFilter(gangProtector)
+	candidateFilter := evictorFilter
+	if gangProtector != nil {
+		candidateFilter = newEvictorFilter(nil)
+	}
 
 	podEvictor := evictions.NewPodEvictor(
 		handle.ClientSet(),
@@ -89,11 +109,14 @@ func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error)
 		evictorArgs.MaxNoOfPodsToEvictPerNode,
 		evictorArgs.MaxNoOfPodsToEvictPerNamespace,
 	)
+	podEvictor.SetGangProtector(gangProtector)
 
 	return &DefaultEvictor{
-		handle:        handle,
-		evictorFilter: evictorFilter,
-		evictor:       podEvictor,
+		handle:          handle,
+		evictorFilter:   evictorFilter,
+		candidateFilter: candidateFilter,
+		gangProtector:   gangProtector,
+		evictor:         podEvictor,
 	}, nil
 }
 
@@ -108,3 +131,18 @@ func (d *DefaultEvictor) Filter(pod *corev1.Pod) bool {
 func (d *DefaultEvictor) Evict(ctx context.Context, pod *corev1.Pod, evictOptions framework.EvictOptions) bool {
 	return d.evictor.Evict(ctx, pod, evictOptions)
 }
+
+// SelectCandidates returns the pods accepted by the filters, the members of the gangs are selected by the
+// GangProtector, so a gang is evicted entirely or keeps its minMember, and the entire small gangs go first.
+func (d *DefaultEvictor) SelectCandidates(pods []*corev1.Pod) []*corev1.Pod {
+	var candidates []*corev1.Pod
+	for _, pod := range pods {
+		if d.candidateFilter.Filter(pod) {
+			candidates = append(candidates, pod)
+		}
+	}
+	if d.gangProtector == nil {
+		return candidates
+	}
+	return d.gangProtector.Select(candidates)
+}
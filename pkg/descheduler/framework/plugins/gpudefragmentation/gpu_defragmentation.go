@@ -0,0 +1,363 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpudefragmentation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config/validation"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
+	podutil "github.com/koordinator-sh/koordinator/pkg/descheduler/pod"
+)
+
+const (
+	PluginName = "GPUDefragmentation"
+
+	fullGPUCore = 100
+)
+
+// GPUDefragmentation evicts the pods sharing GPUs from a node to free whole GPUs for the pending pods
+// requesting whole GPUs, when no node has enough free GPUs for them. The GPU allocation status is read
+// from the device allocations written by the DeviceShare plugin of koord-scheduler.
+type GPUDefragmentation struct {
+	handle    framework.Handle
+	args      *deschedulerconfig.GPUDefragmentationArgs
+	podFilter podutil.FilterFunc
+}
+
+var _ framework.Plugin = &GPUDefragmentation{}
+var _ framework.BalancePlugin = &GPUDefragmentation{}
+
+func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	defragArgs, ok := args.(*deschedulerconfig.GPUDefragmentationArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type GPUDefragmentationArgs, got %T", args)
+	}
+
+	if err := validation.ValidateGPUDefragmentationArgs(nil, defragArgs); err != nil {
+		return nil, err
+	}
+
+	var includedNamespaces, excludedNamespaces sets.String
+	if defragArgs.Namespaces != nil {
+		includedNamespaces = sets.NewString(defragArgs.Namespaces.Include...)
+		excludedNamespaces = sets.NewString(defragArgs.Namespaces.Exclude...)
+	}
+
+	podFilter, err := podutil.NewOptions().
+		WithNamespaces(includedNamespaces).
+		WithoutNamespaces(excludedNamespaces).
+		WithLabelSelector(defragArgs.LabelSelector).
+		BuildFilterFunc()
+	if err != nil {
+		return nil, fmt.Errorf("error initializing pod filter function: %v", err)
+	}
+
+	// make sure the pod informer is started to list the pending pods
+	handle.SharedInformerFactory().Core().V1().Pods().Informer()
+
+	return &GPUDefragmentation{
+		handle:    handle,
+		args:      defragArgs,
+		podFilter: podFilter,
+	}, nil
+}
+
+func (d *GPUDefragmentation) Name() string {
+	return PluginName
+}
+
+func (d *GPUDefragmentation) Balance(ctx context.Context, nodes []*corev1.Node) *framework.Status {
+	pendingPods, err := d.handle.SharedInformerFactory().Core().V1().Pods().Lister().List(labels.Everything())
+	if err != nil {
+		return &framework.Status{Err: err}
+	}
+	demands := getPendingFullGPUDemands(pendingPods)
+	if len(demands) == 0 {
+		return nil
+	}
+
+	var nodeGPUList []*nodeGPUs
+	for _, node := range nodes {
+		pods, err := podutil.ListPodsOnANode(node.Name, d.handle.GetPodsAssignedToNodeFunc(), nil)
+		if err != nil {
+			klog.ErrorS(err, "Failed to get pods", "node", klog.KObj(node))
+			continue
+		}
+		if gpus := newNodeGPUs(node, pods); gpus.total > 0 {
+			nodeGPUList = append(nodeGPUList, gpus)
+		}
+	}
+
+	demand := getUnsatisfiedDemand(nodeGPUList, demands)
+	if demand == 0 {
+		return nil
+	}
+
+	evictable := podutil.WrapFilterFuncs(d.podFilter, d.handle.Evictor().Filter)
+	var maxPods int
+	if d.args.MaxPodsToEvictPerNode != nil {
+		maxPods = int(*d.args.MaxPodsToEvictPerNode)
+	}
+	target, pods := planDefragmentation(nodeGPUList, demand, maxPods, evictable)
+	if target == nil {
+		klog.V(4).InfoS("No node can be defragmented for the pending pods", "gpus", demand)
+		return nil
+	}
+
+	klog.V(1).InfoS("Defragmenting GPUs of node", "node", klog.KObj(target.node), "gpus", demand, "pods", len(pods))
+	for _, pod := range pods {
+		d.handle.Evictor().Evict(ctx, pod, framework.EvictOptions{
+			PluginName: PluginName,
+			Reason:     fmt.Sprintf("defragment GPUs of node %s for the pending pods requesting %d GPUs", target.node.Name, demand),
+		})
+	}
+	return nil
+}
+
+// nodeGPUs is the GPU allocation status of a node.
+type nodeGPUs struct {
+	node  *corev1.Node
+	total int
+	// used is the allocated gpu-core of each GPU minor
+	used map[int32]int64
+	// sharedPods is the pods sharing each GPU minor, and the gpu-core they are allocated
+	sharedPods map[int32]map[*corev1.Pod]int64
+}
+
+func newNodeGPUs(node *corev1.Node, pods []*corev1.Pod) *nodeGPUs {
+	gpus := &nodeGPUs{
+		node:       node,
+		used:       map[int32]int64{},
+		sharedPods: map[int32]map[*corev1.Pod]int64{},
+	}
+	if quantity, ok := node.Status.Allocatable[extension.GPUCore]; ok {
+		gpus.total = int(quantity.Value() / fullGPUCore)
+	}
+	for _, pod := range pods {
+		allocations, err := extension.GetDeviceAllocations(pod.Annotations)
+		if err != nil {
+			klog.V(4).ErrorS(err, "Failed to get device allocations", "pod", klog.KObj(pod))
+			continue
+		}
+		for _, allocation := range allocations[schedulingv1alpha1.GPU] {
+			core := allocation.Resources[extension.GPUCore]
+			gpus.used[allocation.Minor] += core.Value()
+			if core.Value() < fullGPUCore {
+				if gpus.sharedPods[allocation.Minor] == nil {
+					gpus.sharedPods[allocation.Minor] = map[*corev1.Pod]int64{}
+				}
+				gpus.sharedPods[allocation.Minor][pod] = core.Value()
+			}
+		}
+	}
+	return gpus
+}
+
+func (n *nodeGPUs) freeGPUs() int {
+	free := 0
+	for minor := 0; minor < n.total; minor++ {
+		if n.used[int32(minor)] == 0 {
+			free++
+		}
+	}
+	return free
+}
+
+// partialGPUs returns the GPU minors only used by the shared pods, ordered by the used gpu-core.
+func (n *nodeGPUs) partialGPUs() []int32 {
+	var minors []int32
+	for minor, used := range n.used {
+		if used <= 0 || int(minor) >= n.total {
+			continue
+		}
+		var shared int64
+		for _, core := range n.sharedPods[minor] {
+			shared += core
+		}
+		if shared == used {
+			minors = append(minors, minor)
+		}
+	}
+	sort.Slice(minors, func(i, j int) bool {
+		if n.used[minors[i]] != n.used[minors[j]] {
+			return n.used[minors[i]] < n.used[minors[j]]
+		}
+		return minors[i] < minors[j]
+	})
+	return minors
+}
+
+// getPendingFullGPUDemands returns the number of the whole GPUs requested by each pending pod in ascending order.
+func getPendingFullGPUDemands(pods []*corev1.Pod) []int {
+	var demands []int
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" || pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+		if gpus := getFullGPURequest(pod); gpus > 0 {
+			demands = append(demands, gpus)
+		}
+	}
+	sort.Ints(demands)
+	return demands
+}
+
+func getFullGPURequest(pod *corev1.Pod) int {
+	var gpus int64
+	for _, container := range pod.Spec.Containers {
+		requests := container.Resources.Requests
+		if quantity, ok := requests[extension.NvidiaGPU]; ok {
+			gpus += quantity.Value()
+			continue
+		}
+		for _, name := range []corev1.ResourceName{extension.KoordGPU, extension.GPUCore} {
+			if quantity, ok := requests[name]; ok {
+				if core := quantity.Value(); core >= fullGPUCore && core%fullGPUCore == 0 {
+					gpus += core / fullGPUCore
+				}
+				break
+			}
+		}
+	}
+	return int(gpus)
+}
+
+// getUnsatisfiedDemand returns the smallest demand which no node has enough free GPUs for, zero if none.
+func getUnsatisfiedDemand(nodeGPUList []*nodeGPUs, demands []int) int {
+	maxFree := 0
+	for _, gpus := range nodeGPUList {
+		if free := gpus.freeGPUs(); free > maxFree {
+			maxFree = free
+		}
+	}
+	for _, demand := range demands {
+		if demand > maxFree {
+			return demand
+		}
+	}
+	return 0
+}
+
+// planDefragmentation chooses the node which frees the demanded GPUs by evicting the fewest shared pods,
+// and the evicted pods must be able to be placed onto the partial GPUs of the other nodes.
+func planDefragmentation(nodeGPUList []*nodeGPUs, demand, maxPods int, evictable podutil.FilterFunc) (*nodeGPUs, []*corev1.Pod) {
+	var target *nodeGPUs
+	var targetPods []*corev1.Pod
+	for _, gpus := range nodeGPUList {
+		need := demand - gpus.freeGPUs()
+		if need <= 0 || gpus.total < demand {
+			continue
+		}
+		var pods []*corev1.Pod
+		freed := 0
+		for _, minor := range gpus.partialGPUs() {
+			if freed == need {
+				break
+			}
+			minorPods := make([]*corev1.Pod, 0, len(gpus.sharedPods[minor]))
+			for pod := range gpus.sharedPods[minor] {
+				minorPods = append(minorPods, pod)
+			}
+			if !allEvictable(minorPods, evictable) {
+				continue
+			}
+			pods = append(pods, minorPods...)
+			freed++
+		}
+		if freed < need || (maxPods > 0 && len(pods) > maxPods) {
+			continue
+		}
+		if target != nil && len(pods) >= len(targetPods) {
+			continue
+		}
+		if !canPlaceSharedPods(nodeGPUList, gpus, pods) {
+			continue
+		}
+		target, targetPods = gpus, pods
+	}
+	sort.Slice(targetPods, func(i, j int) bool {
+		return targetPods[i].Namespace+"/"+targetPods[i].Name < targetPods[j].Namespace+"/"+targetPods[j].Name
+	})
+	return target, targetPods
+}
+
+func allEvictable(pods []*corev1.Pod, evictable podutil.FilterFunc) bool {
+	for _, pod := range pods {
+		if !evictable(pod) {
+			return false
+		}
+	}
+	return true
+}
+
+// canPlaceSharedPods checks whether the evicted shared pods fit the partial GPUs of the other nodes by first fit
+// decreasing. The free GPUs are not counted, otherwise the evicted pods would fragment them again.
+func canPlaceSharedPods(nodeGPUList []*nodeGPUs, source *nodeGPUs, pods []*corev1.Pod) bool {
+	type podCore struct {
+		pod  *corev1.Pod
+		core int64
+	}
+	var cores []podCore
+	for _, pod := range pods {
+		for _, shared := range source.sharedPods {
+			if core, ok := shared[pod]; ok {
+				cores = append(cores, podCore{pod: pod, core: core})
+			}
+		}
+	}
+	sort.Slice(cores, func(i, j int) bool {
+		return cores[i].core > cores[j].core
+	})
+
+	var available []int64
+	for _, gpus := range nodeGPUList {
+		if gpus == source {
+			continue
+		}
+		for minor, used := range gpus.used {
+			if used > 0 && used < fullGPUCore && int(minor) < gpus.total {
+				available = append(available, fullGPUCore-used)
+			}
+		}
+	}
+	for _, c := range cores {
+		placed := false
+		for i := range available {
+			if available[i] >= c.core {
+				available[i] -= c.core
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpudefragmentation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+func newTestGPUNode(name string, gpus int64) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				extension.GPUCore: *resource.NewQuantity(gpus*fullGPUCore, resource.DecimalSI),
+			},
+		},
+	}
+}
+
+func newTestGPUPod(t *testing.T, name string, minor int32, core int64) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+	}
+	assert.NoError(t, extension.SetDeviceAllocations(pod, extension.DeviceAllocations{
+		schedulingv1alpha1.GPU: []*extension.DeviceAllocation{
+			{
+				Minor: minor,
+				Resources: corev1.ResourceList{
+					extension.GPUCore: *resource.NewQuantity(core, resource.DecimalSI),
+				},
+			},
+		},
+	}))
+	return pod
+}
+
+func TestGetPendingFullGPUDemands(t *testing.T) {
+	newPendingPod := func(name corev1.ResourceName, value int64) *corev1.Pod {
+		return &corev1.Pod{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								name: *resource.NewQuantity(value, resource.DecimalSI),
+							},
+						},
+					},
+				},
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodPending},
+		}
+	}
+	scheduled := newPendingPod(extension.NvidiaGPU, 4)
+	scheduled.Spec.NodeName = "test-node-1"
+	pods := []*corev1.Pod{
+		newPendingPod(extension.NvidiaGPU, 2),
+		newPendingPod(extension.KoordGPU, 100),
+		newPendingPod(extension.GPUCore, 50),
+		newPendingPod(corev1.ResourceCPU, 4),
+		scheduled,
+	}
+	assert.Equal(t, []int{1, 2}, getPendingFullGPUDemands(pods))
+}
+
+func TestPlanDefragmentation(t *testing.T) {
+	// node1: GPU 0 shared by 2 pods, GPU 1 shared by 1 pod, GPU 2 used by a whole GPU pod, GPU 3 free
+	node1Pods := []*corev1.Pod{
+		newTestGPUPod(t, "node1-pod-1", 0, 30),
+		newTestGPUPod(t, "node1-pod-2", 0, 20),
+		newTestGPUPod(t, "node1-pod-3", 1, 10),
+		newTestGPUPod(t, "node1-pod-4", 2, 100),
+	}
+	node1 := newNodeGPUs(newTestGPUNode("test-node-1", 4), node1Pods)
+	assert.Equal(t, 1, node1.freeGPUs())
+	assert.Equal(t, []int32{1, 0}, node1.partialGPUs())
+
+	// node2: GPU 0 shared by 1 pod, GPU 1 free
+	node2 := newNodeGPUs(newTestGPUNode("test-node-2", 2), []*corev1.Pod{
+		newTestGPUPod(t, "node2-pod-1", 0, 40),
+	})
+	nodeGPUList := []*nodeGPUs{node1, node2}
+
+	assert.Equal(t, 0, getUnsatisfiedDemand(nodeGPUList, []int{1}))
+	assert.Equal(t, 2, getUnsatisfiedDemand(nodeGPUList, []int{1, 2, 3}))
+
+	evictAll := func(pod *corev1.Pod) bool { return true }
+	// node1 frees GPU 1 by evicting the pod with the least gpu-core
+	target, pods := planDefragmentation(nodeGPUList, 2, 0, evictAll)
+	assert.Equal(t, node1, target)
+	assert.Equal(t, []string{"node1-pod-3"}, podNames(pods))
+
+	// the pod on GPU 1 of node1 can't be evicted, node2 evicts fewer pods than GPU 0 of node1
+	target, pods = planDefragmentation(nodeGPUList, 2, 0, func(pod *corev1.Pod) bool {
+		return pod.Name != "node1-pod-3"
+	})
+	assert.Equal(t, node2, target)
+	assert.Equal(t, []string{"node2-pod-1"}, podNames(pods))
+
+	// only node1 has enough GPUs, and the evicted pods fit GPU 0 of node2
+	target, pods = planDefragmentation(nodeGPUList, 3, 0, evictAll)
+	assert.Equal(t, node1, target)
+	assert.Equal(t, []string{"node1-pod-1", "node1-pod-2", "node1-pod-3"}, podNames(pods))
+
+	// exceed the max pods to evict
+	target, _ = planDefragmentation(nodeGPUList, 3, 2, evictAll)
+	assert.Nil(t, target)
+
+	// node1 has no enough partial GPUs to free
+	target, pods = planDefragmentation(nodeGPUList, 4, 0, evictAll)
+	assert.Nil(t, target)
+	assert.Nil(t, pods)
+}
+
+func podNames(pods []*corev1.Pod) []string {
+	var names []string
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	return names
+}
+
+func TestCanPlaceSharedPods(t *testing.T) {
+	source := newNodeGPUs(newTestGPUNode("test-node-1", 2), []*corev1.Pod{
+		newTestGPUPod(t, "pod-1", 0, 60),
+	})
+	for _, used := range []int64{50, 30} {
+		other := newNodeGPUs(newTestGPUNode(fmt.Sprintf("test-node-%d", used), 2), []*corev1.Pod{
+			newTestGPUPod(t, "pod", 0, used),
+		})
+		var pods []*corev1.Pod
+		for pod := range source.sharedPods[0] {
+			pods = append(pods, pod)
+		}
+		assert.Equal(t, used <= 40, canPlaceSharedPods([]*nodeGPUs{source, other}, source, pods))
+	}
+}
@@ -0,0 +1,323 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotarebalancing
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	resourcehelper "k8s.io/kubernetes/pkg/api/v1/resource"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	pgclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	pgformers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+	quotalisters "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config/validation"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
+	podutil "github.com/koordinator-sh/koordinator/pkg/descheduler/pod"
+)
+
+const (
+	PluginName = "QuotaRebalancing"
+)
+
+// QuotaRebalancing evicts the pods of the quota groups whose used greatly exceeds the runtime, e.g. the runtime
+// shrinks since the lent resource is taken back by the other quota groups. The most overused quota groups are
+// handled first, and the pods with the lowest priority are evicted first, so the decisions of the elastic quota
+// are enforced instead of waiting for the pods to finish.
+type QuotaRebalancing struct {
+	handle      framework.Handle
+	args        *deschedulerconfig.QuotaRebalancingArgs
+	podFilter   podutil.FilterFunc
+	quotaLister quotalisters.ElasticQuotaLister
+}
+
+var _ framework.Plugin = &QuotaRebalancing{}
+var _ framework.BalancePlugin = &QuotaRebalancing{}
+
+func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	rebalancingArgs, ok := args.(*deschedulerconfig.QuotaRebalancingArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type QuotaRebalancingArgs, got %T", args)
+	}
+
+	if err := validation.ValidateQuotaRebalancingArgs(nil, rebalancingArgs); err != nil {
+		return nil, err
+	}
+
+	var includedNamespaces, excludedNamespaces sets.String
+	if rebalancingArgs.Namespaces != nil {
+		includedNamespaces = sets.NewString(rebalancingArgs.Namespaces.Include...)
+		excludedNamespaces = sets.NewString(rebalancingArgs.Namespaces.Exclude...)
+	}
+
+	podFilter, err := podutil.NewOptions().
+		WithNamespaces(includedNamespaces).
+		WithoutNamespaces(excludedNamespaces).
+		WithLabelSelector(rebalancingArgs.LabelSelector).
+		BuildFilterFunc()
+	if err != nil {
+		return nil, fmt.Errorf("error initializing pod filter function: %v", err)
+	}
+
+	quotaClient, ok := handle.(pgclientset.Interface)
+	if !ok {
+		kubeConfig := *handle.KubeConfig()
+		kubeConfig.ContentType = runtime.ContentTypeJSON
+		kubeConfig.AcceptContentTypes = runtime.ContentTypeJSON
+		quotaClient = pgclientset.NewForConfigOrDie(&kubeConfig)
+	}
+	quotaInformerFactory := pgformers.NewSharedInformerFactory(quotaClient, 0)
+	quotaLister := quotaInformerFactory.Scheduling().V1alpha1().ElasticQuotas().Lister()
+
+	ctx := context.TODO()
+	quotaInformerFactory.Start(ctx.Done())
+	quotaInformerFactory.WaitForCacheSync(ctx.Done())
+
+	return &QuotaRebalancing{
+		handle:      handle,
+		args:        rebalancingArgs,
+		podFilter:   podFilter,
+		quotaLister: quotaLister,
+	}, nil
+}
+
+func (q *QuotaRebalancing) Name() string {
+	return PluginName
+}
+
+func (q *QuotaRebalancing) Balance(ctx context.Context, nodes []*corev1.Node) *framework.Status {
+	quotas, err := q.quotaLister.List(labels.Everything())
+	if err != nil {
+		return &framework.Status{Err: err}
+	}
+	var thresholdPercent int32
+	if q.args.OverusedThresholdPercent != nil {
+		thresholdPercent = *q.args.OverusedThresholdPercent
+	}
+	overusedQuotas := getOverusedQuotas(quotas, thresholdPercent)
+	if len(overusedQuotas) == 0 {
+		return nil
+	}
+
+	quotaPods := map[string][]*corev1.Pod{}
+	for _, node := range nodes {
+		pods, err := podutil.ListPodsOnANode(node.Name, q.handle.GetPodsAssignedToNodeFunc(), nil)
+		if err != nil {
+			klog.ErrorS(err, "Failed to get pods", "node", klog.KObj(node))
+			continue
+		}
+		for _, pod := range pods {
+			quotaName := getPodQuotaName(pod)
+			quotaPods[quotaName] = append(quotaPods[quotaName], pod)
+		}
+	}
+
+	var maxPods int
+	if q.args.MaxPodsToEvictPerQuota != nil {
+		maxPods = int(*q.args.MaxPodsToEvictPerQuota)
+	}
+	for _, quota := range overusedQuotas {
+		pods := selectPodsToEvict(quota, quotaPods[quota.name], maxPods, q.selectCandidates)
+		if len(pods) == 0 {
+			continue
+		}
+		klog.V(1).InfoS("Rebalancing overused quota", "quota", quota.name, "used", quota.used, "runtime", quota.runtime, "pods", len(pods))
+		for _, pod := range pods {
+			q.handle.Evictor().Evict(ctx, pod, framework.EvictOptions{
+				PluginName: PluginName,
+				Reason:     fmt.Sprintf("the used of quota %s exceeds its runtime", quota.name),
+			})
+		}
+	}
+	return nil
+}
+
+// overusedQuota is a quota group whose used exceeds the runtime.
+type overusedQuota struct {
+	name    string
+	used    corev1.ResourceList
+	runtime corev1.ResourceList
+	// ratio is the max ratio of the used exceeding the runtime in all the resource dimensions
+	ratio float64
+}
+
+// getOverusedQuotas returns the leaf quota groups whose used exceeds the runtime by more than thresholdPercent
+// in any resource dimension, ordered by how much they are overused.
+func getOverusedQuotas(quotas []*v1alpha1.ElasticQuota, thresholdPercent int32) []*overusedQuota {
+	quotaMap := make(map[string]*v1alpha1.ElasticQuota, len(quotas))
+	for _, quota := range quotas {
+		quotaMap[quota.Name] = quota
+	}
+
+	var overused []*overusedQuota
+	for _, quota := range quotas {
+		if quota.Name == extension.SystemQuotaName || quota.Name == extension.DefaultQuotaName ||
+			quota.Name == extension.RootQuotaName || extension.IsParentQuota(quota) {
+			continue
+		}
+		if getEvictionPolicy(quota, quotaMap) == extension.QuotaEvictionPolicyNever {
+			continue
+		}
+		runtime, err := extension.GetRuntime(quota)
+		if err != nil {
+			klog.V(4).ErrorS(err, "Failed to get runtime of quota", "quota", klog.KObj(quota))
+			continue
+		}
+		if runtime == nil {
+			continue
+		}
+		ratio, exceeded := getOverusedRatio(quota.Status.Used, runtime, thresholdPercent)
+		if !exceeded {
+			continue
+		}
+		overused = append(overused, &overusedQuota{
+			name:    quota.Name,
+			used:    quota.Status.Used,
+			runtime: runtime,
+			ratio:   ratio,
+		})
+	}
+	sort.Slice(overused, func(i, j int) bool {
+		if overused[i].ratio != overused[j].ratio {
+			return overused[i].ratio > overused[j].ratio
+		}
+		return overused[i].name < overused[j].name
+	})
+	return overused
+}
+
+// getOverusedRatio returns the max ratio of the used exceeding the runtime, and whether it exceeds the threshold.
+func getOverusedRatio(used, runtime corev1.ResourceList, thresholdPercent int32) (float64, bool) {
+	var maxRatio float64
+	exceeded := false
+	for resourceName, runtimeQuantity := range runtime {
+		usedQuantity, ok := used[resourceName]
+		if !ok || usedQuantity.Cmp(runtimeQuantity) <= 0 {
+			continue
+		}
+		var ratio float64
+		if runtimeQuantity.IsZero() {
+			ratio = math.MaxFloat64
+		} else {
+			ratio = float64(usedQuantity.MilliValue()-runtimeQuantity.MilliValue()) / float64(runtimeQuantity.MilliValue())
+		}
+		if ratio*100 > float64(thresholdPercent) {
+			exceeded = true
+		}
+		if ratio > maxRatio {
+			maxRatio = ratio
+		}
+	}
+	return maxRatio, exceeded
+}
+
+// getEvictionPolicy returns the eviction policy of the quota group, which is inherited from the parents if not set.
+func getEvictionPolicy(quota *v1alpha1.ElasticQuota, quotaMap map[string]*v1alpha1.ElasticQuota) extension.QuotaEvictionPolicy {
+	visited := sets.NewString()
+	for quota != nil && !visited.Has(quota.Name) {
+		visited.Insert(quota.Name)
+		if policy, err := extension.GetQuotaPolicy(quota); err == nil && policy.EvictionPolicy != "" {
+			return policy.EvictionPolicy
+		}
+		quota = quotaMap[extension.GetParentQuotaName(quota)]
+	}
+	return ""
+}
+
+// getPodQuotaName returns the quota group the pod is charged to.
+func getPodQuotaName(pod *corev1.Pod) string {
+	if quotaName := pod.Annotations[extension.AnnotationChargedQuota]; quotaName != "" {
+		return quotaName
+	}
+	if quotaName := pod.Labels[extension.LabelQuotaName]; quotaName != "" {
+		return quotaName
+	}
+	return extension.DefaultQuotaName
+}
+
+// selectCandidates returns the evictable pods, the Evictor selecting the candidates together may reorder them,
+// e.g. to evict the entire small gangs first.
+func (q *QuotaRebalancing) selectCandidates(pods []*corev1.Pod) []*corev1.Pod {
+	var candidates []*corev1.Pod
+	evictor := q.handle.Evictor()
+	selector, isSelector := evictor.(framework.EvictionCandidatesSelector)
+	for _, pod := range pods {
+		if q.podFilter(pod) && (isSelector || evictor.Filter(pod)) {
+			candidates = append(candidates, pod)
+		}
+	}
+	if isSelector {
+		return selector.SelectCandidates(candidates)
+	}
+	return candidates
+}
+
+// selectPodsToEvict selects the pods with the lowest priority until the used of the quota group falls back
+// to its runtime, at most maxPods pods are selected if maxPods is positive. The candidates sorted by priority
+// are narrowed and reordered by selectCandidates.
+func selectPodsToEvict(quota *overusedQuota, pods []*corev1.Pod, maxPods int, selectCandidates func([]*corev1.Pod) []*corev1.Pod) []*corev1.Pod {
+	excess := corev1.ResourceList{}
+	for resourceName, runtimeQuantity := range quota.runtime {
+		usedQuantity := quota.used[resourceName]
+		if usedQuantity.Cmp(runtimeQuantity) > 0 {
+			usedQuantity.Sub(runtimeQuantity)
+			excess[resourceName] = usedQuantity
+		}
+	}
+
+	candidates := make([]*corev1.Pod, len(pods))
+	copy(candidates, pods)
+	podutil.SortPodsBasedOnPriorityLowToHigh(candidates)
+	candidates = selectCandidates(candidates)
+
+	var selected []*corev1.Pod
+	for _, pod := range candidates {
+		if len(excess) == 0 || (maxPods > 0 && len(selected) >= maxPods) {
+			break
+		}
+		requests, _ := resourcehelper.PodRequestsAndLimits(pod)
+		reduced := false
+		for resourceName, excessQuantity := range excess {
+			request, ok := requests[resourceName]
+			if !ok || request.IsZero() {
+				continue
+			}
+			reduced = true
+			excessQuantity.Sub(request)
+			if excessQuantity.Sign() <= 0 {
+				delete(excess, resourceName)
+			} else {
+				excess[resourceName] = excessQuantity
+			}
+		}
+		// the pods not requesting the overused resources don't help
+		if reduced {
+			selected = append(selected, pod)
+		}
+	}
+	return selected
+}
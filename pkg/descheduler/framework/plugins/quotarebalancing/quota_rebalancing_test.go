@@ -0,0 +1,182 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quotarebalancing
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func newTestQuota(t *testing.T, name, parent string, used, runtime int64) *v1alpha1.ElasticQuota {
+	runtimeData, err := json.Marshal(corev1.ResourceList{
+		corev1.ResourceCPU: *resource.NewQuantity(runtime, resource.DecimalSI),
+	})
+	assert.NoError(t, err)
+	return &v1alpha1.ElasticQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				extension.LabelQuotaParent: parent,
+			},
+			Annotations: map[string]string{
+				extension.AnnotationRuntime: string(runtimeData),
+			},
+		},
+		Status: v1alpha1.ElasticQuotaStatus{
+			Used: corev1.ResourceList{
+				corev1.ResourceCPU: *resource.NewQuantity(used, resource.DecimalSI),
+			},
+		},
+	}
+}
+
+func newTestPod(name string, priority int32, cpu int64) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: corev1.PodSpec{
+			Priority: &priority,
+			Containers: []corev1.Container{
+				{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU: *resource.NewQuantity(cpu, resource.DecimalSI),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGetOverusedQuotas(t *testing.T) {
+	parent := newTestQuota(t, "parent", extension.RootQuotaName, 100, 10)
+	parent.Labels[extension.LabelQuotaIsParent] = "true"
+	parent.Annotations[extension.AnnotationQuotaPolicy] = `{"evictionPolicy":"Never"}`
+	notRebalanced := newTestQuota(t, "child-of-parent", "parent", 100, 10)
+	noRuntime := newTestQuota(t, "no-runtime", extension.RootQuotaName, 100, 10)
+	delete(noRuntime.Annotations, extension.AnnotationRuntime)
+	quotas := []*v1alpha1.ElasticQuota{
+		parent,
+		notRebalanced,
+		noRuntime,
+		newTestQuota(t, extension.DefaultQuotaName, extension.RootQuotaName, 100, 10),
+		newTestQuota(t, "within-threshold", extension.RootQuotaName, 105, 100),
+		newTestQuota(t, "slightly-overused", extension.RootQuotaName, 30, 20),
+		newTestQuota(t, "heavily-overused", extension.RootQuotaName, 40, 10),
+		newTestQuota(t, "not-overused", extension.RootQuotaName, 10, 20),
+	}
+	got := getOverusedQuotas(quotas, 10)
+	var names []string
+	for _, quota := range got {
+		names = append(names, quota.name)
+	}
+	assert.Equal(t, []string{"heavily-overused", "slightly-overused"}, names)
+	assert.Equal(t, 3.0, got[0].ratio)
+}
+
+func TestGetPodQuotaName(t *testing.T) {
+	pod := &corev1.Pod{}
+	assert.Equal(t, extension.DefaultQuotaName, getPodQuotaName(pod))
+	pod.Labels = map[string]string{extension.LabelQuotaName: "quota-a"}
+	assert.Equal(t, "quota-a", getPodQuotaName(pod))
+	pod.Annotations = map[string]string{extension.AnnotationChargedQuota: "quota-b"}
+	assert.Equal(t, "quota-b", getPodQuotaName(pod))
+}
+
+func TestSelectPodsToEvict(t *testing.T) {
+	quota := &overusedQuota{
+		name: "test-quota",
+		used: corev1.ResourceList{
+			corev1.ResourceCPU: *resource.NewQuantity(40, resource.DecimalSI),
+		},
+		runtime: corev1.ResourceList{
+			corev1.ResourceCPU: *resource.NewQuantity(25, resource.DecimalSI),
+		},
+	}
+	unevictable := newTestPod("unevictable", 0, 20)
+	pods := []*corev1.Pod{
+		newTestPod("high-priority", 100, 10),
+		newTestPod("low-priority", 10, 10),
+		unevictable,
+		newTestPod("middle-priority", 50, 10),
+		newTestPod("no-request", 1, 0),
+	}
+	selectCandidates := func(pods []*corev1.Pod) []*corev1.Pod {
+		var candidates []*corev1.Pod
+		for _, pod := range pods {
+			if pod != unevictable {
+				candidates = append(candidates, pod)
+			}
+		}
+		return candidates
+	}
+
+	// preferMiddle moves the middle-priority pod ahead like the Evictor preferring the entire small gangs
+	preferMiddle := func(pods []*corev1.Pod) []*corev1.Pod {
+		candidates := selectCandidates(pods)
+		for i, pod := range candidates {
+			if pod.Name == "middle-priority" {
+				return append([]*corev1.Pod{pod}, append(candidates[:i:i], candidates[i+1:]...)...)
+			}
+		}
+		return candidates
+	}
+
+	tests := []struct {
+		name             string
+		maxPods          int
+		selectCandidates func([]*corev1.Pod) []*corev1.Pod
+		want             []string
+	}{
+		{
+			name: "evict the lowest priority pods until the used falls back to runtime",
+			want: []string{"low-priority", "middle-priority"},
+		},
+		{
+			name:    "limited by maxPods",
+			maxPods: 1,
+			want:    []string{"low-priority"},
+		},
+		{
+			name:             "keep the order of the candidates selected",
+			selectCandidates: preferMiddle,
+			want:             []string{"middle-priority", "low-priority"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selectFn := selectCandidates
+			if tt.selectCandidates != nil {
+				selectFn = tt.selectCandidates
+			}
+			got := selectPodsToEvict(quota, pods, tt.maxPods, selectFn)
+			var names []string
+			for _, pod := range got {
+				names = append(names, pod.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
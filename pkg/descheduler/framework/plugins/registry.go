@@ -18,6 +18,8 @@ package plugins
 
 import (
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework/plugins/defaultevictor"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework/plugins/gpudefragmentation"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework/plugins/quotarebalancing"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework/plugins/removepodsviolatingnodeaffinity"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework/runtime"
 )
@@ -26,5 +28,7 @@ func NewInTreeRegistry() runtime.Registry {
 	return runtime.Registry{
 		removepodsviolatingnodeaffinity.PluginName: removepodsviolatingnodeaffinity.New,
 		defaultevictor.PluginName:                  defaultevictor.New,
+		gpudefragmentation.PluginName:              gpudefragmentation.New,
+		quotarebalancing.PluginName:                quotarebalancing.New,
 	}
 }
@@ -85,6 +85,14 @@ type Evictor interface {
 	Evict(ctx context.Context, pod *corev1.Pod, evictOptions EvictOptions) bool
 }
 
+// EvictionCandidatesSelector is implemented by the Evictor which selects the pods to evict together, e.g. the Evictor
+// protecting the gangs allows evicting a gang entirely, and prefers the entire small gangs to fragmenting the others.
+type EvictionCandidatesSelector interface {
+	// SelectCandidates returns the pods which can be evicted together, the order of the pods is kept except the
+	// pods preferred by the Evictor are moved ahead.
+	SelectCandidates(pods []*corev1.Pod) []*corev1.Pod
+}
+
 type DeschedulePlugin interface {
 	Plugin
 	Deschedule(ctx context.Context, nodes []*corev1.Node) *Status
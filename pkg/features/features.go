@@ -29,11 +29,46 @@ const (
 
 	// PodValidatingWebhook enables validating webhook for Pods creations or updates.
 	PodValidatingWebhook featuregate.Feature = "PodValidatingWebhook"
+
+	// ColocationConfigDriftDetection enables the controller which detects the drift between the colocation
+	// config of koord-manager and the koord-scheduler config.
+	ColocationConfigDriftDetection featuregate.Feature = "ColocationConfigDriftDetection"
+
+	// ElasticQuotaNamespaceBinding injects the quota label of the namespace onto Pods at admission.
+	ElasticQuotaNamespaceBinding featuregate.Feature = "ElasticQuotaNamespaceBinding"
+
+	// PodGroupAutoCreation enables the controllers which generate the PodGroups of the well-known workload CRs,
+	// and labels the pods of the workloads with their PodGroups at admission.
+	PodGroupAutoCreation featuregate.Feature = "PodGroupAutoCreation"
+
+	// ElasticQuotaValidatingWebhook enables validating webhook for ElasticQuota creations or updates, which rejects
+	// the quotas breaking the invariants of the quota tree.
+	ElasticQuotaValidatingWebhook featuregate.Feature = "ElasticQuotaValidatingWebhook"
+
+	// NodeSLORollout stages the changes of the NodeSLO config across the nodes, and enables the controller which
+	// reports the rollout status and halts the rollout on eviction spikes.
+	NodeSLORollout featuregate.Feature = "NodeSLORollout"
+
+	// ElasticQuotaRequest enables the controller which patches the approved ElasticQuotaRequests onto the
+	// ElasticQuotas.
+	ElasticQuotaRequest featuregate.Feature = "ElasticQuotaRequest"
+
+	// ElasticQuotaResizeValidation rejects the in-place resize of the pods which exceeds the runtime of their
+	// quota groups.
+	ElasticQuotaResizeValidation featuregate.Feature = "ElasticQuotaResizeValidation"
 )
 
 var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	PodMutatingWebhook:   {Default: true, PreRelease: featuregate.Beta},
 	PodValidatingWebhook: {Default: true, PreRelease: featuregate.Beta},
+
+	ColocationConfigDriftDetection: {Default: false, PreRelease: featuregate.Alpha},
+	ElasticQuotaNamespaceBinding:   {Default: false, PreRelease: featuregate.Alpha},
+	PodGroupAutoCreation:           {Default: false, PreRelease: featuregate.Alpha},
+	ElasticQuotaValidatingWebhook:  {Default: false, PreRelease: featuregate.Alpha},
+	NodeSLORollout:                 {Default: false, PreRelease: featuregate.Alpha},
+	ElasticQuotaRequest:            {Default: false, PreRelease: featuregate.Alpha},
+	ElasticQuotaResizeValidation:   {Default: false, PreRelease: featuregate.Alpha},
 }
 
 func init() {
@@ -52,6 +52,18 @@ const (
 	// Accelerators enables GPU related feature in koordlet.
 	// Only Nvidia GPUs are supported as of v0.6.
 	Accelerators featuregate.Feature = "Accelerators"
+
+	// CacheGroup assigns the pods of the same CacheGroup to a dedicated resctrl group.
+	CacheGroup featuregate.Feature = "CacheGroup"
+
+	// PSICollector collects the pressure stall information of the node and pods.
+	PSICollector featuregate.Feature = "PSICollector"
+
+	// NetQoS limits the network bandwidth of pods by tc, and protects the bandwidth of LS pods from BE pods.
+	NetQoS featuregate.Feature = "NetQoS"
+
+	// BlkIOReconcile sets the block io weight and throttling of pods on the devices they use.
+	BlkIOReconcile featuregate.Feature = "BlkIOReconcile"
 )
 
 func init() {
@@ -73,5 +85,9 @@ var (
 		RdtResctrl:             {Default: false, PreRelease: featuregate.Alpha},
 		CgroupReconcile:        {Default: false, PreRelease: featuregate.Alpha},
 		Accelerators:           {Default: false, PreRelease: featuregate.Alpha},
+		CacheGroup:             {Default: false, PreRelease: featuregate.Alpha},
+		PSICollector:           {Default: false, PreRelease: featuregate.Alpha},
+		NetQoS:                 {Default: false, PreRelease: featuregate.Alpha},
+		BlkIOReconcile:         {Default: false, PreRelease: featuregate.Alpha},
 	}
 )
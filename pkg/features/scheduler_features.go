@@ -0,0 +1,49 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"k8s.io/apimachinery/pkg/util/runtime"
+	k8sfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/component-base/featuregate"
+)
+
+const (
+	// ElasticQuotaAccountingVerification periodically recomputes the Request/Used of the elastic quota groups
+	// from the pods listed from apiserver, and corrects the drift of the cached values.
+	ElasticQuotaAccountingVerification featuregate.Feature = "ElasticQuotaAccountingVerification"
+
+	// GangPreemption makes the CompatibleDefaultPreemption take the gangs of the victims as a unit, all the children
+	// of a victim gang are evicted together.
+	GangPreemption featuregate.Feature = "GangPreemption"
+
+	// GangBackoff rejects the other children of the gang in PreFilter once a child fails to be scheduled, until a
+	// node is added or a pod is deleted, so that the whole gang is requeued together.
+	GangBackoff featuregate.Feature = "GangBackoff"
+)
+
+// koord-scheduler features are registered to the feature gate of kube-scheduler,
+// so that they can be set by the --feature-gates flag of koord-scheduler.
+func init() {
+	runtime.Must(k8sfeature.DefaultMutableFeatureGate.Add(defaultSchedulerFeatureGates))
+}
+
+var defaultSchedulerFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
+	ElasticQuotaAccountingVerification: {Default: false, PreRelease: featuregate.Alpha},
+	GangPreemption:                     {Default: false, PreRelease: featuregate.Alpha},
+	GangBackoff:                        {Default: false, PreRelease: featuregate.Alpha},
+}
@@ -45,6 +45,8 @@ type Configuration struct {
 	RuntimeHookConf    *runtimehooks.Config
 	AuditConf          *audit.Config
 	FeatureGates       map[string]bool
+	// ResourceNameAliases maps the vendor-specific resource names onto the canonical device resource names
+	ResourceNameAliases map[string]string
 }
 
 func NewConfiguration() *Configuration {
@@ -70,6 +72,8 @@ func (c *Configuration) InitFlags(fs *flag.FlagSet) {
 	c.AuditConf.InitFlags(fs)
 	fs.Var(cliflag.NewMapStringBool(&c.FeatureGates), "feature-gates", "A set of key=value pairs that describe feature gates for alpha/experimental features. "+
 		"Options are:\n"+strings.Join(features.DefaultKoordletFeatureGate.KnownFeatures(), "\n"))
+	fs.Var(cliflag.NewMapStringString(&c.ResourceNameAliases), "resource-name-aliases", "A set of alias=name pairs that map the vendor-specific "+
+		"resource names onto the canonical device resource names, e.g. amd.com/gpu=nvidia.com/gpu.")
 }
 
 func (c *Configuration) InitClient() error {
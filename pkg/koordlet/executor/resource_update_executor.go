@@ -117,6 +117,16 @@ func (rm *ResourceUpdateExecutor) UpdateByCache(resource ResourceUpdater) (updat
 	return
 }
 
+// Forget drops the cached resources, so that they are updated in the next UpdateByCache even if the values are
+// not changed, e.g. the files are recreated.
+func (rm *ResourceUpdateExecutor) Forget(resources ...ResourceUpdater) {
+	rm.locker.Lock()
+	defer rm.locker.Unlock()
+	for _, resource := range resources {
+		rm.resourceCache.Delete(resource.Key())
+	}
+}
+
 func (rm *ResourceUpdateExecutor) UpdateWithoutErr(resourceUpdater ResourceUpdater) bool {
 	err := rm.Update(resourceUpdater)
 	if err != nil {
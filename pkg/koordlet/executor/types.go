@@ -143,6 +143,9 @@ type CgroupResourceUpdater struct {
 	file                system.CgroupFile
 	lastUpdateTimestamp time.Time
 	updateFunc          UpdateFunc
+	// device is the major:minor of the per-device files, e.g. blkio.throttle.read_iops_device, whose devices are
+	// updated independently
+	device string
 
 	// MergeableResourceUpdater implementation (used by LeveledCacheExecutor):
 	// For cgroup interfaces like `cpuset.cpus` and `memory.min`, reconciliation from top to bottom should keep the
@@ -164,7 +167,10 @@ func (c *CgroupResourceUpdater) Owner() *OwnerRef {
 }
 
 func (c *CgroupResourceUpdater) Key() string {
-	return system.GetCgroupFilePath(c.ParentDir, c.file)
+	if c.device != "" {
+		return system.GetCgroupFileKey(c.ParentDir, c.file) + "#" + c.device
+	}
+	return system.GetCgroupFileKey(c.ParentDir, c.file)
 }
 
 func (c *CgroupResourceUpdater) Value() string {
@@ -188,7 +194,7 @@ func (c *CgroupResourceUpdater) Update() error {
 }
 
 func (c *CgroupResourceUpdater) Clone() ResourceUpdater {
-	return &CgroupResourceUpdater{owner: c.owner, file: c.file, ParentDir: c.ParentDir, value: c.value, lastUpdateTimestamp: c.lastUpdateTimestamp, updateFunc: c.updateFunc}
+	return &CgroupResourceUpdater{owner: c.owner, file: c.file, ParentDir: c.ParentDir, value: c.value, lastUpdateTimestamp: c.lastUpdateTimestamp, updateFunc: c.updateFunc, device: c.device}
 }
 
 func (c *CgroupResourceUpdater) MergeUpdate() (MergeableResourceUpdater, error) {
@@ -228,6 +234,13 @@ func NewMergeableCgroupResourceUpdater(owner *OwnerRef, parentDir string, file s
 	return &CgroupResourceUpdater{owner: owner, file: file, ParentDir: parentDir, value: value, updateFunc: CommonCgroupUpdateFunc, mergeUpdateFunc: mergeUpdateFunc, needMerge: true}
 }
 
+// NewBlkioCgroupResourceUpdater returns a CgroupResourceUpdater of a device in the blkio throttle file, e.g. the
+// value "8:0 1000" of blkio.throttle.read_iops_device, where 0 removes the limit of the device.
+func NewBlkioCgroupResourceUpdater(owner *OwnerRef, parentDir string, file system.CgroupFile, device string, value int64) *CgroupResourceUpdater {
+	return &CgroupResourceUpdater{owner: owner, file: file, ParentDir: parentDir, value: system.BlkioDeviceValue(device, value),
+		updateFunc: CommonCgroupUpdateFunc, needMerge: false, device: device}
+}
+
 func GroupOwnerRef(name string) *OwnerRef {
 	return &OwnerRef{Type: GroupType, Name: name}
 }
@@ -109,3 +109,32 @@ type ContainerThrottledQueryResult struct {
 	QueryResult
 	Metric *ContainerThrottledMetric
 }
+
+// PSIMetric is the pressure stall information in percentage, the avg10 of the "some" and "full" lines of the cpu,
+// memory and io pressure.
+type PSIMetric struct {
+	CPUSomeAvg10 float64
+	MemSomeAvg10 float64
+	MemFullAvg10 float64
+	IOSomeAvg10  float64
+	IOFullAvg10  float64
+}
+
+type NodePSIMetric struct {
+	PSI PSIMetric
+}
+
+type NodePSIQueryResult struct {
+	QueryResult
+	Metric *NodePSIMetric
+}
+
+type PodPSIMetric struct {
+	PodUID string
+	PSI    PSIMetric
+}
+
+type PodPSIQueryResult struct {
+	QueryResult
+	Metric *PodPSIMetric
+}
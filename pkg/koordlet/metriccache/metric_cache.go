@@ -33,7 +33,10 @@ type AggregationFunc func(interface{}, AggregateParam) (float64, error)
 
 const (
 	AggregationTypeAVG   AggregationType = "AVG"
+	AggregationTypeP50   AggregationType = "P50"
 	AggregationTypeP90   AggregationType = "P90"
+	AggregationTypeP95   AggregationType = "P95"
+	AggregationTypeP99   AggregationType = "P99"
 	AggregationTypeLast  AggregationType = "last"
 	AggregationTypeCount AggregationType = "count"
 )
@@ -71,6 +74,8 @@ type MetricCache interface {
 	GetBECPUResourceMetric(param *QueryParam) BECPUResourceQueryResult
 	GetPodThrottledMetric(podUID *string, param *QueryParam) PodThrottledQueryResult
 	GetContainerThrottledMetric(containerID *string, param *QueryParam) ContainerThrottledQueryResult
+	GetNodePSIMetric(param *QueryParam) NodePSIQueryResult
+	GetPodPSIMetric(podUID *string, param *QueryParam) PodPSIQueryResult
 	InsertNodeResourceMetric(t time.Time, nodeResUsed *NodeResourceMetric) error
 	InsertPodResourceMetric(t time.Time, podResUsed *PodResourceMetric) error
 	InsertContainerResourceMetric(t time.Time, containerResUsed *ContainerResourceMetric) error
@@ -78,6 +83,8 @@ type MetricCache interface {
 	InsertBECPUResourceMetric(t time.Time, metric *BECPUResourceMetric) error
 	InsertPodThrottledMetrics(t time.Time, metric *PodThrottledMetric) error
 	InsertContainerThrottledMetrics(t time.Time, metric *ContainerThrottledMetric) error
+	InsertNodePSIMetric(t time.Time, metric *NodePSIMetric) error
+	InsertPodPSIMetric(t time.Time, metric *PodPSIMetric) error
 }
 
 type metricCache struct {
@@ -466,6 +473,98 @@ func (m *metricCache) GetContainerThrottledMetric(containerID *string, param *Qu
 	return result
 }
 
+func (m *metricCache) GetNodePSIMetric(param *QueryParam) NodePSIQueryResult {
+	result := NodePSIQueryResult{}
+	if param == nil || param.Start == nil || param.End == nil {
+		result.Error = fmt.Errorf("GetNodePSIMetric query parameters are illegal %v", param)
+		return result
+	}
+	metrics, err := m.db.GetNodePSIMetric(param.Start, param.End)
+	if err != nil {
+		result.Error = fmt.Errorf("GetNodePSIMetric failed, query params %v, error %v", param, err)
+		return result
+	}
+	if len(metrics) == 0 {
+		result.Error = fmt.Errorf("GetNodePSIMetric not exist, query params %v", param)
+		return result
+	}
+
+	psi, err := aggregatePSIMetric(metrics, getAggregateFunc(param.Aggregate))
+	if err != nil {
+		result.Error = fmt.Errorf("GetNodePSIMetric aggregate failed, metrics %v, error %v", metrics, err)
+		return result
+	}
+
+	count, err := count(metrics)
+	if err != nil {
+		result.Error = fmt.Errorf("GetNodePSIMetric aggregate count failed, metrics %v, error %v", metrics, err)
+		return result
+	}
+
+	result.AggregateInfo = &AggregateInfo{MetricsCount: int64(count)}
+	result.Metric = &NodePSIMetric{PSI: *psi}
+	return result
+}
+
+func (m *metricCache) GetPodPSIMetric(podUID *string, param *QueryParam) PodPSIQueryResult {
+	result := PodPSIQueryResult{}
+	if podUID == nil || param == nil || param.Start == nil || param.End == nil {
+		result.Error = fmt.Errorf("GetPodPSIMetric %v query parameters are illegal %v", podUID, param)
+		return result
+	}
+	metrics, err := m.db.GetPodPSIMetric(podUID, param.Start, param.End)
+	if err != nil {
+		result.Error = fmt.Errorf("GetPodPSIMetric %v failed, query params %v, error %v", *podUID, param, err)
+		return result
+	}
+	if len(metrics) == 0 {
+		result.Error = fmt.Errorf("GetPodPSIMetric %v not exist, query params %v", *podUID, param)
+		return result
+	}
+
+	psi, err := aggregatePSIMetric(metrics, getAggregateFunc(param.Aggregate))
+	if err != nil {
+		result.Error = fmt.Errorf("GetPodPSIMetric %v aggregate failed, metrics %v, error %v", *podUID, metrics, err)
+		return result
+	}
+
+	count, err := count(metrics)
+	if err != nil {
+		result.Error = fmt.Errorf("GetPodPSIMetric %v aggregate count failed, metrics %v, error %v",
+			*podUID, metrics, err)
+		return result
+	}
+
+	result.AggregateInfo = &AggregateInfo{MetricsCount: int64(count)}
+	result.Metric = &PodPSIMetric{
+		PodUID: *podUID,
+		PSI:    *psi,
+	}
+	return result
+}
+
+// aggregatePSIMetric aggregates each field of the psi metrics, which should be nodePSIMetric or podPSIMetric.
+func aggregatePSIMetric(metrics interface{}, aggregateFunc AggregationFunc) (*PSIMetric, error) {
+	psi := &PSIMetric{}
+	for _, field := range []struct {
+		name  string
+		value *float64
+	}{
+		{name: "CPUSomeAvg10", value: &psi.CPUSomeAvg10},
+		{name: "MemSomeAvg10", value: &psi.MemSomeAvg10},
+		{name: "MemFullAvg10", value: &psi.MemFullAvg10},
+		{name: "IOSomeAvg10", value: &psi.IOSomeAvg10},
+		{name: "IOFullAvg10", value: &psi.IOFullAvg10},
+	} {
+		value, err := aggregateFunc(metrics, AggregateParam{ValueFieldName: field.name, TimeFieldName: "Timestamp"})
+		if err != nil {
+			return nil, fmt.Errorf("aggregate %s failed, error %v", field.name, err)
+		}
+		*field.value = value
+	}
+	return psi, nil
+}
+
 func (m *metricCache) InsertNodeResourceMetric(t time.Time, nodeResUsed *NodeResourceMetric) error {
 	gpuUsages := make([]gpuResourceMetric, len(nodeResUsed.GPUs))
 	for idx, usage := range nodeResUsed.GPUs {
@@ -575,6 +674,31 @@ func (m *metricCache) InsertContainerThrottledMetrics(t time.Time, metric *Conta
 	return m.db.InsertContainerThrottledMetric(dbItem)
 }
 
+func (m *metricCache) InsertNodePSIMetric(t time.Time, metric *NodePSIMetric) error {
+	dbItem := &nodePSIMetric{
+		CPUSomeAvg10: metric.PSI.CPUSomeAvg10,
+		MemSomeAvg10: metric.PSI.MemSomeAvg10,
+		MemFullAvg10: metric.PSI.MemFullAvg10,
+		IOSomeAvg10:  metric.PSI.IOSomeAvg10,
+		IOFullAvg10:  metric.PSI.IOFullAvg10,
+		Timestamp:    t,
+	}
+	return m.db.InsertNodePSIMetric(dbItem)
+}
+
+func (m *metricCache) InsertPodPSIMetric(t time.Time, metric *PodPSIMetric) error {
+	dbItem := &podPSIMetric{
+		PodUID:       metric.PodUID,
+		CPUSomeAvg10: metric.PSI.CPUSomeAvg10,
+		MemSomeAvg10: metric.PSI.MemSomeAvg10,
+		MemFullAvg10: metric.PSI.MemFullAvg10,
+		IOSomeAvg10:  metric.PSI.IOSomeAvg10,
+		IOFullAvg10:  metric.PSI.IOFullAvg10,
+		Timestamp:    t,
+	}
+	return m.db.InsertPodPSIMetric(dbItem)
+}
+
 func (m *metricCache) aggregateGPUUsages(gpuResourceMetricsByTime [][]gpuResourceMetric, aggregateFunc AggregationFunc) ([]GPUMetric, error) {
 	if len(gpuResourceMetricsByTime) == 0 {
 		return nil, nil
@@ -641,6 +765,12 @@ func (m *metricCache) recycleDB() {
 	if err := m.db.DeleteContainerThrottledMetric(&oldTime, &expiredTime); err != nil {
 		klog.Warningf("DeleteContainerThrottledMetric failed during recycle, error %v", err)
 	}
+	if err := m.db.DeleteNodePSIMetric(&oldTime, &expiredTime); err != nil {
+		klog.Warningf("DeleteNodePSIMetric failed during recycle, error %v", err)
+	}
+	if err := m.db.DeletePodPSIMetric(&oldTime, &expiredTime); err != nil {
+		klog.Warningf("DeletePodPSIMetric failed during recycle, error %v", err)
+	}
 	// raw records do not need to cleanup
 	klog.Infof("expired metric data before %v has been recycled", expiredTime)
 }
@@ -649,8 +779,14 @@ func getAggregateFunc(aggregationType AggregationType) AggregationFunc {
 	switch aggregationType {
 	case AggregationTypeAVG:
 		return fieldAvgOfMetricList
+	case AggregationTypeP50:
+		return fieldP50OfMetricList
 	case AggregationTypeP90:
 		return fieldP90OfMetricList
+	case AggregationTypeP95:
+		return fieldP95OfMetricList
+	case AggregationTypeP99:
+		return fieldP99OfMetricList
 	case AggregationTypeLast:
 		return fieldLastOfMetricList
 	case AggregationTypeCount:
@@ -21,6 +21,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/koordinator-sh/koordinator/pkg/util"
@@ -1178,6 +1179,60 @@ func Test_metricCache_PodThrottledMetric_CRUD(t *testing.T) {
 	}
 }
 
+func Test_metricCache_PSIMetric_CRUD(t *testing.T) {
+	now := time.Now()
+	s, _ := NewCacheNotShareStorage()
+	m := &metricCache{
+		config: &Config{
+			MetricGCIntervalSeconds: 60,
+			MetricExpireSeconds:     60,
+		},
+		db: s,
+	}
+	nodeSamples := map[time.Time]NodePSIMetric{
+		now.Add(-time.Second * 120): {PSI: PSIMetric{CPUSomeAvg10: 50, MemFullAvg10: 20}},
+		now.Add(-time.Second * 10):  {PSI: PSIMetric{CPUSomeAvg10: 20, MemFullAvg10: 10, IOSomeAvg10: 4}},
+		now.Add(-time.Second * 5):   {PSI: PSIMetric{CPUSomeAvg10: 10, MemFullAvg10: 5, IOSomeAvg10: 2}},
+	}
+	for ts, sample := range nodeSamples {
+		assert.NoError(t, m.InsertNodePSIMetric(ts, &sample))
+	}
+	podSamples := map[time.Time]PodPSIMetric{
+		now.Add(-time.Second * 120): {PodUID: "pod-uid-1", PSI: PSIMetric{CPUSomeAvg10: 30}},
+		now.Add(-time.Second * 5):   {PodUID: "pod-uid-1", PSI: PSIMetric{CPUSomeAvg10: 15, MemSomeAvg10: 1}},
+		now.Add(-time.Second * 4):   {PodUID: "pod-uid-2", PSI: PSIMetric{CPUSomeAvg10: 60}},
+	}
+	for ts, sample := range podSamples {
+		assert.NoError(t, m.InsertPodPSIMetric(ts, &sample))
+	}
+
+	oldStartTime := time.Unix(0, 0)
+	lastParams := &QueryParam{Aggregate: AggregationTypeLast, Start: &oldStartTime, End: &now}
+	avgParams := &QueryParam{Aggregate: AggregationTypeAVG, Start: &oldStartTime, End: &now}
+
+	gotNode := m.GetNodePSIMetric(lastParams)
+	assert.NoError(t, gotNode.Error)
+	assert.Equal(t, &NodePSIMetric{PSI: PSIMetric{CPUSomeAvg10: 10, MemFullAvg10: 5, IOSomeAvg10: 2}}, gotNode.Metric)
+	assert.Equal(t, int64(3), gotNode.AggregateInfo.MetricsCount)
+	podUID := "pod-uid-1"
+	gotPod := m.GetPodPSIMetric(&podUID, lastParams)
+	assert.NoError(t, gotPod.Error)
+	assert.Equal(t, &PodPSIMetric{PodUID: podUID, PSI: PSIMetric{CPUSomeAvg10: 15, MemSomeAvg10: 1}}, gotPod.Metric)
+
+	// delete expire items
+	m.recycleDB()
+
+	gotNode = m.GetNodePSIMetric(avgParams)
+	assert.NoError(t, gotNode.Error)
+	assert.Equal(t, &NodePSIMetric{PSI: PSIMetric{CPUSomeAvg10: 15, MemFullAvg10: 7.5, IOSomeAvg10: 3}}, gotNode.Metric)
+	assert.Equal(t, int64(2), gotNode.AggregateInfo.MetricsCount)
+	gotPod = m.GetPodPSIMetric(&podUID, avgParams)
+	assert.NoError(t, gotPod.Error)
+	assert.Equal(t, int64(1), gotPod.AggregateInfo.MetricsCount)
+	notExistPodUID := "pod-uid-3"
+	assert.Error(t, m.GetPodPSIMetric(&notExistPodUID, avgParams).Error)
+}
+
 func Test_metricCache_aggregateGPUUsages(t *testing.T) {
 	type fields struct {
 		config *Config
@@ -108,6 +108,20 @@ func (mr *MockMetricCacheMockRecorder) GetNodeCPUInfo(param interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodeCPUInfo", reflect.TypeOf((*MockMetricCache)(nil).GetNodeCPUInfo), param)
 }
 
+// GetNodePSIMetric mocks base method.
+func (m *MockMetricCache) GetNodePSIMetric(param *metriccache.QueryParam) metriccache.NodePSIQueryResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNodePSIMetric", param)
+	ret0, _ := ret[0].(metriccache.NodePSIQueryResult)
+	return ret0
+}
+
+// GetNodePSIMetric indicates an expected call of GetNodePSIMetric.
+func (mr *MockMetricCacheMockRecorder) GetNodePSIMetric(param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodePSIMetric", reflect.TypeOf((*MockMetricCache)(nil).GetNodePSIMetric), param)
+}
+
 // GetNodeResourceMetric mocks base method.
 func (m *MockMetricCache) GetNodeResourceMetric(param *metriccache.QueryParam) metriccache.NodeResourceQueryResult {
 	m.ctrl.T.Helper()
@@ -122,6 +136,20 @@ func (mr *MockMetricCacheMockRecorder) GetNodeResourceMetric(param interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodeResourceMetric", reflect.TypeOf((*MockMetricCache)(nil).GetNodeResourceMetric), param)
 }
 
+// GetPodPSIMetric mocks base method.
+func (m *MockMetricCache) GetPodPSIMetric(podUID *string, param *metriccache.QueryParam) metriccache.PodPSIQueryResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPodPSIMetric", podUID, param)
+	ret0, _ := ret[0].(metriccache.PodPSIQueryResult)
+	return ret0
+}
+
+// GetPodPSIMetric indicates an expected call of GetPodPSIMetric.
+func (mr *MockMetricCacheMockRecorder) GetPodPSIMetric(podUID, param interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPodPSIMetric", reflect.TypeOf((*MockMetricCache)(nil).GetPodPSIMetric), podUID, param)
+}
+
 // GetPodResourceMetric mocks base method.
 func (m *MockMetricCache) GetPodResourceMetric(podUID *string, param *metriccache.QueryParam) metriccache.PodResourceQueryResult {
 	m.ctrl.T.Helper()
@@ -206,6 +234,20 @@ func (mr *MockMetricCacheMockRecorder) InsertNodeCPUInfo(info interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertNodeCPUInfo", reflect.TypeOf((*MockMetricCache)(nil).InsertNodeCPUInfo), info)
 }
 
+// InsertNodePSIMetric mocks base method.
+func (m *MockMetricCache) InsertNodePSIMetric(t time.Time, metric *metriccache.NodePSIMetric) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertNodePSIMetric", t, metric)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertNodePSIMetric indicates an expected call of InsertNodePSIMetric.
+func (mr *MockMetricCacheMockRecorder) InsertNodePSIMetric(t, metric interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertNodePSIMetric", reflect.TypeOf((*MockMetricCache)(nil).InsertNodePSIMetric), t, metric)
+}
+
 // InsertNodeResourceMetric mocks base method.
 func (m *MockMetricCache) InsertNodeResourceMetric(t time.Time, nodeResUsed *metriccache.NodeResourceMetric) error {
 	m.ctrl.T.Helper()
@@ -220,6 +262,20 @@ func (mr *MockMetricCacheMockRecorder) InsertNodeResourceMetric(t, nodeResUsed i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertNodeResourceMetric", reflect.TypeOf((*MockMetricCache)(nil).InsertNodeResourceMetric), t, nodeResUsed)
 }
 
+// InsertPodPSIMetric mocks base method.
+func (m *MockMetricCache) InsertPodPSIMetric(t time.Time, metric *metriccache.PodPSIMetric) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertPodPSIMetric", t, metric)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertPodPSIMetric indicates an expected call of InsertPodPSIMetric.
+func (mr *MockMetricCacheMockRecorder) InsertPodPSIMetric(t, metric interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertPodPSIMetric", reflect.TypeOf((*MockMetricCache)(nil).InsertPodPSIMetric), t, metric)
+}
+
 // InsertPodResourceMetric mocks base method.
 func (m *MockMetricCache) InsertPodResourceMetric(t time.Time, podResUsed *metriccache.PodResourceMetric) error {
 	m.ctrl.T.Helper()
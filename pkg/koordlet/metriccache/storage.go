@@ -42,6 +42,7 @@ func newStorage(dsn string) (*storage, error) {
 	db.AutoMigrate(&nodeResourceMetric{}, &podResourceMetric{}, &containerResourceMetric{}, &beCPUResourceMetric{})
 	db.AutoMigrate(&rawRecord{})
 	db.AutoMigrate(&podThrottledMetric{}, &containerThrottledMetric{})
+	db.AutoMigrate(&nodePSIMetric{}, &podPSIMetric{})
 
 	database, err := db.DB()
 	if err != nil {
@@ -86,6 +87,14 @@ func (s *storage) InsertContainerThrottledMetric(m *containerThrottledMetric) er
 	return s.db.Create(m).Error
 }
 
+func (s *storage) InsertNodePSIMetric(m *nodePSIMetric) error {
+	return s.db.Create(m).Error
+}
+
+func (s *storage) InsertPodPSIMetric(m *podPSIMetric) error {
+	return s.db.Create(m).Error
+}
+
 func (s *storage) GetNodeResourceMetric(start, end *time.Time) ([]nodeResourceMetric, error) {
 	var nodeMetrics []nodeResourceMetric
 	err := s.db.Where("timestamp BETWEEN ? AND ?", start, end).Find(&nodeMetrics).Error
@@ -130,6 +139,18 @@ func (s *storage) GetContainerThrottledMetric(id *string, start, end *time.Time)
 	return metrics, err
 }
 
+func (s *storage) GetNodePSIMetric(start, end *time.Time) ([]nodePSIMetric, error) {
+	var metrics []nodePSIMetric
+	err := s.db.Where("timestamp BETWEEN ? AND ?", start, end).Find(&metrics).Error
+	return metrics, err
+}
+
+func (s *storage) GetPodPSIMetric(uid *string, start, end *time.Time) ([]podPSIMetric, error) {
+	var metrics []podPSIMetric
+	err := s.db.Where("pod_uid = ? AND timestamp BETWEEN ? AND ?", uid, start, end).Find(&metrics).Error
+	return metrics, err
+}
+
 func (s *storage) DeleteNodeResourceMetric(start, end *time.Time) error {
 	return s.db.Where("timestamp BETWEEN ? AND ?", start, end).Delete(&nodeResourceMetric{}).Error
 }
@@ -153,3 +174,11 @@ func (s *storage) DeletePodThrottledMetric(start, end *time.Time) error {
 func (s *storage) DeleteContainerThrottledMetric(start, end *time.Time) error {
 	return s.db.Where("timestamp BETWEEN ? AND ?", start, end).Delete(&containerThrottledMetric{}).Error
 }
+
+func (s *storage) DeleteNodePSIMetric(start, end *time.Time) error {
+	return s.db.Where("timestamp BETWEEN ? AND ?", start, end).Delete(&nodePSIMetric{}).Error
+}
+
+func (s *storage) DeletePodPSIMetric(start, end *time.Time) error {
+	return s.db.Where("timestamp BETWEEN ? AND ?", start, end).Delete(&podPSIMetric{}).Error
+}
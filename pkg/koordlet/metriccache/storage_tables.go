@@ -101,6 +101,27 @@ type containerThrottledMetric struct {
 	Timestamp         time.Time
 }
 
+type nodePSIMetric struct {
+	ID           uint64 `gorm:"primarykey"`
+	CPUSomeAvg10 float64
+	MemSomeAvg10 float64
+	MemFullAvg10 float64
+	IOSomeAvg10  float64
+	IOFullAvg10  float64
+	Timestamp    time.Time
+}
+
+type podPSIMetric struct {
+	ID           uint64 `gorm:"primarykey"`
+	PodUID       string `gorm:"index:idx_pod_psi_uid"`
+	CPUSomeAvg10 float64
+	MemSomeAvg10 float64
+	MemFullAvg10 float64
+	IOSomeAvg10  float64
+	IOFullAvg10  float64
+	Timestamp    time.Time
+}
+
 type beCPUResourceMetric struct {
 	ID              uint64 `gorm:"primarykey"`
 	CPUUsedCores    float64
@@ -145,6 +145,18 @@ func fieldCountOfMetricList(metricsList interface{}, aggregateParam AggregatePar
 	return float64(metrics.Len()), nil
 }
 
+func fieldP50OfMetricList(metricsList interface{}, aggregateParam AggregateParam) (float64, error) {
+	return fieldPercentileOfMetricList(metricsList, aggregateParam, 0.50)
+}
+
 func fieldP90OfMetricList(metricsList interface{}, aggregateParam AggregateParam) (float64, error) {
 	return fieldPercentileOfMetricList(metricsList, aggregateParam, 0.90)
 }
+
+func fieldP95OfMetricList(metricsList interface{}, aggregateParam AggregateParam) (float64, error) {
+	return fieldPercentileOfMetricList(metricsList, aggregateParam, 0.95)
+}
+
+func fieldP99OfMetricList(metricsList interface{}, aggregateParam AggregateParam) (float64, error) {
+	return fieldPercentileOfMetricList(metricsList, aggregateParam, 0.99)
+}
@@ -42,11 +42,39 @@ var (
 		Help:      "Number of cores suppress by koordlet",
 	}, []string{NodeKey, BESuppressTypeKey})
 
+	KoordletSelfCPUUsage = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "self_cpu_usage_cores",
+		Help:      "Number of cores used by koordlet itself",
+	}, []string{NodeKey})
+
+	KoordletSelfMemoryRSS = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "self_memory_rss_bytes",
+		Help:      "Resident memory of koordlet itself in bytes",
+	}, []string{NodeKey})
+
+	CollectIntervalScale = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "collect_interval_scale",
+		Help:      "the scale of the collect interval, which is enlarged when koordlet exceeds its resource budget",
+	}, []string{NodeKey})
+
+	CollectDroppedSamples = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "collect_dropped_samples",
+		Help:      "Number of collect rounds dropped to keep koordlet within its resource budget",
+	}, []string{NodeKey})
+
 	CommonCollectors = []prometheus.Collector{
 		KoordletStartTime,
 		CollectNodeCPUInfoStatus,
 		PodEviction,
 		BESuppressCPU,
+		KoordletSelfCPUUsage,
+		KoordletSelfMemoryRSS,
+		CollectIntervalScale,
+		CollectDroppedSamples,
 	}
 )
 
@@ -86,3 +114,28 @@ func RecordBESuppressCores(suppressType string, value float64) {
 	labels[BESuppressTypeKey] = suppressType
 	BESuppressCPU.With(labels).Set(value)
 }
+
+func RecordKoordletSelfResource(cpuCores float64, memoryRSSBytes float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	KoordletSelfCPUUsage.With(labels).Set(cpuCores)
+	KoordletSelfMemoryRSS.With(labels).Set(memoryRSSBytes)
+}
+
+func RecordCollectIntervalScale(value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	CollectIntervalScale.With(labels).Set(value)
+}
+
+func RecordCollectDroppedSamples() {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	CollectDroppedSamples.With(labels).Inc()
+}
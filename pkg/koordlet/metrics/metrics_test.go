@@ -71,5 +71,8 @@ func TestCommonCollectors(t *testing.T) {
 		RecordCollectNodeCPUInfoStatus(nil)
 		RecordBESuppressCores("cfsQuota", float64(1000))
 		RecordPodEviction("evictByCPU")
+		RecordKoordletSelfResource(0.2, float64(100<<20))
+		RecordCollectIntervalScale(2)
+		RecordCollectDroppedSamples()
 	})
 }
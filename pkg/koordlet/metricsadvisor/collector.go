@@ -90,6 +90,7 @@ type collector struct {
 	metricCache    metriccache.MetricCache
 	context        *collectContext
 	state          *collectState
+	governor       *selfGovernor
 }
 
 func NewCollector(cfg *Config, statesInformer statesinformer.StatesInformer, metricCache metriccache.MetricCache) Collector {
@@ -103,6 +104,7 @@ func NewCollector(cfg *Config, statesInformer statesinformer.StatesInformer, met
 	if c.config == nil {
 		c.config = NewDefaultConfig()
 	}
+	c.governor = newSelfGovernor(c.config)
 
 	return c
 }
@@ -129,6 +131,9 @@ func (c *collector) Run(stopCh <-chan struct{}) error {
 	}
 
 	go wait.Until(func() {
+		if !c.governor.shouldCollect() {
+			return
+		}
 		c.collectGPUUsage()
 		c.collectNodeResUsed()
 		// add sync metaService cache check before collect pod information
@@ -142,6 +147,7 @@ func (c *collector) Run(stopCh <-chan struct{}) error {
 		c.collectBECPUResourceMetric()
 		c.collectPodResUsed()
 		c.collectPodThrottledInfo()
+		c.collectPSI()
 	}, time.Duration(c.config.CollectResUsedIntervalSeconds)*time.Second, stopCh)
 
 	go wait.Until(c.collectNodeCPUInfo, time.Duration(c.config.CollectNodeCPUInfoIntervalSeconds)*time.Second, stopCh)
@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsadvisor
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/system"
+)
+
+func (c *collector) collectPSI() {
+	if !features.DefaultKoordletFeatureGate.Enabled(features.PSICollector) {
+		return
+	}
+	c.collectNodePSI()
+	c.collectPodPSI()
+}
+
+func (c *collector) collectNodePSI() {
+	klog.V(6).Info("start collectNodePSI")
+	collectTime := time.Now()
+	psi, err := system.GetNodePSI()
+	if err != nil {
+		klog.V(4).Infof("failed to collect node psi, err: %v", err)
+		return
+	}
+	nodeMetric := &metriccache.NodePSIMetric{PSI: convertPSIMetric(psi)}
+	if err = c.metricCache.InsertNodePSIMetric(collectTime, nodeMetric); err != nil {
+		klog.Errorf("insert node psi metric error: %v", err)
+		return
+	}
+	klog.V(5).Infof("collectNodePSI finished %+v", nodeMetric)
+}
+
+func (c *collector) collectPodPSI() {
+	klog.V(6).Info("start collectPodPSI")
+	podMetas := c.statesInformer.GetAllPods()
+	for _, meta := range podMetas {
+		pod := meta.Pod
+		collectTime := time.Now()
+		psi, err := system.GetCgroupPSI(util.GetPodCgroupDirWithKube(meta.CgroupDir))
+		if err != nil {
+			if pod.Status.Phase == corev1.PodRunning {
+				klog.V(4).Infof("collect pod %s/%s psi failed, err %v", pod.Namespace, pod.Name, err)
+			}
+			continue
+		}
+		podMetric := &metriccache.PodPSIMetric{
+			PodUID: string(pod.UID),
+			PSI:    convertPSIMetric(psi),
+		}
+		if err = c.metricCache.InsertPodPSIMetric(collectTime, podMetric); err != nil {
+			klog.Errorf("insert pod %s/%s psi metric failed, metric %v, err %v", pod.Namespace, pod.Name, podMetric, err)
+		}
+	}
+	klog.V(5).Infof("collectPodPSI finished, pod num %d", len(podMetas))
+}
+
+func convertPSIMetric(psi *system.PSIByResource) metriccache.PSIMetric {
+	metric := metriccache.PSIMetric{
+		CPUSomeAvg10: psi.CPU.Some.Avg10,
+		MemSomeAvg10: psi.Mem.Some.Avg10,
+		IOSomeAvg10:  psi.IO.Some.Avg10,
+	}
+	if psi.Mem.Full != nil {
+		metric.MemFullAvg10 = psi.Mem.Full.Avg10
+	}
+	if psi.IO.Full != nil {
+		metric.IOFullAvg10 = psi.IO.Full.Avg10
+	}
+	return metric
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsadvisor
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/system"
+)
+
+func Test_collectPSI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	metricCache, _ := metriccache.NewCacheNotShareMetricCache(metriccache.NewDefaultConfig())
+	mockStatesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	c := collector{context: newCollectContext(), metricCache: metricCache, statesInformer: mockStatesInformer}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default", UID: "test-pod-uid"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	podCgroupDir := "kubepods.slice/kubepods-podtest_pod_uid.slice"
+	mockStatesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{{Pod: pod, CgroupDir: podCgroupDir}}).AnyTimes()
+
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(true)
+	helper.WriteProcSubFileContents(path.Join(system.ProcPressureDir, "cpu"), "some avg10=12.00 avg60=6.00 avg300=2.00 total=1000\n")
+	helper.WriteProcSubFileContents(path.Join(system.ProcPressureDir, "memory"),
+		"some avg10=8.00 avg60=4.00 avg300=2.00 total=100\nfull avg10=5.00 avg60=2.00 avg300=1.00 total=50\n")
+	helper.WriteProcSubFileContents(path.Join(system.ProcPressureDir, "io"),
+		"some avg10=3.00 avg60=2.00 avg300=1.00 total=300\nfull avg10=1.00 avg60=0.50 avg300=0.20 total=150\n")
+	podDir := util.GetPodCgroupDirWithKube(podCgroupDir)
+	helper.WriteCgroupFileContents(podDir, system.CPUAcctCPUPressure, "some avg10=30.00 avg60=6.00 avg300=2.00 total=1000\n")
+	helper.WriteCgroupFileContents(podDir, system.CPUAcctMemoryPressure,
+		"some avg10=2.00 avg60=1.00 avg300=0.00 total=10\nfull avg10=1.00 avg60=0.00 avg300=0.00 total=5\n")
+	helper.WriteCgroupFileContents(podDir, system.CPUAcctIOPressure,
+		"some avg10=0.00 avg60=0.00 avg300=0.00 total=0\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n")
+
+	oldStartTime := time.Unix(0, 0)
+	now := time.Now().Add(time.Second)
+	params := &metriccache.QueryParam{
+		Aggregate: metriccache.AggregationTypeLast,
+		Start:     &oldStartTime,
+		End:       &now,
+	}
+
+	// nothing is collected when the feature is disabled
+	c.collectPSI()
+	assert.Error(t, metricCache.GetNodePSIMetric(params).Error)
+
+	defer featuregatetesting.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.PSICollector, true)()
+	c.collectPSI()
+	now = time.Now().Add(time.Second)
+
+	gotNode := metricCache.GetNodePSIMetric(params)
+	assert.NoError(t, gotNode.Error)
+	assert.Equal(t, metriccache.PSIMetric{
+		CPUSomeAvg10: 12,
+		MemSomeAvg10: 8,
+		MemFullAvg10: 5,
+		IOSomeAvg10:  3,
+		IOFullAvg10:  1,
+	}, gotNode.Metric.PSI)
+	podUID := string(pod.UID)
+	gotPod := metricCache.GetPodPSIMetric(&podUID, params)
+	assert.NoError(t, gotPod.Error)
+	assert.Equal(t, metriccache.PSIMetric{
+		CPUSomeAvg10: 30,
+		MemSomeAvg10: 2,
+		MemFullAvg10: 1,
+	}, gotPod.Metric.PSI)
+}
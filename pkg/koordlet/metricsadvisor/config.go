@@ -21,16 +21,27 @@ import "flag"
 type Config struct {
 	CollectResUsedIntervalSeconds     int
 	CollectNodeCPUInfoIntervalSeconds int
+	// SelfCPUBudgetMilliCores and SelfMemoryBudgetMB bound the resource used by koordlet itself, the collect interval
+	// is enlarged up to MaxCollectIntervalScale times when koordlet exceeds the budget, zero means no budget.
+	SelfCPUBudgetMilliCores int
+	SelfMemoryBudgetMB      int
+	MaxCollectIntervalScale int
 }
 
 func NewDefaultConfig() *Config {
 	return &Config{
 		CollectResUsedIntervalSeconds:     1,
 		CollectNodeCPUInfoIntervalSeconds: 60,
+		SelfCPUBudgetMilliCores:           500,
+		SelfMemoryBudgetMB:                256,
+		MaxCollectIntervalScale:           8,
 	}
 }
 
 func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.IntVar(&c.CollectResUsedIntervalSeconds, "collect-res-used-interval-seconds", c.CollectResUsedIntervalSeconds, "Collect node/pod resource usage interval by seconds")
 	fs.IntVar(&c.CollectNodeCPUInfoIntervalSeconds, "collect-node-cpu-info-interval-seconds", c.CollectNodeCPUInfoIntervalSeconds, "Collect node cpu info interval by seconds")
+	fs.IntVar(&c.SelfCPUBudgetMilliCores, "self-cpu-budget-millicores", c.SelfCPUBudgetMilliCores, "CPU budget of koordlet itself by millicores, the collect interval is enlarged when exceeded, 0 means no budget")
+	fs.IntVar(&c.SelfMemoryBudgetMB, "self-memory-budget-mb", c.SelfMemoryBudgetMB, "Memory budget of koordlet itself by MB, the collect interval is enlarged when exceeded, 0 means no budget")
+	fs.IntVar(&c.MaxCollectIntervalScale, "max-collect-interval-scale", c.MaxCollectIntervalScale, "Max times the collect interval is enlarged when koordlet exceeds its resource budget")
 }
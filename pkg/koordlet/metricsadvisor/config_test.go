@@ -27,6 +27,9 @@ func Test_NewDefaultConfig(t *testing.T) {
 	expectConfig := &Config{
 		CollectResUsedIntervalSeconds:     1,
 		CollectNodeCPUInfoIntervalSeconds: 60,
+		SelfCPUBudgetMilliCores:           500,
+		SelfMemoryBudgetMB:                256,
+		MaxCollectIntervalScale:           8,
 	}
 	defaultConfig := NewDefaultConfig()
 	assert.Equal(t, expectConfig, defaultConfig)
@@ -37,12 +40,18 @@ func Test_InitFlags(t *testing.T) {
 		"",
 		"--collect-res-used-interval-seconds=3",
 		"--collect-node-cpu-info-interval-seconds=90",
+		"--self-cpu-budget-millicores=1000",
+		"--self-memory-budget-mb=512",
+		"--max-collect-interval-scale=4",
 	}
 	fs := flag.NewFlagSet(cmdArgs[0], flag.ExitOnError)
 
 	type fields struct {
 		CollectResUsedIntervalSeconds     int
 		CollectNodeCPUInfoIntervalSeconds int
+		SelfCPUBudgetMilliCores           int
+		SelfMemoryBudgetMB                int
+		MaxCollectIntervalScale           int
 	}
 	type args struct {
 		fs *flag.FlagSet
@@ -57,6 +66,9 @@ func Test_InitFlags(t *testing.T) {
 			fields: fields{
 				CollectResUsedIntervalSeconds:     3,
 				CollectNodeCPUInfoIntervalSeconds: 90,
+				SelfCPUBudgetMilliCores:           1000,
+				SelfMemoryBudgetMB:                512,
+				MaxCollectIntervalScale:           4,
 			},
 			args: args{fs: fs},
 		},
@@ -66,6 +78,9 @@ func Test_InitFlags(t *testing.T) {
 			raw := &Config{
 				CollectResUsedIntervalSeconds:     tt.fields.CollectResUsedIntervalSeconds,
 				CollectNodeCPUInfoIntervalSeconds: tt.fields.CollectNodeCPUInfoIntervalSeconds,
+				SelfCPUBudgetMilliCores:           tt.fields.SelfCPUBudgetMilliCores,
+				SelfMemoryBudgetMB:                tt.fields.SelfMemoryBudgetMB,
+				MaxCollectIntervalScale:           tt.fields.MaxCollectIntervalScale,
 			}
 			c := NewDefaultConfig()
 			c.InitFlags(tt.args.fs)
@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsadvisor
+
+import (
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+var (
+	selfCPUUsageTicksGetter = util.GetSelfCPUUsageTicks
+	selfMemoryRSSGetter     = util.GetSelfMemoryRSSBytes
+)
+
+// selfGovernor keeps koordlet within its own resource budget, since the agent competing with the LS pods defeats
+// its purpose. When koordlet exceeds the budget, the collect interval is doubled up to maxScale times by dropping
+// the collect rounds, and it's halved back when koordlet uses less than half of the budget.
+type selfGovernor struct {
+	cpuBudgetMilliCores int64
+	memoryBudgetBytes   uint64
+	maxScale            int
+
+	scale       int
+	skipped     int
+	lastCPUStat contextRecord
+}
+
+func newSelfGovernor(cfg *Config) *selfGovernor {
+	maxScale := cfg.MaxCollectIntervalScale
+	if maxScale < 1 {
+		maxScale = 1
+	}
+	return &selfGovernor{
+		cpuBudgetMilliCores: int64(cfg.SelfCPUBudgetMilliCores),
+		memoryBudgetBytes:   uint64(cfg.SelfMemoryBudgetMB) * 1024 * 1024,
+		maxScale:            maxScale,
+		scale:               1,
+	}
+}
+
+// shouldCollect adjusts the scale by the resource used by koordlet and returns whether the current round should
+// collect, the dropped rounds are accounted in the metrics.
+func (g *selfGovernor) shouldCollect() bool {
+	g.adjust()
+	if g.skipped+1 >= g.scale {
+		g.skipped = 0
+		return true
+	}
+	g.skipped++
+	metrics.RecordCollectDroppedSamples()
+	return false
+}
+
+func (g *selfGovernor) adjust() {
+	collectTime := time.Now()
+	cpuTick, err0 := selfCPUUsageTicksGetter()
+	rss, err1 := selfMemoryRSSGetter()
+	if err0 != nil || err1 != nil {
+		klog.V(5).Infof("failed to collect koordlet self usage, CPU err: %s, Memory err: %s", err0, err1)
+		return
+	}
+	lastCPUStat := g.lastCPUStat
+	g.lastCPUStat = contextRecord{
+		cpuTick: cpuTick,
+		ts:      collectTime,
+	}
+	if lastCPUStat.cpuTick <= 0 || !collectTime.After(lastCPUStat.ts) {
+		return
+	}
+	// NOTICE: do subtraction and division first to avoid overflow
+	cpuUsage := float64(cpuTick-lastCPUStat.cpuTick) / float64(collectTime.Sub(lastCPUStat.ts)) * jiffies
+	metrics.RecordKoordletSelfResource(cpuUsage, float64(rss))
+
+	cpuUsageMilliCores := int64(cpuUsage * 1000)
+	overBudget := (g.cpuBudgetMilliCores > 0 && cpuUsageMilliCores > g.cpuBudgetMilliCores) ||
+		(g.memoryBudgetBytes > 0 && rss > g.memoryBudgetBytes)
+	underHalfBudget := (g.cpuBudgetMilliCores <= 0 || cpuUsageMilliCores*2 < g.cpuBudgetMilliCores) &&
+		(g.memoryBudgetBytes <= 0 || rss*2 < g.memoryBudgetBytes)
+
+	oldScale := g.scale
+	if overBudget && g.scale < g.maxScale {
+		g.scale *= 2
+		if g.scale > g.maxScale {
+			g.scale = g.maxScale
+		}
+	} else if underHalfBudget && g.scale > 1 {
+		g.scale /= 2
+	}
+	if g.scale != oldScale {
+		klog.Infof("koordlet uses cpu %vm memory %vB, collect interval scale changes from %v to %v",
+			cpuUsageMilliCores, rss, oldScale, g.scale)
+	}
+	metrics.RecordCollectIntervalScale(float64(g.scale))
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsadvisor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_selfGovernor(t *testing.T) {
+	oldCPUGetter, oldMemoryGetter := selfCPUUsageTicksGetter, selfMemoryRSSGetter
+	defer func() {
+		selfCPUUsageTicksGetter, selfMemoryRSSGetter = oldCPUGetter, oldMemoryGetter
+	}()
+	var cpuTick, rss uint64 = 1000, 100 * 1024 * 1024
+	selfCPUUsageTicksGetter = func() (uint64, error) {
+		return cpuTick, nil
+	}
+	selfMemoryRSSGetter = func() (uint64, error) {
+		return rss, nil
+	}
+
+	g := newSelfGovernor(&Config{
+		SelfCPUBudgetMilliCores: 500,
+		SelfMemoryBudgetMB:      256,
+		MaxCollectIntervalScale: 4,
+	})
+	// the first round only records the cpu stat
+	assert.True(t, g.shouldCollect())
+	assert.Equal(t, 1, g.scale)
+
+	// exceed the memory budget, the interval is doubled and the next round is dropped
+	rss = 300 * 1024 * 1024
+	g.lastCPUStat.ts = g.lastCPUStat.ts.Add(-time.Second)
+	assert.False(t, g.shouldCollect())
+	assert.Equal(t, 2, g.scale)
+	g.lastCPUStat.ts = g.lastCPUStat.ts.Add(-time.Second)
+	g.shouldCollect()
+	assert.Equal(t, 4, g.scale)
+	g.lastCPUStat.ts = g.lastCPUStat.ts.Add(-time.Second)
+	g.shouldCollect()
+	assert.Equal(t, 4, g.scale, "limited by the max scale")
+
+	// drop 3 rounds in 4
+	g.skipped = 0
+	collected := 0
+	for i := 0; i < 8; i++ {
+		g.lastCPUStat.ts = g.lastCPUStat.ts.Add(-time.Second)
+		if g.shouldCollect() {
+			collected++
+		}
+	}
+	assert.Equal(t, 2, collected)
+
+	// less than half of the budget, the interval is halved
+	rss = 100 * 1024 * 1024
+	g.lastCPUStat.ts = g.lastCPUStat.ts.Add(-time.Second)
+	g.shouldCollect()
+	assert.Equal(t, 2, g.scale)
+
+	// exceed the cpu budget with 1 core
+	rss = 200 * 1024 * 1024
+	cpuTick += uint64(float64(time.Second) / jiffies)
+	g.lastCPUStat.ts = g.lastCPUStat.ts.Add(-time.Second)
+	g.shouldCollect()
+	assert.Equal(t, 4, g.scale)
+}
@@ -138,6 +138,10 @@ func (p *pleg) Run(stopCh <-chan struct{}) error {
 		// here we choose cpu subsystem as ground truth,
 		// since we only need to watch one of all subsystems, and cpu subsystem always and must exist
 		cgroupPath := path.Join(p.cgroupRootPath, system.CgroupCPUDir, util.GetPodQoSRelativePath(qosClass))
+		if system.IsCgroupV2() {
+			// all the subsystems share the same hierarchy on the cgroup v2
+			cgroupPath = path.Join(p.cgroupRootPath, util.GetPodQoSRelativePath(qosClass))
+		}
 		err := p.podWatcher.AddWatch(cgroupPath)
 		if err != nil {
 			klog.Errorf("failed to watch path %v err %v", cgroupPath, err)
@@ -19,10 +19,15 @@ package reporter
 import (
 	"flag"
 	"time"
+
+	cliflag "k8s.io/component-base/cli/flag"
 )
 
 type Config struct {
 	ReportInterval time.Duration
+	// LocalStoragePaths maps the storage classes of the local PVs to the mount points of the local storages,
+	// whose capacity and IO utilization are reported in NodeMetric.
+	LocalStoragePaths map[string]string
 }
 
 func NewDefaultConfig() *Config {
@@ -33,4 +38,6 @@ func NewDefaultConfig() *Config {
 
 func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.DurationVar(&c.ReportInterval, "report-interval", c.ReportInterval, "Report interval time. Non-zero values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
+	fs.Var(cliflag.NewMapStringString(&c.LocalStoragePaths), "local-storage-paths", "A set of storageClass=path pairs of the local storages "+
+		"backing the local PVs, e.g. local-ssd=/mnt/disks/ssd0.")
 }
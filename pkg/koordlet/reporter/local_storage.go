@@ -0,0 +1,159 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reporter
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util/system"
+)
+
+const diskStatsFileName = "diskstats"
+
+// the fields of /proc/diskstats, see https://www.kernel.org/doc/Documentation/ABI/testing/procfs-diskstats
+const (
+	diskStatsFieldMajor   = 0
+	diskStatsFieldMinor   = 1
+	diskStatsFieldIOTicks = 12
+)
+
+// localStorageStat is the capacity of a local storage and the device backing it.
+type localStorageStat struct {
+	capacity uint64
+	free     uint64
+	device   string
+}
+
+type ioTicksSample struct {
+	ioTicks   uint64
+	timestamp time.Time
+}
+
+// localStorageCollector collects the capacity and the IO utilization of the local storages from which the local PVs
+// are provisioned. The IO utilization is the increment of the io_ticks of the device since the last collection.
+type localStorageCollector struct {
+	// paths maps the storage class to the mount point of the local storage
+	paths map[string]string
+
+	lock        sync.Mutex
+	lastSamples map[string]ioTicksSample
+}
+
+func newLocalStorageCollector(paths map[string]string) *localStorageCollector {
+	if len(paths) == 0 {
+		return nil
+	}
+	return &localStorageCollector{
+		paths:       paths,
+		lastSamples: map[string]ioTicksSample{},
+	}
+}
+
+func (c *localStorageCollector) collect(now time.Time) []slov1alpha1.LocalStorageInfo {
+	if c == nil {
+		return nil
+	}
+	ioTicks, err := readDiskIOTicks(filepath.Join(system.Conf.ProcRootDir, diskStatsFileName))
+	if err != nil {
+		klog.Warningf("failed to read the disk stats, err: %v", err)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	storageClassNames := make([]string, 0, len(c.paths))
+	for storageClassName := range c.paths {
+		storageClassNames = append(storageClassNames, storageClassName)
+	}
+	sort.Strings(storageClassNames)
+
+	var result []slov1alpha1.LocalStorageInfo
+	for _, storageClassName := range storageClassNames {
+		path := c.paths[storageClassName]
+		stat, err := getLocalStorageStat(path)
+		if err != nil {
+			klog.Warningf("failed to stat the local storage %s of %s, err: %v", path, storageClassName, err)
+			continue
+		}
+		info := slov1alpha1.LocalStorageInfo{
+			StorageClassName: storageClassName,
+			Path:             path,
+			Capacity:         *resource.NewQuantity(int64(stat.capacity), resource.BinarySI),
+			Free:             *resource.NewQuantity(int64(stat.free), resource.BinarySI),
+		}
+		if ticks, ok := ioTicks[stat.device]; ok {
+			info.IOUtilization = c.updateIOUtilization(stat.device, ticks, now)
+		}
+		result = append(result, info)
+	}
+	return result
+}
+
+// updateIOUtilization records the io_ticks of the device and returns the percentage of the time the device was busy
+// since the last sample.
+func (c *localStorageCollector) updateIOUtilization(device string, ioTicks uint64, now time.Time) int64 {
+	last, ok := c.lastSamples[device]
+	c.lastSamples[device] = ioTicksSample{ioTicks: ioTicks, timestamp: now}
+	if !ok || ioTicks < last.ioTicks || !now.After(last.timestamp) {
+		return 0
+	}
+	elapsed := now.Sub(last.timestamp).Milliseconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	utilization := int64(ioTicks-last.ioTicks) * 100 / elapsed
+	if utilization > 100 {
+		utilization = 100
+	}
+	return utilization
+}
+
+// readDiskIOTicks returns the io_ticks (milliseconds spent doing IO) of the devices keyed by "major:minor".
+func readDiskIOTicks(path string) (map[string]uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	result := map[string]uint64{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) <= diskStatsFieldIOTicks {
+			continue
+		}
+		ioTicks, err := strconv.ParseUint(fields[diskStatsFieldIOTicks], 10, 64)
+		if err != nil {
+			continue
+		}
+		result[fmt.Sprintf("%s:%s", fields[diskStatsFieldMajor], fields[diskStatsFieldMinor])] = ioTicks
+	}
+	return result, scanner.Err()
+}
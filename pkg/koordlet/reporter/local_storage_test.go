@@ -0,0 +1,59 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reporter
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_readDiskIOTicks(t *testing.T) {
+	diskStats := `   8       0 sda 1000 10 20000 300 2000 20 40000 600 0 1500 900 0 0 0 0
+   8       1 sda1 900 10 18000 280 1900 20 38000 580 0 1400 860 0 0 0 0
+ 253       0 dm-0 500 0 10000 100 800 0 16000 200 2 700 300
+`
+	path := filepath.Join(t.TempDir(), diskStatsFileName)
+	assert.NoError(t, ioutil.WriteFile(path, []byte(diskStats), 0644))
+
+	ioTicks, err := readDiskIOTicks(path)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]uint64{
+		"8:0":   1500,
+		"8:1":   1400,
+		"253:0": 700,
+	}, ioTicks)
+}
+
+func Test_localStorageCollector_updateIOUtilization(t *testing.T) {
+	assert.Nil(t, newLocalStorageCollector(nil))
+	assert.Nil(t, (*localStorageCollector)(nil).collect(time.Now()))
+
+	c := newLocalStorageCollector(map[string]string{"local-ssd": "/mnt/disks/ssd0"})
+	now := time.Now()
+	// no utilization without the last sample
+	assert.Equal(t, int64(0), c.updateIOUtilization("8:1", 1000, now))
+	// busy for 3s of 10s
+	assert.Equal(t, int64(30), c.updateIOUtilization("8:1", 4000, now.Add(10*time.Second)))
+	// capped at 100
+	assert.Equal(t, int64(100), c.updateIOUtilization("8:1", 20000, now.Add(20*time.Second)))
+	// the counter is reset
+	assert.Equal(t, int64(0), c.updateIOUtilization("8:1", 100, now.Add(30*time.Second)))
+}
@@ -70,8 +70,9 @@ type reporter struct {
 	eventRecorder      record.EventRecorder
 	statusUpdater      *statusUpdater
 
-	statesInformer statesinformer.StatesInformer
-	metricCache    metriccache.MetricCache
+	statesInformer        statesinformer.StatesInformer
+	metricCache           metriccache.MetricCache
+	localStorageCollector *localStorageCollector
 
 	rwMutex    sync.RWMutex
 	nodeMetric *slov1alpha1.NodeMetric
@@ -96,6 +97,8 @@ func NewReporter(cfg *Config, kubeClient *clientset.Clientset, crdClient *client
 		statusUpdater:      newStatusUpdater(crdClient.SloV1alpha1().NodeMetrics()),
 		statesInformer:     statesInformer,
 		metricCache:        metricCache,
+		// nil if no local storage is configured
+		localStorageCollector: newLocalStorageCollector(cfg.LocalStoragePaths),
 	}
 
 	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -297,10 +300,73 @@ func (r *reporter) collectNodeMetric(queryParam *metriccache.QueryParam) *slov1a
 		return nil
 	}
 	return &slov1alpha1.NodeMetricInfo{
-		NodeUsage: *convertNodeMetricToResourceMap(queryResult.Metric),
+		NodeUsage:            *translateResourceMapAliases(convertNodeMetricToResourceMap(queryResult.Metric)),
+		AggregatedNodeUsages: r.collectNodeAggregateMetric(queryParam.End),
+		LocalStorages:        r.localStorageCollector.collect(time.Now()),
 	}
 }
 
+// aggregationTypesOfMetricCache maps the aggregation types of the NodeMetric to the metric cache.
+var aggregationTypesOfMetricCache = map[slov1alpha1.AggregationType]metriccache.AggregationType{
+	slov1alpha1.AVG: metriccache.AggregationTypeAVG,
+	slov1alpha1.P50: metriccache.AggregationTypeP50,
+	slov1alpha1.P90: metriccache.AggregationTypeP90,
+	slov1alpha1.P95: metriccache.AggregationTypeP95,
+	slov1alpha1.P99: metriccache.AggregationTypeP99,
+}
+
+// collectNodeAggregateMetric aggregates the node usage over each duration of the NodeAggregatePolicy. The
+// aggregation type is skipped if the query fails, and the duration is skipped if all the queries fail.
+func (r *reporter) collectNodeAggregateMetric(end *time.Time) []slov1alpha1.AggregatedUsage {
+	aggregatePolicy := r.getNodeAggregatePolicy()
+	if aggregatePolicy == nil || len(aggregatePolicy.Durations) == 0 {
+		return nil
+	}
+	if end == nil {
+		now := time.Now()
+		end = &now
+	}
+
+	var aggregatedUsages []slov1alpha1.AggregatedUsage
+	for _, duration := range aggregatePolicy.Durations {
+		if duration.Duration <= 0 {
+			continue
+		}
+		start := end.Add(-duration.Duration)
+		usage := map[slov1alpha1.AggregationType]slov1alpha1.ResourceMap{}
+		for aggregationType, metricAggregationType := range aggregationTypesOfMetricCache {
+			queryResult := r.metricCache.GetNodeResourceMetric(&metriccache.QueryParam{
+				Aggregate: metricAggregationType,
+				Start:     &start,
+				End:       end,
+			})
+			if queryResult.Error != nil || queryResult.Metric == nil {
+				klog.V(5).Infof("get node resource metric of %v in %v failed, error %v",
+					aggregationType, duration.Duration, queryResult.Error)
+				continue
+			}
+			usage[aggregationType] = *translateResourceMapAliases(convertNodeMetricToResourceMap(queryResult.Metric))
+		}
+		if len(usage) == 0 {
+			continue
+		}
+		aggregatedUsages = append(aggregatedUsages, slov1alpha1.AggregatedUsage{
+			Usage:    usage,
+			Duration: duration,
+		})
+	}
+	return aggregatedUsages
+}
+
+func (r *reporter) getNodeAggregatePolicy() *slov1alpha1.AggregatePolicy {
+	r.rwMutex.RLock()
+	defer r.rwMutex.RUnlock()
+	if r.nodeMetric == nil || r.nodeMetric.Spec.CollectPolicy == nil {
+		return nil
+	}
+	return r.nodeMetric.Spec.CollectPolicy.NodeAggregatePolicy
+}
+
 func (r *reporter) collectPodMetric(podMeta *statesinformer.PodMeta, queryParam *metriccache.QueryParam) *slov1alpha1.PodMetricInfo {
 	if podMeta == nil || podMeta.Pod == nil {
 		return nil
@@ -318,7 +384,7 @@ func (r *reporter) collectPodMetric(podMeta *statesinformer.PodMeta, queryParam
 	return &slov1alpha1.PodMetricInfo{
 		Namespace: podMeta.Pod.Namespace,
 		Name:      podMeta.Pod.Name,
-		PodUsage:  *convertPodMetricToResourceMap(queryResult.Metric),
+		PodUsage:  *translateResourceMapAliases(convertPodMetricToResourceMap(queryResult.Metric)),
 	}
 }
 
@@ -374,13 +440,46 @@ func convertNodeMetricToResourceMap(nodeMetric *metriccache.NodeResourceMetric)
 			deviceInfos = append(deviceInfos, gpuInfo)
 		}
 	}
+	resourceList := corev1.ResourceList{
+		corev1.ResourceCPU:    nodeMetric.CPUUsed.CPUUsed,
+		corev1.ResourceMemory: nodeMetric.MemoryUsed.MemoryWithoutCache,
+	}
+	addGPUUsage(resourceList, deviceInfos)
 	return &slov1alpha1.ResourceMap{
-		ResourceList: corev1.ResourceList{
-			corev1.ResourceCPU:    nodeMetric.CPUUsed.CPUUsed,
-			corev1.ResourceMemory: nodeMetric.MemoryUsed.MemoryWithoutCache,
-		},
-		Devices: deviceInfos,
+		ResourceList: resourceList,
+		Devices:      deviceInfos,
+	}
+}
+
+// addGPUUsage sums the usage of the GPUs as the usage of the gpu-core, gpu-memory and gpu-memory-ratio, which are
+// in the same units as the allocatable of the node, so the GPU utilization can be filtered and scored like cpu.
+func addGPUUsage(resourceList corev1.ResourceList, deviceInfos []schedulingv1alpha1.DeviceInfo) {
+	if len(deviceInfos) == 0 {
+		return
+	}
+	gpuCore := resource.NewQuantity(0, resource.DecimalSI)
+	gpuMemory := resource.NewQuantity(0, resource.BinarySI)
+	gpuMemoryRatio := resource.NewQuantity(0, resource.DecimalSI)
+	for _, deviceInfo := range deviceInfos {
+		if deviceInfo.Type != schedulingv1alpha1.GPU {
+			continue
+		}
+		gpuCore.Add(deviceInfo.Resources[apiext.GPUCore])
+		gpuMemory.Add(deviceInfo.Resources[apiext.GPUMemory])
+		gpuMemoryRatio.Add(deviceInfo.Resources[apiext.GPUMemoryRatio])
+	}
+	resourceList[apiext.GPUCore] = *gpuCore
+	resourceList[apiext.GPUMemory] = *gpuMemory
+	resourceList[apiext.GPUMemoryRatio] = *gpuMemoryRatio
+}
+
+// translateResourceMapAliases reports the aliased resources with the canonical names, the same as the scheduler counts them.
+func translateResourceMapAliases(resourceMap *slov1alpha1.ResourceMap) *slov1alpha1.ResourceMap {
+	resourceMap.ResourceList = apiext.TranslateResourceNameAliases(resourceMap.ResourceList)
+	for i := range resourceMap.Devices {
+		resourceMap.Devices[i].Resources = apiext.TranslateResourceNameAliases(resourceMap.Devices[i].Resources)
 	}
+	return resourceMap
 }
 
 func convertPodMetricToResourceMap(podMetric *metriccache.PodResourceMetric) *slov1alpha1.ResourceMap {
@@ -402,11 +501,13 @@ func convertPodMetricToResourceMap(podMetric *metriccache.PodResourceMetric) *sl
 			deviceInfos = append(deviceInfos, gpuInfo)
 		}
 	}
+	resourceList := corev1.ResourceList{
+		corev1.ResourceCPU:    podMetric.CPUUsed.CPUUsed,
+		corev1.ResourceMemory: podMetric.MemoryUsed.MemoryWithoutCache,
+	}
+	addGPUUsage(resourceList, deviceInfos)
 	return &slov1alpha1.ResourceMap{
-		ResourceList: corev1.ResourceList{
-			corev1.ResourceCPU:    podMetric.CPUUsed.CPUUsed,
-			corev1.ResourceMemory: podMetric.MemoryUsed.MemoryWithoutCache,
-		},
-		Devices: deviceInfos,
+		ResourceList: resourceList,
+		Devices:      deviceInfos,
 	}
 }
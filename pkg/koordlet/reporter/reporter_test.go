@@ -32,6 +32,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	clientbeta1 "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/typed/slo/v1alpha1"
 	fakeclientslov1alpha1 "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/typed/slo/v1alpha1/fake"
@@ -431,3 +432,96 @@ func Test_reporter_sync(t *testing.T) {
 		})
 	}
 }
+
+func Test_reporter_collectNodeAggregateMetric(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mock_metriccache.NewMockMetricCache(ctrl)
+	c.EXPECT().GetNodeResourceMetric(gomock.Any()).DoAndReturn(func(param *metriccache.QueryParam) metriccache.NodeResourceQueryResult {
+		assert.Equal(t, 5*time.Minute, param.End.Sub(*param.Start))
+		if param.Aggregate == metriccache.AggregationTypeP99 {
+			return metriccache.NodeResourceQueryResult{}
+		}
+		cpu := resource.MustParse("1")
+		if param.Aggregate == metriccache.AggregationTypeP95 {
+			cpu = resource.MustParse("3")
+		}
+		return metriccache.NodeResourceQueryResult{
+			Metric: &metriccache.NodeResourceMetric{
+				CPUUsed: metriccache.CPUMetric{
+					CPUUsed: cpu,
+				},
+				MemoryUsed: metriccache.MemoryMetric{
+					MemoryWithoutCache: resource.MustParse("1Gi"),
+				},
+			},
+		}
+	}).Times(5)
+
+	r := &reporter{
+		metricCache: c,
+	}
+	assert.Nil(t, r.collectNodeAggregateMetric(nil))
+
+	r.nodeMetric = &slov1alpha1.NodeMetric{
+		Spec: slov1alpha1.NodeMetricSpec{
+			CollectPolicy: &slov1alpha1.NodeMetricCollectPolicy{
+				NodeAggregatePolicy: &slov1alpha1.AggregatePolicy{
+					Durations: []metav1.Duration{
+						{Duration: 5 * time.Minute},
+					},
+				},
+			},
+		},
+	}
+	got := r.collectNodeAggregateMetric(nil)
+	assert.Len(t, got, 1)
+	assert.Equal(t, 5*time.Minute, got[0].Duration.Duration)
+	assert.Len(t, got[0].Usage, 4)
+	p95 := got[0].Usage[slov1alpha1.P95].ResourceList[v1.ResourceCPU]
+	assert.Equal(t, int64(3000), p95.MilliValue())
+	avg := got[0].Usage[slov1alpha1.AVG].ResourceList[v1.ResourceCPU]
+	assert.Equal(t, int64(1000), avg.MilliValue())
+	_, ok := got[0].Usage[slov1alpha1.P99]
+	assert.False(t, ok)
+}
+
+func Test_convertNodeMetricToResourceMap_GPU(t *testing.T) {
+	resourceMap := convertNodeMetricToResourceMap(&metriccache.NodeResourceMetric{
+		CPUUsed: metriccache.CPUMetric{
+			CPUUsed: resource.MustParse("1"),
+		},
+		MemoryUsed: metriccache.MemoryMetric{
+			MemoryWithoutCache: resource.MustParse("1Gi"),
+		},
+		GPUs: []metriccache.GPUMetric{
+			{
+				DeviceUUID:  "1",
+				Minor:       0,
+				SMUtil:      80,
+				MemoryUsed:  *resource.NewQuantity(30, resource.BinarySI),
+				MemoryTotal: *resource.NewQuantity(100, resource.BinarySI),
+			},
+			{
+				DeviceUUID:  "2",
+				Minor:       1,
+				SMUtil:      40,
+				MemoryUsed:  *resource.NewQuantity(50, resource.BinarySI),
+				MemoryTotal: *resource.NewQuantity(200, resource.BinarySI),
+			},
+		},
+	})
+	assert.Len(t, resourceMap.Devices, 2)
+	gpuCore := resourceMap.ResourceList[apiext.GPUCore]
+	assert.Equal(t, int64(120), gpuCore.Value())
+	gpuMemory := resourceMap.ResourceList[apiext.GPUMemory]
+	assert.Equal(t, int64(80), gpuMemory.Value())
+	gpuMemoryRatio := resourceMap.ResourceList[apiext.GPUMemoryRatio]
+	assert.Equal(t, int64(55), gpuMemoryRatio.Value())
+
+	// no gpu usage is reported without GPUs
+	resourceMap = convertNodeMetricToResourceMap(&metriccache.NodeResourceMetric{})
+	_, ok := resourceMap.ResourceList[apiext.GPUCore]
+	assert.False(t, ok)
+}
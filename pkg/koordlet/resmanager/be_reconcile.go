@@ -17,7 +17,6 @@ limitations under the License.
 package resmanager
 
 import (
-	"io/ioutil"
 	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
@@ -290,9 +289,9 @@ func applyPodBECPULimitIfSpecified(podMeta *statesinformer.PodMeta) error {
 		return err
 	}
 	targetCFSQuota := int(float64(milliCPULimit*podCFSPeriod) / float64(1000))
-	podCFSQuotaPath := util.GetPodCgroupCFSQuotaPath(podMeta.CgroupDir)
+	podDir := util.GetPodCgroupDirWithKube(podMeta.CgroupDir)
 	_ = audit.V(2).Pod(podMeta.Pod.Namespace, podMeta.Pod.Name).Reason(executor.UpdateCPU).Message("set cfs_quota to %v", targetCFSQuota).Do()
-	return ioutil.WriteFile(podCFSQuotaPath, []byte(strconv.Itoa(targetCFSQuota)), 0644)
+	return system.CgroupFileWrite(podDir, system.CPUCFSQuota, strconv.Itoa(targetCFSQuota))
 }
 
 func applyContainerBECPULimitIfSpecified(podMeta *statesinformer.PodMeta, container *corev1.Container, containerStatus *corev1.ContainerStatus) error {
@@ -305,12 +304,12 @@ func applyContainerBECPULimitIfSpecified(podMeta *statesinformer.PodMeta, contai
 		return err
 	}
 	targetCFSQuota := int(float64(milliCPULimit*containerCFSPeriod) / float64(1000))
-	containerCFSQuotaPath, err := util.GetContainerCgroupCFSQuotaPath(podMeta.CgroupDir, containerStatus)
+	containerDir, err := util.GetContainerCgroupPathWithKube(podMeta.CgroupDir, containerStatus)
 	if err != nil {
 		return err
 	}
 	_ = audit.V(2).Pod(podMeta.Pod.Namespace, podMeta.Pod.Name).Container(container.Name).Reason(executor.UpdateCPU).Message("set cfs_quota to %v", targetCFSQuota).Do()
-	return ioutil.WriteFile(containerCFSQuotaPath, []byte(strconv.Itoa(targetCFSQuota)), 0644)
+	return system.CgroupFileWrite(containerDir, system.CPUCFSQuota, strconv.Itoa(targetCFSQuota))
 }
 
 func applyPodBECPURequestIfSpecified(podMeta *statesinformer.PodMeta) error {
@@ -330,12 +329,12 @@ func applyContainerBECPUShareIfSpecified(podMeta *statesinformer.PodMeta, contai
 		return nil
 	}
 	targetCPUShare := int(float64(milliCPURequest*system.CPUShareUnitValue) / float64(1000))
-	containerCPUSharePath, err := util.GetContainerCgroupCPUSharePath(podMeta.CgroupDir, containerStatus)
+	containerDir, err := util.GetContainerCgroupPathWithKube(podMeta.CgroupDir, containerStatus)
 	if err != nil {
 		return err
 	}
 	_ = audit.V(2).Pod(podMeta.Pod.Namespace, podMeta.Pod.Name).Container(container.Name).Reason(executor.UpdateCPU).Message("set cfs_shares to %v", targetCPUShare).Do()
-	return ioutil.WriteFile(containerCPUSharePath, []byte(strconv.Itoa(targetCPUShare)), 0644)
+	return system.CgroupFileWrite(containerDir, system.CPUShares, strconv.Itoa(targetCPUShare))
 }
 
 func applyPodBEMemLimitIfSpecified(podMeta *statesinformer.PodMeta) error {
@@ -343,9 +342,9 @@ func applyPodBEMemLimitIfSpecified(podMeta *statesinformer.PodMeta) error {
 	if memoryLimit <= 0 {
 		return nil
 	}
-	podMemLimitPath := util.GetPodCgroupMemLimitPath(podMeta.CgroupDir)
+	podDir := util.GetPodCgroupDirWithKube(podMeta.CgroupDir)
 	_ = audit.V(2).Pod(podMeta.Pod.Namespace, podMeta.Pod.Name).Reason(executor.UpdateMemory).Message("set memory.limits to %v", memoryLimit).Do()
-	return ioutil.WriteFile(podMemLimitPath, []byte(strconv.Itoa(int(memoryLimit))), 0644)
+	return system.CgroupFileWrite(podDir, system.MemoryLimit, strconv.Itoa(int(memoryLimit)))
 }
 
 func applyContainerBEMemLimitIfSpecified(podMeta *statesinformer.PodMeta, container *corev1.Container, containerStatus *corev1.ContainerStatus) error {
@@ -353,10 +352,10 @@ func applyContainerBEMemLimitIfSpecified(podMeta *statesinformer.PodMeta, contai
 	if memoryLimit <= 0 {
 		return nil
 	}
-	containerMemLimitPath, err := util.GetContainerCgroupMemLimitPath(podMeta.CgroupDir, containerStatus)
+	containerDir, err := util.GetContainerCgroupPathWithKube(podMeta.CgroupDir, containerStatus)
 	if err != nil {
 		return err
 	}
 	_ = audit.V(2).Pod(podMeta.Pod.Namespace, podMeta.Pod.Name).Container(container.Name).Reason(executor.UpdateMemory).Message("set memory.limits to %v", memoryLimit).Do()
-	return ioutil.WriteFile(containerMemLimitPath, []byte(strconv.Itoa(int(memoryLimit))), 0644)
+	return system.CgroupFileWrite(containerDir, system.MemoryLimit, strconv.Itoa(int(memoryLimit)))
 }
@@ -0,0 +1,174 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resmanager
+
+import (
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/executor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/system"
+)
+
+// BlkIOReconcile sets the block io qos of the pods on the pod-level cgroups. The throttling is set on each device
+// used by the volumes and the rootfs of the pod, which are discovered from the mounts of the containers.
+type BlkIOReconcile struct {
+	resManager *resmanager
+	executor   *executor.ResourceUpdateExecutor
+}
+
+func NewBlkIOReconcile(resManager *resmanager) *BlkIOReconcile {
+	executor := executor.NewResourceUpdateExecutor("BlkIOExecutor", resManager.config.ReconcileIntervalSeconds*60)
+	return &BlkIOReconcile{
+		resManager: resManager,
+		executor:   executor,
+	}
+}
+
+func (b *BlkIOReconcile) RunInit(stopCh <-chan struct{}) error {
+	b.executor.Run(stopCh)
+	return nil
+}
+
+func (b *BlkIOReconcile) reconcile() {
+	nodeSLO := b.resManager.getNodeSLOCopy()
+	if nodeSLO == nil || nodeSLO.Spec.ResourceQOSStrategy == nil {
+		klog.Warningf("nodeSLO or nodeSLO.Spec.ResourceQOSStrategy is nil %v", util.DumpJSON(nodeSLO))
+		return
+	}
+
+	var resources []executor.ResourceUpdater
+	for _, podMeta := range b.resManager.statesInformer.GetAllPods() {
+		pod := podMeta.Pod
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		strategy := nodeSLO.Spec.GetResourceQOSStrategy(util.GetPodRuntimeClassName(pod))
+		cfg, err := getMergedPodBlkIOQoS(pod, getPodResourceQoSByQoSClass(pod, strategy, b.resManager.config))
+		if err != nil {
+			klog.Warningf("failed to get the blkio qos of pod %s, err: %v", util.GetPodKey(pod), err)
+			continue
+		}
+		if cfg == nil {
+			// reset the throttling of the devices in case the blkio qos is disabled
+			cfg = &slov1alpha1.BlkIOQOS{}
+		}
+		resources = append(resources, calculatePodBlkIOResources(podMeta, cfg)...)
+	}
+	if b.executor.UpdateBatchByCache(resources...) {
+		klog.V(5).Infof("blkio resources are updated")
+	}
+}
+
+// getMergedPodBlkIOQoS merges the blkio qos of the pod annotation into the config of the qos class. It returns nil
+// if the blkio qos is not enabled for the qos class of the pod.
+func getMergedPodBlkIOQoS(pod *corev1.Pod, qosCfg *slov1alpha1.ResourceQOS) (*slov1alpha1.BlkIOQOS, error) {
+	if qosCfg == nil || qosCfg.BlkIOQOS == nil || qosCfg.BlkIOQOS.Enable == nil || !*qosCfg.BlkIOQOS.Enable {
+		return nil, nil
+	}
+	merged := qosCfg.BlkIOQOS.BlkIOQOS.DeepCopy()
+	podCfg, err := extension.GetPodBlkIOQoSConfig(pod)
+	if err != nil || podCfg == nil {
+		return merged, err
+	}
+	if podCfg.Weight != nil {
+		merged.Weight = podCfg.Weight
+	}
+	if podCfg.ReadIOPS != nil {
+		merged.ReadIOPS = podCfg.ReadIOPS
+	}
+	if podCfg.WriteIOPS != nil {
+		merged.WriteIOPS = podCfg.WriteIOPS
+	}
+	if podCfg.ReadBPS != nil {
+		merged.ReadBPS = podCfg.ReadBPS
+	}
+	if podCfg.WriteBPS != nil {
+		merged.WriteBPS = podCfg.WriteBPS
+	}
+	return merged, nil
+}
+
+// calculatePodBlkIOResources returns the updaters of the blkio weight and the throttling of the pod. The devices
+// without a limit configured are reset to 0, which is "max" in io.max, so the limits removed take effect.
+func calculatePodBlkIOResources(podMeta *statesinformer.PodMeta, cfg *slov1alpha1.BlkIOQOS) []executor.ResourceUpdater {
+	pod := podMeta.Pod
+	owner := executor.PodOwnerRef(pod.Namespace, pod.Name)
+	podDir := util.GetPodCgroupDirWithKube(podMeta.CgroupDir)
+
+	var resources []executor.ResourceUpdater
+	if cfg.Weight != nil {
+		resources = append(resources, executor.NewCommonCgroupResourceUpdater(owner, podDir, system.BlkioWeight, strconv.FormatInt(*cfg.Weight, 10)))
+	}
+
+	throttles := []struct {
+		file  system.CgroupFile
+		value *int64
+	}{
+		{file: system.BlkioReadIops, value: cfg.ReadIOPS},
+		{file: system.BlkioWriteIops, value: cfg.WriteIOPS},
+		{file: system.BlkioReadBps, value: cfg.ReadBPS},
+		{file: system.BlkioWriteBps, value: cfg.WriteBPS},
+	}
+	devices := getPodBlockDevices(podMeta)
+	for _, device := range devices {
+		for _, throttle := range throttles {
+			var value int64
+			if throttle.value != nil {
+				value = *throttle.value
+			}
+			resources = append(resources, executor.NewBlkioCgroupResourceUpdater(owner, podDir, throttle.file, device, value))
+		}
+	}
+	return resources
+}
+
+// getPodBlockDevices returns the disks used by the pod, which are read from the mounts of a process in each container.
+func getPodBlockDevices(podMeta *statesinformer.PodMeta) []string {
+	pod := podMeta.Pod
+	deviceSet := map[string]struct{}{}
+	var devices []string
+	for i := range pod.Status.ContainerStatuses {
+		containerStatus := &pod.Status.ContainerStatuses[i]
+		if containerStatus.State.Running == nil {
+			continue
+		}
+		pids, err := util.GetPIDsInContainer(podMeta.CgroupDir, containerStatus)
+		if err != nil || len(pids) == 0 {
+			klog.V(5).Infof("failed to get the pids of container %s/%s, err: %v", util.GetPodKey(pod), containerStatus.Name, err)
+			continue
+		}
+		containerDevices, err := system.GetProcessBlockDevices(pids[0])
+		if err != nil {
+			klog.V(5).Infof("failed to get the block devices of container %s/%s, err: %v", util.GetPodKey(pod), containerStatus.Name, err)
+			continue
+		}
+		for _, device := range containerDevices {
+			if _, ok := deviceSet[device]; !ok {
+				deviceSet[device] = struct{}{}
+				devices = append(devices, device)
+			}
+		}
+	}
+	return devices
+}
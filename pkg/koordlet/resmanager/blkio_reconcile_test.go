@@ -0,0 +1,196 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resmanager
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/system"
+)
+
+func Test_getMergedPodBlkIOQoS(t *testing.T) {
+	enabledCfg := &slov1alpha1.ResourceQOS{
+		BlkIOQOS: &slov1alpha1.BlkIOQOSCfg{
+			Enable: pointer.Bool(true),
+			BlkIOQOS: slov1alpha1.BlkIOQOS{
+				Weight:   pointer.Int64(100),
+				ReadIOPS: pointer.Int64(1000),
+			},
+		},
+	}
+	tests := []struct {
+		name       string
+		annotation string
+		qosCfg     *slov1alpha1.ResourceQOS
+		want       *slov1alpha1.BlkIOQOS
+		wantErr    bool
+	}{
+		{
+			name:   "qos class not configured",
+			qosCfg: &slov1alpha1.ResourceQOS{},
+		},
+		{
+			name: "qos class disabled",
+			qosCfg: &slov1alpha1.ResourceQOS{
+				BlkIOQOS: &slov1alpha1.BlkIOQOSCfg{Enable: pointer.Bool(false)},
+			},
+			annotation: `{"weight": 50}`,
+		},
+		{
+			name:   "use the qos class config",
+			qosCfg: enabledCfg,
+			want:   &enabledCfg.BlkIOQOS.BlkIOQOS,
+		},
+		{
+			name:       "pod annotation overrides the qos class config",
+			qosCfg:     enabledCfg,
+			annotation: `{"weight": 50, "writeBPS": 1048576}`,
+			want: &slov1alpha1.BlkIOQOS{
+				Weight:   pointer.Int64(50),
+				ReadIOPS: pointer.Int64(1000),
+				WriteBPS: pointer.Int64(1048576),
+			},
+		},
+		{
+			name:       "invalid pod annotation",
+			qosCfg:     enabledCfg,
+			annotation: `{"weight": "50"}`,
+			want:       &enabledCfg.BlkIOQOS.BlkIOQOS,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Annotations: map[string]string{}}}
+			if tt.annotation != "" {
+				pod.Annotations[extension.AnnotationPodBlkIOQoS] = tt.annotation
+			}
+			got, err := getMergedPodBlkIOQoS(pod, tt.qosCfg)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_BlkIOReconcile_reconcile(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	oldSysRootDir := system.Conf.SysRootDir
+	defer func() {
+		system.Conf.SysRootDir = oldSysRootDir
+	}()
+	system.Conf.SysRootDir = path.Join(helper.TempDir, "sys")
+
+	// the container mounts the emptyDir on sda1
+	helper.WriteFileContents("sys/devices/virtual/block/sda/dev", "8:0\n")
+	helper.WriteFileContents("sys/devices/virtual/block/sda/sda1/partition", "1\n")
+	helper.MkDirAll("sys/dev/block")
+	assert.NoError(t, os.Symlink("../../devices/virtual/block/sda/sda1", path.Join(system.Conf.SysRootDir, system.SysDevBlockDir, "8:1")))
+	helper.WriteProcSubFileContents("1234/mountinfo",
+		"1002 1000 8:1 /var/lib/kubelet/pods/uid/volumes/kubernetes.io~empty-dir/data /data rw,relatime - ext4 /dev/sda1 rw\n")
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "be-pod",
+			UID:       "be-pod-uid",
+			Labels: map[string]string{
+				extension.LabelPodQoS: string(extension.QoSBE),
+			},
+			Annotations: map[string]string{
+				extension.AnnotationPodBlkIOQoS: `{"writeBPS": 1048576}`,
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:        "main",
+					ContainerID: "docker://main-container",
+					State:       corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				},
+			},
+		},
+	}
+	podMeta := &statesinformer.PodMeta{Pod: pod, CgroupDir: "kubepods-besteffort.slice/kubepods-besteffort-podbe_pod_uid.slice"}
+	containerDir, err := util.GetContainerCgroupPathWithKube(podMeta.CgroupDir, &pod.Status.ContainerStatuses[0])
+	assert.NoError(t, err)
+	helper.WriteCgroupFileContents(containerDir, system.CPUProcs, "1234\n")
+	podDir := util.GetPodCgroupDirWithKube(podMeta.CgroupDir)
+	for _, file := range []system.CgroupFile{system.BlkioWeight, system.BlkioReadIops, system.BlkioReadBps, system.BlkioWriteIops, system.BlkioWriteBps} {
+		helper.WriteCgroupFileContents(podDir, file, "")
+	}
+
+	nodeSLO := &slov1alpha1.NodeSLO{
+		Spec: slov1alpha1.NodeSLOSpec{
+			ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+				BEClass: &slov1alpha1.ResourceQOS{
+					BlkIOQOS: &slov1alpha1.BlkIOQOSCfg{
+						Enable: pointer.Bool(true),
+						BlkIOQOS: slov1alpha1.BlkIOQOS{
+							Weight:   pointer.Int64(50),
+							ReadIOPS: pointer.Int64(1000),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	si := mock_statesinformer.NewMockStatesInformer(ctrl)
+	si.EXPECT().GetNodeSLO().Return(nodeSLO).AnyTimes()
+	si.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{podMeta}).AnyTimes()
+
+	b := NewBlkIOReconcile(&resmanager{config: NewDefaultConfig(), statesInformer: si})
+	b.reconcile()
+
+	assert.Equal(t, "50", helper.ReadCgroupFileContents(podDir, system.BlkioWeight))
+	assert.Equal(t, "8:0 1000", helper.ReadCgroupFileContents(podDir, system.BlkioReadIops))
+	assert.Equal(t, "8:0 1048576", helper.ReadCgroupFileContents(podDir, system.BlkioWriteBps))
+	assert.Equal(t, "8:0 0", helper.ReadCgroupFileContents(podDir, system.BlkioWriteIops))
+	assert.Equal(t, "8:0 0", helper.ReadCgroupFileContents(podDir, system.BlkioReadBps))
+
+	// the limits removed are reset
+	pod.Annotations = nil
+	nodeSLO.Spec.ResourceQOSStrategy.BEClass.BlkIOQOS.ReadIOPS = nil
+	b.reconcile()
+	assert.Equal(t, "8:0 0", helper.ReadCgroupFileContents(podDir, system.BlkioReadIops))
+	assert.Equal(t, "8:0 0", helper.ReadCgroupFileContents(podDir, system.BlkioWriteBps))
+
+	// the limits are reset if the blkio qos is disabled
+	nodeSLO.Spec.ResourceQOSStrategy.BEClass.BlkIOQOS.ReadIOPS = pointer.Int64(1000)
+	b.reconcile()
+	assert.Equal(t, "8:0 1000", helper.ReadCgroupFileContents(podDir, system.BlkioReadIops))
+	nodeSLO.Spec.ResourceQOSStrategy.BEClass.BlkIOQOS.Enable = pointer.Bool(false)
+	b.reconcile()
+	assert.Equal(t, "8:0 0", helper.ReadCgroupFileContents(podDir, system.BlkioReadIops))
+}
@@ -101,7 +101,7 @@ func (m *CgroupResourcesReconcile) calculateAndUpdateResources(nodeSLO *slov1alp
 	podMetas := m.resmanager.statesInformer.GetAllPods()
 
 	// calculate qos-level, pod-level and container-level resources
-	qosResources, podResources, containerResources := m.calculateResources(nodeSLO.Spec.ResourceQOSStrategy, node, podMetas)
+	qosResources, podResources, containerResources := m.calculateResources(&nodeSLO.Spec, node, podMetas)
 
 	// to make sure the hierarchical cgroup resources are correctly updated, we simply update the resources by
 	// cgroup-level order.
@@ -113,9 +113,12 @@ func (m *CgroupResourcesReconcile) calculateAndUpdateResources(nodeSLO *slov1alp
 	}
 }
 
-// calculateResources calculates qos-level, pod-level and container-level resources with nodeCfg and podMetas
-func (m *CgroupResourcesReconcile) calculateResources(nodeCfg *slov1alpha1.ResourceQOSStrategy, node *corev1.Node,
+// calculateResources calculates qos-level, pod-level and container-level resources with nodeSLOSpec and podMetas.
+// The pod-level and container-level resources use the ResourceQOSStrategy of the RuntimeClass of the pod, while the
+// qos-level resources shared by all the runtimes use the ResourceQOSStrategy of the node.
+func (m *CgroupResourcesReconcile) calculateResources(nodeSLOSpec *slov1alpha1.NodeSLOSpec, node *corev1.Node,
 	podMetas []*statesinformer.PodMeta) (qosLevelResources, podLevelResources, containerLevelResources []executor.MergeableResourceUpdater) {
+	nodeCfg := nodeSLOSpec.ResourceQOSStrategy
 	// TODO: check anolis os version
 	qosSummary := map[corev1.PodQOSClass]*cgroupResourceSummary{
 		corev1.PodQOSGuaranteed: {},
@@ -133,7 +136,8 @@ func (m *CgroupResourcesReconcile) calculateResources(nodeCfg *slov1alpha1.Resou
 
 		// retrieve pod-level config
 		kubeQoS := util.GetKubeQosClass(pod) // assert kubeQoS belongs to {Guaranteed, Burstable, Besteffort}
-		podQoSCfg := getPodResourceQoSByQoSClass(pod, nodeCfg, m.resmanager.config)
+		podCfg := nodeSLOSpec.GetResourceQOSStrategy(util.GetPodRuntimeClassName(pod))
+		podQoSCfg := getPodResourceQoSByQoSClass(pod, podCfg, m.resmanager.config)
 		mergedPodCfg, err := m.getMergedPodResourceQoS(pod, podQoSCfg)
 		if err != nil {
 			klog.Errorf("failed to retrieve pod resourceQoS, err: %v", err)
@@ -807,7 +807,7 @@ func TestCgroupResourceReconcile_calculateResources(t *testing.T) {
 
 			system.NewFileTestUtil(t)
 
-			got, got1, got2 := m.calculateResources(tt.args.nodeCfg, tt.args.node, tt.args.podMetas)
+			got, got1, got2 := m.calculateResources(&slov1alpha1.NodeSLOSpec{ResourceQOSStrategy: tt.args.nodeCfg}, tt.args.node, tt.args.podMetas)
 			assertCgroupResourceEqual(t, tt.want, got)
 			assertCgroupResourceEqual(t, tt.want1, got1)
 			assertCgroupResourceEqual(t, tt.want2, got2)
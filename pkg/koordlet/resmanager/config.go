@@ -29,7 +29,16 @@ type Config struct {
 	MemoryEvictIntervalSeconds int
 	MemoryEvictCoolTimeSeconds int
 	CPUEvictCoolTimeSeconds    int
-	QOSExtensionCfg            *plugins.QOSExtensionConfig
+	// NetQoSDevice is the NIC to limit the egress bandwidth, the interface of the default route is used if empty
+	NetQoSDevice string
+	// NetQoSTotalBandwidthMbps is the total bandwidth of the NIC, the speed of the NIC is used if zero
+	NetQoSTotalBandwidthMbps int64
+	// NetQoSBEMinPercent is the bandwidth guaranteed for BE pods, in percentage of the total
+	NetQoSBEMinPercent int64
+	// NetQoSBEMaxPercent is the max bandwidth BE pods can use, in percentage of the total,
+	// the rest is reserved for the LS pods even if BE pods saturate the NIC
+	NetQoSBEMaxPercent int64
+	QOSExtensionCfg    *plugins.QOSExtensionConfig
 }
 
 func NewDefaultConfig() *Config {
@@ -40,6 +49,8 @@ func NewDefaultConfig() *Config {
 		MemoryEvictIntervalSeconds: 1,
 		MemoryEvictCoolTimeSeconds: 4,
 		CPUEvictCoolTimeSeconds:    20,
+		NetQoSBEMinPercent:         10,
+		NetQoSBEMaxPercent:         60,
 		QOSExtensionCfg:            &plugins.QOSExtensionConfig{FeatureGates: map[string]bool{}},
 	}
 }
@@ -51,5 +62,9 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.IntVar(&c.MemoryEvictIntervalSeconds, "memory-evict-interval-seconds", c.MemoryEvictIntervalSeconds, "evict be pod(memory) interval by seconds")
 	fs.IntVar(&c.MemoryEvictCoolTimeSeconds, "memory-evict-cool-time-seconds", c.MemoryEvictCoolTimeSeconds, "cooling time: memory next evict time should after lastEvictTime + MemoryEvictCoolTimeSeconds")
 	fs.IntVar(&c.CPUEvictCoolTimeSeconds, "cpu-evict-cool-time-seconds", c.CPUEvictCoolTimeSeconds, "cooltime: CPU next evict time should after lastEvictTime + CPUEvictCoolTimeSeconds")
+	fs.StringVar(&c.NetQoSDevice, "net-qos-device", c.NetQoSDevice, "the NIC to limit the egress bandwidth of pods, the interface of the default route is used if empty")
+	fs.Int64Var(&c.NetQoSTotalBandwidthMbps, "net-qos-total-bandwidth-mbps", c.NetQoSTotalBandwidthMbps, "the total bandwidth of the NIC in Mbps, the speed of the NIC is used if zero")
+	fs.Int64Var(&c.NetQoSBEMinPercent, "net-qos-be-min-percent", c.NetQoSBEMinPercent, "the bandwidth guaranteed for be pods, in percentage of the total bandwidth")
+	fs.Int64Var(&c.NetQoSBEMaxPercent, "net-qos-be-max-percent", c.NetQoSBEMaxPercent, "the max bandwidth be pods can use, in percentage of the total bandwidth")
 	c.QOSExtensionCfg.InitFlags(fs)
 }
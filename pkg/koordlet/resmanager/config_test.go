@@ -33,6 +33,8 @@ func Test_NewDefaultConfig(t *testing.T) {
 		MemoryEvictIntervalSeconds: 1,
 		MemoryEvictCoolTimeSeconds: 4,
 		CPUEvictCoolTimeSeconds:    20,
+		NetQoSBEMinPercent:         10,
+		NetQoSBEMaxPercent:         60,
 		QOSExtensionCfg:            &plugins.QOSExtensionConfig{FeatureGates: map[string]bool{}},
 	}
 	defaultConfig := NewDefaultConfig()
@@ -48,6 +50,10 @@ func Test_InitFlags(t *testing.T) {
 		"--memory-evict-interval-seconds=2",
 		"--memory-evict-cool-time-seconds=8",
 		"--cpu-evict-cool-time-seconds=40",
+		"--net-qos-device=eth1",
+		"--net-qos-total-bandwidth-mbps=10000",
+		"--net-qos-be-min-percent=20",
+		"--net-qos-be-max-percent=50",
 		"--qos-extension-plugins=test-plugin=true",
 	}
 	fs := flag.NewFlagSet(cmdArgs[0], flag.ExitOnError)
@@ -59,6 +65,10 @@ func Test_InitFlags(t *testing.T) {
 		MemoryEvictIntervalSeconds int
 		MemoryEvictCoolTimeSeconds int
 		CPUEvictCoolTimeSeconds    int
+		NetQoSDevice               string
+		NetQoSTotalBandwidthMbps   int64
+		NetQoSBEMinPercent         int64
+		NetQoSBEMaxPercent         int64
 		QOSExtensionCfg            *plugins.QOSExtensionConfig
 	}
 	type args struct {
@@ -78,6 +88,10 @@ func Test_InitFlags(t *testing.T) {
 				MemoryEvictIntervalSeconds: 2,
 				MemoryEvictCoolTimeSeconds: 8,
 				CPUEvictCoolTimeSeconds:    40,
+				NetQoSDevice:               "eth1",
+				NetQoSTotalBandwidthMbps:   10000,
+				NetQoSBEMinPercent:         20,
+				NetQoSBEMaxPercent:         50,
 				QOSExtensionCfg:            &plugins.QOSExtensionConfig{FeatureGates: map[string]bool{"test-plugin": true}},
 			},
 			args: args{fs: fs},
@@ -92,6 +106,10 @@ func Test_InitFlags(t *testing.T) {
 				MemoryEvictIntervalSeconds: tt.fields.MemoryEvictIntervalSeconds,
 				MemoryEvictCoolTimeSeconds: tt.fields.MemoryEvictCoolTimeSeconds,
 				CPUEvictCoolTimeSeconds:    tt.fields.CPUEvictCoolTimeSeconds,
+				NetQoSDevice:               tt.fields.NetQoSDevice,
+				NetQoSTotalBandwidthMbps:   tt.fields.NetQoSTotalBandwidthMbps,
+				NetQoSBEMinPercent:         tt.fields.NetQoSBEMinPercent,
+				NetQoSBEMaxPercent:         tt.fields.NetQoSBEMaxPercent,
 				QOSExtensionCfg:            tt.fields.QOSExtensionCfg,
 			}
 			c := NewDefaultConfig()
@@ -199,8 +199,9 @@ func (b *CPUBurst) start() {
 			// ignore LSR and BE pod
 			continue
 		}
-		// merge burst config from pod and node
-		cpuBurstCfg := genPodBurstConfig(podMeta.Pod, &b.nodeCPUBurstStrategy.CPUBurstConfig)
+		// merge burst config from pod and node, the node config may be overridden by the runtime class
+		nodeCfg := nodeSLO.Spec.GetCPUBurstStrategy(util.GetPodRuntimeClassName(podMeta.Pod))
+		cpuBurstCfg := genPodBurstConfig(podMeta.Pod, &nodeCfg.CPUBurstConfig)
 		if cpuBurstCfg == nil {
 			klog.Warningf("pod %v/%v burst config illegal, burst config %v",
 				podMeta.Pod.Namespace, podMeta.Pod.Name, cpuBurstCfg)
@@ -243,10 +244,11 @@ func (b *CPUBurst) getNodeStateForBurst(sharePoolThresholdPercent int64,
 	// calculate cpu share pool info; for conservative reason, include system usage in share pool
 	sharePoolCPUCoresTotal := float64(nodeCPUCoresTotal)
 	sharePoolCPUCoresUsage := nodeCPUCoresUsage
+	now := time.Now()
 	for _, podMeta := range podsMeta {
 		podQOS := apiext.GetPodQoSClass(podMeta.Pod)
-		// exclude LSR pod cpu from cpu share pool
-		if podQOS == apiext.QoSLSR {
+		// exclude LSR pod cpu from cpu share pool, except the LSR pod outside its active windows
+		if podQOS == apiext.QoSLSR && apiext.IsPodCPUSetActive(podMeta.Pod, now) {
 			podRequest := util.GetPodRequest(podMeta.Pod)
 			sharePoolCPUCoresTotal -= float64(podRequest.Cpu().MilliValue()) / 1000
 		}
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
@@ -152,14 +153,21 @@ func (c *CPUEvictor) evictByResourceSatisfaction(node *corev1.Node, thresholdCon
 func (c *CPUEvictor) killAndEvictBEPodsRelease(node *corev1.Node, bePodInfos []*podEvictCPUInfo, cpuNeedMilliRelease int64) {
 	message := fmt.Sprintf("killAndEvictBEPodsRelease for node(%s), need realase CPU : %d", c.resmanager.nodeName, cpuNeedMilliRelease)
 
+	bePodInfos, wholeGangs := c.selectGangEvictablePodInfos(bePodInfos)
 	cpuMilliReleased := int64(0)
 	var killedPods []*corev1.Pod
+	killedGangs := sets.NewString()
 	for _, bePod := range bePodInfos {
-		if cpuMilliReleased < cpuNeedMilliRelease {
+		gangKey := util.GetGangKey(bePod.pod)
+		// the members of an entire gang are killed together
+		if cpuMilliReleased < cpuNeedMilliRelease || killedGangs.Has(gangKey) {
 			podKillMsg := fmt.Sprintf("%s, kill pod : %s", message, bePod.pod.Name)
 			killContainers(bePod.pod, podKillMsg)
 
 			killedPods = append(killedPods, bePod.pod)
+			if wholeGangs.Has(gangKey) {
+				killedGangs.Insert(gangKey)
+			}
 			cpuMilliReleased = cpuMilliReleased + bePod.milliRequest
 		}
 	}
@@ -213,6 +221,22 @@ func (c *CPUEvictor) getPodEvictInfoAndSort(beMetric *metriccache.BECPUResourceM
 	return bePodInfos
 }
 
+// selectGangEvictablePodInfos filters the pods whose eviction breaks their gangs, see resmanager.selectGangEvictablePods.
+func (c *CPUEvictor) selectGangEvictablePodInfos(podInfos []*podEvictCPUInfo) ([]*podEvictCPUInfo, sets.String) {
+	pods := make([]*corev1.Pod, 0, len(podInfos))
+	infoMap := make(map[*corev1.Pod]*podEvictCPUInfo, len(podInfos))
+	for _, info := range podInfos {
+		pods = append(pods, info.pod)
+		infoMap[info.pod] = info
+	}
+	selected, wholeGangs := c.resmanager.selectGangEvictablePods(pods)
+	selectedInfos := make([]*podEvictCPUInfo, 0, len(selected))
+	for _, pod := range selected {
+		selectedInfos = append(selectedInfos, infoMap[pod])
+	}
+	return selectedInfos, wholeGangs
+}
+
 func calculateResourceMilliToRelease(metric *metriccache.BECPUResourceMetric, thresholdConfig *slov1alpha1.ResourceThresholdStrategy) int64 {
 	if metric.CPURequest.IsZero() {
 		klog.Warningf("cpuEvict by ResourceSatisfaction skipped! be pods requests is zero!")
@@ -24,6 +24,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -66,10 +67,13 @@ var (
 type CPUSuppress struct {
 	resmanager             *resmanager
 	suppressPolicyStatuses map[string]suppressPolicyStatus
+	// lastLSUsedMilliCPU is the cpu used by the LS pods and the system in the last round, the ramp-up of the
+	// BE cpu holds while the LS usage keeps increasing. Negative means unknown.
+	lastLSUsedMilliCPU int64
 }
 
 func NewCPUSuppress(resmanager *resmanager) *CPUSuppress {
-	return &CPUSuppress{resmanager: resmanager, suppressPolicyStatuses: map[string]suppressPolicyStatus{}}
+	return &CPUSuppress{resmanager: resmanager, suppressPolicyStatuses: map[string]suppressPolicyStatus{}, lastLSUsedMilliCPU: -1}
 }
 
 // getPodMetricCPUUsage gets pod usage cpu from the PodResourceMetric
@@ -387,6 +391,10 @@ func (r *CPUSuppress) suppressBECPU() {
 		klog.Warningf("suppressBECPU failed, cannot check the featuregate, err: %s", err)
 		return
 	} else if disabled {
+		if r.rampUpBECPUIfNeed(nodeSLO) {
+			klog.V(5).Infof("suppressBECPU disabled by nodeSLO, ramping up be cpu")
+			return
+		}
 		r.recoverCFSQuotaIfNeed()
 		r.recoverCPUSetIfNeed(containerCgroupPathRelativeDepth)
 		klog.V(5).Infof("suppressBECPU skipped, nodeSLO disable the featuregate")
@@ -412,8 +420,17 @@ func (r *CPUSuppress) suppressBECPU() {
 		return
 	}
 
-	suppressCPUQuantity := r.calculateBESuppressCPU(node, nodeMetric, podMetrics, podMetas,
-		*nodeSLO.Spec.ResourceUsedThresholdWithBE.CPUSuppressThresholdPercent)
+	cpuSuppressThresholdPercent := *nodeSLO.Spec.ResourceUsedThresholdWithBE.CPUSuppressThresholdPercent
+	suppressCPUQuantity := r.calculateBESuppressCPU(node, nodeMetric, podMetrics, podMetas, cpuSuppressThresholdPercent)
+	// suppress(BE) := node.Total * SLOPercent - pod(LS).Used - system.Used
+	nodeMilliCPU := node.Status.Allocatable.Cpu().MilliValue()
+	lsUsedMilliCPU := nodeMilliCPU*cpuSuppressThresholdPercent/100 - suppressCPUQuantity.MilliValue()
+	maxIncreasePercent := r.getBEMaxIncreaseCPUPercent(nodeSLO.Spec.ResourceUsedThresholdWithBE, lsUsedMilliCPU, nodeMilliCPU)
+	if r.isLSCPUPressureExceeded(nodeSLO.Spec.ResourceUsedThresholdWithBE, podMetas) {
+		// the LS pods are contended even if the utilization is below the threshold
+		suppressCPUQuantity = resource.NewQuantity(0, resource.DecimalSI)
+		maxIncreasePercent = 0
+	}
 
 	// Step 2.
 	nodeCPUInfo, err := r.resmanager.metricCache.GetNodeCPUInfo(&metriccache.QueryParam{})
@@ -422,27 +439,174 @@ func (r *CPUSuppress) suppressBECPU() {
 		return
 	}
 	if nodeSLO.Spec.ResourceUsedThresholdWithBE.CPUSuppressPolicy == slov1alpha1.CPUCfsQuotaPolicy {
-		adjustByCfsQuota(suppressCPUQuantity, node)
+		adjustByCfsQuota(suppressCPUQuantity, node, maxIncreasePercent)
 		r.suppressPolicyStatuses[string(slov1alpha1.CPUCfsQuotaPolicy)] = policyUsing
 		r.recoverCPUSetIfNeed(containerCgroupPathRelativeDepth)
 	} else {
-		r.adjustByCPUSet(suppressCPUQuantity, nodeCPUInfo)
+		r.adjustByCPUSet(suppressCPUQuantity, nodeCPUInfo, maxIncreasePercent)
 		r.suppressPolicyStatuses[string(slov1alpha1.CPUSetPolicy)] = policyUsing
 		r.recoverCFSQuotaIfNeed()
 	}
 }
 
-func (r *CPUSuppress) adjustByCPUSet(cpusetQuantity *resource.Quantity, nodeCPUInfo *metriccache.NodeCPUInfo) {
+// isLSCPUPressureExceeded returns whether the cpu pressure of any LS pod exceeds CPUSuppressPSIThresholdPercent.
+func (r *CPUSuppress) isLSCPUPressureExceeded(strategy *slov1alpha1.ResourceThresholdStrategy, podMetas []*statesinformer.PodMeta) bool {
+	if strategy == nil || strategy.CPUSuppressPSIThresholdPercent == nil {
+		return false
+	}
+	threshold := float64(*strategy.CPUSuppressPSIThresholdPercent)
+	for _, podMeta := range podMetas {
+		pod := podMeta.Pod
+		if apiext.GetPodQoSClass(pod) == apiext.QoSBE || util.GetKubeQosClass(pod) == corev1.PodQOSBestEffort {
+			continue
+		}
+		podUID := string(pod.UID)
+		queryResult := r.resmanager.collectPodPSIMetricLast(&podUID)
+		if queryResult.Error != nil || queryResult.Metric == nil {
+			continue
+		}
+		if queryResult.Metric.PSI.CPUSomeAvg10 >= threshold {
+			klog.Infof("suppressBECPU to the minimum, cpu pressure of pod %s/%s is %.2f%%, threshold %v%%",
+				pod.Namespace, pod.Name, queryResult.Metric.PSI.CPUSomeAvg10, threshold)
+			return true
+		}
+	}
+	return false
+}
+
+// getBEMaxIncreaseCPUPercent returns the max percent of the node cpu the BE cpu can increase by in this round.
+// With the ramp-up configured, the BE cpu is restored to the full capacity in CPUSuppressRampUpSeconds, and holds
+// while the LS usage increases by more than suppressBypassQuotaDeltaRatio of the node cpu since the last round.
+func (r *CPUSuppress) getBEMaxIncreaseCPUPercent(strategy *slov1alpha1.ResourceThresholdStrategy, lsUsedMilliCPU, nodeMilliCPU int64) float64 {
+	lastLSUsedMilliCPU := r.lastLSUsedMilliCPU
+	r.lastLSUsedMilliCPU = lsUsedMilliCPU
+	if strategy == nil || strategy.CPUSuppressRampUpSeconds == nil || *strategy.CPUSuppressRampUpSeconds <= 0 {
+		return beMaxIncreaseCPUPercent
+	}
+	if lastLSUsedMilliCPU >= 0 && float64(lsUsedMilliCPU-lastLSUsedMilliCPU) > float64(nodeMilliCPU)*suppressBypassQuotaDeltaRatio {
+		klog.V(4).Infof("suppressBECPU holds the ramp-up of be cpu, ls used cpu increases from %vm to %vm",
+			lastLSUsedMilliCPU, lsUsedMilliCPU)
+		return 0
+	}
+	intervalSeconds := int64(1)
+	if r.resmanager.config != nil && r.resmanager.config.CPUSuppressIntervalSeconds > 0 {
+		intervalSeconds = int64(r.resmanager.config.CPUSuppressIntervalSeconds)
+	}
+	return math.Min(float64(intervalSeconds)/float64(*strategy.CPUSuppressRampUpSeconds), 1)
+}
+
+// rampUpBECPUIfNeed widens the BE cpu gradually after the suppression is disabled instead of restoring it at once.
+// It returns false if the ramp-up is not configured, or the BE cpu is about to reach the full capacity, then the
+// BE cgroups should be recovered.
+func (r *CPUSuppress) rampUpBECPUIfNeed(nodeSLO *slov1alpha1.NodeSLO) bool {
+	strategy := nodeSLO.Spec.ResourceUsedThresholdWithBE
+	if strategy == nil || strategy.CPUSuppressRampUpSeconds == nil || *strategy.CPUSuppressRampUpSeconds <= 0 {
+		return false
+	}
+	policy := getCPUSuppressPolicy(nodeSLO)
+	if r.suppressPolicyStatuses[string(policy)] != policyUsing {
+		return false
+	}
+
+	node := r.resmanager.statesInformer.GetNode()
+	podMetas := r.resmanager.statesInformer.GetAllPods()
+	nodeMetric, podMetrics := r.resmanager.collectNodeAndPodMetricLast()
+	if node == nil || nodeMetric == nil || podMetrics == nil {
+		return false
+	}
+	nodeCPUInfo, err := r.resmanager.metricCache.GetNodeCPUInfo(&metriccache.QueryParam{})
+	if err != nil {
+		klog.Warningf("suppressBECPU failed to get nodeCPUInfo from metriccache, err: %s", err)
+		return false
+	}
+	// the cpu used by the LS pods and the system is all that BE can't use
+	nodeMilliCPU := node.Status.Allocatable.Cpu().MilliValue()
+	beAvailableCPU := r.calculateBESuppressCPU(node, nodeMetric, podMetrics, podMetas, 100)
+	maxIncreasePercent := r.getBEMaxIncreaseCPUPercent(strategy, nodeMilliCPU-beAvailableCPU.MilliValue(), nodeMilliCPU)
+
+	if policy == slov1alpha1.CPUCfsQuotaPolicy {
+		beCgroupPath := util.GetKubeQosRelativePath(corev1.PodQOSBestEffort)
+		currentBeQuota, err := system.CgroupFileReadInt(beCgroupPath, system.CPUCFSQuota)
+		if err != nil || *currentBeQuota < 0 {
+			return false
+		}
+		fullQuota := node.Status.Capacity.Cpu().Value() * cfsPeriod
+		if *currentBeQuota+int64(float64(fullQuota)*maxIncreasePercent) >= fullQuota {
+			return false
+		}
+		adjustByCfsQuota(node.Status.Capacity.Cpu(), node, maxIncreasePercent)
+		return true
+	}
+
+	oldCPUSet, err := util.GetRootCgroupCurCPUSet(corev1.PodQOSBestEffort)
+	if err != nil {
+		klog.Warningf("suppressBECPU failed to get current best-effort cgroup cpuset, err: %s", err)
+		return false
+	}
+	// the BE cgroups are recovered to all the cpus of the pool at last
+	lsrCpus, lsCpus := r.getBECPUSetPool(nodeCPUInfo)
+	cpus := len(oldCPUSet) + int(math.Ceil(float64(len(nodeCPUInfo.ProcessorInfos))*maxIncreasePercent))
+	if cpus >= len(lsrCpus)+len(lsCpus) {
+		return false
+	}
+	r.adjustByCPUSet(resource.NewQuantity(int64(cpus), resource.DecimalSI), nodeCPUInfo, maxIncreasePercent)
+	return true
+}
+
+func (r *CPUSuppress) adjustByCPUSet(cpusetQuantity *resource.Quantity, nodeCPUInfo *metriccache.NodeCPUInfo, maxIncreasePercent float64) {
 	oldCPUSet, err := util.GetRootCgroupCurCPUSet(corev1.PodQOSBestEffort)
 	if err != nil {
 		klog.Warningf("applyBESuppressPolicy failed to get current best-effort cgroup cpuset, err: %s", err)
 		return
 	}
 
+	lsrCpus, lsCpus := r.getBECPUSetPool(nodeCPUInfo)
+
+	// set the number of cpuset cpus no less than 2
+	cpus := int32(math.Ceil(float64(cpusetQuantity.MilliValue()) / 1000))
+	if cpus < 2 {
+		cpus = 2
+	}
+	beMaxIncreaseCpuNum := int32(math.Ceil(float64(len(nodeCPUInfo.ProcessorInfos)) * maxIncreasePercent))
+	if cpus-int32(len(oldCPUSet)) > beMaxIncreaseCpuNum {
+		cpus = int32(len(oldCPUSet)) + beMaxIncreaseCpuNum
+	}
+	var beCPUSet []int32
+	lsrCpuNums := int32(int(cpus) * len(lsrCpus) / (len(lsrCpus) + len(lsCpus)))
+
+	if lsrCpuNums > 0 {
+		beCPUSetFromLSR := calculateBESuppressCPUSetPolicy(lsrCpuNums, lsrCpus)
+		beCPUSet = append(beCPUSet, beCPUSetFromLSR...)
+	}
+	if cpus-lsrCpuNums > 0 {
+		beCPUSetFromLS := calculateBESuppressCPUSetPolicy(cpus-lsrCpuNums, lsCpus)
+		beCPUSet = append(beCPUSet, beCPUSetFromLS...)
+	}
+
+	// the new be suppress always need to apply since:
+	// - for a reduce of BE cpuset, we should make effort to protecting LS no matter how huge the decrease is;
+	// - for a enlargement of BE cpuset, it is welcome and costless for BE processes.
+	err = r.applyBESuppressCPUSet(beCPUSet, oldCPUSet)
+	if err != nil {
+		klog.Warningf("suppressBECPU failed to apply be cpu suppress policy, err: %s", err)
+		return
+	}
+	audit.V(1).Node().Reason(executor.AdjustBEByNodeCPUUsage).Message("update BE group to cpuset: %v", beCPUSet).Do()
+	klog.Infof("suppressBECPU finished, suppress be cpu successfully: current cpuset %v", beCPUSet)
+}
+
+// getBECPUSetPool returns the cpus BE pods can use, which are divided into the cpus of the LSR pods and the others.
+// On the node isolating the physical cores, all the hyper-threading siblings of the LSE/LSR cpus are excluded.
+func (r *CPUSuppress) getBECPUSetPool(nodeCPUInfo *metriccache.NodeCPUInfo) (lsrCpus, lsCpus []util.ProcessorInfo) {
 	podMetas := r.resmanager.statesInformer.GetAllPods()
 	// value: 0 -> lse, 1 -> lsr, not exists -> others
 	cpuIdToPool := map[int32]apiext.QoSClass{}
+	now := time.Now()
 	for _, podMeta := range podMetas {
+		// the exclusive cpus of the LSR pod outside its active windows are returned to the share pool
+		if !apiext.IsPodCPUSetActive(podMeta.Pod, now) {
+			continue
+		}
 		alloc, err := apiext.GetResourceStatus(podMeta.Pod.Annotations)
 		if err != nil {
 			continue
@@ -458,48 +622,59 @@ func (r *CPUSuppress) adjustByCPUSet(cpusetQuantity *resource.Quantity, nodeCPUI
 			}
 		}
 	}
-	lsrCpus := []util.ProcessorInfo{}
-	lsCpus := []util.ProcessorInfo{}
+	offloadReservedCPUs := r.getOffloadReservedCPUs()
+	isolatedCores := r.getIsolatedCores(nodeCPUInfo, cpuIdToPool)
+	lsrCpus = []util.ProcessorInfo{}
+	lsCpus = []util.ProcessorInfo{}
 	// FIXME: be pods might be starved since lse pods can run out of all cpus
 	for _, processor := range nodeCPUInfo.ProcessorInfos {
+		if offloadReservedCPUs[processor.CPUID] {
+			continue
+		}
+		if isolatedCores[physicalCore{socketID: processor.SocketID, coreID: processor.CoreID}] {
+			continue
+		}
 		if cpuIdToPool[processor.CPUID] == apiext.QoSLSR {
 			lsrCpus = append(lsrCpus, processor)
 		} else if cpuIdToPool[processor.CPUID] != apiext.QoSLSE {
 			lsCpus = append(lsCpus, processor)
 		}
 	}
+	return lsrCpus, lsCpus
+}
 
-	// set the number of cpuset cpus no less than 2
-	cpus := int32(math.Ceil(float64(cpusetQuantity.MilliValue()) / 1000))
-	if cpus < 2 {
-		cpus = 2
-	}
-	beMaxIncreaseCpuNum := int32(math.Ceil(float64(len(nodeCPUInfo.ProcessorInfos)) * beMaxIncreaseCPUPercent))
-	if cpus-int32(len(oldCPUSet)) > beMaxIncreaseCpuNum {
-		cpus = int32(len(oldCPUSet)) + beMaxIncreaseCpuNum
-	}
-	var beCPUSet []int32
-	lsrCpuNums := int32(int(cpus) * len(lsrCpus) / (len(lsrCpus) + len(lsCpus)))
+type physicalCore struct {
+	socketID int32
+	coreID   int32
+}
 
-	if lsrCpuNums > 0 {
-		beCPUSetFromLSR := calculateBESuppressCPUSetPolicy(lsrCpuNums, lsrCpus)
-		beCPUSet = append(beCPUSet, beCPUSetFromLSR...)
+// getIsolatedCores returns the physical cores of the LSE/LSR cpus if the node isolates them from the BE pods.
+func (r *CPUSuppress) getIsolatedCores(nodeCPUInfo *metriccache.NodeCPUInfo, cpuIdToPool map[int32]apiext.QoSClass) map[physicalCore]bool {
+	node := r.resmanager.statesInformer.GetNode()
+	if node == nil || node.Labels[apiext.LabelNodeCPUBindPolicy] != apiext.NodeCPUBindPolicyFullPCPUsIsolated {
+		return nil
 	}
-	if cpus-lsrCpuNums > 0 {
-		beCPUSetFromLS := calculateBESuppressCPUSetPolicy(cpus-lsrCpuNums, lsCpus)
-		beCPUSet = append(beCPUSet, beCPUSetFromLS...)
+	isolatedCores := map[physicalCore]bool{}
+	for _, processor := range nodeCPUInfo.ProcessorInfos {
+		if qosClass, ok := cpuIdToPool[processor.CPUID]; ok && (qosClass == apiext.QoSLSE || qosClass == apiext.QoSLSR) {
+			isolatedCores[physicalCore{socketID: processor.SocketID, coreID: processor.CoreID}] = true
+		}
 	}
+	return isolatedCores
+}
 
-	// the new be suppress always need to apply since:
-	// - for a reduce of BE cpuset, we should make effort to protecting LS no matter how huge the decrease is;
-	// - for a enlargement of BE cpuset, it is welcome and costless for BE processes.
-	err = r.applyBESuppressCPUSet(beCPUSet, oldCPUSet)
+// getOffloadReservedCPUs returns the CPUs reserved for the offload stacks, which are excluded from the BE cpuset.
+func (r *CPUSuppress) getOffloadReservedCPUs() map[int32]bool {
+	reservedCPUs, err := util.GetNodeSLOReservedCPUs(r.resmanager.getNodeSLOCopy())
 	if err != nil {
-		klog.Warningf("suppressBECPU failed to apply be cpu suppress policy, err: %s", err)
-		return
+		klog.Warningf("failed to parse the reserved cpus of nodeSLO, err: %v", err)
+		return nil
 	}
-	audit.V(1).Node().Reason(executor.AdjustBEByNodeCPUUsage).Message("update BE group to cpuset: %v", beCPUSet).Do()
-	klog.Infof("suppressBECPU finished, suppress be cpu successfully: current cpuset %v", beCPUSet)
+	reserved := make(map[int32]bool, len(reservedCPUs))
+	for _, cpuID := range reservedCPUs {
+		reserved[cpuID] = true
+	}
+	return reserved
 }
 
 func (r *CPUSuppress) recoverCPUSetIfNeed(maxDepth int) {
@@ -508,7 +683,11 @@ func (r *CPUSuppress) recoverCPUSetIfNeed(maxDepth int) {
 	if err != nil {
 		return
 	}
+	offloadReservedCPUs := r.getOffloadReservedCPUs()
 	for _, p := range nodeInfo.ProcessorInfos {
+		if offloadReservedCPUs[p.CPUID] {
+			continue
+		}
 		cpus = append(cpus, int(p.CPUID))
 	}
 
@@ -550,7 +729,7 @@ func (r *CPUSuppress) recoverCPUSetIfNeed(maxDepth int) {
 	r.suppressPolicyStatuses[string(slov1alpha1.CPUSetPolicy)] = policyRecovered
 }
 
-func adjustByCfsQuota(cpuQuantity *resource.Quantity, node *corev1.Node) {
+func adjustByCfsQuota(cpuQuantity *resource.Quantity, node *corev1.Node, maxIncreasePercent float64) {
 	newBeQuota := cpuQuantity.MilliValue() * cfsPeriod / 1000
 	newBeQuota = int64(math.Max(float64(newBeQuota), float64(beMinQuota)))
 
@@ -570,7 +749,7 @@ func adjustByCfsQuota(cpuQuantity *resource.Quantity, node *corev1.Node) {
 		return
 	}
 
-	beMaxIncreaseCPUQuota := float64(node.Status.Capacity.Cpu().Value()) * float64(cfsPeriod) * beMaxIncreaseCPUPercent
+	beMaxIncreaseCPUQuota := float64(node.Status.Capacity.Cpu().Value()) * float64(cfsPeriod) * maxIncreasePercent
 	if float64(newBeQuota)-float64(*currentBeQuota) > beMaxIncreaseCPUQuota {
 		newBeQuota = *currentBeQuota + int64(beMaxIncreaseCPUQuota)
 	}
@@ -18,9 +18,11 @@ package resmanager
 
 import (
 	"encoding/json"
+	"fmt"
 	"path/filepath"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	topov1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
@@ -28,6 +30,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
@@ -942,6 +945,7 @@ func Test_cpuSuppress_recoverCPUSetIfNeed(t *testing.T) {
 			mockMetricCache := mockmetriccache.NewMockMetricCache(ctl)
 			mockStatesInformer := mockstatesinformer.NewMockStatesInformer(ctl)
 			mockStatesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{{Pod: lsePod}}).AnyTimes()
+			mockStatesInformer.EXPECT().GetNodeSLO().Return(nil).AnyTimes()
 			mockMetricCache.EXPECT().GetNodeCPUInfo(gomock.Any()).Return(&mockNodeInfo, nil).AnyTimes()
 			r := &resmanager{
 				statesInformer: mockStatesInformer,
@@ -1260,6 +1264,8 @@ func Test_adjustByCPUSet(t *testing.T) {
 	lsePod := mockLSEPod()
 	mockStatesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{{Pod: lsrPod}, {Pod: lsePod}}).AnyTimes()
 	mockStatesInformer.EXPECT().GetNodeTopo().Return(&topov1alpha1.NodeResourceTopology{}).AnyTimes()
+	mockStatesInformer.EXPECT().GetNodeSLO().Return(nil).AnyTimes()
+	mockStatesInformer.EXPECT().GetNode().Return(nil).AnyTimes()
 	r := &resmanager{
 		statesInformer: mockStatesInformer,
 	}
@@ -1271,7 +1277,7 @@ func Test_adjustByCPUSet(t *testing.T) {
 			podDirs := []string{"pod1", "pod2", "pod3"}
 			testingPrepareBECgroupData(helper, podDirs, tt.args.oldCPUSets)
 
-			cpuSuppress.adjustByCPUSet(tt.args.cpusetQuantity, tt.args.nodeCPUInfo)
+			cpuSuppress.adjustByCPUSet(tt.args.cpusetQuantity, tt.args.nodeCPUInfo, beMaxIncreaseCPUPercent)
 
 			gotCPUSetBECgroup := helper.ReadCgroupFileContents(util.GetKubeQosRelativePath(corev1.PodQOSBestEffort), system.CPUSet)
 			assert.Equal(t, tt.wantCPUSet, gotCPUSetBECgroup, "checkBECPUSet")
@@ -1283,6 +1289,85 @@ func Test_adjustByCPUSet(t *testing.T) {
 	}
 }
 
+func Test_adjustByCPUSetWithInactiveLSRPod(t *testing.T) {
+	nodeCPUInfo := &metriccache.NodeCPUInfo{
+		ProcessorInfos: []util.ProcessorInfo{
+			{CPUID: 0, CoreID: 0, SocketID: 0, NodeID: 0},
+			{CPUID: 1, CoreID: 0, SocketID: 0, NodeID: 0},
+			{CPUID: 2, CoreID: 1, SocketID: 0, NodeID: 0},
+			{CPUID: 3, CoreID: 1, SocketID: 0, NodeID: 0},
+			{CPUID: 4, CoreID: 2, SocketID: 1, NodeID: 1},
+			{CPUID: 5, CoreID: 2, SocketID: 1, NodeID: 1},
+			{CPUID: 6, CoreID: 3, SocketID: 1, NodeID: 1},
+			{CPUID: 7, CoreID: 3, SocketID: 1, NodeID: 1},
+		},
+	}
+	// the lsr pod is active one hour later, its cpus are returned to the share pool now
+	now := time.Now().UTC()
+	lsrPod := mockLSRPod()
+	lsrPod.Annotations[apiext.AnnotationResourceSpec] = fmt.Sprintf(`{"activeWindows":[{"start":"%s","end":"%s"}]}`,
+		now.Add(time.Hour).Format("15:04"), now.Add(2*time.Hour).Format("15:04"))
+	lsePod := mockLSEPod()
+
+	ctrl := gomock.NewController(t)
+	mockStatesInformer := mockstatesinformer.NewMockStatesInformer(ctrl)
+	mockStatesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{{Pod: lsrPod}, {Pod: lsePod}}).AnyTimes()
+	mockStatesInformer.EXPECT().GetNodeTopo().Return(&topov1alpha1.NodeResourceTopology{}).AnyTimes()
+	mockStatesInformer.EXPECT().GetNodeSLO().Return(nil).AnyTimes()
+	mockStatesInformer.EXPECT().GetNode().Return(nil).AnyTimes()
+	r := &resmanager{
+		statesInformer: mockStatesInformer,
+	}
+	cpuSuppress := NewCPUSuppress(r)
+
+	helper := system.NewFileTestUtil(t)
+	testingPrepareBECgroupData(helper, []string{"pod1"}, "7,6,3,2")
+	cpuSuppress.adjustByCPUSet(resource.NewQuantity(3, resource.DecimalSI), nodeCPUInfo, beMaxIncreaseCPUPercent)
+	gotCPUSetBECgroup := helper.ReadCgroupFileContents(util.GetKubeQosRelativePath(corev1.PodQOSBestEffort), system.CPUSet)
+	assert.Equal(t, "0,1,4", gotCPUSetBECgroup)
+}
+
+func Test_adjustByCPUSetWithFullPCPUsIsolated(t *testing.T) {
+	nodeCPUInfo := &metriccache.NodeCPUInfo{
+		ProcessorInfos: []util.ProcessorInfo{
+			{CPUID: 0, CoreID: 0, SocketID: 0, NodeID: 0},
+			{CPUID: 1, CoreID: 0, SocketID: 0, NodeID: 0},
+			{CPUID: 2, CoreID: 1, SocketID: 0, NodeID: 0},
+			{CPUID: 3, CoreID: 1, SocketID: 0, NodeID: 0},
+			{CPUID: 4, CoreID: 2, SocketID: 1, NodeID: 1},
+			{CPUID: 5, CoreID: 2, SocketID: 1, NodeID: 1},
+			{CPUID: 6, CoreID: 3, SocketID: 1, NodeID: 1},
+			{CPUID: 7, CoreID: 3, SocketID: 1, NodeID: 1},
+		},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+			Labels: map[string]string{
+				apiext.LabelNodeCPUBindPolicy: apiext.NodeCPUBindPolicyFullPCPUsIsolated,
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	mockStatesInformer := mockstatesinformer.NewMockStatesInformer(ctrl)
+	mockStatesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{{Pod: mockLSRPod()}, {Pod: mockLSEPod()}}).AnyTimes()
+	mockStatesInformer.EXPECT().GetNodeTopo().Return(&topov1alpha1.NodeResourceTopology{}).AnyTimes()
+	mockStatesInformer.EXPECT().GetNodeSLO().Return(nil).AnyTimes()
+	mockStatesInformer.EXPECT().GetNode().Return(node).AnyTimes()
+	r := &resmanager{
+		statesInformer: mockStatesInformer,
+	}
+	cpuSuppress := NewCPUSuppress(r)
+
+	// the siblings of the lsr cpus 0,6 and the lse cpu 7 are never shared with the be pods
+	helper := system.NewFileTestUtil(t)
+	testingPrepareBECgroupData(helper, []string{"pod1"}, "5,4,3,2")
+	cpuSuppress.adjustByCPUSet(resource.NewQuantity(4, resource.DecimalSI), nodeCPUInfo, beMaxIncreaseCPUPercent)
+	gotCPUSetBECgroup := helper.ReadCgroupFileContents(util.GetKubeQosRelativePath(corev1.PodQOSBestEffort), system.CPUSet)
+	assert.Equal(t, "2,3,4,5", gotCPUSetBECgroup)
+}
+
 func Test_adjustByCfsQuota(t *testing.T) {
 	helper := system.NewFileTestUtil(t)
 	beQosDir := util.GetKubeQosRelativePath(corev1.PodQOSBestEffort)
@@ -1339,7 +1424,7 @@ func Test_adjustByCfsQuota(t *testing.T) {
 	for _, tt := range testCases {
 		t.Run(tt.name, func(t *testing.T) {
 			helper.WriteCgroupFileContents(beQosDir, system.CPUCFSQuota, strconv.FormatInt(tt.preBECfsQuota, 10))
-			adjustByCfsQuota(tt.cpuQuantity, node)
+			adjustByCfsQuota(tt.cpuQuantity, node, beMaxIncreaseCPUPercent)
 			gotBECfsQuota := helper.ReadCgroupFileContents(beQosDir, system.CPUCFSQuota)
 			if gotBECfsQuota != strconv.FormatInt(tt.wantBECfsQuota, 10) {
 				t.Errorf("failed to adjustByCfsQuota, want file %v cfs_quota %v, got %v", system.GetCgroupFilePath(beQosDir, system.CPUCFSQuota), tt.wantBECfsQuota,
@@ -1574,6 +1659,8 @@ func TestCPUSuppress_applyBESuppressCPUSet(t *testing.T) {
 			si := mockstatesinformer.NewMockStatesInformer(ctl)
 			si.EXPECT().GetNodeTopo().Return(nodeTopo).AnyTimes()
 			si.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{}).AnyTimes()
+			// no cpus are reserved for the offload stacks
+			si.EXPECT().GetNodeSLO().Return(nil).AnyTimes()
 			mc := mockmetriccache.NewMockMetricCache(ctl)
 			mc.EXPECT().GetNodeCPUInfo(gomock.Any()).Return(mockNodeInfo, nil).AnyTimes()
 			r := &CPUSuppress{
@@ -1600,3 +1687,123 @@ func TestCPUSuppress_applyBESuppressCPUSet(t *testing.T) {
 		})
 	}
 }
+
+func Test_cpuSuppress_getBEMaxIncreaseCPUPercent(t *testing.T) {
+	r := &resmanager{config: &Config{CPUSuppressIntervalSeconds: 2}}
+	cpuSuppress := NewCPUSuppress(r)
+	nodeMilliCPU := int64(100 * 1000)
+
+	// the default step without the ramp-up
+	strategy := &slov1alpha1.ResourceThresholdStrategy{}
+	assert.Equal(t, beMaxIncreaseCPUPercent, cpuSuppress.getBEMaxIncreaseCPUPercent(strategy, 20*1000, nodeMilliCPU))
+
+	// restore in 20 seconds with the interval of 2 seconds
+	strategy.CPUSuppressRampUpSeconds = pointer.Int64Ptr(20)
+	assert.Equal(t, 0.1, cpuSuppress.getBEMaxIncreaseCPUPercent(strategy, 20*1000, nodeMilliCPU))
+	// the ls usage increases a little
+	assert.Equal(t, 0.1, cpuSuppress.getBEMaxIncreaseCPUPercent(strategy, 20*1000+500, nodeMilliCPU))
+	// hold while the ls usage keeps increasing
+	assert.Equal(t, float64(0), cpuSuppress.getBEMaxIncreaseCPUPercent(strategy, 30*1000, nodeMilliCPU))
+	// the ls usage decreases
+	assert.Equal(t, 0.1, cpuSuppress.getBEMaxIncreaseCPUPercent(strategy, 25*1000, nodeMilliCPU))
+
+	// the period shorter than the interval restores at once
+	strategy.CPUSuppressRampUpSeconds = pointer.Int64Ptr(1)
+	assert.Equal(t, float64(1), cpuSuppress.getBEMaxIncreaseCPUPercent(strategy, 25*1000, nodeMilliCPU))
+}
+
+func Test_adjustByCfsQuotaWithRampUp(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	beQosDir := util.GetKubeQosRelativePath(corev1.PodQOSBestEffort)
+	helper.CreateCgroupFile(beQosDir, system.CPUCFSQuota)
+	node := &corev1.Node{
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("80"),
+			},
+		},
+	}
+	helper.WriteCgroupFileContents(beQosDir, system.CPUCFSQuota, strconv.FormatInt(10*cfsPeriod, 10))
+	adjustByCfsQuota(node.Status.Capacity.Cpu(), node, 0.05)
+	assert.Equal(t, strconv.FormatInt(14*cfsPeriod, 10), helper.ReadCgroupFileContents(beQosDir, system.CPUCFSQuota))
+	// hold the ramp-up
+	adjustByCfsQuota(node.Status.Capacity.Cpu(), node, 0)
+	assert.Equal(t, strconv.FormatInt(14*cfsPeriod, 10), helper.ReadCgroupFileContents(beQosDir, system.CPUCFSQuota))
+}
+
+func Test_cpuSuppress_isLSCPUPressureExceeded(t *testing.T) {
+	newPod := func(name string, qosClass apiext.QoSClass) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				UID:    types.UID(name),
+				Labels: map[string]string{apiext.LabelPodQoS: string(qosClass)},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: "main",
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+						},
+					},
+				},
+			},
+		}
+	}
+	lsPod := newPod("ls-pod", apiext.QoSLS)
+	bePod := newPod("be-pod", apiext.QoSBE)
+	podMetas := []*statesinformer.PodMeta{{Pod: lsPod}, {Pod: bePod}}
+
+	tests := []struct {
+		name     string
+		strategy *slov1alpha1.ResourceThresholdStrategy
+		lsPSI    *metriccache.PodPSIMetric
+		want     bool
+	}{
+		{
+			name:     "threshold not set",
+			strategy: &slov1alpha1.ResourceThresholdStrategy{},
+			lsPSI:    &metriccache.PodPSIMetric{PodUID: "ls-pod", PSI: metriccache.PSIMetric{CPUSomeAvg10: 50}},
+			want:     false,
+		},
+		{
+			name:     "ls pod cpu pressure below threshold",
+			strategy: &slov1alpha1.ResourceThresholdStrategy{CPUSuppressPSIThresholdPercent: pointer.Int64Ptr(20)},
+			lsPSI:    &metriccache.PodPSIMetric{PodUID: "ls-pod", PSI: metriccache.PSIMetric{CPUSomeAvg10: 10, MemSomeAvg10: 50}},
+			want:     false,
+		},
+		{
+			name:     "ls pod cpu pressure exceeds threshold",
+			strategy: &slov1alpha1.ResourceThresholdStrategy{CPUSuppressPSIThresholdPercent: pointer.Int64Ptr(20)},
+			lsPSI:    &metriccache.PodPSIMetric{PodUID: "ls-pod", PSI: metriccache.PSIMetric{CPUSomeAvg10: 25}},
+			want:     true,
+		},
+		{
+			name:     "ls pod psi missing",
+			strategy: &slov1alpha1.ResourceThresholdStrategy{CPUSuppressPSIThresholdPercent: pointer.Int64Ptr(20)},
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+
+			mockMetricCache := mockmetriccache.NewMockMetricCache(ctl)
+			lsPodUID := string(lsPod.UID)
+			lsQueryResult := metriccache.PodPSIQueryResult{Metric: tt.lsPSI}
+			if tt.lsPSI == nil {
+				lsQueryResult.Error = fmt.Errorf("not exist")
+			}
+			mockMetricCache.EXPECT().GetPodPSIMetric(&lsPodUID, gomock.Any()).Return(lsQueryResult).AnyTimes()
+			// the pressure of the BE pods is ignored
+			bePodUID := string(bePod.UID)
+			mockMetricCache.EXPECT().GetPodPSIMetric(&bePodUID, gomock.Any()).Times(0)
+
+			r := &resmanager{metricCache: mockMetricCache, collectResUsedIntervalSeconds: 1}
+			cpuSuppress := NewCPUSuppress(r)
+			assert.Equal(t, tt.want, cpuSuppress.isLSCPUPressureExceeded(tt.strategy, podMetas))
+		})
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resmanager
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+// getGangInfo returns the minMember and the number of the alive members of the gang. The koordlet only knows
+// the members on the node, so the gang is assumed to be fully alive as declared by the total-number annotation.
+// If the minMember is not declared, all the members are considered required.
+func (r *resmanager) getGangInfo(gangKey string, pod *corev1.Pod) (int, int) {
+	localMembers := 0
+	for _, podMeta := range r.statesInformer.GetAllPods() {
+		p := podMeta.Pod
+		if util.GetGangKey(p) == gangKey && p.DeletionTimestamp == nil &&
+			p.Status.Phase != corev1.PodSucceeded && p.Status.Phase != corev1.PodFailed {
+			localMembers++
+		}
+	}
+	minMember := util.GetGangMinMember(pod)
+	aliveMembers := util.GetGangTotalMember(pod)
+	if aliveMembers < localMembers {
+		aliveMembers = localMembers
+	}
+	if minMember == 0 {
+		minMember = aliveMembers
+	}
+	return minMember, aliveMembers
+}
+
+// selectGangEvictablePods returns the pods which can be evicted without breaking their gangs and the gangs
+// which are selected entirely. The members of an entire gang should be evicted together.
+func (r *resmanager) selectGangEvictablePods(pods []*corev1.Pod) ([]*corev1.Pod, sets.String) {
+	gangInfos := map[string][2]int{}
+	gangInfo := func(gangKey string, pod *corev1.Pod) (int, int) {
+		if info, ok := gangInfos[gangKey]; ok {
+			return info[0], info[1]
+		}
+		minMember, aliveMembers := r.getGangInfo(gangKey, pod)
+		gangInfos[gangKey] = [2]int{minMember, aliveMembers}
+		return minMember, aliveMembers
+	}
+	selected := util.SelectGangEvictablePods(pods, gangInfo)
+
+	counts := map[string]int{}
+	for _, pod := range selected {
+		if gangKey := util.GetGangKey(pod); gangKey != "" {
+			counts[gangKey]++
+		}
+	}
+	wholeGangs := sets.NewString()
+	for gangKey, count := range counts {
+		if count >= gangInfos[gangKey][1] {
+			wholeGangs.Insert(gangKey)
+		}
+	}
+	return selected, wholeGangs
+}
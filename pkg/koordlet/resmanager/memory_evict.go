@@ -22,12 +22,15 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/executor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/util"
 )
 
 const (
@@ -110,7 +113,8 @@ func (m *MemoryEvictor) memoryEvict() {
 	}
 
 	nodeMemoryUsage := nodeMetric.MemoryUsed.MemoryWithoutCache.Value() * 100 / memoryCapacity
-	if nodeMemoryUsage < *thresholdPercent {
+	memoryPressureExceeded := m.isMemoryPressureExceeded(thresholdConfig)
+	if nodeMemoryUsage < *thresholdPercent && !memoryPressureExceeded {
 		klog.V(5).Infof("skip memory evict, node memory usage(%v) is below threshold(%v)", nodeMemoryUsage, thresholdConfig)
 		return
 	}
@@ -124,20 +128,48 @@ func (m *MemoryEvictor) memoryEvict() {
 	)
 
 	memoryNeedRelease := memoryCapacity * (nodeMemoryUsage - lowerPercent) / 100
+	if memoryPressureExceeded && memoryNeedRelease < memoryCapacity*memoryReleaseBufferPercent/100 {
+		// the memory is contended even if the usage is below the lower percent, release a buffer at least
+		memoryNeedRelease = memoryCapacity * memoryReleaseBufferPercent / 100
+	}
 	m.killAndEvictBEPods(node, podMetrics, memoryNeedRelease)
 }
 
+// isMemoryPressureExceeded returns whether the memory pressure of the node exceeds MemoryEvictPSIThresholdPercent.
+func (m *MemoryEvictor) isMemoryPressureExceeded(strategy *slov1alpha1.ResourceThresholdStrategy) bool {
+	if strategy.MemoryEvictPSIThresholdPercent == nil {
+		return false
+	}
+	queryResult := m.resManager.collectNodePSIMetricLast()
+	if queryResult.Error != nil || queryResult.Metric == nil {
+		return false
+	}
+	threshold := float64(*strategy.MemoryEvictPSIThresholdPercent)
+	if queryResult.Metric.PSI.MemFullAvg10 < threshold {
+		return false
+	}
+	klog.Infof("node(%v) memory pressure %.2f%% exceeds threshold %v%%", m.resManager.nodeName,
+		queryResult.Metric.PSI.MemFullAvg10, threshold)
+	return true
+}
+
 func (m *MemoryEvictor) killAndEvictBEPods(node *corev1.Node, podMetrics []*metriccache.PodResourceMetric, memoryNeedRelease int64) {
-	bePodInfos := m.getSortedBEPodInfos(podMetrics)
+	bePodInfos, wholeGangs := m.selectGangEvictablePodInfos(m.getSortedBEPodInfos(podMetrics))
 	message := fmt.Sprintf("killAndEvictBEPods for node(%v), need to release memory: %v", m.resManager.nodeName, memoryNeedRelease)
 	memoryReleased := int64(0)
 
 	var killedPods []*corev1.Pod
+	killedGangs := sets.NewString()
 	for _, bePod := range bePodInfos {
-		if memoryReleased < memoryNeedRelease {
+		gangKey := util.GetGangKey(bePod.pod)
+		// the members of an entire gang are killed together
+		if memoryReleased < memoryNeedRelease || killedGangs.Has(gangKey) {
 			killMsg := fmt.Sprintf("%v, kill pod: %v", message, bePod.pod.Name)
 			killContainers(bePod.pod, killMsg)
 			killedPods = append(killedPods, bePod.pod)
+			if wholeGangs.Has(gangKey) {
+				killedGangs.Insert(gangKey)
+			}
 			if bePod.podMetric != nil {
 				memoryReleased += bePod.podMetric.MemoryUsed.MemoryWithoutCache.Value()
 			}
@@ -179,3 +211,19 @@ func (m *MemoryEvictor) getSortedBEPodInfos(podMetrics []*metriccache.PodResourc
 
 	return bePodInfos
 }
+
+// selectGangEvictablePodInfos filters the pods whose eviction breaks their gangs, see resmanager.selectGangEvictablePods.
+func (m *MemoryEvictor) selectGangEvictablePodInfos(podInfos []*podInfo) ([]*podInfo, sets.String) {
+	pods := make([]*corev1.Pod, 0, len(podInfos))
+	infoMap := make(map[*corev1.Pod]*podInfo, len(podInfos))
+	for _, info := range podInfos {
+		pods = append(pods, info.pod)
+		infoMap[info.pod] = info
+	}
+	selected, wholeGangs := m.resManager.selectGangEvictablePods(pods)
+	selectedInfos := make([]*podInfo, 0, len(selected))
+	for _, pod := range selected {
+		selectedInfos = append(selectedInfos, infoMap[pod])
+	}
+	return selectedInfos, wholeGangs
+}
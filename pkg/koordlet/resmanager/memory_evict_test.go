@@ -51,6 +51,7 @@ func Test_memoryEvict(t *testing.T) {
 		node               *corev1.Node
 		nodeMetric         *metriccache.NodeResourceMetric
 		podMetrics         []*metriccache.PodResourceMetric
+		nodePSIMetric      *metriccache.NodePSIMetric
 		pods               []*corev1.Pod
 		thresholdConfig    *slov1alpha1.ResourceThresholdStrategy
 		expectEvictPods    []*corev1.Pod
@@ -364,6 +365,72 @@ func Test_memoryEvict(t *testing.T) {
 				createMemoryEvictTestPod("test_noqos_pod", apiext.QoSNone, 100),
 			},
 		},
+		{
+			name: "test_memoryevict_MemoryEvictPSIThresholdPercent_under_evict_line",
+			node: getNode("80", "120G"),
+			pods: []*corev1.Pod{
+				createMemoryEvictTestPod("test_lsr_pod", apiext.QoSLSR, 1000),
+				createMemoryEvictTestPod("test_ls_pod", apiext.QoSLS, 500),
+				createMemoryEvictTestPod("test_be_pod_priority100_1", apiext.QoSBE, 100),
+				createMemoryEvictTestPod("test_be_pod_priority100_2", apiext.QoSBE, 100),
+				createMemoryEvictTestPod("test_be_pod_priority120", apiext.QoSBE, 120),
+			},
+			nodeMetric: &metriccache.NodeResourceMetric{
+				MemoryUsed: metriccache.MemoryMetric{
+					MemoryWithoutCache: resource.MustParse("80G"),
+				},
+			},
+			podMetrics: []*metriccache.PodResourceMetric{
+				createPodResourceMetric("test_lsr_pod", "30G"),
+				createPodResourceMetric("test_ls_pod", "30G"),
+				createPodResourceMetric("test_be_pod_priority100_1", "4G"),
+				createPodResourceMetric("test_be_pod_priority100_2", "8G"), // evict
+				createPodResourceMetric("test_be_pod_priority120", "8G"),
+			},
+			nodePSIMetric: &metriccache.NodePSIMetric{PSI: metriccache.PSIMetric{MemFullAvg10: 20}},
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:                         pointer.BoolPtr(true),
+				MemoryEvictThresholdPercent:    pointer.Int64Ptr(80),
+				MemoryEvictPSIThresholdPercent: pointer.Int64Ptr(10),
+			}, // release the buffer 2.4G
+			expectEvictPods: []*corev1.Pod{
+				createMemoryEvictTestPod("test_be_pod_priority100_2", apiext.QoSBE, 100),
+			},
+			expectNotEvictPods: []*corev1.Pod{
+				createMemoryEvictTestPod("test_lsr_pod", apiext.QoSLSR, 1000),
+				createMemoryEvictTestPod("test_ls_pod", apiext.QoSLS, 500),
+				createMemoryEvictTestPod("test_be_pod_priority100_1", apiext.QoSBE, 100),
+				createMemoryEvictTestPod("test_be_pod_priority120", apiext.QoSBE, 120),
+			},
+		},
+		{
+			name: "test_memoryevict_MemoryEvictPSIThresholdPercent_below_threshold",
+			node: getNode("80", "120G"),
+			pods: []*corev1.Pod{
+				createMemoryEvictTestPod("test_ls_pod", apiext.QoSLS, 500),
+				createMemoryEvictTestPod("test_be_pod_priority100_1", apiext.QoSBE, 100),
+			},
+			nodeMetric: &metriccache.NodeResourceMetric{
+				MemoryUsed: metriccache.MemoryMetric{
+					MemoryWithoutCache: resource.MustParse("80G"),
+				},
+			},
+			podMetrics: []*metriccache.PodResourceMetric{
+				createPodResourceMetric("test_ls_pod", "60G"),
+				createPodResourceMetric("test_be_pod_priority100_1", "20G"),
+			},
+			nodePSIMetric: &metriccache.NodePSIMetric{PSI: metriccache.PSIMetric{MemSomeAvg10: 20, MemFullAvg10: 5}},
+			thresholdConfig: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:                         pointer.BoolPtr(true),
+				MemoryEvictThresholdPercent:    pointer.Int64Ptr(80),
+				MemoryEvictPSIThresholdPercent: pointer.Int64Ptr(10),
+			},
+			expectEvictPods: []*corev1.Pod{},
+			expectNotEvictPods: []*corev1.Pod{
+				createMemoryEvictTestPod("test_ls_pod", apiext.QoSLS, 500),
+				createMemoryEvictTestPod("test_be_pod_priority100_1", apiext.QoSBE, 100),
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -379,6 +446,8 @@ func Test_memoryEvict(t *testing.T) {
 			mockMetricCache := mock_metriccache.NewMockMetricCache(ctl)
 			mockNodeQueryResult := metriccache.NodeResourceQueryResult{Metric: tt.nodeMetric}
 			mockMetricCache.EXPECT().GetNodeResourceMetric(gomock.Any()).Return(mockNodeQueryResult).AnyTimes()
+			mockNodePSIQueryResult := metriccache.NodePSIQueryResult{Metric: tt.nodePSIMetric}
+			mockMetricCache.EXPECT().GetNodePSIMetric(gomock.Any()).Return(mockNodePSIQueryResult).AnyTimes()
 			for _, podMetric := range tt.podMetrics {
 				mockPodQueryResult := metriccache.PodResourceQueryResult{Metric: podMetric}
 				mockMetricCache.EXPECT().GetPodResourceMetric(&podMetric.PodUID, gomock.Any()).Return(mockPodQueryResult).AnyTimes()
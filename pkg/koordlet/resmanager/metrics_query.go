@@ -122,6 +122,27 @@ func (r *resmanager) collectContainerThrottledMetricLast(containerID *string) me
 	return queryResult
 }
 
+func (r *resmanager) collectNodePSIMetricLast() metriccache.NodePSIQueryResult {
+	queryParam := generateQueryParamsLast(r.collectResUsedIntervalSeconds * 2)
+	queryResult := r.metricCache.GetNodePSIMetric(queryParam)
+	if queryResult.Error != nil {
+		klog.V(4).Infof("get node psi metric failed, error %v", queryResult.Error)
+	}
+	return queryResult
+}
+
+func (r *resmanager) collectPodPSIMetricLast(podUID *string) metriccache.PodPSIQueryResult {
+	if podUID == nil {
+		return metriccache.PodPSIQueryResult{QueryResult: metriccache.QueryResult{Error: fmt.Errorf("pod is nil")}}
+	}
+	queryParam := generateQueryParamsLast(r.collectResUsedIntervalSeconds * 2)
+	queryResult := r.metricCache.GetPodPSIMetric(podUID, queryParam)
+	if queryResult.Error != nil {
+		klog.V(4).Infof("get pod %v psi metric failed, error %v", *podUID, queryResult.Error)
+	}
+	return queryResult
+}
+
 func generateQueryParamsAvg(windowSeconds int64) *metriccache.QueryParam {
 	end := time.Now()
 	start := end.Add(-time.Duration(windowSeconds) * time.Second)
@@ -0,0 +1,383 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resmanager
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/system"
+)
+
+// The egress traffic of the NIC is shaped by the htb classes:
+//
+//	1: htb (default 0x11)
+//	└── 1:1 total bandwidth
+//	    ├── 1:10 LS, rate = total - BE min, ceil = total
+//	    │   ├── 1:11 LS default, the traffic of the host and the LS pods without the pod class
+//	    │   └── 1:100+ pod classes of the LS pods
+//	    └── 1:20 BE, rate = BE min, ceil = BE max
+//	        ├── 1:21 BE default, the traffic of the BE pods without the pod class
+//	        └── 1:100+ pod classes of the BE pods
+//
+// The traffic is classified by the net_cls.classid of the pod with the tc cgroup filter. The ceil of BE protects
+// the bandwidth of LS pods even if BE pods saturate the NIC. The ingress traffic of a pod is limited by the tbf
+// qdisc on the host side veth of the pod, which is the egress of the veth.
+const (
+	netQoSRootClassMinor      uint32 = 0x1
+	netQoSLSClassMinor        uint32 = 0x10
+	netQoSLSDefaultClassMinor uint32 = 0x11
+	netQoSBEClassMinor        uint32 = 0x20
+	netQoSBEDefaultClassMinor uint32 = 0x21
+	netQoSPodClassMinorStart  uint32 = 0x100
+	netQoSPodClassMinorEnd    uint32 = 0xffff
+
+	netQoSLSPriority = "0"
+	netQoSBEPriority = "7"
+
+	// netQoSMinRate is the min rate of the htb classes, which must be positive
+	netQoSMinRate int64 = 8 * 1000
+	// netQoSIngressLatency is the max time a packet waits in the tbf qdisc
+	netQoSIngressLatency = "25ms"
+	// netQoSMinIngressBurst is the min burst of the tbf qdisc in bytes, which must be larger than the mtu
+	netQoSMinIngressBurst int64 = 16 * 1024
+)
+
+type netQoSPodState struct {
+	// classMinor is the minor of the pod class, zero if the pod has no class
+	classMinor  uint32
+	classParent uint32
+	classRate   int64
+	classCeil   int64
+	classPrio   int32
+
+	ingressDevice string
+	ingressLimit  int64
+}
+
+type NetQoSReconcile struct {
+	resManager *resmanager
+
+	device         string
+	totalBandwidth int64
+	beMinBandwidth int64
+	beMaxBandwidth int64
+	// classifyEnabled is false if net_cls is unavailable, e.g. on the cgroup v2, then only the ingress limit works
+	classifyEnabled bool
+
+	podStates  map[string]*netQoSPodState
+	nextMinor  uint32
+	freeMinors []uint32
+}
+
+func NewNetQoSReconcile(resManager *resmanager) *NetQoSReconcile {
+	return &NetQoSReconcile{
+		resManager: resManager,
+		podStates:  map[string]*netQoSPodState{},
+		nextMinor:  netQoSPodClassMinorStart,
+	}
+}
+
+func (n *NetQoSReconcile) RunInit(stopCh <-chan struct{}) error {
+	cfg := n.resManager.config
+	if cfg.NetQoSBEMinPercent < 0 || cfg.NetQoSBEMinPercent > cfg.NetQoSBEMaxPercent || cfg.NetQoSBEMaxPercent > 100 {
+		return fmt.Errorf("invalid be bandwidth percent, min %d, max %d", cfg.NetQoSBEMinPercent, cfg.NetQoSBEMaxPercent)
+	}
+
+	n.device = cfg.NetQoSDevice
+	if n.device == "" {
+		device, err := system.GetDefaultRouteInterface()
+		if err != nil {
+			return fmt.Errorf("failed to get the device of the default route, err: %v", err)
+		}
+		n.device = device
+	}
+	if cfg.NetQoSTotalBandwidthMbps > 0 {
+		n.totalBandwidth = cfg.NetQoSTotalBandwidthMbps * 1000 * 1000
+	} else {
+		speed, err := system.GetInterfaceSpeed(n.device)
+		if err != nil {
+			return fmt.Errorf("failed to get the speed of device %s, please specify the total bandwidth, err: %v", n.device, err)
+		}
+		n.totalBandwidth = speed
+	}
+	n.beMinBandwidth = n.totalBandwidth * cfg.NetQoSBEMinPercent / 100
+	n.beMaxBandwidth = n.totalBandwidth * cfg.NetQoSBEMaxPercent / 100
+
+	n.classifyEnabled = !system.IsCgroupV2() && system.FileExists(system.GetCgroupSubsystemDir(system.CgroupNetClsDir))
+	if !n.classifyEnabled {
+		klog.Warningf("net_cls is unavailable, only the ingress limit of pods is enforced")
+		return nil
+	}
+	if err := n.initNodeClasses(); err != nil {
+		return fmt.Errorf("failed to init the tc classes of device %s, err: %v", n.device, err)
+	}
+	klog.Infof("net qos initialized, device %s, total bandwidth %d, be min %d, be max %d",
+		n.device, n.totalBandwidth, n.beMinBandwidth, n.beMaxBandwidth)
+	return nil
+}
+
+func (n *NetQoSReconcile) initNodeClasses() error {
+	lsRate := n.totalBandwidth - n.beMinBandwidth
+	cmds := [][]string{
+		{"qdisc", "replace", "dev", n.device, "root", "handle", system.TCQdiscHandle(system.TCRootHandleMajor), "htb",
+			"default", strconv.FormatUint(uint64(netQoSLSDefaultClassMinor), 16)},
+		n.htbClassArgs(system.TCQdiscHandle(system.TCRootHandleMajor), netQoSRootClassMinor, n.totalBandwidth, n.totalBandwidth, ""),
+		n.htbClassArgs(n.classID(netQoSRootClassMinor), netQoSLSClassMinor, lsRate, n.totalBandwidth, netQoSLSPriority),
+		n.htbClassArgs(n.classID(netQoSLSClassMinor), netQoSLSDefaultClassMinor, lsRate, n.totalBandwidth, netQoSLSPriority),
+		n.htbClassArgs(n.classID(netQoSRootClassMinor), netQoSBEClassMinor, n.beMinBandwidth, n.beMaxBandwidth, netQoSBEPriority),
+		n.htbClassArgs(n.classID(netQoSBEClassMinor), netQoSBEDefaultClassMinor, n.beMinBandwidth, n.beMaxBandwidth, netQoSBEPriority),
+		{"filter", "replace", "dev", n.device, "parent", system.TCQdiscHandle(system.TCRootHandleMajor), "protocol", "all",
+			"prio", "10", "handle", "1:", "cgroup"},
+	}
+	for _, cmd := range cmds {
+		if err := system.ExecTCOnHost(cmd...); err != nil {
+			return err
+		}
+	}
+	return n.cleanupStalePodClasses()
+}
+
+// cleanupStalePodClasses deletes the pod classes left by the last run, since the classes are allocated in memory.
+func (n *NetQoSReconcile) cleanupStalePodClasses() error {
+	out, _, err := system.ExecCmdOnHost([]string{"tc", "class", "show", "dev", n.device})
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		// class htb 1:100 parent 1:10 prio 0 rate 100Mbit ceil 100Mbit burst 1600b cburst 1600b
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[0] != "class" {
+			continue
+		}
+		ids := strings.Split(fields[2], ":")
+		if len(ids) != 2 {
+			continue
+		}
+		minor, err := strconv.ParseUint(ids[1], 16, 32)
+		if err != nil || uint32(minor) < netQoSPodClassMinorStart {
+			continue
+		}
+		if err := system.ExecTCOnHost("class", "del", "dev", n.device, "classid", fields[2]); err != nil {
+			klog.Warningf("failed to delete stale tc class %s of device %s, err: %v", fields[2], n.device, err)
+		}
+	}
+	return nil
+}
+
+func (n *NetQoSReconcile) classID(minor uint32) string {
+	return system.TCClassID(system.TCRootHandleMajor, minor)
+}
+
+func (n *NetQoSReconcile) htbClassArgs(parent string, minor uint32, rate, ceil int64, prio string) []string {
+	args := []string{"class", "replace", "dev", n.device, "parent", parent, "classid", n.classID(minor), "htb",
+		"rate", system.TCRate(util.MaxInt64(rate, netQoSMinRate)), "ceil", system.TCRate(util.MaxInt64(ceil, netQoSMinRate))}
+	if prio != "" {
+		args = append(args, "prio", prio)
+	}
+	return args
+}
+
+func (n *NetQoSReconcile) reconcile() {
+	seen := map[string]bool{}
+	for _, podMeta := range n.resManager.statesInformer.GetAllPods() {
+		if podMeta == nil || podMeta.Pod == nil {
+			continue
+		}
+		uid := string(podMeta.Pod.UID)
+		seen[uid] = true
+		cfg, err := extension.GetPodNetworkQoSConfig(podMeta.Pod)
+		if err != nil {
+			// keep the applied limits until the annotation is fixed
+			klog.Warningf("failed to parse the network qos of pod %s, err: %v", util.GetPodKey(podMeta.Pod), err)
+			continue
+		}
+		state := n.podStates[uid]
+		if state == nil {
+			state = &netQoSPodState{}
+			n.podStates[uid] = state
+		}
+		if n.classifyEnabled {
+			n.reconcilePodEgress(podMeta, cfg, state)
+		}
+		n.reconcilePodIngress(podMeta.Pod, cfg, state)
+	}
+
+	for uid, state := range n.podStates {
+		if seen[uid] {
+			continue
+		}
+		// the veth is deleted with the pod, so only the pod class needs to be released
+		if err := n.releasePodClass(state); err != nil {
+			// retry in the next round
+			klog.Warningf("failed to release the tc class of pod %s, err: %v", uid, err)
+		}
+		if state.classMinor == 0 {
+			delete(n.podStates, uid)
+		}
+	}
+}
+
+func (n *NetQoSReconcile) reconcilePodEgress(podMeta *statesinformer.PodMeta, cfg *extension.NetworkQoSConfig, state *netQoSPodState) {
+	pod := podMeta.Pod
+	parent, defaultMinor, parentRate, parentCeil := netQoSLSClassMinor, uint32(0), n.totalBandwidth-n.beMinBandwidth, n.totalBandwidth
+	if extension.GetPodQoSClass(pod) == extension.QoSBE {
+		parent, defaultMinor, parentRate, parentCeil = netQoSBEClassMinor, netQoSBEDefaultClassMinor, n.beMinBandwidth, n.beMaxBandwidth
+	}
+
+	targetMinor := defaultMinor
+	if cfg != nil && (cfg.EgressLimit != nil || cfg.Priority != nil) {
+		ceil := parentCeil
+		if cfg.EgressLimit != nil && cfg.EgressLimit.Value() < ceil {
+			ceil = cfg.EgressLimit.Value()
+		}
+		prio := extension.NetworkQoSPriorityHighest
+		if cfg.Priority != nil {
+			prio = *cfg.Priority
+		}
+		if err := n.ensurePodClass(state, parent, util.MinInt64(parentRate, ceil), ceil, prio); err != nil {
+			klog.Warningf("failed to set the tc class of pod %s, err: %v", util.GetPodKey(pod), err)
+			return
+		}
+		targetMinor = state.classMinor
+	} else if err := n.releasePodClass(state); err != nil {
+		// the pod is moved to the default class anyway, retry releasing it in the next round
+		klog.Warningf("failed to release the tc class of pod %s, err: %v", util.GetPodKey(pod), err)
+	}
+
+	classID := "0"
+	if targetMinor != 0 {
+		classID = system.NetClsClassIDValue(system.TCRootHandleMajor, targetMinor)
+	}
+	dirs := []string{util.GetPodCgroupDirWithKube(podMeta.CgroupDir)}
+	// the classid is copied from the parent only when the cgroup is created, so set the containers explicitly
+	for i := range pod.Status.ContainerStatuses {
+		containerDir, err := util.GetContainerCgroupPathWithKube(podMeta.CgroupDir, &pod.Status.ContainerStatuses[i])
+		if err != nil {
+			continue
+		}
+		dirs = append(dirs, containerDir)
+	}
+	for _, dir := range dirs {
+		current, err := system.CgroupFileRead(dir, system.NetClsClassID)
+		if err != nil || current == classID {
+			continue
+		}
+		if err := system.CgroupFileWrite(dir, system.NetClsClassID, classID); err != nil {
+			klog.Warningf("failed to set net_cls.classid of pod %s, dir %s, err: %v", util.GetPodKey(pod), dir, err)
+		}
+	}
+}
+
+func (n *NetQoSReconcile) ensurePodClass(state *netQoSPodState, parent uint32, rate, ceil int64, prio int32) error {
+	if state.classMinor != 0 && state.classParent == parent && state.classRate == rate &&
+		state.classCeil == ceil && state.classPrio == prio {
+		return nil
+	}
+	// the parent of a class can not be changed, e.g. the qos class of the pod is changed, so the class is
+	// re-created only after the old one is released, otherwise retry in the next round
+	if state.classMinor != 0 && state.classParent != parent {
+		if err := n.releasePodClass(state); err != nil {
+			return err
+		}
+	}
+	minor := state.classMinor
+	if minor == 0 {
+		var err error
+		if minor, err = n.allocateMinor(); err != nil {
+			return err
+		}
+	}
+	args := n.htbClassArgs(n.classID(parent), minor, rate, ceil, strconv.Itoa(int(prio)))
+	if err := system.ExecTCOnHost(args...); err != nil {
+		if state.classMinor == 0 {
+			n.freeMinors = append(n.freeMinors, minor)
+		}
+		return err
+	}
+	state.classMinor, state.classParent, state.classRate, state.classCeil, state.classPrio = minor, parent, rate, ceil, prio
+	return nil
+}
+
+// releasePodClass deletes the pod class, and keeps the state to retry if failed.
+func (n *NetQoSReconcile) releasePodClass(state *netQoSPodState) error {
+	if state.classMinor == 0 {
+		return nil
+	}
+	if err := system.ExecTCOnHost("class", "del", "dev", n.device, "classid", n.classID(state.classMinor)); err != nil {
+		return fmt.Errorf("failed to delete tc class %s of device %s, err: %v", n.classID(state.classMinor), n.device, err)
+	}
+	n.freeMinors = append(n.freeMinors, state.classMinor)
+	state.classMinor, state.classParent, state.classRate, state.classCeil, state.classPrio = 0, 0, 0, 0, 0
+	return nil
+}
+
+func (n *NetQoSReconcile) allocateMinor() (uint32, error) {
+	if len(n.freeMinors) > 0 {
+		minor := n.freeMinors[len(n.freeMinors)-1]
+		n.freeMinors = n.freeMinors[:len(n.freeMinors)-1]
+		return minor, nil
+	}
+	if n.nextMinor > netQoSPodClassMinorEnd {
+		return 0, fmt.Errorf("no available tc class")
+	}
+	minor := n.nextMinor
+	n.nextMinor++
+	return minor, nil
+}
+
+func (n *NetQoSReconcile) reconcilePodIngress(pod *corev1.Pod, cfg *extension.NetworkQoSConfig, state *netQoSPodState) {
+	var limit int64
+	if cfg != nil && cfg.IngressLimit != nil && !pod.Spec.HostNetwork && pod.Status.PodIP != "" {
+		limit = cfg.IngressLimit.Value()
+	}
+	if limit == state.ingressLimit {
+		return
+	}
+	if limit == 0 {
+		if err := system.ExecTCOnHost("qdisc", "del", "dev", state.ingressDevice, "root"); err != nil {
+			klog.Warningf("failed to delete the ingress limit of pod %s, device %s, err: %v", util.GetPodKey(pod), state.ingressDevice, err)
+			return
+		}
+		state.ingressDevice, state.ingressLimit = "", 0
+		return
+	}
+
+	device := state.ingressDevice
+	if device == "" {
+		var err error
+		if device, err = system.GetRouteInterface(pod.Status.PodIP); err != nil {
+			klog.Warningf("failed to get the host device of pod %s, err: %v", util.GetPodKey(pod), err)
+			return
+		}
+	}
+	// the burst should hold the traffic of 10ms at least
+	burst := util.MaxInt64(limit/8/100, netQoSMinIngressBurst)
+	if err := system.ExecTCOnHost("qdisc", "replace", "dev", device, "root", "tbf", "rate", system.TCRate(limit),
+		"burst", strconv.FormatInt(burst, 10), "latency", netQoSIngressLatency); err != nil {
+		klog.Warningf("failed to set the ingress limit of pod %s, device %s, err: %v", util.GetPodKey(pod), device, err)
+		return
+	}
+	state.ingressDevice, state.ingressLimit = device, limit
+}
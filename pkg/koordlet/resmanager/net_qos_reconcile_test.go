@@ -0,0 +1,192 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resmanager
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/system"
+)
+
+func newTestNetQoSPodMeta(name string, qos extension.QoSClass, podIP, networkQoS string) *statesinformer.PodMeta {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			UID:       types.UID("uid-" + name),
+			Labels: map[string]string{
+				extension.LabelPodQoS: string(qos),
+			},
+			Annotations: map[string]string{},
+		},
+		Status: corev1.PodStatus{
+			PodIP: podIP,
+		},
+	}
+	if networkQoS != "" {
+		pod.Annotations[extension.AnnotationPodNetworkQoS] = networkQoS
+	}
+	return &statesinformer.PodMeta{
+		Pod:       pod,
+		CgroupDir: "pod-" + name,
+	}
+}
+
+func Test_NetQoSReconcile(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.MkDirAll(system.CgroupNetClsDir)
+
+	var cmds []string
+	oldExecCmdOnHost := system.ExecCmdOnHost
+	defer func() {
+		system.ExecCmdOnHost = oldExecCmdOnHost
+	}()
+	system.ExecCmdOnHost = func(args []string) ([]byte, int, error) {
+		cmd := strings.Join(args, " ")
+		switch cmd {
+		case "tc class show dev eth0":
+			return []byte("class htb 1:11 parent 1:10 prio 0 rate 900Mbit ceil 1Gbit\nclass htb 1:100 parent 1:10 prio 0 rate 10Mbit ceil 10Mbit\n"), 0, nil
+		case "ip route get 10.0.0.2":
+			return []byte("10.0.0.2 dev veth-be src 10.0.0.1 uid 0\n"), 0, nil
+		}
+		cmds = append(cmds, cmd)
+		return nil, 0, nil
+	}
+
+	lsPod := newTestNetQoSPodMeta("ls", extension.QoSLS, "10.0.0.1", `{"egressLimit": "100M", "priority": 2}`)
+	bePod := newTestNetQoSPodMeta("be", extension.QoSBE, "10.0.0.2", `{"ingressLimit": "50M"}`)
+	otherPod := newTestNetQoSPodMeta("other", extension.QoSLS, "10.0.0.3", "")
+	for _, podMeta := range []*statesinformer.PodMeta{lsPod, bePod, otherPod} {
+		helper.WriteCgroupFileContents(util.GetPodCgroupDirWithKube(podMeta.CgroupDir), system.NetClsClassID, "0")
+	}
+	pods := []*statesinformer.PodMeta{lsPod, bePod, otherPod}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	si := mock_statesinformer.NewMockStatesInformer(ctrl)
+	si.EXPECT().GetAllPods().DoAndReturn(func() []*statesinformer.PodMeta { return pods }).AnyTimes()
+
+	cfg := NewDefaultConfig()
+	cfg.NetQoSDevice = "eth0"
+	cfg.NetQoSTotalBandwidthMbps = 1000
+	r := NewNetQoSReconcile(&resmanager{config: cfg, statesInformer: si})
+
+	// init the node classes, and delete the stale pod class
+	assert.NoError(t, r.RunInit(nil))
+	assert.Equal(t, []string{
+		"tc qdisc replace dev eth0 root handle 1: htb default 11",
+		"tc class replace dev eth0 parent 1: classid 1:1 htb rate 1000000000bit ceil 1000000000bit",
+		"tc class replace dev eth0 parent 1:1 classid 1:10 htb rate 900000000bit ceil 1000000000bit prio 0",
+		"tc class replace dev eth0 parent 1:10 classid 1:11 htb rate 900000000bit ceil 1000000000bit prio 0",
+		"tc class replace dev eth0 parent 1:1 classid 1:20 htb rate 100000000bit ceil 600000000bit prio 7",
+		"tc class replace dev eth0 parent 1:20 classid 1:21 htb rate 100000000bit ceil 600000000bit prio 7",
+		"tc filter replace dev eth0 parent 1: protocol all prio 10 handle 1: cgroup",
+		"tc class del dev eth0 classid 1:100",
+	}, cmds)
+
+	// the ls pod gets its own class, the be pod is limited by the tbf on its veth
+	cmds = nil
+	r.reconcile()
+	assert.Equal(t, []string{
+		"tc class replace dev eth0 parent 1:10 classid 1:100 htb rate 100000000bit ceil 100000000bit prio 2",
+		"tc qdisc replace dev veth-be root tbf rate 50000000bit burst 62500 latency 25ms",
+	}, cmds)
+	assert.Equal(t, system.NetClsClassIDValue(1, 0x100), helper.ReadCgroupFileContents(util.GetPodCgroupDirWithKube(lsPod.CgroupDir), system.NetClsClassID))
+	assert.Equal(t, system.NetClsClassIDValue(1, 0x21), helper.ReadCgroupFileContents(util.GetPodCgroupDirWithKube(bePod.CgroupDir), system.NetClsClassID))
+	assert.Equal(t, "0", helper.ReadCgroupFileContents(util.GetPodCgroupDirWithKube(otherPod.CgroupDir), system.NetClsClassID))
+
+	// nothing changed
+	cmds = nil
+	r.reconcile()
+	assert.Nil(t, cmds)
+
+	// the ls pod is deleted and the ingress limit of the be pod is removed
+	cmds = nil
+	delete(bePod.Pod.Annotations, extension.AnnotationPodNetworkQoS)
+	pods = []*statesinformer.PodMeta{bePod, otherPod}
+	r.reconcile()
+	assert.Equal(t, []string{
+		"tc qdisc del dev veth-be root",
+		"tc class del dev eth0 classid 1:100",
+	}, cmds)
+	assert.Equal(t, []uint32{0x100}, r.freeMinors)
+	assert.Len(t, r.podStates, 2)
+}
+
+func Test_NetQoSReconcile_ChangeClassParent(t *testing.T) {
+	var cmds []string
+	deleteFailed := false
+	oldExecCmdOnHost := system.ExecCmdOnHost
+	defer func() {
+		system.ExecCmdOnHost = oldExecCmdOnHost
+	}()
+	system.ExecCmdOnHost = func(args []string) ([]byte, int, error) {
+		cmd := strings.Join(args, " ")
+		cmds = append(cmds, cmd)
+		if deleteFailed && strings.HasPrefix(cmd, "tc class del") {
+			return nil, 2, fmt.Errorf("device or resource busy")
+		}
+		return nil, 0, nil
+	}
+
+	r := NewNetQoSReconcile(&resmanager{config: NewDefaultConfig()})
+	r.device = "eth0"
+	state := &netQoSPodState{}
+	assert.NoError(t, r.ensurePodClass(state, netQoSLSClassMinor, 100000000, 100000000, 2))
+	assert.Equal(t, uint32(0x100), state.classMinor)
+
+	// the class under the old parent fails to be released, so it's not replaced
+	cmds = nil
+	deleteFailed = true
+	assert.Error(t, r.ensurePodClass(state, netQoSBEClassMinor, 100000000, 100000000, 2))
+	assert.Equal(t, []string{"tc class del dev eth0 classid 1:100"}, cmds)
+	assert.Equal(t, uint32(0x100), state.classMinor)
+	assert.Equal(t, netQoSLSClassMinor, state.classParent)
+
+	// retry in the next round
+	cmds = nil
+	deleteFailed = false
+	assert.NoError(t, r.ensurePodClass(state, netQoSBEClassMinor, 100000000, 100000000, 2))
+	assert.Equal(t, []string{
+		"tc class del dev eth0 classid 1:100",
+		"tc class replace dev eth0 parent 1:20 classid 1:100 htb rate 100000000bit ceil 100000000bit prio 2",
+	}, cmds)
+	assert.Equal(t, netQoSBEClassMinor, state.classParent)
+}
+
+func Test_NetQoSReconcile_InvalidAnnotation(t *testing.T) {
+	podMeta := newTestNetQoSPodMeta("ls", extension.QoSLS, "10.0.0.1", `{"egressLimit": "100M", "priority": 8}`)
+	_, err := extension.GetPodNetworkQoSConfig(podMeta.Pod)
+	assert.Error(t, err)
+
+	podMeta = newTestNetQoSPodMeta("ls", extension.QoSLS, "10.0.0.1", `{"ingressLimit": "0"}`)
+	_, err = extension.GetPodNetworkQoSConfig(podMeta.Pod)
+	assert.Error(t, err)
+}
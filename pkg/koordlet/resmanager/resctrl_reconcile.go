@@ -20,12 +20,14 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/executor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
@@ -44,6 +46,8 @@ const (
 	BEResctrlGroup = "BE"
 	// UnknownResctrlGroup is the resctrl group which is unknown to reconcile
 	UnknownResctrlGroup = "Unknown"
+	// CacheGroupResctrlGroupPrefix is the name prefix of the resctrl groups of the CacheGroups
+	CacheGroupResctrlGroupPrefix = "CG-"
 )
 
 var (
@@ -97,6 +101,41 @@ func getResourceQOSForResctrlGroup(strategy *slov1alpha1.ResourceQOSStrategy, gr
 	return nil
 }
 
+// getCacheGroupResctrlGroup returns the resctrl group of the CacheGroup, e.g. CG-default_redis.
+// The underscore is not allowed in the namespace and name, so the groups never conflict.
+func getCacheGroupResctrlGroup(namespace, name string) string {
+	return CacheGroupResctrlGroupPrefix + namespace + "_" + name
+}
+
+// getPodCacheGroup returns the resctrl group and the CacheGroup the pod joins.
+// It returns empty if the pod does not join any existing CacheGroup or the CacheGroup feature is disabled.
+func (r *ResctrlReconcile) getPodCacheGroup(pod *corev1.Pod) (string, *slov1alpha1.CacheGroup) {
+	if !features.DefaultKoordletFeatureGate.Enabled(features.CacheGroup) {
+		return "", nil
+	}
+	name := extension.GetPodCacheGroup(pod)
+	if name == "" {
+		return "", nil
+	}
+	cacheGroup := r.resManager.statesInformer.GetCacheGroup(pod.Namespace, name)
+	if cacheGroup == nil {
+		klog.V(5).Infof("CacheGroup %s/%s of pod %s not found", pod.Namespace, name, util.GetPodKey(pod))
+		return "", nil
+	}
+	return getCacheGroupResctrlGroup(pod.Namespace, name), cacheGroup
+}
+
+func getResourceQOSForCacheGroup(cacheGroup *slov1alpha1.CacheGroup) *slov1alpha1.ResourceQOS {
+	if cacheGroup == nil || cacheGroup.Spec.ResctrlQOS == nil {
+		return nil
+	}
+	return &slov1alpha1.ResourceQOS{
+		ResctrlQOS: &slov1alpha1.ResctrlQOSCfg{
+			ResctrlQOS: *cacheGroup.Spec.ResctrlQOS,
+		},
+	}
+}
+
 func initCatResctrl() error {
 	// check if the resctrl root and l3_cat feature are enabled correctly
 	if err := system.CheckAndTryEnableResctrlCat(); err != nil {
@@ -264,38 +303,42 @@ func (r *ResctrlReconcile) calculateAndApplyCatL3GroupTasks(group string, taskId
 	return nil
 }
 
-func (r *ResctrlReconcile) reconcileCatResctrlPolicy(qosStrategy *slov1alpha1.ResourceQOSStrategy) {
-	// 1. retrieve rdt configs from nodeSLOSpec
-	// 2.1 get cbm and l3 numbers, which are general for all resctrl groups
-	// 2.2 calculate applying resctrl policies, like cat policy and so on, with each rdt config
-	// 3. apply the policies onto resctrl groups
-
+// getCatL3CbmAndNum returns the cat l3 cbm and the number of l3 caches, which are general for all resctrl groups.
+func (r *ResctrlReconcile) getCatL3CbmAndNum() (uint, int, error) {
 	// read cat l3 cbm
 	nodeCPUInfo, err := r.resManager.metricCache.GetNodeCPUInfo(&metriccache.QueryParam{})
 	if err != nil {
-		klog.Warningf("failed to get nodeCPUInfo, err: %v", err)
-		return
+		return 0, 0, fmt.Errorf("failed to get nodeCPUInfo, err: %v", err)
 	}
 	if nodeCPUInfo == nil {
-		klog.Warning("failed to get nodeCPUInfo, the value is nil")
-		return
+		return 0, 0, fmt.Errorf("failed to get nodeCPUInfo, the value is nil")
 	}
 	cbmStr := nodeCPUInfo.BasicInfo.CatL3CbmMask
 	if len(cbmStr) <= 0 {
-		klog.Warning("failed to get cat l3 cbm, cbm is empty")
-		return
+		return 0, 0, fmt.Errorf("failed to get cat l3 cbm, cbm is empty")
 	}
 	cbmValue, err := strconv.ParseUint(cbmStr, 16, 32)
 	if err != nil {
-		klog.Warningf("failed to parse cat l3 cbm %s, err: %v", cbmStr, err)
-		return
+		return 0, 0, fmt.Errorf("failed to parse cat l3 cbm %s, err: %v", cbmStr, err)
 	}
-	cbm := uint(cbmValue)
 
 	// get the number of l3 caches; it is larger than 0
 	l3Num := int(nodeCPUInfo.TotalInfo.NumberL3s)
 	if l3Num <= 0 {
-		klog.Warningf("failed to get the number of l3 caches, invalid value %v", l3Num)
+		return 0, 0, fmt.Errorf("failed to get the number of l3 caches, invalid value %v", l3Num)
+	}
+	return uint(cbmValue), l3Num, nil
+}
+
+func (r *ResctrlReconcile) reconcileCatResctrlPolicy(qosStrategy *slov1alpha1.ResourceQOSStrategy) {
+	// 1. retrieve rdt configs from nodeSLOSpec
+	// 2.1 get cbm and l3 numbers, which are general for all resctrl groups
+	// 2.2 calculate applying resctrl policies, like cat policy and so on, with each rdt config
+	// 3. apply the policies onto resctrl groups
+
+	cbm, l3Num, err := r.getCatL3CbmAndNum()
+	if err != nil {
+		klog.Warningf("failed to get cat l3 cbm and the number of l3 caches, err: %v", err)
 		return
 	}
 
@@ -339,6 +382,11 @@ func (r *ResctrlReconcile) reconcileResctrlGroups(qosStrategy *slov1alpha1.Resou
 			continue
 		}
 
+		// the pods in the CacheGroups are reconciled in reconcileCacheGroups
+		if group, _ := r.getPodCacheGroup(pod); group != "" {
+			continue
+		}
+
 		// only extension-QoS-specified pod are considered
 		podQoSCfg := getPodResourceQoSByQoSClass(pod, qosStrategy, r.resManager.config)
 		if podQoSCfg.ResctrlQOS.Enable == nil || !(*podQoSCfg.ResctrlQOS.Enable) {
@@ -362,6 +410,99 @@ func (r *ResctrlReconcile) reconcileResctrlGroups(qosStrategy *slov1alpha1.Resou
 	}
 }
 
+func (r *ResctrlReconcile) reconcileCacheGroups() {
+	// 1. collect the CacheGroups joined by the pods on the node, and remove the resctrl groups of the others
+	// 2. create the resctrl groups of the CacheGroups and apply their policies against `schemata` file
+	// 3. add the related task ids in the resctrl groups of the CacheGroups
+	if !features.DefaultKoordletFeatureGate.Enabled(features.CacheGroup) {
+		return
+	}
+
+	cacheGroups := map[string]*slov1alpha1.CacheGroup{}
+	cacheGroupPods := map[string][]*statesinformer.PodMeta{}
+	podsMeta := r.resManager.statesInformer.GetAllPods()
+	for _, podMeta := range podsMeta {
+		pod := podMeta.Pod
+		// only Running and Pending pods are considered
+		if pod.Status.Phase != corev1.PodRunning && pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+		group, cacheGroup := r.getPodCacheGroup(pod)
+		if group == "" {
+			continue
+		}
+		cacheGroups[group] = cacheGroup
+		cacheGroupPods[group] = append(cacheGroupPods[group], podMeta)
+	}
+
+	// the number of resctrl groups (CLOS) is limited by the hardware, so release the unused ones
+	r.removeStaleCacheGroups(cacheGroups)
+
+	cbm, l3Num, err := r.getCatL3CbmAndNum()
+	if err != nil {
+		klog.Warningf("failed to get cat l3 cbm and the number of l3 caches, err: %v", err)
+		return
+	}
+	for group, cacheGroup := range cacheGroups {
+		// the creation fails with ENOSPC if the CLOS are exhausted, and the pods stay in their current groups
+		if err = initCatGroupIfNotExist(group); err != nil {
+			klog.Warningf("failed to init resctrl group for CacheGroup %s/%s, err: %v",
+				cacheGroup.Namespace, cacheGroup.Name, err)
+			continue
+		}
+
+		resourceQoS := getResourceQOSForCacheGroup(cacheGroup)
+		err = r.calculateAndApplyCatL3PolicyForGroup(group, cbm, l3Num, resourceQoS)
+		if err != nil {
+			klog.Warningf("failed to apply l3 cat policy for group %v, err: %v", group, err)
+		}
+		err = r.calculateAndApplyCatMbPolicyForGroup(group, l3Num, resourceQoS)
+		if err != nil {
+			klog.Warningf("failed to apply cat MB policy for group %v, err: %v", group, err)
+		}
+
+		curTaskMap, err := system.ReadResctrlTasksMap(group)
+		if err != nil {
+			klog.Warningf("failed to read Cat L3 tasks for resctrl group %s, err: %s", group, err)
+		}
+		var taskIds []int
+		for _, podMeta := range cacheGroupPods[group] {
+			taskIds = append(taskIds, getPodCgroupNewTaskIds(podMeta, curTaskMap)...)
+		}
+		err = r.calculateAndApplyCatL3GroupTasks(group, taskIds)
+		if err != nil {
+			klog.Warningf("failed to apply l3 cat tasks for group %s, err %s", group, err)
+		}
+	}
+}
+
+// removeStaleCacheGroups removes the resctrl groups of the CacheGroups no pod on the node joins.
+// The remaining tasks of a removed group are moved back to the root group by the kernel.
+func (r *ResctrlReconcile) removeStaleCacheGroups(cacheGroups map[string]*slov1alpha1.CacheGroup) {
+	entries, err := os.ReadDir(system.GetResctrlSubsystemDirPath())
+	if err != nil {
+		klog.Warningf("failed to list resctrl groups, err: %v", err)
+		return
+	}
+	for _, entry := range entries {
+		group := entry.Name()
+		if !entry.IsDir() || !strings.HasPrefix(group, CacheGroupResctrlGroupPrefix) {
+			continue
+		}
+		if _, ok := cacheGroups[group]; ok {
+			continue
+		}
+		if err = os.Remove(system.GetResctrlGroupRootDirPath(group)); err != nil {
+			klog.Warningf("failed to remove resctrl group %s, err: %v", group, err)
+			continue
+		}
+		// the schemata of the group is reset if it is recreated later
+		r.executor.Forget(executor.CalculateL3SchemataResource(group, "", 0),
+			executor.CalculateMbSchemataResource(group, "", 0))
+		klog.V(4).Infof("remove resctrl group %s of the stale CacheGroup", group)
+	}
+}
+
 func (r *ResctrlReconcile) reconcile() {
 	// Step 0. create and init them if resctrl groups do not exist
 	// Step 1. reconcile rdt policies against `schemata` file
@@ -393,5 +534,6 @@ func (r *ResctrlReconcile) reconcile() {
 		return
 	}
 	r.reconcileCatResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+	r.reconcileCacheGroups()
 	r.reconcileResctrlGroups(nodeSLO.Spec.ResourceQOSStrategy)
 }
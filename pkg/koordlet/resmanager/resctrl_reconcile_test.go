@@ -28,10 +28,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/pointer"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/executor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	mock_metriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
@@ -1089,6 +1091,114 @@ func TestResctrlReconcile_reconcileResctrlGroups(t *testing.T) {
 	})
 }
 
+func TestResctrlReconcile_reconcileCacheGroups(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.CacheGroup, true)()
+
+	wantResctrlTaskStr := "122450122454123111128912"
+	testingContainerParentDir := "kubepods.slice/p0/cri-containerd-c0.scope"
+	testingContainerTasksStr := "122450\n122454\n123111\n128912"
+	newTestingPodMeta := func(name, cacheGroup string) *statesinformer.PodMeta {
+		return &statesinformer.PodMeta{
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      name,
+					UID:       "p0",
+					Labels: map[string]string{
+						extension.LabelPodQoS: string(extension.QoSLS),
+					},
+					Annotations: map[string]string{
+						extension.AnnotationPodCacheGroup: cacheGroup,
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "container0",
+						},
+					},
+				},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					ContainerStatuses: []corev1.ContainerStatus{
+						{
+							Name:        "container0",
+							ContainerID: "containerd://c0",
+						},
+					},
+				},
+			},
+			CgroupDir: "p0",
+		}
+	}
+	cacheGroup := &slov1alpha1.CacheGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "redis",
+		},
+		Spec: slov1alpha1.CacheGroupSpec{
+			ResctrlQOS: &slov1alpha1.ResctrlQOS{
+				CATRangeStartPercent: pointer.Int64Ptr(0),
+				CATRangeEndPercent:   pointer.Int64Ptr(30),
+			},
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{
+		newTestingPodMeta("pod0", "redis"),
+		newTestingPodMeta("pod1", "not-exist"),
+	}).AnyTimes()
+	statesInformer.EXPECT().GetCacheGroup("default", "redis").Return(cacheGroup).AnyTimes()
+	statesInformer.EXPECT().GetCacheGroup("default", "not-exist").Return(nil).AnyTimes()
+	metricCache := mock_metriccache.NewMockMetricCache(ctrl)
+	metricCache.EXPECT().GetNodeCPUInfo(&metriccache.QueryParam{}).Return(&metriccache.NodeCPUInfo{
+		BasicInfo: util.CPUBasicInfo{CatL3CbmMask: "7ff"},
+		TotalInfo: util.CPUTotalInfo{NumberL3s: 2},
+	}, nil).AnyTimes()
+	rm := &resmanager{statesInformer: statesInformer, metricCache: metricCache}
+	r := ResctrlReconcile{
+		resManager: rm,
+		executor:   executor.NewResourceUpdateExecutor("ResctrlReconcile", 30),
+	}
+	stop := make(chan struct{})
+	r.RunInit(stop)
+	defer func() { stop <- struct{}{} }()
+
+	helper := system.NewFileTestUtil(t)
+	sysFSRootDirName := "reconcileCacheGroups"
+	helper.MkDirAll(sysFSRootDirName)
+	system.Conf.SysFSRootDir = path.Join(helper.TempDir, sysFSRootDirName)
+	system.CommonRootDir = ""
+
+	testingPrepareResctrlL3CatGroups(t, "7ff", "L3:0=7ff;1=7ff\n")
+	testingPrepareContainerCgroupCPUTasks(t, testingContainerParentDir, testingContainerTasksStr)
+	resctrlDirPath := filepath.Join(system.Conf.SysFSRootDir, system.ResctrlDir)
+	// the tasks file is created by the kernel along with the group
+	group := getCacheGroupResctrlGroup("default", "redis")
+	assert.Equal(t, "CG-default_redis", group)
+	assert.NoError(t, os.MkdirAll(filepath.Join(resctrlDirPath, group), 0700))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(resctrlDirPath, group, system.ResctrlTaskFileName), []byte{}, 0666))
+	staleGroup := getCacheGroupResctrlGroup("default", "stale")
+	assert.NoError(t, os.MkdirAll(filepath.Join(resctrlDirPath, staleGroup), 0700))
+
+	r.reconcileCacheGroups()
+
+	got, err := ioutil.ReadFile(filepath.Join(resctrlDirPath, group, system.SchemataFileName))
+	assert.NoError(t, err)
+	assert.Equal(t, "L3:0=f;1=f;\n", string(got))
+	got, err = ioutil.ReadFile(filepath.Join(resctrlDirPath, group, system.ResctrlTaskFileName))
+	assert.NoError(t, err)
+	assert.Equal(t, wantResctrlTaskStr, string(got))
+	_, err = os.Stat(filepath.Join(resctrlDirPath, staleGroup))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(resctrlDirPath, getCacheGroupResctrlGroup("default", "not-exist")))
+	assert.True(t, os.IsNotExist(err))
+}
+
 func TestResctrlReconcile_reconcile(t *testing.T) {
 	// preparing
 	testingContainerParentDir := "kubepods.slice/p0/cri-containerd-c0.scope"
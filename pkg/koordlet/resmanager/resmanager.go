@@ -19,6 +19,7 @@ package resmanager
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
@@ -95,7 +96,7 @@ func NewResManager(cfg *Config, schema *apiruntime.Scheme, kubeClient clientset.
 
 // isFeatureDisabled returns whether the featuregate is disabled by nodeSLO config
 func isFeatureDisabled(nodeSLO *slov1alpha1.NodeSLO, feature featuregate.Feature) (bool, error) {
-	if nodeSLO == nil || nodeSLO.Spec == (slov1alpha1.NodeSLOSpec{}) {
+	if nodeSLO == nil || reflect.DeepEqual(nodeSLO.Spec, slov1alpha1.NodeSLOSpec{}) {
 		return true, fmt.Errorf("cannot parse feature config for invalid nodeSLO %v", nodeSLO)
 	}
 
@@ -146,6 +147,14 @@ func (r *resmanager) Run(stopCh <-chan struct{}) error {
 	util.RunFeatureWithInit(func() error { return rdtResCtrl.RunInit(stopCh) }, rdtResCtrl.reconcile,
 		[]featuregate.Feature{features.RdtResctrl}, r.config.ReconcileIntervalSeconds, stopCh)
 
+	netQoS := NewNetQoSReconcile(r)
+	util.RunFeatureWithInit(func() error { return netQoS.RunInit(stopCh) }, netQoS.reconcile,
+		[]featuregate.Feature{features.NetQoS}, r.config.ReconcileIntervalSeconds, stopCh)
+
+	blkIOReconcile := NewBlkIOReconcile(r)
+	util.RunFeatureWithInit(func() error { return blkIOReconcile.RunInit(stopCh) }, blkIOReconcile.reconcile,
+		[]featuregate.Feature{features.BlkIOReconcile}, r.config.ReconcileIntervalSeconds, stopCh)
+
 	klog.Infof("start resmanager extensions")
 	plugins.SetupPlugins(r.kubeClient, r.metricCache, r.statesInformer)
 	utilruntime.Must(plugins.StartPlugins(r.config.QOSExtensionCfg, stopCh))
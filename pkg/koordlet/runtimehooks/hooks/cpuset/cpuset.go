@@ -76,6 +76,12 @@ func (p *cpusetPlugin) SetContainerCPUSet(proto protocol.HooksProtocol) error {
 		return err
 	} else if cpusetVal != "" {
 		containerCtx.Response.Resources.CPUSet = pointer.StringPtr(cpusetVal)
+		// bind the memory to the NUMA Nodes as the NUMA topology policy of the pod required
+		if memsVal, err := getCPUSetMemsFromPod(containerReq.PodAnnotations); err != nil {
+			return err
+		} else if memsVal != "" {
+			containerCtx.Response.Resources.CPUSetMems = pointer.StringPtr(memsVal)
+		}
 		return nil
 	}
 
@@ -100,3 +106,11 @@ func getCPUSetFromPod(podAnnotations map[string]string) (string, error) {
 	}
 	return podAlloc.CPUSet, nil
 }
+
+func getCPUSetMemsFromPod(podAnnotations map[string]string) (string, error) {
+	podAlloc, err := ext.GetResourceStatus(podAnnotations)
+	if err != nil {
+		return "", err
+	}
+	return podAlloc.NUMANodes, nil
+}
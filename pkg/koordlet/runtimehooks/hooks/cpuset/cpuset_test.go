@@ -265,3 +265,27 @@ func Test_getCPUSetFromPod(t *testing.T) {
 		})
 	}
 }
+
+func Test_cpusetPlugin_SetContainerCPUSetMems(t *testing.T) {
+	testHelper := system.NewFileTestUtil(t)
+	containerCtx := &protocol.ContainerContext{
+		Request: protocol.ContainerRequest{
+			CgroupParent: "kubepods/test-pod/test-container/",
+			PodAnnotations: map[string]string{
+				ext.AnnotationResourceStatus: util.DumpJSON(&ext.ResourceStatus{
+					CPUSet:    "2-4",
+					NUMANodes: "0",
+				}),
+			},
+		},
+	}
+	initCPUSet(containerCtx.Request.CgroupParent, "", testHelper)
+	testHelper.WriteCgroupFileContents(containerCtx.Request.CgroupParent, system.CPUSetMems, "0-1")
+
+	p := &cpusetPlugin{}
+	assert.NoError(t, p.SetContainerCPUSet(containerCtx))
+	assert.Equal(t, "0", *containerCtx.Response.Resources.CPUSetMems)
+	containerCtx.ReconcilerDone()
+	assert.Equal(t, "2-4", getCPUSet(containerCtx.Request.CgroupParent, testHelper))
+	assert.Equal(t, "0", testHelper.ReadCgroupFileContents(containerCtx.Request.CgroupParent, system.CPUSetMems))
+}
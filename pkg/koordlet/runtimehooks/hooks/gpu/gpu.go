@@ -31,11 +31,26 @@ import (
 
 const GpuAllocEnv = "NVIDIA_VISIBLE_DEVICES"
 
+// RemoteGPUEndpointsEnv is the env of the endpoints to attach the remote GPUs over the fabric,
+// which is consumed by the remote GPU client in the container.
+const RemoteGPUEndpointsEnv = "KOORD_REMOTE_GPU_ENDPOINTS"
+
+const (
+	// GPUMemoryLimitEnv is the env of the GPU memory limits in bytes of the visible GPUs in the order of
+	// NVIDIA_VISIBLE_DEVICES, which is enforced by the GPU isolation library in the container.
+	GPUMemoryLimitEnv = "KOORD_GPU_MEMORY_LIMIT"
+	// GPUCoreLimitEnv is the env of the compute utilization limits in percentage of the visible GPUs in the order of
+	// NVIDIA_VISIBLE_DEVICES, which is enforced by the GPU isolation library in the container.
+	GPUCoreLimitEnv = "KOORD_GPU_CORE_LIMIT"
+)
+
 type gpuPlugin struct{}
 
 func (p *gpuPlugin) Register() {
 	klog.V(5).Infof("register hook %v", "gpu env inject")
 	hooks.Register(rmconfig.PreCreateContainer, "gpu env inject", "inject NVIDIA_VISIBLE_DEVICES env into container", p.InjectContainerGPUEnv)
+	hooks.Register(rmconfig.PreCreateContainer, "remote gpu env inject", "inject KOORD_REMOTE_GPU_ENDPOINTS env into container", p.InjectContainerRemoteGPUEnv)
+	hooks.Register(rmconfig.PreCreateContainer, "gpu isolation env inject", "inject KOORD_GPU_MEMORY_LIMIT and KOORD_GPU_CORE_LIMIT env into container", p.InjectContainerGPUIsolationEnv)
 }
 
 var singleton *gpuPlugin
@@ -57,8 +72,8 @@ func (p *gpuPlugin) InjectContainerGPUEnv(proto protocol.HooksProtocol) error {
 	if err != nil {
 		return err
 	}
-	devices, ok := alloc[schedulingv1alpha1.GPU]
-	if !ok || len(devices) == 0 {
+	devices, migDevices := alloc[schedulingv1alpha1.GPU], alloc[schedulingv1alpha1.MIG]
+	if len(devices) == 0 && len(migDevices) == 0 {
 		klog.V(5).Infof("no gpu alloc info in pod anno, %s", containerReq.PodMeta.Name)
 		return nil
 	}
@@ -66,9 +81,99 @@ func (p *gpuPlugin) InjectContainerGPUEnv(proto protocol.HooksProtocol) error {
 	for _, d := range devices {
 		gpuIDs = append(gpuIDs, fmt.Sprintf("%d", d.Minor))
 	}
+	// the MIG devices are identified by the UUID
+	for _, d := range migDevices {
+		if d.UUID == "" {
+			return fmt.Errorf("uuid of mig device %d is empty", d.Minor)
+		}
+		gpuIDs = append(gpuIDs, d.UUID)
+	}
 	if containerCtx.Response.ContainerEnvs == nil {
 		containerCtx.Response.ContainerEnvs = make(map[string]string)
 	}
 	containerCtx.Response.ContainerEnvs[GpuAllocEnv] = strings.Join(gpuIDs, ",")
 	return nil
 }
+
+func (p *gpuPlugin) InjectContainerRemoteGPUEnv(proto protocol.HooksProtocol) error {
+	containerCtx := proto.(*protocol.ContainerContext)
+	if containerCtx == nil {
+		return fmt.Errorf("container protocol is nil for plugin gpu")
+	}
+	containerReq := containerCtx.Request
+	alloc, err := ext.GetDeviceAllocations(containerReq.PodAnnotations)
+	if err != nil {
+		return err
+	}
+	devices, ok := alloc[schedulingv1alpha1.RemoteGPU]
+	if !ok || len(devices) == 0 {
+		klog.V(5).Infof("no remote gpu alloc info in pod anno, %s", containerReq.PodMeta.Name)
+		return nil
+	}
+	endpoints := []string{}
+	for _, d := range devices {
+		if d.Endpoint == "" {
+			return fmt.Errorf("endpoint of remote gpu %d is empty", d.Minor)
+		}
+		endpoints = append(endpoints, d.Endpoint)
+	}
+	if containerCtx.Response.ContainerEnvs == nil {
+		containerCtx.Response.ContainerEnvs = make(map[string]string)
+	}
+	containerCtx.Response.ContainerEnvs[RemoteGPUEndpointsEnv] = strings.Join(endpoints, ",")
+	return nil
+}
+
+// InjectContainerGPUIsolationEnv injects the limits of the GPUs shared with the other pods according to the
+// GPU isolation level of the pod, the GPUs allocated exclusively are not limited.
+func (p *gpuPlugin) InjectContainerGPUIsolationEnv(proto protocol.HooksProtocol) error {
+	containerCtx := proto.(*protocol.ContainerContext)
+	if containerCtx == nil {
+		return fmt.Errorf("container protocol is nil for plugin gpu")
+	}
+	containerReq := containerCtx.Request
+	alloc, err := ext.GetDeviceAllocations(containerReq.PodAnnotations)
+	if err != nil {
+		return err
+	}
+	devices, ok := alloc[schedulingv1alpha1.GPU]
+	if !ok || len(devices) == 0 {
+		return nil
+	}
+	level, err := ext.GetGPUIsolationLevel(containerReq.PodAnnotations)
+	if err != nil {
+		return err
+	}
+	if level == ext.GPUIsolationLevelNone {
+		return nil
+	}
+
+	shared := false
+	memoryLimits := make([]string, 0, len(devices))
+	coreLimits := make([]string, 0, len(devices))
+	for _, d := range devices {
+		gpuCore, gpuMemoryRatio := d.Resources[ext.GPUCore], d.Resources[ext.GPUMemoryRatio]
+		if gpuCore.Value() < 100 || gpuMemoryRatio.Value() < 100 {
+			shared = true
+		}
+		gpuMemory, ok := d.Resources[ext.GPUMemory]
+		if !ok {
+			return fmt.Errorf("gpu memory of gpu %d is not allocated", d.Minor)
+		}
+		memoryLimits = append(memoryLimits, fmt.Sprintf("%d", gpuMemory.Value()))
+		coreLimits = append(coreLimits, fmt.Sprintf("%d", gpuCore.Value()))
+	}
+	if !shared {
+		klog.V(5).Infof("gpus are not shared by pod %s, skip the isolation", containerReq.PodMeta.Name)
+		return nil
+	}
+
+	if containerCtx.Response.ContainerEnvs == nil {
+		containerCtx.Response.ContainerEnvs = make(map[string]string)
+	}
+	containerCtx.Response.ContainerEnvs[GPUMemoryLimitEnv] = strings.Join(memoryLimits, ",")
+	if level == ext.GPUIsolationLevelMemoryAndCore {
+		containerCtx.Response.ContainerEnvs[GPUCoreLimitEnv] = strings.Join(coreLimits, ",")
+	}
+	return nil
+}
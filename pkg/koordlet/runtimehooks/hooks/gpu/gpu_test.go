@@ -62,6 +62,30 @@ func Test_InjectContainerGPUEnv(t *testing.T) {
 				},
 			},
 		},
+		{
+			"test mig alloc",
+			"MIG-0,MIG-1",
+			false,
+			&protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationDeviceAllocated: "{\"mig\": [{\"minor\": 8, \"uuid\": \"MIG-0\"},{\"minor\": 9, \"uuid\": \"MIG-1\"}]}",
+					},
+				},
+			},
+		},
+		{
+			"test mig alloc without uuid",
+			"",
+			true,
+			&protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationDeviceAllocated: "{\"mig\": [{\"minor\": 8}]}",
+					},
+				},
+			},
+		},
 	}
 	plugin := gpuPlugin{}
 	for _, tt := range tests {
@@ -77,3 +101,155 @@ func Test_InjectContainerGPUEnv(t *testing.T) {
 		}
 	}
 }
+
+func Test_InjectContainerRemoteGPUEnv(t *testing.T) {
+	tests := []struct {
+		name              string
+		expectedEndpoints string
+		expectedError     bool
+		proto             protocol.HooksProtocol
+	}{
+		{
+			"test empty proto",
+			"",
+			true,
+			nil,
+		},
+		{
+			"test normal remote gpu alloc",
+			"10.0.0.1:9999,10.0.0.2:9999",
+			false,
+			&protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationDeviceAllocated: "{\"remote-gpu\": [{\"minor\": 0, \"endpoint\": \"10.0.0.1:9999\"},{\"minor\": 1, \"endpoint\": \"10.0.0.2:9999\"}]}",
+					},
+				},
+			},
+		},
+		{
+			"test remote gpu alloc without endpoint",
+			"",
+			true,
+			&protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationDeviceAllocated: "{\"remote-gpu\": [{\"minor\": 0}]}",
+					},
+				},
+			},
+		},
+		{
+			"test local gpu alloc only",
+			"",
+			false,
+			&protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationDeviceAllocated: "{\"gpu\": [{\"minor\": 0},{\"minor\": 1}]}",
+					},
+				},
+			},
+		},
+	}
+	plugin := gpuPlugin{}
+	for _, tt := range tests {
+		var containerCtx *protocol.ContainerContext
+		if tt.proto != nil {
+			containerCtx = tt.proto.(*protocol.ContainerContext)
+		}
+		err := plugin.InjectContainerRemoteGPUEnv(containerCtx)
+		assert.Equal(t, tt.expectedError, err != nil, tt.name)
+		if tt.proto != nil {
+			containerCtx := tt.proto.(*protocol.ContainerContext)
+			assert.Equal(t, tt.expectedEndpoints, containerCtx.Response.ContainerEnvs[RemoteGPUEndpointsEnv], tt.name)
+		}
+	}
+}
+
+func Test_InjectContainerGPUIsolationEnv(t *testing.T) {
+	sharedAlloc := `{"gpu": [{"minor": 0, "resources": {"kubernetes.io/gpu-core": "50", "kubernetes.io/gpu-memory-ratio": "25", "kubernetes.io/gpu-memory": "4Gi"}}]}`
+	tests := []struct {
+		name                string
+		expectedMemoryLimit string
+		expectedCoreLimit   string
+		expectedError       bool
+		proto               protocol.HooksProtocol
+	}{
+		{
+			name:          "test empty proto",
+			expectedError: true,
+		},
+		{
+			name:                "test shared gpu with the default isolation level",
+			expectedMemoryLimit: "4294967296",
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationDeviceAllocated: sharedAlloc,
+					},
+				},
+			},
+		},
+		{
+			name:                "test shared gpu isolating memory and core",
+			expectedMemoryLimit: "4294967296",
+			expectedCoreLimit:   "50",
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationDeviceAllocated:   sharedAlloc,
+						ext.AnnotationGPUIsolationLevel: string(ext.GPUIsolationLevelMemoryAndCore),
+					},
+				},
+			},
+		},
+		{
+			name: "test shared gpu without isolation",
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationDeviceAllocated:   sharedAlloc,
+						ext.AnnotationGPUIsolationLevel: string(ext.GPUIsolationLevelNone),
+					},
+				},
+			},
+		},
+		{
+			name: "test exclusive gpus",
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationDeviceAllocated: `{"gpu": [{"minor": 0, "resources": {"kubernetes.io/gpu-core": "100", "kubernetes.io/gpu-memory-ratio": "100", "kubernetes.io/gpu-memory": "16Gi"}}]}`,
+					},
+				},
+			},
+		},
+		{
+			name:          "test invalid isolation level",
+			expectedError: true,
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationDeviceAllocated:   sharedAlloc,
+						ext.AnnotationGPUIsolationLevel: "Hard",
+					},
+				},
+			},
+		},
+	}
+	plugin := gpuPlugin{}
+	for _, tt := range tests {
+		var containerCtx *protocol.ContainerContext
+		if tt.proto != nil {
+			containerCtx = tt.proto.(*protocol.ContainerContext)
+		}
+		err := plugin.InjectContainerGPUIsolationEnv(containerCtx)
+		assert.Equal(t, tt.expectedError, err != nil, tt.name)
+		if tt.proto != nil {
+			containerCtx := tt.proto.(*protocol.ContainerContext)
+			assert.Equal(t, tt.expectedMemoryLimit, containerCtx.Response.ContainerEnvs[GPUMemoryLimitEnv], tt.name)
+			assert.Equal(t, tt.expectedCoreLimit, containerCtx.Response.ContainerEnvs[GPUCoreLimitEnv], tt.name)
+		}
+	}
+}
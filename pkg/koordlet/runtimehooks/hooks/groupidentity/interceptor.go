@@ -39,7 +39,7 @@ func (b *bvtPlugin) SetPodBvtValue(p protocol.HooksProtocol) error {
 	req := podCtx.Request
 	podQOS := ext.GetQoSClassByAttrs(req.Labels, req.Annotations)
 	podKubeQOS := util.GetKubeQoSByCgroupParent(req.CgroupParent)
-	podBvt := r.getPodBvtValue(podQOS, podKubeQOS)
+	podBvt := r.getPodBvtValue(podQOS, podKubeQOS, req.RuntimeClassName)
 	podCtx.Response.Resources.CPUBvt = pointer.Int64(podBvt)
 	return nil
 }
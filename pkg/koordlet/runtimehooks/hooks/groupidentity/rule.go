@@ -35,9 +35,14 @@ type bvtRule struct {
 	podQOSParams     map[ext.QoSClass]int64
 	kubeQOSDirParams map[corev1.PodQOSClass]int64
 	kubeQOSPodParams map[corev1.PodQOSClass]int64
+	// runtimeClassPodQOSParams overrides the podQOSParams for the pods of the runtime classes
+	runtimeClassPodQOSParams map[string]map[ext.QoSClass]int64
 }
 
-func (r *bvtRule) getPodBvtValue(podQoSClass ext.QoSClass, podKubeQOS corev1.PodQOSClass) int64 {
+func (r *bvtRule) getPodBvtValue(podQoSClass ext.QoSClass, podKubeQOS corev1.PodQOSClass, runtimeClassName string) int64 {
+	if val, exist := r.runtimeClassPodQOSParams[runtimeClassName][podQoSClass]; exist {
+		return val
+	}
 	if val, exist := r.podQOSParams[podQoSClass]; exist {
 		return val
 	}
@@ -96,6 +101,7 @@ func (b *bvtPlugin) parseRule(mergedNodeSLOIf interface{}) (bool, error) {
 			corev1.PodQOSBurstable:  burstablePodVal,
 			corev1.PodQOSBestEffort: besteffortPodVal,
 		},
+		runtimeClassPodQOSParams: parseRuntimeClassPodQOSParams(mergedNodeSLO.RuntimeClassStrategies),
 	}
 
 	updated := b.updateRule(newRule)
@@ -103,6 +109,33 @@ func (b *bvtPlugin) parseRule(mergedNodeSLOIf interface{}) (bool, error) {
 	return updated, nil
 }
 
+// parseRuntimeClassPodQOSParams parses the bvt values of the pods for the runtime classes overriding the
+// ResourceQOSStrategy, the kube qos dirs are shared by all the runtimes and always use the node-level values.
+func parseRuntimeClassPodQOSParams(strategies []slov1alpha1.RuntimeClassStrategy) map[string]map[ext.QoSClass]int64 {
+	var params map[string]map[ext.QoSClass]int64
+	for _, strategy := range strategies {
+		qosStrategy := strategy.ResourceQOSStrategy
+		if qosStrategy == nil {
+			continue
+		}
+		qosParams := map[ext.QoSClass]int64{}
+		for qos, resourceQOS := range map[ext.QoSClass]*slov1alpha1.ResourceQOS{
+			ext.QoSLSR: qosStrategy.LSRClass,
+			ext.QoSLS:  qosStrategy.LSClass,
+			ext.QoSBE:  qosStrategy.BEClass,
+		} {
+			if resourceQOS != nil && resourceQOS.CPUQOS != nil && resourceQOS.CPUQOS.GroupIdentity != nil {
+				qosParams[qos] = *resourceQOS.CPUQOS.GroupIdentity
+			}
+		}
+		if params == nil {
+			params = map[string]map[ext.QoSClass]int64{}
+		}
+		params[strategy.RuntimeClassName] = qosParams
+	}
+	return params
+}
+
 func (b *bvtPlugin) ruleUpdateCb(pods []*statesinformer.PodMeta) error {
 	if !b.SystemSupported() {
 		klog.V(5).Infof("plugin %s is not supported by system", name)
@@ -126,7 +159,7 @@ func (b *bvtPlugin) ruleUpdateCb(pods []*statesinformer.PodMeta) error {
 	for _, podMeta := range pods {
 		podQOS := ext.GetPodQoSClass(podMeta.Pod)
 		podKubeQOS := podMeta.Pod.Status.QOSClass
-		podBvt := r.getPodBvtValue(podQOS, podKubeQOS)
+		podBvt := r.getPodBvtValue(podQOS, podKubeQOS, util.GetPodRuntimeClassName(podMeta.Pod))
 		podCgroupPath := util.GetPodCgroupDirWithKube(podMeta.CgroupDir)
 		if err := sysutil.CgroupFileWrite(podCgroupPath, sysutil.CPUBVTWarpNs, strconv.FormatInt(podBvt, 10)); err != nil {
 			klog.Infof("update pod %s cpu bvt failed, dir %v, error %v",
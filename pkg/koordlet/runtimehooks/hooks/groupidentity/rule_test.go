@@ -38,10 +38,13 @@ func Test_bvtRule_getPodBvtValue(t *testing.T) {
 		podQOSParams     map[ext.QoSClass]int64
 		kubeQOSDirParams map[corev1.PodQOSClass]int64
 		kubeQOSPodParams map[corev1.PodQOSClass]int64
+
+		runtimeClassPodQOSParams map[string]map[ext.QoSClass]int64
 	}
 	type args struct {
-		podQoSClass ext.QoSClass
-		podKubeQoS  corev1.PodQOSClass
+		podQoSClass      ext.QoSClass
+		podKubeQoS       corev1.PodQOSClass
+		runtimeClassName string
 	}
 	tests := []struct {
 		name   string
@@ -81,6 +84,40 @@ func Test_bvtRule_getPodBvtValue(t *testing.T) {
 			},
 			want: 1,
 		},
+		{
+			name: "use runtime class qos",
+			fields: fields{
+				podQOSParams: map[ext.QoSClass]int64{
+					ext.QoSLS: 2,
+				},
+				runtimeClassPodQOSParams: map[string]map[ext.QoSClass]int64{
+					"kata": {ext.QoSLS: 0},
+				},
+			},
+			args: args{
+				podQoSClass:      ext.QoSLS,
+				podKubeQoS:       corev1.PodQOSBurstable,
+				runtimeClassName: "kata",
+			},
+			want: 0,
+		},
+		{
+			name: "runtime class not overridden",
+			fields: fields{
+				podQOSParams: map[ext.QoSClass]int64{
+					ext.QoSLS: 2,
+				},
+				runtimeClassPodQOSParams: map[string]map[ext.QoSClass]int64{
+					"kata": {ext.QoSLS: 0},
+				},
+			},
+			args: args{
+				podQoSClass:      ext.QoSLS,
+				podKubeQoS:       corev1.PodQOSBurstable,
+				runtimeClassName: "gvisor",
+			},
+			want: 2,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -88,8 +125,10 @@ func Test_bvtRule_getPodBvtValue(t *testing.T) {
 				podQOSParams:     tt.fields.podQOSParams,
 				kubeQOSDirParams: tt.fields.kubeQOSDirParams,
 				kubeQOSPodParams: tt.fields.kubeQOSPodParams,
+
+				runtimeClassPodQOSParams: tt.fields.runtimeClassPodQOSParams,
 			}
-			if got := r.getPodBvtValue(tt.args.podQoSClass, tt.args.podKubeQoS); got != tt.want {
+			if got := r.getPodBvtValue(tt.args.podQoSClass, tt.args.podKubeQoS, tt.args.runtimeClassName); got != tt.want {
 				t.Errorf("getPodBvtValue() = %v, want %v", got, tt.want)
 			}
 		})
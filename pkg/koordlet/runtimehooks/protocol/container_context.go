@@ -83,6 +83,9 @@ func (c *ContainerResponse) ProxyDone(resp *runtimeapi.ContainerResourceHookResp
 	if c.Resources.CPUSet != nil {
 		resp.ContainerResources.CpusetCpus = *c.Resources.CPUSet
 	}
+	if c.Resources.CPUSetMems != nil {
+		resp.ContainerResources.CpusetMems = *c.Resources.CPUSetMems
+	}
 	if c.Resources.CFSQuota != nil {
 		resp.ContainerResources.CpuQuota = *c.Resources.CFSQuota
 	}
@@ -130,6 +133,18 @@ func (c *ContainerContext) injectForOrigin() {
 				"set container cpuset to %v", *c.Response.Resources.CPUSet).Do()
 		}
 	}
+	if c.Response.Resources.CPUSetMems != nil {
+		if err := injectCPUSetMems(c.Request.CgroupParent, *c.Response.Resources.CPUSetMems); err != nil {
+			klog.Infof("set container %v/%v/%v cpuset mems %v on cgroup parent %v failed, error %v", c.Request.PodMeta.Namespace,
+				c.Request.PodMeta.Name, c.Request.ContainerMeta.Name, *c.Response.Resources.CPUSetMems, c.Request.CgroupParent, err)
+		} else {
+			klog.V(5).Infof("set container %v/%v/%v cpuset mems %v on cgroup parent %v",
+				c.Request.PodMeta.Namespace, c.Request.PodMeta.Name, c.Request.ContainerMeta.Name,
+				*c.Response.Resources.CPUSetMems, c.Request.CgroupParent)
+			audit.V(2).Container(c.Request.ContainerMeta.ID).Reason("runtime-hooks").Message(
+				"set container cpuset mems to %v", *c.Response.Resources.CPUSetMems).Do()
+		}
+	}
 	// TODO other fields
 }
 
@@ -49,6 +49,9 @@ type PodRequest struct {
 	Labels       map[string]string
 	Annotations  map[string]string
 	CgroupParent string
+	// RuntimeClassName is the runtime handler from the proxy, which is assumed to be the same as the name of the
+	// RuntimeClass of the pod.
+	RuntimeClassName string
 }
 
 func (p *PodRequest) FromProxy(req *runtimeapi.PodSandboxHookRequest) {
@@ -56,6 +59,7 @@ func (p *PodRequest) FromProxy(req *runtimeapi.PodSandboxHookRequest) {
 	p.Labels = req.GetLabels()
 	p.Annotations = req.GetAnnotations()
 	p.CgroupParent = req.GetCgroupParent()
+	p.RuntimeClassName = req.GetRuntimeHandler()
 }
 
 func (p *PodRequest) FromReconciler(podMeta *statesinformer.PodMeta) {
@@ -63,6 +67,7 @@ func (p *PodRequest) FromReconciler(podMeta *statesinformer.PodMeta) {
 	p.Labels = podMeta.Pod.Labels
 	p.Annotations = podMeta.Pod.Annotations
 	p.CgroupParent = util.GetPodCgroupDirWithKube(podMeta.CgroupDir)
+	p.RuntimeClassName = util.GetPodRuntimeClassName(podMeta.Pod)
 }
 
 type PodResponse struct {
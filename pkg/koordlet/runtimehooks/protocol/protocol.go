@@ -58,13 +58,15 @@ type Resources struct {
 	CPUShares *int64
 	CFSQuota  *int64
 	CPUSet    *string
+	// CPUSetMems is the memory nodes of the container
+	CPUSetMems *string
 
 	// extended resources
 	CPUBvt *int64
 }
 
 func (r *Resources) IsOriginResSet() bool {
-	return r.CPUShares != nil || r.CFSQuota != nil || r.CPUSet != nil
+	return r.CPUShares != nil || r.CFSQuota != nil || r.CPUSet != nil || r.CPUSetMems != nil
 }
 
 func injectCPUSet(cgroupParent string, cpuset string) error {
@@ -74,6 +76,13 @@ func injectCPUSet(cgroupParent string, cpuset string) error {
 	return nil
 }
 
+func injectCPUSetMems(cgroupParent string, mems string) error {
+	if err := sysutil.CgroupFileWrite(cgroupParent, sysutil.CPUSetMems, mems); err != nil {
+		return err
+	}
+	return nil
+}
+
 func injectCPUBvt(cgroupParent string, bvtValue int64) error {
 	bvtValueStr := strconv.FormatInt(bvtValue, 10)
 	if err := sysutil.CgroupFileWrite(cgroupParent, sysutil.CPUBVTWarpNs, bvtValueStr); err != nil {
@@ -67,6 +67,20 @@ func (mr *MockStatesInformerMockRecorder) GetAllPods() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllPods", reflect.TypeOf((*MockStatesInformer)(nil).GetAllPods))
 }
 
+// GetCacheGroup mocks base method.
+func (m *MockStatesInformer) GetCacheGroup(namespace, name string) *v1alpha10.CacheGroup {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCacheGroup", namespace, name)
+	ret0, _ := ret[0].(*v1alpha10.CacheGroup)
+	return ret0
+}
+
+// GetCacheGroup indicates an expected call of GetCacheGroup.
+func (mr *MockStatesInformerMockRecorder) GetCacheGroup(namespace, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCacheGroup", reflect.TypeOf((*MockStatesInformer)(nil).GetCacheGroup), namespace, name)
+}
+
 // GetNode mocks base method.
 func (m *MockStatesInformer) GetNode() *v1.Node {
 	m.ctrl.T.Helper()
@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statesinformer
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apiruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	koordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
+)
+
+// GetCacheGroup returns the CacheGroup, or nil if it does not exist or the CacheGroup feature is disabled.
+func (s *statesInformer) GetCacheGroup(namespace, name string) *slov1alpha1.CacheGroup {
+	if s.cacheGroupInformer == nil {
+		return nil
+	}
+	obj, exist, err := s.cacheGroupInformer.GetStore().GetByKey(namespace + "/" + name)
+	if err != nil {
+		klog.Warningf("failed to get CacheGroup %s/%s, err: %v", namespace, name, err)
+		return nil
+	}
+	if !exist {
+		return nil
+	}
+	cacheGroup, ok := obj.(*slov1alpha1.CacheGroup)
+	if !ok {
+		klog.Errorf("unable to convert object to *slov1alpha1.CacheGroup, %T", obj)
+		return nil
+	}
+	return cacheGroup.DeepCopy()
+}
+
+func newCacheGroupInformer(client koordclientset.Interface) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (apiruntime.Object, error) {
+				return client.SloV1alpha1().CacheGroups(metav1.NamespaceAll).List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return client.SloV1alpha1().CacheGroups(metav1.NamespaceAll).Watch(context.TODO(), options)
+			},
+		},
+		&slov1alpha1.CacheGroup{},
+		time.Hour*12,
+		cache.Indexers{},
+	)
+}
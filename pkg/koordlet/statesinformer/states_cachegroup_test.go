@@ -0,0 +1,59 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statesinformer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	koordclientfake "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
+)
+
+func Test_statesInformer_GetCacheGroup(t *testing.T) {
+	cacheGroup := &slov1alpha1.CacheGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-group",
+		},
+		Spec: slov1alpha1.CacheGroupSpec{
+			ResctrlQOS: &slov1alpha1.ResctrlQOS{
+				CATRangeStartPercent: pointer.Int64Ptr(0),
+				CATRangeEndPercent:   pointer.Int64Ptr(50),
+			},
+		},
+	}
+
+	// feature disabled
+	s := &statesInformer{}
+	assert.Nil(t, s.GetCacheGroup("default", "test-group"))
+
+	s.cacheGroupInformer = newCacheGroupInformer(koordclientfake.NewSimpleClientset(cacheGroup))
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go s.cacheGroupInformer.Run(stopCh)
+	assert.True(t, cache.WaitForCacheSync(stopCh, s.cacheGroupInformer.HasSynced))
+
+	got := s.GetCacheGroup("default", "test-group")
+	assert.NotNil(t, got)
+	assert.Equal(t, cacheGroup.Spec, got.Spec)
+	assert.Nil(t, s.GetCacheGroup("other", "test-group"))
+}
@@ -18,6 +18,7 @@ package statesinformer
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"time"
 
@@ -31,8 +32,23 @@ import (
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/util"
 )
 
+var (
+	getGPUBusIDFn      = getGPUBusID
+	getPCIDeviceInfoFn = util.GetPCIDeviceInfo
+	getRDMADevicesFn   = util.GetRDMADevices
+)
+
+// reportedDeviceTypes are the device types reported by koordlet, the devices of the other types in the Device CR,
+// e.g. the NPUs, are reported by the third-party agents and kept as is.
+var reportedDeviceTypes = map[schedulingv1alpha1.DeviceType]bool{
+	schedulingv1alpha1.GPU:  true,
+	schedulingv1alpha1.RDMA: true,
+	schedulingv1alpha1.MIG:  true,
+}
+
 func generateQueryParam() *metriccache.QueryParam {
 	end := time.Now()
 	start := end.Add(-time.Duration(60) * time.Second)
@@ -45,7 +61,12 @@ func generateQueryParam() *metriccache.QueryParam {
 
 func (s *statesInformer) reportDevice() {
 	copyNode := s.GetNode()
-	gpuDevices := s.buildGPUDevice()
+	devices := s.buildGPUDevice()
+	if len(devices) > 0 {
+		devices = s.buildMIGDevice(devices)
+	}
+	devices = append(devices, buildRDMADevice()...)
+	devices = append(devices, s.getCustomDevices(copyNode.Name)...)
 	blocker := true
 	device := &schedulingv1alpha1.Device{
 		ObjectMeta: metav1.ObjectMeta{
@@ -62,7 +83,7 @@ func (s *statesInformer) reportDevice() {
 			},
 		},
 		Spec: schedulingv1alpha1.DeviceSpec{
-			Devices: gpuDevices,
+			Devices: devices,
 		},
 	}
 	// TODO: compare diff before update
@@ -80,6 +101,24 @@ func (s *statesInformer) reportDevice() {
 	}
 }
 
+// getCustomDevices returns the devices reported by the third-party agents in the Device CR.
+func (s *statesInformer) getCustomDevices(name string) []schedulingv1alpha1.DeviceInfo {
+	device, err := s.deviceClient.Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			klog.Warningf("failed to get device %s, err: %v", name, err)
+		}
+		return nil
+	}
+	var deviceInfos []schedulingv1alpha1.DeviceInfo
+	for _, deviceInfo := range device.Spec.Devices {
+		if !reportedDeviceTypes[deviceInfo.Type] {
+			deviceInfos = append(deviceInfos, deviceInfo)
+		}
+	}
+	return deviceInfos
+}
+
 func (s *statesInformer) buildGPUDevice() []schedulingv1alpha1.DeviceInfo {
 	queryParam := generateQueryParam()
 	nodeResource := s.metricsCache.GetNodeResourceMetric(queryParam)
@@ -109,6 +148,73 @@ func (s *statesInformer) buildGPUDevice() []schedulingv1alpha1.DeviceInfo {
 				extension.GPUMemory:      gpu.MemoryTotal,
 				extension.GPUMemoryRatio: *resource.NewQuantity(100, resource.DecimalSI),
 			},
+			Topology: getGPUTopology(gpu.DeviceUUID),
+		})
+	}
+	return deviceInfos
+}
+
+// getGPUTopology returns the PCIe attachment of the GPU, or nil if it is unknown.
+func getGPUTopology(uuid string) *schedulingv1alpha1.DeviceTopology {
+	busID, err := getGPUBusIDFn(uuid)
+	if err != nil {
+		klog.V(4).Infof("failed to get bus id of gpu %s, err: %v", uuid, err)
+		return nil
+	}
+	info, err := getPCIDeviceInfoFn(busID)
+	if err != nil {
+		klog.V(4).Infof("failed to get pci device info of gpu %s, err: %v", uuid, err)
+		return nil
+	}
+	return &schedulingv1alpha1.DeviceTopology{
+		NodeID: info.NUMANode,
+		PCIEID: info.PCIERoot,
+		BusID:  info.BusID,
+	}
+}
+
+func getGPUBusID(uuid string) (string, error) {
+	device, ret := nvml.DeviceGetHandleByUUID(uuid)
+	if ret != nvml.SUCCESS {
+		return "", fmt.Errorf("unable to get device %s: %v", uuid, nvml.ErrorString(ret))
+	}
+	pciInfo, ret := device.GetPciInfo()
+	if ret != nvml.SUCCESS {
+		return "", fmt.Errorf("unable to get pci info of device %s: %v", uuid, nvml.ErrorString(ret))
+	}
+	busID := make([]byte, 0, len(pciInfo.BusId))
+	for _, c := range pciInfo.BusId {
+		if c == 0 {
+			break
+		}
+		busID = append(busID, byte(c))
+	}
+	return util.NormalizePCIBusID(string(busID)), nil
+}
+
+// buildRDMADevice reports the RDMA devices with their PCIe attachment, so the scheduler can allocate
+// the RDMA devices close to the GPUs.
+func buildRDMADevice() []schedulingv1alpha1.DeviceInfo {
+	rdmaDevices, err := getRDMADevicesFn()
+	if err != nil {
+		klog.Errorf("failed to get rdma devices, err: %v", err)
+		return nil
+	}
+	var deviceInfos []schedulingv1alpha1.DeviceInfo
+	for i, rdma := range rdmaDevices {
+		deviceInfos = append(deviceInfos, schedulingv1alpha1.DeviceInfo{
+			UUID:   rdma.Name,
+			Minor:  int32(i),
+			Type:   schedulingv1alpha1.RDMA,
+			Health: true,
+			Resources: map[corev1.ResourceName]resource.Quantity{
+				extension.KoordRDMA: *resource.NewQuantity(100, resource.DecimalSI),
+			},
+			Topology: &schedulingv1alpha1.DeviceTopology{
+				NodeID: rdma.NUMANode,
+				PCIEID: rdma.PCIERoot,
+				BusID:  rdma.BusID,
+			},
 		})
 	}
 	return deviceInfos
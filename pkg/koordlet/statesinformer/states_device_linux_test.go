@@ -18,6 +18,7 @@ package statesinformer
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -31,15 +32,56 @@ import (
 	schedulingfake "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	mock_metriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
+	"github.com/koordinator-sh/koordinator/pkg/util"
 )
 
 func Test_reportGPUDevice(t *testing.T) {
+	oldBusIDFn, oldPCIDeviceInfoFn, oldRDMADevicesFn := getGPUBusIDFn, getPCIDeviceInfoFn, getRDMADevicesFn
+	oldMIGManager := defaultMIGManager
+	defer func() {
+		getGPUBusIDFn, getPCIDeviceInfoFn, getRDMADevicesFn = oldBusIDFn, oldPCIDeviceInfoFn, oldRDMADevicesFn
+		defaultMIGManager = oldMIGManager
+	}()
+	defaultMIGManager = &fakeMIGManager{}
+	getGPUBusIDFn = func(uuid string) (string, error) {
+		if uuid == "1" {
+			return "0000:3e:00.0", nil
+		}
+		return "", fmt.Errorf("not found")
+	}
+	getPCIDeviceInfoFn = func(busID string) (*util.PCIDeviceInfo, error) {
+		return &util.PCIDeviceInfo{BusID: busID, NUMANode: 0, PCIERoot: "0000:3a:00.0"}, nil
+	}
+	getRDMADevicesFn = func() ([]util.RDMADeviceInfo, error) {
+		return []util.RDMADeviceInfo{
+			{Name: "mlx5_0", PCIDeviceInfo: util.PCIDeviceInfo{BusID: "0000:3d:00.0", NUMANode: 0, PCIERoot: "0000:3a:00.0"}},
+		}, nil
+	}
+
 	testNode := &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "test",
 		},
 	}
-	fakeClient := schedulingfake.NewSimpleClientset().SchedulingV1alpha1().Devices()
+	npu := schedulingv1alpha1.DeviceInfo{
+		UUID:   "npu-0",
+		Minor:  0,
+		Type:   schedulingv1alpha1.DeviceType("npu"),
+		Health: true,
+		Resources: map[corev1.ResourceName]resource.Quantity{
+			corev1.ResourceName("example.com/npu"): *resource.NewQuantity(1, resource.DecimalSI),
+		},
+	}
+	// the npu reported by the third-party agent is kept, and the stale gpu is replaced
+	fakeClient := schedulingfake.NewSimpleClientset(&schedulingv1alpha1.Device{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: schedulingv1alpha1.DeviceSpec{
+			Devices: []schedulingv1alpha1.DeviceInfo{
+				{UUID: "stale", Minor: 3, Type: schedulingv1alpha1.GPU, Health: true},
+				npu,
+			},
+		},
+	}).SchedulingV1alpha1().Devices()
 	ctl := gomock.NewController(t)
 	mockMetricCache := mock_metriccache.NewMockMetricCache(ctl)
 	fakeResult := metriccache.NodeResourceQueryResult{
@@ -80,6 +122,11 @@ func Test_reportGPUDevice(t *testing.T) {
 				extension.GPUMemory:      *resource.NewQuantity(8000, resource.BinarySI),
 				extension.GPUMemoryRatio: *resource.NewQuantity(100, resource.DecimalSI),
 			},
+			Topology: &schedulingv1alpha1.DeviceTopology{
+				NodeID: 0,
+				PCIEID: "0000:3a:00.0",
+				BusID:  "0000:3e:00.0",
+			},
 		},
 		{
 			UUID:   "2",
@@ -92,6 +139,21 @@ func Test_reportGPUDevice(t *testing.T) {
 				extension.GPUMemoryRatio: *resource.NewQuantity(100, resource.DecimalSI),
 			},
 		},
+		{
+			UUID:   "mlx5_0",
+			Minor:  0,
+			Type:   schedulingv1alpha1.RDMA,
+			Health: true,
+			Resources: map[corev1.ResourceName]resource.Quantity{
+				extension.KoordRDMA: *resource.NewQuantity(100, resource.DecimalSI),
+			},
+			Topology: &schedulingv1alpha1.DeviceTopology{
+				NodeID: 0,
+				PCIEID: "0000:3a:00.0",
+				BusID:  "0000:3d:00.0",
+			},
+		},
+		npu,
 	}
 	device, err := fakeClient.Get(context.TODO(), "test", metav1.GetOptions{})
 	assert.Equal(t, nil, err)
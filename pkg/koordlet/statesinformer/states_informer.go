@@ -61,6 +61,8 @@ type StatesInformer interface {
 
 	GetAllPods() []*PodMeta
 
+	GetCacheGroup(namespace, name string) *slov1alpha1.CacheGroup
+
 	GetNodeTopo() *topov1alpha1.NodeResourceTopology
 
 	RegisterCallbacks(objType RegisterType, name, description string, callbackFn UpdateCbFn)
@@ -82,6 +84,8 @@ type statesInformer struct {
 	nodeSLORWMutex  sync.RWMutex
 	nodeSLO         *slov1alpha1.NodeSLO
 
+	cacheGroupInformer cache.SharedIndexInformer
+
 	nodeTopoMutex  sync.RWMutex
 	nodeTopology   *topov1alpha1.NodeResourceTopology
 	topologyClient topologyclientset.Interface
@@ -102,6 +106,10 @@ type statesInformer struct {
 func NewStatesInformer(config *Config, kubeClient clientset.Interface, crdClient koordclientset.Interface, topologyClient *topologyclientset.Clientset, metricsCache metriccache.MetricCache, pleg pleg.Pleg, nodeName string, schedulingClient *v1alpha1.SchedulingV1alpha1Client) StatesInformer {
 	nodeInformer := newNodeInformer(kubeClient, nodeName)
 	nodeSLOInformer := newNodeSLOInformer(crdClient, nodeName)
+	var cacheGroupInformer cache.SharedIndexInformer
+	if features.DefaultKoordletFeatureGate.Enabled(features.CacheGroup) {
+		cacheGroupInformer = newCacheGroupInformer(crdClient)
+	}
 
 	return &statesInformer{
 		config:       config,
@@ -112,6 +120,8 @@ func NewStatesInformer(config *Config, kubeClient clientset.Interface, crdClient
 		nodeInformer:    nodeInformer,
 		nodeSLOInformer: nodeSLOInformer,
 
+		cacheGroupInformer: cacheGroupInformer,
+
 		podMap:     map[string]*PodMeta{},
 		podCreated: make(chan string, 1), // set 1 buffer
 
@@ -143,6 +153,10 @@ func (s *statesInformer) Run(stopCh <-chan struct{}) error {
 	// waiting for node synced.
 	waitInformersSynced := []cache.InformerSynced{
 		s.nodeInformer.HasSynced, s.nodeSLOInformer.HasSynced}
+	if s.cacheGroupInformer != nil {
+		go s.cacheGroupInformer.Run(stopCh)
+		waitInformersSynced = append(waitInformersSynced, s.cacheGroupInformer.HasSynced)
+	}
 	if !cache.WaitForCacheSync(stopCh, waitInformersSynced...) {
 		return fmt.Errorf("timed out waiting for states informer caches to sync")
 	}
@@ -0,0 +1,329 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statesinformer
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+// migMinorsPerGPU is the max number of MIG devices of a GPU, the minor of a MIG device in the Device CR is
+// generated by the minor of its parent GPU and its index.
+const migMinorsPerGPU = 8
+
+var defaultMIGManager migManager = &nvmlMIGManager{}
+
+type migDevice struct {
+	UUID        string
+	ParentMinor int32
+	Index       int
+	Profile     string
+}
+
+// migManager lists and reconfigures the MIG devices of the GPUs in MIG mode.
+type migManager interface {
+	// listMIGDevices returns the MIG devices of the GPUs in MIG mode, indexed by the minor of the parent GPU.
+	listMIGDevices() (map[int32][]migDevice, error)
+	// reconfigure destroys all the MIG devices of the GPU and creates the MIG devices of the profiles.
+	reconfigure(minor int32, profiles []string) error
+}
+
+// buildMIGDevice replaces the GPUs in MIG mode with their MIG devices. Before reporting, the MIG geometry of
+// the idle GPUs is reconfigured according to the MIG config of the node.
+func (s *statesInformer) buildMIGDevice(gpus []schedulingv1alpha1.DeviceInfo) []schedulingv1alpha1.DeviceInfo {
+	migDevices, err := defaultMIGManager.listMIGDevices()
+	if err != nil {
+		klog.Errorf("failed to list mig devices, err: %v", err)
+		return gpus
+	}
+	if len(migDevices) == 0 {
+		return gpus
+	}
+	if s.reconcileMIG(migDevices) {
+		if migDevices, err = defaultMIGManager.listMIGDevices(); err != nil {
+			klog.Errorf("failed to list mig devices, err: %v", err)
+			return gpus
+		}
+	}
+
+	var deviceInfos []schedulingv1alpha1.DeviceInfo
+	for _, gpu := range gpus {
+		if _, ok := migDevices[gpu.Minor]; !ok {
+			deviceInfos = append(deviceInfos, gpu)
+		}
+	}
+	for _, gpu := range gpus {
+		for _, mig := range migDevices[gpu.Minor] {
+			deviceInfos = append(deviceInfos, schedulingv1alpha1.DeviceInfo{
+				UUID:   mig.UUID,
+				Minor:  mig.ParentMinor*migMinorsPerGPU + int32(mig.Index),
+				Type:   schedulingv1alpha1.MIG,
+				Health: gpu.Health,
+				Resources: map[corev1.ResourceName]resource.Quantity{
+					extension.MIGResourceName(mig.Profile): *resource.NewQuantity(1, resource.DecimalSI),
+				},
+				Topology: gpu.Topology,
+				MIG: &schedulingv1alpha1.MIGDeviceInfo{
+					ParentMinor: mig.ParentMinor,
+					Profile:     mig.Profile,
+				},
+			})
+		}
+	}
+	return deviceInfos
+}
+
+// reconcileMIG reconfigures the GPUs whose MIG geometry differs from the MIG config of the node. The GPUs with
+// MIG devices allocated to the running pods are skipped. It returns true if any GPU is reconfigured.
+func (s *statesInformer) reconcileMIG(migDevices map[int32][]migDevice) bool {
+	node := s.GetNode()
+	if node == nil {
+		return false
+	}
+	migConfig, err := extension.GetNodeMIGConfig(node.Annotations)
+	if err != nil {
+		klog.Errorf("failed to get mig config of node %s, err: %v", node.Name, err)
+		return false
+	}
+	if migConfig == nil {
+		return false
+	}
+
+	allocated := s.getAllocatedMIGDevices()
+	reconfigured := false
+	for _, gpu := range migConfig.GPUs {
+		devices, ok := migDevices[gpu.Minor]
+		if !ok {
+			klog.V(4).Infof("gpu %d is not in mig mode, skip reconfiguring", gpu.Minor)
+			continue
+		}
+		var profiles []string
+		inUse := false
+		for _, device := range devices {
+			profiles = append(profiles, device.Profile)
+			if _, ok := allocated[device.UUID]; ok {
+				inUse = true
+			}
+		}
+		if reflect.DeepEqual(profiles, gpu.Profiles) {
+			continue
+		}
+		if inUse {
+			klog.V(4).Infof("mig devices of gpu %d are in use, skip reconfiguring", gpu.Minor)
+			continue
+		}
+		if err := defaultMIGManager.reconfigure(gpu.Minor, gpu.Profiles); err != nil {
+			klog.Errorf("failed to reconfigure mig devices of gpu %d to %v, err: %v", gpu.Minor, gpu.Profiles, err)
+			continue
+		}
+		klog.Infof("reconfigure mig devices of gpu %d from %v to %v", gpu.Minor, profiles, gpu.Profiles)
+		reconfigured = true
+	}
+	return reconfigured
+}
+
+func (s *statesInformer) getAllocatedMIGDevices() map[string]struct{} {
+	allocated := map[string]struct{}{}
+	for _, podMeta := range s.GetAllPods() {
+		if podMeta.Pod == nil || util.IsPodTerminated(podMeta.Pod) {
+			continue
+		}
+		allocations, err := extension.GetDeviceAllocations(podMeta.Pod.Annotations)
+		if err != nil {
+			klog.V(4).Infof("failed to get device allocations of pod %s/%s, err: %v",
+				podMeta.Pod.Namespace, podMeta.Pod.Name, err)
+			continue
+		}
+		for _, allocation := range allocations[schedulingv1alpha1.MIG] {
+			if allocation.UUID != "" {
+				allocated[allocation.UUID] = struct{}{}
+			}
+		}
+	}
+	return allocated
+}
+
+// migProfileName returns the profile name of the MIG device in the form of NVIDIA, e.g. 1g.5gb, or 1c.2g.10gb
+// if the compute instance only takes a part of the gpu instance.
+func migProfileName(giSliceCount, ciSliceCount uint32, memorySizeMB uint64) string {
+	memoryGB := uint64(math.Ceil(float64(memorySizeMB) / 1024))
+	if ciSliceCount == giSliceCount {
+		return fmt.Sprintf("%dg.%dgb", giSliceCount, memoryGB)
+	}
+	return fmt.Sprintf("%dc.%dg.%dgb", ciSliceCount, giSliceCount, memoryGB)
+}
+
+type nvmlMIGManager struct{}
+
+func (m *nvmlMIGManager) listMIGDevices() (map[int32][]migDevice, error) {
+	count, ret := nvml.DeviceGetCount()
+	if ret != nvml.SUCCESS {
+		return nil, fmt.Errorf("unable to get device count: %v", nvml.ErrorString(ret))
+	}
+	migDevices := map[int32][]migDevice{}
+	for i := 0; i < count; i++ {
+		device, ret := nvml.DeviceGetHandleByIndex(i)
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("unable to get device at index %d: %v", i, nvml.ErrorString(ret))
+		}
+		mode, _, ret := device.GetMigMode()
+		if ret == nvml.ERROR_NOT_SUPPORTED || (ret == nvml.SUCCESS && mode != nvml.DEVICE_MIG_ENABLE) {
+			continue
+		}
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("unable to get mig mode of device at index %d: %v", i, nvml.ErrorString(ret))
+		}
+		minor, ret := device.GetMinorNumber()
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("unable to get minor of device at index %d: %v", i, nvml.ErrorString(ret))
+		}
+		maxCount, ret := device.GetMaxMigDeviceCount()
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("unable to get max mig device count of gpu %d: %v", minor, nvml.ErrorString(ret))
+		}
+		devices := []migDevice{}
+		for j := 0; j < maxCount; j++ {
+			mig, ret := device.GetMigDeviceHandleByIndex(j)
+			if ret == nvml.ERROR_NOT_FOUND {
+				continue
+			}
+			if ret != nvml.SUCCESS {
+				return nil, fmt.Errorf("unable to get mig device %d of gpu %d: %v", j, minor, nvml.ErrorString(ret))
+			}
+			uuid, ret := mig.GetUUID()
+			if ret != nvml.SUCCESS {
+				return nil, fmt.Errorf("unable to get uuid of mig device %d of gpu %d: %v", j, minor, nvml.ErrorString(ret))
+			}
+			attributes, ret := mig.GetAttributes()
+			if ret != nvml.SUCCESS {
+				return nil, fmt.Errorf("unable to get attributes of mig device %s: %v", uuid, nvml.ErrorString(ret))
+			}
+			devices = append(devices, migDevice{
+				UUID:        uuid,
+				ParentMinor: int32(minor),
+				Index:       j,
+				Profile:     migProfileName(attributes.GpuInstanceSliceCount, attributes.ComputeInstanceSliceCount, attributes.MemorySizeMB),
+			})
+		}
+		migDevices[int32(minor)] = devices
+	}
+	return migDevices, nil
+}
+
+func (m *nvmlMIGManager) reconfigure(minor int32, profiles []string) error {
+	device, err := getDeviceByMinor(minor)
+	if err != nil {
+		return err
+	}
+
+	giProfiles := map[string]nvml.GpuInstanceProfileInfo{}
+	for i := 0; i < nvml.GPU_INSTANCE_PROFILE_COUNT; i++ {
+		info, ret := device.GetGpuInstanceProfileInfo(i)
+		if ret != nvml.SUCCESS {
+			continue
+		}
+		giProfiles[migProfileName(info.SliceCount, info.SliceCount, info.MemorySizeMB)] = info
+		if err := destroyGPUInstances(device, &info); err != nil {
+			return err
+		}
+	}
+
+	for _, profile := range profiles {
+		giInfo, ok := giProfiles[profile]
+		if !ok {
+			return fmt.Errorf("mig profile %s is not supported by gpu %d", profile, minor)
+		}
+		gi, ret := device.CreateGpuInstance(&giInfo)
+		if ret != nvml.SUCCESS {
+			return fmt.Errorf("unable to create gpu instance %s on gpu %d: %v", profile, minor, nvml.ErrorString(ret))
+		}
+		if err := createComputeInstance(gi, giInfo.SliceCount); err != nil {
+			return fmt.Errorf("unable to create compute instance %s on gpu %d: %v", profile, minor, err)
+		}
+	}
+	return nil
+}
+
+func getDeviceByMinor(minor int32) (nvml.Device, error) {
+	count, ret := nvml.DeviceGetCount()
+	if ret != nvml.SUCCESS {
+		return nvml.Device{}, fmt.Errorf("unable to get device count: %v", nvml.ErrorString(ret))
+	}
+	for i := 0; i < count; i++ {
+		device, ret := nvml.DeviceGetHandleByIndex(i)
+		if ret != nvml.SUCCESS {
+			continue
+		}
+		if m, ret := device.GetMinorNumber(); ret == nvml.SUCCESS && int32(m) == minor {
+			return device, nil
+		}
+	}
+	return nvml.Device{}, fmt.Errorf("gpu %d not found", minor)
+}
+
+func destroyGPUInstances(device nvml.Device, info *nvml.GpuInstanceProfileInfo) error {
+	gis, ret := device.GetGpuInstances(info)
+	if ret != nvml.SUCCESS {
+		return nil
+	}
+	for _, gi := range gis {
+		for i := 0; i < nvml.COMPUTE_INSTANCE_PROFILE_COUNT; i++ {
+			ciInfo, ret := gi.GetComputeInstanceProfileInfo(i, nvml.COMPUTE_INSTANCE_ENGINE_PROFILE_SHARED)
+			if ret != nvml.SUCCESS {
+				continue
+			}
+			cis, ret := gi.GetComputeInstances(&ciInfo)
+			if ret != nvml.SUCCESS {
+				continue
+			}
+			for _, ci := range cis {
+				if ret := ci.Destroy(); ret != nvml.SUCCESS {
+					return fmt.Errorf("unable to destroy compute instance: %v", nvml.ErrorString(ret))
+				}
+			}
+		}
+		if ret := gi.Destroy(); ret != nvml.SUCCESS {
+			return fmt.Errorf("unable to destroy gpu instance: %v", nvml.ErrorString(ret))
+		}
+	}
+	return nil
+}
+
+// createComputeInstance creates a compute instance taking the whole gpu instance.
+func createComputeInstance(gi nvml.GpuInstance, sliceCount uint32) error {
+	for i := 0; i < nvml.COMPUTE_INSTANCE_PROFILE_COUNT; i++ {
+		ciInfo, ret := gi.GetComputeInstanceProfileInfo(i, nvml.COMPUTE_INSTANCE_ENGINE_PROFILE_SHARED)
+		if ret != nvml.SUCCESS || ciInfo.SliceCount != sliceCount {
+			continue
+		}
+		if _, ret := gi.CreateComputeInstance(&ciInfo); ret != nvml.SUCCESS {
+			return fmt.Errorf("%v", nvml.ErrorString(ret))
+		}
+		return nil
+	}
+	return fmt.Errorf("no compute instance profile with %d slices", sliceCount)
+}
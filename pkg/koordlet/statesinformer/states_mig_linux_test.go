@@ -0,0 +1,173 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statesinformer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+type fakeMIGManager struct {
+	devices      map[int32][]migDevice
+	reconfigured map[int32][]string
+}
+
+func (m *fakeMIGManager) listMIGDevices() (map[int32][]migDevice, error) {
+	return m.devices, nil
+}
+
+func (m *fakeMIGManager) reconfigure(minor int32, profiles []string) error {
+	if m.reconfigured == nil {
+		m.reconfigured = map[int32][]string{}
+	}
+	m.reconfigured[minor] = profiles
+	var devices []migDevice
+	for i, profile := range profiles {
+		devices = append(devices, migDevice{
+			UUID:        "MIG-" + profile,
+			ParentMinor: minor,
+			Index:       i,
+			Profile:     profile,
+		})
+	}
+	m.devices[minor] = devices
+	return nil
+}
+
+func Test_migProfileName(t *testing.T) {
+	assert.Equal(t, "1g.5gb", migProfileName(1, 1, 4864))
+	assert.Equal(t, "3g.20gb", migProfileName(3, 3, 20096))
+	assert.Equal(t, "7g.40gb", migProfileName(7, 7, 40192))
+	assert.Equal(t, "1c.3g.20gb", migProfileName(3, 1, 20096))
+}
+
+func Test_buildMIGDevice(t *testing.T) {
+	oldMIGManager := defaultMIGManager
+	defer func() {
+		defaultMIGManager = oldMIGManager
+	}()
+
+	gpus := []schedulingv1alpha1.DeviceInfo{
+		{
+			UUID:   "GPU-0",
+			Minor:  0,
+			Type:   schedulingv1alpha1.GPU,
+			Health: true,
+		},
+		{
+			UUID:   "GPU-1",
+			Minor:  1,
+			Type:   schedulingv1alpha1.GPU,
+			Health: true,
+		},
+		{
+			UUID:   "GPU-2",
+			Minor:  2,
+			Type:   schedulingv1alpha1.GPU,
+			Health: true,
+		},
+	}
+	migManager := &fakeMIGManager{
+		devices: map[int32][]migDevice{
+			1: {
+				{UUID: "MIG-1-0", ParentMinor: 1, Index: 0, Profile: "7g.40gb"},
+			},
+			2: {
+				{UUID: "MIG-2-0", ParentMinor: 2, Index: 0, Profile: "7g.40gb"},
+			},
+		},
+	}
+	defaultMIGManager = migManager
+
+	allocations := extension.DeviceAllocations{
+		schedulingv1alpha1.MIG: {
+			{
+				Minor: 16,
+				UUID:  "MIG-2-0",
+				Resources: corev1.ResourceList{
+					extension.MIGResourceName("7g.40gb"): *resource.NewQuantity(1, resource.DecimalSI),
+				},
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: "default",
+		},
+	}
+	assert.NoError(t, extension.SetDeviceAllocations(pod, allocations))
+
+	s := &statesInformer{
+		node: &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test",
+				Annotations: map[string]string{
+					extension.AnnotationNodeMIGConfig: `{"gpus":[{"minor":1,"profiles":["3g.20gb","3g.20gb"]},{"minor":2,"profiles":["1g.5gb"]}]}`,
+				},
+			},
+		},
+		podMap: map[string]*PodMeta{
+			"default/test-pod": {Pod: pod},
+		},
+	}
+	got := s.buildMIGDevice(gpus)
+
+	// gpu 2 is in use, only gpu 1 is reconfigured
+	assert.Equal(t, map[int32][]string{1: {"3g.20gb", "3g.20gb"}}, migManager.reconfigured)
+	expected := []schedulingv1alpha1.DeviceInfo{
+		gpus[0],
+		{
+			UUID:   "MIG-3g.20gb",
+			Minor:  8,
+			Type:   schedulingv1alpha1.MIG,
+			Health: true,
+			Resources: map[corev1.ResourceName]resource.Quantity{
+				extension.MIGResourceName("3g.20gb"): *resource.NewQuantity(1, resource.DecimalSI),
+			},
+			MIG: &schedulingv1alpha1.MIGDeviceInfo{ParentMinor: 1, Profile: "3g.20gb"},
+		},
+		{
+			UUID:   "MIG-3g.20gb",
+			Minor:  9,
+			Type:   schedulingv1alpha1.MIG,
+			Health: true,
+			Resources: map[corev1.ResourceName]resource.Quantity{
+				extension.MIGResourceName("3g.20gb"): *resource.NewQuantity(1, resource.DecimalSI),
+			},
+			MIG: &schedulingv1alpha1.MIGDeviceInfo{ParentMinor: 1, Profile: "3g.20gb"},
+		},
+		{
+			UUID:   "MIG-2-0",
+			Minor:  16,
+			Type:   schedulingv1alpha1.MIG,
+			Health: true,
+			Resources: map[corev1.ResourceName]resource.Quantity{
+				extension.MIGResourceName("7g.40gb"): *resource.NewQuantity(1, resource.DecimalSI),
+			},
+			MIG: &schedulingv1alpha1.MIGDeviceInfo{ParentMinor: 2, Profile: "7g.40gb"},
+		},
+	}
+	assert.Equal(t, expected, got)
+}
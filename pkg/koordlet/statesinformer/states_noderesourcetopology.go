@@ -20,12 +20,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"sort"
+	"time"
 
 	"github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
@@ -43,6 +46,17 @@ import (
 
 var (
 	getKubeletCommandlineFn = system.GetKubeletCommandline
+	getNUMANodeMemInfosFn   = util.GetNUMANodeMemInfos
+	getNUMANodeHugePagesFn  = util.GetNUMANodeHugePages
+)
+
+const (
+	// dynamicCPUPoolMetricWindow is the window of the recent usage which the shared pool is sized by
+	dynamicCPUPoolMetricWindow = 3 * time.Minute
+	// dynamicCPUPoolLoadMargin is the ratio of the spare CPUs the shared pool keeps over the recent usage
+	dynamicCPUPoolLoadMargin = 0.2
+	// dynamicCPUPoolMaxParkedRatio limits the ratio of the shared pool which can be parked in the exclusive pool
+	dynamicCPUPoolMaxParkedRatio = 0.5
 )
 
 func (s *statesInformer) syncNodeResourceTopology(node *corev1.Node) {
@@ -165,6 +179,25 @@ func (s *statesInformer) reportNodeTopology() {
 		return
 	}
 
+	// the CPUs reserved for the offload stacks are excluded from the shared pools
+	reservedCPUs, err := util.GetNodeSLOReservedCPUs(s.GetNodeSLO())
+	if err != nil {
+		klog.Errorf("failed to parse reserved cpus of nodeSLO %s, err: %v", nodeName, err)
+	}
+	var cpuReservationJSON []byte
+	if len(reservedCPUs) > 0 {
+		cpus := make([]int, 0, len(reservedCPUs))
+		for _, cpuID := range reservedCPUs {
+			delete(sharedPoolCPUs, cpuID)
+			cpus = append(cpus, int(cpuID))
+		}
+		cpuReservationJSON, err = json.Marshal(extension.CPUReservation{ReservedCPUs: cpuset.NewCPUSet(cpus...).String()})
+		if err != nil {
+			klog.Errorf("failed to marshal cpu reservation of node %s, err: %v", nodeName, err)
+			return
+		}
+	}
+
 	kubeletPort := int(s.GetNode().Status.DaemonEndpoints.KubeletEndpoint.Port)
 	args, err := getKubeletCommandlineFn(kubeletPort)
 	if err != nil {
@@ -236,6 +269,27 @@ func (s *statesInformer) reportNodeTopology() {
 		return
 	}
 
+	var numaMemoryJSON []byte
+	numaMemory, err := calNUMAMemory()
+	if err != nil {
+		klog.Errorf("failed to cal numa memory of node %s, err: %v", nodeName, err)
+	} else if len(numaMemory.Detail) > 0 {
+		numaMemoryJSON, err = json.Marshal(numaMemory)
+		if err != nil {
+			klog.Errorf("failed to marshal numa memory of node %s, err: %v", nodeName, err)
+			return
+		}
+	}
+
+	var cpuPoolLayoutJSON []byte
+	if cpuPoolLayout := s.calCPUPoolLayout(sharedPoolCPUs); cpuPoolLayout != nil {
+		cpuPoolLayoutJSON, err = json.Marshal(cpuPoolLayout)
+		if err != nil {
+			klog.Errorf("failed to marshal cpu pool layout of node %s, err: %v", nodeName, err)
+			return
+		}
+	}
+
 	sharePools := s.calCPUSharePools(sharedPoolCPUs)
 	cpuSharePoolsJSON, err := json.Marshal(sharePools)
 	if err != nil {
@@ -260,6 +314,19 @@ func (s *statesInformer) reportNodeTopology() {
 		if len(podAllocsJSON) != 0 {
 			nodeResourceTopology.Annotations[extension.AnnotationNodeCPUAllocs] = string(podAllocsJSON)
 		}
+		if len(cpuReservationJSON) != 0 {
+			nodeResourceTopology.Annotations[extension.AnnotationNodeCPUReservation] = string(cpuReservationJSON)
+		} else {
+			delete(nodeResourceTopology.Annotations, extension.AnnotationNodeCPUReservation)
+		}
+		if len(numaMemoryJSON) != 0 {
+			nodeResourceTopology.Annotations[extension.AnnotationNodeNUMAMemory] = string(numaMemoryJSON)
+		}
+		if len(cpuPoolLayoutJSON) != 0 {
+			nodeResourceTopology.Annotations[extension.AnnotationNodeCPUPoolLayout] = string(cpuPoolLayoutJSON)
+		} else {
+			delete(nodeResourceTopology.Annotations, extension.AnnotationNodeCPUPoolLayout)
+		}
 		_, err = s.topologyClient.TopologyV1alpha1().NodeResourceTopologies().Update(context.TODO(), nodeResourceTopology, metav1.UpdateOptions{})
 		if err != nil {
 			klog.Errorf("failed to update cpu info of node %s, err: %v", nodeName, err)
@@ -324,6 +391,146 @@ func (s *statesInformer) calCPUSharePools(sharedPoolCPUs map[int32]*extension.CP
 	return sharePools
 }
 
+// calCPUPoolLayout parks the idle CPUs of the shared pool in the exclusive pool if the node dynamically resizes the
+// pools, and removes them from sharedPoolCPUs. The shared pool keeps the CPUs which the LS and BE Pods recently used
+// with a margin, so it shrinks as the load falls, and grows back as the load rises or the LSE/LSR Pods are deleted.
+// It returns nil if the node doesn't resize the pools dynamically or the recent usage is unknown.
+func (s *statesInformer) calCPUPoolLayout(sharedPoolCPUs map[int32]*extension.CPUInfo) *extension.CPUPoolLayout {
+	node := s.GetNode()
+	if node == nil || node.Labels[extension.LabelNodeCPUPoolPolicy] != extension.NodeCPUPoolPolicyDynamic {
+		return nil
+	}
+
+	end := time.Now()
+	start := end.Add(-dynamicCPUPoolMetricWindow)
+	queryParam := &metriccache.QueryParam{
+		Aggregate: metriccache.AggregationTypeAVG,
+		Start:     &start,
+		End:       &end,
+	}
+	nodeResult := s.metricsCache.GetNodeResourceMetric(queryParam)
+	if nodeResult.Error != nil || nodeResult.Metric == nil {
+		klog.Warningf("failed to get node resource metric of node %s for the cpu pool layout, err: %v", node.Name, nodeResult.Error)
+		return nil
+	}
+	// the usage of the LSE/LSR Pods is excluded since they don't run in the shared pool
+	sharedUsedMilli := nodeResult.Metric.CPUUsed.CPUUsed.MilliValue()
+	var exclusiveCPUs []int
+	for _, podMeta := range s.GetAllPods() {
+		status, err := extension.GetResourceStatus(podMeta.Pod.Annotations)
+		if err != nil || status.CPUSet == "" {
+			continue
+		}
+		set, err := cpuset.Parse(status.CPUSet)
+		if err != nil {
+			klog.Errorf("failed to parse cpuset info of pod %s, err: %v", podMeta.Pod.Name, err)
+			continue
+		}
+		for _, cpuID := range set.ToSliceNoSort() {
+			delete(sharedPoolCPUs, int32(cpuID))
+			exclusiveCPUs = append(exclusiveCPUs, cpuID)
+		}
+		podUID := string(podMeta.Pod.UID)
+		podResult := s.metricsCache.GetPodResourceMetric(&podUID, queryParam)
+		if podResult.Error == nil && podResult.Metric != nil {
+			sharedUsedMilli -= podResult.Metric.CPUUsed.CPUUsed.MilliValue()
+		}
+	}
+	if sharedUsedMilli < 0 {
+		sharedUsedMilli = 0
+	}
+
+	keptCPUs := int(math.Ceil(float64(sharedUsedMilli) * (1 + dynamicCPUPoolLoadMargin) / 1000))
+	numParked := len(sharedPoolCPUs) - keptCPUs
+	if maxParked := int(float64(len(sharedPoolCPUs)) * dynamicCPUPoolMaxParkedRatio); numParked > maxParked {
+		numParked = maxParked
+	}
+
+	// park the whole physical cores from the tail, so the exclusive pool can satisfy the FullPCPUs policy
+	type physicalCore struct {
+		socket, core int32
+	}
+	coreCPUs := map[physicalCore][]int{}
+	var cores []physicalCore
+	for cpuID, info := range sharedPoolCPUs {
+		if info == nil {
+			continue
+		}
+		core := physicalCore{socket: info.Socket, core: info.Core}
+		if _, ok := coreCPUs[core]; !ok {
+			cores = append(cores, core)
+		}
+		coreCPUs[core] = append(coreCPUs[core], int(cpuID))
+	}
+	sort.Slice(cores, func(i, j int) bool {
+		if cores[i].socket != cores[j].socket {
+			return cores[i].socket > cores[j].socket
+		}
+		return cores[i].core > cores[j].core
+	})
+	for _, core := range cores {
+		cpus := coreCPUs[core]
+		if len(cpus) > numParked {
+			continue
+		}
+		for _, cpuID := range cpus {
+			delete(sharedPoolCPUs, int32(cpuID))
+			exclusiveCPUs = append(exclusiveCPUs, cpuID)
+		}
+		numParked -= len(cpus)
+	}
+
+	sharedCPUs := make([]int, 0, len(sharedPoolCPUs))
+	for cpuID := range sharedPoolCPUs {
+		sharedCPUs = append(sharedCPUs, int(cpuID))
+	}
+	return &extension.CPUPoolLayout{
+		ExclusiveCPUs: cpuset.NewCPUSet(exclusiveCPUs...).String(),
+		SharedCPUs:    cpuset.NewCPUSet(sharedCPUs...).String(),
+	}
+}
+
+// calNUMAMemory returns the memory and hugepages capacity of the NUMA Nodes, which the scheduler accounts
+// the memory bound to.
+func calNUMAMemory() (*extension.NUMAMemory, error) {
+	memInfos, err := getNUMANodeMemInfosFn()
+	if err != nil {
+		return nil, err
+	}
+	hugePages, err := getNUMANodeHugePagesFn()
+	if err != nil {
+		return nil, err
+	}
+	numaMemory := &extension.NUMAMemory{}
+	for nodeID, memInfo := range memInfos {
+		nodeMemory := extension.NUMANodeMemory{Node: nodeID}
+		// the pre-allocated hugepages are counted in MemTotal, but they can't be used as the normal memory
+		memTotalKB := memInfo.MemTotal
+		for _, pages := range hugePages[nodeID] {
+			if pages.Total == 0 {
+				continue
+			}
+			if nodeMemory.HugePages == nil {
+				nodeMemory.HugePages = corev1.ResourceList{}
+			}
+			pageSize := resource.NewQuantity(int64(pages.PageSizeKB)*1024, resource.BinarySI)
+			resourceName := corev1.ResourceName(corev1.ResourceHugePagesPrefix + pageSize.String())
+			nodeMemory.HugePages[resourceName] = *resource.NewQuantity(int64(pages.PageSizeKB*pages.Total)*1024, resource.BinarySI)
+			if memTotalKB > pages.PageSizeKB*pages.Total {
+				memTotalKB -= pages.PageSizeKB * pages.Total
+			} else {
+				memTotalKB = 0
+			}
+		}
+		nodeMemory.Capacity = *resource.NewQuantity(int64(memTotalKB)*1024, resource.BinarySI)
+		numaMemory.Detail = append(numaMemory.Detail, nodeMemory)
+	}
+	sort.Slice(numaMemory.Detail, func(i, j int) bool {
+		return numaMemory.Detail[i].Node < numaMemory.Detail[j].Node
+	})
+	return numaMemory, nil
+}
+
 func (s *statesInformer) calCPUTopology() (*metriccache.NodeCPUInfo, *extension.CPUTopology, map[int32]*extension.CPUInfo, error) {
 	nodeCPUInfo, err := s.metricsCache.GetNodeCPUInfo(&metriccache.QueryParam{})
 	if err != nil {
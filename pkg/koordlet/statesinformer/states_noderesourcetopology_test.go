@@ -28,6 +28,7 @@ import (
 	topologyclientsetfake "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/clientset/versioned/fake"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/kubernetes/pkg/kubelet/cm/cpumanager"
@@ -232,9 +233,27 @@ func Test_calGuaranteedCpu(t *testing.T) {
 
 func Test_reportNodeTopology(t *testing.T) {
 	oldFn := getKubeletCommandlineFn
+	oldMemInfosFn := getNUMANodeMemInfosFn
+	oldHugePagesFn := getNUMANodeHugePagesFn
 	defer func() {
 		getKubeletCommandlineFn = oldFn
+		getNUMANodeMemInfosFn = oldMemInfosFn
+		getNUMANodeHugePagesFn = oldHugePagesFn
 	}()
+	getNUMANodeMemInfosFn = func() (map[int32]*util.MemInfo, error) {
+		return map[int32]*util.MemInfo{
+			1: {MemTotal: 16384000},
+			0: {MemTotal: 32768000},
+		}, nil
+	}
+	getNUMANodeHugePagesFn = func() (map[int32][]util.HugePagesInfo, error) {
+		return map[int32][]util.HugePagesInfo{
+			0: {
+				{PageSizeKB: 1048576, Total: 2, Free: 2},
+				{PageSizeKB: 2048, Total: 0, Free: 0},
+			},
+		}, nil
+	}
 
 	client := topologyclientsetfake.NewSimpleClientset()
 	testNode := &corev1.Node{
@@ -322,4 +341,86 @@ func Test_reportNodeTopology(t *testing.T) {
 
 	assert.Equal(t, `[{"socket":0,"node":0,"cpuset":"0-2"},{"socket":1,"node":1,"cpuset":"6-7"}]`, topology.Annotations[extension.AnnotationNodeCPUSharedPools])
 	assert.Equal(t, `{"detail":[{"id":0,"core":0,"socket":0,"node":0},{"id":1,"core":0,"socket":0,"node":0},{"id":2,"core":1,"socket":0,"node":0},{"id":3,"core":1,"socket":0,"node":0},{"id":4,"core":2,"socket":1,"node":1},{"id":5,"core":2,"socket":1,"node":1},{"id":6,"core":3,"socket":1,"node":1},{"id":7,"core":3,"socket":1,"node":1}]}`, topology.Annotations[extension.AnnotationNodeCPUTopology])
+	assert.Equal(t, `{"detail":[{"node":0,"capacity":"29952Mi","hugePages":{"hugepages-1Gi":"2Gi"}},{"node":1,"capacity":"16000Mi"}]}`, topology.Annotations[extension.AnnotationNodeNUMAMemory])
+}
+
+func Test_calCPUPoolLayout(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	mockMetricCache := mock_metriccache.NewMockMetricCache(ctl)
+	mockMetricCache.EXPECT().GetNodeResourceMetric(gomock.Any()).Return(metriccache.NodeResourceQueryResult{
+		Metric: &metriccache.NodeResourceMetric{
+			CPUUsed: metriccache.CPUMetric{CPUUsed: resource.MustParse("3")},
+		},
+	}).AnyTimes()
+	mockMetricCache.EXPECT().GetPodResourceMetric(gomock.Any(), gomock.Any()).Return(metriccache.PodResourceQueryResult{
+		Metric: &metriccache.PodResourceMetric{
+			CPUUsed: metriccache.CPUMetric{CPUUsed: resource.MustParse("1500m")},
+		},
+	}).AnyTimes()
+
+	newSharedPoolCPUs := func() map[int32]*extension.CPUInfo {
+		cpus := map[int32]*extension.CPUInfo{}
+		for i := int32(0); i < 8; i++ {
+			cpus[i] = &extension.CPUInfo{ID: i, Core: i / 2, Socket: i / 4, Node: i / 4}
+		}
+		return cpus
+	}
+	podMap := map[string]*PodMeta{
+		"pod1": {
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod1",
+					Namespace: "ns1",
+					UID:       "pod1",
+					Annotations: map[string]string{
+						extension.AnnotationResourceStatus: `{"cpuset": "4-5" }`,
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		labels         map[string]string
+		wantLayout     *extension.CPUPoolLayout
+		wantSharedCPUs []int32
+	}{
+		{
+			name:           "static pool",
+			wantSharedCPUs: []int32{0, 1, 2, 3, 4, 5, 6, 7},
+		},
+		{
+			name:   "dynamic pool",
+			labels: map[string]string{extension.LabelNodeCPUPoolPolicy: extension.NodeCPUPoolPolicyDynamic},
+			// the shared pool keeps 2 CPUs for the usage of 1.5 cores, and parks at most 3 of the 6 CPUs
+			wantLayout: &extension.CPUPoolLayout{
+				ExclusiveCPUs: "4-7",
+				SharedCPUs:    "0-3",
+			},
+			wantSharedCPUs: []int32{0, 1, 2, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &statesInformer{
+				metricsCache: mockMetricCache,
+				podMap:       podMap,
+				node: &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "test",
+						Labels: tt.labels,
+					},
+				},
+			}
+			sharedPoolCPUs := newSharedPoolCPUs()
+			assert.Equal(t, tt.wantLayout, s.calCPUPoolLayout(sharedPoolCPUs))
+			var gotSharedCPUs []int32
+			for cpuID := range sharedPoolCPUs {
+				gotSharedCPUs = append(gotSharedCPUs, cpuID)
+			}
+			assert.ElementsMatch(t, tt.wantSharedCPUs, gotSharedCPUs)
+		})
+	}
 }
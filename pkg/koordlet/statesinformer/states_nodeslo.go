@@ -112,6 +112,33 @@ func (s *statesInformer) mergeNodeSLOSpec(nodeSLO *slov1alpha1.NodeSLO) {
 	if mergedCPUBurstStrategySpec != nil {
 		s.nodeSLO.Spec.CPUBurstStrategy = mergedCPUBurstStrategySpec
 	}
+
+	// merge RuntimeClassStrategies with the merged strategies of the node
+	s.nodeSLO.Spec.RuntimeClassStrategies = mergeSLOSpecRuntimeClassStrategies(s.nodeSLO.Spec.ResourceQOSStrategy,
+		s.nodeSLO.Spec.CPUBurstStrategy, nodeSLO.Spec.RuntimeClassStrategies)
+}
+
+// mergeSLOSpecRuntimeClassStrategies merges the strategies of each RuntimeClass with the strategies of the node, the
+// strategies not overridden by the RuntimeClass are left nil to fall back to the node.
+func mergeSLOSpecRuntimeClassStrategies(nodeResourceQOSStrategy *slov1alpha1.ResourceQOSStrategy,
+	nodeCPUBurstStrategy *slov1alpha1.CPUBurstStrategy, newSpecs []slov1alpha1.RuntimeClassStrategy) []slov1alpha1.RuntimeClassStrategy {
+	if len(newSpecs) == 0 {
+		return nil
+	}
+	out := make([]slov1alpha1.RuntimeClassStrategy, 0, len(newSpecs))
+	for i := range newSpecs {
+		newSpec := &newSpecs[i]
+		merged := slov1alpha1.RuntimeClassStrategy{RuntimeClassName: newSpec.RuntimeClassName}
+		if newSpec.ResourceQOSStrategy != nil {
+			merged.ResourceQOSStrategy = mergeSLOSpecResourceQOSStrategy(nodeResourceQOSStrategy, newSpec.ResourceQOSStrategy)
+			mergeNoneResourceQOSIfDisabled(merged.ResourceQOSStrategy)
+		}
+		if newSpec.CPUBurstStrategy != nil {
+			merged.CPUBurstStrategy = mergeSLOSpecCPUBurstStrategy(nodeCPUBurstStrategy, newSpec.CPUBurstStrategy)
+		}
+		out = append(out, merged)
+	}
+	return out
 }
 
 // mergeSLOSpecResourceUsedThresholdWithBE merges the nodeSLO ResourceUsedThresholdWithBE with default configs
@@ -402,6 +402,65 @@ func Test_mergeSLOSpecResourceQOSStrategy(t *testing.T) {
 	}
 }
 
+func Test_mergeSLOSpecRuntimeClassStrategies(t *testing.T) {
+	nodeResourceQOS := util.DefaultResourceQOSStrategy()
+	nodeCPUBurst := util.DefaultCPUBurstStrategy()
+
+	newSpecs := []slov1alpha1.RuntimeClassStrategy{
+		{
+			RuntimeClassName: "kata",
+			ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+				BEClass: &slov1alpha1.ResourceQOS{
+					MemoryQOS: &slov1alpha1.MemoryQOSCfg{
+						MemoryQOS: slov1alpha1.MemoryQOS{
+							WmarkRatio: pointer.Int64Ptr(80),
+						},
+					},
+				},
+			},
+		},
+		{
+			RuntimeClassName: "gvisor",
+			CPUBurstStrategy: &slov1alpha1.CPUBurstStrategy{
+				CPUBurstConfig: slov1alpha1.CPUBurstConfig{
+					Policy: slov1alpha1.CPUBurstNone,
+				},
+			},
+		},
+	}
+
+	wantKataResourceQOS := nodeResourceQOS.DeepCopy()
+	wantKataResourceQOS.BEClass.MemoryQOS.WmarkRatio = pointer.Int64Ptr(80)
+	mergeNoneResourceQOSIfDisabled(wantKataResourceQOS)
+	wantGVisorCPUBurst := nodeCPUBurst.DeepCopy()
+	wantGVisorCPUBurst.Policy = slov1alpha1.CPUBurstNone
+
+	got := mergeSLOSpecRuntimeClassStrategies(nodeResourceQOS, nodeCPUBurst, newSpecs)
+	assert.Equal(t, []slov1alpha1.RuntimeClassStrategy{
+		{
+			RuntimeClassName:    "kata",
+			ResourceQOSStrategy: wantKataResourceQOS,
+		},
+		{
+			RuntimeClassName: "gvisor",
+			CPUBurstStrategy: wantGVisorCPUBurst,
+		},
+	}, got)
+	// the strategies of the node are not changed
+	assert.Equal(t, util.DefaultResourceQOSStrategy(), nodeResourceQOS)
+	assert.Nil(t, mergeSLOSpecRuntimeClassStrategies(nodeResourceQOS, nodeCPUBurst, nil))
+
+	spec := &slov1alpha1.NodeSLOSpec{
+		ResourceQOSStrategy:    nodeResourceQOS,
+		CPUBurstStrategy:       nodeCPUBurst,
+		RuntimeClassStrategies: got,
+	}
+	assert.Equal(t, wantKataResourceQOS, spec.GetResourceQOSStrategy("kata"))
+	assert.Equal(t, nodeCPUBurst, spec.GetCPUBurstStrategy("kata"))
+	assert.Equal(t, wantGVisorCPUBurst, spec.GetCPUBurstStrategy("gvisor"))
+	assert.Equal(t, nodeResourceQOS, spec.GetResourceQOSStrategy(""))
+}
+
 func Test_mergeNoneResourceQOSIfDisabled(t *testing.T) {
 	testDefault := util.DefaultResourceQOSStrategy()
 	testAllNone := util.NoneResourceQOSStrategy()
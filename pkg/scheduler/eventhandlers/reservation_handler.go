@@ -177,8 +177,9 @@ func AddScheduleEventHandler(sched *scheduler.Scheduler, internalHandler Schedul
 		FilterFunc: func(obj interface{}) bool {
 			switch t := obj.(type) {
 			case *schedulingv1alpha1.Reservation:
-				return isResponsibleForReservation(sched.Profiles, t) && !util.IsReservationAvailable(t) &&
-					!util.IsReservationFailed(t) && !util.IsReservationSucceeded(t)
+				// the bulk reservation is not scheduled, but its child reservations are
+				return isResponsibleForReservation(sched.Profiles, t) && !util.IsBulkReservation(t) &&
+					!util.IsReservationAvailable(t) && !util.IsReservationFailed(t) && !util.IsReservationSucceeded(t)
 			case cache.DeletedFinalStateUnknown:
 				if r, ok := t.Obj.(*schedulingv1alpha1.Reservation); ok {
 					// DeletedFinalStateUnknown object can be stale, so just try to cleanup without check.
@@ -0,0 +1,362 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frameworkext
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	listercorev1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+const (
+	// AssumeStateConfigMapKey is the key of the exported AssumeState in the ConfigMap
+	AssumeStateConfigMapKey = "assumeState"
+
+	defaultAssumeStateExportInterval = time.Second
+	defaultAssumeStateRestoreTTL     = 30 * time.Second
+)
+
+// AssumeState is the in-flight state of the scheduler exported by the leader, i.e. the pods assumed but not bound
+// yet, so that the incoming leader does not double-allocate the resources held by them during the handoff.
+type AssumeState struct {
+	ExportTime metav1.Time   `json:"exportTime"`
+	Pods       []*AssumedPod `json:"pods,omitempty"`
+}
+
+type AssumedPod struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	UID       types.UID `json:"uid"`
+	NodeName  string    `json:"nodeName"`
+	// Holds are the resources held by the plugins for the pod, e.g. the allocated devices, keyed by the plugin name.
+	Holds map[string]json.RawMessage `json:"holds,omitempty"`
+}
+
+// AssumeStateHolder is implemented by the plugins which hold resources for the assumed pods out of the scheduler
+// cache, e.g. the devices or the quota, so that the holds can be handed off to the incoming leader.
+type AssumeStateHolder interface {
+	// ExportAssumeState returns the hold of the assumed pod, nil if nothing is held.
+	ExportAssumeState(pod *corev1.Pod, nodeName string) (json.RawMessage, error)
+	// RestoreAssumeState holds the resources for the pod assumed by the previous leader.
+	RestoreAssumeState(pod *corev1.Pod, nodeName string, hold json.RawMessage) error
+	// ForgetAssumeState releases the restored hold if the pod is not bound in time.
+	ForgetAssumeState(pod *corev1.Pod, nodeName string, hold json.RawMessage)
+}
+
+// LeaderResyncer is implemented by the plugins caching the state accumulated from the events, e.g. the quota
+// accounting, which may be stale or double-counted after a failover. Resync rebuilds the state from the informer
+// caches when the scheduler starts leading, after the assumed pods of the previous leader are restored.
+type LeaderResyncer interface {
+	Resync(ctx context.Context) error
+}
+
+// assumedPodCache is the part of the scheduler cache used to restore the assumed pods.
+type assumedPodCache interface {
+	AssumePod(pod *corev1.Pod) error
+	FinishBinding(pod *corev1.Pod) error
+	ForgetPod(pod *corev1.Pod) error
+}
+
+type restoredPod struct {
+	assumedPod *AssumedPod
+	pod        *corev1.Pod
+	deadline   time.Time
+}
+
+// AssumeStateManager exports the AssumeState to a ConfigMap periodically while leading, and restores the
+// AssumeState exported by the previous leader when it starts leading. The restored pods are assumed in the
+// scheduler cache, so they are skipped by the scheduling queue and their resources are held until they are
+// bound or the restore TTL expires.
+type AssumeStateManager struct {
+	client    clientset.Interface
+	namespace string
+	name      string
+	ttl       time.Duration
+
+	lock            sync.Mutex
+	holders         map[string]AssumeStateHolder
+	resyncers       map[string]LeaderResyncer
+	cache           assumedPodCache
+	listAssumedPods func() []*corev1.Pod
+	lastExported    []byte
+	restored        map[types.UID]*restoredPod
+}
+
+func NewAssumeStateManager(client clientset.Interface, namespace, name string) *AssumeStateManager {
+	return &AssumeStateManager{
+		client:    client,
+		namespace: namespace,
+		name:      name,
+		ttl:       defaultAssumeStateRestoreTTL,
+		holders:   map[string]AssumeStateHolder{},
+		resyncers: map[string]LeaderResyncer{},
+		restored:  map[types.UID]*restoredPod{},
+	}
+}
+
+// RegisterPlugin registers the plugin as an AssumeStateHolder and a LeaderResyncer if it implements the interfaces.
+func (m *AssumeStateManager) RegisterPlugin(plugin framework.Plugin) {
+	if holder, ok := plugin.(AssumeStateHolder); ok {
+		m.RegisterHolder(plugin.Name(), holder)
+	}
+	if resyncer, ok := plugin.(LeaderResyncer); ok {
+		m.RegisterResyncer(plugin.Name(), resyncer)
+	}
+}
+
+func (m *AssumeStateManager) RegisterHolder(name string, holder AssumeStateHolder) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.holders[name] = holder
+}
+
+func (m *AssumeStateManager) RegisterResyncer(name string, resyncer LeaderResyncer) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.resyncers[name] = resyncer
+}
+
+// SetScheduler sets the scheduler whose cache is exported and restored.
+func (m *AssumeStateManager) SetScheduler(sched *scheduler.Scheduler) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.cache = sched.SchedulerCache
+	m.listAssumedPods = func() []*corev1.Pod {
+		dump := sched.SchedulerCache.Dump()
+		var pods []*corev1.Pod
+		for _, nodeInfo := range dump.Nodes {
+			for _, podInfo := range nodeInfo.Pods {
+				if dump.AssumedPods.Has(string(podInfo.Pod.UID)) {
+					pods = append(pods, podInfo.Pod)
+				}
+			}
+		}
+		return pods
+	}
+}
+
+// Run exports the AssumeState periodically and expires the restored pods until ctx is done, the AssumeState is
+// exported once more before returning.
+func (m *AssumeStateManager) Run(ctx context.Context, podLister listercorev1.PodLister) {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := m.Export(ctx); err != nil {
+			klog.Errorf("failed to export assume state, err: %v", err)
+		}
+		m.ExpireRestored(podLister)
+	}, defaultAssumeStateExportInterval)
+	// the ctx is done, use a new context to export the final state
+	exportCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := m.Export(exportCtx); err != nil {
+		klog.Errorf("failed to export assume state before exiting, err: %v", err)
+	}
+}
+
+// Export writes the current AssumeState to the ConfigMap, it does nothing if the state is not changed.
+func (m *AssumeStateManager) Export(ctx context.Context) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.listAssumedPods == nil {
+		return nil
+	}
+
+	state := &AssumeState{}
+	for _, pod := range m.listAssumedPods() {
+		assumedPod := &AssumedPod{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			UID:       pod.UID,
+			NodeName:  pod.Spec.NodeName,
+		}
+		for name, holder := range m.holders {
+			hold, err := holder.ExportAssumeState(pod, pod.Spec.NodeName)
+			if err != nil {
+				klog.Errorf("failed to export assume state of pod %s/%s by %s, err: %v", pod.Namespace, pod.Name, name, err)
+				continue
+			}
+			if hold != nil {
+				if assumedPod.Holds == nil {
+					assumedPod.Holds = map[string]json.RawMessage{}
+				}
+				assumedPod.Holds[name] = hold
+			}
+		}
+		state.Pods = append(state.Pods, assumedPod)
+	}
+	sort.Slice(state.Pods, func(i, j int) bool {
+		return state.Pods[i].UID < state.Pods[j].UID
+	})
+	podsData, err := json.Marshal(state.Pods)
+	if err != nil {
+		return err
+	}
+	if m.lastExported != nil && bytes.Equal(podsData, m.lastExported) {
+		return nil
+	}
+	state.ExportTime = metav1.Now()
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err := m.writeConfigMap(ctx, string(data)); err != nil {
+		return err
+	}
+	m.lastExported = podsData
+	klog.V(4).Infof("exported assume state of %d pods", len(state.Pods))
+	return nil
+}
+
+func (m *AssumeStateManager) writeConfigMap(ctx context.Context, data string) error {
+	configMaps := m.client.CoreV1().ConfigMaps(m.namespace)
+	configMap, err := configMaps.Get(ctx, m.name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: m.namespace, Name: m.name},
+			Data:       map[string]string{AssumeStateConfigMapKey: data},
+		}
+		_, err = configMaps.Create(ctx, configMap, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	configMap = configMap.DeepCopy()
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[AssumeStateConfigMapKey] = data
+	_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+	return err
+}
+
+// Restore reads the AssumeState exported by the previous leader and holds the resources for the pods which are
+// still not bound, it should be called after the informers are synced and before the scheduler runs.
+func (m *AssumeStateManager) Restore(ctx context.Context, podLister listercorev1.PodLister) error {
+	configMap, err := m.client.CoreV1().ConfigMaps(m.namespace).Get(ctx, m.name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	data, ok := configMap.Data[AssumeStateConfigMapKey]
+	if !ok || data == "" {
+		return nil
+	}
+	state := &AssumeState{}
+	if err := json.Unmarshal([]byte(data), state); err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.cache == nil {
+		return nil
+	}
+	deadline := time.Now().Add(m.ttl)
+	for _, assumedPod := range state.Pods {
+		pod, err := podLister.Pods(assumedPod.Namespace).Get(assumedPod.Name)
+		if err != nil || pod.UID != assumedPod.UID || pod.Spec.NodeName != "" || pod.DeletionTimestamp != nil {
+			// the pod is gone or already bound, it is accounted by the informers
+			continue
+		}
+		podCopy := pod.DeepCopy()
+		podCopy.Spec.NodeName = assumedPod.NodeName
+		if err := m.cache.AssumePod(podCopy); err != nil {
+			klog.Errorf("failed to restore assumed pod %s/%s, err: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		// let the scheduler cache expire the assumed pod if we fail to forget it
+		_ = m.cache.FinishBinding(podCopy)
+		for name, hold := range assumedPod.Holds {
+			holder, ok := m.holders[name]
+			if !ok {
+				continue
+			}
+			if err := holder.RestoreAssumeState(podCopy, assumedPod.NodeName, hold); err != nil {
+				klog.Errorf("failed to restore assume state of pod %s/%s by %s, err: %v", pod.Namespace, pod.Name, name, err)
+			}
+		}
+		m.restored[pod.UID] = &restoredPod{
+			assumedPod: assumedPod,
+			pod:        podCopy,
+			deadline:   deadline,
+		}
+		klog.V(4).Infof("restored assumed pod %s/%s on node %s", pod.Namespace, pod.Name, assumedPod.NodeName)
+	}
+	klog.Infof("restored %d of %d assumed pods exported at %v", len(m.restored), len(state.Pods), state.ExportTime)
+	return nil
+}
+
+// Resync calls all the LeaderResyncers, it should be called after Restore and before the scheduler runs.
+func (m *AssumeStateManager) Resync(ctx context.Context) {
+	m.lock.Lock()
+	resyncers := make(map[string]LeaderResyncer, len(m.resyncers))
+	for name, resyncer := range m.resyncers {
+		resyncers[name] = resyncer
+	}
+	m.lock.Unlock()
+
+	for name, resyncer := range resyncers {
+		if err := resyncer.Resync(ctx); err != nil {
+			klog.Errorf("failed to resync %s when starting leading, err: %v", name, err)
+		}
+	}
+}
+
+// ExpireRestored releases the restored pods which are not bound before the deadline.
+func (m *AssumeStateManager) ExpireRestored(podLister listercorev1.PodLister) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := time.Now()
+	for uid, restored := range m.restored {
+		pod, err := podLister.Pods(restored.pod.Namespace).Get(restored.pod.Name)
+		if err == nil && pod.UID == uid && pod.Spec.NodeName != "" {
+			// the pod is bound, the holds become the allocation of the bound pod
+			delete(m.restored, uid)
+			continue
+		}
+		if now.Before(restored.deadline) {
+			continue
+		}
+		klog.Infof("restored assumed pod %s/%s is not bound in time, forget it", restored.pod.Namespace, restored.pod.Name)
+		if err := m.cache.ForgetPod(restored.pod); err != nil {
+			klog.V(4).Infof("failed to forget restored pod %s/%s, err: %v", restored.pod.Namespace, restored.pod.Name, err)
+		}
+		for name, hold := range restored.assumedPod.Holds {
+			if holder, ok := m.holders[name]; ok {
+				holder.ForgetAssumeState(restored.pod, restored.assumedPod.NodeName, hold)
+			}
+		}
+		delete(m.restored, uid)
+	}
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frameworkext
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	listercorev1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type fakeAssumedPodCache struct {
+	assumed map[types.UID]*corev1.Pod
+}
+
+func (c *fakeAssumedPodCache) AssumePod(pod *corev1.Pod) error {
+	c.assumed[pod.UID] = pod
+	return nil
+}
+
+func (c *fakeAssumedPodCache) FinishBinding(pod *corev1.Pod) error {
+	return nil
+}
+
+func (c *fakeAssumedPodCache) ForgetPod(pod *corev1.Pod) error {
+	delete(c.assumed, pod.UID)
+	return nil
+}
+
+func (c *fakeAssumedPodCache) list() []*corev1.Pod {
+	var pods []*corev1.Pod
+	for _, pod := range c.assumed {
+		pods = append(pods, pod)
+	}
+	return pods
+}
+
+type fakeAssumeStateHolder struct {
+	holds map[types.UID]string
+}
+
+func (h *fakeAssumeStateHolder) ExportAssumeState(pod *corev1.Pod, nodeName string) (json.RawMessage, error) {
+	hold, ok := h.holds[pod.UID]
+	if !ok {
+		return nil, nil
+	}
+	return json.Marshal(hold)
+}
+
+func (h *fakeAssumeStateHolder) RestoreAssumeState(pod *corev1.Pod, nodeName string, hold json.RawMessage) error {
+	var value string
+	if err := json.Unmarshal(hold, &value); err != nil {
+		return err
+	}
+	h.holds[pod.UID] = value
+	return nil
+}
+
+func (h *fakeAssumeStateHolder) ForgetAssumeState(pod *corev1.Pod, nodeName string, hold json.RawMessage) {
+	delete(h.holds, pod.UID)
+}
+
+func newTestAssumeStateManager(client *kubefake.Clientset) (*AssumeStateManager, *fakeAssumedPodCache, *fakeAssumeStateHolder) {
+	m := NewAssumeStateManager(client, "koordinator-system", "koord-scheduler-assume-state")
+	podCache := &fakeAssumedPodCache{assumed: map[types.UID]*corev1.Pod{}}
+	m.cache = podCache
+	m.listAssumedPods = podCache.list
+	holder := &fakeAssumeStateHolder{holds: map[types.UID]string{}}
+	m.RegisterHolder("FakeDevice", holder)
+	return m, podCache, holder
+}
+
+func newTestPodLister(pods ...*corev1.Pod) listercorev1.PodLister {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, pod := range pods {
+		_ = indexer.Add(pod)
+	}
+	return listercorev1.NewPodLister(indexer)
+}
+
+// TestAssumeStateManager_Handoff simulates the rolling restart of the scheduler: the outgoing leader exports the
+// assumed pods and the incoming leader restores them before scheduling.
+func TestAssumeStateManager_Handoff(t *testing.T) {
+	client := kubefake.NewSimpleClientset()
+	pendingPods := []*corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-1", UID: "uid-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-2", UID: "uid-2"}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-3", UID: "uid-3"}},
+	}
+
+	// the outgoing leader has assumed all the pods and is binding them
+	oldLeader, oldCache, oldHolder := newTestAssumeStateManager(client)
+	for i, pod := range pendingPods {
+		assumedPod := pod.DeepCopy()
+		assumedPod.Spec.NodeName = "node-1"
+		assert.NoError(t, oldCache.AssumePod(assumedPod))
+		if i == 0 {
+			oldHolder.holds[pod.UID] = "gpu-0"
+		}
+	}
+	assert.NoError(t, oldLeader.Export(context.TODO()))
+	configMap, err := client.CoreV1().ConfigMaps("koordinator-system").Get(context.TODO(), "koord-scheduler-assume-state", metav1.GetOptions{})
+	assert.NoError(t, err)
+	state := &AssumeState{}
+	assert.NoError(t, json.Unmarshal([]byte(configMap.Data[AssumeStateConfigMapKey]), state))
+	assert.Len(t, state.Pods, 3)
+
+	// pod-3 is bound during the handoff, it's accounted by the informers of the incoming leader
+	boundPod := pendingPods[2].DeepCopy()
+	boundPod.Spec.NodeName = "node-1"
+	podLister := newTestPodLister(pendingPods[0], pendingPods[1], boundPod)
+
+	newLeader, newCache, newHolder := newTestAssumeStateManager(client)
+	assert.NoError(t, newLeader.Restore(context.TODO(), podLister))
+	assert.Len(t, newCache.assumed, 2)
+	assert.Equal(t, "node-1", newCache.assumed["uid-1"].Spec.NodeName)
+	assert.Equal(t, map[types.UID]string{"uid-1": "gpu-0"}, newHolder.holds)
+
+	// pod-1 is bound, the holds are kept as its allocation
+	boundPod1 := pendingPods[0].DeepCopy()
+	boundPod1.Spec.NodeName = "node-1"
+	podLister = newTestPodLister(boundPod1, pendingPods[1], boundPod)
+	newLeader.ExpireRestored(podLister)
+	assert.Len(t, newLeader.restored, 1)
+	assert.Equal(t, map[types.UID]string{"uid-1": "gpu-0"}, newHolder.holds)
+
+	// pod-2 is not bound in time, it's forgotten and will be scheduled again
+	newLeader.restored["uid-2"].deadline = time.Now().Add(-time.Second)
+	newLeader.ExpireRestored(podLister)
+	assert.Len(t, newLeader.restored, 0)
+	assert.NotContains(t, newCache.assumed, types.UID("uid-2"))
+}
+
+func TestAssumeStateManager_ExportUnchanged(t *testing.T) {
+	client := kubefake.NewSimpleClientset()
+	m, podCache, _ := newTestAssumeStateManager(client)
+	assert.NoError(t, m.Export(context.TODO()))
+	assert.NoError(t, podCache.AssumePod(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-1", UID: "uid-1"}}))
+	assert.NoError(t, m.Export(context.TODO()))
+	actions := len(client.Actions())
+
+	// nothing is written if the assumed pods are not changed
+	assert.NoError(t, m.Export(context.TODO()))
+	assert.Equal(t, actions, len(client.Actions()))
+}
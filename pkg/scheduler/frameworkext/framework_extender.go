@@ -38,13 +38,19 @@ type ExtendedHandle interface {
 	KoordinatorClientSet() koordinatorclientset.Interface
 	KoordinatorSharedInformerFactory() koordinatorinformers.SharedInformerFactory
 	SnapshotSharedLister() framework.SharedLister
+	// StopCh is closed when the scheduler exits, the plugins stop their background goroutines with it.
+	StopCh() <-chan struct{}
+	// GangAdmitters returns the GangAdmitters of the plugins, the Coscheduling admits the gangGroups by them.
+	GangAdmitters() *GangAdmitters
 }
 
 type extendedHandleOptions struct {
 	servicesEngine                   *services.Engine
+	assumeStateManager               *AssumeStateManager
 	koordinatorClientSet             koordinatorclientset.Interface
 	koordinatorSharedInformerFactory koordinatorinformers.SharedInformerFactory
 	sharedListerAdapter              SharedListerAdapter
+	stopCh                           <-chan struct{}
 }
 
 type SharedListerAdapter func(lister framework.SharedLister) framework.SharedLister
@@ -57,6 +63,12 @@ func WithServicesEngine(engine *services.Engine) Option {
 	}
 }
 
+func WithAssumeStateManager(manager *AssumeStateManager) Option {
+	return func(options *extendedHandleOptions) {
+		options.assumeStateManager = manager
+	}
+}
+
 func WithKoordinatorClientSet(koordinatorClientSet koordinatorclientset.Interface) Option {
 	return func(options *extendedHandleOptions) {
 		options.koordinatorClientSet = koordinatorClientSet
@@ -75,13 +87,22 @@ func WithSharedListerFactory(adapter SharedListerAdapter) Option {
 	}
 }
 
+func WithStopCh(stopCh <-chan struct{}) Option {
+	return func(options *extendedHandleOptions) {
+		options.stopCh = stopCh
+	}
+}
+
 type frameworkExtendedHandleImpl struct {
 	once sync.Once
 	framework.Handle
 	servicesEngine                   *services.Engine
+	assumeStateManager               *AssumeStateManager
 	koordinatorClientSet             koordinatorclientset.Interface
 	koordinatorSharedInformerFactory koordinatorinformers.SharedInformerFactory
 	sharedListerAdapter              SharedListerAdapter
+	stopCh                           <-chan struct{}
+	gangAdmitters                    *GangAdmitters
 }
 
 func NewExtendedHandle(options ...Option) ExtendedHandle {
@@ -92,9 +113,12 @@ func NewExtendedHandle(options ...Option) ExtendedHandle {
 
 	return &frameworkExtendedHandleImpl{
 		servicesEngine:                   handleOptions.servicesEngine,
+		assumeStateManager:               handleOptions.assumeStateManager,
 		koordinatorClientSet:             handleOptions.koordinatorClientSet,
 		koordinatorSharedInformerFactory: handleOptions.koordinatorSharedInformerFactory,
 		sharedListerAdapter:              handleOptions.sharedListerAdapter,
+		stopCh:                           handleOptions.stopCh,
+		gangAdmitters:                    NewGangAdmitters(),
 	}
 }
 
@@ -106,6 +130,14 @@ func (ext *frameworkExtendedHandleImpl) KoordinatorSharedInformerFactory() koord
 	return ext.koordinatorSharedInformerFactory
 }
 
+func (ext *frameworkExtendedHandleImpl) StopCh() <-chan struct{} {
+	return ext.stopCh
+}
+
+func (ext *frameworkExtendedHandleImpl) GangAdmitters() *GangAdmitters {
+	return ext.gangAdmitters
+}
+
 func (ext *frameworkExtendedHandleImpl) SnapshotSharedLister() framework.SharedLister {
 	if ext.sharedListerAdapter != nil {
 		return ext.sharedListerAdapter(ext.Handle.SnapshotSharedLister())
@@ -222,6 +254,10 @@ func PluginFactoryProxy(extendHandle ExtendedHandle, factoryFn frameworkruntime.
 		if impl.servicesEngine != nil {
 			impl.servicesEngine.RegisterPluginService(plugin)
 		}
+		if impl.assumeStateManager != nil {
+			impl.assumeStateManager.RegisterPlugin(plugin)
+		}
+		impl.gangAdmitters.RegisterPlugin(plugin)
 		return plugin, nil
 	}
 }
@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frameworkext
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// GangAdmitter is implemented by the plugins admitting a gangGroup as a whole before any child is assumed. For
+// example, the ElasticQuota reserves the quota of all the children in all the quota groups involved, so that the
+// quota groups never admit part of the gangGroup each and deadlock.
+type GangAdmitter interface {
+	Name() string
+	// AdmitGangGroup admits the children of the gangs until ttl elapses, the children are keyed by the gang id.
+	// If the gangGroup can't be admitted as a whole, nothing is admitted and an error is returned.
+	AdmitGangGroup(children map[string][]*corev1.Pod, ttl time.Duration) error
+	// ReleaseGangGroup releases what is admitted for the gangs and not taken over by the children yet.
+	ReleaseGangGroup(gangIds []string)
+}
+
+// GangAdmitters holds the GangAdmitters of the plugins built by the PluginFactoryProxy. The plugins are built in
+// any order, so the Coscheduling lists the GangAdmitters on each admission instead of when it's built.
+type GangAdmitters struct {
+	lock      sync.RWMutex
+	admitters map[string]GangAdmitter
+}
+
+func NewGangAdmitters() *GangAdmitters {
+	return &GangAdmitters{
+		admitters: map[string]GangAdmitter{},
+	}
+}
+
+// RegisterPlugin registers the plugin as a GangAdmitter if it implements the interface.
+func (a *GangAdmitters) RegisterPlugin(plugin framework.Plugin) {
+	if admitter, ok := plugin.(GangAdmitter); ok {
+		a.Register(admitter)
+	}
+}
+
+// Register registers the GangAdmitter, it overrides the registered GangAdmitter with the same name.
+func (a *GangAdmitters) Register(admitter GangAdmitter) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.admitters[admitter.Name()] = admitter
+}
+
+// List returns the GangAdmitters sorted by name, a nil GangAdmitters has none.
+func (a *GangAdmitters) List() []GangAdmitter {
+	if a == nil {
+		return nil
+	}
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	admitters := make([]GangAdmitter, 0, len(a.admitters))
+	for _, admitter := range a.admitters {
+		admitters = append(admitters, admitter)
+	}
+	sort.Slice(admitters, func(i, j int) bool {
+		return admitters[i].Name() < admitters[j].Name()
+	})
+	return admitters
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package frameworkext
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type fakeGangAdmitter struct {
+	name string
+}
+
+func (a *fakeGangAdmitter) Name() string {
+	return a.name
+}
+
+func (a *fakeGangAdmitter) AdmitGangGroup(children map[string][]*corev1.Pod, ttl time.Duration) error {
+	return nil
+}
+
+func (a *fakeGangAdmitter) ReleaseGangGroup(gangIds []string) {}
+
+func TestGangAdmitters(t *testing.T) {
+	var nilAdmitters *GangAdmitters
+	assert.Empty(t, nilAdmitters.List())
+
+	admitters := NewGangAdmitters()
+	admitters.RegisterPlugin(&fakeGangAdmitter{name: "b"})
+	admitters.RegisterPlugin(&fakeGangAdmitter{name: "a"})
+	admitters.Register(&fakeGangAdmitter{name: "b"})
+	list := admitters.List()
+	assert.Len(t, list, 2)
+	assert.Equal(t, "a", list[0].Name())
+	assert.Equal(t, "b", list[1].Name())
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/pflag"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultMaxInflightPerClient = 2
+	defaultQPSPerClient         = 5
+	defaultBurstPerClient       = 10
+
+	// flowControlIdleClientTimeout is the time after which the state of an idle client is dropped
+	flowControlIdleClientTimeout = 5 * time.Minute
+)
+
+// FlowControlOptions limits the requests of each client to the services, so that the heavy polling of the
+// dashboards can't degrade the scheduling latency.
+type FlowControlOptions struct {
+	// MaxInflightPerClient is the max number of the requests served concurrently for one client, 0 means no limit.
+	MaxInflightPerClient int
+	// QPSPerClient is the max QPS of one client, 0 means no limit.
+	QPSPerClient float64
+	// BurstPerClient is the burst of the QPS of one client.
+	BurstPerClient int
+}
+
+func NewDefaultFlowControlOptions() *FlowControlOptions {
+	return &FlowControlOptions{
+		MaxInflightPerClient: defaultMaxInflightPerClient,
+		QPSPerClient:         defaultQPSPerClient,
+		BurstPerClient:       defaultBurstPerClient,
+	}
+}
+
+func (o *FlowControlOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&o.MaxInflightPerClient, "services-max-inflight-per-client", o.MaxInflightPerClient, "The max number of the services requests served concurrently for one client, 0 means no limit.")
+	fs.Float64Var(&o.QPSPerClient, "services-qps-per-client", o.QPSPerClient, "The max QPS of the services requests of one client, 0 means no limit.")
+	fs.IntVar(&o.BurstPerClient, "services-burst-per-client", o.BurstPerClient, "The burst of the QPS of the services requests of one client.")
+}
+
+// FlowController rejects the requests exceeding the concurrency or QPS of the client with 429, the client is
+// identified by its IP.
+type FlowController struct {
+	options FlowControlOptions
+	nowFn   func() time.Time
+
+	lock        sync.Mutex
+	clients     map[string]*clientFlow
+	lastCleanup time.Time
+}
+
+type clientFlow struct {
+	inflight int
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func NewFlowController(options *FlowControlOptions) *FlowController {
+	return &FlowController{
+		options: *options,
+		nowFn:   time.Now,
+		clients: map[string]*clientFlow{},
+	}
+}
+
+// Handler returns the middleware applying the flow control.
+func (f *FlowController) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		release, reason := f.acquire(c.ClientIP())
+		if release == nil {
+			c.Header("Retry-After", "1")
+			ResponseErrorMessage(c, http.StatusTooManyRequests, reason)
+			c.Abort()
+			return
+		}
+		defer release()
+		c.Next()
+	}
+}
+
+// acquire admits a request of the client, it returns the function to release the request if admitted,
+// otherwise the reason of the rejection.
+func (f *FlowController) acquire(client string) (func(), string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	now := f.nowFn()
+	f.cleanupIdleClientsLocked(now)
+	flow, ok := f.clients[client]
+	if !ok {
+		flow = &clientFlow{}
+		if f.options.QPSPerClient > 0 {
+			flow.limiter = rate.NewLimiter(rate.Limit(f.options.QPSPerClient), f.options.BurstPerClient)
+		}
+		f.clients[client] = flow
+	}
+	flow.lastSeen = now
+
+	if f.options.MaxInflightPerClient > 0 && flow.inflight >= f.options.MaxInflightPerClient {
+		return nil, "too many concurrent requests"
+	}
+	if flow.limiter != nil && !flow.limiter.AllowN(now, 1) {
+		return nil, "too many requests"
+	}
+	flow.inflight++
+	return func() {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+		flow.inflight--
+	}, ""
+}
+
+func (f *FlowController) cleanupIdleClientsLocked(now time.Time) {
+	if now.Sub(f.lastCleanup) < flowControlIdleClientTimeout {
+		return
+	}
+	f.lastCleanup = now
+	for client, flow := range f.clients {
+		if flow.inflight == 0 && now.Sub(flow.lastSeen) >= flowControlIdleClientTimeout {
+			delete(f.clients, client)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlowController(t *testing.T) {
+	now := time.Now()
+	f := NewFlowController(&FlowControlOptions{
+		MaxInflightPerClient: 2,
+		QPSPerClient:         1,
+		BurstPerClient:       3,
+	})
+	f.nowFn = func() time.Time { return now }
+
+	release1, _ := f.acquire("client-a")
+	assert.NotNil(t, release1)
+	release2, _ := f.acquire("client-a")
+	assert.NotNil(t, release2)
+	// the concurrency of client-a is exhausted, but client-b is not affected
+	release, reason := f.acquire("client-a")
+	assert.Nil(t, release)
+	assert.Equal(t, "too many concurrent requests", reason)
+	releaseB, _ := f.acquire("client-b")
+	assert.NotNil(t, releaseB)
+	releaseB()
+
+	// the burst of client-a is exhausted
+	release1()
+	release3, _ := f.acquire("client-a")
+	assert.NotNil(t, release3)
+	release3()
+	release, reason = f.acquire("client-a")
+	assert.Nil(t, release)
+	assert.Equal(t, "too many requests", reason)
+
+	// the token is refilled
+	now = now.Add(time.Second)
+	release4, _ := f.acquire("client-a")
+	assert.NotNil(t, release4)
+	release4()
+	release2()
+
+	// the idle clients are dropped
+	now = now.Add(flowControlIdleClientTimeout)
+	release5, _ := f.acquire("client-c")
+	assert.NotNil(t, release5)
+	assert.Len(t, f.clients, 1)
+}
+
+func TestFlowControllerHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	e := gin.New()
+	e.Use(NewFlowController(&FlowControlOptions{QPSPerClient: 1, BurstPerClient: 1}).Handler())
+	e.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, "ok")
+	})
+
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const (
+	// KoordSchedulerSubsystem - subsystem name used by koord-scheduler
+	KoordSchedulerSubsystem = "koord_scheduler"
+)
+
+var (
+	ElasticQuotaAccountingDrift = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      KoordSchedulerSubsystem,
+			Name:           "elastic_quota_accounting_drift",
+			Help:           "Drift of the cached Request/Used of the elastic quota group from the value recomputed from pods, by the quota, by the field, by the resource. It is the value before the correction",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"quota", "field", "resource"})
+
+	ElasticQuotaResource = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      KoordSchedulerSubsystem,
+			Name:           "elastic_quota_resource",
+			Help:           "Runtime/Used/Request/Min/Max of the elastic quota group, by the quota, by the field, by the resource",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"quota", "field", "resource"})
+
+	ElasticQuotaDominantShare = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem:      KoordSchedulerSubsystem,
+			Name:           "elastic_quota_dominant_share",
+			Help:           "Dominant resource share of the used of the elastic quota group in the resource of its parent, by the quota, by the dominant resource",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"quota", "resource"})
+
+	ElasticQuotaAdmissionRejections = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      KoordSchedulerSubsystem,
+			Name:           "elastic_quota_admission_rejections_total",
+			Help:           "Number of pods rejected by the elastic quota group, by the quota, by the reason",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"quota", "reason"})
+
+	ElasticQuotaRuntimeCalculationLatency = metrics.NewHistogram(
+		&metrics.HistogramOpts{
+			Subsystem:      KoordSchedulerSubsystem,
+			Name:           "elastic_quota_runtime_calculation_duration_seconds",
+			Help:           "Latency of the runtime recalculation of the elastic quota group in seconds",
+			Buckets:        metrics.ExponentialBuckets(0.00001, 2, 16),
+			StabilityLevel: metrics.ALPHA,
+		})
+
+	ElasticQuotaResyncs = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      KoordSchedulerSubsystem,
+			Name:           "elastic_quota_resyncs_total",
+			Help:           "Number of the full resyncs of the elastic quota groups when starting leading, by the result, i.e. consistent, drifted or failed",
+			StabilityLevel: metrics.ALPHA,
+		}, []string{"result"})
+
+	metricsList = []metrics.Registerable{
+		ElasticQuotaAccountingDrift,
+		ElasticQuotaResyncs,
+		ElasticQuotaResource,
+		ElasticQuotaDominantShare,
+		ElasticQuotaAdmissionRejections,
+		ElasticQuotaRuntimeCalculationLatency,
+	}
+)
+
+var registerMetrics sync.Once
+
+// Register all metrics.
+func Register() {
+	// Register the metrics.
+	registerMetrics.Do(func() {
+		RegisterMetrics(metricsList...)
+	})
+}
+
+// RegisterMetrics registers a list of metrics.
+func RegisterMetrics(extraMetrics ...metrics.Registerable) {
+	for _, metric := range extraMetrics {
+		legacyregistry.MustRegister(metric)
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cachegroup
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+const (
+	Name = "CacheGroup"
+)
+
+var (
+	_ framework.ScorePlugin = &Plugin{}
+)
+
+// Plugin prefers the nodes running more pods of the same CacheGroup, so that the cooperating pods share the
+// LLC partition of the CacheGroup on fewer nodes and the limited CLOS of the nodes are saved.
+type Plugin struct {
+	handle framework.Handle
+}
+
+func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	return &Plugin{handle: handle}, nil
+}
+
+func (p *Plugin) Name() string { return Name }
+
+func (p *Plugin) Score(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, nodeName string) (int64, *framework.Status) {
+	cacheGroup := extension.GetPodCacheGroup(pod)
+	if cacheGroup == "" {
+		return 0, nil
+	}
+	nodeInfo, err := p.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
+	}
+	if nodeInfo == nil || nodeInfo.Node() == nil {
+		return 0, framework.NewStatus(framework.Error, "node not found")
+	}
+	var count int64
+	for _, podInfo := range nodeInfo.Pods {
+		if podInfo.Pod.Namespace == pod.Namespace && extension.GetPodCacheGroup(podInfo.Pod) == cacheGroup {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (p *Plugin) ScoreExtensions() framework.ScoreExtensions {
+	return p
+}
+
+// NormalizeScore scales the number of the pods in the same CacheGroup to [0, MaxNodeScore].
+func (p *Plugin) NormalizeScore(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, scores framework.NodeScoreList) *framework.Status {
+	var maxCount int64
+	for i := range scores {
+		if scores[i].Score > maxCount {
+			maxCount = scores[i].Score
+		}
+	}
+	if maxCount == 0 {
+		return nil
+	}
+	for i := range scores {
+		scores[i].Score = scores[i].Score * framework.MaxNodeScore / maxCount
+	}
+	return nil
+}
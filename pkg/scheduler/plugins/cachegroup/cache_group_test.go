@@ -0,0 +1,180 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cachegroup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	"k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	schedulertesting "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+var _ framework.SharedLister = &testSharedLister{}
+
+type testSharedLister struct {
+	nodes       []*corev1.Node
+	nodeInfos   []*framework.NodeInfo
+	nodeInfoMap map[string]*framework.NodeInfo
+}
+
+func newTestSharedLister(pods []*corev1.Pod, nodes []*corev1.Node) *testSharedLister {
+	nodeInfoMap := make(map[string]*framework.NodeInfo)
+	nodeInfos := make([]*framework.NodeInfo, 0)
+	for _, pod := range pods {
+		nodeName := pod.Spec.NodeName
+		if _, ok := nodeInfoMap[nodeName]; !ok {
+			nodeInfoMap[nodeName] = framework.NewNodeInfo()
+		}
+		nodeInfoMap[nodeName].AddPod(pod)
+	}
+	for _, node := range nodes {
+		if _, ok := nodeInfoMap[node.Name]; !ok {
+			nodeInfoMap[node.Name] = framework.NewNodeInfo()
+		}
+		nodeInfoMap[node.Name].SetNode(node)
+	}
+
+	for _, v := range nodeInfoMap {
+		nodeInfos = append(nodeInfos, v)
+	}
+
+	return &testSharedLister{
+		nodes:       nodes,
+		nodeInfos:   nodeInfos,
+		nodeInfoMap: nodeInfoMap,
+	}
+}
+
+func (f *testSharedLister) NodeInfos() framework.NodeInfoLister {
+	return f
+}
+
+func (f *testSharedLister) List() ([]*framework.NodeInfo, error) {
+	return f.nodeInfos, nil
+}
+
+func (f *testSharedLister) HavePodsWithAffinityList() ([]*framework.NodeInfo, error) {
+	return nil, nil
+}
+
+func (f *testSharedLister) HavePodsWithRequiredAntiAffinityList() ([]*framework.NodeInfo, error) {
+	return nil, nil
+}
+
+func (f *testSharedLister) Get(nodeName string) (*framework.NodeInfo, error) {
+	return f.nodeInfoMap[nodeName], nil
+}
+
+func newTestPod(namespace, name, nodeName, cacheGroup string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+		},
+	}
+	if cacheGroup != "" {
+		pod.Annotations = map[string]string{
+			extension.AnnotationPodCacheGroup: cacheGroup,
+		}
+	}
+	return pod
+}
+
+func TestScore(t *testing.T) {
+	var nodes []*corev1.Node
+	for _, name := range []string{"node-0", "node-1", "node-2"} {
+		nodes = append(nodes, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	pods := []*corev1.Pod{
+		newTestPod("default", "pod-0", "node-0", "redis"),
+		newTestPod("default", "pod-1", "node-0", "redis"),
+		newTestPod("default", "pod-2", "node-1", "redis"),
+		// the group with the same name in another namespace is another tenant
+		newTestPod("other", "pod-3", "node-2", "redis"),
+		newTestPod("default", "pod-4", "node-2", "mysql"),
+		newTestPod("default", "pod-5", "node-2", ""),
+	}
+
+	registeredPlugins := []schedulertesting.RegisterPluginFunc{
+		schedulertesting.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		schedulertesting.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+		schedulertesting.RegisterScorePlugin(Name, New, 1),
+	}
+	cs := kubefake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(cs, 0)
+	fh, err := schedulertesting.NewFramework(registeredPlugins, "koord-scheduler",
+		runtime.WithClientSet(cs),
+		runtime.WithInformerFactory(informerFactory),
+		runtime.WithSnapshotSharedLister(newTestSharedLister(pods, nodes)),
+	)
+	assert.NoError(t, err)
+	pl, err := New(nil, fh)
+	assert.NoError(t, err)
+	p := pl.(*Plugin)
+
+	tests := []struct {
+		name       string
+		pod        *corev1.Pod
+		wantScores []int64
+	}{
+		{
+			name:       "pod not in any cache group",
+			pod:        newTestPod("default", "test", "", ""),
+			wantScores: []int64{0, 0, 0},
+		},
+		{
+			name:       "prefer the nodes running more pods of the same group",
+			pod:        newTestPod("default", "test", "", "redis"),
+			wantScores: []int64{100, 50, 0},
+		},
+		{
+			name:       "no pod of the same group",
+			pod:        newTestPod("default", "test", "", "kafka"),
+			wantScores: []int64{0, 0, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scores framework.NodeScoreList
+			for _, node := range nodes {
+				score, status := p.Score(context.TODO(), framework.NewCycleState(), tt.pod, node.Name)
+				assert.True(t, status.IsSuccess())
+				scores = append(scores, framework.NodeScore{Name: node.Name, Score: score})
+			}
+			assert.True(t, p.NormalizeScore(context.TODO(), framework.NewCycleState(), tt.pod, scores).IsSuccess())
+			var gotScores []int64
+			for _, score := range scores {
+				gotScores = append(gotScores, score.Score)
+			}
+			assert.Equal(t, tt.wantScores, gotScores)
+		})
+	}
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compatibledefaultpreemption
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	policylisters "k8s.io/client-go/listers/policy/v1"
+	"k8s.io/client-go/tools/cache"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultpreemption"
+	schedulerutil "k8s.io/kubernetes/pkg/scheduler/util"
+
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/coscheduling/util"
+	koordutil "github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	// gangIndex indexes the pods by the gang id, i.e. the namespace/name of the gang.
+	gangIndex = "gang"
+)
+
+func gangIndexFunc(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil, nil
+	}
+	gangName := util.GetGangNameByPod(pod)
+	if gangName == "" {
+		return nil, nil
+	}
+	return []string{util.GetId(pod.Namespace, gangName)}, nil
+}
+
+// gangPreemption takes the gangs of the victims as a unit: all the bound children of a victim gang are preempted
+// together, since the gang can't run with part of the children anyway. The children may run on the other nodes than
+// the candidate, so the PDB violations are counted for them too, and the candidates breaking fewer gangs are
+// preferred naturally since they have fewer victims.
+type gangPreemption struct {
+	podIndexer cache.Indexer
+	pdbLister  policylisters.PodDisruptionBudgetLister
+}
+
+var _ defaultpreemption.Candidate = &candidate{}
+
+type candidate struct {
+	victims *extenderv1.Victims
+	name    string
+}
+
+func (c *candidate) Victims() *extenderv1.Victims {
+	return c.victims
+}
+
+func (c *candidate) Name() string {
+	return c.name
+}
+
+func newGangPreemption(fh framework.Handle, enablePodDisruptionBudget bool) (*gangPreemption, error) {
+	podInformer := fh.SharedInformerFactory().Core().V1().Pods().Informer()
+	if _, ok := podInformer.GetIndexer().GetIndexers()[gangIndex]; !ok {
+		if err := podInformer.AddIndexers(cache.Indexers{gangIndex: gangIndexFunc}); err != nil {
+			return nil, err
+		}
+	}
+	gp := &gangPreemption{podIndexer: podInformer.GetIndexer()}
+	// keep the same PDB view as DefaultPreemption
+	if enablePodDisruptionBudget {
+		gp.pdbLister = fh.SharedInformerFactory().Policy().V1().PodDisruptionBudgets().Lister()
+	}
+	return gp, nil
+}
+
+// expandCandidates expands the victims of each candidate to their gangs. The candidates whose victim gangs can't be
+// preempted as a unit are dropped, and the reasons are recorded in the nodeToStatusMap.
+func (p *gangPreemption) expandCandidates(preemptor *corev1.Pod, candidates []defaultpreemption.Candidate,
+	nodeToStatusMap framework.NodeToStatusMap) ([]defaultpreemption.Candidate, error) {
+	var pdbs []*policy.PodDisruptionBudget
+	if p.pdbLister != nil {
+		var err error
+		pdbs, err = p.pdbLister.List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	gangChildren := map[string][]*corev1.Pod{}
+	result := make([]defaultpreemption.Candidate, 0, len(candidates))
+	for _, c := range candidates {
+		extraVictims, err := p.expandGangVictims(preemptor, c.Victims().Pods, gangChildren)
+		if err != nil {
+			klog.V(4).InfoS("Failed to preempt the victim gangs", "pod", klog.KObj(preemptor), "node", c.Name(), "err", err)
+			nodeToStatusMap[c.Name()] = framework.NewStatus(framework.Unschedulable, err.Error())
+			continue
+		}
+		if len(extraVictims) == 0 {
+			result = append(result, c)
+			continue
+		}
+		victims := append(append([]*corev1.Pod{}, c.Victims().Pods...), extraVictims...)
+		// the candidates are compared by the highest priority victim first
+		sort.SliceStable(victims, func(i, j int) bool { return schedulerutil.MoreImportantPod(victims[i], victims[j]) })
+		result = append(result, &candidate{
+			name: c.Name(),
+			victims: &extenderv1.Victims{
+				Pods:             victims,
+				NumPDBViolations: c.Victims().NumPDBViolations + countPDBViolations(c.Victims().Pods, extraVictims, pdbs),
+			},
+		})
+	}
+	return result, nil
+}
+
+// expandGangVictims returns the bound children of the gangs of the victims which are not victims yet, it fails if any
+// child of the victim gangs has no lower priority than the preemptor, which means the gang can't be preempted as a unit.
+// The children of each gang are cached in gangChildren across the candidates.
+func (p *gangPreemption) expandGangVictims(preemptor *corev1.Pod, victims []*corev1.Pod, gangChildren map[string][]*corev1.Pod) ([]*corev1.Pod, error) {
+	preemptorPriority := corev1helpers.PodPriority(preemptor)
+	preemptorGangId := ""
+	if gangName := util.GetGangNameByPod(preemptor); gangName != "" {
+		preemptorGangId = util.GetId(preemptor.Namespace, gangName)
+	}
+
+	victimSet := make(map[types.UID]bool, len(victims))
+	for _, victim := range victims {
+		victimSet[victim.UID] = true
+	}
+	var extraVictims []*corev1.Pod
+	visitedGangs := map[string]bool{}
+	for _, victim := range victims {
+		gangName := util.GetGangNameByPod(victim)
+		if gangName == "" {
+			continue
+		}
+		gangId := util.GetId(victim.Namespace, gangName)
+		if visitedGangs[gangId] || gangId == preemptorGangId {
+			continue
+		}
+		visitedGangs[gangId] = true
+
+		children, ok := gangChildren[gangId]
+		if !ok {
+			objs, err := p.podIndexer.ByIndex(gangIndex, gangId)
+			if err != nil {
+				return nil, err
+			}
+			for _, obj := range objs {
+				if child, ok := obj.(*corev1.Pod); ok && child.Spec.NodeName != "" && !koordutil.IsPodTerminated(child) {
+					children = append(children, child)
+				}
+			}
+			gangChildren[gangId] = children
+		}
+		for _, child := range children {
+			if victimSet[child.UID] {
+				continue
+			}
+			if corev1helpers.PodPriority(child) >= preemptorPriority {
+				return nil, fmt.Errorf("gang %s can't be preempted as a unit, the priority of pod %s/%s is not lower than the preemptor",
+					gangId, child.Namespace, child.Name)
+			}
+			victimSet[child.UID] = true
+			extraVictims = append(extraVictims, child)
+		}
+	}
+	return extraVictims, nil
+}
+
+// countPDBViolations counts the extra victims violating the PDBs, the budgets are consumed by the victims first
+// since the violations of them are counted already.
+func countPDBViolations(victims, extraVictims []*corev1.Pod, pdbs []*policy.PodDisruptionBudget) int64 {
+	if len(pdbs) == 0 {
+		return 0
+	}
+	pdbsAllowed := make([]int32, len(pdbs))
+	for i, pdb := range pdbs {
+		pdbsAllowed[i] = pdb.Status.DisruptionsAllowed
+	}
+	// consumeBudget decreases the budgets of the PDBs matching the pod, and returns whether any of them is violated.
+	consumeBudget := func(pod *corev1.Pod) bool {
+		violated := false
+		// A pod with no labels will not match any PDB.
+		if len(pod.Labels) == 0 {
+			return false
+		}
+		for i, pdb := range pdbs {
+			if pdb.Namespace != pod.Namespace {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+			if err != nil || selector.Empty() || !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			// Existing in DisruptedPods means it has been processed in API server.
+			if _, exist := pdb.Status.DisruptedPods[pod.Name]; exist {
+				continue
+			}
+			pdbsAllowed[i]--
+			if pdbsAllowed[i] < 0 {
+				violated = true
+			}
+		}
+		return violated
+	}
+	for _, victim := range victims {
+		consumeBudget(victim)
+	}
+	var numViolations int64
+	for _, victim := range extraVictims {
+		if consumeBudget(victim) {
+			numViolations++
+		}
+	}
+	return numViolations
+}
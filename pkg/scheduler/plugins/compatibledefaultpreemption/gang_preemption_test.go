@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compatibledefaultpreemption
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	policylisters "k8s.io/client-go/listers/policy/v1"
+	"k8s.io/client-go/tools/cache"
+	extenderv1 "k8s.io/kube-scheduler/extender/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultpreemption"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+var podStartTime = metav1.Now()
+
+func makeGangPod(name, gang, node string, priority int32) *corev1.Pod {
+	pod := st.MakePod().Namespace("default").Name(name).UID(name).Node(node).Priority(priority).Label("app", "test").Obj()
+	pod.Status.StartTime = &podStartTime
+	if gang != "" {
+		pod.Annotations = map[string]string{extension.AnnotationGangName: gang}
+	}
+	return pod
+}
+
+func podNames(pods []*corev1.Pod) []string {
+	var names []string
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	return names
+}
+
+func TestGangPreemption_ExpandCandidates(t *testing.T) {
+	pods := []*corev1.Pod{
+		makeGangPod("gang-a-1", "gang-a", "node-1", 10),
+		makeGangPod("gang-a-2", "gang-a", "node-2", 20),
+		makeGangPod("gang-a-3", "gang-a", "", 10),
+		makeGangPod("gang-b-1", "gang-b", "node-1", 10),
+		makeGangPod("gang-b-2", "gang-b", "node-2", 100),
+		makeGangPod("gang-c-1", "gang-c", "node-1", 10),
+		makeGangPod("gang-c-2", "gang-c", "node-2", 10),
+		makeGangPod("single", "", "node-1", 10),
+	}
+	podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{gangIndex: gangIndexFunc})
+	for _, pod := range pods {
+		assert.NoError(t, podIndexer.Add(pod))
+	}
+	pdb := &policy.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pdb"},
+		Spec: policy.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}},
+		},
+		Status: policy.PodDisruptionBudgetStatus{DisruptionsAllowed: 1},
+	}
+
+	tests := []struct {
+		name               string
+		preemptor          *corev1.Pod
+		victims            []*corev1.Pod
+		pdbs               []*policy.PodDisruptionBudget
+		want               []string
+		wantPDBViolations  int64
+		wantUnschedulable  bool
+		wantCandidateExist bool
+	}{
+		{
+			name:               "pod not in gang",
+			preemptor:          makeGangPod("preemptor", "", "", 50),
+			victims:            []*corev1.Pod{pods[7]},
+			want:               []string{"single"},
+			wantCandidateExist: true,
+		},
+		{
+			name:               "bound children of victim gang on other nodes preempted together",
+			preemptor:          makeGangPod("preemptor", "", "", 50),
+			victims:            []*corev1.Pod{pods[0], pods[7]},
+			want:               []string{"gang-a-2", "gang-a-1", "single"},
+			wantCandidateExist: true,
+		},
+		{
+			name:               "PDB violations of the extra victims counted",
+			preemptor:          makeGangPod("preemptor", "", "", 50),
+			victims:            []*corev1.Pod{pods[0]},
+			pdbs:               []*policy.PodDisruptionBudget{pdb},
+			want:               []string{"gang-a-2", "gang-a-1"},
+			wantPDBViolations:  1,
+			wantCandidateExist: true,
+		},
+		{
+			name:              "child of victim gang with higher priority",
+			preemptor:         makeGangPod("preemptor", "", "", 50),
+			victims:           []*corev1.Pod{pods[3]},
+			wantUnschedulable: true,
+		},
+		{
+			name:               "children of the gang of the preemptor not expanded",
+			preemptor:          makeGangPod("preemptor", "gang-c", "", 50),
+			victims:            []*corev1.Pod{pods[5]},
+			want:               []string{"gang-c-1"},
+			wantCandidateExist: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &gangPreemption{podIndexer: podIndexer}
+			if len(tt.pdbs) > 0 {
+				pdbIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+				for _, pdb := range tt.pdbs {
+					assert.NoError(t, pdbIndexer.Add(pdb))
+				}
+				p.pdbLister = policylisters.NewPodDisruptionBudgetLister(pdbIndexer)
+			}
+			candidates := []defaultpreemption.Candidate{
+				&candidate{name: "node-1", victims: &extenderv1.Victims{Pods: tt.victims}},
+			}
+			nodeToStatusMap := framework.NodeToStatusMap{}
+			got, err := p.expandCandidates(tt.preemptor, candidates, nodeToStatusMap)
+			assert.NoError(t, err)
+			if !tt.wantCandidateExist {
+				assert.Empty(t, got)
+				assert.Equal(t, tt.wantUnschedulable, nodeToStatusMap["node-1"].Code() == framework.Unschedulable)
+				return
+			}
+			assert.Len(t, got, 1)
+			assert.Equal(t, "node-1", got[0].Name())
+			assert.Equal(t, tt.want, podNames(got[0].Victims().Pods))
+			assert.Equal(t, tt.wantPDBViolations, got[0].Victims().NumPDBViolations)
+			assert.Empty(t, nodeToStatusMap)
+		})
+	}
+}
@@ -17,10 +17,14 @@ limitations under the License.
 package compatibledefaultpreemption
 
 import (
+	"context"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apiserver/pkg/util/feature"
+	listersv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
 	scheduledconfigv1beta2config "k8s.io/kube-scheduler/config/v1beta2"
 	"k8s.io/kubernetes/pkg/features"
 	scheduledconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
@@ -29,6 +33,9 @@ import (
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultpreemption"
 	plfeature "k8s.io/kubernetes/pkg/scheduler/framework/plugins/feature"
 	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	"k8s.io/kubernetes/pkg/scheduler/metrics"
+
+	koordfeatures "github.com/koordinator-sh/koordinator/pkg/features"
 )
 
 const (
@@ -36,8 +43,11 @@ const (
 )
 
 type CompatibleDefaultPreemption struct {
-	args *scheduledconfig.DefaultPreemptionArgs
-	framework.PostFilterPlugin
+	args           *scheduledconfig.DefaultPreemptionArgs
+	handle         framework.Handle
+	podLister      listersv1.PodLister
+	gangPreemption *gangPreemption
+	*defaultpreemption.DefaultPreemption
 }
 
 func New(dpArgs runtime.Object, fh framework.Handle) (framework.Plugin, error) {
@@ -74,9 +84,16 @@ func New(dpArgs runtime.Object, fh framework.Handle) (framework.Plugin, error) {
 	if err != nil {
 		return nil, err
 	}
+	gp, err := newGangPreemption(fh, fts.EnablePodDisruptionBudget)
+	if err != nil {
+		return nil, err
+	}
 	return &CompatibleDefaultPreemption{
-		args:             dpArgs.(*scheduledconfig.DefaultPreemptionArgs),
-		PostFilterPlugin: plg.(framework.PostFilterPlugin),
+		args:              dpArgs.(*scheduledconfig.DefaultPreemptionArgs),
+		handle:            fh,
+		podLister:         fh.SharedInformerFactory().Core().V1().Pods().Lister(),
+		gangPreemption:    gp,
+		DefaultPreemption: plg.(*defaultpreemption.DefaultPreemption),
 	}, nil
 }
 
@@ -84,6 +101,80 @@ func (plg *CompatibleDefaultPreemption) Name() string {
 	return Name
 }
 
+// PostFilter preempts the victims as DefaultPreemption does, and if GangPreemption is enabled, the gangs of the
+// victims are preempted as a unit.
+func (plg *CompatibleDefaultPreemption) PostFilter(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, m framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+	if !feature.DefaultFeatureGate.Enabled(koordfeatures.GangPreemption) {
+		return plg.DefaultPreemption.PostFilter(ctx, state, pod, m)
+	}
+	defer func() {
+		metrics.PreemptionAttempts.Inc()
+	}()
+
+	nnn, status := plg.preempt(ctx, state, pod, m)
+	if !status.IsSuccess() {
+		return nil, status
+	}
+	// This happens when the pod is not eligible for preemption or extenders filtered all candidates.
+	if nnn == "" {
+		return nil, framework.NewStatus(framework.Unschedulable)
+	}
+	return &framework.PostFilterResult{NominatedNodeName: nnn}, framework.NewStatus(framework.Success)
+}
+
+// preempt follows the preemption of DefaultPreemption, except that the victims of the candidates are expanded to
+// their gangs before the extenders and the selection of the best candidate.
+func (plg *CompatibleDefaultPreemption) preempt(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, m framework.NodeToStatusMap) (string, *framework.Status) {
+	nodeLister := plg.handle.SnapshotSharedLister().NodeInfos()
+
+	podNamespace, podName := pod.Namespace, pod.Name
+	pod, err := plg.podLister.Pods(pod.Namespace).Get(pod.Name)
+	if err != nil {
+		klog.ErrorS(err, "getting the updated preemptor pod object", "pod", klog.KRef(podNamespace, podName))
+		return "", framework.AsStatus(err)
+	}
+
+	if !defaultpreemption.PodEligibleToPreemptOthers(pod, nodeLister, m[pod.Status.NominatedNodeName]) {
+		klog.V(5).InfoS("Pod is not eligible for more preemption", "pod", klog.KObj(pod))
+		return "", nil
+	}
+
+	candidates, nodeToStatusMap, status := plg.DefaultPreemption.FindCandidates(ctx, state, pod, m)
+	if !status.IsSuccess() {
+		return "", status
+	}
+	candidates, err = plg.gangPreemption.expandCandidates(pod, candidates, nodeToStatusMap)
+	if err != nil {
+		return "", framework.AsStatus(err)
+	}
+
+	if len(candidates) == 0 {
+		fitError := &framework.FitError{
+			Pod:         pod,
+			NumAllNodes: len(nodeToStatusMap),
+			Diagnosis: framework.Diagnosis{
+				NodeToStatusMap: nodeToStatusMap,
+			},
+		}
+		return "", framework.NewStatus(framework.Unschedulable, fitError.Error())
+	}
+
+	candidates, status = defaultpreemption.CallExtenders(plg.handle.Extenders(), pod, nodeLister, candidates)
+	if !status.IsSuccess() {
+		return "", status
+	}
+
+	bestCandidate := defaultpreemption.SelectCandidate(candidates)
+	if bestCandidate == nil || len(bestCandidate.Name()) == 0 {
+		return "", nil
+	}
+
+	if status := defaultpreemption.PrepareCandidate(bestCandidate, plg.handle, plg.handle.ClientSet(), pod, plg.Name()); !status.IsSuccess() {
+		return "", status
+	}
+	return bestCandidate.Name(), nil
+}
+
 func getDefaultPreemptionArgs() (*scheduledconfig.DefaultPreemptionArgs, error) {
 	var v1beta2args scheduledconfigv1beta2config.DefaultPreemptionArgs
 	v1beta2.SetDefaults_DefaultPreemptionArgs(&v1beta2args)
@@ -19,16 +19,23 @@ package core
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8sfeature "k8s.io/apiserver/pkg/util/feature"
 	informerv1 "k8s.io/client-go/informers/core/v1"
 	listerv1 "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
@@ -39,6 +46,8 @@ import (
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/coscheduling/util"
 )
 
@@ -65,6 +74,7 @@ type Manager interface {
 	ActivateSiblings(*corev1.Pod, *framework.CycleState)
 	AllowGangGroup(*corev1.Pod, framework.Handle, string)
 	Unreserve(context.Context, *framework.CycleState, *corev1.Pod, string, framework.Handle, string)
+	RecordGangAssemblyFailure(*corev1.Pod, string)
 }
 
 // PodGroupManager defines the scheduling operation called
@@ -82,16 +92,23 @@ type PodGroupManager struct {
 	reserveResourcePercentage int32
 	// cache stores gang info
 	cache *GangCache
+	// gangAdmitters admit the gangGroups as a whole before any child is assumed, nil means none
+	gangAdmitters *frameworkext.GangAdmitters
+	// assemblyStatusQueue holds the gangs whose assembly status is published outside the scheduling cycle
+	assemblyStatusQueue workqueue.RateLimitingInterface
 	sync.RWMutex
 }
 
 // NewPodGroupManager creates a new operation object.
-func NewPodGroupManager(pgClient pgclientset.Interface,
-	pgInformer pginformer.PodGroupInformer, podInformer informerv1.PodInformer, args *config.CoschedulingArgs) *PodGroupManager {
+func NewPodGroupManager(pgClient pgclientset.Interface, pgInformer pginformer.PodGroupInformer, podInformer informerv1.PodInformer,
+	args *config.CoschedulingArgs, gangAdmitters *frameworkext.GangAdmitters) *PodGroupManager {
 	pgMgr := &PodGroupManager{
-		pgClient:  pgClient,
-		pgLister:  pgInformer.Lister(),
-		podLister: podInformer.Lister(),
+		pgClient:      pgClient,
+		pgLister:      pgInformer.Lister(),
+		podLister:     podInformer.Lister(),
+		gangAdmitters: gangAdmitters,
+		assemblyStatusQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(),
+			"GangAssemblyStatus"),
 	}
 	gangCache := NewGangCache(args, podInformer.Lister(), pgInformer.Lister(), pgMgr.pgClient)
 	pgMgr.cache = gangCache
@@ -162,11 +179,13 @@ func (pgMgr *PodGroupManager) ActivateSiblings(pod *corev1.Pod, state *framework
 }
 
 // PreFilter
-// i.Check whether children in Gang has met the requirements of minimum number under each Gang, and reject the pod if negative.
+// i.Check whether children in Gang has met the requirements of minimum number under each Gang, and whether the gangs of
+// the parent Gang has met the minimum number of the parent, and reject the pod if negative.
 // ii.Check whether the Gang is inited, and reject the pod if positive.
-// iii.Check whether the Gang is OnceResourceSatisfied
-// iv.Check whether the Gang has met the scheduleCycleValid check, and reject the pod if negative(only Strict mode ).
-// v.Try update scheduleCycle, scheduleCycleValid, childrenScheduleRoundMap as mentioned above.
+// iii.Check whether the Gang is OnceResourceSatisfied, and whether the Gang is backing off(GangBackoff only).
+// iv.Admit the whole GangGroup by the GangAdmitters before any child is assumed, and reject the pod if negative.
+// v.Check whether the Gang has met the scheduleCycleValid check, and reject the pod if negative(only Strict mode ).
+// vi.Try update scheduleCycle, scheduleCycleValid, childrenScheduleRoundMap as mentioned above.
 func (pgMgr *PodGroupManager) PreFilter(ctx context.Context, pod *corev1.Pod) error {
 	if !util.IsPodNeedGang(pod) {
 		return nil
@@ -192,11 +211,23 @@ func (pgMgr *PodGroupManager) PreFilter(ctx context.Context, pod *corev1.Pod) er
 	if gang.OnceResourceSatisfied {
 		return nil
 	}
+	if k8sfeature.DefaultFeatureGate.Enabled(features.GangBackoff) && gang.isBackingOff() {
+		return fmt.Errorf("gang is backing off until the cluster changes, gangName: %v, podName: %v", gang.Name,
+			util.GetId(pod.Namespace, pod.Name))
+	}
 	// check minNum
 	if gang.getChildrenNum() < gang.getGangMinNum() {
 		return fmt.Errorf("gang child pod not collect enough, gangName: %v, podName: %v", gang.Name,
 			util.GetId(pod.Namespace, pod.Name))
 	}
+	if !pgMgr.isParentGangSatisfied(gang) {
+		return fmt.Errorf("gangs of the parent not collect enough, gangName: %v, parent: %v, podName: %v", gang.Name,
+			gang.getParentGang(), util.GetId(pod.Namespace, pod.Name))
+	}
+	if err := pgMgr.tryAdmitGangGroup(gang); err != nil {
+		return fmt.Errorf("gang not admitted, gangName: %v, podName: %v, err: %v", gang.Name,
+			util.GetId(pod.Namespace, pod.Name), err)
+	}
 
 	gangMode := gang.getGangMode()
 	if gangMode == extension.GangModeStrict {
@@ -215,7 +246,9 @@ func (pgMgr *PodGroupManager) PreFilter(ctx context.Context, pod *corev1.Pod) er
 
 // PostFilter
 // i. If strict-mode, we will set scheduleCycleValid to false and release all assumed pods.
-// ii. If non-strict mode, we will do nothing.
+// ii. If non-strict mode, we will record the pod as a straggler, and allow the gangGroup if the assumed pods are enough
+// with the stragglers tolerated.
+// iii. If the pod is not tolerated as a straggler, the gang starts backoff(GangBackoff only).
 func (pgMgr *PodGroupManager) PostFilter(ctx context.Context, pod *corev1.Pod, handle framework.Handle, pluginName string) (*framework.PostFilterResult, *framework.Status) {
 	if !util.IsPodNeedGang(pod) {
 		return &framework.PostFilterResult{}, framework.NewStatus(framework.Unschedulable, "")
@@ -239,14 +272,52 @@ func (pgMgr *PodGroupManager) PostFilter(ctx context.Context, pod *corev1.Pod, h
 			}
 		})
 		gang.setScheduleCycleValid(false)
+		pgMgr.releaseGangGroupAdmission(gang)
+		pgMgr.tryStartGangBackoff(gang)
 		return &framework.PostFilterResult{}, framework.NewStatus(framework.Unschedulable,
 			fmt.Sprintf("Gang: %v gets rejected this cycle due to Pod: %v is unschedulable even after "+
 				"PostFilter in StrictMode", gang.Name, pod.Name))
 	}
+	// the waiting pods don't come to Permit again, allow them here if the straggler is the last one they wait for
+	if !gang.addStraggler(pod) {
+		pgMgr.tryStartGangBackoff(gang)
+	} else if pgMgr.isGangGroupValidForPermit(gang) {
+		klog.InfoS("postFilter allows the gangGroup with the straggler tolerated", "gang", gang.Name, "pod", klog.KObj(pod))
+		pgMgr.AllowGangGroup(pod, handle, pluginName)
+	}
 
 	return &framework.PostFilterResult{}, framework.NewStatus(framework.Unschedulable, "")
 }
 
+func (pgMgr *PodGroupManager) tryStartGangBackoff(gang *Gang) {
+	if k8sfeature.DefaultFeatureGate.Enabled(features.GangBackoff) {
+		gang.startBackoff()
+	}
+}
+
+// OnClusterChanged clears the backoff of all the gangs when a node is added or a pod holding resources is deleted,
+// which may make the blocked gangs schedulable, the rejected children are requeued together by the EventsToRegister
+// of the plugin. The deletion of a pending or terminated pod releases neither node resources nor quota, so the
+// backoff is kept.
+func (pgMgr *PodGroupManager) OnClusterChanged(obj interface{}) {
+	if !k8sfeature.DefaultFeatureGate.Enabled(features.GangBackoff) {
+		return
+	}
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if pod, ok := obj.(*corev1.Pod); ok && !isPodHoldingResources(pod) {
+		return
+	}
+	for _, gang := range pgMgr.cache.getAllGangs() {
+		gang.clearBackoff()
+	}
+}
+
+func isPodHoldingResources(pod *corev1.Pod) bool {
+	return pod.Spec.NodeName != "" && pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed
+}
+
 // Permit
 // we will calculate all Gangs in GangGroup whether the current number of assumed-pods in each Gang meets the Gang's minimum requirement.
 // and decide whether we should let the pod wait in Permit stage or let the whole gangGroup go binding
@@ -263,29 +334,37 @@ func (pgMgr *PodGroupManager) Permit(ctx context.Context, pod *corev1.Pod) (time
 	// first add pod to the gang's WaitingPodsMap
 	gang.addAssumedPod(pod)
 
+	if !pgMgr.isGangGroupValidForPermit(gang) {
+		return gang.getRemainingWaitTime(), Wait
+	}
+	return 0, Success
+}
+
+// isGangGroupValidForPermit checks whether all the gangs of the gangGroup have assumed enough children.
+func (pgMgr *PodGroupManager) isGangGroupValidForPermit(gang *Gang) bool {
+	if !pgMgr.isParentGangSatisfied(gang) {
+		// wait for the other gangs of the parent
+		return false
+	}
 	gangGroup := gang.getGangGroup()
-	allGangGroupAssumed := true
 	// only the gang itself
 	if len(gangGroup) == 0 {
-		allGangGroupAssumed = gang.isGangValidForPermit()
-	} else {
-		// check each gang group
-		for _, groupName := range gangGroup {
-			gangTmp := pgMgr.cache.getGangFromCacheByGangId(groupName, false)
-			if gangTmp == nil || !gangTmp.isGangValidForPermit() {
-				allGangGroupAssumed = false
-				break
-			}
-		}
+		return gang.isGangValidForPermit()
 	}
-	if !allGangGroupAssumed {
-		return gang.WaitTime, Wait
+	// check each gang group
+	for _, groupName := range gangGroup {
+		gangTmp := pgMgr.cache.getGangFromCacheByGangId(groupName, false)
+		if gangTmp == nil || !gangTmp.isGangValidForPermit() {
+			return false
+		}
 	}
-	return 0, Success
+	return true
 }
 
 // Unreserve
 // if gang is resourceSatisfied, we only delAssumedPod
+// if gang fails to assemble the minimum number of children within the wait time, we release all the assumed pods
+// of the gangGroup whatever the mode is, forget the stragglers, and record an Event on the PodGroup
 // if gang is not resourceSatisfied and is in StrictMode, we release all the assumed pods
 func (pgMgr *PodGroupManager) Unreserve(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, nodeName string, handle framework.Handle, pluginName string) {
 	if !util.IsPodNeedGang(pod) {
@@ -296,9 +375,15 @@ func (pgMgr *PodGroupManager) Unreserve(ctx context.Context, state *framework.Cy
 		klog.InfoS("Pod does not belong to any gang", "pod", klog.KObj(pod))
 		return
 	}
+	// check the timeout before the pod is deleted, which may reset the partial scheduling
+	timeout := gang.tryRollbackPartialSchedule()
 	// first delete the pod from gang's waitingFroBindChildren map
 	gang.delAssumedPod(pod)
 
+	if timeout {
+		pgMgr.rollbackGangGroup(gang, pod, handle, pluginName)
+		return
+	}
 	if !gang.isGangOnceResourceSatisfied() && gang.getGangMode() == extension.GangModeStrict {
 		// release resource of all assumed children of the gang
 		handle.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
@@ -308,10 +393,40 @@ func (pgMgr *PodGroupManager) Unreserve(ctx context.Context, state *framework.Cy
 				waitingPod.Reject(pluginName, "rejection in Unreserve")
 			}
 		})
+		pgMgr.releaseGangGroupAdmission(gang)
 	}
 
 }
 
+// rollbackGangGroup rejects all the waiting pods of the gangGroup, the Unreserve of all the reserve plugins is called
+// for each rejected pod, which releases the assumed pod and rolls back its quota and reservation charges.
+func (pgMgr *PodGroupManager) rollbackGangGroup(gang *Gang, pod *corev1.Pod, handle framework.Handle, pluginName string) {
+	gangSet := map[string]bool{gang.Name: true}
+	for _, gangId := range gang.getGangGroup() {
+		gangSet[gangId] = true
+	}
+	handle.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
+		waitingGangId := util.GetId(waitingPod.GetPod().Namespace, util.GetGangNameByPod(waitingPod.GetPod()))
+		if gangSet[waitingGangId] {
+			klog.InfoS("unReserve rejects the pod from timeout Gang", "gang", waitingGangId, "pod", klog.KObj(waitingPod.GetPod()))
+			waitingPod.Reject(pluginName, "gang partial scheduling timeout")
+		}
+	})
+	pgMgr.releaseGangGroupAdmission(gang)
+
+	recorder := handle.EventRecorder()
+	if recorder == nil {
+		return
+	}
+	var regarding runtime.Object = pod
+	if _, pg := pgMgr.GetPodGroup(pod); pg != nil {
+		regarding = pg
+	}
+	recorder.Eventf(regarding, pod, corev1.EventTypeWarning, "GangRollback", "Unreserve",
+		"Gang %v failed to assemble %d children within %v, all the assumed pods are released",
+		gang.Name, gang.getGangMinNum(), gang.getGangWaitTime())
+}
+
 // PostBind updates a PodGroup's status.
 func (pgMgr *PodGroupManager) PostBind(ctx context.Context, pod *corev1.Pod, nodeName string) {
 	if !util.IsPodNeedGang(pod) {
@@ -333,6 +448,7 @@ func (pgMgr *PodGroupManager) PostBind(ctx context.Context, pod *corev1.Pod, nod
 	pgCopy := pg.DeepCopy()
 
 	pgCopy.Status.Scheduled = int32(gang.getBoundPodNum())
+	assemblyStatusChanged := setGangAssemblyStatus(pgCopy, gang)
 
 	if pgCopy.Status.Scheduled >= pgCopy.Spec.MinMember {
 		pgCopy.Status.Phase = v1alpha1.PodGroupScheduled
@@ -344,7 +460,7 @@ func (pgMgr *PodGroupManager) PostBind(ctx context.Context, pod *corev1.Pod, nod
 			pgCopy.Status.ScheduleStartTime = metav1.Time{Time: time.Now()}
 		}
 	}
-	if pgCopy.Status.Phase != pg.Status.Phase {
+	if pgCopy.Status.Phase != pg.Status.Phase || assemblyStatusChanged {
 		pg, err := pgMgr.pgLister.PodGroups(pgCopy.Namespace).Get(pgCopy.Name)
 		if err != nil {
 			klog.ErrorS(err, "PosFilter failed to get PodGroup", "podGroup", klog.KObj(pgCopy))
@@ -365,6 +481,99 @@ func (pgMgr *PodGroupManager) PostBind(ctx context.Context, pod *corev1.Pod, nod
 
 }
 
+// RecordGangAssemblyFailure records the reason why the child fails to be scheduled. The reason should be stable
+// across the scheduling cycles, e.g. without the node counts, as the assembly status of the gang is published on the
+// PodGroup with an Event when the reason of a child changes. The publishing is batched outside the scheduling cycle.
+func (pgMgr *PodGroupManager) RecordGangAssemblyFailure(pod *corev1.Pod, reason string) {
+	gang := pgMgr.GetGangByPod(pod)
+	if gang == nil || gang.isGangOnceResourceSatisfied() {
+		return
+	}
+	if !gang.setFailureReason(pod, reason) {
+		return
+	}
+	pgMgr.assemblyStatusQueue.AddAfter(gang.Name, gangAssemblyStatusSyncDelay)
+}
+
+// RunAssemblyStatusWriter publishes the assembly status of the gangs queued by RecordGangAssemblyFailure until stopCh
+// is closed, the failures of one gang within gangAssemblyStatusSyncDelay are published in one patch.
+func (pgMgr *PodGroupManager) RunAssemblyStatusWriter(recorder events.EventRecorder, stopCh <-chan struct{}) {
+	go func() {
+		<-stopCh
+		pgMgr.assemblyStatusQueue.ShutDown()
+	}()
+	go wait.Until(func() {
+		for pgMgr.processNextAssemblyStatus(recorder) {
+		}
+	}, time.Second, stopCh)
+}
+
+func (pgMgr *PodGroupManager) processNextAssemblyStatus(recorder events.EventRecorder) bool {
+	key, quit := pgMgr.assemblyStatusQueue.Get()
+	if quit {
+		return false
+	}
+	defer pgMgr.assemblyStatusQueue.Done(key)
+
+	if err := pgMgr.syncAssemblyStatus(key.(string), recorder); err != nil {
+		klog.ErrorS(err, "Failed to publish gang assembly status, will retry", "gang", key)
+		pgMgr.assemblyStatusQueue.AddRateLimited(key)
+		return true
+	}
+	pgMgr.assemblyStatusQueue.Forget(key)
+	return true
+}
+
+func (pgMgr *PodGroupManager) syncAssemblyStatus(gangId string, recorder events.EventRecorder) error {
+	gang := pgMgr.cache.getGangFromCacheByGangId(gangId, false)
+	if gang == nil || gang.isGangOnceResourceSatisfied() {
+		return nil
+	}
+	namespace, name, err := cache.SplitMetaNamespaceKey(gangId)
+	if err != nil {
+		return nil
+	}
+	pg, err := pgMgr.pgLister.PodGroups(namespace).Get(name)
+	if err != nil {
+		// the gang created by the pod annotations has no PodGroup
+		return nil
+	}
+	pgCopy := pg.DeepCopy()
+	if !setGangAssemblyStatus(pgCopy, gang) {
+		return nil
+	}
+	patch, err := util.CreateMergePatch(pg, pgCopy)
+	if err != nil {
+		return err
+	}
+	if err := pgMgr.PatchPodGroup(pg.Name, pg.Namespace, patch); err != nil {
+		return err
+	}
+	if recorder != nil {
+		recorder.Eventf(pg, nil, corev1.EventTypeWarning, "GangAssemblyBlocked", "Scheduling",
+			"Gang %v failed to be assembled, the children failed to be scheduled: %v", gang.Name,
+			strings.Join(gang.getFailureReasons(), "; "))
+	}
+	return nil
+}
+
+// setGangAssemblyStatus sets the assembly status of the gang on the PodGroup, it returns whether the status changes.
+func setGangAssemblyStatus(pg *v1alpha1.PodGroup, gang *Gang) bool {
+	data, err := json.Marshal(gang.getAssemblyStatus())
+	if err != nil {
+		klog.ErrorS(err, "Failed to marshal gang assembly status", "gang", gang.Name)
+		return false
+	}
+	if pg.Annotations[extension.AnnotationGangAssemblyStatus] == string(data) {
+		return false
+	}
+	if pg.Annotations == nil {
+		pg.Annotations = map[string]string{}
+	}
+	pg.Annotations[extension.AnnotationGangAssemblyStatus] = string(data)
+	return true
+}
+
 func (pgMgr *PodGroupManager) GetCreatTime(podInfo *framework.QueuedPodInfo) time.Time {
 	// first check if the pod belongs to the Gang
 	// it doesn't belong to the gang,we get the creation time of the pod
@@ -391,6 +600,20 @@ func (pgMgr *PodGroupManager) PatchPodGroup(pgName string, namespace string, pat
 	return err
 }
 
+// isParentGangSatisfied checks whether the number of the children gangs of the parent gang reaches the minimum number
+// of the parent, it's always true if the gang has no parent.
+func (pgMgr *PodGroupManager) isParentGangSatisfied(gang *Gang) bool {
+	parentId := gang.getParentGang()
+	if parentId == "" {
+		return true
+	}
+	parent := pgMgr.cache.getGangFromCacheByGangId(parentId, false)
+	if parent == nil {
+		return false
+	}
+	return pgMgr.cache.getChildGangNum(parentId) >= parent.getGangMinNum()
+}
+
 // GetPodGroup returns the PodGroup that a Pod belongs to in cache.
 func (pgMgr *PodGroupManager) GetPodGroup(pod *corev1.Pod) (string, *v1alpha1.PodGroup) {
 	pgName := util.GetGangNameByPod(pod)
@@ -430,6 +653,8 @@ func (pgMgr *PodGroupManager) AllowGangGroup(pod *corev1.Pod, handle framework.H
 			}
 		}
 	})
+	// all the children are assumed, release what they haven't taken over
+	pgMgr.releaseGangGroupAdmission(gang)
 
 }
 
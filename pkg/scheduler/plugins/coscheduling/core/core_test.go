@@ -25,10 +25,15 @@ import (
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/client-go/informers"
 	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/retry"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
 	st "k8s.io/kubernetes/pkg/scheduler/testing"
 	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
 	fakepgclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
@@ -37,6 +42,7 @@ import (
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/coscheduling/util"
 )
 
@@ -53,7 +59,7 @@ func NewManager4Test() *Mgr {
 	podClient := clientsetfake.NewSimpleClientset()
 	informerFactory := informers.NewSharedInformerFactory(podClient, 0)
 	podInformer := informerFactory.Core().V1().Pods()
-	pgManager := NewPodGroupManager(pgClient, pgInformer, podInformer, &config.CoschedulingArgs{DefaultTimeout: &metav1.Duration{Duration: 300 * time.Second}})
+	pgManager := NewPodGroupManager(pgClient, pgInformer, podInformer, &config.CoschedulingArgs{DefaultTimeout: &metav1.Duration{Duration: 300 * time.Second}}, nil)
 	return &Mgr{
 		pgMgr:      pgManager,
 		pgInformer: pgInformer,
@@ -296,6 +302,14 @@ func TestPlugin_PreFilter(t *testing.T) {
 // PostFilter logic test in Coscheduling_test, because without the plugin and framework,we cannot assert the waitingPods
 
 func TestPermit(t *testing.T) {
+	preTimeNowFn := timeNowFn
+	defer func() {
+		timeNowFn = preTimeNowFn
+	}()
+	now := time.Now()
+	timeNowFn = func() time.Time {
+		return now
+	}
 
 	gangACreatedTime := time.Now()
 	tests := []struct {
@@ -404,6 +418,186 @@ func TestPermit(t *testing.T) {
 	}
 }
 
+func TestPermit_PartialScheduleTimeout(t *testing.T) {
+	preTimeNowFn := timeNowFn
+	defer func() {
+		timeNowFn = preTimeNowFn
+	}()
+	now := time.Now()
+	timeNowFn = func() time.Time {
+		return now
+	}
+
+	mgr := NewManager4Test().pgMgr
+	mgr.cache.onPodGroupAdd(makePg("gangA", "gangA_ns", 3, &now, nil))
+	pods := []*corev1.Pod{
+		st.MakePod().Name("pod1").UID("pod1").Namespace("gangA_ns").Label(v1alpha1.PodGroupLabel, "gangA").Obj(),
+		st.MakePod().Name("pod2").UID("pod2").Namespace("gangA_ns").Label(v1alpha1.PodGroupLabel, "gangA").Obj(),
+		st.MakePod().Name("pod3").UID("pod3").Namespace("gangA_ns").Label(v1alpha1.PodGroupLabel, "gangA").Obj(),
+	}
+	for _, pod := range pods {
+		mgr.cache.onPodAdd(pod)
+	}
+	gang := mgr.GetGangByPod(pods[0])
+	ctx := context.TODO()
+
+	waitTime, status := mgr.Permit(ctx, pods[0])
+	assert.Equal(t, Wait, status)
+	assert.Equal(t, 10*time.Second, waitTime)
+
+	// the wait time is counted from the first assumed pod
+	now = now.Add(4 * time.Second)
+	waitTime, status = mgr.Permit(ctx, pods[1])
+	assert.Equal(t, Wait, status)
+	assert.Equal(t, 6*time.Second, waitTime)
+	assert.False(t, gang.tryRollbackPartialSchedule())
+
+	// the rollback happens only once for each round
+	now = now.Add(6 * time.Second)
+	assert.True(t, gang.tryRollbackPartialSchedule())
+	assert.False(t, gang.tryRollbackPartialSchedule())
+
+	// the next round gets the whole wait time after all the assumed pods are released
+	gang.delAssumedPod(pods[0])
+	gang.delAssumedPod(pods[1])
+	waitTime, status = mgr.Permit(ctx, pods[2])
+	assert.Equal(t, Wait, status)
+	assert.Equal(t, 10*time.Second, waitTime)
+}
+
+func TestPermit_ParentGang(t *testing.T) {
+	mgr := NewManager4Test().pgMgr
+	now := time.Now()
+	mgr.cache.onPodGroupAdd(makePg("job", "ns1", 2, &now, nil))
+	ps := makePg("ps", "ns1", 1, &now, nil)
+	ps.Annotations = map[string]string{extension.AnnotationGangParent: "job"}
+	mgr.cache.onPodGroupAdd(ps)
+	psPod := st.MakePod().Name("ps-0").UID("ps-0").Namespace("ns1").Label(v1alpha1.PodGroupLabel, "ps").Obj()
+	mgr.cache.onPodAdd(psPod)
+	ctx := context.TODO()
+
+	// the worker gang is not created yet
+	assert.Error(t, mgr.PreFilter(ctx, psPod))
+	_, status := mgr.Permit(ctx, psPod)
+	assert.Equal(t, Wait, status)
+
+	worker := makePg("worker", "ns1", 2, &now, nil)
+	worker.Annotations = map[string]string{extension.AnnotationGangParent: "job"}
+	mgr.cache.onPodGroupAdd(worker)
+	workerPods := []*corev1.Pod{
+		st.MakePod().Name("worker-0").UID("worker-0").Namespace("ns1").Label(v1alpha1.PodGroupLabel, "worker").Obj(),
+		st.MakePod().Name("worker-1").UID("worker-1").Namespace("ns1").Label(v1alpha1.PodGroupLabel, "worker").Obj(),
+	}
+	for _, pod := range workerPods {
+		mgr.cache.onPodAdd(pod)
+	}
+	assert.NoError(t, mgr.PreFilter(ctx, workerPods[0]))
+
+	// all the gangs of the parent are bundled
+	_, status = mgr.Permit(ctx, workerPods[0])
+	assert.Equal(t, Wait, status)
+	_, status = mgr.Permit(ctx, workerPods[1])
+	assert.Equal(t, Success, status)
+}
+
+func TestPreFilter_GangBackoff(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, k8sfeature.DefaultMutableFeatureGate, features.GangBackoff, true)()
+	preTimeNowFn := timeNowFn
+	defer func() {
+		timeNowFn = preTimeNowFn
+	}()
+	now := time.Now()
+	timeNowFn = func() time.Time {
+		return now
+	}
+
+	mgr := NewManager4Test().pgMgr
+	pg := makePg("ganga", "ganga_ns", 2, &now, nil)
+	pg.Annotations = map[string]string{extension.AnnotationGangMode: extension.GangModeNonStrict}
+	mgr.cache.onPodGroupAdd(pg)
+	pods := []*corev1.Pod{
+		st.MakePod().Name("pod1").UID("pod1").Namespace("ganga_ns").Label(v1alpha1.PodGroupLabel, "ganga").Obj(),
+		st.MakePod().Name("pod2").UID("pod2").Namespace("ganga_ns").Label(v1alpha1.PodGroupLabel, "ganga").Obj(),
+	}
+	for _, pod := range pods {
+		mgr.cache.onPodAdd(pod)
+	}
+	ctx := context.TODO()
+	assert.NoError(t, mgr.PreFilter(ctx, pods[0]))
+
+	// the other children are rejected once a child fails
+	_, status := mgr.PostFilter(ctx, pods[0], nil, "")
+	assert.Equal(t, framework.Unschedulable, status.Code())
+	assert.Error(t, mgr.PreFilter(ctx, pods[1]))
+
+	// the deletion of the pods holding no resources doesn't clear the backoff
+	pendingPod := st.MakePod().Name("pending").UID("pending").Namespace("default").Obj()
+	mgr.OnClusterChanged(pendingPod)
+	assert.Error(t, mgr.PreFilter(ctx, pods[1]))
+	terminatedPod := st.MakePod().Name("terminated").UID("terminated").Namespace("default").Node("node1").Obj()
+	terminatedPod.Status.Phase = corev1.PodSucceeded
+	mgr.OnClusterChanged(cache.DeletedFinalStateUnknown{Key: "default/terminated", Obj: terminatedPod})
+	assert.Error(t, mgr.PreFilter(ctx, pods[1]))
+
+	// the backoff is cleared when a running pod is deleted
+	runningPod := st.MakePod().Name("running").UID("running").Namespace("default").Node("node1").Obj()
+	runningPod.Status.Phase = corev1.PodRunning
+	mgr.OnClusterChanged(runningPod)
+	assert.NoError(t, mgr.PreFilter(ctx, pods[1]))
+	_, status = mgr.PostFilter(ctx, pods[1], nil, "")
+	assert.Equal(t, framework.Unschedulable, status.Code())
+	assert.Error(t, mgr.PreFilter(ctx, pods[0]))
+
+	// the backoff is cleared when a node is added
+	mgr.OnClusterChanged(&corev1.Node{})
+	assert.NoError(t, mgr.PreFilter(ctx, pods[0]))
+
+	// the backoff expires if the event is missed
+	_, status = mgr.PostFilter(ctx, pods[1], nil, "")
+	assert.Equal(t, framework.Unschedulable, status.Code())
+	assert.Error(t, mgr.PreFilter(ctx, pods[0]))
+	now = now.Add(maxGangBackoffDuration)
+	assert.NoError(t, mgr.PreFilter(ctx, pods[0]))
+}
+
+func TestPermit_MinResources(t *testing.T) {
+	mgr := NewManager4Test().pgMgr
+	now := time.Now()
+	minResources := corev1.ResourceList{extension.NvidiaGPU: resource.MustParse("4")}
+	mgr.cache.onPodGroupAdd(makePg("gangA", "gangA_ns", 1, &now, &minResources))
+	pods := []*corev1.Pod{
+		st.MakePod().Name("pod1").UID("pod1").Namespace("gangA_ns").Label(v1alpha1.PodGroupLabel, "gangA").
+			Req(map[corev1.ResourceName]string{extension.NvidiaGPU: "2"}).Obj(),
+		st.MakePod().Name("pod2").UID("pod2").Namespace("gangA_ns").Label(v1alpha1.PodGroupLabel, "gangA").
+			Req(map[corev1.ResourceName]string{extension.NvidiaGPU: "1"}).Obj(),
+		st.MakePod().Name("pod3").UID("pod3").Namespace("gangA_ns").Label(v1alpha1.PodGroupLabel, "gangA").
+			Req(map[corev1.ResourceName]string{extension.NvidiaGPU: "1"}).Obj(),
+	}
+	for _, pod := range pods {
+		mgr.cache.onPodAdd(pod)
+	}
+	ctx := context.TODO()
+
+	// the minimum number is reached, but the minimum resources are not
+	_, status := mgr.Permit(ctx, pods[0])
+	assert.Equal(t, Wait, status)
+	// the bound children are counted too
+	mgr.GetGangByPod(pods[1]).addBoundPod(pods[1])
+	_, status = mgr.Permit(ctx, pods[0])
+	assert.Equal(t, Wait, status)
+	_, status = mgr.Permit(ctx, pods[2])
+	assert.Equal(t, Success, status)
+}
+
+func TestParseMinResources(t *testing.T) {
+	gang := NewGang("ns/gangA")
+	assert.Nil(t, parseMinResources(nil, gang))
+	assert.Nil(t, parseMinResources(map[string]string{extension.AnnotationGangMinResources: "invalid"}, gang))
+	assert.Nil(t, parseMinResources(map[string]string{extension.AnnotationGangMinResources: `{"nvidia.com/gpu": "0"}`}, gang))
+	assert.Equal(t, corev1.ResourceList{extension.NvidiaGPU: resource.MustParse("8")},
+		parseMinResources(map[string]string{extension.AnnotationGangMinResources: `{"nvidia.com/gpu": "8"}`}, gang))
+}
+
 // Unreserve also tested in the Coscheduling_test
 
 func TestPostBind(t *testing.T) {
@@ -17,21 +17,29 @@ limitations under the License.
 package core
 
 import (
+	"encoding/json"
 	"strconv"
 	"sync"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/coscheduling/util"
+	koordutil "github.com/koordinator-sh/koordinator/pkg/util"
 )
 
 var (
 	timeNowFn = time.Now
+	// maxGangBackoffDuration bounds the backoff of the gang in case the event clearing it is missed.
+	maxGangBackoffDuration = 30 * time.Second
+	// gangAssemblyStatusSyncDelay batches the failures of the children of a gang into one patch of the PodGroup.
+	gangAssemblyStatusSyncDelay = time.Second
 )
 
 const (
@@ -50,8 +58,13 @@ type Gang struct {
 	Mode              string
 	MinRequiredNumber int
 	TotalChildrenNum  int
-	GangGroup         []string
-	Children          map[string]*v1.Pod
+	// MinResources is the minimum aggregate resources of the assumed and bound children, it's checked besides
+	// MinRequiredNumber in Permit stage.
+	MinResources v1.ResourceList
+	GangGroup    []string
+	// ParentGang is the id of the parent gang, whose children gangs are bundled as the GangGroup by the GangCache.
+	ParentGang string
+	Children   map[string]*v1.Pod
 	// pods that have already assumed(waiting in Permit stage)
 	WaitingForBindChildren map[string]*v1.Pod
 	// pods that have already bound
@@ -60,6 +73,20 @@ type Gang struct {
 	// children number has reached the minNum in the early step,
 	// once this variable is set true, it is irreversible.
 	OnceResourceSatisfied bool
+	// PartialScheduleStartTime is the time the first child is assumed. If the gang fails to assemble MinRequiredNumber
+	// children within WaitTime since then, all the assumed children are rolled back.
+	PartialScheduleStartTime time.Time
+	// Admitted indicates whether the gangGroup is admitted by the GangAdmitters in this round of scheduling.
+	Admitted bool
+	// MaxStragglers is the number of the children the NonStrict gang tolerates to fail, and Stragglers are the
+	// children failed to be scheduled before the gang is ResourceSatisfied.
+	MaxStragglers int
+	Stragglers    map[string]struct{}
+	// FailureReasons is the last failure reason of the children neither assumed nor bound, keyed by the pod name.
+	FailureReasons map[string]string
+	// BackoffStartTime is the time a child fails to be scheduled, the other children are rejected in PreFilter
+	// until the cluster changes, see GangBackoff.
+	BackoffStartTime time.Time
 
 	// if the podGroup should be passed at PreFilter stage(Strict-Mode)
 	ScheduleCycleValid bool
@@ -128,6 +155,8 @@ func (gang *Gang) tryInitByPodConfig(pod *v1.Pod, args *config.CoschedulingArgs)
 		mode = extension.GangModeStrict
 	}
 	gang.Mode = mode
+	gang.MaxStragglers = parseMaxStragglers(pod.Annotations, gang)
+	gang.MinResources = parseMinResources(pod.Annotations, gang)
 
 	// here we assume that Coscheduling's CreateTime equal with the pod's CreateTime
 	gang.CreateTime = pod.CreationTimestamp.Time
@@ -151,6 +180,7 @@ func (gang *Gang) tryInitByPodConfig(pod *v1.Pod, args *config.CoschedulingArgs)
 			gang.Name, pod.Annotations[extension.AnnotationGangGroups])
 	}
 	gang.GangGroup = groupSlice
+	gang.ParentGang = parseParentGang(pod.Namespace, pod.Annotations, gang)
 
 	gang.GangFrom = GangFromPodAnnotation
 
@@ -190,6 +220,10 @@ func (gang *Gang) tryInitByPodGroup(pg *v1alpha1.PodGroup, args *config.Coschedu
 		mode = extension.GangModeStrict
 	}
 	gang.Mode = mode
+	gang.MaxStragglers = parseMaxStragglers(pg.Annotations, gang)
+	if pg.Spec.MinResources != nil && !quotav1.IsZero(*pg.Spec.MinResources) {
+		gang.MinResources = pg.Spec.MinResources.DeepCopy()
+	}
 
 	// here we assume that Coscheduling's CreateTime equal with the podGroup CRD CreateTime
 	gang.CreateTime = pg.CreationTimestamp.Time
@@ -213,6 +247,7 @@ func (gang *Gang) tryInitByPodGroup(pg *v1alpha1.PodGroup, args *config.Coschedu
 			gang.Name, pg.Annotations[extension.AnnotationGangGroups])
 	}
 	gang.GangGroup = groupSlice
+	gang.ParentGang = parseParentGang(pg.Namespace, pg.Annotations, gang)
 
 	gang.GangFrom = GangFromPodGroupCrd
 
@@ -223,6 +258,55 @@ func (gang *Gang) tryInitByPodGroup(pg *v1alpha1.PodGroup, args *config.Coschedu
 		gang.Mode, gang.WaitTime, gang.GangGroup)
 }
 
+// parseMaxStragglers returns the number of the stragglers the NonStrict gang tolerates, which is at most the
+// MinRequiredNumber.
+func parseMaxStragglers(annotations map[string]string, gang *Gang) int {
+	value, ok := annotations[extension.AnnotationGangMaxStragglers]
+	if !ok || gang.Mode != extension.GangModeNonStrict {
+		return 0
+	}
+	maxStragglers, err := strconv.Atoi(value)
+	if err != nil || maxStragglers < 0 {
+		klog.Errorf("annotation MaxStragglers illegal, gangName: %v, value: %v", gang.Name, value)
+		return 0
+	}
+	if maxStragglers > gang.MinRequiredNumber {
+		maxStragglers = gang.MinRequiredNumber
+	}
+	return maxStragglers
+}
+
+// parseMinResources returns the minimum aggregate resources of the gang in the annotations.
+func parseMinResources(annotations map[string]string, gang *Gang) v1.ResourceList {
+	value, ok := annotations[extension.AnnotationGangMinResources]
+	if !ok {
+		return nil
+	}
+	minResources := v1.ResourceList{}
+	if err := json.Unmarshal([]byte(value), &minResources); err != nil {
+		klog.Errorf("annotation MinResources illegal, gangName: %v, value: %v, err: %v", gang.Name, value, err)
+		return nil
+	}
+	if quotav1.IsZero(minResources) {
+		return nil
+	}
+	return minResources
+}
+
+// parseParentGang returns the id of the parent gang in the annotations, the GangGroup is overridden by the
+// children gangs of the parent if it's specified.
+func parseParentGang(namespace string, annotations map[string]string, gang *Gang) string {
+	parentName := annotations[extension.AnnotationGangParent]
+	if parentName == "" {
+		return ""
+	}
+	if len(gang.GangGroup) != 0 {
+		klog.Errorf("gang specifies both the parent and the gangGroups, the gangGroups are ignored, gangName: %v, parent: %v",
+			gang.Name, parentName)
+	}
+	return util.GetId(namespace, parentName)
+}
+
 func (gang *Gang) deletePod(pod *v1.Pod) bool {
 	if pod == nil {
 		return false
@@ -238,6 +322,8 @@ func (gang *Gang) deletePod(pod *v1.Pod) bool {
 	delete(gang.WaitingForBindChildren, podId)
 	delete(gang.BoundChildren, podId)
 	delete(gang.ChildrenScheduleRoundMap, podId)
+	delete(gang.Stragglers, podId)
+	delete(gang.FailureReasons, pod.Name)
 	if gang.GangFrom == GangFromPodAnnotation {
 		if len(gang.Children) == 0 {
 			return true
@@ -323,6 +409,21 @@ func (gang *Gang) getGangGroup() []string {
 	return gang.GangGroup
 }
 
+func (gang *Gang) setGangGroup(gangGroup []string) {
+	gang.lock.Lock()
+	defer gang.lock.Unlock()
+
+	gang.GangGroup = append([]string{}, gangGroup...)
+	klog.Infof("setGangGroup, gangName: %v, gangGroup: %v", gang.Name, gang.GangGroup)
+}
+
+func (gang *Gang) getParentGang() string {
+	gang.lock.Lock()
+	defer gang.lock.Unlock()
+
+	return gang.ParentGang
+}
+
 func (gang *Gang) isGangOnceResourceSatisfied() bool {
 	gang.lock.Lock()
 	defer gang.lock.Unlock()
@@ -330,6 +431,20 @@ func (gang *Gang) isGangOnceResourceSatisfied() bool {
 	return gang.OnceResourceSatisfied
 }
 
+func (gang *Gang) isAdmitted() bool {
+	gang.lock.Lock()
+	defer gang.lock.Unlock()
+
+	return gang.Admitted
+}
+
+func (gang *Gang) setAdmitted(admitted bool) {
+	gang.lock.Lock()
+	defer gang.lock.Unlock()
+
+	gang.Admitted = admitted
+}
+
 func (gang *Gang) isScheduleCycleValid() bool {
 	gang.lock.Lock()
 	defer gang.lock.Unlock()
@@ -393,6 +508,11 @@ func (gang *Gang) addAssumedPod(pod *v1.Pod) {
 		gang.WaitingForBindChildren[podId] = pod
 		klog.Infof("AddAssumedPod, gangName: %v, podName: %v", gang.Name, podId)
 	}
+	delete(gang.Stragglers, podId)
+	delete(gang.FailureReasons, pod.Name)
+	if !gang.OnceResourceSatisfied && gang.PartialScheduleStartTime.IsZero() {
+		gang.PartialScheduleStartTime = timeNowFn()
+	}
 }
 
 func (gang *Gang) delAssumedPod(pod *v1.Pod) {
@@ -404,6 +524,137 @@ func (gang *Gang) delAssumedPod(pod *v1.Pod) {
 		delete(gang.WaitingForBindChildren, podId)
 		klog.Infof("delAssumedPod, gangName: %v, podName: %v", gang.Name, podId)
 	}
+	// the next round of assembling gets the whole wait time
+	if len(gang.WaitingForBindChildren) == 0 {
+		gang.PartialScheduleStartTime = time.Time{}
+	}
+}
+
+// getRemainingWaitTime returns the time the assumed children can still wait in Permit stage, which is counted
+// from the first assumed child rather than each child.
+func (gang *Gang) getRemainingWaitTime() time.Duration {
+	gang.lock.Lock()
+	defer gang.lock.Unlock()
+
+	if gang.WaitTime <= 0 || gang.PartialScheduleStartTime.IsZero() {
+		return gang.WaitTime
+	}
+	remaining := gang.WaitTime - timeNowFn().Sub(gang.PartialScheduleStartTime)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// tryRollbackPartialSchedule checks whether the gang has failed to assemble MinRequiredNumber children within
+// WaitTime, and resets the partial scheduling so that the rollback only happens once for each round.
+func (gang *Gang) tryRollbackPartialSchedule() bool {
+	gang.lock.Lock()
+	defer gang.lock.Unlock()
+
+	if gang.OnceResourceSatisfied || gang.WaitTime <= 0 || gang.PartialScheduleStartTime.IsZero() ||
+		timeNowFn().Sub(gang.PartialScheduleStartTime) < gang.WaitTime {
+		return false
+	}
+	klog.Infof("Gang partial scheduling timeout, gangName: %v, assumed: %v, minRequiredNumber: %v, startTime: %v",
+		gang.Name, len(gang.WaitingForBindChildren), gang.MinRequiredNumber, gang.PartialScheduleStartTime)
+	gang.PartialScheduleStartTime = time.Time{}
+	// the stragglers get another chance in the next round
+	gang.Stragglers = nil
+	return true
+}
+
+// addStraggler records the child failed to be scheduled, it returns false if the gang doesn't tolerate stragglers.
+func (gang *Gang) addStraggler(pod *v1.Pod) bool {
+	gang.lock.Lock()
+	defer gang.lock.Unlock()
+
+	if gang.MaxStragglers <= 0 || gang.OnceResourceSatisfied {
+		return false
+	}
+	podId := util.GetId(pod.Namespace, pod.Name)
+	if _, ok := gang.WaitingForBindChildren[podId]; ok {
+		return false
+	}
+	if gang.Stragglers == nil {
+		gang.Stragglers = make(map[string]struct{})
+	}
+	if _, ok := gang.Stragglers[podId]; !ok {
+		gang.Stragglers[podId] = struct{}{}
+		klog.Infof("addStraggler, gangName: %v, podName: %v, stragglers: %v, maxStragglers: %v",
+			gang.Name, podId, len(gang.Stragglers), gang.MaxStragglers)
+	}
+	return true
+}
+
+// setFailureReason records the reason why the child fails to be scheduled, it returns whether the reason changes.
+func (gang *Gang) setFailureReason(pod *v1.Pod, reason string) bool {
+	gang.lock.Lock()
+	defer gang.lock.Unlock()
+
+	if gang.FailureReasons == nil {
+		gang.FailureReasons = make(map[string]string)
+	}
+	if gang.FailureReasons[pod.Name] == reason {
+		return false
+	}
+	gang.FailureReasons[pod.Name] = reason
+	return true
+}
+
+// getFailureReasons returns the distinct failure reasons of the children, sorted.
+func (gang *Gang) getFailureReasons() []string {
+	gang.lock.Lock()
+	defer gang.lock.Unlock()
+
+	reasons := sets.NewString()
+	for _, reason := range gang.FailureReasons {
+		reasons.Insert(reason)
+	}
+	return reasons.List()
+}
+
+func (gang *Gang) getAssemblyStatus() *extension.GangAssemblyStatus {
+	gang.lock.Lock()
+	defer gang.lock.Unlock()
+
+	status := &extension.GangAssemblyStatus{
+		MinMember: int32(gang.MinRequiredNumber),
+		Children:  int32(len(gang.Children)),
+		Waiting:   int32(len(gang.WaitingForBindChildren)),
+		Bound:     int32(len(gang.BoundChildren)),
+	}
+	if len(gang.FailureReasons) > 0 {
+		status.FailedChildren = make(map[string]string, len(gang.FailureReasons))
+		for name, reason := range gang.FailureReasons {
+			status.FailedChildren[name] = reason
+		}
+	}
+	return status
+}
+
+func (gang *Gang) startBackoff() {
+	gang.lock.Lock()
+	defer gang.lock.Unlock()
+
+	if gang.BackoffStartTime.IsZero() {
+		gang.BackoffStartTime = timeNowFn()
+		klog.Infof("Gang starts backoff, gangName: %v", gang.Name)
+	}
+}
+
+func (gang *Gang) clearBackoff() {
+	gang.lock.Lock()
+	defer gang.lock.Unlock()
+
+	gang.BackoffStartTime = time.Time{}
+}
+
+func (gang *Gang) isBackingOff() bool {
+	gang.lock.Lock()
+	defer gang.lock.Unlock()
+
+	return !gang.BackoffStartTime.IsZero() && timeNowFn().Sub(gang.BackoffStartTime) < maxGangBackoffDuration
 }
 
 func (gang *Gang) getChildrenFromGang() (children []*v1.Pod) {
@@ -428,6 +679,7 @@ func (gang *Gang) setResourceSatisfied() {
 
 	if !gang.OnceResourceSatisfied {
 		gang.OnceResourceSatisfied = true
+		gang.PartialScheduleStartTime = time.Time{}
 		klog.Infof("Gang ResourceSatisfied, gangName: %v", gang.Name)
 	}
 }
@@ -438,11 +690,13 @@ func (gang *Gang) addBoundPod(pod *v1.Pod) {
 
 	podId := util.GetId(pod.Namespace, pod.Name)
 	delete(gang.WaitingForBindChildren, podId)
+	delete(gang.FailureReasons, pod.Name)
 	gang.BoundChildren[podId] = pod
 
 	klog.Infof("AddBoundPod, gangName: %v, podName: %v", gang.Name, podId)
-	if len(gang.BoundChildren) >= gang.MinRequiredNumber {
+	if len(gang.BoundChildren) >= gang.MinRequiredNumber && gang.isMinResourcesSatisfiedNoLock(gang.BoundChildren) {
 		gang.OnceResourceSatisfied = true
+		gang.PartialScheduleStartTime = time.Time{}
 		klog.Infof("Gang ResourceSatisfied due to addBoundPod, gangName: %v", gang.Name)
 	}
 }
@@ -454,5 +708,41 @@ func (gang *Gang) isGangValidForPermit() bool {
 		klog.Infof("isGangValidForPermit find gang hasn't inited ,gang: %v", gang.Name)
 		return false
 	}
-	return len(gang.WaitingForBindChildren) >= gang.MinRequiredNumber || gang.OnceResourceSatisfied == true
+	if gang.OnceResourceSatisfied {
+		return true
+	}
+	// the stragglers up to MaxStragglers are tolerated, at least one child should be assumed though
+	tolerated := len(gang.Stragglers)
+	if tolerated > gang.MaxStragglers {
+		tolerated = gang.MaxStragglers
+	}
+	if tolerated > 0 && len(gang.WaitingForBindChildren) == 0 {
+		return false
+	}
+	if len(gang.WaitingForBindChildren) < gang.MinRequiredNumber-tolerated {
+		return false
+	}
+	return gang.isMinResourcesSatisfiedNoLock(gang.WaitingForBindChildren, gang.BoundChildren)
+}
+
+// isMinResourcesSatisfiedNoLock checks whether the aggregate requests of the children reach the MinResources
+// in every dimension.
+func (gang *Gang) isMinResourcesSatisfiedNoLock(children ...map[string]*v1.Pod) bool {
+	if len(gang.MinResources) == 0 {
+		return true
+	}
+	requests := v1.ResourceList{}
+	for _, pods := range children {
+		for _, pod := range pods {
+			requests = quotav1.Add(requests, koordutil.GetPodRequest(pod))
+		}
+	}
+	for name, minQuantity := range gang.MinResources {
+		if quantity := requests[name]; quantity.Cmp(minQuantity) < 0 {
+			klog.V(4).Infof("gang hasn't reached MinResources, gangName: %v, resource: %v, requested: %v, min: %v",
+				gang.Name, name, quantity.String(), minQuantity.String())
+			return false
+		}
+	}
+	return true
 }
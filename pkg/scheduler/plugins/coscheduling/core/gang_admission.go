@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// getGangGroupGangs returns the gangs of the gangGroup in cache, including the gang itself.
+func (pgMgr *PodGroupManager) getGangGroupGangs(gang *Gang) []*Gang {
+	gangGroup := gang.getGangGroup()
+	if len(gangGroup) == 0 {
+		return []*Gang{gang}
+	}
+	gangs := make([]*Gang, 0, len(gangGroup))
+	for _, gangId := range gangGroup {
+		if groupGang := pgMgr.cache.getGangFromCacheByGangId(gangId, false); groupGang != nil {
+			gangs = append(gangs, groupGang)
+		}
+	}
+	return gangs
+}
+
+// tryAdmitGangGroup admits the gangGroup by all the frameworkext.GangAdmitters once in each round of scheduling, the admitted
+// part is released if any GangAdmitter rejects.
+func (pgMgr *PodGroupManager) tryAdmitGangGroup(gang *Gang) error {
+	admitters := pgMgr.gangAdmitters.List()
+	if len(admitters) == 0 || gang.isAdmitted() {
+		return nil
+	}
+
+	gangs := pgMgr.getGangGroupGangs(gang)
+	gangIds := make([]string, 0, len(gangs))
+	children := make(map[string][]*corev1.Pod, len(gangs))
+	for _, groupGang := range gangs {
+		gangIds = append(gangIds, groupGang.Name)
+		children[groupGang.Name] = groupGang.getChildrenFromGang()
+	}
+	ttl := gang.getGangWaitTime()
+	for i, admitter := range admitters {
+		if err := admitter.AdmitGangGroup(children, ttl); err != nil {
+			for _, admitted := range admitters[:i] {
+				admitted.ReleaseGangGroup(gangIds)
+			}
+			return fmt.Errorf("gangGroup %v is rejected by %v, err: %v", gangIds, admitter.Name(), err)
+		}
+	}
+	for _, groupGang := range gangs {
+		groupGang.setAdmitted(true)
+	}
+	klog.Infof("gangGroup is admitted, gangs: %v", gangIds)
+	return nil
+}
+
+// releaseGangGroupAdmission releases the admission of the gangGroup, so that the next round is admitted again.
+func (pgMgr *PodGroupManager) releaseGangGroupAdmission(gang *Gang) {
+	admitters := pgMgr.gangAdmitters.List()
+	if len(admitters) == 0 {
+		return
+	}
+	var gangIds []string
+	for _, groupGang := range pgMgr.getGangGroupGangs(gang) {
+		if groupGang.isAdmitted() {
+			groupGang.setAdmitted(false)
+			gangIds = append(gangIds, groupGang.Name)
+		}
+	}
+	if len(gangIds) == 0 {
+		return
+	}
+	for _, admitter := range admitters {
+		admitter.ReleaseGangGroup(gangIds)
+	}
+	klog.Infof("gangGroup admission is released, gangs: %v", gangIds)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+)
+
+type testGangAdmitter struct {
+	reject   bool
+	admitted map[string]int
+}
+
+func (a *testGangAdmitter) Name() string {
+	return "Test"
+}
+
+func (a *testGangAdmitter) AdmitGangGroup(children map[string][]*corev1.Pod, ttl time.Duration) error {
+	if a.reject {
+		return fmt.Errorf("rejected")
+	}
+	for gangId, pods := range children {
+		a.admitted[gangId] = len(pods)
+	}
+	return nil
+}
+
+func (a *testGangAdmitter) ReleaseGangGroup(gangIds []string) {
+	for _, gangId := range gangIds {
+		delete(a.admitted, gangId)
+	}
+}
+
+func TestPreFilter_GangAdmission(t *testing.T) {
+	admitter := &testGangAdmitter{reject: true, admitted: map[string]int{}}
+	mgr := NewManager4Test().pgMgr
+	mgr.gangAdmitters = frameworkext.NewGangAdmitters()
+	mgr.gangAdmitters.Register(admitter)
+	now := time.Now()
+	mgr.cache.onPodGroupAdd(makePg("ganga", "ganga_ns", 2, &now, nil))
+	pods := []*corev1.Pod{
+		st.MakePod().Name("pod1").UID("pod1").Namespace("ganga_ns").Label(v1alpha1.PodGroupLabel, "ganga").Obj(),
+		st.MakePod().Name("pod2").UID("pod2").Namespace("ganga_ns").Label(v1alpha1.PodGroupLabel, "ganga").Obj(),
+	}
+	for _, pod := range pods {
+		mgr.cache.onPodAdd(pod)
+	}
+	gang := mgr.GetGangByPod(pods[0])
+	ctx := context.TODO()
+
+	assert.Error(t, mgr.PreFilter(ctx, pods[0]))
+	assert.False(t, gang.isAdmitted())
+	assert.Empty(t, admitter.admitted)
+
+	// the gangGroup is admitted once with all the children
+	admitter.reject = false
+	assert.NoError(t, mgr.PreFilter(ctx, pods[1]))
+	assert.True(t, gang.isAdmitted())
+	assert.Equal(t, map[string]int{"ganga_ns/ganga": 2}, admitter.admitted)
+	admitter.admitted["ganga_ns/ganga"] = 0
+	assert.NoError(t, mgr.PreFilter(ctx, pods[0]))
+	assert.Equal(t, map[string]int{"ganga_ns/ganga": 0}, admitter.admitted)
+
+	mgr.releaseGangGroupAdmission(gang)
+	assert.False(t, gang.isAdmitted())
+	assert.Empty(t, admitter.admitted)
+}
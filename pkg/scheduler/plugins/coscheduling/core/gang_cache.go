@@ -18,6 +18,7 @@ package core
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -40,8 +41,10 @@ import (
 )
 
 type GangCache struct {
-	lock       *sync.RWMutex
-	gangItems  map[string]*Gang
+	lock      *sync.RWMutex
+	gangItems map[string]*Gang
+	// childGangs records the ids of the children gangs of each parent gang
+	childGangs map[string]map[string]struct{}
 	pluginArgs *config.CoschedulingArgs
 	podLister  listerv1.PodLister
 	pgLister   pglister.PodGroupLister
@@ -51,6 +54,7 @@ type GangCache struct {
 func NewGangCache(args *config.CoschedulingArgs, podLister listerv1.PodLister, pgLister pglister.PodGroupLister, client pgclientset.Interface) *GangCache {
 	return &GangCache{
 		gangItems:  make(map[string]*Gang),
+		childGangs: make(map[string]map[string]struct{}),
 		lock:       new(sync.RWMutex),
 		pluginArgs: args,
 		podLister:  podLister,
@@ -75,10 +79,73 @@ func (gangCache *GangCache) deleteGangFromCacheByGangId(gangId string) {
 	gangCache.lock.Lock()
 	defer gangCache.lock.Unlock()
 
+	if gang := gangCache.gangItems[gangId]; gang != nil {
+		if parentId := gang.getParentGang(); parentId != "" {
+			delete(gangCache.childGangs[parentId], gangId)
+			if len(gangCache.childGangs[parentId]) == 0 {
+				delete(gangCache.childGangs, parentId)
+			}
+			gangCache.refreshChildGangGroupNoLock(parentId)
+		}
+	}
 	delete(gangCache.gangItems, gangId)
 	klog.Infof("delete gang from cache, gang: %v", gangId)
 }
 
+// linkParentGang records the gang as a child of its parent gang, and bundles all the children gangs of the parent
+// as their GangGroup.
+func (gangCache *GangCache) linkParentGang(gang *Gang) {
+	parentId := gang.getParentGang()
+	if parentId == "" {
+		return
+	}
+	gangCache.lock.Lock()
+	defer gangCache.lock.Unlock()
+
+	children := gangCache.childGangs[parentId]
+	if children == nil {
+		children = make(map[string]struct{})
+		gangCache.childGangs[parentId] = children
+	}
+	if _, ok := children[gang.Name]; ok {
+		return
+	}
+	children[gang.Name] = struct{}{}
+	klog.Infof("link gang to the parent gang, gang: %v, parent: %v", gang.Name, parentId)
+	gangCache.refreshChildGangGroupNoLock(parentId)
+}
+
+func (gangCache *GangCache) refreshChildGangGroupNoLock(parentId string) {
+	gangGroup := make([]string, 0, len(gangCache.childGangs[parentId]))
+	for gangId := range gangCache.childGangs[parentId] {
+		gangGroup = append(gangGroup, gangId)
+	}
+	sort.Strings(gangGroup)
+	for _, gangId := range gangGroup {
+		if gang := gangCache.gangItems[gangId]; gang != nil {
+			gang.setGangGroup(gangGroup)
+		}
+	}
+}
+
+func (gangCache *GangCache) getChildGangNum(parentId string) int {
+	gangCache.lock.RLock()
+	defer gangCache.lock.RUnlock()
+
+	return len(gangCache.childGangs[parentId])
+}
+
+func (gangCache *GangCache) getAllGangs() []*Gang {
+	gangCache.lock.RLock()
+	defer gangCache.lock.RUnlock()
+
+	gangs := make([]*Gang, 0, len(gangCache.gangItems))
+	for _, gang := range gangCache.gangItems {
+		gangs = append(gangs, gang)
+	}
+	return gangs
+}
+
 func (gangCache *GangCache) onPodAdd(obj interface{}) {
 	pod, ok := obj.(*v1.Pod)
 	if !ok {
@@ -123,6 +190,7 @@ func (gangCache *GangCache) onPodAdd(obj interface{}) {
 		}
 	}
 	gang.setChild(pod)
+	gangCache.linkParentGang(gang)
 	if pod.Spec.NodeName != "" {
 		gang.addBoundPod(pod)
 		gang.setResourceSatisfied()
@@ -188,6 +256,7 @@ func (gangCache *GangCache) onPodGroupAdd(obj interface{}) {
 	}
 
 	gang.tryInitByPodGroup(pg, gangCache.pluginArgs)
+	gangCache.linkParentGang(gang)
 }
 
 func (gangCache *GangCache) onPodGroupUpdate(oldObj interface{}, newObj interface{}) {
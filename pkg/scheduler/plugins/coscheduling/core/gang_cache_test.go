@@ -932,3 +932,24 @@ func TestGangCache_OnGangDelete(t *testing.T) {
 	assert.Equal(t, wantedGang, cacheGang)
 
 }
+
+func TestGangCache_ParentGang(t *testing.T) {
+	pgClient := fakepgclientset.NewSimpleClientset()
+	cache := NewGangCache(&config.CoschedulingArgs{}, nil, nil, pgClient)
+
+	now := time.Now()
+	cache.onPodGroupAdd(makePg("job", "default", 2, &now, nil))
+	for _, name := range []string{"ps", "worker"} {
+		pg := makePg(name, "default", 1, &now, nil)
+		pg.Annotations = map[string]string{extension.AnnotationGangParent: "job"}
+		cache.onPodGroupAdd(pg)
+	}
+	assert.Equal(t, 2, cache.getChildGangNum("default/job"))
+	assert.Equal(t, []string{"default/ps", "default/worker"}, cache.getGangFromCacheByGangId("default/ps", false).getGangGroup())
+	assert.Equal(t, []string{"default/ps", "default/worker"}, cache.getGangFromCacheByGangId("default/worker", false).getGangGroup())
+	assert.Empty(t, cache.getGangFromCacheByGangId("default/job", false).getGangGroup())
+
+	cache.onPodGroupDelete(makePg("worker", "default", 1, &now, nil))
+	assert.Equal(t, 1, cache.getChildGangNum("default/job"))
+	assert.Equal(t, []string{"default/ps"}, cache.getGangFromCacheByGangId("default/ps", false).getGangGroup())
+}
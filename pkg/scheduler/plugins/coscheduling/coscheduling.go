@@ -20,10 +20,14 @@ package coscheduling
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	pgclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
@@ -35,6 +39,7 @@ import (
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
 	"github.com/koordinator-sh/koordinator/apis/scheduling/config/validation"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/coscheduling/controller"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/coscheduling/core"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/coscheduling/util"
@@ -81,7 +86,22 @@ func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error)
 
 	ctx := context.TODO()
 
-	pgMgr := core.NewPodGroupManager(pgClient, pgInformer, podInformer, args)
+	var gangAdmitters *frameworkext.GangAdmitters
+	stopCh := wait.NeverStop
+	if extendedHandle, ok := handle.(frameworkext.ExtendedHandle); ok {
+		gangAdmitters = extendedHandle.GangAdmitters()
+		if extendedHandle.StopCh() != nil {
+			stopCh = extendedHandle.StopCh()
+		}
+	}
+	pgMgr := core.NewPodGroupManager(pgClient, pgInformer, podInformer, args, gangAdmitters)
+	pgMgr.RunAssemblyStatusWriter(handle.EventRecorder(), stopCh)
+	handle.SharedInformerFactory().Core().V1().Nodes().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: pgMgr.OnClusterChanged,
+	})
+	podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: pgMgr.OnClusterChanged,
+	})
 	plugin := &Coscheduling{
 		frameworkHandler: handle,
 		pgMgr:            pgMgr,
@@ -111,7 +131,8 @@ func (cs *Coscheduling) EventsToRegister() []framework.ClusterEvent {
 	// https://git.k8s.io/kubernetes/pkg/scheduler/eventhandlers.go#L403-L410
 	pgGVK := fmt.Sprintf("podgroups.v1alpha1.%v", scheduling.GroupName)
 	return []framework.ClusterEvent{
-		{Resource: framework.Pod, ActionType: framework.Add},
+		{Resource: framework.Pod, ActionType: framework.Add | framework.Delete},
+		{Resource: framework.Node, ActionType: framework.Add},
 		{Resource: framework.GVK(pgGVK), ActionType: framework.Add | framework.Update},
 	}
 }
@@ -168,13 +189,29 @@ func (cs *Coscheduling) PreFilter(ctx context.Context, state *framework.CycleSta
 }
 
 // PostFilter
-// i. If strict-mode, we will set scheduleCycleValid to false and release all assumed pods.
-// ii. If non-strict mode, we will do nothing.
+// i. Record why the pod fails to be scheduled in the assembly status of the gang.
+// ii. If strict-mode, we will set scheduleCycleValid to false and release all assumed pods.
+// iii. If non-strict mode, we will record the pod as a straggler.
 func (cs *Coscheduling) PostFilter(ctx context.Context, state *framework.CycleState, pod *v1.Pod,
 	filteredNodeStatusMap framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+	if util.IsPodNeedGang(pod) {
+		cs.pgMgr.RecordGangAssemblyFailure(pod, failureReasonKey(filteredNodeStatusMap))
+	}
 	return cs.pgMgr.PostFilter(ctx, pod, cs.frameworkHandler, Name)
 }
 
+// failureReasonKey returns the sorted distinct reasons why the nodes are filtered out. Unlike FitError.Error(), it
+// has no node counts, so it stays the same across the scheduling cycles until the reasons change.
+func failureReasonKey(filteredNodeStatusMap framework.NodeToStatusMap) string {
+	reasons := sets.NewString()
+	for _, status := range filteredNodeStatusMap {
+		if status != nil {
+			reasons.Insert(status.Reasons()...)
+		}
+	}
+	return strings.Join(reasons.List(), ", ")
+}
+
 // PreFilterExtensions returns a PreFilterExtensions interface if the plugin implements one.
 func (cs *Coscheduling) PreFilterExtensions() framework.PreFilterExtensions {
 	return nil
@@ -28,6 +28,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apiruntime "k8s.io/apimachinery/pkg/runtime"
 	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	scheduledconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
@@ -46,6 +47,7 @@ import (
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
 	"github.com/koordinator-sh/koordinator/apis/scheduling/config/v1beta2"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/coscheduling/core"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/coscheduling/util"
 )
 
@@ -136,6 +138,7 @@ type pluginTestSuit struct {
 	proxyNew           runtime.PluginFactory
 	gangSchedulingArgs *config.CoschedulingArgs
 	pgClient           *fakepgclientset.Clientset
+	eventRecorder      *record.FakeRecorder
 }
 
 func newPluginTestSuit(t *testing.T, nodes []*corev1.Node) *pluginTestSuit {
@@ -168,12 +171,14 @@ func newPluginTestSuit(t *testing.T, nodes []*corev1.Node) *pluginTestSuit {
 	cs := kubefake.NewSimpleClientset()
 	informerFactory := informers.NewSharedInformerFactory(cs, 0)
 	snapshot := newTestSharedLister(nil, nodes)
+	fakeRecorder := record.NewFakeRecorder(1024)
 	fh, err := schedulertesting.NewFramework(
 		registeredPlugins,
 		"koord-scheduler",
 		runtime.WithClientSet(cs),
 		runtime.WithInformerFactory(informerFactory),
 		runtime.WithSnapshotSharedLister(snapshot),
+		runtime.WithEventRecorder(record.NewEventRecorderAdapter(fakeRecorder)),
 	)
 	assert.Nil(t, err)
 	return &pluginTestSuit{
@@ -181,6 +186,7 @@ func newPluginTestSuit(t *testing.T, nodes []*corev1.Node) *pluginTestSuit {
 		proxyNew:           proxyNew,
 		gangSchedulingArgs: &gangSchedulingArgs,
 		pgClient:           pgClientSet,
+		eventRecorder:      fakeRecorder,
 	}
 }
 
@@ -651,6 +657,10 @@ func TestPermit(t *testing.T) {
 				tmpPod := pod
 				suit.Handle.(framework.Framework).RunPermitPlugins(ctx, cycleState, tmpPod, "")
 				gp.Permit(ctx, cycleState, pod, "")
+				if !tt.needInWaitingPods {
+					// the pods are not expected to be allowed, they are rejected once the gang times out
+					continue
+				}
 				//start goroutine to wait for the waitingPod's Allow signal from Permit stage
 				go func() {
 					status := suit.Handle.(framework.Framework).WaitOnPermit(context.Background(), tmpPod)
@@ -781,3 +791,168 @@ func TestUnreserve(t *testing.T) {
 		})
 	}
 }
+
+func TestUnreserve_PartialScheduleTimeout(t *testing.T) {
+	suit := newPluginTestSuit(t, nil)
+	p, err := suit.proxyNew(suit.gangSchedulingArgs, suit.Handle)
+	assert.NotNil(t, p)
+	assert.Nil(t, err)
+	suit.start()
+	gangACreatedTime := time.Now()
+	// the NonStrict gang releases the assumed pods only when it times out
+	pg := makePg("gangA", "gangA_ns", 3, &gangACreatedTime, nil)
+	pg.Annotations = map[string]string{extension.AnnotationGangMode: extension.GangModeNonStrict}
+	_, err = suit.pgClient.SchedulingV1alpha1().PodGroups("gangA_ns").Create(context.TODO(), pg, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+
+	pod := st.MakePod().Name("pod1").UID("pod1").Namespace("gangA_ns").Label(v1alpha1.PodGroupLabel, "gangA").Obj()
+	waitingPods := []*corev1.Pod{
+		st.MakePod().Name("pod2").UID("pod2").Namespace("gangA_ns").Label(v1alpha1.PodGroupLabel, "gangA").Obj(),
+	}
+	for _, pod := range append(waitingPods, pod) {
+		_, err := suit.Handle.ClientSet().CoreV1().Pods("gangA_ns").Create(context.TODO(), pod, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	gp := p.(*Coscheduling)
+	ctx := context.TODO()
+	cycleState := framework.NewCycleState()
+	var wg sync.WaitGroup
+	wg.Add(len(waitingPods))
+	for _, waitingPod := range waitingPods {
+		tmpPod := waitingPod
+		suit.Handle.(framework.Framework).RunPermitPlugins(ctx, cycleState, tmpPod, "")
+		go func() {
+			defer wg.Done()
+			status := suit.Handle.(framework.Framework).WaitOnPermit(context.Background(), tmpPod)
+			assert.False(t, status.IsSuccess())
+		}()
+	}
+
+	// the waiting pods are kept if the gang doesn't time out
+	gp.Unreserve(ctx, cycleState, pod, "")
+	waitingNum := 0
+	suit.Handle.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
+		waitingNum++
+	})
+	assert.Equal(t, len(waitingPods), waitingNum)
+	assert.Empty(t, suit.eventRecorder.Events)
+
+	gang := gp.pgMgr.(*core.PodGroupManager).GetGangByPod(pod)
+	gang.PartialScheduleStartTime = time.Now().Add(-time.Minute)
+	gp.Unreserve(ctx, cycleState, pod, "")
+	wg.Wait()
+	assert.Len(t, suit.eventRecorder.Events, 1)
+	assert.Contains(t, <-suit.eventRecorder.Events, "GangRollback")
+}
+
+func TestPostFilter_Stragglers(t *testing.T) {
+	suit := newPluginTestSuit(t, nil)
+	p, err := suit.proxyNew(suit.gangSchedulingArgs, suit.Handle)
+	assert.NotNil(t, p)
+	assert.Nil(t, err)
+	suit.start()
+	gangACreatedTime := time.Now()
+	// the NonStrict gang tolerates one child failing to be scheduled
+	pg := makePg("ganga", "ganga_ns", 3, &gangACreatedTime, nil)
+	pg.Annotations = map[string]string{
+		extension.AnnotationGangMode:          extension.GangModeNonStrict,
+		extension.AnnotationGangMaxStragglers: "1",
+	}
+	_, err = suit.pgClient.SchedulingV1alpha1().PodGroups("ganga_ns").Create(context.TODO(), pg, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+
+	straggler := st.MakePod().Name("pod1").UID("pod1").Namespace("ganga_ns").Label(v1alpha1.PodGroupLabel, "ganga").Obj()
+	waitingPods := []*corev1.Pod{
+		st.MakePod().Name("pod2").UID("pod2").Namespace("ganga_ns").Label(v1alpha1.PodGroupLabel, "ganga").Obj(),
+		st.MakePod().Name("pod3").UID("pod3").Namespace("ganga_ns").Label(v1alpha1.PodGroupLabel, "ganga").Obj(),
+	}
+	for _, pod := range append(waitingPods, straggler) {
+		_, err := suit.Handle.ClientSet().CoreV1().Pods("ganga_ns").Create(context.TODO(), pod, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	gp := p.(*Coscheduling)
+	ctx := context.TODO()
+	cycleState := framework.NewCycleState()
+	var wg sync.WaitGroup
+	wg.Add(len(waitingPods))
+	for _, waitingPod := range waitingPods {
+		tmpPod := waitingPod
+		suit.Handle.(framework.Framework).RunPermitPlugins(ctx, cycleState, tmpPod, "")
+		gp.Permit(ctx, cycleState, tmpPod, "")
+		go func() {
+			defer wg.Done()
+			status := suit.Handle.(framework.Framework).WaitOnPermit(context.Background(), tmpPod)
+			assert.True(t, status.IsSuccess())
+		}()
+	}
+	waitingNum := 0
+	suit.Handle.IterateOverWaitingPods(func(waitingPod framework.WaitingPod) {
+		waitingNum++
+	})
+	assert.Equal(t, len(waitingPods), waitingNum)
+
+	// the rest of the gang goes to bind once the straggler fails
+	_, status := gp.PostFilter(ctx, cycleState, straggler, nil)
+	assert.Equal(t, framework.Unschedulable, status.Code())
+	wg.Wait()
+}
+
+func TestPostFilter_AssemblyStatus(t *testing.T) {
+	suit := newPluginTestSuit(t, nil)
+	p, err := suit.proxyNew(suit.gangSchedulingArgs, suit.Handle)
+	assert.NotNil(t, p)
+	assert.Nil(t, err)
+	suit.start()
+	gangACreatedTime := time.Now()
+	pg := makePg("ganga", "ganga_ns", 2, &gangACreatedTime, nil)
+	_, err = suit.pgClient.SchedulingV1alpha1().PodGroups("ganga_ns").Create(context.TODO(), pg, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+
+	pods := []*corev1.Pod{
+		st.MakePod().Name("pod1").UID("pod1").Namespace("ganga_ns").Label(v1alpha1.PodGroupLabel, "ganga").Obj(),
+		st.MakePod().Name("pod2").UID("pod2").Namespace("ganga_ns").Label(v1alpha1.PodGroupLabel, "ganga").Obj(),
+	}
+	for _, pod := range pods {
+		_, err := suit.Handle.ClientSet().CoreV1().Pods("ganga_ns").Create(context.TODO(), pod, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	gp := p.(*Coscheduling)
+	ctx := context.TODO()
+	nodeStatus := framework.NodeToStatusMap{
+		"node1": framework.NewStatus(framework.Unschedulable, "Insufficient cpu"),
+		"node2": framework.NewStatus(framework.Unschedulable, "Insufficient memory", "Insufficient cpu"),
+	}
+	_, status := gp.PostFilter(ctx, framework.NewCycleState(), pods[0], nodeStatus)
+	assert.Equal(t, framework.Unschedulable, status.Code())
+
+	// the assembly status is published outside the scheduling cycle
+	assert.Eventually(t, func() bool {
+		return len(suit.eventRecorder.Events) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Contains(t, <-suit.eventRecorder.Events, "GangAssemblyBlocked")
+	pg, err = suit.pgClient.SchedulingV1alpha1().PodGroups("ganga_ns").Get(context.TODO(), "ganga", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assemblyStatus, err := extension.GetGangAssemblyStatus(pg.Annotations)
+	assert.NoError(t, err)
+	assert.Equal(t, &extension.GangAssemblyStatus{
+		MinMember:      2,
+		Children:       2,
+		FailedChildren: map[string]string{"pod1": "Insufficient cpu, Insufficient memory"},
+	}, assemblyStatus)
+
+	// the same reasons are not recorded again even if the number of the nodes changes
+	nodeStatus["node3"] = framework.NewStatus(framework.Unschedulable, "Insufficient cpu")
+	_, status = gp.PostFilter(ctx, framework.NewCycleState(), pods[0], nodeStatus)
+	assert.Equal(t, framework.Unschedulable, status.Code())
+	time.Sleep(2 * time.Second)
+	assert.Empty(t, suit.eventRecorder.Events)
+}
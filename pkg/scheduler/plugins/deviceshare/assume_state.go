@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deviceshare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+)
+
+var (
+	_ framework.PostBindPlugin       = &Plugin{}
+	_ frameworkext.AssumeStateHolder = &Plugin{}
+)
+
+// recordAssumedAllocations records the devices allocated for the assumed pod until it's bound or unreserved,
+// so that they can be handed off to the incoming leader.
+func (g *Plugin) recordAssumedAllocations(pod *corev1.Pod, allocations apiext.DeviceAllocations) {
+	g.assumedLock.Lock()
+	defer g.assumedLock.Unlock()
+	if g.assumedAllocations == nil {
+		g.assumedAllocations = map[types.UID]apiext.DeviceAllocations{}
+	}
+	g.assumedAllocations[pod.UID] = allocations
+}
+
+func (g *Plugin) forgetAssumedAllocations(pod *corev1.Pod) {
+	g.assumedLock.Lock()
+	defer g.assumedLock.Unlock()
+	delete(g.assumedAllocations, pod.UID)
+}
+
+func (g *Plugin) PostBind(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) {
+	g.forgetAssumedAllocations(pod)
+}
+
+func (g *Plugin) ExportAssumeState(pod *corev1.Pod, nodeName string) (json.RawMessage, error) {
+	g.assumedLock.Lock()
+	defer g.assumedLock.Unlock()
+	allocations, ok := g.assumedAllocations[pod.UID]
+	if !ok || len(allocations) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(allocations)
+}
+
+func (g *Plugin) RestoreAssumeState(pod *corev1.Pod, nodeName string, hold json.RawMessage) error {
+	allocations := apiext.DeviceAllocations{}
+	if err := json.Unmarshal(hold, &allocations); err != nil {
+		return err
+	}
+	nodeDeviceInfo := g.nodeDeviceCache.getNodeDevice(nodeName)
+	if nodeDeviceInfo == nil {
+		return fmt.Errorf("node device cache not found, nodeName: %v", nodeName)
+	}
+	nodeDeviceInfo.lock.Lock()
+	defer nodeDeviceInfo.lock.Unlock()
+	nodeDeviceInfo.updateCacheUsed(allocations, pod, true)
+	g.recordAssumedAllocations(pod, allocations)
+	return nil
+}
+
+func (g *Plugin) ForgetAssumeState(pod *corev1.Pod, nodeName string, hold json.RawMessage) {
+	allocations := apiext.DeviceAllocations{}
+	if err := json.Unmarshal(hold, &allocations); err != nil {
+		return
+	}
+	g.forgetAssumedAllocations(pod)
+	nodeDeviceInfo := g.nodeDeviceCache.getNodeDevice(nodeName)
+	if nodeDeviceInfo == nil {
+		return
+	}
+	nodeDeviceInfo.lock.Lock()
+	defer nodeDeviceInfo.lock.Unlock()
+	nodeDeviceInfo.updateCacheUsed(allocations, pod, false)
+}
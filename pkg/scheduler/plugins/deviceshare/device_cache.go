@@ -18,6 +18,7 @@ package deviceshare
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
 	corev1 "k8s.io/api/core/v1"
@@ -25,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
@@ -35,6 +37,9 @@ type nodeDeviceCache struct {
 	// nodeDeviceInfos stores nodeDevice for each node
 	// and uses node name as map key.
 	nodeDeviceInfos map[string]*nodeDevice
+	// recentAllocations stores the devices allocated to the recently deleted pods,
+	// and uses node name and pod namespace/name as map key.
+	recentAllocations map[string]*recentAllocation
 }
 
 type nodeDevice struct {
@@ -43,6 +48,15 @@ type nodeDevice struct {
 	deviceFree  map[schedulingv1alpha1.DeviceType]deviceResources
 	deviceUsed  map[schedulingv1alpha1.DeviceType]deviceResources
 	allocateSet map[schedulingv1alpha1.DeviceType]map[types.NamespacedName]struct{}
+	// remoteDevices stores the attributes of the remote GPUs and uses the minor as map key,
+	// it is nil if the node has no remote GPU.
+	remoteDevices map[int]*schedulingv1alpha1.RemoteDeviceInfo
+	// deviceTopology stores the PCIe attachment of the local devices reported by koordlet,
+	// it is nil if the node doesn't report it.
+	deviceTopology map[schedulingv1alpha1.DeviceType]map[int]*schedulingv1alpha1.DeviceTopology
+	// migUUIDs stores the UUIDs of the MIG devices and uses the minor as map key,
+	// it is nil if the node has no MIG device.
+	migUUIDs map[int]string
 }
 
 // deviceResources is used to present resources per device.
@@ -191,61 +205,119 @@ func (n *nodeDevice) updateAllocateSet(allocations apiext.DeviceAllocations, pod
 	}
 }
 
-func (n *nodeDevice) tryAllocateDevice(podRequest corev1.ResourceList) (apiext.DeviceAllocations, error) {
+// tryAllocateDevice allocates the devices for the pod, the minors in preferred are tried first.
+// If the local GPUs are insufficient, the remote GPUs whose latency class is tolerated are tried.
+// The GPUs are allocated first, so the RDMA devices closest to them can be allocated.
+func (n *nodeDevice) tryAllocateDevice(podRequest corev1.ResourceList, preferred apiext.DeviceAllocations, remoteLatencyTolerance schedulingv1alpha1.DeviceLatencyClass, strategy apiext.GPUAllocationStrategy) (apiext.DeviceAllocations, error) {
 	allocateResult := make(apiext.DeviceAllocations)
 
-	for deviceType := range deviceResourceNames {
-		switch deviceType {
-		case schedulingv1alpha1.RDMA, schedulingv1alpha1.FPGA:
-			if !hasDeviceResource(podRequest, deviceType) {
-				break
-			}
-			if err := n.tryAllocateCommonDevice(podRequest, deviceType, allocateResult); err != nil {
+	if hasDeviceResource(podRequest, schedulingv1alpha1.GPU) {
+		if err := n.tryAllocateGPU(podRequest, allocateResult, preferred, strategy); err != nil {
+			if remoteLatencyTolerance == "" {
 				return nil, err
 			}
-		case schedulingv1alpha1.GPU:
-			if !hasDeviceResource(podRequest, deviceType) {
-				break
-			}
-			if err := n.tryAllocateGPU(podRequest, allocateResult); err != nil {
+			if remoteErr := n.tryAllocateRemoteGPU(podRequest, allocateResult, preferred, remoteLatencyTolerance, strategy); remoteErr != nil {
 				return nil, err
 			}
-		default:
+		}
+	}
+
+	for deviceType := range getDeviceResourceNames() {
+		if deviceType == schedulingv1alpha1.GPU {
+			// allocated above
+			continue
+		}
+		if _, ok := getCommonDeviceTypes()[deviceType]; !ok {
 			klog.Warningf("device type %v is not supported yet", deviceType)
+			continue
+		}
+		if !hasDeviceResource(podRequest, deviceType) {
+			continue
+		}
+		if err := n.tryAllocateCommonDevice(podRequest, deviceType, allocateResult, preferred); err != nil {
+			return nil, err
+		}
+	}
+
+	if migRequest := getMIGRequest(podRequest); len(migRequest) > 0 {
+		if err := n.tryAllocateMIG(migRequest, allocateResult, preferred); err != nil {
+			return nil, err
 		}
 	}
 
 	return allocateResult, nil
 }
 
-func (n *nodeDevice) tryAllocateCommonDevice(podRequest corev1.ResourceList, deviceType schedulingv1alpha1.DeviceType, allocateResult apiext.DeviceAllocations) error {
-	podRequest = quotav1.Mask(podRequest, deviceResourceNames[deviceType])
+// tryAllocateMIG allocates the whole MIG devices of the requested profiles.
+func (n *nodeDevice) tryAllocateMIG(migRequest corev1.ResourceList, allocateResult apiext.DeviceAllocations, preferred apiext.DeviceAllocations) error {
+	resourceNames := make([]corev1.ResourceName, 0, len(migRequest))
+	for resourceName := range migRequest {
+		resourceNames = append(resourceNames, resourceName)
+	}
+	sort.Slice(resourceNames, func(i, j int) bool {
+		return resourceNames[i] < resourceNames[j]
+	})
+
+	var deviceAllocations []*apiext.DeviceAllocation
+	allocated := map[int]bool{}
+	minors := sortedMinors(n.deviceFree[schedulingv1alpha1.MIG], preferred, schedulingv1alpha1.MIG)
+	for _, resourceName := range resourceNames {
+		quantity := migRequest[resourceName]
+		wanted := quantity.Value()
+		for _, minor := range minors {
+			if wanted == 0 {
+				break
+			}
+			free := n.deviceFree[schedulingv1alpha1.MIG][minor][resourceName]
+			if allocated[minor] || free.Value() < 1 {
+				continue
+			}
+			allocated[minor] = true
+			deviceAllocations = append(deviceAllocations, &apiext.DeviceAllocation{
+				Minor: int32(minor),
+				Resources: corev1.ResourceList{
+					resourceName: *resource.NewQuantity(1, resource.DecimalSI),
+				},
+				UUID: n.migUUIDs[minor],
+			})
+			wanted--
+		}
+		if wanted > 0 {
+			klog.V(5).Infof("node MIG resource does not satisfy pod's request, expect %v, lack %v", quantity.Value(), wanted)
+			return fmt.Errorf("node does not have enough %v", resourceName)
+		}
+	}
+	allocateResult[schedulingv1alpha1.MIG] = deviceAllocations
+	return nil
+}
+
+func (n *nodeDevice) tryAllocateCommonDevice(podRequest corev1.ResourceList, deviceType schedulingv1alpha1.DeviceType, allocateResult apiext.DeviceAllocations, preferred apiext.DeviceAllocations) error {
+	podRequest = quotav1.Mask(podRequest, getDeviceResourceNames()[deviceType])
 	nodeDeviceTotal := n.deviceTotal[deviceType]
 	if len(nodeDeviceTotal) <= 0 {
 		return fmt.Errorf("node does not have enough %v", deviceType)
 	}
 
+	spec, ok := getCommonDeviceTypes()[deviceType]
+	if !ok {
+		return fmt.Errorf("device type %v is not supported yet", deviceType)
+	}
+	if !spec.shareable {
+		return n.tryAllocateExclusiveDevice(podRequest, deviceType, spec, allocateResult, preferred)
+	}
+
 	var deviceAllocations []*apiext.DeviceAllocation
 
 	if isMultipleCommonDevicePod(podRequest, deviceType) {
-		var commonDeviceWanted int64
-		var podRequestPerCard corev1.ResourceList
-		switch deviceType {
-		case schedulingv1alpha1.RDMA:
-			commonDevice := podRequest[apiext.KoordRDMA]
-			commonDeviceWanted = commonDevice.Value() / 100
-			podRequestPerCard = corev1.ResourceList{
-				apiext.KoordRDMA: *resource.NewQuantity(commonDevice.Value()/commonDeviceWanted, resource.DecimalSI),
-			}
-		case schedulingv1alpha1.FPGA:
-			commonDevice := podRequest[apiext.KoordFPGA]
-			commonDeviceWanted = commonDevice.Value() / 100
-			podRequestPerCard = corev1.ResourceList{
-				apiext.KoordFPGA: *resource.NewQuantity(commonDevice.Value()/commonDeviceWanted, resource.DecimalSI),
-			}
+		resourceName := spec.resourceNames[0]
+		commonDevice := podRequest[resourceName]
+		commonDeviceWanted := commonDevice.Value() / 100
+		podRequestPerCard := corev1.ResourceList{
+			resourceName: *resource.NewQuantity(commonDevice.Value()/commonDeviceWanted, resource.DecimalSI),
 		}
 		satisfiedDeviceCount := 0
-		for minor, resources := range n.deviceFree[deviceType] {
+		for _, minor := range n.sortedMinorsByAffinity(deviceType, preferred, allocateResult) {
+			resources := n.deviceFree[deviceType][minor]
 			if satisfied, _ := quotav1.LessThanOrEqual(podRequestPerCard, resources); satisfied {
 				satisfiedDeviceCount++
 				deviceAllocations = append(deviceAllocations, &apiext.DeviceAllocation{
@@ -262,7 +334,8 @@ func (n *nodeDevice) tryAllocateCommonDevice(podRequest corev1.ResourceList, dev
 		return fmt.Errorf("node does not have enough %v", deviceType)
 	}
 
-	for minor, resources := range n.deviceFree[deviceType] {
+	for _, minor := range n.sortedMinorsByAffinity(deviceType, preferred, allocateResult) {
+		resources := n.deviceFree[deviceType][minor]
 		if satisfied, _ := quotav1.LessThanOrEqual(podRequest, resources); satisfied {
 			deviceAllocations = append(deviceAllocations, &apiext.DeviceAllocation{
 				Minor:     int32(minor),
@@ -276,9 +349,57 @@ func (n *nodeDevice) tryAllocateCommonDevice(podRequest corev1.ResourceList, dev
 	return fmt.Errorf("node does not have enough %v", deviceType)
 }
 
-func (n *nodeDevice) tryAllocateGPU(podRequest corev1.ResourceList, allocateResult apiext.DeviceAllocations) error {
-	podRequest = quotav1.Mask(podRequest, deviceResourceNames[schedulingv1alpha1.GPU])
-	nodeDeviceTotal := n.deviceTotal[schedulingv1alpha1.GPU]
+// tryAllocateExclusiveDevice allocates the number of whole devices requested, the devices used by any pod are skipped.
+func (n *nodeDevice) tryAllocateExclusiveDevice(podRequest corev1.ResourceList, deviceType schedulingv1alpha1.DeviceType, spec *commonDeviceType, allocateResult apiext.DeviceAllocations, preferred apiext.DeviceAllocations) error {
+	quantity := podRequest[spec.resourceNames[0]]
+	wanted := quantity.Value()
+	var deviceAllocations []*apiext.DeviceAllocation
+	for _, minor := range n.sortedMinorsByAffinity(deviceType, preferred, allocateResult) {
+		if int64(len(deviceAllocations)) == wanted {
+			break
+		}
+		total := n.deviceTotal[deviceType][minor]
+		if quotav1.IsZero(total) || !quotav1.Equals(n.deviceFree[deviceType][minor], total) {
+			continue
+		}
+		deviceAllocations = append(deviceAllocations, &apiext.DeviceAllocation{
+			Minor:     int32(minor),
+			Resources: total.DeepCopy(),
+		})
+	}
+	if int64(len(deviceAllocations)) < wanted {
+		klog.V(5).Infof("node resource does not satisfy pod's %v request, expect %v, got %v", deviceType, wanted, len(deviceAllocations))
+		return fmt.Errorf("node does not have enough %v", deviceType)
+	}
+	allocateResult[deviceType] = deviceAllocations
+	return nil
+}
+
+func (n *nodeDevice) tryAllocateGPU(podRequest corev1.ResourceList, allocateResult apiext.DeviceAllocations, preferred apiext.DeviceAllocations, strategy apiext.GPUAllocationStrategy) error {
+	return n.tryAllocateGPUOfType(schedulingv1alpha1.GPU, podRequest, allocateResult, preferred, strategy, nil)
+}
+
+// tryAllocateRemoteGPU allocates the remote GPUs whose latency class is tolerated,
+// and sets the endpoints of the allocated GPUs to attach them.
+func (n *nodeDevice) tryAllocateRemoteGPU(podRequest corev1.ResourceList, allocateResult apiext.DeviceAllocations, preferred apiext.DeviceAllocations, latencyTolerance schedulingv1alpha1.DeviceLatencyClass, strategy apiext.GPUAllocationStrategy) error {
+	tolerated := func(minor int) bool {
+		remote := n.remoteDevices[minor]
+		return remote != nil && apiext.IsDeviceLatencyTolerated(remote.LatencyClass, latencyTolerance)
+	}
+	if err := n.tryAllocateGPUOfType(schedulingv1alpha1.RemoteGPU, podRequest, allocateResult, preferred, strategy, tolerated); err != nil {
+		return err
+	}
+	for _, allocation := range allocateResult[schedulingv1alpha1.RemoteGPU] {
+		allocation.Endpoint = n.remoteDevices[int(allocation.Minor)].Endpoint
+	}
+	return nil
+}
+
+// tryAllocateGPUOfType allocates the GPUs of the deviceType, only the minors accepted by the filter are allocated if it is not nil.
+// The partial-GPU request is placed according to the strategy.
+func (n *nodeDevice) tryAllocateGPUOfType(deviceType schedulingv1alpha1.DeviceType, podRequest corev1.ResourceList, allocateResult apiext.DeviceAllocations, preferred apiext.DeviceAllocations, strategy apiext.GPUAllocationStrategy, filter func(minor int) bool) error {
+	podRequest = quotav1.Mask(podRequest, getDeviceResourceNames()[schedulingv1alpha1.GPU])
+	nodeDeviceTotal := n.deviceTotal[deviceType]
 	if len(nodeDeviceTotal) <= 0 {
 		return fmt.Errorf("node does not have enough GPU")
 	}
@@ -295,7 +416,11 @@ func (n *nodeDevice) tryAllocateGPU(podRequest corev1.ResourceList, allocateResu
 			apiext.GPUMemoryRatio: *resource.NewQuantity(gpuMemRatio.Value()/gpuWanted, resource.DecimalSI),
 		}
 		satisfiedDeviceCount := 0
-		for minor, resources := range n.deviceFree[schedulingv1alpha1.GPU] {
+		for _, minor := range sortedMinors(n.deviceFree[deviceType], preferred, deviceType) {
+			if filter != nil && !filter(minor) {
+				continue
+			}
+			resources := n.deviceFree[deviceType][minor]
 			if satisfied, _ := quotav1.LessThanOrEqual(podRequestPerCard, resources); satisfied {
 				satisfiedDeviceCount++
 				deviceAllocations = append(deviceAllocations, &apiext.DeviceAllocation{
@@ -304,23 +429,160 @@ func (n *nodeDevice) tryAllocateGPU(podRequest corev1.ResourceList, allocateResu
 				})
 			}
 			if satisfiedDeviceCount == int(gpuWanted) {
-				allocateResult[schedulingv1alpha1.GPU] = deviceAllocations
+				allocateResult[deviceType] = deviceAllocations
 				return nil
 			}
 		}
 		klog.V(5).Infof("node GPU resource does not satisfy pod's multiple GPU request, expect %v, got %v", gpuWanted, satisfiedDeviceCount)
 		return fmt.Errorf("node does not have enough GPU")
 	}
-	for minor, resources := range n.deviceFree[schedulingv1alpha1.GPU] {
+	for _, minor := range n.sortedGPUMinorsByStrategy(deviceType, preferred, strategy) {
+		if filter != nil && !filter(minor) {
+			continue
+		}
+		resources := n.deviceFree[deviceType][minor]
 		if satisfied, _ := quotav1.LessThanOrEqual(podRequest, resources); satisfied {
 			deviceAllocations = append(deviceAllocations, &apiext.DeviceAllocation{
 				Minor:     int32(minor),
 				Resources: podRequest,
 			})
-			allocateResult[schedulingv1alpha1.GPU] = deviceAllocations
+			allocateResult[deviceType] = deviceAllocations
 			return nil
 		}
 	}
 	klog.V(5).Infof("node GPU resource does not satisfy pod's request")
 	return fmt.Errorf("node does not have enough GPU")
 }
+
+// sortedGPUMinorsByStrategy returns the minors of the GPUs, the minors in preferred come first, then the GPUs are
+// sorted by the free gpu-core according to the strategy, the most used GPUs come first for Binpack and the least
+// used GPUs come first for Spread.
+func (n *nodeDevice) sortedGPUMinorsByStrategy(deviceType schedulingv1alpha1.DeviceType, preferred apiext.DeviceAllocations, strategy apiext.GPUAllocationStrategy) []int {
+	minors := sortedMinors(n.deviceFree[deviceType], preferred, deviceType)
+	if strategy == "" {
+		return minors
+	}
+	isPreferred := map[int]bool{}
+	for _, allocation := range preferred[deviceType] {
+		isPreferred[int(allocation.Minor)] = true
+	}
+	freeCore := make(map[int]int64, len(minors))
+	for _, minor := range minors {
+		free := n.deviceFree[deviceType][minor][apiext.GPUCore]
+		freeCore[minor] = free.Value()
+	}
+	sort.SliceStable(minors, func(i, j int) bool {
+		if isPreferred[minors[i]] != isPreferred[minors[j]] {
+			return isPreferred[minors[i]]
+		}
+		if strategy == apiext.GPUAllocationStrategySpread {
+			return freeCore[minors[i]] > freeCore[minors[j]]
+		}
+		return freeCore[minors[i]] < freeCore[minors[j]]
+	})
+	return minors
+}
+
+// scoreGPUAllocation returns the score of the allocated GPUs according to the strategy, the node where the
+// GPUs are most used after the allocation gets the highest score for Binpack, and the least used for Spread.
+func (n *nodeDevice) scoreGPUAllocation(allocateResult apiext.DeviceAllocations, strategy apiext.GPUAllocationStrategy) int64 {
+	var score, count int64
+	for _, deviceType := range []schedulingv1alpha1.DeviceType{schedulingv1alpha1.GPU, schedulingv1alpha1.RemoteGPU} {
+		for _, allocation := range allocateResult[deviceType] {
+			total := n.deviceTotal[deviceType][int(allocation.Minor)][apiext.GPUCore]
+			if total.Value() <= 0 {
+				continue
+			}
+			free := n.deviceFree[deviceType][int(allocation.Minor)][apiext.GPUCore]
+			allocated := allocation.Resources[apiext.GPUCore]
+			used := total.Value() - free.Value() + allocated.Value()
+			if strategy == apiext.GPUAllocationStrategySpread {
+				used = total.Value() - used
+			}
+			score += used * framework.MaxNodeScore / total.Value()
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return score / count
+}
+
+const (
+	// topologyAffinityNone means the device is on the other NUMA Node of the allocated GPUs
+	topologyAffinityNone = iota
+	// topologyAffinityNUMANode means the device is on the same NUMA Node with one of the allocated GPUs
+	topologyAffinityNUMANode
+	// topologyAffinityPCIe means the device is under the same PCIe switch with one of the allocated GPUs
+	topologyAffinityPCIe
+)
+
+// topologyAffinity returns how close the device is to the allocated GPUs.
+func (n *nodeDevice) topologyAffinity(deviceType schedulingv1alpha1.DeviceType, minor int, allocateResult apiext.DeviceAllocations) int {
+	topology := n.deviceTopology[deviceType][minor]
+	if topology == nil {
+		return topologyAffinityNone
+	}
+	affinity := topologyAffinityNone
+	for _, allocation := range allocateResult[schedulingv1alpha1.GPU] {
+		gpuTopology := n.deviceTopology[schedulingv1alpha1.GPU][int(allocation.Minor)]
+		if gpuTopology == nil {
+			continue
+		}
+		if topology.PCIEID != "" && topology.PCIEID == gpuTopology.PCIEID {
+			return topologyAffinityPCIe
+		}
+		if topology.NodeID >= 0 && topology.NodeID == gpuTopology.NodeID {
+			affinity = topologyAffinityNUMANode
+		}
+	}
+	return affinity
+}
+
+// sortedMinorsByAffinity returns the minors of the deviceType, the minors in preferred come first,
+// then the topology-aware devices, e.g. RDMA, are sorted by the affinity to the allocated GPUs to minimize
+// the cross-socket traffic.
+func (n *nodeDevice) sortedMinorsByAffinity(deviceType schedulingv1alpha1.DeviceType, preferred apiext.DeviceAllocations, allocateResult apiext.DeviceAllocations) []int {
+	minors := sortedMinors(n.deviceFree[deviceType], preferred, deviceType)
+	if !isTopologyAwareDevice(deviceType) || len(allocateResult[schedulingv1alpha1.GPU]) == 0 {
+		return minors
+	}
+	isPreferred := map[int]bool{}
+	for _, allocation := range preferred[deviceType] {
+		isPreferred[int(allocation.Minor)] = true
+	}
+	affinity := make(map[int]int, len(minors))
+	for _, minor := range minors {
+		affinity[minor] = n.topologyAffinity(deviceType, minor, allocateResult)
+	}
+	sort.SliceStable(minors, func(i, j int) bool {
+		if isPreferred[minors[i]] != isPreferred[minors[j]] {
+			return isPreferred[minors[i]]
+		}
+		return affinity[minors[i]] > affinity[minors[j]]
+	})
+	return minors
+}
+
+// scoreTopology returns the score of the allocated topology-aware devices, e.g. RDMA, by their affinity to the
+// allocated GPUs.
+func (n *nodeDevice) scoreTopology(allocateResult apiext.DeviceAllocations) int64 {
+	if len(allocateResult[schedulingv1alpha1.GPU]) == 0 {
+		return 0
+	}
+	var affinity, count int64
+	for deviceType, allocations := range allocateResult {
+		if !isTopologyAwareDevice(deviceType) {
+			continue
+		}
+		for _, allocation := range allocations {
+			affinity += int64(n.topologyAffinity(deviceType, int(allocation.Minor), allocateResult))
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return affinity * framework.MaxNodeScore / (topologyAffinityPCIe * count)
+}
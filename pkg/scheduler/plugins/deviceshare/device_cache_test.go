@@ -20,8 +20,13 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingconfig "github.com/koordinator-sh/koordinator/apis/scheduling/config"
 	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 )
 
@@ -41,3 +46,315 @@ func Test_newNodeDevice(t *testing.T) {
 	}
 	assert.Equal(t, expectNodeDevice, newNodeDevice())
 }
+
+func Test_nodeDevice_tryAllocateRemoteGPU(t *testing.T) {
+	gpuResources := corev1.ResourceList{
+		apiext.GPUCore:        resource.MustParse("100"),
+		apiext.GPUMemoryRatio: resource.MustParse("100"),
+		apiext.GPUMemory:      resource.MustParse("16Gi"),
+	}
+	deviceCache := newNodeDeviceCache()
+	deviceCache.update("test-node-1", &schedulingv1alpha1.Device{
+		Spec: schedulingv1alpha1.DeviceSpec{
+			Devices: []schedulingv1alpha1.DeviceInfo{
+				{Type: schedulingv1alpha1.GPU, Minor: 0, Health: true, Resources: gpuResources},
+				{
+					Type: schedulingv1alpha1.RemoteGPU, Minor: 0, Health: true, Resources: gpuResources,
+					Remote: &schedulingv1alpha1.RemoteDeviceInfo{Endpoint: "10.0.0.1:9999", LatencyClass: schedulingv1alpha1.DeviceLatencyClassHigh},
+				},
+				{
+					Type: schedulingv1alpha1.RemoteGPU, Minor: 1, Health: true, Resources: gpuResources,
+					Remote: &schedulingv1alpha1.RemoteDeviceInfo{Endpoint: "10.0.0.2:9999", LatencyClass: schedulingv1alpha1.DeviceLatencyClassLow},
+				},
+			},
+		},
+	})
+	nd := deviceCache.getNodeDevice("test-node-1")
+	podRequest := corev1.ResourceList{
+		apiext.GPUCore:        resource.MustParse("100"),
+		apiext.GPUMemoryRatio: resource.MustParse("100"),
+	}
+
+	// the local GPU is allocated first
+	allocations, err := nd.tryAllocateDevice(podRequest, nil, schedulingv1alpha1.DeviceLatencyClassHigh, "")
+	assert.NoError(t, err)
+	assert.Len(t, allocations[schedulingv1alpha1.GPU], 1)
+	assert.Empty(t, allocations[schedulingv1alpha1.RemoteGPU])
+	nd.updateCacheUsed(allocations, &corev1.Pod{}, true)
+
+	// the pod not tolerating the remote GPUs can't be allocated
+	_, err = nd.tryAllocateDevice(podRequest, nil, "", "")
+	assert.Error(t, err)
+
+	// only the remote GPU with the tolerated latency class is allocated
+	allocations, err = nd.tryAllocateDevice(podRequest, nil, schedulingv1alpha1.DeviceLatencyClassMedium, "")
+	assert.NoError(t, err)
+	assert.Empty(t, allocations[schedulingv1alpha1.GPU])
+	assert.Len(t, allocations[schedulingv1alpha1.RemoteGPU], 1)
+	assert.Equal(t, int32(1), allocations[schedulingv1alpha1.RemoteGPU][0].Minor)
+	assert.Equal(t, "10.0.0.2:9999", allocations[schedulingv1alpha1.RemoteGPU][0].Endpoint)
+}
+
+func Test_nodeDevice_tryAllocateRDMAWithTopology(t *testing.T) {
+	gpuResources := corev1.ResourceList{
+		apiext.GPUCore:        resource.MustParse("100"),
+		apiext.GPUMemoryRatio: resource.MustParse("100"),
+		apiext.GPUMemory:      resource.MustParse("16Gi"),
+	}
+	rdmaResources := corev1.ResourceList{
+		apiext.KoordRDMA: resource.MustParse("100"),
+	}
+	deviceCache := newNodeDeviceCache()
+	deviceCache.update("test-node-1", &schedulingv1alpha1.Device{
+		Spec: schedulingv1alpha1.DeviceSpec{
+			Devices: []schedulingv1alpha1.DeviceInfo{
+				{
+					Type: schedulingv1alpha1.GPU, Minor: 0, Health: true, Resources: gpuResources,
+					Topology: &schedulingv1alpha1.DeviceTopology{NodeID: 0, PCIEID: "0000:3a:00.0"},
+				},
+				{
+					Type: schedulingv1alpha1.GPU, Minor: 1, Health: true, Resources: gpuResources,
+					Topology: &schedulingv1alpha1.DeviceTopology{NodeID: 1, PCIEID: "0000:d7:00.0"},
+				},
+				{
+					Type: schedulingv1alpha1.RDMA, Minor: 0, Health: true, Resources: rdmaResources,
+					Topology: &schedulingv1alpha1.DeviceTopology{NodeID: 1, PCIEID: "0000:d7:00.0"},
+				},
+				{
+					Type: schedulingv1alpha1.RDMA, Minor: 1, Health: true, Resources: rdmaResources,
+					Topology: &schedulingv1alpha1.DeviceTopology{NodeID: 0, PCIEID: "0000:5d:00.0"},
+				},
+				{
+					Type: schedulingv1alpha1.RDMA, Minor: 2, Health: true, Resources: rdmaResources,
+					Topology: &schedulingv1alpha1.DeviceTopology{NodeID: 0, PCIEID: "0000:3a:00.0"},
+				},
+			},
+		},
+	})
+	nd := deviceCache.getNodeDevice("test-node-1")
+	podRequest := corev1.ResourceList{
+		apiext.GPUCore:        resource.MustParse("100"),
+		apiext.GPUMemoryRatio: resource.MustParse("100"),
+		apiext.KoordRDMA:      resource.MustParse("100"),
+	}
+
+	// the RDMA device under the same PCIe switch with the GPU is allocated
+	allocations, err := nd.tryAllocateDevice(podRequest, nil, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), allocations[schedulingv1alpha1.GPU][0].Minor)
+	assert.Equal(t, int32(2), allocations[schedulingv1alpha1.RDMA][0].Minor)
+	assert.Equal(t, int64(100), nd.scoreTopology(allocations))
+
+	// the RDMA device on the same NUMA Node is allocated if the one under the same PCIe switch is used
+	nd.updateCacheUsed(apiext.DeviceAllocations{
+		schedulingv1alpha1.RDMA: allocations[schedulingv1alpha1.RDMA],
+	}, &corev1.Pod{}, true)
+	allocations, err = nd.tryAllocateDevice(podRequest, nil, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), allocations[schedulingv1alpha1.GPU][0].Minor)
+	assert.Equal(t, int32(1), allocations[schedulingv1alpha1.RDMA][0].Minor)
+	assert.Equal(t, int64(50), nd.scoreTopology(allocations))
+}
+
+func Test_nodeDevice_tryAllocateGPUWithStrategy(t *testing.T) {
+	gpuResources := corev1.ResourceList{
+		apiext.GPUCore:        resource.MustParse("100"),
+		apiext.GPUMemoryRatio: resource.MustParse("100"),
+		apiext.GPUMemory:      resource.MustParse("16Gi"),
+	}
+	deviceCache := newNodeDeviceCache()
+	deviceCache.update("test-node-1", &schedulingv1alpha1.Device{
+		Spec: schedulingv1alpha1.DeviceSpec{
+			Devices: []schedulingv1alpha1.DeviceInfo{
+				{Type: schedulingv1alpha1.GPU, Minor: 0, Health: true, Resources: gpuResources},
+				{Type: schedulingv1alpha1.GPU, Minor: 1, Health: true, Resources: gpuResources},
+				{Type: schedulingv1alpha1.GPU, Minor: 2, Health: true, Resources: gpuResources},
+			},
+		},
+	})
+	nd := deviceCache.getNodeDevice("test-node-1")
+	used := func(minor int32, percent string) *apiext.DeviceAllocation {
+		return &apiext.DeviceAllocation{
+			Minor: minor,
+			Resources: corev1.ResourceList{
+				apiext.GPUCore:        resource.MustParse(percent),
+				apiext.GPUMemoryRatio: resource.MustParse(percent),
+			},
+		}
+	}
+	nd.updateCacheUsed(apiext.DeviceAllocations{
+		schedulingv1alpha1.GPU: {used(1, "30"), used(2, "60")},
+	}, &corev1.Pod{}, true)
+
+	podRequest := corev1.ResourceList{
+		apiext.GPUCore:        resource.MustParse("30"),
+		apiext.GPUMemoryRatio: resource.MustParse("30"),
+	}
+
+	// the most used GPU which still fits is packed
+	allocations, err := nd.tryAllocateDevice(podRequest, nil, "", apiext.GPUAllocationStrategyBinpack)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), allocations[schedulingv1alpha1.GPU][0].Minor)
+	assert.Equal(t, int64(90), nd.scoreGPUAllocation(allocations, apiext.GPUAllocationStrategyBinpack))
+
+	// the least used GPU is chosen to minimize the interference
+	allocations, err = nd.tryAllocateDevice(podRequest, nil, "", apiext.GPUAllocationStrategySpread)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), allocations[schedulingv1alpha1.GPU][0].Minor)
+	assert.Equal(t, int64(70), nd.scoreGPUAllocation(allocations, apiext.GPUAllocationStrategySpread))
+
+	// the preferred GPU comes first regardless of the strategy
+	preferred := apiext.DeviceAllocations{schedulingv1alpha1.GPU: {used(1, "30")}}
+	allocations, err = nd.tryAllocateDevice(podRequest, preferred, "", apiext.GPUAllocationStrategyBinpack)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), allocations[schedulingv1alpha1.GPU][0].Minor)
+}
+
+func Test_nodeDevice_tryAllocateMIG(t *testing.T) {
+	mig1g := apiext.MIGResourceName("1g.5gb")
+	mig3g := apiext.MIGResourceName("3g.20gb")
+	deviceCache := newNodeDeviceCache()
+	deviceCache.update("test-node-1", &schedulingv1alpha1.Device{
+		Spec: schedulingv1alpha1.DeviceSpec{
+			Devices: []schedulingv1alpha1.DeviceInfo{
+				{
+					Type: schedulingv1alpha1.MIG, UUID: "MIG-0", Minor: 0, Health: true,
+					Resources: corev1.ResourceList{mig3g: resource.MustParse("1")},
+					MIG:       &schedulingv1alpha1.MIGDeviceInfo{ParentMinor: 0, Profile: "3g.20gb"},
+				},
+				{
+					Type: schedulingv1alpha1.MIG, UUID: "MIG-1", Minor: 1, Health: true,
+					Resources: corev1.ResourceList{mig1g: resource.MustParse("1")},
+					MIG:       &schedulingv1alpha1.MIGDeviceInfo{ParentMinor: 0, Profile: "1g.5gb"},
+				},
+				{
+					Type: schedulingv1alpha1.MIG, UUID: "MIG-2", Minor: 2, Health: true,
+					Resources: corev1.ResourceList{mig1g: resource.MustParse("1")},
+					MIG:       &schedulingv1alpha1.MIGDeviceInfo{ParentMinor: 0, Profile: "1g.5gb"},
+				},
+			},
+		},
+	})
+	nd := deviceCache.getNodeDevice("test-node-1")
+
+	allocations, err := nd.tryAllocateDevice(corev1.ResourceList{
+		mig1g: resource.MustParse("1"),
+		mig3g: resource.MustParse("1"),
+	}, nil, "", "")
+	assert.NoError(t, err)
+	expected := apiext.DeviceAllocations{
+		schedulingv1alpha1.MIG: {
+			{
+				Minor:     1,
+				Resources: corev1.ResourceList{mig1g: *resource.NewQuantity(1, resource.DecimalSI)},
+				UUID:      "MIG-1",
+			},
+			{
+				Minor:     0,
+				Resources: corev1.ResourceList{mig3g: *resource.NewQuantity(1, resource.DecimalSI)},
+				UUID:      "MIG-0",
+			},
+		},
+	}
+	assert.Equal(t, expected, allocations)
+
+	// the allocated MIG device can't be allocated again
+	nd.updateCacheUsed(allocations, &corev1.Pod{}, true)
+	allocations, err = nd.tryAllocateDevice(corev1.ResourceList{mig1g: resource.MustParse("1")}, nil, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "MIG-2", allocations[schedulingv1alpha1.MIG][0].UUID)
+
+	_, err = nd.tryAllocateDevice(corev1.ResourceList{mig3g: resource.MustParse("1")}, nil, "", "")
+	assert.Error(t, err)
+}
+
+func Test_nodeDevice_tryAllocateCustomDevice(t *testing.T) {
+	npu := schedulingv1alpha1.DeviceType("npu")
+	npuResource := corev1.ResourceName("example.com/npu")
+	npuAlias := corev1.ResourceName("example.com/npu-legacy")
+	defer deviceTypes.Store(getDeviceTypes())
+	assert.NoError(t, registerCustomDeviceTypes([]schedulingconfig.CustomDeviceType{
+		{
+			Type:          string(npu),
+			ResourceNames: []corev1.ResourceName{npuResource, npuAlias},
+			TopologyAware: true,
+		},
+	}))
+	// the types shared with the other components are never modified
+	assert.NotContains(t, apiext.DeviceResourceNames, npu)
+	// the same declaration can be registered again by the other profiles
+	assert.NoError(t, registerCustomDeviceTypes([]schedulingconfig.CustomDeviceType{
+		{
+			Type:          string(npu),
+			ResourceNames: []corev1.ResourceName{npuResource, npuAlias},
+			TopologyAware: true,
+		},
+	}))
+	assert.Error(t, registerCustomDeviceTypes([]schedulingconfig.CustomDeviceType{
+		{
+			Type:          string(npu),
+			ResourceNames: []corev1.ResourceName{npuResource},
+			Shareable:     true,
+		},
+	}))
+
+	gpuResources := corev1.ResourceList{
+		apiext.GPUCore:        resource.MustParse("100"),
+		apiext.GPUMemoryRatio: resource.MustParse("100"),
+		apiext.GPUMemory:      resource.MustParse("16Gi"),
+	}
+	npuResources := corev1.ResourceList{
+		npuResource: resource.MustParse("1"),
+	}
+	deviceCache := newNodeDeviceCache()
+	deviceCache.update("test-node-1", &schedulingv1alpha1.Device{
+		Spec: schedulingv1alpha1.DeviceSpec{
+			Devices: []schedulingv1alpha1.DeviceInfo{
+				{
+					Type: schedulingv1alpha1.GPU, Minor: 0, Health: true, Resources: gpuResources,
+					Topology: &schedulingv1alpha1.DeviceTopology{NodeID: 1, PCIEID: "0000:d7:00.0"},
+				},
+				{
+					Type: npu, Minor: 0, Health: true, Resources: npuResources,
+					Topology: &schedulingv1alpha1.DeviceTopology{NodeID: 0, PCIEID: "0000:3a:00.0"},
+				},
+				{
+					Type: npu, Minor: 1, Health: true, Resources: npuResources,
+					Topology: &schedulingv1alpha1.DeviceTopology{NodeID: 1, PCIEID: "0000:d8:00.0"},
+				},
+				{
+					Type: npu, Minor: 2, Health: true, Resources: npuResources,
+					Topology: &schedulingv1alpha1.DeviceTopology{NodeID: 1, PCIEID: "0000:d7:00.0"},
+				},
+			},
+		},
+	})
+	nd := deviceCache.getNodeDevice("test-node-1")
+
+	// the alias is converted to the resource reported by the devices
+	podRequest := corev1.ResourceList{
+		apiext.GPUCore:        resource.MustParse("100"),
+		apiext.GPUMemoryRatio: resource.MustParse("100"),
+		npuAlias:              resource.MustParse("2"),
+	}
+	assert.NoError(t, validateCommonDeviceRequest(podRequest, npu))
+	podRequest = quotav1.Add(quotav1.Mask(podRequest, getDeviceResourceNames()[schedulingv1alpha1.GPU]), convertCommonDeviceResource(podRequest, npu))
+	npuRequest := podRequest[npuResource]
+	assert.Equal(t, int64(2), npuRequest.Value())
+
+	// the whole devices closest to the GPU are allocated
+	allocations, err := nd.tryAllocateDevice(podRequest, nil, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []*apiext.DeviceAllocation{
+		{Minor: 2, Resources: npuResources},
+		{Minor: 1, Resources: npuResources},
+	}, allocations[npu])
+	assert.Equal(t, int64(75), nd.scoreTopology(allocations))
+
+	// the used devices are not shared
+	nd.updateCacheUsed(apiext.DeviceAllocations{npu: allocations[npu]}, &corev1.Pod{}, true)
+	_, err = nd.tryAllocateDevice(podRequest, nil, "", "")
+	assert.Error(t, err)
+
+	assert.Error(t, validateCommonDeviceRequest(corev1.ResourceList{npuResource: resource.MustParse("500m")}, npu))
+}
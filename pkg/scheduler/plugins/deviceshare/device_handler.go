@@ -85,12 +85,39 @@ func (n *nodeDeviceCache) update(nodeName string, device *schedulingv1alpha1.Dev
 	defer info.lock.Unlock()
 
 	nodeDeviceResource := map[schedulingv1alpha1.DeviceType]deviceResources{}
+	var remoteDevices map[int]*schedulingv1alpha1.RemoteDeviceInfo
+	var deviceTopology map[schedulingv1alpha1.DeviceType]map[int]*schedulingv1alpha1.DeviceTopology
+	var migUUIDs map[int]string
 	for _, deviceInfo := range device.Spec.Devices {
 		if nodeDeviceResource[deviceInfo.Type] == nil {
 			nodeDeviceResource[deviceInfo.Type] = make(deviceResources)
 		}
 		nodeDeviceResource[deviceInfo.Type][int(deviceInfo.Minor)] = deviceInfo.Resources
+		if deviceInfo.Type == schedulingv1alpha1.RemoteGPU && deviceInfo.Remote != nil {
+			if remoteDevices == nil {
+				remoteDevices = map[int]*schedulingv1alpha1.RemoteDeviceInfo{}
+			}
+			remoteDevices[int(deviceInfo.Minor)] = deviceInfo.Remote.DeepCopy()
+		}
+		if deviceInfo.Type == schedulingv1alpha1.MIG {
+			if migUUIDs == nil {
+				migUUIDs = map[int]string{}
+			}
+			migUUIDs[int(deviceInfo.Minor)] = deviceInfo.UUID
+		}
+		if deviceInfo.Topology != nil {
+			if deviceTopology == nil {
+				deviceTopology = map[schedulingv1alpha1.DeviceType]map[int]*schedulingv1alpha1.DeviceTopology{}
+			}
+			if deviceTopology[deviceInfo.Type] == nil {
+				deviceTopology[deviceInfo.Type] = map[int]*schedulingv1alpha1.DeviceTopology{}
+			}
+			deviceTopology[deviceInfo.Type][int(deviceInfo.Minor)] = deviceInfo.Topology.DeepCopy()
+		}
 	}
 
 	info.resetDeviceTotal(nodeDeviceResource)
+	info.remoteDevices = remoteDevices
+	info.deviceTopology = deviceTopology
+	info.migUUIDs = migUUIDs
 }
@@ -19,6 +19,7 @@ package deviceshare
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -27,10 +28,14 @@ import (
 	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
-	"k8s.io/kubernetes/pkg/api/v1/resource"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
+	pgclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	pgformers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+	schedlister "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingconfig "github.com/koordinator-sh/koordinator/apis/scheduling/config"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config/validation"
 	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
 	"github.com/koordinator-sh/koordinator/pkg/util"
@@ -53,11 +58,19 @@ const (
 type Plugin struct {
 	handle          framework.Handle
 	nodeDeviceCache *nodeDeviceCache
+	// quotaLister is used to get the gpu allocation strategy of the ElasticQuota, it is nil if the
+	// ElasticQuota can't be listed.
+	quotaLister schedlister.ElasticQuotaLister
+
+	assumedLock sync.Mutex
+	// assumedAllocations are the devices allocated for the assumed pods, keyed by the pod UID
+	assumedAllocations map[types.UID]apiext.DeviceAllocations
 }
 
 var (
 	_ framework.PreFilterPlugin = &Plugin{}
 	_ framework.FilterPlugin    = &Plugin{}
+	_ framework.ScorePlugin     = &Plugin{}
 	_ framework.ReservePlugin   = &Plugin{}
 	_ framework.PreBindPlugin   = &Plugin{}
 )
@@ -66,6 +79,10 @@ type preFilterState struct {
 	skip                    bool
 	allocationResult        apiext.DeviceAllocations
 	convertedDeviceResource corev1.ResourceList
+	// remoteDeviceLatencyTolerance is the highest latency class of the remote devices the pod tolerates
+	remoteDeviceLatencyTolerance schedulingv1alpha1.DeviceLatencyClass
+	// gpuAllocationStrategy decides whether the partial-GPU request is packed or spread
+	gpuAllocationStrategy apiext.GPUAllocationStrategy
 }
 
 func (s *preFilterState) Clone() framework.StateData {
@@ -78,13 +95,14 @@ func (g *Plugin) Name() string {
 
 func (g *Plugin) PreFilter(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod) *framework.Status {
 	state := &preFilterState{
-		skip:                    true,
-		convertedDeviceResource: make(corev1.ResourceList),
+		skip:                         true,
+		convertedDeviceResource:      make(corev1.ResourceList),
+		remoteDeviceLatencyTolerance: apiext.GetRemoteDeviceLatencyTolerance(pod.Annotations),
 	}
 
-	podRequest, _ := resource.PodRequestsAndLimits(pod)
+	podRequest := getPodDeviceRequest(pod)
 
-	for deviceType := range deviceResourceNames {
+	for deviceType := range getDeviceResourceNames() {
 		switch deviceType {
 		case schedulingv1alpha1.GPU:
 			if !hasDeviceResource(podRequest, deviceType) {
@@ -94,12 +112,24 @@ func (g *Plugin) PreFilter(ctx context.Context, cycleState *framework.CycleState
 			if err != nil {
 				return framework.NewStatus(framework.Error, err.Error())
 			}
+			if _, err := apiext.GetGPUIsolationLevel(pod.Annotations); err != nil {
+				return framework.NewStatus(framework.Error, err.Error())
+			}
+			strategy, err := g.getGPUAllocationStrategy(pod)
+			if err != nil {
+				return framework.NewStatus(framework.Error, err.Error())
+			}
+			state.gpuAllocationStrategy = strategy
 			state.convertedDeviceResource = quotav1.Add(
 				state.convertedDeviceResource,
 				convertGPUResource(podRequest, combination),
 			)
 			state.skip = false
-		case schedulingv1alpha1.RDMA, schedulingv1alpha1.FPGA:
+		default:
+			if _, ok := getCommonDeviceTypes()[deviceType]; !ok {
+				klog.Warningf("device type %v is not supported yet", deviceType)
+				break
+			}
 			if !hasDeviceResource(podRequest, deviceType) {
 				break
 			}
@@ -111,15 +141,46 @@ func (g *Plugin) PreFilter(ctx context.Context, cycleState *framework.CycleState
 				convertCommonDeviceResource(podRequest, deviceType),
 			)
 			state.skip = false
-		default:
-			klog.Warningf("device type %v is not supported yet", deviceType)
 		}
 	}
 
+	// the MIG devices are requested by the profile, e.g. nvidia.com/mig-1g.5gb
+	if migRequest := getMIGRequest(podRequest); len(migRequest) > 0 {
+		if err := validateMIGRequest(podRequest, migRequest); err != nil {
+			return framework.NewStatus(framework.Error, err.Error())
+		}
+		state.convertedDeviceResource = quotav1.Add(state.convertedDeviceResource, migRequest)
+		state.skip = false
+	}
+
 	cycleState.Write(stateKey, state)
 	return nil
 }
 
+// getGPUAllocationStrategy returns the gpu allocation strategy of the pod, the strategy of the ElasticQuota
+// the pod belongs to is used if the pod doesn't specify it.
+func (g *Plugin) getGPUAllocationStrategy(pod *corev1.Pod) (apiext.GPUAllocationStrategy, error) {
+	strategy, err := apiext.GetGPUAllocationStrategy(pod.Annotations)
+	if err != nil || strategy != "" {
+		return strategy, err
+	}
+	quotaName := pod.Labels[apiext.LabelQuotaName]
+	if quotaName == "" || g.quotaLister == nil {
+		return "", nil
+	}
+	quota, err := g.quotaLister.ElasticQuotas(pod.Namespace).Get(quotaName)
+	if err != nil {
+		klog.V(5).Infof("failed to get ElasticQuota %s/%s of pod %s, err: %v", pod.Namespace, quotaName, klog.KObj(pod), err)
+		return "", nil
+	}
+	strategy, err = apiext.GetGPUAllocationStrategy(quota.Annotations)
+	if err != nil {
+		klog.Warningf("ignore the gpu allocation strategy of ElasticQuota %s/%s, err: %v", quota.Namespace, quota.Name, err)
+		return "", nil
+	}
+	return strategy, nil
+}
+
 func (g *Plugin) PreFilterExtensions() framework.PreFilterExtensions {
 	return nil
 }
@@ -153,10 +214,12 @@ func (g *Plugin) Filter(ctx context.Context, cycleState *framework.CycleState, p
 
 	podRequest := state.convertedDeviceResource
 
+	preferred := g.nodeDeviceCache.getRecentAllocations(nodeInfo.Node().Name, pod)
+
 	nodeDeviceInfo.lock.RLock()
 	defer nodeDeviceInfo.lock.RUnlock()
 
-	allocateResult, err := nodeDeviceInfo.tryAllocateDevice(podRequest)
+	allocateResult, err := nodeDeviceInfo.tryAllocateDevice(podRequest, preferred, state.remoteDeviceLatencyTolerance, state.gpuAllocationStrategy)
 	if len(allocateResult) != 0 && err == nil {
 		return nil
 	}
@@ -164,6 +227,50 @@ func (g *Plugin) Filter(ctx context.Context, cycleState *framework.CycleState, p
 	return framework.NewStatus(framework.Unschedulable, ErrInsufficientDevices)
 }
 
+// Score prefers the node where the topology-aware devices, e.g. RDMA, can be allocated under the same PCIe switch
+// or on the same NUMA Node with the GPUs for the pod requesting both of them.
+func (g *Plugin) Score(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) (int64, *framework.Status) {
+	state, status := getPreFilterState(cycleState)
+	if !status.IsSuccess() {
+		return 0, status
+	}
+	podRequest := state.convertedDeviceResource
+	if state.skip || !hasDeviceResource(podRequest, schedulingv1alpha1.GPU) {
+		return 0, nil
+	}
+	scoreTopology := hasTopologyAwareDeviceResource(podRequest)
+	if !scoreTopology && state.gpuAllocationStrategy == "" {
+		return 0, nil
+	}
+
+	nodeDeviceInfo := g.nodeDeviceCache.getNodeDevice(nodeName)
+	if nodeDeviceInfo == nil {
+		return 0, nil
+	}
+
+	preferred := g.nodeDeviceCache.getRecentAllocations(nodeName, pod)
+
+	nodeDeviceInfo.lock.RLock()
+	defer nodeDeviceInfo.lock.RUnlock()
+
+	allocateResult, err := nodeDeviceInfo.tryAllocateDevice(podRequest, preferred, state.remoteDeviceLatencyTolerance, state.gpuAllocationStrategy)
+	if err != nil {
+		return 0, nil
+	}
+	if state.gpuAllocationStrategy == "" {
+		return nodeDeviceInfo.scoreTopology(allocateResult), nil
+	}
+	score := nodeDeviceInfo.scoreGPUAllocation(allocateResult, state.gpuAllocationStrategy)
+	if scoreTopology {
+		score = (score + nodeDeviceInfo.scoreTopology(allocateResult)) / 2
+	}
+	return score, nil
+}
+
+func (g *Plugin) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
+
 func (g *Plugin) Reserve(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) *framework.Status {
 	state, status := getPreFilterState(cycleState)
 	if !status.IsSuccess() {
@@ -180,15 +287,18 @@ func (g *Plugin) Reserve(ctx context.Context, cycleState *framework.CycleState,
 
 	podRequest := state.convertedDeviceResource
 
+	preferred := g.nodeDeviceCache.getRecentAllocations(nodeName, pod)
+
 	nodeDeviceInfo.lock.Lock()
 	defer nodeDeviceInfo.lock.Unlock()
 
-	allocateResult, err := nodeDeviceInfo.tryAllocateDevice(podRequest)
+	allocateResult, err := nodeDeviceInfo.tryAllocateDevice(podRequest, preferred, state.remoteDeviceLatencyTolerance, state.gpuAllocationStrategy)
 	if err != nil || len(allocateResult) == 0 {
 		return framework.NewStatus(framework.Unschedulable, ErrInsufficientDevices)
 	}
 
 	nodeDeviceInfo.updateCacheUsed(allocateResult, pod, true)
+	g.recordAssumedAllocations(pod, allocateResult)
 
 	state.allocationResult = allocateResult
 	return nil
@@ -212,6 +322,7 @@ func (g *Plugin) Unreserve(ctx context.Context, cycleState *framework.CycleState
 	defer nodeDeviceInfo.lock.Unlock()
 
 	nodeDeviceInfo.updateCacheUsed(state.allocationResult, pod, false)
+	g.forgetAssumedAllocations(pod)
 
 	state.allocationResult = nil
 }
@@ -257,6 +368,19 @@ func (g *Plugin) PreBind(ctx context.Context, cycleState *framework.CycleState,
 }
 
 func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	if args != nil {
+		pluginArgs, ok := args.(*schedulingconfig.DeviceShareArgs)
+		if !ok {
+			return nil, fmt.Errorf("want args to be of type DeviceShareArgs, got %T", args)
+		}
+		if err := validation.ValidateDeviceShareArgs(pluginArgs); err != nil {
+			return nil, err
+		}
+		if err := registerCustomDeviceTypes(pluginArgs.CustomDeviceTypes); err != nil {
+			return nil, err
+		}
+	}
+
 	extendedHandle, ok := handle.(frameworkext.ExtendedHandle)
 	if !ok {
 		return nil, fmt.Errorf("expect handle to be type frameworkext.ExtendedHandle, got %T", handle)
@@ -280,14 +404,32 @@ func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error)
 	podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    deviceCache.onPodAdd,
 		UpdateFunc: deviceCache.onPodUpdate,
-		DeleteFunc: deviceCache.onDeviceDelete,
+		DeleteFunc: deviceCache.onPodDelete,
 	})
 	// make sure Pods are loaded before scheduler starts working
 	podInformerFactory.Start(context.TODO().Done())
 	podInformerFactory.WaitForCacheSync(context.TODO().Done())
 
+	// the ElasticQuota may specify the gpu allocation strategy for all the pods of the quota
+	var quotaLister schedlister.ElasticQuotaLister
+	pgClient, ok := handle.(pgclientset.Interface)
+	if !ok && handle.KubeConfig() != nil {
+		kubeConfig := *handle.KubeConfig()
+		kubeConfig.ContentType = runtime.ContentTypeJSON
+		kubeConfig.AcceptContentTypes = runtime.ContentTypeJSON
+		pgClient = pgclientset.NewForConfigOrDie(&kubeConfig)
+	}
+	if pgClient != nil {
+		pgInformerFactory := pgformers.NewSharedInformerFactory(pgClient, 0)
+		quotaLister = pgInformerFactory.Scheduling().V1alpha1().ElasticQuotas().Lister()
+		pgInformerFactory.Start(context.TODO().Done())
+		pgInformerFactory.WaitForCacheSync(context.TODO().Done())
+	}
+
 	return &Plugin{
-		handle:          handle,
-		nodeDeviceCache: deviceCache,
+		handle:             handle,
+		nodeDeviceCache:    deviceCache,
+		quotaLister:        quotaLister,
+		assumedAllocations: map[types.UID]apiext.DeviceAllocations{},
 	}, nil
 }
@@ -20,7 +20,6 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
-	"k8s.io/kubernetes/pkg/api/v1/resource"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 )
@@ -38,10 +37,10 @@ func (n *nodeDeviceCache) onPodAdd(obj interface{}) {
 		return
 	}
 
-	podRequest, _ := resource.PodRequestsAndLimits(pod)
+	podRequest := getPodDeviceRequest(pod)
 
 	deviceExist := false
-	for deviceType := range deviceResourceNames {
+	for deviceType := range getDeviceResourceNames() {
 		if hasDeviceResource(podRequest, deviceType) {
 			deviceExist = true
 		}
@@ -91,10 +90,10 @@ func (n *nodeDeviceCache) onPodDelete(obj interface{}) {
 		return
 	}
 
-	podRequest, _ := resource.PodRequestsAndLimits(pod)
+	podRequest := getPodDeviceRequest(pod)
 
 	deviceExist := false
-	for deviceType := range deviceResourceNames {
+	for deviceType := range getDeviceResourceNames() {
 		if hasDeviceResource(podRequest, deviceType) {
 			deviceExist = true
 		}
@@ -112,8 +111,8 @@ func (n *nodeDeviceCache) onPodDelete(obj interface{}) {
 	}
 
 	info.lock.Lock()
-	defer info.lock.Unlock()
-
 	info.updateCacheUsed(devicesAllocation, pod, false)
+	info.lock.Unlock()
+	n.rememberAllocations(pod, devicesAllocation)
 	klog.V(5).InfoS("pod cache deleted", "pod", klog.KObj(pod))
 }
@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deviceshare
+
+import (
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+// recentAllocationTTL is how long the devices of a deleted pod are remembered. When a pod with the same
+// name is recreated on the same node in time, e.g. a restarted inference pod of a StatefulSet, the same
+// devices are preferred to reduce the cost of reloading the cache and the model.
+const recentAllocationTTL = 5 * time.Minute
+
+var timeNowFn = time.Now
+
+type recentAllocation struct {
+	allocations apiext.DeviceAllocations
+	deletedTime time.Time
+}
+
+func recentAllocationKey(nodeName string, pod *corev1.Pod) string {
+	return nodeName + "/" + pod.Namespace + "/" + pod.Name
+}
+
+// rememberAllocations records the devices allocated to the deleted pod, and removes the expired records.
+func (n *nodeDeviceCache) rememberAllocations(pod *corev1.Pod, allocations apiext.DeviceAllocations) {
+	if pod.Spec.NodeName == "" || len(allocations) == 0 {
+		return
+	}
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	now := timeNowFn()
+	if n.recentAllocations == nil {
+		n.recentAllocations = map[string]*recentAllocation{}
+	}
+	for key, allocation := range n.recentAllocations {
+		if now.Sub(allocation.deletedTime) > recentAllocationTTL {
+			delete(n.recentAllocations, key)
+		}
+	}
+	n.recentAllocations[recentAllocationKey(pod.Spec.NodeName, pod)] = &recentAllocation{
+		allocations: allocations,
+		deletedTime: now,
+	}
+}
+
+// getRecentAllocations returns the devices allocated to the previous pod with the same name on the node.
+func (n *nodeDeviceCache) getRecentAllocations(nodeName string, pod *corev1.Pod) apiext.DeviceAllocations {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	allocation := n.recentAllocations[recentAllocationKey(nodeName, pod)]
+	if allocation == nil || timeNowFn().Sub(allocation.deletedTime) > recentAllocationTTL {
+		return nil
+	}
+	return allocation.allocations
+}
+
+// sortedMinors returns the minors of the devices with the preferred minors first, the others are sorted
+// in ascending order to make the allocation stable.
+func sortedMinors(resources deviceResources, preferred apiext.DeviceAllocations, deviceType schedulingv1alpha1.DeviceType) []int {
+	isPreferred := map[int]bool{}
+	for _, allocation := range preferred[deviceType] {
+		isPreferred[int(allocation.Minor)] = true
+	}
+	minors := make([]int, 0, len(resources))
+	for minor := range resources {
+		minors = append(minors, minor)
+	}
+	sort.Slice(minors, func(i, j int) bool {
+		if isPreferred[minors[i]] != isPreferred[minors[j]] {
+			return isPreferred[minors[i]]
+		}
+		return minors[i] < minors[j]
+	})
+	return minors
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deviceshare
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+func TestRecentAllocations(t *testing.T) {
+	now := time.Now()
+	timeNowFn = func() time.Time {
+		return now
+	}
+	defer func() {
+		timeNowFn = time.Now
+	}()
+
+	gpuResources := func() corev1.ResourceList {
+		return corev1.ResourceList{
+			apiext.GPUCore:        resource.MustParse("100"),
+			apiext.GPUMemoryRatio: resource.MustParse("100"),
+			apiext.GPUMemory:      resource.MustParse("16Gi"),
+		}
+	}
+	nd := newNodeDevice()
+	nd.deviceTotal[schedulingv1alpha1.GPU] = deviceResources{}
+	nd.deviceFree[schedulingv1alpha1.GPU] = deviceResources{}
+	for minor := 0; minor < 4; minor++ {
+		nd.deviceTotal[schedulingv1alpha1.GPU][minor] = gpuResources()
+		nd.deviceFree[schedulingv1alpha1.GPU][minor] = gpuResources()
+	}
+	podRequest := corev1.ResourceList{
+		apiext.GPUCore:        resource.MustParse("200"),
+		apiext.GPUMemoryRatio: resource.MustParse("200"),
+	}
+	allocatedMinors := func(allocations apiext.DeviceAllocations) []int32 {
+		var minors []int32
+		for _, allocation := range allocations[schedulingv1alpha1.GPU] {
+			minors = append(minors, allocation.Minor)
+		}
+		return minors
+	}
+
+	// the devices are allocated in ascending order without the previous allocation
+	allocations, err := nd.tryAllocateDevice(podRequest, nil, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{0, 1}, allocatedMinors(allocations))
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "inference-0"},
+		Spec:       corev1.PodSpec{NodeName: "test-node-1"},
+	}
+	cache := newNodeDeviceCache()
+	cache.rememberAllocations(pod, apiext.DeviceAllocations{
+		schedulingv1alpha1.GPU: {{Minor: 3}, {Minor: 2}},
+	})
+
+	recreatedPod := pod.DeepCopy()
+	recreatedPod.Spec.NodeName = ""
+	preferred := cache.getRecentAllocations("test-node-1", recreatedPod)
+	allocations, err = nd.tryAllocateDevice(podRequest, preferred, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{2, 3}, allocatedMinors(allocations))
+
+	// the previous allocation is only preferred on the same node
+	assert.Nil(t, cache.getRecentAllocations("test-node-2", recreatedPod))
+
+	// the previous allocation is forgotten after the ttl
+	now = now.Add(recentAllocationTTL + time.Second)
+	assert.Nil(t, cache.getRecentAllocations("test-node-1", recreatedPod))
+	cache.rememberAllocations(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "inference-1"},
+		Spec:       corev1.PodSpec{NodeName: "test-node-1"},
+	}, apiext.DeviceAllocations{
+		schedulingv1alpha1.GPU: {{Minor: 0}},
+	})
+	assert.Len(t, cache.recentAllocations, 1)
+}
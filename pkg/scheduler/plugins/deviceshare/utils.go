@@ -18,12 +18,17 @@ package deviceshare
 
 import (
 	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/klog/v2"
+	podresource "k8s.io/kubernetes/pkg/api/v1/resource"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingconfig "github.com/koordinator-sh/koordinator/apis/scheduling/config"
 	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 )
 
@@ -35,10 +40,139 @@ const (
 	gpuMemoryRatioExist
 )
 
-var deviceResourceNames = map[schedulingv1alpha1.DeviceType][]corev1.ResourceName{
-	schedulingv1alpha1.GPU:  {apiext.NvidiaGPU, apiext.KoordGPU, apiext.GPUCore, apiext.GPUMemory, apiext.GPUMemoryRatio},
-	schedulingv1alpha1.RDMA: {apiext.KoordRDMA},
-	schedulingv1alpha1.FPGA: {apiext.KoordFPGA},
+// commonDeviceType describes how the devices of a type other than the GPU are requested and allocated.
+type commonDeviceType struct {
+	// resourceNames are the resources requesting the devices, the first one is reported by the devices
+	// and the others are its aliases.
+	resourceNames []corev1.ResourceName
+	// shareable devices report 100 of the resource each and can be shared by the pods requesting less than 100,
+	// otherwise the pod requests the number of the devices and each device is allocated exclusively.
+	shareable bool
+	// topologyAware devices are allocated close to the allocated GPUs.
+	topologyAware bool
+}
+
+// deviceTypeRegistry holds the device types allocated by the plugin. It's never modified once stored, the custom
+// device types are registered by storing a modified copy, so the scheduling and the event handlers read it without
+// locking. The apiext.DeviceResourceNames is copied and never written.
+type deviceTypeRegistry struct {
+	// resourceNames are the resources requesting each type of devices.
+	resourceNames map[schedulingv1alpha1.DeviceType][]corev1.ResourceName
+	// commonDeviceTypes are the device types other than the GPU.
+	commonDeviceTypes map[schedulingv1alpha1.DeviceType]*commonDeviceType
+}
+
+var (
+	// deviceTypesLock serializes the registrations, the readers load deviceTypes directly.
+	deviceTypesLock sync.Mutex
+	deviceTypes     atomic.Value
+)
+
+func init() {
+	resourceNames := make(map[schedulingv1alpha1.DeviceType][]corev1.ResourceName, len(apiext.DeviceResourceNames))
+	for deviceType, names := range apiext.DeviceResourceNames {
+		resourceNames[deviceType] = append([]corev1.ResourceName{}, names...)
+	}
+	deviceTypes.Store(&deviceTypeRegistry{
+		resourceNames: resourceNames,
+		commonDeviceTypes: map[schedulingv1alpha1.DeviceType]*commonDeviceType{
+			schedulingv1alpha1.RDMA: {resourceNames: []corev1.ResourceName{apiext.KoordRDMA}, shareable: true, topologyAware: true},
+			schedulingv1alpha1.FPGA: {resourceNames: []corev1.ResourceName{apiext.KoordFPGA}, shareable: true},
+		},
+	})
+}
+
+func getDeviceTypes() *deviceTypeRegistry {
+	return deviceTypes.Load().(*deviceTypeRegistry)
+}
+
+// getDeviceResourceNames returns the resources requesting each type of devices, it must not be modified.
+func getDeviceResourceNames() map[schedulingv1alpha1.DeviceType][]corev1.ResourceName {
+	return getDeviceTypes().resourceNames
+}
+
+// getCommonDeviceTypes returns the device types other than the GPU, it must not be modified.
+func getCommonDeviceTypes() map[schedulingv1alpha1.DeviceType]*commonDeviceType {
+	return getDeviceTypes().commonDeviceTypes
+}
+
+// registerCustomDeviceTypes registers the third-party device types declared in the DeviceShareArgs.
+// It's called when the plugin is created, nothing is registered if any type conflicts.
+func registerCustomDeviceTypes(customDeviceTypes []schedulingconfig.CustomDeviceType) error {
+	deviceTypesLock.Lock()
+	defer deviceTypesLock.Unlock()
+
+	registered := getDeviceTypes()
+	registry := &deviceTypeRegistry{
+		resourceNames:     make(map[schedulingv1alpha1.DeviceType][]corev1.ResourceName, len(registered.resourceNames)),
+		commonDeviceTypes: make(map[schedulingv1alpha1.DeviceType]*commonDeviceType, len(registered.commonDeviceTypes)),
+	}
+	for deviceType, resourceNames := range registered.resourceNames {
+		registry.resourceNames[deviceType] = resourceNames
+	}
+	for deviceType, spec := range registered.commonDeviceTypes {
+		registry.commonDeviceTypes[deviceType] = spec
+	}
+	for _, customDeviceType := range customDeviceTypes {
+		deviceType := schedulingv1alpha1.DeviceType(customDeviceType.Type)
+		spec := &commonDeviceType{
+			resourceNames: append([]corev1.ResourceName{}, customDeviceType.ResourceNames...),
+			shareable:     customDeviceType.Shareable,
+			topologyAware: customDeviceType.TopologyAware,
+		}
+		if registeredSpec, ok := registry.commonDeviceTypes[deviceType]; ok {
+			// the plugins of multiple profiles register the same types
+			if !reflect.DeepEqual(registeredSpec, spec) {
+				return fmt.Errorf("device type %v is already registered differently", deviceType)
+			}
+			continue
+		}
+		registry.commonDeviceTypes[deviceType] = spec
+		registry.resourceNames[deviceType] = spec.resourceNames
+	}
+	deviceTypes.Store(registry)
+	return nil
+}
+
+func isTopologyAwareDevice(deviceType schedulingv1alpha1.DeviceType) bool {
+	spec, ok := getCommonDeviceTypes()[deviceType]
+	return ok && spec.topologyAware
+}
+
+func hasTopologyAwareDeviceResource(podRequest corev1.ResourceList) bool {
+	for deviceType, spec := range getCommonDeviceTypes() {
+		if spec.topologyAware && hasDeviceResource(podRequest, deviceType) {
+			return true
+		}
+	}
+	return false
+}
+
+// getCommonDeviceRequest returns the request of the common device type, the aliases are added up. The request of the
+// pod is copied as is if only one of the aliases is requested.
+func getCommonDeviceRequest(podRequest corev1.ResourceList, spec *commonDeviceType) (resource.Quantity, bool) {
+	var quantity resource.Quantity
+	requested := false
+	for _, resourceName := range spec.resourceNames {
+		value, ok := podRequest[resourceName]
+		if !ok {
+			continue
+		}
+		if requested {
+			quantity.Add(value)
+		} else {
+			quantity = value.DeepCopy()
+			requested = true
+		}
+	}
+	return quantity, requested
+}
+
+// getPodDeviceRequest returns the request of the pod, the aliased device resource names are translated to the
+// canonical ones.
+func getPodDeviceRequest(pod *corev1.Pod) corev1.ResourceList {
+	podRequest, _ := podresource.PodRequestsAndLimits(pod)
+	return apiext.TranslateResourceNameAliases(podRequest)
 }
 
 func hasDeviceResource(podRequest corev1.ResourceList, deviceType schedulingv1alpha1.DeviceType) bool {
@@ -46,7 +180,7 @@ func hasDeviceResource(podRequest corev1.ResourceList, deviceType schedulingv1al
 		klog.Warningf("skip checking hasDeviceResource, because pod request is empty")
 		return false
 	}
-	for _, resourceName := range deviceResourceNames[deviceType] {
+	for _, resourceName := range getDeviceResourceNames()[deviceType] {
 		if _, ok := podRequest[resourceName]; ok {
 			return true
 		}
@@ -55,21 +189,52 @@ func hasDeviceResource(podRequest corev1.ResourceList, deviceType schedulingv1al
 	return false
 }
 
+// getMIGRequest returns the MIG devices requested by the pod, e.g. nvidia.com/mig-1g.5gb: 1.
+func getMIGRequest(podRequest corev1.ResourceList) corev1.ResourceList {
+	var migRequest corev1.ResourceList
+	for resourceName, quantity := range podRequest {
+		if _, ok := apiext.GetMIGProfile(resourceName); !ok {
+			continue
+		}
+		if migRequest == nil {
+			migRequest = corev1.ResourceList{}
+		}
+		migRequest[resourceName] = quantity
+	}
+	return migRequest
+}
+
+// validateMIGRequest checks the MIG devices are requested in whole devices and not mixed with the shared GPU.
+func validateMIGRequest(podRequest, migRequest corev1.ResourceList) error {
+	if hasDeviceResource(podRequest, schedulingv1alpha1.GPU) {
+		return fmt.Errorf("mig devices can't be requested together with gpu")
+	}
+	for resourceName, quantity := range migRequest {
+		if quantity.MilliValue() <= 0 || quantity.MilliValue()%1000 != 0 {
+			return fmt.Errorf("failed to validate %v: %v", resourceName, quantity.String())
+		}
+	}
+	return nil
+}
+
 func validateCommonDeviceRequest(podRequest corev1.ResourceList, deviceType schedulingv1alpha1.DeviceType) error {
 	if podRequest == nil || len(podRequest) == 0 {
 		return fmt.Errorf("pod request should not be empty")
 	}
-	var commonDevice resource.Quantity
-	switch deviceType {
-	case schedulingv1alpha1.FPGA:
-		commonDevice = podRequest[apiext.KoordFPGA]
-	case schedulingv1alpha1.RDMA:
-		commonDevice = podRequest[apiext.KoordRDMA]
-	default:
+	spec, ok := getCommonDeviceTypes()[deviceType]
+	if !ok {
 		return fmt.Errorf("device type %v is not supported yet", deviceType)
 	}
-	if commonDevice.Value() > 100 && commonDevice.Value()%100 != 0 {
-		return fmt.Errorf("failed to validate %v%v: %v", apiext.ResourceDomainPrefix, deviceType, commonDevice.Value())
+	commonDevice, _ := getCommonDeviceRequest(podRequest, spec)
+	if spec.shareable {
+		if commonDevice.Value() > 100 && commonDevice.Value()%100 != 0 {
+			return fmt.Errorf("failed to validate %v: %v", spec.resourceNames[0], commonDevice.Value())
+		}
+		return nil
+	}
+	// the exclusive devices are requested in whole devices
+	if commonDevice.MilliValue() <= 0 || commonDevice.MilliValue()%1000 != 0 {
+		return fmt.Errorf("failed to validate %v: %v", spec.resourceNames[0], commonDevice.String())
 	}
 	return nil
 }
@@ -107,6 +272,10 @@ func validateGPURequest(podRequest corev1.ResourceList) (uint, error) {
 		if gpuMemRatio.Value() > 100 && gpuMemRatio.Value()%100 != 0 {
 			return gpuCombination, fmt.Errorf("failed to validate %v: %v", apiext.GPUMemoryRatio, gpuMemRatio.Value())
 		}
+		// the pod sharing one GPU can't get the memory of more than one GPU
+		if gpuCore := podRequest[apiext.GPUCore]; gpuCore.Value() <= 100 && gpuMemRatio.Value() > 100 {
+			return gpuCombination, fmt.Errorf("failed to validate %v: %v, more than the memory of one GPU", apiext.GPUMemoryRatio, gpuMemRatio.Value())
+		}
 		gpuCombination |= gpuMemoryRatioExist
 	}
 
@@ -125,25 +294,18 @@ func convertCommonDeviceResource(podRequest corev1.ResourceList, deviceType sche
 		klog.Warningf("pod request should not be empty")
 		return nil
 	}
-	var resources corev1.ResourceList
-	switch deviceType {
-	case schedulingv1alpha1.RDMA:
-		if value, ok := podRequest[apiext.KoordRDMA]; ok {
-			resources = corev1.ResourceList{
-				apiext.KoordRDMA: value,
-			}
-		}
-	case schedulingv1alpha1.FPGA:
-		if value, ok := podRequest[apiext.KoordFPGA]; ok {
-			resources = corev1.ResourceList{
-				apiext.KoordFPGA: value,
-			}
-		}
-	default:
+	spec, ok := getCommonDeviceTypes()[deviceType]
+	if !ok {
 		klog.Warningf("device type %v is not supported yet", deviceType)
 		return nil
 	}
-	return resources
+	value, ok := getCommonDeviceRequest(podRequest, spec)
+	if !ok {
+		return nil
+	}
+	return corev1.ResourceList{
+		spec.resourceNames[0]: value,
+	}
 }
 
 // nvidia.com/gpu means applying for full-card
@@ -185,16 +347,12 @@ func isMultipleCommonDevicePod(podRequest corev1.ResourceList, deviceType schedu
 		klog.Warningf("pod request should not be empty")
 		return false
 	}
-	switch deviceType {
-	case schedulingv1alpha1.RDMA:
-		rdma := podRequest[apiext.KoordRDMA]
-		return rdma.Value() > 100 && rdma.Value()%100 == 0
-	case schedulingv1alpha1.FPGA:
-		fpga := podRequest[apiext.KoordFPGA]
-		return fpga.Value() > 100 && fpga.Value()%100 == 0
-	default:
+	spec, ok := getCommonDeviceTypes()[deviceType]
+	if !ok || !spec.shareable {
 		return false
 	}
+	commonDevice, _ := getCommonDeviceRequest(podRequest, spec)
+	return commonDevice.Value() > 100 && commonDevice.Value()%100 == 0
 }
 
 func isMultipleGPUPod(podRequest corev1.ResourceList) bool {
@@ -222,8 +380,8 @@ func patchContainerGPUResource(pod *corev1.Pod, podRequest corev1.ResourceList)
 		if reqs == nil {
 			continue
 		}
-		for _, v := range deviceResourceNames[schedulingv1alpha1.GPU] {
-			if _, ok := reqs[v]; ok {
+		for name := range reqs {
+			if isGPUResourceName(apiext.GetCanonicalResourceName(name)) {
 				needPatch = true
 				break
 			}
@@ -237,6 +395,15 @@ func patchContainerGPUResource(pod *corev1.Pod, podRequest corev1.ResourceList)
 	}
 }
 
+func isGPUResourceName(name corev1.ResourceName) bool {
+	for _, v := range getDeviceResourceNames()[schedulingv1alpha1.GPU] {
+		if name == v {
+			return true
+		}
+	}
+	return false
+}
+
 func fillGPUTotalMem(nodeDeviceTotal deviceResources, podRequest corev1.ResourceList) {
 	// nodeDeviceTotal uses the minor of GPU as key. However, under certain circumstances,
 	// minor 0 might not exist. We need to iterate the cache once to find the active minor.
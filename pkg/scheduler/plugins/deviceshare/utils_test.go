@@ -217,6 +217,15 @@ func Test_validateGPURequest(t *testing.T) {
 			want:    0,
 			wantErr: true,
 		},
+		{
+			name: "invalid gpu request 5",
+			podRequest: corev1.ResourceList{
+				apiext.GPUCore:        resource.MustParse("50"),
+				apiext.GPUMemoryRatio: resource.MustParse("200"),
+			},
+			want:    0,
+			wantErr: true,
+		},
 		{
 			name: "valid gpu request 1",
 			podRequest: corev1.ResourceList{
@@ -263,6 +272,47 @@ func Test_validateGPURequest(t *testing.T) {
 	}
 }
 
+func Test_validateMIGRequest(t *testing.T) {
+	mig1g := apiext.MIGResourceName("1g.5gb")
+	tests := []struct {
+		name       string
+		podRequest corev1.ResourceList
+		wantErr    bool
+	}{
+		{
+			name: "valid mig request",
+			podRequest: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("4"),
+				mig1g:              resource.MustParse("2"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid mig request with gpu",
+			podRequest: corev1.ResourceList{
+				mig1g:          resource.MustParse("1"),
+				apiext.GPUCore: resource.MustParse("100"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid fractional mig request",
+			podRequest: corev1.ResourceList{
+				mig1g: resource.MustParse("500m"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migRequest := getMIGRequest(tt.podRequest)
+			assert.Equal(t, corev1.ResourceList{mig1g: tt.podRequest[mig1g]}, migRequest)
+			err := validateMIGRequest(tt.podRequest, migRequest)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
 func Test_convertCommonDeviceResource(t *testing.T) {
 	type args struct {
 		podRequest corev1.ResourceList
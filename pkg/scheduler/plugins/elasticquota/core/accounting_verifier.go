@@ -0,0 +1,194 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	k8sfeature "k8s.io/apiserver/pkg/util/feature"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	accountingFieldRequest = "request"
+	accountingFieldUsed    = "used"
+)
+
+// QuotaAccountingDrift is the difference between the cached Request/Used of a leaf quota group and the
+// values recomputed from the pods, a positive value means the cached value is larger.
+type QuotaAccountingDrift struct {
+	QuotaName string
+	Request   v1.ResourceList
+	Used      v1.ResourceList
+}
+
+// GetPodQuotaName returns the quota group of the pod, the pod without quota label belongs to the default quota group.
+func GetPodQuotaName(pod *v1.Pod) string {
+	if quotaName := pod.Labels[extension.LabelQuotaName]; quotaName != "" {
+		return quotaName
+	}
+	return extension.DefaultQuotaName
+}
+
+// QuotaAccountingVerifier periodically recomputes the Request/Used of the leaf quota groups from the pods listed
+// from apiserver, and corrects the cached values if they drift. It assumes all the pods are accounted by
+// GroupQuotaManager.UpdatePodAccountingState, and the tracked pods are resynchronized with the listed pods too.
+// It should run at low frequency in production since it holds the lock of the GroupQuotaManager while verifying,
+// and can run aggressively in e2e tests to catch the accounting bugs.
+type QuotaAccountingVerifier struct {
+	gqm       *GroupQuotaManager
+	podLister listerv1.PodLister
+	interval  time.Duration
+}
+
+func NewQuotaAccountingVerifier(gqm *GroupQuotaManager, podLister listerv1.PodLister, interval time.Duration) *QuotaAccountingVerifier {
+	return &QuotaAccountingVerifier{
+		gqm:       gqm,
+		podLister: podLister,
+		interval:  interval,
+	}
+}
+
+// Start runs the verifier until stopCh is closed, it does nothing if ElasticQuotaAccountingVerification is disabled.
+func (v *QuotaAccountingVerifier) Start(stopCh <-chan struct{}) {
+	if !k8sfeature.DefaultFeatureGate.Enabled(features.ElasticQuotaAccountingVerification) {
+		return
+	}
+	klog.Infof("start elastic quota accounting verifier, interval: %v", v.interval)
+	go wait.Until(func() {
+		v.Verify()
+	}, v.interval, stopCh)
+}
+
+// Verify corrects the drift of all the leaf quota groups and returns the drifts before the correction.
+func (v *QuotaAccountingVerifier) Verify() []*QuotaAccountingDrift {
+	pods, err := v.podLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list pods for elastic quota accounting verification, err: %v", err)
+		return nil
+	}
+	return v.gqm.VerifyAccounting(pods)
+}
+
+// VerifyAccounting recomputes the Request/Used of the leaf quota groups from the pods and resynchronizes the tracked
+// pods, then corrects the drift of all the leaf quota groups and returns the drifts before the correction.
+func (gqm *GroupQuotaManager) VerifyAccounting(pods []*v1.Pod) []*QuotaAccountingDrift {
+	gqm.hierarchyUpdateLock.RLock()
+	defer gqm.hierarchyUpdateLock.RUnlock()
+
+	// the pending request deltas are not drift
+	gqm.flushDirtyRequestsNoLock()
+
+	cache := gqm.podAccountingCache
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	expectedRequest := map[string]v1.ResourceList{}
+	expectedUsed := map[string]v1.ResourceList{}
+	trackedPods := make(map[types.UID]*podAccountingInfo, len(pods))
+	now := time.Now()
+	for _, pod := range pods {
+		quotaName := GetPodQuotaName(pod)
+		if gqm.getQuotaInfoByNameNoLock(quotaName) == nil {
+			continue
+		}
+		// the assumed pod is not bound in apiserver yet, trust the tracked state
+		oldInfo := cache.pods[pod.UID]
+		assumed := oldInfo != nil && oldInfo.state == PodAccountingStateAssumed
+		state := getPodAccountingStateByPolicy(pod, assumed, cache.policy, now)
+		if state == PodAccountingStateGone {
+			continue
+		}
+		info := &podAccountingInfo{
+			quotaName:     quotaName,
+			priorityClass: extension.GetPriorityClass(pod),
+			request:       getPodRequest(pod),
+			state:         state,
+		}
+		if oldInfo != nil && oldInfo.quotaName == quotaName {
+			// the request of the tracked pod is only changed by ResizePodAccounting
+			info.request = oldInfo.request
+			info.priorityClass = oldInfo.priorityClass
+		}
+		trackedPods[pod.UID] = info
+		if state.countRequest() {
+			expectedRequest[quotaName] = quotav1.Add(expectedRequest[quotaName], info.request)
+		}
+		if state.countUsed() {
+			expectedUsed[quotaName] = quotav1.Add(expectedUsed[quotaName], info.request)
+		}
+	}
+	cache.pods = trackedPods
+	// the Reservations charged to the quota groups are not drift
+	gqm.addReservationAccountingNoLock(expectedRequest, expectedUsed)
+
+	var drifts []*QuotaAccountingDrift
+	systemOrDefaultUsedChanged := false
+	for quotaName, quotaInfo := range gqm.quotaInfoMap {
+		if quotaInfo.IsParent || quotaName == extension.RootQuotaName {
+			continue
+		}
+		requestDrift := quotav1.Subtract(quotaInfo.GetRequest(), expectedRequest[quotaName])
+		usedDrift := quotav1.Subtract(quotaInfo.GetUsed(), expectedUsed[quotaName])
+		recordAccountingDrift(quotaName, accountingFieldRequest, requestDrift)
+		recordAccountingDrift(quotaName, accountingFieldUsed, usedDrift)
+
+		requestDrifted := !quotav1.IsZero(requestDrift)
+		usedDrifted := !quotav1.IsZero(usedDrift)
+		if !requestDrifted && !usedDrifted {
+			continue
+		}
+		klog.Warningf("elastic quota %s accounting drifted, request drift: %v, used drift: %v",
+			quotaName, util.DumpJSON(requestDrift), util.DumpJSON(usedDrift))
+		if requestDrifted {
+			gqm.updateGroupDeltaRequestNoLock(quotaName, quotav1.Subtract(v1.ResourceList{}, requestDrift))
+		}
+		if usedDrifted {
+			gqm.updateGroupDeltaUsedNoLock(quotaName, quotav1.Subtract(v1.ResourceList{}, usedDrift))
+			if quotaName == extension.SystemQuotaName || quotaName == extension.DefaultQuotaName {
+				systemOrDefaultUsedChanged = true
+			}
+		}
+		drifts = append(drifts, &QuotaAccountingDrift{
+			QuotaName: quotaName,
+			Request:   requestDrift,
+			Used:      usedDrift,
+		})
+	}
+	// if systemQuotaGroup or DefaultQuotaGroup's used change, update cluster total resource.
+	if systemOrDefaultUsedChanged {
+		gqm.updateClusterTotalResourceNoLock(v1.ResourceList{})
+	}
+	return drifts
+}
+
+func recordAccountingDrift(quotaName, field string, drift v1.ResourceList) {
+	for resourceName, quantity := range drift {
+		metrics.ElasticQuotaAccountingDrift.WithLabelValues(quotaName, field, string(resourceName)).Set(float64(quantity.MilliValue()) / 1000)
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestQuotaAccountingVerifier_Verify(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 50, 500, 10, 100, true, false)
+	AddQuotaToManager(t, gqm, "2", extension.RootQuotaName, 50, 500, 10, 100, true, false)
+
+	newPod := func(name string, cpu, memory int64, nodeName string) *v1.Pod {
+		pod := newTestQuotaPod(name, cpu, memory)
+		pod.Labels = map[string]string{extension.LabelQuotaName: "1"}
+		pod.Spec.NodeName = nodeName
+		return pod
+	}
+	pod1 := newPod("pod-1", 2, 20, "node-1")
+	pod2 := newPod("pod-2", 1, 10, "")
+	pod3 := newPod("pod-3", 4, 40, "node-1")
+	pod4 := newPod("pod-4", 3, 30, "")
+
+	// the creation of pod-2 and the deletion of pod-3 are missed
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod1, PodAccountingStateRunning))
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod3, PodAccountingStateRunning))
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod4, PodAccountingStateAssumed))
+	gqm.UpdateGroupDeltaRequest("2", createResourceList(5, 50))
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, pod := range []*v1.Pod{pod1, pod2, pod4} {
+		assert.NoError(t, indexer.Add(pod))
+	}
+	verifier := NewQuotaAccountingVerifier(gqm, listerv1.NewPodLister(indexer), time.Second)
+
+	drifts := verifier.Verify()
+	driftMap := map[string]*QuotaAccountingDrift{}
+	for _, drift := range drifts {
+		driftMap[drift.QuotaName] = drift
+	}
+	assert.Len(t, driftMap, 2)
+	assert.True(t, quotav1.Equals(createResourceList(3, 30), driftMap["1"].Request), "request drift: %v", driftMap["1"].Request)
+	assert.True(t, quotav1.Equals(createResourceList(4, 40), driftMap["1"].Used), "used drift: %v", driftMap["1"].Used)
+	assert.True(t, quotav1.Equals(createResourceList(5, 50), driftMap["2"].Request), "request drift: %v", driftMap["2"].Request)
+	assert.True(t, quotav1.IsZero(driftMap["2"].Used))
+
+	// the cached values are corrected
+	quotaInfo := gqm.GetQuotaInfoByName("1")
+	assert.True(t, quotav1.Equals(createResourceList(6, 60), quotaInfo.GetRequest()))
+	assert.True(t, quotav1.Equals(createResourceList(5, 50), quotaInfo.GetUsed()))
+	assert.True(t, quotav1.IsZero(gqm.GetQuotaInfoByName("2").GetRequest()))
+
+	// the tracked pods are resynchronized, and the assumed pod is kept
+	assert.Equal(t, PodAccountingStateRunning, gqm.GetPodAccountingState(pod1.UID))
+	assert.Equal(t, PodAccountingStatePending, gqm.GetPodAccountingState(pod2.UID))
+	assert.Equal(t, PodAccountingStateGone, gqm.GetPodAccountingState(pod3.UID))
+	assert.Equal(t, PodAccountingStateAssumed, gqm.GetPodAccountingState(pod4.UID))
+
+	// no drift any more, and the missed events are idempotent
+	assert.Empty(t, verifier.Verify())
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod2, PodAccountingStatePending))
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod3, PodAccountingStateGone))
+	assert.Empty(t, verifier.Verify())
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"encoding/json"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+)
+
+var _ frameworkext.AssumeStateHolder = &GroupQuotaManager{}
+
+// quotaAssumeStateHold is the quota held by the assumed pod, which is handed off to the incoming leader.
+type quotaAssumeStateHold struct {
+	Quota string `json:"quota"`
+}
+
+func (gqm *GroupQuotaManager) ExportAssumeState(pod *v1.Pod, nodeName string) (json.RawMessage, error) {
+	gqm.podAccountingCache.lock.Lock()
+	defer gqm.podAccountingCache.lock.Unlock()
+	info, ok := gqm.podAccountingCache.pods[pod.UID]
+	if !ok || info.state != PodAccountingStateAssumed {
+		return nil, nil
+	}
+	return json.Marshal(&quotaAssumeStateHold{Quota: info.quotaName})
+}
+
+func (gqm *GroupQuotaManager) RestoreAssumeState(pod *v1.Pod, nodeName string, hold json.RawMessage) error {
+	quotaHold := &quotaAssumeStateHold{}
+	if err := json.Unmarshal(hold, quotaHold); err != nil {
+		return err
+	}
+	return gqm.UpdatePodAccountingState(quotaHold.Quota, pod, PodAccountingStateAssumed)
+}
+
+// ForgetAssumeState moves the pod back to Pending if it's still assumed, the pod is still counted in the Request.
+func (gqm *GroupQuotaManager) ForgetAssumeState(pod *v1.Pod, nodeName string, hold json.RawMessage) {
+	quotaHold := &quotaAssumeStateHold{}
+	if err := json.Unmarshal(hold, quotaHold); err != nil {
+		return
+	}
+	if gqm.GetPodAccountingState(pod.UID) != PodAccountingStateAssumed {
+		return
+	}
+	_ = gqm.UpdatePodAccountingState(quotaHold.Quota, pod, PodAccountingStatePending)
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestGroupQuotaManager_AssumeStateHandoff(t *testing.T) {
+	newManager := func() *GroupQuotaManager {
+		gqm := NewGroupQuotaManager4Test()
+		gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+		AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 100, 1000*GigaByte, 50, 500*GigaByte, true, false)
+		return gqm
+	}
+	pod := newTestQuotaPod("pod-1", 10, 100)
+
+	oldLeader := newManager()
+	assert.NoError(t, oldLeader.UpdatePodAccountingState("1", pod, PodAccountingStatePending))
+	hold, err := oldLeader.ExportAssumeState(pod, "node-1")
+	assert.NoError(t, err)
+	assert.Nil(t, hold, "the pending pod holds nothing")
+	assert.NoError(t, oldLeader.UpdatePodAccountingState("1", pod, PodAccountingStateAssumed))
+	hold, err = oldLeader.ExportAssumeState(pod, "node-1")
+	assert.NoError(t, err)
+	assert.NotNil(t, hold)
+
+	// the incoming leader only sees the pending pod
+	newLeader := newManager()
+	assert.NoError(t, newLeader.UpdatePodAccountingState("1", pod, PodAccountingStatePending))
+	assert.NoError(t, newLeader.RestoreAssumeState(pod, "node-1", hold))
+	assert.Equal(t, PodAccountingStateAssumed, newLeader.GetPodAccountingState(pod.UID))
+	assert.True(t, quotav1.Equals(createResourceList(10, 100), newLeader.GetQuotaInfoByName("1").GetUsed()))
+
+	// the pod is not bound in time
+	newLeader.ForgetAssumeState(pod, "node-1", hold)
+	assert.Equal(t, PodAccountingStatePending, newLeader.GetPodAccountingState(pod.UID))
+	assert.True(t, quotav1.Equals(createResourceList(10, 100), newLeader.GetQuotaInfoByName("1").GetRequest()))
+	assert.True(t, quotav1.IsZero(newLeader.GetQuotaInfoByName("1").GetUsed()))
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+)
+
+// requestBatch accumulates the request delta of the quota groups marked dirty, and the deltas are propagated up
+// the quota tree together every interval. The deltas of the same quota group are merged, so the pods added and
+// deleted within one interval cost nothing, and each quota tree is recalculated once per interval at most.
+type requestBatch struct {
+	interval time.Duration
+
+	lock sync.Mutex
+	// dirtyRequests is the accumulated request delta of the dirty quota groups
+	dirtyRequests map[string]v1.ResourceList
+}
+
+func newRequestBatch(interval time.Duration) *requestBatch {
+	return &requestBatch{
+		interval:      interval,
+		dirtyRequests: map[string]v1.ResourceList{},
+	}
+}
+
+func (b *requestBatch) markDirty(quotaName string, deltaReq v1.ResourceList) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.dirtyRequests[quotaName] = quotav1.Add(b.dirtyRequests[quotaName], deltaReq)
+}
+
+func (b *requestBatch) drain() map[string]v1.ResourceList {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	dirtyRequests := b.dirtyRequests
+	b.dirtyRequests = map[string]v1.ResourceList{}
+	return dirtyRequests
+}
+
+// SetBatchRecalculateInterval enables the batch recalculation of the request, the request delta of the quota groups
+// is propagated up the quota tree every interval instead of on each pod event, so the request and the runtime of
+// the quota groups may be stale for one interval at most. It's disabled if interval is not positive, and the
+// pending deltas are propagated immediately.
+func (gqm *GroupQuotaManager) SetBatchRecalculateInterval(interval time.Duration) {
+	gqm.hierarchyUpdateLock.Lock()
+	defer gqm.hierarchyUpdateLock.Unlock()
+
+	gqm.flushDirtyRequestsNoLock()
+	if interval <= 0 {
+		gqm.requestBatch = nil
+	} else {
+		gqm.requestBatch = newRequestBatch(interval)
+	}
+	klog.V(3).Infof("Set BatchRecalculateInterval, interval:%v", interval)
+}
+
+// RunBatchRecalculation propagates the pending request deltas every batch recalculate interval until stopCh is
+// closed, it does nothing if the batch recalculation is disabled.
+func (gqm *GroupQuotaManager) RunBatchRecalculation(stopCh <-chan struct{}) {
+	gqm.hierarchyUpdateLock.RLock()
+	batch := gqm.requestBatch
+	gqm.hierarchyUpdateLock.RUnlock()
+	if batch == nil {
+		return
+	}
+	go wait.Until(gqm.FlushDirtyRequests, batch.interval, stopCh)
+}
+
+// FlushDirtyRequests propagates the pending request deltas of all the dirty quota groups.
+func (gqm *GroupQuotaManager) FlushDirtyRequests() {
+	gqm.hierarchyUpdateLock.RLock()
+	defer gqm.hierarchyUpdateLock.RUnlock()
+
+	gqm.flushDirtyRequestsNoLock()
+}
+
+func (gqm *GroupQuotaManager) flushDirtyRequestsNoLock() {
+	if gqm.requestBatch == nil {
+		return
+	}
+	for quotaName, deltaReq := range gqm.requestBatch.drain() {
+		if quotav1.IsZero(deltaReq) {
+			continue
+		}
+		gqm.updateGroupDeltaRequestNoLock(quotaName, deltaReq)
+	}
+}
+
+// markGroupDeltaRequestNoLock marks the quota group dirty if the batch recalculation is enabled, or propagates
+// the request delta immediately.
+func (gqm *GroupQuotaManager) markGroupDeltaRequestNoLock(quotaName string, deltaReq v1.ResourceList) {
+	if gqm.requestBatch == nil {
+		gqm.updateGroupDeltaRequestNoLock(quotaName, deltaReq)
+		return
+	}
+	gqm.requestBatch.markDirty(quotaName, deltaReq)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestGroupQuotaManager_BatchRecalculation(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	AddQuotaToManager(t, gqm, "parent", extension.RootQuotaName, 100, 1000*GigaByte, 50, 500*GigaByte, true, true)
+	AddQuotaToManager(t, gqm, "1", "parent", 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	gqm.SetBatchRecalculateInterval(100 * time.Millisecond)
+
+	// the request is not propagated until flushed
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(30, 300*GigaByte))
+	assert.True(t, quotav1.IsZero(gqm.GetQuotaInfoByName("1").GetRequest()))
+	assert.True(t, quotav1.IsZero(gqm.GetQuotaInfoByName("parent").GetRequest()))
+
+	// the deltas of the same quota group are merged
+	pod := newTestQuotaPod("pod1", 10, 100*GigaByte)
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod, PodAccountingStatePending))
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod, PodAccountingStateGone))
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(10, 100*GigaByte))
+	assert.Len(t, gqm.requestBatch.dirtyRequests, 1)
+
+	gqm.FlushDirtyRequests()
+	assert.True(t, quotav1.Equals(createResourceList(40, 400*GigaByte), gqm.GetQuotaInfoByName("1").GetRequest()))
+	assert.True(t, quotav1.Equals(createResourceList(40, 400*GigaByte), gqm.GetQuotaInfoByName("parent").GetRequest()))
+	assert.Equal(t, int64(40), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Len(t, gqm.requestBatch.dirtyRequests, 0)
+
+	// the used is never batched
+	gqm.UpdateGroupDeltaUsed("1", createResourceList(10, 100*GigaByte))
+	assert.True(t, quotav1.Equals(createResourceList(10, 100*GigaByte), gqm.GetQuotaInfoByName("1").GetUsed()))
+
+	// the pending deltas are propagated when disabled
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(-40, -400*GigaByte))
+	gqm.SetBatchRecalculateInterval(0)
+	assert.Nil(t, gqm.requestBatch)
+	assert.True(t, quotav1.IsZero(gqm.GetQuotaInfoByName("1").GetRequest()))
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(10, 100*GigaByte))
+	assert.True(t, quotav1.Equals(createResourceList(10, 100*GigaByte), gqm.GetQuotaInfoByName("1").GetRequest()))
+}
+
+func TestGroupQuotaManager_RunBatchRecalculation(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	gqm.SetBatchRecalculateInterval(10 * time.Millisecond)
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	gqm.RunBatchRecalculation(stopCh)
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(30, 300*GigaByte))
+	assert.Eventually(t, func() bool {
+		return quotav1.Equals(createResourceList(30, 300*GigaByte), gqm.GetQuotaInfoByName("1").GetRequest())
+	}, time.Second, 10*time.Millisecond)
+}
+
+func newBenchmarkBatchGroupQuotaManager(interval time.Duration) (*GroupQuotaManager, []*v1.Pod) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(10000, 10000*GigaByte))
+	for i := 0; i < 10; i++ {
+		parentName := fmt.Sprintf("parent-%d", i)
+		AddQuotaToManager2(gqm, parentName, extension.RootQuotaName, 1000, 1000*GigaByte, 100, 100*GigaByte, true, true)
+		for j := 0; j < 10; j++ {
+			AddQuotaToManager2(gqm, fmt.Sprintf("%s-%d", parentName, j), parentName, 100, 100*GigaByte, 10, 10*GigaByte, true, false)
+		}
+	}
+	gqm.SetBatchRecalculateInterval(interval)
+	pods := make([]*v1.Pod, 1000)
+	for i := range pods {
+		pods[i] = newTestQuotaPod(fmt.Sprintf("pod-%d", i), 1, GigaByte)
+	}
+	return gqm, pods
+}
+
+// benchmarkPodChurn adds and deletes the pods of the batch workloads, and the runtime is refreshed every
+// 100 pod events, which is roughly how often the scheduler checks the quota of a churny workload.
+func benchmarkPodChurn(b *testing.B, interval time.Duration) {
+	gqm, pods := newBenchmarkBatchGroupQuotaManager(interval)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pod := pods[i%len(pods)]
+		quotaName := fmt.Sprintf("parent-%d-%d", i%10, i/10%10)
+		_ = gqm.UpdatePodAccountingState(quotaName, pod, PodAccountingStatePending)
+		_ = gqm.UpdatePodAccountingState(quotaName, pod, PodAccountingStateGone)
+		if i%100 == 0 {
+			if interval > 0 {
+				gqm.FlushDirtyRequests()
+			}
+			gqm.RefreshRuntime(quotaName)
+		}
+	}
+}
+
+func BenchmarkGroupQuotaManager_PodChurnImmediate(b *testing.B) {
+	benchmarkPodChurn(b, 0)
+}
+
+func BenchmarkGroupQuotaManager_PodChurnBatch(b *testing.B) {
+	benchmarkPodChurn(b, 100*time.Millisecond)
+}
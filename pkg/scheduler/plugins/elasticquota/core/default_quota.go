@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+// UpdateDefaultQuotaGroup updates the min and max of the DefaultQuotaGroup, which absorbs the pods without the
+// quota label. The max bounds the untracked workload, and the min is reserved from the cluster for it, so that the
+// quota trees can't share the min even if the DefaultQuotaGroup uses less.
+func (gqm *GroupQuotaManager) UpdateDefaultQuotaGroup(min, max v1.ResourceList) error {
+	for resourceName, quantity := range min {
+		if quantity.Sign() < 0 {
+			return fmt.Errorf("min of the default quota group should not be negative, resourceName:%v, got %v",
+				resourceName, quantity.String())
+		}
+		if maxQuantity, ok := max[resourceName]; ok && quantity.Cmp(maxQuantity) > 0 {
+			return fmt.Errorf("min of the default quota group should not be larger than max, resourceName:%v, min:%v, max:%v",
+				resourceName, quantity.String(), maxQuantity.String())
+		}
+	}
+
+	gqm.hierarchyUpdateLock.Lock()
+	defer gqm.hierarchyUpdateLock.Unlock()
+
+	quotaInfo := gqm.quotaInfoMap[extension.DefaultQuotaName]
+	quotaInfo.lock.Lock()
+	quotaInfo.setMaxQuotaNoLock(max)
+	quotaInfo.setOriginalMinQuotaNoLock(min)
+	quotaInfo.setAutoScaleMinQuotaNoLock(min)
+	quotaInfo.lock.Unlock()
+
+	gqm.updateClusterTotalResourceNoLock(v1.ResourceList{})
+	gqm.rebuildQuotaSnapshotsNoLock()
+	klog.V(3).Infof("Update DefaultQuotaGroup, min:%v, max:%v", min, max)
+	return nil
+}
+
+// getDefaultQuotaGroupOccupiedNoLock returns the resource taken by the DefaultQuotaGroup from the cluster, which is
+// the larger one of its used and min.
+func (gqm *GroupQuotaManager) getDefaultQuotaGroupOccupiedNoLock() v1.ResourceList {
+	quotaInfo := gqm.quotaInfoMap[extension.DefaultQuotaName]
+	quotaInfo.lock.Lock()
+	defer quotaInfo.lock.Unlock()
+	return quotav1.Max(quotaInfo.CalculateInfo.Used, quotaInfo.CalculateInfo.OriginalMin)
+}
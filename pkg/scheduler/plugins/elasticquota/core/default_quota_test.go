@@ -0,0 +1,51 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestGroupQuotaManager_UpdateDefaultQuotaGroupMin(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 100, 1000*GigaByte, 0, 0, true, false)
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(100, 1000*GigaByte))
+	assert.Equal(t, int64(100), cpuValue(gqm.RefreshRuntime("1")))
+
+	assert.Error(t, gqm.UpdateDefaultQuotaGroup(createResourceList(-1, 0), createResourceList(50, 500*GigaByte)))
+	assert.Error(t, gqm.UpdateDefaultQuotaGroup(createResourceList(60, 0), createResourceList(50, 500*GigaByte)))
+
+	// the min of the default quota group is reserved even if it's unused
+	assert.NoError(t, gqm.UpdateDefaultQuotaGroup(createResourceList(30, 300*GigaByte), createResourceList(50, 500*GigaByte)))
+	assert.Equal(t, int64(70), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(50), cpuValue(gqm.RefreshRuntime(extension.DefaultQuotaName)))
+
+	// the used beyond the min takes more
+	gqm.UpdateGroupDeltaUsed(extension.DefaultQuotaName, createResourceList(40, 100*GigaByte))
+	assert.Equal(t, int64(60), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(700*GigaByte), memoryValue(gqm.RefreshRuntime("1")))
+
+	// the admission of the unlabeled pods is bounded by the max
+	admission, err := gqm.CheckQuotaAdmission("", createResourceList(20, 0))
+	assert.NoError(t, err)
+	assert.False(t, admission.Admitted)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+// QuotaDominantShare is the dominant resource share of a quota group, i.e. the max share of its used among all the
+// resource dimensions of the resource it competes for, which is the runtime of its parent, or the cluster total
+// resource for the children of the root. A quota group can't game the fairness by requesting little of an abundant
+// dimension when the shared resource is distributed by the dominant share.
+type QuotaDominantShare struct {
+	Name     string          `json:"name"`
+	Resource v1.ResourceName `json:"resource,omitempty"`
+	Share    float64         `json:"share"`
+}
+
+// GetQuotaDominantShares returns the dominant shares of all the quota groups in the quota tree sorted by name.
+func (gqm *GroupQuotaManager) GetQuotaDominantShares() []*QuotaDominantShare {
+	gqm.hierarchyUpdateLock.RLock()
+	defer gqm.hierarchyUpdateLock.RUnlock()
+
+	return gqm.getQuotaDominantSharesNoLock()
+}
+
+func (gqm *GroupQuotaManager) getQuotaDominantSharesNoLock() []*QuotaDominantShare {
+	snapshots := gqm.ListQuotaSnapshots()
+	runtimes := make(map[string]v1.ResourceList, len(snapshots))
+	for _, snapshot := range snapshots {
+		runtimes[snapshot.Name] = snapshot.Runtime
+	}
+
+	var shares []*QuotaDominantShare
+	for _, snapshot := range snapshots {
+		if snapshot.Name == extension.SystemQuotaName || snapshot.Name == extension.DefaultQuotaName {
+			continue
+		}
+		capacity := runtimes[snapshot.ParentName]
+		if snapshot.ParentName == extension.RootQuotaName {
+			// the root-level quota group scoped to a node pool shares the capacity of the pool
+			capacity = gqm.totalResourceExceptSystemAndDefaultUsed
+			if quotaInfo := gqm.getQuotaInfoByNameNoLock(snapshot.Name); quotaInfo != nil {
+				capacity = gqm.getParentTreeTotalResourceNoLock(quotaInfo)
+			}
+		}
+		resourceName, share := dominantShare(snapshot.Used, capacity)
+		shares = append(shares, &QuotaDominantShare{
+			Name:     snapshot.Name,
+			Resource: resourceName,
+			Share:    share,
+		})
+	}
+	return shares
+}
+
+// dominantShare returns the resource dimension with the max share of used in capacity and the share, the dimension
+// without capacity is ignored.
+func dominantShare(used, capacity v1.ResourceList) (v1.ResourceName, float64) {
+	var dominantResource v1.ResourceName
+	var maxShare float64
+	for resourceName, quantity := range used {
+		total, ok := capacity[resourceName]
+		if !ok || total.MilliValue() <= 0 {
+			continue
+		}
+		share := float64(quantity.MilliValue()) / float64(total.MilliValue())
+		if share > maxShare || share == maxShare && dominantResource != "" && resourceName < dominantResource {
+			dominantResource = resourceName
+			maxShare = share
+		}
+	}
+	return dominantResource, maxShare
+}
+
+// QuotaDominantShareHandler serves the dominant shares of all the quota groups, e.g. GET /quotaDominantShares
+func QuotaDominantShareHandler(gqm *GroupQuotaManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gqm.GetQuotaDominantShares())
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+)
+
+func TestDominantShare(t *testing.T) {
+	capacity := createResourceList(100, 1000*GigaByte)
+	tests := []struct {
+		name         string
+		used         v1.ResourceList
+		capacity     v1.ResourceList
+		wantResource v1.ResourceName
+		wantShare    float64
+	}{
+		{
+			name:         "cpu is dominant",
+			used:         createResourceList(50, 100*GigaByte),
+			capacity:     capacity,
+			wantResource: v1.ResourceCPU,
+			wantShare:    0.5,
+		},
+		{
+			name:         "memory is dominant",
+			used:         createResourceList(10, 600*GigaByte),
+			capacity:     capacity,
+			wantResource: v1.ResourceMemory,
+			wantShare:    0.6,
+		},
+		{
+			name:         "the dimension without capacity is ignored",
+			used:         createResourceList(10, 600*GigaByte),
+			capacity:     createResourceList(100, 0),
+			wantResource: v1.ResourceCPU,
+			wantShare:    0.1,
+		},
+		{
+			name:     "nothing used",
+			used:     v1.ResourceList{},
+			capacity: capacity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resourceName, share := dominantShare(tt.used, tt.capacity)
+			assert.Equal(t, tt.wantResource, resourceName)
+			assert.InDelta(t, tt.wantShare, share, 1e-9)
+		})
+	}
+}
+
+func TestGroupQuotaManager_GetQuotaDominantShares(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	AddQuotaToManager(t, gqm, "2", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	gqm.UpdateGroupDeltaUsed("1", createResourceList(50, 100*GigaByte))
+	gqm.UpdateGroupDeltaUsed("2", createResourceList(10, 600*GigaByte))
+
+	assert.Equal(t, []*QuotaDominantShare{
+		{Name: "1", Resource: v1.ResourceCPU, Share: 0.5},
+		{Name: "2", Resource: v1.ResourceMemory, Share: 0.6},
+	}, gqm.GetQuotaDominantShares())
+
+	engine := gin.New()
+	gqm.RegisterEndpoints(engine.Group("/"))
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/quotaDominantShares", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var shares []*QuotaDominantShare
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &shares))
+	assert.Equal(t, gqm.GetQuotaDominantShares(), shares)
+}
+
+func TestGroupQuotaManager_RuntimeCalculateStrategyPolicy(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	parent := CreateQuota("parent", extension.RootQuotaName, 100, 1000*GigaByte, 100, 1000*GigaByte, true, true)
+	parent.Annotations[extension.AnnotationQuotaPolicy] = fmt.Sprintf(`{"runtimeCalculateStrategy":%q}`, config.RuntimeCalculateStrategyPriorityStrict)
+	assert.NoError(t, gqm.UpdateQuota(parent, false))
+	AddQuotaToManager(t, gqm, "1", "parent", 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	AddQuotaToManager(t, gqm, "2", "parent", 200, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(100, 100*GigaByte))
+	gqm.UpdateGroupDeltaRequest("2", createResourceList(100, 100*GigaByte))
+
+	// the children of parent are distributed by priority, quota 2 with the larger shared weight gets all the rest
+	assert.Equal(t, string(config.RuntimeCalculateStrategyPriorityStrict), gqm.runtimeQuotaCalculatorMap["parent"].strategy.Name())
+	assert.Equal(t, int64(20), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(80), cpuValue(gqm.RefreshRuntime("2")))
+
+	// the strategy of the policy takes precedence over the one of the GroupQuotaManager
+	assert.NoError(t, gqm.SetRuntimeCalculateStrategy(string(config.RuntimeCalculateStrategyDominantResourceFairness)))
+	assert.Equal(t, string(config.RuntimeCalculateStrategyPriorityStrict), gqm.runtimeQuotaCalculatorMap["parent"].strategy.Name())
+	assert.Equal(t, string(config.RuntimeCalculateStrategyDominantResourceFairness), gqm.runtimeQuotaCalculatorMap[extension.RootQuotaName].strategy.Name())
+}
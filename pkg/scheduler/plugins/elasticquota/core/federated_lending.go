@@ -0,0 +1,162 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+// SetFederatedLendingPolicy sets the policy of the lending between the root quota trees, nil disables it.
+func (gqm *GroupQuotaManager) SetFederatedLendingPolicy(policy *extension.FederatedLendingPolicy) {
+	gqm.hierarchyUpdateLock.Lock()
+	defer gqm.hierarchyUpdateLock.Unlock()
+
+	gqm.federatedLendingPolicy = policy.DeepCopy()
+	rootCalculator := gqm.runtimeQuotaCalculatorMap[extension.RootQuotaName]
+	rootCalculator.SetStrategy(gqm.newRuntimeCalculateStrategyNoLock(extension.RootQuotaName))
+	klog.V(3).Infof("Set FederatedLendingPolicy, policy:%+v", gqm.federatedLendingPolicy)
+}
+
+// newFederatedLendingStrategy wraps the strategy of the root quota group if the federated lending is enabled.
+func newFederatedLendingStrategy(base RuntimeCalculateStrategy, policy *extension.FederatedLendingPolicy) RuntimeCalculateStrategy {
+	if policy == nil || !policy.Enabled {
+		return base
+	}
+	strategy := &federatedLendingStrategy{base: base}
+	if len(policy.Members) > 0 {
+		strategy.members = make(map[string]bool, len(policy.Members))
+		for _, member := range policy.Members {
+			strategy.members[member] = true
+		}
+	}
+	return strategy
+}
+
+// federatedLendingStrategy distributes the resource of the root quota group in two phases. At first the base strategy
+// runs with the members keeping their idle min, so the idle min of the members is never lent to the other trees.
+// Then the idle min of the members is lent to the members requesting more than their runtime quota by the shared
+// weight, no more than the demand of the borrowers. Since the runtime quota is recalculated whenever the request
+// changes, the lent resource is reclaimed as soon as the demand of the lender returns.
+type federatedLendingStrategy struct {
+	base RuntimeCalculateStrategy
+	// members are the names of the root quota trees joining the federation, nil means all of them
+	members map[string]bool
+}
+
+func (s *federatedLendingStrategy) Name() string {
+	return s.base.Name()
+}
+
+func (s *federatedLendingStrategy) isMember(name string) bool {
+	return s.members == nil || s.members[name]
+}
+
+func (s *federatedLendingStrategy) Calculate(totalResource v1.ResourceList, quotaNodes map[v1.ResourceName][]QuotaNode) {
+	keepIdleMinNodes := make(map[v1.ResourceName][]QuotaNode, len(quotaNodes))
+	for resKey, nodes := range quotaNodes {
+		wrapped := make([]QuotaNode, 0, len(nodes))
+		for _, node := range nodes {
+			if s.isMember(node.Name()) {
+				node = &nonLendingQuotaNode{QuotaNode: node}
+			}
+			wrapped = append(wrapped, node)
+		}
+		keepIdleMinNodes[resKey] = wrapped
+	}
+	s.base.Calculate(totalResource, keepIdleMinNodes)
+
+	for _, nodes := range quotaNodes {
+		var lenders, borrowers []QuotaNode
+		var idle, borrowersRuntime int64
+		for _, node := range nodes {
+			if !s.isMember(node.Name()) {
+				continue
+			}
+			if node.RuntimeQuota() > node.Request() {
+				lenders = append(lenders, node)
+				idle += node.RuntimeQuota() - node.Request()
+			} else if node.RuntimeQuota() < node.Request() {
+				borrowers = append(borrowers, &fixedMinQuotaNode{QuotaNode: node, min: node.RuntimeQuota()})
+				borrowersRuntime += node.RuntimeQuota()
+			}
+		}
+		if idle <= 0 || len(borrowers) == 0 {
+			continue
+		}
+
+		redistributeByWeight(borrowersRuntime+idle, borrowers)
+		var lent int64
+		for _, node := range borrowers {
+			lent += node.RuntimeQuota()
+		}
+		lent -= borrowersRuntime
+		reclaimIdleMin(lenders, idle, lent)
+	}
+}
+
+// reclaimIdleMin takes the lent resource from the lenders in proportion to their idle min.
+func reclaimIdleMin(lenders []QuotaNode, idle, lent int64) {
+	if lent <= 0 {
+		return
+	}
+	sort.Slice(lenders, func(i, j int) bool {
+		return lenders[i].Name() < lenders[j].Name()
+	})
+	remaining := lent
+	for _, node := range lenders {
+		lenderIdle := node.RuntimeQuota() - node.Request()
+		taken := int64(float64(lenderIdle) * float64(lent) / float64(idle))
+		node.SetRuntimeQuota(node.RuntimeQuota() - taken)
+		remaining -= taken
+	}
+	// the rounding remainder
+	for _, node := range lenders {
+		if remaining <= 0 {
+			break
+		}
+		taken := node.RuntimeQuota() - node.Request()
+		if taken > remaining {
+			taken = remaining
+		}
+		node.SetRuntimeQuota(node.RuntimeQuota() - taken)
+		remaining -= taken
+	}
+}
+
+// nonLendingQuotaNode keeps the idle min of the quota group.
+type nonLendingQuotaNode struct {
+	QuotaNode
+}
+
+func (n *nonLendingQuotaNode) AllowLentResource() bool {
+	return false
+}
+
+// fixedMinQuotaNode guarantees the runtime quota calculated before as the min of the quota group.
+type fixedMinQuotaNode struct {
+	QuotaNode
+	min int64
+}
+
+func (n *fixedMinQuotaNode) Min() int64 {
+	return n.min
+}
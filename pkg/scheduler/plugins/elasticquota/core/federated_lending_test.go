@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestGroupQuotaManager_SetFederatedLendingPolicy(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	AddQuotaToManager(t, gqm, "a", extension.RootQuotaName, 100, 1000*GigaByte, 40, 400*GigaByte, false, false)
+	AddQuotaToManager(t, gqm, "b", extension.RootQuotaName, 100, 1000*GigaByte, 40, 400*GigaByte, false, false)
+	AddQuotaToManager(t, gqm, "c", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, false, false)
+	gqm.UpdateGroupDeltaRequest("a", createResourceList(10, 100*GigaByte))
+	gqm.UpdateGroupDeltaRequest("b", createResourceList(80, 800*GigaByte))
+
+	// the trees don't lend their resource
+	assert.Equal(t, int64(40), cpuValue(gqm.RefreshRuntime("a")))
+	assert.Equal(t, int64(40), cpuValue(gqm.RefreshRuntime("b")))
+	assert.Equal(t, int64(20), cpuValue(gqm.RefreshRuntime("c")))
+
+	// the idle min of a is lent to b, c is not in the federation
+	gqm.SetFederatedLendingPolicy(&extension.FederatedLendingPolicy{
+		Enabled: true,
+		Members: []string{"a", "b"},
+	})
+	assert.Equal(t, int64(10), cpuValue(gqm.RefreshRuntime("a")))
+	assert.Equal(t, int64(70), cpuValue(gqm.RefreshRuntime("b")))
+	assert.Equal(t, int64(20), cpuValue(gqm.RefreshRuntime("c")))
+
+	// the lent resource is reclaimed when the demand of a returns
+	gqm.UpdateGroupDeltaRequest("a", createResourceList(20, 200*GigaByte))
+	assert.Equal(t, int64(30), cpuValue(gqm.RefreshRuntime("a")))
+	assert.Equal(t, int64(50), cpuValue(gqm.RefreshRuntime("b")))
+
+	// all the trees join the federation, the idle min of a and c is lent to b
+	gqm.SetFederatedLendingPolicy(&extension.FederatedLendingPolicy{Enabled: true})
+	assert.Equal(t, int64(30), cpuValue(gqm.RefreshRuntime("a")))
+	assert.Equal(t, int64(70), cpuValue(gqm.RefreshRuntime("b")))
+	assert.Equal(t, int64(0), cpuValue(gqm.RefreshRuntime("c")))
+
+	gqm.SetFederatedLendingPolicy(nil)
+	assert.Equal(t, int64(40), cpuValue(gqm.RefreshRuntime("a")))
+	assert.Equal(t, int64(40), cpuValue(gqm.RefreshRuntime("b")))
+}
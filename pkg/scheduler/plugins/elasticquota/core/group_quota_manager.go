@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	quotav1 "k8s.io/apiserver/pkg/quota/v1"
@@ -27,12 +29,14 @@ import (
 	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
 )
 
 type GroupQuotaManager struct {
 	// hierarchyUpdateLock used for resourceKeys/quotaInfoMap/quotaTreeWrapper change
 	hierarchyUpdateLock sync.RWMutex
-	// totalResource without systemQuotaGroup and DefaultQuotaGroup's used Quota
+	// totalResource without systemQuotaGroup and DefaultQuotaGroup's used Quota, the min of DefaultQuotaGroup
+	// is excluded too if it's larger than the used
 	totalResourceExceptSystemAndDefaultUsed v1.ResourceList
 	// totalResource with systemQuotaGroup and DefaultQuotaGroup's used Quota
 	totalResource v1.ResourceList
@@ -45,8 +49,47 @@ type GroupQuotaManager struct {
 	// quotaTopoNodeMap only stores the topology of the quota
 	quotaTopoNodeMap     map[string]*QuotaTopoNode
 	scaleMinQuotaEnabled bool
+	// minQuotaOversellRatio is the default oversell ratio of the quota trees' minQuota
+	minQuotaOversellRatio float64
 	// scaleMinQuotaManager is used when overRootResource
 	scaleMinQuotaManager *ScaleMinQuotaManager
+	// runtimeCalculateStrategyName is the strategy used by all runtimeQuotaCalculators, empty means the default
+	runtimeCalculateStrategyName string
+	// federatedLendingPolicy is the policy of the lending between the root quota trees, nil means disabled
+	federatedLendingPolicy *extension.FederatedLendingPolicy
+	// minQuotaPriorityClasses are the priority classes whose request is counted toward the min, nil means all
+	minQuotaPriorityClasses map[extension.PriorityClass]struct{}
+	// podAccountingCache tracks the pods counted in the request/used of the quota groups
+	podAccountingCache *podAccountingCache
+	// sharedWeightOverrides is the SharedWeight of the quota groups overridden by the SharedWeightProvider
+	sharedWeightOverrides map[string]v1.ResourceList
+	// usageDecay lowers the SharedWeight of the quota groups which have consumed lots of shared resource recently,
+	// nil means disabled
+	usageDecay *usageDecay
+	// nodeAllocatableMap records the allocatable of the nodes counted in the totalResource
+	nodeAllocatableMap map[string]v1.ResourceList
+	// nodeLabelsMap records the labels of the nodes to select the nodes of the node pools
+	nodeLabelsMap map[string]map[string]string
+	// nodePools are the node pools the root-level quota groups are scoped to, key is the node selector
+	nodePools map[string]*nodePool
+	// nodePoolsTotalResource is the allocatable of the nodes in any node pool
+	nodePoolsTotalResource v1.ResourceList
+	// reservationAccountingCache tracks the Reservations charged to the request/used of the quota groups, its lock
+	// is acquired after the lock of the podAccountingCache
+	reservationAccountingCache *reservationAccountingCache
+	// quotaReservationLock protects quotaReservations, it's acquired after the lock of the podAccountingCache
+	quotaReservationLock sync.Mutex
+	// quotaReservations are the quota reserved for the groups of pods atomically, key is the reservation name
+	quotaReservations map[string]*QuotaReservation
+	// requestBatch accumulates the request delta of the dirty quota groups, nil means the request delta is
+	// propagated immediately
+	requestBatch *requestBatch
+	// quotaSnapshots stores the quotaSnapshotTable, which publishes the immutable snapshots of the quota groups
+	// for the lock-free readers
+	quotaSnapshots atomic.Value
+	// runtimeHistory keeps the recent runtime, used and request of the quota groups, nil means not recorded
+	runtimeHistory       *quotaRuntimeHistory
+	quotaSnapshotVersion int64
 	once                 sync.Once
 }
 
@@ -59,15 +102,66 @@ func NewGroupQuotaManager(systemGroupMax, defaultGroupMax v1.ResourceList) *Grou
 		runtimeQuotaCalculatorMap:               make(map[string]*RuntimeQuotaCalculator),
 		quotaTopoNodeMap:                        make(map[string]*QuotaTopoNode),
 		scaleMinQuotaManager:                    NewScaleMinQuotaManager(),
+		podAccountingCache:                      newPodAccountingCache(),
+		reservationAccountingCache:              newReservationAccountingCache(),
+		sharedWeightOverrides:                   make(map[string]v1.ResourceList),
+		nodeAllocatableMap:                      make(map[string]v1.ResourceList),
+		nodeLabelsMap:                           make(map[string]map[string]string),
+		nodePools:                               make(map[string]*nodePool),
+		nodePoolsTotalResource:                  v1.ResourceList{},
+		quotaReservations:                       make(map[string]*QuotaReservation),
 	}
 	quotaManager.quotaInfoMap[extension.SystemQuotaName] = NewQuotaInfo(false, true, extension.SystemQuotaName, "")
 	quotaManager.quotaInfoMap[extension.SystemQuotaName].setMaxQuotaNoLock(systemGroupMax)
 	quotaManager.quotaInfoMap[extension.DefaultQuotaName] = NewQuotaInfo(false, true, extension.DefaultQuotaName, "")
 	quotaManager.quotaInfoMap[extension.DefaultQuotaName].setMaxQuotaNoLock(defaultGroupMax)
 	quotaManager.runtimeQuotaCalculatorMap[extension.RootQuotaName] = NewRuntimeQuotaCalculator(extension.RootQuotaName)
+	quotaManager.rebuildQuotaSnapshotsNoLock()
 	return quotaManager
 }
 
+// NewGroupQuotaManagerWithArgs creates the GroupQuotaManager configured by the ElasticQuotaArgs, the args should
+// have been defaulted and validated.
+func NewGroupQuotaManagerWithArgs(args *config.ElasticQuotaArgs) (*GroupQuotaManager, error) {
+	gqm := NewGroupQuotaManager(args.SystemQuotaGroupMax, args.DefaultQuotaGroupMax)
+	gqm.SetScaleMinQuotaEnabled(true)
+	if err := gqm.UpdateDefaultQuotaGroup(args.DefaultQuotaGroupMin, args.DefaultQuotaGroupMax); err != nil {
+		return nil, err
+	}
+	if args.RuntimeCalculateStrategy != "" {
+		if err := gqm.SetRuntimeCalculateStrategy(string(args.RuntimeCalculateStrategy)); err != nil {
+			return nil, err
+		}
+	}
+	if args.MinQuotaOversellPercent != nil {
+		gqm.SetMinQuotaOversellRatio(float64(*args.MinQuotaOversellPercent) / 100)
+	}
+	if len(args.MinQuotaPriorityClasses) > 0 {
+		priorityClasses := make([]extension.PriorityClass, 0, len(args.MinQuotaPriorityClasses))
+		for _, priorityClass := range args.MinQuotaPriorityClasses {
+			priorityClasses = append(priorityClasses, extension.PriorityClass(priorityClass))
+		}
+		gqm.SetMinQuotaPriorityClasses(priorityClasses)
+	}
+	gqm.SetPodAccountingPolicy(args.PodAccounting)
+	if args.ReservationAccountingPolicy != "" {
+		gqm.SetReservationAccountingPolicy(args.ReservationAccountingPolicy)
+	}
+	if args.BatchRecalculateInterval != nil {
+		gqm.SetBatchRecalculateInterval(args.BatchRecalculateInterval.Duration)
+	}
+	if args.FederatedLending != nil {
+		gqm.SetFederatedLendingPolicy(&extension.FederatedLendingPolicy{
+			Enabled: true,
+			Members: args.FederatedLending.Members,
+		})
+	}
+	if args.UsageDecay != nil {
+		gqm.SetUsageDecay(args.UsageDecay.HalfLife.Duration, float64(args.UsageDecay.PenaltyPercent)/100)
+	}
+	return gqm, nil
+}
+
 func (gqm *GroupQuotaManager) SetScaleMinQuotaEnabled(flag bool) {
 	gqm.hierarchyUpdateLock.Lock()
 	defer gqm.hierarchyUpdateLock.Unlock()
@@ -76,6 +170,79 @@ func (gqm *GroupQuotaManager) SetScaleMinQuotaEnabled(flag bool) {
 	klog.V(3).Infof("Set ScaleMinQuotaEnabled, flag:%v", gqm.scaleMinQuotaEnabled)
 }
 
+// SetMinQuotaOversellRatio sets the default oversell ratio of all quota trees, which allows the sum of the children's
+// minQuota to be up to ratio times of the parent's resource. The quota group with its own ratio overrides it.
+func (gqm *GroupQuotaManager) SetMinQuotaOversellRatio(ratio float64) {
+	gqm.hierarchyUpdateLock.Lock()
+	defer gqm.hierarchyUpdateLock.Unlock()
+
+	gqm.minQuotaOversellRatio = ratio
+	if rootNode, ok := gqm.quotaTopoNodeMap[extension.RootQuotaName]; ok {
+		gqm.updateMinQuotaOversellRatioRecursiveNoLock(rootNode, ratio)
+	} else {
+		gqm.scaleMinQuotaManager.UpdateMinQuotaOversellRatio(extension.RootQuotaName, ratio)
+	}
+	for nodeSelector := range gqm.nodePools {
+		gqm.scaleMinQuotaManager.UpdateMinQuotaOversellRatio(nodePoolTreeName(nodeSelector), ratio)
+	}
+	klog.V(3).Infof("Set MinQuotaOversellRatio, ratio:%v", gqm.minQuotaOversellRatio)
+}
+
+// SetRuntimeCalculateStrategy sets the strategy to distribute the resource of the parent quota group to its children,
+// the strategy should have been registered by RegisterRuntimeCalculateStrategy.
+func (gqm *GroupQuotaManager) SetRuntimeCalculateStrategy(name string) error {
+	if _, err := NewRuntimeCalculateStrategy(name); err != nil {
+		return err
+	}
+
+	gqm.hierarchyUpdateLock.Lock()
+	defer gqm.hierarchyUpdateLock.Unlock()
+
+	gqm.runtimeCalculateStrategyName = name
+	for treeName, runtimeQuotaCalculator := range gqm.runtimeQuotaCalculatorMap {
+		runtimeQuotaCalculator.SetStrategy(gqm.newRuntimeCalculateStrategyNoLock(treeName))
+		klog.V(5).Infof("Set RuntimeCalculateStrategy of %v, strategy:%v", treeName, runtimeQuotaCalculator.strategy.Name())
+	}
+	klog.V(3).Infof("Set RuntimeCalculateStrategy, strategy:%v", name)
+	return nil
+}
+
+// newRuntimeQuotaCalculatorNoLock creates the runtimeQuotaCalculator with the strategy of the quota group
+func (gqm *GroupQuotaManager) newRuntimeQuotaCalculatorNoLock(treeName string) *RuntimeQuotaCalculator {
+	runtimeQuotaCalculator := NewRuntimeQuotaCalculator(treeName)
+	runtimeQuotaCalculator.SetStrategy(gqm.newRuntimeCalculateStrategyNoLock(treeName))
+	return runtimeQuotaCalculator
+}
+
+// newRuntimeCalculateStrategyNoLock creates the strategy to distribute the resource of the quota group, the strategy
+// of the root quota group lends the idle min between the root quota trees if the federated lending is enabled.
+func (gqm *GroupQuotaManager) newRuntimeCalculateStrategyNoLock(treeName string) RuntimeCalculateStrategy {
+	strategy := gqm.newBaseRuntimeCalculateStrategyNoLock(treeName)
+	if treeName == extension.RootQuotaName {
+		return newFederatedLendingStrategy(strategy, gqm.federatedLendingPolicy)
+	}
+	return strategy
+}
+
+// newBaseRuntimeCalculateStrategyNoLock creates the strategy by name, the strategy of the quota group's effective
+// policy takes precedence over the strategy of the GroupQuotaManager.
+func (gqm *GroupQuotaManager) newBaseRuntimeCalculateStrategyNoLock(treeName string) RuntimeCalculateStrategy {
+	name := gqm.runtimeCalculateStrategyName
+	if quotaInfo := gqm.getQuotaInfoByNameNoLock(treeName); quotaInfo != nil &&
+		quotaInfo.EffectivePolicy != nil && quotaInfo.EffectivePolicy.RuntimeCalculateStrategy != "" {
+		name = quotaInfo.EffectivePolicy.RuntimeCalculateStrategy
+	}
+	if name == "" {
+		return newDefaultRuntimeCalculateStrategy()
+	}
+	strategy, err := NewRuntimeCalculateStrategy(name)
+	if err != nil {
+		klog.Errorf("failed to create RuntimeCalculateStrategy %v of %v, use the default, err: %v", name, treeName, err)
+		return newDefaultRuntimeCalculateStrategy()
+	}
+	return strategy
+}
+
 func (gqm *GroupQuotaManager) UpdateClusterTotalResource(deltaRes v1.ResourceList) {
 	gqm.hierarchyUpdateLock.Lock()
 	defer gqm.hierarchyUpdateLock.Unlock()
@@ -87,7 +254,8 @@ func (gqm *GroupQuotaManager) UpdateClusterTotalResource(deltaRes v1.ResourceLis
 func (gqm *GroupQuotaManager) updateClusterTotalResourceNoLock(deltaRes v1.ResourceList) {
 	gqm.totalResource = quotav1.Add(gqm.totalResource, deltaRes)
 
-	sysAndDefaultUsed := gqm.quotaInfoMap[extension.DefaultQuotaName].GetUsed()
+	// the min of DefaultQuotaGroup is reserved even if it's unused
+	sysAndDefaultUsed := gqm.getDefaultQuotaGroupOccupiedNoLock()
 	sysAndDefaultUsed = quotav1.Add(sysAndDefaultUsed, gqm.quotaInfoMap[extension.SystemQuotaName].GetUsed())
 	totalResNoSysOrDefault := quotav1.Subtract(gqm.totalResource, sysAndDefaultUsed)
 
@@ -95,7 +263,7 @@ func (gqm *GroupQuotaManager) updateClusterTotalResourceNoLock(deltaRes v1.Resou
 
 	if !quotav1.IsZero(diffRes) {
 		gqm.totalResourceExceptSystemAndDefaultUsed = totalResNoSysOrDefault.DeepCopy()
-		gqm.runtimeQuotaCalculatorMap[extension.RootQuotaName].SetClusterTotalResource(totalResNoSysOrDefault)
+		gqm.runtimeQuotaCalculatorMap[extension.RootQuotaName].SetClusterTotalResource(gqm.getRootTotalResourceNoLock())
 		klog.V(3).Infof("UpdateClusterResource finish totalResourceExceptSystemAndDefaultUsed:%v", gqm.totalResourceExceptSystemAndDefaultUsed)
 	}
 }
@@ -111,7 +279,7 @@ func (gqm *GroupQuotaManager) UpdateGroupDeltaRequest(quotaName string, deltaReq
 	gqm.hierarchyUpdateLock.RLock()
 	defer gqm.hierarchyUpdateLock.RUnlock()
 
-	gqm.updateGroupDeltaRequestNoLock(quotaName, deltaReq)
+	gqm.markGroupDeltaRequestNoLock(quotaName, deltaReq)
 }
 
 // updateGroupDeltaRequestNoLock no need lock gqm.lock
@@ -132,7 +300,7 @@ func (gqm *GroupQuotaManager) updateGroupDeltaRequestNoLock(quotaName string, de
 func (gqm *GroupQuotaManager) updateGroupDeltaRequestTopoRecursiveNoLock(deltaReq v1.ResourceList, curToAllParInfos []*QuotaInfo) {
 	for i := 0; i < len(curToAllParInfos); i++ {
 		curQuotaInfo := curToAllParInfos[i]
-		directParRuntimeCalculatorPtr := gqm.getRuntimeQuotaCalculatorByNameNoLock(curQuotaInfo.ParentName)
+		directParRuntimeCalculatorPtr := gqm.getParentRuntimeQuotaCalculatorNoLock(curQuotaInfo)
 		if directParRuntimeCalculatorPtr == nil {
 			klog.Errorf("treeWrapper not exist!  parentName:%v", curQuotaInfo.Name, curQuotaInfo.ParentName)
 			return
@@ -145,6 +313,7 @@ func (gqm *GroupQuotaManager) updateGroupDeltaRequestTopoRecursiveNoLock(deltaRe
 		if directParRuntimeCalculatorPtr.NeedUpdateOneGroupRequest(curQuotaInfo) {
 			directParRuntimeCalculatorPtr.UpdateOneGroupRequest(curQuotaInfo)
 		}
+		gqm.publishQuotaSnapshotNoLock(curQuotaInfo)
 	}
 }
 
@@ -173,6 +342,7 @@ func (gqm *GroupQuotaManager) updateGroupDeltaUsedNoLock(quotaName string, delta
 	for i := 0; i < allQuotaInfoLen; i++ {
 		quotaInfo := curToAllParInfos[i]
 		quotaInfo.addUsedNonNegativeNoLock(delta)
+		gqm.publishQuotaSnapshotNoLock(quotaInfo)
 	}
 }
 
@@ -184,6 +354,8 @@ func (gqm *GroupQuotaManager) RefreshRuntime(quotaName string) v1.ResourceList {
 }
 
 func (gqm *GroupQuotaManager) refreshRuntimeNoLock(quotaName string) v1.ResourceList {
+	defer recordRuntimeCalculationLatency(time.Now())
+
 	quotaInfo := gqm.getQuotaInfoByNameNoLock(quotaName)
 	if quotaInfo == nil {
 		return nil
@@ -197,10 +369,10 @@ func (gqm *GroupQuotaManager) refreshRuntimeNoLock(quotaName string) v1.Resource
 
 	defer gqm.scopedLockForQuotaInfo(curToAllParInfos)()
 
-	totalRes := gqm.totalResourceExceptSystemAndDefaultUsed.DeepCopy()
+	totalRes := gqm.getParentTreeTotalResourceNoLock(curToAllParInfos[len(curToAllParInfos)-1])
 	for i := len(curToAllParInfos) - 1; i >= 0; i-- {
 		quotaInfo = curToAllParInfos[i]
-		parRuntimeQuotaCalculator := gqm.getRuntimeQuotaCalculatorByNameNoLock(quotaInfo.ParentName)
+		parRuntimeQuotaCalculator := gqm.getParentRuntimeQuotaCalculatorNoLock(quotaInfo)
 		if parRuntimeQuotaCalculator == nil {
 			klog.Errorf("treeWrapper not exist! parentQuotaName:%v", quotaInfo.ParentName)
 			return nil
@@ -214,15 +386,21 @@ func (gqm *GroupQuotaManager) refreshRuntimeNoLock(quotaName string) v1.Resource
 		// 1. execute scaleMin logic with totalRes and update scaledMin if needed
 		if gqm.scaleMinQuotaEnabled {
 			needScale, newMinQuota := gqm.scaleMinQuotaManager.GetScaledMinQuota(
-				totalRes, quotaInfo.ParentName, quotaInfo.Name)
+				totalRes, gqm.getParentTreeNameNoLock(quotaInfo), quotaInfo.Name)
 			if needScale {
+				oldLimitReq := quotaInfo.getLimitRequestNoLock()
 				gqm.updateOneGroupAutoScaleMinQuotaNoLock(quotaInfo, newMinQuota)
+				// the borrow limit is relative to the scaled min, the parents see the change of the limited request
+				if deltaReq := quotav1.Subtract(quotaInfo.getLimitRequestNoLock(), oldLimitReq); !quotav1.IsZero(deltaReq) {
+					gqm.updateGroupDeltaRequestTopoRecursiveNoLock(deltaReq, curToAllParInfos[i+1:])
+				}
 			}
 		}
 
 		// 2. update parent's runtimeQuota
 		if quotaInfo.RuntimeVersion != parRuntimeQuotaCalculator.GetVersion() {
 			parRuntimeQuotaCalculator.UpdateOneGroupRuntimeQuota(quotaInfo)
+			gqm.publishQuotaSnapshotNoLock(quotaInfo)
 		}
 		newSubGroupsTotalRes := quotaInfo.CalculateInfo.Runtime.DeepCopy()
 
@@ -242,7 +420,8 @@ func (gqm *GroupQuotaManager) refreshRuntimeNoLock(quotaName string) v1.Resource
 func (gqm *GroupQuotaManager) updateOneGroupAutoScaleMinQuotaNoLock(quotaInfo *QuotaInfo, newMinRes v1.ResourceList) {
 	if !quotav1.Equals(quotaInfo.CalculateInfo.AutoScaleMin, newMinRes) {
 		quotaInfo.setAutoScaleMinQuotaNoLock(newMinRes)
-		gqm.runtimeQuotaCalculatorMap[quotaInfo.ParentName].UpdateOneGroupMinQuota(quotaInfo)
+		gqm.getParentRuntimeQuotaCalculatorNoLock(quotaInfo).UpdateOneGroupMinQuota(quotaInfo)
+		gqm.publishQuotaSnapshotNoLock(quotaInfo)
 	}
 }
 
@@ -309,6 +488,9 @@ func (gqm *GroupQuotaManager) UpdateQuota(quota *v1alpha1.ElasticQuota, isDelete
 		delete(gqm.quotaInfoMap, quotaName)
 	} else {
 		newQuotaInfo := NewQuotaInfoFromQuota(quota)
+		if err := gqm.checkPriorityTierNoLock(newQuotaInfo); err != nil {
+			return err
+		}
 		// update the local quotaInfo's crd
 		if localQuotaInfo, exist := gqm.quotaInfoMap[quotaName]; exist {
 			localQuotaInfo.UpdateQuotaInfoFromRemote(newQuotaInfo)
@@ -324,11 +506,17 @@ func (gqm *GroupQuotaManager) UpdateQuota(quota *v1alpha1.ElasticQuota, isDelete
 func (gqm *GroupQuotaManager) updateQuotaGroupConfigNoLock() {
 	// rebuild gqm.quotaTopoNodeMap
 	gqm.buildSubParGroupTopoNoLock()
+	// rebuild the node pools of the root-level quota groups
+	gqm.buildNodePoolsNoLock()
+	// resolve the policies inherited from the parents
+	gqm.updateEffectivePolicyRecursiveNoLock(gqm.quotaTopoNodeMap[extension.RootQuotaName], newRootQuotaPolicy())
 	// reset gqm.runtimeQuotaCalculator
 	gqm.resetAllGroupQuotaNoLock()
+	// the topology may change, publish all the quota groups
+	gqm.rebuildQuotaSnapshotsNoLock()
 }
 
-//BuildSubParGroupTopoNoLock reBuild a nodeTree from root, no need to lock gqm.lock
+// BuildSubParGroupTopoNoLock reBuild a nodeTree from root, no need to lock gqm.lock
 func (gqm *GroupQuotaManager) buildSubParGroupTopoNoLock() {
 	//rebuild QuotaTopoNodeMap
 	gqm.quotaTopoNodeMap = make(map[string]*QuotaTopoNode)
@@ -357,6 +545,7 @@ func (gqm *GroupQuotaManager) buildSubParGroupTopoNoLock() {
 // ResetAllGroupQuotaNoLock no need to lock gqm.lock
 func (gqm *GroupQuotaManager) resetAllGroupQuotaNoLock() {
 	childRequestMap, childUsedMap := make(quotaResMapType), make(quotaResMapType)
+	childRequestByPriorityMap, childUsedByPriorityMap := map[string]PriorityResourceList{}, map[string]PriorityResourceList{}
 	for quotaName, topoNode := range gqm.quotaTopoNodeMap {
 		if quotaName == extension.RootQuotaName {
 			continue
@@ -365,6 +554,8 @@ func (gqm *GroupQuotaManager) resetAllGroupQuotaNoLock() {
 		if !topoNode.quotaInfo.IsParent {
 			childRequestMap[quotaName] = topoNode.quotaInfo.CalculateInfo.Request.DeepCopy()
 			childUsedMap[quotaName] = topoNode.quotaInfo.CalculateInfo.Used.DeepCopy()
+			childRequestByPriorityMap[quotaName] = topoNode.quotaInfo.CalculateInfo.RequestByPriority.DeepCopy()
+			childUsedByPriorityMap[quotaName] = topoNode.quotaInfo.CalculateInfo.UsedByPriority.DeepCopy()
 		}
 		topoNode.quotaInfo.clearForResetNoLock()
 		topoNode.quotaInfo.lock.Unlock()
@@ -373,9 +564,15 @@ func (gqm *GroupQuotaManager) resetAllGroupQuotaNoLock() {
 	// clear old runtimeQuotaCalculator
 	gqm.runtimeQuotaCalculatorMap = make(map[string]*RuntimeQuotaCalculator)
 	// reset runtimeQuotaCalculator
-	gqm.runtimeQuotaCalculatorMap[extension.RootQuotaName] = NewRuntimeQuotaCalculator(extension.RootQuotaName)
-	gqm.runtimeQuotaCalculatorMap[extension.RootQuotaName].SetClusterTotalResource(gqm.totalResourceExceptSystemAndDefaultUsed)
+	gqm.runtimeQuotaCalculatorMap[extension.RootQuotaName] = gqm.newRuntimeQuotaCalculatorNoLock(extension.RootQuotaName)
+	for nodeSelector := range gqm.nodePools {
+		treeName := nodePoolTreeName(nodeSelector)
+		gqm.runtimeQuotaCalculatorMap[treeName] = gqm.newRuntimeQuotaCalculatorNoLock(treeName)
+		gqm.scaleMinQuotaManager.UpdateMinQuotaOversellRatio(treeName, gqm.minQuotaOversellRatio)
+	}
+	gqm.refreshNodePoolsTotalResourceNoLock()
 	rootNode := gqm.quotaTopoNodeMap[extension.RootQuotaName]
+	gqm.updateMinQuotaOversellRatioRecursiveNoLock(rootNode, gqm.minQuotaOversellRatio)
 	gqm.resetAllGroupQuotaRecursiveNoLock(rootNode)
 	gqm.updateResourceKeyNoLock()
 
@@ -384,6 +581,34 @@ func (gqm *GroupQuotaManager) resetAllGroupQuotaNoLock() {
 		if !topoNode.quotaInfo.IsParent {
 			gqm.updateGroupDeltaRequestNoLock(quotaName, childRequestMap[quotaName])
 			gqm.updateGroupDeltaUsedNoLock(quotaName, childUsedMap[quotaName])
+			for priorityClass, request := range childRequestByPriorityMap[quotaName] {
+				gqm.updateGroupDeltaPriorityNoLock(quotaName, priorityClass, request, nil)
+			}
+			for priorityClass, used := range childUsedByPriorityMap[quotaName] {
+				gqm.updateGroupDeltaPriorityNoLock(quotaName, priorityClass, nil, used)
+			}
+		}
+	}
+	// the quota groups without any request of the counted priority classes are limited too
+	if gqm.minQuotaPriorityClasses != nil {
+		for quotaName, topoNode := range gqm.quotaTopoNodeMap {
+			if quotaName == extension.RootQuotaName {
+				continue
+			}
+			topoNode.quotaInfo.lock.Lock()
+			gqm.updateMinCountedRequestNoLock(topoNode.quotaInfo)
+			topoNode.quotaInfo.lock.Unlock()
+		}
+	}
+	// the reserved is requested even if there is no request, pass it to the parents as the base of the request
+	for quotaName, topoNode := range gqm.quotaTopoNodeMap {
+		if quotaName == extension.RootQuotaName || quotav1.IsZero(topoNode.quotaInfo.CalculateInfo.Reserved) {
+			continue
+		}
+		// refresh the request of the quota group in its parent's calculator, then add the reserved to the parent
+		gqm.updateGroupDeltaRequestNoLock(quotaName, v1.ResourceList{})
+		if topoNode.quotaInfo.ParentName != extension.RootQuotaName {
+			gqm.updateGroupDeltaRequestNoLock(topoNode.quotaInfo.ParentName, topoNode.quotaInfo.CalculateInfo.Reserved)
 		}
 	}
 }
@@ -392,7 +617,7 @@ func (gqm *GroupQuotaManager) resetAllGroupQuotaNoLock() {
 func (gqm *GroupQuotaManager) resetAllGroupQuotaRecursiveNoLock(rootNode *QuotaTopoNode) {
 	childGroupQuotaInfos := rootNode.GetChildGroupQuotaInfos()
 	for subName, topoNode := range childGroupQuotaInfos {
-		gqm.runtimeQuotaCalculatorMap[subName] = NewRuntimeQuotaCalculator(subName)
+		gqm.runtimeQuotaCalculatorMap[subName] = gqm.newRuntimeQuotaCalculatorNoLock(subName)
 
 		gqm.updateOneGroupMaxQuotaNoLock(topoNode.quotaInfo)
 		gqm.updateMinQuotaNoLock(topoNode.quotaInfo)
@@ -402,19 +627,31 @@ func (gqm *GroupQuotaManager) resetAllGroupQuotaRecursiveNoLock(rootNode *QuotaT
 	}
 }
 
-//updateOneGroupMaxQuotaNoLock no need to lock gqm.lock
+// updateMinQuotaOversellRatioRecursiveNoLock propagates the oversell ratio from top to bottom, the quota group
+// without its own ratio inherits the ratio of its parent. no need to lock gqm.lock
+func (gqm *GroupQuotaManager) updateMinQuotaOversellRatioRecursiveNoLock(topoNode *QuotaTopoNode, ratio float64) {
+	if topoNode.quotaInfo.MinQuotaOversellRatio > 0 {
+		ratio = topoNode.quotaInfo.MinQuotaOversellRatio
+	}
+	gqm.scaleMinQuotaManager.UpdateMinQuotaOversellRatio(topoNode.name, ratio)
+	for _, childTopoNode := range topoNode.GetChildGroupQuotaInfos() {
+		gqm.updateMinQuotaOversellRatioRecursiveNoLock(childTopoNode, ratio)
+	}
+}
+
+// updateOneGroupMaxQuotaNoLock no need to lock gqm.lock
 func (gqm *GroupQuotaManager) updateOneGroupMaxQuotaNoLock(quotaInfo *QuotaInfo) {
 	quotaInfo.lock.Lock()
 	defer quotaInfo.lock.Unlock()
 
-	runtimeQuotaCalculator := gqm.getRuntimeQuotaCalculatorByNameNoLock(quotaInfo.ParentName)
+	runtimeQuotaCalculator := gqm.getParentRuntimeQuotaCalculatorNoLock(quotaInfo)
 	runtimeQuotaCalculator.UpdateOneGroupMaxQuota(quotaInfo)
 }
 
 // updateMinQuotaNoLock no need to lock gqm.lock
 func (gqm *GroupQuotaManager) updateMinQuotaNoLock(quotaInfo *QuotaInfo) {
 	gqm.updateOneGroupOriginalMinQuotaNoLock(quotaInfo)
-	gqm.scaleMinQuotaManager.Update(quotaInfo.ParentName, quotaInfo.Name,
+	gqm.scaleMinQuotaManager.Update(gqm.getParentTreeNameNoLock(quotaInfo), quotaInfo.Name,
 		quotaInfo.CalculateInfo.OriginalMin, gqm.scaleMinQuotaEnabled)
 }
 
@@ -424,7 +661,7 @@ func (gqm *GroupQuotaManager) updateOneGroupOriginalMinQuotaNoLock(quotaInfo *Qu
 	defer quotaInfo.lock.Unlock()
 
 	quotaInfo.setAutoScaleMinQuotaNoLock(quotaInfo.CalculateInfo.OriginalMin)
-	gqm.runtimeQuotaCalculatorMap[quotaInfo.ParentName].UpdateOneGroupMinQuota(quotaInfo)
+	gqm.getParentRuntimeQuotaCalculatorNoLock(quotaInfo).UpdateOneGroupMinQuota(quotaInfo)
 }
 
 // updateOneGroupSharedWeightNoLock no need to lock gqm.lock
@@ -432,7 +669,29 @@ func (gqm *GroupQuotaManager) updateOneGroupSharedWeightNoLock(quotaInfo *QuotaI
 	quotaInfo.lock.Lock()
 	defer quotaInfo.lock.Unlock()
 
-	gqm.runtimeQuotaCalculatorMap[quotaInfo.ParentName].UpdateOneGroupSharedWeight(quotaInfo)
+	if _, ok := gqm.sharedWeightOverrides[quotaInfo.Name]; ok || gqm.usageDecay != nil {
+		quotaInfo.setSharedWeightNoLock(gqm.getEffectiveSharedWeightNoLock(quotaInfo))
+	}
+	gqm.getParentRuntimeQuotaCalculatorNoLock(quotaInfo).UpdateOneGroupSharedWeight(quotaInfo)
+}
+
+// getBaseSharedWeightNoLock returns the SharedWeight overridden by the SharedWeightProvider, or the SharedWeight
+// of the quota's annotation. quotaInfo.lock should be held.
+func (gqm *GroupQuotaManager) getBaseSharedWeightNoLock(quotaInfo *QuotaInfo) v1.ResourceList {
+	if sharedWeight, ok := gqm.sharedWeightOverrides[quotaInfo.Name]; ok {
+		return sharedWeight
+	}
+	return quotaInfo.CalculateInfo.OriginalSharedWeight
+}
+
+// getEffectiveSharedWeightNoLock returns the SharedWeight used by the runtimeQuotaCalculator, which is the base
+// SharedWeight lowered by the usage decay. quotaInfo.lock should be held.
+func (gqm *GroupQuotaManager) getEffectiveSharedWeightNoLock(quotaInfo *QuotaInfo) v1.ResourceList {
+	sharedWeight := gqm.getBaseSharedWeightNoLock(quotaInfo)
+	if gqm.usageDecay == nil {
+		return sharedWeight.DeepCopy()
+	}
+	return gqm.usageDecay.decaySharedWeight(quotaInfo.Name, sharedWeight)
 }
 
 func (gqm *GroupQuotaManager) updateResourceKeyNoLock() {
@@ -29,9 +29,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
 )
 
 const (
@@ -573,6 +575,77 @@ func TestGroupQuotaManager_MultiUpdateQuotaRequest_WithScaledMinQuota2(t *testin
 	assert.Equal(t, createResourceList(66, 200*GigaByte/3), quotaInfo.CalculateInfo.AutoScaleMin)
 }
 
+// TestGroupQuotaManager_MultiUpdateQuotaRequest_WithMinQuotaOversellRatio test scaledMinQuota when the children's
+// sum of the minQuota is allowed to be oversold.
+func TestGroupQuotaManager_MultiUpdateQuotaRequest_WithMinQuotaOversellRatio(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.scaleMinQuotaEnabled = true
+	gqm.UpdateClusterTotalResource(createResourceList(200, 200*GigaByte))
+
+	quota := CreateQuota("p", "root", 1000, 1000*GigaByte, 300, 300*GigaByte, true, true)
+	quota.Annotations[extension.AnnotationMinQuotaOversellRatio] = "1.5"
+	assert.NoError(t, gqm.UpdateQuota(quota, false))
+	AddQuotaToManager(t, gqm, "a", "p", 1000, 1000*GigaByte, 100, 100*GigaByte, true, false)
+	AddQuotaToManager(t, gqm, "b", "p", 1000, 1000*GigaByte, 100, 100*GigaByte, true, false)
+	AddQuotaToManager(t, gqm, "c", "p", 1000, 1000*GigaByte, 100, 100*GigaByte, true, false)
+
+	request := createResourceList(200, 200*GigaByte)
+	gqm.UpdateGroupDeltaRequest("a", request)
+	gqm.UpdateGroupDeltaRequest("b", request)
+	gqm.UpdateGroupDeltaRequest("c", request)
+
+	// the root tree has no oversell ratio, the minQuota of "p" is scaled to the totalRes,
+	// but the children of "p" can be oversold up to 1.5 times of the runtime of "p".
+	assert.Equal(t, createResourceList(200, 200*GigaByte), gqm.RefreshRuntime("p"))
+	assert.Equal(t, createResourceList(100, 100*GigaByte), gqm.RefreshRuntime("a"))
+	assert.Equal(t, createResourceList(100, 100*GigaByte), gqm.RefreshRuntime("b"))
+	assert.Equal(t, createResourceList(100, 100*GigaByte), gqm.RefreshRuntime("c"))
+	assert.Equal(t, createResourceList(200, 200*GigaByte), gqm.GetQuotaInfoByName("p").CalculateInfo.AutoScaleMin)
+	assert.Equal(t, createResourceList(100, 100*GigaByte), gqm.GetQuotaInfoByName("a").CalculateInfo.AutoScaleMin)
+
+	// the default oversell ratio applies to the root tree
+	gqm.SetMinQuotaOversellRatio(1.5)
+	gqm.RefreshRuntime("p")
+	assert.Equal(t, createResourceList(300, 300*GigaByte), gqm.GetQuotaInfoByName("p").CalculateInfo.AutoScaleMin)
+	assert.Equal(t, 1.5, gqm.scaleMinQuotaManager.getMinQuotaOversellRatioNoLock(extension.RootQuotaName))
+	assert.Equal(t, 1.5, gqm.scaleMinQuotaManager.getMinQuotaOversellRatioNoLock("p"))
+	assert.Equal(t, 1.5, gqm.scaleMinQuotaManager.getMinQuotaOversellRatioNoLock("a"))
+}
+
+func TestNewGroupQuotaManagerWithArgs(t *testing.T) {
+	args := &config.ElasticQuotaArgs{
+		DefaultQuotaGroupMax:        createResourceList(100, 100*GigaByte),
+		DefaultQuotaGroupMin:        createResourceList(10, 10*GigaByte),
+		SystemQuotaGroupMax:         createResourceList(200, 200*GigaByte),
+		RuntimeCalculateStrategy:    config.RuntimeCalculateStrategyPriorityStrict,
+		MinQuotaPriorityClasses:     []string{string(extension.PriorityProd)},
+		ReservationAccountingPolicy: config.ReservationAccountingPolicyRequest,
+		MinQuotaOversellPercent:     pointer.Int64Ptr(150),
+		BatchRecalculateInterval:    &metav1.Duration{Duration: time.Second},
+		FederatedLending:            &config.FederatedLendingArgs{Members: []string{"a", "b"}},
+		UsageDecay:                  &config.UsageDecayArgs{HalfLife: metav1.Duration{Duration: time.Hour}, PenaltyPercent: 50},
+	}
+	gqm, err := NewGroupQuotaManagerWithArgs(args)
+	assert.NoError(t, err)
+	assert.True(t, gqm.scaleMinQuotaEnabled)
+	assert.Equal(t, 1.5, gqm.minQuotaOversellRatio)
+	assert.Equal(t, 1.5, gqm.scaleMinQuotaManager.getMinQuotaOversellRatioNoLock(extension.RootQuotaName))
+	assert.Equal(t, string(config.RuntimeCalculateStrategyPriorityStrict), gqm.runtimeCalculateStrategyName)
+	assert.Equal(t, map[extension.PriorityClass]struct{}{extension.PriorityProd: {}}, gqm.minQuotaPriorityClasses)
+	assert.Equal(t, time.Second, gqm.requestBatch.interval)
+	assert.Equal(t, &extension.FederatedLendingPolicy{Enabled: true, Members: []string{"a", "b"}}, gqm.federatedLendingPolicy)
+	assert.Equal(t, time.Hour, gqm.usageDecay.halfLife)
+	assert.Equal(t, 0.5, gqm.usageDecay.penaltyFactor)
+	assert.Equal(t, createResourceList(200, 200*GigaByte), gqm.GetQuotaInfoByName(extension.SystemQuotaName).CalculateInfo.Max)
+	defaultQuotaInfo := gqm.GetQuotaInfoByName(extension.DefaultQuotaName)
+	assert.Equal(t, createResourceList(100, 100*GigaByte), defaultQuotaInfo.CalculateInfo.Max)
+	assert.Equal(t, createResourceList(10, 10*GigaByte), defaultQuotaInfo.CalculateInfo.OriginalMin)
+
+	args.RuntimeCalculateStrategy = "unknown"
+	_, err = NewGroupQuotaManagerWithArgs(args)
+	assert.Error(t, err)
+}
+
 func TestGroupQuotaManager_MultiUpdateQuotaUsed(t *testing.T) {
 	gqm := NewGroupQuotaManager4Test()
 
@@ -1106,10 +1179,19 @@ func NewGroupQuotaManager4Test() *GroupQuotaManager {
 		runtimeQuotaCalculatorMap:               make(map[string]*RuntimeQuotaCalculator),
 		scaleMinQuotaManager:                    NewScaleMinQuotaManager(),
 		quotaTopoNodeMap:                        make(map[string]*QuotaTopoNode),
+		podAccountingCache:                      newPodAccountingCache(),
+		reservationAccountingCache:              newReservationAccountingCache(),
+		sharedWeightOverrides:                   make(map[string]v1.ResourceList),
+		nodeAllocatableMap:                      make(map[string]v1.ResourceList),
+		nodeLabelsMap:                           make(map[string]map[string]string),
+		nodePools:                               make(map[string]*nodePool),
+		nodePoolsTotalResource:                  v1.ResourceList{},
+		quotaReservations:                       make(map[string]*QuotaReservation),
 	}
 	quotaManager.quotaInfoMap[extension.SystemQuotaName] = NewQuotaInfo(false, true, extension.SystemQuotaName, "")
 	quotaManager.quotaInfoMap[extension.DefaultQuotaName] = NewQuotaInfo(false, true, extension.DefaultQuotaName, "")
 	quotaManager.runtimeQuotaCalculatorMap[extension.RootQuotaName] = NewRuntimeQuotaCalculator(extension.RootQuotaName)
+	quotaManager.rebuildQuotaSnapshotsNoLock()
 	return quotaManager
 }
 
@@ -1145,3 +1227,102 @@ func AddQuotaToManager2(gqm *GroupQuotaManager, quotaName string, parent string,
 	gqm.UpdateQuota(quota, false)
 	return quota
 }
+
+func TestGroupQuotaManager_ReservedQuota(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	AddQuotaToManager(t, gqm, "parent", extension.RootQuotaName, 100, 1000*GigaByte, 50, 500*GigaByte, true, true)
+	quota := CreateQuota("1", "parent", 100, 1000*GigaByte, 40, 400*GigaByte, true, false)
+	quota.Annotations[extension.AnnotationReserved] = `{"cpu":30}`
+	assert.NoError(t, gqm.UpdateQuota(quota, false))
+	AddQuotaToManager(t, gqm, "2", extension.RootQuotaName, 100, 1000*GigaByte, 10, 100*GigaByte, true, false)
+	assert.Equal(t, int64(30), gqm.GetQuotaInfoByName("1").CalculateInfo.Reserved.Cpu().Value())
+
+	// the reserved is never lent even if quota 1 has no request
+	gqm.UpdateGroupDeltaRequest("2", createResourceList(100, 1000*GigaByte))
+	assert.Equal(t, int64(30), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(70), cpuValue(gqm.RefreshRuntime("2")))
+	assert.Equal(t, int64(0), memoryValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(1000*GigaByte), memoryValue(gqm.RefreshRuntime("2")))
+
+	// the request less than reserved doesn't change anything
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(10, 0))
+	assert.Equal(t, int64(30), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(70), cpuValue(gqm.RefreshRuntime("2")))
+
+	// the reserved is kept after the quotas are reset
+	AddQuotaToManager(t, gqm, "3", extension.RootQuotaName, 100, 1000*GigaByte, 0, 0, true, false)
+	assert.Equal(t, int64(30), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(70), cpuValue(gqm.RefreshRuntime("2")))
+	assert.Equal(t, int64(30), cpuValue(gqm.GetQuotaInfoByName("parent").GetRequest()))
+
+	// the request beyond the reserved competes as usual
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(40, 0))
+	assert.Equal(t, int64(50), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(50), cpuValue(gqm.RefreshRuntime("2")))
+}
+
+func TestGroupQuotaManager_BorrowLimit(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	quota := CreateQuota("1", extension.RootQuotaName, 100, 1000*GigaByte, 10, 100*GigaByte, true, false)
+	quota.Annotations[extension.AnnotationBorrowLimit] = `{"cpu":5}`
+	assert.NoError(t, gqm.UpdateQuota(quota, false))
+	AddQuotaToManager(t, gqm, "2", extension.RootQuotaName, 100, 1000*GigaByte, 10, 100*GigaByte, true, false)
+	assert.Equal(t, int64(5), gqm.GetQuotaInfoByName("1").CalculateInfo.BorrowLimit.Cpu().Value())
+
+	// the request within the borrow limit is satisfied as usual
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(12, 200*GigaByte))
+	assert.Equal(t, int64(12), cpuValue(gqm.RefreshRuntime("1")))
+
+	// quota 1 borrows at most 5 cpu beyond its min, the memory isn't limited
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(88, 800*GigaByte))
+	gqm.UpdateGroupDeltaRequest("2", createResourceList(100, 100*GigaByte))
+	assert.Equal(t, int64(15), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(85), cpuValue(gqm.RefreshRuntime("2")))
+	assert.Equal(t, int64(900*GigaByte), memoryValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(100), cpuValue(gqm.GetQuotaInfoByName("1").GetRequest()))
+
+	// the borrow limit is removed
+	delete(quota.Annotations, extension.AnnotationBorrowLimit)
+	assert.NoError(t, gqm.UpdateQuota(quota, false))
+	assert.Equal(t, int64(50), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(50), cpuValue(gqm.RefreshRuntime("2")))
+}
+
+func TestGroupQuotaManager_BorrowLimitWithScaledMinQuota(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.scaleMinQuotaEnabled = true
+	gqm.UpdateClusterTotalResource(createResourceList(200, 1000*GigaByte))
+	quota := CreateQuota("1", extension.RootQuotaName, 100, 1000*GigaByte, 40, 100*GigaByte, true, false)
+	quota.Annotations[extension.AnnotationBorrowLimit] = `{"cpu":5}`
+	assert.NoError(t, gqm.UpdateQuota(quota, false))
+	AddQuotaToManager(t, gqm, "2", extension.RootQuotaName, 100, 1000*GigaByte, 40, 100*GigaByte, true, false)
+	AddQuotaToManager(t, gqm, "3", extension.RootQuotaName, 100, 1000*GigaByte, 40, 100*GigaByte, true, false)
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(100, 0))
+	gqm.UpdateGroupDeltaRequest("2", createResourceList(100, 0))
+
+	// quota 1 borrows at most 5 cpu beyond its min 40
+	assert.Equal(t, int64(45), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(100), cpuValue(gqm.RefreshRuntime("2")))
+
+	// the min of the quotas is scaled to 60 * 40 / 120 = 20 cpu, the idle min of quota 3 is lent, and
+	// quota 1 borrows at most 5 cpu beyond its scaled min instead of its original min
+	gqm.UpdateClusterTotalResource(createResourceList(-140, 0))
+	for _, name := range []string{"1", "2", "3"} {
+		gqm.RefreshRuntime(name)
+	}
+	assert.Equal(t, int64(20), gqm.GetQuotaInfoByName("1").CalculateInfo.AutoScaleMin.Cpu().Value())
+	reqLimit := gqm.runtimeQuotaCalculatorMap[extension.RootQuotaName].groupReqLimit["1"]
+	assert.Equal(t, int64(25), reqLimit.Cpu().Value())
+	assert.Equal(t, int64(25), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(35), cpuValue(gqm.RefreshRuntime("2")))
+	assert.Equal(t, int64(0), cpuValue(gqm.RefreshRuntime("3")))
+
+	// the original min is recovered when the cluster grows again
+	gqm.UpdateClusterTotalResource(createResourceList(140, 0))
+	for _, name := range []string{"1", "2", "3"} {
+		gqm.RefreshRuntime(name)
+	}
+	assert.Equal(t, int64(45), cpuValue(gqm.RefreshRuntime("1")))
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+// nodePool is the set of the nodes selected by the node selector of the root-level quota groups. The root-level
+// quota groups scoped to the same node pool share a runtimeQuotaCalculator, whose total resource is the allocatable
+// of the nodes in the pool. The nodes in any pool are dedicated to the pools, so they are excluded from the total
+// resource of the unscoped quota groups.
+type nodePool struct {
+	selector      labels.Selector
+	totalResource v1.ResourceList
+}
+
+// nodePoolTreeName returns the name of the runtimeQuotaCalculator of the node pool, the "/" never appears in the
+// name of the quota groups so it never conflicts with them.
+func nodePoolTreeName(nodeSelector string) string {
+	return fmt.Sprintf("%s/%s", extension.RootQuotaName, nodeSelector)
+}
+
+// buildNodePoolsNoLock rebuilds the node pools by the node selectors of the root-level quota groups.
+func (gqm *GroupQuotaManager) buildNodePoolsNoLock() {
+	nodePools := map[string]*nodePool{}
+	for quotaName, quotaInfo := range gqm.quotaInfoMap {
+		if quotaInfo.ParentName != extension.RootQuotaName || quotaInfo.NodeSelector == "" {
+			continue
+		}
+		if _, ok := nodePools[quotaInfo.NodeSelector]; ok {
+			continue
+		}
+		selector, err := labels.Parse(quotaInfo.NodeSelector)
+		if err != nil {
+			klog.Errorf("failed to parse node selector %v of quota %v, err: %v", quotaInfo.NodeSelector, quotaName, err)
+			continue
+		}
+		nodePools[quotaInfo.NodeSelector] = &nodePool{selector: selector, totalResource: v1.ResourceList{}}
+	}
+	gqm.nodePools = nodePools
+}
+
+// getParentTreeNameNoLock returns the name of the runtimeQuotaCalculator which distributes the resource to the quota
+// group, which is the node pool for the scoped root-level quota groups.
+func (gqm *GroupQuotaManager) getParentTreeNameNoLock(quotaInfo *QuotaInfo) string {
+	if quotaInfo.ParentName == extension.RootQuotaName && quotaInfo.NodeSelector != "" {
+		if _, ok := gqm.nodePools[quotaInfo.NodeSelector]; ok {
+			return nodePoolTreeName(quotaInfo.NodeSelector)
+		}
+	}
+	return quotaInfo.ParentName
+}
+
+func (gqm *GroupQuotaManager) getParentRuntimeQuotaCalculatorNoLock(quotaInfo *QuotaInfo) *RuntimeQuotaCalculator {
+	return gqm.getRuntimeQuotaCalculatorByNameNoLock(gqm.getParentTreeNameNoLock(quotaInfo))
+}
+
+// getParentTreeTotalResourceNoLock returns the total resource distributed to the root-level quota group.
+func (gqm *GroupQuotaManager) getParentTreeTotalResourceNoLock(quotaInfo *QuotaInfo) v1.ResourceList {
+	if quotaInfo.ParentName == extension.RootQuotaName && quotaInfo.NodeSelector != "" {
+		if pool, ok := gqm.nodePools[quotaInfo.NodeSelector]; ok {
+			return pool.totalResource.DeepCopy()
+		}
+	}
+	return gqm.getRootTotalResourceNoLock()
+}
+
+// getRootTotalResourceNoLock returns the total resource of the unscoped root-level quota groups, which excludes
+// the allocatable of the nodes in the node pools.
+func (gqm *GroupQuotaManager) getRootTotalResourceNoLock() v1.ResourceList {
+	if len(gqm.nodePools) == 0 {
+		return gqm.totalResourceExceptSystemAndDefaultUsed.DeepCopy()
+	}
+	total := quotav1.Subtract(gqm.totalResourceExceptSystemAndDefaultUsed, gqm.nodePoolsTotalResource)
+	for _, resName := range quotav1.IsNegative(total) {
+		total[resName] = *resource.NewQuantity(0, resource.DecimalSI)
+	}
+	return total
+}
+
+// refreshNodePoolsTotalResourceNoLock recalculates the total resource of the node pools by the allocatable of the
+// nodes, and updates the total resource of the runtimeQuotaCalculators of the node pools and the root.
+func (gqm *GroupQuotaManager) refreshNodePoolsTotalResourceNoLock() {
+	for _, pool := range gqm.nodePools {
+		pool.totalResource = v1.ResourceList{}
+	}
+	nodePoolsTotalResource := v1.ResourceList{}
+	for nodeName, allocatable := range gqm.nodeAllocatableMap {
+		nodeLabels := labels.Set(gqm.nodeLabelsMap[nodeName])
+		inPool := false
+		for _, pool := range gqm.nodePools {
+			if pool.selector.Matches(nodeLabels) {
+				pool.totalResource = quotav1.Add(pool.totalResource, allocatable)
+				inPool = true
+			}
+		}
+		if inPool {
+			nodePoolsTotalResource = quotav1.Add(nodePoolsTotalResource, allocatable)
+		}
+	}
+	gqm.nodePoolsTotalResource = nodePoolsTotalResource
+
+	for nodeSelector, pool := range gqm.nodePools {
+		if runtimeQuotaCalculator := gqm.getRuntimeQuotaCalculatorByNameNoLock(nodePoolTreeName(nodeSelector)); runtimeQuotaCalculator != nil {
+			runtimeQuotaCalculator.SetClusterTotalResource(pool.totalResource)
+		}
+	}
+	gqm.runtimeQuotaCalculatorMap[extension.RootQuotaName].SetClusterTotalResource(gqm.getRootTotalResourceNoLock())
+}
+
+// GetNodePoolTotalResource returns the total resource of the node pool the quota group is scoped to, and whether the
+// quota group is scoped to a node pool.
+func (gqm *GroupQuotaManager) GetNodePoolTotalResource(quotaName string) (v1.ResourceList, bool) {
+	gqm.hierarchyUpdateLock.RLock()
+	defer gqm.hierarchyUpdateLock.RUnlock()
+
+	pool := gqm.getNodePoolNoLock(quotaName)
+	if pool == nil {
+		return nil, false
+	}
+	return pool.totalResource.DeepCopy(), true
+}
+
+// IsNodeInQuotaPool checks if the pods of the quota group can count the capacity of the node, the node must be in the
+// node pool if the quota group is scoped to one.
+func (gqm *GroupQuotaManager) IsNodeInQuotaPool(quotaName string, node *v1.Node) bool {
+	gqm.hierarchyUpdateLock.RLock()
+	defer gqm.hierarchyUpdateLock.RUnlock()
+
+	pool := gqm.getNodePoolNoLock(quotaName)
+	return pool == nil || pool.selector.Matches(labels.Set(node.Labels))
+}
+
+// getNodePoolNoLock returns the node pool of the root-level ancestor of the quota group, nil if not scoped.
+func (gqm *GroupQuotaManager) getNodePoolNoLock(quotaName string) *nodePool {
+	curToAllParInfos := gqm.getCurToAllParentGroupQuotaInfoNoLock(quotaName)
+	if len(curToAllParInfos) == 0 {
+		return nil
+	}
+	rootLevelQuotaInfo := curToAllParInfos[len(curToAllParInfos)-1]
+	if rootLevelQuotaInfo.ParentName != extension.RootQuotaName || rootLevelQuotaInfo.NodeSelector == "" {
+		return nil
+	}
+	return gqm.nodePools[rootLevelQuotaInfo.NodeSelector]
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestGroupQuotaManager_NodePool(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	newNode := func(name, pool string, cpu int64) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"pool": pool}},
+			Status:     v1.NodeStatus{Allocatable: createResourceList(cpu, 10*cpu*GigaByte)},
+		}
+	}
+	gpuNode := newNode("gpu-node", "gpu", 40)
+	cpuNode := newNode("cpu-node", "cpu", 60)
+	gqm.OnNodeAdd(gpuNode)
+	gqm.OnNodeAdd(cpuNode)
+
+	quota := CreateQuota("gpu-team", extension.RootQuotaName, 100, 1000*GigaByte, 0, 0, true, false)
+	quota.Annotations[extension.AnnotationNodeSelector] = `{"matchLabels":{"pool":"gpu"}}`
+	assert.NoError(t, gqm.UpdateQuota(quota, false))
+	AddQuotaToManager(t, gqm, "general", extension.RootQuotaName, 100, 1000*GigaByte, 0, 0, true, false)
+	gqm.UpdateGroupDeltaRequest("gpu-team", createResourceList(100, 1000*GigaByte))
+	gqm.UpdateGroupDeltaRequest("general", createResourceList(100, 1000*GigaByte))
+
+	// the scoped quota group gets the capacity of its node pool, the others get the rest
+	assert.Equal(t, int64(40), cpuValue(gqm.RefreshRuntime("gpu-team")))
+	assert.Equal(t, int64(60), cpuValue(gqm.RefreshRuntime("general")))
+	total, scoped := gqm.GetNodePoolTotalResource("gpu-team")
+	assert.True(t, scoped)
+	assert.Equal(t, int64(40), total.Cpu().Value())
+	_, scoped = gqm.GetNodePoolTotalResource("general")
+	assert.False(t, scoped)
+	assert.True(t, gqm.IsNodeInQuotaPool("gpu-team", gpuNode))
+	assert.False(t, gqm.IsNodeInQuotaPool("gpu-team", cpuNode))
+	assert.True(t, gqm.IsNodeInQuotaPool("general", gpuNode))
+
+	// the node joins the pool
+	gqm.OnNodeUpdate(newNode("cpu-node", "gpu", 60))
+	assert.Equal(t, int64(100), cpuValue(gqm.RefreshRuntime("gpu-team")))
+	assert.Equal(t, int64(0), cpuValue(gqm.RefreshRuntime("general")))
+
+	// the quota tree is no longer scoped
+	delete(quota.Annotations, extension.AnnotationNodeSelector)
+	assert.NoError(t, gqm.UpdateQuota(quota, false))
+	assert.Equal(t, int64(50), cpuValue(gqm.RefreshRuntime("gpu-team")))
+	assert.Equal(t, int64(50), cpuValue(gqm.RefreshRuntime("general")))
+	_, scoped = gqm.GetNodePoolTotalResource("gpu-team")
+	assert.False(t, scoped)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+// NodeEventHandler returns the handler which keeps the cluster total resource consistent with the allocatable of
+// the nodes. All the dimensions of the allocatable are counted, so the extended resources like nvidia.com/gpu which
+// are reported by the device plugins after the node is added are updated dynamically, and the AutoScaleMin of
+// these dimensions is recomputed on the next refreshing of the runtime.
+func (gqm *GroupQuotaManager) NodeEventHandler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if node, ok := obj.(*v1.Node); ok {
+				gqm.OnNodeAdd(node)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if node, ok := newObj.(*v1.Node); ok {
+				gqm.OnNodeUpdate(node)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			var node *v1.Node
+			switch t := obj.(type) {
+			case *v1.Node:
+				node = t
+			case cache.DeletedFinalStateUnknown:
+				node, _ = t.Obj.(*v1.Node)
+			}
+			if node != nil {
+				gqm.OnNodeDelete(node)
+			}
+		},
+	}
+}
+
+func (gqm *GroupQuotaManager) OnNodeAdd(node *v1.Node) {
+	gqm.updateNodeAllocatable(node.Name, node.Labels, node.Status.Allocatable)
+}
+
+func (gqm *GroupQuotaManager) OnNodeUpdate(node *v1.Node) {
+	gqm.updateNodeAllocatable(node.Name, node.Labels, node.Status.Allocatable)
+}
+
+func (gqm *GroupQuotaManager) OnNodeDelete(node *v1.Node) {
+	gqm.updateNodeAllocatable(node.Name, nil, nil)
+}
+
+// updateNodeAllocatable updates the cluster total resource by the change of the node's allocatable,
+// nil allocatable means the node is deleted. The total resource of the node pools is updated if the node
+// joins or leaves a node pool.
+func (gqm *GroupQuotaManager) updateNodeAllocatable(nodeName string, nodeLabels map[string]string, allocatable v1.ResourceList) {
+	gqm.hierarchyUpdateLock.Lock()
+	defer gqm.hierarchyUpdateLock.Unlock()
+
+	allocatable = extension.TranslateResourceNameAliases(allocatable)
+	oldAllocatable := gqm.nodeAllocatableMap[nodeName]
+	deltaRes := quotav1.Subtract(allocatable, oldAllocatable)
+	labelsChanged := !labels.Equals(gqm.nodeLabelsMap[nodeName], nodeLabels)
+	if allocatable == nil {
+		delete(gqm.nodeAllocatableMap, nodeName)
+		delete(gqm.nodeLabelsMap, nodeName)
+	} else {
+		gqm.nodeAllocatableMap[nodeName] = allocatable.DeepCopy()
+		gqm.nodeLabelsMap[nodeName] = copyNodeLabels(nodeLabels)
+	}
+	if len(gqm.nodePools) > 0 && (labelsChanged || !quotav1.IsZero(deltaRes)) {
+		gqm.refreshNodePoolsTotalResourceNoLock()
+	}
+	if quotav1.IsZero(deltaRes) {
+		return
+	}
+
+	klog.V(3).Infof("node %v allocatable changes, deltaRes:%v", nodeName, deltaRes)
+	gqm.updateClusterTotalResourceNoLock(deltaRes)
+}
+
+func copyNodeLabels(nodeLabels map[string]string) map[string]string {
+	copied := make(map[string]string, len(nodeLabels))
+	for key, value := range nodeLabels {
+		copied[key] = value
+	}
+	return copied
+}
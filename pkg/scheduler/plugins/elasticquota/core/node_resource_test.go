@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestGroupQuotaManager_NodeEventHandler(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.SetScaleMinQuotaEnabled(true)
+	for _, name := range []string{"1", "2"} {
+		quota := CreateQuota(name, extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+		quota.Spec.Max[extension.NvidiaGPU] = *resource.NewQuantity(8, resource.DecimalSI)
+		quota.Spec.Min[extension.NvidiaGPU] = *resource.NewQuantity(4, resource.DecimalSI)
+		assert.NoError(t, gqm.UpdateQuota(quota, false))
+		request := createResourceList(10, 10*GigaByte)
+		request[extension.NvidiaGPU] = *resource.NewQuantity(4, resource.DecimalSI)
+		gqm.UpdateGroupDeltaRequest(name, request)
+	}
+
+	newNode := func(gpu int64) *v1.Node {
+		allocatable := createResourceList(100, 1000*GigaByte)
+		if gpu > 0 {
+			allocatable[extension.NvidiaGPU] = *resource.NewQuantity(gpu, resource.DecimalSI)
+		}
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Status:     v1.NodeStatus{Allocatable: allocatable},
+		}
+	}
+	assertGPU := func(autoScaleMin, runtime int64) {
+		for _, name := range []string{"1", "2"} {
+			assert.Equal(t, runtime, resourceValue(gqm.RefreshRuntime(name), extension.NvidiaGPU))
+			quotaInfo := gqm.GetQuotaInfoByName(name)
+			assert.Equal(t, autoScaleMin, quotaInfo.CalculateInfo.AutoScaleMin.Name(extension.NvidiaGPU, resource.DecimalSI).Value())
+		}
+	}
+
+	handler := gqm.NodeEventHandler()
+	// the gpu is not reported yet, the min of gpu is scaled to zero
+	handler.OnAdd(newNode(0))
+	assert.Equal(t, int64(100), cpuValue(gqm.GetClusterTotalResource()))
+	assertGPU(0, 0)
+
+	// the device plugin reports half of the gpu
+	handler.OnUpdate(newNode(0), newNode(4))
+	assertGPU(2, 2)
+
+	// all the gpu are reported
+	handler.OnUpdate(newNode(4), newNode(8))
+	handler.OnUpdate(newNode(8), newNode(8))
+	assert.Equal(t, int64(8), resourceValue(gqm.GetClusterTotalResource(), extension.NvidiaGPU))
+	assertGPU(4, 4)
+
+	handler.OnDelete(cache.DeletedFinalStateUnknown{Key: "node-1", Obj: newNode(8)})
+	assert.Equal(t, int64(0), cpuValue(gqm.GetClusterTotalResource()))
+	assertGPU(0, 0)
+}
@@ -0,0 +1,355 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+// PodAccountingState is the state of a pod in the lifecycle of the quota accounting.
+//
+//	Pending -> Assumed -> Running -> Terminating -> Gone
+//
+// A pod in Pending, Assumed or Running state is counted in the Request of its quota group, and a pod in Assumed,
+// Running or Terminating state is counted in the Used. A Terminating pod still holds its resource, but it won't
+// request any more resource. A Gone pod is no longer tracked.
+type PodAccountingState string
+
+const (
+	PodAccountingStatePending     PodAccountingState = "Pending"
+	PodAccountingStateAssumed     PodAccountingState = "Assumed"
+	PodAccountingStateRunning     PodAccountingState = "Running"
+	PodAccountingStateTerminating PodAccountingState = "Terminating"
+	PodAccountingStateGone        PodAccountingState = "Gone"
+)
+
+// podAccountingTransitions are the valid transitions, the transition to the same state is always valid.
+var podAccountingTransitions = map[PodAccountingState][]PodAccountingState{
+	PodAccountingStatePending: {PodAccountingStateAssumed, PodAccountingStateRunning, PodAccountingStateTerminating, PodAccountingStateGone},
+	// the assumed pod goes back to Pending when the scheduler forgets it
+	PodAccountingStateAssumed:     {PodAccountingStatePending, PodAccountingStateRunning, PodAccountingStateTerminating, PodAccountingStateGone},
+	PodAccountingStateRunning:     {PodAccountingStateTerminating, PodAccountingStateGone},
+	PodAccountingStateTerminating: {PodAccountingStateGone},
+}
+
+func (s PodAccountingState) countRequest() bool {
+	return s == PodAccountingStatePending || s == PodAccountingStateAssumed || s == PodAccountingStateRunning
+}
+
+func (s PodAccountingState) countUsed() bool {
+	return s == PodAccountingStateAssumed || s == PodAccountingStateRunning || s == PodAccountingStateTerminating
+}
+
+func isValidPodAccountingTransition(from, to PodAccountingState) bool {
+	if from == to {
+		return true
+	}
+	for _, state := range podAccountingTransitions[from] {
+		if state == to {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPodAccountingState returns the state of the pod observed from the pod object, assumed indicates
+// whether the pod has been assumed by the scheduler but not bound yet.
+func GetPodAccountingState(pod *v1.Pod, assumed bool) PodAccountingState {
+	if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		return PodAccountingStateGone
+	}
+	if pod.DeletionTimestamp != nil {
+		return PodAccountingStateTerminating
+	}
+	if pod.Spec.NodeName != "" {
+		return PodAccountingStateRunning
+	}
+	if assumed {
+		return PodAccountingStateAssumed
+	}
+	return PodAccountingStatePending
+}
+
+// getPodAccountingStateByPolicy returns the state of the pod observed from the pod object under the accounting policy.
+// The pods excluded by the policy are Gone, and they are tracked again once the policy counts them.
+func getPodAccountingStateByPolicy(pod *v1.Pod, assumed bool, policy *config.PodAccountingArgs, now time.Time) PodAccountingState {
+	state := GetPodAccountingState(pod, assumed)
+	if policy == nil {
+		return state
+	}
+	switch state {
+	case PodAccountingStateGone:
+		if policy.TerminalGracePeriod.Duration > 0 && pod.DeletionTimestamp == nil {
+			if finishTime := getPodFinishTime(pod); !finishTime.IsZero() && now.Sub(finishTime) < policy.TerminalGracePeriod.Duration {
+				// the terminal pod still holds its resource, but it won't request any more resource
+				return PodAccountingStateTerminating
+			}
+		}
+	case PodAccountingStateRunning:
+		if pod.Status.Phase != v1.PodPending {
+			break
+		}
+		if policy.PendingTimeout.Duration > 0 && now.Sub(getPodScheduledTime(pod)) >= policy.PendingTimeout.Duration {
+			return PodAccountingStateGone
+		}
+		if policy.UsedCountPolicy == config.PodUsedCountPolicyRunning {
+			return PodAccountingStatePending
+		}
+	case PodAccountingStateAssumed:
+		if policy.UsedCountPolicy == config.PodUsedCountPolicyRunning {
+			return PodAccountingStatePending
+		}
+	}
+	return state
+}
+
+// getPodScheduledTime returns when the pod is bound to the node, the creation time if unknown.
+func getPodScheduledTime(pod *v1.Pod) time.Time {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionTrue && !condition.LastTransitionTime.IsZero() {
+			return condition.LastTransitionTime.Time
+		}
+	}
+	return pod.CreationTimestamp.Time
+}
+
+// getPodFinishTime returns when the last container of the terminal pod finishes, zero if unknown.
+func getPodFinishTime(pod *v1.Pod) time.Time {
+	var finishTime time.Time
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Terminated != nil && status.State.Terminated.FinishedAt.After(finishTime) {
+			finishTime = status.State.Terminated.FinishedAt.Time
+		}
+	}
+	return finishTime
+}
+
+// PodAccountingTransitionHook is called after the pod transits from one state to another. The hook is called with
+// the lock of the GroupQuotaManager held, so it must not call the GroupQuotaManager.
+type PodAccountingTransitionHook func(quotaName string, pod *v1.Pod, from, to PodAccountingState)
+
+type podAccountingInfo struct {
+	quotaName     string
+	priorityClass extension.PriorityClass
+	request       v1.ResourceList
+	state         PodAccountingState
+}
+
+// podAccountingCache tracks the state of all the pods which are counted by the quota groups.
+type podAccountingCache struct {
+	lock   sync.Mutex
+	pods   map[types.UID]*podAccountingInfo
+	hooks  []PodAccountingTransitionHook
+	policy *config.PodAccountingArgs
+}
+
+func newPodAccountingCache() *podAccountingCache {
+	return &podAccountingCache{
+		pods: make(map[types.UID]*podAccountingInfo),
+	}
+}
+
+// RegisterPodAccountingTransitionHook registers the hook called on each state transition of the pods.
+func (gqm *GroupQuotaManager) RegisterPodAccountingTransitionHook(hook PodAccountingTransitionHook) {
+	gqm.podAccountingCache.lock.Lock()
+	defer gqm.podAccountingCache.lock.Unlock()
+	gqm.podAccountingCache.hooks = append(gqm.podAccountingCache.hooks, hook)
+}
+
+// SetPodAccountingPolicy sets the policy deciding which pods are counted by the quota groups, nil restores the
+// default policy. The tracked pods follow the new policy at their next update or the next accounting verification.
+func (gqm *GroupQuotaManager) SetPodAccountingPolicy(policy *config.PodAccountingArgs) {
+	gqm.podAccountingCache.lock.Lock()
+	defer gqm.podAccountingCache.lock.Unlock()
+	gqm.podAccountingCache.policy = policy.DeepCopy()
+}
+
+// ResolvePodAccountingState returns the state of the pod observed from the pod object under the accounting policy,
+// the pod event handlers should pass it to UpdatePodAccountingState.
+func (gqm *GroupQuotaManager) ResolvePodAccountingState(pod *v1.Pod, assumed bool) PodAccountingState {
+	gqm.podAccountingCache.lock.Lock()
+	policy := gqm.podAccountingCache.policy
+	gqm.podAccountingCache.lock.Unlock()
+	return getPodAccountingStateByPolicy(pod, assumed, policy, time.Now())
+}
+
+// GetPodAccountingState returns the state of the pod tracked by the GroupQuotaManager, Gone if the pod is not tracked.
+func (gqm *GroupQuotaManager) GetPodAccountingState(uid types.UID) PodAccountingState {
+	gqm.podAccountingCache.lock.Lock()
+	defer gqm.podAccountingCache.lock.Unlock()
+	if info, ok := gqm.podAccountingCache.pods[uid]; ok {
+		return info.state
+	}
+	return PodAccountingStateGone
+}
+
+// GetQuotaPodCount returns the number of the pods tracked in the quota group.
+func (gqm *GroupQuotaManager) GetQuotaPodCount(quotaName string) int {
+	gqm.podAccountingCache.lock.Lock()
+	defer gqm.podAccountingCache.lock.Unlock()
+	count := 0
+	for _, info := range gqm.podAccountingCache.pods {
+		if info.quotaName == quotaName {
+			count++
+		}
+	}
+	return count
+}
+
+// UpdatePodAccountingState moves the pod to the state and updates the Request/Used of the quota group consistently.
+// If the pod is moved to another quota group, it is removed from the old quota group and added to the new one.
+// The request of the pod is recorded when it is tracked first, and only changed by ResizePodAccounting.
+func (gqm *GroupQuotaManager) UpdatePodAccountingState(quotaName string, pod *v1.Pod, state PodAccountingState) error {
+	gqm.hierarchyUpdateLock.RLock()
+	defer gqm.hierarchyUpdateLock.RUnlock()
+
+	if quotaName == "" {
+		quotaName = extension.DefaultQuotaName
+	}
+
+	cache := gqm.podAccountingCache
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	oldInfo, exist := cache.pods[pod.UID]
+	oldState := PodAccountingStateGone
+	if exist {
+		oldState = oldInfo.state
+	}
+	if oldState == PodAccountingStateGone && state == PodAccountingStateGone {
+		return nil
+	}
+	if exist && !isValidPodAccountingTransition(oldState, state) {
+		return fmt.Errorf("invalid pod accounting transition of pod %s/%s from %s to %s", pod.Namespace, pod.Name, oldState, state)
+	}
+	if exist && oldInfo.quotaName != quotaName && state != PodAccountingStateGone {
+		// the pod is moved to another quota group, remove it from the old one first
+		gqm.applyPodAccountingDeltaNoLock(oldInfo, oldState, PodAccountingStateGone)
+		delete(cache.pods, pod.UID)
+		exist = false
+	}
+
+	newInfo := oldInfo
+	if !exist {
+		newInfo = &podAccountingInfo{
+			quotaName:     quotaName,
+			priorityClass: extension.GetPriorityClass(pod),
+			request:       getPodRequest(pod),
+			state:         PodAccountingStateGone,
+		}
+	}
+	gqm.applyPodAccountingDeltaNoLock(newInfo, newInfo.state, state)
+	gqm.takeOverQuotaReservationNoLock(pod, newInfo, newInfo.state, state)
+	newInfo.state = state
+	if state == PodAccountingStateGone {
+		delete(cache.pods, pod.UID)
+	} else {
+		cache.pods[pod.UID] = newInfo
+	}
+
+	if oldState != state {
+		klog.V(5).Infof("pod %s/%s of quota %s transits from %s to %s", pod.Namespace, pod.Name, newInfo.quotaName, oldState, state)
+		for _, hook := range cache.hooks {
+			hook(newInfo.quotaName, pod, oldState, state)
+		}
+	}
+	return nil
+}
+
+// ResizePodAccounting updates the recorded request of the pod after its resources are resized in place, and moves
+// the difference into the Request/Used of its quota group according to its current state. It's a no-op if the pod
+// is not tracked or its request is not changed.
+func (gqm *GroupQuotaManager) ResizePodAccounting(pod *v1.Pod) {
+	gqm.hierarchyUpdateLock.RLock()
+	defer gqm.hierarchyUpdateLock.RUnlock()
+
+	cache := gqm.podAccountingCache
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	info, exist := cache.pods[pod.UID]
+	if !exist {
+		return
+	}
+	newRequest := getPodRequest(pod)
+	if quotav1.Equals(info.request, newRequest) {
+		return
+	}
+	delta := quotav1.Subtract(newRequest, info.request)
+	var deltaRequest, deltaUsed v1.ResourceList
+	if info.state.countRequest() {
+		deltaRequest = delta
+		gqm.markGroupDeltaRequestNoLock(info.quotaName, deltaRequest)
+	}
+	if info.state.countUsed() {
+		deltaUsed = delta
+		gqm.updateGroupDeltaUsedNoLock(info.quotaName, deltaUsed)
+		if info.quotaName == extension.SystemQuotaName || info.quotaName == extension.DefaultQuotaName {
+			gqm.updateClusterTotalResourceNoLock(v1.ResourceList{})
+		}
+	}
+	if deltaRequest != nil || deltaUsed != nil {
+		gqm.updateGroupDeltaPriorityNoLock(info.quotaName, info.priorityClass, deltaRequest, deltaUsed)
+	}
+	klog.V(5).Infof("pod %s/%s of quota %s is resized, request from %v to %v",
+		pod.Namespace, pod.Name, info.quotaName, util.DumpJSON(info.request), util.DumpJSON(newRequest))
+	info.request = newRequest
+}
+
+// getPodRequest returns the request of the pod counted by the quota groups, the aliased resource names are
+// translated to the canonical ones.
+func getPodRequest(pod *v1.Pod) v1.ResourceList {
+	return extension.TranslateResourceNameAliases(util.GetPodRequest(pod))
+}
+
+// applyPodAccountingDeltaNoLock updates the Request/Used of the quota group by the difference between the states.
+// The breakdown by the priority class is updated immediately even if the request delta is batched.
+func (gqm *GroupQuotaManager) applyPodAccountingDeltaNoLock(info *podAccountingInfo, from, to PodAccountingState) {
+	var deltaRequest, deltaUsed v1.ResourceList
+	if from.countRequest() != to.countRequest() {
+		deltaRequest = info.request
+		if from.countRequest() {
+			deltaRequest = quotav1.Subtract(v1.ResourceList{}, info.request)
+		}
+		gqm.markGroupDeltaRequestNoLock(info.quotaName, deltaRequest)
+	}
+	if from.countUsed() != to.countUsed() {
+		deltaUsed = info.request
+		if from.countUsed() {
+			deltaUsed = quotav1.Subtract(v1.ResourceList{}, info.request)
+		}
+		gqm.updateGroupDeltaUsedNoLock(info.quotaName, deltaUsed)
+		// if systemQuotaGroup or DefaultQuotaGroup's used change, update cluster total resource.
+		if info.quotaName == extension.SystemQuotaName || info.quotaName == extension.DefaultQuotaName {
+			gqm.updateClusterTotalResourceNoLock(v1.ResourceList{})
+		}
+	}
+	if deltaRequest != nil || deltaUsed != nil {
+		gqm.updateGroupDeltaPriorityNoLock(info.quotaName, info.priorityClass, deltaRequest, deltaUsed)
+	}
+}
@@ -0,0 +1,305 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+)
+
+func newTestQuotaPod(name string, cpu, memory int64) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			UID:       types.UID("uid-" + name),
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Resources: v1.ResourceRequirements{
+						Requests: createResourceList(cpu, memory),
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGetPodAccountingState(t *testing.T) {
+	pod := newTestQuotaPod("pod-1", 1, 1)
+	assert.Equal(t, PodAccountingStatePending, GetPodAccountingState(pod, false))
+	assert.Equal(t, PodAccountingStateAssumed, GetPodAccountingState(pod, true))
+
+	pod.Spec.NodeName = "node-1"
+	assert.Equal(t, PodAccountingStateRunning, GetPodAccountingState(pod, false))
+
+	pod.DeletionTimestamp = &metav1.Time{}
+	assert.Equal(t, PodAccountingStateTerminating, GetPodAccountingState(pod, false))
+
+	pod.Status.Phase = v1.PodSucceeded
+	assert.Equal(t, PodAccountingStateGone, GetPodAccountingState(pod, false))
+}
+
+func TestGetPodAccountingStateByPolicy(t *testing.T) {
+	now := time.Now()
+	newPod := func(nodeName string, phase v1.PodPhase, age time.Duration) *v1.Pod {
+		pod := newTestQuotaPod("pod-1", 1, 1)
+		pod.Spec.NodeName = nodeName
+		pod.Status.Phase = phase
+		pod.Status.Conditions = []v1.PodCondition{
+			{Type: v1.PodScheduled, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(now.Add(-age))},
+		}
+		if phase == v1.PodSucceeded {
+			pod.Status.ContainerStatuses = []v1.ContainerStatus{
+				{State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{FinishedAt: metav1.NewTime(now.Add(-age))}}},
+			}
+		}
+		return pod
+	}
+	runningOnly := &config.PodAccountingArgs{UsedCountPolicy: config.PodUsedCountPolicyRunning}
+	scheduled := &config.PodAccountingArgs{
+		UsedCountPolicy:     config.PodUsedCountPolicyScheduled,
+		PendingTimeout:      metav1.Duration{Duration: 10 * time.Minute},
+		TerminalGracePeriod: metav1.Duration{Duration: time.Minute},
+	}
+
+	tests := []struct {
+		name    string
+		pod     *v1.Pod
+		assumed bool
+		policy  *config.PodAccountingArgs
+		want    PodAccountingState
+	}{
+		{
+			name:   "default policy",
+			pod:    newPod("node-1", v1.PodPending, time.Hour),
+			policy: nil,
+			want:   PodAccountingStateRunning,
+		},
+		{
+			name:    "assumed pod is not counted in used by running policy",
+			pod:     newPod("", v1.PodPending, 0),
+			assumed: true,
+			policy:  runningOnly,
+			want:    PodAccountingStatePending,
+		},
+		{
+			name:   "bound pod not running is not counted in used by running policy",
+			pod:    newPod("node-1", v1.PodPending, time.Second),
+			policy: runningOnly,
+			want:   PodAccountingStatePending,
+		},
+		{
+			name:   "running pod is counted by running policy",
+			pod:    newPod("node-1", v1.PodRunning, time.Hour),
+			policy: runningOnly,
+			want:   PodAccountingStateRunning,
+		},
+		{
+			name:   "pod pending on the node within timeout",
+			pod:    newPod("node-1", v1.PodPending, time.Minute),
+			policy: scheduled,
+			want:   PodAccountingStateRunning,
+		},
+		{
+			name:   "pod pending on the node exceeds timeout",
+			pod:    newPod("node-1", v1.PodPending, time.Hour),
+			policy: scheduled,
+			want:   PodAccountingStateGone,
+		},
+		{
+			name:   "terminal pod within grace period",
+			pod:    newPod("node-1", v1.PodSucceeded, 30*time.Second),
+			policy: scheduled,
+			want:   PodAccountingStateTerminating,
+		},
+		{
+			name:   "terminal pod exceeds grace period",
+			pod:    newPod("node-1", v1.PodSucceeded, 2*time.Minute),
+			policy: scheduled,
+			want:   PodAccountingStateGone,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getPodAccountingStateByPolicy(tt.pod, tt.assumed, tt.policy, now))
+		})
+	}
+}
+
+func TestGroupQuotaManager_UpdatePodAccountingState(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 50, 500, 10, 100, true, false)
+	AddQuotaToManager(t, gqm, "2", extension.RootQuotaName, 50, 500, 10, 100, true, false)
+
+	var transitions []PodAccountingState
+	gqm.RegisterPodAccountingTransitionHook(func(quotaName string, pod *v1.Pod, from, to PodAccountingState) {
+		transitions = append(transitions, to)
+	})
+
+	assertQuota := func(quotaName string, request, used v1.ResourceList) {
+		quotaInfo := gqm.GetQuotaInfoByName(quotaName)
+		// the resources released are left as zero
+		assert.True(t, quotav1.Equals(quotav1.RemoveZeros(request), quotav1.RemoveZeros(quotaInfo.GetRequest())), "request of %s: %v", quotaName, quotaInfo.GetRequest())
+		assert.True(t, quotav1.Equals(quotav1.RemoveZeros(used), quotav1.RemoveZeros(quotaInfo.GetUsed())), "used of %s: %v", quotaName, quotaInfo.GetUsed())
+	}
+
+	pod := newTestQuotaPod("pod-1", 10, 100)
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod, PodAccountingStatePending))
+	assertQuota("1", createResourceList(10, 100), createResourceList(0, 0))
+
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod, PodAccountingStateAssumed))
+	assertQuota("1", createResourceList(10, 100), createResourceList(10, 100))
+
+	// update with the same state is idempotent
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod, PodAccountingStateAssumed))
+	assertQuota("1", createResourceList(10, 100), createResourceList(10, 100))
+
+	// forget the assumed pod
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod, PodAccountingStatePending))
+	assertQuota("1", createResourceList(10, 100), createResourceList(0, 0))
+
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod, PodAccountingStateRunning))
+	assertQuota("1", createResourceList(10, 100), createResourceList(10, 100))
+
+	// running pod can't go back to pending
+	assert.Error(t, gqm.UpdatePodAccountingState("1", pod, PodAccountingStatePending))
+	assertQuota("1", createResourceList(10, 100), createResourceList(10, 100))
+
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod, PodAccountingStateTerminating))
+	assertQuota("1", createResourceList(0, 0), createResourceList(10, 100))
+	assert.Equal(t, PodAccountingStateTerminating, gqm.GetPodAccountingState(pod.UID))
+
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod, PodAccountingStateGone))
+	assertQuota("1", createResourceList(0, 0), createResourceList(0, 0))
+	assert.Equal(t, PodAccountingStateGone, gqm.GetPodAccountingState(pod.UID))
+
+	// gone twice doesn't undercount
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod, PodAccountingStateGone))
+	assertQuota("1", createResourceList(0, 0), createResourceList(0, 0))
+
+	assert.Equal(t, []PodAccountingState{
+		PodAccountingStatePending,
+		PodAccountingStateAssumed,
+		PodAccountingStatePending,
+		PodAccountingStateRunning,
+		PodAccountingStateTerminating,
+		PodAccountingStateGone,
+	}, transitions)
+
+	// the pod is moved to another quota group
+	pod2 := newTestQuotaPod("pod-2", 5, 50)
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod2, PodAccountingStateRunning))
+	assertQuota("1", createResourceList(5, 50), createResourceList(5, 50))
+	assert.NoError(t, gqm.UpdatePodAccountingState("2", pod2, PodAccountingStateRunning))
+	assertQuota("1", createResourceList(0, 0), createResourceList(0, 0))
+	assertQuota("2", createResourceList(5, 50), createResourceList(5, 50))
+}
+
+func TestGroupQuotaManager_ResizePodAccounting(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 50, 500, 10, 100, true, false)
+
+	assertQuota := func(request, used v1.ResourceList) {
+		quotaInfo := gqm.GetQuotaInfoByName("1")
+		assert.True(t, quotav1.Equals(quotav1.RemoveZeros(request), quotav1.RemoveZeros(quotaInfo.GetRequest())), "request: %v", quotaInfo.GetRequest())
+		assert.True(t, quotav1.Equals(quotav1.RemoveZeros(used), quotav1.RemoveZeros(quotaInfo.GetUsed())), "used: %v", quotaInfo.GetUsed())
+	}
+
+	// the pod not tracked is ignored
+	gqm.ResizePodAccounting(newTestQuotaPod("pod-1", 20, 200))
+	assertQuota(createResourceList(0, 0), createResourceList(0, 0))
+
+	pod := newTestQuotaPod("pod-1", 10, 100)
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod, PodAccountingStateRunning))
+	assertQuota(createResourceList(10, 100), createResourceList(10, 100))
+
+	// scale up
+	pod.Spec.Containers[0].Resources.Requests = createResourceList(20, 150)
+	gqm.ResizePodAccounting(pod)
+	assertQuota(createResourceList(20, 150), createResourceList(20, 150))
+
+	// scale down
+	pod.Spec.Containers[0].Resources.Requests = createResourceList(5, 150)
+	gqm.ResizePodAccounting(pod)
+	assertQuota(createResourceList(5, 150), createResourceList(5, 150))
+
+	// the terminating pod only counts in the used
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod, PodAccountingStateTerminating))
+	pod.Spec.Containers[0].Resources.Requests = createResourceList(8, 150)
+	gqm.ResizePodAccounting(pod)
+	assertQuota(createResourceList(0, 0), createResourceList(8, 150))
+
+	// the resized request is released when the pod is gone
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod, PodAccountingStateGone))
+	assertQuota(createResourceList(0, 0), createResourceList(0, 0))
+}
+
+func TestGroupQuotaManager_PodAccountingResourceNameAliases(t *testing.T) {
+	assert.NoError(t, extension.SetResourceNameAliases(map[string]string{"amd.com/gpu": string(extension.NvidiaGPU)}))
+	defer extension.SetResourceNameAliases(nil)
+
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 50, 500, 10, 100, true, false)
+
+	pod := newTestQuotaPod("pod-1", 10, 100)
+	pod.Spec.Containers[0].Resources.Requests["amd.com/gpu"] = *resource.NewQuantity(2, resource.DecimalSI)
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod, PodAccountingStateRunning))
+
+	used := gqm.GetQuotaInfoByName("1").GetUsed()
+	assert.NotContains(t, used, v1.ResourceName("amd.com/gpu"))
+	gpu := used[extension.NvidiaGPU]
+	assert.Equal(t, int64(2), gpu.Value())
+}
+
+func TestNewQuotaInfoFromQuotaResourceNameAliases(t *testing.T) {
+	assert.NoError(t, extension.SetResourceNameAliases(map[string]string{"amd.com/gpu": string(extension.NvidiaGPU)}))
+	defer extension.SetResourceNameAliases(nil)
+
+	quota := CreateQuota("1", extension.RootQuotaName, 50, 500, 10, 100, true, false)
+	quota.Spec.Max["amd.com/gpu"] = *resource.NewQuantity(8, resource.DecimalSI)
+	quota.Spec.Min["amd.com/gpu"] = *resource.NewQuantity(4, resource.DecimalSI)
+	quota.Annotations[extension.AnnotationSharedWeight] = `{"amd.com/gpu":"8"}`
+	quota.Annotations[extension.AnnotationReserved] = `{"amd.com/gpu":"2"}`
+
+	quotaInfo := NewQuotaInfoFromQuota(quota)
+	for name, resourceList := range map[string]v1.ResourceList{
+		"max":          quotaInfo.CalculateInfo.Max,
+		"min":          quotaInfo.CalculateInfo.OriginalMin,
+		"sharedWeight": quotaInfo.CalculateInfo.SharedWeight,
+		"reserved":     quotaInfo.CalculateInfo.Reserved,
+	} {
+		assert.NotContains(t, resourceList, v1.ResourceName("amd.com/gpu"), name)
+		assert.Contains(t, resourceList, extension.NvidiaGPU, name)
+	}
+	reserved := quotaInfo.CalculateInfo.Reserved[extension.NvidiaGPU]
+	assert.Equal(t, int64(2), reserved.Value())
+}
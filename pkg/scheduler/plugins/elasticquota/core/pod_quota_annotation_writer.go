@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+// podQuotaAnnotation is the quota group a pod is charged to and the runtime of the quota group at admission.
+type podQuotaAnnotation struct {
+	namespace string
+	name      string
+	uid       types.UID
+	quotaName string
+	runtime   v1.ResourceList
+	// scheduled means the pod is bound and the annotation can be written
+	scheduled bool
+}
+
+// PodQuotaAnnotationWriter annotates the scheduled pods with the quota group they are charged to and the runtime of
+// the quota group when they are admitted, i.e. assumed by the scheduler, for the post-hoc billing and debugging.
+// The koordlet can apply the quota-aware QoS on the node by the annotations too.
+type PodQuotaAnnotationWriter struct {
+	client   kubernetes.Interface
+	interval time.Duration
+
+	lock sync.Mutex
+	pods map[types.UID]*podQuotaAnnotation
+}
+
+// NewPodQuotaAnnotationWriter creates the writer and registers it to the pod accounting of the GroupQuotaManager.
+func NewPodQuotaAnnotationWriter(gqm *GroupQuotaManager, client kubernetes.Interface, interval time.Duration) *PodQuotaAnnotationWriter {
+	w := &PodQuotaAnnotationWriter{
+		client:   client,
+		interval: interval,
+		pods:     map[types.UID]*podQuotaAnnotation{},
+	}
+	gqm.RegisterPodAccountingTransitionHook(func(quotaName string, pod *v1.Pod, from, to PodAccountingState) {
+		w.onPodAccountingTransition(gqm, quotaName, pod, from, to)
+	})
+	return w
+}
+
+// onPodAccountingTransition records the runtime when the pod is assumed, and marks the pod ready to be annotated
+// when it's bound. It's called with the lock of the GroupQuotaManager held, so the runtime is read from the snapshot.
+func (w *PodQuotaAnnotationWriter) onPodAccountingTransition(gqm *GroupQuotaManager, quotaName string, pod *v1.Pod, from, to PodAccountingState) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	switch to {
+	case PodAccountingStateAssumed:
+		var runtime v1.ResourceList
+		if snapshot := gqm.GetQuotaSnapshot(quotaName); snapshot != nil {
+			runtime = snapshot.Runtime
+		}
+		w.pods[pod.UID] = &podQuotaAnnotation{
+			namespace: pod.Namespace,
+			name:      pod.Name,
+			uid:       pod.UID,
+			quotaName: quotaName,
+			runtime:   runtime,
+		}
+	case PodAccountingStateRunning:
+		// the pods not admitted by this scheduler, e.g. before restarting, are not annotated
+		if annotation, ok := w.pods[pod.UID]; ok {
+			annotation.scheduled = true
+		}
+	default:
+		delete(w.pods, pod.UID)
+	}
+}
+
+// Start runs the writer until stopCh is closed.
+func (w *PodQuotaAnnotationWriter) Start(stopCh <-chan struct{}) {
+	klog.Infof("start pod quota annotation writer, interval: %v", w.interval)
+	go wait.Until(w.Sync, w.interval, stopCh)
+}
+
+// Sync patches the annotations of the scheduled pods, the failed ones are retried in the next round.
+func (w *PodQuotaAnnotationWriter) Sync() {
+	w.lock.Lock()
+	var scheduled []*podQuotaAnnotation
+	for uid, annotation := range w.pods {
+		if annotation.scheduled {
+			scheduled = append(scheduled, annotation)
+			delete(w.pods, uid)
+		}
+	}
+	w.lock.Unlock()
+
+	for _, annotation := range scheduled {
+		err := w.patchPod(annotation)
+		if err == nil || errors.IsNotFound(err) || errors.IsConflict(err) {
+			continue
+		}
+		klog.Errorf("failed to annotate pod %v/%v with quota %v, err: %v", annotation.namespace, annotation.name, annotation.quotaName, err)
+		w.lock.Lock()
+		if _, ok := w.pods[annotation.uid]; !ok {
+			w.pods[annotation.uid] = annotation
+		}
+		w.lock.Unlock()
+	}
+}
+
+func (w *PodQuotaAnnotationWriter) patchPod(annotation *podQuotaAnnotation) error {
+	runtime, err := json.Marshal(annotation.runtime)
+	if err != nil {
+		return err
+	}
+	// the uid makes the patch fail with conflict if the pod is recreated with the same name
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"uid": annotation.uid,
+			"annotations": map[string]string{
+				extension.AnnotationChargedQuota:     annotation.quotaName,
+				extension.AnnotationAdmissionRuntime: string(runtime),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.client.CoreV1().Pods(annotation.namespace).Patch(context.TODO(), annotation.name,
+		types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestPodQuotaAnnotationWriter(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(40, 400*GigaByte))
+	gqm.RefreshRuntime("1")
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-1", UID: "pod-1-uid"},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Resources: v1.ResourceRequirements{Requests: createResourceList(10, 10*GigaByte)}},
+			},
+		},
+	}
+	client := fake.NewSimpleClientset(pod)
+	writer := NewPodQuotaAnnotationWriter(gqm, client, time.Second)
+
+	// the assumed pod is not annotated until it's bound
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod, PodAccountingStateAssumed))
+	admissionRuntime := gqm.GetQuotaSnapshot("1").Runtime
+	writer.Sync()
+	got, err := client.CoreV1().Pods("default").Get(context.TODO(), "pod-1", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, got.Annotations[extension.AnnotationChargedQuota])
+
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod, PodAccountingStateRunning))
+	writer.Sync()
+	got, err = client.CoreV1().Pods("default").Get(context.TODO(), "pod-1", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "1", got.Annotations[extension.AnnotationChargedQuota])
+	runtime, err := extension.GetAdmissionRuntime(got)
+	assert.NoError(t, err)
+	assert.True(t, quotav1.Equals(admissionRuntime, runtime), "runtime: %v", runtime)
+
+	// the pod not admitted by the scheduler is not annotated
+	pod2 := pod.DeepCopy()
+	pod2.Name, pod2.UID = "pod-2", "pod-2-uid"
+	_, err = client.CoreV1().Pods("default").Create(context.TODO(), pod2, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod2, PodAccountingStateRunning))
+	client.ClearActions()
+	writer.Sync()
+	assert.Empty(t, client.Actions())
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+)
+
+type agingPod struct {
+	quotaName string
+	// waitingSince is the time the pod is rejected by the quota group first
+	waitingSince time.Time
+}
+
+// PriorityAging raises the in-group scheduling priority of the pods pending long for the quota contention, so the
+// small old jobs are not starved by the continuous streams of new big ones in the same quota group. Only the order
+// of the pods in the scheduling queue is affected, the priority of the pod itself and its preemption rights are not.
+type PriorityAging struct {
+	args config.PriorityAgingArgs
+
+	lock    sync.Mutex
+	waiting map[types.UID]*agingPod
+}
+
+// NewPriorityAging creates the PriorityAging and registers it to the pod accounting of the GroupQuotaManager, the
+// pods stop aging once they leave the Pending state.
+func NewPriorityAging(gqm *GroupQuotaManager, args *config.PriorityAgingArgs) *PriorityAging {
+	a := &PriorityAging{
+		args:    *args,
+		waiting: map[types.UID]*agingPod{},
+	}
+	gqm.RegisterPodAccountingTransitionHook(func(quotaName string, pod *v1.Pod, from, to PodAccountingState) {
+		if to != PodAccountingStatePending {
+			a.Forget(pod)
+		}
+	})
+	return a
+}
+
+// OnQuotaRejected starts aging the pod when it's rejected by the quota group first.
+func (a *PriorityAging) OnQuotaRejected(quotaName string, pod *v1.Pod, now time.Time) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if waiting, ok := a.waiting[pod.UID]; ok && waiting.quotaName == quotaName {
+		return
+	}
+	a.waiting[pod.UID] = &agingPod{
+		quotaName:    quotaName,
+		waitingSince: now,
+	}
+}
+
+// Forget stops aging the pod.
+func (a *PriorityAging) Forget(pod *v1.Pod) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	delete(a.waiting, pod.UID)
+}
+
+// GetEffectivePriority returns the priority of the pod in the scheduling queue of the quota group, which is the
+// priority of the pod raised Step each Interval since it's rejected by the quota group, up to MaxBoost.
+func (a *PriorityAging) GetEffectivePriority(quotaName string, pod *v1.Pod, now time.Time) int32 {
+	priority := corev1helpers.PodPriority(pod)
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	waiting, ok := a.waiting[pod.UID]
+	if !ok || waiting.quotaName != quotaName {
+		return priority
+	}
+	effective := int64(priority) + a.getBoost(now.Sub(waiting.waitingSince))
+	if effective > math.MaxInt32 {
+		effective = math.MaxInt32
+	}
+	return int32(effective)
+}
+
+func (a *PriorityAging) getBoost(waited time.Duration) int64 {
+	if waited <= 0 || a.args.Interval.Duration <= 0 {
+		return 0
+	}
+	steps := int64(waited / a.args.Interval.Duration)
+	boost := steps * int64(a.args.Step)
+	if a.args.MaxBoost > 0 && boost > int64(a.args.MaxBoost) {
+		boost = int64(a.args.MaxBoost)
+	}
+	return boost
+}
+
+// Less orders the pods of the same quota group by the effective priority, then by the creation time.
+func (a *PriorityAging) Less(quotaName string, pod1, pod2 *v1.Pod, now time.Time) bool {
+	priority1 := a.GetEffectivePriority(quotaName, pod1, now)
+	priority2 := a.GetEffectivePriority(quotaName, pod2, now)
+	if priority1 != priority2 {
+		return priority1 > priority2
+	}
+	return pod1.CreationTimestamp.Before(&pod2.CreationTimestamp)
+}
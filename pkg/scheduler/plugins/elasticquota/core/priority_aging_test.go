@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+)
+
+func TestPriorityAging(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	aging := NewPriorityAging(gqm, &config.PriorityAgingArgs{
+		Interval: metav1.Duration{Duration: time.Minute},
+		Step:     10,
+		MaxBoost: 30,
+	})
+
+	now := time.Now()
+	newPod := func(name string, priority int32, request v1.ResourceList, created time.Time) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				UID:               types.UID(name),
+				CreationTimestamp: metav1.Time{Time: created},
+			},
+			Spec: v1.PodSpec{
+				Priority: pointer.Int32(priority),
+				Containers: []v1.Container{
+					{Resources: v1.ResourceRequirements{Requests: request}},
+				},
+			},
+		}
+	}
+	smallOldPod := newPod("small-old", 100, createResourceList(1, GigaByte), now.Add(-time.Hour))
+	bigNewPod := newPod("big-new", 120, createResourceList(50, 500*GigaByte), now)
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", smallOldPod, PodAccountingStatePending))
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", bigNewPod, PodAccountingStatePending))
+
+	// not rejected by the quota yet
+	assert.Equal(t, int32(100), aging.GetEffectivePriority("1", smallOldPod, now))
+	assert.False(t, aging.Less("1", smallOldPod, bigNewPod, now))
+
+	aging.OnQuotaRejected("1", smallOldPod, now)
+	// rejected again doesn't reset the waiting time
+	aging.OnQuotaRejected("1", smallOldPod, now.Add(time.Minute))
+	assert.Equal(t, int32(110), aging.GetEffectivePriority("1", smallOldPod, now.Add(90*time.Second)))
+	assert.Equal(t, int32(100), aging.GetEffectivePriority("2", smallOldPod, now.Add(90*time.Second)))
+	// same effective priority, the older one first
+	assert.True(t, aging.Less("1", smallOldPod, bigNewPod, now.Add(2*time.Minute)))
+	// the boost is limited by MaxBoost
+	assert.Equal(t, int32(130), aging.GetEffectivePriority("1", smallOldPod, now.Add(time.Hour)))
+	// the priority of the pod itself is not changed
+	assert.Equal(t, int32(100), *smallOldPod.Spec.Priority)
+
+	// the pod stops aging once it's assumed
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", smallOldPod, PodAccountingStateAssumed))
+	assert.Equal(t, int32(100), aging.GetEffectivePriority("1", smallOldPod, now.Add(time.Hour)))
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+// getPriorityTiersNoLock returns the distinct priority tiers of the child quota groups in descending order.
+func (qtw *RuntimeQuotaCalculator) getPriorityTiersNoLock() []int32 {
+	tierSet := map[int32]struct{}{}
+	for _, tree := range qtw.quotaTree {
+		for _, node := range tree.quotaNodes {
+			tierSet[node.tier] = struct{}{}
+		}
+	}
+	tiers := make([]int32, 0, len(tierSet))
+	for tier := range tierSet {
+		tiers = append(tiers, tier)
+	}
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i] > tiers[j]
+	})
+	return tiers
+}
+
+// calculateTieredRuntimeNoLock guarantees the min of all the child quota groups first, then distributes the rest
+// tier by tier in descending order. The configured strategy, e.g. DominantResourceFairness or PriorityStrict,
+// shares the rest among the quota groups of the same tier, so the lower tiers get nothing until the higher tiers
+// are satisfied up to their request.
+func (qtw *RuntimeQuotaCalculator) calculateTieredRuntimeNoLock(tiers []int32) {
+	//lock outside
+	remaining := map[v1.ResourceName]int64{}
+	tierNodes := map[int32]map[v1.ResourceName][]QuotaNode{}
+	tierMin := map[int32]map[v1.ResourceName]int64{}
+	for _, tier := range tiers {
+		tierNodes[tier] = map[v1.ResourceName][]QuotaNode{}
+		tierMin[tier] = map[v1.ResourceName]int64{}
+	}
+	for resKey := range qtw.resourceKeys {
+		total := qtw.totalResource[resKey]
+		remaining[resKey] = total.Value()
+		for _, node := range qtw.quotaTree[resKey].quotaNodes {
+			tierNodes[node.tier][resKey] = append(tierNodes[node.tier][resKey], node)
+		}
+		for _, tier := range tiers {
+			assigned := assignMinRuntimeQuota(tierNodes[tier][resKey])
+			tierMin[tier][resKey] = assigned
+			remaining[resKey] -= assigned
+		}
+		if remaining[resKey] < 0 {
+			remaining[resKey] = 0
+		}
+	}
+
+	for _, tier := range tiers {
+		tierTotal := v1.ResourceList{}
+		for resKey := range qtw.resourceKeys {
+			tierTotal[resKey] = *resource.NewQuantity(tierMin[tier][resKey]+remaining[resKey], resource.DecimalSI)
+		}
+		qtw.strategy.Calculate(tierTotal, tierNodes[tier])
+		for resKey, nodes := range tierNodes[tier] {
+			var runtime int64
+			for _, node := range nodes {
+				runtime += node.RuntimeQuota()
+			}
+			remaining[resKey] -= runtime - tierMin[tier][resKey]
+			if remaining[resKey] < 0 {
+				remaining[resKey] = 0
+			}
+		}
+	}
+}
+
+// ValidatePriorityTier checks that the priority tier of the quota group nests in the tree: a quota group can't
+// be in a higher tier than its parent, since the parent is the upper bound of the resource its children can get
+// in the tiers of the parent's siblings. The children of the root are not limited.
+func ValidatePriorityTier(quotaName string, tier int32, parentName string, parentTier int32) error {
+	if parentName == "" || parentName == extension.RootQuotaName {
+		return nil
+	}
+	if tier > parentTier {
+		return fmt.Errorf("priority tier %d of quota %s is higher than priority tier %d of its parent %s",
+			tier, quotaName, parentTier, parentName)
+	}
+	return nil
+}
+
+// checkPriorityTierNoLock checks the priority tier of the quota group against its parent and its children.
+func (gqm *GroupQuotaManager) checkPriorityTierNoLock(quotaInfo *QuotaInfo) error {
+	if parentInfo, exist := gqm.quotaInfoMap[quotaInfo.ParentName]; exist {
+		if err := ValidatePriorityTier(quotaInfo.Name, quotaInfo.PriorityTier, parentInfo.Name, parentInfo.PriorityTier); err != nil {
+			return err
+		}
+	}
+	for _, childInfo := range gqm.quotaInfoMap {
+		if childInfo.ParentName != quotaInfo.Name {
+			continue
+		}
+		if err := ValidatePriorityTier(childInfo.Name, childInfo.PriorityTier, quotaInfo.Name, quotaInfo.PriorityTier); err != nil {
+			return err
+		}
+	}
+	return nil
+}
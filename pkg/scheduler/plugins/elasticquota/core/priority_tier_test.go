@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+)
+
+func TestRuntimeQuotaCalculator_PriorityTier(t *testing.T) {
+	qtw := newTestRuntimeQuotaCalculator(newDefaultRuntimeCalculateStrategy())
+	qtw.quotaTree[corev1.ResourceCPU].updateTier("node4", 1)
+	qtw.calculateRuntimeNoLock()
+	// the min is guaranteed, then node4 in the higher tier is satisfied up to its request as far as possible
+	assert.Equal(t, map[string]int64{
+		"node1": 5,
+		"node2": 15,
+		"node3": 20,
+		"node4": 60,
+	}, getTestRuntimeQuota(qtw))
+
+	qtw.quotaTree[corev1.ResourceCPU].updateRequest("node4", 30)
+	qtw.calculateRuntimeNoLock()
+	// node4 is satisfied, the rest is shared by the lower tier
+	assert.Equal(t, map[string]int64{
+		"node1": 5,
+		"node2": 20,
+		"node3": 40,
+		"node4": 30,
+	}, getTestRuntimeQuota(qtw))
+}
+
+func TestRuntimeQuotaCalculator_PriorityTierWithPriorityStrict(t *testing.T) {
+	strategy, err := NewRuntimeCalculateStrategy(string(config.RuntimeCalculateStrategyPriorityStrict))
+	assert.NoError(t, err)
+	qtw := newTestRuntimeQuotaCalculator(strategy)
+	qtw.totalResource = corev1.ResourceList{
+		corev1.ResourceCPU: *resource.NewQuantity(70, resource.DecimalSI),
+	}
+	qtw.quotaTree[corev1.ResourceCPU].updateRequest("node2", 40)
+	for _, name := range []string{"node1", "node2", "node3"} {
+		qtw.quotaTree[corev1.ResourceCPU].updateTier(name, 1)
+	}
+	qtw.calculateRuntimeNoLock()
+	// the min is guaranteed, then the rest goes to the higher tier, where node2 with the larger shared weight
+	// takes all of it before node3 gets anything, which the weighted fair share would split between them
+	assert.Equal(t, map[string]int64{
+		"node1": 5,
+		"node2": 30,
+		"node3": 20,
+		"node4": 15,
+	}, getTestRuntimeQuota(qtw))
+}
+
+func TestRuntimeQuotaCalculator_PriorityTierWithDRF(t *testing.T) {
+	strategy, err := NewRuntimeCalculateStrategy(string(config.RuntimeCalculateStrategyDominantResourceFairness))
+	assert.NoError(t, err)
+	cpu, memory := corev1.ResourceCPU, corev1.ResourceMemory
+	qtw := NewRuntimeQuotaCalculator("testTreeName")
+	qtw.SetStrategy(strategy)
+	qtw.UpdateResourceKeys(map[corev1.ResourceName]struct{}{cpu: {}, memory: {}})
+	qtw.quotaTree[cpu].insert("a", 1, 8, 0, true)
+	qtw.quotaTree[memory].insert("a", 1, 10, 0, true)
+	qtw.quotaTree[cpu].insert("b", 1, 16, 0, true)
+	qtw.quotaTree[memory].insert("b", 1, 16, 0, true)
+	qtw.quotaTree[cpu].updateTier("a", 1)
+	qtw.quotaTree[memory].updateTier("a", 1)
+	qtw.totalResource = corev1.ResourceList{
+		cpu:    *resource.NewQuantity(16, resource.DecimalSI),
+		memory: *resource.NewQuantity(100, resource.DecimalSI),
+	}
+	qtw.calculateRuntimeNoLock()
+
+	got := map[string]map[corev1.ResourceName]int64{}
+	for resKey, tree := range qtw.quotaTree {
+		for name, node := range tree.quotaNodes {
+			if got[name] == nil {
+				got[name] = map[corev1.ResourceName]int64{}
+			}
+			got[name][resKey] = node.runtimeQuota
+		}
+	}
+	// a in the higher tier is satisfied first, then DRF shares the rest with b in proportion to its request of
+	// all dimensions, so b gets no more memory than cpu though the memory is idle, which the weighted fair share
+	// would give up to its request
+	assert.Equal(t, map[string]map[corev1.ResourceName]int64{
+		"a": {cpu: 8, memory: 10},
+		"b": {cpu: 8, memory: 8},
+	}, got)
+}
+
+func TestGroupQuotaManager_PriorityTier(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	AddQuotaToManager(t, gqm, "2", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(100, 100*GigaByte))
+	gqm.UpdateGroupDeltaRequest("2", createResourceList(100, 100*GigaByte))
+
+	// the same tier by default, shared by weight
+	assert.Equal(t, int64(50), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(50), cpuValue(gqm.RefreshRuntime("2")))
+
+	quota := CreateQuota("2", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	quota.Annotations[extension.AnnotationPriorityTier] = "1"
+	assert.NoError(t, gqm.UpdateQuota(quota, false))
+	assert.Equal(t, int32(1), gqm.GetQuotaInfoByName("2").PriorityTier)
+	assert.Equal(t, int64(20), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(80), cpuValue(gqm.RefreshRuntime("2")))
+}
+
+func TestGroupQuotaManager_ValidatePriorityTier(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+
+	parent := CreateQuota("parent", extension.RootQuotaName, 100, 1000*GigaByte, 0, 0, true, true)
+	parent.Annotations[extension.AnnotationPriorityTier] = "2"
+	assert.NoError(t, gqm.UpdateQuota(parent, false))
+
+	child := CreateQuota("child", "parent", 100, 1000*GigaByte, 0, 0, true, false)
+	child.Annotations[extension.AnnotationPriorityTier] = "3"
+	assert.Error(t, gqm.UpdateQuota(child, false))
+	assert.Nil(t, gqm.GetQuotaInfoByName("child"))
+
+	child.Annotations[extension.AnnotationPriorityTier] = "2"
+	assert.NoError(t, gqm.UpdateQuota(child, false))
+
+	// the parent can't be lowered below its children
+	parent.Annotations[extension.AnnotationPriorityTier] = "1"
+	assert.Error(t, gqm.UpdateQuota(parent, false))
+	assert.Equal(t, int32(2), gqm.GetQuotaInfoByName("parent").PriorityTier)
+
+	// the children of the root are not limited
+	assert.NoError(t, ValidatePriorityTier("parent", 5, extension.RootQuotaName, 0))
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/services"
+)
+
+// PriorityResourceList is the resource broken down by the priority class of the pods.
+type PriorityResourceList map[extension.PriorityClass]v1.ResourceList
+
+func (l PriorityResourceList) DeepCopy() PriorityResourceList {
+	out := make(PriorityResourceList, len(l))
+	for priorityClass, resources := range l {
+		out[priorityClass] = resources.DeepCopy()
+	}
+	return out
+}
+
+// addNonNegative adds the delta to the resource of the priority class, the priority class without any resource is removed.
+func (l PriorityResourceList) addNonNegative(priorityClass extension.PriorityClass, delta v1.ResourceList) {
+	resources := quotav1.Add(l[priorityClass], delta)
+	for _, resName := range quotav1.IsNegative(resources) {
+		resources[resName] = *resource.NewQuantity(0, resource.DecimalSI)
+	}
+	if quotav1.IsZero(resources) {
+		delete(l, priorityClass)
+		return
+	}
+	l[priorityClass] = resources
+}
+
+// sum returns the total resource of the priority classes.
+func (l PriorityResourceList) sum(priorityClasses map[extension.PriorityClass]struct{}) v1.ResourceList {
+	total := v1.ResourceList{}
+	for priorityClass := range priorityClasses {
+		total = quotav1.Add(total, l[priorityClass])
+	}
+	return total
+}
+
+// GetRequestByPriority returns the request of the quota group broken down by the priority class of the pods.
+func (qi *QuotaInfo) GetRequestByPriority() PriorityResourceList {
+	qi.lock.Lock()
+	defer qi.lock.Unlock()
+	return qi.CalculateInfo.RequestByPriority.DeepCopy()
+}
+
+// GetUsedByPriority returns the used of the quota group broken down by the priority class of the pods.
+func (qi *QuotaInfo) GetUsedByPriority() PriorityResourceList {
+	qi.lock.Lock()
+	defer qi.lock.Unlock()
+	return qi.CalculateInfo.UsedByPriority.DeepCopy()
+}
+
+// QuotaPriorityUsage is the request and the used of the quota group broken down by the priority class of the pods.
+type QuotaPriorityUsage struct {
+	Name              string               `json:"name"`
+	RequestByPriority PriorityResourceList `json:"requestByPriority,omitempty"`
+	UsedByPriority    PriorityResourceList `json:"usedByPriority,omitempty"`
+}
+
+func (gqm *GroupQuotaManager) registerPriorityUsageEndpoint(group *gin.RouterGroup) {
+	group.GET("/quotas/:quotaName/priorityUsage", func(c *gin.Context) {
+		quotaName := c.Param("quotaName")
+		quotaInfo := gqm.GetQuotaInfoByName(quotaName)
+		if quotaInfo == nil {
+			services.ResponseErrorMessage(c, http.StatusNotFound, "quota %s not found", quotaName)
+			return
+		}
+		c.JSON(http.StatusOK, &QuotaPriorityUsage{
+			Name:              quotaName,
+			RequestByPriority: quotaInfo.GetRequestByPriority(),
+			UsedByPriority:    quotaInfo.GetUsedByPriority(),
+		})
+	})
+}
+
+// SetMinQuotaPriorityClasses limits the request counted toward the min of the quota groups to the pods of the
+// priority classes, the request of the other pods competes for the shared resource only. Empty means all the
+// request is counted.
+func (gqm *GroupQuotaManager) SetMinQuotaPriorityClasses(priorityClasses []extension.PriorityClass) {
+	gqm.hierarchyUpdateLock.Lock()
+	defer gqm.hierarchyUpdateLock.Unlock()
+
+	gqm.minQuotaPriorityClasses = nil
+	if len(priorityClasses) > 0 {
+		gqm.minQuotaPriorityClasses = make(map[extension.PriorityClass]struct{}, len(priorityClasses))
+		for _, priorityClass := range priorityClasses {
+			gqm.minQuotaPriorityClasses[priorityClass] = struct{}{}
+		}
+	}
+	for _, quotaInfo := range gqm.quotaInfoMap {
+		quotaInfo.lock.Lock()
+		gqm.updateMinCountedRequestNoLock(quotaInfo)
+		quotaInfo.lock.Unlock()
+	}
+	klog.V(3).Infof("Set MinQuotaPriorityClasses, priorityClasses:%v", priorityClasses)
+}
+
+// updateGroupDeltaPriorityNoLock updates the request/used of the priority class of the quota group and all its parents.
+func (gqm *GroupQuotaManager) updateGroupDeltaPriorityNoLock(quotaName string, priorityClass extension.PriorityClass,
+	deltaRequest, deltaUsed v1.ResourceList) {
+	curToAllParInfos := gqm.getCurToAllParentGroupQuotaInfoNoLock(quotaName)
+	if len(curToAllParInfos) <= 0 {
+		return
+	}
+
+	defer gqm.scopedLockForQuotaInfo(curToAllParInfos)()
+	for _, quotaInfo := range curToAllParInfos {
+		if !quotav1.IsZero(deltaRequest) {
+			quotaInfo.CalculateInfo.RequestByPriority.addNonNegative(priorityClass, deltaRequest)
+			gqm.updateMinCountedRequestNoLock(quotaInfo)
+		}
+		if !quotav1.IsZero(deltaUsed) {
+			quotaInfo.CalculateInfo.UsedByPriority.addNonNegative(priorityClass, deltaUsed)
+		}
+		gqm.publishQuotaSnapshotNoLock(quotaInfo)
+	}
+}
+
+// updateMinCountedRequestNoLock refreshes the request counted toward the min of the quota group in its parent's
+// runtimeQuotaCalculator, the caller should hold the lock of quotaInfo.
+func (gqm *GroupQuotaManager) updateMinCountedRequestNoLock(quotaInfo *QuotaInfo) {
+	runtimeQuotaCalculator := gqm.getParentRuntimeQuotaCalculatorNoLock(quotaInfo)
+	if runtimeQuotaCalculator == nil {
+		return
+	}
+	var counted v1.ResourceList
+	if gqm.minQuotaPriorityClasses != nil {
+		counted = quotaInfo.CalculateInfo.RequestByPriority.sum(gqm.minQuotaPriorityClasses)
+	}
+	runtimeQuotaCalculator.UpdateOneGroupMinCountedRequest(quotaInfo, counted)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func newTestPriorityQuotaPod(name string, priority int32, cpu, memory int64) *v1.Pod {
+	pod := newTestQuotaPod(name, cpu, memory)
+	pod.Spec.Priority = &priority
+	return pod
+}
+
+func TestGroupQuotaManager_UsageByPriority(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000))
+	AddQuotaToManager(t, gqm, "parent", extension.RootQuotaName, 100, 1000, 50, 500, true, true)
+	AddQuotaToManager(t, gqm, "child", "parent", 100, 1000, 50, 500, true, false)
+
+	prodPod := newTestPriorityQuotaPod("prod", extension.PriorityProdValueMin, 10, 100)
+	batchPod := newTestPriorityQuotaPod("batch", extension.PriorityBatchValueMin, 20, 200)
+	assert.NoError(t, gqm.UpdatePodAccountingState("child", prodPod, PodAccountingStateRunning))
+	assert.NoError(t, gqm.UpdatePodAccountingState("child", batchPod, PodAccountingStatePending))
+
+	for _, quotaName := range []string{"child", "parent"} {
+		quotaInfo := gqm.GetQuotaInfoByName(quotaName)
+		requestByPriority := quotaInfo.GetRequestByPriority()
+		assert.Len(t, requestByPriority, 2)
+		assert.True(t, quotav1.Equals(createResourceList(10, 100), requestByPriority[extension.PriorityProd]))
+		assert.True(t, quotav1.Equals(createResourceList(20, 200), requestByPriority[extension.PriorityBatch]))
+		usedByPriority := quotaInfo.GetUsedByPriority()
+		assert.Len(t, usedByPriority, 1)
+		assert.True(t, quotav1.Equals(createResourceList(10, 100), usedByPriority[extension.PriorityProd]))
+	}
+
+	engine := gin.New()
+	gqm.RegisterEndpoints(engine.Group("/"))
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/quotas/child/priorityUsage", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	usage := &QuotaPriorityUsage{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), usage))
+	assert.Equal(t, "child", usage.Name)
+	assert.True(t, quotav1.Equals(createResourceList(20, 200), usage.RequestByPriority[extension.PriorityBatch]))
+	assert.True(t, quotav1.Equals(createResourceList(10, 100), usage.UsedByPriority[extension.PriorityProd]))
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/quotas/not-exist/priorityUsage", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// the breakdown survives the reset of the quota tree
+	AddQuotaToManager(t, gqm, "other", extension.RootQuotaName, 100, 1000, 0, 0, true, false)
+	snapshot := gqm.GetQuotaSnapshot("parent")
+	assert.True(t, quotav1.Equals(createResourceList(20, 200), snapshot.RequestByPriority[extension.PriorityBatch]))
+
+	assert.NoError(t, gqm.UpdatePodAccountingState("child", batchPod, PodAccountingStateGone))
+	assert.NoError(t, gqm.UpdatePodAccountingState("child", prodPod, PodAccountingStateTerminating))
+	requestByPriority := gqm.GetQuotaInfoByName("parent").GetRequestByPriority()
+	assert.Len(t, requestByPriority, 0)
+	usedByPriority := gqm.GetQuotaInfoByName("parent").GetUsedByPriority()
+	assert.True(t, quotav1.Equals(createResourceList(10, 100), usedByPriority[extension.PriorityProd]))
+}
+
+func TestGroupQuotaManager_SetMinQuotaPriorityClasses(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 100, 1000, 40, 400, true, false)
+	AddQuotaToManager(t, gqm, "2", extension.RootQuotaName, 100, 1000, 40, 400, true, false)
+
+	batchPod := newTestPriorityQuotaPod("batch", extension.PriorityBatchValueMin, 60, 0)
+	prodPod := newTestPriorityQuotaPod("prod", extension.PriorityProdValueMin, 60, 0)
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", batchPod, PodAccountingStatePending))
+	assert.NoError(t, gqm.UpdatePodAccountingState("2", prodPod, PodAccountingStatePending))
+
+	// all the request is counted toward the min by default
+	assert.Equal(t, int64(50), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(50), cpuValue(gqm.RefreshRuntime("2")))
+
+	// the batch request of quota 1 is not guaranteed by its min, it only competes for the shared resource
+	gqm.SetMinQuotaPriorityClasses([]extension.PriorityClass{extension.PriorityProd})
+	assert.Equal(t, int64(40), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(60), cpuValue(gqm.RefreshRuntime("2")))
+
+	// the option is kept after the quota tree is reset
+	AddQuotaToManager(t, gqm, "3", extension.RootQuotaName, 100, 1000, 0, 0, true, false)
+	assert.Equal(t, int64(40), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(60), cpuValue(gqm.RefreshRuntime("2")))
+
+	gqm.SetMinQuotaPriorityClasses(nil)
+	assert.Equal(t, int64(50), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(50), cpuValue(gqm.RefreshRuntime("2")))
+}
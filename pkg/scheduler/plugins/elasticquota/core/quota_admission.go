@@ -0,0 +1,141 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/services"
+)
+
+const (
+	AdmissionRejectReasonExceedMax     = "ExceedMax"
+	AdmissionRejectReasonExceedRuntime = "ExceedRuntime"
+	AdmissionRejectReasonQuotaDeleting = "QuotaDeleting"
+	// AdmissionRejectReasonDeviceTypeNotAllowed and AdmissionRejectReasonDeviceModelNotAllowed are reported by
+	// the device restriction of the quota group, see CheckDeviceRestriction.
+	AdmissionRejectReasonDeviceTypeNotAllowed  = "DeviceTypeNotAllowed"
+	AdmissionRejectReasonDeviceModelNotAllowed = "DeviceModelNotAllowed"
+)
+
+// QuotaAdmission is the result of the dry-run admission of a pod in a quota group.
+type QuotaAdmission struct {
+	Name       string          `json:"name"`
+	PodRequest v1.ResourceList `json:"podRequest,omitempty"`
+	Max        v1.ResourceList `json:"max,omitempty"`
+	Runtime    v1.ResourceList `json:"runtime,omitempty"`
+	Used       v1.ResourceList `json:"used,omitempty"`
+	Admitted   bool            `json:"admitted"`
+	// Reasons explains why the pod is rejected, the reason is one of the AdmissionRejectReasons followed by
+	// the exceeded resources.
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// CheckQuotaAdmission answers whether a pod requesting podRequest would be admitted by the quota group right now,
+// without changing anything. The pod is admitted if the used plus podRequest exceeds neither the max nor the
+// resource the quota group can use right now, i.e. the larger one of its runtime and its min.
+func (gqm *GroupQuotaManager) CheckQuotaAdmission(quotaName string, podRequest v1.ResourceList) (*QuotaAdmission, error) {
+	gqm.hierarchyUpdateLock.RLock()
+	defer gqm.hierarchyUpdateLock.RUnlock()
+
+	if quotaName == "" {
+		quotaName = extension.DefaultQuotaName
+	}
+	quotaInfo := gqm.getQuotaInfoByNameNoLock(quotaName)
+	if quotaInfo == nil {
+		return nil, fmt.Errorf("quota %s not found", quotaName)
+	}
+	if quotaInfo.IsParent {
+		return nil, fmt.Errorf("quota %s is a parent quota group which can't admit pods", quotaName)
+	}
+	return gqm.checkQuotaAdmissionNoLock(quotaInfo, podRequest), nil
+}
+
+func (gqm *GroupQuotaManager) checkQuotaAdmissionNoLock(quotaInfo *QuotaInfo, podRequest v1.ResourceList) *QuotaAdmission {
+	quotaName := quotaInfo.Name
+	runtime := gqm.refreshRuntimeNoLock(quotaName)
+	max := quotaInfo.GetMax()
+	used := quotaInfo.GetUsed()
+	newUsed := quotav1.Add(used, podRequest)
+	requestNames := quotav1.ResourceNames(quotav1.RemoveZeros(podRequest))
+
+	admission := &QuotaAdmission{
+		Name:       quotaName,
+		PodRequest: podRequest,
+		Max:        max,
+		Runtime:    runtime,
+		Used:       used,
+	}
+	if quotaInfo.Deleting {
+		admission.Reasons = append(admission.Reasons, AdmissionRejectReasonQuotaDeleting)
+	}
+	if exceeded := exceededResourceNames(newUsed, max, requestNames); len(exceeded) > 0 {
+		admission.Reasons = append(admission.Reasons, fmt.Sprintf("%s: %v", AdmissionRejectReasonExceedMax, exceeded))
+	}
+	usable := quotav1.Max(runtime, quotaInfo.getMinForCapacity())
+	usable = quotav1.Mask(usable, quotav1.ResourceNames(max))
+	if exceeded := exceededResourceNames(newUsed, usable, requestNames); len(exceeded) > 0 {
+		admission.Reasons = append(admission.Reasons, fmt.Sprintf("%s: %v", AdmissionRejectReasonExceedRuntime, exceeded))
+	}
+	admission.Admitted = len(admission.Reasons) == 0
+	return admission
+}
+
+// exceededResourceNames returns the sorted resources in resourceNames which are limited by limit and exceeded by used.
+func exceededResourceNames(used, limit v1.ResourceList, resourceNames []v1.ResourceName) []string {
+	var exceeded []string
+	for _, resourceName := range resourceNames {
+		limitQuantity, ok := limit[resourceName]
+		if !ok {
+			continue
+		}
+		if usedQuantity := used[resourceName]; usedQuantity.Cmp(limitQuantity) > 0 {
+			exceeded = append(exceeded, string(resourceName))
+		}
+	}
+	sort.Strings(exceeded)
+	return exceeded
+}
+
+// QuotaAdmissionHandler serves the dry-run admission of a pod in the quota group, the shape of the pod is specified
+// by the query parameters, e.g. GET /quotaAdmission/:quotaName?cpu=4&memory=8Gi
+func QuotaAdmissionHandler(gqm *GroupQuotaManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		podRequest, ok := parsePodRequestFromQuery(c)
+		if !ok {
+			return
+		}
+		quotaName := c.Param("quotaName")
+		if gqm.GetQuotaInfoByName(quotaName) == nil {
+			services.ResponseErrorMessage(c, http.StatusNotFound, "cannot find quota %s", quotaName)
+			return
+		}
+		admission, err := gqm.CheckQuotaAdmission(quotaName, podRequest)
+		if err != nil {
+			services.ResponseErrorMessage(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		c.JSON(http.StatusOK, admission)
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestGroupQuotaManager_CheckQuotaAdmission(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	AddQuotaToManager(t, gqm, "parent", extension.RootQuotaName, 100, 1000*GigaByte, 50, 500*GigaByte, true, true)
+	AddQuotaToManager(t, gqm, "1", "parent", 50, 500*GigaByte, 10, 100*GigaByte, true, false)
+	AddQuotaToManager(t, gqm, "2", "parent", 100, 1000*GigaByte, 40, 400*GigaByte, true, false)
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(40, 400*GigaByte))
+	gqm.UpdateGroupDeltaUsed("1", createResourceList(40, 400*GigaByte))
+	gqm.UpdateGroupDeltaRequest("2", createResourceList(100, 1000*GigaByte))
+
+	tests := []struct {
+		name       string
+		quotaName  string
+		cpu        int64
+		mem        int64
+		wantErr    bool
+		wantAdmit  bool
+		wantReason []string
+	}{
+		{
+			name:      "quota not found",
+			quotaName: "not-exist",
+			cpu:       1,
+			wantErr:   true,
+		},
+		{
+			name:      "parent quota",
+			quotaName: "parent",
+			cpu:       1,
+			wantErr:   true,
+		},
+		{
+			name:      "admitted within min",
+			quotaName: "2",
+			cpu:       10,
+			mem:       10 * GigaByte,
+			wantAdmit: true,
+		},
+		{
+			name:       "exceed runtime",
+			quotaName:  "1",
+			cpu:        5,
+			wantReason: []string{"ExceedRuntime: [cpu]"},
+		},
+		{
+			name:       "exceed max and runtime",
+			quotaName:  "1",
+			cpu:        20,
+			mem:        200 * GigaByte,
+			wantReason: []string{"ExceedMax: [cpu memory]", "ExceedRuntime: [cpu memory]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			admission, err := gqm.CheckQuotaAdmission(tt.quotaName, createResourceList(tt.cpu, tt.mem))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantAdmit, admission.Admitted)
+			assert.Equal(t, tt.wantReason, admission.Reasons)
+		})
+	}
+}
+
+func TestGroupQuotaManager_QuotaAdmissionEndpoint(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 50, 500*GigaByte, 10, 100*GigaByte, true, false)
+	engine := gin.New()
+	gqm.RegisterEndpoints(engine.Group("/"))
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/quotaAdmission/1?cpu=4&memory=8Gi", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	admission := &QuotaAdmission{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), admission))
+	assert.Equal(t, "1", admission.Name)
+	assert.True(t, admission.Admitted)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/quotaAdmission/not-exist?cpu=4", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/quotaAdmission/1", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/services"
+)
+
+const (
+	CapacityLimitedByQuota = "Quota"
+	CapacityLimitedByNode  = "Node"
+)
+
+// QuotaCapacity describes how many more pods of the given shape the quota group can admit right now.
+type QuotaCapacity struct {
+	Name      string          `json:"name"`
+	Runtime   v1.ResourceList `json:"runtime,omitempty"`
+	Used      v1.ResourceList `json:"used,omitempty"`
+	Available v1.ResourceList `json:"available,omitempty"`
+	// QuotaPods is the number of pods limited by the runtime of the quota group, -1 means unlimited.
+	QuotaPods int64 `json:"quotaPods"`
+	// NodeFitPods is the number of pods which can be placed on the nodes.
+	NodeFitPods int64 `json:"nodeFitPods"`
+	// Pods is the number of pods which can be admitted, the min of QuotaPods and NodeFitPods.
+	Pods      int64  `json:"pods"`
+	LimitedBy string `json:"limitedBy,omitempty"`
+}
+
+// GetQuotaCapacity returns the capacity of all the leaf quota groups for the pods requesting podRequest.
+// The resource a quota group can use right now is the larger one of its runtime and its min, since the min
+// is guaranteed even if the quota group has no pending request yet.
+// nodeFitPods is the number of pods which can be placed on the nodes, which is shared by all quota groups.
+func (gqm *GroupQuotaManager) GetQuotaCapacity(podRequest v1.ResourceList, nodeFitPods int64) []*QuotaCapacity {
+	gqm.hierarchyUpdateLock.RLock()
+	defer gqm.hierarchyUpdateLock.RUnlock()
+
+	var capacities []*QuotaCapacity
+	for quotaName, quotaInfo := range gqm.quotaInfoMap {
+		if quotaInfo.IsParent {
+			continue
+		}
+		runtime := gqm.refreshRuntimeNoLock(quotaName)
+		used := quotaInfo.GetUsed()
+		usable := quotav1.Max(runtime, quotaInfo.getMinForCapacity())
+		usable = quotav1.Mask(usable, quotav1.ResourceNames(quotaInfo.GetMax()))
+		capacity := &QuotaCapacity{
+			Name:        quotaName,
+			Runtime:     runtime,
+			Used:        used,
+			Available:   quotav1.Mask(quotav1.SubtractWithNonNegativeResult(usable, used), quotav1.ResourceNames(usable)),
+			NodeFitPods: nodeFitPods,
+		}
+		capacity.QuotaPods = countFitPods(capacity.Available, podRequest)
+		capacity.Pods, capacity.LimitedBy = capacity.NodeFitPods, CapacityLimitedByNode
+		if capacity.QuotaPods >= 0 && capacity.QuotaPods < capacity.NodeFitPods {
+			capacity.Pods, capacity.LimitedBy = capacity.QuotaPods, CapacityLimitedByQuota
+		}
+		capacities = append(capacities, capacity)
+	}
+	sort.Slice(capacities, func(i, j int) bool {
+		return capacities[i].Name < capacities[j].Name
+	})
+	return capacities
+}
+
+func (qi *QuotaInfo) getMinForCapacity() v1.ResourceList {
+	qi.lock.Lock()
+	defer qi.lock.Unlock()
+	if qi.Name == extension.SystemQuotaName || qi.Name == extension.DefaultQuotaName {
+		return qi.CalculateInfo.Max.DeepCopy()
+	}
+	return qi.CalculateInfo.AutoScaleMin.DeepCopy()
+}
+
+// countFitPods returns how many pods requesting podRequest fit in the available resources, only the resources
+// in available are limited, -1 means none of the requested resources is limited.
+func countFitPods(available, podRequest v1.ResourceList) int64 {
+	pods := int64(-1)
+	for resourceName, request := range podRequest {
+		if request.IsZero() {
+			continue
+		}
+		quantity, ok := available[resourceName]
+		if !ok {
+			continue
+		}
+		count := quantity.MilliValue() / request.MilliValue()
+		if pods < 0 || count < pods {
+			pods = count
+		}
+	}
+	return pods
+}
+
+// CountNodeFitPods returns how many pods requesting podRequest can be placed on the nodes, considering the
+// remaining resources (including the extended resources such as devices) and the allowed pod number of each node.
+func CountNodeFitPods(nodeInfos []*framework.NodeInfo, podRequest v1.ResourceList) int64 {
+	var total int64
+	for _, nodeInfo := range nodeInfos {
+		node := nodeInfo.Node()
+		if node == nil || node.Spec.Unschedulable {
+			continue
+		}
+		count := int64(nodeInfo.Allocatable.AllowedPodNumber - len(nodeInfo.Pods))
+		if count <= 0 {
+			continue
+		}
+		for resourceName, request := range podRequest {
+			if request.IsZero() {
+				continue
+			}
+			var allocatable, requested int64
+			switch resourceName {
+			case v1.ResourceCPU:
+				allocatable, requested = nodeInfo.Allocatable.MilliCPU, nodeInfo.Requested.MilliCPU
+			case v1.ResourceMemory:
+				allocatable, requested = nodeInfo.Allocatable.Memory, nodeInfo.Requested.Memory
+			case v1.ResourceEphemeralStorage:
+				allocatable, requested = nodeInfo.Allocatable.EphemeralStorage, nodeInfo.Requested.EphemeralStorage
+			default:
+				allocatable, requested = nodeInfo.Allocatable.ScalarResources[resourceName], nodeInfo.Requested.ScalarResources[resourceName]
+			}
+			var requestValue int64
+			if resourceName == v1.ResourceCPU {
+				requestValue = request.MilliValue()
+			} else {
+				requestValue = request.Value()
+			}
+			if fit := (allocatable - requested) / requestValue; fit < count {
+				count = fit
+			}
+			if count <= 0 {
+				break
+			}
+		}
+		if count > 0 {
+			total += count
+		}
+	}
+	return total
+}
+
+// QuotaCapacityHandler serves the quota-aware capacity report, the shape of the pod is specified by the
+// query parameters, e.g. GET /quotaCapacity?cpu=4&memory=8Gi&koordinator.sh/gpu-core=100
+func QuotaCapacityHandler(gqm *GroupQuotaManager, nodeInfoLister framework.NodeInfoLister) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		podRequest, ok := parsePodRequestFromQuery(c)
+		if !ok {
+			return
+		}
+		nodeInfos, err := nodeInfoLister.List()
+		if err != nil {
+			services.ResponseErrorMessage(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		nodeFitPods := CountNodeFitPods(nodeInfos, podRequest)
+		c.JSON(http.StatusOK, gqm.GetQuotaCapacity(podRequest, nodeFitPods))
+	}
+}
+
+// parsePodRequestFromQuery parses the pod request from the query parameters, it responses the error and returns
+// false if the pod request is invalid or empty.
+func parsePodRequestFromQuery(c *gin.Context) (v1.ResourceList, bool) {
+	podRequest := v1.ResourceList{}
+	for key, values := range c.Request.URL.Query() {
+		if len(values) == 0 {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(values[0])
+		if err != nil {
+			services.ResponseErrorMessage(c, http.StatusBadRequest, "invalid quantity of %s, err: %v", key, err)
+			return nil, false
+		}
+		podRequest[v1.ResourceName(key)] = quantity
+	}
+	if quotav1.IsZero(podRequest) {
+		services.ResponseErrorMessage(c, http.StatusBadRequest, "the pod request is empty")
+		return nil, false
+	}
+	return podRequest, true
+}
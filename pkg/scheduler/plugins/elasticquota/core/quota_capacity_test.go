@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestGroupQuotaManager_GetQuotaCapacity(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 50, 500*GigaByte, 10, 100*GigaByte, true, false)
+	AddQuotaToManager(t, gqm, "2", extension.RootQuotaName, 50, 500*GigaByte, 20, 200*GigaByte, true, false)
+
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(40, 400*GigaByte))
+	gqm.UpdateGroupDeltaUsed("1", createResourceList(20, 200*GigaByte))
+
+	podRequest := createResourceList(4, 2*GigaByte)
+	capacities := gqm.GetQuotaCapacity(podRequest, 6)
+	capacityMap := map[string]*QuotaCapacity{}
+	for _, capacity := range capacities {
+		capacityMap[capacity.Name] = capacity
+	}
+	assert.Len(t, capacityMap, 4)
+
+	// runtime is 40 and used is 20
+	assert.Equal(t, int64(5), capacityMap["1"].QuotaPods)
+	assert.Equal(t, int64(5), capacityMap["1"].Pods)
+	assert.Equal(t, CapacityLimitedByQuota, capacityMap["1"].LimitedBy)
+	// no request yet, min is guaranteed
+	assert.Equal(t, int64(5), capacityMap["2"].QuotaPods)
+	assert.Equal(t, int64(5), capacityMap["2"].Pods)
+	// the max of default quota is not set
+	assert.Equal(t, int64(-1), capacityMap[extension.DefaultQuotaName].QuotaPods)
+	assert.Equal(t, int64(6), capacityMap[extension.DefaultQuotaName].Pods)
+	assert.Equal(t, CapacityLimitedByNode, capacityMap[extension.DefaultQuotaName].LimitedBy)
+}
+
+func TestCountNodeFitPods(t *testing.T) {
+	gpuResource := extension.GPUCore
+	newNodeInfo := func(name string, unschedulable bool, allocatable v1.ResourceList, pods ...*v1.Pod) *framework.NodeInfo {
+		nodeInfo := framework.NewNodeInfo(pods...)
+		nodeInfo.SetNode(&v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1.NodeSpec{Unschedulable: unschedulable},
+			Status:     v1.NodeStatus{Allocatable: allocatable},
+		})
+		return nodeInfo
+	}
+	runningPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "running-pod"},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{
+							v1.ResourceCPU: resource.MustParse("2"),
+							gpuResource:    resource.MustParse("100"),
+						},
+					},
+				},
+			},
+		},
+	}
+	nodeInfos := []*framework.NodeInfo{
+		newNodeInfo("node-1", false, v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("8"),
+			v1.ResourceMemory: resource.MustParse("16Gi"),
+			v1.ResourcePods:   resource.MustParse("10"),
+			gpuResource:       resource.MustParse("400"),
+		}, runningPod),
+		newNodeInfo("node-2", false, v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("16"),
+			v1.ResourceMemory: resource.MustParse("32Gi"),
+			v1.ResourcePods:   resource.MustParse("2"),
+		}),
+		newNodeInfo("node-3", true, v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("16"),
+			v1.ResourceMemory: resource.MustParse("32Gi"),
+			v1.ResourcePods:   resource.MustParse("10"),
+		}),
+	}
+
+	tests := []struct {
+		name       string
+		podRequest v1.ResourceList
+		want       int64
+	}{
+		{
+			name: "cpu and memory",
+			podRequest: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("2"),
+				v1.ResourceMemory: resource.MustParse("4Gi"),
+			},
+			// node-1 is limited by cpu, node-2 is limited by pod number
+			want: 3 + 2,
+		},
+		{
+			name: "with gpu",
+			podRequest: v1.ResourceList{
+				v1.ResourceCPU: resource.MustParse("1"),
+				gpuResource:    resource.MustParse("100"),
+			},
+			// only node-1 has gpu left
+			want: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CountNodeFitPods(nodeInfos, tt.podRequest))
+		})
+	}
+}
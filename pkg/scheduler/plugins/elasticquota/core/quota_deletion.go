@@ -0,0 +1,214 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	schedlister "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+const (
+	QuotaEventReasonReparented      = "QuotaReparented"
+	QuotaEventReasonDeletionBlocked = "QuotaDeletionBlocked"
+	QuotaEventReasonDeleted         = "QuotaDeleted"
+)
+
+// QuotaDeletionController protects the ElasticQuotas by the finalizer extension.ElasticQuotaFinalizer, so that
+// deleting a quota with live pods doesn't leave the accounting dangling. When a quota is being deleted, its child
+// quotas are moved to its parent first, then the finalizer is removed after all its pods are drained. The quota
+// being deleted admits no more pods.
+type QuotaDeletionController struct {
+	gqm         *GroupQuotaManager
+	client      schedclientset.Interface
+	quotaLister schedlister.ElasticQuotaLister
+	recorder    events.EventRecorder
+	interval    time.Duration
+	trigger     chan struct{}
+}
+
+func NewQuotaDeletionController(gqm *GroupQuotaManager, client schedclientset.Interface, quotaLister schedlister.ElasticQuotaLister,
+	recorder events.EventRecorder, interval time.Duration) *QuotaDeletionController {
+	return &QuotaDeletionController{
+		gqm:         gqm,
+		client:      client,
+		quotaLister: quotaLister,
+		recorder:    recorder,
+		interval:    interval,
+		trigger:     make(chan struct{}, 1),
+	}
+}
+
+// Start runs the controller until stopCh is closed, it syncs every interval and on each Trigger.
+func (c *QuotaDeletionController) Start(stopCh <-chan struct{}) {
+	klog.Infof("start elastic quota deletion controller, interval: %v", c.interval)
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			c.Sync()
+			select {
+			case <-stopCh:
+				return
+			case <-ticker.C:
+			case <-c.trigger:
+			}
+		}
+	}()
+}
+
+// Trigger requests a Sync without waiting for the interval, the requests made before the Sync starts are merged.
+// It never blocks, so it's safe to call from the informer event handlers.
+func (c *QuotaDeletionController) Trigger() {
+	select {
+	case c.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Sync adds the finalizer to the new quotas and finishes the deletion of the quotas being deleted.
+func (c *QuotaDeletionController) Sync() {
+	quotas, err := c.quotaLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list elastic quotas, err: %v", err)
+		return
+	}
+	for _, quota := range quotas {
+		switch quota.Name {
+		case extension.RootQuotaName, extension.SystemQuotaName, extension.DefaultQuotaName:
+			// the built-in quotas are never deleted
+			continue
+		}
+		if quota.DeletionTimestamp == nil {
+			if !hasQuotaFinalizer(quota) {
+				err = c.patchFinalizers(quota, append(quota.Finalizers, extension.ElasticQuotaFinalizer))
+			}
+		} else {
+			err = c.syncDeletingQuota(quota, quotas)
+		}
+		if err != nil {
+			klog.Errorf("failed to sync finalizer of elastic quota %v/%v, err: %v", quota.Namespace, quota.Name, err)
+		}
+	}
+}
+
+func (c *QuotaDeletionController) syncDeletingQuota(quota *v1alpha1.ElasticQuota, quotas []*v1alpha1.ElasticQuota) error {
+	if !hasQuotaFinalizer(quota) {
+		return nil
+	}
+
+	// move the child quotas to the grandparent, the deletion continues after the GroupQuotaManager sees the moves
+	parentName := extension.GetParentQuotaName(quota)
+	childCount := 0
+	for _, child := range quotas {
+		if child.Name == quota.Name || extension.GetParentQuotaName(child) != quota.Name {
+			continue
+		}
+		childCount++
+		if err := c.reparentQuota(child, parentName); err != nil {
+			return err
+		}
+		c.recorder.Eventf(child, quota, v1.EventTypeNormal, QuotaEventReasonReparented, "Reparent",
+			"quota %s is moved from the deleting quota %s to %s", child.Name, quota.Name, parentName)
+		c.recorder.Eventf(quota, child, v1.EventTypeNormal, QuotaEventReasonReparented, "Reparent",
+			"child quota %s is moved to %s", child.Name, parentName)
+	}
+	if childCount > 0 {
+		return nil
+	}
+
+	if podCount := c.gqm.GetQuotaPodCount(quota.Name); podCount > 0 {
+		c.recorder.Eventf(quota, nil, v1.EventTypeWarning, QuotaEventReasonDeletionBlocked, "Delete",
+			"quota %s still has %d pods, waiting for them to drain", quota.Name, podCount)
+		return nil
+	}
+
+	var finalizers []string
+	for _, finalizer := range quota.Finalizers {
+		if finalizer != extension.ElasticQuotaFinalizer {
+			finalizers = append(finalizers, finalizer)
+		}
+	}
+	if err := c.patchFinalizers(quota, finalizers); err != nil {
+		return err
+	}
+	c.recorder.Eventf(quota, nil, v1.EventTypeNormal, QuotaEventReasonDeleted, "Delete",
+		"quota %s has no child quotas and pods, the deletion is finished", quota.Name)
+	return nil
+}
+
+func (c *QuotaDeletionController) reparentQuota(quota *v1alpha1.ElasticQuota, parentName string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]string{extension.LabelQuotaParent: parentName},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.client.SchedulingV1alpha1().ElasticQuotas(quota.Namespace).Patch(context.TODO(), quota.Name,
+		types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// patchFinalizers replaces the finalizers of the quota, the resourceVersion guards against the concurrent changes.
+func (c *QuotaDeletionController) patchFinalizers(quota *v1alpha1.ElasticQuota, finalizers []string) error {
+	if finalizers == nil {
+		finalizers = []string{}
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"finalizers":      finalizers,
+			"resourceVersion": quota.ResourceVersion,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.client.SchedulingV1alpha1().ElasticQuotas(quota.Namespace).Patch(context.TODO(), quota.Name,
+		types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// IsQuotaDeleting returns whether the quota group is being deleted.
+func (gqm *GroupQuotaManager) IsQuotaDeleting(quotaName string) bool {
+	gqm.hierarchyUpdateLock.RLock()
+	defer gqm.hierarchyUpdateLock.RUnlock()
+	quotaInfo := gqm.getQuotaInfoByNameNoLock(quotaName)
+	return quotaInfo != nil && quotaInfo.Deleting
+}
+
+func hasQuotaFinalizer(quota *v1alpha1.ElasticQuota) bool {
+	for _, finalizer := range quota.Finalizers {
+		if finalizer == extension.ElasticQuotaFinalizer {
+			return true
+		}
+	}
+	return false
+}
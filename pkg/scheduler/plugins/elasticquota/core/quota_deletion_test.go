@@ -0,0 +1,125 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	schedlister "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestQuotaDeletionController_Sync(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	parent := AddQuotaToManager(t, gqm, "parent", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, true)
+	child := AddQuotaToManager(t, gqm, "child", "parent", 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	parent.Namespace, child.Namespace = "default", "default"
+
+	client := fake.NewSimpleClientset(parent, child)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	recorder := events.NewFakeRecorder(10)
+	controller := NewQuotaDeletionController(gqm, client, schedlister.NewElasticQuotaLister(indexer), recorder, time.Second)
+	getQuota := func(name string) *v1alpha1.ElasticQuota {
+		quota, err := client.SchedulingV1alpha1().ElasticQuotas("default").Get(context.TODO(), name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		return quota
+	}
+	syncIndexer := func() {
+		for _, name := range []string{"parent", "child"} {
+			assert.NoError(t, indexer.Update(getQuota(name)))
+		}
+	}
+
+	// the finalizer is added to all the quotas
+	syncIndexer()
+	controller.Sync()
+	assert.Equal(t, []string{extension.ElasticQuotaFinalizer}, getQuota("parent").Finalizers)
+	assert.Equal(t, []string{extension.ElasticQuotaFinalizer}, getQuota("child").Finalizers)
+
+	// the child quota is moved to the root when its parent is being deleted
+	syncIndexer()
+	deletingParent := getQuota("parent")
+	deletingParent.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	assert.NoError(t, indexer.Update(deletingParent))
+	controller.Sync()
+	assert.Equal(t, extension.RootQuotaName, getQuota("child").Labels[extension.LabelQuotaParent])
+	assert.Equal(t, []string{extension.ElasticQuotaFinalizer}, getQuota("parent").Finalizers)
+	assert.Contains(t, <-recorder.Events, QuotaEventReasonReparented)
+
+	// the parent has no child and pod now
+	assert.NoError(t, indexer.Update(getQuota("child")))
+	for len(recorder.Events) > 0 {
+		<-recorder.Events
+	}
+	controller.Sync()
+	assert.Empty(t, getQuota("parent").Finalizers)
+	assert.Contains(t, <-recorder.Events, QuotaEventReasonDeleted)
+
+	// the deletion of the child quota is blocked by its pod, and it admits no more pods
+	assert.NoError(t, gqm.UpdateQuota(getQuota("child"), false))
+	pod := newTestQuotaPod("pod-1", 10, 10*GigaByte)
+	assert.NoError(t, gqm.UpdatePodAccountingState("child", pod, PodAccountingStateRunning))
+	deletingChild := getQuota("child")
+	deletingChild.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	assert.NoError(t, indexer.Delete(deletingParent))
+	assert.NoError(t, indexer.Update(deletingChild))
+	assert.NoError(t, gqm.UpdateQuota(deletingChild, false))
+	assert.True(t, gqm.IsQuotaDeleting("child"))
+	admission, err := gqm.CheckQuotaAdmission("child", createResourceList(1, GigaByte))
+	assert.NoError(t, err)
+	assert.False(t, admission.Admitted)
+	assert.Contains(t, admission.Reasons, AdmissionRejectReasonQuotaDeleting)
+
+	controller.Sync()
+	assert.Equal(t, []string{extension.ElasticQuotaFinalizer}, getQuota("child").Finalizers)
+	assert.Contains(t, <-recorder.Events, QuotaEventReasonDeletionBlocked)
+
+	assert.NoError(t, gqm.UpdatePodAccountingState("child", pod, PodAccountingStateGone))
+	controller.Sync()
+	assert.Empty(t, getQuota("child").Finalizers)
+}
+
+func TestQuotaDeletionController_Trigger(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	quota := CreateQuota("1", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	quota.Namespace = "default"
+	client := fake.NewSimpleClientset(quota)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	controller := NewQuotaDeletionController(gqm, client, schedlister.NewElasticQuotaLister(indexer), events.NewFakeRecorder(10), time.Hour)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	controller.Start(stopCh)
+
+	// the quota created after the first sync gets the finalizer on the trigger instead of the next interval
+	assert.NoError(t, indexer.Add(quota))
+	controller.Trigger()
+	controller.Trigger()
+	assert.Eventually(t, func() bool {
+		synced, err := client.SchedulingV1alpha1().ElasticQuotas("default").Get(context.TODO(), "1", metav1.GetOptions{})
+		return err == nil && hasQuotaFinalizer(synced)
+	}, 5*time.Second, 10*time.Millisecond)
+}
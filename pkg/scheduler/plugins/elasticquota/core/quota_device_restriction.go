@@ -0,0 +1,164 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+// intersectDeviceRestriction returns the devices allowed by both of the restrictions.
+func intersectDeviceRestriction(parent, child *extension.QuotaDeviceRestriction) *extension.QuotaDeviceRestriction {
+	if parent == nil {
+		return child.DeepCopy()
+	}
+	out := parent.DeepCopy()
+	if child.AllowedTypes != nil {
+		if out.AllowedTypes == nil {
+			out.AllowedTypes = append([]schedulingv1alpha1.DeviceType{}, child.AllowedTypes...)
+		} else {
+			allowed := make([]schedulingv1alpha1.DeviceType, 0, len(child.AllowedTypes))
+			for _, deviceType := range child.AllowedTypes {
+				if containsDeviceType(out.AllowedTypes, deviceType) {
+					allowed = append(allowed, deviceType)
+				}
+			}
+			out.AllowedTypes = allowed
+		}
+	}
+	if child.AllowedModels != nil {
+		if out.AllowedModels == nil {
+			out.AllowedModels = append([]string{}, child.AllowedModels...)
+		} else {
+			out.AllowedModels = sets.NewString(out.AllowedModels...).Intersection(sets.NewString(child.AllowedModels...)).List()
+		}
+	}
+	return out
+}
+
+func containsDeviceType(deviceTypes []schedulingv1alpha1.DeviceType, deviceType schedulingv1alpha1.DeviceType) bool {
+	for _, t := range deviceTypes {
+		if t == deviceType {
+			return true
+		}
+	}
+	return false
+}
+
+// getRequestedDeviceTypes returns the sorted device types requested by the pod.
+func getRequestedDeviceTypes(podRequest v1.ResourceList) []schedulingv1alpha1.DeviceType {
+	var deviceTypes []schedulingv1alpha1.DeviceType
+	for deviceType, resourceNames := range extension.DeviceResourceNames {
+		for _, resourceName := range resourceNames {
+			if quantity, ok := podRequest[resourceName]; ok && !quantity.IsZero() {
+				deviceTypes = append(deviceTypes, deviceType)
+				break
+			}
+		}
+	}
+	sort.Slice(deviceTypes, func(i, j int) bool {
+		return deviceTypes[i] < deviceTypes[j]
+	})
+	return deviceTypes
+}
+
+// getSelectedGPUModels returns the GPU models selected by the node selector or the required node affinity of the pod.
+func getSelectedGPUModels(pod *v1.Pod) []string {
+	models := sets.NewString()
+	if model, ok := pod.Spec.NodeSelector[extension.LabelGPUModel]; ok {
+		models.Insert(model)
+	}
+	if pod.Spec.Affinity != nil && pod.Spec.Affinity.NodeAffinity != nil &&
+		pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		for _, term := range pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+			for _, expr := range term.MatchExpressions {
+				if expr.Key == extension.LabelGPUModel && expr.Operator == v1.NodeSelectorOpIn {
+					models.Insert(expr.Values...)
+				}
+			}
+		}
+	}
+	return models.List()
+}
+
+func (gqm *GroupQuotaManager) getDeviceRestriction(quotaName string) *extension.QuotaDeviceRestriction {
+	if quotaName == "" {
+		quotaName = extension.DefaultQuotaName
+	}
+	policy := gqm.GetEffectiveQuotaPolicy(quotaName)
+	if policy == nil {
+		return nil
+	}
+	return policy.DeviceRestriction
+}
+
+// CheckDeviceRestriction returns the reasons why the pod is rejected by the device restriction of the quota group,
+// empty if the pod is admitted. The reason is AdmissionRejectReasonDeviceTypeNotAllowed or
+// AdmissionRejectReasonDeviceModelNotAllowed followed by the devices not allowed.
+func (gqm *GroupQuotaManager) CheckDeviceRestriction(quotaName string, pod *v1.Pod) []string {
+	restriction := gqm.getDeviceRestriction(quotaName)
+	if restriction == nil {
+		return nil
+	}
+
+	var reasons []string
+	deviceTypes := getRequestedDeviceTypes(getPodRequest(pod))
+	if restriction.AllowedTypes != nil {
+		var notAllowed []string
+		for _, deviceType := range deviceTypes {
+			if !containsDeviceType(restriction.AllowedTypes, deviceType) {
+				notAllowed = append(notAllowed, string(deviceType))
+			}
+		}
+		if len(notAllowed) > 0 {
+			reasons = append(reasons, fmt.Sprintf("%s: %v", AdmissionRejectReasonDeviceTypeNotAllowed, notAllowed))
+		}
+	}
+	if restriction.AllowedModels != nil && containsDeviceType(deviceTypes, schedulingv1alpha1.GPU) {
+		allowed := sets.NewString(restriction.AllowedModels...)
+		var notAllowed []string
+		for _, model := range getSelectedGPUModels(pod) {
+			if !allowed.Has(model) {
+				notAllowed = append(notAllowed, model)
+			}
+		}
+		if len(notAllowed) > 0 {
+			reasons = append(reasons, fmt.Sprintf("%s: %v", AdmissionRejectReasonDeviceModelNotAllowed, notAllowed))
+		}
+	}
+	return reasons
+}
+
+// IsNodeGPUModelAllowed returns whether the pod requesting the GPUs can run on the node. If the quota group
+// restricts the GPU models, the nodes of the other models or the unknown model are not allowed.
+func (gqm *GroupQuotaManager) IsNodeGPUModelAllowed(quotaName string, pod *v1.Pod, node *v1.Node) bool {
+	restriction := gqm.getDeviceRestriction(quotaName)
+	if restriction == nil || restriction.AllowedModels == nil {
+		return true
+	}
+	if !containsDeviceType(getRequestedDeviceTypes(getPodRequest(pod)), schedulingv1alpha1.GPU) {
+		return true
+	}
+	model, ok := node.Labels[extension.LabelGPUModel]
+	return ok && sets.NewString(restriction.AllowedModels...).Has(model)
+}
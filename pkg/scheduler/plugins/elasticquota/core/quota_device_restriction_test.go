@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+func TestGroupQuotaManager_CheckDeviceRestriction(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+
+	parent := CreateQuota("p", extension.RootQuotaName, 100, 1000*GigaByte, 50, 500*GigaByte, true, true)
+	parent.Annotations[extension.AnnotationQuotaPolicy] = `{"deviceRestriction":{"allowedTypes":["gpu","rdma"],"allowedModels":["A10","A100"]}}`
+	assert.NoError(t, gqm.UpdateQuota(parent, false))
+	// c1 narrows the models, and can't widen the types
+	child1 := CreateQuota("c1", "p", 50, 500*GigaByte, 20, 200*GigaByte, true, false)
+	child1.Annotations[extension.AnnotationQuotaPolicy] = `{"deviceRestriction":{"allowedTypes":["gpu","fpga"],"allowedModels":["A10"]}}`
+	assert.NoError(t, gqm.UpdateQuota(child1, false))
+	child2 := CreateQuota("c2", "p", 50, 500*GigaByte, 20, 200*GigaByte, true, false)
+	assert.NoError(t, gqm.UpdateQuota(child2, false))
+
+	restriction := gqm.GetEffectiveQuotaPolicy("c1").DeviceRestriction
+	assert.Equal(t, []schedulingv1alpha1.DeviceType{schedulingv1alpha1.GPU}, restriction.AllowedTypes)
+	assert.Equal(t, []string{"A10"}, restriction.AllowedModels)
+	restriction = gqm.GetEffectiveQuotaPolicy("c2").DeviceRestriction
+	assert.Equal(t, []string{"A10", "A100"}, restriction.AllowedModels)
+
+	newPod := func(model string, requests v1.ResourceList) *v1.Pod {
+		pod := newTestQuotaPod("pod-1", 1, GigaByte)
+		for name, quantity := range requests {
+			pod.Spec.Containers[0].Resources.Requests[name] = quantity
+		}
+		if model != "" {
+			pod.Spec.NodeSelector = map[string]string{extension.LabelGPUModel: model}
+		}
+		return pod
+	}
+	gpuRequest := v1.ResourceList{extension.GPUCore: resource.MustParse("100")}
+	rdmaRequest := v1.ResourceList{extension.KoordRDMA: resource.MustParse("1")}
+
+	assert.Empty(t, gqm.CheckDeviceRestriction("c1", newPod("", nil)))
+	assert.Empty(t, gqm.CheckDeviceRestriction("c1", newPod("A10", gpuRequest)))
+	assert.Equal(t, []string{"DeviceTypeNotAllowed: [rdma]"}, gqm.CheckDeviceRestriction("c1", newPod("", rdmaRequest)))
+	assert.Equal(t, []string{"DeviceModelNotAllowed: [A100]"}, gqm.CheckDeviceRestriction("c1", newPod("A100", gpuRequest)))
+	assert.Empty(t, gqm.CheckDeviceRestriction("c2", newPod("A100", gpuRequest)))
+	assert.Empty(t, gqm.CheckDeviceRestriction("c2", newPod("", rdmaRequest)))
+	// the quota groups without restriction
+	assert.Empty(t, gqm.CheckDeviceRestriction(extension.DefaultQuotaName, newPod("H100", gpuRequest)))
+
+	newNode := func(model string) *v1.Node {
+		node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{}}}
+		if model != "" {
+			node.Labels[extension.LabelGPUModel] = model
+		}
+		return node
+	}
+	assert.True(t, gqm.IsNodeGPUModelAllowed("c1", newPod("", gpuRequest), newNode("A10")))
+	assert.False(t, gqm.IsNodeGPUModelAllowed("c1", newPod("", gpuRequest), newNode("A100")))
+	assert.False(t, gqm.IsNodeGPUModelAllowed("c1", newPod("", gpuRequest), newNode("")))
+	assert.True(t, gqm.IsNodeGPUModelAllowed("c1", newPod("", nil), newNode("A100")))
+	assert.True(t, gqm.IsNodeGPUModelAllowed("c2", newPod("", gpuRequest), newNode("A100")))
+}
+
+func TestIntersectDeviceRestriction(t *testing.T) {
+	parent := &extension.QuotaDeviceRestriction{AllowedTypes: []schedulingv1alpha1.DeviceType{schedulingv1alpha1.GPU}}
+	child := &extension.QuotaDeviceRestriction{AllowedTypes: []schedulingv1alpha1.DeviceType{schedulingv1alpha1.RDMA}}
+	restriction := intersectDeviceRestriction(parent, child)
+	// nothing is allowed rather than everything
+	assert.NotNil(t, restriction.AllowedTypes)
+	assert.Empty(t, restriction.AllowedTypes)
+	assert.Nil(t, restriction.AllowedModels)
+
+	restriction = intersectDeviceRestriction(nil, child)
+	assert.Equal(t, child, restriction)
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/services"
+)
+
+// EffectiveQuotaConfig is the configuration of the quota group resolved by the GroupQuotaManager, which merges the
+// annotations of the quota group and its parents, and the configuration of the scheduler.
+type EffectiveQuotaConfig struct {
+	Name string `json:"name"`
+	// Ancestors are the parent quota groups from the nearest one to the root-level one.
+	Ancestors []string `json:"ancestors,omitempty"`
+	// Policy is the policy merged with the parents' policies.
+	Policy            *extension.QuotaPolicy `json:"policy,omitempty"`
+	AllowLentResource bool                   `json:"allowLentResource"`
+	PriorityTier      int32                  `json:"priorityTier,omitempty"`
+	// SharedWeight is the SharedWeight used by the runtime calculation, which may be overridden by the
+	// SharedWeightProvider or lowered by the usage decay.
+	SharedWeight v1.ResourceList `json:"sharedWeight,omitempty"`
+	// MinQuotaOversellRatio is the oversell ratio of the children's min, inherited from the parents or the scheduler.
+	MinQuotaOversellRatio float64 `json:"minQuotaOversellRatio,omitempty"`
+	// NodeSelector is the node selector of the node pool the quota group is scoped to, which is inherited from
+	// the root-level ancestor.
+	NodeSelector string `json:"nodeSelector,omitempty"`
+	// RuntimeCalculateStrategy is the strategy to distribute the resource of the quota group to its children.
+	RuntimeCalculateStrategy string          `json:"runtimeCalculateStrategy,omitempty"`
+	Min                      v1.ResourceList `json:"min,omitempty"`
+	Max                      v1.ResourceList `json:"max,omitempty"`
+	Reserved                 v1.ResourceList `json:"reserved,omitempty"`
+	BorrowLimit              v1.ResourceList `json:"borrowLimit,omitempty"`
+}
+
+// GetEffectiveQuotaConfig returns the effective configuration of the quota group, nil if the quota group doesn't exist.
+func (gqm *GroupQuotaManager) GetEffectiveQuotaConfig(quotaName string) *EffectiveQuotaConfig {
+	gqm.hierarchyUpdateLock.RLock()
+	defer gqm.hierarchyUpdateLock.RUnlock()
+
+	curToAllParInfos := gqm.getCurToAllParentGroupQuotaInfoNoLock(quotaName)
+	if len(curToAllParInfos) == 0 {
+		return nil
+	}
+
+	config := &EffectiveQuotaConfig{
+		Name:                  quotaName,
+		MinQuotaOversellRatio: gqm.minQuotaOversellRatio,
+	}
+	for i, quotaInfo := range curToAllParInfos {
+		if i > 0 {
+			config.Ancestors = append(config.Ancestors, quotaInfo.Name)
+		}
+	}
+	// the MinQuotaOversellRatio, NodeSelector and ParentName are only changed with the hierarchyUpdateLock held
+	for _, quotaInfo := range curToAllParInfos {
+		if quotaInfo.MinQuotaOversellRatio > 0 {
+			config.MinQuotaOversellRatio = quotaInfo.MinQuotaOversellRatio
+			break
+		}
+	}
+	if rootLevelQuotaInfo := curToAllParInfos[len(curToAllParInfos)-1]; rootLevelQuotaInfo.ParentName == extension.RootQuotaName {
+		if _, ok := gqm.nodePools[rootLevelQuotaInfo.NodeSelector]; ok {
+			config.NodeSelector = rootLevelQuotaInfo.NodeSelector
+		}
+	}
+
+	quotaInfo := curToAllParInfos[0]
+	quotaInfo.lock.Lock()
+	defer quotaInfo.lock.Unlock()
+	if quotaInfo.EffectivePolicy != nil {
+		config.Policy = quotaInfo.EffectivePolicy.DeepCopy()
+	} else {
+		// the system and default quota groups are not in the quota tree
+		config.Policy = inheritQuotaPolicy(quotaInfo.Policy, newRootQuotaPolicy())
+	}
+	config.AllowLentResource = quotaInfo.AllowLentResource
+	config.PriorityTier = quotaInfo.PriorityTier
+	config.SharedWeight = quotaInfo.CalculateInfo.SharedWeight.DeepCopy()
+	config.Min = quotaInfo.CalculateInfo.AutoScaleMin.DeepCopy()
+	config.Max = quotaInfo.CalculateInfo.Max.DeepCopy()
+	config.Reserved = quotaInfo.CalculateInfo.Reserved.DeepCopy()
+	config.BorrowLimit = quotaInfo.CalculateInfo.BorrowLimit.DeepCopy()
+	if quotaInfo.IsParent {
+		config.RuntimeCalculateStrategy = config.Policy.RuntimeCalculateStrategy
+		if config.RuntimeCalculateStrategy == "" {
+			config.RuntimeCalculateStrategy = gqm.runtimeCalculateStrategyName
+		}
+		if config.RuntimeCalculateStrategy == "" {
+			config.RuntimeCalculateStrategy = newDefaultRuntimeCalculateStrategy().Name()
+		}
+	}
+	return config
+}
+
+func (gqm *GroupQuotaManager) registerEffectiveConfigEndpoint(group *gin.RouterGroup) {
+	group.GET("/quotas/:quotaName/effective", func(c *gin.Context) {
+		quotaName := c.Param("quotaName")
+		config := gqm.GetEffectiveQuotaConfig(quotaName)
+		if config == nil {
+			services.ResponseErrorMessage(c, http.StatusNotFound, "quota %s not found", quotaName)
+			return
+		}
+		c.JSON(http.StatusOK, config)
+	})
+}
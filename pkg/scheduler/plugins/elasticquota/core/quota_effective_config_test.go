@@ -0,0 +1,93 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+)
+
+func TestGroupQuotaManager_GetEffectiveQuotaConfig(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.SetMinQuotaOversellRatio(1.2)
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+
+	parent := CreateQuota("p", extension.RootQuotaName, 100, 1000*GigaByte, 50, 500*GigaByte, true, true)
+	parent.Annotations[extension.AnnotationNodeSelector] = `{"matchLabels":{"pool":"gpu"}}`
+	parent.Annotations[extension.AnnotationMinQuotaOversellRatio] = "1.5"
+	parent.Annotations[extension.AnnotationPriorityTier] = "2"
+	parent.Annotations[extension.AnnotationQuotaPolicy] = `{"evictionPolicy":"Never","runtimeCalculateStrategy":"PriorityStrict"}`
+	assert.NoError(t, gqm.UpdateQuota(parent, false))
+	child := CreateQuota("c", "p", 50, 500*GigaByte, 20, 200*GigaByte, false, false)
+	child.Annotations[extension.AnnotationPriorityTier] = "1"
+	assert.NoError(t, gqm.UpdateQuota(child, false))
+	AddQuotaToManager(t, gqm, "other", extension.RootQuotaName, 100, 1000*GigaByte, 10, 100*GigaByte, true, false)
+
+	effective := gqm.GetEffectiveQuotaConfig("c")
+	assert.Equal(t, "c", effective.Name)
+	assert.Equal(t, []string{"p"}, effective.Ancestors)
+	assert.Equal(t, extension.QuotaEvictionPolicyNever, effective.Policy.EvictionPolicy)
+	assert.False(t, effective.AllowLentResource)
+	assert.Equal(t, int32(1), effective.PriorityTier)
+	assert.Equal(t, 1.5, effective.MinQuotaOversellRatio)
+	assert.Equal(t, "pool=gpu", effective.NodeSelector)
+	assert.Empty(t, effective.RuntimeCalculateStrategy)
+	assert.True(t, quotav1.Equals(createResourceList(50, 500*GigaByte), effective.Max))
+	assert.True(t, quotav1.Equals(createResourceList(20, 200*GigaByte), effective.Min))
+	assert.True(t, quotav1.Equals(createResourceList(50, 500*GigaByte), effective.SharedWeight))
+
+	effective = gqm.GetEffectiveQuotaConfig("p")
+	assert.Empty(t, effective.Ancestors)
+	assert.Equal(t, string(config.RuntimeCalculateStrategyPriorityStrict), effective.RuntimeCalculateStrategy)
+
+	effective = gqm.GetEffectiveQuotaConfig("other")
+	assert.Equal(t, 1.2, effective.MinQuotaOversellRatio)
+	assert.Empty(t, effective.NodeSelector)
+
+	assert.NotNil(t, gqm.GetEffectiveQuotaConfig(extension.DefaultQuotaName))
+	assert.Nil(t, gqm.GetEffectiveQuotaConfig("not-exist"))
+}
+
+func TestGroupQuotaManager_EffectiveConfigEndpoint(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 50, 500, 10, 100, true, false)
+
+	engine := gin.Default()
+	gqm.RegisterEndpoints(engine.Group("/"))
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/quotas/1/effective", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	effective := &EffectiveQuotaConfig{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), effective))
+	assert.Equal(t, "1", effective.Name)
+	assert.True(t, effective.AllowLentResource)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/quotas/not-exist/effective", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -21,7 +21,9 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
 	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
@@ -42,8 +44,20 @@ type QuotaCalculateInfo struct {
 	Request v1.ResourceList `json:"request,omitempty"`
 	// SharedWeight determines the ability of quota groups to compete for shared resources
 	SharedWeight v1.ResourceList `json:"sharedWeight,omitempty"`
+	// OriginalSharedWeight is the SharedWeight from the quota's annotation, SharedWeight may be overridden
+	// by the SharedWeightProvider and falls back to it
+	OriginalSharedWeight v1.ResourceList `json:"originalSharedWeight,omitempty"`
 	// Runtime is the current actual resource that can be used by the quota group
 	Runtime v1.ResourceList `json:"runtime,omitempty"`
+	// Reserved is the part of min which is never lent to the other quota groups, the quota group always
+	// requests at least Reserved from its parent, so that the borrowed usage can't eat into it even transiently.
+	Reserved v1.ResourceList `json:"reserved,omitempty"`
+	// BorrowLimit is the max resource the quota group can borrow beyond its AutoScaleMin, the request passed to
+	// the runtime calculator is limited by AutoScaleMin + BorrowLimit. The resources not in it are only limited by Max.
+	BorrowLimit v1.ResourceList `json:"borrowLimit,omitempty"`
+	// RequestByPriority and UsedByPriority are the Request and Used broken down by the priority class of the pods
+	RequestByPriority PriorityResourceList `json:"requestByPriority,omitempty"`
+	UsedByPriority    PriorityResourceList `json:"usedByPriority,omitempty"`
 }
 
 type QuotaInfo struct {
@@ -56,9 +70,22 @@ type QuotaInfo struct {
 	// If runtimeVersion not equal to quotaTree runtimeVersion, means runtime has been updated.
 	RuntimeVersion int64 `json:"runtimeVersion"`
 	// Allow lent resource to other quota group
-	AllowLentResource bool               `json:"allowLentResource"`
-	CalculateInfo     QuotaCalculateInfo `json:"calculateInfo,omitempty"`
-	lock              sync.Mutex
+	AllowLentResource bool `json:"allowLentResource"`
+	// PriorityTier is the priority tier of the quota group among its siblings
+	PriorityTier int32 `json:"priorityTier,omitempty"`
+	// Deleting means the quota is being deleted, it admits no more pods
+	Deleting bool `json:"deleting,omitempty"`
+	// MinQuotaOversellRatio allows the children's sum of min up to ratio times of the quota group's resource,
+	// zero means inheriting from the parent quota group.
+	MinQuotaOversellRatio float64 `json:"minQuotaOversellRatio,omitempty"`
+	// NodeSelector is the label selector of the node pool the quota tree is scoped to, empty means all the nodes.
+	// It only takes effect on the quota group whose parent is the root quota group.
+	NodeSelector string `json:"nodeSelector,omitempty"`
+	// Policy is the policy of the quota group itself, EffectivePolicy is the policy merged with the parents'.
+	Policy          *extension.QuotaPolicy `json:"policy,omitempty"`
+	EffectivePolicy *extension.QuotaPolicy `json:"effectivePolicy,omitempty"`
+	CalculateInfo   QuotaCalculateInfo     `json:"calculateInfo,omitempty"`
+	lock            sync.Mutex
 }
 
 func NewQuotaInfo(isParent, allowLentResource bool, name, parentName string) *QuotaInfo {
@@ -69,13 +96,18 @@ func NewQuotaInfo(isParent, allowLentResource bool, name, parentName string) *Qu
 		AllowLentResource: allowLentResource,
 		RuntimeVersion:    0,
 		CalculateInfo: QuotaCalculateInfo{
-			Max:          v1.ResourceList{},
-			AutoScaleMin: v1.ResourceList{},
-			OriginalMin:  v1.ResourceList{},
-			Used:         v1.ResourceList{},
-			Request:      v1.ResourceList{},
-			SharedWeight: v1.ResourceList{},
-			Runtime:      v1.ResourceList{},
+			Max:                  v1.ResourceList{},
+			AutoScaleMin:         v1.ResourceList{},
+			OriginalMin:          v1.ResourceList{},
+			Used:                 v1.ResourceList{},
+			Request:              v1.ResourceList{},
+			SharedWeight:         v1.ResourceList{},
+			OriginalSharedWeight: v1.ResourceList{},
+			Runtime:              v1.ResourceList{},
+			Reserved:             v1.ResourceList{},
+			BorrowLimit:          v1.ResourceList{},
+			RequestByPriority:    PriorityResourceList{},
+			UsedByPriority:       PriorityResourceList{},
 		},
 	}
 }
@@ -87,19 +119,30 @@ func (qi *QuotaInfo) DeepCopy() *QuotaInfo {
 	defer qi.lock.Unlock()
 
 	return &QuotaInfo{
-		Name:              qi.Name,
-		ParentName:        qi.ParentName,
-		IsParent:          qi.IsParent,
-		AllowLentResource: qi.AllowLentResource,
-		RuntimeVersion:    qi.RuntimeVersion,
+		Name:                  qi.Name,
+		ParentName:            qi.ParentName,
+		IsParent:              qi.IsParent,
+		AllowLentResource:     qi.AllowLentResource,
+		PriorityTier:          qi.PriorityTier,
+		Deleting:              qi.Deleting,
+		RuntimeVersion:        qi.RuntimeVersion,
+		MinQuotaOversellRatio: qi.MinQuotaOversellRatio,
+		NodeSelector:          qi.NodeSelector,
+		Policy:                qi.Policy.DeepCopy(),
+		EffectivePolicy:       qi.EffectivePolicy.DeepCopy(),
 		CalculateInfo: QuotaCalculateInfo{
-			Max:          qi.CalculateInfo.Max.DeepCopy(),
-			AutoScaleMin: qi.CalculateInfo.AutoScaleMin.DeepCopy(),
-			OriginalMin:  qi.CalculateInfo.OriginalMin.DeepCopy(),
-			Used:         qi.CalculateInfo.Used.DeepCopy(),
-			Request:      qi.CalculateInfo.Request.DeepCopy(),
-			SharedWeight: qi.CalculateInfo.SharedWeight.DeepCopy(),
-			Runtime:      qi.CalculateInfo.Runtime.DeepCopy(),
+			Max:                  qi.CalculateInfo.Max.DeepCopy(),
+			AutoScaleMin:         qi.CalculateInfo.AutoScaleMin.DeepCopy(),
+			OriginalMin:          qi.CalculateInfo.OriginalMin.DeepCopy(),
+			Used:                 qi.CalculateInfo.Used.DeepCopy(),
+			Request:              qi.CalculateInfo.Request.DeepCopy(),
+			SharedWeight:         qi.CalculateInfo.SharedWeight.DeepCopy(),
+			OriginalSharedWeight: qi.CalculateInfo.OriginalSharedWeight.DeepCopy(),
+			Runtime:              qi.CalculateInfo.Runtime.DeepCopy(),
+			Reserved:             qi.CalculateInfo.Reserved.DeepCopy(),
+			BorrowLimit:          qi.CalculateInfo.BorrowLimit.DeepCopy(),
+			RequestByPriority:    qi.CalculateInfo.RequestByPriority.DeepCopy(),
+			UsedByPriority:       qi.CalculateInfo.UsedByPriority.DeepCopy(),
 		},
 	}
 }
@@ -116,12 +159,20 @@ func (qi *QuotaInfo) UpdateQuotaInfoFromRemote(quotaInfo *QuotaInfo) {
 
 	qi.setMaxQuotaNoLock(quotaInfo.CalculateInfo.Max)
 	qi.setOriginalMinQuotaNoLock(quotaInfo.CalculateInfo.OriginalMin)
+	qi.CalculateInfo.Reserved = quotaInfo.CalculateInfo.Reserved.DeepCopy()
+	qi.CalculateInfo.BorrowLimit = quotaInfo.CalculateInfo.BorrowLimit.DeepCopy()
 	sharedWeight := quotaInfo.CalculateInfo.SharedWeight.DeepCopy()
 	if quotav1.IsZero(sharedWeight) {
 		sharedWeight = quotaInfo.CalculateInfo.Max.DeepCopy()
 	}
 	qi.CalculateInfo.SharedWeight = sharedWeight
+	qi.CalculateInfo.OriginalSharedWeight = sharedWeight.DeepCopy()
 	qi.AllowLentResource = quotaInfo.AllowLentResource
+	qi.PriorityTier = quotaInfo.PriorityTier
+	qi.Deleting = quotaInfo.Deleting
+	qi.MinQuotaOversellRatio = quotaInfo.MinQuotaOversellRatio
+	qi.NodeSelector = quotaInfo.NodeSelector
+	qi.Policy = quotaInfo.Policy.DeepCopy()
 	qi.IsParent = quotaInfo.IsParent
 	qi.ParentName = quotaInfo.ParentName
 }
@@ -141,6 +192,24 @@ func (qi *QuotaInfo) getLimitRequestNoLock() v1.ResourceList {
 			}
 		}
 	}
+	// the quota group can't borrow more than the borrow limit beyond its min
+	for resName, borrowLimit := range qi.CalculateInfo.BorrowLimit {
+		quantity, ok := limitRequest[resName]
+		if !ok {
+			continue
+		}
+		limit := qi.CalculateInfo.AutoScaleMin[resName].DeepCopy()
+		limit.Add(borrowLimit)
+		if quantity.Cmp(limit) == 1 {
+			limitRequest[resName] = limit
+		}
+	}
+	// the reserved is always requested to never lend it
+	for resName, reserved := range qi.CalculateInfo.Reserved {
+		if quantity, ok := limitRequest[resName]; !ok || quantity.Cmp(reserved) < 0 {
+			limitRequest[resName] = reserved.DeepCopy()
+		}
+	}
 	return limitRequest
 }
 
@@ -174,6 +243,10 @@ func (qi *QuotaInfo) setSharedWeightNoLock(res v1.ResourceList) {
 	qi.CalculateInfo.SharedWeight = res.DeepCopy()
 }
 
+func (qi *QuotaInfo) setOriginalSharedWeightNoLock(res v1.ResourceList) {
+	qi.CalculateInfo.OriginalSharedWeight = res.DeepCopy()
+}
+
 func (qi *QuotaInfo) GetRequest() v1.ResourceList {
 	qi.lock.Lock()
 	defer qi.lock.Unlock()
@@ -205,22 +278,52 @@ func NewQuotaInfoFromQuota(quota *v1alpha1.ElasticQuota) *QuotaInfo {
 	allowLentResource := extension.IsAllowLentResource(quota)
 
 	quotaInfo := NewQuotaInfo(isParent, allowLentResource, quota.Name, parentName)
-	quotaInfo.setOriginalMinQuotaNoLock(quota.Spec.Min)
-	quotaInfo.setMaxQuotaNoLock(quota.Spec.Max)
-	newSharedWeight := extension.GetSharedWeight(quota)
+	quotaInfo.MinQuotaOversellRatio = extension.GetMinQuotaOversellRatio(quota)
+	policy, err := extension.GetQuotaPolicy(quota)
+	if err != nil {
+		klog.Errorf("failed to get policy of quota %v, err: %v", quota.Name, err)
+	}
+	quotaInfo.Policy = policy
+	priorityTier, err := extension.GetPriorityTier(quota)
+	if err != nil {
+		klog.Errorf("failed to get priority tier of quota %v, err: %v", quota.Name, err)
+	}
+	quotaInfo.PriorityTier = priorityTier
+	if nodeSelector, err := extension.GetNodeSelector(quota); err != nil {
+		klog.Errorf("failed to get node selector of quota %v, err: %v", quota.Name, err)
+	} else if nodeSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(nodeSelector)
+		if err != nil {
+			klog.Errorf("invalid node selector of quota %v, err: %v", quota.Name, err)
+		} else {
+			quotaInfo.NodeSelector = selector.String()
+		}
+	}
+	quotaInfo.Deleting = quota.DeletionTimestamp != nil
+	quotaInfo.setOriginalMinQuotaNoLock(extension.TranslateResourceNameAliases(quota.Spec.Min))
+	quotaInfo.setMaxQuotaNoLock(extension.TranslateResourceNameAliases(quota.Spec.Max))
+	newSharedWeight := extension.TranslateResourceNameAliases(extension.GetSharedWeight(quota))
 	quotaInfo.setSharedWeightNoLock(newSharedWeight)
+	quotaInfo.setOriginalSharedWeightNoLock(newSharedWeight)
+	quotaInfo.CalculateInfo.Reserved = extension.TranslateResourceNameAliases(extension.GetReserved(quota))
+	quotaInfo.CalculateInfo.BorrowLimit = extension.TranslateResourceNameAliases(extension.GetBorrowLimit(quota))
 
 	return quotaInfo
 }
 
+// getMaskedRuntimeNoLock returns the runtime of the resources limited by max, the reserved can always be used
+// even if the min is scaled down.
 func (qi *QuotaInfo) getMaskedRuntimeNoLock() v1.ResourceList {
-	return quotav1.Mask(qi.CalculateInfo.Runtime, quotav1.ResourceNames(qi.CalculateInfo.Max))
+	runtime := quotav1.Max(qi.CalculateInfo.Runtime, qi.CalculateInfo.Reserved)
+	return quotav1.Mask(runtime, quotav1.ResourceNames(qi.CalculateInfo.Max))
 }
 
 func (qi *QuotaInfo) clearForResetNoLock() {
 	qi.CalculateInfo.Request = v1.ResourceList{}
 	qi.CalculateInfo.Used = v1.ResourceList{}
 	qi.CalculateInfo.Runtime = v1.ResourceList{}
+	qi.CalculateInfo.RequestByPriority = PriorityResourceList{}
+	qi.CalculateInfo.UsedByPriority = PriorityResourceList{}
 	qi.RuntimeVersion = 0
 }
 
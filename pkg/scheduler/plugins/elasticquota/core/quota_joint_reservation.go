@@ -0,0 +1,258 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/coscheduling/util"
+)
+
+const (
+	// GangQuotaAdmitterName is the name of the GangQuotaAdmitter, the ElasticQuota plugin admits the gangGroups by it.
+	GangQuotaAdmitterName = "ElasticQuota"
+	// defaultGangQuotaReservationTTL is the ttl of the joint quota reservation of the gang without wait time.
+	defaultGangQuotaReservationTTL = 10 * time.Minute
+)
+
+// JointQuotaRequest is the request of a group of pods in one quota group reserved by ReserveJointQuota.
+type JointQuotaRequest struct {
+	// Name is the name of the joint quota reservation, the pods annotated with it by extension.AnnotationQuotaReservation
+	// or belonging to the gang identified by it take over the reservation.
+	Name      string
+	QuotaName string
+	// Request is the request of the pods, which is counted in the Used of the quota group.
+	Request v1.ResourceList
+	// UncountedRequest is the part of the Request not counted in the Request of the quota group yet.
+	UncountedRequest v1.ResourceList
+}
+
+// jointQuotaReservationName returns the name of the part of the joint quota reservation in the quota group, the "/"
+// never appears in the name of the quota groups so it never conflicts with them.
+func jointQuotaReservationName(name, quotaName string) string {
+	return fmt.Sprintf("%s/%s", name, quotaName)
+}
+
+// ReserveJointQuota reserves the requests in all the quota groups involved atomically until ttl elapses. The quota
+// groups are checked together with all the requests pending, so that they never admit part of the requests each.
+// The returned QuotaAdmissions are sorted by the quota name, and nothing is reserved if any of them isn't admitted.
+func (gqm *GroupQuotaManager) ReserveJointQuota(requests []*JointQuotaRequest, ttl time.Duration) ([]*QuotaAdmission, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("ttl of the joint quota reservation must be positive")
+	}
+
+	gqm.hierarchyUpdateLock.Lock()
+	defer gqm.hierarchyUpdateLock.Unlock()
+
+	gqm.quotaReservationLock.Lock()
+	defer gqm.quotaReservationLock.Unlock()
+
+	merged := map[string]*JointQuotaRequest{}
+	quotaRequests := map[string]v1.ResourceList{}
+	for _, request := range requests {
+		if request.Name == "" {
+			return nil, fmt.Errorf("name of the joint quota reservation is empty")
+		}
+		quotaName := request.QuotaName
+		if quotaName == "" {
+			quotaName = extension.DefaultQuotaName
+		}
+		quotaInfo := gqm.getQuotaInfoByNameNoLock(quotaName)
+		if quotaInfo == nil {
+			return nil, fmt.Errorf("quota %s not found", quotaName)
+		}
+		if quotaInfo.IsParent {
+			return nil, fmt.Errorf("quota %s is a parent quota group which can't admit pods", quotaName)
+		}
+		name := jointQuotaReservationName(request.Name, quotaName)
+		if _, ok := gqm.quotaReservations[name]; ok {
+			return nil, fmt.Errorf("quota reservation %s already exists", name)
+		}
+		if m, ok := merged[name]; ok {
+			m.Request = quotav1.Add(m.Request, request.Request)
+			m.UncountedRequest = quotav1.Add(m.UncountedRequest, request.UncountedRequest)
+		} else {
+			merged[name] = &JointQuotaRequest{
+				Name:             request.Name,
+				QuotaName:        quotaName,
+				Request:          request.Request.DeepCopy(),
+				UncountedRequest: request.UncountedRequest.DeepCopy(),
+			}
+		}
+		quotaRequests[quotaName] = quotav1.Add(quotaRequests[quotaName], request.Request)
+	}
+
+	// the runtime is calculated as if all the pods were pending, so that the quota groups sharing the resource
+	// are checked against each other
+	gqm.flushDirtyRequestsNoLock()
+	for _, request := range merged {
+		gqm.updateGroupDeltaRequestNoLock(request.QuotaName, request.UncountedRequest)
+	}
+	var rejected []string
+	admissions := make([]*QuotaAdmission, 0, len(quotaRequests))
+	for quotaName, request := range quotaRequests {
+		admission := gqm.checkQuotaAdmissionNoLock(gqm.getQuotaInfoByNameNoLock(quotaName), request)
+		if !admission.Admitted {
+			rejected = append(rejected, quotaName)
+		}
+		admissions = append(admissions, admission)
+	}
+	sort.Slice(admissions, func(i, j int) bool {
+		return admissions[i].Name < admissions[j].Name
+	})
+	if len(rejected) > 0 {
+		for _, request := range merged {
+			gqm.updateGroupDeltaRequestNoLock(request.QuotaName, quotav1.Subtract(v1.ResourceList{}, request.UncountedRequest))
+		}
+		klog.V(4).Infof("joint quota reservation is rejected by quotas %v", rejected)
+		return admissions, nil
+	}
+
+	systemOrDefaultUsedChanged := false
+	expireTime := time.Now().Add(ttl)
+	for name, request := range merged {
+		gqm.updateGroupDeltaUsedNoLock(request.QuotaName, request.Request)
+		if request.QuotaName == extension.SystemQuotaName || request.QuotaName == extension.DefaultQuotaName {
+			systemOrDefaultUsedChanged = true
+		}
+		gqm.quotaReservations[name] = &QuotaReservation{
+			Name:           name,
+			JointName:      request.Name,
+			QuotaName:      request.QuotaName,
+			Request:        request.Request,
+			ExpireTime:     expireTime,
+			PendingRequest: request.UncountedRequest.DeepCopy(),
+			PendingUsed:    request.Request.DeepCopy(),
+		}
+		klog.V(4).Infof("reserved quota %v in quota %s for joint quota reservation %s, ttl %v",
+			request.Request, request.QuotaName, request.Name, ttl)
+	}
+	if systemOrDefaultUsedChanged {
+		gqm.updateClusterTotalResourceNoLock(v1.ResourceList{})
+	}
+	return admissions, nil
+}
+
+// ReleaseJointQuotaReservation releases the parts of the joint quota reservation in all the quota groups which are
+// not taken over by the pods yet, it returns whether the reservation exists.
+func (gqm *GroupQuotaManager) ReleaseJointQuotaReservation(name string) bool {
+	gqm.hierarchyUpdateLock.RLock()
+	defer gqm.hierarchyUpdateLock.RUnlock()
+
+	gqm.quotaReservationLock.Lock()
+	defer gqm.quotaReservationLock.Unlock()
+
+	exist := false
+	for _, reservation := range gqm.quotaReservations {
+		if reservation.JointName == name {
+			gqm.releaseQuotaReservationNoLock(reservation)
+			exist = true
+		}
+	}
+	return exist
+}
+
+var _ frameworkext.GangAdmitter = &GangQuotaAdmitter{}
+
+// GangQuotaAdmitter admits the gangGroups whose children span several quota groups, e.g. the parameter servers in
+// one quota group and the workers in another. The quota of all the children is reserved jointly by
+// ReserveJointQuota, and taken over by the children once they are counted in the quota groups.
+type GangQuotaAdmitter struct {
+	gqm *GroupQuotaManager
+}
+
+func NewGangQuotaAdmitter(gqm *GroupQuotaManager) *GangQuotaAdmitter {
+	return &GangQuotaAdmitter{gqm: gqm}
+}
+
+func (a *GangQuotaAdmitter) Name() string {
+	return GangQuotaAdmitterName
+}
+
+func (a *GangQuotaAdmitter) AdmitGangGroup(children map[string][]*v1.Pod, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = defaultGangQuotaReservationTTL
+	}
+	var requests []*JointQuotaRequest
+	for gangId, pods := range children {
+		for _, pod := range pods {
+			state := a.gqm.GetPodAccountingState(pod.UID)
+			if state.countUsed() {
+				continue
+			}
+			request := &JointQuotaRequest{
+				Name:      gangId,
+				QuotaName: GetPodQuotaName(pod),
+				Request:   getPodRequest(pod),
+			}
+			if !state.countRequest() {
+				request.UncountedRequest = request.Request
+			}
+			requests = append(requests, request)
+		}
+	}
+	if len(requests) == 0 {
+		return nil
+	}
+
+	admissions, err := a.gqm.ReserveJointQuota(requests, ttl)
+	if err != nil {
+		return err
+	}
+	for _, admission := range admissions {
+		if !admission.Admitted {
+			return fmt.Errorf("quota %s rejects, reasons: %v", admission.Name, admission.Reasons)
+		}
+	}
+	return nil
+}
+
+func (a *GangQuotaAdmitter) ReleaseGangGroup(gangIds []string) {
+	for _, gangId := range gangIds {
+		a.gqm.ReleaseJointQuotaReservation(gangId)
+	}
+}
+
+// getPodQuotaReservationNoLock returns the quota reservation the pod takes over in the quota group, which is named by
+// the annotation extension.AnnotationQuotaReservation, or the joint quota reservation of the pod's gang.
+// gqm.quotaReservationLock should be held.
+func (gqm *GroupQuotaManager) getPodQuotaReservationNoLock(pod *v1.Pod, quotaName string) *QuotaReservation {
+	var names []string
+	if name := pod.Annotations[extension.AnnotationQuotaReservation]; name != "" {
+		if reservation, ok := gqm.quotaReservations[name]; ok && reservation.QuotaName == quotaName {
+			return reservation
+		}
+		names = append(names, name)
+	}
+	if gangName := util.GetGangNameByPod(pod); gangName != "" {
+		names = append(names, util.GetId(pod.Namespace, gangName))
+	}
+	for _, name := range names {
+		if reservation, ok := gqm.quotaReservations[jointQuotaReservationName(name, quotaName)]; ok {
+			return reservation
+		}
+	}
+	return nil
+}
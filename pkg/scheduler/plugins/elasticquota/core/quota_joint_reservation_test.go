@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestGangQuotaAdmitter(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000))
+	AddQuotaToManager(t, gqm, "ps", extension.RootQuotaName, 20, 200, 10, 100, true, false)
+	AddQuotaToManager(t, gqm, "worker", extension.RootQuotaName, 30, 300, 20, 200, true, false)
+	admitter := NewGangQuotaAdmitter(gqm)
+
+	assertQuota := func(quotaName string, request, used v1.ResourceList) {
+		quotaInfo := gqm.GetQuotaInfoByName(quotaName)
+		assert.True(t, quotav1.Equals(request, quotaInfo.GetRequest()), "request of %s: %v", quotaName, quotaInfo.GetRequest())
+		assert.True(t, quotav1.Equals(used, quotaInfo.GetUsed()), "used of %s: %v", quotaName, quotaInfo.GetUsed())
+	}
+	newPod := func(name, gangName, quotaName string, cpu, memory int64) *v1.Pod {
+		pod := newTestQuotaPod(name, cpu, memory)
+		pod.Labels = map[string]string{
+			extension.LabelQuotaName: quotaName,
+			v1alpha1.PodGroupLabel:   gangName,
+		}
+		return pod
+	}
+
+	// the workers of gang-a don't fit, so the parameter servers are not admitted either
+	children := map[string][]*v1.Pod{
+		"default/gang-a": {
+			newPod("ps-1", "gang-a", "ps", 4, 40),
+			newPod("worker-1", "gang-a", "worker", 20, 200),
+			newPod("worker-2", "gang-a", "worker", 20, 200),
+		},
+	}
+	assert.Error(t, admitter.AdmitGangGroup(children, time.Minute))
+	for _, quotaName := range []string{"ps", "worker"} {
+		assert.True(t, quotav1.IsZero(gqm.GetQuotaInfoByName(quotaName).GetRequest()))
+		assert.True(t, quotav1.IsZero(gqm.GetQuotaInfoByName(quotaName).GetUsed()))
+	}
+
+	// the pending pod is counted in the request already
+	ps := newPod("ps-1", "gang-b", "ps", 4, 40)
+	worker := newPod("worker-1", "gang-b", "worker", 10, 100)
+	assert.NoError(t, gqm.UpdatePodAccountingState("ps", ps, PodAccountingStatePending))
+	children = map[string][]*v1.Pod{
+		"default/gang-b": {ps, worker, newPod("worker-2", "gang-b", "worker", 10, 100)},
+	}
+	assert.NoError(t, admitter.AdmitGangGroup(children, time.Minute))
+	assertQuota("ps", createResourceList(4, 40), createResourceList(4, 40))
+	assertQuota("worker", createResourceList(20, 200), createResourceList(20, 200))
+	assert.Error(t, admitter.AdmitGangGroup(children, time.Minute))
+
+	// the children of the gang take over the joint quota reservation
+	assert.NoError(t, gqm.UpdatePodAccountingState("ps", ps, PodAccountingStateAssumed))
+	assert.NoError(t, gqm.UpdatePodAccountingState("worker", worker, PodAccountingStateAssumed))
+	assertQuota("ps", createResourceList(4, 40), createResourceList(4, 40))
+	assertQuota("worker", createResourceList(20, 200), createResourceList(20, 200))
+	assert.Nil(t, gqm.GetQuotaReservation(jointQuotaReservationName("default/gang-b", "ps")))
+
+	// the rest is released
+	admitter.ReleaseGangGroup([]string{"default/gang-b"})
+	assertQuota("ps", createResourceList(4, 40), createResourceList(4, 40))
+	assertQuota("worker", createResourceList(10, 100), createResourceList(10, 100))
+	assert.False(t, gqm.ReleaseJointQuotaReservation("default/gang-b"))
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/metrics"
+)
+
+const (
+	QuotaMetricsFieldRuntime = "runtime"
+	QuotaMetricsFieldUsed    = "used"
+	QuotaMetricsFieldRequest = "request"
+	QuotaMetricsFieldMin     = "min"
+	QuotaMetricsFieldMax     = "max"
+)
+
+// RunQuotaMetricsRecorder records the metrics of all the quota groups every interval until stopCh is closed.
+func (gqm *GroupQuotaManager) RunQuotaMetricsRecorder(interval time.Duration, stopCh <-chan struct{}) {
+	klog.Infof("start elastic quota metrics recorder, interval: %v", interval)
+	go wait.Until(gqm.RecordQuotaMetrics, interval, stopCh)
+}
+
+// RecordQuotaMetrics refreshes the runtime of the leaf quota groups, and exports the runtime, used, request,
+// min, max and the dominant share of all the quota groups. The min is the AutoScaleMin which is actually guaranteed.
+func (gqm *GroupQuotaManager) RecordQuotaMetrics() {
+	gqm.hierarchyUpdateLock.RLock()
+	defer gqm.hierarchyUpdateLock.RUnlock()
+
+	for quotaName, quotaInfo := range gqm.quotaInfoMap {
+		if !quotaInfo.IsParent {
+			// the runtime of the parents on the path is refreshed too
+			gqm.refreshRuntimeNoLock(quotaName)
+		}
+	}
+
+	// reset to remove the series of the deleted quota groups
+	metrics.ElasticQuotaResource.Reset()
+	for _, snapshot := range gqm.ListQuotaSnapshots() {
+		recordQuotaResource(snapshot.Name, QuotaMetricsFieldRuntime, snapshot.Runtime)
+		recordQuotaResource(snapshot.Name, QuotaMetricsFieldUsed, snapshot.Used)
+		recordQuotaResource(snapshot.Name, QuotaMetricsFieldRequest, snapshot.Request)
+		recordQuotaResource(snapshot.Name, QuotaMetricsFieldMin, snapshot.AutoScaleMin)
+		recordQuotaResource(snapshot.Name, QuotaMetricsFieldMax, snapshot.Max)
+	}
+	metrics.ElasticQuotaDominantShare.Reset()
+	for _, share := range gqm.getQuotaDominantSharesNoLock() {
+		metrics.ElasticQuotaDominantShare.WithLabelValues(share.Name, string(share.Resource)).Set(share.Share)
+	}
+}
+
+func recordQuotaResource(quotaName, field string, resourceList v1.ResourceList) {
+	for resourceName, quantity := range resourceList {
+		metrics.ElasticQuotaResource.WithLabelValues(quotaName, field, string(resourceName)).Set(float64(quantity.MilliValue()) / 1000)
+	}
+}
+
+// RecordAdmissionRejection counts the pod rejected by the quota group for the reason, e.g. exceeding the runtime.
+func RecordAdmissionRejection(quotaName, reason string) {
+	metrics.ElasticQuotaAdmissionRejections.WithLabelValues(quotaName, reason).Inc()
+}
+
+// RecordAdmissionRejections counts the pod rejected by the admission for each of its reasons, the exceeded
+// resources are trimmed from the reasons to keep the series bounded.
+func RecordAdmissionRejections(admission *QuotaAdmission) {
+	for _, reason := range admission.Reasons {
+		if i := strings.Index(reason, ":"); i >= 0 {
+			reason = reason[:i]
+		}
+		RecordAdmissionRejection(admission.Name, reason)
+	}
+}
+
+func recordRuntimeCalculationLatency(start time.Time) {
+	metrics.ElasticQuotaRuntimeCalculationLatency.Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/component-base/metrics/testutil"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/metrics"
+)
+
+func TestGroupQuotaManager_RecordQuotaMetrics(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	AddQuotaToManager(t, gqm, "parent", extension.RootQuotaName, 100, 1000*GigaByte, 50, 500*GigaByte, true, true)
+	AddQuotaToManager(t, gqm, "1", "parent", 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(40, 400*GigaByte))
+
+	gqm.RecordQuotaMetrics()
+
+	// the runtime of the leaf and its parent is refreshed
+	assert.Equal(t, createResourceList(40, 400*GigaByte), gqm.GetQuotaInfoByName("1").GetRuntime())
+	assert.Equal(t, createResourceList(40, 400*GigaByte), gqm.GetQuotaInfoByName("parent").GetRuntime())
+}
+
+func TestRecordAdmissionRejections(t *testing.T) {
+	metrics.Register()
+	admission := &QuotaAdmission{
+		Name:     "test-quota",
+		Admitted: false,
+		Reasons:  []string{AdmissionRejectReasonQuotaDeleting, AdmissionRejectReasonExceedMax + ": [cpu memory]"},
+	}
+	exceedMax := metrics.ElasticQuotaAdmissionRejections.WithLabelValues("test-quota", AdmissionRejectReasonExceedMax)
+	before, err := testutil.GetCounterMetricValue(exceedMax)
+	assert.NoError(t, err)
+
+	RecordAdmissionRejections(admission)
+	after, err := testutil.GetCounterMetricValue(exceedMax)
+	assert.NoError(t, err)
+	assert.Equal(t, before+1, after)
+	deleting, err := testutil.GetCounterMetricValue(
+		metrics.ElasticQuotaAdmissionRejections.WithLabelValues("test-quota", AdmissionRejectReasonQuotaDeleting))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), deleting)
+}
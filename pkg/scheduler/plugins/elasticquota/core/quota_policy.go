@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+// newRootQuotaPolicy returns the policy inherited by the children of the root quota group.
+func newRootQuotaPolicy() *extension.QuotaPolicy {
+	allowLentResource := true
+	return &extension.QuotaPolicy{
+		AllowLentResource: &allowLentResource,
+	}
+}
+
+// inheritQuotaPolicy merges the policy of the quota group with the effective policy of its parent, the fields set by
+// the quota group take precedence.
+func inheritQuotaPolicy(policy, parentPolicy *extension.QuotaPolicy) *extension.QuotaPolicy {
+	effective := parentPolicy.DeepCopy()
+	if effective == nil {
+		effective = &extension.QuotaPolicy{}
+	}
+	if policy == nil {
+		return effective
+	}
+	if policy.AllowLentResource != nil {
+		allowLentResource := *policy.AllowLentResource
+		effective.AllowLentResource = &allowLentResource
+	}
+	if len(policy.NodeSelector) > 0 && effective.NodeSelector == nil {
+		effective.NodeSelector = make(map[string]string, len(policy.NodeSelector))
+	}
+	for k, v := range policy.NodeSelector {
+		effective.NodeSelector[k] = v
+	}
+	if len(policy.DefaultLimits) > 0 && effective.DefaultLimits == nil {
+		effective.DefaultLimits = make(v1.ResourceList, len(policy.DefaultLimits))
+	}
+	for resourceName, quantity := range policy.DefaultLimits {
+		effective.DefaultLimits[resourceName] = quantity.DeepCopy()
+	}
+	if policy.EvictionPolicy != "" {
+		effective.EvictionPolicy = policy.EvictionPolicy
+	}
+	if policy.RuntimeCalculateStrategy != "" {
+		effective.RuntimeCalculateStrategy = policy.RuntimeCalculateStrategy
+	}
+	if policy.DeviceRestriction != nil {
+		effective.DeviceRestriction = intersectDeviceRestriction(effective.DeviceRestriction, policy.DeviceRestriction)
+	}
+	return effective
+}
+
+// updateEffectivePolicyRecursiveNoLock resolves the effective policy from top to bottom, and the AllowLentResource
+// of the quota group follows the effective policy. no need to lock gqm.lock
+func (gqm *GroupQuotaManager) updateEffectivePolicyRecursiveNoLock(topoNode *QuotaTopoNode, parentPolicy *extension.QuotaPolicy) {
+	for _, childTopoNode := range topoNode.GetChildGroupQuotaInfos() {
+		quotaInfo := childTopoNode.quotaInfo
+		quotaInfo.lock.Lock()
+		effective := inheritQuotaPolicy(quotaInfo.Policy, parentPolicy)
+		quotaInfo.EffectivePolicy = effective
+		quotaInfo.AllowLentResource = *effective.AllowLentResource
+		quotaInfo.lock.Unlock()
+
+		gqm.updateEffectivePolicyRecursiveNoLock(childTopoNode, effective)
+	}
+}
+
+// GetEffectiveQuotaPolicy returns the policy of the quota group merged with the policies of all its parents,
+// nil if the quota group doesn't exist.
+func (gqm *GroupQuotaManager) GetEffectiveQuotaPolicy(quotaName string) *extension.QuotaPolicy {
+	gqm.hierarchyUpdateLock.RLock()
+	defer gqm.hierarchyUpdateLock.RUnlock()
+
+	quotaInfo := gqm.getQuotaInfoByNameNoLock(quotaName)
+	if quotaInfo == nil {
+		return nil
+	}
+	quotaInfo.lock.Lock()
+	defer quotaInfo.lock.Unlock()
+	if quotaInfo.EffectivePolicy != nil {
+		return quotaInfo.EffectivePolicy.DeepCopy()
+	}
+	// the system and default quota groups are not in the quota tree
+	return inheritQuotaPolicy(quotaInfo.Policy, newRootQuotaPolicy())
+}
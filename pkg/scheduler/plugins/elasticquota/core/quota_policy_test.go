@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestGroupQuotaManager_GetEffectiveQuotaPolicy(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+
+	parent := CreateQuota("p", extension.RootQuotaName, 100, 1000*GigaByte, 50, 500*GigaByte, false, true)
+	parent.Annotations[extension.AnnotationQuotaPolicy] = `{"nodeSelector":{"pool":"a"},"defaultLimits":{"cpu":"1"},"evictionPolicy":"Never"}`
+	assert.NoError(t, gqm.UpdateQuota(parent, false))
+
+	// c1 doesn't set allowLentResource, and overrides the eviction policy
+	child1 := CreateQuota("c1", "p", 50, 500*GigaByte, 20, 200*GigaByte, true, false)
+	delete(child1.Labels, extension.LabelAllowLentResource)
+	child1.Annotations[extension.AnnotationQuotaPolicy] = `{"nodeSelector":{"zone":"z1"},"evictionPolicy":"Reclaimable"}`
+	assert.NoError(t, gqm.UpdateQuota(child1, false))
+	child2 := CreateQuota("c2", "p", 50, 500*GigaByte, 20, 200*GigaByte, true, false)
+	assert.NoError(t, gqm.UpdateQuota(child2, false))
+
+	policy := gqm.GetEffectiveQuotaPolicy("c1")
+	assert.False(t, *policy.AllowLentResource)
+	assert.Equal(t, map[string]string{"pool": "a", "zone": "z1"}, policy.NodeSelector)
+	assert.Len(t, policy.DefaultLimits, 1)
+	assert.Equal(t, int64(1), policy.DefaultLimits.Cpu().Value())
+	assert.Equal(t, extension.QuotaEvictionPolicyReclaimable, policy.EvictionPolicy)
+	assert.False(t, gqm.GetQuotaInfoByName("c1").AllowLentResource)
+
+	policy = gqm.GetEffectiveQuotaPolicy("c2")
+	assert.True(t, *policy.AllowLentResource)
+	assert.Equal(t, map[string]string{"pool": "a"}, policy.NodeSelector)
+	assert.Equal(t, extension.QuotaEvictionPolicyNever, policy.EvictionPolicy)
+	assert.True(t, gqm.GetQuotaInfoByName("c2").AllowLentResource)
+
+	// c1 follows the parent when the parent allows lending the resource
+	parent.Labels[extension.LabelAllowLentResource] = "true"
+	assert.NoError(t, gqm.UpdateQuota(parent, false))
+	assert.True(t, *gqm.GetEffectiveQuotaPolicy("c1").AllowLentResource)
+	assert.True(t, gqm.GetQuotaInfoByName("c1").AllowLentResource)
+
+	assert.True(t, *gqm.GetEffectiveQuotaPolicy(extension.DefaultQuotaName).AllowLentResource)
+	assert.Nil(t, gqm.GetEffectiveQuotaPolicy("not-exist"))
+}
+
+func TestGetQuotaPolicy(t *testing.T) {
+	quota := CreateQuota("1", extension.RootQuotaName, 100, 100, 10, 10, false, false)
+	policy, err := extension.GetQuotaPolicy(quota)
+	assert.NoError(t, err)
+	assert.False(t, *policy.AllowLentResource)
+
+	quota.Annotations[extension.AnnotationQuotaPolicy] = "invalid"
+	_, err = extension.GetQuotaPolicy(quota)
+	assert.Error(t, err)
+}
@@ -0,0 +1,295 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/services"
+)
+
+// QuotaReservation is the quota reserved for a group of pods atomically, e.g. all the replicas of a Job, so that
+// either all of them fit in the quota group or none is admitted. The reserved quota is counted in the Request and
+// the Used of the quota group until it is taken over by the pods annotated with extension.AnnotationQuotaReservation,
+// released, or expired.
+type QuotaReservation struct {
+	Name string
+	// JointName is the name of the joint quota reservation the reservation is part of, see ReserveJointQuota.
+	JointName string
+	QuotaName string
+	// Request is the total request of the pods reserved for.
+	Request    v1.ResourceList
+	ExpireTime time.Time
+	// PendingRequest is the part of the Request not taken over by the pods counted in the Request of the quota group.
+	PendingRequest v1.ResourceList
+	// PendingUsed is the part of the Request not taken over by the pods counted in the Used of the quota group.
+	PendingUsed v1.ResourceList
+}
+
+func (r *QuotaReservation) DeepCopy() *QuotaReservation {
+	if r == nil {
+		return nil
+	}
+	return &QuotaReservation{
+		Name:           r.Name,
+		JointName:      r.JointName,
+		QuotaName:      r.QuotaName,
+		Request:        r.Request.DeepCopy(),
+		ExpireTime:     r.ExpireTime,
+		PendingRequest: r.PendingRequest.DeepCopy(),
+		PendingUsed:    r.PendingUsed.DeepCopy(),
+	}
+}
+
+// ReserveQuota reserves the quota for replicas pods requesting podRequest in the quota group until ttl elapses.
+// The reservation is admitted only if the total request of the pods fits in the quota group as a whole, otherwise
+// nothing is reserved, and the returned QuotaAdmission explains why.
+func (gqm *GroupQuotaManager) ReserveQuota(name, quotaName string, podRequest v1.ResourceList, replicas int64, ttl time.Duration) (*QuotaAdmission, error) {
+	if name == "" {
+		return nil, fmt.Errorf("name of the quota reservation is empty")
+	}
+	if replicas <= 0 {
+		return nil, fmt.Errorf("replicas of the quota reservation %s must be positive", name)
+	}
+	if ttl <= 0 {
+		return nil, fmt.Errorf("ttl of the quota reservation %s must be positive", name)
+	}
+
+	gqm.hierarchyUpdateLock.Lock()
+	defer gqm.hierarchyUpdateLock.Unlock()
+
+	if quotaName == "" {
+		quotaName = extension.DefaultQuotaName
+	}
+	quotaInfo := gqm.getQuotaInfoByNameNoLock(quotaName)
+	if quotaInfo == nil {
+		return nil, fmt.Errorf("quota %s not found", quotaName)
+	}
+	if quotaInfo.IsParent {
+		return nil, fmt.Errorf("quota %s is a parent quota group which can't admit pods", quotaName)
+	}
+
+	gqm.quotaReservationLock.Lock()
+	defer gqm.quotaReservationLock.Unlock()
+
+	if _, ok := gqm.quotaReservations[name]; ok {
+		return nil, fmt.Errorf("quota reservation %s already exists", name)
+	}
+
+	// the runtime is calculated as if the pods were pending, so that the reservation can borrow the idle resource
+	request := multiplyResourceList(podRequest, replicas)
+	gqm.flushDirtyRequestsNoLock()
+	gqm.updateGroupDeltaRequestNoLock(quotaName, request)
+	admission := gqm.checkQuotaAdmissionNoLock(quotaInfo, request)
+	if !admission.Admitted {
+		gqm.updateGroupDeltaRequestNoLock(quotaName, quotav1.Subtract(v1.ResourceList{}, request))
+		klog.V(4).Infof("quota reservation %s of quota %s is rejected, reasons: %v", name, quotaName, admission.Reasons)
+		return admission, nil
+	}
+	gqm.updateGroupDeltaUsedNoLock(quotaName, request)
+	if quotaName == extension.SystemQuotaName || quotaName == extension.DefaultQuotaName {
+		gqm.updateClusterTotalResourceNoLock(v1.ResourceList{})
+	}
+
+	gqm.quotaReservations[name] = &QuotaReservation{
+		Name:           name,
+		QuotaName:      quotaName,
+		Request:        request,
+		ExpireTime:     time.Now().Add(ttl),
+		PendingRequest: request.DeepCopy(),
+		PendingUsed:    request.DeepCopy(),
+	}
+	klog.V(4).Infof("reserved quota %v in quota %s for quota reservation %s, ttl %v", request, quotaName, name, ttl)
+	return admission, nil
+}
+
+// GetQuotaReservation returns a copy of the quota reservation, nil if it doesn't exist.
+func (gqm *GroupQuotaManager) GetQuotaReservation(name string) *QuotaReservation {
+	gqm.quotaReservationLock.Lock()
+	defer gqm.quotaReservationLock.Unlock()
+	return gqm.quotaReservations[name].DeepCopy()
+}
+
+// ReleaseQuotaReservation releases the part of the reserved quota not taken over by the pods yet, it returns
+// whether the reservation exists.
+func (gqm *GroupQuotaManager) ReleaseQuotaReservation(name string) bool {
+	gqm.hierarchyUpdateLock.RLock()
+	defer gqm.hierarchyUpdateLock.RUnlock()
+
+	gqm.quotaReservationLock.Lock()
+	defer gqm.quotaReservationLock.Unlock()
+
+	reservation, ok := gqm.quotaReservations[name]
+	if !ok {
+		return false
+	}
+	gqm.releaseQuotaReservationNoLock(reservation)
+	return true
+}
+
+// CleanupExpiredQuotaReservations releases the quota reservations expired at now.
+func (gqm *GroupQuotaManager) CleanupExpiredQuotaReservations(now time.Time) {
+	gqm.hierarchyUpdateLock.RLock()
+	defer gqm.hierarchyUpdateLock.RUnlock()
+
+	gqm.quotaReservationLock.Lock()
+	defer gqm.quotaReservationLock.Unlock()
+
+	for _, reservation := range gqm.quotaReservations {
+		if now.After(reservation.ExpireTime) {
+			klog.V(4).Infof("quota reservation %s of quota %s expired", reservation.Name, reservation.QuotaName)
+			gqm.releaseQuotaReservationNoLock(reservation)
+		}
+	}
+}
+
+// RunQuotaReservationCleanup releases the expired quota reservations every interval until stopCh is closed.
+func (gqm *GroupQuotaManager) RunQuotaReservationCleanup(interval time.Duration, stopCh <-chan struct{}) {
+	go wait.Until(func() {
+		gqm.CleanupExpiredQuotaReservations(time.Now())
+	}, interval, stopCh)
+}
+
+// QuotaReservationRequest is the body to create a quota reservation by the endpoint.
+type QuotaReservationRequest struct {
+	QuotaName  string          `json:"quotaName,omitempty"`
+	PodRequest v1.ResourceList `json:"podRequest"`
+	Replicas   int64           `json:"replicas"`
+	TTL        metav1.Duration `json:"ttl"`
+}
+
+// registerQuotaReservationEndpoints exposes the quota reservations, e.g. POST /quotaReservations/job-a with the
+// QuotaReservationRequest as the body reserves the quota and responses the QuotaAdmission, GET and DELETE
+// /quotaReservations/job-a get and release the reservation.
+func (gqm *GroupQuotaManager) registerQuotaReservationEndpoints(group *gin.RouterGroup) {
+	group.POST("/quotaReservations/:name", func(c *gin.Context) {
+		request := &QuotaReservationRequest{}
+		if err := c.ShouldBindJSON(request); err != nil {
+			services.ResponseErrorMessage(c, http.StatusBadRequest, "invalid quota reservation request: %v", err)
+			return
+		}
+		admission, err := gqm.ReserveQuota(c.Param("name"), request.QuotaName,
+			extension.TranslateResourceNameAliases(request.PodRequest), request.Replicas, request.TTL.Duration)
+		if err != nil {
+			services.ResponseErrorMessage(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		c.JSON(http.StatusOK, admission)
+	})
+	group.GET("/quotaReservations/:name", func(c *gin.Context) {
+		name := c.Param("name")
+		reservation := gqm.GetQuotaReservation(name)
+		if reservation == nil {
+			services.ResponseErrorMessage(c, http.StatusNotFound, "quota reservation %s not found", name)
+			return
+		}
+		c.JSON(http.StatusOK, reservation)
+	})
+	group.DELETE("/quotaReservations/:name", func(c *gin.Context) {
+		name := c.Param("name")
+		if !gqm.ReleaseQuotaReservation(name) {
+			services.ResponseErrorMessage(c, http.StatusNotFound, "quota reservation %s not found", name)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+}
+
+func (gqm *GroupQuotaManager) releaseQuotaReservationNoLock(reservation *QuotaReservation) {
+	if !quotav1.IsZero(reservation.PendingRequest) {
+		gqm.markGroupDeltaRequestNoLock(reservation.QuotaName, quotav1.Subtract(v1.ResourceList{}, reservation.PendingRequest))
+	}
+	if !quotav1.IsZero(reservation.PendingUsed) {
+		gqm.updateGroupDeltaUsedNoLock(reservation.QuotaName, quotav1.Subtract(v1.ResourceList{}, reservation.PendingUsed))
+		if reservation.QuotaName == extension.SystemQuotaName || reservation.QuotaName == extension.DefaultQuotaName {
+			gqm.updateClusterTotalResourceNoLock(v1.ResourceList{})
+		}
+	}
+	delete(gqm.quotaReservations, reservation.Name)
+}
+
+// takeOverQuotaReservationNoLock moves the request of the pod out of its quota reservation when the pod starts to
+// be counted in the Request or the Used of the quota group, so that the pod isn't counted twice.
+func (gqm *GroupQuotaManager) takeOverQuotaReservationNoLock(pod *v1.Pod, info *podAccountingInfo, from, to PodAccountingState) {
+	takeOverRequest := !from.countRequest() && to.countRequest()
+	takeOverUsed := !from.countUsed() && to.countUsed()
+	if !takeOverRequest && !takeOverUsed {
+		return
+	}
+
+	gqm.quotaReservationLock.Lock()
+	defer gqm.quotaReservationLock.Unlock()
+
+	reservation := gqm.getPodQuotaReservationNoLock(pod, info.quotaName)
+	if reservation == nil {
+		return
+	}
+	if takeOverRequest {
+		delta := minResourceList(reservation.PendingRequest, info.request)
+		reservation.PendingRequest = quotav1.Subtract(reservation.PendingRequest, delta)
+		gqm.markGroupDeltaRequestNoLock(info.quotaName, quotav1.Subtract(v1.ResourceList{}, delta))
+	}
+	if takeOverUsed {
+		delta := minResourceList(reservation.PendingUsed, info.request)
+		reservation.PendingUsed = quotav1.Subtract(reservation.PendingUsed, delta)
+		gqm.updateGroupDeltaUsedNoLock(info.quotaName, quotav1.Subtract(v1.ResourceList{}, delta))
+	}
+	if quotav1.IsZero(reservation.PendingRequest) && quotav1.IsZero(reservation.PendingUsed) {
+		klog.V(4).Infof("quota reservation %s of quota %s is taken over by the pods", reservation.Name, info.quotaName)
+		delete(gqm.quotaReservations, reservation.Name)
+	}
+}
+
+// minResourceList returns the smaller quantity of each resource in both a and b.
+func minResourceList(a, b v1.ResourceList) v1.ResourceList {
+	result := v1.ResourceList{}
+	for name, quantityA := range a {
+		quantityB, ok := b[name]
+		if !ok {
+			continue
+		}
+		if quantityA.Cmp(quantityB) < 0 {
+			result[name] = quantityA.DeepCopy()
+		} else {
+			result[name] = quantityB.DeepCopy()
+		}
+	}
+	return result
+}
+
+func multiplyResourceList(resourceList v1.ResourceList, n int64) v1.ResourceList {
+	result := make(v1.ResourceList, len(resourceList))
+	for name, quantity := range resourceList {
+		if name == v1.ResourceCPU {
+			result[name] = *resource.NewMilliQuantity(quantity.MilliValue()*n, quantity.Format)
+		} else {
+			result[name] = *resource.NewQuantity(quantity.Value()*n, quantity.Format)
+		}
+	}
+	return result
+}
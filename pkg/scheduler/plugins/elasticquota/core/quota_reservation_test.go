@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestGroupQuotaManager_ReserveQuota(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 50, 500, 10, 100, true, false)
+	AddQuotaToManager(t, gqm, "2", extension.RootQuotaName, 50, 500, 10, 100, true, false)
+
+	assertQuota := func(quotaName string, request, used v1.ResourceList) {
+		quotaInfo := gqm.GetQuotaInfoByName(quotaName)
+		assert.True(t, quotav1.Equals(request, quotaInfo.GetRequest()), "request of %s: %v", quotaName, quotaInfo.GetRequest())
+		assert.True(t, quotav1.Equals(used, quotaInfo.GetUsed()), "used of %s: %v", quotaName, quotaInfo.GetUsed())
+	}
+
+	_, err := gqm.ReserveQuota("job-1", "not-exist", createResourceList(4, 10), 10, time.Minute)
+	assert.Error(t, err)
+
+	// all the replicas fit in the quota group
+	admission, err := gqm.ReserveQuota("job-1", "1", createResourceList(4, 10), 10, time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, admission.Admitted)
+	assertQuota("1", createResourceList(40, 100), createResourceList(40, 100))
+
+	_, err = gqm.ReserveQuota("job-1", "1", createResourceList(4, 10), 1, time.Minute)
+	assert.Error(t, err)
+
+	// none of the replicas is admitted if they don't fit as a whole
+	admission, err = gqm.ReserveQuota("job-2", "1", createResourceList(4, 10), 5, time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, admission.Admitted)
+	assert.Nil(t, gqm.GetQuotaReservation("job-2"))
+	assertQuota("1", createResourceList(40, 100), createResourceList(40, 100))
+
+	// the pods of the reservation take over the reserved quota
+	pod := newTestQuotaPod("pod-1", 4, 10)
+	pod.Annotations = map[string]string{extension.AnnotationQuotaReservation: "job-1"}
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod, PodAccountingStatePending))
+	assertQuota("1", createResourceList(40, 100), createResourceList(40, 100))
+	assert.NoError(t, gqm.UpdatePodAccountingState("1", pod, PodAccountingStateAssumed))
+	assertQuota("1", createResourceList(40, 100), createResourceList(40, 100))
+	reservation := gqm.GetQuotaReservation("job-1")
+	assert.True(t, quotav1.Equals(createResourceList(36, 90), reservation.PendingRequest))
+	assert.True(t, quotav1.Equals(createResourceList(36, 90), reservation.PendingUsed))
+
+	// the rest is released
+	assert.True(t, gqm.ReleaseQuotaReservation("job-1"))
+	assert.False(t, gqm.ReleaseQuotaReservation("job-1"))
+	assertQuota("1", createResourceList(4, 10), createResourceList(4, 10))
+	assert.True(t, quotav1.IsZero(gqm.GetQuotaInfoByName("2").GetUsed()))
+}
+
+func TestGroupQuotaManager_CleanupExpiredQuotaReservations(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 50, 500, 10, 100, true, false)
+
+	admission, err := gqm.ReserveQuota("job-1", "1", createResourceList(2, 10), 10, time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, admission.Admitted)
+
+	gqm.CleanupExpiredQuotaReservations(time.Now())
+	assert.NotNil(t, gqm.GetQuotaReservation("job-1"))
+
+	gqm.CleanupExpiredQuotaReservations(time.Now().Add(2 * time.Minute))
+	assert.Nil(t, gqm.GetQuotaReservation("job-1"))
+	quotaInfo := gqm.GetQuotaInfoByName("1")
+	assert.True(t, quotav1.IsZero(quotaInfo.GetRequest()))
+	assert.True(t, quotav1.IsZero(quotaInfo.GetUsed()))
+}
+
+func TestGroupQuotaManager_QuotaReservationEndpoints(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 50, 500, 10, 100, true, false)
+	engine := gin.New()
+	gqm.RegisterEndpoints(engine.Group("/"))
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	w := serve(http.MethodPost, "/quotaReservations/job-1",
+		`{"quotaName":"1","podRequest":{"cpu":"4","memory":"10"},"replicas":10,"ttl":"1m"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	admission := &QuotaAdmission{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), admission))
+	assert.True(t, admission.Admitted)
+	assert.True(t, quotav1.Equals(createResourceList(40, 100), gqm.GetQuotaInfoByName("1").GetUsed()))
+
+	w = serve(http.MethodPost, "/quotaReservations/job-2", `{"quotaName":"1","podRequest":{"cpu":"4"},"replicas":0,"ttl":"1m"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = serve(http.MethodPost, "/quotaReservations/job-2", `invalid`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serve(http.MethodGet, "/quotaReservations/job-1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	reservation := &QuotaReservation{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), reservation))
+	assert.Equal(t, "1", reservation.QuotaName)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/quotaReservations/job-2", "").Code)
+
+	assert.Equal(t, http.StatusOK, serve(http.MethodDelete, "/quotaReservations/job-1", "").Code)
+	assert.Nil(t, gqm.GetQuotaReservation("job-1"))
+	assert.True(t, quotav1.Equals(createResourceList(0, 0), gqm.GetQuotaInfoByName("1").GetUsed()))
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodDelete, "/quotaReservations/job-1", "").Code)
+}
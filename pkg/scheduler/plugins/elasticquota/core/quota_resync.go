@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedlister "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/metrics"
+)
+
+const (
+	quotaResyncResultConsistent = "consistent"
+	quotaResyncResultDrifted    = "drifted"
+	quotaResyncResultFailed     = "failed"
+)
+
+var _ frameworkext.LeaderResyncer = &QuotaResyncer{}
+
+// QuotaResyncResult is the inconsistency found by the resync, which is corrected already.
+type QuotaResyncResult struct {
+	// AddedQuotas are the quota groups missing in the GroupQuotaManager.
+	AddedQuotas []string
+	// DeletedQuotas are the quota groups in the GroupQuotaManager which no longer exist.
+	DeletedQuotas []string
+	// Drifts are the accounting drifts of the leaf quota groups.
+	Drifts []*QuotaAccountingDrift
+}
+
+func (r *QuotaResyncResult) Consistent() bool {
+	return len(r.AddedQuotas) == 0 && len(r.DeletedQuotas) == 0 && len(r.Drifts) == 0
+}
+
+// QuotaResyncer rebuilds the quota groups and the pod accounting of the GroupQuotaManager from the informer caches.
+// The events observed by a standby scheduler or missed around a failover may leave the accounting stale or
+// double-counted, so the resync should run when the scheduler starts leading, see frameworkext.LeaderResyncer.
+type QuotaResyncer struct {
+	gqm         *GroupQuotaManager
+	quotaLister schedlister.ElasticQuotaLister
+	podLister   listerv1.PodLister
+}
+
+func NewQuotaResyncer(gqm *GroupQuotaManager, quotaLister schedlister.ElasticQuotaLister, podLister listerv1.PodLister) *QuotaResyncer {
+	return &QuotaResyncer{
+		gqm:         gqm,
+		quotaLister: quotaLister,
+		podLister:   podLister,
+	}
+}
+
+func (r *QuotaResyncer) Resync(ctx context.Context) error {
+	quotas, err := r.quotaLister.List(labels.Everything())
+	if err != nil {
+		metrics.ElasticQuotaResyncs.WithLabelValues(quotaResyncResultFailed).Inc()
+		return err
+	}
+	pods, err := r.podLister.List(labels.Everything())
+	if err != nil {
+		metrics.ElasticQuotaResyncs.WithLabelValues(quotaResyncResultFailed).Inc()
+		return err
+	}
+	// the scheduler may be stopped or lose the leadership meanwhile
+	if err := ctx.Err(); err != nil {
+		metrics.ElasticQuotaResyncs.WithLabelValues(quotaResyncResultFailed).Inc()
+		return err
+	}
+
+	start := time.Now()
+	result := r.gqm.Resync(quotas, pods)
+	if result.Consistent() {
+		metrics.ElasticQuotaResyncs.WithLabelValues(quotaResyncResultConsistent).Inc()
+		klog.Infof("elastic quota resync finished in %v, %d quotas and %d pods are consistent",
+			time.Since(start), len(quotas), len(pods))
+		return nil
+	}
+	metrics.ElasticQuotaResyncs.WithLabelValues(quotaResyncResultDrifted).Inc()
+	klog.Warningf("elastic quota resync finished in %v, corrected the inconsistency, added quotas: %v, deleted quotas: %v, drifted quotas: %d",
+		time.Since(start), result.AddedQuotas, result.DeletedQuotas, len(result.Drifts))
+	return nil
+}
+
+// Resync updates the quota groups to the quotas, deletes the quota groups not in the quotas, then verifies the
+// accounting with the pods. The built-in quota groups are never deleted.
+func (gqm *GroupQuotaManager) Resync(quotas []*v1alpha1.ElasticQuota, pods []*v1.Pod) *QuotaResyncResult {
+	result := &QuotaResyncResult{}
+	listed := sets.NewString()
+	for _, quota := range quotas {
+		listed.Insert(quota.Name)
+		if gqm.GetQuotaInfoByName(quota.Name) == nil {
+			result.AddedQuotas = append(result.AddedQuotas, quota.Name)
+		}
+		if err := gqm.UpdateQuota(quota, false); err != nil {
+			klog.Errorf("failed to resync elastic quota %s, err: %v", quota.Name, err)
+		}
+	}
+
+	builtin := sets.NewString(extension.RootQuotaName, extension.SystemQuotaName, extension.DefaultQuotaName)
+	for _, quotaName := range gqm.listQuotaNames() {
+		if listed.Has(quotaName) || builtin.Has(quotaName) {
+			continue
+		}
+		quota := &v1alpha1.ElasticQuota{ObjectMeta: metav1.ObjectMeta{Name: quotaName}}
+		if err := gqm.UpdateQuota(quota, true); err != nil {
+			klog.Errorf("failed to delete stale elastic quota %s, err: %v", quotaName, err)
+			continue
+		}
+		result.DeletedQuotas = append(result.DeletedQuotas, quotaName)
+	}
+	sort.Strings(result.AddedQuotas)
+	sort.Strings(result.DeletedQuotas)
+
+	// the pods of the deleted quota groups are untracked by the verification
+	result.Drifts = gqm.VerifyAccounting(pods)
+	return result
+}
+
+func (gqm *GroupQuotaManager) listQuotaNames() []string {
+	gqm.hierarchyUpdateLock.RLock()
+	defer gqm.hierarchyUpdateLock.RUnlock()
+	names := make([]string, 0, len(gqm.quotaInfoMap))
+	for quotaName := range gqm.quotaInfoMap {
+		names = append(names, quotaName)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedlister "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestGroupQuotaManager_Resync(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 50, 500, 10, 100, true, false)
+	AddQuotaToManager(t, gqm, "stale", extension.RootQuotaName, 50, 500, 10, 100, true, false)
+
+	pod1 := newTestQuotaPod("pod-1", 2, 20)
+	pod1.Labels = map[string]string{extension.LabelQuotaName: "1"}
+	pod1.Spec.NodeName = "node-1"
+	pod2 := newTestQuotaPod("pod-2", 1, 10)
+	pod2.Labels = map[string]string{extension.LabelQuotaName: "2"}
+	pod2.Spec.NodeName = "node-1"
+	// the creation of pod-1 is missed
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(5, 50))
+
+	quotas := []*v1alpha1.ElasticQuota{
+		CreateQuota("1", extension.RootQuotaName, 50, 500, 10, 100, true, false),
+		CreateQuota("2", extension.RootQuotaName, 50, 500, 10, 100, true, false),
+	}
+	result := gqm.Resync(quotas, []*v1.Pod{pod1, pod2})
+	assert.False(t, result.Consistent())
+	assert.Equal(t, []string{"2"}, result.AddedQuotas)
+	assert.Equal(t, []string{"stale"}, result.DeletedQuotas)
+	assert.Len(t, result.Drifts, 2)
+
+	assert.Nil(t, gqm.GetQuotaInfoByName("stale"))
+	assert.NotNil(t, gqm.GetQuotaInfoByName(extension.SystemQuotaName))
+	assert.NotNil(t, gqm.GetQuotaInfoByName(extension.DefaultQuotaName))
+	assert.True(t, quotav1.Equals(createResourceList(2, 20), gqm.GetQuotaInfoByName("1").GetRequest()))
+	assert.True(t, quotav1.Equals(createResourceList(2, 20), gqm.GetQuotaInfoByName("1").GetUsed()))
+	assert.True(t, quotav1.Equals(createResourceList(1, 10), gqm.GetQuotaInfoByName("2").GetUsed()))
+
+	// nothing to correct after the resync
+	result = gqm.Resync(quotas, []*v1.Pod{pod1, pod2})
+	assert.True(t, result.Consistent())
+}
+
+func TestQuotaResyncer_ResyncCanceled(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	AddQuotaToManager(t, gqm, "stale", extension.RootQuotaName, 50, 500, 10, 100, true, false)
+	resyncer := NewQuotaResyncer(gqm,
+		schedlister.NewElasticQuotaLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})),
+		listerv1.NewPodLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})))
+
+	// the stopped scheduler doesn't touch the quota groups
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, resyncer.Resync(ctx))
+	assert.NotNil(t, gqm.GetQuotaInfoByName("stale"))
+
+	assert.NoError(t, resyncer.Resync(context.Background()))
+	assert.Nil(t, gqm.GetQuotaInfoByName("stale"))
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/services"
+)
+
+var _ services.APIServiceProvider = &GroupQuotaManager{}
+
+// QuotaRuntimeSample is the runtime, used and request of a quota group at a moment.
+type QuotaRuntimeSample struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Runtime   v1.ResourceList `json:"runtime,omitempty"`
+	Used      v1.ResourceList `json:"used,omitempty"`
+	Request   v1.ResourceList `json:"request,omitempty"`
+}
+
+// quotaRuntimeRing is a ring buffer of the samples of a quota group, the oldest sample is overwritten when it's full.
+type quotaRuntimeRing struct {
+	samples []QuotaRuntimeSample
+	// next is the index the next sample is written to
+	next  int
+	count int
+}
+
+func (r *quotaRuntimeRing) add(sample QuotaRuntimeSample) {
+	r.samples[r.next] = sample
+	r.next = (r.next + 1) % len(r.samples)
+	if r.count < len(r.samples) {
+		r.count++
+	}
+}
+
+// list returns the samples not earlier than since in time order.
+func (r *quotaRuntimeRing) list(since time.Time) []QuotaRuntimeSample {
+	result := make([]QuotaRuntimeSample, 0, r.count)
+	start := (r.next - r.count + len(r.samples)) % len(r.samples)
+	for i := 0; i < r.count; i++ {
+		sample := r.samples[(start+i)%len(r.samples)]
+		if sample.Timestamp.Before(since) {
+			continue
+		}
+		result = append(result, sample)
+	}
+	return result
+}
+
+// quotaRuntimeHistory keeps the samples of all the quota groups in the retention.
+type quotaRuntimeHistory struct {
+	lock     sync.RWMutex
+	capacity int
+	rings    map[string]*quotaRuntimeRing
+}
+
+func newQuotaRuntimeHistory(interval, retention time.Duration) *quotaRuntimeHistory {
+	capacity := int(retention / interval)
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &quotaRuntimeHistory{
+		capacity: capacity,
+		rings:    map[string]*quotaRuntimeRing{},
+	}
+}
+
+// RunQuotaRuntimeHistoryRecorder records the runtime, used and request of all the quota groups every interval,
+// and keeps the samples in the retention, until stopCh is closed.
+func (gqm *GroupQuotaManager) RunQuotaRuntimeHistoryRecorder(interval, retention time.Duration, stopCh <-chan struct{}) {
+	if interval <= 0 || retention <= 0 {
+		return
+	}
+	gqm.hierarchyUpdateLock.Lock()
+	gqm.runtimeHistory = newQuotaRuntimeHistory(interval, retention)
+	gqm.hierarchyUpdateLock.Unlock()
+
+	klog.Infof("start elastic quota runtime history recorder, interval: %v, retention: %v", interval, retention)
+	go wait.Until(func() {
+		gqm.RecordQuotaRuntimeHistory(time.Now())
+	}, interval, stopCh)
+}
+
+// RecordQuotaRuntimeHistory refreshes the runtime of the leaf quota groups and records a sample of all the quota
+// groups at now, the samples of the deleted quota groups are dropped.
+func (gqm *GroupQuotaManager) RecordQuotaRuntimeHistory(now time.Time) {
+	gqm.hierarchyUpdateLock.RLock()
+	history := gqm.runtimeHistory
+	if history == nil {
+		gqm.hierarchyUpdateLock.RUnlock()
+		return
+	}
+	for quotaName, quotaInfo := range gqm.quotaInfoMap {
+		if !quotaInfo.IsParent {
+			// the runtime of the parents on the path is refreshed too
+			gqm.refreshRuntimeNoLock(quotaName)
+		}
+	}
+	gqm.hierarchyUpdateLock.RUnlock()
+
+	snapshots := gqm.ListQuotaSnapshots()
+	history.lock.Lock()
+	defer history.lock.Unlock()
+	rings := make(map[string]*quotaRuntimeRing, len(snapshots))
+	for _, snapshot := range snapshots {
+		ring := history.rings[snapshot.Name]
+		if ring == nil {
+			ring = &quotaRuntimeRing{samples: make([]QuotaRuntimeSample, history.capacity)}
+		}
+		ring.add(QuotaRuntimeSample{
+			Timestamp: now,
+			Runtime:   snapshot.Runtime,
+			Used:      snapshot.Used,
+			Request:   snapshot.Request,
+		})
+		rings[snapshot.Name] = ring
+	}
+	history.rings = rings
+}
+
+// GetQuotaRuntimeHistory returns the samples of the quota group recorded since the time in time order,
+// it returns nil if the history isn't recorded or the quota group doesn't exist.
+func (gqm *GroupQuotaManager) GetQuotaRuntimeHistory(quotaName string, since time.Time) []QuotaRuntimeSample {
+	gqm.hierarchyUpdateLock.RLock()
+	history := gqm.runtimeHistory
+	gqm.hierarchyUpdateLock.RUnlock()
+	if history == nil {
+		return nil
+	}
+
+	history.lock.RLock()
+	defer history.lock.RUnlock()
+	ring := history.rings[quotaName]
+	if ring == nil {
+		return nil
+	}
+	return ring.list(since)
+}
+
+// RegisterEndpoints exposes the runtime history of the quota groups, the optional query parameter "duration"
+// limits the samples to the recent duration, e.g. /quotas/team-a/history?duration=30m. The effective configuration
+// and the usage by priority of the quota groups, the quota reservations, the dry-run admission and the dominant
+// shares are exposed too, e.g. /quotas/team-a/effective, /quotas/team-a/priorityUsage,
+// /quotaAdmission/team-a?cpu=4&memory=8Gi and /quotaDominantShares.
+func (gqm *GroupQuotaManager) RegisterEndpoints(group *gin.RouterGroup) {
+	group.GET("/quotas/:quotaName/history", func(c *gin.Context) {
+		quotaName := c.Param("quotaName")
+		var since time.Time
+		if value := c.Query("duration"); value != "" {
+			duration, err := time.ParseDuration(value)
+			if err != nil {
+				services.ResponseErrorMessage(c, http.StatusBadRequest, "invalid duration %q: %v", value, err)
+				return
+			}
+			since = time.Now().Add(-duration)
+		}
+		samples := gqm.GetQuotaRuntimeHistory(quotaName, since)
+		if samples == nil {
+			services.ResponseErrorMessage(c, http.StatusNotFound, "no runtime history of quota %s", quotaName)
+			return
+		}
+		c.JSON(http.StatusOK, samples)
+	})
+	gqm.registerEffectiveConfigEndpoint(group)
+	gqm.registerPriorityUsageEndpoint(group)
+	gqm.registerQuotaReservationEndpoints(group)
+	group.GET("/quotaAdmission/:quotaName", QuotaAdmissionHandler(gqm))
+	group.GET("/quotaDominantShares", QuotaDominantShareHandler(gqm))
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestGroupQuotaManager_RecordQuotaRuntimeHistory(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	AddQuotaToManager(t, gqm, "2", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+
+	// not recorded yet
+	now := time.Now()
+	gqm.RecordQuotaRuntimeHistory(now)
+	assert.Nil(t, gqm.GetQuotaRuntimeHistory("1", time.Time{}))
+
+	// keep 3 samples at most
+	gqm.runtimeHistory = newQuotaRuntimeHistory(time.Minute, 3*time.Minute)
+	for i := 1; i <= 4; i++ {
+		gqm.UpdateGroupDeltaRequest("1", createResourceList(10, 0))
+		gqm.RecordQuotaRuntimeHistory(now.Add(time.Duration(i) * time.Minute))
+	}
+	samples := gqm.GetQuotaRuntimeHistory("1", time.Time{})
+	assert.Len(t, samples, 3)
+	for i, sample := range samples {
+		assert.Equal(t, now.Add(time.Duration(i+2)*time.Minute), sample.Timestamp)
+		assert.Equal(t, int64(10*(i+2)), sample.Request.Cpu().Value())
+		assert.Equal(t, int64(10*(i+2)), sample.Runtime.Cpu().Value())
+	}
+	samples = gqm.GetQuotaRuntimeHistory("1", now.Add(3*time.Minute))
+	assert.Len(t, samples, 2)
+	assert.Equal(t, int64(40), samples[1].Runtime.Cpu().Value())
+
+	// the samples of the deleted quota group are dropped
+	assert.NoError(t, gqm.UpdateQuota(CreateQuota("2", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false), true))
+	gqm.RecordQuotaRuntimeHistory(now.Add(5 * time.Minute))
+	assert.Nil(t, gqm.GetQuotaRuntimeHistory("2", time.Time{}))
+	assert.Len(t, gqm.GetQuotaRuntimeHistory("1", time.Time{}), 3)
+}
+
+func TestGroupQuotaManager_RuntimeHistoryEndpoints(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	gqm.runtimeHistory = newQuotaRuntimeHistory(time.Minute, time.Hour)
+	gqm.RecordQuotaRuntimeHistory(time.Now().Add(-2 * time.Hour))
+	gqm.RecordQuotaRuntimeHistory(time.Now())
+
+	engine := gin.Default()
+	gqm.RegisterEndpoints(engine.Group("/"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/quotas/1/history?duration=1h", nil)
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	var samples []QuotaRuntimeSample
+	assert.NoError(t, json.NewDecoder(w.Result().Body).Decode(&samples))
+	assert.Len(t, samples, 1)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/quotas/1/history?duration=abc", nil)
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/quotas/not-exist/history", nil)
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+}
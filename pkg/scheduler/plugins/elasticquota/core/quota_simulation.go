@@ -0,0 +1,251 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+// QuotaRuntimeChange is the runtime of a quota group before and after the simulated change, the Current is nil
+// if the quota group is added by the change, and the Simulated is nil if it's deleted.
+type QuotaRuntimeChange struct {
+	Name      string
+	Current   v1.ResourceList
+	Simulated v1.ResourceList
+}
+
+// SimulateQuotaUpdate returns the runtime of all the quota groups sorted by name before and after the quota is
+// updated, or deleted if isDelete. It calculates on a copy of the quota groups and never mutates the
+// GroupQuotaManager, so the capacity planners can preview the effect of a change before applying it.
+func (gqm *GroupQuotaManager) SimulateQuotaUpdate(quota *v1alpha1.ElasticQuota, isDelete bool) ([]*QuotaRuntimeChange, error) {
+	current := gqm.cloneForSimulation()
+	simulated := gqm.cloneForSimulation()
+	if err := simulated.checkSimulatedQuotaUpdate(quota, isDelete); err != nil {
+		return nil, err
+	}
+	if err := simulated.UpdateQuota(quota, isDelete); err != nil {
+		return nil, err
+	}
+
+	currentRuntime := current.refreshAllRuntime()
+	simulatedRuntime := simulated.refreshAllRuntime()
+	changes := make([]*QuotaRuntimeChange, 0, len(simulatedRuntime))
+	for name, runtime := range simulatedRuntime {
+		changes = append(changes, &QuotaRuntimeChange{
+			Name:      name,
+			Current:   currentRuntime[name],
+			Simulated: runtime,
+		})
+	}
+	for name, runtime := range currentRuntime {
+		if _, ok := simulatedRuntime[name]; !ok {
+			changes = append(changes, &QuotaRuntimeChange{
+				Name:    name,
+				Current: runtime,
+			})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+	return changes, nil
+}
+
+// checkSimulatedQuotaUpdate rejects the change breaking the quota tree, which the webhook rejects in the cluster.
+func (gqm *GroupQuotaManager) checkSimulatedQuotaUpdate(quota *v1alpha1.ElasticQuota, isDelete bool) error {
+	gqm.hierarchyUpdateLock.RLock()
+	defer gqm.hierarchyUpdateLock.RUnlock()
+
+	if quota.Name == extension.RootQuotaName || quota.Name == extension.SystemQuotaName {
+		return fmt.Errorf("quota %v can't be changed", quota.Name)
+	}
+	if isDelete {
+		if _, ok := gqm.quotaInfoMap[quota.Name]; !ok {
+			return fmt.Errorf("quota %v not found", quota.Name)
+		}
+		for _, quotaInfo := range gqm.quotaInfoMap {
+			if quotaInfo.ParentName == quota.Name {
+				return fmt.Errorf("quota %v still has child quota %v", quota.Name, quotaInfo.Name)
+			}
+		}
+		return nil
+	}
+	return gqm.checkParentQuotaNoLock(quota.Name, extension.GetParentQuotaName(quota))
+}
+
+func (gqm *GroupQuotaManager) checkParentQuotaNoLock(quotaName, parentName string) error {
+	if parentName == extension.RootQuotaName {
+		return nil
+	}
+	parentInfo, ok := gqm.quotaInfoMap[parentName]
+	if !ok {
+		return fmt.Errorf("parent quota %v of quota %v not found", parentName, quotaName)
+	}
+	if !parentInfo.IsParent {
+		return fmt.Errorf("parent quota %v of quota %v is not a parent quota", parentName, quotaName)
+	}
+	return nil
+}
+
+// cloneForSimulation copies the quota groups and the settings into a new GroupQuotaManager, the pending request
+// deltas of the batch recalculation are applied to the copy.
+func (gqm *GroupQuotaManager) cloneForSimulation() *GroupQuotaManager {
+	gqm.hierarchyUpdateLock.RLock()
+	clone := &GroupQuotaManager{
+		totalResourceExceptSystemAndDefaultUsed: gqm.totalResourceExceptSystemAndDefaultUsed.DeepCopy(),
+		totalResource:                           gqm.totalResource.DeepCopy(),
+		resourceKeys:                            make(map[v1.ResourceName]struct{}),
+		quotaInfoMap:                            make(map[string]*QuotaInfo, len(gqm.quotaInfoMap)),
+		runtimeQuotaCalculatorMap:               make(map[string]*RuntimeQuotaCalculator),
+		quotaTopoNodeMap:                        make(map[string]*QuotaTopoNode),
+		scaleMinQuotaEnabled:                    gqm.scaleMinQuotaEnabled,
+		minQuotaOversellRatio:                   gqm.minQuotaOversellRatio,
+		scaleMinQuotaManager:                    NewScaleMinQuotaManager(),
+		runtimeCalculateStrategyName:            gqm.runtimeCalculateStrategyName,
+		federatedLendingPolicy:                  gqm.federatedLendingPolicy.DeepCopy(),
+		podAccountingCache:                      newPodAccountingCache(),
+		reservationAccountingCache:              newReservationAccountingCache(),
+		sharedWeightOverrides:                   make(map[string]v1.ResourceList, len(gqm.sharedWeightOverrides)),
+		nodeAllocatableMap:                      make(map[string]v1.ResourceList, len(gqm.nodeAllocatableMap)),
+		nodeLabelsMap:                           make(map[string]map[string]string, len(gqm.nodeLabelsMap)),
+		nodePools:                               make(map[string]*nodePool),
+		nodePoolsTotalResource:                  v1.ResourceList{},
+		quotaReservations:                       make(map[string]*QuotaReservation),
+	}
+	for quotaName, quotaInfo := range gqm.quotaInfoMap {
+		clone.quotaInfoMap[quotaName] = quotaInfo.DeepCopy()
+	}
+	for quotaName, sharedWeight := range gqm.sharedWeightOverrides {
+		clone.sharedWeightOverrides[quotaName] = sharedWeight.DeepCopy()
+	}
+	// the node pools are rebuilt by the allocatable and the labels of the nodes
+	for nodeName, allocatable := range gqm.nodeAllocatableMap {
+		clone.nodeAllocatableMap[nodeName] = allocatable.DeepCopy()
+	}
+	for nodeName, nodeLabels := range gqm.nodeLabelsMap {
+		clone.nodeLabelsMap[nodeName] = copyNodeLabels(nodeLabels)
+	}
+	if gqm.minQuotaPriorityClasses != nil {
+		clone.minQuotaPriorityClasses = make(map[extension.PriorityClass]struct{}, len(gqm.minQuotaPriorityClasses))
+		for priorityClass := range gqm.minQuotaPriorityClasses {
+			clone.minQuotaPriorityClasses[priorityClass] = struct{}{}
+		}
+	}
+	if gqm.usageDecay != nil {
+		clone.usageDecay = newUsageDecay(gqm.usageDecay.halfLife, gqm.usageDecay.penaltyFactor)
+		for quotaName, multipliers := range gqm.usageDecay.multipliers {
+			clone.usageDecay.multipliers[quotaName] = make(map[v1.ResourceName]float64, len(multipliers))
+			for resourceName, multiplier := range multipliers {
+				clone.usageDecay.multipliers[quotaName][resourceName] = multiplier
+			}
+		}
+	}
+	pendingRequests := map[string]v1.ResourceList{}
+	if gqm.requestBatch != nil {
+		gqm.requestBatch.lock.Lock()
+		for quotaName, deltaReq := range gqm.requestBatch.dirtyRequests {
+			pendingRequests[quotaName] = deltaReq.DeepCopy()
+		}
+		gqm.requestBatch.lock.Unlock()
+	}
+	gqm.hierarchyUpdateLock.RUnlock()
+
+	clone.hierarchyUpdateLock.Lock()
+	defer clone.hierarchyUpdateLock.Unlock()
+	clone.runtimeQuotaCalculatorMap[extension.RootQuotaName] = NewRuntimeQuotaCalculator(extension.RootQuotaName)
+	clone.updateQuotaGroupConfigNoLock()
+	for quotaName, deltaReq := range pendingRequests {
+		clone.updateGroupDeltaRequestNoLock(quotaName, deltaReq)
+	}
+	return clone
+}
+
+// refreshAllRuntime refreshes and returns the runtime of all the quota groups.
+func (gqm *GroupQuotaManager) refreshAllRuntime() map[string]v1.ResourceList {
+	gqm.hierarchyUpdateLock.RLock()
+	defer gqm.hierarchyUpdateLock.RUnlock()
+
+	runtime := make(map[string]v1.ResourceList, len(gqm.quotaInfoMap))
+	for quotaName := range gqm.quotaInfoMap {
+		runtime[quotaName] = gqm.refreshRuntimeNoLock(quotaName)
+	}
+	return runtime
+}
+
+// NewGroupQuotaManagerFromQuotas builds a GroupQuotaManager from the ElasticQuotas exported from the cluster to
+// simulate offline. The Request of the leaf quota groups is restored from the extension.AnnotationRequest published
+// by the QuotaStatusWriter, and the Used is restored from the status.
+func NewGroupQuotaManagerFromQuotas(quotas []*v1alpha1.ElasticQuota, totalResource v1.ResourceList) (*GroupQuotaManager, error) {
+	var systemGroupMax, defaultGroupMax v1.ResourceList
+	for _, quota := range quotas {
+		switch quota.Name {
+		case extension.SystemQuotaName:
+			systemGroupMax = quota.Spec.Max
+		case extension.DefaultQuotaName:
+			defaultGroupMax = quota.Spec.Max
+		}
+	}
+	gqm := NewGroupQuotaManager(systemGroupMax, defaultGroupMax)
+
+	gqm.hierarchyUpdateLock.Lock()
+	defer gqm.hierarchyUpdateLock.Unlock()
+
+	for _, quota := range quotas {
+		if quota.Name == extension.RootQuotaName || quota.Name == extension.SystemQuotaName {
+			continue
+		}
+		quotaInfo := NewQuotaInfoFromQuota(quota)
+		if localQuotaInfo, ok := gqm.quotaInfoMap[quota.Name]; ok {
+			localQuotaInfo.UpdateQuotaInfoFromRemote(quotaInfo)
+		} else {
+			gqm.quotaInfoMap[quota.Name] = quotaInfo
+		}
+	}
+	for quotaName, quotaInfo := range gqm.quotaInfoMap {
+		if quotaName == extension.SystemQuotaName || quotaName == extension.DefaultQuotaName {
+			continue
+		}
+		if err := gqm.checkParentQuotaNoLock(quotaName, quotaInfo.ParentName); err != nil {
+			return nil, err
+		}
+	}
+	gqm.updateQuotaGroupConfigNoLock()
+
+	for _, quota := range quotas {
+		quotaInfo := gqm.quotaInfoMap[quota.Name]
+		if quotaInfo == nil || quotaInfo.IsParent {
+			continue
+		}
+		if data, ok := quota.Annotations[extension.AnnotationRequest]; ok {
+			request := v1.ResourceList{}
+			if err := json.Unmarshal([]byte(data), &request); err != nil {
+				return nil, fmt.Errorf("failed to parse request of quota %v, err: %v", quota.Name, err)
+			}
+			gqm.updateGroupDeltaRequestNoLock(quota.Name, request)
+		}
+		gqm.updateGroupDeltaUsedNoLock(quota.Name, quota.Status.Used)
+	}
+	gqm.updateClusterTotalResourceNoLock(totalResource)
+	return gqm, nil
+}
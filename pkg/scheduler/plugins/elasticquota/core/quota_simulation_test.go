@@ -0,0 +1,88 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestGroupQuotaManager_SimulateQuotaUpdate(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	AddQuotaToManager(t, gqm, "2", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(100, 100*GigaByte))
+	gqm.UpdateGroupDeltaRequest("2", createResourceList(100, 100*GigaByte))
+
+	// raise the min of quota 1, the rest is shared equally
+	quota := CreateQuota("1", extension.RootQuotaName, 100, 1000*GigaByte, 60, 200*GigaByte, true, false)
+	changes, err := gqm.SimulateQuotaUpdate(quota, false)
+	assert.NoError(t, err)
+	changeMap := map[string]*QuotaRuntimeChange{}
+	for _, change := range changes {
+		changeMap[change.Name] = change
+	}
+	assert.Equal(t, int64(50), changeMap["1"].Current.Cpu().Value())
+	assert.Equal(t, int64(70), changeMap["1"].Simulated.Cpu().Value())
+	assert.Equal(t, int64(50), changeMap["2"].Current.Cpu().Value())
+	assert.Equal(t, int64(30), changeMap["2"].Simulated.Cpu().Value())
+
+	// the live state is not mutated
+	assert.Equal(t, int64(20), gqm.GetQuotaInfoByName("1").CalculateInfo.OriginalMin.Cpu().Value())
+	assert.Equal(t, int64(50), cpuValue(gqm.RefreshRuntime("1")))
+
+	// add a new quota
+	quota = CreateQuota("3", extension.RootQuotaName, 100, 1000*GigaByte, 40, 200*GigaByte, true, false)
+	changes, err = gqm.SimulateQuotaUpdate(quota, false)
+	assert.NoError(t, err)
+	for _, change := range changes {
+		if change.Name == "3" {
+			assert.Nil(t, change.Current)
+			assert.NotNil(t, change.Simulated)
+		}
+	}
+	assert.Nil(t, gqm.GetQuotaInfoByName("3"))
+
+	// the quota tree is broken
+	quota = CreateQuota("4", "not-exist", 100, 1000*GigaByte, 40, 200*GigaByte, true, false)
+	_, err = gqm.SimulateQuotaUpdate(quota, false)
+	assert.Error(t, err)
+	quota = CreateQuota("4", "2", 100, 1000*GigaByte, 40, 200*GigaByte, true, false)
+	_, err = gqm.SimulateQuotaUpdate(quota, false)
+	assert.Error(t, err)
+}
+
+func TestNewGroupQuotaManagerFromQuotas(t *testing.T) {
+	quota1 := CreateQuota("1", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	quota1.Annotations[extension.AnnotationRequest] = `{"cpu":"100","memory":"100Gi"}`
+	quota2 := CreateQuota("2", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	quota2.Annotations[extension.AnnotationRequest] = `{"cpu":"10","memory":"10Gi"}`
+
+	gqm, err := NewGroupQuotaManagerFromQuotas([]*v1alpha1.ElasticQuota{quota1, quota2}, createResourceList(100, 1000*GigaByte))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(90), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(10), cpuValue(gqm.RefreshRuntime("2")))
+
+	quota3 := CreateQuota("3", "not-exist", 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	_, err = NewGroupQuotaManagerFromQuotas([]*v1alpha1.ElasticQuota{quota1, quota3}, createResourceList(100, 1000*GigaByte))
+	assert.Error(t, err)
+}
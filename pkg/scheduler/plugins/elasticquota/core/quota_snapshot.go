@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"sort"
+	"sync/atomic"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// QuotaSnapshot is an immutable view of a quota group published by the GroupQuotaManager, it can be read
+// without any lock and must never be modified. Each change of the quota group publishes a new snapshot instead of
+// updating the published one, so the readers on the scheduling hot path never contend with the writers.
+// The Runtime is the one calculated by the last RefreshRuntime, which may be stale until it's refreshed again.
+type QuotaSnapshot struct {
+	Name              string
+	ParentName        string
+	IsParent          bool
+	AllowLentResource bool
+	Max               v1.ResourceList
+	OriginalMin       v1.ResourceList
+	AutoScaleMin      v1.ResourceList
+	Reserved          v1.ResourceList
+	BorrowLimit       v1.ResourceList
+	SharedWeight      v1.ResourceList
+	Request           v1.ResourceList
+	Used              v1.ResourceList
+	Runtime           v1.ResourceList
+	RequestByPriority PriorityResourceList
+	UsedByPriority    PriorityResourceList
+	// Version increases each time a snapshot is published, a newer snapshot has a larger Version
+	Version int64
+}
+
+// quotaSnapshotRef holds the latest snapshot of a quota group, the writers replace it atomically.
+type quotaSnapshotRef struct {
+	value atomic.Value
+}
+
+func (r *quotaSnapshotRef) load() *QuotaSnapshot {
+	snapshot, _ := r.value.Load().(*QuotaSnapshot)
+	return snapshot
+}
+
+// quotaSnapshotTable is the immutable table of the quota groups, it's replaced as a whole only when the
+// topology of the quota groups changes, and the snapshot of each quota group is replaced in its ref.
+type quotaSnapshotTable map[string]*quotaSnapshotRef
+
+// GetQuotaSnapshot returns the latest snapshot of the quota group without any lock, nil if not found.
+func (gqm *GroupQuotaManager) GetQuotaSnapshot(quotaName string) *QuotaSnapshot {
+	table, _ := gqm.quotaSnapshots.Load().(quotaSnapshotTable)
+	if ref, ok := table[quotaName]; ok {
+		return ref.load()
+	}
+	return nil
+}
+
+// ListQuotaSnapshots returns the latest snapshots of all the quota groups sorted by name without any lock.
+func (gqm *GroupQuotaManager) ListQuotaSnapshots() []*QuotaSnapshot {
+	table, _ := gqm.quotaSnapshots.Load().(quotaSnapshotTable)
+	snapshots := make([]*QuotaSnapshot, 0, len(table))
+	for _, ref := range table {
+		if snapshot := ref.load(); snapshot != nil {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Name < snapshots[j].Name
+	})
+	return snapshots
+}
+
+// newQuotaSnapshotNoLock copies the quota group into a new snapshot, the caller should hold the lock of quotaInfo.
+func (gqm *GroupQuotaManager) newQuotaSnapshotNoLock(quotaInfo *QuotaInfo) *QuotaSnapshot {
+	return &QuotaSnapshot{
+		Name:              quotaInfo.Name,
+		ParentName:        quotaInfo.ParentName,
+		IsParent:          quotaInfo.IsParent,
+		AllowLentResource: quotaInfo.AllowLentResource,
+		Max:               quotaInfo.CalculateInfo.Max.DeepCopy(),
+		OriginalMin:       quotaInfo.CalculateInfo.OriginalMin.DeepCopy(),
+		AutoScaleMin:      quotaInfo.CalculateInfo.AutoScaleMin.DeepCopy(),
+		Reserved:          quotaInfo.CalculateInfo.Reserved.DeepCopy(),
+		BorrowLimit:       quotaInfo.CalculateInfo.BorrowLimit.DeepCopy(),
+		SharedWeight:      quotaInfo.CalculateInfo.SharedWeight.DeepCopy(),
+		Request:           quotaInfo.CalculateInfo.Request.DeepCopy(),
+		Used:              quotaInfo.CalculateInfo.Used.DeepCopy(),
+		Runtime:           quotaInfo.CalculateInfo.Runtime.DeepCopy(),
+		RequestByPriority: quotaInfo.CalculateInfo.RequestByPriority.DeepCopy(),
+		UsedByPriority:    quotaInfo.CalculateInfo.UsedByPriority.DeepCopy(),
+		Version:           atomic.AddInt64(&gqm.quotaSnapshotVersion, 1),
+	}
+}
+
+// publishQuotaSnapshotNoLock publishes the new snapshot of the quota group, the caller should hold the lock of
+// quotaInfo. The quota group not in the table yet is published by the next rebuildQuotaSnapshotsNoLock.
+func (gqm *GroupQuotaManager) publishQuotaSnapshotNoLock(quotaInfo *QuotaInfo) {
+	table, _ := gqm.quotaSnapshots.Load().(quotaSnapshotTable)
+	ref, ok := table[quotaInfo.Name]
+	if !ok {
+		return
+	}
+	ref.value.Store(gqm.newQuotaSnapshotNoLock(quotaInfo))
+}
+
+// rebuildQuotaSnapshotsNoLock publishes a new table with the snapshots of all the quota groups, the caller should
+// hold the write lock of hierarchyUpdateLock.
+func (gqm *GroupQuotaManager) rebuildQuotaSnapshotsNoLock() {
+	table := make(quotaSnapshotTable, len(gqm.quotaInfoMap))
+	for quotaName, quotaInfo := range gqm.quotaInfoMap {
+		ref := &quotaSnapshotRef{}
+		quotaInfo.lock.Lock()
+		ref.value.Store(gqm.newQuotaSnapshotNoLock(quotaInfo))
+		quotaInfo.lock.Unlock()
+		table[quotaName] = ref
+	}
+	gqm.quotaSnapshots.Store(table)
+}
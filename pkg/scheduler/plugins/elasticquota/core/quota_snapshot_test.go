@@ -0,0 +1,156 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestGroupQuotaManager_QuotaSnapshot(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	assert.NotNil(t, gqm.GetQuotaSnapshot(extension.DefaultQuotaName))
+	assert.Nil(t, gqm.GetQuotaSnapshot("1"))
+
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	AddQuotaToManager(t, gqm, "parent", extension.RootQuotaName, 100, 1000*GigaByte, 50, 500*GigaByte, true, true)
+	AddQuotaToManager(t, gqm, "1", "parent", 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	snapshot := gqm.GetQuotaSnapshot("1")
+	assert.NotNil(t, snapshot)
+	assert.Equal(t, "parent", snapshot.ParentName)
+	assert.True(t, quotav1.Equals(createResourceList(100, 1000*GigaByte), snapshot.Max))
+	assert.True(t, quotav1.IsZero(snapshot.Request))
+
+	// the change publishes new snapshots of the quota group and its parents, the old one is never modified
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(30, 300*GigaByte))
+	gqm.UpdateGroupDeltaUsed("1", createResourceList(10, 100*GigaByte))
+	assert.True(t, quotav1.IsZero(snapshot.Request))
+	newSnapshot := gqm.GetQuotaSnapshot("1")
+	assert.Greater(t, newSnapshot.Version, snapshot.Version)
+	assert.True(t, quotav1.Equals(createResourceList(30, 300*GigaByte), newSnapshot.Request))
+	assert.True(t, quotav1.Equals(createResourceList(10, 100*GigaByte), newSnapshot.Used))
+	assert.True(t, quotav1.Equals(createResourceList(30, 300*GigaByte), gqm.GetQuotaSnapshot("parent").Request))
+	assert.True(t, quotav1.Equals(createResourceList(10, 100*GigaByte), gqm.GetQuotaSnapshot("parent").Used))
+
+	// the runtime is published when refreshed
+	gqm.RefreshRuntime("1")
+	assert.True(t, quotav1.Equals(createResourceList(30, 300*GigaByte), gqm.GetQuotaSnapshot("1").Runtime))
+
+	snapshots := gqm.ListQuotaSnapshots()
+	var names []string
+	for _, s := range snapshots {
+		names = append(names, s.Name)
+	}
+	assert.Equal(t, []string{"1", extension.DefaultQuotaName, "parent", extension.SystemQuotaName}, names)
+
+	// the deleted quota group is removed
+	assert.NoError(t, gqm.UpdateQuota(CreateQuota("1", "parent", 100, 1000*GigaByte, 20, 200*GigaByte, true, false), true))
+	assert.Nil(t, gqm.GetQuotaSnapshot("1"))
+}
+
+const (
+	benchmarkSnapshotParents     = 50
+	benchmarkSnapshotLeavesPer   = 100
+	benchmarkSnapshotPodsPerLeaf = 20
+)
+
+var (
+	benchmarkSnapshotOnce sync.Once
+	benchmarkSnapshotGQM  *GroupQuotaManager
+)
+
+// getBenchmarkSnapshotGroupQuotaManager returns the GroupQuotaManager with 5k leaf quota groups and 100k pods.
+func getBenchmarkSnapshotGroupQuotaManager() *GroupQuotaManager {
+	benchmarkSnapshotOnce.Do(func() {
+		gqm := NewGroupQuotaManager4Test()
+		gqm.UpdateClusterTotalResource(createResourceList(100000, 100000*GigaByte))
+		for i := 0; i < benchmarkSnapshotParents; i++ {
+			parentName := fmt.Sprintf("parent-%d", i)
+			AddQuotaToManager2(gqm, parentName, extension.RootQuotaName, 2000, 2000*GigaByte, 1000, 1000*GigaByte, true, true)
+			for j := 0; j < benchmarkSnapshotLeavesPer; j++ {
+				quotaName := fmt.Sprintf("%s-%d", parentName, j)
+				AddQuotaToManager2(gqm, quotaName, parentName, 40, 40*GigaByte, 10, 10*GigaByte, true, false)
+				for k := 0; k < benchmarkSnapshotPodsPerLeaf; k++ {
+					pod := newTestQuotaPod(fmt.Sprintf("%s-pod-%d", quotaName, k), 1, GigaByte)
+					_ = gqm.UpdatePodAccountingState(quotaName, pod, PodAccountingStateRunning)
+				}
+			}
+		}
+		benchmarkSnapshotGQM = gqm
+	})
+	return benchmarkSnapshotGQM
+}
+
+// benchmarkQuotaRead reads the quota groups in parallel while a writer keeps changing the used of them.
+func benchmarkQuotaRead(b *testing.B, read func(gqm *GroupQuotaManager, quotaName string)) {
+	gqm := getBenchmarkSnapshotGroupQuotaManager()
+	leafNum := benchmarkSnapshotParents * benchmarkSnapshotLeavesPer
+	quotaNames := make([]string, 0, leafNum)
+	for i := 0; i < benchmarkSnapshotParents; i++ {
+		for j := 0; j < benchmarkSnapshotLeavesPer; j++ {
+			quotaNames = append(quotaNames, fmt.Sprintf("parent-%d-%d", i, j))
+		}
+	}
+
+	stopCh := make(chan struct{})
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		delta := createResourceList(1, GigaByte)
+		negDelta := createResourceList(-1, -GigaByte)
+		for i := 0; ; i++ {
+			select {
+			case <-stopCh:
+				return
+			default:
+			}
+			quotaName := quotaNames[i%leafNum]
+			gqm.UpdateGroupDeltaUsed(quotaName, delta)
+			gqm.UpdateGroupDeltaUsed(quotaName, negDelta)
+		}
+	}()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			read(gqm, quotaNames[i%leafNum])
+			i++
+		}
+	})
+	b.StopTimer()
+	close(stopCh)
+	<-writerDone
+}
+
+func BenchmarkGroupQuotaManager_ReadQuotaInfoDeepCopy(b *testing.B) {
+	benchmarkQuotaRead(b, func(gqm *GroupQuotaManager, quotaName string) {
+		_ = gqm.GetQuotaInfoByName(quotaName).DeepCopy().CalculateInfo.Used
+	})
+}
+
+func BenchmarkGroupQuotaManager_ReadQuotaSnapshot(b *testing.B) {
+	benchmarkQuotaRead(b, func(gqm *GroupQuotaManager, quotaName string) {
+		_ = gqm.GetQuotaSnapshot(quotaName).Used
+	})
+}
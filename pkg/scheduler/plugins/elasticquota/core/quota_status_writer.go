@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	schedclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	schedlister "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+// QuotaStatusWriter periodically publishes the Used of the quota groups to the status of the ElasticQuotas,
+// the Request and Runtime to the annotations extension.AnnotationRequest and extension.AnnotationRuntime, and
+// the EffectiveQuotaConfig to extension.AnnotationEffectiveConfig, so that the dashboards, the controllers and
+// the users can consume them without scraping the logs or merging the annotations across the hierarchy.
+type QuotaStatusWriter struct {
+	gqm         *GroupQuotaManager
+	client      schedclientset.Interface
+	quotaLister schedlister.ElasticQuotaLister
+	interval    time.Duration
+}
+
+func NewQuotaStatusWriter(gqm *GroupQuotaManager, client schedclientset.Interface, quotaLister schedlister.ElasticQuotaLister, interval time.Duration) *QuotaStatusWriter {
+	return &QuotaStatusWriter{
+		gqm:         gqm,
+		client:      client,
+		quotaLister: quotaLister,
+		interval:    interval,
+	}
+}
+
+// Start runs the writer until stopCh is closed.
+func (w *QuotaStatusWriter) Start(stopCh <-chan struct{}) {
+	klog.Infof("start elastic quota status writer, interval: %v", w.interval)
+	go wait.Until(w.Sync, w.interval, stopCh)
+}
+
+// Sync patches the ElasticQuotas whose published Used, Request, Runtime or effective config is out of date.
+func (w *QuotaStatusWriter) Sync() {
+	quotas, err := w.quotaLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list elastic quotas, err: %v", err)
+		return
+	}
+	for _, quota := range quotas {
+		if quota.Name == extension.RootQuotaName {
+			continue
+		}
+		quotaInfo := w.gqm.GetQuotaInfoByName(quota.Name)
+		if quotaInfo == nil {
+			continue
+		}
+		runtime := w.gqm.RefreshRuntime(quota.Name)
+		quotaInfo = quotaInfo.DeepCopy()
+		effectiveConfig := w.gqm.GetEffectiveQuotaConfig(quota.Name)
+		if err := w.patchQuota(quota, quotaInfo.CalculateInfo.Used, quotaInfo.CalculateInfo.Request, runtime, effectiveConfig); err != nil {
+			klog.Errorf("failed to update status of elastic quota %v/%v, err: %v", quota.Namespace, quota.Name, err)
+		}
+	}
+}
+
+func (w *QuotaStatusWriter) patchQuota(quota *v1alpha1.ElasticQuota, used, request, runtime v1.ResourceList,
+	effectiveConfig *EffectiveQuotaConfig) error {
+	annotations := map[string]string{}
+	values := map[string]interface{}{
+		extension.AnnotationRequest: request,
+		extension.AnnotationRuntime: runtime,
+	}
+	if effectiveConfig != nil {
+		values[extension.AnnotationEffectiveConfig] = effectiveConfig
+	}
+	for key, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if quota.Annotations[key] != string(data) {
+			annotations[key] = string(data)
+		}
+	}
+	if len(annotations) > 0 {
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{"annotations": annotations},
+		})
+		if err != nil {
+			return err
+		}
+		_, err = w.client.SchedulingV1alpha1().ElasticQuotas(quota.Namespace).Patch(context.TODO(), quota.Name,
+			types.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			return err
+		}
+	}
+
+	if quota.Status.Used == nil || !quotav1.Equals(quota.Status.Used, used) {
+		patch, err := json.Marshal(map[string]interface{}{
+			"status": map[string]interface{}{"used": used},
+		})
+		if err != nil {
+			return err
+		}
+		_, err = w.client.SchedulingV1alpha1().ElasticQuotas(quota.Namespace).Patch(context.TODO(), quota.Name,
+			types.MergePatchType, patch, metav1.PatchOptions{}, "status")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned/fake"
+	schedlister "sigs.k8s.io/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestQuotaStatusWriter_Sync(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	quota := AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	quota.Namespace = "default"
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(40, 400*GigaByte))
+	gqm.UpdateGroupDeltaUsed("1", createResourceList(30, 300*GigaByte))
+
+	client := fake.NewSimpleClientset(quota)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.NoError(t, indexer.Add(quota))
+	writer := NewQuotaStatusWriter(gqm, client, schedlister.NewElasticQuotaLister(indexer), time.Second)
+	writer.Sync()
+
+	got, err := client.SchedulingV1alpha1().ElasticQuotas("default").Get(context.TODO(), "1", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.True(t, quotav1.Equals(createResourceList(30, 300*GigaByte), got.Status.Used), "used: %v", got.Status.Used)
+	var request, runtime v1.ResourceList
+	assert.NoError(t, json.Unmarshal([]byte(got.Annotations[extension.AnnotationRequest]), &request))
+	assert.NoError(t, json.Unmarshal([]byte(got.Annotations[extension.AnnotationRuntime]), &runtime))
+	assert.True(t, quotav1.Equals(createResourceList(40, 400*GigaByte), request), "request: %v", request)
+	assert.True(t, quotav1.Equals(createResourceList(40, 400*GigaByte), runtime), "runtime: %v", runtime)
+	effective := &EffectiveQuotaConfig{}
+	assert.NoError(t, json.Unmarshal([]byte(got.Annotations[extension.AnnotationEffectiveConfig]), effective))
+	assert.Equal(t, "1", effective.Name)
+	assert.True(t, quotav1.Equals(createResourceList(100, 1000*GigaByte), effective.Max), "max: %v", effective.Max)
+
+	// nothing is patched if the published values are up to date
+	assert.NoError(t, indexer.Update(got))
+	client.ClearActions()
+	writer.Sync()
+	assert.Empty(t, client.Actions())
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+// reservationAccountingCache tracks the unallocated resource of the active Reservations charged to the quota groups.
+// The allocated part is counted by the owner pods, so the Reservation and its owners are not counted twice.
+type reservationAccountingCache struct {
+	lock         sync.Mutex
+	policy       config.ReservationAccountingPolicy
+	reservations map[types.UID]*reservationAccountingInfo
+}
+
+type reservationAccountingInfo struct {
+	quotaName string
+	// request is the resource reserved but not allocated by the owners
+	request v1.ResourceList
+}
+
+func newReservationAccountingCache() *reservationAccountingCache {
+	return &reservationAccountingCache{
+		policy:       config.ReservationAccountingPolicyNone,
+		reservations: make(map[types.UID]*reservationAccountingInfo),
+	}
+}
+
+func (c *reservationAccountingCache) countRequest() bool {
+	return c.policy == config.ReservationAccountingPolicyRequest || c.policy == config.ReservationAccountingPolicyRequestAndUsed
+}
+
+func (c *reservationAccountingCache) countUsed() bool {
+	return c.policy == config.ReservationAccountingPolicyRequestAndUsed
+}
+
+// GetReservationQuotaName returns the quota group charged for the Reservation, which is the quota label of the
+// Reservation or its template. The Reservation without quota label belongs to the default quota group.
+func GetReservationQuotaName(r *schedulingv1alpha1.Reservation) string {
+	if quotaName := r.Labels[extension.LabelQuotaName]; quotaName != "" {
+		return quotaName
+	}
+	if r.Spec.Template != nil {
+		if quotaName := r.Spec.Template.Labels[extension.LabelQuotaName]; quotaName != "" {
+			return quotaName
+		}
+	}
+	return extension.DefaultQuotaName
+}
+
+// getReservationUnallocated returns the resource reserved by the active Reservation but not allocated yet.
+func getReservationUnallocated(r *schedulingv1alpha1.Reservation) v1.ResourceList {
+	if !util.IsReservationActive(r) {
+		return nil
+	}
+	requests := getPodRequest(util.NewReservePod(r))
+	allocated := extension.TranslateResourceNameAliases(r.Status.Allocated)
+	return quotav1.Mask(quotav1.SubtractWithNonNegativeResult(requests, allocated), quotav1.ResourceNames(requests))
+}
+
+// SetReservationAccountingPolicy sets how the Reservations are charged to the quota groups, the tracked Reservations
+// are charged again under the new policy immediately.
+func (gqm *GroupQuotaManager) SetReservationAccountingPolicy(policy config.ReservationAccountingPolicy) {
+	gqm.hierarchyUpdateLock.RLock()
+	defer gqm.hierarchyUpdateLock.RUnlock()
+
+	if policy == "" {
+		policy = config.ReservationAccountingPolicyNone
+	}
+	cache := gqm.reservationAccountingCache
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	if cache.policy == policy {
+		return
+	}
+	for _, info := range cache.reservations {
+		gqm.chargeReservationNoLock(info.quotaName, quotav1.Subtract(v1.ResourceList{}, info.request))
+	}
+	cache.policy = policy
+	for _, info := range cache.reservations {
+		gqm.chargeReservationNoLock(info.quotaName, info.request)
+	}
+	klog.V(3).Infof("set reservation accounting policy %s", policy)
+}
+
+// UpdateReservationAccounting charges the unallocated resource of the Reservation to its quota group, the charge is
+// released when the Reservation is no longer active. The Reservation events handlers should call it on both the
+// add and update events.
+func (gqm *GroupQuotaManager) UpdateReservationAccounting(r *schedulingv1alpha1.Reservation) {
+	gqm.hierarchyUpdateLock.RLock()
+	defer gqm.hierarchyUpdateLock.RUnlock()
+
+	cache := gqm.reservationAccountingCache
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	quotaName := GetReservationQuotaName(r)
+	request := getReservationUnallocated(r)
+	if gqm.getQuotaInfoByNameNoLock(quotaName) == nil {
+		// the quota group may be created later, the Reservation is charged at its next update
+		request = nil
+	}
+	gqm.updateReservationAccountingNoLock(r, quotaName, request)
+}
+
+// DeleteReservationAccounting releases the charge of the Reservation.
+func (gqm *GroupQuotaManager) DeleteReservationAccounting(r *schedulingv1alpha1.Reservation) {
+	gqm.hierarchyUpdateLock.RLock()
+	defer gqm.hierarchyUpdateLock.RUnlock()
+
+	cache := gqm.reservationAccountingCache
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	gqm.updateReservationAccountingNoLock(r, "", nil)
+}
+
+// ReservationEventHandler returns the handler keeping the charge of the Reservations up to date.
+func (gqm *GroupQuotaManager) ReservationEventHandler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if r, ok := obj.(*schedulingv1alpha1.Reservation); ok {
+				gqm.UpdateReservationAccounting(r)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if r, ok := newObj.(*schedulingv1alpha1.Reservation); ok {
+				gqm.UpdateReservationAccounting(r)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			var r *schedulingv1alpha1.Reservation
+			switch t := obj.(type) {
+			case *schedulingv1alpha1.Reservation:
+				r = t
+			case cache.DeletedFinalStateUnknown:
+				r, _ = t.Obj.(*schedulingv1alpha1.Reservation)
+			}
+			if r != nil {
+				gqm.DeleteReservationAccounting(r)
+			}
+		},
+	}
+}
+
+// GetReservationAccounting returns the resource of the Reservation charged to the quota group, nil if not charged.
+func (gqm *GroupQuotaManager) GetReservationAccounting(uid types.UID) (string, v1.ResourceList) {
+	gqm.reservationAccountingCache.lock.Lock()
+	defer gqm.reservationAccountingCache.lock.Unlock()
+	info, ok := gqm.reservationAccountingCache.reservations[uid]
+	if !ok {
+		return "", nil
+	}
+	return info.quotaName, info.request.DeepCopy()
+}
+
+func (gqm *GroupQuotaManager) updateReservationAccountingNoLock(r *schedulingv1alpha1.Reservation, quotaName string, request v1.ResourceList) {
+	cache := gqm.reservationAccountingCache
+	oldInfo, exist := cache.reservations[r.UID]
+	if exist && oldInfo.quotaName == quotaName && quotav1.Equals(oldInfo.request, request) {
+		return
+	}
+	if exist {
+		gqm.chargeReservationNoLock(oldInfo.quotaName, quotav1.Subtract(v1.ResourceList{}, oldInfo.request))
+		delete(cache.reservations, r.UID)
+	}
+	if quotav1.IsZero(request) {
+		if exist {
+			klog.V(5).Infof("reservation %s is no longer charged to quota %s", r.Name, oldInfo.quotaName)
+		}
+		return
+	}
+	gqm.chargeReservationNoLock(quotaName, request)
+	cache.reservations[r.UID] = &reservationAccountingInfo{
+		quotaName: quotaName,
+		request:   request,
+	}
+	klog.V(5).Infof("reservation %s is charged to quota %s, request %v", r.Name, quotaName, util.DumpJSON(request))
+}
+
+// chargeReservationNoLock updates the Request/Used of the quota group by the delta of the Reservations under the policy.
+func (gqm *GroupQuotaManager) chargeReservationNoLock(quotaName string, delta v1.ResourceList) {
+	cache := gqm.reservationAccountingCache
+	if cache.countRequest() {
+		gqm.markGroupDeltaRequestNoLock(quotaName, delta)
+	}
+	if cache.countUsed() {
+		gqm.updateGroupDeltaUsedNoLock(quotaName, delta)
+		if quotaName == extension.SystemQuotaName || quotaName == extension.DefaultQuotaName {
+			gqm.updateClusterTotalResourceNoLock(v1.ResourceList{})
+		}
+	}
+}
+
+// addReservationAccountingNoLock adds the charge of the Reservations to the expected Request/Used of the quota groups.
+func (gqm *GroupQuotaManager) addReservationAccountingNoLock(expectedRequest, expectedUsed map[string]v1.ResourceList) {
+	cache := gqm.reservationAccountingCache
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	for _, info := range cache.reservations {
+		if cache.countRequest() {
+			expectedRequest[info.quotaName] = quotav1.Add(expectedRequest[info.quotaName], info.request)
+		}
+		if cache.countUsed() {
+			expectedUsed[info.quotaName] = quotav1.Add(expectedUsed[info.quotaName], info.request)
+		}
+	}
+}
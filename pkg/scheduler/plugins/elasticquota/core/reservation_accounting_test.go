@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+func newTestQuotaReservation(name, quotaName string, request v1.ResourceList) *schedulingv1alpha1.Reservation {
+	return &schedulingv1alpha1.Reservation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			UID:    types.UID("uid-" + name),
+			Labels: map[string]string{extension.LabelQuotaName: quotaName},
+		},
+		Spec: schedulingv1alpha1.ReservationSpec{
+			Template: &v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{
+							Name:      "main",
+							Resources: v1.ResourceRequirements{Requests: request},
+						},
+					},
+				},
+			},
+		},
+		Status: schedulingv1alpha1.ReservationStatus{
+			Phase:    schedulingv1alpha1.ReservationAvailable,
+			NodeName: "test-node-0",
+		},
+	}
+}
+
+func TestGroupQuotaManager_UpdateReservationAccounting(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 50, 500, 10, 100, true, false)
+
+	assertQuota := func(request, used v1.ResourceList) {
+		quotaInfo := gqm.GetQuotaInfoByName("1")
+		assert.True(t, quotav1.Equals(request, quotaInfo.GetRequest()), "request: %v", quotaInfo.GetRequest())
+		assert.True(t, quotav1.Equals(used, quotaInfo.GetUsed()), "used: %v", quotaInfo.GetUsed())
+	}
+
+	// not charged by default
+	r := newTestQuotaReservation("r-0", "1", createResourceList(8, 80))
+	gqm.UpdateReservationAccounting(r)
+	assertQuota(v1.ResourceList{}, v1.ResourceList{})
+
+	gqm.SetReservationAccountingPolicy(config.ReservationAccountingPolicyRequestAndUsed)
+	gqm.UpdateReservationAccounting(r)
+	assertQuota(createResourceList(8, 80), createResourceList(8, 80))
+	quotaName, request := gqm.GetReservationAccounting(r.UID)
+	assert.Equal(t, "1", quotaName)
+	assert.True(t, quotav1.Equals(createResourceList(8, 80), request))
+
+	// the allocated part is counted by the owners
+	r.Status.Allocated = createResourceList(2, 20)
+	gqm.UpdateReservationAccounting(r)
+	assertQuota(createResourceList(6, 60), createResourceList(6, 60))
+
+	// the tracked reservations are charged again under the new policy
+	gqm.SetReservationAccountingPolicy(config.ReservationAccountingPolicyRequest)
+	assertQuota(createResourceList(6, 60), createResourceList(0, 0))
+
+	// the verifier doesn't treat the charge as drift
+	assert.Empty(t, gqm.VerifyAccounting(nil))
+	assertQuota(createResourceList(6, 60), createResourceList(0, 0))
+
+	// the charge is released when the reservation is not active
+	r.Status.Phase = schedulingv1alpha1.ReservationSucceeded
+	gqm.UpdateReservationAccounting(r)
+	assertQuota(createResourceList(0, 0), createResourceList(0, 0))
+	quotaName, request = gqm.GetReservationAccounting(r.UID)
+	assert.Equal(t, "", quotaName)
+	assert.Nil(t, request)
+
+	r.Status.Phase = schedulingv1alpha1.ReservationAvailable
+	gqm.UpdateReservationAccounting(r)
+	assertQuota(createResourceList(6, 60), createResourceList(0, 0))
+	gqm.DeleteReservationAccounting(r)
+	assertQuota(createResourceList(0, 0), createResourceList(0, 0))
+}
+
+func TestGroupQuotaManager_ReservationEventHandler(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 50, 500, 10, 100, true, false)
+	gqm.SetReservationAccountingPolicy(config.ReservationAccountingPolicyRequest)
+
+	handler := gqm.ReservationEventHandler()
+	r := newTestQuotaReservation("r-0", "1", createResourceList(8, 80))
+	handler.OnAdd(r)
+	assert.True(t, quotav1.Equals(createResourceList(8, 80), gqm.GetQuotaInfoByName("1").GetRequest()))
+
+	newR := r.DeepCopy()
+	newR.Status.Allocated = createResourceList(2, 20)
+	handler.OnUpdate(r, newR)
+	assert.True(t, quotav1.Equals(createResourceList(6, 60), gqm.GetQuotaInfoByName("1").GetRequest()))
+
+	handler.OnDelete(cache.DeletedFinalStateUnknown{Key: newR.Name, Obj: newR})
+	assert.True(t, quotav1.Equals(createResourceList(0, 0), gqm.GetQuotaInfoByName("1").GetRequest()))
+	quotaName, _ := gqm.GetReservationAccounting(r.UID)
+	assert.Equal(t, "", quotaName)
+}
+
+func TestGetReservationQuotaName(t *testing.T) {
+	r := newTestQuotaReservation("r-0", "1", createResourceList(8, 80))
+	assert.Equal(t, "1", GetReservationQuotaName(r))
+
+	r.Labels = nil
+	assert.Equal(t, extension.DefaultQuotaName, GetReservationQuotaName(r))
+
+	r.Spec.Template.Labels = map[string]string{extension.LabelQuotaName: "2"}
+	assert.Equal(t, "2", GetReservationQuotaName(r))
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+)
+
+// QuotaNode is the view of a child quota group in one resource dimension, which is exposed to the
+// RuntimeCalculateStrategy to calculate the runtime quota.
+type QuotaNode interface {
+	Name() string
+	Request() int64
+	Min() int64
+	SharedWeight() int64
+	AllowLentResource() bool
+	RuntimeQuota() int64
+	SetRuntimeQuota(runtimeQuota int64)
+}
+
+// RuntimeCalculateStrategy distributes the total resource of the parent quota group (or the cluster) to the
+// child quota groups. quotaNodes contains all the child quota groups of each resource dimension, and the strategy
+// should set the runtime quota of all of them.
+type RuntimeCalculateStrategy interface {
+	Name() string
+	Calculate(totalResource v1.ResourceList, quotaNodes map[v1.ResourceName][]QuotaNode)
+}
+
+// RuntimeCalculateStrategyFactory creates a RuntimeCalculateStrategy.
+type RuntimeCalculateStrategyFactory func() RuntimeCalculateStrategy
+
+var (
+	runtimeCalculateStrategyLock      sync.RWMutex
+	runtimeCalculateStrategyFactories = map[string]RuntimeCalculateStrategyFactory{
+		string(config.RuntimeCalculateStrategyWeightedFairShare): func() RuntimeCalculateStrategy {
+			return &weightedFairShareStrategy{}
+		},
+		string(config.RuntimeCalculateStrategyPriorityStrict): func() RuntimeCalculateStrategy {
+			return &priorityStrictStrategy{}
+		},
+		string(config.RuntimeCalculateStrategyDominantResourceFairness): func() RuntimeCalculateStrategy {
+			return &drfStrategy{}
+		},
+	}
+)
+
+// RegisterRuntimeCalculateStrategy registers a custom RuntimeCalculateStrategy, which can be selected by the
+// RuntimeCalculateStrategy of the ElasticQuotaArgs. It overrides the registered strategy with the same name.
+func RegisterRuntimeCalculateStrategy(name string, factory RuntimeCalculateStrategyFactory) {
+	runtimeCalculateStrategyLock.Lock()
+	defer runtimeCalculateStrategyLock.Unlock()
+	runtimeCalculateStrategyFactories[name] = factory
+}
+
+// NewRuntimeCalculateStrategy creates the registered RuntimeCalculateStrategy by name.
+func NewRuntimeCalculateStrategy(name string) (RuntimeCalculateStrategy, error) {
+	runtimeCalculateStrategyLock.RLock()
+	defer runtimeCalculateStrategyLock.RUnlock()
+	factory, ok := runtimeCalculateStrategyFactories[name]
+	if !ok {
+		return nil, fmt.Errorf("runtime calculate strategy %s is not registered", name)
+	}
+	return factory(), nil
+}
+
+func newDefaultRuntimeCalculateStrategy() RuntimeCalculateStrategy {
+	return &weightedFairShareStrategy{}
+}
+
+// weightedFairShareStrategy calculates each resource dimension independently, see quotaTree.redistribution.
+type weightedFairShareStrategy struct{}
+
+func (s *weightedFairShareStrategy) Name() string {
+	return string(config.RuntimeCalculateStrategyWeightedFairShare)
+}
+
+func (s *weightedFairShareStrategy) Calculate(totalResource v1.ResourceList, quotaNodes map[v1.ResourceName][]QuotaNode) {
+	for resKey, nodes := range quotaNodes {
+		totalResourcePerKey := totalResource[resKey]
+		redistributeByWeight(totalResourcePerKey.Value(), nodes)
+	}
+}
+
+// priorityStrictStrategy guarantees the min of all quota groups first, then satisfies the quota groups
+// one by one in descending order of the SharedWeight.
+type priorityStrictStrategy struct{}
+
+func (s *priorityStrictStrategy) Name() string {
+	return string(config.RuntimeCalculateStrategyPriorityStrict)
+}
+
+func (s *priorityStrictStrategy) Calculate(totalResource v1.ResourceList, quotaNodes map[v1.ResourceName][]QuotaNode) {
+	for resKey, nodes := range quotaNodes {
+		totalResourcePerKey := totalResource[resKey]
+		toPartitionResource := totalResourcePerKey.Value() - assignMinRuntimeQuota(nodes)
+
+		sortedNodes := make([]QuotaNode, 0, len(nodes))
+		for _, node := range nodes {
+			if node.Request() > node.RuntimeQuota() {
+				sortedNodes = append(sortedNodes, node)
+			}
+		}
+		sort.Slice(sortedNodes, func(i, j int) bool {
+			if sortedNodes[i].SharedWeight() != sortedNodes[j].SharedWeight() {
+				return sortedNodes[i].SharedWeight() > sortedNodes[j].SharedWeight()
+			}
+			return sortedNodes[i].Name() < sortedNodes[j].Name()
+		})
+		for _, node := range sortedNodes {
+			if toPartitionResource <= 0 {
+				break
+			}
+			delta := node.Request() - node.RuntimeQuota()
+			if delta > toPartitionResource {
+				delta = toPartitionResource
+			}
+			node.SetRuntimeQuota(node.RuntimeQuota() + delta)
+			toPartitionResource -= delta
+		}
+	}
+}
+
+// assignMinRuntimeQuota sets the runtime quota of the nodes as the part of request guaranteed by min,
+// and returns the total assigned resource.
+func assignMinRuntimeQuota(nodes []QuotaNode) int64 {
+	var assigned int64
+	for _, node := range nodes {
+		if node.Request() > node.Min() {
+			node.SetRuntimeQuota(node.Min())
+		} else if node.AllowLentResource() {
+			node.SetRuntimeQuota(node.Request())
+		} else {
+			// if node is not allowLentResource, even if the request is smaller
+			// than autoScaleMin, runtimeQuota is min.
+			node.SetRuntimeQuota(node.Min())
+		}
+		assigned += node.RuntimeQuota()
+	}
+	return assigned
+}
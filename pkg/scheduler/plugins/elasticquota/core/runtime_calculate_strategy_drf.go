@@ -0,0 +1,181 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"math"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+)
+
+const (
+	// drfSearchIterations is the number of iterations of the binary search for the dominant share
+	drfSearchIterations = 64
+	// drfEpsilon tolerates the precision loss of the binary search when rounding the allocation
+	drfEpsilon = 1e-6
+)
+
+// drfStrategy distributes the resource by Dominant Resource Fairness. After the min of all quota groups
+// is guaranteed, the lendable resource is filled progressively: each quota group gets the resource in
+// proportion to its remaining request of all dimensions, and the quota group with the smallest dominant
+// share (the max share of all dimensions) is always filled first. When one dimension is exhausted, the
+// quota groups requesting it are frozen, and the others continue to be filled.
+type drfStrategy struct{}
+
+func (s *drfStrategy) Name() string {
+	return string(config.RuntimeCalculateStrategyDominantResourceFairness)
+}
+
+type drfGroup struct {
+	nodes  map[v1.ResourceName]QuotaNode
+	demand map[v1.ResourceName]int64
+}
+
+func (s *drfStrategy) Calculate(totalResource v1.ResourceList, quotaNodes map[v1.ResourceName][]QuotaNode) {
+	total := map[v1.ResourceName]int64{}
+	remaining := map[v1.ResourceName]int64{}
+	groups := map[string]*drfGroup{}
+	var resourceNames []v1.ResourceName
+	for resKey, nodes := range quotaNodes {
+		totalResourcePerKey := totalResource[resKey]
+		total[resKey] = totalResourcePerKey.Value()
+		remaining[resKey] = total[resKey] - assignMinRuntimeQuota(nodes)
+		resourceNames = append(resourceNames, resKey)
+
+		for _, node := range nodes {
+			group, ok := groups[node.Name()]
+			if !ok {
+				group = &drfGroup{
+					nodes:  map[v1.ResourceName]QuotaNode{},
+					demand: map[v1.ResourceName]int64{},
+				}
+				groups[node.Name()] = group
+			}
+			group.nodes[resKey] = node
+			// the dimension without resource can't be shared, ignore it to not block the other dimensions
+			if total[resKey] > 0 && node.Request() > node.RuntimeQuota() {
+				group.demand[resKey] = node.Request() - node.RuntimeQuota()
+			}
+		}
+	}
+	sort.Slice(resourceNames, func(i, j int) bool {
+		return resourceNames[i] < resourceNames[j]
+	})
+
+	var active []*drfGroup
+	for _, group := range groups {
+		if len(group.demand) > 0 {
+			active = append(active, group)
+		}
+	}
+
+	for len(active) > 0 {
+		share := searchDominantShare(active, resourceNames, total, remaining)
+
+		var stillActive []*drfGroup
+		saturated := map[v1.ResourceName]bool{}
+		for _, resKey := range resourceNames {
+			if float64(remaining[resKey])-sumDRFAllocation(active, resKey, share, total) < 1 {
+				saturated[resKey] = true
+			}
+		}
+		for _, group := range active {
+			fraction := group.fraction(share, total)
+			frozen := fraction >= 1
+			for resKey := range group.demand {
+				if saturated[resKey] {
+					frozen = true
+				}
+			}
+			if !frozen {
+				stillActive = append(stillActive, group)
+				continue
+			}
+			for resKey, demand := range group.demand {
+				allocated := int64(fraction*float64(demand) + drfEpsilon)
+				node := group.nodes[resKey]
+				node.SetRuntimeQuota(node.RuntimeQuota() + allocated)
+				remaining[resKey] -= allocated
+			}
+		}
+		if len(stillActive) == len(active) {
+			// nothing is frozen, which only happens when the precision is exhausted
+			break
+		}
+		active = stillActive
+	}
+}
+
+// fraction returns the fraction of the remaining request the group gets when its dominant share reaches share.
+func (g *drfGroup) fraction(share float64, total map[v1.ResourceName]int64) float64 {
+	fraction := 1.0
+	for resKey, demand := range g.demand {
+		node := g.nodes[resKey]
+		f := (share*float64(total[resKey]) - float64(node.RuntimeQuota())) / float64(demand)
+		fraction = math.Min(fraction, f)
+	}
+	return math.Max(fraction, 0)
+}
+
+func sumDRFAllocation(groups []*drfGroup, resKey v1.ResourceName, share float64, total map[v1.ResourceName]int64) float64 {
+	var sum float64
+	for _, group := range groups {
+		if demand, ok := group.demand[resKey]; ok {
+			sum += group.fraction(share, total) * float64(demand)
+		}
+	}
+	return sum
+}
+
+// searchDominantShare returns the max dominant share which all the groups can reach without exceeding
+// the remaining resource of any dimension.
+func searchDominantShare(groups []*drfGroup, resourceNames []v1.ResourceName, total, remaining map[v1.ResourceName]int64) float64 {
+	fits := func(share float64) bool {
+		for _, resKey := range resourceNames {
+			if sumDRFAllocation(groups, resKey, share, total) > float64(remaining[resKey]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// all the groups are satisfied when the share reaches hi
+	hi := 0.0
+	for _, group := range groups {
+		for resKey, demand := range group.demand {
+			node := group.nodes[resKey]
+			hi = math.Max(hi, float64(node.RuntimeQuota()+demand)/float64(total[resKey]))
+		}
+	}
+	if fits(hi) {
+		return hi
+	}
+
+	lo := 0.0
+	for i := 0; i < drfSearchIterations; i++ {
+		mid := (lo + hi) / 2
+		if fits(mid) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
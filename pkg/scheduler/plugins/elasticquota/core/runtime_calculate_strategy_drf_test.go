@@ -0,0 +1,132 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+)
+
+func TestDRFStrategy(t *testing.T) {
+	gpu := extension.GPUCore
+	cpu := corev1.ResourceCPU
+	tests := []struct {
+		name          string
+		totalResource corev1.ResourceList
+		// name -> resource -> (sharedWeight, request, min)
+		nodes map[string]map[corev1.ResourceName][3]int64
+		want  map[string]map[corev1.ResourceName]int64
+	}{
+		{
+			name: "all requests are satisfied",
+			totalResource: corev1.ResourceList{
+				cpu: *resource.NewQuantity(100, resource.DecimalSI),
+				gpu: *resource.NewQuantity(10, resource.DecimalSI),
+			},
+			nodes: map[string]map[corev1.ResourceName][3]int64{
+				"a": {cpu: {1, 10, 0}, gpu: {1, 5, 0}},
+				"b": {cpu: {1, 50, 0}, gpu: {1, 0, 0}},
+			},
+			want: map[string]map[corev1.ResourceName]int64{
+				"a": {cpu: 10, gpu: 5},
+				"b": {cpu: 50, gpu: 0},
+			},
+		},
+		{
+			name: "balance the dominant share",
+			totalResource: corev1.ResourceList{
+				cpu: *resource.NewQuantity(100, resource.DecimalSI),
+				gpu: *resource.NewQuantity(10, resource.DecimalSI),
+			},
+			nodes: map[string]map[corev1.ResourceName][3]int64{
+				// the dominant resource of a is gpu, and the dominant resource of b is cpu
+				"a": {cpu: {1, 10, 0}, gpu: {1, 10, 0}},
+				"b": {cpu: {1, 100, 0}, gpu: {1, 0, 0}},
+			},
+			want: map[string]map[corev1.ResourceName]int64{
+				"a": {cpu: 9, gpu: 9},
+				"b": {cpu: 90, gpu: 0},
+			},
+		},
+		{
+			name: "continue to fill the groups not blocked by the exhausted resource",
+			totalResource: corev1.ResourceList{
+				cpu: *resource.NewQuantity(100, resource.DecimalSI),
+				gpu: *resource.NewQuantity(10, resource.DecimalSI),
+			},
+			nodes: map[string]map[corev1.ResourceName][3]int64{
+				"a": {cpu: {1, 10, 0}, gpu: {1, 20, 0}},
+				"b": {cpu: {1, 10, 0}, gpu: {1, 20, 0}},
+				"c": {cpu: {1, 100, 0}, gpu: {1, 0, 0}},
+			},
+			want: map[string]map[corev1.ResourceName]int64{
+				"a": {cpu: 2, gpu: 5},
+				"b": {cpu: 2, gpu: 5},
+				"c": {cpu: 96, gpu: 0},
+			},
+		},
+		{
+			name: "min is guaranteed",
+			totalResource: corev1.ResourceList{
+				cpu: *resource.NewQuantity(100, resource.DecimalSI),
+				gpu: *resource.NewQuantity(10, resource.DecimalSI),
+			},
+			nodes: map[string]map[corev1.ResourceName][3]int64{
+				"a": {cpu: {1, 100, 60}, gpu: {1, 0, 0}},
+				"b": {cpu: {1, 100, 0}, gpu: {1, 0, 0}},
+			},
+			want: map[string]map[corev1.ResourceName]int64{
+				"a": {cpu: 60, gpu: 0},
+				"b": {cpu: 40, gpu: 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := NewRuntimeCalculateStrategy(string(config.RuntimeCalculateStrategyDominantResourceFairness))
+			assert.NoError(t, err)
+
+			qtw := NewRuntimeQuotaCalculator("testTreeName")
+			qtw.SetStrategy(strategy)
+			qtw.UpdateResourceKeys(map[corev1.ResourceName]struct{}{cpu: {}, gpu: {}})
+			for name, resources := range tt.nodes {
+				for resKey, v := range resources {
+					qtw.quotaTree[resKey].insert(name, v[0], v[1], v[2], true)
+				}
+			}
+			qtw.totalResource = tt.totalResource
+			qtw.calculateRuntimeNoLock()
+
+			got := map[string]map[corev1.ResourceName]int64{}
+			for resKey, tree := range qtw.quotaTree {
+				for name, node := range tree.quotaNodes {
+					if got[name] == nil {
+						got[name] = map[corev1.ResourceName]int64{}
+					}
+					got[name][resKey] = node.runtimeQuota
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
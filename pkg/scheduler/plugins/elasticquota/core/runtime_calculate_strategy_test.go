@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+)
+
+func newTestRuntimeQuotaCalculator(strategy RuntimeCalculateStrategy) *RuntimeQuotaCalculator {
+	qtw := NewRuntimeQuotaCalculator("testTreeName")
+	qtw.SetStrategy(strategy)
+	cpu := corev1.ResourceCPU
+	qtw.UpdateResourceKeys(map[corev1.ResourceName]struct{}{cpu: {}})
+	qtw.quotaTree[cpu].insert("node1", 40, 5, 10, true)
+	qtw.quotaTree[cpu].insert("node2", 60, 20, 15, true)
+	qtw.quotaTree[cpu].insert("node3", 50, 40, 20, true)
+	qtw.quotaTree[cpu].insert("node4", 80, 70, 15, true)
+	qtw.totalResource = corev1.ResourceList{
+		cpu: *resource.NewQuantity(100, resource.DecimalSI),
+	}
+	return qtw
+}
+
+func getTestRuntimeQuota(qtw *RuntimeQuotaCalculator) map[string]int64 {
+	runtime := map[string]int64{}
+	for name, node := range qtw.quotaTree[corev1.ResourceCPU].quotaNodes {
+		runtime[name] = node.runtimeQuota
+	}
+	return runtime
+}
+
+func TestPriorityStrictStrategy(t *testing.T) {
+	strategy, err := NewRuntimeCalculateStrategy(string(config.RuntimeCalculateStrategyPriorityStrict))
+	assert.NoError(t, err)
+	qtw := newTestRuntimeQuotaCalculator(strategy)
+	qtw.calculateRuntimeNoLock()
+	// the min is guaranteed, the rest is assigned to node4 with the largest shared weight
+	assert.Equal(t, map[string]int64{
+		"node1": 5,
+		"node2": 15,
+		"node3": 20,
+		"node4": 60,
+	}, getTestRuntimeQuota(qtw))
+}
+
+type testMinOnlyStrategy struct{}
+
+func (s *testMinOnlyStrategy) Name() string {
+	return "MinOnly"
+}
+
+func (s *testMinOnlyStrategy) Calculate(totalResource corev1.ResourceList, quotaNodes map[corev1.ResourceName][]QuotaNode) {
+	for _, nodes := range quotaNodes {
+		assignMinRuntimeQuota(nodes)
+	}
+}
+
+func TestRegisterRuntimeCalculateStrategy(t *testing.T) {
+	_, err := NewRuntimeCalculateStrategy("MinOnly")
+	assert.Error(t, err)
+
+	RegisterRuntimeCalculateStrategy("MinOnly", func() RuntimeCalculateStrategy {
+		return &testMinOnlyStrategy{}
+	})
+	strategy, err := NewRuntimeCalculateStrategy("MinOnly")
+	assert.NoError(t, err)
+	assert.Equal(t, "MinOnly", strategy.Name())
+
+	qtw := newTestRuntimeQuotaCalculator(strategy)
+	qtw.calculateRuntimeNoLock()
+	assert.Equal(t, map[string]int64{
+		"node1": 5,
+		"node2": 15,
+		"node3": 20,
+		"node4": 15,
+	}, getTestRuntimeQuota(qtw))
+}
+
+func TestGroupQuotaManager_SetRuntimeCalculateStrategy(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	assert.Error(t, gqm.SetRuntimeCalculateStrategy("not-exist"))
+
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	AddQuotaToManager(t, gqm, "2", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(100, 100*GigaByte))
+	gqm.UpdateGroupDeltaRequest("2", createResourceList(100, 100*GigaByte))
+
+	// weighted fair share by default
+	assert.Equal(t, int64(50), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(50), cpuValue(gqm.RefreshRuntime("2")))
+
+	// shared weight of quota 2 is larger, it gets all the rest
+	quota := CreateQuota("2", extension.RootQuotaName, 200, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	assert.NoError(t, gqm.UpdateQuota(quota, false))
+	assert.NoError(t, gqm.SetRuntimeCalculateStrategy(string(config.RuntimeCalculateStrategyPriorityStrict)))
+	assert.Equal(t, int64(20), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(80), cpuValue(gqm.RefreshRuntime("2")))
+}
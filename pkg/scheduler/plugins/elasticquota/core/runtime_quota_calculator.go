@@ -32,6 +32,9 @@ type quotaNode struct {
 	min               int64
 	runtimeQuota      int64
 	allowLentResource bool
+	tier              int32
+	// minCountedRequest is the part of the request counted toward the min, negative means all the request
+	minCountedRequest int64
 }
 
 func NewQuotaNode(quotaName string, sharedWeight, request, min int64, allowLentResource bool) *quotaNode {
@@ -42,9 +45,45 @@ func NewQuotaNode(quotaName string, sharedWeight, request, min int64, allowLentR
 		min:               min,
 		runtimeQuota:      0,
 		allowLentResource: allowLentResource,
+		minCountedRequest: -1,
 	}
 }
 
+var _ QuotaNode = &quotaNode{}
+
+func (qn *quotaNode) Name() string {
+	return qn.quotaName
+}
+
+func (qn *quotaNode) Request() int64 {
+	return qn.request
+}
+
+// Min returns the min of the quota group, which is limited by the request counted toward the min
+// if the quota group allows lending its resource.
+func (qn *quotaNode) Min() int64 {
+	if qn.allowLentResource && qn.minCountedRequest >= 0 && qn.minCountedRequest < qn.min {
+		return qn.minCountedRequest
+	}
+	return qn.min
+}
+
+func (qn *quotaNode) SharedWeight() int64 {
+	return qn.sharedWeight
+}
+
+func (qn *quotaNode) AllowLentResource() bool {
+	return qn.allowLentResource
+}
+
+func (qn *quotaNode) RuntimeQuota() int64 {
+	return qn.runtimeQuota
+}
+
+func (qn *quotaNode) SetRuntimeQuota(runtimeQuota int64) {
+	qn.runtimeQuota = runtimeQuota
+}
+
 // quotaTree abstract the struct to calculate each resource dimension's runtime Quota independently
 type quotaTree struct {
 	quotaNodes map[string]*quotaNode
@@ -92,6 +131,20 @@ func (qt *quotaTree) updateRequest(groupName string, request int64) {
 	}
 }
 
+func (qt *quotaTree) updateTier(groupName string, tier int32) {
+	if nodeValue, exist := qt.quotaNodes[groupName]; exist {
+		nodeValue.tier = tier
+	}
+}
+
+func (qt *quotaTree) updateMinCountedRequest(groupName string, minCountedRequest int64) bool {
+	if nodeValue, exist := qt.quotaNodes[groupName]; exist && nodeValue.minCountedRequest != minCountedRequest {
+		nodeValue.minCountedRequest = minCountedRequest
+		return true
+	}
+	return false
+}
+
 func (qt *quotaTree) find(groupName string) (bool, *quotaNode) {
 	if nodeValue, exist := qt.quotaNodes[groupName]; exist {
 		return exist, nodeValue
@@ -103,56 +156,68 @@ func (qt *quotaTree) find(groupName string) (bool, *quotaNode) {
 //redistribution distribute the parentQuotaGroup's (or totalResource of the cluster (except the
 // DefaultQuotaGroup/SystemQuotaGroup) resource to the childQuotaGroup's according to the PR's rule
 func (qt *quotaTree) redistribution(totalResource int64) {
+	redistributeByWeight(totalResource, qt.getQuotaNodes())
+}
+
+func (qt *quotaTree) getQuotaNodes() []QuotaNode {
+	nodes := make([]QuotaNode, 0, len(qt.quotaNodes))
+	for _, node := range qt.quotaNodes {
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+func redistributeByWeight(totalResource int64, nodes []QuotaNode) {
 	toPartitionResource := totalResource
 	totalSharedWeight := int64(0)
-	needAdjustQuotaNodes := make([]*quotaNode, 0)
-	for _, node := range qt.quotaNodes {
-		if node.request > node.min {
+	needAdjustQuotaNodes := make([]QuotaNode, 0)
+	for _, node := range nodes {
+		if node.Request() > node.Min() {
 			// if a node's request > autoScaleMin, the node needs adjustQuota
 			// the node's runtime is autoScaleMin
 			needAdjustQuotaNodes = append(needAdjustQuotaNodes, node)
-			totalSharedWeight += node.sharedWeight
-			node.runtimeQuota = node.min
+			totalSharedWeight += node.SharedWeight()
+			node.SetRuntimeQuota(node.Min())
 		} else {
-			if node.allowLentResource {
-				node.runtimeQuota = node.request
+			if node.AllowLentResource() {
+				node.SetRuntimeQuota(node.Request())
 			} else {
 				// if node is not allowLentResource, even if the request is smaller
 				// than autoScaleMin, runtimeQuota is request.
-				node.runtimeQuota = node.min
+				node.SetRuntimeQuota(node.Min())
 			}
 		}
-		toPartitionResource -= node.runtimeQuota
+		toPartitionResource -= node.RuntimeQuota()
 	}
 
 	if toPartitionResource > 0 {
-		qt.iterationForRedistribution(toPartitionResource, totalSharedWeight, needAdjustQuotaNodes)
+		iterationForRedistribution(toPartitionResource, totalSharedWeight, needAdjustQuotaNodes)
 	}
 }
 
-func (qt *quotaTree) iterationForRedistribution(totalRes, totalSharedWeight int64, nodes []*quotaNode) {
+func iterationForRedistribution(totalRes, totalSharedWeight int64, nodes []QuotaNode) {
 	if totalSharedWeight <= 0 {
 		// if totalSharedWeight is not larger than 0, no need to iterate anymore.
 		return
 	}
 
-	needAdjustQuotaNodes := make([]*quotaNode, 0)
+	needAdjustQuotaNodes := make([]QuotaNode, 0)
 	toPartitionResource, needAdjustTotalSharedWeight := int64(0), int64(0)
 	for _, node := range nodes {
-		runtimeQuotaDelta := int64(float64(node.sharedWeight)*float64(totalRes)/float64(totalSharedWeight) + 0.5)
-		node.runtimeQuota += runtimeQuotaDelta
-		if node.runtimeQuota < node.request {
+		runtimeQuotaDelta := int64(float64(node.SharedWeight())*float64(totalRes)/float64(totalSharedWeight) + 0.5)
+		node.SetRuntimeQuota(node.RuntimeQuota() + runtimeQuotaDelta)
+		if node.RuntimeQuota() < node.Request() {
 			// if node's runtime is still less than request, the node still need to iterate.
 			needAdjustQuotaNodes = append(needAdjustQuotaNodes, node)
-			needAdjustTotalSharedWeight += node.sharedWeight
+			needAdjustTotalSharedWeight += node.SharedWeight()
 		} else {
-			toPartitionResource += node.runtimeQuota - node.request
-			node.runtimeQuota = node.request
+			toPartitionResource += node.RuntimeQuota() - node.Request()
+			node.SetRuntimeQuota(node.Request())
 		}
 	}
 
 	if toPartitionResource > 0 && len(needAdjustQuotaNodes) > 0 {
-		qt.iterationForRedistribution(toPartitionResource, needAdjustTotalSharedWeight, needAdjustQuotaNodes)
+		iterationForRedistribution(toPartitionResource, needAdjustTotalSharedWeight, needAdjustQuotaNodes)
 	}
 }
 
@@ -168,7 +233,8 @@ type RuntimeQuotaCalculator struct {
 	quotaTree            quotaTreeMapType             // has all resource dimension's information
 	totalResource        v1.ResourceList              // the parentQuotaInfo's runtimeQuota or the clusterResource
 	lock                 sync.Mutex
-	treeName             string // the same as the parentQuotaInfo's Name
+	treeName             string                   // the same as the parentQuotaInfo's Name
+	strategy             RuntimeCalculateStrategy // distributes the totalResource to the childQuotaInfos
 }
 
 func NewRuntimeQuotaCalculator(treeName string) *RuntimeQuotaCalculator {
@@ -179,9 +245,23 @@ func NewRuntimeQuotaCalculator(treeName string) *RuntimeQuotaCalculator {
 		quotaTree:            make(quotaTreeMapType),
 		totalResource:        v1.ResourceList{},
 		treeName:             treeName,
+		strategy:             newDefaultRuntimeCalculateStrategy(),
 	}
 }
 
+// SetStrategy changes the strategy to calculate the runtimeQuota, then increase globalRuntimeVersion
+func (qtw *RuntimeQuotaCalculator) SetStrategy(strategy RuntimeCalculateStrategy) {
+	if strategy == nil {
+		return
+	}
+
+	qtw.lock.Lock()
+	defer qtw.lock.Unlock()
+
+	qtw.strategy = strategy
+	qtw.globalRuntimeVersion++
+}
+
 func (qtw *RuntimeQuotaCalculator) UpdateResourceKeys(resourceKeys map[v1.ResourceName]struct{}) {
 	newResourceKey := make(map[v1.ResourceName]struct{})
 	for resKey := range resourceKeys {
@@ -255,6 +335,7 @@ func (qtw *RuntimeQuotaCalculator) UpdateOneGroupMaxQuota(quotaInfo *QuotaInfo)
 			qtw.quotaTree[resKey].insert(quotaInfo.Name, sharedWeightPerKey.Value(), reqLimitPerKey.Value(),
 				autoScaleMinQuotaPerKey.Value(), quotaInfo.AllowLentResource)
 		}
+		qtw.quotaTree[resKey].updateTier(quotaInfo.Name, quotaInfo.PriorityTier)
 
 		// update reqLimitPerKey
 		localReqLimit[resKey] = reqLimitPerKey
@@ -267,24 +348,31 @@ func (qtw *RuntimeQuotaCalculator) UpdateOneGroupMaxQuota(quotaInfo *QuotaInfo)
 	}
 }
 
-// UpdateOneGroupMinQuota the autoScaleMin change, then increase globalRuntimeVersion
+// UpdateOneGroupMinQuota the autoScaleMin change, then increase globalRuntimeVersion.
+// The limited request is updated too, as the borrow limit is relative to the autoScaleMin.
 func (qtw *RuntimeQuotaCalculator) UpdateOneGroupMinQuota(quotaInfo *QuotaInfo) {
 	qtw.lock.Lock()
 	defer qtw.lock.Unlock()
 
+	localReqLimit := qtw.getGroupRequestLimitNoLock(quotaInfo.Name)
 	reqLimit := quotaInfo.getLimitRequestNoLock()
 	minQuota := quotaInfo.CalculateInfo.AutoScaleMin.DeepCopy()
 	for resKey := range qtw.resourceKeys {
 		// update/insert quotaNode
 		newMinQuotaPerKey := *minQuota.Name(resKey, resource.DecimalSI)
+		reqLimitPerKey := *reqLimit.Name(resKey, resource.DecimalSI)
 		if exist, _ := qtw.quotaTree[resKey].find(quotaInfo.Name); exist {
 			qtw.quotaTree[resKey].updateMin(quotaInfo.Name, newMinQuotaPerKey.Value())
+			qtw.quotaTree[resKey].updateRequest(quotaInfo.Name, reqLimitPerKey.Value())
 		} else {
 			sharedWeightPerKey := *quotaInfo.CalculateInfo.SharedWeight.Name(resKey, resource.DecimalSI)
-			reqLimitPerKey := *reqLimit.Name(resKey, resource.DecimalSI)
 			qtw.quotaTree[resKey].insert(quotaInfo.Name, sharedWeightPerKey.Value(), reqLimitPerKey.Value(),
 				newMinQuotaPerKey.Value(), quotaInfo.AllowLentResource)
 		}
+		qtw.quotaTree[resKey].updateTier(quotaInfo.Name, quotaInfo.PriorityTier)
+
+		// update reqLimitPerKey
+		localReqLimit[resKey] = reqLimitPerKey
 	}
 
 	qtw.globalRuntimeVersion++
@@ -312,6 +400,7 @@ func (qtw *RuntimeQuotaCalculator) UpdateOneGroupSharedWeight(quotaInfo *QuotaIn
 			qtw.quotaTree[resKey].insert(quotaInfo.Name, newSharedWeightPerKey.Value(), reqLimitPerKey.Value(),
 				minQuotaPerKey.Value(), quotaInfo.AllowLentResource)
 		}
+		qtw.quotaTree[resKey].updateTier(quotaInfo.Name, quotaInfo.PriorityTier)
 	}
 
 	qtw.globalRuntimeVersion++
@@ -359,6 +448,7 @@ func (qtw *RuntimeQuotaCalculator) UpdateOneGroupRequest(quotaInfo *QuotaInfo) {
 			qtw.quotaTree[resKey].insert(quotaInfo.Name, sharedWeightPerKey.Value(), reqLimitPerKey.Value(),
 				minQuotaPerKey.Value(), quotaInfo.AllowLentResource)
 		}
+		qtw.quotaTree[resKey].updateTier(quotaInfo.Name, quotaInfo.PriorityTier)
 
 		// update reqLimitPerKey
 		reqLimit[resKey] = reqLimitPerKey
@@ -371,6 +461,28 @@ func (qtw *RuntimeQuotaCalculator) UpdateOneGroupRequest(quotaInfo *QuotaInfo) {
 	}
 }
 
+// UpdateOneGroupMinCountedRequest updates the part of the childGroup's request counted toward its min, nil means
+// all the request is counted. Increase globalRuntimeVersion if changed.
+func (qtw *RuntimeQuotaCalculator) UpdateOneGroupMinCountedRequest(quotaInfo *QuotaInfo, counted v1.ResourceList) {
+	qtw.lock.Lock()
+	defer qtw.lock.Unlock()
+
+	changed := false
+	for resKey := range qtw.resourceKeys {
+		minCountedRequest := int64(-1)
+		if counted != nil {
+			countedPerKey := counted[resKey]
+			minCountedRequest = countedPerKey.Value()
+		}
+		if qtw.quotaTree[resKey].updateMinCountedRequest(quotaInfo.Name, minCountedRequest) {
+			changed = true
+		}
+	}
+	if changed {
+		qtw.globalRuntimeVersion++
+	}
+}
+
 // SetClusterTotalResource increase/decrease the totalResource of the RuntimeQuotaCalculator, the resource that can be "lent to" will
 // change, then increase globalRuntimeVersion
 func (qtw *RuntimeQuotaCalculator) SetClusterTotalResource(full v1.ResourceList) {
@@ -424,12 +536,19 @@ func (qtw *RuntimeQuotaCalculator) GetVersion() int64 {
 	return qtw.globalRuntimeVersion
 }
 
+// calculateRuntimeNoLock distributes the totalResource to the quota groups by the strategy, the quota groups in
+// different priority tiers are distributed tier by tier with the same strategy, see calculateTieredRuntimeNoLock.
 func (qtw *RuntimeQuotaCalculator) calculateRuntimeNoLock() {
 	//lock outside
+	if tiers := qtw.getPriorityTiersNoLock(); len(tiers) > 1 {
+		qtw.calculateTieredRuntimeNoLock(tiers)
+		return
+	}
+	quotaNodes := make(map[v1.ResourceName][]QuotaNode, len(qtw.resourceKeys))
 	for resKey := range qtw.resourceKeys {
-		totalResourcePerKey := *qtw.totalResource.Name(resKey, resource.DecimalSI)
-		qtw.quotaTree[resKey].redistribution(totalResourcePerKey.Value())
+		quotaNodes[resKey] = qtw.quotaTree[resKey].getQuotaNodes()
 	}
+	qtw.strategy.Calculate(qtw.totalResource, quotaNodes)
 }
 
 func (qtw *RuntimeQuotaCalculator) logQuotaInfoNoLock(verb string, quotaInfo *QuotaInfo) {
@@ -99,6 +99,20 @@ func createResourceList(cpu int64, memory int64) corev1.ResourceList {
 	return resourceList
 }
 
+// cpuValue, memoryValue and resourceValue read the quantity from the resource list returned by the methods,
+// which is not addressable.
+func cpuValue(resourceList corev1.ResourceList) int64 {
+	return resourceList.Cpu().Value()
+}
+
+func memoryValue(resourceList corev1.ResourceList) int64 {
+	return resourceList.Memory().Value()
+}
+
+func resourceValue(resourceList corev1.ResourceList, name corev1.ResourceName) int64 {
+	return resourceList.Name(name, resource.DecimalSI).Value()
+}
+
 func createElasticQuota() *v1alpha1.ElasticQuota {
 	eQ := &v1alpha1.ElasticQuota{
 		Spec: v1alpha1.ElasticQuotaSpec{
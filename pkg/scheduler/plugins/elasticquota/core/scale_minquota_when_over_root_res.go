@@ -41,6 +41,9 @@ type ScaleMinQuotaManager struct {
 	// totalRes, just return the originalMinQuota.
 	originalMinQuotaMap         map[string]v1.ResourceList
 	quotaEnableMinQuotaScaleMap map[string]bool
+	// minQuotaOversellRatioMap key: quotaName, val: the ratio of its children's sum minQuota to its totalRes
+	// allowed before scaling, the children's minQuota are scaled to ratio times of the totalRes if exceeded.
+	minQuotaOversellRatioMap map[string]float64
 }
 
 func NewScaleMinQuotaManager() *ScaleMinQuotaManager {
@@ -49,6 +52,7 @@ func NewScaleMinQuotaManager() *ScaleMinQuotaManager {
 		enableScaleSubsSumMinQuotaMap:  make(map[string]v1.ResourceList),
 		disableScaleSubsSumMinQuotaMap: make(map[string]v1.ResourceList),
 		quotaEnableMinQuotaScaleMap:    make(map[string]bool),
+		minQuotaOversellRatioMap:       make(map[string]float64),
 	}
 	return info
 }
@@ -113,6 +117,26 @@ func (s *ScaleMinQuotaManager) Delete(parQuotaName, subQuotaName string) {
 		subQuotaName, s.originalMinQuotaMap[subQuotaName], s.quotaEnableMinQuotaScaleMap[subQuotaName])
 }
 
+// UpdateMinQuotaOversellRatio updates the oversell ratio of the parQuotaName's children's minQuota,
+// the ratio not larger than 0 means no oversell.
+func (s *ScaleMinQuotaManager) UpdateMinQuotaOversellRatio(parQuotaName string, ratio float64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if ratio <= 0 || ratio == 1 {
+		delete(s.minQuotaOversellRatioMap, parQuotaName)
+		return
+	}
+	s.minQuotaOversellRatioMap[parQuotaName] = ratio
+}
+
+func (s *ScaleMinQuotaManager) getMinQuotaOversellRatioNoLock(parQuotaName string) float64 {
+	if ratio, ok := s.minQuotaOversellRatioMap[parQuotaName]; ok {
+		return ratio
+	}
+	return 1
+}
+
 func (s *ScaleMinQuotaManager) GetScaledMinQuota(newTotalRes v1.ResourceList, parQuotaName, subQuotaName string) (bool, v1.ResourceList) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
@@ -128,10 +152,16 @@ func (s *ScaleMinQuotaManager) GetScaledMinQuota(newTotalRes v1.ResourceList, pa
 		return false, nil
 	}
 
-	// get the dimensions where children's minQuota sum is larger than newTotalRes
+	// the children's minQuota are allowed to be oversold up to ratio times of the totalRes
+	if ratio := s.getMinQuotaOversellRatioNoLock(parQuotaName); ratio != 1 {
+		newTotalRes = scaleResourceList(newTotalRes, ratio)
+	}
+
+	// get the dimensions where children's minQuota sum is larger than newTotalRes, the dimension missing in the
+	// newTotalRes is regarded as zero, e.g. the extended resources reported by the device plugins later.
 	needScaleDimensions := make([]v1.ResourceName, 0)
-	for resName := range newTotalRes {
-		sum := quotav1.Add(s.disableScaleSubsSumMinQuotaMap[parQuotaName], s.enableScaleSubsSumMinQuotaMap[parQuotaName])
+	sum := quotav1.Add(s.disableScaleSubsSumMinQuotaMap[parQuotaName], s.enableScaleSubsSumMinQuotaMap[parQuotaName])
+	for resName := range quotav1.Add(newTotalRes, sum) {
 		if newTotalRes.Name(resName, resource.DecimalSI).Cmp(*sum.Name(resName, resource.DecimalSI)) == -1 {
 			needScaleDimensions = append(needScaleDimensions, resName)
 		}
@@ -170,3 +200,11 @@ func (s *ScaleMinQuotaManager) GetScaledMinQuota(newTotalRes v1.ResourceList, pa
 	}
 	return true, newMinQuota
 }
+
+func scaleResourceList(resList v1.ResourceList, ratio float64) v1.ResourceList {
+	scaled := make(v1.ResourceList, len(resList))
+	for resName, quantity := range resList {
+		scaled[resName] = *resource.NewQuantity(int64(float64(quantity.Value())*ratio+0.5), quantity.Format)
+	}
+	return scaled
+}
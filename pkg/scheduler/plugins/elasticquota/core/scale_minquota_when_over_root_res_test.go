@@ -109,6 +109,31 @@ func TestScaleMinQuotaWhenOverRootResInfo_GetScaledMinQuota(t *testing.T) {
 	}
 }
 
+func TestScaleMinQuotaWhenOverRootResInfo_GetScaledMinQuotaWithOversellRatio(t *testing.T) {
+	info := NewScaleMinQuotaManager()
+	info.Update("100", "1", createResourceList(50, 50), false)
+	info.Update("100", "2", createResourceList(50, 50), true)
+	info.Update("100", "3", createResourceList(50, 50), true)
+	info.UpdateMinQuotaOversellRatio("100", 1.5)
+
+	// the children's sum minQuota is not larger than 1.5 times of the totalRes
+	result, newMinQuota := info.GetScaledMinQuota(createResourceList(100, 100), "100", "2")
+	if result != true || !quotav1.Equals(newMinQuota, createResourceList(50, 50)) {
+		t.Error("error")
+	}
+	// scale to 1.5 times of the totalRes
+	result, newMinQuota = info.GetScaledMinQuota(createResourceList(80, 80), "100", "2")
+	if result != true || !quotav1.Equals(newMinQuota, createResourceList(35, 35)) {
+		t.Error("error")
+	}
+
+	info.UpdateMinQuotaOversellRatio("100", 0)
+	result, newMinQuota = info.GetScaledMinQuota(createResourceList(100, 100), "100", "2")
+	if result != true || !quotav1.Equals(newMinQuota, createResourceList(25, 25)) {
+		t.Error("error")
+	}
+}
+
 func TestScaleMinQuotaWhenOverRootResInfo_UpdateAndDelete(t *testing.T) {
 	info := NewScaleMinQuotaManager()
 	if len(info.enableScaleSubsSumMinQuotaMap) != 0 ||
@@ -0,0 +1,211 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/wait"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+// SharedWeightProvider provides the SharedWeight of the quota groups from an external source, e.g. a service
+// which adjusts the weights by the business priorities. The quota group or the resource missing in the result uses
+// the SharedWeight of the quota's annotation.
+type SharedWeightProvider interface {
+	Name() string
+	GetSharedWeights() (map[string]v1.ResourceList, error)
+}
+
+type httpSharedWeightProvider struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPSharedWeightProvider creates a SharedWeightProvider which gets the SharedWeight of the quota groups from
+// the url, the response should be a JSON object from the quota name to the SharedWeight, e.g.
+//
+//	{"quota-a": {"cpu": "100", "memory": "200Gi"}}
+func NewHTTPSharedWeightProvider(url string, timeout time.Duration) SharedWeightProvider {
+	return &httpSharedWeightProvider{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *httpSharedWeightProvider) Name() string {
+	return "HTTP"
+}
+
+func (p *httpSharedWeightProvider) GetSharedWeights() (map[string]v1.ResourceList, error) {
+	resp, err := p.client.Get(p.url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, p.url)
+	}
+	weights := map[string]v1.ResourceList{}
+	if err := json.NewDecoder(resp.Body).Decode(&weights); err != nil {
+		return nil, err
+	}
+	return weights, nil
+}
+
+// SharedWeightSyncer periodically gets the SharedWeight from the SharedWeightProvider and updates the GroupQuotaManager.
+// The last weights are cached for cacheTTL when the provider fails, and the SharedWeight of the annotations is used
+// after that. The SharedWeight moves towards the target by transitionRatio of the difference in each sync, so that the
+// runtime quota of the quota groups changes smoothly, transitionRatio not in (0, 1) means changing at once.
+type SharedWeightSyncer struct {
+	gqm             *GroupQuotaManager
+	provider        SharedWeightProvider
+	interval        time.Duration
+	cacheTTL        time.Duration
+	transitionRatio float64
+
+	lock            sync.Mutex
+	cachedWeights   map[string]v1.ResourceList
+	lastSuccessTime time.Time
+}
+
+func NewSharedWeightSyncer(gqm *GroupQuotaManager, provider SharedWeightProvider, interval, cacheTTL time.Duration, transitionRatio float64) *SharedWeightSyncer {
+	return &SharedWeightSyncer{
+		gqm:             gqm,
+		provider:        provider,
+		interval:        interval,
+		cacheTTL:        cacheTTL,
+		transitionRatio: transitionRatio,
+	}
+}
+
+// Start runs the syncer until stopCh is closed.
+func (s *SharedWeightSyncer) Start(stopCh <-chan struct{}) {
+	klog.Infof("start SharedWeight syncer, provider: %v, interval: %v", s.provider.Name(), s.interval)
+	go wait.Until(func() {
+		s.Sync()
+	}, s.interval, stopCh)
+}
+
+// Sync gets the SharedWeight from the provider once and updates the GroupQuotaManager, it returns whether the
+// SharedWeight of all the quota groups has reached the target.
+func (s *SharedWeightSyncer) Sync() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	weights, err := s.provider.GetSharedWeights()
+	if err != nil {
+		klog.Errorf("failed to get SharedWeight from provider %v, err: %v", s.provider.Name(), err)
+		if time.Since(s.lastSuccessTime) > s.cacheTTL {
+			s.cachedWeights = nil
+		}
+	} else {
+		s.cachedWeights = weights
+		s.lastSuccessTime = time.Now()
+	}
+	return s.gqm.UpdateSharedWeights(s.cachedWeights, s.transitionRatio)
+}
+
+// UpdateSharedWeights moves the SharedWeight of the quota groups towards the target weights by transitionRatio of
+// the difference, the quota group or the resource missing in the weights falls back to the SharedWeight of the quota's
+// annotation. It returns whether the SharedWeight of all the quota groups has reached the target.
+func (gqm *GroupQuotaManager) UpdateSharedWeights(weights map[string]v1.ResourceList, transitionRatio float64) bool {
+	gqm.hierarchyUpdateLock.Lock()
+	defer gqm.hierarchyUpdateLock.Unlock()
+
+	for quotaName := range gqm.sharedWeightOverrides {
+		if _, ok := gqm.quotaInfoMap[quotaName]; !ok {
+			delete(gqm.sharedWeightOverrides, quotaName)
+		}
+	}
+
+	converged := true
+	for quotaName, quotaInfo := range gqm.quotaInfoMap {
+		if quotaName == extension.SystemQuotaName || quotaName == extension.DefaultQuotaName {
+			continue
+		}
+		quotaInfo.lock.Lock()
+		target := quotaInfo.CalculateInfo.OriginalSharedWeight.DeepCopy()
+		for resourceName, weight := range weights[quotaName] {
+			target[resourceName] = weight
+		}
+		current := gqm.getBaseSharedWeightNoLock(quotaInfo)
+		next := transitSharedWeight(current, target, transitionRatio)
+		if !isSharedWeightEqual(next, target) {
+			converged = false
+		}
+		if isSharedWeightEqual(next, quotaInfo.CalculateInfo.OriginalSharedWeight) {
+			delete(gqm.sharedWeightOverrides, quotaName)
+		} else {
+			gqm.sharedWeightOverrides[quotaName] = next
+		}
+		if !isSharedWeightEqual(next, current) {
+			klog.V(4).Infof("update SharedWeight of quota %v from %v to %v, target: %v", quotaName, current, next, target)
+		}
+		gqm.refreshEffectiveSharedWeightNoLock(quotaInfo)
+		quotaInfo.lock.Unlock()
+	}
+	return converged
+}
+
+// refreshEffectiveSharedWeightNoLock updates the SharedWeight of the quota group in the runtimeQuotaCalculator if the
+// effective SharedWeight changes. quotaInfo.lock should be held.
+func (gqm *GroupQuotaManager) refreshEffectiveSharedWeightNoLock(quotaInfo *QuotaInfo) {
+	sharedWeight := gqm.getEffectiveSharedWeightNoLock(quotaInfo)
+	if isSharedWeightEqual(sharedWeight, quotaInfo.CalculateInfo.SharedWeight) {
+		return
+	}
+	quotaInfo.setSharedWeightNoLock(sharedWeight)
+	if runtimeQuotaCalculator := gqm.getParentRuntimeQuotaCalculatorNoLock(quotaInfo); runtimeQuotaCalculator != nil {
+		runtimeQuotaCalculator.UpdateOneGroupSharedWeight(quotaInfo)
+	}
+	gqm.publishQuotaSnapshotNoLock(quotaInfo)
+}
+
+// transitSharedWeight moves current towards target by ratio of the difference, the difference not larger than 1
+// is eliminated at once to converge.
+func transitSharedWeight(current, target v1.ResourceList, ratio float64) v1.ResourceList {
+	if ratio <= 0 || ratio >= 1 {
+		return target.DeepCopy()
+	}
+	next := v1.ResourceList{}
+	for resourceName := range quotav1.Add(current, target) {
+		currentValue, targetValue := current.Name(resourceName, resource.DecimalSI).Value(), target.Name(resourceName, resource.DecimalSI).Value()
+		value := targetValue
+		if diff := targetValue - currentValue; diff > 1 || diff < -1 {
+			value = currentValue + int64(math.Round(float64(diff)*ratio))
+		}
+		if _, ok := target[resourceName]; ok || value != 0 {
+			next[resourceName] = *resource.NewQuantity(value, resource.DecimalSI)
+		}
+	}
+	return next
+}
+
+func isSharedWeightEqual(a, b v1.ResourceList) bool {
+	return quotav1.IsZero(quotav1.Subtract(a, b))
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+type fakeSharedWeightProvider struct {
+	weights map[string]v1.ResourceList
+	err     error
+}
+
+func (p *fakeSharedWeightProvider) Name() string {
+	return "Fake"
+}
+
+func (p *fakeSharedWeightProvider) GetSharedWeights() (map[string]v1.ResourceList, error) {
+	return p.weights, p.err
+}
+
+func TestSharedWeightSyncer_Sync(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	AddQuotaToManager(t, gqm, "2", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(100, 100*GigaByte))
+	gqm.UpdateGroupDeltaRequest("2", createResourceList(100, 100*GigaByte))
+	assert.Equal(t, int64(50), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(50), cpuValue(gqm.RefreshRuntime("2")))
+
+	provider := &fakeSharedWeightProvider{
+		weights: map[string]v1.ResourceList{
+			"2": {v1.ResourceCPU: *resource.NewQuantity(300, resource.DecimalSI)},
+		},
+	}
+	syncer := NewSharedWeightSyncer(gqm, provider, time.Second, time.Hour, 0.5)
+
+	// the weight of quota 2 moves from 100 to 300 smoothly
+	assert.False(t, syncer.Sync())
+	assert.Equal(t, int64(200), gqm.GetQuotaInfoByName("2").CalculateInfo.SharedWeight.Cpu().Value())
+	assert.False(t, syncer.Sync())
+	assert.Equal(t, int64(250), gqm.GetQuotaInfoByName("2").CalculateInfo.SharedWeight.Cpu().Value())
+	for !syncer.Sync() {
+	}
+	quotaInfo := gqm.GetQuotaInfoByName("2")
+	assert.Equal(t, int64(300), quotaInfo.CalculateInfo.SharedWeight.Cpu().Value())
+	// the memory missing in the result uses the annotation
+	assert.Equal(t, int64(1000*GigaByte), quotaInfo.CalculateInfo.SharedWeight.Memory().Value())
+	// the min 20 is guaranteed, the rest 60 is shared by 1:3
+	assert.Equal(t, int64(35), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(65), cpuValue(gqm.RefreshRuntime("2")))
+
+	// the override is kept when the quota is updated
+	quota := CreateQuota("2", extension.RootQuotaName, 100, 1000*GigaByte, 30, 200*GigaByte, true, false)
+	assert.NoError(t, gqm.UpdateQuota(quota, false))
+	assert.Equal(t, int64(300), gqm.GetQuotaInfoByName("2").CalculateInfo.SharedWeight.Cpu().Value())
+
+	// the cached weights are used when the provider fails
+	provider.weights, provider.err = nil, fmt.Errorf("unavailable")
+	assert.True(t, syncer.Sync())
+	assert.Equal(t, int64(300), gqm.GetQuotaInfoByName("2").CalculateInfo.SharedWeight.Cpu().Value())
+
+	// fall back to the annotation when the cache expires
+	syncer.cacheTTL = 0
+	syncer.transitionRatio = 1
+	assert.True(t, syncer.Sync())
+	quotaInfo = gqm.GetQuotaInfoByName("2")
+	assert.True(t, quotav1.Equals(quotaInfo.CalculateInfo.OriginalSharedWeight, quotaInfo.CalculateInfo.SharedWeight))
+	assert.Empty(t, gqm.sharedWeightOverrides)
+}
+
+func TestTransitSharedWeight(t *testing.T) {
+	current := createResourceList(100, 100)
+	target := createResourceList(300, 101)
+	assert.True(t, quotav1.Equals(createResourceList(200, 101), transitSharedWeight(current, target, 0.5)))
+	assert.True(t, quotav1.Equals(target, transitSharedWeight(current, target, 0)))
+	assert.True(t, quotav1.Equals(target, transitSharedWeight(current, target, 1)))
+}
+
+func TestHTTPSharedWeightProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"quota-a": {"cpu": "100", "memory": "200"}}`))
+	}))
+	defer server.Close()
+
+	provider := NewHTTPSharedWeightProvider(server.URL, time.Second)
+	weights, err := provider.GetSharedWeights()
+	assert.NoError(t, err)
+	assert.True(t, quotav1.Equals(createResourceList(100, 200), weights["quota-a"]))
+
+	server.Close()
+	_, err = provider.GetSharedWeights()
+	assert.Error(t, err)
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"math"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+// usageDecay tracks the integral of the shared resource used by each quota group, i.e. the used exceeding the min,
+// and the integral decays by half every halfLife. The SharedWeight of a quota group is lowered by penaltyFactor times
+// the ratio of its decayed usage to the decayed integral of the whole resource of its parent, so that the quota group
+// which has consumed lots of shared resource recently gets less for a while, and all the quota groups get a fair share
+// in the long term.
+type usageDecay struct {
+	halfLife      time.Duration
+	penaltyFactor float64
+
+	lastUpdateTime time.Time
+	// decayedUsage is the decayed integral of the shared resource used by the quota groups, in value*seconds
+	decayedUsage map[string]map[v1.ResourceName]float64
+	// multipliers is the ratio of the effective SharedWeight to the base SharedWeight
+	multipliers map[string]map[v1.ResourceName]float64
+}
+
+func newUsageDecay(halfLife time.Duration, penaltyFactor float64) *usageDecay {
+	return &usageDecay{
+		halfLife:      halfLife,
+		penaltyFactor: penaltyFactor,
+		decayedUsage:  map[string]map[v1.ResourceName]float64{},
+		multipliers:   map[string]map[v1.ResourceName]float64{},
+	}
+}
+
+// decaySharedWeight returns the SharedWeight lowered by the multipliers of the quota group, the lowered SharedWeight
+// is at least 1 to keep the quota group competing for the shared resource.
+func (d *usageDecay) decaySharedWeight(quotaName string, sharedWeight v1.ResourceList) v1.ResourceList {
+	multipliers := d.multipliers[quotaName]
+	result := make(v1.ResourceList, len(sharedWeight))
+	for resourceName, quantity := range sharedWeight {
+		multiplier, ok := multipliers[resourceName]
+		if !ok || quantity.Value() <= 0 {
+			result[resourceName] = quantity.DeepCopy()
+			continue
+		}
+		value := int64(math.Round(float64(quantity.Value()) * multiplier))
+		if value < 1 {
+			value = 1
+		}
+		result[resourceName] = *resource.NewQuantity(value, resource.DecimalSI)
+	}
+	return result
+}
+
+// SetUsageDecay enables the usage decay of the SharedWeight, halfLife is the half-life of the usage integral and
+// penaltyFactor in (0, 1) is the max ratio the SharedWeight is lowered. It's disabled if halfLife or penaltyFactor
+// is not positive.
+func (gqm *GroupQuotaManager) SetUsageDecay(halfLife time.Duration, penaltyFactor float64) {
+	gqm.hierarchyUpdateLock.Lock()
+	defer gqm.hierarchyUpdateLock.Unlock()
+
+	if halfLife <= 0 || penaltyFactor <= 0 {
+		gqm.usageDecay = nil
+	} else {
+		if penaltyFactor >= 1 {
+			penaltyFactor = 0.99
+		}
+		gqm.usageDecay = newUsageDecay(halfLife, penaltyFactor)
+	}
+	for quotaName, quotaInfo := range gqm.quotaInfoMap {
+		if quotaName == extension.SystemQuotaName || quotaName == extension.DefaultQuotaName {
+			continue
+		}
+		quotaInfo.lock.Lock()
+		gqm.refreshEffectiveSharedWeightNoLock(quotaInfo)
+		quotaInfo.lock.Unlock()
+	}
+	klog.V(3).Infof("Set UsageDecay, halfLife:%v, penaltyFactor:%v", halfLife, penaltyFactor)
+}
+
+// RunUsageDecay updates the usage decay every interval until stopCh is closed.
+func (gqm *GroupQuotaManager) RunUsageDecay(interval time.Duration, stopCh <-chan struct{}) {
+	go wait.Until(func() {
+		gqm.UpdateUsageDecay(time.Now())
+	}, interval, stopCh)
+}
+
+// UpdateUsageDecay accumulates the shared resource used by the quota groups since the last update, and updates
+// the effective SharedWeight of the quota groups by their decayed usage.
+func (gqm *GroupQuotaManager) UpdateUsageDecay(now time.Time) {
+	gqm.hierarchyUpdateLock.Lock()
+	defer gqm.hierarchyUpdateLock.Unlock()
+
+	d := gqm.usageDecay
+	if d == nil {
+		return
+	}
+
+	var elapsed float64
+	if !d.lastUpdateTime.IsZero() {
+		elapsed = now.Sub(d.lastUpdateTime).Seconds()
+	}
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	d.lastUpdateTime = now
+	decay := math.Pow(0.5, elapsed/d.halfLife.Seconds())
+
+	for quotaName := range d.decayedUsage {
+		if _, ok := gqm.quotaInfoMap[quotaName]; !ok {
+			delete(d.decayedUsage, quotaName)
+		}
+	}
+	for quotaName, quotaInfo := range gqm.quotaInfoMap {
+		if quotaName == extension.SystemQuotaName || quotaName == extension.DefaultQuotaName {
+			continue
+		}
+		usage, ok := d.decayedUsage[quotaName]
+		if !ok {
+			usage = map[v1.ResourceName]float64{}
+			d.decayedUsage[quotaName] = usage
+		}
+		for resourceName := range usage {
+			usage[resourceName] *= decay
+		}
+		quotaInfo.lock.Lock()
+		for resourceName, used := range quotaInfo.CalculateInfo.Used {
+			minQuota := quotaInfo.CalculateInfo.AutoScaleMin.Name(resourceName, resource.DecimalSI)
+			if shared := float64(used.MilliValue()-minQuota.MilliValue()) / 1000; shared > 0 {
+				usage[resourceName] += shared * elapsed
+			}
+		}
+		quotaInfo.lock.Unlock()
+	}
+
+	// the decayed integral of a constant resource converges to resource * halfLife / ln2
+	integralFactor := d.halfLife.Seconds() / math.Ln2
+	d.multipliers = map[string]map[v1.ResourceName]float64{}
+	for quotaName, usage := range d.decayedUsage {
+		quotaInfo := gqm.quotaInfoMap[quotaName]
+		capacity := gqm.totalResource
+		if parentInfo, ok := gqm.quotaInfoMap[quotaInfo.ParentName]; ok {
+			capacity = parentInfo.GetMax()
+		}
+		multipliers := map[v1.ResourceName]float64{}
+		for resourceName, value := range usage {
+			total := float64(capacity.Name(resourceName, resource.DecimalSI).MilliValue()) / 1000 * integralFactor
+			if total <= 0 {
+				continue
+			}
+			multipliers[resourceName] = 1 - d.penaltyFactor*math.Min(value/total, 1)
+		}
+		d.multipliers[quotaName] = multipliers
+
+		quotaInfo.lock.Lock()
+		gqm.refreshEffectiveSharedWeightNoLock(quotaInfo)
+		quotaInfo.lock.Unlock()
+	}
+}
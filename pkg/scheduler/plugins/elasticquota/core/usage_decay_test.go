@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestGroupQuotaManager_UpdateUsageDecay(t *testing.T) {
+	gqm := NewGroupQuotaManager4Test()
+	gqm.UpdateClusterTotalResource(createResourceList(100, 1000*GigaByte))
+	AddQuotaToManager(t, gqm, "1", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	AddQuotaToManager(t, gqm, "2", extension.RootQuotaName, 100, 1000*GigaByte, 20, 200*GigaByte, true, false)
+	gqm.UpdateGroupDeltaRequest("1", createResourceList(100, 100*GigaByte))
+	gqm.UpdateGroupDeltaRequest("2", createResourceList(100, 100*GigaByte))
+	// quota 1 borrows 60 cpu from quota 2
+	gqm.UpdateGroupDeltaUsed("1", createResourceList(80, 0))
+	gqm.UpdateGroupDeltaUsed("2", createResourceList(20, 0))
+
+	gqm.SetUsageDecay(10*time.Minute, 0.9)
+	now := time.Now()
+	gqm.UpdateUsageDecay(now)
+	assert.Equal(t, int64(50), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(50), cpuValue(gqm.RefreshRuntime("2")))
+
+	// the SharedWeight of quota 1 is lowered by the recent usage
+	now = now.Add(10 * time.Minute)
+	gqm.UpdateUsageDecay(now)
+	sharedWeight1 := gqm.GetQuotaInfoByName("1").CalculateInfo.SharedWeight.Cpu().Value()
+	assert.Less(t, sharedWeight1, int64(100))
+	assert.Greater(t, sharedWeight1, int64(1))
+	assert.Equal(t, int64(100), gqm.GetQuotaInfoByName("2").CalculateInfo.SharedWeight.Cpu().Value())
+	// the memory is not borrowed
+	assert.Equal(t, int64(1000*GigaByte), gqm.GetQuotaInfoByName("1").CalculateInfo.SharedWeight.Memory().Value())
+	runtime1, runtime2 := cpuValue(gqm.RefreshRuntime("1")), cpuValue(gqm.RefreshRuntime("2"))
+	assert.Less(t, runtime1, runtime2)
+	assert.Equal(t, int64(100), runtime1+runtime2)
+
+	// the penalty fades after quota 1 stops borrowing
+	gqm.UpdateGroupDeltaUsed("1", createResourceList(-60, 0))
+	now = now.Add(10 * time.Hour)
+	gqm.UpdateUsageDecay(now)
+	assert.Equal(t, int64(100), gqm.GetQuotaInfoByName("1").CalculateInfo.SharedWeight.Cpu().Value())
+	assert.Equal(t, int64(50), cpuValue(gqm.RefreshRuntime("1")))
+	assert.Equal(t, int64(50), cpuValue(gqm.RefreshRuntime("2")))
+
+	// disable the usage decay
+	now = now.Add(10 * time.Minute)
+	gqm.UpdateGroupDeltaUsed("1", createResourceList(60, 0))
+	gqm.UpdateUsageDecay(now)
+	gqm.SetUsageDecay(0, 0)
+	gqm.UpdateUsageDecay(now.Add(10 * time.Minute))
+	assert.Equal(t, int64(100), gqm.GetQuotaInfoByName("1").CalculateInfo.SharedWeight.Cpu().Value())
+}
@@ -0,0 +1,383 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquota
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/client-go/tools/cache"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	pgclientset "sigs.k8s.io/scheduler-plugins/pkg/generated/clientset/versioned"
+	pgformers "sigs.k8s.io/scheduler-plugins/pkg/generated/informers/externalversions"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config/validation"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/services"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/elasticquota/core"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	// Name is the name of the plugin used in the plugin registry and configurations.
+	Name = "ElasticQuota"
+)
+
+// Plugin admits the pods by the runtime quota of their quota groups, and keeps the GroupQuotaManager consistent
+// with the ElasticQuotas, the nodes and the pods.
+type Plugin struct {
+	handle                  framework.Handle
+	groupQuotaManager       *core.GroupQuotaManager
+	quotaDeletionController *core.QuotaDeletionController
+	quotaResyncer           *core.QuotaResyncer
+	gangQuotaAdmitter       *core.GangQuotaAdmitter
+	priorityAging           *core.PriorityAging
+	nodeInfoLister          framework.NodeInfoLister
+	stopCh                  <-chan struct{}
+}
+
+var (
+	_ framework.QueueSortPlugin   = &Plugin{}
+	_ framework.PreFilterPlugin   = &Plugin{}
+	_ framework.FilterPlugin      = &Plugin{}
+	_ framework.ReservePlugin     = &Plugin{}
+	_ services.APIServiceProvider = &Plugin{}
+	_ frameworkext.LeaderResyncer = &Plugin{}
+	_ frameworkext.GangAdmitter   = &Plugin{}
+)
+
+func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	args, ok := obj.(*config.ElasticQuotaArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type ElasticQuotaArgs, got %T", obj)
+	}
+	if err := validation.ValidateElasticQuotaArgs(args); err != nil {
+		return nil, err
+	}
+	groupQuotaManager, err := core.NewGroupQuotaManagerWithArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	quotaClient, ok := handle.(pgclientset.Interface)
+	if !ok {
+		kubeConfig := *handle.KubeConfig()
+		kubeConfig.ContentType = runtime.ContentTypeJSON
+		kubeConfig.AcceptContentTypes = runtime.ContentTypeJSON
+		quotaClient = pgclientset.NewForConfigOrDie(&kubeConfig)
+	}
+	quotaInformerFactory := pgformers.NewSharedInformerFactory(quotaClient, 0)
+	quotaInformer := quotaInformerFactory.Scheduling().V1alpha1().ElasticQuotas()
+
+	podInformer := handle.SharedInformerFactory().Core().V1().Pods()
+	plugin := &Plugin{
+		handle:            handle,
+		groupQuotaManager: groupQuotaManager,
+		quotaDeletionController: core.NewQuotaDeletionController(groupQuotaManager, quotaClient, quotaInformer.Lister(),
+			handle.EventRecorder(), args.QuotaDeletionSyncPeriod.Duration),
+		quotaResyncer:     core.NewQuotaResyncer(groupQuotaManager, quotaInformer.Lister(), podInformer.Lister()),
+		gangQuotaAdmitter: core.NewGangQuotaAdmitter(groupQuotaManager),
+		nodeInfoLister:    handle.SnapshotSharedLister().NodeInfos(),
+		stopCh:            getStopCh(handle),
+	}
+	if args.PriorityAging != nil {
+		plugin.priorityAging = core.NewPriorityAging(groupQuotaManager, args.PriorityAging)
+	}
+	var podQuotaAnnotationWriter *core.PodQuotaAnnotationWriter
+	if args.PodQuotaAnnotationSyncPeriod != nil && args.PodQuotaAnnotationSyncPeriod.Duration > 0 {
+		// registered before the pods are accounted, so the pods assumed later are all annotated
+		podQuotaAnnotationWriter = core.NewPodQuotaAnnotationWriter(groupQuotaManager, handle.ClientSet(),
+			args.PodQuotaAnnotationSyncPeriod.Duration)
+	}
+	quotaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    plugin.OnQuotaAdd,
+		UpdateFunc: plugin.OnQuotaUpdate,
+		DeleteFunc: plugin.OnQuotaDelete,
+	})
+	handle.SharedInformerFactory().Core().V1().Nodes().Informer().AddEventHandler(groupQuotaManager.NodeEventHandler())
+	podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    plugin.OnPodAdd,
+		UpdateFunc: plugin.OnPodUpdate,
+		DeleteFunc: plugin.OnPodDelete,
+	})
+	extendedHandle, _ := handle.(frameworkext.ExtendedHandle)
+	if extendedHandle != nil {
+		reservationInformer := extendedHandle.KoordinatorSharedInformerFactory().Scheduling().V1alpha1().Reservations().Informer()
+		reservationInformer.AddEventHandler(groupQuotaManager.ReservationEventHandler())
+	}
+
+	stopCh := plugin.stopCh
+	quotaInformerFactory.Start(stopCh)
+	handle.SharedInformerFactory().Start(stopCh)
+	quotaInformerFactory.WaitForCacheSync(stopCh)
+	handle.SharedInformerFactory().WaitForCacheSync(stopCh)
+	if extendedHandle != nil {
+		extendedHandle.KoordinatorSharedInformerFactory().Start(stopCh)
+		extendedHandle.KoordinatorSharedInformerFactory().WaitForCacheSync(stopCh)
+	}
+
+	groupQuotaManager.RunBatchRecalculation(stopCh)
+	if args.UsageDecay != nil {
+		groupQuotaManager.RunUsageDecay(args.UsageDecay.UpdateInterval.Duration, stopCh)
+	}
+	groupQuotaManager.RunQuotaReservationCleanup(args.QuotaReservationCleanupInterval.Duration, stopCh)
+	groupQuotaManager.RunQuotaMetricsRecorder(args.QuotaMetricsRecordPeriod.Duration, stopCh)
+	groupQuotaManager.RunQuotaRuntimeHistoryRecorder(args.QuotaRuntimeHistoryInterval.Duration,
+		args.QuotaRuntimeHistoryRetention.Duration, stopCh)
+	if podQuotaAnnotationWriter != nil {
+		podQuotaAnnotationWriter.Start(stopCh)
+	}
+	plugin.quotaDeletionController.Start(stopCh)
+	core.NewQuotaAccountingVerifier(groupQuotaManager, podInformer.Lister(), args.AccountingVerificationPeriod.Duration).Start(stopCh)
+	core.NewQuotaStatusWriter(groupQuotaManager, quotaClient, quotaInformer.Lister(), args.QuotaStatusSyncPeriod.Duration).Start(stopCh)
+	if provider := args.SharedWeightProvider; provider != nil {
+		core.NewSharedWeightSyncer(groupQuotaManager, core.NewHTTPSharedWeightProvider(provider.URL, provider.Timeout.Duration),
+			provider.SyncPeriod.Duration, provider.CacheTTL.Duration, float64(provider.TransitionPercent)/100).Start(stopCh)
+	}
+
+	return plugin, nil
+}
+
+// getStopCh returns the channel closed when the scheduler exits, the background goroutines of the plugin built
+// without the ExtendedHandle run until the process exits.
+func getStopCh(handle framework.Handle) <-chan struct{} {
+	if extendedHandle, ok := handle.(frameworkext.ExtendedHandle); ok && extendedHandle.StopCh() != nil {
+		return extendedHandle.StopCh()
+	}
+	return wait.NeverStop
+}
+
+func (p *Plugin) Name() string {
+	return Name
+}
+
+// Less orders the pods by the priority raised by the PriorityAging for the time they are rejected by the quota
+// groups, then by the time they are added to the queue. It works as the PrioritySort if the PriorityAging is
+// disabled, so the plugin is enabled at the queueSort only if the PriorityAging is wanted.
+func (p *Plugin) Less(podInfo1, podInfo2 *framework.QueuedPodInfo) bool {
+	now := time.Now()
+	priority1 := p.getEffectivePriority(podInfo1.Pod, now)
+	priority2 := p.getEffectivePriority(podInfo2.Pod, now)
+	if priority1 != priority2 {
+		return priority1 > priority2
+	}
+	return podInfo1.Timestamp.Before(podInfo2.Timestamp)
+}
+
+func (p *Plugin) getEffectivePriority(pod *corev1.Pod, now time.Time) int32 {
+	if p.priorityAging == nil {
+		return corev1helpers.PodPriority(pod)
+	}
+	return p.priorityAging.GetEffectivePriority(core.GetPodQuotaName(pod), pod, now)
+}
+
+// PreFilter rejects the pod if the used of its quota group plus the request of the pod exceeds the max or the
+// runtime of the quota group, the pod may be admitted later when the runtime grows. The pod of the quota group
+// scoped to a node pool is rejected too if it requests more than the total resource of the node pool.
+func (p *Plugin) PreFilter(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod) *framework.Status {
+	quotaName := core.GetPodQuotaName(pod)
+	podRequest := extension.TranslateResourceNameAliases(util.GetPodRequest(pod))
+	if poolTotal, ok := p.groupQuotaManager.GetNodePoolTotalResource(quotaName); ok {
+		if fit, exceeded := quotav1.LessThanOrEqual(quotav1.Mask(podRequest, quotav1.ResourceNames(poolTotal)), poolTotal); !fit {
+			return framework.NewStatus(framework.Unschedulable,
+				fmt.Sprintf("Insufficient node pool of quota %s, exceeded: %v", quotaName, exceeded))
+		}
+	}
+	admission, err := p.groupQuotaManager.CheckQuotaAdmission(quotaName, podRequest)
+	if err != nil {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, err.Error())
+	}
+	if !admission.Admitted {
+		core.RecordAdmissionRejections(admission)
+		if p.priorityAging != nil {
+			p.priorityAging.OnQuotaRejected(quotaName, pod, time.Now())
+		}
+		return framework.NewStatus(framework.Unschedulable,
+			fmt.Sprintf("Insufficient quota %s, %s", quotaName, strings.Join(admission.Reasons, ", ")))
+	}
+	return nil
+}
+
+func (p *Plugin) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+// Filter keeps the pod of the quota group scoped to a node pool on the nodes of the node pool, since only their
+// resource is distributed to the quota group.
+func (p *Plugin) Filter(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	node := nodeInfo.Node()
+	if node == nil {
+		return framework.NewStatus(framework.Error, "node not found")
+	}
+	quotaName := core.GetPodQuotaName(pod)
+	if !p.groupQuotaManager.IsNodeInQuotaPool(quotaName, node) {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable,
+			fmt.Sprintf("node(s) not in the node pool of quota %s", quotaName))
+	}
+	return nil
+}
+
+// Reserve counts the pod in the used of its quota group before it's bound, so the pods scheduled later see it.
+func (p *Plugin) Reserve(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) *framework.Status {
+	if err := p.groupQuotaManager.UpdatePodAccountingState(core.GetPodQuotaName(pod), pod, core.PodAccountingStateAssumed); err != nil {
+		return framework.NewStatus(framework.Error, err.Error())
+	}
+	return nil
+}
+
+// Unreserve moves the pod back to Pending, the pod event handlers correct it if the pod is gone meanwhile.
+func (p *Plugin) Unreserve(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeName string) {
+	if err := p.groupQuotaManager.UpdatePodAccountingState(core.GetPodQuotaName(pod), pod, core.PodAccountingStatePending); err != nil {
+		klog.ErrorS(err, "Failed to unreserve quota of pod", "pod", klog.KObj(pod))
+	}
+}
+
+// Resync rebuilds the quota groups and the pod accounting from the informer caches when the scheduler starts
+// leading, it's given up once the scheduler is stopped.
+func (p *Plugin) Resync(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-p.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return p.quotaResyncer.Resync(ctx)
+}
+
+// AdmitGangGroup reserves the quota of all the children of the gangGroup jointly, it's called by the Coscheduling
+// through the frameworkext.GangAdmitters before any child is assumed.
+func (p *Plugin) AdmitGangGroup(children map[string][]*corev1.Pod, ttl time.Duration) error {
+	return p.gangQuotaAdmitter.AdmitGangGroup(children, ttl)
+}
+
+func (p *Plugin) ReleaseGangGroup(gangIds []string) {
+	p.gangQuotaAdmitter.ReleaseGangGroup(gangIds)
+}
+
+// RegisterEndpoints exposes the endpoints of the GroupQuotaManager, and the quota-aware capacity report counting
+// the pods fit on the nodes of the scheduler's snapshot, e.g. /quotaCapacity?cpu=4&memory=8Gi.
+func (p *Plugin) RegisterEndpoints(group *gin.RouterGroup) {
+	p.groupQuotaManager.RegisterEndpoints(group)
+	group.GET("/quotaCapacity", core.QuotaCapacityHandler(p.groupQuotaManager, p.nodeInfoLister))
+}
+
+func (p *Plugin) OnQuotaAdd(obj interface{}) {
+	quota, ok := obj.(*v1alpha1.ElasticQuota)
+	if !ok {
+		return
+	}
+	if err := p.groupQuotaManager.UpdateQuota(quota, false); err != nil {
+		klog.ErrorS(err, "Failed to add ElasticQuota", "quota", klog.KObj(quota))
+	}
+	// the new quota gets the finalizer and the quota being deleted is drained without waiting for the interval
+	if !hasQuotaFinalizer(quota) || quota.DeletionTimestamp != nil {
+		p.triggerQuotaDeletion()
+	}
+}
+
+func (p *Plugin) OnQuotaUpdate(oldObj, newObj interface{}) {
+	p.OnQuotaAdd(newObj)
+}
+
+func (p *Plugin) OnQuotaDelete(obj interface{}) {
+	var quota *v1alpha1.ElasticQuota
+	switch t := obj.(type) {
+	case *v1alpha1.ElasticQuota:
+		quota = t
+	case cache.DeletedFinalStateUnknown:
+		quota, _ = t.Obj.(*v1alpha1.ElasticQuota)
+	}
+	if quota == nil {
+		return
+	}
+	if err := p.groupQuotaManager.UpdateQuota(quota, true); err != nil {
+		klog.ErrorS(err, "Failed to delete ElasticQuota", "quota", klog.KObj(quota))
+	}
+	// the child quotas moved away from the deleted quota may finish their own deletion now
+	p.triggerQuotaDeletion()
+}
+
+func (p *Plugin) triggerQuotaDeletion() {
+	if p.quotaDeletionController != nil {
+		p.quotaDeletionController.Trigger()
+	}
+}
+
+func hasQuotaFinalizer(quota *v1alpha1.ElasticQuota) bool {
+	for _, finalizer := range quota.Finalizers {
+		if finalizer == extension.ElasticQuotaFinalizer {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Plugin) OnPodAdd(obj interface{}) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	// the pod assumed by Reserve keeps Assumed until it's bound
+	assumed := p.groupQuotaManager.GetPodAccountingState(pod.UID) == core.PodAccountingStateAssumed
+	state := p.groupQuotaManager.ResolvePodAccountingState(pod, assumed)
+	if err := p.groupQuotaManager.UpdatePodAccountingState(core.GetPodQuotaName(pod), pod, state); err != nil {
+		klog.ErrorS(err, "Failed to update quota accounting of pod", "pod", klog.KObj(pod))
+	}
+}
+
+func (p *Plugin) OnPodUpdate(oldObj, newObj interface{}) {
+	p.OnPodAdd(newObj)
+	if pod, ok := newObj.(*corev1.Pod); ok {
+		p.groupQuotaManager.ResizePodAccounting(pod)
+	}
+}
+
+func (p *Plugin) OnPodDelete(obj interface{}) {
+	var pod *corev1.Pod
+	switch t := obj.(type) {
+	case *corev1.Pod:
+		pod = t
+	case cache.DeletedFinalStateUnknown:
+		pod, _ = t.Obj.(*corev1.Pod)
+	}
+	if pod == nil {
+		return
+	}
+	quotaName := core.GetPodQuotaName(pod)
+	if err := p.groupQuotaManager.UpdatePodAccountingState(quotaName, pod, core.PodAccountingStateGone); err != nil {
+		klog.ErrorS(err, "Failed to remove quota accounting of pod", "pod", klog.KObj(pod))
+	}
+	// the deletion of the quota may be waiting for its last pods to drain
+	if p.groupQuotaManager.IsQuotaDeleting(quotaName) {
+		p.triggerQuotaDeletion()
+	}
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquota
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/elasticquota/core"
+)
+
+func newTestPlugin(t *testing.T) *Plugin {
+	groupQuotaManager, err := core.NewGroupQuotaManagerWithArgs(&config.ElasticQuotaArgs{
+		DefaultQuotaGroupMax: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("96")},
+		SystemQuotaGroupMax:  corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("96")},
+	})
+	assert.NoError(t, err)
+	groupQuotaManager.OnNodeAdd(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")},
+		},
+	})
+	return &Plugin{groupQuotaManager: groupQuotaManager}
+}
+
+func newTestQuota(name string, max, min string) *v1alpha1.ElasticQuota {
+	return &v1alpha1.ElasticQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{extension.LabelQuotaParent: extension.RootQuotaName},
+		},
+		Spec: v1alpha1.ElasticQuotaSpec{
+			Max: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(max)},
+			Min: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(min)},
+		},
+	}
+}
+
+func newTestPod(name, quotaName, cpu string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			UID:       types.UID(name),
+			Labels:    map[string]string{extension.LabelQuotaName: quotaName},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestPlugin_PreFilterAndReserve(t *testing.T) {
+	p := newTestPlugin(t)
+	p.OnQuotaAdd(newTestQuota("test-quota", "4", "4"))
+
+	pod1 := newTestPod("pod-1", "test-quota", "3")
+	pod2 := newTestPod("pod-2", "test-quota", "3")
+	p.OnPodAdd(pod1)
+	p.OnPodAdd(pod2)
+
+	status := p.PreFilter(context.TODO(), framework.NewCycleState(), pod1)
+	assert.True(t, status.IsSuccess())
+	status = p.Reserve(context.TODO(), framework.NewCycleState(), pod1, "node-1")
+	assert.True(t, status.IsSuccess())
+	assert.Equal(t, core.PodAccountingStateAssumed, p.groupQuotaManager.GetPodAccountingState(pod1.UID))
+
+	// the update before binding keeps the pod assumed
+	p.OnPodUpdate(pod1, pod1)
+	assert.Equal(t, core.PodAccountingStateAssumed, p.groupQuotaManager.GetPodAccountingState(pod1.UID))
+
+	status = p.PreFilter(context.TODO(), framework.NewCycleState(), pod2)
+	assert.Equal(t, framework.Unschedulable, status.Code())
+
+	p.Unreserve(context.TODO(), framework.NewCycleState(), pod1, "node-1")
+	assert.Equal(t, core.PodAccountingStatePending, p.groupQuotaManager.GetPodAccountingState(pod1.UID))
+	status = p.PreFilter(context.TODO(), framework.NewCycleState(), pod2)
+	assert.True(t, status.IsSuccess())
+
+	p.OnPodDelete(pod1)
+	p.OnPodDelete(pod2)
+	assert.Equal(t, core.PodAccountingStateGone, p.groupQuotaManager.GetPodAccountingState(pod1.UID))
+	assert.Equal(t, 0, p.groupQuotaManager.GetQuotaPodCount("test-quota"))
+	p.OnQuotaDelete(newTestQuota("test-quota", "4", "4"))
+	assert.Nil(t, p.groupQuotaManager.GetQuotaInfoByName("test-quota"))
+}
+
+func TestPlugin_LessWithPriorityAging(t *testing.T) {
+	p := newTestPlugin(t)
+	p.OnQuotaAdd(newTestQuota("test-quota", "4", "4"))
+	now := time.Now()
+	pod1 := newTestPod("pod-1", "test-quota", "3")
+	pod2 := newTestPod("pod-2", "test-quota", "3")
+	pod3 := newTestPod("pod-3", "test-quota", "1")
+	pod3.Spec.Priority = pointer.Int32(50)
+	podInfo2 := &framework.QueuedPodInfo{PodInfo: framework.NewPodInfo(pod2), Timestamp: now}
+	podInfo3 := &framework.QueuedPodInfo{PodInfo: framework.NewPodInfo(pod3), Timestamp: now.Add(time.Second)}
+
+	// the same as the PrioritySort without the PriorityAging
+	assert.True(t, p.Less(podInfo3, podInfo2))
+	assert.False(t, p.Less(podInfo2, podInfo3))
+
+	p.priorityAging = core.NewPriorityAging(p.groupQuotaManager, &config.PriorityAgingArgs{
+		Interval: metav1.Duration{Duration: time.Millisecond},
+		Step:     10,
+		MaxBoost: 100,
+	})
+	p.OnPodAdd(pod1)
+	p.OnPodAdd(pod2)
+	p.OnPodAdd(pod3)
+	assert.True(t, p.Reserve(context.TODO(), framework.NewCycleState(), pod1, "node-1").IsSuccess())
+	assert.Equal(t, framework.Unschedulable, p.PreFilter(context.TODO(), framework.NewCycleState(), pod2).Code())
+
+	// the pod rejected by the quota group is raised over the pod of higher priority after waiting
+	time.Sleep(20 * time.Millisecond)
+	assert.True(t, p.Less(podInfo2, podInfo3))
+	assert.False(t, p.Less(podInfo3, podInfo2))
+
+	// the pod stops aging once it's assumed
+	p.Unreserve(context.TODO(), framework.NewCycleState(), pod1, "node-1")
+	assert.True(t, p.Reserve(context.TODO(), framework.NewCycleState(), pod2, "node-1").IsSuccess())
+	assert.True(t, p.Less(podInfo3, podInfo2))
+}
+
+type fakeNodeInfoLister struct {
+	framework.NodeInfoLister
+	nodeInfos []*framework.NodeInfo
+}
+
+func (f *fakeNodeInfoLister) List() ([]*framework.NodeInfo, error) {
+	return f.nodeInfos, nil
+}
+
+func TestPlugin_QuotaCapacityEndpoint(t *testing.T) {
+	p := newTestPlugin(t)
+	p.OnQuotaAdd(newTestQuota("test-quota", "4", "4"))
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:  resource.MustParse("8"),
+				corev1.ResourcePods: resource.MustParse("100"),
+			},
+		},
+	})
+	p.nodeInfoLister = &fakeNodeInfoLister{nodeInfos: []*framework.NodeInfo{nodeInfo}}
+	engine := gin.New()
+	p.RegisterEndpoints(engine.Group("/"))
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/quotaCapacity?cpu=1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var capacities []*core.QuotaCapacity
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &capacities))
+	var capacity *core.QuotaCapacity
+	for _, c := range capacities {
+		if c.Name == "test-quota" {
+			capacity = c
+		}
+	}
+	if assert.NotNil(t, capacity) {
+		assert.Equal(t, int64(4), capacity.Pods)
+		assert.Equal(t, int64(8), capacity.NodeFitPods)
+	}
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/quotaCapacity", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPlugin_NodePool(t *testing.T) {
+	p := newTestPlugin(t)
+	poolNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-2", Labels: map[string]string{"pool": "gpu"}},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+		},
+	}
+	p.groupQuotaManager.OnNodeAdd(poolNode)
+	quota := newTestQuota("test-quota", "8", "4")
+	quota.Annotations = map[string]string{extension.AnnotationNodeSelector: `{"matchLabels":{"pool":"gpu"}}`}
+	p.OnQuotaAdd(quota)
+
+	// the pod requesting more than the node pool is rejected
+	pod1 := newTestPod("pod-1", "test-quota", "6")
+	p.OnPodAdd(pod1)
+	status := p.PreFilter(context.TODO(), framework.NewCycleState(), pod1)
+	assert.Equal(t, framework.Unschedulable, status.Code())
+	assert.Contains(t, status.Message(), "node pool")
+
+	pod2 := newTestPod("pod-2", "test-quota", "2")
+	p.OnPodAdd(pod2)
+	assert.True(t, p.PreFilter(context.TODO(), framework.NewCycleState(), pod2).IsSuccess())
+
+	// the pod is kept on the nodes of the node pool
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}})
+	assert.Equal(t, framework.UnschedulableAndUnresolvable, p.Filter(context.TODO(), framework.NewCycleState(), pod2, nodeInfo).Code())
+	nodeInfo = framework.NewNodeInfo()
+	nodeInfo.SetNode(poolNode)
+	assert.True(t, p.Filter(context.TODO(), framework.NewCycleState(), pod2, nodeInfo).IsSuccess())
+
+	// the pods of the unscoped quota groups are not limited
+	pod3 := newTestPod("pod-3", extension.DefaultQuotaName, "2")
+	assert.True(t, p.Filter(context.TODO(), framework.NewCycleState(), pod3, nodeInfo).IsSuccess())
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadaware
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+// usageForecaster projects the node usage ahead by double exponential smoothing (Holt's linear trend) over the
+// NodeUsage reported by the NodeMetrics. The level and the trend are smoothed per resource, and the trend is
+// measured per second since the NodeMetrics are reported at irregular intervals.
+type usageForecaster struct {
+	lock    sync.RWMutex
+	alpha   float64
+	beta    float64
+	horizon time.Duration
+	nodes   map[string]*nodeUsageForecast
+}
+
+type nodeUsageForecast struct {
+	timestamp time.Time
+	samples   int
+	// level and trend are in milli values, the trend is per second
+	level map[corev1.ResourceName]float64
+	trend map[corev1.ResourceName]float64
+}
+
+func newUsageForecaster(args *config.LoadAwareSchedulingForecastArgs) *usageForecaster {
+	f := &usageForecaster{
+		alpha:   0.5,
+		beta:    0.3,
+		horizon: 5 * time.Minute,
+		nodes:   map[string]*nodeUsageForecast{},
+	}
+	if args.HorizonSeconds != nil {
+		f.horizon = time.Duration(*args.HorizonSeconds) * time.Second
+	}
+	if args.LevelSmoothingPercent != nil {
+		f.alpha = float64(*args.LevelSmoothingPercent) / 100
+	}
+	if args.TrendSmoothingPercent != nil {
+		f.beta = float64(*args.TrendSmoothingPercent) / 100
+	}
+	return f
+}
+
+// update smooths the usage of the node sampled at the timestamp, the samples not newer than the last one are ignored.
+func (f *usageForecaster) update(nodeName string, timestamp time.Time, usage corev1.ResourceList) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	forecast := f.nodes[nodeName]
+	if forecast == nil {
+		forecast = &nodeUsageForecast{
+			level: map[corev1.ResourceName]float64{},
+			trend: map[corev1.ResourceName]float64{},
+		}
+		f.nodes[nodeName] = forecast
+	} else if !timestamp.After(forecast.timestamp) {
+		return
+	}
+
+	elapsed := timestamp.Sub(forecast.timestamp).Seconds()
+	for resourceName, quantity := range usage {
+		value := float64(quantity.MilliValue())
+		lastLevel, ok := forecast.level[resourceName]
+		if !ok || forecast.samples == 0 {
+			forecast.level[resourceName] = value
+			forecast.trend[resourceName] = 0
+			continue
+		}
+		lastTrend := forecast.trend[resourceName]
+		level := f.alpha*value + (1-f.alpha)*(lastLevel+lastTrend*elapsed)
+		forecast.level[resourceName] = level
+		forecast.trend[resourceName] = f.beta*(level-lastLevel)/elapsed + (1-f.beta)*lastTrend
+	}
+	forecast.timestamp = timestamp
+	forecast.samples++
+}
+
+func (f *usageForecaster) delete(nodeName string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	delete(f.nodes, nodeName)
+}
+
+// forecast returns the usage of the node projected to the horizon after now. It returns false if the node has
+// not reported enough samples to estimate the trend.
+func (f *usageForecaster) forecast(nodeName string, now time.Time) (corev1.ResourceList, bool) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	forecast := f.nodes[nodeName]
+	if forecast == nil || forecast.samples < 2 {
+		return nil, false
+	}
+	ahead := (now.Sub(forecast.timestamp) + f.horizon).Seconds()
+	usage := make(corev1.ResourceList, len(forecast.level))
+	for resourceName, level := range forecast.level {
+		value := math.Max(level+forecast.trend[resourceName]*ahead, 0)
+		usage[resourceName] = *resource.NewMilliQuantity(int64(math.Round(value)), resource.DecimalSI)
+	}
+	return usage, true
+}
+
+func (f *usageForecaster) updateNodeMetric(nodeMetric *slov1alpha1.NodeMetric) {
+	status := nodeMetric.Status
+	if status.UpdateTime == nil || status.NodeMetric == nil || status.NodeMetric.NodeUsage.ResourceList == nil {
+		return
+	}
+	f.update(nodeMetric.Name, status.UpdateTime.Time, status.NodeMetric.NodeUsage.ResourceList)
+}
+
+func (f *usageForecaster) OnAdd(obj interface{}) {
+	nodeMetric, ok := obj.(*slov1alpha1.NodeMetric)
+	if !ok {
+		return
+	}
+	f.updateNodeMetric(nodeMetric)
+}
+
+func (f *usageForecaster) OnUpdate(oldObj, newObj interface{}) {
+	nodeMetric, ok := newObj.(*slov1alpha1.NodeMetric)
+	if !ok {
+		return
+	}
+	f.updateNodeMetric(nodeMetric)
+}
+
+func (f *usageForecaster) OnDelete(obj interface{}) {
+	var nodeMetric *slov1alpha1.NodeMetric
+	switch t := obj.(type) {
+	case *slov1alpha1.NodeMetric:
+		nodeMetric = t
+	case cache.DeletedFinalStateUnknown:
+		var ok bool
+		nodeMetric, ok = t.Obj.(*slov1alpha1.NodeMetric)
+		if !ok {
+			return
+		}
+	default:
+		return
+	}
+	f.delete(nodeMetric.Name)
+}
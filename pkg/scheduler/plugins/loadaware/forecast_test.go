@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadaware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+func newTestNodeMetric(name string, timestamp time.Time, cpu string) *slov1alpha1.NodeMetric {
+	return &slov1alpha1.NodeMetric{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: slov1alpha1.NodeMetricStatus{
+			UpdateTime: &metav1.Time{Time: timestamp},
+			NodeMetric: &slov1alpha1.NodeMetricInfo{
+				NodeUsage: slov1alpha1.ResourceMap{
+					ResourceList: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse(cpu),
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestUsageForecaster(t *testing.T) {
+	now := time.Now()
+	forecaster := newUsageForecaster(&config.LoadAwareSchedulingForecastArgs{
+		HorizonSeconds:        pointer.Int64(60),
+		LevelSmoothingPercent: pointer.Int64(100),
+		TrendSmoothingPercent: pointer.Int64(100),
+	})
+
+	forecaster.OnAdd(newTestNodeMetric("node-1", now.Add(-2*time.Minute), "10"))
+	_, ok := forecaster.forecast("node-1", now)
+	assert.False(t, ok, "the trend is unknown with one sample")
+
+	// the usage ramps up 10 cores per minute
+	forecaster.OnUpdate(nil, newTestNodeMetric("node-1", now.Add(-time.Minute), "20"))
+	forecaster.OnUpdate(nil, newTestNodeMetric("node-1", now, "30"))
+	// the stale sample is ignored
+	forecaster.OnUpdate(nil, newTestNodeMetric("node-1", now.Add(-30*time.Second), "100"))
+	usage, ok := forecaster.forecast("node-1", now)
+	assert.True(t, ok)
+	assert.Equal(t, int64(40000), usage.Cpu().MilliValue())
+
+	// the usage never falls below zero
+	forecaster.OnUpdate(nil, newTestNodeMetric("node-1", now.Add(time.Minute), "1"))
+	usage, ok = forecaster.forecast("node-1", now.Add(time.Minute))
+	assert.True(t, ok)
+	assert.Equal(t, int64(0), usage.Cpu().MilliValue())
+
+	forecaster.OnDelete(cache.DeletedFinalStateUnknown{Obj: newTestNodeMetric("node-1", now, "1")})
+	_, ok = forecaster.forecast("node-1", now)
+	assert.False(t, ok)
+}
+
+func TestUsageForecasterSmoothing(t *testing.T) {
+	now := time.Now()
+	timeNowFn = func() time.Time {
+		return now
+	}
+	defer func() {
+		timeNowFn = time.Now
+	}()
+	forecaster := newUsageForecaster(&config.LoadAwareSchedulingForecastArgs{
+		HorizonSeconds:        pointer.Int64(300),
+		LevelSmoothingPercent: pointer.Int64(50),
+		TrendSmoothingPercent: pointer.Int64(30),
+	})
+	for i := 0; i < 10; i++ {
+		forecaster.update("node-1", now.Add(time.Duration(i-9)*time.Minute), corev1.ResourceList{
+			corev1.ResourceCPU: *resource.NewQuantity(int64(10+i), resource.DecimalSI),
+		})
+	}
+	// the node ramping up is projected above the latest usage
+	usage, ok := forecaster.forecast("node-1", now)
+	assert.True(t, ok)
+	assert.Greater(t, usage.Cpu().MilliValue(), int64(19000))
+
+	p := &Plugin{
+		args:            &config.LoadAwareSchedulingArgs{},
+		usageForecaster: forecaster,
+	}
+	nodeMetric := newTestNodeMetric("node-1", now, "19")
+	scoreUsage := p.getScoreNodeUsage(nodeMetric)
+	assert.Equal(t, usage.Cpu().MilliValue(), scoreUsage.Cpu().MilliValue())
+	// fall back to the latest usage without the history
+	nodeMetric = newTestNodeMetric("node-2", now, "19")
+	scoreUsage = p.getScoreNodeUsage(nodeMetric)
+	assert.Equal(t, int64(19000), scoreUsage.Cpu().MilliValue())
+}
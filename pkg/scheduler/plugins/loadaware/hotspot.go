@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadaware
+
+import (
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+const defaultHotspotCooldown = 5 * time.Minute
+
+// hotspotCooldown remembers the nodes whose usage crossed the critical thresholds, and keeps them filtered for
+// the cooldown period since they are last seen as hotspots, even if the usage dips in the meantime. The usage is
+// observed only when the NodeMetrics are reported, and the cooldown is anchored to the report time, so the
+// scheduling cycles never extend it.
+type hotspotCooldown struct {
+	lock           sync.RWMutex
+	thresholds     map[corev1.ResourceName]int64
+	cooldown       time.Duration
+	nodeLister     corelisters.NodeLister
+	metricProvider *cachedMetricProvider
+	cooldownUntil  map[string]time.Time
+}
+
+func newHotspotCooldown(args *config.LoadAwareSchedulingHotspotCooldownArgs, nodeLister corelisters.NodeLister, metricProvider *cachedMetricProvider) *hotspotCooldown {
+	h := &hotspotCooldown{
+		thresholds:     args.CriticalThresholds,
+		cooldown:       defaultHotspotCooldown,
+		nodeLister:     nodeLister,
+		metricProvider: metricProvider,
+		cooldownUntil:  map[string]time.Time{},
+	}
+	if args.CooldownSeconds != nil {
+		h.cooldown = time.Duration(*args.CooldownSeconds) * time.Second
+	}
+	return h
+}
+
+// observe checks the usage of the node observed at the time against the critical thresholds, and starts the cooldown
+// since then if any is crossed. providedUsage is the usage provided by the MetricProvider, which takes precedence
+// over the usage.
+func (h *hotspotCooldown) observe(node *corev1.Node, usage, providedUsage corev1.ResourceList, observedTime time.Time) {
+	var hotResource corev1.ResourceName
+	for resourceName, threshold := range h.thresholds {
+		if threshold == 0 {
+			continue
+		}
+		used, ok := providedUsage[resourceName]
+		if !ok {
+			if used, ok = usage[resourceName]; !ok {
+				continue
+			}
+		}
+		total := getNodeAllocatable(node, resourceName, providedUsage)
+		if total.IsZero() {
+			continue
+		}
+		if used.MilliValue()*100 >= total.MilliValue()*threshold {
+			hotResource = resourceName
+			break
+		}
+	}
+	if hotResource == "" {
+		return
+	}
+
+	now := timeNowFn()
+	until := observedTime.Add(h.cooldown)
+	if !now.Before(until) {
+		return
+	}
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	// the NodeMetrics replayed by the informer never shorten the cooldown
+	if until.After(h.cooldownUntil[node.Name]) {
+		h.cooldownUntil[node.Name] = until
+	}
+	// clean up the nodes already cooled down
+	for nodeName, until := range h.cooldownUntil {
+		if !now.Before(until) {
+			delete(h.cooldownUntil, nodeName)
+		}
+	}
+	klog.V(4).InfoS("node is a hotspot, start cooldown", "node", node.Name, "resource", hotResource, "cooldown", h.cooldown)
+}
+
+func (h *hotspotCooldown) inCooldown(nodeName string) bool {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	until, ok := h.cooldownUntil[nodeName]
+	return ok && timeNowFn().Before(until)
+}
+
+func (h *hotspotCooldown) delete(nodeName string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	delete(h.cooldownUntil, nodeName)
+}
+
+func (h *hotspotCooldown) observeNodeMetric(nodeMetric *slov1alpha1.NodeMetric) {
+	if nodeMetric.Status.UpdateTime == nil || nodeMetric.Status.NodeMetric == nil ||
+		nodeMetric.Status.NodeMetric.NodeUsage.ResourceList == nil {
+		return
+	}
+	node, err := h.nodeLister.Get(nodeMetric.Name)
+	if err != nil {
+		return
+	}
+	var providedUsage corev1.ResourceList
+	if h.metricProvider != nil {
+		providedUsage = h.metricProvider.getNodeUsage(node.Name)
+	}
+	h.observe(node, nodeMetric.Status.NodeMetric.NodeUsage.ResourceList, providedUsage, nodeMetric.Status.UpdateTime.Time)
+}
+
+func (h *hotspotCooldown) OnAdd(obj interface{}) {
+	nodeMetric, ok := obj.(*slov1alpha1.NodeMetric)
+	if !ok {
+		return
+	}
+	h.observeNodeMetric(nodeMetric)
+}
+
+func (h *hotspotCooldown) OnUpdate(oldObj, newObj interface{}) {
+	nodeMetric, ok := newObj.(*slov1alpha1.NodeMetric)
+	if !ok {
+		return
+	}
+	h.observeNodeMetric(nodeMetric)
+}
+
+func (h *hotspotCooldown) OnDelete(obj interface{}) {
+	var nodeMetric *slov1alpha1.NodeMetric
+	switch t := obj.(type) {
+	case *slov1alpha1.NodeMetric:
+		nodeMetric = t
+	case cache.DeletedFinalStateUnknown:
+		var ok bool
+		nodeMetric, ok = t.Obj.(*slov1alpha1.NodeMetric)
+		if !ok {
+			return
+		}
+	default:
+		return
+	}
+	h.delete(nodeMetric.Name)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadaware
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config/v1beta2"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+func TestHotspotCooldown(t *testing.T) {
+	now := time.Now()
+	timeNowFn = func() time.Time {
+		return now
+	}
+	defer func() {
+		timeNowFn = time.Now
+	}()
+
+	h := newHotspotCooldown(&config.LoadAwareSchedulingHotspotCooldownArgs{
+		CriticalThresholds: map[corev1.ResourceName]int64{
+			corev1.ResourceCPU: 90,
+			"psi-cpu":          50,
+		},
+		CooldownSeconds: pointer.Int64(60),
+	}, nil, nil)
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node-1"},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("100"),
+			},
+		},
+	}
+
+	h.observe(node, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("80")}, nil, now)
+	assert.False(t, h.inCooldown(node.Name))
+
+	// the cooldown starts when the usage is reported
+	reportTime := now
+	now = now.Add(10 * time.Second)
+	h.observe(node, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("95")}, nil, reportTime)
+	assert.True(t, h.inCooldown(node.Name))
+
+	// the usage dips, but the node is still in cooldown
+	now = now.Add(20 * time.Second)
+	h.observe(node, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50")}, nil, now)
+	assert.True(t, h.inCooldown(node.Name))
+
+	// the same report replayed never extends the cooldown
+	h.observe(node, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("95")}, nil, reportTime)
+	now = now.Add(31 * time.Second)
+	assert.False(t, h.inCooldown(node.Name))
+
+	// the report older than the cooldown is ignored
+	h.observe(node, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("95")}, nil, reportTime)
+	assert.False(t, h.inCooldown(node.Name))
+
+	// the provided usage missing in the allocatable is a percentage
+	h.observe(node, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50")}, corev1.ResourceList{"psi-cpu": resource.MustParse("60")}, now)
+	assert.True(t, h.inCooldown(node.Name))
+
+	h.delete(node.Name)
+	assert.False(t, h.inCooldown(node.Name))
+}
+
+func TestFilterHotspotCooldown(t *testing.T) {
+	var v1beta2args v1beta2.LoadAwareSchedulingArgs
+	v1beta2args.HotspotCooldown = &v1beta2.LoadAwareSchedulingHotspotCooldownArgs{
+		CriticalThresholds: map[corev1.ResourceName]int64{
+			corev1.ResourceCPU: 90,
+		},
+	}
+	v1beta2.SetDefaults_LoadAwareSchedulingArgs(&v1beta2args)
+	assert.Equal(t, int64(300), *v1beta2args.HotspotCooldown.CooldownSeconds)
+	var args config.LoadAwareSchedulingArgs
+	assert.NoError(t, v1beta2.Convert_v1beta2_LoadAwareSchedulingArgs_To_config_LoadAwareSchedulingArgs(&v1beta2args, &args, nil))
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node-1"},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100"),
+				corev1.ResourceMemory: resource.MustParse("100Gi"),
+			},
+		},
+	}
+	p, nodeInfo := newTestPluginWithNodeMetric(t, &args, node, nil, &slov1alpha1.NodeMetricInfo{
+		NodeUsage: slov1alpha1.ResourceMap{
+			ResourceList: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("95"),
+			},
+		},
+	})
+	// the usage is observed only from the NodeMetric events, the node isn't known yet, so it's filtered by the
+	// usage thresholds only
+	status := p.Filter(context.TODO(), framework.NewCycleState(), &corev1.Pod{}, nodeInfo)
+	assert.True(t, framework.NewStatus(framework.Unschedulable, fmt.Sprintf(ErrReasonUsageExceedThreshold, corev1.ResourceCPU)).Equal(status))
+	assert.False(t, p.hotspotCooldown.inCooldown(node.Name))
+
+	assert.NoError(t, p.handle.SharedInformerFactory().Core().V1().Nodes().Informer().GetStore().Add(node))
+	nodeMetric, err := p.nodeMetricLister.Get(node.Name)
+	assert.NoError(t, err)
+	p.hotspotCooldown.OnUpdate(nodeMetric, nodeMetric)
+	status = p.Filter(context.TODO(), framework.NewCycleState(), &corev1.Pod{}, nodeInfo)
+	assert.True(t, framework.NewStatus(framework.Unschedulable, ErrReasonNodeHotspotCooldown).Equal(status))
+	assert.True(t, p.hotspotCooldown.inCooldown(node.Name))
+}
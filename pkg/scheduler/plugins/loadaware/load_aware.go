@@ -26,6 +26,8 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/klog/v2"
 	resourceapi "k8s.io/kubernetes/pkg/api/v1/resource"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
@@ -39,9 +41,11 @@ import (
 )
 
 const (
-	Name                          = "LoadAwareScheduling"
-	ErrReasonNodeMetricExpired    = "node(s) nodeMetric expired"
-	ErrReasonUsageExceedThreshold = "node(s) %s usage exceed threshold"
+	Name                             = "LoadAwareScheduling"
+	ErrReasonNodeMetricExpired       = "node(s) nodeMetric expired"
+	ErrReasonUsageExceedThreshold    = "node(s) %s usage exceed threshold"
+	ErrReasonPodChurnExceedThreshold = "node(s) pod churn exceed threshold"
+	ErrReasonNodeHotspotCooldown     = "node(s) hotspot in cooldown"
 )
 
 const (
@@ -51,6 +55,8 @@ const (
 	DefaultMemoryRequest int64 = 200 * 1024 * 1024 // 200 MB
 	// DefaultNodeMetricReportInterval defines the default koodlet report NodeMetric interval.
 	DefaultNodeMetricReportInterval = 60 * time.Second
+	// DefaultPodChurnWindow defines the default window to count the pods started or stopped on the node.
+	DefaultPodChurnWindow = 60 * time.Second
 )
 
 var (
@@ -64,6 +70,10 @@ type Plugin struct {
 	args             *config.LoadAwareSchedulingArgs
 	nodeMetricLister slolisters.NodeMetricLister
 	podAssignCache   *podAssignCache
+	pvcLister        corelisters.PersistentVolumeClaimLister
+	usageForecaster  *usageForecaster
+	metricProvider   *cachedMetricProvider
+	hotspotCooldown  *hotspotCooldown
 }
 
 func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error) {
@@ -82,14 +92,53 @@ func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error)
 	}
 
 	assignCache := newPodAssignCache()
+	if pluginArgs.PodChurnWindowSeconds != nil {
+		assignCache.churnWindow = time.Duration(*pluginArgs.PodChurnWindowSeconds) * time.Second
+	}
 	frameworkExtender.SharedInformerFactory().Core().V1().Pods().Informer().AddEventHandler(assignCache)
 	nodeMetricLister := frameworkExtender.KoordinatorSharedInformerFactory().Slo().V1alpha1().NodeMetrics().Lister()
+	pvcLister := frameworkExtender.SharedInformerFactory().Core().V1().PersistentVolumeClaims().Lister()
+
+	var forecaster *usageForecaster
+	if pluginArgs.Forecast != nil {
+		forecaster = newUsageForecaster(pluginArgs.Forecast)
+		frameworkExtender.KoordinatorSharedInformerFactory().Slo().V1alpha1().NodeMetrics().Informer().AddEventHandler(forecaster)
+	}
+
+	var metricProvider *cachedMetricProvider
+	if pluginArgs.MetricProvider != nil {
+		provider, err := NewMetricProvider(pluginArgs.MetricProvider)
+		if err != nil {
+			return nil, err
+		}
+		interval := defaultMetricProviderCacheInterval
+		if pluginArgs.MetricProvider.CacheSeconds != nil {
+			interval = time.Duration(*pluginArgs.MetricProvider.CacheSeconds) * time.Second
+		}
+		metricProvider = newCachedMetricProvider(provider, interval)
+		stopCh := wait.NeverStop
+		if frameworkExtender.StopCh() != nil {
+			stopCh = frameworkExtender.StopCh()
+		}
+		go wait.Until(metricProvider.refresh, metricProvider.interval, stopCh)
+	}
+
+	var cooldown *hotspotCooldown
+	if pluginArgs.HotspotCooldown != nil {
+		nodeLister := frameworkExtender.SharedInformerFactory().Core().V1().Nodes().Lister()
+		cooldown = newHotspotCooldown(pluginArgs.HotspotCooldown, nodeLister, metricProvider)
+		frameworkExtender.KoordinatorSharedInformerFactory().Slo().V1alpha1().NodeMetrics().Informer().AddEventHandler(cooldown)
+	}
 
 	return &Plugin{
 		handle:           handle,
 		args:             pluginArgs,
 		nodeMetricLister: nodeMetricLister,
 		podAssignCache:   assignCache,
+		pvcLister:        pvcLister,
+		usageForecaster:  forecaster,
+		metricProvider:   metricProvider,
+		hotspotCooldown:  cooldown,
 	}, nil
 }
 
@@ -118,7 +167,22 @@ func (p *Plugin) Filter(ctx context.Context, state *framework.CycleState, pod *c
 		}
 	}
 
+	if threshold := p.podChurnThreshold(); threshold > 0 && p.podAssignCache.churn(node.Name) >= threshold {
+		return framework.NewStatus(framework.Unschedulable, ErrReasonPodChurnExceedThreshold)
+	}
+
+	if p.hotspotCooldown != nil && p.hotspotCooldown.inCooldown(node.Name) {
+		return framework.NewStatus(framework.Unschedulable, ErrReasonNodeHotspotCooldown)
+	}
+
+	if status := p.filterLocalStorage(state, pod, node.Name, nodeMetric); !status.IsSuccess() {
+		return status
+	}
+
 	usageThresholds := p.args.UsageThresholds
+	if profile := p.getUsageThresholdProfile(pod); profile != nil && len(profile.UsageThresholds) > 0 {
+		usageThresholds = profile.UsageThresholds
+	}
 	customUsageThresholds, err := extension.GetCustomUsageThresholds(node)
 	if err != nil {
 		klog.V(5).ErrorS(err, "failed to GetCustomUsageThresholds from", "node", node.Name)
@@ -129,18 +193,36 @@ func (p *Plugin) Filter(ctx context.Context, state *framework.CycleState, pod *c
 	}
 
 	if len(usageThresholds) > 0 {
-		if nodeMetric.Status.NodeMetric == nil {
-			return nil
-		}
+		// the pods assigned recently are not reflected in the NodeMetric yet, count their estimated usage to
+		// not schedule too many pods onto the same node within one reporting interval
+		estimatedAssignedPodUsage := p.estimatedAssignedPodUsage(node.Name, nodeMetric, usageThresholds)
+		providedUsage := p.getProvidedNodeUsage(node.Name)
 		for resourceName, threshold := range usageThresholds {
 			if threshold == 0 {
 				continue
 			}
-			total := node.Status.Allocatable[resourceName]
+			total := getNodeAllocatable(node, resourceName, providedUsage)
 			if total.IsZero() {
 				continue
 			}
-			used := nodeMetric.Status.NodeMetric.NodeUsage.ResourceList[resourceName]
+			var used resource.Quantity
+			if resourceName == corev1.ResourcePods {
+				// the kubelet and the network agents degrade at high pod density, count the pods directly
+				used = *resource.NewQuantity(int64(len(nodeInfo.Pods)), resource.DecimalSI)
+			} else if quantity, ok := providedUsage[resourceName]; ok {
+				used = quantity
+			} else if nodeMetric.Status.NodeMetric != nil {
+				used = nodeMetric.Status.NodeMetric.NodeUsage.ResourceList[resourceName].DeepCopy()
+				if estimated := estimatedAssignedPodUsage[resourceName]; estimated > 0 {
+					if resourceName == corev1.ResourceCPU {
+						used.Add(*resource.NewMilliQuantity(estimated, resource.DecimalSI))
+					} else {
+						used.Add(*resource.NewQuantity(estimated, resource.BinarySI))
+					}
+				}
+			} else {
+				continue
+			}
 			usage := int64(math.Round(float64(used.MilliValue()) / float64(total.MilliValue()) * 100))
 			if usage >= threshold {
 				return framework.NewStatus(framework.Unschedulable, fmt.Sprintf(ErrReasonUsageExceedThreshold, resourceName))
@@ -186,22 +268,35 @@ func (p *Plugin) Score(ctx context.Context, state *framework.CycleState, pod *co
 		return 0, nil
 	}
 
-	estimatedUsed := estimatedPodUsed(pod, p.args.ResourceWeights, p.args.EstimatedScalingFactors)
-	estimatedAssignedPodUsage := p.estimatedAssignedPodUsage(nodeName, nodeMetric)
+	resourceWeights := p.getResourceWeights(pod)
+	estimatedUsed := estimatedPodUsed(pod, resourceWeights, p.args.EstimatedScalingFactors)
+	estimatedAssignedPodUsage := p.estimatedAssignedPodUsage(nodeName, nodeMetric, resourceWeights)
 	for resourceName, value := range estimatedAssignedPodUsage {
 		estimatedUsed[resourceName] += value
 	}
 
+	nodeUsage := p.getScoreNodeUsage(nodeMetric)
+	providedUsage := p.getProvidedNodeUsage(nodeName)
+	if len(providedUsage) > 0 {
+		mergedUsage := nodeUsage.DeepCopy()
+		if mergedUsage == nil {
+			mergedUsage = corev1.ResourceList{}
+		}
+		for resourceName, quantity := range providedUsage {
+			mergedUsage[resourceName] = quantity
+		}
+		nodeUsage = mergedUsage
+	}
 	allocatable := make(map[corev1.ResourceName]int64)
-	for resourceName := range p.args.ResourceWeights {
-		quantity := node.Status.Allocatable[resourceName]
+	for resourceName := range resourceWeights {
+		quantity := getNodeAllocatable(node, resourceName, providedUsage)
 		if resourceName == corev1.ResourceCPU {
 			allocatable[resourceName] = quantity.MilliValue()
 		} else {
 			allocatable[resourceName] = quantity.Value()
 		}
-		if nodeMetric.Status.NodeMetric != nil {
-			quantity = nodeMetric.Status.NodeMetric.NodeUsage.ResourceList[resourceName]
+		if nodeUsage != nil {
+			quantity = nodeUsage[resourceName]
 			if resourceName == corev1.ResourceCPU {
 				estimatedUsed[resourceName] += quantity.MilliValue()
 			} else {
@@ -210,10 +305,138 @@ func (p *Plugin) Score(ctx context.Context, state *framework.CycleState, pod *co
 		}
 	}
 
-	score := loadAwareSchedulingScorer(p.args.ResourceWeights, estimatedUsed, allocatable)
+	if _, ok := resourceWeights[corev1.ResourcePods]; ok {
+		estimatedUsed[corev1.ResourcePods] = int64(len(nodeInfo.Pods)) + 1
+	}
+
+	score := loadAwareSchedulingScorer(resourceWeights, estimatedUsed, allocatable)
+	var weightSum int64
+	for _, weight := range resourceWeights {
+		weightSum += weight
+	}
+	if churnWeight := p.podChurnWeight(); churnWeight > 0 {
+		churnScore := leastRequestedScore(p.podAssignCache.churn(nodeName)+1, p.podChurnThreshold())
+		score = (score*weightSum + churnScore*churnWeight) / (weightSum + churnWeight)
+		weightSum += churnWeight
+	}
+	if storageWeight := p.localStorageWeight(); storageWeight > 0 {
+		if storageScore, ok := p.scoreLocalStorage(state, pod, nodeName, nodeMetric); ok {
+			score = (score*weightSum + storageScore*storageWeight) / (weightSum + storageWeight)
+		}
+	}
 	return score, nil
 }
 
+// getScoreNodeUsage returns the node usage to score the node. The projected usage and the aggregated usage are
+// preferred in order if configured, and it falls back to the latest average usage if neither is available.
+func (p *Plugin) getScoreNodeUsage(nodeMetric *slov1alpha1.NodeMetric) corev1.ResourceList {
+	if nodeMetric.Status.NodeMetric == nil {
+		return nil
+	}
+	if p.usageForecaster != nil {
+		if usage, ok := p.usageForecaster.forecast(nodeMetric.Name, timeNowFn()); ok {
+			return usage
+		}
+	}
+	if aggregated := p.args.Aggregated; aggregated != nil && aggregated.ScoreAggregationType != "" {
+		usage := getAggregatedNodeUsage(nodeMetric.Status.NodeMetric, aggregated.ScoreAggregationType, aggregated.ScoreAggregatedDuration.Duration)
+		if usage != nil {
+			return usage
+		}
+	}
+	return nodeMetric.Status.NodeMetric.NodeUsage.ResourceList
+}
+
+// getAggregatedNodeUsage returns the node usage aggregated by the aggregationType over the duration,
+// zero duration means the longest one.
+func getAggregatedNodeUsage(nodeMetricInfo *slov1alpha1.NodeMetricInfo, aggregationType slov1alpha1.AggregationType, duration time.Duration) corev1.ResourceList {
+	var target *slov1alpha1.AggregatedUsage
+	for i := range nodeMetricInfo.AggregatedNodeUsages {
+		aggregatedUsage := &nodeMetricInfo.AggregatedNodeUsages[i]
+		if _, ok := aggregatedUsage.Usage[aggregationType]; !ok {
+			continue
+		}
+		if duration > 0 {
+			if aggregatedUsage.Duration.Duration == duration {
+				target = aggregatedUsage
+				break
+			}
+		} else if target == nil || aggregatedUsage.Duration.Duration > target.Duration.Duration {
+			target = aggregatedUsage
+		}
+	}
+	if target == nil {
+		return nil
+	}
+	return target.Usage[aggregationType].ResourceList
+}
+
+// getProvidedNodeUsage returns the node usage from the MetricProvider, nil if not configured.
+func (p *Plugin) getProvidedNodeUsage(nodeName string) corev1.ResourceList {
+	if p.metricProvider == nil {
+		return nil
+	}
+	return p.metricProvider.getNodeUsage(nodeName)
+}
+
+// getNodeAllocatable returns the allocatable of the resource. The usage provided by the MetricProvider of the
+// resource not allocatable on the node is in percentage, e.g. the saturation of the application QPS.
+func getNodeAllocatable(node *corev1.Node, resourceName corev1.ResourceName, providedUsage corev1.ResourceList) resource.Quantity {
+	if quantity, ok := node.Status.Allocatable[resourceName]; ok {
+		return quantity
+	}
+	if _, ok := providedUsage[resourceName]; ok {
+		return *resource.NewQuantity(100, resource.DecimalSI)
+	}
+	return resource.Quantity{}
+}
+
+// getUsageThresholdProfile returns the first UsageThresholdProfile selecting the pod, nil if none matches.
+func (p *Plugin) getUsageThresholdProfile(pod *corev1.Pod) *config.LoadAwareSchedulingUsageThresholdProfile {
+	priorityClass := string(extension.GetPriorityClass(pod))
+	for i := range p.args.UsageThresholdProfiles {
+		profile := &p.args.UsageThresholdProfiles[i]
+		if len(profile.Namespaces) > 0 && !containsString(profile.Namespaces, pod.Namespace) {
+			continue
+		}
+		if len(profile.PriorityClasses) > 0 && !containsString(profile.PriorityClasses, priorityClass) {
+			continue
+		}
+		return profile
+	}
+	return nil
+}
+
+func (p *Plugin) getResourceWeights(pod *corev1.Pod) map[corev1.ResourceName]int64 {
+	if profile := p.getUsageThresholdProfile(pod); profile != nil && len(profile.ResourceWeights) > 0 {
+		return profile.ResourceWeights
+	}
+	return p.args.ResourceWeights
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Plugin) podChurnThreshold() int64 {
+	if p.args.PodChurnThreshold == nil {
+		return 0
+	}
+	return *p.args.PodChurnThreshold
+}
+
+func (p *Plugin) podChurnWeight() int64 {
+	if p.args.PodChurnWeight == nil || p.podChurnThreshold() <= 0 {
+		return 0
+	}
+	return *p.args.PodChurnWeight
+}
+
 func isNodeMetricExpired(nodeMetric *slov1alpha1.NodeMetric, nodeMetricExpirationSeconds int64) bool {
 	return nodeMetric == nil ||
 		nodeMetric.Status.UpdateTime == nil ||
@@ -221,16 +444,19 @@ func isNodeMetricExpired(nodeMetric *slov1alpha1.NodeMetric, nodeMetricExpiratio
 			time.Since(nodeMetric.Status.UpdateTime.Time) >= time.Duration(nodeMetricExpirationSeconds)*time.Second
 }
 
-func (p *Plugin) estimatedAssignedPodUsage(nodeName string, nodeMetric *slov1alpha1.NodeMetric) map[corev1.ResourceName]int64 {
+// estimatedAssignedPodUsage estimates the usage of the pods assigned to the node but not reflected in the NodeMetric,
+// which are assigned after the NodeMetric updated or within the last reporting interval.
+func (p *Plugin) estimatedAssignedPodUsage(nodeName string, nodeMetric *slov1alpha1.NodeMetric, resourceWeights map[corev1.ResourceName]int64) map[corev1.ResourceName]int64 {
 	estimatedUsed := make(map[corev1.ResourceName]int64)
 	nodeMetricReportInterval := getNodeMetricReportInterval(nodeMetric)
 	p.podAssignCache.lock.RLock()
 	defer p.podAssignCache.lock.RUnlock()
 	for _, assignInfo := range p.podAssignCache.podInfoItems[nodeName] {
-		if assignInfo.timestamp.After(nodeMetric.Status.UpdateTime.Time) ||
+		if nodeMetric.Status.UpdateTime == nil ||
+			assignInfo.timestamp.After(nodeMetric.Status.UpdateTime.Time) ||
 			assignInfo.timestamp.Before(nodeMetric.Status.UpdateTime.Time) &&
 				nodeMetric.Status.UpdateTime.Sub(assignInfo.timestamp) < nodeMetricReportInterval {
-			estimated := estimatedPodUsed(assignInfo.pod, p.args.ResourceWeights, p.args.EstimatedScalingFactors)
+			estimated := estimatedPodUsed(assignInfo.pod, resourceWeights, p.args.EstimatedScalingFactors)
 			for resourceName, value := range estimated {
 				estimatedUsed[resourceName] += value
 			}
@@ -248,6 +474,8 @@ func getNodeMetricReportInterval(nodeMetric *slov1alpha1.NodeMetric) time.Durati
 
 func estimatedPodUsed(pod *corev1.Pod, resourceWeights map[corev1.ResourceName]int64, scalingFactors map[corev1.ResourceName]int64) map[corev1.ResourceName]int64 {
 	requests, limits := resourceapi.PodRequestsAndLimits(pod)
+	translateGPURequests(requests)
+	translateGPURequests(limits)
 	estimatedUsed := make(map[corev1.ResourceName]int64)
 	priorityClass := extension.GetPriorityClass(pod)
 	for resourceName := range resourceWeights {
@@ -257,6 +485,27 @@ func estimatedPodUsed(pod *corev1.Pod, resourceWeights map[corev1.ResourceName]i
 	return estimatedUsed
 }
 
+// translateGPURequests translates the whole GPUs requested by nvidia.com/gpu or koordinator.sh/gpu to the
+// gpu-core and gpu-memory-ratio, which are reported as the GPU utilization of the node.
+func translateGPURequests(resourceList corev1.ResourceList) {
+	if _, ok := resourceList[extension.GPUCore]; ok {
+		return
+	}
+	var gpu *resource.Quantity
+	if koordGPU, ok := resourceList[extension.KoordGPU]; ok {
+		gpu = &koordGPU
+	} else if nvidiaGPU, ok := resourceList[extension.NvidiaGPU]; ok {
+		gpu = resource.NewQuantity(nvidiaGPU.Value()*100, resource.DecimalSI)
+	}
+	if gpu == nil {
+		return
+	}
+	resourceList[extension.GPUCore] = gpu.DeepCopy()
+	if _, ok := resourceList[extension.GPUMemoryRatio]; !ok {
+		resourceList[extension.GPUMemoryRatio] = gpu.DeepCopy()
+	}
+}
+
 func estimatedUsedByResource(requests, limits corev1.ResourceList, resourceName corev1.ResourceName, scalingFactor int64) int64 {
 	limitQuantity := limits[resourceName]
 	requestQuantity := requests[resourceName]
@@ -1112,3 +1112,510 @@ func TestScore(t *testing.T) {
 		})
 	}
 }
+
+func newTestPodDensityPlugin(t *testing.T, args *config.LoadAwareSchedulingArgs, node *corev1.Node, pods []*corev1.Pod) (*Plugin, *framework.NodeInfo) {
+	return newTestPluginWithNodeMetric(t, args, node, pods, &slov1alpha1.NodeMetricInfo{})
+}
+
+func newTestPluginWithNodeMetric(t *testing.T, args *config.LoadAwareSchedulingArgs, node *corev1.Node, pods []*corev1.Pod, nodeMetricInfo *slov1alpha1.NodeMetricInfo) (*Plugin, *framework.NodeInfo) {
+	koordClientSet := koordfake.NewSimpleClientset()
+	koordSharedInformerFactory := koordinatorinformers.NewSharedInformerFactory(koordClientSet, 0)
+	extendHandle := frameworkext.NewExtendedHandle(
+		frameworkext.WithKoordinatorClientSet(koordClientSet),
+		frameworkext.WithKoordinatorSharedInformerFactory(koordSharedInformerFactory),
+	)
+	proxyNew := frameworkext.PluginFactoryProxy(extendHandle, New)
+
+	registeredPlugins := []schedulertesting.RegisterPluginFunc{
+		schedulertesting.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		schedulertesting.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+	}
+	cs := kubefake.NewSimpleClientset()
+	snapshot := newTestSharedLister(pods, []*corev1.Node{node})
+	fh, err := schedulertesting.NewFramework(registeredPlugins, "koord-scheduler",
+		runtime.WithClientSet(cs),
+		runtime.WithInformerFactory(informers.NewSharedInformerFactory(cs, 0)),
+		runtime.WithSnapshotSharedLister(snapshot),
+	)
+	assert.NoError(t, err)
+	p, err := proxyNew(args, fh)
+	assert.NoError(t, err)
+
+	nodeMetric := &slov1alpha1.NodeMetric{
+		ObjectMeta: metav1.ObjectMeta{Name: node.Name},
+		Status: slov1alpha1.NodeMetricStatus{
+			UpdateTime: &metav1.Time{Time: time.Now()},
+			NodeMetric: nodeMetricInfo,
+		},
+	}
+	_, err = koordClientSet.SloV1alpha1().NodeMetrics().Create(context.TODO(), nodeMetric, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	koordSharedInformerFactory.Start(context.TODO().Done())
+	koordSharedInformerFactory.WaitForCacheSync(context.TODO().Done())
+
+	nodeInfo, err := snapshot.Get(node.Name)
+	assert.NoError(t, err)
+	return p.(*Plugin), nodeInfo
+}
+
+func TestPodDensity(t *testing.T) {
+	var v1beta2args v1beta2.LoadAwareSchedulingArgs
+	v1beta2.SetDefaults_LoadAwareSchedulingArgs(&v1beta2args)
+	v1beta2args.ResourceWeights = map[corev1.ResourceName]int64{
+		corev1.ResourceCPU:    1,
+		corev1.ResourceMemory: 1,
+		corev1.ResourcePods:   1,
+	}
+	v1beta2args.UsageThresholds = map[corev1.ResourceName]int64{
+		corev1.ResourcePods: 50,
+	}
+	v1beta2args.PodChurnThreshold = pointer.Int64(4)
+	v1beta2args.PodChurnWeight = pointer.Int64(1)
+	var args config.LoadAwareSchedulingArgs
+	assert.NoError(t, v1beta2.Convert_v1beta2_LoadAwareSchedulingArgs_To_config_LoadAwareSchedulingArgs(&v1beta2args, &args, nil))
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node-1"},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("96"),
+				corev1.ResourceMemory: resource.MustParse("512Gi"),
+				corev1.ResourcePods:   resource.MustParse("10"),
+			},
+		},
+	}
+	var pods []*corev1.Pod
+	for i := 0; i < 4; i++ {
+		pods = append(pods, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("pod-%d", i), UID: uuid.NewUUID()},
+			Spec:       corev1.PodSpec{NodeName: node.Name},
+		})
+	}
+	p, nodeInfo := newTestPodDensityPlugin(t, &args, node, pods)
+
+	// 4 of 10 pods is below the threshold
+	assert.Nil(t, p.Filter(context.TODO(), framework.NewCycleState(), &corev1.Pod{}, nodeInfo))
+	nodeInfo.AddPod(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-4", UID: uuid.NewUUID()},
+		Spec:       corev1.PodSpec{NodeName: node.Name},
+	})
+	status := p.Filter(context.TODO(), framework.NewCycleState(), &corev1.Pod{}, nodeInfo)
+	assert.True(t, framework.NewStatus(framework.Unschedulable, fmt.Sprintf(ErrReasonUsageExceedThreshold, corev1.ResourcePods)).Equal(status))
+
+	// cpu and memory score 99, pods score (10-5-1)*100/10=40, so the resource score is (99+99+40)/3=79,
+	// and the churn score is (4-2-1)*100/4=25
+	p.podAssignCache.assign(node.Name, pods[0])
+	p.podAssignCache.assign(node.Name, pods[1])
+	score, status := p.Score(context.TODO(), framework.NewCycleState(), &corev1.Pod{}, node.Name)
+	assert.Nil(t, status)
+	assert.Equal(t, int64((79*3+25)/4), score)
+
+	// the node is filtered when the pod churn reaches the threshold
+	p.podAssignCache.unAssign(node.Name, pods[0])
+	p.podAssignCache.assign(node.Name, pods[2])
+	status = p.Filter(context.TODO(), framework.NewCycleState(), &corev1.Pod{}, nodeInfo)
+	assert.True(t, framework.NewStatus(framework.Unschedulable, ErrReasonPodChurnExceedThreshold).Equal(status))
+}
+
+func TestLocalStorage(t *testing.T) {
+	var v1beta2args v1beta2.LoadAwareSchedulingArgs
+	v1beta2.SetDefaults_LoadAwareSchedulingArgs(&v1beta2args)
+	v1beta2args.ResourceWeights = map[corev1.ResourceName]int64{
+		corev1.ResourceCPU: 1,
+	}
+	v1beta2args.UsageThresholds = nil
+	v1beta2args.EstimatedScalingFactors = map[corev1.ResourceName]int64{
+		corev1.ResourceCPU: 100,
+	}
+	v1beta2args.LocalStorageIOUtilizationThreshold = pointer.Int64(80)
+	v1beta2args.LocalStorageWeight = pointer.Int64(1)
+	var args config.LoadAwareSchedulingArgs
+	assert.NoError(t, v1beta2.Convert_v1beta2_LoadAwareSchedulingArgs_To_config_LoadAwareSchedulingArgs(&v1beta2args, &args, nil))
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node-1"},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100"),
+				corev1.ResourceMemory: resource.MustParse("512Gi"),
+			},
+		},
+	}
+	nodeMetricInfo := &slov1alpha1.NodeMetricInfo{
+		NodeUsage: slov1alpha1.ResourceMap{
+			ResourceList: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("0"),
+			},
+		},
+		LocalStorages: []slov1alpha1.LocalStorageInfo{
+			{
+				StorageClassName: "local-ssd",
+				Capacity:         resource.MustParse("100Gi"),
+				Free:             resource.MustParse("60Gi"),
+				IOUtilization:    20,
+			},
+		},
+	}
+	p, nodeInfo := newTestPluginWithNodeMetric(t, &args, node, nil, nodeMetricInfo)
+
+	newPVC := func(name, storageClassName, volumeName, request string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: pointer.String(storageClassName),
+				VolumeName:       volumeName,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse(request),
+					},
+				},
+			},
+		}
+	}
+	pvcStore := p.handle.SharedInformerFactory().Core().V1().PersistentVolumeClaims().Informer().GetStore()
+	for _, pvc := range []*corev1.PersistentVolumeClaim{
+		newPVC("small", "local-ssd", "", "20Gi"),
+		newPVC("large", "local-ssd", "", "80Gi"),
+		newPVC("bound", "local-ssd", "pv-1", "80Gi"),
+		newPVC("remote", "remote", "", "1Ti"),
+		newPVC("unknown", "local-hdd", "", "1Gi"),
+	} {
+		assert.NoError(t, pvcStore.Add(pvc))
+	}
+	newPod := func(claimName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-" + claimName, UID: uuid.NewUUID()},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("10"),
+							},
+							Limits: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("10"),
+							},
+						},
+					},
+				},
+				Volumes: []corev1.Volume{
+					{
+						Name: "data",
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	// the bound PVC and the PVC of a non-local storage class are not counted
+	assert.Nil(t, p.Filter(context.TODO(), framework.NewCycleState(), newPod("bound"), nodeInfo))
+	assert.Nil(t, p.Filter(context.TODO(), framework.NewCycleState(), newPod("remote"), nodeInfo))
+	assert.Nil(t, p.Filter(context.TODO(), framework.NewCycleState(), newPod("small"), nodeInfo))
+	status := p.Filter(context.TODO(), framework.NewCycleState(), newPod("large"), nodeInfo)
+	assert.True(t, framework.NewStatus(framework.Unschedulable, fmt.Sprintf(ErrReasonLocalStorageInsufficient, "local-ssd")).Equal(status))
+
+	// cpu score is (100-10)*100/100=90, the storage score is ((100-40-20)*100/100+(100-20))/2=60
+	score, status := p.Score(context.TODO(), framework.NewCycleState(), newPod("small"), node.Name)
+	assert.Nil(t, status)
+	assert.Equal(t, int64((90+60)/2), score)
+	// the pod without local storage is scored by the load only
+	score, status = p.Score(context.TODO(), framework.NewCycleState(), newPod("remote"), node.Name)
+	assert.Nil(t, status)
+	assert.Equal(t, int64(90), score)
+
+	// the storage class not reported by any node is not local
+	assert.Nil(t, p.Filter(context.TODO(), framework.NewCycleState(), newPod("unknown"), nodeInfo))
+
+	p.args.LocalStorageIOUtilizationThreshold = pointer.Int64(20)
+	status = p.Filter(context.TODO(), framework.NewCycleState(), newPod("small"), nodeInfo)
+	assert.True(t, framework.NewStatus(framework.Unschedulable, fmt.Sprintf(ErrReasonLocalStorageIOExceedThreshold, "local-ssd")).Equal(status))
+	p.args.LocalStorageIOUtilizationThreshold = pointer.Int64(80)
+
+	// the storage requested by the assigned pods is reserved until the next report
+	for i := 0; i < 3; i++ {
+		p.podAssignCache.assign(node.Name, newPod("small"))
+	}
+	status = p.Filter(context.TODO(), framework.NewCycleState(), newPod("small"), nodeInfo)
+	assert.True(t, framework.NewStatus(framework.Unschedulable, fmt.Sprintf(ErrReasonLocalStorageInsufficient, "local-ssd")).Equal(status))
+}
+
+func TestScoreAggregatedNodeUsage(t *testing.T) {
+	newUsage := func(cpu string) slov1alpha1.ResourceMap {
+		return slov1alpha1.ResourceMap{
+			ResourceList: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse(cpu),
+			},
+		}
+	}
+	nodeMetric := &slov1alpha1.NodeMetric{
+		Status: slov1alpha1.NodeMetricStatus{
+			NodeMetric: &slov1alpha1.NodeMetricInfo{
+				NodeUsage: newUsage("1"),
+				AggregatedNodeUsages: []slov1alpha1.AggregatedUsage{
+					{
+						Usage: map[slov1alpha1.AggregationType]slov1alpha1.ResourceMap{
+							slov1alpha1.P95: newUsage("5"),
+							slov1alpha1.P99: newUsage("6"),
+						},
+						Duration: metav1.Duration{Duration: 5 * time.Minute},
+					},
+					{
+						Usage: map[slov1alpha1.AggregationType]slov1alpha1.ResourceMap{
+							slov1alpha1.P95: newUsage("7"),
+						},
+						Duration: metav1.Duration{Duration: 30 * time.Minute},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		aggregated *config.LoadAwareSchedulingAggregatedArgs
+		want       string
+	}{
+		{
+			name: "latest usage by default",
+			want: "1",
+		},
+		{
+			name: "p95 of the longest duration",
+			aggregated: &config.LoadAwareSchedulingAggregatedArgs{
+				ScoreAggregationType: slov1alpha1.P95,
+			},
+			want: "7",
+		},
+		{
+			name: "p95 of the specified duration",
+			aggregated: &config.LoadAwareSchedulingAggregatedArgs{
+				ScoreAggregationType:    slov1alpha1.P95,
+				ScoreAggregatedDuration: metav1.Duration{Duration: 5 * time.Minute},
+			},
+			want: "5",
+		},
+		{
+			name: "p99 of the longest duration reporting it",
+			aggregated: &config.LoadAwareSchedulingAggregatedArgs{
+				ScoreAggregationType: slov1alpha1.P99,
+			},
+			want: "6",
+		},
+		{
+			name: "fall back to latest usage if the duration is not reported",
+			aggregated: &config.LoadAwareSchedulingAggregatedArgs{
+				ScoreAggregationType:    slov1alpha1.P95,
+				ScoreAggregatedDuration: metav1.Duration{Duration: time.Hour},
+			},
+			want: "1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Plugin{
+				args: &config.LoadAwareSchedulingArgs{
+					Aggregated: tt.aggregated,
+				},
+			}
+			usage := p.getScoreNodeUsage(nodeMetric)
+			want := resource.MustParse(tt.want)
+			assert.Equal(t, want.MilliValue(), usage.Cpu().MilliValue())
+		})
+	}
+}
+
+func TestUsageThresholdProfiles(t *testing.T) {
+	var v1beta2args v1beta2.LoadAwareSchedulingArgs
+	v1beta2.SetDefaults_LoadAwareSchedulingArgs(&v1beta2args)
+	v1beta2args.UsageThresholds = map[corev1.ResourceName]int64{
+		corev1.ResourceCPU: 65,
+	}
+	v1beta2args.UsageThresholdProfiles = []v1beta2.LoadAwareSchedulingUsageThresholdProfile{
+		{
+			Namespaces:      []string{"default"},
+			PriorityClasses: []string{string(extension.PriorityBatch)},
+			UsageThresholds: map[corev1.ResourceName]int64{
+				corev1.ResourceCPU: 85,
+			},
+			ResourceWeights: map[corev1.ResourceName]int64{
+				corev1.ResourceCPU: 1,
+			},
+		},
+	}
+	var args config.LoadAwareSchedulingArgs
+	assert.NoError(t, v1beta2.Convert_v1beta2_LoadAwareSchedulingArgs_To_config_LoadAwareSchedulingArgs(&v1beta2args, &args, nil))
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node-1"},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100"),
+				corev1.ResourceMemory: resource.MustParse("100Gi"),
+			},
+		},
+	}
+	p, nodeInfo := newTestPluginWithNodeMetric(t, &args, node, nil, &slov1alpha1.NodeMetricInfo{
+		NodeUsage: slov1alpha1.ResourceMap{
+			ResourceList: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("70"),
+				corev1.ResourceMemory: resource.MustParse("10Gi"),
+			},
+		},
+	})
+
+	prodPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "prod"},
+		Spec:       corev1.PodSpec{Priority: pointer.Int32Ptr(extension.PriorityProdValueMax)},
+	}
+	batchPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "batch"},
+		Spec:       corev1.PodSpec{Priority: pointer.Int32Ptr(extension.PriorityBatchValueMax)},
+	}
+	otherBatchPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "batch"},
+		Spec:       corev1.PodSpec{Priority: pointer.Int32Ptr(extension.PriorityBatchValueMax)},
+	}
+
+	assert.Nil(t, p.getUsageThresholdProfile(prodPod))
+	assert.Nil(t, p.getUsageThresholdProfile(otherBatchPod))
+	assert.NotNil(t, p.getUsageThresholdProfile(batchPod))
+
+	// 70% cpu usage exceeds the default threshold but not the batch one
+	status := p.Filter(context.TODO(), framework.NewCycleState(), prodPod, nodeInfo)
+	assert.True(t, framework.NewStatus(framework.Unschedulable, fmt.Sprintf(ErrReasonUsageExceedThreshold, corev1.ResourceCPU)).Equal(status))
+	status = p.Filter(context.TODO(), framework.NewCycleState(), otherBatchPod, nodeInfo)
+	assert.False(t, status.IsSuccess())
+	assert.Nil(t, p.Filter(context.TODO(), framework.NewCycleState(), batchPod, nodeInfo))
+
+	// the batch pod is scored by cpu only
+	assert.Equal(t, map[corev1.ResourceName]int64{corev1.ResourceCPU: 1}, p.getResourceWeights(batchPod))
+	assert.Equal(t, args.ResourceWeights, p.getResourceWeights(prodPod))
+	score, status := p.Score(context.TODO(), framework.NewCycleState(), batchPod, node.Name)
+	assert.Nil(t, status)
+	assert.Equal(t, int64(29), score)
+}
+
+func TestFilterWithAssignedPods(t *testing.T) {
+	var v1beta2args v1beta2.LoadAwareSchedulingArgs
+	v1beta2.SetDefaults_LoadAwareSchedulingArgs(&v1beta2args)
+	v1beta2args.UsageThresholds = map[corev1.ResourceName]int64{
+		corev1.ResourceCPU: 65,
+	}
+	var args config.LoadAwareSchedulingArgs
+	assert.NoError(t, v1beta2.Convert_v1beta2_LoadAwareSchedulingArgs_To_config_LoadAwareSchedulingArgs(&v1beta2args, &args, nil))
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node-1"},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100"),
+				corev1.ResourceMemory: resource.MustParse("100Gi"),
+			},
+		},
+	}
+	p, nodeInfo := newTestPluginWithNodeMetric(t, &args, node, nil, &slov1alpha1.NodeMetricInfo{
+		NodeUsage: slov1alpha1.ResourceMap{
+			ResourceList: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("60"),
+			},
+		},
+	})
+	assert.Nil(t, p.Filter(context.TODO(), framework.NewCycleState(), &corev1.Pod{}, nodeInfo))
+
+	// the assigned pod is estimated to use 8*85%=6.8 cores, which is not reported yet
+	assignedPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "assigned", UID: uuid.NewUUID()},
+		Spec: corev1.PodSpec{
+			NodeName: node.Name,
+			Containers: []corev1.Container{
+				{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("8"),
+						},
+						// the estimated usage is capped by the limit
+						Limits: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("8"),
+						},
+					},
+				},
+			},
+		},
+	}
+	p.podAssignCache.assign(node.Name, assignedPod)
+	status := p.Filter(context.TODO(), framework.NewCycleState(), &corev1.Pod{}, nodeInfo)
+	assert.True(t, framework.NewStatus(framework.Unschedulable, fmt.Sprintf(ErrReasonUsageExceedThreshold, corev1.ResourceCPU)).Equal(status))
+
+	p.podAssignCache.unAssign(node.Name, assignedPod)
+	assert.Nil(t, p.Filter(context.TODO(), framework.NewCycleState(), &corev1.Pod{}, nodeInfo))
+}
+
+func TestGPUUtilization(t *testing.T) {
+	var v1beta2args v1beta2.LoadAwareSchedulingArgs
+	v1beta2.SetDefaults_LoadAwareSchedulingArgs(&v1beta2args)
+	v1beta2args.ResourceWeights = map[corev1.ResourceName]int64{
+		corev1.ResourceCPU: 1,
+		extension.GPUCore:  1,
+	}
+	v1beta2args.UsageThresholds = map[corev1.ResourceName]int64{
+		extension.GPUCore: 70,
+	}
+	v1beta2args.EstimatedScalingFactors = map[corev1.ResourceName]int64{
+		corev1.ResourceCPU: 85,
+		extension.GPUCore:  100,
+	}
+	var args config.LoadAwareSchedulingArgs
+	assert.NoError(t, v1beta2.Convert_v1beta2_LoadAwareSchedulingArgs_To_config_LoadAwareSchedulingArgs(&v1beta2args, &args, nil))
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node-1"},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:       resource.MustParse("100"),
+				corev1.ResourceMemory:    resource.MustParse("100Gi"),
+				extension.GPUCore:        resource.MustParse("200"),
+				extension.GPUMemoryRatio: resource.MustParse("200"),
+			},
+		},
+	}
+	p, nodeInfo := newTestPluginWithNodeMetric(t, &args, node, nil, &slov1alpha1.NodeMetricInfo{
+		NodeUsage: slov1alpha1.ResourceMap{
+			ResourceList: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("10"),
+				extension.GPUCore:  resource.MustParse("40"),
+			},
+		},
+	})
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu-pod", UID: uuid.NewUUID()},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							extension.NvidiaGPU: resource.MustParse("1"),
+						},
+						Limits: corev1.ResourceList{
+							extension.NvidiaGPU: resource.MustParse("1"),
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.Nil(t, p.Filter(context.TODO(), framework.NewCycleState(), pod, nodeInfo))
+
+	// the whole GPU is estimated as 100 gpu-core, so the gpu score is (200-40-100)*100/200=30,
+	// and the cpu score is (100-10-0.25)*100/100=89
+	score, status := p.Score(context.TODO(), framework.NewCycleState(), pod, node.Name)
+	assert.Nil(t, status)
+	assert.Equal(t, int64((89+30)/2), score)
+
+	// 40+100 of 200 gpu-core exceeds the threshold after the pod is assigned
+	p.podAssignCache.assign(node.Name, pod)
+	status = p.Filter(context.TODO(), framework.NewCycleState(), &corev1.Pod{}, nodeInfo)
+	assert.True(t, framework.NewStatus(framework.Unschedulable, fmt.Sprintf(ErrReasonUsageExceedThreshold, extension.GPUCore)).Equal(status))
+}
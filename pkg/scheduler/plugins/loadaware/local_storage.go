@@ -0,0 +1,217 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadaware
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+const (
+	ErrReasonLocalStorageNotFound          = "node(s) local storage %s not found"
+	ErrReasonLocalStorageInsufficient      = "node(s) insufficient local storage %s"
+	ErrReasonLocalStorageIOExceedThreshold = "node(s) local storage %s IO utilization exceed threshold"
+
+	localStorageStateKey = Name + "/localStorage"
+)
+
+// localStorageState records the local storage requested by the unbound PVCs of the pod, which is calculated
+// once in a scheduling cycle.
+type localStorageState struct {
+	// requests is the requested capacity in bytes by the storage class name
+	requests map[string]int64
+	// localStorageClasses is the storage classes reported by the NodeMetrics
+	localStorageClasses map[string]bool
+}
+
+func (s *localStorageState) Clone() framework.StateData {
+	return s
+}
+
+func (p *Plugin) getLocalStorageState(cycleState *framework.CycleState, pod *corev1.Pod) *localStorageState {
+	if data, err := cycleState.Read(localStorageStateKey); err == nil {
+		if s, ok := data.(*localStorageState); ok {
+			return s
+		}
+	}
+	s := &localStorageState{}
+	if hasPVC(pod) {
+		s.localStorageClasses = p.listLocalStorageClasses()
+		s.requests = p.getPodLocalStorageRequests(pod, s.localStorageClasses, true)
+	}
+	cycleState.Write(localStorageStateKey, s)
+	return s
+}
+
+func hasPVC(pod *corev1.Pod) bool {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Plugin) listLocalStorageClasses() map[string]bool {
+	nodeMetrics, err := p.nodeMetricLister.List(labels.Everything())
+	if err != nil {
+		klog.V(5).ErrorS(err, "failed to list NodeMetrics")
+		return nil
+	}
+	classes := map[string]bool{}
+	for _, nodeMetric := range nodeMetrics {
+		if nodeMetric.Status.NodeMetric == nil {
+			continue
+		}
+		for _, storage := range nodeMetric.Status.NodeMetric.LocalStorages {
+			classes[storage.StorageClassName] = true
+		}
+	}
+	return classes
+}
+
+// getPodLocalStorageRequests sums the storage requested by the PVCs of the pod whose storage class is a local
+// storage class. If onlyUnbound is true, the bound PVCs are skipped since their volumes are already provisioned.
+func (p *Plugin) getPodLocalStorageRequests(pod *corev1.Pod, localStorageClasses map[string]bool, onlyUnbound bool) map[string]int64 {
+	if len(localStorageClasses) == 0 || p.pvcLister == nil {
+		return nil
+	}
+	var requests map[string]int64
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		pvc, err := p.pvcLister.PersistentVolumeClaims(pod.Namespace).Get(volume.PersistentVolumeClaim.ClaimName)
+		if err != nil {
+			klog.V(5).ErrorS(err, "failed to get PVC", "pod", klog.KObj(pod), "pvc", volume.PersistentVolumeClaim.ClaimName)
+			continue
+		}
+		if pvc.Spec.StorageClassName == nil || !localStorageClasses[*pvc.Spec.StorageClassName] {
+			continue
+		}
+		if onlyUnbound && pvc.Spec.VolumeName != "" {
+			continue
+		}
+		quantity := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		if requests == nil {
+			requests = map[string]int64{}
+		}
+		requests[*pvc.Spec.StorageClassName] += quantity.Value()
+	}
+	return requests
+}
+
+// estimatedAssignedPodLocalStorage returns the local storage requested by the pods assigned to the node
+// but not reflected in the NodeMetric yet.
+func (p *Plugin) estimatedAssignedPodLocalStorage(nodeName string, nodeMetric *slov1alpha1.NodeMetric, localStorageClasses map[string]bool) map[string]int64 {
+	estimated := map[string]int64{}
+	nodeMetricReportInterval := getNodeMetricReportInterval(nodeMetric)
+	p.podAssignCache.lock.RLock()
+	defer p.podAssignCache.lock.RUnlock()
+	for _, assignInfo := range p.podAssignCache.podInfoItems[nodeName] {
+		if assignInfo.timestamp.After(nodeMetric.Status.UpdateTime.Time) ||
+			assignInfo.timestamp.Before(nodeMetric.Status.UpdateTime.Time) &&
+				nodeMetric.Status.UpdateTime.Sub(assignInfo.timestamp) < nodeMetricReportInterval {
+			for class, value := range p.getPodLocalStorageRequests(assignInfo.pod, localStorageClasses, false) {
+				estimated[class] += value
+			}
+		}
+	}
+	return estimated
+}
+
+func getLocalStorageInfo(nodeMetric *slov1alpha1.NodeMetric, storageClassName string) *slov1alpha1.LocalStorageInfo {
+	if nodeMetric.Status.NodeMetric == nil {
+		return nil
+	}
+	for i := range nodeMetric.Status.NodeMetric.LocalStorages {
+		if nodeMetric.Status.NodeMetric.LocalStorages[i].StorageClassName == storageClassName {
+			return &nodeMetric.Status.NodeMetric.LocalStorages[i]
+		}
+	}
+	return nil
+}
+
+func (p *Plugin) filterLocalStorage(cycleState *framework.CycleState, pod *corev1.Pod, nodeName string, nodeMetric *slov1alpha1.NodeMetric) *framework.Status {
+	s := p.getLocalStorageState(cycleState, pod)
+	if len(s.requests) == 0 {
+		return nil
+	}
+	var assigned map[string]int64
+	if nodeMetric.Status.UpdateTime != nil {
+		assigned = p.estimatedAssignedPodLocalStorage(nodeName, nodeMetric, s.localStorageClasses)
+	}
+	threshold := p.localStorageIOUtilizationThreshold()
+	for class, request := range s.requests {
+		storage := getLocalStorageInfo(nodeMetric, class)
+		if storage == nil {
+			return framework.NewStatus(framework.UnschedulableAndUnresolvable, fmt.Sprintf(ErrReasonLocalStorageNotFound, class))
+		}
+		if storage.Free.Value()-assigned[class] < request {
+			return framework.NewStatus(framework.Unschedulable, fmt.Sprintf(ErrReasonLocalStorageInsufficient, class))
+		}
+		if threshold > 0 && storage.IOUtilization >= threshold {
+			return framework.NewStatus(framework.Unschedulable, fmt.Sprintf(ErrReasonLocalStorageIOExceedThreshold, class))
+		}
+	}
+	return nil
+}
+
+// scoreLocalStorage scores the node by the free capacity and the IO headroom of the requested local storages,
+// the second return value is false if the pod requests no local storage.
+func (p *Plugin) scoreLocalStorage(cycleState *framework.CycleState, pod *corev1.Pod, nodeName string, nodeMetric *slov1alpha1.NodeMetric) (int64, bool) {
+	s := p.getLocalStorageState(cycleState, pod)
+	if len(s.requests) == 0 {
+		return 0, false
+	}
+	assigned := p.estimatedAssignedPodLocalStorage(nodeName, nodeMetric, s.localStorageClasses)
+	var score int64
+	for class, request := range s.requests {
+		storage := getLocalStorageInfo(nodeMetric, class)
+		if storage == nil {
+			continue
+		}
+		capacity := storage.Capacity.Value()
+		used := capacity - storage.Free.Value() + assigned[class] + request
+		ioScore := framework.MaxNodeScore - storage.IOUtilization
+		if ioScore < 0 {
+			ioScore = 0
+		}
+		score += (leastRequestedScore(used, capacity) + ioScore) / 2
+	}
+	return score / int64(len(s.requests)), true
+}
+
+func (p *Plugin) localStorageIOUtilizationThreshold() int64 {
+	if p.args.LocalStorageIOUtilizationThreshold == nil {
+		return 0
+	}
+	return *p.args.LocalStorageIOUtilizationThreshold
+}
+
+func (p *Plugin) localStorageWeight() int64 {
+	if p.args.LocalStorageWeight == nil {
+		return 0
+	}
+	return *p.args.LocalStorageWeight
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadaware
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+)
+
+// MetricProvider provides the node usage from the custom metric source besides the NodeMetric,
+// e.g. the saturation of the application QPS collected by Prometheus.
+type MetricProvider interface {
+	Name() string
+	// ListNodeUsages returns the usage of all the nodes by the node name.
+	ListNodeUsages(ctx context.Context) (map[string]corev1.ResourceList, error)
+}
+
+// MetricProviderFactory creates a MetricProvider by the args.
+type MetricProviderFactory func(args *config.LoadAwareSchedulingMetricProviderArgs) (MetricProvider, error)
+
+var (
+	metricProviderLock      sync.RWMutex
+	metricProviderFactories = map[config.MetricProviderType]MetricProviderFactory{
+		config.MetricProviderPrometheus: newPrometheusMetricProvider,
+	}
+)
+
+// RegisterMetricProvider registers a custom MetricProvider, which can be selected by the Type of the
+// MetricProvider args. It overrides the registered provider with the same type.
+func RegisterMetricProvider(providerType config.MetricProviderType, factory MetricProviderFactory) {
+	metricProviderLock.Lock()
+	defer metricProviderLock.Unlock()
+	metricProviderFactories[providerType] = factory
+}
+
+// NewMetricProvider creates the registered MetricProvider by the type of the args.
+func NewMetricProvider(args *config.LoadAwareSchedulingMetricProviderArgs) (MetricProvider, error) {
+	metricProviderLock.RLock()
+	factory, ok := metricProviderFactories[args.Type]
+	metricProviderLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("metric provider %s is not registered", args.Type)
+	}
+	return factory(args)
+}
+
+const (
+	defaultMetricProviderCacheInterval = 30 * time.Second
+	defaultMetricProviderTimeout       = 10 * time.Second
+)
+
+// cachedMetricProvider refreshes the node usage from the MetricProvider periodically, so the Filter and Score
+// never wait for the metric source. The cached usage is dropped if it is not refreshed for a few intervals.
+type cachedMetricProvider struct {
+	provider   MetricProvider
+	interval   time.Duration
+	lock       sync.RWMutex
+	usages     map[string]corev1.ResourceList
+	updateTime time.Time
+}
+
+func newCachedMetricProvider(provider MetricProvider, interval time.Duration) *cachedMetricProvider {
+	if interval <= 0 {
+		interval = defaultMetricProviderCacheInterval
+	}
+	return &cachedMetricProvider{
+		provider: provider,
+		interval: interval,
+	}
+}
+
+func (c *cachedMetricProvider) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultMetricProviderTimeout)
+	defer cancel()
+	usages, err := c.provider.ListNodeUsages(ctx)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list node usages from metric provider", "provider", c.provider.Name())
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.usages = usages
+	c.updateTime = timeNowFn()
+}
+
+// getNodeUsage returns the cached usage of the node, nil if not provided or expired.
+func (c *cachedMetricProvider) getNodeUsage(nodeName string) corev1.ResourceList {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if timeNowFn().Sub(c.updateTime) > 3*c.interval {
+		return nil
+	}
+	return c.usages[nodeName]
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadaware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+)
+
+// prometheusMetricProvider queries the node usage by the instant queries of the Prometheus HTTP API,
+// each query should return a vector with one sample per node identified by the nodeLabel.
+type prometheusMetricProvider struct {
+	client    *http.Client
+	address   string
+	nodeLabel string
+	queries   map[corev1.ResourceName]string
+}
+
+func newPrometheusMetricProvider(args *config.LoadAwareSchedulingMetricProviderArgs) (MetricProvider, error) {
+	if _, err := url.Parse(args.Address); err != nil {
+		return nil, fmt.Errorf("invalid prometheus address %s, err: %v", args.Address, err)
+	}
+	nodeLabel := args.NodeLabel
+	if nodeLabel == "" {
+		nodeLabel = "node"
+	}
+	return &prometheusMetricProvider{
+		client:    &http.Client{Timeout: defaultMetricProviderTimeout},
+		address:   strings.TrimSuffix(args.Address, "/"),
+		nodeLabel: nodeLabel,
+		queries:   args.Queries,
+	}, nil
+}
+
+func (p *prometheusMetricProvider) Name() string {
+	return string(config.MetricProviderPrometheus)
+}
+
+type prometheusQueryResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Data      struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			// Value is the pair of the timestamp and the sample value in string
+			Value []interface{} `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+func (p *prometheusMetricProvider) ListNodeUsages(ctx context.Context) (map[string]corev1.ResourceList, error) {
+	usages := map[string]corev1.ResourceList{}
+	for resourceName, query := range p.queries {
+		samples, err := p.query(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s, err: %v", resourceName, err)
+		}
+		for nodeName, value := range samples {
+			usage := usages[nodeName]
+			if usage == nil {
+				usage = corev1.ResourceList{}
+				usages[nodeName] = usage
+			}
+			usage[resourceName] = *resource.NewMilliQuantity(int64(math.Round(value*1000)), resource.DecimalSI)
+		}
+	}
+	return usages, nil
+}
+
+// query returns the sample value of each node.
+func (p *prometheusMetricProvider) query(ctx context.Context, query string) (map[string]float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.address+"/api/v1/query?"+url.Values{"query": []string{query}}.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response prometheusQueryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode the response with status code %d, err: %v", resp.StatusCode, err)
+	}
+	if response.Status != "success" {
+		return nil, fmt.Errorf("%s: %s", response.ErrorType, response.Error)
+	}
+	if response.Data.ResultType != "vector" {
+		return nil, fmt.Errorf("unexpected result type %s, want vector", response.Data.ResultType)
+	}
+
+	samples := map[string]float64{}
+	for _, result := range response.Data.Result {
+		nodeName := result.Metric[p.nodeLabel]
+		if nodeName == "" || len(result.Value) != 2 {
+			continue
+		}
+		raw, ok := result.Value[1].(string)
+		if !ok {
+			continue
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			continue
+		}
+		samples[nodeName] = value
+	}
+	return samples, nil
+}
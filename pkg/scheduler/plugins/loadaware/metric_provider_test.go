@@ -0,0 +1,190 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadaware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config/v1beta2"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+const testQPSSaturation corev1.ResourceName = "qps-saturation"
+
+func newTestPrometheusServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query", r.URL.Path)
+		var result string
+		switch r.URL.Query().Get("query") {
+		case "qps_saturation":
+			result = `[{"metric":{"node":"test-node-1"},"value":[1690000000.1,"70.5"]},{"metric":{"node":"test-node-2"},"value":[1690000000.1,"NaN"]}]`
+		case "cpu_usage":
+			result = `[{"metric":{"node":"test-node-1"},"value":[1690000000.1,"1.5"]},{"metric":{"instance":"x"},"value":[1690000000.1,"3"]}]`
+		default:
+			fmt.Fprint(w, `{"status":"error","errorType":"bad_data","error":"parse error"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":%s}}`, result)
+	}))
+}
+
+func TestPrometheusMetricProvider(t *testing.T) {
+	server := newTestPrometheusServer(t)
+	defer server.Close()
+
+	provider, err := NewMetricProvider(&config.LoadAwareSchedulingMetricProviderArgs{
+		Type:    config.MetricProviderPrometheus,
+		Address: server.URL + "/",
+		Queries: map[corev1.ResourceName]string{
+			testQPSSaturation:  "qps_saturation",
+			corev1.ResourceCPU: "cpu_usage",
+		},
+	})
+	assert.NoError(t, err)
+	usages, err := provider.ListNodeUsages(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(usages))
+	saturation := usages["test-node-1"][testQPSSaturation]
+	assert.Equal(t, int64(70500), saturation.MilliValue())
+	cpu := usages["test-node-1"][corev1.ResourceCPU]
+	assert.Equal(t, int64(1500), cpu.MilliValue())
+
+	provider, err = NewMetricProvider(&config.LoadAwareSchedulingMetricProviderArgs{
+		Type:    config.MetricProviderPrometheus,
+		Address: server.URL,
+		Queries: map[corev1.ResourceName]string{
+			testQPSSaturation: "invalid",
+		},
+	})
+	assert.NoError(t, err)
+	_, err = provider.ListNodeUsages(context.TODO())
+	assert.Error(t, err)
+
+	_, err = NewMetricProvider(&config.LoadAwareSchedulingMetricProviderArgs{Type: "not-exist"})
+	assert.Error(t, err)
+}
+
+type testMetricProvider struct {
+	usages map[string]corev1.ResourceList
+	err    error
+}
+
+func (p *testMetricProvider) Name() string {
+	return "Test"
+}
+
+func (p *testMetricProvider) ListNodeUsages(ctx context.Context) (map[string]corev1.ResourceList, error) {
+	return p.usages, p.err
+}
+
+func TestCachedMetricProvider(t *testing.T) {
+	now := time.Now()
+	timeNowFn = func() time.Time {
+		return now
+	}
+	defer func() {
+		timeNowFn = time.Now
+	}()
+
+	RegisterMetricProvider("Test", func(args *config.LoadAwareSchedulingMetricProviderArgs) (MetricProvider, error) {
+		return &testMetricProvider{}, nil
+	})
+	provider, err := NewMetricProvider(&config.LoadAwareSchedulingMetricProviderArgs{Type: "Test"})
+	assert.NoError(t, err)
+	testProvider := provider.(*testMetricProvider)
+	testProvider.usages = map[string]corev1.ResourceList{
+		"test-node-1": {testQPSSaturation: resource.MustParse("70")},
+	}
+	cached := newCachedMetricProvider(provider, time.Minute)
+	assert.Nil(t, cached.getNodeUsage("test-node-1"))
+
+	cached.refresh()
+	assert.Equal(t, testProvider.usages["test-node-1"], cached.getNodeUsage("test-node-1"))
+
+	// the stale usage is kept on failure until it expires
+	testProvider.err = fmt.Errorf("unavailable")
+	cached.refresh()
+	assert.NotNil(t, cached.getNodeUsage("test-node-1"))
+	now = now.Add(4 * time.Minute)
+	assert.Nil(t, cached.getNodeUsage("test-node-1"))
+}
+
+func TestFilterAndScoreWithMetricProvider(t *testing.T) {
+	var v1beta2args v1beta2.LoadAwareSchedulingArgs
+	v1beta2.SetDefaults_LoadAwareSchedulingArgs(&v1beta2args)
+	v1beta2args.ResourceWeights = map[corev1.ResourceName]int64{
+		corev1.ResourceCPU: 1,
+		testQPSSaturation:  1,
+	}
+	v1beta2args.UsageThresholds = map[corev1.ResourceName]int64{
+		testQPSSaturation: 80,
+	}
+	v1beta2args.EstimatedScalingFactors = map[corev1.ResourceName]int64{
+		corev1.ResourceCPU: 85,
+		testQPSSaturation:  100,
+	}
+	var args config.LoadAwareSchedulingArgs
+	assert.NoError(t, v1beta2.Convert_v1beta2_LoadAwareSchedulingArgs_To_config_LoadAwareSchedulingArgs(&v1beta2args, &args, nil))
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node-1"},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100"),
+				corev1.ResourceMemory: resource.MustParse("100Gi"),
+			},
+		},
+	}
+	p, nodeInfo := newTestPluginWithNodeMetric(t, &args, node, nil, &slov1alpha1.NodeMetricInfo{
+		NodeUsage: slov1alpha1.ResourceMap{
+			ResourceList: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("10"),
+			},
+		},
+	})
+	provider := &testMetricProvider{
+		usages: map[string]corev1.ResourceList{
+			node.Name: {testQPSSaturation: resource.MustParse("70")},
+		},
+	}
+	p.metricProvider = newCachedMetricProvider(provider, time.Minute)
+	p.metricProvider.refresh()
+
+	// the saturation is in percentage since the node has no allocatable of it
+	assert.Nil(t, p.Filter(context.TODO(), framework.NewCycleState(), &corev1.Pod{}, nodeInfo))
+	// the cpu score is (100-10-0.25)*100/100=89, and the saturation score is 100-70=30
+	score, status := p.Score(context.TODO(), framework.NewCycleState(), &corev1.Pod{}, node.Name)
+	assert.Nil(t, status)
+	assert.Equal(t, int64((89+30)/2), score)
+
+	provider.usages[node.Name] = corev1.ResourceList{testQPSSaturation: resource.MustParse("85")}
+	p.metricProvider.refresh()
+	status = p.Filter(context.TODO(), framework.NewCycleState(), &corev1.Pod{}, nodeInfo)
+	assert.True(t, framework.NewStatus(framework.Unschedulable, fmt.Sprintf(ErrReasonUsageExceedThreshold, testQPSSaturation)).Equal(status))
+}
@@ -37,6 +37,9 @@ type podAssignCache struct {
 	// podInfoItems stores podAssignInfo according to each node.
 	// podAssignInfo is indexed using the Pod's types.UID
 	podInfoItems map[string]map[types.UID]*podAssignInfo
+	// churnEvents stores the time of the pods started or stopped on each node within the churnWindow
+	churnEvents map[string][]time.Time
+	churnWindow time.Duration
 }
 
 type podAssignInfo struct {
@@ -47,6 +50,8 @@ type podAssignInfo struct {
 func newPodAssignCache() *podAssignCache {
 	return &podAssignCache{
 		podInfoItems: map[string]map[types.UID]*podAssignInfo{},
+		churnEvents:  map[string][]time.Time{},
+		churnWindow:  DefaultPodChurnWindow,
 	}
 }
 
@@ -61,6 +66,9 @@ func (p *podAssignCache) assign(nodeName string, pod *corev1.Pod) {
 		m = make(map[types.UID]*podAssignInfo)
 		p.podInfoItems[nodeName] = m
 	}
+	if _, ok := m[pod.UID]; !ok {
+		p.recordChurnLocked(nodeName)
+	}
 	m[pod.UID] = &podAssignInfo{
 		timestamp: timeNowFn(),
 		pod:       pod,
@@ -73,12 +81,43 @@ func (p *podAssignCache) unAssign(nodeName string, pod *corev1.Pod) {
 	}
 	p.lock.Lock()
 	defer p.lock.Unlock()
+	if _, ok := p.podInfoItems[nodeName][pod.UID]; ok {
+		p.recordChurnLocked(nodeName)
+	}
 	delete(p.podInfoItems[nodeName], pod.UID)
 	if len(p.podInfoItems[nodeName]) == 0 {
 		delete(p.podInfoItems, nodeName)
 	}
 }
 
+func (p *podAssignCache) recordChurnLocked(nodeName string) {
+	if p.churnEvents == nil {
+		p.churnEvents = map[string][]time.Time{}
+	}
+	now := timeNowFn()
+	events := p.churnEvents[nodeName]
+	// the events are in time order, drop the expired ones
+	i := 0
+	for i < len(events) && now.Sub(events[i]) >= p.churnWindow {
+		i++
+	}
+	p.churnEvents[nodeName] = append(events[i:], now)
+}
+
+// churn returns the number of pods started or stopped on the node within the churnWindow.
+func (p *podAssignCache) churn(nodeName string) int64 {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	now := timeNowFn()
+	var count int64
+	for _, t := range p.churnEvents[nodeName] {
+		if now.Sub(t) < p.churnWindow {
+			count++
+		}
+	}
+	return count
+}
+
 func (p *podAssignCache) OnAdd(obj interface{}) {
 	pod, ok := obj.(*corev1.Pod)
 	if !ok {
@@ -21,6 +21,7 @@ import (
 	"sort"
 
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	schedulingconfig "github.com/koordinator-sh/koordinator/apis/scheduling/config"
 )
@@ -44,6 +45,15 @@ func takeCPUs(
 	}
 
 	fullPCPUs := cpuBindPolicy == schedulingconfig.CPUBindPolicyFullPCPUs
+	// Spread the CPUs across all the NUMA Nodes if possible, otherwise pack them as the LeastAllocated strategy,
+	// see freeCoresInNode and freeCPUsInNode.
+	if numaAllocatedStrategy == schedulingconfig.NUMADistributeEvenly {
+		if cpus := acc.distributeEvenly(fullPCPUs); len(cpus) > 0 {
+			acc.take(cpus...)
+			return acc.result, nil
+		}
+	}
+
 	if fullPCPUs || acc.topology.CPUsPerCore() == 1 {
 		// According to the NUMA allocation strategy,
 		// select the NUMA Node with the most remaining amount or the least amount remaining
@@ -736,6 +746,76 @@ func (a *cpuAccumulator) sortCPUsByRefCount(cpus []int) {
 	})
 }
 
+// freeCPUsForDistribution returns the free CPUs of each NUMA Node to distribute, and the number of the CPUs taken
+// each time, which is a physical core if fullPCPUs.
+func (a *cpuAccumulator) freeCPUsForDistribution(fullPCPUs bool, filterExclusive bool) ([][]int, int) {
+	if fullPCPUs {
+		return a.freeCoresInNode(true, filterExclusive), a.topology.CPUsPerCore()
+	}
+	cpusInNodes := a.freeCPUsInNode(filterExclusive)
+	for i := range cpusInNodes {
+		cpusInNodes[i] = a.spreadCPUs(cpusInNodes[i])
+	}
+	return cpusInNodes, 1
+}
+
+// distributeEvenly selects the CPUs from all the NUMA Nodes in turn, one physical core (FullPCPUs) or one CPU
+// at a time, so that the Pod uses the memory bandwidth of all the NUMA Nodes. It returns nil if the free CPUs
+// are not enough.
+func (a *cpuAccumulator) distributeEvenly(fullPCPUs bool) []int {
+	for _, filterExclusive := range []bool{true, false} {
+		cpusInNodes, step := a.freeCPUsForDistribution(fullPCPUs, filterExclusive)
+		if a.numCPUsNeeded%step != 0 {
+			return nil
+		}
+		var selected []int
+		offsets := make([]int, len(cpusInNodes))
+		for progressed := true; progressed && len(selected) < a.numCPUsNeeded; {
+			progressed = false
+			for i, cpus := range cpusInNodes {
+				if len(selected) >= a.numCPUsNeeded {
+					break
+				}
+				if offsets[i]+step > len(cpus) {
+					continue
+				}
+				selected = append(selected, cpus[offsets[i]:offsets[i]+step]...)
+				offsets[i] += step
+				progressed = true
+			}
+		}
+		if len(selected) == a.numCPUsNeeded {
+			return selected
+		}
+	}
+	return nil
+}
+
+// distributeEvenlyScore scores how evenly the requested CPUs can be spread across the NUMA Nodes, which is the
+// ratio of the NUMA Nodes having enough free CPUs for their share.
+func (a *cpuAccumulator) distributeEvenlyScore(fullPCPUs bool) int64 {
+	numNodes := a.topology.NumNodes
+	if numNodes == 0 || a.numCPUsNeeded <= 0 {
+		return 0
+	}
+	cpusInNodes, step := a.freeCPUsForDistribution(fullPCPUs, false)
+	share := (a.numCPUsNeeded + numNodes - 1) / numNodes
+	if remainder := share % step; remainder != 0 {
+		share += step - remainder
+	}
+	var freeCPUs, satisfiedNodes int
+	for _, cpus := range cpusInNodes {
+		freeCPUs += len(cpus)
+		if len(cpus) >= share {
+			satisfiedNodes++
+		}
+	}
+	if freeCPUs < a.numCPUsNeeded {
+		return 0
+	}
+	return int64(satisfiedNodes) * framework.MaxNodeScore / int64(numNodes)
+}
+
 func (a *cpuAccumulator) spreadCPUs(cpus []int) []int {
 	if len(cpus) <= a.topology.CPUsPerCore() {
 		return cpus
@@ -287,6 +287,74 @@ func TestTakeFullPCPUsWithNUMALeastAllocated(t *testing.T) {
 	}
 }
 
+func TestTakeCPUsWithNUMADistributeEvenly(t *testing.T) {
+	tests := []struct {
+		name          string
+		topology      *CPUTopology
+		allocatedCPUs CPUSet
+		cpuBindPolicy schedulingconfig.CPUBindPolicy
+		numCPUsNeeded int
+		wantResult    CPUSet
+	}{
+		{
+			name:          "distribute full cores across NUMA Nodes",
+			topology:      buildCPUTopologyForTest(2, 2, 4, 2),
+			cpuBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+			numCPUsNeeded: 8,
+			wantResult:    MustParse("0-1,8-9,16-17,24-25"),
+		},
+		{
+			name:          "distribute full cores from the most idle NUMA Nodes first",
+			topology:      buildCPUTopologyForTest(2, 2, 4, 2),
+			allocatedCPUs: MustParse("0-5"),
+			cpuBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+			numCPUsNeeded: 4,
+			wantResult:    MustParse("16-17,24-25"),
+		},
+		{
+			name:          "distribute full cores with exhausted NUMA Node",
+			topology:      buildCPUTopologyForTest(1, 2, 2, 2),
+			allocatedCPUs: MustParse("0-3"),
+			cpuBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+			numCPUsNeeded: 4,
+			wantResult:    MustParse("4-7"),
+		},
+		{
+			name:          "distribute spread cpus across NUMA Nodes",
+			topology:      buildCPUTopologyForTest(2, 2, 4, 2),
+			cpuBindPolicy: schedulingconfig.CPUBindPolicySpreadByPCPUs,
+			numCPUsNeeded: 4,
+			wantResult:    NewCPUSet(0, 8, 16, 24),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			availableCPUs := tt.topology.CPUDetails.CPUs().Difference(tt.allocatedCPUs)
+			allocatedCPUsDetails := tt.topology.CPUDetails.KeepOnly(tt.allocatedCPUs)
+			result, err := takeCPUs(
+				tt.topology, 1, availableCPUs, allocatedCPUsDetails,
+				tt.numCPUsNeeded, tt.cpuBindPolicy, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMADistributeEvenly)
+			assert.NoError(t, err)
+			assert.True(t, tt.wantResult.Equals(result), "expect: %s, but got: %s", tt.wantResult, result)
+		})
+	}
+}
+
+func TestDistributeEvenlyScore(t *testing.T) {
+	topology := buildCPUTopologyForTest(2, 2, 4, 2)
+	allocatedCPUs := MustParse("0-7")
+	availableCPUs := topology.CPUDetails.CPUs().Difference(allocatedCPUs)
+	acc := newCPUAccumulator(topology, 1, availableCPUs, topology.CPUDetails.KeepOnly(allocatedCPUs), 8,
+		schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMADistributeEvenly)
+	// 3 of the 4 NUMA Nodes have 2 free CPUs for their share
+	assert.Equal(t, int64(75), acc.distributeEvenlyScore(true))
+
+	acc = newCPUAccumulator(topology, 1, availableCPUs, topology.CPUDetails.KeepOnly(allocatedCPUs), 32,
+		schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMADistributeEvenly)
+	assert.Equal(t, int64(0), acc.distributeEvenlyScore(true))
+}
+
 func TestCPUSpreadByPCPUs(t *testing.T) {
 	topology := buildCPUTopologyForTest(2, 2, 4, 2)
 	acc := newCPUAccumulator(topology, 1, topology.CPUDetails.CPUs(), nil, 8, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated)
@@ -568,7 +636,7 @@ func TestTakeCPUsWithMaxRefCount(t *testing.T) {
 
 	// first pod request 4 CPUs
 	podUID := uuid.NewUUID()
-	availableCPUs, allocatedCPUsDetails := allocationState.getAvailableCPUs(cpuTopology, 2, NewCPUSet())
+	availableCPUs, allocatedCPUsDetails := allocationState.getAvailableCPUs(cpuTopology, 2, NewCPUSet(), nil)
 	result, err := takeCPUs(
 		cpuTopology, 2, availableCPUs, allocatedCPUsDetails,
 		4, schedulingconfig.CPUBindPolicyFullPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated)
@@ -578,7 +646,7 @@ func TestTakeCPUsWithMaxRefCount(t *testing.T) {
 
 	// second pod request 5 CPUs
 	podUID = uuid.NewUUID()
-	availableCPUs, allocatedCPUsDetails = allocationState.getAvailableCPUs(cpuTopology, 2, NewCPUSet())
+	availableCPUs, allocatedCPUsDetails = allocationState.getAvailableCPUs(cpuTopology, 2, NewCPUSet(), nil)
 	result, err = takeCPUs(
 		cpuTopology, 2, availableCPUs, allocatedCPUsDetails,
 		5, schedulingconfig.CPUBindPolicyFullPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated)
@@ -588,7 +656,7 @@ func TestTakeCPUsWithMaxRefCount(t *testing.T) {
 
 	// third pod request 4 cpus
 	podUID = uuid.NewUUID()
-	availableCPUs, allocatedCPUsDetails = allocationState.getAvailableCPUs(cpuTopology, 2, NewCPUSet())
+	availableCPUs, allocatedCPUsDetails = allocationState.getAvailableCPUs(cpuTopology, 2, NewCPUSet(), nil)
 	result, err = takeCPUs(
 		cpuTopology, 2, availableCPUs, allocatedCPUsDetails,
 		4, schedulingconfig.CPUBindPolicyFullPCPUs, schedulingconfig.CPUExclusivePolicyNone, schedulingconfig.NUMAMostAllocated)
@@ -19,8 +19,12 @@ package nodenumaresource
 import (
 	"sync"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 
+	"github.com/koordinator-sh/koordinator/apis/extension"
 	schedulingconfig "github.com/koordinator-sh/koordinator/apis/scheduling/config"
 )
 
@@ -29,6 +33,15 @@ type cpuAllocation struct {
 	nodeName      string
 	allocatedPods map[types.UID]CPUSet
 	allocatedCPUs CPUDetails
+	// activeWindows records the ActiveWindows of the LSR Pods which only hold the CPUs in the windows
+	activeWindows map[types.UID]extension.ActiveWindows
+	// allocatedNUMAResources records the memory and hugepages of the Pods bound to the NUMA Nodes
+	allocatedNUMAResources map[types.UID]numaResourceAllocation
+}
+
+type numaResourceAllocation struct {
+	numaNodes CPUSet
+	resources corev1.ResourceList
 }
 
 func newCPUAllocation(nodeName string) *cpuAllocation {
@@ -36,12 +49,55 @@ func newCPUAllocation(nodeName string) *cpuAllocation {
 		nodeName:      nodeName,
 		allocatedPods: map[types.UID]CPUSet{},
 		allocatedCPUs: NewCPUDetails(),
+		activeWindows: map[types.UID]extension.ActiveWindows{},
+	}
+}
+
+func (n *cpuAllocation) updateAllocatedNUMAResources(podUID types.UID, numaNodes CPUSet, resources corev1.ResourceList) {
+	if numaNodes.IsEmpty() || quotav1.IsZero(resources) {
+		delete(n.allocatedNUMAResources, podUID)
+		return
+	}
+	if n.allocatedNUMAResources == nil {
+		n.allocatedNUMAResources = map[types.UID]numaResourceAllocation{}
+	}
+	n.allocatedNUMAResources[podUID] = numaResourceAllocation{numaNodes: numaNodes, resources: resources}
+}
+
+// getAvailableNUMAResources returns the free memory and hugepages of each NUMA Node. The resources of the Pod
+// bound to multiple NUMA Nodes are accounted evenly in these NUMA Nodes.
+func (n *cpuAllocation) getAvailableNUMAResources(capacity map[int]corev1.ResourceList) map[int]corev1.ResourceList {
+	available := make(map[int]corev1.ResourceList, len(capacity))
+	for nodeID, resources := range capacity {
+		available[nodeID] = resources.DeepCopy()
+	}
+	for _, allocation := range n.allocatedNUMAResources {
+		numaNodes := allocation.numaNodes.ToSliceNoSort()
+		used := splitNUMAResources(allocation.resources, len(numaNodes))
+		for _, nodeID := range numaNodes {
+			if resources, ok := available[nodeID]; ok {
+				available[nodeID] = quotav1.Subtract(resources, quotav1.Mask(used, quotav1.ResourceNames(resources)))
+			}
+		}
 	}
+	return available
 }
 
-func (n *cpuAllocation) updateAllocatedCPUSet(cpuTopology *CPUTopology, podUID types.UID, cpuset CPUSet, cpuExclusivePolicy schedulingconfig.CPUExclusivePolicy) {
+// splitNUMAResources returns the resources accounted in each of the numaNodes NUMA Nodes.
+func splitNUMAResources(resources corev1.ResourceList, numaNodes int) corev1.ResourceList {
+	result := make(corev1.ResourceList, len(resources))
+	for name, quantity := range resources {
+		result[name] = *resource.NewQuantity(quantity.Value()/int64(numaNodes), quantity.Format)
+	}
+	return result
+}
+
+func (n *cpuAllocation) updateAllocatedCPUSet(cpuTopology *CPUTopology, podUID types.UID, cpuset CPUSet, cpuExclusivePolicy schedulingconfig.CPUExclusivePolicy, activeWindows extension.ActiveWindows) {
 	n.releaseCPUs(podUID)
 	n.addCPUs(cpuTopology, podUID, cpuset, cpuExclusivePolicy)
+	if len(activeWindows) > 0 {
+		n.activeWindows[podUID] = activeWindows
+	}
 }
 
 func (n *cpuAllocation) addCPUs(cpuTopology *CPUTopology, podUID types.UID, cpuset CPUSet, exclusivePolicy schedulingconfig.CPUExclusivePolicy) {
@@ -67,6 +123,8 @@ func (n *cpuAllocation) releaseCPUs(podUID types.UID) {
 		return
 	}
 	delete(n.allocatedPods, podUID)
+	delete(n.activeWindows, podUID)
+	delete(n.allocatedNUMAResources, podUID)
 
 	for _, cpuID := range cpuset.ToSliceNoSort() {
 		cpuInfo, ok := n.allocatedCPUs[cpuID]
@@ -82,8 +140,29 @@ func (n *cpuAllocation) releaseCPUs(podUID types.UID) {
 	}
 }
 
-func (n *cpuAllocation) getAvailableCPUs(cpuTopology *CPUTopology, maxRefCount int, reservedCPUs CPUSet) (availableCPUs CPUSet, allocateInfo CPUDetails) {
+// getAvailableCPUs returns the CPUs available to the Pod active in activeWindows. The CPUs held by the
+// LSR Pods whose ActiveWindows don't overlap with activeWindows are time-shared, so they are not counted.
+func (n *cpuAllocation) getAvailableCPUs(cpuTopology *CPUTopology, maxRefCount int, reservedCPUs CPUSet, activeWindows extension.ActiveWindows) (availableCPUs CPUSet, allocateInfo CPUDetails) {
 	allocateInfo = n.allocatedCPUs.Clone()
+	if len(activeWindows) > 0 {
+		for podUID, podActiveWindows := range n.activeWindows {
+			if podActiveWindows.Overlaps(activeWindows) {
+				continue
+			}
+			for _, cpuID := range n.allocatedPods[podUID].ToSliceNoSort() {
+				cpuInfo, ok := allocateInfo[cpuID]
+				if !ok {
+					continue
+				}
+				cpuInfo.RefCount--
+				if cpuInfo.RefCount == 0 {
+					delete(allocateInfo, cpuID)
+				} else {
+					allocateInfo[cpuID] = cpuInfo
+				}
+			}
+		}
+	}
 	allocated := allocateInfo.CPUs().Filter(func(cpuID int) bool {
 		return allocateInfo[cpuID].RefCount >= maxRefCount
 	})
@@ -20,9 +20,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/uuid"
 
+	"github.com/koordinator-sh/koordinator/apis/extension"
 	schedulingconfig "github.com/koordinator-sh/koordinator/apis/scheduling/config"
 )
 
@@ -53,7 +56,7 @@ func TestNodeAllocationStateAddCPUs(t *testing.T) {
 	assert.Equal(t, expectAllocatedPods, allocationState.allocatedPods)
 	assert.Equal(t, expectAllocatedCPUs, allocationState.allocatedCPUs)
 
-	availableCPUs, _ := allocationState.getAvailableCPUs(cpuTopology, 2, NewCPUSet())
+	availableCPUs, _ := allocationState.getAvailableCPUs(cpuTopology, 2, NewCPUSet(), nil)
 	expectAvailableCPUs := MustParse("0-15")
 	assert.Equal(t, expectAvailableCPUs, availableCPUs)
 
@@ -62,7 +65,7 @@ func TestNodeAllocationStateAddCPUs(t *testing.T) {
 	assert.Equal(t, expectAllocatedPods, allocationState.allocatedPods)
 	assert.Equal(t, expectAllocatedCPUs, allocationState.allocatedCPUs)
 
-	availableCPUs, _ = allocationState.getAvailableCPUs(cpuTopology, 2, NewCPUSet())
+	availableCPUs, _ = allocationState.getAvailableCPUs(cpuTopology, 2, NewCPUSet(), nil)
 	MustParse("0-15")
 	assert.Equal(t, expectAvailableCPUs, availableCPUs)
 
@@ -119,19 +122,77 @@ func Test_cpuAllocation_getAvailableCPUs(t *testing.T) {
 	podUID := uuid.NewUUID()
 	allocationState.addCPUs(cpuTopology, podUID, MustParse("1-4"), schedulingconfig.CPUExclusivePolicyPCPULevel)
 
-	availableCPUs, _ := allocationState.getAvailableCPUs(cpuTopology, 2, NewCPUSet())
+	availableCPUs, _ := allocationState.getAvailableCPUs(cpuTopology, 2, NewCPUSet(), nil)
 	expectAvailableCPUs := MustParse("0-15")
 	assert.Equal(t, expectAvailableCPUs, availableCPUs)
 
 	// test with add already allocated cpu(refCount > 1 but less than maxRefCount) and another pod
 	anotherPodUID := uuid.NewUUID()
 	allocationState.addCPUs(cpuTopology, anotherPodUID, MustParse("2-5"), schedulingconfig.CPUExclusivePolicyPCPULevel)
-	availableCPUs, _ = allocationState.getAvailableCPUs(cpuTopology, 2, NewCPUSet())
+	availableCPUs, _ = allocationState.getAvailableCPUs(cpuTopology, 2, NewCPUSet(), nil)
 	expectAvailableCPUs = MustParse("0-1,5-15")
 	assert.Equal(t, expectAvailableCPUs, availableCPUs)
 
 	allocationState.releaseCPUs(podUID)
-	availableCPUs, _ = allocationState.getAvailableCPUs(cpuTopology, 1, NewCPUSet())
+	availableCPUs, _ = allocationState.getAvailableCPUs(cpuTopology, 1, NewCPUSet(), nil)
 	expectAvailableCPUs = MustParse("0-1,6-15")
 	assert.Equal(t, expectAvailableCPUs, availableCPUs)
 }
+
+func Test_cpuAllocation_getAvailableCPUsWithActiveWindows(t *testing.T) {
+	cpuTopology := buildCPUTopologyForTest(2, 1, 4, 2)
+	marketHours := extension.ActiveWindows{{Start: "09:30", End: "16:00"}}
+	night := extension.ActiveWindows{{Start: "22:00", End: "06:00"}}
+
+	allocationState := newCPUAllocation("test-node-1")
+	podUID := uuid.NewUUID()
+	allocationState.updateAllocatedCPUSet(cpuTopology, podUID, MustParse("0-3"), schedulingconfig.CPUExclusivePolicyNone, marketHours)
+
+	// the cpus are time-shared with the pod active at night
+	availableCPUs, _ := allocationState.getAvailableCPUs(cpuTopology, 1, NewCPUSet(), night)
+	assert.Equal(t, MustParse("0-15"), availableCPUs)
+	// but not with the pod always active or active in the overlapped windows
+	availableCPUs, _ = allocationState.getAvailableCPUs(cpuTopology, 1, NewCPUSet(), nil)
+	assert.Equal(t, MustParse("4-15"), availableCPUs)
+	availableCPUs, _ = allocationState.getAvailableCPUs(cpuTopology, 1, NewCPUSet(), extension.ActiveWindows{{Start: "15:00", End: "23:00"}})
+	assert.Equal(t, MustParse("4-15"), availableCPUs)
+
+	allocationState.releaseCPUs(podUID)
+	assert.Empty(t, allocationState.activeWindows)
+}
+
+func Test_cpuAllocation_getAvailableNUMAResources(t *testing.T) {
+	cpuTopology := buildCPUTopologyForTest(2, 1, 4, 2)
+	allocationState := newCPUAllocation("test-node-1")
+	hugePages1Gi := corev1.ResourceName("hugepages-1Gi")
+	capacity := map[int]corev1.ResourceList{
+		0: {corev1.ResourceMemory: resource.MustParse("8Gi"), hugePages1Gi: resource.MustParse("4Gi")},
+		1: {corev1.ResourceMemory: resource.MustParse("8Gi")},
+	}
+
+	podUID1 := uuid.NewUUID()
+	allocationState.addCPUs(cpuTopology, podUID1, MustParse("0-1"), schedulingconfig.CPUExclusivePolicyNone)
+	allocationState.updateAllocatedNUMAResources(podUID1, NewCPUSet(0), corev1.ResourceList{
+		corev1.ResourceMemory: resource.MustParse("4Gi"),
+		hugePages1Gi:          resource.MustParse("2Gi"),
+	})
+	// the memory of the pod bound to both NUMA Nodes is accounted evenly
+	podUID2 := uuid.NewUUID()
+	allocationState.addCPUs(cpuTopology, podUID2, MustParse("2-3,8-9"), schedulingconfig.CPUExclusivePolicyNone)
+	allocationState.updateAllocatedNUMAResources(podUID2, NewCPUSet(0, 1), corev1.ResourceList{
+		corev1.ResourceMemory: resource.MustParse("2Gi"),
+	})
+	available := allocationState.getAvailableNUMAResources(capacity)
+	numa0, numa1 := available[0], available[1]
+	assert.Equal(t, int64(3<<30), numa0.Memory().Value())
+	assert.Equal(t, int64(2<<30), numa0.Name(hugePages1Gi, resource.BinarySI).Value())
+	assert.Equal(t, int64(7<<30), numa1.Memory().Value())
+	_, ok := numa1[hugePages1Gi]
+	assert.False(t, ok)
+
+	allocationState.releaseCPUs(podUID1)
+	available = allocationState.getAvailableNUMAResources(capacity)
+	numa0 = available[0]
+	assert.Equal(t, int64(7<<30), numa0.Memory().Value())
+	assert.Equal(t, int64(4<<30), numa0.Name(hugePages1Gi, resource.BinarySI).Value())
+}
@@ -36,9 +36,12 @@ type CPUManager interface {
 		node *corev1.Node,
 		numCPUsNeeded int,
 		cpuBindPolicy schedulingconfig.CPUBindPolicy,
-		cpuExclusivePolicy schedulingconfig.CPUExclusivePolicy) (CPUSet, error)
+		cpuExclusivePolicy schedulingconfig.CPUExclusivePolicy,
+		activeWindows extension.ActiveWindows,
+		numaTopologyPolicy extension.NUMATopologyPolicy,
+		numaAllocateStrategy schedulingconfig.NUMAAllocateStrategy) (CPUSet, error)
 
-	UpdateAllocatedCPUSet(nodeName string, podUID types.UID, cpuset CPUSet, cpuExclusivePolicy schedulingconfig.CPUExclusivePolicy)
+	UpdateAllocatedCPUSet(nodeName string, podUID types.UID, cpuset CPUSet, cpuExclusivePolicy schedulingconfig.CPUExclusivePolicy, activeWindows extension.ActiveWindows)
 
 	Free(nodeName string, podUID types.UID)
 
@@ -46,9 +49,19 @@ type CPUManager interface {
 		node *corev1.Node,
 		numCPUsNeeded int,
 		cpuBindPolicy schedulingconfig.CPUBindPolicy,
-		cpuExclusivePolicy schedulingconfig.CPUExclusivePolicy) int64
+		cpuExclusivePolicy schedulingconfig.CPUExclusivePolicy,
+		activeWindows extension.ActiveWindows,
+		numaAllocateStrategy schedulingconfig.NUMAAllocateStrategy) int64
 
 	GetAvailableCPUs(nodeName string) (availableCPUs CPUSet, allocated CPUDetails, err error)
+
+	// UpdateAllocatedNUMAResources records the memory and hugepages of the Pod bound to the NUMA Nodes,
+	// it must be called after UpdateAllocatedCPUSet and is released by Free.
+	UpdateAllocatedNUMAResources(nodeName string, podUID types.UID, numaNodes CPUSet, resources corev1.ResourceList)
+
+	// GetAvailableNUMAResources returns the free memory and hugepages of each NUMA Node,
+	// nil if the node doesn't report the NUMA memory.
+	GetAvailableNUMAResources(nodeName string) map[int]corev1.ResourceList
 }
 
 type cpuManagerImpl struct {
@@ -111,6 +124,9 @@ func (c *cpuManagerImpl) Allocate(
 	numCPUsNeeded int,
 	cpuBindPolicy schedulingconfig.CPUBindPolicy,
 	cpuExclusivePolicy schedulingconfig.CPUExclusivePolicy,
+	activeWindows extension.ActiveWindows,
+	numaTopologyPolicy extension.NUMATopologyPolicy,
+	numaAllocateStrategy schedulingconfig.NUMAAllocateStrategy,
 ) (CPUSet, error) {
 	result := CPUSet{}
 	// The Pod requires the CPU to be allocated according to CPUBindPolicy,
@@ -130,8 +146,12 @@ func (c *cpuManagerImpl) Allocate(
 	allocation.lock.Lock()
 	defer allocation.lock.Unlock()
 
-	availableCPUs, allocated := allocation.getAvailableCPUs(cpuTopologyOptions.CPUTopology, cpuTopologyOptions.MaxRefCount, reservedCPUs)
-	numaAllocateStrategy := c.getNUMAAllocateStrategy(node)
+	availableCPUs, allocated := allocation.getAvailableCPUs(cpuTopologyOptions.CPUTopology, cpuTopologyOptions.MaxRefCount, reservedCPUs, activeWindows)
+	numaAllocateStrategy = c.getNUMAAllocateStrategy(node, numaAllocateStrategy)
+	if numaAllocateStrategy == schedulingconfig.NUMADistributeEvenly && requireNUMAAlignment(numaTopologyPolicy) {
+		// the NUMA topology policy requires the CPUs in the fewest NUMA Nodes
+		numaAllocateStrategy = schedulingconfig.NUMAMostAllocated
+	}
 	result, err := takeCPUs(
 		cpuTopologyOptions.CPUTopology,
 		cpuTopologyOptions.MaxRefCount,
@@ -142,10 +162,16 @@ func (c *cpuManagerImpl) Allocate(
 		cpuExclusivePolicy,
 		numaAllocateStrategy,
 	)
-	return result, err
+	if err != nil {
+		return result, err
+	}
+	if err := checkNUMATopologyPolicy(cpuTopologyOptions.CPUTopology, result, numaTopologyPolicy); err != nil {
+		return CPUSet{}, err
+	}
+	return result, nil
 }
 
-func (c *cpuManagerImpl) UpdateAllocatedCPUSet(nodeName string, podUID types.UID, cpuset CPUSet, cpuExclusivePolicy schedulingconfig.CPUExclusivePolicy) {
+func (c *cpuManagerImpl) UpdateAllocatedCPUSet(nodeName string, podUID types.UID, cpuset CPUSet, cpuExclusivePolicy schedulingconfig.CPUExclusivePolicy, activeWindows extension.ActiveWindows) {
 	cpuTopologyOptions := c.topologyManager.GetCPUTopologyOptions(nodeName)
 	if cpuTopologyOptions.CPUTopology == nil || !cpuTopologyOptions.CPUTopology.IsValid() {
 		return
@@ -155,7 +181,7 @@ func (c *cpuManagerImpl) UpdateAllocatedCPUSet(nodeName string, podUID types.UID
 	allocation.lock.Lock()
 	defer allocation.lock.Unlock()
 
-	allocation.updateAllocatedCPUSet(cpuTopologyOptions.CPUTopology, podUID, cpuset, cpuExclusivePolicy)
+	allocation.updateAllocatedCPUSet(cpuTopologyOptions.CPUTopology, podUID, cpuset, cpuExclusivePolicy, activeWindows)
 }
 
 func (c *cpuManagerImpl) Free(nodeName string, podUID types.UID) {
@@ -165,18 +191,38 @@ func (c *cpuManagerImpl) Free(nodeName string, podUID types.UID) {
 	allocation.releaseCPUs(podUID)
 }
 
+func (c *cpuManagerImpl) UpdateAllocatedNUMAResources(nodeName string, podUID types.UID, numaNodes CPUSet, resources corev1.ResourceList) {
+	allocation := c.getOrCreateAllocation(nodeName)
+	allocation.lock.Lock()
+	defer allocation.lock.Unlock()
+	allocation.updateAllocatedNUMAResources(podUID, numaNodes, resources)
+}
+
+func (c *cpuManagerImpl) GetAvailableNUMAResources(nodeName string) map[int]corev1.ResourceList {
+	capacity := c.topologyManager.GetCPUTopologyOptions(nodeName).NUMANodeResources
+	if len(capacity) == 0 {
+		return nil
+	}
+	allocation := c.getOrCreateAllocation(nodeName)
+	allocation.lock.Lock()
+	defer allocation.lock.Unlock()
+	return allocation.getAvailableNUMAResources(capacity)
+}
+
 func (c *cpuManagerImpl) Score(
 	node *corev1.Node,
 	numCPUsNeeded int,
 	cpuBindPolicy schedulingconfig.CPUBindPolicy,
 	cpuExclusivePolicy schedulingconfig.CPUExclusivePolicy,
+	activeWindows extension.ActiveWindows,
+	numaAllocateStrategy schedulingconfig.NUMAAllocateStrategy,
 ) int64 {
 	cpuTopologyOptions := c.topologyManager.GetCPUTopologyOptions(node.Name)
 	if cpuTopologyOptions.CPUTopology == nil || !cpuTopologyOptions.CPUTopology.IsValid() {
 		return 0
 	}
 
-	numaAllocateStrategy := c.getNUMAAllocateStrategy(node)
+	numaAllocateStrategy = c.getNUMAAllocateStrategy(node, numaAllocateStrategy)
 	reservedCPUs := cpuTopologyOptions.ReservedCPUs
 
 	allocation := c.getOrCreateAllocation(node.Name)
@@ -184,7 +230,7 @@ func (c *cpuManagerImpl) Score(
 	defer allocation.lock.Unlock()
 
 	cpuTopology := cpuTopologyOptions.CPUTopology
-	availableCPUs, allocated := allocation.getAvailableCPUs(cpuTopology, cpuTopologyOptions.MaxRefCount, reservedCPUs)
+	availableCPUs, allocated := allocation.getAvailableCPUs(cpuTopology, cpuTopologyOptions.MaxRefCount, reservedCPUs, activeWindows)
 	acc := newCPUAccumulator(
 		cpuTopology,
 		cpuTopologyOptions.MaxRefCount,
@@ -195,6 +241,10 @@ func (c *cpuManagerImpl) Score(
 		numaAllocateStrategy,
 	)
 
+	if numaAllocateStrategy == schedulingconfig.NUMADistributeEvenly {
+		return acc.distributeEvenlyScore(cpuBindPolicy == schedulingconfig.CPUBindPolicyFullPCPUs)
+	}
+
 	var freeCPUs [][]int
 	if cpuBindPolicy == schedulingconfig.CPUBindPolicyFullPCPUs {
 		if numCPUsNeeded <= cpuTopology.CPUsPerNode() {
@@ -268,7 +318,11 @@ func leastRequestedScore(requested, capacity int64) int64 {
 	return ((capacity - requested) * framework.MaxNodeScore) / capacity
 }
 
-func (c *cpuManagerImpl) getNUMAAllocateStrategy(node *corev1.Node) schedulingconfig.NUMAAllocateStrategy {
+// getNUMAAllocateStrategy returns the strategy of the Pod if specified, otherwise the strategy of the node or the default.
+func (c *cpuManagerImpl) getNUMAAllocateStrategy(node *corev1.Node, podStrategy schedulingconfig.NUMAAllocateStrategy) schedulingconfig.NUMAAllocateStrategy {
+	if podStrategy != "" {
+		return podStrategy
+	}
 	numaAllocateStrategy := c.numaAllocateStrategy
 	if val := schedulingconfig.NUMAAllocateStrategy(node.Labels[extension.LabelNodeNUMAAllocateStrategy]); val != "" {
 		numaAllocateStrategy = val
@@ -288,6 +342,6 @@ func (c *cpuManagerImpl) GetAvailableCPUs(nodeName string) (availableCPUs CPUSet
 	allocation := c.getOrCreateAllocation(nodeName)
 	allocation.lock.Lock()
 	defer allocation.lock.Unlock()
-	availableCPUs, allocated = allocation.getAvailableCPUs(cpuTopologyOptions.CPUTopology, cpuTopologyOptions.MaxRefCount, cpuTopologyOptions.ReservedCPUs)
+	availableCPUs, allocated = allocation.getAvailableCPUs(cpuTopologyOptions.CPUTopology, cpuTopologyOptions.MaxRefCount, cpuTopologyOptions.ReservedCPUs, nil)
 	return availableCPUs, allocated, nil
 }
@@ -102,6 +102,21 @@ func (topo *CPUTopology) CPUsPerNode() int {
 	return topo.NumCPUs / topo.NumNodes
 }
 
+// AlignToCores rounds up the number of logical CPUs to the full physical cores.
+func (topo *CPUTopology) AlignToCores(numCPUs int) int {
+	cpusPerCore := topo.CPUsPerCore()
+	if cpusPerCore <= 1 {
+		return numCPUs
+	}
+	return (numCPUs + cpusPerCore - 1) / cpusPerCore * cpusPerCore
+}
+
+// IsFullCores checks if the cpus contain all the logical CPUs of the physical cores they belong to.
+func (topo *CPUTopology) IsFullCores(cpus CPUSet) bool {
+	cores := topo.CPUDetails.KeepOnly(cpus).Cores()
+	return topo.CPUDetails.CPUsInCores(cores.ToSliceNoSort()...).Equals(cpus)
+}
+
 // CPUDetails is a map from logical CPU ID to CPUInfo.
 type CPUDetails map[int]CPUInfo
 
@@ -19,6 +19,8 @@ package nodenumaresource
 import (
 	"sync"
 
+	corev1 "k8s.io/api/core/v1"
+
 	"github.com/koordinator-sh/koordinator/apis/extension"
 )
 
@@ -34,6 +36,8 @@ type CPUTopologyOptions struct {
 	ReservedCPUs CPUSet                             `json:"reservedCPUs,omitempty"`
 	MaxRefCount  int                                `json:"maxRefCount,omitempty"`
 	Policy       *extension.KubeletCPUManagerPolicy `json:"policy,omitempty"`
+	// NUMANodeResources is the memory and hugepages capacity of each NUMA Node, the key is the NUMA Node ID seen by the OS.
+	NUMANodeResources map[int]corev1.ResourceList `json:"numaNodeResources,omitempty"`
 }
 
 type cpuTopologyManager struct {
@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+// getNUMATopologyPolicy returns the NUMA topology policy of the pod on the node, the policy declared by the pod
+// takes precedence over the node-wide policy.
+func getNUMATopologyPolicy(podPolicy extension.NUMATopologyPolicy, node *corev1.Node) extension.NUMATopologyPolicy {
+	if podPolicy != extension.NUMATopologyPolicyNone {
+		return podPolicy
+	}
+	return extension.GetNodeNUMATopologyPolicy(node.Labels)
+}
+
+// requireNUMAAlignment returns whether the allocation violating the policy must be rejected.
+func requireNUMAAlignment(policy extension.NUMATopologyPolicy) bool {
+	return policy == extension.NUMATopologyPolicyRestricted || policy == extension.NUMATopologyPolicySingleNUMANode
+}
+
+// checkNUMATopologyPolicy checks whether the CPUs are aligned to the NUMA Nodes as the policy requires.
+// Restricted requires the CPUs in the fewest NUMA Nodes the requested CPUs can fit in,
+// and SingleNUMANode requires the CPUs in a single NUMA Node.
+func checkNUMATopologyPolicy(topology *CPUTopology, cpus CPUSet, policy extension.NUMATopologyPolicy) error {
+	if !requireNUMAAlignment(policy) || cpus.IsEmpty() || !topology.IsValid() {
+		return nil
+	}
+	maxNUMANodes := 1
+	if policy == extension.NUMATopologyPolicyRestricted {
+		cpusPerNode := topology.CPUsPerNode()
+		maxNUMANodes = (cpus.Count() + cpusPerNode - 1) / cpusPerNode
+	}
+	if topology.CPUDetails.KeepOnly(cpus).NUMANodes().Count() > maxNUMANodes {
+		return errors.New(ErrNUMATopologyPolicyNotSatisfied)
+	}
+	return nil
+}
+
+// getNUMANodeIDs returns the IDs of the NUMA Nodes the CPUs belong to, which are the IDs seen by the OS.
+func getNUMANodeIDs(topology *CPUTopology, cpus CPUSet) CPUSet {
+	b := NewCPUSetBuilder()
+	for _, nodeID := range topology.CPUDetails.KeepOnly(cpus).NUMANodes().ToSliceNoSort() {
+		// the NUMA Node ID is encoded with the socket ID by the CPUTopologyBuilder
+		b.Add(nodeID & 0xffff)
+	}
+	return b.Result()
+}
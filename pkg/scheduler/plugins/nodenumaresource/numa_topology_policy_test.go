@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestCheckNUMATopologyPolicy(t *testing.T) {
+	// 2 sockets, 2 NUMA Nodes per socket, 8 CPUs per NUMA Node
+	topology := buildCPUTopologyForTest(2, 2, 4, 2)
+	tests := []struct {
+		name    string
+		cpus    CPUSet
+		policy  extension.NUMATopologyPolicy
+		wantErr bool
+	}{
+		{
+			name:   "no policy",
+			cpus:   MustParse("0-3,8-11"),
+			policy: extension.NUMATopologyPolicyNone,
+		},
+		{
+			name:   "BestEffort never rejects",
+			cpus:   MustParse("0-3,8-11"),
+			policy: extension.NUMATopologyPolicyBestEffort,
+		},
+		{
+			name:   "SingleNUMANode satisfied",
+			cpus:   MustParse("8-15"),
+			policy: extension.NUMATopologyPolicySingleNUMANode,
+		},
+		{
+			name:    "SingleNUMANode across NUMA Nodes",
+			cpus:    MustParse("0-3,8-11"),
+			policy:  extension.NUMATopologyPolicySingleNUMANode,
+			wantErr: true,
+		},
+		{
+			name:   "Restricted in the fewest NUMA Nodes",
+			cpus:   MustParse("0-11"),
+			policy: extension.NUMATopologyPolicyRestricted,
+		},
+		{
+			name:    "Restricted across more NUMA Nodes than needed",
+			cpus:    MustParse("0-3,8-11"),
+			policy:  extension.NUMATopologyPolicyRestricted,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkNUMATopologyPolicy(topology, tt.cpus, tt.policy)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestGetNUMANodeIDs(t *testing.T) {
+	topology := buildCPUTopologyForTest(2, 2, 4, 2)
+	assert.Equal(t, "1-2", getNUMANodeIDs(topology, MustParse("8-9,16-17")).String())
+}
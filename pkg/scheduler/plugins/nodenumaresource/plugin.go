@@ -19,7 +19,9 @@ package nodenumaresource
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -45,10 +47,16 @@ const (
 )
 
 const (
-	ErrNotFoundCPUTopology     = "node(s) CPU Topology not found"
-	ErrInvalidCPUTopology      = "node(s) invalid CPU Topology"
-	ErrSMTAlignmentError       = "node(s) requested cpus not multiple cpus per core"
-	ErrRequiredFullPCPUsPolicy = "node(s) required FullPCPUs policy"
+	ErrNotFoundCPUTopology            = "node(s) CPU Topology not found"
+	ErrInvalidCPUTopology             = "node(s) invalid CPU Topology"
+	ErrSMTAlignmentError              = "node(s) requested cpus not multiple cpus per core"
+	ErrRequiredFullPCPUsPolicy        = "node(s) required FullPCPUs policy"
+	ErrInvalidNUMATopologyPolicy      = "invalid NUMA topology policy"
+	ErrNUMATopologyPolicyNotSatisfied = "node(s) NUMA topology policy not satisfied"
+	ErrInvalidNUMAAllocateStrategy    = "invalid NUMA allocate strategy"
+	ErrInvalidMemoryBindPolicy        = "invalid memory bind policy"
+	ErrInsufficientNUMAMemory         = "node(s) insufficient memory in NUMA Nodes"
+	ErrInsufficientNUMAHugePages      = "node(s) insufficient hugepages in NUMA Nodes"
 )
 
 var (
@@ -72,6 +80,7 @@ type Plugin struct {
 	pluginArgs      *schedulingconfig.NodeNUMAResourceArgs
 	topologyManager CPUTopologyManager
 	cpuManager      CPUManager
+	recentCPUSets   *recentCPUSets
 }
 
 type Option func(*pluginOptions)
@@ -125,13 +134,15 @@ func NewWithOptions(args runtime.Object, handle framework.Handle, opts ...Option
 			return nil, err
 		}
 	}
-	registerPodEventHandler(handle, options.cpuManager)
+	recentCPUSets := newRecentCPUSets()
+	registerPodEventHandler(handle, options.cpuManager, recentCPUSets)
 
 	return &Plugin{
 		handle:          handle,
 		pluginArgs:      pluginArgs,
 		topologyManager: options.topologyManager,
 		cpuManager:      options.cpuManager,
+		recentCPUSets:   recentCPUSets,
 	}, nil
 }
 
@@ -162,14 +173,21 @@ type preFilterState struct {
 	resourceSpec                *extension.ResourceSpec
 	preferredCPUBindPolicy      schedulingconfig.CPUBindPolicy
 	preferredCPUExclusivePolicy schedulingconfig.CPUExclusivePolicy
+	activeWindows               extension.ActiveWindows
+	numaTopologyPolicy          extension.NUMATopologyPolicy
+	numaAllocateStrategy        extension.NUMAAllocateStrategy
+	memoryBindPolicy            extension.MemoryBindPolicy
 	numCPUsNeeded               int
+	requestedNUMAResources      corev1.ResourceList
 	allocatedCPUs               CPUSet
+	allocatedNUMANodes          CPUSet
 }
 
 func (s *preFilterState) Clone() framework.StateData {
 	return &preFilterState{
 		skip:          s.skip,
 		resourceSpec:  s.resourceSpec,
+		activeWindows: s.activeWindows,
 		allocatedCPUs: s.allocatedCPUs.Clone(),
 	}
 }
@@ -200,11 +218,31 @@ func (p *Plugin) PreFilter(ctx context.Context, cycleState *framework.CycleState
 			}
 
 			if requestedCPU > 0 {
+				// only the LSR Pods can time-share the exclusive CPUs
+				if qosClass == extension.QoSLSR {
+					if err := resourceSpec.ActiveWindows.Validate(); err != nil {
+						return framework.NewStatus(framework.UnschedulableAndUnresolvable, err.Error())
+					}
+					state.activeWindows = resourceSpec.ActiveWindows
+				}
 				state.skip = false
 				state.resourceSpec = resourceSpec
 				state.preferredCPUBindPolicy = preferredCPUBindPolicy
 				state.preferredCPUExclusivePolicy = resourceSpec.PreferredCPUExclusivePolicy
+				if !resourceSpec.NUMATopologyPolicy.IsValid() {
+					return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrInvalidNUMATopologyPolicy)
+				}
+				state.numaTopologyPolicy = resourceSpec.NUMATopologyPolicy
+				if !extension.IsValidNUMAAllocateStrategy(resourceSpec.NUMAAllocateStrategy) {
+					return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrInvalidNUMAAllocateStrategy)
+				}
+				state.numaAllocateStrategy = resourceSpec.NUMAAllocateStrategy
+				if !resourceSpec.MemoryBindPolicy.IsValid() {
+					return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrInvalidMemoryBindPolicy)
+				}
+				state.memoryBindPolicy = resourceSpec.MemoryBindPolicy
 				state.numCPUsNeeded = int(requestedCPU / 1000)
+				state.requestedNUMAResources = getNUMAResourceRequests(requests)
 			}
 		}
 	}
@@ -226,6 +264,66 @@ func getPreFilterState(cycleState *framework.CycleState) (*preFilterState, *fram
 	return state, nil
 }
 
+func isFullPCPUsIsolated(node *corev1.Node) bool {
+	return node.Labels[extension.LabelNodeCPUBindPolicy] == extension.NodeCPUBindPolicyFullPCPUsIsolated
+}
+
+// getCPUAllocationOnNode returns the number of CPUs and the CPU bind policy to allocate on the node.
+// The node isolating the physical cores always allocates the full physical cores, so the idle siblings
+// of the Pod are never allocated to the others.
+func getCPUAllocationOnNode(state *preFilterState, node *corev1.Node, topology *CPUTopology) (int, schedulingconfig.CPUBindPolicy) {
+	if !isFullPCPUsIsolated(node) || topology == nil || !topology.IsValid() {
+		return state.numCPUsNeeded, state.preferredCPUBindPolicy
+	}
+	return topology.AlignToCores(state.numCPUsNeeded), schedulingconfig.CPUBindPolicyFullPCPUs
+}
+
+// bindNUMANodes returns whether the Pod is bound to the NUMA Nodes of the allocated CPUs, which binds the memory
+// of the Pod to these NUMA Nodes as well.
+func bindNUMANodes(state *preFilterState, numaTopologyPolicy extension.NUMATopologyPolicy) bool {
+	return requireNUMAAlignment(numaTopologyPolicy) || state.memoryBindPolicy == extension.MemoryBindPolicyNUMALocal
+}
+
+// getNUMAResourceRequests returns the memory and hugepages requests which are accounted in the NUMA Nodes.
+func getNUMAResourceRequests(requests corev1.ResourceList) corev1.ResourceList {
+	var result corev1.ResourceList
+	for name, quantity := range requests {
+		if (name == corev1.ResourceMemory || strings.HasPrefix(string(name), corev1.ResourceHugePagesPrefix)) && !quantity.IsZero() {
+			if result == nil {
+				result = corev1.ResourceList{}
+			}
+			result[name] = quantity
+		}
+	}
+	return result
+}
+
+// checkNUMAResources checks whether the NUMA Nodes have enough free memory and hugepages for the Pod bound to them,
+// the resources are accounted evenly in these NUMA Nodes. It's skipped if the node doesn't report the NUMA memory.
+func (p *Plugin) checkNUMAResources(nodeName string, numaNodes CPUSet, requests corev1.ResourceList) error {
+	if numaNodes.IsEmpty() || len(requests) == 0 {
+		return nil
+	}
+	available := p.cpuManager.GetAvailableNUMAResources(nodeName)
+	if available == nil {
+		return nil
+	}
+	requestsPerNode := splitNUMAResources(requests, numaNodes.Count())
+	for _, nodeID := range numaNodes.ToSliceNoSort() {
+		for name, quantity := range requestsPerNode {
+			free := available[nodeID][name]
+			if free.Cmp(quantity) >= 0 {
+				continue
+			}
+			if name == corev1.ResourceMemory {
+				return errors.New(ErrInsufficientNUMAMemory)
+			}
+			return errors.New(ErrInsufficientNUMAHugePages)
+		}
+	}
+	return nil
+}
+
 func (p *Plugin) Filter(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
 	state, status := getPreFilterState(cycleState)
 	if !status.IsSuccess() {
@@ -264,6 +362,22 @@ func (p *Plugin) Filter(ctx context.Context, cycleState *framework.CycleState, p
 		}
 	}
 
+	numCPUsNeeded, cpuBindPolicy := getCPUAllocationOnNode(state, node, cpuTopologyOptions.CPUTopology)
+	numaTopologyPolicy := getNUMATopologyPolicy(state.numaTopologyPolicy, node)
+	if numaTopologyPolicy == extension.NUMATopologyPolicySingleNUMANode &&
+		numCPUsNeeded > cpuTopologyOptions.CPUTopology.CPUsPerNode() {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrNUMATopologyPolicyNotSatisfied)
+	}
+	if bindNUMANodes(state, numaTopologyPolicy) {
+		result, err := p.cpuManager.Allocate(node, numCPUsNeeded, cpuBindPolicy, state.preferredCPUExclusivePolicy, state.activeWindows, numaTopologyPolicy, state.numaAllocateStrategy)
+		if err != nil {
+			return framework.NewStatus(framework.Unschedulable, err.Error())
+		}
+		if err := p.checkNUMAResources(node.Name, getNUMANodeIDs(cpuTopologyOptions.CPUTopology, result), state.requestedNUMAResources); err != nil {
+			return framework.NewStatus(framework.Unschedulable, err.Error())
+		}
+	}
+
 	return nil
 }
 
@@ -285,7 +399,9 @@ func (p *Plugin) Score(ctx context.Context, cycleState *framework.CycleState, po
 		return 0, framework.NewStatus(framework.Error, "node not found")
 	}
 
-	score := p.cpuManager.Score(node, state.numCPUsNeeded, state.preferredCPUBindPolicy, state.preferredCPUExclusivePolicy)
+	cpuTopology := p.topologyManager.GetCPUTopologyOptions(nodeName).CPUTopology
+	numCPUsNeeded, cpuBindPolicy := getCPUAllocationOnNode(state, node, cpuTopology)
+	score := p.cpuManager.Score(node, numCPUsNeeded, cpuBindPolicy, state.preferredCPUExclusivePolicy, state.activeWindows, state.numaAllocateStrategy)
 	return score, nil
 }
 
@@ -311,11 +427,38 @@ func (p *Plugin) Reserve(ctx context.Context, cycleState *framework.CycleState,
 		return framework.NewStatus(framework.Error, "node not found")
 	}
 
-	result, err := p.cpuManager.Allocate(node, state.numCPUsNeeded, state.preferredCPUBindPolicy, state.preferredCPUExclusivePolicy)
-	if err != nil {
-		return framework.AsStatus(err)
+	numaTopologyPolicy := getNUMATopologyPolicy(state.numaTopologyPolicy, node)
+	cpuTopology := p.topologyManager.GetCPUTopologyOptions(nodeName).CPUTopology
+	numCPUsNeeded, cpuBindPolicy := getCPUAllocationOnNode(state, node, cpuTopology)
+	// prefer the CPUs of the previous pod with the same name to reduce the cost of reloading the caches
+	result, ok := p.takeRecentCPUs(nodeName, pod, numCPUsNeeded)
+	if ok && cpuTopology != nil && checkNUMATopologyPolicy(cpuTopology, result, numaTopologyPolicy) != nil {
+		ok = false
 	}
-	p.cpuManager.UpdateAllocatedCPUSet(nodeName, pod.UID, result, state.preferredCPUExclusivePolicy)
+	if ok && cpuTopology != nil && isFullPCPUsIsolated(node) && !cpuTopology.IsFullCores(result) {
+		ok = false
+	}
+	bindNUMA := bindNUMANodes(state, numaTopologyPolicy) && cpuTopology != nil
+	if ok && bindNUMA && p.checkNUMAResources(nodeName, getNUMANodeIDs(cpuTopology, result), state.requestedNUMAResources) != nil {
+		ok = false
+	}
+	if !ok {
+		var err error
+		result, err = p.cpuManager.Allocate(node, numCPUsNeeded, cpuBindPolicy, state.preferredCPUExclusivePolicy, state.activeWindows, numaTopologyPolicy, state.numaAllocateStrategy)
+		if err != nil {
+			return framework.AsStatus(err)
+		}
+	}
+	state.allocatedNUMANodes = CPUSet{}
+	if bindNUMA {
+		numaNodes := getNUMANodeIDs(cpuTopology, result)
+		if err := p.checkNUMAResources(nodeName, numaNodes, state.requestedNUMAResources); err != nil {
+			return framework.NewStatus(framework.Unschedulable, err.Error())
+		}
+		state.allocatedNUMANodes = numaNodes
+	}
+	p.cpuManager.UpdateAllocatedCPUSet(nodeName, pod.UID, result, state.preferredCPUExclusivePolicy, state.activeWindows)
+	p.cpuManager.UpdateAllocatedNUMAResources(nodeName, pod.UID, state.allocatedNUMANodes, state.requestedNUMAResources)
 	state.allocatedCPUs = result
 	return nil
 }
@@ -352,6 +495,9 @@ func (p *Plugin) PreBind(ctx context.Context, cycleState *framework.CycleState,
 		state.resourceSpec.PreferredCPUBindPolicy == schedulingconfig.CPUBindPolicyDefault {
 		resourceSpec := &extension.ResourceSpec{
 			PreferredCPUBindPolicy: p.pluginArgs.DefaultCPUBindPolicy,
+			ActiveWindows:          state.resourceSpec.ActiveWindows,
+			NUMATopologyPolicy:     state.resourceSpec.NUMATopologyPolicy,
+			NUMAAllocateStrategy:   state.resourceSpec.NUMAAllocateStrategy,
 		}
 		resourceSpecData, err := json.Marshal(resourceSpec)
 		if err != nil {
@@ -364,6 +510,9 @@ func (p *Plugin) PreBind(ctx context.Context, cycleState *framework.CycleState,
 	}
 
 	resourceStatus := &extension.ResourceStatus{CPUSet: state.allocatedCPUs.String()}
+	if !state.allocatedNUMANodes.IsEmpty() {
+		resourceStatus.NUMANodes = state.allocatedNUMANodes.String()
+	}
 	err := SetResourceStatus(pod, resourceStatus)
 	if err != nil {
 		return framework.NewStatus(framework.Error, err.Error())
@@ -86,7 +86,7 @@ func TestEndpointsQueryAvailableCPUsOptions(t *testing.T) {
 		*options = cpuTopologyOptions
 	})
 
-	p.cpuManager.UpdateAllocatedCPUSet("test-node-1", uuid.NewUUID(), MustParse("0,2,4,6"), schedulingconfig.CPUExclusivePolicyNone)
+	p.cpuManager.UpdateAllocatedCPUSet("test-node-1", uuid.NewUUID(), MustParse("0,2,4,6"), schedulingconfig.CPUExclusivePolicyNone, nil)
 
 	engine := gin.Default()
 	p.RegisterEndpoints(engine.Group("/"))
@@ -404,6 +404,7 @@ func TestPlugin_Filter(t *testing.T) {
 		state           *preFilterState
 		pod             *corev1.Pod
 		allocationState *cpuAllocation
+		numaResources   map[int]corev1.ResourceList
 		want            *framework.Status
 	}{
 		{
@@ -517,6 +518,118 @@ func TestPlugin_Filter(t *testing.T) {
 			pod:  &corev1.Pod{},
 			want: framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrRequiredFullPCPUsPolicy),
 		},
+		{
+			name: "verify SingleNUMANode of the pod with too many CPUs",
+			state: &preFilterState{
+				skip:                   false,
+				resourceSpec:           &extension.ResourceSpec{},
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+				numaTopologyPolicy:     extension.NUMATopologyPolicySingleNUMANode,
+				numCPUsNeeded:          12,
+			},
+			cpuTopology:     buildCPUTopologyForTest(2, 1, 4, 2),
+			allocationState: newCPUAllocation("test-node-1"),
+			pod:             &corev1.Pod{},
+			want:            framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrNUMATopologyPolicyNotSatisfied),
+		},
+		{
+			name: "verify SingleNUMANode of the node",
+			nodeLabels: map[string]string{
+				extension.LabelNodeNUMATopologyPolicy: string(extension.NUMATopologyPolicySingleNUMANode),
+			},
+			state: &preFilterState{
+				skip:                   false,
+				resourceSpec:           &extension.ResourceSpec{},
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+				numCPUsNeeded:          8,
+			},
+			cpuTopology:     buildCPUTopologyForTest(2, 1, 4, 2),
+			allocationState: newCPUAllocation("test-node-1"),
+			pod:             &corev1.Pod{},
+			want:            nil,
+		},
+		{
+			name: "the BestEffort of the pod overrides the SingleNUMANode of the node",
+			nodeLabels: map[string]string{
+				extension.LabelNodeNUMATopologyPolicy: string(extension.NUMATopologyPolicySingleNUMANode),
+			},
+			state: &preFilterState{
+				skip:                   false,
+				resourceSpec:           &extension.ResourceSpec{},
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+				numaTopologyPolicy:     extension.NUMATopologyPolicyBestEffort,
+				numCPUsNeeded:          12,
+			},
+			cpuTopology:     buildCPUTopologyForTest(2, 1, 4, 2),
+			allocationState: newCPUAllocation("test-node-1"),
+			pod:             &corev1.Pod{},
+			want:            nil,
+		},
+		{
+			name: "failed to bind memory with insufficient NUMA memory",
+			state: &preFilterState{
+				skip:                   false,
+				resourceSpec:           &extension.ResourceSpec{},
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+				memoryBindPolicy:       extension.MemoryBindPolicyNUMALocal,
+				numCPUsNeeded:          4,
+				requestedNUMAResources: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("8Gi"),
+				},
+			},
+			cpuTopology:     buildCPUTopologyForTest(2, 1, 4, 2),
+			allocationState: newCPUAllocation("test-node-1"),
+			numaResources: map[int]corev1.ResourceList{
+				0: {corev1.ResourceMemory: resource.MustParse("4Gi")},
+				1: {corev1.ResourceMemory: resource.MustParse("4Gi")},
+			},
+			pod:  &corev1.Pod{},
+			want: framework.NewStatus(framework.Unschedulable, ErrInsufficientNUMAMemory),
+		},
+		{
+			name: "failed to bind hugepages with insufficient NUMA hugepages",
+			state: &preFilterState{
+				skip:                   false,
+				resourceSpec:           &extension.ResourceSpec{},
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+				memoryBindPolicy:       extension.MemoryBindPolicyNUMALocal,
+				numCPUsNeeded:          4,
+				requestedNUMAResources: corev1.ResourceList{
+					corev1.ResourceMemory:                resource.MustParse("8Gi"),
+					corev1.ResourceName("hugepages-1Gi"): resource.MustParse("2Gi"),
+				},
+			},
+			cpuTopology:     buildCPUTopologyForTest(2, 1, 4, 2),
+			allocationState: newCPUAllocation("test-node-1"),
+			numaResources: map[int]corev1.ResourceList{
+				0: {corev1.ResourceMemory: resource.MustParse("16Gi"), corev1.ResourceName("hugepages-1Gi"): resource.MustParse("1Gi")},
+				1: {corev1.ResourceMemory: resource.MustParse("16Gi"), corev1.ResourceName("hugepages-1Gi"): resource.MustParse("4Gi")},
+			},
+			pod:  &corev1.Pod{},
+			want: framework.NewStatus(framework.Unschedulable, ErrInsufficientNUMAHugePages),
+		},
+		{
+			name: "succeed to bind memory and hugepages with sufficient NUMA resources",
+			state: &preFilterState{
+				skip:                   false,
+				resourceSpec:           &extension.ResourceSpec{},
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+				memoryBindPolicy:       extension.MemoryBindPolicyNUMALocal,
+				numCPUsNeeded:          4,
+				requestedNUMAResources: corev1.ResourceList{
+					corev1.ResourceMemory:                resource.MustParse("8Gi"),
+					corev1.ResourceName("hugepages-1Gi"): resource.MustParse("2Gi"),
+				},
+			},
+			cpuTopology:     buildCPUTopologyForTest(2, 1, 4, 2),
+			allocationState: newCPUAllocation("test-node-1"),
+			numaResources: map[int]corev1.ResourceList{
+				0: {corev1.ResourceMemory: resource.MustParse("16Gi"), corev1.ResourceName("hugepages-1Gi"): resource.MustParse("4Gi")},
+				1: {corev1.ResourceMemory: resource.MustParse("16Gi"), corev1.ResourceName("hugepages-1Gi"): resource.MustParse("4Gi")},
+			},
+			pod:  &corev1.Pod{},
+			want: nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -546,8 +659,9 @@ func TestPlugin_Filter(t *testing.T) {
 			plg := p.(*Plugin)
 			if tt.allocationState != nil {
 				topologyOptions := CPUTopologyOptions{
-					CPUTopology: tt.cpuTopology,
-					Policy:      tt.kubeletPolicy,
+					CPUTopology:       tt.cpuTopology,
+					Policy:            tt.kubeletPolicy,
+					NUMANodeResources: tt.numaResources,
 				}
 				plg.topologyManager.UpdateCPUTopologyOptions(tt.allocationState.nodeName, func(options *CPUTopologyOptions) {
 					*options = topologyOptions
@@ -806,6 +920,7 @@ func TestPlugin_Reserve(t *testing.T) {
 		cpuTopology     *CPUTopology
 		allocationState *cpuAllocation
 		allocatedCPUs   []int
+		recentCPUs      []int
 		want            *framework.Status
 		wantCPUSet      CPUSet
 	}{
@@ -910,6 +1025,82 @@ func TestPlugin_Reserve(t *testing.T) {
 			want:            nil,
 			wantCPUSet:      NewCPUSet(4, 5, 6, 7),
 		},
+		{
+			name: "succeed with the free CPUs of the previous pod",
+			state: &preFilterState{
+				skip:          false,
+				numCPUsNeeded: 4,
+				resourceSpec: &extension.ResourceSpec{
+					PreferredCPUBindPolicy: extension.CPUBindPolicyFullPCPUs,
+				},
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+			},
+			cpuTopology:     buildCPUTopologyForTest(2, 1, 8, 2),
+			allocationState: newCPUAllocation("test-node-1"),
+			allocatedCPUs:   []int{0, 1, 2, 3},
+			recentCPUs:      []int{8, 9, 10, 11},
+			pod:             &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-pod"}},
+			want:            nil,
+			wantCPUSet:      NewCPUSet(8, 9, 10, 11),
+		},
+		{
+			name: "succeed with ignoring the allocated CPUs of the previous pod",
+			state: &preFilterState{
+				skip:          false,
+				numCPUsNeeded: 4,
+				resourceSpec: &extension.ResourceSpec{
+					PreferredCPUBindPolicy: extension.CPUBindPolicyFullPCPUs,
+				},
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+			},
+			cpuTopology:     buildCPUTopologyForTest(2, 1, 8, 2),
+			allocationState: newCPUAllocation("test-node-1"),
+			allocatedCPUs:   []int{0, 1, 2, 3},
+			recentCPUs:      []int{2, 3, 8, 9},
+			pod:             &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-pod"}},
+			want:            nil,
+			wantCPUSet:      NewCPUSet(4, 5, 6, 7),
+		},
+		{
+			name: "succeed with full physical cores on the node isolating the cores",
+			nodeLabels: map[string]string{
+				extension.LabelNodeCPUBindPolicy: extension.NodeCPUBindPolicyFullPCPUsIsolated,
+			},
+			state: &preFilterState{
+				skip:          false,
+				numCPUsNeeded: 3,
+				resourceSpec: &extension.ResourceSpec{
+					PreferredCPUBindPolicy: extension.CPUBindPolicySpreadByPCPUs,
+				},
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicySpreadByPCPUs,
+			},
+			cpuTopology:     buildCPUTopologyForTest(2, 1, 8, 2),
+			allocationState: newCPUAllocation("test-node-1"),
+			pod:             &corev1.Pod{},
+			want:            nil,
+			wantCPUSet:      NewCPUSet(0, 1, 2, 3),
+		},
+		{
+			name: "succeed with ignoring the partial cores of the previous pod on the node isolating the cores",
+			nodeLabels: map[string]string{
+				extension.LabelNodeCPUBindPolicy: extension.NodeCPUBindPolicyFullPCPUsIsolated,
+			},
+			state: &preFilterState{
+				skip:          false,
+				numCPUsNeeded: 4,
+				resourceSpec: &extension.ResourceSpec{
+					PreferredCPUBindPolicy: extension.CPUBindPolicyFullPCPUs,
+				},
+				preferredCPUBindPolicy: schedulingconfig.CPUBindPolicyFullPCPUs,
+			},
+			cpuTopology:     buildCPUTopologyForTest(2, 1, 8, 2),
+			allocationState: newCPUAllocation("test-node-1"),
+			allocatedCPUs:   []int{0, 1, 2, 3},
+			recentCPUs:      []int{9, 10, 11, 12},
+			pod:             &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-pod"}},
+			want:            nil,
+			wantCPUSet:      NewCPUSet(4, 5, 6, 7),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -950,6 +1141,11 @@ func TestPlugin_Reserve(t *testing.T) {
 				cpuManager := plg.cpuManager.(*cpuManagerImpl)
 				cpuManager.allocationStates[tt.allocationState.nodeName] = tt.allocationState
 			}
+			if len(tt.recentCPUs) > 0 {
+				deletedPod := tt.pod.DeepCopy()
+				deletedPod.Spec.NodeName = "test-node-1"
+				plg.recentCPUSets.remember(deletedPod, NewCPUSet(tt.recentCPUs...))
+			}
 
 			suit.start()
 
@@ -1002,7 +1198,7 @@ func TestPlugin_Unreserve(t *testing.T) {
 			allocationStates: map[string]*cpuAllocation{},
 		},
 	}
-	plg.cpuManager.UpdateAllocatedCPUSet("test-node-1", pod.UID, state.allocatedCPUs, schedulingconfig.CPUExclusivePolicyNone)
+	plg.cpuManager.UpdateAllocatedCPUSet("test-node-1", pod.UID, state.allocatedCPUs, schedulingconfig.CPUExclusivePolicyNone, nil)
 	plg.Unreserve(context.TODO(), cycleState, pod, "test-node-1")
 
 	availableCPUs, allocated, err := plg.cpuManager.GetAvailableCPUs("test-node-1")
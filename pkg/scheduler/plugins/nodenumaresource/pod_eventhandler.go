@@ -19,18 +19,22 @@ package nodenumaresource
 import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/cache"
+	resourceapi "k8s.io/kubernetes/pkg/api/v1/resource"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
+	"github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 )
 
 type podEventHandler struct {
-	cpuManager CPUManager
+	cpuManager    CPUManager
+	recentCPUSets *recentCPUSets
 }
 
-func registerPodEventHandler(handle framework.Handle, cpuManager CPUManager) {
+func registerPodEventHandler(handle framework.Handle, cpuManager CPUManager, recentCPUSets *recentCPUSets) {
 	handle.SharedInformerFactory().Core().V1().Pods().Informer().AddEventHandler(&podEventHandler{
-		cpuManager: cpuManager,
+		cpuManager:    cpuManager,
+		recentCPUSets: recentCPUSets,
 	})
 }
 
@@ -99,7 +103,19 @@ func (c *podEventHandler) updatePod(oldPod, pod *corev1.Pod) {
 		return
 	}
 
-	c.cpuManager.UpdateAllocatedCPUSet(pod.Spec.NodeName, pod.UID, cpuset, resourceSpec.PreferredCPUExclusivePolicy)
+	var activeWindows extension.ActiveWindows
+	if GetPodQoSClass(pod) == extension.QoSLSR {
+		activeWindows = resourceSpec.ActiveWindows
+	}
+	c.cpuManager.UpdateAllocatedCPUSet(pod.Spec.NodeName, pod.UID, cpuset, resourceSpec.PreferredCPUExclusivePolicy, activeWindows)
+
+	// the memory and hugepages of the Pod are bound to the NUMA Nodes by koordlet
+	numaNodes, err := Parse(resourceStatus.NUMANodes)
+	if err != nil || numaNodes.IsEmpty() {
+		return
+	}
+	requests, _ := resourceapi.PodRequestsAndLimits(pod)
+	c.cpuManager.UpdateAllocatedNUMAResources(pod.Spec.NodeName, pod.UID, numaNodes, getNUMAResourceRequests(requests))
 }
 
 func (c *podEventHandler) deletePod(pod *corev1.Pod) {
@@ -117,4 +133,7 @@ func (c *podEventHandler) deletePod(pod *corev1.Pod) {
 	}
 
 	c.cpuManager.Free(pod.Spec.NodeName, pod.UID)
+	if c.recentCPUSets != nil {
+		c.recentCPUSets.remember(pod, cpuset)
+	}
 }
@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// recentCPUSetTTL is how long the CPUs of a deleted pod are remembered. When a pod with the same
+// name is recreated on the same node in time, the same CPUs are preferred to keep the warm caches.
+const recentCPUSetTTL = 5 * time.Minute
+
+var timeNowFn = time.Now
+
+type recentCPUSet struct {
+	cpuset      CPUSet
+	deletedTime time.Time
+}
+
+// recentCPUSets stores the CPUs allocated to the recently deleted pods,
+// and uses node name and pod namespace/name as map key.
+type recentCPUSets struct {
+	lock    sync.Mutex
+	cpusets map[string]*recentCPUSet
+}
+
+func newRecentCPUSets() *recentCPUSets {
+	return &recentCPUSets{
+		cpusets: map[string]*recentCPUSet{},
+	}
+}
+
+func recentCPUSetKey(nodeName string, pod *corev1.Pod) string {
+	return nodeName + "/" + pod.Namespace + "/" + pod.Name
+}
+
+// remember records the CPUs allocated to the deleted pod, and removes the expired records.
+func (r *recentCPUSets) remember(pod *corev1.Pod, cpuset CPUSet) {
+	if pod.Spec.NodeName == "" || cpuset.IsEmpty() {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := timeNowFn()
+	for key, v := range r.cpusets {
+		if now.Sub(v.deletedTime) > recentCPUSetTTL {
+			delete(r.cpusets, key)
+		}
+	}
+	r.cpusets[recentCPUSetKey(pod.Spec.NodeName, pod)] = &recentCPUSet{
+		cpuset:      cpuset,
+		deletedTime: now,
+	}
+}
+
+// get returns the CPUs allocated to the previous pod with the same name on the node.
+func (r *recentCPUSets) get(nodeName string, pod *corev1.Pod) CPUSet {
+	if r == nil {
+		return NewCPUSet()
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	v := r.cpusets[recentCPUSetKey(nodeName, pod)]
+	if v == nil || timeNowFn().Sub(v.deletedTime) > recentCPUSetTTL {
+		return NewCPUSet()
+	}
+	return v.cpuset
+}
+
+// takeRecentCPUs returns the CPUs allocated to the previous pod with the same name on the node if
+// all of them are still free and the number of CPUs is unchanged, so they can be reused directly.
+func (p *Plugin) takeRecentCPUs(nodeName string, pod *corev1.Pod, numCPUsNeeded int) (CPUSet, bool) {
+	cpus := p.recentCPUSets.get(nodeName, pod)
+	if cpus.IsEmpty() || cpus.Count() != numCPUsNeeded {
+		return cpus, false
+	}
+	availableCPUs, allocated, err := p.cpuManager.GetAvailableCPUs(nodeName)
+	if err != nil || !cpus.IsSubsetOf(availableCPUs) {
+		return cpus, false
+	}
+	for _, cpu := range cpus.ToSliceNoSort() {
+		// the CPUs shared with the other pods may be constrained by their exclusive policies
+		if _, ok := allocated[cpu]; ok {
+			return cpus, false
+		}
+	}
+	return cpus, true
+}
@@ -22,6 +22,7 @@ import (
 	nrtv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
 	nrtclientset "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/clientset/versioned"
 	nrtinformers "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/informers/externalversions"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
@@ -118,6 +119,44 @@ func (m *nodeResourceTopologyEventHandler) updateNodeResourceTopology(oldNodeRes
 		}
 	}
 
+	var offloadReservedCPUs CPUSet
+	cpuReservation, err := extension.GetNodeCPUReservation(newNodeResTopology.Annotations)
+	if err != nil {
+		klog.Errorf("Failed to GetNodeCPUReservation from NodeResourceTopology %s, err: %v", newNodeResTopology.Name, err)
+	} else {
+		offloadReservedCPUs, err = Parse(cpuReservation.ReservedCPUs)
+		if err != nil {
+			klog.Errorf("Failed to Parse offload reserved CPUs %s, err: %v", cpuReservation.ReservedCPUs, err)
+		}
+	}
+
+	// the CPUs kept in the shared pool by koordlet can't be allocated exclusively
+	var sharedPoolCPUs CPUSet
+	cpuPoolLayout, err := extension.GetNodeCPUPoolLayout(newNodeResTopology.Annotations)
+	if err != nil {
+		klog.Errorf("Failed to GetNodeCPUPoolLayout from NodeResourceTopology %s, err: %v", newNodeResTopology.Name, err)
+	} else if cpuPoolLayout != nil {
+		sharedPoolCPUs, err = Parse(cpuPoolLayout.SharedCPUs)
+		if err != nil {
+			klog.Errorf("Failed to Parse shared pool CPUs %s, err: %v", cpuPoolLayout.SharedCPUs, err)
+		}
+	}
+
+	var numaNodeResources map[int]corev1.ResourceList
+	numaMemory, err := extension.GetNUMAMemory(newNodeResTopology.Annotations)
+	if err != nil {
+		klog.Errorf("Failed to GetNUMAMemory from NodeResourceTopology %s, err: %v", newNodeResTopology.Name, err)
+	} else if len(numaMemory.Detail) > 0 {
+		numaNodeResources = make(map[int]corev1.ResourceList, len(numaMemory.Detail))
+		for _, v := range numaMemory.Detail {
+			resources := corev1.ResourceList{corev1.ResourceMemory: v.Capacity}
+			for name, quantity := range v.HugePages {
+				resources[name] = quantity
+			}
+			numaNodeResources[int(v.Node)] = resources
+		}
+	}
+
 	reportedCPUTopology, err := extension.GetCPUTopology(newNodeResTopology.Annotations)
 	if err != nil {
 		klog.Errorf("Failed to GetCPUTopology, name: %s, err: %v", newNodeResTopology.Name, err)
@@ -126,14 +165,17 @@ func (m *nodeResourceTopologyEventHandler) updateNodeResourceTopology(oldNodeRes
 	cpuTopology := convertCPUTopology(reportedCPUTopology)
 	reservedCPUs := m.getPodAllocsCPUSet(podCPUAllocs)
 	reservedCPUs = reservedCPUs.Union(kubeletReservedCPUs)
+	reservedCPUs = reservedCPUs.Union(offloadReservedCPUs)
+	reservedCPUs = reservedCPUs.Union(sharedPoolCPUs)
 
 	nodeName := newNodeResTopology.Name
 	m.topologyManager.UpdateCPUTopologyOptions(nodeName, func(options *CPUTopologyOptions) {
 		*options = CPUTopologyOptions{
-			CPUTopology:  cpuTopology,
-			ReservedCPUs: reservedCPUs,
-			Policy:       kubeletPolicy,
-			MaxRefCount:  options.MaxRefCount,
+			CPUTopology:       cpuTopology,
+			ReservedCPUs:      reservedCPUs,
+			Policy:            kubeletPolicy,
+			MaxRefCount:       options.MaxRefCount,
+			NUMANodeResources: numaNodeResources,
 		}
 	})
 }
@@ -0,0 +1,200 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservation
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+// syncBulkReservation creates the child reservations for the replicas of the bulk reservation, deletes the
+// unallocated ones exceeding the replicas, and reports how many of them are available in the status.
+func (p *Plugin) syncBulkReservation(ctx context.Context, r *schedulingv1alpha1.Reservation) error {
+	children, err := p.listBulkReservationChildren(r)
+	if err != nil {
+		return err
+	}
+
+	replicas := int(*r.Spec.Replicas)
+	var created, available int32
+	for i := 0; i < replicas; i++ {
+		name := getBulkReservationChildName(r, i)
+		child, ok := children[name]
+		if !ok {
+			child, err = p.client.Reservations().Create(ctx, newBulkReservationChild(r, i), metav1.CreateOptions{})
+			if errors.IsAlreadyExists(err) {
+				// the informer cache is stale, count it at the next sync
+				continue
+			} else if err != nil {
+				return err
+			}
+			klog.V(4).InfoS("create child reservation for bulk reservation", "reservation", klog.KObj(r), "child", name)
+		}
+		delete(children, name)
+		created++
+		if util.IsReservationAvailable(child) {
+			available++
+		}
+	}
+	// the children left exceed the replicas, keep the ones allocated by owners until they are released
+	for _, child := range children {
+		if len(child.Status.CurrentOwners) > 0 {
+			continue
+		}
+		err = p.client.Reservations().Delete(ctx, child.Name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		klog.V(4).InfoS("delete child reservation exceeding the replicas", "reservation", klog.KObj(r), "child", child.Name)
+	}
+
+	return p.updateBulkReservationStatus(ctx, r, created, available)
+}
+
+// syncBulkReservationOfChild refreshes the status of the bulk reservation when the availability of its child changes.
+func (p *Plugin) syncBulkReservationOfChild(child *schedulingv1alpha1.Reservation) {
+	parentName := child.Labels[apiext.LabelReservationParent]
+	if parentName == "" {
+		return
+	}
+	r, err := p.rLister.Get(parentName)
+	if err != nil || !util.IsBulkReservation(r) || !metav1.IsControlledBy(child, r) ||
+		util.IsReservationFailed(r) || util.IsReservationSucceeded(r) {
+		return
+	}
+	if err = p.syncBulkReservation(context.TODO(), r); err != nil {
+		klog.Warningf("failed to sync bulk reservation %s, err: %s", klog.KObj(r), err)
+	}
+}
+
+// listBulkReservationChildren returns the child reservations controlled by the bulk reservation, key is the name.
+func (p *Plugin) listBulkReservationChildren(r *schedulingv1alpha1.Reservation) (map[string]*schedulingv1alpha1.Reservation, error) {
+	rList, err := p.rLister.List(labels.SelectorFromSet(labels.Set{apiext.LabelReservationParent: r.Name}))
+	if err != nil {
+		return nil, err
+	}
+	children := map[string]*schedulingv1alpha1.Reservation{}
+	for _, child := range rList {
+		// the reservations re-created from the children are not controlled by the bulk reservation
+		if metav1.IsControlledBy(child, r) {
+			children[child.Name] = child
+		}
+	}
+	return children, nil
+}
+
+func (p *Plugin) updateBulkReservationStatus(ctx context.Context, r *schedulingv1alpha1.Reservation, created, available int32) error {
+	if r.Status.Replicas == created && r.Status.AvailableReplicas == available &&
+		r.Status.Phase == getBulkReservationPhase(r, available) {
+		return nil
+	}
+	return util.RetryOnConflictOrTooManyRequests(func() error {
+		curR, err := p.rLister.Get(r.Name)
+		if errors.IsNotFound(err) {
+			klog.V(4).InfoS("reservation not found, abort the update", "reservation", klog.KObj(r))
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if !util.IsBulkReservation(curR) || util.IsReservationFailed(curR) || util.IsReservationSucceeded(curR) {
+			return nil
+		}
+		curR = curR.DeepCopy()
+		setBulkReservationStatus(curR, created, available)
+		_, err = p.client.Reservations().UpdateStatus(ctx, curR, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// setBulkReservationStatus sets the bulk reservation Available if all the replicas are available, otherwise it keeps
+// Pending and reports the partial fulfillment in the Ready condition.
+func setBulkReservationStatus(r *schedulingv1alpha1.Reservation, created, available int32) {
+	r.Status.Replicas = created
+	r.Status.AvailableReplicas = available
+
+	r.Status.Phase = getBulkReservationPhase(r, available)
+	condition := schedulingv1alpha1.ReservationCondition{
+		Type:    schedulingv1alpha1.ReservationConditionReady,
+		Status:  schedulingv1alpha1.ConditionStatusFalse,
+		Reason:  schedulingv1alpha1.ReasonReservationPartiallyAvailable,
+		Message: fmt.Sprintf("%d/%d reservations are available", available, *r.Spec.Replicas),
+	}
+	if r.Status.Phase == schedulingv1alpha1.ReservationAvailable {
+		condition.Status = schedulingv1alpha1.ConditionStatusTrue
+		condition.Reason = schedulingv1alpha1.ReasonReservationAvailable
+	}
+
+	now := metav1.Now()
+	condition.LastProbeTime = now
+	condition.LastTransitionTime = now
+	for i := range r.Status.Conditions {
+		if r.Status.Conditions[i].Type != schedulingv1alpha1.ReservationConditionReady {
+			continue
+		}
+		if r.Status.Conditions[i].Status == condition.Status {
+			condition.LastTransitionTime = r.Status.Conditions[i].LastTransitionTime
+		}
+		r.Status.Conditions[i] = condition
+		return
+	}
+	r.Status.Conditions = append(r.Status.Conditions, condition)
+}
+
+func getBulkReservationPhase(r *schedulingv1alpha1.Reservation, available int32) schedulingv1alpha1.ReservationPhase {
+	if available >= *r.Spec.Replicas {
+		return schedulingv1alpha1.ReservationAvailable
+	}
+	return schedulingv1alpha1.ReservationPending
+}
+
+func getBulkReservationChildName(r *schedulingv1alpha1.Reservation, index int) string {
+	return fmt.Sprintf("%s-%d", r.Name, index)
+}
+
+// newBulkReservationChild creates the child reservation for a replica of the bulk reservation. The child inherits the
+// template, the owners and the expiration, and it's deleted with the bulk reservation.
+func newBulkReservationChild(r *schedulingv1alpha1.Reservation, index int) *schedulingv1alpha1.Reservation {
+	child := &schedulingv1alpha1.Reservation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        getBulkReservationChildName(r, index),
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(r, schedulingv1alpha1.SchemeGroupVersion.WithKind("Reservation")),
+			},
+		},
+		Spec: *r.Spec.DeepCopy(),
+	}
+	for k, v := range r.Labels {
+		child.Labels[k] = v
+	}
+	for k, v := range r.Annotations {
+		child.Annotations[k] = v
+	}
+	child.Labels[apiext.LabelReservationParent] = r.Name
+	child.Spec.Replicas = nil
+	return child
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	koordfake "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
+	koordinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions"
+)
+
+func TestPlugin_syncBulkReservation(t *testing.T) {
+	r := newTestPreemptibleReservation("r-bulk", 5000)
+	r.Status = schedulingv1alpha1.ReservationStatus{}
+	r.Spec.Replicas = pointer.Int32Ptr(3)
+	client := koordfake.NewSimpleClientset(r)
+	informerFactory := koordinformers.NewSharedInformerFactory(client, 0)
+	rInformer := informerFactory.Scheduling().V1alpha1().Reservations()
+	assert.NoError(t, rInformer.Informer().GetStore().Add(r))
+	p := &Plugin{
+		rLister: rInformer.Lister(),
+		client:  client.SchedulingV1alpha1(),
+	}
+
+	// the children are created
+	assert.NoError(t, p.syncBulkReservation(context.TODO(), r))
+	children, err := client.SchedulingV1alpha1().Reservations().List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, children.Items, 4)
+	for i := range children.Items {
+		child := &children.Items[i]
+		if child.Name == r.Name {
+			continue
+		}
+		assert.Equal(t, r.Name, child.Labels[apiext.LabelReservationParent])
+		assert.True(t, metav1.IsControlledBy(child, r))
+		assert.Nil(t, child.Spec.Replicas)
+		assert.Equal(t, r.Spec.Owners, child.Spec.Owners)
+	}
+	got, err := client.SchedulingV1alpha1().Reservations().Get(context.TODO(), r.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), got.Status.Replicas)
+	assert.Equal(t, int32(0), got.Status.AvailableReplicas)
+	assert.Equal(t, schedulingv1alpha1.ReservationPending, got.Status.Phase)
+
+	// two of the children are available, the bulk reservation is partially fulfilled
+	for i := 0; i < 3; i++ {
+		child := newBulkReservationChild(r, i)
+		if i < 2 {
+			setReservationAvailable(child, "test-node-0")
+		}
+		assert.NoError(t, rInformer.Informer().GetStore().Add(child))
+	}
+	assert.NoError(t, p.syncBulkReservation(context.TODO(), got))
+	got, err = client.SchedulingV1alpha1().Reservations().Get(context.TODO(), r.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), got.Status.AvailableReplicas)
+	assert.Equal(t, schedulingv1alpha1.ReservationPending, got.Status.Phase)
+	assert.Equal(t, schedulingv1alpha1.ReasonReservationPartiallyAvailable, got.Status.Conditions[0].Reason)
+
+	// scale down, the unallocated child exceeding the replicas is deleted
+	got.Spec.Replicas = pointer.Int32Ptr(2)
+	assert.NoError(t, rInformer.Informer().GetStore().Update(got))
+	assert.NoError(t, p.syncBulkReservation(context.TODO(), got))
+	_, err = client.SchedulingV1alpha1().Reservations().Get(context.TODO(), getBulkReservationChildName(r, 2), metav1.GetOptions{})
+	assert.Error(t, err)
+	got, err = client.SchedulingV1alpha1().Reservations().Get(context.TODO(), r.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), got.Status.AvailableReplicas)
+	assert.Equal(t, schedulingv1alpha1.ReservationAvailable, got.Status.Phase)
+	assert.Equal(t, schedulingv1alpha1.ConditionStatusTrue, got.Status.Conditions[0].Status)
+}
@@ -46,15 +46,30 @@ func (p *Plugin) gcReservations() {
 	for _, r := range rList {
 		// expire reservations
 		// the reserve pods of expired reservations would be dequeue or removed from cache by the scheduler handler.
-		if isReservationNeedExpiration(r) {
+		if isReservationNeedExpiration(r) && isReservationNeedRenewal(r) {
+			// the reservation in use is renewed for another TTL instead of expired
+			if err = p.renewReservation(r); err != nil {
+				klog.Warningf("failed to renew reservation %s, err: %s", klog.KObj(r), err)
+			}
+		} else if isReservationNeedExpiration(r) {
 			// marked as expired in cache even if the reservation is failed to set expired
 			if err = p.expireReservation(r); err != nil {
 				klog.Warningf("failed to update reservation %s as expired, err: %s", klog.KObj(r), err)
 			}
+		} else if util.IsBulkReservation(r) && !util.IsReservationFailed(r) && !util.IsReservationSucceeded(r) {
+			// create the child reservations and report the partial fulfillment
+			if err = p.syncBulkReservation(context.TODO(), r); err != nil {
+				klog.Warningf("failed to sync bulk reservation %s, err: %s", klog.KObj(r), err)
+			}
+		} else if util.IsReservationActive(r) && p.isReservationNeedRelocation(r) {
+			// relocate the reservation missed by the node handler
+			if err = p.relocateReservation(context.TODO(), r); err != nil {
+				klog.Warningf("failed to relocate reservation %s, err: %s", klog.KObj(r), err)
+			}
 		} else if util.IsReservationActive(r) {
 			// sync active reservation for correct owner statuses
 			p.syncActiveReservation(r)
-		} else if util.IsReservationExpired(r) || util.IsReservationSucceeded(r) {
+		} else if util.IsReservationExpired(r) || util.IsReservationSucceeded(r) || isReservationCancelled(r) {
 			p.reservationCache.AddToInactive(r)
 		}
 	}
@@ -124,6 +139,30 @@ func (p *Plugin) expireReservation(r *schedulingv1alpha1.Reservation) error {
 	})
 }
 
+func (p *Plugin) renewReservation(r *schedulingv1alpha1.Reservation) error {
+	return util.RetryOnConflictOrTooManyRequests(func() error {
+		curR, err := p.rLister.Get(r.Name)
+		if errors.IsNotFound(err) {
+			klog.V(4).InfoS("reservation not found, abort the renewal",
+				"reservation", klog.KObj(r))
+			return nil
+		} else if err != nil {
+			klog.V(3).InfoS("failed to get reservation",
+				"reservation", klog.KObj(r), "err", err)
+			return err
+		}
+
+		curR = curR.DeepCopy()
+		now := metav1.Now()
+		curR.Status.RenewTime = &now
+		_, err = p.client.Reservations().UpdateStatus(context.TODO(), curR, metav1.UpdateOptions{})
+		if err == nil {
+			klog.V(4).InfoS("renew reservation", "reservation", klog.KObj(curR), "owners", len(curR.Status.CurrentOwners))
+		}
+		return err
+	})
+}
+
 func (p *Plugin) syncActiveReservation(r *schedulingv1alpha1.Reservation) {
 	var actualOwners, missedOwners []corev1.ObjectReference
 	actualAllocated := util.NewZeroResourceList()
@@ -140,6 +179,13 @@ func (p *Plugin) syncActiveReservation(r *schedulingv1alpha1.Reservation) {
 			missedOwners = append(missedOwners, owner)
 			continue
 		}
+		// the terminated owners release the reserved resources, so the sequential owners can allocate them
+		if util.IsPodTerminated(pod) {
+			klog.V(5).InfoS("release the reservation allocated by the terminated owner pod",
+				"reservation", klog.KObj(r), "pod", klog.KObj(pod), "phase", pod.Status.Phase)
+			missedOwners = append(missedOwners, owner)
+			continue
+		}
 		actualOwners = append(actualOwners, owner)
 		req, _ := resourceapi.PodRequestsAndLimits(pod)
 		actualAllocated = quotav1.Add(actualAllocated, req)
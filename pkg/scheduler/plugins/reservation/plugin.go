@@ -78,6 +78,7 @@ type Plugin struct {
 	informer         cache.SharedIndexInformer
 	rLister          listerschedulingv1alpha1.ReservationLister
 	podLister        listercorev1.PodLister
+	nodeLister       listercorev1.NodeLister
 	client           clientschedulingv1alpha1.SchedulingV1alpha1Interface // for updates
 	parallelizeUntil parallelizeUntilFunc
 	reservationCache *reservationCache
@@ -113,6 +114,7 @@ func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error)
 		informer:         reservationInformer,
 		rLister:          reservationInterface.Lister(),
 		podLister:        extendedHandle.SharedInformerFactory().Core().V1().Pods().Lister(),
+		nodeLister:       extendedHandle.SharedInformerFactory().Core().V1().Nodes().Lister(),
 		client:           extendedHandle.KoordinatorClientSet().SchedulingV1alpha1(),
 		parallelizeUntil: defaultParallelizeUntil(handle),
 		reservationCache: newReservationCache(),
@@ -124,8 +126,13 @@ func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error)
 		UpdateFunc: p.handleOnUpdate,
 		DeleteFunc: p.handleOnDelete,
 	})
-	// handle reservations on deleted nodes
+	// handle reservations on deleted nodes, and relocate reservations on cordoned or not ready nodes
 	extendedHandle.SharedInformerFactory().Core().V1().Nodes().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if p.isRelocationEnabled() {
+				p.handleOnNodeUpdate(oldObj, newObj)
+			}
+		},
 		DeleteFunc: func(obj interface{}) {
 			switch t := obj.(type) {
 			case *corev1.Node:
@@ -233,6 +240,9 @@ func (p *Plugin) PostFilter(ctx context.Context, state *framework.CycleState, po
 		// return err to stop default preemption
 		return nil, framework.NewStatus(framework.Error)
 	}
+	if p.args != nil && p.args.EnablePreemption != nil && *p.args.EnablePreemption {
+		return p.preemptReservations(ctx, state, pod, filteredNodeStatusMap)
+	}
 	return nil, framework.NewStatus(framework.Unschedulable)
 }
 
@@ -427,6 +437,7 @@ func (p *Plugin) Unreserve(ctx context.Context, cycleState *framework.CycleState
 	unreserved := target.DeepCopy()
 	err := removeReservationAllocated(unreserved, pod)
 	if err == nil {
+		revertReservationConsumed(unreserved)
 		p.reservationCache.Unassume(unreserved, true)
 	} else {
 		klog.V(4).InfoS("Unreserve failed to unassume reservation in cache, current owner not matched",
@@ -462,6 +473,7 @@ func (p *Plugin) Unreserve(ctx context.Context, cycleState *framework.CycleState
 				"reservation", klog.KObj(curR), "pod", klog.KObj(pod), "err", err1)
 			return nil
 		}
+		revertReservationConsumed(curR)
 
 		_, err1 = p.client.Reservations().UpdateStatus(context.TODO(), curR, metav1.UpdateOptions{})
 		if err1 != nil {
@@ -634,6 +646,11 @@ func (p *Plugin) handleOnAdd(obj interface{}) {
 		p.reservationCache.AddToActive(r)
 	} else if util.IsReservationFailed(r) || util.IsReservationSucceeded(r) {
 		p.reservationCache.AddToInactive(r)
+	} else if util.IsBulkReservation(r) {
+		// create the child reservations without waiting for the GC turn
+		if err := p.syncBulkReservation(context.TODO(), r); err != nil {
+			klog.Warningf("failed to sync bulk reservation %s, err: %s", klog.KObj(r), err)
+		}
 	}
 	klog.V(5).InfoS("reservation cache add", "reservation", klog.KObj(r))
 }
@@ -664,6 +681,9 @@ func (p *Plugin) handleOnUpdate(oldObj, newObj interface{}) {
 	} else if util.IsReservationFailed(newR) || util.IsReservationSucceeded(newR) {
 		p.reservationCache.AddToInactive(newR)
 	}
+	if util.IsReservationAvailable(oldR) != util.IsReservationAvailable(newR) {
+		p.syncBulkReservationOfChild(newR)
+	}
 	klog.V(5).InfoS("reservation cache update", "reservation", klog.KObj(newR))
 }
 
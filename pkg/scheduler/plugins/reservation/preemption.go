@@ -0,0 +1,357 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	// ErrReasonNoPreemptibleReservation is the reason for no reservation can be preempted to make the pod fit.
+	ErrReasonNoPreemptibleReservation = "no preemptible reservation helps the pod to fit"
+)
+
+// preemptReservations tries to make room for the unschedulable pod by preempting the reservations with lower
+// priority. The node which needs the fewest reservations to be preempted is nominated.
+// A preempted reservation which is allocated by some owners is shrunk to the allocated resources, otherwise it
+// is cancelled.
+func (p *Plugin) preemptReservations(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, filteredNodeStatusMap framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+	var nominatedNode string
+	var victims []*schedulingv1alpha1.Reservation
+	for nodeName, status := range filteredNodeStatusMap {
+		// the node can't help even if all the reservations are preempted
+		if status.Code() != framework.Unschedulable {
+			continue
+		}
+		nodeVictims := p.selectReservationVictimsOnNode(ctx, state, pod, nodeName)
+		if len(nodeVictims) <= 0 {
+			continue
+		}
+		if victims == nil || len(nodeVictims) < len(victims) ||
+			len(nodeVictims) == len(victims) && nodeName < nominatedNode {
+			nominatedNode = nodeName
+			victims = nodeVictims
+		}
+	}
+	if len(victims) <= 0 {
+		return nil, framework.NewStatus(framework.Unschedulable, ErrReasonNoPreemptibleReservation)
+	}
+
+	for _, r := range victims {
+		if err := p.preemptReservation(ctx, pod, r); err != nil {
+			klog.Warningf("failed to preempt reservation %s for pod %s, err: %v", klog.KObj(r), klog.KObj(pod), err)
+			return nil, framework.AsStatus(err)
+		}
+	}
+	klog.V(4).InfoS("preempted reservations for pod", "pod", klog.KObj(pod), "node", nominatedNode, "reservations", len(victims))
+	return &framework.PostFilterResult{NominatedNodeName: nominatedNode}, framework.NewStatus(framework.Success)
+}
+
+// selectReservationVictimsOnNode returns the reservations on the node to preempt for the pod, the reservations with
+// lower priority are preempted first. It returns nil if the pod can't fit the node after preemption.
+func (p *Plugin) selectReservationVictimsOnNode(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, nodeName string) []*schedulingv1alpha1.Reservation {
+	nodeInfo, err := p.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		klog.V(5).InfoS("failed to get node info for reservation preemption", "node", nodeName, "err", err)
+		return nil
+	}
+	rOnNode, err := p.informer.GetIndexer().ByIndex(NodeNameIndex, nodeName)
+	if err != nil {
+		klog.V(4).InfoS("failed to list reservations on node for preemption", "node", nodeName, "err", err)
+		return nil
+	}
+	var candidates []*schedulingv1alpha1.Reservation
+	for _, obj := range rOnNode {
+		r, ok := obj.(*schedulingv1alpha1.Reservation)
+		if ok && isReservationPreemptible(r, pod, p.args.PreemptionPriorityThreshold) {
+			candidates = append(candidates, r)
+		}
+	}
+	if len(candidates) <= 0 {
+		return nil
+	}
+	sortReservationsForPreemption(candidates)
+
+	nodeInfoCopy := nodeInfo.Clone()
+	var victims []*schedulingv1alpha1.Reservation
+	for _, r := range candidates {
+		if err = nodeInfoCopy.RemovePod(util.NewReservePod(r)); err != nil {
+			klog.V(5).InfoS("failed to remove reserve pod from node info", "reservation", klog.KObj(r), "err", err)
+			continue
+		}
+		if shrunk := getShrunkReservation(r); shrunk != nil {
+			nodeInfoCopy.AddPod(util.NewReservePod(shrunk))
+		}
+		victims = append(victims, r)
+		if status := p.handle.RunFilterPlugins(ctx, state.Clone(), pod, nodeInfoCopy).Merge(); status.IsSuccess() {
+			return victims
+		}
+	}
+	return nil
+}
+
+// preemptReservation shrinks or cancels the reservation and re-creates the preempted part if it is required.
+func (p *Plugin) preemptReservation(ctx context.Context, pod *corev1.Pod, r *schedulingv1alpha1.Reservation) error {
+	released := getReservationReleasable(r)
+	msg := fmt.Sprintf("preempted by pod %s/%s", pod.Namespace, pod.Name)
+	done, shrunk, err := p.releaseReservation(ctx, r, schedulingv1alpha1.ReasonReservationPreempted, msg)
+	if err != nil || !done {
+		return err
+	}
+
+	action := "cancelled"
+	if shrunk {
+		action = "shrunk"
+	}
+	p.handle.EventRecorder().Eventf(r, pod, corev1.EventTypeWarning, schedulingv1alpha1.ReasonReservationPreempted, "Preempting",
+		"Reservation is %s on node %s to make room for pod %s/%s", action, util.GetReservationNodeName(r), pod.Namespace, pod.Name)
+
+	if r.Annotations[apiext.AnnotationReservationRecreateOnPreemption] != "true" {
+		return nil
+	}
+	p.recreateReservation(ctx, r, released, apiext.AnnotationReservationPreemptedFrom, "Preempting")
+	return nil
+}
+
+// releaseReservation releases the unallocated resources of the reservation. The reservation allocated by some owners
+// is shrunk to the allocated resources, otherwise it is cancelled with the reason.
+// It returns whether the reservation is released and whether it is shrunk.
+func (p *Plugin) releaseReservation(ctx context.Context, r *schedulingv1alpha1.Reservation, reason, msg string) (bool, bool, error) {
+	var done, shrunk bool
+	err := util.RetryOnConflictOrTooManyRequests(func() error {
+		done, shrunk = false, false
+		curR, err := p.rLister.Get(r.Name)
+		if errors.IsNotFound(err) {
+			klog.V(4).InfoS("reservation not found, abort the release", "reservation", klog.KObj(r))
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if !util.IsReservationActive(curR) || quotav1.IsZero(getReservationReleasable(curR)) {
+			klog.V(4).InfoS("skip releasing the reservation not active or fully allocated", "reservation", klog.KObj(curR))
+			return nil
+		}
+
+		newR := getShrunkReservation(curR)
+		if newR == nil {
+			// no owner allocates the reservation, just cancel it
+			curR = curR.DeepCopy()
+			setReservationCancelled(curR, reason, msg)
+			_, err = p.client.Reservations().UpdateStatus(ctx, curR, metav1.UpdateOptions{})
+			if err != nil {
+				return err
+			}
+			p.reservationCache.AddToInactive(curR)
+			done = true
+			return nil
+		}
+
+		newR, err = p.client.Reservations().Update(ctx, newR, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		newR = newR.DeepCopy()
+		newR.Status.Allocatable = getReservationRequests(newR)
+		_, err = p.client.Reservations().UpdateStatus(ctx, newR, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		done, shrunk = true, true
+		return nil
+	})
+	return done, shrunk, err
+}
+
+// recreateReservation creates a new reservation for the released resources of the reservation on another node.
+// The name of the original reservation is recorded in the annotation of the new one.
+func (p *Plugin) recreateReservation(ctx context.Context, r *schedulingv1alpha1.Reservation, released corev1.ResourceList, annotationKey, action string) {
+	newR := newRecreatedReservation(r, released)
+	newR.Annotations[annotationKey] = r.Name
+	recreated, err := p.client.Reservations().Create(ctx, newR, metav1.CreateOptions{})
+	if err != nil {
+		// the release has been done, only record the failure
+		klog.Warningf("failed to re-create the released reservation %s, err: %v", klog.KObj(r), err)
+		p.handle.EventRecorder().Eventf(r, nil, corev1.EventTypeWarning, "FailedRecreate", action,
+			"Failed to re-create the released reservation: %v", err)
+		return
+	}
+	p.handle.EventRecorder().Eventf(recreated, r, corev1.EventTypeNormal, "Recreated", action,
+		"Reservation is re-created from the released reservation %s", r.Name)
+}
+
+// isReservationPreemptible checks if the reservation can be preempted by the pod. The reservation must be available,
+// have lower priority than both the pod and the threshold, not be allocatable to the pod and have unallocated
+// resources to release.
+func isReservationPreemptible(r *schedulingv1alpha1.Reservation, pod *corev1.Pod, threshold *int32) bool {
+	if !util.IsReservationAvailable(r) || r.Spec.Template == nil {
+		return false
+	}
+	priority := getReservationPriority(r)
+	if priority >= corev1helpers.PodPriority(pod) || threshold != nil && priority >= *threshold {
+		return false
+	}
+	if matchReservationOwners(pod, r) {
+		return false
+	}
+	return !quotav1.IsZero(getReservationReleasable(r))
+}
+
+func getReservationPriority(r *schedulingv1alpha1.Reservation) int32 {
+	if r.Spec.Template != nil && r.Spec.Template.Spec.Priority != nil {
+		return *r.Spec.Template.Spec.Priority
+	}
+	return 0
+}
+
+// sortReservationsForPreemption sorts the reservations by priority ascending, and the idle and newer ones come first.
+func sortReservationsForPreemption(rList []*schedulingv1alpha1.Reservation) {
+	sort.SliceStable(rList, func(i, j int) bool {
+		pi, pj := getReservationPriority(rList[i]), getReservationPriority(rList[j])
+		if pi != pj {
+			return pi < pj
+		}
+		if len(rList[i].Status.CurrentOwners) != len(rList[j].Status.CurrentOwners) {
+			return len(rList[i].Status.CurrentOwners) < len(rList[j].Status.CurrentOwners)
+		}
+		return rList[j].CreationTimestamp.Before(&rList[i].CreationTimestamp)
+	})
+}
+
+// getReservationReleasable returns the reserved resources not allocated by the owners.
+func getReservationReleasable(r *schedulingv1alpha1.Reservation) corev1.ResourceList {
+	requests := getReservationRequests(r)
+	return quotav1.RemoveZeros(quotav1.SubtractWithNonNegativeResult(requests, r.Status.Allocated))
+}
+
+// getShrunkReservation returns a copy of the reservation which only reserves the resources allocated by the owners.
+// It returns nil if the reservation is not allocated by any owner.
+func getShrunkReservation(r *schedulingv1alpha1.Reservation) *schedulingv1alpha1.Reservation {
+	if len(r.Status.CurrentOwners) <= 0 {
+		return nil
+	}
+	requests := getReservationRequests(r)
+	allocated := quotav1.Mask(r.Status.Allocated, quotav1.ResourceNames(requests))
+	shrunk := r.DeepCopy()
+	setReservationRequests(shrunk, allocated)
+	return shrunk
+}
+
+// setReservationRequests sets the requests of the reservation template to the resources, which are all put in the
+// first container.
+func setReservationRequests(r *schedulingv1alpha1.Reservation, requests corev1.ResourceList) {
+	spec := &r.Spec.Template.Spec
+	for i := range spec.InitContainers {
+		spec.InitContainers[i].Resources.Requests = nil
+	}
+	for i := range spec.Containers {
+		spec.Containers[i].Resources.Requests = nil
+	}
+	if len(spec.Containers) <= 0 {
+		spec.Containers = []corev1.Container{{Name: "reserved"}}
+	}
+	spec.Containers[0].Resources.Requests = requests.DeepCopy()
+	spec.Overhead = nil
+}
+
+// setReservationCancelled marks the reservation as failed with the reason, e.g. Preempted, Relocated.
+func setReservationCancelled(r *schedulingv1alpha1.Reservation, reason, msg string) {
+	r.Status.Phase = schedulingv1alpha1.ReservationFailed
+	condition := schedulingv1alpha1.ReservationCondition{
+		Type:               schedulingv1alpha1.ReservationConditionReady,
+		Status:             schedulingv1alpha1.ConditionStatusFalse,
+		Reason:             reason,
+		Message:            msg,
+		LastProbeTime:      metav1.Now(),
+		LastTransitionTime: metav1.Now(),
+	}
+	for i := range r.Status.Conditions {
+		if r.Status.Conditions[i].Type == schedulingv1alpha1.ReservationConditionReady {
+			r.Status.Conditions[i] = condition
+			return
+		}
+	}
+	r.Status.Conditions = append(r.Status.Conditions, condition)
+}
+
+// newRecreatedReservation returns a new reservation reserving the given resources of the reservation, which
+// avoids the node of the original reservation.
+func newRecreatedReservation(r *schedulingv1alpha1.Reservation, requests corev1.ResourceList) *schedulingv1alpha1.Reservation {
+	newR := &schedulingv1alpha1.Reservation{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: r.Name + "-",
+			Labels:       map[string]string{},
+			Annotations:  map[string]string{},
+		},
+		Spec: *r.Spec.DeepCopy(),
+	}
+	for k, v := range r.Labels {
+		newR.Labels[k] = v
+	}
+	for k, v := range r.Annotations {
+		newR.Annotations[k] = v
+	}
+	// the origin is recorded by the caller
+	delete(newR.Annotations, apiext.AnnotationReservationPreemptedFrom)
+	delete(newR.Annotations, apiext.AnnotationReservationRelocatedFrom)
+	setReservationRequests(newR, requests)
+
+	// the expiration of the original reservation is inherited, and the new one should not go back to the same node
+	newR.Spec.Template.Spec.NodeName = ""
+	excludeNodeFromReservation(newR, util.GetReservationNodeName(r))
+	return newR
+}
+
+func excludeNodeFromReservation(r *schedulingv1alpha1.Reservation, nodeName string) {
+	spec := &r.Spec.Template.Spec
+	if spec.Affinity == nil {
+		spec.Affinity = &corev1.Affinity{}
+	}
+	if spec.Affinity.NodeAffinity == nil {
+		spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	nodeAffinity := spec.Affinity.NodeAffinity
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+	nodeSelector := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(nodeSelector.NodeSelectorTerms) <= 0 {
+		nodeSelector.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+	}
+	// the terms are ORed, so the node is excluded from every term
+	requirement := corev1.NodeSelectorRequirement{
+		Key:      metav1.ObjectNameField,
+		Operator: corev1.NodeSelectorOpNotIn,
+		Values:   []string{nodeName},
+	}
+	for i := range nodeSelector.NodeSelectorTerms {
+		nodeSelector.NodeSelectorTerms[i].MatchFields = append(nodeSelector.NodeSelectorTerms[i].MatchFields, requirement)
+	}
+}
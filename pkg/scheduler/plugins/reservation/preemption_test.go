@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/utils/pointer"
+
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+func newTestPreemptibleReservation(name string, priority int32) *schedulingv1alpha1.Reservation {
+	r := &schedulingv1alpha1.Reservation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			UID:               types.UID("uid-" + name),
+			CreationTimestamp: metav1.Now(),
+		},
+		Spec: schedulingv1alpha1.ReservationSpec{
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Priority: pointer.Int32Ptr(priority),
+					Containers: []corev1.Container{
+						{
+							Name: "main",
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("4"),
+									corev1.ResourceMemory: resource.MustParse("8Gi"),
+								},
+							},
+						},
+					},
+				},
+			},
+			Owners: []schedulingv1alpha1.ReservationOwner{
+				{
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"app": "reserved"},
+					},
+				},
+			},
+			TTL: &metav1.Duration{Duration: time.Hour},
+		},
+	}
+	setReservationAvailable(r, "test-node-0")
+	return r
+}
+
+func Test_isReservationPreemptible(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-0", Namespace: "default"},
+		Spec:       corev1.PodSpec{Priority: pointer.Int32Ptr(9000)},
+	}
+	r := newTestPreemptibleReservation("r-0", 5000)
+	assert.True(t, isReservationPreemptible(r, pod, nil))
+	// the priority of the reservation is not lower than the threshold
+	assert.False(t, isReservationPreemptible(r, pod, pointer.Int32Ptr(5000)))
+	assert.True(t, isReservationPreemptible(r, pod, pointer.Int32Ptr(6000)))
+
+	// the priority of the reservation is not lower than the pod
+	assert.False(t, isReservationPreemptible(newTestPreemptibleReservation("r-1", 9000), pod, nil))
+
+	// the pod can allocate the reservation
+	ownerPod := pod.DeepCopy()
+	ownerPod.Labels = map[string]string{"app": "reserved"}
+	assert.False(t, isReservationPreemptible(r, ownerPod, nil))
+
+	// nothing to release
+	rAllocated := r.DeepCopy()
+	rAllocated.Status.Allocated = getReservationRequests(r)
+	assert.False(t, isReservationPreemptible(rAllocated, pod, nil))
+}
+
+func Test_getShrunkReservation(t *testing.T) {
+	r := newTestPreemptibleReservation("r-0", 5000)
+	assert.Nil(t, getShrunkReservation(r))
+
+	r.Status.CurrentOwners = []corev1.ObjectReference{{Namespace: "default", Name: "owner-0", UID: "owner-0"}}
+	r.Status.Allocated = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("1"),
+		corev1.ResourceMemory: resource.MustParse("2Gi"),
+	}
+	shrunk := getShrunkReservation(r)
+	assert.NotNil(t, shrunk)
+	assert.True(t, quotav1.Equals(r.Status.Allocated, getReservationRequests(shrunk)))
+	assert.True(t, quotav1.Equals(corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("3"),
+		corev1.ResourceMemory: resource.MustParse("6Gi"),
+	}, getReservationReleasable(r)))
+	// the original one is not changed
+	assert.Equal(t, resource.MustParse("4"), r.Spec.Template.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU])
+}
+
+func Test_newRecreatedReservation(t *testing.T) {
+	r := newTestPreemptibleReservation("r-0", 5000)
+	r.Labels = map[string]string{"app": "test"}
+	released := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3")}
+	newR := newRecreatedReservation(r, released)
+	assert.Equal(t, "r-0-", newR.GenerateName)
+	assert.Equal(t, r.Labels, newR.Labels)
+	assert.True(t, quotav1.Equals(released, getReservationRequests(newR)))
+	assert.Equal(t, schedulingv1alpha1.ReservationStatus{}, newR.Status)
+	assert.Equal(t, []corev1.NodeSelectorTerm{
+		{
+			MatchFields: []corev1.NodeSelectorRequirement{
+				{Key: metav1.ObjectNameField, Operator: corev1.NodeSelectorOpNotIn, Values: []string{"test-node-0"}},
+			},
+		},
+	}, newR.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+}
+
+func Test_sortReservationsForPreemption(t *testing.T) {
+	r0 := newTestPreemptibleReservation("r-0", 5000)
+	r1 := newTestPreemptibleReservation("r-1", 1000)
+	r2 := newTestPreemptibleReservation("r-2", 1000)
+	r2.Status.CurrentOwners = []corev1.ObjectReference{{Namespace: "default", Name: "owner-0", UID: "owner-0"}}
+	r3 := newTestPreemptibleReservation("r-3", 1000)
+	r3.CreationTimestamp = metav1.NewTime(r1.CreationTimestamp.Add(time.Minute))
+	rList := []*schedulingv1alpha1.Reservation{r0, r1, r2, r3}
+	sortReservationsForPreemption(rList)
+	assert.Equal(t, []*schedulingv1alpha1.Reservation{r3, r1, r2, r0}, rList)
+}
+
+func Test_setReservationCancelled(t *testing.T) {
+	r := newTestPreemptibleReservation("r-0", 5000)
+	setReservationCancelled(r, schedulingv1alpha1.ReasonReservationPreempted, "preempted by pod default/pod-0")
+	assert.True(t, isReservationCancelled(r))
+	assert.Equal(t, schedulingv1alpha1.ReservationFailed, r.Status.Phase)
+	assert.Len(t, r.Status.Conditions, 2)
+}
@@ -157,6 +157,8 @@ func (a *AvailableCache) Add(r *schedulingv1alpha1.Reservation) {
 	// such as phase=Available, nodeName != "", requests > 0
 	a.lock.Lock()
 	defer a.lock.Unlock()
+	// replace the previous version, the owners which have released the reservation are no longer tracked
+	a.deleteNoLock(r)
 	rInfo := newReservationInfo(r)
 	a.reservations[util.GetReservationKey(r)] = rInfo
 	nodeName := util.GetReservationNodeName(r)
@@ -169,11 +171,17 @@ func (a *AvailableCache) Add(r *schedulingv1alpha1.Reservation) {
 func (a *AvailableCache) Delete(r *schedulingv1alpha1.Reservation) {
 	a.lock.Lock()
 	defer a.lock.Unlock()
+	a.deleteNoLock(r)
+}
+
+func (a *AvailableCache) deleteNoLock(r *schedulingv1alpha1.Reservation) {
 	if r == nil || len(util.GetReservationNodeName(r)) <= 0 {
 		return
 	}
 	// cleanup r map
-	delete(a.reservations, util.GetReservationKey(r))
+	key := util.GetReservationKey(r)
+	old := a.reservations[key]
+	delete(a.reservations, key)
 	// cleanup nodeToR
 	nodeName := util.GetReservationNodeName(r)
 	rOnNode := a.nodeToR[nodeName]
@@ -186,10 +194,14 @@ func (a *AvailableCache) Delete(r *schedulingv1alpha1.Reservation) {
 	if len(a.nodeToR[nodeName]) <= 0 {
 		delete(a.nodeToR, nodeName)
 	}
-	// cleanup ownerToR
-	for _, owner := range r.Status.CurrentOwners {
+	// cleanup ownerToR, including the owners of the cached version which may have released the reservation
+	owners := r.Status.CurrentOwners
+	if old != nil {
+		owners = append(owners[:len(owners):len(owners)], old.Reservation.Status.CurrentOwners...)
+	}
+	for _, owner := range owners {
 		rInfo := a.ownerToR[getOwnerKey(&owner)]
-		if rInfo != nil && rInfo.Reservation.Name != r.Name {
+		if rInfo != nil && rInfo.Reservation.Name == r.Name {
 			delete(a.ownerToR, getOwnerKey(&owner))
 		}
 	}
@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservation
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+func (p *Plugin) isRelocationEnabled() bool {
+	return p.args != nil && p.args.EnableRelocation != nil && *p.args.EnableRelocation
+}
+
+// isNodeUnavailableForReservation checks if the reservations on the node are useless, i.e. the node is cordoned
+// or not ready.
+func isNodeUnavailableForReservation(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return true
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status != corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// isReservationNeedRelocation checks if the active reservation is on an unavailable node and has resources to release.
+func (p *Plugin) isReservationNeedRelocation(r *schedulingv1alpha1.Reservation) bool {
+	if !p.isRelocationEnabled() || p.nodeLister == nil {
+		return false
+	}
+	node, err := p.nodeLister.Get(util.GetReservationNodeName(r))
+	if err != nil {
+		// the reservations on deleted nodes are expired by the node handler
+		return false
+	}
+	return isNodeUnavailableForReservation(node) && !quotav1.IsZero(getReservationReleasable(r))
+}
+
+func (p *Plugin) handleOnNodeUpdate(oldObj, newObj interface{}) {
+	oldNode, oldOK := oldObj.(*corev1.Node)
+	newNode, newOK := newObj.(*corev1.Node)
+	if !oldOK || !newOK {
+		klog.V(3).InfoS("reservation's node informer update func parse obj failed", "old", oldObj, "new", newObj)
+		return
+	}
+	// only relocate when the node turns unavailable, the missed ones are relocated in the GC turn
+	if isNodeUnavailableForReservation(oldNode) || !isNodeUnavailableForReservation(newNode) {
+		return
+	}
+	p.relocateReservationsOnNode(newNode)
+}
+
+// relocateReservationsOnNode releases the unallocated resources of the active reservations on the unavailable node,
+// and re-creates the reservations for them on the other nodes.
+func (p *Plugin) relocateReservationsOnNode(node *corev1.Node) {
+	rOnNode, err := p.informer.GetIndexer().ByIndex(NodeNameIndex, node.Name)
+	if err != nil {
+		klog.V(4).InfoS("failed to list reservations for node relocation from indexer",
+			"node", node.Name, "err", err)
+		return
+	}
+	for _, obj := range rOnNode {
+		r, ok := obj.(*schedulingv1alpha1.Reservation)
+		if !ok {
+			klog.V(5).Infof("unable to convert to *schedulingv1alpha1.Reservation, obj %T", obj)
+			continue
+		}
+		if !util.IsReservationActive(r) {
+			continue
+		}
+		if err = p.relocateReservation(context.TODO(), r); err != nil {
+			klog.Warningf("failed to relocate reservation %s on node %s, err: %s", klog.KObj(r), node.Name, err)
+		}
+	}
+}
+
+func (p *Plugin) relocateReservation(ctx context.Context, r *schedulingv1alpha1.Reservation) error {
+	released := getReservationReleasable(r)
+	nodeName := util.GetReservationNodeName(r)
+	msg := fmt.Sprintf("node %s is cordoned or not ready", nodeName)
+	done, shrunk, err := p.releaseReservation(ctx, r, schedulingv1alpha1.ReasonReservationRelocated, msg)
+	if err != nil || !done {
+		return err
+	}
+
+	action := "cancelled"
+	if shrunk {
+		action = "shrunk"
+	}
+	p.handle.EventRecorder().Eventf(r, nil, corev1.EventTypeWarning, schedulingv1alpha1.ReasonReservationRelocated, "Relocating",
+		"Reservation is %s since node %s is cordoned or not ready", action, nodeName)
+	klog.V(4).InfoS("relocate reservation", "reservation", klog.KObj(r), "node", nodeName, "action", action)
+
+	// the owners are kept, so the pods allocating the reservation can follow the new one
+	p.recreateReservation(ctx, r, released, apiext.AnnotationReservationRelocatedFrom, "Relocating")
+	return nil
+}
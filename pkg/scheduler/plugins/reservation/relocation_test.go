@@ -0,0 +1,111 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reservation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/scheduling/config"
+)
+
+func Test_isNodeUnavailableForReservation(t *testing.T) {
+	tests := []struct {
+		name string
+		node *corev1.Node
+		want bool
+	}{
+		{
+			name: "ready node",
+			node: &corev1.Node{
+				Status: corev1.NodeStatus{
+					Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "node without ready condition",
+			node: &corev1.Node{},
+			want: false,
+		},
+		{
+			name: "cordoned node",
+			node: &corev1.Node{
+				Spec: corev1.NodeSpec{Unschedulable: true},
+				Status: corev1.NodeStatus{
+					Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "not ready node",
+			node: &corev1.Node{
+				Status: corev1.NodeStatus{
+					Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionUnknown}},
+				},
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isNodeUnavailableForReservation(tt.node))
+		})
+	}
+}
+
+func TestPlugin_isReservationNeedRelocation(t *testing.T) {
+	informerFactory := informers.NewSharedInformerFactory(kubefake.NewSimpleClientset(), 0)
+	nodeInformer := informerFactory.Core().V1().Nodes()
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node-0"},
+		Spec:       corev1.NodeSpec{Unschedulable: true},
+	}
+	assert.NoError(t, nodeInformer.Informer().GetStore().Add(node))
+
+	r := newTestPreemptibleReservation("r-0", 5000)
+	p := &Plugin{nodeLister: nodeInformer.Lister()}
+	// relocation is disabled by default
+	assert.False(t, p.isReservationNeedRelocation(r))
+
+	p.args = &config.ReservationArgs{EnableRelocation: pointer.Bool(true)}
+	assert.True(t, p.isReservationNeedRelocation(r))
+
+	// nothing to release
+	rAllocated := r.DeepCopy()
+	rAllocated.Status.Allocated = getReservationRequests(r)
+	assert.False(t, p.isReservationNeedRelocation(rAllocated))
+
+	// the node is uncordoned
+	node = node.DeepCopy()
+	node.Spec.Unschedulable = false
+	assert.NoError(t, nodeInformer.Informer().GetStore().Update(node))
+	assert.False(t, p.isReservationNeedRelocation(r))
+
+	// the node is not found
+	rOnMissingNode := r.DeepCopy()
+	setReservationAvailable(rOnMissingNode, "test-node-1")
+	assert.False(t, p.isReservationNeedRelocation(rOnMissingNode))
+}
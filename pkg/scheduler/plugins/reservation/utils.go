@@ -60,17 +60,41 @@ func isReservationNeedExpiration(r *schedulingv1alpha1.Reservation) bool {
 		return false
 	}
 	// 3. if both TTL and Expires are set, firstly check Expires
+	// the TTL of a renewed reservation is counted from the last renewal
+	ttlStart := r.CreationTimestamp.Time
+	if r.Status.RenewTime != nil {
+		ttlStart = r.Status.RenewTime.Time
+	}
 	return r.Spec.Expires != nil && time.Now().After(r.Spec.Expires.Time) ||
-		r.Spec.TTL != nil && time.Since(r.CreationTimestamp.Time) > r.Spec.TTL.Duration
+		r.Spec.TTL != nil && time.Since(ttlStart) > r.Spec.TTL.Duration
+}
+
+// isReservationNeedRenewal checks if the expiring reservation should be renewed instead of expired, which
+// requires the Renew policy and the reservation is still allocated by any owner.
+func isReservationNeedRenewal(r *schedulingv1alpha1.Reservation) bool {
+	return r.Spec.ExpirationPolicy == schedulingv1alpha1.ReservationExpirationPolicyRenew &&
+		r.Spec.Expires == nil && r.Spec.TTL != nil &&
+		util.IsReservationAvailable(r) && len(r.Status.CurrentOwners) > 0
+}
+
+// isReservationConsumed checks if the reservation has been allocated by enough owners and is not allocatable anymore.
+func isReservationConsumed(r *schedulingv1alpha1.Reservation) bool {
+	if r.Spec.AllocateOnce {
+		return r.Status.Consumptions >= 1
+	}
+	return r.Spec.ExpirationPolicy == schedulingv1alpha1.ReservationExpirationPolicyConsumed &&
+		r.Spec.MaxConsumptions != nil && r.Status.Consumptions >= *r.Spec.MaxConsumptions
 }
 
 func isReservationNeedCleanup(r *schedulingv1alpha1.Reservation) bool {
 	if r == nil {
 		return true
 	}
-	if util.IsReservationExpired(r) {
+	if util.IsReservationExpired(r) || isReservationCancelled(r) {
 		for _, condition := range r.Status.Conditions {
-			if condition.Reason == schedulingv1alpha1.ReasonReservationExpired {
+			if condition.Reason == schedulingv1alpha1.ReasonReservationExpired ||
+				condition.Reason == schedulingv1alpha1.ReasonReservationPreempted ||
+				condition.Reason == schedulingv1alpha1.ReasonReservationRelocated {
 				return time.Since(condition.LastTransitionTime.Time) > defaultGCDuration
 			}
 		}
@@ -84,6 +108,20 @@ func isReservationNeedCleanup(r *schedulingv1alpha1.Reservation) bool {
 	return false
 }
 
+// isReservationCancelled checks if the reservation is failed since it is preempted or relocated.
+func isReservationCancelled(r *schedulingv1alpha1.Reservation) bool {
+	if !util.IsReservationFailed(r) {
+		return false
+	}
+	for _, condition := range r.Status.Conditions {
+		if condition.Type == schedulingv1alpha1.ReservationConditionReady {
+			return condition.Reason == schedulingv1alpha1.ReasonReservationPreempted ||
+				condition.Reason == schedulingv1alpha1.ReasonReservationRelocated
+		}
+	}
+	return false
+}
+
 func setReservationAvailable(r *schedulingv1alpha1.Reservation, nodeName string) {
 	// just annotate scheduled node at status
 	util.SetReservationNodeName(r, nodeName)
@@ -165,11 +203,12 @@ func setReservationAllocated(r *schedulingv1alpha1.Reservation, pod *corev1.Pod)
 		} else {
 			r.Status.Allocated = quotav1.Add(r.Status.Allocated, requests)
 		}
+		r.Status.Consumptions++
 	} else {
 		// keep old allocated
 		r.Status.CurrentOwners[idx] = owner
 	}
-	if r.Spec.AllocateOnce {
+	if r.Spec.AllocateOnce || isReservationConsumed(r) {
 		setReservationSucceeded(r)
 	}
 }
@@ -226,6 +265,17 @@ func removeReservationAllocated(r *schedulingv1alpha1.Reservation, pod *corev1.P
 	return nil
 }
 
+// revertReservationConsumed reverts the consumption of the owner which failed to allocate the reservation.
+// Unlike the finished owners, the failed one should not count in the consumptions.
+func revertReservationConsumed(r *schedulingv1alpha1.Reservation) {
+	if r.Status.Consumptions > 0 {
+		r.Status.Consumptions--
+	}
+	if util.IsReservationSucceeded(r) && !isReservationConsumed(r) {
+		removeReservationSucceeded(r)
+	}
+}
+
 func removeReservationSucceeded(r *schedulingv1alpha1.Reservation) {
 	// only available reservation can trans to succeeded
 	r.Status.Phase = schedulingv1alpha1.ReservationAvailable
@@ -250,7 +300,8 @@ func getReservationRequests(r *schedulingv1alpha1.Reservation) corev1.ResourceLi
 }
 
 func matchReservation(pod *corev1.Pod, rMeta *reservationInfo) bool {
-	return matchReservationOwners(pod, rMeta.Reservation) && matchReservationResources(pod, rMeta.Reservation, rMeta.Resources)
+	return !isReservationConsumed(rMeta.Reservation) && matchReservationOwners(pod, rMeta.Reservation) &&
+		matchReservationResources(pod, rMeta.Reservation, rMeta.Resources)
 }
 
 func matchReservationResources(pod *corev1.Pod, r *schedulingv1alpha1.Reservation, reservedResources corev1.ResourceList) bool {
@@ -318,6 +369,9 @@ func matchReservationControllerReference(pod *corev1.Pod, controllerRef *schedul
 
 func dumpMatchReservationReason(pod *corev1.Pod, rMeta *reservationInfo) string {
 	var msg strings.Builder
+	if isReservationConsumed(rMeta.Reservation) {
+		msg.WriteString("reservation consumed;")
+	}
 	if !matchReservationOwners(pod, rMeta.Reservation) {
 		msg.WriteString("owner specs not matched;")
 	}
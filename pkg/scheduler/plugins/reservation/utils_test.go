@@ -18,10 +18,13 @@ package reservation
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
 
 	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
@@ -243,3 +246,113 @@ func Test_matchReservationOwners(t *testing.T) {
 		})
 	}
 }
+
+func Test_reservationConsumption(t *testing.T) {
+	r := &schedulingv1alpha1.Reservation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "reserve-pod-0",
+			UID:  "123456",
+		},
+		Spec: schedulingv1alpha1.ReservationSpec{
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU: resource.MustParse("4"),
+								},
+							},
+						},
+					},
+				},
+			},
+			Owners: []schedulingv1alpha1.ReservationOwner{
+				{
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"job": "test"},
+					},
+				},
+			},
+			ExpirationPolicy: schedulingv1alpha1.ReservationExpirationPolicyConsumed,
+			MaxConsumptions:  pointer.Int32Ptr(2),
+		},
+	}
+	setReservationAvailable(r, "test-node-0")
+	newPod := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				UID:       types.UID(name),
+				Labels:    map[string]string{"job": "test"},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("4"),
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	// the first owner consumes the reservation, which is still available after it finishes
+	pod0 := newPod("pod-0")
+	assert.True(t, matchReservation(pod0, newReservationInfo(r)))
+	setReservationAllocated(r, pod0)
+	assert.Equal(t, int32(1), r.Status.Consumptions)
+	assert.Equal(t, schedulingv1alpha1.ReservationAvailable, r.Status.Phase)
+	assert.NoError(t, removeReservationAllocated(r, pod0))
+	assert.Equal(t, int32(1), r.Status.Consumptions)
+
+	// the second owner consumes the reservation at last
+	pod1 := newPod("pod-1")
+	assert.True(t, matchReservation(pod1, newReservationInfo(r)))
+	setReservationAllocated(r, pod1)
+	assert.Equal(t, int32(2), r.Status.Consumptions)
+	assert.Equal(t, schedulingv1alpha1.ReservationSucceeded, r.Status.Phase)
+	assert.False(t, matchReservation(newPod("pod-2"), newReservationInfo(r)))
+
+	// the consumption of the failed owner is reverted
+	assert.NoError(t, removeReservationAllocated(r, pod1))
+	revertReservationConsumed(r)
+	assert.Equal(t, int32(1), r.Status.Consumptions)
+	assert.Equal(t, schedulingv1alpha1.ReservationAvailable, r.Status.Phase)
+	assert.True(t, matchReservation(newPod("pod-2"), newReservationInfo(r)))
+}
+
+func Test_isReservationNeedRenewal(t *testing.T) {
+	now := time.Now()
+	r := &schedulingv1alpha1.Reservation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "reserve-pod-0",
+			CreationTimestamp: metav1.Time{Time: now.Add(-2 * time.Hour)},
+		},
+		Spec: schedulingv1alpha1.ReservationSpec{
+			TTL:              &metav1.Duration{Duration: time.Hour},
+			ExpirationPolicy: schedulingv1alpha1.ReservationExpirationPolicyRenew,
+		},
+		Status: schedulingv1alpha1.ReservationStatus{
+			Phase:    schedulingv1alpha1.ReservationAvailable,
+			NodeName: "test-node-0",
+		},
+	}
+	// the idle reservation expires
+	assert.True(t, isReservationNeedExpiration(r))
+	assert.False(t, isReservationNeedRenewal(r))
+
+	// the reservation in use is renewed
+	r.Status.CurrentOwners = []corev1.ObjectReference{{Namespace: "default", Name: "pod-0", UID: "pod-0"}}
+	assert.True(t, isReservationNeedRenewal(r))
+
+	// the TTL is counted from the last renewal
+	r.Status.RenewTime = &metav1.Time{Time: now.Add(-30 * time.Minute)}
+	assert.False(t, isReservationNeedExpiration(r))
+	r.Status.RenewTime = &metav1.Time{Time: now.Add(-90 * time.Minute)}
+	assert.True(t, isReservationNeedExpiration(r))
+}
@@ -33,10 +33,13 @@ var (
 	// SLO configmap name
 	ConfigNameSpace  = "koordinator-system"
 	SLOCtrlConfigMap = "slo-controller-config"
+	// koord-scheduler configmap name
+	SchedulerConfigMap = "koord-scheduler-config"
 )
 
 func InitFlags(fs *flag.FlagSet) {
 	fs.StringVar(&SLOCtrlConfigMap, "slo-config-name", SLOCtrlConfigMap, "determines the name the slo-controller configmap uses.")
+	fs.StringVar(&SchedulerConfigMap, "scheduler-config-name", SchedulerConfigMap, "determines the name the koord-scheduler configmap uses.")
 	fs.StringVar(&ConfigNameSpace, "config-namespace", ConfigNameSpace, "determines the namespace of configmap uses.")
 }
 
@@ -79,6 +82,46 @@ type CPUBurstCfg struct {
 	NodeStrategies  []NodeCPUBurstCfg             `json:"nodeStrategies,omitempty"`
 }
 
+// +k8s:deepcopy-gen=true
+type NodeCPUReservationCfg struct {
+	// an empty label selector matches all objects while a nil label selector matches no objects
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+	*slov1alpha1.CPUReservationStrategy
+}
+
+// +k8s:deepcopy-gen=true
+type CPUReservationCfg struct {
+	ClusterStrategy *slov1alpha1.CPUReservationStrategy `json:"clusterStrategy,omitempty"`
+	NodeStrategies  []NodeCPUReservationCfg             `json:"nodeStrategies,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+type NodeRuntimeClassCfg struct {
+	// an empty label selector matches all objects while a nil label selector matches no objects
+	NodeSelector           *metav1.LabelSelector              `json:"nodeSelector,omitempty"`
+	RuntimeClassStrategies []slov1alpha1.RuntimeClassStrategy `json:"runtimeClassStrategies,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+type RuntimeClassCfg struct {
+	ClusterStrategies []slov1alpha1.RuntimeClassStrategy `json:"clusterStrategies,omitempty"`
+	NodeStrategies    []NodeRuntimeClassCfg              `json:"nodeStrategies,omitempty"`
+}
+
+// NodeSLORolloutCfg stages the changes of the NodeSLO config across the nodes.
+// +k8s:deepcopy-gen=true
+type NodeSLORolloutCfg struct {
+	// Enable stages the changes, otherwise the changes are applied to all the nodes at once.
+	Enable *bool `json:"enable,omitempty"`
+	// CanaryNodeSelector selects the nodes updated first regardless of the Percentage.
+	CanaryNodeSelector *metav1.LabelSelector `json:"canaryNodeSelector,omitempty"`
+	// Percentage is the percentage of the nodes updated to the latest revision besides the canary nodes.
+	Percentage *int64 `json:"percentage,omitempty"`
+	// MaxEvictions halts the rollout when the pods evicted by koordlet on the updated nodes since the
+	// revision started exceeds it. The rollout is never halted if it is not set.
+	MaxEvictions *int64 `json:"maxEvictions,omitempty"`
+}
+
 // +k8s:deepcopy-gen=true
 type ResourceQOSCfg struct {
 	ClusterStrategy *slov1alpha1.ResourceQOSStrategy `json:"clusterStrategy,omitempty"`
@@ -102,7 +145,11 @@ type ColocationStrategy struct {
 	DegradeTimeMinutes             *int64   `json:"degradeTimeMinutes,omitempty"`
 	UpdateTimeThresholdSeconds     *int64   `json:"updateTimeThresholdSeconds,omitempty"`
 	ResourceDiffThreshold          *float64 `json:"resourceDiffThreshold,omitempty"`
-	ColocationStrategyExtender     `json:",inline"`
+
+	// MetricNodeAggregatePolicy is the durations to aggregate the node usage in percentiles, nil means disabled.
+	MetricNodeAggregatePolicy *slov1alpha1.AggregatePolicy `json:"metricNodeAggregatePolicy,omitempty"`
+
+	ColocationStrategyExtender `json:",inline"`
 }
 
 func NewDefaultColocationCfg() *ColocationCfg {
@@ -139,7 +186,20 @@ func IsColocationStrategyValid(strategy *ColocationStrategy) bool {
 		(strategy.MemoryReclaimThresholdPercent == nil || *strategy.MemoryReclaimThresholdPercent > 0) &&
 		(strategy.DegradeTimeMinutes == nil || *strategy.DegradeTimeMinutes > 0) &&
 		(strategy.UpdateTimeThresholdSeconds == nil || *strategy.UpdateTimeThresholdSeconds > 0) &&
-		(strategy.ResourceDiffThreshold == nil || *strategy.ResourceDiffThreshold > 0)
+		(strategy.ResourceDiffThreshold == nil || *strategy.ResourceDiffThreshold > 0) &&
+		isAggregatePolicyValid(strategy.MetricNodeAggregatePolicy)
+}
+
+func isAggregatePolicyValid(policy *slov1alpha1.AggregatePolicy) bool {
+	if policy == nil {
+		return true
+	}
+	for _, duration := range policy.Durations {
+		if duration.Duration <= 0 {
+			return false
+		}
+	}
+	return true
 }
 
 func IsNodeColocationCfgValid(nodeCfg *NodeColocationCfg) bool {
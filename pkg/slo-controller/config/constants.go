@@ -22,6 +22,12 @@ const (
 	ResourceThresholdConfigKey = "resource-threshold-config"
 	ResourceQOSConfigKey       = "resource-qos-config"
 	CPUBurstConfigKey          = "cpu-burst-config"
+	CPUReservationConfigKey    = "cpu-reservation-config"
+	RuntimeClassConfigKey      = "runtime-class-config"
+	NodeSLORolloutConfigKey    = "nodeslo-rollout-config"
+
+	// SchedulerConfigKey is the key of the KubeSchedulerConfiguration in the koord-scheduler configmap
+	SchedulerConfigKey = "koord-scheduler-config"
 )
 
 /*
@@ -31,6 +37,9 @@ Koordinator uses configmap to manage the configuration of SLO, the configmap is
    - <ResourceThresholdConfigKey>
    - <ResourceQOSConfigKey>
    - <CPUBurstConfigKey>
+   - <CPUReservationConfigKey>
+   - <RuntimeClassConfigKey>
+   - <NodeSLORolloutConfigKey>
 
 et.
   TODO add a sample here
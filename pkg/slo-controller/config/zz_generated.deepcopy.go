@@ -53,6 +53,33 @@ func (in *CPUBurstCfg) DeepCopy() *CPUBurstCfg {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUReservationCfg) DeepCopyInto(out *CPUReservationCfg) {
+	*out = *in
+	if in.ClusterStrategy != nil {
+		in, out := &in.ClusterStrategy, &out.ClusterStrategy
+		*out = new(v1alpha1.CPUReservationStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeStrategies != nil {
+		in, out := &in.NodeStrategies, &out.NodeStrategies
+		*out = make([]NodeCPUReservationCfg, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUReservationCfg.
+func (in *CPUReservationCfg) DeepCopy() *CPUReservationCfg {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUReservationCfg)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ColocationCfg) DeepCopyInto(out *ColocationCfg) {
 	*out = *in
@@ -94,6 +121,11 @@ func (in *ColocationStrategy) DeepCopyInto(out *ColocationStrategy) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.MetricNodeAggregatePolicy != nil {
+		in, out := &in.MetricNodeAggregatePolicy, &out.MetricNodeAggregatePolicy
+		*out = new(v1alpha1.AggregatePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.CPUReclaimThresholdPercent != nil {
 		in, out := &in.CPUReclaimThresholdPercent, &out.CPUReclaimThresholdPercent
 		*out = new(int64)
@@ -173,6 +205,31 @@ func (in *NodeCPUBurstCfg) DeepCopy() *NodeCPUBurstCfg {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeCPUReservationCfg) DeepCopyInto(out *NodeCPUReservationCfg) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CPUReservationStrategy != nil {
+		in, out := &in.CPUReservationStrategy, &out.CPUReservationStrategy
+		*out = new(v1alpha1.CPUReservationStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeCPUReservationCfg.
+func (in *NodeCPUReservationCfg) DeepCopy() *NodeCPUReservationCfg {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeCPUReservationCfg)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeColocationCfg) DeepCopyInto(out *NodeColocationCfg) {
 	*out = *in
@@ -244,6 +301,68 @@ func (in *NodeResourceThresholdStrategy) DeepCopy() *NodeResourceThresholdStrate
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeRuntimeClassCfg) DeepCopyInto(out *NodeRuntimeClassCfg) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RuntimeClassStrategies != nil {
+		in, out := &in.RuntimeClassStrategies, &out.RuntimeClassStrategies
+		*out = make([]v1alpha1.RuntimeClassStrategy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeRuntimeClassCfg.
+func (in *NodeRuntimeClassCfg) DeepCopy() *NodeRuntimeClassCfg {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeRuntimeClassCfg)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSLORolloutCfg) DeepCopyInto(out *NodeSLORolloutCfg) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CanaryNodeSelector != nil {
+		in, out := &in.CanaryNodeSelector, &out.CanaryNodeSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Percentage != nil {
+		in, out := &in.Percentage, &out.Percentage
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxEvictions != nil {
+		in, out := &in.MaxEvictions, &out.MaxEvictions
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSLORolloutCfg.
+func (in *NodeSLORolloutCfg) DeepCopy() *NodeSLORolloutCfg {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSLORolloutCfg)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceQOSCfg) DeepCopyInto(out *ResourceQOSCfg) {
 	*out = *in
@@ -297,3 +416,32 @@ func (in *ResourceThresholdCfg) DeepCopy() *ResourceThresholdCfg {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuntimeClassCfg) DeepCopyInto(out *RuntimeClassCfg) {
+	*out = *in
+	if in.ClusterStrategies != nil {
+		in, out := &in.ClusterStrategies, &out.ClusterStrategies
+		*out = make([]v1alpha1.RuntimeClassStrategy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeStrategies != nil {
+		in, out := &in.NodeStrategies, &out.NodeStrategies
+		*out = make([]NodeRuntimeClassCfg, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuntimeClassCfg.
+func (in *RuntimeClassCfg) DeepCopy() *RuntimeClassCfg {
+	if in == nil {
+		return nil
+	}
+	out := new(RuntimeClassCfg)
+	in.DeepCopyInto(out)
+	return out
+}
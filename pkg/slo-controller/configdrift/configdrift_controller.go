@@ -0,0 +1,197 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configdrift
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/config"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	// AnnotationColocationConsistency records the ColocationConsistent condition on the slo-controller configmap.
+	AnnotationColocationConsistency = extension.DomainPrefix + "colocation-consistency"
+
+	ConditionColocationConsistent = "ColocationConsistent"
+
+	ReasonConfigDriftDetected = "ColocationConfigDriftDetected"
+	ReasonConfigConsistent    = "ColocationConfigConsistent"
+)
+
+// ConfigDriftReconciler compares the colocation strategy of the node resource calculation with the
+// koord-scheduler configuration, and records a ColocationConsistent condition on the slo-controller configmap.
+type ConfigDriftReconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+func (r *ConfigDriftReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	sloConfigMap := &corev1.ConfigMap{}
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: config.ConfigNameSpace, Name: config.SLOCtrlConfigMap}, sloConfigMap)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// the condition is recorded on the slo-controller configmap, nothing to do if it does not exist
+			return ctrl.Result{}, nil
+		}
+		klog.Errorf("failed to get configmap %s/%s, err: %v", config.ConfigNameSpace, config.SLOCtrlConfigMap, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	colocationCfg, err := parseColocationCfg(sloConfigMap)
+	if err != nil {
+		// the colocation handler has already recorded the unmarshal failure
+		klog.Warningf("skip config drift detection since colocation config is invalid, err: %v", err)
+		return ctrl.Result{}, nil
+	}
+
+	schedulerConfigMap := &corev1.ConfigMap{}
+	err = r.Client.Get(ctx, types.NamespacedName{Namespace: config.ConfigNameSpace, Name: config.SchedulerConfigMap}, schedulerConfigMap)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			klog.V(4).Infof("scheduler configmap %s/%s not found, skip config drift detection",
+				config.ConfigNameSpace, config.SchedulerConfigMap)
+			return ctrl.Result{}, nil
+		}
+		klog.Errorf("failed to get configmap %s/%s, err: %v", config.ConfigNameSpace, config.SchedulerConfigMap, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	schedulerCfg, err := ParseSchedulerConfig(schedulerConfigMap.Data[config.SchedulerConfigKey])
+	if err != nil {
+		klog.Warningf("skip config drift detection since scheduler config is invalid, err: %v", err)
+		return ctrl.Result{}, nil
+	}
+
+	drifts := CheckColocationDrift(colocationCfg, schedulerCfg)
+	condition := newColocationConsistentCondition(drifts)
+	changed, err := r.updateConditionIfChanged(ctx, sloConfigMap, condition)
+	if err != nil {
+		klog.Errorf("failed to update condition %s on configmap %s/%s, err: %v", ConditionColocationConsistent,
+			config.ConfigNameSpace, config.SLOCtrlConfigMap, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	if changed {
+		klog.V(3).Infof("condition %s of configmap %s/%s changed to %v, message: %s", ConditionColocationConsistent,
+			config.ConfigNameSpace, config.SLOCtrlConfigMap, condition.Status, condition.Message)
+		if len(drifts) > 0 {
+			r.Recorder.Event(sloConfigMap, corev1.EventTypeWarning, ReasonConfigDriftDetected, condition.Message)
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *ConfigDriftReconciler) updateConditionIfChanged(ctx context.Context, configMap *corev1.ConfigMap, condition *metav1.Condition) (bool, error) {
+	oldCondition, _ := GetColocationConsistentCondition(configMap)
+	if oldCondition != nil && oldCondition.Status == condition.Status &&
+		oldCondition.Reason == condition.Reason && oldCondition.Message == condition.Message {
+		return false, nil
+	}
+	if oldCondition != nil && oldCondition.Status == condition.Status {
+		condition.LastTransitionTime = oldCondition.LastTransitionTime
+	}
+
+	data, err := json.Marshal(condition)
+	if err != nil {
+		return false, err
+	}
+	patch := client.MergeFrom(configMap.DeepCopy())
+	if configMap.Annotations == nil {
+		configMap.Annotations = map[string]string{}
+	}
+	configMap.Annotations[AnnotationColocationConsistency] = string(data)
+	if err = r.Client.Patch(ctx, configMap, patch); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetColocationConsistentCondition returns the ColocationConsistent condition recorded on the configmap.
+func GetColocationConsistentCondition(configMap *corev1.ConfigMap) (*metav1.Condition, error) {
+	if configMap == nil || configMap.Annotations[AnnotationColocationConsistency] == "" {
+		return nil, nil
+	}
+	condition := &metav1.Condition{}
+	if err := json.Unmarshal([]byte(configMap.Annotations[AnnotationColocationConsistency]), condition); err != nil {
+		return nil, err
+	}
+	return condition, nil
+}
+
+func newColocationConsistentCondition(drifts []string) *metav1.Condition {
+	condition := &metav1.Condition{
+		Type:               ConditionColocationConsistent,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonConfigConsistent,
+		LastTransitionTime: metav1.Now(),
+	}
+	if len(drifts) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonConfigDriftDetected
+		condition.Message = strings.Join(drifts, "; ")
+	}
+	return condition
+}
+
+func parseColocationCfg(configMap *corev1.ConfigMap) (*config.ColocationCfg, error) {
+	cfg := &config.ColocationCfg{}
+	configStr := configMap.Data[config.ColocationConfigKey]
+	if configStr == "" {
+		return config.NewDefaultColocationCfg(), nil
+	}
+	if err := json.Unmarshal([]byte(configStr), cfg); err != nil {
+		return nil, err
+	}
+	mergedClusterCfg := config.DefaultColocationStrategy()
+	mergedInterface, err := util.MergeCfg(&mergedClusterCfg, &cfg.ColocationStrategy)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ColocationStrategy = *(mergedInterface.(*config.ColocationStrategy))
+	return cfg, nil
+}
+
+func isWatchedConfigMap(obj client.Object) bool {
+	return obj.GetNamespace() == config.ConfigNameSpace &&
+		(obj.GetName() == config.SLOCtrlConfigMap || obj.GetName() == config.SchedulerConfigMap)
+}
+
+func (r *ConfigDriftReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("configdrift").
+		For(&corev1.ConfigMap{}, builder.WithPredicates(predicate.NewPredicateFuncs(isWatchedConfigMap))).
+		Complete(r)
+}
+
+var _ reconcile.Reconciler = &ConfigDriftReconciler{}
@@ -0,0 +1,236 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configdrift
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/config"
+)
+
+const (
+	testSchedulerConfigWithBatchResource = `
+apiVersion: kubescheduler.config.k8s.io/v1beta2
+kind: KubeSchedulerConfiguration
+profiles:
+  - schedulerName: koord-scheduler
+    plugins:
+      filter:
+        enabled:
+          - name: LoadAwareScheduling
+          - name: BatchResourceFit
+`
+	testSchedulerConfigWithoutBatchResource = `
+apiVersion: kubescheduler.config.k8s.io/v1beta2
+kind: KubeSchedulerConfiguration
+profiles:
+  - schedulerName: koord-scheduler
+    plugins:
+      filter:
+        enabled:
+          - name: LoadAwareScheduling
+        disabled:
+          - name: BatchResourceFit
+`
+	testSchedulerConfigWithAllDisabled = `
+apiVersion: kubescheduler.config.k8s.io/v1beta2
+kind: KubeSchedulerConfiguration
+profiles:
+  - schedulerName: koord-scheduler
+    plugins:
+      filter:
+        enabled:
+          - name: BatchResourceFit
+        disabled:
+          - name: "*"
+`
+)
+
+func TestParseSchedulerConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    *SchedulerConfigSummary
+		wantErr bool
+	}{
+		{
+			name: "batch resource fit enabled",
+			data: testSchedulerConfigWithBatchResource,
+			want: &SchedulerConfigSummary{
+				Profiles:                 []string{"koord-scheduler"},
+				BatchResourceFitProfiles: []string{"koord-scheduler"},
+			},
+		},
+		{
+			name: "batch resource fit disabled",
+			data: testSchedulerConfigWithoutBatchResource,
+			want: &SchedulerConfigSummary{
+				Profiles: []string{"koord-scheduler"},
+			},
+		},
+		{
+			name: "batch resource fit enabled with all default plugins disabled",
+			data: testSchedulerConfigWithAllDisabled,
+			want: &SchedulerConfigSummary{
+				Profiles:                 []string{"koord-scheduler"},
+				BatchResourceFitProfiles: []string{"koord-scheduler"},
+			},
+		},
+		{
+			name:    "invalid config",
+			data:    "profiles: invalid",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSchedulerConfig(tt.data)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCheckColocationDrift(t *testing.T) {
+	tests := []struct {
+		name          string
+		colocationCfg *config.ColocationCfg
+		schedulerCfg  *SchedulerConfigSummary
+		wantDrift     bool
+	}{
+		{
+			name:          "colocation disabled without batch resource fit",
+			colocationCfg: config.NewDefaultColocationCfg(),
+			schedulerCfg:  &SchedulerConfigSummary{Profiles: []string{"koord-scheduler"}},
+			wantDrift:     false,
+		},
+		{
+			name: "colocation enabled with batch resource fit",
+			colocationCfg: &config.ColocationCfg{
+				ColocationStrategy: config.ColocationStrategy{Enable: pointer.Bool(true)},
+			},
+			schedulerCfg: &SchedulerConfigSummary{
+				Profiles:                 []string{"koord-scheduler"},
+				BatchResourceFitProfiles: []string{"koord-scheduler"},
+			},
+			wantDrift: false,
+		},
+		{
+			name: "colocation enabled on some nodes without batch resource fit",
+			colocationCfg: &config.ColocationCfg{
+				ColocationStrategy: config.ColocationStrategy{Enable: pointer.Bool(false)},
+				NodeConfigs: []config.NodeColocationCfg{
+					{
+						NodeSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "colocation"}},
+						ColocationStrategy: config.ColocationStrategy{Enable: pointer.Bool(true)},
+					},
+				},
+			},
+			schedulerCfg: &SchedulerConfigSummary{Profiles: []string{"koord-scheduler"}},
+			wantDrift:    true,
+		},
+		{
+			name:          "colocation disabled with batch resource fit",
+			colocationCfg: config.NewDefaultColocationCfg(),
+			schedulerCfg: &SchedulerConfigSummary{
+				Profiles:                 []string{"koord-scheduler"},
+				BatchResourceFitProfiles: []string{"koord-scheduler"},
+			},
+			wantDrift: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckColocationDrift(tt.colocationCfg, tt.schedulerCfg)
+			assert.Equal(t, tt.wantDrift, len(got) > 0, got)
+		})
+	}
+}
+
+func TestConfigDriftReconciler_Reconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	sloConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      config.SLOCtrlConfigMap,
+			Namespace: config.ConfigNameSpace,
+		},
+		Data: map[string]string{
+			config.ColocationConfigKey: `{"enable":true}`,
+		},
+	}
+	schedulerConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      config.SchedulerConfigMap,
+			Namespace: config.ConfigNameSpace,
+		},
+		Data: map[string]string{
+			config.SchedulerConfigKey: testSchedulerConfigWithoutBatchResource,
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sloConfigMap, schedulerConfigMap).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &ConfigDriftReconciler{
+		Client:   fakeClient,
+		Recorder: recorder,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: config.ConfigNameSpace, Name: config.SLOCtrlConfigMap}}
+	_, err := r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+
+	gotConfigMap := &corev1.ConfigMap{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, gotConfigMap))
+	condition, err := GetColocationConsistentCondition(gotConfigMap)
+	assert.NoError(t, err)
+	assert.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonConfigDriftDetected, condition.Reason)
+	assert.Len(t, recorder.Events, 1)
+
+	// reconcile again, the condition is not changed and no more event is recorded
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.Len(t, recorder.Events, 1)
+
+	// fix the scheduler config
+	schedulerConfigMap = &corev1.ConfigMap{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: config.ConfigNameSpace, Name: config.SchedulerConfigMap}, schedulerConfigMap))
+	schedulerConfigMap.Data[config.SchedulerConfigKey] = testSchedulerConfigWithBatchResource
+	assert.NoError(t, fakeClient.Update(context.TODO(), schedulerConfigMap))
+
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	gotConfigMap = &corev1.ConfigMap{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), req.NamespacedName, gotConfigMap))
+	condition, err = GetColocationConsistentCondition(gotConfigMap)
+	assert.NoError(t, err)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonConfigConsistent, condition.Reason)
+}
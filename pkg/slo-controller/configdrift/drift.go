@@ -0,0 +1,132 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configdrift
+
+import (
+	"fmt"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/config"
+)
+
+const (
+	// BatchResourceFitPluginName is the name of the scheduler plugin which admits pods requesting batch resources.
+	BatchResourceFitPluginName = "BatchResourceFit"
+)
+
+// schedulerConfiguration only contains the fields of KubeSchedulerConfiguration the drift detection cares about,
+// so that it is compatible with all versions of the scheduler configuration.
+type schedulerConfiguration struct {
+	Profiles []schedulerProfile `json:"profiles,omitempty"`
+}
+
+type schedulerProfile struct {
+	SchedulerName string            `json:"schedulerName,omitempty"`
+	Plugins       *schedulerPlugins `json:"plugins,omitempty"`
+}
+
+type schedulerPlugins struct {
+	Filter *schedulerPluginSet `json:"filter,omitempty"`
+}
+
+type schedulerPluginSet struct {
+	Enabled  []schedulerPlugin `json:"enabled,omitempty"`
+	Disabled []schedulerPlugin `json:"disabled,omitempty"`
+}
+
+type schedulerPlugin struct {
+	Name string `json:"name"`
+}
+
+// SchedulerConfigSummary is the part of the koord-scheduler configuration which must be consistent
+// with the colocation strategy used by the node resource calculation.
+type SchedulerConfigSummary struct {
+	// Profiles are the scheduler names of all profiles.
+	Profiles []string
+	// BatchResourceFitProfiles are the scheduler names of the profiles which enable the BatchResourceFit filter.
+	BatchResourceFitProfiles []string
+}
+
+// ParseSchedulerConfig parses the KubeSchedulerConfiguration in yaml or json format.
+func ParseSchedulerConfig(data string) (*SchedulerConfigSummary, error) {
+	cfg := &schedulerConfiguration{}
+	if err := yaml.Unmarshal([]byte(data), cfg); err != nil {
+		return nil, err
+	}
+
+	summary := &SchedulerConfigSummary{}
+	for _, profile := range cfg.Profiles {
+		summary.Profiles = append(summary.Profiles, profile.SchedulerName)
+		if profile.Plugins == nil || profile.Plugins.Filter == nil {
+			continue
+		}
+		if isPluginEnabled(profile.Plugins.Filter, BatchResourceFitPluginName) {
+			summary.BatchResourceFitProfiles = append(summary.BatchResourceFitProfiles, profile.SchedulerName)
+		}
+	}
+	sort.Strings(summary.Profiles)
+	sort.Strings(summary.BatchResourceFitProfiles)
+	return summary, nil
+}
+
+// isPluginEnabled follows the merging of the scheduler framework: the disabled list, including "*", only removes
+// the default plugins, and the plugins in the enabled list are always enabled. BatchResourceFit is not a default
+// plugin, so it's enabled if and only if it's in the enabled list.
+func isPluginEnabled(pluginSet *schedulerPluginSet, pluginName string) bool {
+	for _, plugin := range pluginSet.Enabled {
+		if plugin.Name == pluginName {
+			return true
+		}
+	}
+	return false
+}
+
+// isColocationEnabled returns true if the colocation is enabled by the cluster strategy or any node strategy,
+// which means batch resources are overcommitted on some nodes.
+func isColocationEnabled(cfg *config.ColocationCfg) bool {
+	if cfg.Enable != nil && *cfg.Enable {
+		return true
+	}
+	for _, nodeCfg := range cfg.NodeConfigs {
+		if nodeCfg.Enable != nil && *nodeCfg.Enable {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckColocationDrift compares the colocation config with the scheduler config and returns the
+// inconsistencies found. An empty result means the two configs are consistent.
+func CheckColocationDrift(colocationCfg *config.ColocationCfg, schedulerCfg *SchedulerConfigSummary) []string {
+	if colocationCfg == nil || schedulerCfg == nil {
+		return nil
+	}
+
+	var drifts []string
+	colocationEnabled := isColocationEnabled(colocationCfg)
+	if colocationEnabled && len(schedulerCfg.BatchResourceFitProfiles) == 0 {
+		drifts = append(drifts, fmt.Sprintf("colocation is enabled but none of the scheduler profiles %v enables %s, "+
+			"the batch resources will be over-admitted", schedulerCfg.Profiles, BatchResourceFitPluginName))
+	}
+	if !colocationEnabled && len(schedulerCfg.BatchResourceFitProfiles) > 0 {
+		drifts = append(drifts, fmt.Sprintf("colocation is disabled but the scheduler profiles %v enable %s, "+
+			"pods requesting batch resources can not be admitted", schedulerCfg.BatchResourceFitProfiles, BatchResourceFitPluginName))
+	}
+	return drifts
+}
@@ -40,6 +40,7 @@ func getNodeMetricCollectPolicy(strategy *config.ColocationStrategy) (*slov1alph
 	collectPolicy := &slov1alpha1.NodeMetricCollectPolicy{
 		AggregateDurationSeconds: strategy.MetricAggregateDurationSeconds,
 		ReportIntervalSeconds:    strategy.MetricReportIntervalSeconds,
+		NodeAggregatePolicy:      strategy.MetricNodeAggregatePolicy,
 	}
 	return collectPolicy, nil
 }
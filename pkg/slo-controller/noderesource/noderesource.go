@@ -26,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 
@@ -131,9 +132,42 @@ func (r *NodeResourceReconciler) isGPUResourceNeedSync(new, old *corev1.Node) bo
 			return true
 		}
 	}
+	for _, resourceName := range getMIGResourceNames(old.Status.Allocatable, new.Status.Allocatable) {
+		if util.IsResourceDiff(old.Status.Allocatable, new.Status.Allocatable, resourceName, *strategy.ResourceDiffThreshold) {
+			klog.Warningf("node %v resource diff bigger than %v, need sync", resourceName, *strategy.ResourceDiffThreshold)
+			return true
+		}
+	}
 	return false
 }
 
+// getMIGResourceNames returns the MIG resource names in any of the resource lists.
+func getMIGResourceNames(resourceLists ...corev1.ResourceList) []corev1.ResourceName {
+	names := map[corev1.ResourceName]struct{}{}
+	for _, resourceList := range resourceLists {
+		for resourceName := range resourceList {
+			if _, ok := extension.GetMIGProfile(resourceName); ok {
+				names[resourceName] = struct{}{}
+			}
+		}
+	}
+	resourceNames := make([]corev1.ResourceName, 0, len(names))
+	for resourceName := range names {
+		resourceNames = append(resourceNames, resourceName)
+	}
+	return resourceNames
+}
+
+// setMIGNodeResource replaces the MIG resources of the resource list with the MIG devices reported.
+func setMIGNodeResource(resourceList, migTotal corev1.ResourceList) {
+	for _, resourceName := range getMIGResourceNames(resourceList) {
+		delete(resourceList, resourceName)
+	}
+	for resourceName, quantity := range migTotal {
+		resourceList[resourceName] = quantity.DeepCopy()
+	}
+}
+
 func (r *NodeResourceReconciler) updateGPUNodeResource(node *corev1.Node, device *schedulingv1alpha1.Device) error {
 	if device == nil {
 		return nil
@@ -141,18 +175,26 @@ func (r *NodeResourceReconciler) updateGPUNodeResource(node *corev1.Node, device
 	memoryTotal := resource.NewQuantity(0, resource.DecimalSI)
 	coreTotal := resource.NewQuantity(0, resource.BinarySI)
 	ratioTotal := resource.NewQuantity(0, resource.DecimalSI)
+	// the MIG devices are exposed as the extended resources per profile, e.g. nvidia.com/mig-1g.5gb
+	migTotal := corev1.ResourceList{}
 	for _, gpu := range device.Spec.Devices {
-		if gpu.Health {
-			memoryTotal.Add(gpu.Resources[extension.GPUMemory])
-			coreTotal.Add(gpu.Resources[extension.GPUCore])
-			ratioTotal.Add(gpu.Resources[extension.GPUMemoryRatio])
+		if !gpu.Health {
+			continue
+		}
+		if gpu.Type == schedulingv1alpha1.MIG {
+			migTotal = quotav1.Add(migTotal, gpu.Resources)
+			continue
 		}
+		memoryTotal.Add(gpu.Resources[extension.GPUMemory])
+		coreTotal.Add(gpu.Resources[extension.GPUCore])
+		ratioTotal.Add(gpu.Resources[extension.GPUMemoryRatio])
 	}
 
 	copyNode := node.DeepCopy()
 	copyNode.Status.Allocatable[extension.GPUCore] = *coreTotal
 	copyNode.Status.Allocatable[extension.GPUMemory] = *memoryTotal
 	copyNode.Status.Allocatable[extension.GPUMemoryRatio] = *ratioTotal
+	setMIGNodeResource(copyNode.Status.Allocatable, migTotal)
 
 	if !r.isGPUResourceNeedSync(copyNode, node) {
 		return nil
@@ -174,6 +216,8 @@ func (r *NodeResourceReconciler) updateGPUNodeResource(node *corev1.Node, device
 		updateNode.Status.Allocatable[extension.GPUCore] = *coreTotal
 		updateNode.Status.Capacity[extension.GPUMemoryRatio] = *ratioTotal
 		updateNode.Status.Allocatable[extension.GPUMemoryRatio] = *ratioTotal
+		setMIGNodeResource(updateNode.Status.Capacity, migTotal)
+		setMIGNodeResource(updateNode.Status.Allocatable, migTotal)
 
 		if err := r.Client.Status().Update(context.TODO(), updateNode); err != nil {
 			klog.Errorf("failed to update node gpu resource %v, error: %v", updateNode.Name, err)
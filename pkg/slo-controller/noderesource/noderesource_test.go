@@ -374,6 +374,32 @@ func Test_isGPUResourceNeedSync(t *testing.T) {
 			},
 			true,
 		},
+		{
+			&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node0",
+				},
+				Status: corev1.NodeStatus{
+					Allocatable: corev1.ResourceList{
+						apiext.MIGResourceName("7g.40gb"): resource.MustParse("1"),
+					},
+				},
+			},
+			&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node0",
+				},
+				Status: corev1.NodeStatus{
+					Allocatable: corev1.ResourceList{
+						apiext.MIGResourceName("3g.20gb"): resource.MustParse("2"),
+					},
+				},
+			},
+			&SyncContext{
+				contextMap: map[string]time.Time{"/test-node0": time.Now()},
+			},
+			true,
+		},
 	}
 	configf := &config.ColocationCfg{
 		ColocationStrategy: config.ColocationStrategy{
@@ -44,9 +44,11 @@ type SLOCfgCache interface {
 }
 
 type SLOCfg struct {
-	ThresholdCfgMerged   config.ResourceThresholdCfg `json:"thresholdCfgMerged,omitempty"`
-	ResourceQOSCfgMerged config.ResourceQOSCfg       `json:"resourceQOSCfgMerged,omitempty"`
-	CPUBurstCfgMerged    config.CPUBurstCfg          `json:"cpuBurstCfgMerged,omitempty"`
+	ThresholdCfgMerged      config.ResourceThresholdCfg `json:"thresholdCfgMerged,omitempty"`
+	ResourceQOSCfgMerged    config.ResourceQOSCfg       `json:"resourceQOSCfgMerged,omitempty"`
+	CPUBurstCfgMerged       config.CPUBurstCfg          `json:"cpuBurstCfgMerged,omitempty"`
+	CPUReservationCfgMerged config.CPUReservationCfg    `json:"cpuReservationCfgMerged,omitempty"`
+	RuntimeClassCfgMerged   config.RuntimeClassCfg      `json:"runtimeClassCfgMerged,omitempty"`
 }
 
 func (in *SLOCfg) DeepCopy() *SLOCfg {
@@ -54,6 +56,8 @@ func (in *SLOCfg) DeepCopy() *SLOCfg {
 	out.ThresholdCfgMerged = *in.ThresholdCfgMerged.DeepCopy()
 	out.CPUBurstCfgMerged = *in.CPUBurstCfgMerged.DeepCopy()
 	out.ResourceQOSCfgMerged = *in.ResourceQOSCfgMerged.DeepCopy()
+	out.CPUReservationCfgMerged = *in.CPUReservationCfgMerged.DeepCopy()
+	out.RuntimeClassCfgMerged = *in.RuntimeClassCfgMerged.DeepCopy()
 	return out
 }
 
@@ -66,9 +70,11 @@ type sLOCfgCache struct {
 
 func DefaultSLOCfg() SLOCfg {
 	return SLOCfg{
-		ThresholdCfgMerged:   config.ResourceThresholdCfg{ClusterStrategy: util.DefaultResourceThresholdStrategy()},
-		ResourceQOSCfgMerged: config.ResourceQOSCfg{ClusterStrategy: &slov1alpha1.ResourceQOSStrategy{}},
-		CPUBurstCfgMerged:    config.CPUBurstCfg{ClusterStrategy: util.DefaultCPUBurstStrategy()},
+		ThresholdCfgMerged:      config.ResourceThresholdCfg{ClusterStrategy: util.DefaultResourceThresholdStrategy()},
+		ResourceQOSCfgMerged:    config.ResourceQOSCfg{ClusterStrategy: &slov1alpha1.ResourceQOSStrategy{}},
+		CPUBurstCfgMerged:       config.CPUBurstCfg{ClusterStrategy: util.DefaultCPUBurstStrategy()},
+		CPUReservationCfgMerged: config.CPUReservationCfg{},
+		RuntimeClassCfgMerged:   config.RuntimeClassCfg{},
 	}
 }
 
@@ -131,6 +137,16 @@ func (p *SLOCfgHandlerForConfigMapEvent) syncConfig(configMap *corev1.ConfigMap)
 		klog.V(5).Infof("failed to get CPUBurstCfg, err: %s", err)
 		p.recorder.Eventf(configMap, "Warning", config.ReasonSLOConfigUnmarshalFailed, "failed to unmarshal CPUBurstCfg, err: %s", err)
 	}
+	newSLOCfg.CPUReservationCfgMerged, err = calculateCPUReservationCfgMerged(oldSLOCfgCopy.CPUReservationCfgMerged, configMap)
+	if err != nil {
+		klog.V(5).Infof("failed to get CPUReservationCfg, err: %s", err)
+		p.recorder.Eventf(configMap, "Warning", config.ReasonSLOConfigUnmarshalFailed, "failed to unmarshal CPUReservationCfg, err: %s", err)
+	}
+	newSLOCfg.RuntimeClassCfgMerged, err = calculateRuntimeClassCfgMerged(oldSLOCfgCopy.RuntimeClassCfgMerged, configMap)
+	if err != nil {
+		klog.V(5).Infof("failed to get RuntimeClassCfg, err: %s", err)
+		p.recorder.Eventf(configMap, "Warning", config.ReasonSLOConfigUnmarshalFailed, "failed to unmarshal RuntimeClassCfg, err: %s", err)
+	}
 
 	return p.updateCacheIfChanged(newSLOCfg)
 }
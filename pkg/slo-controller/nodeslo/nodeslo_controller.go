@@ -19,10 +19,12 @@ package nodeslo
 import (
 	"context"
 	"reflect"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -32,9 +34,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/config"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/nodemetric"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
 )
 
+// nodeSLORolloutRequeueInterval is the interval to recheck the NodeSLO which is held by the rollout
+const nodeSLORolloutRequeueInterval = time.Minute
+
 // NodeSLOReconciler reconciles a NodeSLO object
 type NodeSLOReconciler struct {
 	client.Client
@@ -86,9 +94,35 @@ func (r *NodeSLOReconciler) getNodeSLOSpec(node *corev1.Node, oldSpec *slov1alph
 		klog.Warningf("getNodeSLOSpec(): failed to get cpuBurstConfig spec for node %s,error: %v", node.Name, err)
 	}
 
+	nodeSLOSpec.CPUReservationStrategy, err = getCPUReservationConfigSpec(node, &sloCfg.CPUReservationCfgMerged)
+	if err != nil {
+		klog.Warningf("getNodeSLOSpec(): failed to get cpuReservationConfig spec for node %s,error: %v", node.Name, err)
+	}
+
+	nodeSLOSpec.RuntimeClassStrategies, err = getRuntimeClassConfigSpec(node, &sloCfg.RuntimeClassCfgMerged)
+	if err != nil {
+		klog.Warningf("getNodeSLOSpec(): failed to get runtimeClassConfig spec for node %s,error: %v", node.Name, err)
+	}
+
 	return nodeSLOSpec, nil
 }
 
+// getNodeSLORollout returns the rollout state of the latest revision, nil if the NodeSLORollout feature is disabled.
+func (r *NodeSLOReconciler) getNodeSLORollout() (*nodeSLORollout, error) {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.NodeSLORollout) {
+		return nil, nil
+	}
+	configMap := &corev1.ConfigMap{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Namespace: config.ConfigNameSpace, Name: config.SLOCtrlConfigMap}, configMap)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		configMap = nil
+	}
+	return newNodeSLORollout(configMap), nil
+}
+
 // +kubebuilder:rbac:groups=slo.koordinator.sh,resources=nodeslos,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=slo.koordinator.sh,resources=nodeslos/status,verbs=get;update;patch
 
@@ -106,11 +140,17 @@ func (r *NodeSLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return reconcile.Result{Requeue: false}, nil
 	}
 
+	rollout, err := r.getNodeSLORollout()
+	if err != nil {
+		klog.Errorf("syncNodeSLO failed to get nodeSLO rollout, error: %v", err)
+		return reconcile.Result{Requeue: true}, err
+	}
+
 	// get the node
 	nodeExist := true
 	nodeName := req.Name
 	node := &corev1.Node{}
-	err := r.Client.Get(context.TODO(), req.NamespacedName, node)
+	err = r.Client.Get(context.TODO(), req.NamespacedName, node)
 	if err != nil {
 		if !errors.IsNotFound(err) {
 			klog.Errorf("syncNodeSLO failed to find node %v, error: %v", nodeName, err)
@@ -154,6 +194,10 @@ func (r *NodeSLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 			klog.Errorf("syncNodeSLO failed to init nodeSLO instance: %v", err)
 			return reconcile.Result{Requeue: true}, err
 		}
+		// the new node is updated to the latest revision directly since it has no running pods to disturb
+		if rollout != nil {
+			setNodeSLORevisionOfObject(nodeSLO, rollout.revision)
+		}
 		err = r.Client.Create(context.TODO(), nodeSLO)
 		if err != nil {
 			klog.Errorf("syncNodeSLO failed to create nodeSLO instance: %v", err)
@@ -166,8 +210,19 @@ func (r *NodeSLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 			klog.Errorf("syncNodeSLO failed to get nodeSLO spec: %v", err)
 			return reconcile.Result{Requeue: true}, err
 		}
-		if !reflect.DeepEqual(nodeSLOSpec, &nodeSLO.Spec) {
+		specChanged := !reflect.DeepEqual(nodeSLOSpec, &nodeSLO.Spec)
+		if specChanged && rollout != nil && !rollout.isNodeAllowed(node) {
+			klog.V(4).Infof("syncNodeSLO hold nodeSLO %v since it is not in the rollout of revision %s",
+				nodeSLOName, rollout.revision)
+			return reconcile.Result{RequeueAfter: nodeSLORolloutRequeueInterval}, nil
+		}
+		// the revision is recorded even if the spec is not changed, so the updated nodes can be counted
+		revisionChanged := rollout != nil && getNodeSLORevisionOfObject(nodeSLO) != rollout.revision
+		if specChanged || revisionChanged {
 			nodeSLO.Spec = *nodeSLOSpec
+			if rollout != nil {
+				setNodeSLORevisionOfObject(nodeSLO, rollout.revision)
+			}
 			err = r.Client.Update(context.TODO(), nodeSLO)
 			if err != nil {
 				klog.Errorf("syncNodeSLO failed to update nodeSLO %v, error: %v", nodeSLOName, err)
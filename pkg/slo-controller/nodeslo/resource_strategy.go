@@ -18,11 +18,13 @@ package nodeslo
 
 import (
 	"encoding/json"
+	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/kubelet/cm/cpuset"
 
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/config"
@@ -79,6 +81,36 @@ func getCPUBurstConfigSpec(node *corev1.Node, cfg *config.CPUBurstCfg) (*slov1al
 	return cfg.ClusterStrategy.DeepCopy(), nil
 }
 
+func getCPUReservationConfigSpec(node *corev1.Node, cfg *config.CPUReservationCfg) (*slov1alpha1.CPUReservationStrategy, error) {
+	nodeLabels := labels.Set(node.Labels)
+	for _, nodeStrategy := range cfg.NodeStrategies {
+		selector, err := metav1.LabelSelectorAsSelector(nodeStrategy.NodeSelector)
+		if err != nil {
+			klog.Errorf("failed to parse node selector %v, err: %v", nodeStrategy.NodeSelector, err)
+			continue
+		}
+		if selector.Matches(nodeLabels) {
+			return nodeStrategy.CPUReservationStrategy.DeepCopy(), nil
+		}
+	}
+	return cfg.ClusterStrategy.DeepCopy(), nil
+}
+
+func getRuntimeClassConfigSpec(node *corev1.Node, cfg *config.RuntimeClassCfg) ([]slov1alpha1.RuntimeClassStrategy, error) {
+	nodeLabels := labels.Set(node.Labels)
+	for _, nodeStrategy := range cfg.NodeStrategies {
+		selector, err := metav1.LabelSelectorAsSelector(nodeStrategy.NodeSelector)
+		if err != nil {
+			klog.Errorf("failed to parse node selector %v, err: %v", nodeStrategy.NodeSelector, err)
+			continue
+		}
+		if selector.Matches(nodeLabels) {
+			return nodeStrategy.DeepCopy().RuntimeClassStrategies, nil
+		}
+	}
+	return cfg.DeepCopy().ClusterStrategies, nil
+}
+
 func calculateResourceThresholdCfgMerged(oldCfg config.ResourceThresholdCfg, configMap *corev1.ConfigMap) (config.ResourceThresholdCfg, error) {
 	cfgStr, ok := configMap.Data[config.ResourceThresholdConfigKey]
 	if !ok {
@@ -185,3 +217,104 @@ func calculateCPUBurstCfgMerged(oldCfg config.CPUBurstCfg, configMap *corev1.Con
 
 	return mergedCfg, nil
 }
+
+func calculateCPUReservationCfgMerged(oldCfg config.CPUReservationCfg, configMap *corev1.ConfigMap) (config.CPUReservationCfg, error) {
+	cfgStr, ok := configMap.Data[config.CPUReservationConfigKey]
+	if !ok {
+		return DefaultSLOCfg().CPUReservationCfgMerged, nil
+	}
+
+	mergedCfg := config.CPUReservationCfg{}
+	if err := json.Unmarshal([]byte(cfgStr), &mergedCfg); err != nil {
+		klog.Errorf("failed to unmarshal config %s, err: %s", config.CPUReservationConfigKey, err)
+		return oldCfg, err
+	}
+
+	// validate the reserved cpus
+	if err := validateCPUReservationStrategy(mergedCfg.ClusterStrategy); err != nil {
+		return oldCfg, err
+	}
+	for _, nodeStrategy := range mergedCfg.NodeStrategies {
+		if err := validateCPUReservationStrategy(nodeStrategy.CPUReservationStrategy); err != nil {
+			return oldCfg, err
+		}
+	}
+
+	// no cpu is reserved by default, so the cluster strategy is not merged with the default config
+	for index, nodeStrategy := range mergedCfg.NodeStrategies {
+		// merge with clusterStrategy
+		clusterCfgCopy := mergedCfg.ClusterStrategy.DeepCopy()
+		if nodeStrategy.CPUReservationStrategy == nil {
+			mergedCfg.NodeStrategies[index].CPUReservationStrategy = clusterCfgCopy
+		} else if clusterCfgCopy != nil {
+			mergedStrategyInterface, _ := util.MergeCfg(clusterCfgCopy, nodeStrategy.CPUReservationStrategy)
+			mergedCfg.NodeStrategies[index].CPUReservationStrategy = mergedStrategyInterface.(*slov1alpha1.CPUReservationStrategy)
+		}
+	}
+
+	return mergedCfg, nil
+}
+
+func validateCPUReservationStrategy(strategy *slov1alpha1.CPUReservationStrategy) error {
+	if strategy == nil || strategy.ReservedCPUs == nil {
+		return nil
+	}
+	if _, err := cpuset.Parse(*strategy.ReservedCPUs); err != nil {
+		return fmt.Errorf("invalid reservedCPUs %s, err: %v", *strategy.ReservedCPUs, err)
+	}
+	return nil
+}
+
+func calculateRuntimeClassCfgMerged(oldCfg config.RuntimeClassCfg, configMap *corev1.ConfigMap) (config.RuntimeClassCfg, error) {
+	cfgStr, ok := configMap.Data[config.RuntimeClassConfigKey]
+	if !ok {
+		return DefaultSLOCfg().RuntimeClassCfgMerged, nil
+	}
+
+	mergedCfg := config.RuntimeClassCfg{}
+	if err := json.Unmarshal([]byte(cfgStr), &mergedCfg); err != nil {
+		klog.Errorf("failed to unmarshal config %s, err: %s", config.RuntimeClassConfigKey, err)
+		return oldCfg, err
+	}
+
+	if err := validateRuntimeClassStrategies(mergedCfg.ClusterStrategies); err != nil {
+		return oldCfg, err
+	}
+	for _, nodeStrategy := range mergedCfg.NodeStrategies {
+		if err := validateRuntimeClassStrategies(nodeStrategy.RuntimeClassStrategies); err != nil {
+			return oldCfg, err
+		}
+	}
+
+	// the node strategy overrides the cluster strategy of the same runtime class, and inherits the others
+	for index, nodeStrategy := range mergedCfg.NodeStrategies {
+		merged := make([]slov1alpha1.RuntimeClassStrategy, 0, len(mergedCfg.ClusterStrategies)+len(nodeStrategy.RuntimeClassStrategies))
+		overridden := map[string]bool{}
+		for _, strategy := range nodeStrategy.RuntimeClassStrategies {
+			overridden[strategy.RuntimeClassName] = true
+		}
+		for _, strategy := range mergedCfg.ClusterStrategies {
+			if !overridden[strategy.RuntimeClassName] {
+				merged = append(merged, *strategy.DeepCopy())
+			}
+		}
+		merged = append(merged, nodeStrategy.RuntimeClassStrategies...)
+		mergedCfg.NodeStrategies[index].RuntimeClassStrategies = merged
+	}
+
+	return mergedCfg, nil
+}
+
+func validateRuntimeClassStrategies(strategies []slov1alpha1.RuntimeClassStrategy) error {
+	runtimeClassNames := map[string]bool{}
+	for _, strategy := range strategies {
+		if strategy.RuntimeClassName == "" {
+			return fmt.Errorf("runtimeClassName is required")
+		}
+		if runtimeClassNames[strategy.RuntimeClassName] {
+			return fmt.Errorf("duplicated runtimeClassName %s", strategy.RuntimeClassName)
+		}
+		runtimeClassNames[strategy.RuntimeClassName] = true
+	}
+	return nil
+}
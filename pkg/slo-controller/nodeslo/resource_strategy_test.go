@@ -764,3 +764,99 @@ func Test_calculateCPUBurstCfgMerged(t *testing.T) {
 		})
 	}
 }
+
+func Test_calculateCPUReservationCfgMerged(t *testing.T) {
+	oldCfg := config.CPUReservationCfg{
+		ClusterStrategy: &slov1alpha1.CPUReservationStrategy{ReservedCPUs: pointer.String("0")},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-node",
+			Labels: map[string]string{"offload": "spdk"},
+		},
+	}
+
+	// no reservation by default
+	got, err := calculateCPUReservationCfgMerged(oldCfg, &corev1.ConfigMap{})
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultSLOCfg().CPUReservationCfgMerged, got)
+	spec, err := getCPUReservationConfigSpec(node, &got)
+	assert.NoError(t, err)
+	assert.Nil(t, spec)
+
+	// the node strategy overrides the cluster strategy
+	got, err = calculateCPUReservationCfgMerged(oldCfg, &corev1.ConfigMap{
+		Data: map[string]string{
+			config.CPUReservationConfigKey: `{"clusterStrategy":{"reservedCPUs":"0-1"},"nodeStrategies":[{"nodeSelector":{"matchLabels":{"offload":"spdk"}},"reservedCPUs":"0-3"},{"nodeSelector":{"matchLabels":{"offload":"none"}}}]}`,
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "0-1", *got.NodeStrategies[1].ReservedCPUs)
+	spec, err = getCPUReservationConfigSpec(node, &got)
+	assert.NoError(t, err)
+	assert.Equal(t, "0-3", *spec.ReservedCPUs)
+	spec, err = getCPUReservationConfigSpec(&corev1.Node{}, &got)
+	assert.NoError(t, err)
+	assert.Equal(t, "0-1", *spec.ReservedCPUs)
+
+	// keep the old config if the reserved cpus are invalid
+	got, err = calculateCPUReservationCfgMerged(oldCfg, &corev1.ConfigMap{
+		Data: map[string]string{
+			config.CPUReservationConfigKey: `{"clusterStrategy":{"reservedCPUs":"a-b"}}`,
+		},
+	})
+	assert.Error(t, err)
+	assert.Equal(t, oldCfg, got)
+}
+
+func Test_calculateRuntimeClassCfgMerged(t *testing.T) {
+	oldCfg := config.RuntimeClassCfg{
+		ClusterStrategies: []slov1alpha1.RuntimeClassStrategy{{RuntimeClassName: "kata"}},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-node",
+			Labels: map[string]string{"runtime": "mixed"},
+		},
+	}
+
+	// no runtime class is overridden by default
+	got, err := calculateRuntimeClassCfgMerged(oldCfg, &corev1.ConfigMap{})
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultSLOCfg().RuntimeClassCfgMerged, got)
+	spec, err := getRuntimeClassConfigSpec(node, &got)
+	assert.NoError(t, err)
+	assert.Nil(t, spec)
+
+	// the node strategy overrides the cluster strategy of the same runtime class
+	got, err = calculateRuntimeClassCfgMerged(oldCfg, &corev1.ConfigMap{
+		Data: map[string]string{
+			config.RuntimeClassConfigKey: `{"clusterStrategies":[{"runtimeClassName":"kata","cpuBurstStrategy":{"policy":"none"}},{"runtimeClassName":"gvisor","cpuBurstStrategy":{"policy":"none"}}],"nodeStrategies":[{"nodeSelector":{"matchLabels":{"runtime":"mixed"}},"runtimeClassStrategies":[{"runtimeClassName":"kata","cpuBurstStrategy":{"policy":"auto"}}]}]}`,
+		},
+	})
+	assert.NoError(t, err)
+	spec, err = getRuntimeClassConfigSpec(node, &got)
+	assert.NoError(t, err)
+	assert.Equal(t, []slov1alpha1.RuntimeClassStrategy{
+		{
+			RuntimeClassName: "gvisor",
+			CPUBurstStrategy: &slov1alpha1.CPUBurstStrategy{CPUBurstConfig: slov1alpha1.CPUBurstConfig{Policy: slov1alpha1.CPUBurstNone}},
+		},
+		{
+			RuntimeClassName: "kata",
+			CPUBurstStrategy: &slov1alpha1.CPUBurstStrategy{CPUBurstConfig: slov1alpha1.CPUBurstConfig{Policy: slov1alpha1.CPUBurstAuto}},
+		},
+	}, spec)
+	spec, err = getRuntimeClassConfigSpec(&corev1.Node{}, &got)
+	assert.NoError(t, err)
+	assert.Equal(t, got.ClusterStrategies, spec)
+
+	// keep the old config if the runtime class is duplicated
+	got, err = calculateRuntimeClassCfgMerged(oldCfg, &corev1.ConfigMap{
+		Data: map[string]string{
+			config.RuntimeClassConfigKey: `{"clusterStrategies":[{"runtimeClassName":"kata"},{"runtimeClassName":"kata"}]}`,
+		},
+	})
+	assert.Error(t, err)
+	assert.Equal(t, oldCfg, got)
+}
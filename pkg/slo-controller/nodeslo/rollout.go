@@ -0,0 +1,190 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeslo
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/config"
+)
+
+const (
+	// AnnotationNodeSLORevision records the revision of the slo-controller config which the NodeSLO is updated to.
+	AnnotationNodeSLORevision = extension.DomainPrefix + "nodeslo-revision"
+	// AnnotationNodeSLORolloutStatus records the NodeSLORolloutStatus on the slo-controller configmap.
+	AnnotationNodeSLORolloutStatus = extension.DomainPrefix + "nodeslo-rollout-status"
+)
+
+// nodeSLOConfigKeys are the keys of the slo-controller configmap which make up the NodeSLO spec.
+var nodeSLOConfigKeys = []string{
+	config.ResourceThresholdConfigKey,
+	config.ResourceQOSConfigKey,
+	config.CPUBurstConfigKey,
+	config.CPUReservationConfigKey,
+	config.RuntimeClassConfigKey,
+}
+
+type NodeSLORolloutPhase string
+
+const (
+	NodeSLORolloutProgressing NodeSLORolloutPhase = "Progressing"
+	NodeSLORolloutCompleted   NodeSLORolloutPhase = "Completed"
+	// NodeSLORolloutHalted means no more node is updated to the revision until the config changes again.
+	NodeSLORolloutHalted NodeSLORolloutPhase = "Halted"
+)
+
+// NodeSLORolloutStatus is the status of the rollout of one revision of the slo-controller config.
+type NodeSLORolloutStatus struct {
+	Revision     string              `json:"revision"`
+	Phase        NodeSLORolloutPhase `json:"phase"`
+	StartTime    metav1.Time         `json:"startTime"`
+	UpdatedNodes int32               `json:"updatedNodes"`
+	TotalNodes   int32               `json:"totalNodes"`
+	// Evictions is the number of the pods evicted by koordlet on the updated nodes since the revision started.
+	Evictions int64  `json:"evictions"`
+	Message   string `json:"message,omitempty"`
+}
+
+// getNodeSLORevision returns the revision of the NodeSLO config in the configmap. The rollout config itself is
+// excluded, so changing the pace of the rollout does not start a new revision.
+func getNodeSLORevision(configMap *corev1.ConfigMap) string {
+	hasher := fnv.New32a()
+	if configMap != nil {
+		for _, key := range nodeSLOConfigKeys {
+			if value, ok := configMap.Data[key]; ok {
+				_, _ = fmt.Fprintf(hasher, "%s=%s\n", key, value)
+			}
+		}
+	}
+	return fmt.Sprintf("%08x", hasher.Sum32())
+}
+
+// parseNodeSLORolloutCfg returns the rollout config in the configmap, the rollout is disabled if it is not set.
+func parseNodeSLORolloutCfg(configMap *corev1.ConfigMap) (*config.NodeSLORolloutCfg, error) {
+	cfg := &config.NodeSLORolloutCfg{}
+	if configMap == nil || configMap.Data[config.NodeSLORolloutConfigKey] == "" {
+		return cfg, nil
+	}
+	if err := json.Unmarshal([]byte(configMap.Data[config.NodeSLORolloutConfigKey]), cfg); err != nil {
+		return nil, err
+	}
+	if cfg.Percentage != nil && (*cfg.Percentage < 0 || *cfg.Percentage > 100) {
+		return nil, fmt.Errorf("percentage %d is out of range [0, 100]", *cfg.Percentage)
+	}
+	if cfg.MaxEvictions != nil && *cfg.MaxEvictions < 0 {
+		return nil, fmt.Errorf("maxEvictions %d should not be negative", *cfg.MaxEvictions)
+	}
+	if cfg.CanaryNodeSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(cfg.CanaryNodeSelector); err != nil {
+			return nil, fmt.Errorf("invalid canaryNodeSelector, err: %v", err)
+		}
+	}
+	return cfg, nil
+}
+
+func isNodeSLORolloutEnabled(cfg *config.NodeSLORolloutCfg) bool {
+	return cfg != nil && cfg.Enable != nil && *cfg.Enable
+}
+
+// isNodeInRollout checks if the node is selected to be updated to the latest revision. The canary nodes are always
+// selected, and the other nodes are selected by the hash of the node name, so the selected nodes stay selected as
+// the percentage grows.
+func isNodeInRollout(node *corev1.Node, cfg *config.NodeSLORolloutCfg) bool {
+	if cfg.CanaryNodeSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(cfg.CanaryNodeSelector)
+		if err == nil && selector.Matches(labels.Set(node.Labels)) {
+			return true
+		}
+	}
+	if cfg.Percentage == nil {
+		return false
+	}
+	hasher := fnv.New32a()
+	_, _ = hasher.Write([]byte(node.Name))
+	return int64(hasher.Sum32()%100) < *cfg.Percentage
+}
+
+// GetNodeSLORolloutStatus returns the NodeSLORolloutStatus recorded on the slo-controller configmap.
+func GetNodeSLORolloutStatus(configMap *corev1.ConfigMap) (*NodeSLORolloutStatus, error) {
+	if configMap == nil || configMap.Annotations[AnnotationNodeSLORolloutStatus] == "" {
+		return nil, nil
+	}
+	status := &NodeSLORolloutStatus{}
+	if err := json.Unmarshal([]byte(configMap.Annotations[AnnotationNodeSLORolloutStatus]), status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func getNodeSLORevisionOfObject(nodeSLO *slov1alpha1.NodeSLO) string {
+	return nodeSLO.Annotations[AnnotationNodeSLORevision]
+}
+
+func setNodeSLORevisionOfObject(nodeSLO *slov1alpha1.NodeSLO, revision string) {
+	if nodeSLO.Annotations == nil {
+		nodeSLO.Annotations = map[string]string{}
+	}
+	nodeSLO.Annotations[AnnotationNodeSLORevision] = revision
+}
+
+// nodeSLORollout is the rollout state used to decide whether a NodeSLO can be updated to the latest revision.
+type nodeSLORollout struct {
+	revision string
+	// cfg is nil if the rollout is disabled
+	cfg    *config.NodeSLORolloutCfg
+	halted bool
+}
+
+func (r *nodeSLORollout) isNodeAllowed(node *corev1.Node) bool {
+	if !isNodeSLORolloutEnabled(r.cfg) {
+		return true
+	}
+	if r.halted {
+		return false
+	}
+	return isNodeInRollout(node, r.cfg)
+}
+
+func newNodeSLORollout(configMap *corev1.ConfigMap) *nodeSLORollout {
+	rollout := &nodeSLORollout{revision: getNodeSLORevision(configMap)}
+	cfg, err := parseNodeSLORolloutCfg(configMap)
+	if err != nil {
+		// hold the changes rather than applying them to all the nodes at once
+		klog.Warningf("failed to parse nodeslo rollout config, hold the changes of revision %s, err: %v", rollout.revision, err)
+		cfg = &config.NodeSLORolloutCfg{Enable: pointer.Bool(true)}
+	}
+	if !isNodeSLORolloutEnabled(cfg) {
+		return rollout
+	}
+	rollout.cfg = cfg
+	status, err := GetNodeSLORolloutStatus(configMap)
+	if err != nil {
+		klog.Warningf("failed to parse nodeslo rollout status, err: %v", err)
+	}
+	rollout.halted = status != nil && status.Revision == rollout.revision && status.Phase == NodeSLORolloutHalted
+	return rollout
+}
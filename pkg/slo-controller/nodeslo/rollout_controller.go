@@ -0,0 +1,232 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeslo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/config"
+)
+
+const (
+	// koordletReasonEvictPodSuccess is the reason of the node event recorded by koordlet after evicting a pod
+	koordletReasonEvictPodSuccess = "evictPodSuccess"
+
+	ReasonNodeSLORolloutHalted    = "NodeSLORolloutHalted"
+	ReasonNodeSLORolloutCompleted = "NodeSLORolloutCompleted"
+
+	// nodeSLORolloutResyncInterval is the interval to refresh the status of the progressing rollout
+	nodeSLORolloutResyncInterval = 30 * time.Second
+)
+
+// NodeSLORolloutReconciler reports the rollout status of the latest revision of the slo-controller config on the
+// configmap, and halts the rollout when the pods evicted by koordlet on the updated nodes exceed the limit.
+type NodeSLORolloutReconciler struct {
+	client.Client
+	// APIReader lists the events without caching all of them
+	APIReader client.Reader
+	Recorder  record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;create;patch
+// +kubebuilder:rbac:groups=slo.koordinator.sh,resources=nodeslos,verbs=get;list;watch
+
+func (r *NodeSLORolloutReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	configMap := &corev1.ConfigMap{}
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: config.ConfigNameSpace, Name: config.SLOCtrlConfigMap}, configMap)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		klog.Errorf("failed to get configmap %s/%s, err: %v", config.ConfigNameSpace, config.SLOCtrlConfigMap, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	rolloutCfg, err := parseNodeSLORolloutCfg(configMap)
+	if err != nil {
+		klog.Warningf("failed to parse nodeslo rollout config, err: %v", err)
+		r.Recorder.Eventf(configMap, corev1.EventTypeWarning, config.ReasonSLOConfigUnmarshalFailed,
+			"failed to unmarshal NodeSLORolloutCfg, err: %s", err)
+		return ctrl.Result{}, nil
+	}
+	if !isNodeSLORolloutEnabled(rolloutCfg) {
+		return ctrl.Result{}, nil
+	}
+
+	oldStatus, err := GetNodeSLORolloutStatus(configMap)
+	if err != nil {
+		klog.Warningf("failed to parse nodeslo rollout status, reset it, err: %v", err)
+	}
+	status, err := r.calculateRolloutStatus(ctx, rolloutCfg, getNodeSLORevision(configMap), oldStatus)
+	if err != nil {
+		klog.Errorf("failed to calculate nodeslo rollout status, err: %v", err)
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	if oldStatus == nil || !reflect.DeepEqual(*oldStatus, *status) {
+		if err = r.updateRolloutStatus(ctx, configMap, status); err != nil {
+			klog.Errorf("failed to update nodeslo rollout status on configmap %s/%s, err: %v",
+				config.ConfigNameSpace, config.SLOCtrlConfigMap, err)
+			return ctrl.Result{Requeue: true}, err
+		}
+		klog.V(4).Infof("nodeslo rollout of revision %s is %s, %d/%d nodes updated, %d evictions",
+			status.Revision, status.Phase, status.UpdatedNodes, status.TotalNodes, status.Evictions)
+		phaseChanged := oldStatus == nil || oldStatus.Revision != status.Revision || oldStatus.Phase != status.Phase
+		if phaseChanged && status.Phase == NodeSLORolloutHalted {
+			r.Recorder.Event(configMap, corev1.EventTypeWarning, ReasonNodeSLORolloutHalted, status.Message)
+		} else if phaseChanged && status.Phase == NodeSLORolloutCompleted {
+			r.Recorder.Eventf(configMap, corev1.EventTypeNormal, ReasonNodeSLORolloutCompleted,
+				"nodeslo revision %s is rolled out to all the %d nodes", status.Revision, status.TotalNodes)
+		}
+	}
+
+	if status.Phase == NodeSLORolloutProgressing {
+		return ctrl.Result{RequeueAfter: nodeSLORolloutResyncInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// calculateRolloutStatus counts the nodes updated to the revision and the evictions on them. The halted rollout
+// stays halted until a new revision starts.
+func (r *NodeSLORolloutReconciler) calculateRolloutStatus(ctx context.Context, cfg *config.NodeSLORolloutCfg,
+	revision string, oldStatus *NodeSLORolloutStatus) (*NodeSLORolloutStatus, error) {
+	status := &NodeSLORolloutStatus{
+		Revision:  revision,
+		Phase:     NodeSLORolloutProgressing,
+		StartTime: metav1.Now(),
+	}
+	if oldStatus != nil && oldStatus.Revision == revision {
+		status.StartTime = oldStatus.StartTime
+		status.Message = oldStatus.Message
+		if oldStatus.Phase == NodeSLORolloutHalted {
+			status.Phase = NodeSLORolloutHalted
+		}
+	}
+
+	nodeSLOList := &slov1alpha1.NodeSLOList{}
+	if err := r.Client.List(ctx, nodeSLOList); err != nil {
+		return nil, err
+	}
+	updatedNodes := map[string]bool{}
+	for i := range nodeSLOList.Items {
+		if getNodeSLORevisionOfObject(&nodeSLOList.Items[i]) == revision {
+			updatedNodes[nodeSLOList.Items[i].Name] = true
+		}
+	}
+	status.TotalNodes = int32(len(nodeSLOList.Items))
+	status.UpdatedNodes = int32(len(updatedNodes))
+
+	evictions, err := r.countEvictions(ctx, updatedNodes, status.StartTime.Time)
+	if err != nil {
+		return nil, err
+	}
+	status.Evictions = evictions
+
+	if status.Phase == NodeSLORolloutHalted {
+		return status, nil
+	}
+	if cfg.MaxEvictions != nil && evictions > *cfg.MaxEvictions {
+		status.Phase = NodeSLORolloutHalted
+		status.Message = fmt.Sprintf("nodeslo rollout of revision %s is halted since %d pods are evicted on the updated nodes, exceeding %d",
+			revision, evictions, *cfg.MaxEvictions)
+		return status, nil
+	}
+	if status.UpdatedNodes >= status.TotalNodes {
+		status.Phase = NodeSLORolloutCompleted
+	}
+	status.Message = ""
+	return status, nil
+}
+
+// countEvictions counts the pods evicted by koordlet on the nodes since the time.
+func (r *NodeSLORolloutReconciler) countEvictions(ctx context.Context, nodes map[string]bool, since time.Time) (int64, error) {
+	if len(nodes) == 0 {
+		return 0, nil
+	}
+	eventList := &corev1.EventList{}
+	if err := r.APIReader.List(ctx, eventList, client.MatchingFields{
+		"involvedObject.kind": "Node",
+		"reason":              koordletReasonEvictPodSuccess,
+	}); err != nil {
+		return 0, err
+	}
+	var evictions int64
+	for i := range eventList.Items {
+		event := &eventList.Items[i]
+		if event.InvolvedObject.Kind != "Node" || event.Reason != koordletReasonEvictPodSuccess ||
+			!nodes[event.InvolvedObject.Name] {
+			continue
+		}
+		lastTime := event.LastTimestamp.Time
+		if lastTime.IsZero() {
+			lastTime = event.EventTime.Time
+		}
+		if lastTime.Before(since) {
+			continue
+		}
+		if event.Count > 1 {
+			evictions += int64(event.Count)
+		} else {
+			evictions++
+		}
+	}
+	return evictions, nil
+}
+
+func (r *NodeSLORolloutReconciler) updateRolloutStatus(ctx context.Context, configMap *corev1.ConfigMap, status *NodeSLORolloutStatus) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	patch := client.MergeFrom(configMap.DeepCopy())
+	if configMap.Annotations == nil {
+		configMap.Annotations = map[string]string{}
+	}
+	configMap.Annotations[AnnotationNodeSLORolloutStatus] = string(data)
+	return r.Client.Patch(ctx, configMap, patch)
+}
+
+func isSLOCtrlConfigMap(obj client.Object) bool {
+	return obj.GetNamespace() == config.ConfigNameSpace && obj.GetName() == config.SLOCtrlConfigMap
+}
+
+func (r *NodeSLORolloutReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("nodeslo-rollout").
+		For(&corev1.ConfigMap{}, builder.WithPredicates(predicate.NewPredicateFuncs(isSLOCtrlConfigMap))).
+		Complete(r)
+}
+
+var _ reconcile.Reconciler = &NodeSLORolloutReconciler{}
@@ -0,0 +1,216 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeslo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/config"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+func Test_getNodeSLORevision(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		Data: map[string]string{
+			config.CPUBurstConfigKey: `{"clusterStrategy":{"cfsQuotaBurstPeriodSeconds":60}}`,
+		},
+	}
+	revision := getNodeSLORevision(configMap)
+	assert.NotEqual(t, getNodeSLORevision(nil), revision)
+
+	// the rollout config is not a part of the revision
+	configMap.Data[config.NodeSLORolloutConfigKey] = `{"enable":true,"percentage":50}`
+	assert.Equal(t, revision, getNodeSLORevision(configMap))
+
+	configMap.Data[config.CPUBurstConfigKey] = `{"clusterStrategy":{"cfsQuotaBurstPeriodSeconds":30}}`
+	assert.NotEqual(t, revision, getNodeSLORevision(configMap))
+}
+
+func Test_parseNodeSLORolloutCfg(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfgStr  string
+		want    *config.NodeSLORolloutCfg
+		wantErr bool
+	}{
+		{
+			name: "not set",
+			want: &config.NodeSLORolloutCfg{},
+		},
+		{
+			name:   "valid config",
+			cfgStr: `{"enable":true,"percentage":20,"maxEvictions":5}`,
+			want: &config.NodeSLORolloutCfg{
+				Enable:       pointer.Bool(true),
+				Percentage:   pointer.Int64(20),
+				MaxEvictions: pointer.Int64(5),
+			},
+		},
+		{
+			name:    "percentage out of range",
+			cfgStr:  `{"enable":true,"percentage":120}`,
+			wantErr: true,
+		},
+		{
+			name:    "negative max evictions",
+			cfgStr:  `{"enable":true,"maxEvictions":-1}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			cfgStr:  `{"enable":true`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configMap := &corev1.ConfigMap{Data: map[string]string{}}
+			if tt.cfgStr != "" {
+				configMap.Data[config.NodeSLORolloutConfigKey] = tt.cfgStr
+			}
+			got, err := parseNodeSLORolloutCfg(configMap)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_isNodeInRollout(t *testing.T) {
+	canaryNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-0", Labels: map[string]string{"canary": "true"}}}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+	cfg := &config.NodeSLORolloutCfg{
+		Enable:             pointer.Bool(true),
+		CanaryNodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"canary": "true"}},
+	}
+	assert.True(t, isNodeInRollout(canaryNode, cfg))
+	assert.False(t, isNodeInRollout(node, cfg))
+
+	cfg.Percentage = pointer.Int64(0)
+	assert.False(t, isNodeInRollout(node, cfg))
+	cfg.Percentage = pointer.Int64(100)
+	assert.True(t, isNodeInRollout(node, cfg))
+}
+
+func TestNodeSLORollout(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultMutableFeatureGate, features.NodeSLORollout, true)()
+
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	slov1alpha1.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	r := &NodeSLOReconciler{Client: fakeClient, Scheme: scheme}
+	configMapCacheHandler := NewSLOCfgHandlerForConfigMapEvent(fakeClient, DefaultSLOCfg(), &record.FakeRecorder{})
+	r.sloCfgCache = configMapCacheHandler
+	rolloutReconciler := &NodeSLORolloutReconciler{Client: fakeClient, APIReader: fakeClient, Recorder: &record.FakeRecorder{}}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: config.SLOCtrlConfigMap, Namespace: config.ConfigNameSpace},
+		Data: map[string]string{
+			config.NodeSLORolloutConfigKey: `{"enable":true,"canaryNodeSelector":{"matchLabels":{"canary":"true"}},"maxEvictions":1}`,
+		},
+	}
+	assert.NoError(t, fakeClient.Create(context.TODO(), configMap))
+	configMapCacheHandler.SyncCacheIfChanged(configMap)
+	nodes := []*corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "canary-node", Labels: map[string]string{"canary": "true"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}},
+	}
+	for _, node := range nodes {
+		assert.NoError(t, fakeClient.Create(context.TODO(), node))
+		_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: node.Name}})
+		assert.NoError(t, err)
+	}
+
+	// the new nodes are updated to the first revision directly
+	_, err := rolloutReconciler.Reconcile(context.TODO(), ctrl.Request{})
+	assert.NoError(t, err)
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: config.ConfigNameSpace, Name: config.SLOCtrlConfigMap}, configMap))
+	status, err := GetNodeSLORolloutStatus(configMap)
+	assert.NoError(t, err)
+	assert.Equal(t, NodeSLORolloutCompleted, status.Phase)
+	assert.Equal(t, int32(2), status.UpdatedNodes)
+
+	// only the canary node is updated to the new revision
+	configMap.Data[config.CPUBurstConfigKey] = `{"clusterStrategy":{"cfsQuotaBurstPeriodSeconds":60}}`
+	assert.NoError(t, fakeClient.Update(context.TODO(), configMap))
+	configMapCacheHandler.SyncCacheIfChanged(configMap)
+	for _, node := range nodes {
+		_, err = r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: node.Name}})
+		assert.NoError(t, err)
+	}
+	revision := getNodeSLORevision(configMap)
+	nodeSLO := &slov1alpha1.NodeSLO{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: "canary-node"}, nodeSLO))
+	assert.Equal(t, revision, getNodeSLORevisionOfObject(nodeSLO))
+	assert.Equal(t, int64(60), *nodeSLO.Spec.CPUBurstStrategy.CFSQuotaBurstPeriodSeconds)
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: "test-node"}, nodeSLO))
+	assert.NotEqual(t, revision, getNodeSLORevisionOfObject(nodeSLO))
+
+	result, err := rolloutReconciler.Reconcile(context.TODO(), ctrl.Request{})
+	assert.NoError(t, err)
+	assert.Equal(t, nodeSLORolloutResyncInterval, result.RequeueAfter)
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: config.ConfigNameSpace, Name: config.SLOCtrlConfigMap}, configMap))
+	status, err = GetNodeSLORolloutStatus(configMap)
+	assert.NoError(t, err)
+	assert.Equal(t, revision, status.Revision)
+	assert.Equal(t, NodeSLORolloutProgressing, status.Phase)
+	assert.Equal(t, int32(1), status.UpdatedNodes)
+	assert.Equal(t, int32(2), status.TotalNodes)
+
+	// halt the rollout when the evictions on the updated nodes exceed the limit
+	assert.NoError(t, fakeClient.Create(context.TODO(), &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "canary-node.evict", Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Node", Name: "canary-node"},
+		Reason:         koordletReasonEvictPodSuccess,
+		Count:          2,
+		LastTimestamp:  metav1.NewTime(status.StartTime.Add(time.Minute)),
+	}))
+	_, err = rolloutReconciler.Reconcile(context.TODO(), ctrl.Request{})
+	assert.NoError(t, err)
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: config.ConfigNameSpace, Name: config.SLOCtrlConfigMap}, configMap))
+	status, err = GetNodeSLORolloutStatus(configMap)
+	assert.NoError(t, err)
+	assert.Equal(t, NodeSLORolloutHalted, status.Phase)
+	assert.Equal(t, int64(2), status.Evictions)
+
+	// the halted rollout holds all the other nodes even if they are selected
+	configMap.Data[config.NodeSLORolloutConfigKey] = `{"enable":true,"percentage":100,"maxEvictions":1}`
+	assert.NoError(t, fakeClient.Update(context.TODO(), configMap))
+	result, err = r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-node"}})
+	assert.NoError(t, err)
+	assert.Equal(t, nodeSLORolloutRequeueInterval, result.RequeueAfter)
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: "test-node"}, nodeSLO))
+	assert.NotEqual(t, revision, getNodeSLORevisionOfObject(nodeSLO))
+}
@@ -114,6 +114,12 @@ func (c *Cache) set(key string, value interface{}, expiration time.Duration) err
 	return nil
 }
 
+func (c *Cache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, key)
+}
+
 func (c *Cache) Get(key string) (interface{}, bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -145,51 +145,35 @@ func GetContainerCurTasksPath(podParentDir string, c *corev1.ContainerStatus) (s
 }
 
 func GetContainerCurCPUShare(podParentDir string, c *corev1.ContainerStatus) (int64, error) {
-	cgroupPath, err := GetContainerCgroupCPUSharePath(podParentDir, c)
-	if err != nil {
-		return 0, err
-	}
-	rawContent, err := ioutil.ReadFile(cgroupPath)
+	containerPath, err := GetContainerCgroupPathWithKube(podParentDir, c)
 	if err != nil {
 		return 0, err
 	}
-	return strconv.ParseInt(strings.TrimSpace(string(rawContent)), 10, 64)
+	return readCgroupInt(containerPath, system.CPUShares)
 }
 
 func GetContainerCurCFSPeriod(podParentDir string, c *corev1.ContainerStatus) (int64, error) {
-	cgroupPath, err := GetContainerCgroupCFSPeriodPath(podParentDir, c)
-	if err != nil {
-		return 0, err
-	}
-	rawContent, err := ioutil.ReadFile(cgroupPath)
+	containerPath, err := GetContainerCgroupPathWithKube(podParentDir, c)
 	if err != nil {
 		return 0, err
 	}
-	return strconv.ParseInt(strings.TrimSpace(string(rawContent)), 10, 64)
+	return readCgroupInt(containerPath, system.CPUCFSPeriod)
 }
 
 func GetContainerCurCFSQuota(podParentDir string, c *corev1.ContainerStatus) (int64, error) {
-	cgroupPath, err := GetContainerCgroupCFSQuotaPath(podParentDir, c)
-	if err != nil {
-		return 0, err
-	}
-	rawContent, err := ioutil.ReadFile(cgroupPath)
+	containerPath, err := GetContainerCgroupPathWithKube(podParentDir, c)
 	if err != nil {
 		return 0, err
 	}
-	return strconv.ParseInt(strings.TrimSpace(string(rawContent)), 10, 64)
+	return readCgroupInt(containerPath, system.CPUCFSQuota)
 }
 
 func GetContainerCurMemLimitBytes(podParentDir string, c *corev1.ContainerStatus) (int64, error) {
-	cgroupPath, err := GetContainerCgroupMemLimitPath(podParentDir, c)
-	if err != nil {
-		return 0, err
-	}
-	rawContent, err := ioutil.ReadFile(cgroupPath)
+	containerPath, err := GetContainerCgroupPathWithKube(podParentDir, c)
 	if err != nil {
 		return 0, err
 	}
-	return strconv.ParseInt(strings.TrimSpace(string(rawContent)), 10, 64)
+	return readCgroupInt(containerPath, system.MemoryLimit)
 }
 
 func GetContainerCurTasks(podParentDir string, c *corev1.ContainerStatus) ([]int, error) {
@@ -24,6 +24,7 @@ import (
 	"strconv"
 	"strings"
 
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/util/system"
 )
 
@@ -103,3 +104,11 @@ func GenerateCPUSetStr(cpuset []int32) string {
 func WriteCgroupCPUSet(cgroupFileDir, cpusetStr string) error {
 	return ioutil.WriteFile(filepath.Join(cgroupFileDir, system.CPUSFileName), []byte(cpusetStr), 0644)
 }
+
+// GetNodeSLOReservedCPUs returns the CPUs reserved for the offload stacks by the NodeSLO
+func GetNodeSLOReservedCPUs(nodeSLO *slov1alpha1.NodeSLO) ([]int32, error) {
+	if nodeSLO == nil || nodeSLO.Spec.CPUReservationStrategy == nil || nodeSLO.Spec.CPUReservationStrategy.ReservedCPUs == nil {
+		return nil, nil
+	}
+	return ParseCPUSetStr(*nodeSLO.Spec.CPUReservationStrategy.ReservedCPUs)
+}
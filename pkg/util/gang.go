@@ -0,0 +1,143 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+// GetGangKey returns the namespaced name of the gang the pod belongs to, "" if the pod is not a gang member.
+func GetGangKey(pod *corev1.Pod) string {
+	if pod == nil {
+		return ""
+	}
+	gangName := pod.Labels[v1alpha1.PodGroupLabel]
+	if gangName == "" {
+		gangName = pod.Annotations[extension.AnnotationGangName]
+	}
+	if gangName == "" {
+		return ""
+	}
+	return pod.Namespace + "/" + gangName
+}
+
+// GetGangMinMember returns the minMember of the gang declared by the pod, 0 if it is not declared.
+func GetGangMinMember(pod *corev1.Pod) int {
+	minMember, err := strconv.Atoi(pod.Annotations[extension.AnnotationGangMinNum])
+	if err != nil || minMember < 0 {
+		return 0
+	}
+	return minMember
+}
+
+// GetGangTotalMember returns the total number of the gang members declared by the pod, which is the
+// minMember if it is not declared.
+func GetGangTotalMember(pod *corev1.Pod) int {
+	minMember := GetGangMinMember(pod)
+	totalMember, err := strconv.Atoi(pod.Annotations[extension.AnnotationGangTotalNum])
+	if err != nil || totalMember < minMember {
+		return minMember
+	}
+	return totalMember
+}
+
+// GangInfoFunc returns the minMember and the number of the alive members of the gang.
+type GangInfoFunc func(gangKey string, pod *corev1.Pod) (minMember, aliveMembers int)
+
+type gangEvictionCandidate struct {
+	pods         []*corev1.Pod
+	minMember    int
+	aliveMembers int
+}
+
+func (c *gangEvictionCandidate) whole() bool {
+	return len(c.pods) >= c.aliveMembers
+}
+
+// SelectGangEvictablePods returns the pods which can be evicted without breaking their gangs. The members of a
+// gang are only evicted if the gang keeps at least minMember alive members, unless the whole gang is selected.
+// The order of the pods is kept, except that the entire gangs are evicted from small to large before the members
+// fragmenting a gang, so that the small gangs are preferred to be evicted over the large ones.
+func SelectGangEvictablePods(pods []*corev1.Pod, gangInfo GangInfoFunc) []*corev1.Pod {
+	candidates := map[string]*gangEvictionCandidate{}
+	seen := map[string]bool{}
+	for _, pod := range pods {
+		gangKey := GetGangKey(pod)
+		podKey := pod.Namespace + "/" + pod.Name
+		if gangKey == "" || seen[podKey] {
+			continue
+		}
+		seen[podKey] = true
+		candidate, ok := candidates[gangKey]
+		if !ok {
+			candidate = &gangEvictionCandidate{}
+			candidate.minMember, candidate.aliveMembers = gangInfo(gangKey, pod)
+			candidates[gangKey] = candidate
+		}
+		candidate.pods = append(candidate.pods, pod)
+	}
+
+	allowed := map[string]bool{}
+	for _, candidate := range candidates {
+		if candidate.whole() {
+			for _, pod := range candidate.pods {
+				allowed[pod.Namespace+"/"+pod.Name] = true
+			}
+			continue
+		}
+		quota := candidate.aliveMembers - candidate.minMember
+		for i := 0; i < quota && i < len(candidate.pods); i++ {
+			allowed[candidate.pods[i].Namespace+"/"+candidate.pods[i].Name] = true
+		}
+	}
+
+	// 0: not a gang member, 1: member of an entire gang, 2: member fragmenting a gang
+	rank := func(pod *corev1.Pod) (int, int) {
+		candidate := candidates[GetGangKey(pod)]
+		if candidate == nil {
+			return 0, 0
+		}
+		if candidate.whole() {
+			return 1, candidate.aliveMembers
+		}
+		return 2, 0
+	}
+	var selected []*corev1.Pod
+	for _, pod := range pods {
+		podKey := pod.Namespace + "/" + pod.Name
+		if GetGangKey(pod) == "" || allowed[podKey] {
+			selected = append(selected, pod)
+			// a pod listed twice is only selected once
+			delete(allowed, podKey)
+		}
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		rankI, sizeI := rank(selected[i])
+		rankJ, sizeJ := rank(selected[j])
+		if rankI != rankJ {
+			return rankI < rankJ
+		}
+		return sizeI < sizeJ
+	})
+	return selected
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func newTestGangPod(name, gangName string, minMember string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+		},
+	}
+	if gangName != "" {
+		pod.Annotations = map[string]string{
+			extension.AnnotationGangName:   gangName,
+			extension.AnnotationGangMinNum: minMember,
+		}
+	}
+	return pod
+}
+
+func TestGetGangKey(t *testing.T) {
+	pod := newTestGangPod("pod-1", "gang-a", "2")
+	assert.Equal(t, "default/gang-a", GetGangKey(pod))
+	assert.Equal(t, 2, GetGangMinMember(pod))
+	assert.Equal(t, 2, GetGangTotalMember(pod))
+
+	pod.Labels = map[string]string{v1alpha1.PodGroupLabel: "pg-b"}
+	assert.Equal(t, "default/pg-b", GetGangKey(pod))
+	pod.Annotations[extension.AnnotationGangTotalNum] = "4"
+	assert.Equal(t, 4, GetGangTotalMember(pod))
+
+	assert.Equal(t, "", GetGangKey(newTestGangPod("pod-2", "", "")))
+	assert.Equal(t, 0, GetGangMinMember(newTestGangPod("pod-3", "gang-c", "invalid")))
+}
+
+func TestSelectGangEvictablePods(t *testing.T) {
+	gangSizes := map[string][2]int{
+		"default/small": {2, 2},
+		"default/large": {4, 6},
+		"default/tight": {3, 3},
+	}
+	gangInfo := func(gangKey string, pod *corev1.Pod) (int, int) {
+		return gangSizes[gangKey][0], gangSizes[gangKey][1]
+	}
+	large1 := newTestGangPod("large-1", "large", "4")
+	large2 := newTestGangPod("large-2", "large", "4")
+	large3 := newTestGangPod("large-3", "large", "4")
+	small1 := newTestGangPod("small-1", "small", "2")
+	small2 := newTestGangPod("small-2", "small", "2")
+	tight1 := newTestGangPod("tight-1", "tight", "3")
+	normal := newTestGangPod("normal", "", "")
+
+	selected := SelectGangEvictablePods([]*corev1.Pod{large1, large2, large3, tight1, small1, normal, small2, small1}, gangInfo)
+	var names []string
+	for _, pod := range selected {
+		names = append(names, pod.Name)
+	}
+	// the non-gang pod first, then the entire small gang, then at most 2 members of the large gang,
+	// the member of the tight gang can't be evicted
+	assert.Equal(t, []string{"normal", "small-1", "small-2", "large-1", "large-2"}, names)
+}
@@ -19,15 +19,20 @@ package util
 import (
 	"fmt"
 	"io/ioutil"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+
+	"github.com/koordinator-sh/koordinator/pkg/util/system"
 )
 
 const (
 	ProcMemInfoPath = "/proc/meminfo"
+	// NUMANodeSysDir is the dir of the NUMA Nodes relative to the sys root dir
+	NUMANodeSysDir = "devices/system/node"
 )
 
 type MemInfo struct {
@@ -98,7 +103,12 @@ func readMemInfo(path string) (*MemInfo, error) {
 		}
 		valFields := strings.Fields(fields[1])
 		val, _ := strconv.ParseUint(valFields[0], 10, 64)
-		statMap[fields[0]] = val
+		key := fields[0]
+		// the meminfo of the NUMA Node is prefixed with the node ID, e.g. "Node 0 MemTotal:"
+		if keyFields := strings.Fields(key); len(keyFields) == 3 && keyFields[0] == "Node" {
+			key = keyFields[2]
+		}
+		statMap[key] = val
 	}
 
 	elem := reflect.ValueOf(&info).Elem()
@@ -129,6 +139,78 @@ func GetMemInfoUsageKB() (int64, error) {
 	return usage, nil
 }
 
+// GetNUMANodeMemInfos returns the meminfo of each NUMA Node, the key is the NUMA Node ID.
+func GetNUMANodeMemInfos() (map[int32]*MemInfo, error) {
+	nodeDirs, err := filepath.Glob(filepath.Join(system.Conf.SysRootDir, NUMANodeSysDir, "node*"))
+	if err != nil {
+		return nil, err
+	}
+	memInfos := map[int32]*MemInfo{}
+	for _, nodeDir := range nodeDirs {
+		nodeID, err := strconv.ParseInt(strings.TrimPrefix(filepath.Base(nodeDir), "node"), 10, 32)
+		if err != nil {
+			continue
+		}
+		memInfo, err := readMemInfo(filepath.Join(nodeDir, "meminfo"))
+		if err != nil {
+			return nil, err
+		}
+		memInfos[int32(nodeID)] = memInfo
+	}
+	return memInfos, nil
+}
+
+// HugePagesInfo is the hugepages of one page size.
+type HugePagesInfo struct {
+	PageSizeKB uint64
+	Total      uint64
+	Free       uint64
+}
+
+// GetNUMANodeHugePages returns the hugepages of each NUMA Node, the key is the NUMA Node ID.
+func GetNUMANodeHugePages() (map[int32][]HugePagesInfo, error) {
+	nodeDirs, err := filepath.Glob(filepath.Join(system.Conf.SysRootDir, NUMANodeSysDir, "node*"))
+	if err != nil {
+		return nil, err
+	}
+	hugePages := map[int32][]HugePagesInfo{}
+	for _, nodeDir := range nodeDirs {
+		nodeID, err := strconv.ParseInt(strings.TrimPrefix(filepath.Base(nodeDir), "node"), 10, 32)
+		if err != nil {
+			continue
+		}
+		// e.g. /sys/devices/system/node/node0/hugepages/hugepages-2048kB/nr_hugepages
+		pageDirs, err := filepath.Glob(filepath.Join(nodeDir, "hugepages", "hugepages-*kB"))
+		if err != nil {
+			return nil, err
+		}
+		for _, pageDir := range pageDirs {
+			pageSize, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(filepath.Base(pageDir), "hugepages-"), "kB"), 10, 64)
+			if err != nil {
+				continue
+			}
+			total, err := readUint(filepath.Join(pageDir, "nr_hugepages"))
+			if err != nil {
+				return nil, err
+			}
+			free, err := readUint(filepath.Join(pageDir, "free_hugepages"))
+			if err != nil {
+				return nil, err
+			}
+			hugePages[int32(nodeID)] = append(hugePages[int32(nodeID)], HugePagesInfo{PageSizeKB: pageSize, Total: total, Free: free})
+		}
+	}
+	return hugePages, nil
+}
+
+func readUint(path string) (uint64, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+}
+
 func readCgroupMemStat(memStatPath string) (int64, error) {
 	// memory.stat usage: total_inactive_anon + total_active_anon + total_unevictable
 	// format: ...total_inactive_anon $total_inactive_anon\ntotal_active_anon $total_active_anon\n
@@ -139,8 +221,13 @@ func readCgroupMemStat(memStatPath string) (int64, error) {
 		return 0, err
 	}
 	var total int64 = 0
+	// the entries are hierarchical on the cgroup v2 without the prefix "total_"
+	prefix := "total_"
+	if system.IsCgroupV2() {
+		prefix = ""
+	}
 	// check if all "usage" entries are exactly counted
-	entryMap := map[string]bool{"total_inactive_anon": true, "total_active_anon": true, "total_unevictable": true}
+	entryMap := map[string]bool{prefix + "inactive_anon": true, prefix + "active_anon": true, prefix + "unevictable": true}
 	memStats := strings.Split(string(rawStats), "\n")
 	for _, stat := range memStats {
 		fieldStat := strings.Fields(stat)
@@ -153,7 +240,7 @@ func readCgroupMemStat(memStatPath string) (int64, error) {
 			entryMap[fieldStat[0]] = false
 		}
 	}
-	if entryMap["total_inactive_anon"] || entryMap["total_active_anon"] || entryMap["total_unevictable"] {
+	if entryMap[prefix+"inactive_anon"] || entryMap[prefix+"active_anon"] || entryMap[prefix+"unevictable"] {
 		return 0, fmt.Errorf("pod memStat %s is illegally formatted", memStats)
 	}
 	return total, nil
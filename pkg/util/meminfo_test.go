@@ -18,6 +18,7 @@ package util
 
 import (
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
@@ -176,6 +177,62 @@ func Test_GetMemInfoUsageKB(t *testing.T) {
 	t.Log("meminfo: ", memInfoUsage)
 }
 
+func Test_GetNUMANodeMemInfos(t *testing.T) {
+	tempDir := t.TempDir()
+	oldSysRootDir := system.Conf.SysRootDir
+	system.Conf.SysRootDir = tempDir
+	defer func() {
+		system.Conf.SysRootDir = oldSysRootDir
+	}()
+	for nodeID, content := range map[string]string{
+		"node0": "Node 0 MemTotal:       32768000 kB\nNode 0 MemFree:        16384000 kB\n",
+		"node1": "Node 1 MemTotal:       16384000 kB\nNode 1 MemFree:         8192000 kB\n",
+	} {
+		nodeDir := filepath.Join(tempDir, NUMANodeSysDir, nodeID)
+		assert.NoError(t, os.MkdirAll(nodeDir, 0755))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(nodeDir, "meminfo"), []byte(content), 0666))
+	}
+	// the other entries in the dir are ignored
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(tempDir, NUMANodeSysDir, "nodeX"), []byte{}, 0666))
+
+	got, err := GetNUMANodeMemInfos()
+	assert.NoError(t, err)
+	assert.Equal(t, map[int32]*MemInfo{
+		0: {MemTotal: 32768000, MemFree: 16384000},
+		1: {MemTotal: 16384000, MemFree: 8192000},
+	}, got)
+}
+
+func Test_GetNUMANodeHugePages(t *testing.T) {
+	tempDir := t.TempDir()
+	oldSysRootDir := system.Conf.SysRootDir
+	system.Conf.SysRootDir = tempDir
+	defer func() {
+		system.Conf.SysRootDir = oldSysRootDir
+	}()
+	writeHugePages := func(nodeID, pageSize, total, free string) {
+		pageDir := filepath.Join(tempDir, NUMANodeSysDir, nodeID, "hugepages", pageSize)
+		assert.NoError(t, os.MkdirAll(pageDir, 0755))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(pageDir, "nr_hugepages"), []byte(total+"\n"), 0666))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(pageDir, "free_hugepages"), []byte(free+"\n"), 0666))
+	}
+	writeHugePages("node0", "hugepages-2048kB", "512", "256")
+	writeHugePages("node0", "hugepages-1048576kB", "4", "4")
+	writeHugePages("node1", "hugepages-2048kB", "0", "0")
+
+	got, err := GetNUMANodeHugePages()
+	assert.NoError(t, err)
+	assert.Equal(t, map[int32][]HugePagesInfo{
+		0: {
+			{PageSizeKB: 1048576, Total: 4, Free: 4},
+			{PageSizeKB: 2048, Total: 512, Free: 256},
+		},
+		1: {
+			{PageSizeKB: 2048, Total: 0, Free: 0},
+		},
+	}, got)
+}
+
 func Test_readPodMemStat(t *testing.T) {
 	tempDir := t.TempDir()
 	tempInvalidPodCgroupDir := filepath.Join(tempDir, "no_cgroup")
@@ -42,7 +42,7 @@ func GetKubeQosRelativePath(qosClass corev1.PodQOSClass) string {
 // @output /sys/fs/cgroup/cpuset/kubepods.slice/kubepods-besteffort.slice
 func GetRootCgroupCPUSetDir(qosClass corev1.PodQOSClass) string {
 	rootCgroupParentDir := GetKubeQosRelativePath(qosClass)
-	return path.Join(system.Conf.CgroupRootDir, system.CgroupCPUSetDir, rootCgroupParentDir)
+	return path.Join(system.GetCgroupSubsystemDir(system.CgroupCPUSetDir), rootCgroupParentDir)
 }
 
 // GetRootCgroupCurCPUSet gets the current cpuset of the specified podQos' root cgroup
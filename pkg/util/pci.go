@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/koordinator-sh/koordinator/pkg/util/system"
+)
+
+const (
+	// PCIDeviceSysDir is the dir of the PCI devices relative to the sys root dir
+	PCIDeviceSysDir = "bus/pci/devices"
+	// InfinibandSysDir is the dir of the RDMA devices relative to the sys root dir
+	InfinibandSysDir = "class/infiniband"
+)
+
+// pciHostBridgePattern matches the host bridge in the sysfs path of the PCI device, e.g. pci0000:3a
+var pciHostBridgePattern = regexp.MustCompile(`^pci[0-9a-f]{4}:[0-9a-f]{2}$`)
+
+type PCIDeviceInfo struct {
+	// BusID is the PCI bus address of the device, e.g. 0000:3b:00.0
+	BusID string
+	// NUMANode is the NUMA Node the device is attached to, -1 if unknown
+	NUMANode int32
+	// PCIERoot is the PCIe root port the device is attached to, the devices under the same PCIe switch share it
+	PCIERoot string
+}
+
+type RDMADeviceInfo struct {
+	// Name is the name of the RDMA device, e.g. mlx5_0
+	Name string
+	PCIDeviceInfo
+}
+
+// NormalizePCIBusID converts the PCI bus address to the format of the sysfs, e.g. 00000000:3B:00.0 to 0000:3b:00.0.
+func NormalizePCIBusID(busID string) string {
+	busID = strings.ToLower(strings.TrimSpace(busID))
+	parts := strings.SplitN(busID, ":", 2)
+	if len(parts) == 2 && len(parts[0]) > 4 {
+		busID = parts[0][len(parts[0])-4:] + ":" + parts[1]
+	}
+	return busID
+}
+
+// GetPCIDeviceInfo returns the attachment of the PCI device with the bus address in the sysfs format.
+func GetPCIDeviceInfo(busID string) (*PCIDeviceInfo, error) {
+	return getPCIDeviceInfo(filepath.Join(system.Conf.SysRootDir, PCIDeviceSysDir, busID))
+}
+
+// GetRDMADevices returns the RDMA devices on the node sorted by the name.
+func GetRDMADevices() ([]RDMADeviceInfo, error) {
+	deviceDirs, err := filepath.Glob(filepath.Join(system.Conf.SysRootDir, InfinibandSysDir, "*"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(deviceDirs)
+	var devices []RDMADeviceInfo
+	for _, deviceDir := range deviceDirs {
+		info, err := getPCIDeviceInfo(filepath.Join(deviceDir, "device"))
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, RDMADeviceInfo{
+			Name:          filepath.Base(deviceDir),
+			PCIDeviceInfo: *info,
+		})
+	}
+	return devices, nil
+}
+
+func getPCIDeviceInfo(devicePath string) (*PCIDeviceInfo, error) {
+	// the path links to the device under the host bridge, e.g. /sys/devices/pci0000:3a/0000:3a:00.0/0000:3b:00.0
+	realPath, err := filepath.EvalSymlinks(devicePath)
+	if err != nil {
+		return nil, err
+	}
+	info := &PCIDeviceInfo{
+		BusID:    filepath.Base(realPath),
+		NUMANode: -1,
+	}
+	if data, err := ioutil.ReadFile(filepath.Join(realPath, "numa_node")); err == nil {
+		if nodeID, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 32); err == nil && nodeID >= 0 {
+			info.NUMANode = int32(nodeID)
+		}
+	}
+	parts := strings.Split(filepath.ToSlash(realPath), "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if pciHostBridgePattern.MatchString(parts[i]) {
+			info.PCIERoot = parts[i+1]
+			break
+		}
+	}
+	return info, nil
+}
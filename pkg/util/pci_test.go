@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/pkg/util/system"
+)
+
+func Test_NormalizePCIBusID(t *testing.T) {
+	assert.Equal(t, "0000:3b:00.0", NormalizePCIBusID("00000000:3B:00.0"))
+	assert.Equal(t, "0000:3b:00.0", NormalizePCIBusID("0000:3b:00.0"))
+}
+
+func Test_GetRDMADevices(t *testing.T) {
+	tempDir := t.TempDir()
+	oldSysRootDir := system.Conf.SysRootDir
+	system.Conf.SysRootDir = tempDir
+	defer func() {
+		system.Conf.SysRootDir = oldSysRootDir
+	}()
+	addDevice := func(path, numaNode, busID, rdmaName string) {
+		deviceDir := filepath.Join(tempDir, "devices", path)
+		assert.NoError(t, os.MkdirAll(deviceDir, 0755))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(deviceDir, "numa_node"), []byte(numaNode+"\n"), 0666))
+		assert.NoError(t, os.MkdirAll(filepath.Join(tempDir, PCIDeviceSysDir), 0755))
+		assert.NoError(t, os.Symlink(deviceDir, filepath.Join(tempDir, PCIDeviceSysDir, busID)))
+		if rdmaName != "" {
+			rdmaDir := filepath.Join(tempDir, InfinibandSysDir, rdmaName)
+			assert.NoError(t, os.MkdirAll(rdmaDir, 0755))
+			assert.NoError(t, os.Symlink(deviceDir, filepath.Join(rdmaDir, "device")))
+		}
+	}
+	addDevice("pci0000:3a/0000:3a:00.0/0000:3b:00.0/0000:3c:00.0/0000:3d:00.0", "0", "0000:3d:00.0", "mlx5_1")
+	addDevice("pci0000:3a/0000:3a:00.0/0000:3b:00.0/0000:3c:04.0/0000:3e:00.0", "0", "0000:3e:00.0", "")
+	addDevice("pci0000:d7/0000:d7:00.0/0000:d8:00.0", "-1", "0000:d8:00.0", "mlx5_0")
+
+	gpu, err := GetPCIDeviceInfo("0000:3e:00.0")
+	assert.NoError(t, err)
+	assert.Equal(t, &PCIDeviceInfo{BusID: "0000:3e:00.0", NUMANode: 0, PCIERoot: "0000:3a:00.0"}, gpu)
+
+	got, err := GetRDMADevices()
+	assert.NoError(t, err)
+	assert.Equal(t, []RDMADeviceInfo{
+		{Name: "mlx5_0", PCIDeviceInfo: PCIDeviceInfo{BusID: "0000:d8:00.0", NUMANode: -1, PCIERoot: "0000:d7:00.0"}},
+		{Name: "mlx5_1", PCIDeviceInfo: PCIDeviceInfo{BusID: "0000:3d:00.0", NUMANode: 0, PCIERoot: "0000:3a:00.0"}},
+	}, got)
+}
@@ -18,9 +18,7 @@ package util
 
 import (
 	"fmt"
-	"io/ioutil"
 	"path"
-	"strconv"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -101,6 +99,14 @@ func GetKubeQosClass(pod *corev1.Pod) corev1.PodQOSClass {
 	return qosClass
 }
 
+// GetPodRuntimeClassName returns the RuntimeClassName of the pod, empty if the pod runs with the default runtime.
+func GetPodRuntimeClassName(pod *corev1.Pod) string {
+	if pod.Spec.RuntimeClassName == nil {
+		return ""
+	}
+	return *pod.Spec.RuntimeClassName
+}
+
 func GetKubeQoSByCgroupParent(cgroupDir string) corev1.PodQOSClass {
 	if strings.Contains(cgroupDir, "besteffort") {
 		return corev1.PodQOSBestEffort
@@ -193,39 +199,28 @@ func GetPodBEMemoryByteLimit(pod *corev1.Pod) int64 {
 }
 
 func GetPodCurCPUShare(podParentDir string) (int64, error) {
-	cgroupPath := GetPodCgroupCPUSharePath(podParentDir)
-	rawContent, err := ioutil.ReadFile(cgroupPath)
-	if err != nil {
-		return 0, err
-	}
-	return strconv.ParseInt(strings.TrimSpace(string(rawContent)), 10, 64)
+	return readCgroupInt(GetPodCgroupDirWithKube(podParentDir), system.CPUShares)
 }
 
 func GetPodCurCFSPeriod(podParentDir string) (int64, error) {
-	cgroupPath := GetPodCgroupCFSPeriodPath(podParentDir)
-	rawContent, err := ioutil.ReadFile(cgroupPath)
-	if err != nil {
-		return 0, err
-	}
-	return strconv.ParseInt(strings.TrimSpace(string(rawContent)), 10, 64)
+	return readCgroupInt(GetPodCgroupDirWithKube(podParentDir), system.CPUCFSPeriod)
 }
 
 func GetPodCurCFSQuota(podParentDir string) (int64, error) {
-	cgroupPath := GetPodCgroupCFSQuotaPath(podParentDir)
-	rawContent, err := ioutil.ReadFile(cgroupPath)
-	if err != nil {
-		return 0, err
-	}
-	return strconv.ParseInt(strings.TrimSpace(string(rawContent)), 10, 64)
+	return readCgroupInt(GetPodCgroupDirWithKube(podParentDir), system.CPUCFSQuota)
 }
 
 func GetPodCurMemLimitBytes(podParentDir string) (int64, error) {
-	cgroupPath := GetPodCgroupMemLimitPath(podParentDir)
-	rawContent, err := ioutil.ReadFile(cgroupPath)
+	return readCgroupInt(GetPodCgroupDirWithKube(podParentDir), system.MemoryLimit)
+}
+
+// readCgroupInt reads the cgroup file in the format of cgroup v1, which is converted on the cgroup v2.
+func readCgroupInt(cgroupDir string, file system.CgroupFile) (int64, error) {
+	value, err := system.CgroupFileReadInt(cgroupDir, file)
 	if err != nil {
 		return 0, err
 	}
-	return strconv.ParseInt(strings.TrimSpace(string(rawContent)), 10, 64)
+	return *value, nil
 }
 
 // @return like kubepods.slice/kubepods-burstable.slice/
@@ -99,9 +99,31 @@ func ValidateReservation(r *schedulingv1alpha1.Reservation) error {
 	if r.Spec.TTL == nil && r.Spec.Expires == nil {
 		return fmt.Errorf("the reservation misses the expiration spec")
 	}
+	switch r.Spec.ExpirationPolicy {
+	case "", schedulingv1alpha1.ReservationExpirationPolicyRelease:
+	case schedulingv1alpha1.ReservationExpirationPolicyRenew:
+		if r.Spec.TTL == nil || r.Spec.Expires != nil {
+			return fmt.Errorf("the reservation with the Renew expiration policy must specify the ttl instead of expires")
+		}
+	case schedulingv1alpha1.ReservationExpirationPolicyConsumed:
+		if r.Spec.MaxConsumptions == nil || *r.Spec.MaxConsumptions <= 0 {
+			return fmt.Errorf("the reservation with the Consumed expiration policy must specify a positive maxConsumptions")
+		}
+	default:
+		return fmt.Errorf("the reservation has an unknown expiration policy %s", r.Spec.ExpirationPolicy)
+	}
+	if r.Spec.Replicas != nil && *r.Spec.Replicas <= 0 {
+		return fmt.Errorf("the reservation must specify positive replicas, got %d", *r.Spec.Replicas)
+	}
 	return nil
 }
 
+// IsBulkReservation checks if the reservation creates the child reservations for its replicas instead of being
+// scheduled itself.
+func IsBulkReservation(r *schedulingv1alpha1.Reservation) bool {
+	return r != nil && r.Spec.Replicas != nil
+}
+
 func IsReservePod(pod *corev1.Pod) bool {
 	return pod != nil && pod.Annotations != nil && pod.Annotations[AnnotationReservePod] == "true"
 }
@@ -19,6 +19,8 @@ package util
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -65,6 +67,60 @@ func GetCPUStatUsageTicks() (uint64, error) {
 	return readTotalCPUStat(system.ProcStatFile.File)
 }
 
+func readProcessCPUStat(statPath string) (uint64, error) {
+	rawStat, err := ioutil.ReadFile(statPath)
+	if err != nil {
+		return 0, err
+	}
+	// the comm of the process may contain spaces, so parse the fields after the last ')'
+	stat := string(rawStat)
+	commEnd := strings.LastIndex(stat, ")")
+	if commEnd < 0 {
+		return 0, fmt.Errorf("%s is illegally formatted", statPath)
+	}
+	// format: $state $ppid ... $utime $stime, the utime and stime are the 14th and 15th fields
+	fieldStat := strings.Fields(stat[commEnd+1:])
+	if len(fieldStat) <= 12 {
+		return 0, fmt.Errorf("%s is illegally formatted", statPath)
+	}
+	var total uint64 = 0
+	for _, i := range []int{11, 12} {
+		v, err := strconv.ParseUint(fieldStat[i], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse process stat %s, err: %s", statPath, err)
+		}
+		total += v
+	}
+	return total, nil
+}
+
+// GetSelfCPUUsageTicks returns the CPU usage ticks of the current process, i.e. $utime + $stime
+func GetSelfCPUUsageTicks() (uint64, error) {
+	return readProcessCPUStat(filepath.Join(system.Conf.ProcRootDir, "self", "stat"))
+}
+
+func readProcessRSSBytes(statmPath string) (uint64, error) {
+	rawStatm, err := ioutil.ReadFile(statmPath)
+	if err != nil {
+		return 0, err
+	}
+	// format: $size $resident $shared ..., in pages
+	fieldStatm := strings.Fields(string(rawStatm))
+	if len(fieldStatm) < 2 {
+		return 0, fmt.Errorf("%s is illegally formatted", statmPath)
+	}
+	pages, err := strconv.ParseUint(fieldStatm[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse process statm %s, err: %s", statmPath, err)
+	}
+	return pages * uint64(os.Getpagesize()), nil
+}
+
+// GetSelfMemoryRSSBytes returns the resident set size of the current process in bytes
+func GetSelfMemoryRSSBytes() (uint64, error) {
+	return readProcessRSSBytes(filepath.Join(system.Conf.ProcRootDir, "self", "statm"))
+}
+
 func readCPUAcctUsage(usagePath string) (uint64, error) {
 	v, err := ioutil.ReadFile(usagePath)
 	if err != nil {
@@ -73,6 +129,10 @@ func readCPUAcctUsage(usagePath string) (uint64, error) {
 
 	r, err1 := strconv.ParseUint(strings.TrimSpace(string(v)), 10, 64)
 	if err1 != nil {
+		// the usage is in cpu.stat on the cgroup v2
+		if system.IsCgroupV2() {
+			return system.ParseCPUStatUsageNanoseconds(string(v))
+		}
 		return 0, err1
 	}
 	return r, nil
@@ -95,6 +95,28 @@ func Test_GetCPUStatUsageTicks(t *testing.T) {
 	t.Log("get cpu stat usage ticks ", cpuStatUsage)
 }
 
+func Test_readProcessStat(t *testing.T) {
+	tempDir := t.TempDir()
+	tempStatPath := filepath.Join(tempDir, "stat")
+	statContentStr := "12345 (koord let) S 1 12345 12345 0 -1 4194560 150000 0 20 0 3000 1500 0 0 20 0 30 0 " +
+		"1034 2000000000 20000 18446744073709551615 1 1 0 0 0 0 0 0 2143420159 0 0 0 17 3 0 0 0 0 0\n"
+	assert.NoError(t, ioutil.WriteFile(tempStatPath, []byte(statContentStr), 0666))
+	tempStatmPath := filepath.Join(tempDir, "statm")
+	assert.NoError(t, ioutil.WriteFile(tempStatmPath, []byte("488282 20000 6000 5000 0 100000 0\n"), 0666))
+
+	ticks, err := readProcessCPUStat(tempStatPath)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4500), ticks)
+	_, err = readProcessCPUStat(filepath.Join(tempDir, "no_stat"))
+	assert.Error(t, err)
+
+	rss, err := readProcessRSSBytes(tempStatmPath)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(20000*os.Getpagesize()), rss)
+	_, err = readProcessRSSBytes(filepath.Join(tempDir, "no_statm"))
+	assert.Error(t, err)
+}
+
 func Test_readPodCPUUsage(t *testing.T) {
 	tempDir := t.TempDir()
 	tempInvalidPodCgroupDir := filepath.Join(tempDir, "no_cgroup")
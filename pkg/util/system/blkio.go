@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	ProcMountInfoFileName = "mountinfo"
+
+	// SysDevBlockDir contains the links of the block devices named by major:minor, e.g. /sys/dev/block/8:0
+	SysDevBlockDir = "dev/block"
+)
+
+// GetProcessBlockDevices returns the disks of the filesystems mounted in the mount namespace of the process, e.g.
+// the devices of the volumes and the rootfs of a container, in the format of major:minor. The partitions are
+// converted into their disks since the blkio throttling only accepts the disks, and the virtual filesystems,
+// e.g. tmpfs, overlay and nfs, are ignored.
+func GetProcessBlockDevices(pid uint32) ([]string, error) {
+	mountInfoFile := path.Join(Conf.ProcRootDir, strconv.FormatUint(uint64(pid), 10), ProcMountInfoFileName)
+	f, err := os.Open(mountInfoFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	devices := map[string]struct{}{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// 36 35 8:1 /var/lib/kubelet/pods/xxx/volumes/kubernetes.io~empty-dir/data /data rw,relatime - ext4 /dev/sda1 rw
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || strings.HasPrefix(fields[2], "0:") {
+			continue
+		}
+		if disk, ok := getBlockDiskDevice(fields[2]); ok {
+			devices[disk] = struct{}{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(devices))
+	for device := range devices {
+		result = append(result, device)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// getBlockDiskDevice returns the disk of the block device. /sys/dev/block/8:1 links to .../block/sda/sda1 for a
+// partition, whose parent dir is the disk.
+func getBlockDiskDevice(device string) (string, bool) {
+	devicePath, err := filepath.EvalSymlinks(path.Join(Conf.SysRootDir, SysDevBlockDir, device))
+	if err != nil {
+		return "", false
+	}
+	if !FileExists(path.Join(devicePath, "partition")) {
+		return device, true
+	}
+	content, err := ioutil.ReadFile(path.Join(path.Dir(devicePath), "dev"))
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(content)), true
+}
+
+// BlkioDeviceValue returns the value of the blkio throttle file for the device, e.g. "8:0 1000".
+func BlkioDeviceValue(device string, value int64) string {
+	return fmt.Sprintf("%s %d", device, value)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetProcessBlockDevices(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+	oldSysRootDir := Conf.SysRootDir
+	defer func() {
+		Conf.SysRootDir = oldSysRootDir
+	}()
+	Conf.SysRootDir = path.Join(helper.TempDir, "sys")
+
+	// sda with the partition sda1, and the lvm dm-0
+	helper.WriteFileContents("sys/devices/pci0000:00/block/sda/dev", "8:0\n")
+	helper.WriteFileContents("sys/devices/pci0000:00/block/sda/sda1/dev", "8:1\n")
+	helper.WriteFileContents("sys/devices/pci0000:00/block/sda/sda1/partition", "1\n")
+	helper.WriteFileContents("sys/devices/virtual/block/dm-0/dev", "253:0\n")
+	helper.MkDirAll("sys/dev/block")
+	for device, target := range map[string]string{
+		"8:0":   "../../devices/pci0000:00/block/sda",
+		"8:1":   "../../devices/pci0000:00/block/sda/sda1",
+		"253:0": "../../devices/virtual/block/dm-0",
+	} {
+		assert.NoError(t, os.Symlink(target, path.Join(Conf.SysRootDir, SysDevBlockDir, device)))
+	}
+
+	_, err := GetProcessBlockDevices(100)
+	assert.Error(t, err)
+
+	helper.WriteProcSubFileContents("100/mountinfo", `1000 900 0:45 / / rw,relatime - overlay overlay rw
+1001 1000 0:50 / /proc rw,nosuid - proc proc rw
+1002 1000 8:1 /var/lib/kubelet/pods/uid/volumes/kubernetes.io~empty-dir/data /data rw,relatime - ext4 /dev/sda1 rw
+1003 1000 8:1 /var/lib/kubelet/pods/uid/etc-hosts /etc/hosts rw,relatime - ext4 /dev/sda1 rw
+1004 1000 253:0 /pv-1 /mnt/pv rw,relatime - xfs /dev/mapper/vg-pv rw
+1005 1000 9:9 / /mnt/unknown rw,relatime - ext4 /dev/unknown rw
+`)
+	devices, err := GetProcessBlockDevices(100)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"253:0", "8:0"}, devices)
+}
+
+func TestBlkioDeviceValue(t *testing.T) {
+	assert.Equal(t, "8:0 1000", BlkioDeviceValue("8:0", 1000))
+}
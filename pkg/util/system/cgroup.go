@@ -61,10 +61,6 @@ func CgroupFileWriteIfDifferent(cgroupTaskDir string, file CgroupFile, value str
 }
 
 func CgroupFileReadInt(cgroupTaskDir string, file CgroupFile) (*int64, error) {
-	if file.IsAnolisOS && !HostSystemInfo.IsAnolisOS {
-		return nil, fmt.Errorf("read cgroup config : %s fail, need anolis kernel", file.ResourceFileName)
-	}
-
 	dataStr, err := CgroupFileRead(cgroupTaskDir, file)
 	if err != nil {
 		return nil, err
@@ -87,31 +83,54 @@ func CgroupFileReadInt(cgroupTaskDir string, file CgroupFile) (*int64, error) {
 }
 
 func CgroupFileRead(cgroupTaskDir string, file CgroupFile) (string, error) {
-	if file.IsAnolisOS && !HostSystemInfo.IsAnolisOS {
-		return "", fmt.Errorf("read cgroup config : %s fail, need anolis kernel", file.ResourceFileName)
+	if err := checkCgroupFileSupported(file, "read"); err != nil {
+		return "", err
 	}
 
 	klog.V(5).Infof("read %s,%s", cgroupTaskDir, file.ResourceFileName)
 	filePath := GetCgroupFilePath(cgroupTaskDir, file)
 
 	data, err := ioutil.ReadFile(filePath)
-	return strings.Trim(string(data), "\n"), err
+	content := strings.Trim(string(data), "\n")
+	if err != nil || !IsCgroupV2() {
+		return content, err
+	}
+	if v2File := getCgroupV2File(file); v2File.read != nil {
+		return v2File.read(content)
+	}
+	return content, nil
 }
 
 func CgroupFileWrite(cgroupTaskDir string, file CgroupFile, data string) error {
-	if file.IsAnolisOS && !HostSystemInfo.IsAnolisOS {
-		return fmt.Errorf("write cgroup config : %v [%s] fail, need anolis kernel", file.ResourceFileName, data)
+	if err := checkCgroupFileSupported(file, "write"); err != nil {
+		return fmt.Errorf("%v [%s]", err, data)
 	}
 
 	klog.V(5).Infof("write %s,%s [%s]", cgroupTaskDir, file.ResourceFileName, data)
 	filePath := GetCgroupFilePath(cgroupTaskDir, file)
 
+	if IsCgroupV2() {
+		if v2File := getCgroupV2File(file); v2File.write != nil {
+			current, err := ioutil.ReadFile(filePath)
+			if err != nil {
+				return err
+			}
+			if data, err = v2File.write(data, strings.TrimSpace(string(current))); err != nil {
+				return err
+			}
+		}
+	}
 	return ioutil.WriteFile(filePath, []byte(data), 0644)
 }
 
 // @cgroupTaskDir kubepods.slice/kubepods-pod7712555c_ce62_454a_9e18_9ff0217b8941.slice/
 // @return /sys/fs/cgroup/cpu/kubepods.slice/kubepods-pod7712555c_ce62_454a_9e18_9ff0217b8941.slice/cpu.shares
+// on the cgroup v2, it returns the v2 file in the unified hierarchy, e.g.
+// /sys/fs/cgroup/kubepods.slice/kubepods-pod7712555c_ce62_454a_9e18_9ff0217b8941.slice/cpu.weight
 func GetCgroupFilePath(cgroupTaskDir string, file CgroupFile) string {
+	if IsCgroupV2() {
+		return path.Join(Conf.CgroupRootDir, cgroupTaskDir, getCgroupV2File(file).ResourceFileName)
+	}
 	return path.Join(Conf.CgroupRootDir, file.Subfs, cgroupTaskDir, file.ResourceFileName)
 }
 
@@ -171,6 +190,15 @@ func GetCPUStatRaw(cgroupPath string) (*CPUStatRaw, error) {
 					cgroupPath, content, err)
 			}
 			counter++
+		case "throttled_usec":
+			// cgroup v2
+			throttledMicroSeconds, err := strconv.ParseInt(val, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("parse throttled_usec field failed, path %s, raw content %s, err: %v",
+					cgroupPath, content, err)
+			}
+			cpuThrottledRaw.ThrottledNanoSeconds = throttledMicroSeconds * 1000
+			counter++
 		}
 	}
 
@@ -37,8 +37,8 @@ const (
 )
 
 func GuessCgroupDriverFromCgroupName() CgroupDriverType {
-	systemdKubepodDirExists := FileExists(filepath.Join(Conf.CgroupRootDir, "cpu", KubeRootNameSystemd))
-	cgroupfsKubepodDirExists := FileExists(filepath.Join(Conf.CgroupRootDir, "cpu", KubeRootNameCgroupfs))
+	systemdKubepodDirExists := FileExists(filepath.Join(GetCgroupSubsystemDir(CgroupCPUDir), KubeRootNameSystemd))
+	cgroupfsKubepodDirExists := FileExists(filepath.Join(GetCgroupSubsystemDir(CgroupCPUDir), KubeRootNameCgroupfs))
 	if systemdKubepodDirExists != cgroupfsKubepodDirExists {
 		if systemdKubepodDirExists {
 			return Systemd
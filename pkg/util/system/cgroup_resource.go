@@ -28,6 +28,7 @@ const ( // subsystems
 	CgroupCPUacctDir string = "cpuacct/"
 	CgroupMemDir     string = "memory/"
 	CgroupBlkioDir   string = "blkio/"
+	CgroupNetClsDir  string = "net_cls/"
 )
 
 const (
@@ -40,6 +41,7 @@ const (
 	CPUBVTWarpNsName  = "cpu.bvt_warp_ns"
 	CPUBurstName      = "cpu.cfs_burst_us"
 	CPUSFileName      = "cpuset.cpus"
+	CPUSetMemsName    = "cpuset.mems"
 	CPUTaskFileName   = "tasks"
 
 	CpuacctUsageFileName = "cpuacct.usage"
@@ -61,6 +63,9 @@ const (
 	BlkioTRBpsFileName  = "blkio.throttle.read_bps_device"
 	BlkioTWIopsFileName = "blkio.throttle.write_iops_device"
 	BlkioTWBpsFileName  = "blkio.throttle.write_bps_device"
+	BlkioWeightFileName = "blkio.weight"
+
+	NetClsClassIDFileName = "net_cls.classid"
 
 	ProcsFileName = "cgroup.procs"
 )
@@ -82,6 +87,7 @@ var (
 	BlkioReadBpsValidator   = &RangeValidator{name: BlkioTRBpsFileName, min: 0, max: math.MaxInt64}
 	BlkioWriteIopsValidator = &RangeValidator{name: BlkioTWIopsFileName, min: 0, max: math.MaxInt64}
 	BlkioWriteBpsValidator  = &RangeValidator{name: BlkioTWBpsFileName, min: 0, max: math.MaxInt64}
+	BlkioWeightValidator    = &RangeValidator{name: BlkioWeightFileName, min: 10, max: 1000}
 )
 
 var (
@@ -93,10 +99,15 @@ var (
 	CPUBurst     = CgroupFile{ResourceFileName: CPUBurstName, Subfs: CgroupCPUDir, IsAnolisOS: true, Validator: CPUBurstValidator}
 	CPUBVTWarpNs = CgroupFile{ResourceFileName: CPUBVTWarpNsName, Subfs: CgroupCPUDir, IsAnolisOS: true, Validator: CPUBvtWarpNsValidator}
 
-	CPUSet = CgroupFile{ResourceFileName: CPUSFileName, Subfs: CgroupCPUSetDir, IsAnolisOS: false}
+	CPUSet     = CgroupFile{ResourceFileName: CPUSFileName, Subfs: CgroupCPUSetDir, IsAnolisOS: false}
+	CPUSetMems = CgroupFile{ResourceFileName: CPUSetMemsName, Subfs: CgroupCPUSetDir, IsAnolisOS: false}
 
 	CpuacctUsage = CgroupFile{ResourceFileName: CpuacctUsageFileName, Subfs: CgroupCPUacctDir, IsAnolisOS: false}
 
+	CPUAcctCPUPressure    = CgroupFile{ResourceFileName: CPUPressureFileName, Subfs: CgroupCPUacctDir, IsAnolisOS: true}
+	CPUAcctMemoryPressure = CgroupFile{ResourceFileName: MemoryPressureFileName, Subfs: CgroupCPUacctDir, IsAnolisOS: true}
+	CPUAcctIOPressure     = CgroupFile{ResourceFileName: IOPressureFileName, Subfs: CgroupCPUacctDir, IsAnolisOS: true}
+
 	MemStat             = CgroupFile{ResourceFileName: MemStatFileName, Subfs: CgroupMemDir, IsAnolisOS: false}
 	MemorySWLimit       = CgroupFile{ResourceFileName: MemorySWLimitFileName, Subfs: CgroupMemDir, IsAnolisOS: false}
 	MemoryLimit         = CgroupFile{ResourceFileName: MemoryLimitFileName, Subfs: CgroupMemDir, IsAnolisOS: false}
@@ -114,6 +125,9 @@ var (
 	BlkioReadBps   = CgroupFile{ResourceFileName: BlkioTRBpsFileName, Subfs: CgroupBlkioDir, IsAnolisOS: false, Validator: BlkioReadBpsValidator}
 	BlkioWriteIops = CgroupFile{ResourceFileName: BlkioTWIopsFileName, Subfs: CgroupBlkioDir, IsAnolisOS: false, Validator: BlkioWriteIopsValidator}
 	BlkioWriteBps  = CgroupFile{ResourceFileName: BlkioTWBpsFileName, Subfs: CgroupBlkioDir, IsAnolisOS: false, Validator: BlkioWriteBpsValidator}
+	BlkioWeight    = CgroupFile{ResourceFileName: BlkioWeightFileName, Subfs: CgroupBlkioDir, IsAnolisOS: false, Validator: BlkioWeightValidator}
+
+	NetClsClassID = CgroupFile{ResourceFileName: NetClsClassIDFileName, Subfs: CgroupNetClsDir, IsAnolisOS: false}
 
 	CPUProcs = CgroupFile{ResourceFileName: ProcsFileName, Subfs: CgroupCPUDir, IsAnolisOS: false}
 )
@@ -0,0 +1,343 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	// CgroupV2ControllersFileName only exists in the root of the cgroup v2 (unified hierarchy).
+	CgroupV2ControllersFileName = "cgroup.controllers"
+
+	CPUWeightFileName   = "cpu.weight"
+	CPUMaxFileName      = "cpu.max"
+	CPUMaxBurstFileName = "cpu.max.burst"
+	CPUThreadsFileName  = "cgroup.threads"
+	MemMaxFileName      = "memory.max"
+	IOMaxFileName       = "io.max"
+	IOWeightFileName    = "io.weight"
+
+	cpuSharesMin = 2
+	cpuSharesMax = 262144
+	cpuWeightMin = 1
+	cpuWeightMax = 10000
+
+	blkioWeightMin = 10
+	blkioWeightMax = 1000
+	ioWeightMin    = 1
+	ioWeightMax    = 10000
+)
+
+// cgroupV2File describes how a cgroup v1 file is accessed on the cgroup v2. The callers always read and write
+// the values in the format of cgroup v1, which are converted from/to the content of the v2 file.
+type cgroupV2File struct {
+	ResourceFileName string
+	IsAnolisOS       bool
+	// unsupported means the v1 file has no equivalent on the cgroup v2
+	unsupported bool
+	// read converts the content of the v2 file into the v1 value, the content is returned as is if nil.
+	read func(content string) (string, error)
+	// write converts the v1 value into the content of the v2 file, current is the current content of the v2 file
+	// for the v2 files shared by multiple v1 files. The value is written as is if nil.
+	write func(value, current string) (string, error)
+}
+
+// cgroupV2Files are the v1 files whose name or format are different on the cgroup v2, keyed by the v1 file name.
+// The other files keep the same names and formats, e.g. cpuset.cpus and cgroup.procs.
+var cgroupV2Files = map[string]cgroupV2File{
+	CPUSharesFileName: {
+		ResourceFileName: CPUWeightFileName,
+		read:             convertCPUWeightToShares,
+		write: func(value, _ string) (string, error) {
+			return convertCPUSharesToWeight(value)
+		},
+	},
+	CPUCFSQuotaName: {
+		ResourceFileName: CPUMaxFileName,
+		read: func(content string) (string, error) {
+			quota, _, err := parseCPUMax(content)
+			return quota, err
+		},
+		write: func(value, current string) (string, error) {
+			_, period, err := parseCPUMax(current)
+			if err != nil {
+				return "", err
+			}
+			quota := strings.TrimSpace(value)
+			if quota == strconv.FormatInt(CFSQuotaUnlimitedValue, 10) {
+				quota = CgroupMaxSymbolStr
+			}
+			return quota + " " + period, nil
+		},
+	},
+	CPUCFSPeriodName: {
+		ResourceFileName: CPUMaxFileName,
+		read: func(content string) (string, error) {
+			_, period, err := parseCPUMax(content)
+			return period, err
+		},
+		write: func(value, current string) (string, error) {
+			quota, _, err := parseCPUMax(current)
+			if err != nil {
+				return "", err
+			}
+			if quota == strconv.FormatInt(CFSQuotaUnlimitedValue, 10) {
+				quota = CgroupMaxSymbolStr
+			}
+			return quota + " " + strings.TrimSpace(value), nil
+		},
+	},
+	CPUBurstName: {
+		// cpu.max.burst is supported by the upstream kernel since 5.14
+		ResourceFileName: CPUMaxBurstFileName,
+	},
+	CPUTaskFileName: {
+		ResourceFileName: CPUThreadsFileName,
+	},
+	CpuacctUsageFileName: {
+		ResourceFileName: CPUStatFileName,
+		read: func(content string) (string, error) {
+			usage, err := ParseCPUStatUsageNanoseconds(content)
+			if err != nil {
+				return "", err
+			}
+			return strconv.FormatUint(usage, 10), nil
+		},
+	},
+	MemoryLimitFileName: {
+		ResourceFileName: MemMaxFileName,
+		write: func(value, _ string) (string, error) {
+			if strings.TrimSpace(value) == "-1" {
+				return CgroupMaxSymbolStr, nil
+			}
+			return value, nil
+		},
+	},
+	MemorySWLimitFileName: {
+		// memory.swap.max only limits the swap, which is different from memory.memsw.limit_in_bytes
+		ResourceFileName: MemorySWLimitFileName,
+		unsupported:      true,
+	},
+	// memory.min, memory.low, memory.high and memory.oom.group are native on the cgroup v2
+	MemMinFileName:      {ResourceFileName: MemMinFileName},
+	MemLowFileName:      {ResourceFileName: MemLowFileName},
+	MemHighFileName:     {ResourceFileName: MemHighFileName},
+	MemOomGroupFileName: {ResourceFileName: MemOomGroupFileName},
+	BlkioTRIopsFileName: newIOMaxFile("riops"),
+	BlkioTRBpsFileName:  newIOMaxFile("rbps"),
+	BlkioTWIopsFileName: newIOMaxFile("wiops"),
+	BlkioTWBpsFileName:  newIOMaxFile("wbps"),
+	BlkioWeightFileName: {
+		ResourceFileName: IOWeightFileName,
+		read:             convertIOWeightToBlkioWeight,
+		write: func(value, _ string) (string, error) {
+			return convertBlkioWeightToIOWeight(value)
+		},
+	},
+	// net_cls is not available on the cgroup v2, the traffic should be classified by the ebpf instead
+	NetClsClassIDFileName: {ResourceFileName: NetClsClassIDFileName, unsupported: true},
+
+	// the pressure files are native on the cgroup v2, while they are in cpuacct of the anolis kernel on the cgroup v1
+	CPUPressureFileName:    {ResourceFileName: CPUPressureFileName},
+	MemoryPressureFileName: {ResourceFileName: MemoryPressureFileName},
+	IOPressureFileName:     {ResourceFileName: IOPressureFileName},
+}
+
+// IsCgroupV2 returns whether the cgroup v2 (unified hierarchy) is mounted on the cgroup root dir.
+func IsCgroupV2() bool {
+	return HostSystemInfo.IsCgroupV2
+}
+
+func isCgroupV2Mounted() bool {
+	return FileExists(path.Join(Conf.CgroupRootDir, CgroupV2ControllersFileName))
+}
+
+// GetCgroupSubsystemDir returns the root dir of the cgroup subsystem, e.g. /sys/fs/cgroup/cpu/ on the cgroup v1,
+// while all the subsystems share the root dir on the cgroup v2.
+func GetCgroupSubsystemDir(subfs string) string {
+	return getCgroupSubsystemDir(subfs, IsCgroupV2())
+}
+
+func getCgroupSubsystemDir(subfs string, isCgroupV2 bool) string {
+	if isCgroupV2 {
+		return Conf.CgroupRootDir
+	}
+	return path.Join(Conf.CgroupRootDir, subfs)
+}
+
+// getCgroupV2File returns how the cgroup file is accessed on the cgroup v2.
+func getCgroupV2File(file CgroupFile) cgroupV2File {
+	if v2File, ok := cgroupV2Files[file.ResourceFileName]; ok {
+		return v2File
+	}
+	return cgroupV2File{ResourceFileName: file.ResourceFileName, IsAnolisOS: file.IsAnolisOS}
+}
+
+// checkCgroupFileSupported returns an error if the cgroup file is not supported by the host.
+func checkCgroupFileSupported(file CgroupFile, op string) error {
+	needAnolisOS := file.IsAnolisOS
+	if IsCgroupV2() {
+		v2File := getCgroupV2File(file)
+		if v2File.unsupported {
+			return fmt.Errorf("%s cgroup config : %s fail, not supported on cgroup v2", op, file.ResourceFileName)
+		}
+		needAnolisOS = v2File.IsAnolisOS
+	}
+	if needAnolisOS && !HostSystemInfo.IsAnolisOS {
+		return fmt.Errorf("%s cgroup config : %s fail, need anolis kernel", op, file.ResourceFileName)
+	}
+	return nil
+}
+
+// GetCgroupFileKey returns the unique key of the cgroup file of the dir. The v1 files sharing the same v2 file,
+// e.g. cpu.cfs_quota_us and cpu.cfs_period_us sharing cpu.max, have different keys.
+func GetCgroupFileKey(cgroupTaskDir string, file CgroupFile) string {
+	filePath := GetCgroupFilePath(cgroupTaskDir, file)
+	if IsCgroupV2() && getCgroupV2File(file).ResourceFileName != file.ResourceFileName {
+		return filePath + "#" + file.ResourceFileName
+	}
+	return filePath
+}
+
+func convertCPUSharesToWeight(value string) (string, error) {
+	shares, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return "", err
+	}
+	if shares < cpuSharesMin {
+		shares = cpuSharesMin
+	} else if shares > cpuSharesMax {
+		shares = cpuSharesMax
+	}
+	// the same conversion as the container runtimes, which maps [2, 262144] to [1, 10000]
+	weight := cpuWeightMin + ((shares-cpuSharesMin)*(cpuWeightMax-cpuWeightMin))/(cpuSharesMax-cpuSharesMin)
+	return strconv.FormatInt(weight, 10), nil
+}
+
+func convertCPUWeightToShares(content string) (string, error) {
+	weight, err := strconv.ParseInt(strings.TrimSpace(content), 10, 64)
+	if err != nil {
+		return "", err
+	}
+	shares := cpuSharesMin + ((weight-cpuWeightMin)*(cpuSharesMax-cpuSharesMin))/(cpuWeightMax-cpuWeightMin)
+	return strconv.FormatInt(shares, 10), nil
+}
+
+// parseCPUMax parses the content of cpu.max, e.g. "max 100000", and returns the quota and the period in the
+// format of cpu.cfs_quota_us and cpu.cfs_period_us.
+func parseCPUMax(content string) (string, string, error) {
+	fields := strings.Fields(content)
+	if len(fields) == 0 || len(fields) > 2 {
+		return "", "", fmt.Errorf("cpu.max %q is illegally formatted", content)
+	}
+	quota := fields[0]
+	if quota == CgroupMaxSymbolStr {
+		quota = strconv.FormatInt(CFSQuotaUnlimitedValue, 10)
+	}
+	period := strconv.FormatInt(CFSBasePeriodValue, 10)
+	if len(fields) == 2 {
+		period = fields[1]
+	}
+	return quota, period, nil
+}
+
+// ParseCPUStatUsageNanoseconds parses the usage_usec of the cpu.stat on the cgroup v2 in nanoseconds.
+func ParseCPUStatUsageNanoseconds(content string) (uint64, error) {
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "usage_usec" {
+			usage, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return 0, err
+			}
+			return usage * 1000, nil
+		}
+	}
+	return 0, fmt.Errorf("usage_usec not found in cpu.stat")
+}
+
+// convertBlkioWeightToIOWeight maps the blkio.weight in [10, 1000] to the io.weight in [1, 10000], which is the
+// same conversion as the container runtimes.
+func convertBlkioWeightToIOWeight(value string) (string, error) {
+	weight, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return "", err
+	}
+	if weight < blkioWeightMin {
+		weight = blkioWeightMin
+	} else if weight > blkioWeightMax {
+		weight = blkioWeightMax
+	}
+	ioWeight := ioWeightMin + ((weight-blkioWeightMin)*(ioWeightMax-ioWeightMin))/(blkioWeightMax-blkioWeightMin)
+	return "default " + strconv.FormatInt(ioWeight, 10), nil
+}
+
+// convertIOWeightToBlkioWeight parses the default weight of the io.weight, e.g. "default 100\n8:0 200", in the
+// format of blkio.weight.
+func convertIOWeightToBlkioWeight(content string) (string, error) {
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != "default" {
+			continue
+		}
+		ioWeight, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return "", err
+		}
+		weight := blkioWeightMin + ((ioWeight-ioWeightMin)*(blkioWeightMax-blkioWeightMin))/(ioWeightMax-ioWeightMin)
+		return strconv.FormatInt(weight, 10), nil
+	}
+	return "", fmt.Errorf("default weight not found in io.weight %q", content)
+}
+
+// newIOMaxFile returns the io.max equivalent of the blkio throttle file. A line of the blkio throttle file is
+// like "8:0 1000", which is "8:0 riops=1000" in io.max, and 0 means no limit.
+func newIOMaxFile(key string) cgroupV2File {
+	return cgroupV2File{
+		ResourceFileName: IOMaxFileName,
+		read: func(content string) (string, error) {
+			var lines []string
+			for _, line := range strings.Split(content, "\n") {
+				fields := strings.Fields(line)
+				if len(fields) == 0 {
+					continue
+				}
+				for _, field := range fields[1:] {
+					if value := strings.TrimPrefix(field, key+"="); value != field && value != CgroupMaxSymbolStr {
+						lines = append(lines, fields[0]+" "+value)
+					}
+				}
+			}
+			return strings.Join(lines, "\n"), nil
+		},
+		write: func(value, _ string) (string, error) {
+			fields := strings.Fields(value)
+			if len(fields) != 2 {
+				return "", fmt.Errorf("blkio value %q is illegally formatted", value)
+			}
+			limit := fields[1]
+			if limit == "0" {
+				limit = CgroupMaxSymbolStr
+			}
+			return fields[0] + " " + key + "=" + limit, nil
+		},
+	}
+}
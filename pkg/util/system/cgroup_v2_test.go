@@ -0,0 +1,144 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCgroupFilePathOnCgroupV2(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	taskDir := "kubepods.slice/kubepods-besteffort.slice"
+	assert.Equal(t, path.Join(helper.TempDir, CgroupCPUDir, taskDir, CPUSharesFileName), GetCgroupFilePath(taskDir, CPUShares))
+	assert.Equal(t, path.Join(helper.TempDir, CgroupCPUDir), GetCgroupSubsystemDir(CgroupCPUDir))
+
+	helper.SetCgroupsV2(true)
+	assert.Equal(t, path.Join(helper.TempDir, taskDir, CPUWeightFileName), GetCgroupFilePath(taskDir, CPUShares))
+	assert.Equal(t, path.Join(helper.TempDir, taskDir, CPUMaxFileName), GetCgroupFilePath(taskDir, CPUCFSQuota))
+	assert.Equal(t, path.Join(helper.TempDir, taskDir, MemHighFileName), GetCgroupFilePath(taskDir, MemHigh))
+	assert.Equal(t, helper.TempDir, GetCgroupSubsystemDir(CgroupCPUDir))
+	assert.NotEqual(t, GetCgroupFileKey(taskDir, CPUCFSQuota), GetCgroupFileKey(taskDir, CPUCFSPeriod))
+}
+
+func TestCgroupFileReadWriteOnCgroupV2(t *testing.T) {
+	taskDir := "kubepods.slice"
+	tests := []struct {
+		name        string
+		file        CgroupFile
+		content     string
+		wantRead    string
+		value       string
+		wantContent string
+		wantErr     bool
+	}{
+		{
+			name:        "cpu.shares is converted from/to cpu.weight",
+			file:        CPUShares,
+			content:     "10000",
+			wantRead:    "262144",
+			value:       "1024",
+			wantContent: "39",
+		},
+		{
+			name:        "cfs quota keeps the period of cpu.max",
+			file:        CPUCFSQuota,
+			content:     "max 100000",
+			wantRead:    "-1",
+			value:       "200000",
+			wantContent: "200000 100000",
+		},
+		{
+			name:        "cfs quota unlimited",
+			file:        CPUCFSQuota,
+			content:     "200000 50000",
+			wantRead:    "200000",
+			value:       "-1",
+			wantContent: "max 50000",
+		},
+		{
+			name:        "cfs period keeps the quota of cpu.max",
+			file:        CPUCFSPeriod,
+			content:     "max 100000",
+			wantRead:    "100000",
+			value:       "50000",
+			wantContent: "max 50000",
+		},
+		{
+			name:        "memory limit is written into memory.max",
+			file:        MemoryLimit,
+			content:     "max",
+			wantRead:    "max",
+			value:       "-1",
+			wantContent: "max",
+		},
+		{
+			name:        "blkio read iops is converted from/to io.max",
+			file:        BlkioReadIops,
+			content:     "253:16 rbps=max wbps=max riops=2048 wiops=max",
+			wantRead:    "253:16 2048",
+			value:       "253:16 0",
+			wantContent: "253:16 riops=max",
+		},
+		{
+			name:        "blkio weight is scaled from/to io.weight",
+			file:        BlkioWeight,
+			content:     "default 100\n253:16 200",
+			wantRead:    "19",
+			value:       "500",
+			wantContent: "default 4950",
+		},
+		{
+			name:    "memsw is not supported",
+			file:    MemorySWLimit,
+			content: "max",
+			value:   "-1",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := NewFileTestUtil(t)
+			defer helper.Cleanup()
+			helper.SetCgroupsV2(true)
+
+			helper.WriteCgroupFileContents(taskDir, tt.file, tt.content)
+			got, err := CgroupFileRead(taskDir, tt.file)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantRead, got)
+
+			err = CgroupFileWrite(taskDir, tt.file, tt.value)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if !tt.wantErr {
+				assert.Equal(t, tt.wantContent, helper.ReadFileContents(path.Join(taskDir, getCgroupV2File(tt.file).ResourceFileName)))
+			}
+		})
+	}
+}
+
+func TestParseCPUStatUsageNanoseconds(t *testing.T) {
+	usage, err := ParseCPUStatUsageNanoseconds("usage_usec 1000\nuser_usec 600\nsystem_usec 400\n")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1000000), usage)
+
+	_, err = ParseCPUStatUsageNanoseconds("nr_periods 0\n")
+	assert.Error(t, err)
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	ProcPressureDir = "pressure"
+
+	CPUPressureFileName    = "cpu.pressure"
+	MemoryPressureFileName = "memory.pressure"
+	IOPressureFileName     = "io.pressure"
+)
+
+// PSILine is a line of the pressure stall information, e.g. "some avg10=0.22 avg60=0.17 avg300=1.11 total=58761459".
+// The averages are in percentage and the total is in microseconds.
+type PSILine struct {
+	Avg10  float64
+	Avg60  float64
+	Avg300 float64
+	Total  uint64
+}
+
+// PSIStats is the pressure stall information of a resource. Some is the share of time some tasks are stalled, and
+// Full is the share of time all non-idle tasks are stalled, which is nil if not reported, e.g. the cpu of the node.
+type PSIStats struct {
+	Some *PSILine
+	Full *PSILine
+}
+
+// PSIByResource is the pressure stall information of the cpu, memory and io.
+type PSIByResource struct {
+	CPU PSIStats
+	Mem PSIStats
+	IO  PSIStats
+}
+
+// GetNodePSI reads the pressure stall information of the node in /proc/pressure/, which requires the kernel built
+// with CONFIG_PSI and psi is not disabled in the boot parameters.
+func GetNodePSI() (*PSIByResource, error) {
+	psi := &PSIByResource{}
+	for _, item := range []struct {
+		name  string
+		stats *PSIStats
+	}{
+		{name: "cpu", stats: &psi.CPU},
+		{name: "memory", stats: &psi.Mem},
+		{name: "io", stats: &psi.IO},
+	} {
+		content, err := ioutil.ReadFile(path.Join(Conf.ProcRootDir, ProcPressureDir, item.name))
+		if err != nil {
+			return nil, err
+		}
+		stats, err := ParsePSIStats(string(content))
+		if err != nil {
+			return nil, err
+		}
+		*item.stats = *stats
+	}
+	return psi, nil
+}
+
+// GetCgroupPSI reads the pressure stall information of the cgroup, which is supported on the cgroup v2, or on the
+// cgroup v1 of the anolis kernel with the psi of the cgroup v1 enabled.
+func GetCgroupPSI(cgroupTaskDir string) (*PSIByResource, error) {
+	psi := &PSIByResource{}
+	for _, item := range []struct {
+		file  CgroupFile
+		stats *PSIStats
+	}{
+		{file: CPUAcctCPUPressure, stats: &psi.CPU},
+		{file: CPUAcctMemoryPressure, stats: &psi.Mem},
+		{file: CPUAcctIOPressure, stats: &psi.IO},
+	} {
+		content, err := CgroupFileRead(cgroupTaskDir, item.file)
+		if err != nil {
+			return nil, err
+		}
+		stats, err := ParsePSIStats(content)
+		if err != nil {
+			return nil, err
+		}
+		*item.stats = *stats
+	}
+	return psi, nil
+}
+
+// ParsePSIStats parses the content of a pressure file, which is like:
+// some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+// full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func ParsePSIStats(content string) (*PSIStats, error) {
+	stats := &PSIStats{}
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		psiLine, err := parsePSILine(fields[1:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse psi line %q, err: %v", line, err)
+		}
+		switch fields[0] {
+		case "some":
+			stats.Some = psiLine
+		case "full":
+			stats.Full = psiLine
+		}
+	}
+	if stats.Some == nil {
+		return nil, fmt.Errorf("psi %q is illegally formatted", content)
+	}
+	return stats, nil
+}
+
+func parsePSILine(fields []string) (*PSILine, error) {
+	psiLine := &PSILine{}
+	for _, field := range fields {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("illegal field %s", field)
+		}
+		var err error
+		switch kv[0] {
+		case "avg10":
+			psiLine.Avg10, err = strconv.ParseFloat(kv[1], 64)
+		case "avg60":
+			psiLine.Avg60, err = strconv.ParseFloat(kv[1], 64)
+		case "avg300":
+			psiLine.Avg300, err = strconv.ParseFloat(kv[1], 64)
+		case "total":
+			psiLine.Total, err = strconv.ParseUint(kv[1], 10, 64)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return psiLine, nil
+}
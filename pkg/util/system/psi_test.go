@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePSIStats(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    *PSIStats
+		wantErr bool
+	}{
+		{
+			name:    "parse some and full",
+			content: "some avg10=1.50 avg60=0.80 avg300=0.20 total=123456\nfull avg10=0.50 avg60=0.30 avg300=0.10 total=6789\n",
+			want: &PSIStats{
+				Some: &PSILine{Avg10: 1.5, Avg60: 0.8, Avg300: 0.2, Total: 123456},
+				Full: &PSILine{Avg10: 0.5, Avg60: 0.3, Avg300: 0.1, Total: 6789},
+			},
+		},
+		{
+			name:    "parse some only",
+			content: "some avg10=12.00 avg60=6.00 avg300=2.00 total=100\n",
+			want: &PSIStats{
+				Some: &PSILine{Avg10: 12, Avg60: 6, Avg300: 2, Total: 100},
+			},
+		},
+		{
+			name:    "illegal value",
+			content: "some avg10=abc avg60=0.00 avg300=0.00 total=0\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing some",
+			content: "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePSIStats(tt.content)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetNodeAndCgroupPSI(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	cpuContent := "some avg10=10.00 avg60=5.00 avg300=1.00 total=1000\n"
+	memContent := "some avg10=4.00 avg60=2.00 avg300=1.00 total=200\nfull avg10=2.00 avg60=1.00 avg300=0.50 total=100\n"
+	ioContent := "some avg10=3.00 avg60=2.00 avg300=1.00 total=300\nfull avg10=1.00 avg60=0.50 avg300=0.20 total=150\n"
+	helper.WriteProcSubFileContents(path.Join(ProcPressureDir, "cpu"), cpuContent)
+	helper.WriteProcSubFileContents(path.Join(ProcPressureDir, "memory"), memContent)
+	helper.WriteProcSubFileContents(path.Join(ProcPressureDir, "io"), ioContent)
+
+	psi, err := GetNodePSI()
+	assert.NoError(t, err)
+	assert.Equal(t, 10.0, psi.CPU.Some.Avg10)
+	assert.Nil(t, psi.CPU.Full)
+	assert.Equal(t, 2.0, psi.Mem.Full.Avg10)
+	assert.Equal(t, 1.0, psi.IO.Full.Avg10)
+
+	// the cgroup psi is not supported on the cgroup v1 without the anolis kernel
+	taskDir := "kubepods.slice"
+	HostSystemInfo.IsAnolisOS = false
+	_, err = GetCgroupPSI(taskDir)
+	assert.Error(t, err)
+
+	helper.SetCgroupsV2(true)
+	helper.WriteCgroupFileContents(taskDir, CPUAcctCPUPressure, cpuContent)
+	helper.WriteCgroupFileContents(taskDir, CPUAcctMemoryPressure, memContent)
+	helper.WriteCgroupFileContents(taskDir, CPUAcctIOPressure, ioContent)
+	assert.Equal(t, path.Join(helper.TempDir, taskDir, CPUPressureFileName), GetCgroupFilePath(taskDir, CPUAcctCPUPressure))
+	psi, err = GetCgroupPSI(taskDir)
+	assert.NoError(t, err)
+	assert.Equal(t, 10.0, psi.CPU.Some.Avg10)
+	assert.Equal(t, 4.0, psi.Mem.Some.Avg10)
+	assert.Equal(t, 3.0, psi.IO.Some.Avg10)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	ProcNetRouteFileName = "net/route"
+
+	// TCRootHandleMajor is the major of the root htb qdisc handle, i.e. "1:"
+	TCRootHandleMajor uint32 = 1
+)
+
+// TCQdiscHandle returns the tc qdisc handle of the major, e.g. "1:".
+func TCQdiscHandle(major uint32) string {
+	return fmt.Sprintf("%x:", major)
+}
+
+// TCClassID returns the tc class id of the major and minor, e.g. "1:2".
+func TCClassID(major, minor uint32) string {
+	return fmt.Sprintf("%x:%x", major, minor)
+}
+
+// NetClsClassIDValue returns the value of the net_cls.classid which classifies the traffic into the tc class
+// major:minor by the tc cgroup filter, i.e. 0xAAAABBBB where AAAA is the major and BBBB is the minor.
+func NetClsClassIDValue(major, minor uint32) string {
+	return strconv.FormatUint(uint64(major)<<16|uint64(minor), 10)
+}
+
+// TCRate formats the bandwidth in bits per second for tc, e.g. "1000000bit".
+func TCRate(bitsPerSecond int64) string {
+	return fmt.Sprintf("%dbit", bitsPerSecond)
+}
+
+// ExecTCOnHost runs the tc command on the host.
+func ExecTCOnHost(args ...string) error {
+	cmds := append([]string{"tc"}, args...)
+	_, _, err := ExecCmdOnHost(cmds)
+	return err
+}
+
+// GetDefaultRouteInterface returns the interface of the default route in /proc/net/route, e.g. "eth0".
+func GetDefaultRouteInterface() (string, error) {
+	routeFile := path.Join(Conf.ProcRootDir, ProcNetRouteFileName)
+	f, err := os.Open(routeFile)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Iface Destination Gateway Flags RefCnt Use Metric Mask MTU Window IRTT
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 || fields[0] == "Iface" {
+			continue
+		}
+		if fields[1] == "00000000" && fields[7] == "00000000" {
+			return fields[0], nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("default route not found in %s", routeFile)
+}
+
+// GetInterfaceSpeed returns the speed of the interface in bits per second, which is reported in Mbps in
+// /sys/class/net/<iface>/speed. It fails for the virtual interfaces which do not report the speed.
+func GetInterfaceSpeed(iface string) (int64, error) {
+	content, err := ioutil.ReadFile(path.Join(Conf.SysRootDir, "class/net", iface, "speed"))
+	if err != nil {
+		return 0, err
+	}
+	speed, err := strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if speed <= 0 {
+		return 0, fmt.Errorf("unknown speed %d of interface %s", speed, iface)
+	}
+	return speed * 1000 * 1000, nil
+}
+
+// GetRouteInterface returns the interface routing to the ip on the host, e.g. the host side veth of a pod ip.
+func GetRouteInterface(ip string) (string, error) {
+	out, _, err := ExecCmdOnHost([]string{"ip", "route", "get", ip})
+	if err != nil {
+		return "", err
+	}
+	// 10.0.0.12 dev cali1234 src 10.0.0.1 uid 0
+	fields := strings.Fields(string(out))
+	for i := 0; i+1 < len(fields); i++ {
+		if fields[i] == "dev" {
+			return fields[i+1], nil
+		}
+	}
+	return "", fmt.Errorf("no device in the route of %s: %s", ip, strings.TrimSpace(string(out)))
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetClsClassIDValue(t *testing.T) {
+	assert.Equal(t, "65538", NetClsClassIDValue(1, 2))
+	assert.Equal(t, "1:", TCQdiscHandle(1))
+	assert.Equal(t, "1:64", TCClassID(1, 100))
+	assert.Equal(t, "1000bit", TCRate(1000))
+}
+
+func TestGetDefaultRouteInterface(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	_, err := GetDefaultRouteInterface()
+	assert.Error(t, err)
+
+	helper.WriteProcSubFileContents(ProcNetRouteFileName, `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+cali1234	0C00000A	00000000	0005	0	0	0	FFFFFFFF	0	0	0
+eth0	00000000	0100000A	0003	0	0	100	00000000	0	0	0
+eth0	0000000A	00000000	0001	0	0	100	0000FFFF	0	0	0
+`)
+	iface, err := GetDefaultRouteInterface()
+	assert.NoError(t, err)
+	assert.Equal(t, "eth0", iface)
+}
+
+func TestGetRouteInterface(t *testing.T) {
+	oldExecCmdOnHost := ExecCmdOnHost
+	defer func() {
+		ExecCmdOnHost = oldExecCmdOnHost
+	}()
+
+	ExecCmdOnHost = func(cmds []string) ([]byte, int, error) {
+		assert.Equal(t, []string{"ip", "route", "get", "10.0.0.12"}, cmds)
+		return []byte("10.0.0.12 dev cali1234 src 10.0.0.1 uid 0\n    cache\n"), 0, nil
+	}
+	iface, err := GetRouteInterface("10.0.0.12")
+	assert.NoError(t, err)
+	assert.Equal(t, "cali1234", iface)
+
+	ExecCmdOnHost = func(cmds []string) ([]byte, int, error) {
+		return []byte("unreachable 10.0.0.12\n"), 0, nil
+	}
+	_, err = GetRouteInterface("10.0.0.12")
+	assert.Error(t, err)
+}
@@ -39,6 +39,7 @@ func NewFileTestUtil(t *testing.T) *FileTestUtil {
 	t.Setenv("TMPDIR", "/tmp")
 	tempDir := t.TempDir()
 	HostSystemInfo.IsAnolisOS = true
+	HostSystemInfo.IsCgroupV2 = false
 
 	Conf.ProcRootDir = path.Join(tempDir, "proc")
 	err := os.MkdirAll(Conf.ProcRootDir, 0777)
@@ -48,6 +49,11 @@ func NewFileTestUtil(t *testing.T) *FileTestUtil {
 	return &FileTestUtil{TempDir: tempDir, t: t}
 }
 
+// SetCgroupsV2 mocks the cgroup v2 (unified hierarchy), the cgroup files are created without the subsystem dirs.
+func (c *FileTestUtil) SetCgroupsV2(useCgroupsV2 bool) {
+	HostSystemInfo.IsCgroupV2 = useCgroupsV2
+}
+
 func (c *FileTestUtil) Cleanup() {
 	os.RemoveAll(c.TempDir)
 }
@@ -72,6 +78,9 @@ func (c *FileTestUtil) CreateFile(fileRelativePath string) {
 
 func (c *FileTestUtil) WriteFileContents(fileRelativePath, contents string) {
 	filePath := path.Join(c.TempDir, fileRelativePath)
+	if err := os.MkdirAll(path.Dir(filePath), 0777); err != nil {
+		c.t.Fatal(err)
+	}
 	err := ioutil.WriteFile(filePath, []byte(contents), 0644)
 	if err != nil {
 		c.t.Fatal(err)
@@ -101,7 +110,7 @@ func (c *FileTestUtil) CreateProcSubFile(fileRelativePath string) {
 func (c *FileTestUtil) WriteProcSubFileContents(relativeFilePath string, contents string) {
 	file := path.Join(Conf.ProcRootDir, relativeFilePath)
 	if !FileExists(file) {
-		c.CreateProcSubFile(file)
+		c.CreateProcSubFile(relativeFilePath)
 	}
 	err := ioutil.WriteFile(file, []byte(contents), 0644)
 	if err != nil {
@@ -134,7 +143,13 @@ func (c *FileTestUtil) WriteCgroupFileContents(taskDir string, file CgroupFile,
 	if !FileExists(filePath) {
 		c.CreateCgroupFile(taskDir, file)
 	}
-	err := CgroupFileWrite(taskDir, file, contents)
+	var err error
+	if IsCgroupV2() {
+		// the contents are written into the v2 file as is
+		err = ioutil.WriteFile(filePath, []byte(contents), 0644)
+	} else {
+		err = CgroupFileWrite(taskDir, file, contents)
+	}
 	if err != nil {
 		c.t.Fatal(err)
 	}
@@ -25,29 +25,33 @@ import (
 var HostSystemInfo = collectVersionInfo()
 
 func collectVersionInfo() VersionInfo {
+	isCgroupV2 := isCgroupV2Mounted()
 	return VersionInfo{
-		IsAnolisOS: isAnolisOS(),
+		IsAnolisOS: isAnolisOS(isCgroupV2),
+		IsCgroupV2: isCgroupV2,
 	}
 }
 
 type VersionInfo struct {
 	// Open Anolis OS (kernel): https://github.com/alibaba/cloud-kernel
 	IsAnolisOS bool
+	// IsCgroupV2 means the cgroup v2 (unified hierarchy) is used instead of the cgroup v1
+	IsCgroupV2 bool
 }
 
-func isAnolisOS() bool {
-	return isSupportBvtOrWmarRatio()
+func isAnolisOS(isCgroupV2 bool) bool {
+	return isSupportBvtOrWmarRatio(isCgroupV2)
 }
 
-func isSupportBvtOrWmarRatio() bool {
-	bvtFilePath := filepath.Join(Conf.CgroupRootDir, CgroupCPUDir, CPUBVTWarpNsName)
+func isSupportBvtOrWmarRatio(isCgroupV2 bool) bool {
+	bvtFilePath := filepath.Join(getCgroupSubsystemDir(CgroupCPUDir, isCgroupV2), CPUBVTWarpNsName)
 	exists, err := PathExists(bvtFilePath)
 	klog.V(2).Infof("PathExists bvt: exists: %v, error:%v", exists, err)
 	if err == nil && exists {
 		return true
 	}
 
-	wmarkRatioPath := filepath.Join(Conf.CgroupRootDir, CgroupMemDir, "*", MemWmarkRatioFileName)
+	wmarkRatioPath := filepath.Join(getCgroupSubsystemDir(CgroupMemDir, isCgroupV2), "*", MemWmarkRatioFileName)
 	matches, err := filepath.Glob(wmarkRatioPath)
 	klog.V(2).Infof("PathExists wmark_ratio: exists: %v, error:%v", matches, err)
 	if err == nil && len(matches) > 0 {
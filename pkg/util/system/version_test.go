@@ -61,7 +61,7 @@ func TestIsAnolisOS(t *testing.T) {
 			helper := NewFileTestUtil(t)
 			helper.MkDirAll(tt.fs)
 			helper.CreateFile(tt.cgroupFile)
-			assert.Equal(t, tt.expect, isAnolisOS())
+			assert.Equal(t, tt.expect, isAnolisOS(false))
 		})
 	}
 
@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	utilclient "github.com/koordinator-sh/koordinator/pkg/util/client"
+)
+
+// +kubebuilder:rbac:groups=scheduling.sigs.k8s.io,resources=elasticquotas,verbs=get;list;watch
+
+// validateQuotaTree checks the invariants of the quota tree, which would otherwise only surface as weird runtime
+// quotas in the scheduler:
+//   - the min and max are not negative, and the min is not larger than the max
+//   - the parent exists and is a parent quota, and the max of the child is not larger than the max of the parent
+//   - a quota having children is a parent quota, and its max is not smaller than the max of its children
+func (h *ElasticQuotaValidatingHandler) validateQuotaTree(ctx context.Context, quota *v1alpha1.ElasticQuota) (field.ErrorList, error) {
+	allErrs := validateQuotaResources(quota)
+
+	quotaList := &v1alpha1.ElasticQuotaList{}
+	if err := h.Client.List(ctx, quotaList, utilclient.DisableDeepCopy); err != nil {
+		return nil, err
+	}
+	var parent *v1alpha1.ElasticQuota
+	var children []*v1alpha1.ElasticQuota
+	parentName := extension.GetParentQuotaName(quota)
+	for i := range quotaList.Items {
+		item := &quotaList.Items[i]
+		if item.Name == quota.Name {
+			continue
+		}
+		if item.Name == parentName {
+			parent = item
+		}
+		if extension.GetParentQuotaName(item) == quota.Name {
+			children = append(children, item)
+		}
+	}
+
+	parentPath := field.NewPath("metadata", "labels").Key(extension.LabelQuotaParent)
+	if parentName == quota.Name {
+		allErrs = append(allErrs, field.Invalid(parentPath, parentName, "quota can't be the parent of itself"))
+	} else if parentName != extension.RootQuotaName {
+		if parent == nil {
+			allErrs = append(allErrs, field.NotFound(parentPath, parentName))
+		} else if !extension.IsParentQuota(parent) {
+			allErrs = append(allErrs, field.Invalid(parentPath, parentName, "parent quota is not a parent quota group"))
+		} else {
+			allErrs = append(allErrs, validateMaxNotExceeded(field.NewPath("spec", "max"), quota.Spec.Max, parent.Spec.Max,
+				fmt.Sprintf("max of parent quota %s", parent.Name))...)
+		}
+	}
+
+	if len(children) > 0 {
+		if !extension.IsParentQuota(quota) {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("metadata", "labels").Key(extension.LabelQuotaIsParent),
+				fmt.Sprintf("quota has %d children, it can't be a leaf quota", len(children))))
+		}
+		for _, child := range children {
+			for resourceName, childMax := range child.Spec.Max {
+				if max, ok := quota.Spec.Max[resourceName]; ok && max.Cmp(childMax) < 0 {
+					allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "max").Key(string(resourceName)), max.String(),
+						fmt.Sprintf("must be no less than the max of child quota %s %s", child.Name, childMax.String())))
+				}
+			}
+		}
+	}
+	return allErrs, nil
+}
+
+// validateQuotaResources checks the quantities of the min and max are not negative and the min is not larger than the max.
+func validateQuotaResources(quota *v1alpha1.ElasticQuota) field.ErrorList {
+	var allErrs field.ErrorList
+	minPath, maxPath := field.NewPath("spec", "min"), field.NewPath("spec", "max")
+	for resourceName, quantity := range quota.Spec.Min {
+		if quantity.Sign() < 0 {
+			allErrs = append(allErrs, field.Invalid(minPath.Key(string(resourceName)), quantity.String(), "must be non-negative"))
+		}
+	}
+	for resourceName, quantity := range quota.Spec.Max {
+		if quantity.Sign() < 0 {
+			allErrs = append(allErrs, field.Invalid(maxPath.Key(string(resourceName)), quantity.String(), "must be non-negative"))
+		}
+	}
+	allErrs = append(allErrs, validateMaxNotExceeded(minPath, quota.Spec.Min, quota.Spec.Max, "max")...)
+	return allErrs
+}
+
+// validateMaxNotExceeded checks the resources limited by max are not larger than it, the resources not in max are unlimited.
+func validateMaxNotExceeded(path *field.Path, resources, max corev1.ResourceList, maxName string) field.ErrorList {
+	var allErrs field.ErrorList
+	for resourceName, quantity := range resources {
+		limit, ok := max[resourceName]
+		if ok && quantity.Cmp(limit) > 0 {
+			allErrs = append(allErrs, field.Invalid(path.Key(string(resourceName)), quantity.String(),
+				fmt.Sprintf("must be no more than the %s %s", maxName, limit.String())))
+		}
+	}
+	return allErrs
+}
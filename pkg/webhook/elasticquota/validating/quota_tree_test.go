@@ -0,0 +1,170 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func init() {
+	_ = v1alpha1.AddToScheme(scheme.Scheme)
+}
+
+func newTestQuota(name, parent string, isParent bool, min, max corev1.ResourceList) *v1alpha1.ElasticQuota {
+	quota := &v1alpha1.ElasticQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "quota-ns",
+			Name:      name,
+			Labels:    map[string]string{},
+		},
+		Spec: v1alpha1.ElasticQuotaSpec{
+			Min: min,
+			Max: max,
+		},
+	}
+	if parent != "" {
+		quota.Labels[extension.LabelQuotaParent] = parent
+	}
+	if isParent {
+		quota.Labels[extension.LabelQuotaIsParent] = "true"
+	}
+	return quota
+}
+
+func newTestResourceList(cpu, memory string) corev1.ResourceList {
+	return corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(memory),
+	}
+}
+
+func TestElasticQuotaValidatingHandler(t *testing.T) {
+	existing := []runtime.Object{
+		newTestQuota("parent", "", true, newTestResourceList("10", "10Gi"), newTestResourceList("100", "100Gi")),
+		newTestQuota("child", "parent", false, newTestResourceList("5", "5Gi"), newTestResourceList("50", "50Gi")),
+		newTestQuota("leaf", "", false, newTestResourceList("5", "5Gi"), newTestResourceList("50", "50Gi")),
+	}
+
+	tests := []struct {
+		name        string
+		operation   admissionv1.Operation
+		quota       *v1alpha1.ElasticQuota
+		wantAllowed bool
+		wantReason  string
+	}{
+		{
+			name:        "valid child quota",
+			operation:   admissionv1.Create,
+			quota:       newTestQuota("new", "parent", false, newTestResourceList("5", "5Gi"), newTestResourceList("100", "100Gi")),
+			wantAllowed: true,
+		},
+		{
+			name:        "negative quantity",
+			operation:   admissionv1.Create,
+			quota:       newTestQuota("new", "", false, newTestResourceList("-1", "5Gi"), newTestResourceList("10", "10Gi")),
+			wantAllowed: false,
+			wantReason:  `spec.min[cpu]: Invalid value: "-1": must be non-negative`,
+		},
+		{
+			name:        "min larger than max",
+			operation:   admissionv1.Create,
+			quota:       newTestQuota("new", "", false, newTestResourceList("20", "5Gi"), newTestResourceList("10", "10Gi")),
+			wantAllowed: false,
+			wantReason:  `spec.min[cpu]: Invalid value: "20": must be no more than the max 10`,
+		},
+		{
+			name:        "unknown parent",
+			operation:   admissionv1.Create,
+			quota:       newTestQuota("new", "not-exist", false, nil, nil),
+			wantAllowed: false,
+			wantReason:  `metadata.labels[quota.scheduling.koordinator.sh/parent]: Not found: "not-exist"`,
+		},
+		{
+			name:        "parent is a leaf quota",
+			operation:   admissionv1.Create,
+			quota:       newTestQuota("new", "leaf", false, nil, nil),
+			wantAllowed: false,
+			wantReason:  `metadata.labels[quota.scheduling.koordinator.sh/parent]: Invalid value: "leaf": parent quota is not a parent quota group`,
+		},
+		{
+			name:        "child max larger than parent max",
+			operation:   admissionv1.Create,
+			quota:       newTestQuota("new", "parent", false, nil, newTestResourceList("100", "200Gi")),
+			wantAllowed: false,
+			wantReason:  `spec.max[memory]: Invalid value: "200Gi": must be no more than the max of parent quota parent 100Gi`,
+		},
+		{
+			name:        "change parent quota having children to leaf",
+			operation:   admissionv1.Update,
+			quota:       newTestQuota("parent", "", false, newTestResourceList("10", "10Gi"), newTestResourceList("100", "100Gi")),
+			wantAllowed: false,
+			wantReason:  `metadata.labels[quota.scheduling.koordinator.sh/is-parent]: Forbidden: quota has 1 children, it can't be a leaf quota`,
+		},
+		{
+			name:        "shrink parent max below child max",
+			operation:   admissionv1.Update,
+			quota:       newTestQuota("parent", "", true, newTestResourceList("10", "10Gi"), newTestResourceList("40", "100Gi")),
+			wantAllowed: false,
+			wantReason:  `spec.max[cpu]: Invalid value: "40": must be no less than the max of child quota child 50`,
+		},
+		{
+			name:        "update leaf quota",
+			operation:   admissionv1.Update,
+			quota:       newTestQuota("child", "parent", false, newTestResourceList("10", "10Gi"), newTestResourceList("60", "60Gi")),
+			wantAllowed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewClientBuilder().WithRuntimeObjects(existing...).Build()
+			decoder, _ := admission.NewDecoder(scheme.Scheme)
+			handler := &ElasticQuotaValidatingHandler{
+				Client:  client,
+				Decoder: decoder,
+			}
+
+			raw, err := json.Marshal(tt.quota)
+			assert.NoError(t, err)
+			req := admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Resource:  metav1.GroupVersionResource{Group: v1alpha1.SchemeGroupVersion.Group, Version: v1alpha1.SchemeGroupVersion.Version, Resource: "elasticquotas"},
+					Operation: tt.operation,
+					Object:    runtime.RawExtension{Raw: raw},
+				},
+			}
+			allowed, reason, err := handler.validatingElasticQuotaFn(context.TODO(), req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantAllowed, allowed)
+			assert.Contains(t, reason, tt.wantReason)
+		})
+	}
+}
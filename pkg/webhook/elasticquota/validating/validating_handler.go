@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"context"
+	"net/http"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+)
+
+// ElasticQuotaValidatingHandler handles ElasticQuota
+type ElasticQuotaValidatingHandler struct {
+	Client client.Client
+
+	// Decoder decodes objects
+	Decoder *admission.Decoder
+}
+
+var _ admission.Handler = &ElasticQuotaValidatingHandler{}
+
+func shouldIgnoreIfNotElasticQuota(req admission.Request) bool {
+	// Ignore all calls to sub resources or resources other than elasticquotas.
+	if len(req.AdmissionRequest.SubResource) != 0 ||
+		req.AdmissionRequest.Resource.Resource != "elasticquotas" {
+		return true
+	}
+	return false
+}
+
+func (h *ElasticQuotaValidatingHandler) validatingElasticQuotaFn(ctx context.Context, req admission.Request) (allowed bool, reason string, err error) {
+	allowed = true
+	if shouldIgnoreIfNotElasticQuota(req) {
+		return
+	}
+
+	quota := &v1alpha1.ElasticQuota{}
+	if err = h.Decoder.DecodeRaw(req.Object, quota); err != nil {
+		return false, "", err
+	}
+	allErrs, err := h.validateQuotaTree(ctx, quota)
+	if err != nil {
+		return false, "", err
+	}
+	if aggregated := allErrs.ToAggregate(); aggregated != nil {
+		return false, aggregated.Error(), nil
+	}
+	return
+}
+
+// Handle handles admission requests.
+func (h *ElasticQuotaValidatingHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	allowed, reason, err := h.validatingElasticQuotaFn(ctx, req)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	return admission.ValidationResponse(allowed, reason)
+}
+
+var _ inject.Client = &ElasticQuotaValidatingHandler{}
+
+// InjectClient injects the client into the ElasticQuotaValidatingHandler
+func (h *ElasticQuotaValidatingHandler) InjectClient(c client.Client) error {
+	h.Client = c
+	return nil
+}
+
+var _ admission.DecoderInjector = &ElasticQuotaValidatingHandler{}
+
+// InjectDecoder injects the decoder into the ElasticQuotaValidatingHandler
+func (h *ElasticQuotaValidatingHandler) InjectDecoder(d *admission.Decoder) error {
+	h.Decoder = d
+	return nil
+}
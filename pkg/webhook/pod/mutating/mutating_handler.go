@@ -70,6 +70,16 @@ func (h *PodMutatingHandler) Handle(ctx context.Context, req admission.Request)
 		return admission.Errored(http.StatusInternalServerError, err)
 	}
 
+	if err = h.namespaceQuotaMutatingPod(ctx, req, obj); err != nil {
+		klog.Errorf("Failed to mutating Pod %s/%s by namespace quota, err: %v", obj.Namespace, obj.Name, err)
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if err = h.podGroupMutatingPod(ctx, req, obj); err != nil {
+		klog.Errorf("Failed to mutating Pod %s/%s by PodGroup, err: %v", obj.Namespace, obj.Name, err)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
 	if reflect.DeepEqual(obj, clone) {
 		return admission.Allowed("")
 	}
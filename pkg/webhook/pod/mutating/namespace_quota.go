@@ -0,0 +1,101 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"context"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	utilclient "github.com/koordinator-sh/koordinator/pkg/util/client"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=scheduling.sigs.k8s.io,resources=elasticquotas,verbs=get;list;watch
+
+// namespaceQuotaMutatingPod injects the quota label of the namespace onto the pod. The namespace is bound to
+// a quota group by labeling the Namespace with extension.LabelQuotaName, and the quota must be a leaf quota.
+// Pods which have already been labeled with a quota name are left untouched.
+func (h *PodMutatingHandler) namespaceQuotaMutatingPod(ctx context.Context, req admission.Request, pod *corev1.Pod) error {
+	if req.Operation != admissionv1.Create {
+		return nil
+	}
+	if !utilfeature.DefaultFeatureGate.Enabled(features.ElasticQuotaNamespaceBinding) {
+		return nil
+	}
+	if pod.Labels[extension.LabelQuotaName] != "" {
+		return nil
+	}
+
+	namespace := &corev1.Namespace{}
+	err := h.Client.Get(ctx, types.NamespacedName{Name: pod.Namespace}, namespace)
+	if err != nil {
+		return err
+	}
+	quotaName := namespace.Labels[extension.LabelQuotaName]
+	if quotaName == "" {
+		return nil
+	}
+
+	if err = h.validateNamespaceQuota(ctx, quotaName); err != nil {
+		return fmt.Errorf("namespace %s is bound to an invalid quota, %v", pod.Namespace, err)
+	}
+
+	if pod.Labels == nil {
+		pod.Labels = make(map[string]string)
+	}
+	pod.Labels[extension.LabelQuotaName] = quotaName
+	klog.V(4).Infof("mutate Pod %s/%s with quota %s bound by namespace", pod.Namespace, pod.Name, quotaName)
+	return nil
+}
+
+// validateNamespaceQuota checks the quota exists and is a leaf quota, since pods can only be admitted by leaf quotas.
+func (h *PodMutatingHandler) validateNamespaceQuota(ctx context.Context, quotaName string) error {
+	switch quotaName {
+	case extension.RootQuotaName:
+		return fmt.Errorf("quota %s is not a leaf quota", quotaName)
+	case extension.SystemQuotaName, extension.DefaultQuotaName:
+		// the built-in quotas are always created by the quota manager
+		return nil
+	}
+
+	quotaList := &v1alpha1.ElasticQuotaList{}
+	err := h.Client.List(ctx, quotaList, utilclient.DisableDeepCopy)
+	if err != nil {
+		return err
+	}
+	for i := range quotaList.Items {
+		quota := &quotaList.Items[i]
+		if quota.Name != quotaName {
+			continue
+		}
+		if extension.IsParentQuota(quota) {
+			return fmt.Errorf("quota %s is not a leaf quota", quotaName)
+		}
+		return nil
+	}
+	return fmt.Errorf("quota %s not found", quotaName)
+}
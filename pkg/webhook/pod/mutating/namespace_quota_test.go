@@ -0,0 +1,160 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+func init() {
+	_ = v1alpha1.AddToScheme(scheme.Scheme)
+}
+
+func TestNamespaceQuotaMutatingPod(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultMutableFeatureGate, features.ElasticQuotaNamespaceBinding, true)()
+
+	quotas := []runtime.Object{
+		&v1alpha1.ElasticQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "quota-ns",
+				Name:      "parent-quota",
+				Labels: map[string]string{
+					extension.LabelQuotaIsParent: "true",
+				},
+			},
+		},
+		&v1alpha1.ElasticQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "quota-ns",
+				Name:      "leaf-quota",
+				Labels: map[string]string{
+					extension.LabelQuotaParent: "parent-quota",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		operation      admissionv1.Operation
+		namespaceQuota string
+		podQuota       string
+		wantQuota      string
+		wantErr        bool
+	}{
+		{
+			name:      "namespace without quota",
+			operation: admissionv1.Create,
+			wantQuota: "",
+		},
+		{
+			name:           "inject leaf quota",
+			operation:      admissionv1.Create,
+			namespaceQuota: "leaf-quota",
+			wantQuota:      "leaf-quota",
+		},
+		{
+			name:           "inject default quota",
+			operation:      admissionv1.Create,
+			namespaceQuota: extension.DefaultQuotaName,
+			wantQuota:      extension.DefaultQuotaName,
+		},
+		{
+			name:           "pod already has quota",
+			operation:      admissionv1.Create,
+			namespaceQuota: "leaf-quota",
+			podQuota:       "other-quota",
+			wantQuota:      "other-quota",
+		},
+		{
+			name:           "ignore update",
+			operation:      admissionv1.Update,
+			namespaceQuota: "leaf-quota",
+			wantQuota:      "",
+		},
+		{
+			name:           "parent quota is rejected",
+			operation:      admissionv1.Create,
+			namespaceQuota: "parent-quota",
+			wantErr:        true,
+		},
+		{
+			name:           "root quota is rejected",
+			operation:      admissionv1.Create,
+			namespaceQuota: extension.RootQuotaName,
+			wantErr:        true,
+		},
+		{
+			name:           "missing quota is rejected",
+			operation:      admissionv1.Create,
+			namespaceQuota: "not-exist-quota",
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namespace := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "default",
+					Labels: map[string]string{},
+				},
+			}
+			if tt.namespaceQuota != "" {
+				namespace.Labels[extension.LabelQuotaName] = tt.namespaceQuota
+			}
+			client := fake.NewClientBuilder().WithRuntimeObjects(append(quotas, namespace)...).Build()
+			decoder, _ := admission.NewDecoder(scheme.Scheme)
+			handler := &PodMutatingHandler{
+				Client:  client,
+				Decoder: decoder,
+			}
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test-pod-1",
+				},
+			}
+			if tt.podQuota != "" {
+				pod.Labels = map[string]string{extension.LabelQuotaName: tt.podQuota}
+			}
+
+			req := newAdmission(tt.operation, runtime.RawExtension{}, runtime.RawExtension{}, "")
+			err := handler.namespaceQuotaMutatingPod(context.TODO(), req, pod)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			if !tt.wantErr {
+				assert.Equal(t, tt.wantQuota, pod.Labels[extension.LabelQuotaName])
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"context"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/controllers/podgroup"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+// podGroupMutatingPod labels the pod with the PodGroup generated for its owner workload, e.g. a PyTorchJob.
+// Pods which have already joined a gang are left untouched.
+func (h *PodMutatingHandler) podGroupMutatingPod(ctx context.Context, req admission.Request, pod *corev1.Pod) error {
+	if req.Operation != admissionv1.Create {
+		return nil
+	}
+	if !utilfeature.DefaultFeatureGate.Enabled(features.PodGroupAutoCreation) {
+		return nil
+	}
+	if pod.Labels[v1alpha1.PodGroupLabel] != "" || pod.Annotations[extension.AnnotationGangName] != "" {
+		return nil
+	}
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil || podgroup.GetWorkloadMapping(owner.APIVersion, owner.Kind) == nil {
+		return nil
+	}
+
+	if pod.Labels == nil {
+		pod.Labels = make(map[string]string)
+	}
+	pod.Labels[v1alpha1.PodGroupLabel] = owner.Name
+	klog.V(4).Infof("mutate Pod %s/%s with PodGroup of %s %s", pod.Namespace, pod.Name, owner.Kind, owner.Name)
+	return nil
+}
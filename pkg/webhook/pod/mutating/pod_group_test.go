@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+func TestPodGroupMutatingPod(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultMutableFeatureGate, features.PodGroupAutoCreation, true)()
+
+	tests := []struct {
+		name         string
+		operation    admissionv1.Operation
+		owner        *metav1.OwnerReference
+		podGroup     string
+		wantPodGroup string
+	}{
+		{
+			name:      "pod without owner",
+			operation: admissionv1.Create,
+		},
+		{
+			name:      "owner is not mapped",
+			operation: admissionv1.Create,
+			owner: &metav1.OwnerReference{
+				APIVersion: "apps/v1",
+				Kind:       "ReplicaSet",
+				Name:       "test-rs",
+				Controller: pointer.Bool(true),
+			},
+		},
+		{
+			name:      "pod of PyTorchJob",
+			operation: admissionv1.Create,
+			owner: &metav1.OwnerReference{
+				APIVersion: "kubeflow.org/v1",
+				Kind:       "PyTorchJob",
+				Name:       "test-job",
+				Controller: pointer.Bool(true),
+			},
+			wantPodGroup: "test-job",
+		},
+		{
+			name:      "pod already in a gang",
+			operation: admissionv1.Create,
+			owner: &metav1.OwnerReference{
+				APIVersion: "kubeflow.org/v1",
+				Kind:       "PyTorchJob",
+				Name:       "test-job",
+				Controller: pointer.Bool(true),
+			},
+			podGroup:     "other-gang",
+			wantPodGroup: "other-gang",
+		},
+		{
+			name:      "ignore update",
+			operation: admissionv1.Update,
+			owner: &metav1.OwnerReference{
+				APIVersion: "ray.io/v1alpha1",
+				Kind:       "RayCluster",
+				Name:       "test-cluster",
+				Controller: pointer.Bool(true),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &PodMutatingHandler{}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test-pod-1",
+				},
+			}
+			if tt.owner != nil {
+				pod.OwnerReferences = []metav1.OwnerReference{*tt.owner}
+			}
+			if tt.podGroup != "" {
+				pod.Labels = map[string]string{v1alpha1.PodGroupLabel: tt.podGroup}
+			}
+
+			req := newAdmission(tt.operation, runtime.RawExtension{}, runtime.RawExtension{}, "")
+			assert.NoError(t, handler.podGroupMutatingPod(context.TODO(), req, pod))
+			assert.Equal(t, tt.wantPodGroup, pod.Labels[v1alpha1.PodGroupLabel])
+		})
+	}
+}
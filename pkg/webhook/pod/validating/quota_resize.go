@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	utilclient "github.com/koordinator-sh/koordinator/pkg/util/client"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+// +kubebuilder:rbac:groups=scheduling.sigs.k8s.io,resources=elasticquotas,verbs=get;list;watch
+
+// podResizeSubResource is the subresource to resize the resources of the pod in place.
+const podResizeSubResource = "resize"
+
+// quotaResizeValidatingPod rejects the in-place resize of the scheduled pod if the increased request exceeds the
+// runtime of its quota group. The Used and the Runtime are published onto the ElasticQuota by koord-scheduler,
+// and the pods not scheduled yet are left to the admission of koord-scheduler.
+func (h *PodValidatingHandler) quotaResizeValidatingPod(ctx context.Context, req admission.Request) (bool, string, error) {
+	if req.Operation != admissionv1.Update || !utilfeature.DefaultFeatureGate.Enabled(features.ElasticQuotaResizeValidation) {
+		return true, "", nil
+	}
+	oldPod := &corev1.Pod{}
+	if err := h.Decoder.DecodeRaw(req.OldObject, oldPod); err != nil {
+		return false, "", err
+	}
+	newPod := &corev1.Pod{}
+	if err := h.Decoder.DecodeRaw(req.Object, newPod); err != nil {
+		return false, "", err
+	}
+	if newPod.Spec.NodeName == "" {
+		return true, "", nil
+	}
+
+	quotaName := newPod.Labels[extension.LabelQuotaName]
+	if quotaName == "" || quotaName == extension.DefaultQuotaName || quotaName == extension.SystemQuotaName {
+		return true, "", nil
+	}
+	increased := getIncreasedRequest(oldPod, newPod)
+	if len(increased) == 0 {
+		return true, "", nil
+	}
+
+	quota, err := h.getElasticQuota(ctx, quotaName)
+	if err != nil {
+		return false, "", err
+	}
+	if quota == nil {
+		return true, "", nil
+	}
+	value, exist := quota.Annotations[extension.AnnotationRuntime]
+	if !exist {
+		// the runtime is not published yet
+		return true, "", nil
+	}
+	runtime := corev1.ResourceList{}
+	if err = json.Unmarshal([]byte(value), &runtime); err != nil {
+		return false, "", fmt.Errorf("failed to parse the runtime of quota %s, err: %v", quotaName, err)
+	}
+
+	for resourceName, quantity := range increased {
+		runtimeQuantity, ok := runtime[resourceName]
+		if !ok {
+			continue
+		}
+		used := quota.Status.Used[resourceName]
+		used.Add(quantity)
+		if used.Cmp(runtimeQuantity) > 0 {
+			return false, fmt.Sprintf("resize of pod %s/%s exceeds the runtime of quota %s, %s increased %s, used %s, runtime %s",
+				newPod.Namespace, newPod.Name, quotaName, resourceName, quantity.String(), used.String(), runtimeQuantity.String()), nil
+		}
+	}
+	return true, "", nil
+}
+
+// getIncreasedRequest returns the increased part of the request counted by the quota groups.
+func getIncreasedRequest(oldPod, newPod *corev1.Pod) corev1.ResourceList {
+	oldRequest := extension.TranslateResourceNameAliases(util.GetPodRequest(oldPod))
+	newRequest := extension.TranslateResourceNameAliases(util.GetPodRequest(newPod))
+	increased := corev1.ResourceList{}
+	for resourceName, quantity := range quotav1.Subtract(newRequest, oldRequest) {
+		if quantity.Sign() > 0 {
+			increased[resourceName] = quantity
+		}
+	}
+	return increased
+}
+
+// getElasticQuota returns the ElasticQuota by name, the names of the ElasticQuotas are unique across namespaces.
+func (h *PodValidatingHandler) getElasticQuota(ctx context.Context, quotaName string) (*v1alpha1.ElasticQuota, error) {
+	quotaList := &v1alpha1.ElasticQuotaList{}
+	if err := h.Client.List(ctx, quotaList, utilclient.DisableDeepCopy); err != nil {
+		return nil, err
+	}
+	for i := range quotaList.Items {
+		if quotaList.Items[i].Name == quotaName {
+			return &quotaList.Items[i], nil
+		}
+	}
+	return nil, nil
+}
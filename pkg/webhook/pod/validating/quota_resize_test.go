@@ -0,0 +1,165 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+func init() {
+	_ = v1alpha1.AddToScheme(scheme.Scheme)
+}
+
+func TestQuotaResizeValidatingPod(t *testing.T) {
+	defer featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultMutableFeatureGate, features.ElasticQuotaResizeValidation, true)()
+
+	quota := &v1alpha1.ElasticQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "quota-ns",
+			Name:      "quota-1",
+			Annotations: map[string]string{
+				extension.AnnotationRuntime: `{"cpu":"10","memory":"20Gi"}`,
+			},
+		},
+		Status: v1alpha1.ElasticQuotaStatus{
+			Used: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("8"),
+				corev1.ResourceMemory: resource.MustParse("10Gi"),
+			},
+		},
+	}
+	newPod := func(quotaName, nodeName, cpu string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "pod-1",
+				Labels:    map[string]string{extension.LabelQuotaName: quotaName},
+			},
+			Spec: corev1.PodSpec{
+				NodeName: nodeName,
+				Containers: []corev1.Container{
+					{
+						Name: "main",
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse(cpu),
+								corev1.ResourceMemory: resource.MustParse("1Gi"),
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		operation   admissionv1.Operation
+		oldPod      *corev1.Pod
+		newPod      *corev1.Pod
+		wantAllowed bool
+	}{
+		{
+			name:        "ignore create",
+			operation:   admissionv1.Create,
+			newPod:      newPod("quota-1", "node-1", "4"),
+			wantAllowed: true,
+		},
+		{
+			name:        "scale up within runtime",
+			operation:   admissionv1.Update,
+			oldPod:      newPod("quota-1", "node-1", "2"),
+			newPod:      newPod("quota-1", "node-1", "4"),
+			wantAllowed: true,
+		},
+		{
+			name:        "scale up exceeds runtime",
+			operation:   admissionv1.Update,
+			oldPod:      newPod("quota-1", "node-1", "2"),
+			newPod:      newPod("quota-1", "node-1", "5"),
+			wantAllowed: false,
+		},
+		{
+			name:        "scale down",
+			operation:   admissionv1.Update,
+			oldPod:      newPod("quota-1", "node-1", "4"),
+			newPod:      newPod("quota-1", "node-1", "2"),
+			wantAllowed: true,
+		},
+		{
+			name:        "pod not scheduled",
+			operation:   admissionv1.Update,
+			oldPod:      newPod("quota-1", "", "2"),
+			newPod:      newPod("quota-1", "", "5"),
+			wantAllowed: true,
+		},
+		{
+			name:        "default quota",
+			operation:   admissionv1.Update,
+			oldPod:      newPod(extension.DefaultQuotaName, "node-1", "2"),
+			newPod:      newPod(extension.DefaultQuotaName, "node-1", "50"),
+			wantAllowed: true,
+		},
+		{
+			name:        "quota not found",
+			operation:   admissionv1.Update,
+			oldPod:      newPod("quota-2", "node-1", "2"),
+			newPod:      newPod("quota-2", "node-1", "50"),
+			wantAllowed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewClientBuilder().WithRuntimeObjects(quota.DeepCopy()).Build()
+			decoder, _ := admission.NewDecoder(scheme.Scheme)
+			h := &PodValidatingHandler{
+				Client:  client,
+				Decoder: decoder,
+			}
+
+			var objRawExt, oldObjRawExt runtime.RawExtension
+			if tt.newPod != nil {
+				objRawExt = runtime.RawExtension{Raw: []byte(util.DumpJSON(tt.newPod))}
+			}
+			if tt.oldPod != nil {
+				oldObjRawExt = runtime.RawExtension{Raw: []byte(util.DumpJSON(tt.oldPod))}
+			}
+			req := newAdmissionRequest(tt.operation, objRawExt, oldObjRawExt, podResizeSubResource)
+			gotAllowed, gotReason, err := h.quotaResizeValidatingPod(context.TODO(), admission.Request{AdmissionRequest: req})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantAllowed, gotAllowed, gotReason)
+		})
+	}
+}
@@ -38,8 +38,8 @@ type PodValidatingHandler struct {
 var _ admission.Handler = &PodValidatingHandler{}
 
 func shouldIgnoreIfNotPod(req admission.Request) bool {
-	// Ignore all calls to sub resources or resources other than pods.
-	if len(req.AdmissionRequest.SubResource) != 0 ||
+	// Ignore all calls to sub resources except the in-place resize, or resources other than pods.
+	if (len(req.AdmissionRequest.SubResource) != 0 && req.AdmissionRequest.SubResource != podResizeSubResource) ||
 		req.AdmissionRequest.Resource.Resource != "pods" {
 		return true
 	}
@@ -56,7 +56,15 @@ func (h *PodValidatingHandler) validatingPodFn(ctx context.Context, req admissio
 		return
 	}
 
+	if req.AdmissionRequest.SubResource == podResizeSubResource {
+		return h.quotaResizeValidatingPod(ctx, req)
+	}
+
 	allowed, reason, err = h.clusterColocationProfileValidatingPod(ctx, req)
+	if !allowed || err != nil {
+		return
+	}
+	allowed, reason, err = h.quotaResizeValidatingPod(ctx, req)
 	return
 }
 